@@ -3,6 +3,7 @@ module hello-world-grpc
 go 1.23.0
 
 require (
+	github.com/gogf/gf/v2 v2.10.0
 	google.golang.org/grpc v1.65.0
 	google.golang.org/protobuf v1.36.11
 )
@@ -15,7 +16,6 @@ require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
-	github.com/gogf/gf/v2 v2.10.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/grokify/html-strip-tags-go v0.1.0 // indirect
@@ -34,28 +34,24 @@ func (s *greeterServer) SayHello(ctx context.Context, req *hellopb.HelloRequest)
 
 // ---- gRPC 客户端调用 ----
 
-func callGrpc(host string, port int, name string) string {
+// callGrpc 调用 Greeter.SayHello，wait 为等待服务端就绪的最长时间
+// 从注册中心发现实例并在实例间负载均衡时，使用 golang-sdk 的 grpcclient.Dial 代替直连固定地址
+func callGrpc(host string, port int, name string, wait time.Duration) string {
 	target := fmt.Sprintf("%s:%d", host, port)
-	// 带重试
-	for i := 0; i < 30; i++ {
-		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		if err != nil {
-			fmt.Printf("（连接失败 %ds）\r", i+1)
-			time.Sleep(1 * time.Second)
-			continue
-		}
-		client := hellopb.NewGreeterClient(conn)
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		reply, err := client.SayHello(ctx, &hellopb.HelloRequest{Name: name})
-		cancel()
-		conn.Close()
-		if err == nil {
-			return reply.GetMessage()
-		}
-		fmt.Printf("（等待 gRPC 服务就绪 %ds）\r", i+1)
-		time.Sleep(1 * time.Second)
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Sprintf("连接失败: %v", err)
+	}
+	defer conn.Close()
+
+	// WaitForReady：服务端未就绪时等待连接建立而不是立即失败，最多等待 wait
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	reply, err := hellopb.NewGreeterClient(conn).SayHello(ctx, &hellopb.HelloRequest{Name: name}, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Sprintf("调用失败: %v", err)
 	}
-	return "调用超时"
+	return reply.GetMessage()
 }
 
 func main() {
@@ -89,7 +85,7 @@ func main() {
 		}
 		r.Response.WriteJsonExit(g.Map{
 			"go":   "Hello " + name + ", I am GoLang (gRPC)",
-			"java": callGrpc("localhost", 9091, name),
+			"java": callGrpc("localhost", 9091, name, 5*time.Second),
 		})
 	})
 
@@ -102,9 +98,9 @@ func main() {
 	fmt.Println("[Go] 浏览器访问: http://localhost:8093")
 
 	go func() {
-		time.Sleep(2 * time.Second)
 		fmt.Println("\n[Go 本地 gRPC] Hello world, I am GoLang (gRPC)")
-		fmt.Println("[Go → Java gRPC] " + callGrpc("localhost", 9091, "GoLang"))
+		// 等待 Java 服务启动，最多 30 秒
+		fmt.Println("[Go → Java gRPC] " + callGrpc("localhost", 9091, "GoLang", 30*time.Second))
 		fmt.Println("\n[Go] 服务运行中（Ctrl+C 退出）...")
 	}()
 
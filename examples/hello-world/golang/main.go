@@ -1,98 +1,51 @@
 // Hello World - Golang 示例（GoFrame）
-// 使用 RpcProxy 进行跨语言调用，服务地址通过 config.yaml 配置
+// 使用 golang-sdk 的 RpcProxy 进行跨语言调用，php-service/java-service 经注册中心发现、负载均衡选择实例
 // 端口 8093，与 Java(8091)、PHP(8092) 互调 hello.sayHello
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"time"
 
+	"github.com/framework/golang-sdk/client"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/registry"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
-	"gopkg.in/yaml.v3"
 )
 
-// ---- RPC 代理：从配置文件读取远程服务地址 ----
+// ---- RPC 代理：从注册中心发现远程服务 ----
 
-type rpcProxy struct {
-	services map[string]serviceEndpoint
-	client   *http.Client
-}
-
-type serviceEndpoint struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
-}
-
-type proxyConfig struct {
-	Framework struct {
-		Services map[string]serviceEndpoint `yaml:"services"`
-	} `yaml:"framework"`
-}
-
-func loadRpcProxy(configPath string) *rpcProxy {
-	p := &rpcProxy{
-		services: make(map[string]serviceEndpoint),
-		client:   &http.Client{Timeout: 5 * time.Second},
-	}
-	data, err := os.ReadFile(configPath)
+// callService 调用远程服务，启动时已等待其就绪，失败时直接返回错误
+func callService(rpc *client.RpcProxy, service, method string, params interface{}) string {
+	result, err := rpc.Call(service, method, params)
 	if err != nil {
-		fmt.Printf("[Go] 警告: 无法读取配置 %s: %v\n", configPath, err)
-		return p
-	}
-	var cfg proxyConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		fmt.Printf("[Go] 警告: 解析配置失败: %v\n", err)
-		return p
-	}
-	p.services = cfg.Framework.Services
-	for name, ep := range p.services {
-		fmt.Printf("[Go] 注册远程服务: %s -> %s:%d\n", name, ep.Host, ep.Port)
+		return fmt.Sprintf("调用失败: %v", err)
 	}
-	return p
+	return result
 }
 
-func (p *rpcProxy) Call(service, method string, params interface{}) string {
-	ep, ok := p.services[service]
-	if !ok {
-		return "未知服务: " + service
+// registerSelf 将本服务注册到注册中心，供其他语言经同一注册中心发现
+func registerSelf(rpc *client.RpcProxy, port int) {
+	reg := rpc.Registry()
+	if reg == nil {
+		return
 	}
-	url := fmt.Sprintf("http://%s:%d/jsonrpc", ep.Host, ep.Port)
-
-	reqBody, _ := json.Marshal(map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  method,
-		"params":  params,
-		"id":      1,
+	hostname, _ := os.Hostname()
+	err := reg.Register(context.Background(), &registry.ServiceInfo{
+		ID:        fmt.Sprintf("go-service-%s-%d", hostname, port),
+		Name:      "go-service",
+		Language:  "golang",
+		Address:   "localhost",
+		Port:      port,
+		Protocols: []string{"jsonrpc"},
 	})
-
-	// 带重试（等待其他服务启动）
-	for i := 0; i < 30; i++ {
-		resp, err := p.client.Post(url, "application/json", bytes.NewBuffer(reqBody))
-		if err != nil {
-			fmt.Printf("（等待 %s 就绪 %ds）\r", service, i+1)
-			time.Sleep(1 * time.Second)
-			continue
-		}
-		defer resp.Body.Close()
-
-		var rpcResp struct {
-			Result interface{} `json:"result"`
-			Error  interface{} `json:"error"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-			return "解析响应失败"
-		}
-		if rpcResp.Result != nil {
-			return fmt.Sprintf("%v", rpcResp.Result)
-		}
-		return fmt.Sprintf("错误: %v", rpcResp.Error)
+	if err != nil {
+		fmt.Printf("[Go] 警告: 注册服务失败: %v\n", err)
 	}
-	return "调用超时: " + service
 }
 
 func main() {
@@ -100,8 +53,14 @@ func main() {
 	fmt.Println("  Hello World - Golang (GoFrame)")
 	fmt.Println("========================================")
 
-	// 1. 从配置文件加载远程服务定义
-	rpc := loadRpcProxy("config.yaml")
+	// 1. 从配置文件创建 RpcProxy，远程服务经注册中心发现
+	rpc, err := client.NewRpcProxyFromConfig("config.yaml")
+	if err != nil {
+		fmt.Printf("[Go] 加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer rpc.Close()
+	registerSelf(rpc, 8093)
 
 	s := g.Server()
 	s.SetPort(8093)
@@ -109,14 +68,20 @@ func main() {
 	// POST /jsonrpc — 供其他语言调用
 	s.BindHandler("POST:/jsonrpc", func(r *ghttp.Request) {
 		var req struct {
-			Method string      `json:"method"`
-			Params interface{} `json:"params"`
-			ID     int         `json:"id"`
+			Method string            `json:"method"`
+			Params interface{}       `json:"params"`
+			ID     int               `json:"id"`
+			Meta   map[string]string `json:"meta"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			r.Response.WriteStatus(400, "parse error")
 			return
 		}
+		// 调用方的剩余超时在请求头或 meta 的 X-Timeout-Ms 中，超时或调用方断开连接后 ctx 结束，不再继续处理
+		ctx, cancelHeader := adapter.ExtractDeadline(r.Context(), map[string]string{adapter.HeaderTimeout: r.GetHeader(adapter.HeaderTimeout)})
+		defer cancelHeader()
+		ctx, cancel := adapter.ExtractDeadline(ctx, req.Meta)
+		defer cancel()
 		var result interface{}
 		switch req.Method {
 		case "hello.sayHello":
@@ -135,6 +100,19 @@ func main() {
 				}
 			}
 			result = "Hello " + name + ", I am GoLang"
+		case "hello.slow":
+			// 模拟慢请求：params 为毫秒数，调用方放弃等待时提前返回
+			ms, _ := req.Params.(float64)
+			select {
+			case <-time.After(time.Duration(ms) * time.Millisecond):
+				result = "Slow hello, I am GoLang"
+			case <-ctx.Done():
+				r.Response.WriteJsonExit(g.Map{
+					"jsonrpc": "2.0",
+					"error":   g.Map{"code": -32603, "message": ctx.Err().Error()},
+					"id":      req.ID,
+				})
+			}
 		default:
 			result = map[string]interface{}{"error": "method not found"}
 		}
@@ -158,8 +136,8 @@ func main() {
 		}
 		r.Response.WriteJsonExit(g.Map{
 			"go":   "Hello " + displayName + ", I am GoLang",
-			"php":  rpc.Call("php-service", "hello.sayHello", params),
-			"java": rpc.Call("java-service", "hello.sayHello", params),
+			"php":  callService(rpc, "php-service", "hello.sayHello", params),
+			"java": callService(rpc, "java-service", "hello.sayHello", params),
 		})
 	})
 
@@ -174,10 +152,20 @@ func main() {
 
 	// 后台调用其他服务
 	go func() {
-		time.Sleep(500 * time.Millisecond)
+		// 等待其他语言的服务注册并开始监听，各最多 30 秒
+		rpc.OnWaitProgress(func(progress client.WaitProgress) {
+			if progress.State == client.WaitStateWaiting {
+				fmt.Printf("（等待 %s 就绪，第 %d 次）\r", progress.Service, progress.Attempt)
+			}
+		})
+		for _, service := range []string{"php-service", "java-service"} {
+			if err := rpc.WaitForService(context.Background(), service, 30*time.Second); err != nil {
+				fmt.Printf("\n[Go] 警告: %v\n", err)
+			}
+		}
 		fmt.Println("\n[Go 本地] Hello world, I am GoLang")
-		fmt.Println("[Go → PHP] " + rpc.Call("php-service", "hello.sayHello", map[string]string{"name": "GoLang"}))
-		fmt.Println("[Go → Java] " + rpc.Call("java-service", "hello.sayHello", map[string]string{"name": "GoLang"}))
+		fmt.Println("[Go → PHP] " + callService(rpc, "php-service", "hello.sayHello", map[string]string{"name": "GoLang"}))
+		fmt.Println("[Go → Java] " + callService(rpc, "java-service", "hello.sayHello", map[string]string{"name": "GoLang"}))
 		fmt.Println("\n[Go] 服务运行中（Ctrl+C 退出）...")
 	}()
 
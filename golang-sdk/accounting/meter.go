@@ -0,0 +1,315 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/security"
+)
+
+const (
+	// DefaultInterval 默认的汇总周期，每个周期结束时导出一次用量
+	DefaultInterval = time.Minute
+	// DefaultMaxPending 导出失败时默认最多保留的用量记录数，超过时丢弃最早的记录
+	DefaultMaxPending = 10000
+	// AnonymousCaller 未认证请求的调用方
+	AnonymousCaller = "anonymous"
+)
+
+// Usage 一个汇总周期内某个调用方调用某个方法的用量，按调用方、租户和方法汇总
+type Usage struct {
+	WindowStart time.Time `json:"windowStart"`
+	WindowEnd   time.Time `json:"windowEnd"`
+	Service     string    `json:"service,omitempty"`
+	Instance    string    `json:"instance,omitempty"`
+	Method      string    `json:"method"`
+	// Caller 调用方标识：API 密钥或 JWT 认证的用户 ID，未认证时为 AnonymousCaller
+	Caller   string `json:"caller"`
+	Tenant   string `json:"tenant,omitempty"`
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+	// RequestBytes、ResponseBytes 请求参数和处理结果按 JSON 编码的字节数，请求参数包括附件
+	RequestBytes  int64 `json:"requestBytes"`
+	ResponseBytes int64 `json:"responseBytes"`
+	// ComputeTime 业务方法的处理时间之和
+	ComputeTime time.Duration `json:"computeTime"`
+}
+
+// Sample 一次请求的用量
+type Sample struct {
+	Method        string
+	Caller        string
+	Tenant        string
+	RequestBytes  int64
+	ResponseBytes int64
+	ComputeTime   time.Duration
+	Failed        bool
+}
+
+// Options 计量选项
+type Options struct {
+	// Sink 用量的导出目标，必填
+	Sink Sink
+	// Interval 汇总周期，为 0 时使用 DefaultInterval
+	Interval time.Duration
+	// Service、Instance 写入每条用量记录的服务名和实例 ID，多个实例导出到同一目标时区分来源
+	Service  string
+	Instance string
+	// Identify 从请求 context 读取调用方和租户，为 nil 时使用 CallerFromContext
+	Identify func(ctx context.Context) (caller, tenant string)
+	// MaxPending 导出失败时最多保留、在下个周期重试的用量记录数，为 0 时使用 DefaultMaxPending
+	MaxPending int
+	// OnError 导出失败或丢弃记录时调用，为 nil 时忽略
+	OnError func(err error)
+}
+
+// usageKey 用量的汇总维度
+type usageKey struct {
+	method string
+	caller string
+	tenant string
+}
+
+// Meter 按调用方、租户和方法统计请求数、字节数和处理时间，定期导出到 Sink，用于多团队共用网关时的费用分摊
+//
+// NewMeter 启动后台导出，Close 停止并导出剩余的用量：
+//
+//	meter := accounting.NewMeter(&accounting.Options{Sink: accounting.NewCSVSink(file)})
+//	defer meter.Close(ctx)
+//	result, err := meter.Measure(ctx, "order.create", params, func() (interface{}, error) {
+//	    return handler(ctx, params)
+//	})
+type Meter struct {
+	options Options
+
+	mu          sync.Mutex
+	windowStart time.Time
+	usages      map[usageKey]*Usage
+
+	// exportMu 保证导出按周期顺序进行
+	exportMu sync.Mutex
+	pending  []*Usage
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMeter 创建计量器并启动后台导出
+func NewMeter(options *Options) *Meter {
+	m := &Meter{
+		options:     *options,
+		windowStart: time.Now(),
+		usages:      make(map[usageKey]*Usage),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if m.options.Interval <= 0 {
+		m.options.Interval = DefaultInterval
+	}
+	if m.options.MaxPending <= 0 {
+		m.options.MaxPending = DefaultMaxPending
+	}
+	if m.options.Identify == nil {
+		m.options.Identify = CallerFromContext
+	}
+	go m.run()
+	return m
+}
+
+// run 每个汇总周期导出一次用量
+func (m *Meter) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Flush(context.Background())
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Measure 调用 fn 并记录一次请求的用量，调用方和租户从 ctx 读取
+func (m *Meter) Measure(ctx context.Context, method string, params interface{}, fn func() (interface{}, error)) (interface{}, error) {
+	caller, tenant := m.options.Identify(ctx)
+	requestBytes := EncodedSize(params)
+	if attachments := adapter.AttachmentsFromContext(ctx); attachments != nil {
+		for _, name := range attachments.Names() {
+			requestBytes += attachments.Get(name).Size()
+		}
+	}
+
+	start := time.Now()
+	result, err := fn()
+	m.Record(&Sample{
+		Method:        method,
+		Caller:        caller,
+		Tenant:        tenant,
+		RequestBytes:  requestBytes,
+		ResponseBytes: EncodedSize(result),
+		ComputeTime:   time.Since(start),
+		Failed:        err != nil,
+	})
+	return result, err
+}
+
+// Record 将一次请求的用量计入当前周期，调用方为空时记为 AnonymousCaller
+func (m *Meter) Record(sample *Sample) {
+	key := usageKey{method: sample.Method, caller: sample.Caller, tenant: sample.Tenant}
+	if key.caller == "" {
+		key.caller = AnonymousCaller
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := m.usages[key]
+	if !ok {
+		usage = &Usage{
+			Service:  m.options.Service,
+			Instance: m.options.Instance,
+			Method:   key.method,
+			Caller:   key.caller,
+			Tenant:   key.tenant,
+		}
+		m.usages[key] = usage
+	}
+	usage.Requests++
+	if sample.Failed {
+		usage.Errors++
+	}
+	usage.RequestBytes += sample.RequestBytes
+	usage.ResponseBytes += sample.ResponseBytes
+	usage.ComputeTime += sample.ComputeTime
+}
+
+// Current 返回当前周期尚未导出的用量，按方法、调用方和租户排序
+func (m *Meter) Current() []*Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	usages := make([]*Usage, 0, len(m.usages))
+	for _, usage := range m.usages {
+		u := *usage
+		u.WindowStart, u.WindowEnd = m.windowStart, now
+		usages = append(usages, &u)
+	}
+	sortUsages(usages)
+	return usages
+}
+
+// Flush 结束当前周期并导出用量，连同之前导出失败的记录；导出失败时保留记录在下次重试
+func (m *Meter) Flush(ctx context.Context) error {
+	m.exportMu.Lock()
+	defer m.exportMu.Unlock()
+
+	m.mu.Lock()
+	now := time.Now()
+	batch := make([]*Usage, 0, len(m.usages))
+	for _, usage := range m.usages {
+		usage.WindowStart, usage.WindowEnd = m.windowStart, now
+		batch = append(batch, usage)
+	}
+	m.windowStart = now
+	m.usages = make(map[usageKey]*Usage)
+	m.mu.Unlock()
+	sortUsages(batch)
+
+	batch = append(m.pending, batch...)
+	m.pending = nil
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := m.options.Sink.Export(ctx, batch); err != nil {
+		err = fmt.Errorf("failed to export %d usage records: %w", len(batch), err)
+		if dropped := len(batch) - m.options.MaxPending; dropped > 0 {
+			batch = batch[dropped:]
+			m.reportError(fmt.Errorf("dropped %d usage records after export failures", dropped))
+		}
+		m.pending = batch
+		m.reportError(err)
+		return err
+	}
+	return nil
+}
+
+// Close 停止后台导出并导出剩余的用量，只执行一次
+func (m *Meter) Close(ctx context.Context) error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.stop)
+		<-m.done
+		err = m.Flush(ctx)
+	})
+	return err
+}
+
+// reportError 调用 OnError
+func (m *Meter) reportError(err error) {
+	if m.options.OnError != nil {
+		m.options.OnError(err)
+	}
+}
+
+// CallerFromContext 从认证后的安全上下文读取调用方（用户 ID）和租户，未认证时租户取 security.WithTenantID 写入的值
+func CallerFromContext(ctx context.Context) (caller, tenant string) {
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		caller, tenant = sc.UserID, sc.TenantID
+	}
+	if tenant == "" {
+		tenant = security.TenantIDFromContext(ctx)
+	}
+	return caller, tenant
+}
+
+// EncodedSize 返回 v 按 JSON 编码的字节数；[]byte 为其长度，流式结果无法预先计算，返回 0
+func EncodedSize(v interface{}) int64 {
+	switch value := v.(type) {
+	case nil:
+		return 0
+	case []byte:
+		return int64(len(value))
+	case json.RawMessage:
+		return int64(len(value))
+	case *adapter.Stream:
+		return 0
+	}
+	var counter countingWriter
+	if err := json.NewEncoder(&counter).Encode(v); err != nil {
+		return 0
+	}
+	// Encode 在末尾追加换行
+	return counter.n - 1
+}
+
+// countingWriter 只统计写入字节数的 io.Writer
+type countingWriter struct {
+	n int64
+}
+
+// Write 累加字节数
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// sortUsages 按方法、调用方和租户排序，导出结果稳定便于对账
+func sortUsages(usages []*Usage) {
+	sort.Slice(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Caller != b.Caller {
+			return a.Caller < b.Caller
+		}
+		return a.Tenant < b.Tenant
+	})
+}
@@ -0,0 +1,175 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/security"
+)
+
+// memorySink 测试用的导出目标，保存每批导出的用量
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]*Usage
+	err     error
+}
+
+func (s *memorySink) Export(ctx context.Context, usages []*Usage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, usages)
+	return nil
+}
+
+func (s *memorySink) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *memorySink) snapshot() [][]*Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]*Usage(nil), s.batches...)
+}
+
+func TestMeterMeasure(t *testing.T) {
+	sink := &memorySink{}
+	meter := NewMeter(&Options{Sink: sink, Interval: time.Hour, Service: "gateway", Instance: "gw-1"})
+	defer meter.Close(context.Background())
+
+	teamA := adapter.WithSecurityContext(context.Background(), &adapter.SecurityContext{
+		UserID: "team-a", TenantID: "acme", AuthMethod: adapter.AuthMethodAPIKey,
+	})
+	for i := 0; i < 2; i++ {
+		meter.Measure(teamA, "order.create", map[string]interface{}{"id": 1}, func() (interface{}, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "ok", nil
+		})
+	}
+	meter.Measure(teamA, "order.create", nil, func() (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	// 未认证请求记为匿名调用方，租户取 context 中的租户 ID
+	anonymous := security.WithTenantID(context.Background(), "globex")
+	meter.Measure(anonymous, "order.create", []byte("12345"), func() (interface{}, error) {
+		return []byte("123"), nil
+	})
+
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	batches := sink.snapshot()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected 1 batch with 2 usages, got %+v", batches)
+	}
+	anon, a := batches[0][0], batches[0][1]
+	if anon.Caller != AnonymousCaller || anon.Tenant != "globex" || anon.RequestBytes != 5 || anon.ResponseBytes != 3 {
+		t.Errorf("Unexpected anonymous usage: %+v", anon)
+	}
+	// {"id":1} 为 8 字节，"ok" 为 4 字节
+	if a.Caller != "team-a" || a.Tenant != "acme" || a.Requests != 3 || a.Errors != 1 ||
+		a.RequestBytes != 16 || a.ResponseBytes != 8 || a.Service != "gateway" || a.Instance != "gw-1" {
+		t.Errorf("Unexpected team-a usage: %+v", a)
+	}
+	if a.ComputeTime < 10*time.Millisecond || a.WindowEnd.Before(a.WindowStart) {
+		t.Errorf("Unexpected compute time or window: %+v", a)
+	}
+
+	// 没有新请求时不导出空批次
+	if err := meter.Flush(context.Background()); err != nil || len(sink.snapshot()) != 1 {
+		t.Errorf("Expected no export for an empty window, err=%v batches=%d", err, len(sink.snapshot()))
+	}
+}
+
+func TestMeterRetriesFailedExport(t *testing.T) {
+	sink := &memorySink{err: errors.New("unavailable")}
+	var reported []error
+	meter := NewMeter(&Options{
+		Sink:       sink,
+		Interval:   time.Hour,
+		MaxPending: 2,
+		OnError:    func(err error) { reported = append(reported, err) },
+	})
+	defer meter.Close(context.Background())
+
+	for _, method := range []string{"a", "b", "c"} {
+		meter.Record(&Sample{Method: method, Caller: "svc"})
+	}
+	if err := meter.Flush(context.Background()); err == nil {
+		t.Fatal("Expected export error")
+	}
+	// 超过 MaxPending 的最早记录被丢弃
+	if len(reported) != 2 {
+		t.Errorf("Expected drop and export errors, got %v", reported)
+	}
+
+	sink.setErr(nil)
+	meter.Record(&Sample{Method: "d", Caller: "svc"})
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	batches := sink.snapshot()
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("Expected retried usages in one batch, got %+v", batches)
+	}
+	for i, method := range []string{"b", "c", "d"} {
+		if batches[0][i].Method != method {
+			t.Errorf("usage %d method = %s, want %s", i, batches[0][i].Method, method)
+		}
+	}
+}
+
+func TestMeterPeriodicExportAndClose(t *testing.T) {
+	sink := &memorySink{}
+	meter := NewMeter(&Options{Sink: sink, Interval: 20 * time.Millisecond})
+
+	meter.Record(&Sample{Method: "m", Caller: "svc", Tenant: "t"})
+	deadline := time.Now().Add(time.Second)
+	for len(sink.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(sink.snapshot()) != 1 {
+		t.Fatal("Expected periodic export")
+	}
+
+	// 关闭时导出剩余的用量
+	meter.Record(&Sample{Method: "m", Caller: "svc", Tenant: "t"})
+	if current := meter.Current(); len(current) != 1 || current[0].Requests != 1 {
+		t.Errorf("Unexpected current usage: %+v", current)
+	}
+	if err := meter.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if batches := sink.snapshot(); len(batches) != 2 {
+		t.Errorf("Expected final export on Close, got %d batches", len(batches))
+	}
+	if err := meter.Close(context.Background()); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+}
+
+func TestEncodedSize(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  int64
+	}{
+		{nil, 0},
+		{"ok", 4},
+		{map[string]interface{}{"a": 1}, 7},
+		{[]byte("raw"), 3},
+		{adapter.NewStream(func(ctx context.Context, send func(item interface{}) error) error { return nil }), 0},
+	}
+	for _, tt := range tests {
+		if got := EncodedSize(tt.value); got != tt.want {
+			t.Errorf("EncodedSize(%v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPTimeout HTTPSink 默认的请求超时
+const DefaultHTTPTimeout = 10 * time.Second
+
+// Sink 用量的导出目标，每个汇总周期调用一次 Export；返回错误时 Meter 在下个周期重试这批记录
+type Sink interface {
+	Export(ctx context.Context, usages []*Usage) error
+}
+
+// SinkFunc 以函数实现 Sink
+type SinkFunc func(ctx context.Context, usages []*Usage) error
+
+// Export 调用函数
+func (f SinkFunc) Export(ctx context.Context, usages []*Usage) error {
+	return f(ctx, usages)
+}
+
+// csvHeader CSV 的列名，时间为 RFC 3339，处理时间为毫秒
+var csvHeader = []string{
+	"window_start", "window_end", "service", "instance", "method", "caller", "tenant",
+	"requests", "errors", "request_bytes", "response_bytes", "compute_time_ms",
+}
+
+// CSVSink 以 CSV 格式追加写入用量，每条用量一行，写入的第一行为列名
+type CSVSink struct {
+	mu          sync.Mutex
+	writer      io.Writer
+	closer      io.Closer
+	wroteHeader bool
+}
+
+// NewCSVSink 创建写入 w 的 CSV 导出目标
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{writer: w}
+}
+
+// NewCSVFileSink 打开或创建 CSV 文件并追加写入，文件权限为 0600；已有内容的文件不再写入列名
+func NewCSVFileSink(path string) (*CSVSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open accounting file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open accounting file: %w", err)
+	}
+	return &CSVSink{writer: file, closer: file, wroteHeader: info.Size() > 0}, nil
+}
+
+// Export 写入用量
+func (s *CSVSink) Export(ctx context.Context, usages []*Usage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 先写入缓冲区，写入失败时不留下半批记录
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if !s.wroteHeader {
+		writer.Write(csvHeader)
+	}
+	for _, u := range usages {
+		writer.Write([]string{
+			u.WindowStart.UTC().Format(time.RFC3339),
+			u.WindowEnd.UTC().Format(time.RFC3339),
+			u.Service,
+			u.Instance,
+			u.Method,
+			u.Caller,
+			u.Tenant,
+			strconv.FormatInt(u.Requests, 10),
+			strconv.FormatInt(u.Errors, 10),
+			strconv.FormatInt(u.RequestBytes, 10),
+			strconv.FormatInt(u.ResponseBytes, 10),
+			strconv.FormatFloat(float64(u.ComputeTime)/float64(time.Millisecond), 'f', 3, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+	if _, err := s.writer.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+	s.wroteHeader = true
+	return nil
+}
+
+// Close 关闭 NewCSVFileSink 打开的文件
+func (s *CSVSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// HTTPSink 以 JSON 数组 POST 用量到计费系统，响应状态码不是 2xx 时返回错误
+type HTTPSink struct {
+	URL string
+	// Headers 附加的请求头，如计费系统的认证令牌
+	Headers map[string]string
+	// Client 发送请求的 HTTP 客户端，为 nil 时使用超时为 DefaultHTTPTimeout 的客户端
+	Client *http.Client
+}
+
+// Export 发送用量
+func (s *HTTPSink) Export(ctx context.Context, usages []*Usage) error {
+	body, err := json.Marshal(usages)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create usage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send usage: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("usage endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Publisher 发布消息，由 kafka.KafkaProtocolHandler 实现
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key []byte, value interface{}) error
+}
+
+// KafkaSink 将每条用量作为一条 JSON 消息发布到 Kafka 主题，消息键为租户（没有租户时为调用方），
+// 同一租户的用量进入同一分区
+type KafkaSink struct {
+	Publisher Publisher
+	Topic     string
+}
+
+// Export 发布用量，某条发布失败时返回错误，已发布的记录在重试时会重复发布，消费方可按
+// windowStart、instance、method、caller、tenant 去重
+func (s *KafkaSink) Export(ctx context.Context, usages []*Usage) error {
+	for _, u := range usages {
+		key := u.Tenant
+		if key == "" {
+			key = u.Caller
+		}
+		if err := s.Publisher.Publish(ctx, s.Topic, []byte(key), u); err != nil {
+			return fmt.Errorf("failed to publish usage to %s: %w", s.Topic, err)
+		}
+	}
+	return nil
+}
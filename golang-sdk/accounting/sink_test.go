@@ -0,0 +1,124 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testUsages 测试用的用量
+func testUsages() []*Usage {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	return []*Usage{{
+		WindowStart:   start,
+		WindowEnd:     start.Add(time.Minute),
+		Service:       "gateway",
+		Method:        "order.create",
+		Caller:        "team-a",
+		Tenant:        "acme",
+		Requests:      3,
+		Errors:        1,
+		RequestBytes:  120,
+		ResponseBytes: 48,
+		ComputeTime:   1500 * time.Microsecond,
+	}}
+}
+
+func TestCSVSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewCSVSink(&buf)
+	for i := 0; i < 2; i++ {
+		if err := sink.Export(context.Background(), testUsages()); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+	row := "2024-05-01T08:00:00Z,2024-05-01T08:01:00Z,gateway,,order.create,team-a,acme,3,1,120,48,1.500\n"
+	want := strings.Join(csvHeader, ",") + "\n" + row + row
+	if buf.String() != want {
+		t.Errorf("CSV = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCSVFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.csv")
+	for i := 0; i < 2; i++ {
+		sink, err := NewCSVFileSink(path)
+		if err != nil {
+			t.Fatalf("NewCSVFileSink failed: %v", err)
+		}
+		if err := sink.Export(context.Background(), testUsages()); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		sink.Close()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	// 重新打开已有内容的文件时不重复写入列名
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[0], "window_start,") {
+		t.Errorf("Unexpected CSV file:\n%s", data)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []*Usage
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := &HTTPSink{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if err := sink.Export(context.Background(), testUsages()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(received) != 1 || received[0].Caller != "team-a" || received[0].ComputeTime != 1500*time.Microsecond {
+		t.Errorf("Unexpected received usage: %+v", received)
+	}
+
+	status = http.StatusBadGateway
+	if err := sink.Export(context.Background(), testUsages()); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+}
+
+// publisherFunc 以函数实现 Publisher
+type publisherFunc func(ctx context.Context, topic string, key []byte, value interface{}) error
+
+func (f publisherFunc) Publish(ctx context.Context, topic string, key []byte, value interface{}) error {
+	return f(ctx, topic, key, value)
+}
+
+func TestKafkaSink(t *testing.T) {
+	var keys []string
+	usages := append(testUsages(), &Usage{Method: "order.get", Caller: "team-b"})
+	sink := &KafkaSink{Topic: "usage", Publisher: publisherFunc(func(ctx context.Context, topic string, key []byte, value interface{}) error {
+		if topic != "usage" {
+			t.Errorf("topic = %s, want usage", topic)
+		}
+		if _, ok := value.(*Usage); !ok {
+			t.Errorf("Expected *Usage value, got %T", value)
+		}
+		keys = append(keys, string(key))
+		return nil
+	})}
+	if err := sink.Export(context.Background(), usages); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	// 消息键为租户，没有租户时为调用方
+	if len(keys) != 2 || keys[0] != "acme" || keys[1] != "team-b" {
+		t.Errorf("keys = %v, want [acme team-b]", keys)
+	}
+}
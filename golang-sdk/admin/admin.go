@@ -0,0 +1,279 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// Operation 管理操作，params 为 JSON 参数，返回值按 JSON 编码
+type Operation func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// Options 管理接口选项
+type Options struct {
+	// Authenticate 认证请求并授权调用 operation，返回错误时拒绝请求；为 nil 时拒绝所有请求，避免未经认证暴露管理接口
+	Authenticate func(r *http.Request, operation string) error
+}
+
+// operation 已注册的操作
+type operation struct {
+	fn     Operation
+	action bool
+}
+
+// Admin 运行时管理接口
+//
+// 以 HTTP 和 JSON-RPC 两种方式提供已注册的查询和操作，挂载在 /admin/ 下：
+//
+//	GET  /admin/                 列出全部操作
+//	GET  /admin/<name>?k=v       执行查询，查询参数组成 JSON 对象作为 params
+//	POST /admin/<name>           执行查询或操作，请求体为 params
+//	POST /admin/                 JSON-RPC 2.0，method 为操作名
+type Admin struct {
+	authenticate func(r *http.Request, operation string) error
+
+	mu         sync.RWMutex
+	operations map[string]operation
+}
+
+// New 创建管理接口
+func New(options *Options) *Admin {
+	if options == nil {
+		options = &Options{}
+	}
+	return &Admin{
+		authenticate: options.Authenticate,
+		operations:   make(map[string]operation),
+	}
+}
+
+// Query 注册只读查询，可通过 GET 或 POST 调用
+func (a *Admin) Query(name string, fn Operation) {
+	a.register(name, fn, false)
+}
+
+// Action 注册修改运行时状态的操作，只能通过 POST 调用
+func (a *Admin) Action(name string, fn Operation) {
+	a.register(name, fn, true)
+}
+
+// register 注册操作，同名操作被替换
+func (a *Admin) register(name string, fn Operation, action bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.operations[name] = operation{fn: fn, action: action}
+}
+
+// OperationInfo 操作说明
+type OperationInfo struct {
+	Name   string `json:"name"`
+	Action bool   `json:"action"`
+}
+
+// Operations 返回已注册的操作，按名称排序
+func (a *Admin) Operations() []OperationInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	infos := make([]OperationInfo, 0, len(a.operations))
+	for name, op := range a.operations {
+		infos = append(infos, OperationInfo{Name: name, Action: op.action})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// Invoke 执行操作，不做认证，供进程内调用
+func (a *Admin) Invoke(ctx context.Context, name string, params json.RawMessage) (interface{}, error) {
+	op, ok := a.lookup(name)
+	if !ok {
+		return nil, notFound(name)
+	}
+	return op.fn(ctx, params)
+}
+
+// lookup 查找操作
+func (a *Admin) lookup(name string) (operation, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	op, ok := a.operations[name]
+	return op, ok
+}
+
+// ServeHTTP 处理 /admin/ 下的请求
+func (a *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin"), "/")
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			if err := a.authorize(r, ""); err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, a.Operations())
+		case http.MethodPost:
+			a.serveJSONRPC(w, r)
+		default:
+			writeMethodNotAllowed(w, r.Method)
+		}
+		return
+	}
+
+	if err := a.authorize(r, name); err != nil {
+		writeError(w, err)
+		return
+	}
+	op, ok := a.lookup(name)
+	if !ok {
+		writeError(w, notFound(name))
+		return
+	}
+
+	var params json.RawMessage
+	switch {
+	case r.Method == http.MethodGet && !op.action:
+		query := make(map[string]string)
+		for key, values := range r.URL.Query() {
+			query[key] = values[0]
+		}
+		params, _ = json.Marshal(query)
+	case r.Method == http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid params"))
+			return
+		}
+	default:
+		writeMethodNotAllowed(w, r.Method)
+		return
+	}
+
+	result, err := op.fn(r.Context(), params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// jsonRpcRequest JSON-RPC 请求
+type jsonRpcRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      interface{}     `json:"id"`
+}
+
+// jsonRpcResponse JSON-RPC 响应
+type jsonRpcResponse struct {
+	Jsonrpc string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *jsonRpcError `json:"error,omitempty"`
+	ID      interface{}   `json:"id"`
+}
+
+// jsonRpcError JSON-RPC 错误对象，data 为框架错误的跨语言传输格式
+type jsonRpcError struct {
+	Code    int                           `json:"code"`
+	Message string                        `json:"message"`
+	Data    *frameworkerrors.ErrorPayload `json:"data,omitempty"`
+}
+
+// serveJSONRPC 以 JSON-RPC 2.0 执行操作
+func (a *Admin) serveJSONRPC(w http.ResponseWriter, r *http.Request) {
+	var request jsonRpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusOK, jsonRpcResponse{
+			Jsonrpc: "2.0",
+			Error:   &jsonRpcError{Code: -32700, Message: "Parse error"},
+		})
+		return
+	}
+
+	result, err := a.invokeAuthorized(r, request.Method, request.Params)
+	response := jsonRpcResponse{Jsonrpc: "2.0", Result: result, ID: request.ID}
+	if err != nil {
+		payload := toFrameworkError(err).ToPayload()
+		response.Result = nil
+		response.Error = &jsonRpcError{
+			Code:    frameworkerrors.ErrorCode(payload.Code).ToJSONRPCCode(),
+			Message: payload.Message,
+			Data:    payload,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// DecodeParams 将操作参数解码到 v，参数为空时不修改 v，格式错误时返回 BadRequest
+func DecodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid params")
+	}
+	return nil
+}
+
+// invokeAuthorized 认证后执行操作
+func (a *Admin) invokeAuthorized(r *http.Request, name string, params json.RawMessage) (interface{}, error) {
+	if err := a.authorize(r, name); err != nil {
+		return nil, err
+	}
+	return a.Invoke(r.Context(), name, params)
+}
+
+// authorize 认证请求，未配置认证时拒绝
+func (a *Admin) authorize(r *http.Request, name string) error {
+	if a.authenticate == nil {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.Forbidden, "admin authentication is not configured")
+	}
+	if err := a.authenticate(r, name); err != nil {
+		if _, ok := frameworkerrors.FromError(err); ok {
+			return err
+		}
+		return frameworkerrors.Wrap(err, frameworkerrors.Unauthorized, "admin authentication failed")
+	}
+	return nil
+}
+
+// notFound 操作不存在
+func notFound(name string) error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("admin operation %s not found", name))
+}
+
+// writeMethodNotAllowed 请求方法不支持，返回 405
+func writeMethodNotAllowed(w http.ResponseWriter, method string) {
+	fe := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, fmt.Sprintf("method %s not allowed", method))
+	writeJSON(w, http.StatusMethodNotAllowed, fe.ToPayload())
+}
+
+// toFrameworkError 非框架错误视为内部错误
+func toFrameworkError(err error) *frameworkerrors.FrameworkError {
+	if fe, ok := frameworkerrors.FromError(err); ok {
+		return fe
+	}
+	return frameworkerrors.Wrap(err, frameworkerrors.InternalError, err.Error())
+}
+
+// writeError 按错误码的 HTTP 状态返回跨语言传输格式的错误
+func writeError(w http.ResponseWriter, err error) {
+	fe := toFrameworkError(err)
+	writeJSON(w, fe.Code.ToHTTPStatus(), fe.ToPayload())
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// newTestAdmin 令牌为 secret 时通过认证，记录最近一次认证的操作名
+func newTestAdmin(authorized *string) *Admin {
+	a := New(&Options{Authenticate: func(r *http.Request, operation string) error {
+		*authorized = operation
+		if r.Header.Get("X-Admin-Token") != "secret" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}})
+	a.Query("echo", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p map[string]string
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+	a.Action("reset", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Service string `json:"service"`
+		}
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Service == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "service is required")
+		}
+		return "reset " + p.Service, nil
+	})
+	return a
+}
+
+func TestAdminHTTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		token      string
+		wantStatus int
+		wantBody   string
+		wantOp     string
+	}{
+		{"列出操作", http.MethodGet, "/admin/", "", "secret", http.StatusOK, `[{"name":"echo","action":false},{"name":"reset","action":true}]`, ""},
+		{"GET 查询", http.MethodGet, "/admin/echo?service=order", "", "secret", http.StatusOK, `{"service":"order"}`, "echo"},
+		{"POST 操作", http.MethodPost, "/admin/reset", `{"service":"order"}`, "secret", http.StatusOK, `"reset order"`, "reset"},
+		{"操作不接受 GET", http.MethodGet, "/admin/reset?service=order", "", "secret", http.StatusMethodNotAllowed, "", "reset"},
+		{"参数错误", http.MethodPost, "/admin/reset", `{}`, "secret", http.StatusBadRequest, "", "reset"},
+		{"参数格式错误", http.MethodPost, "/admin/reset", `{`, "secret", http.StatusBadRequest, "", "reset"},
+		{"认证失败", http.MethodPost, "/admin/reset", `{"service":"order"}`, "wrong", http.StatusUnauthorized, "", "reset"},
+		{"未注册的操作", http.MethodGet, "/admin/missing", "", "secret", http.StatusNotFound, "", "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var op string
+			a := newTestAdmin(&op)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("X-Admin-Token", tt.token)
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+			if op != tt.wantOp {
+				t.Errorf("authenticated operation = %q, want %q", op, tt.wantOp)
+			}
+		})
+	}
+}
+
+func TestAdminJSONRPC(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantValue interface{}
+	}{
+		{"调用操作", `{"jsonrpc":"2.0","method":"reset","params":{"service":"order"},"id":1}`, 0, "reset order"},
+		{"业务错误", `{"jsonrpc":"2.0","method":"reset","params":{},"id":2}`, frameworkerrors.BadRequest.ToJSONRPCCode(), nil},
+		{"未注册的操作", `{"jsonrpc":"2.0","method":"missing","id":3}`, frameworkerrors.NotFound.ToJSONRPCCode(), nil},
+		{"解析错误", `{`, -32700, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var op string
+			a := newTestAdmin(&op)
+			req := httptest.NewRequest(http.MethodPost, "/admin/", strings.NewReader(tt.body))
+			req.Header.Set("X-Admin-Token", "secret")
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, req)
+
+			var resp struct {
+				Result interface{} `json:"result"`
+				Error  *struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response %s: %v", rec.Body.String(), err)
+			}
+			if tt.wantCode == 0 {
+				if resp.Error != nil || resp.Result != tt.wantValue {
+					t.Errorf("response = %s, want result %v", rec.Body.String(), tt.wantValue)
+				}
+				return
+			}
+			if resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("response = %s, want error code %d", rec.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAdminWithoutAuthentication(t *testing.T) {
+	a := New(nil)
+	a.Query("status", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return "ok", nil
+	})
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/status", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+
+	// 进程内调用不经过认证
+	if result, err := a.Invoke(context.Background(), "status", nil); err != nil || result != "ok" {
+		t.Errorf("Invoke = %v, %v; want ok", result, err)
+	}
+}
@@ -0,0 +1,342 @@
+// Package catalog 服务目录页面：列出注册中心中的服务及其版本、语言、健康状态和协议端口，并可在页面上填写方法和
+// JSON 参数直接调用，用于跨语言联调时的手工测试
+//
+// 调用经 Options.Call 发出，framework.Server 挂载时为服务的客户端（按注册中心路由，以 JSON-RPC 等协议连接目标实例），
+// 因此页面上的调用与其他服务之间的调用经过相同的路由、负载均衡和重试
+package catalog
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/registry"
+)
+
+// DefaultCallTimeout 页面调用的默认超时
+const DefaultCallTimeout = 30 * time.Second
+
+// maxCallBody 调用请求体的上限
+const maxCallBody = 1 << 20
+
+// healthCheckConcurrency 同时进行的实例健康检查数
+const healthCheckConcurrency = 16
+
+//go:embed index.html
+var indexHTML []byte
+
+// Caller 调用服务的方法，签名与 client.FrameworkClient.Call 相同；request 为 json.RawMessage，response 为 *json.RawMessage
+type Caller func(ctx context.Context, service, method string, request, response interface{}) error
+
+// Options 服务目录配置
+type Options struct {
+	// Registry 列出服务的注册中心，须实现 registry.PatternDiscoverer
+	Registry registry.ServiceRegistry
+	// Call 页面调用服务的方法，为 nil 时调用返回 NotImplemented 错误，页面只展示服务
+	Call Caller
+	// Authenticate 认证请求，operation 为 list（页面和服务列表）或 call（调用方法）；为 nil 时不认证，
+	// 只应在开发环境或只监听回环地址时使用
+	Authenticate func(r *http.Request, operation string) error
+	// CallTimeout 调用的超时，为 0 时使用 DefaultCallTimeout
+	CallTimeout time.Duration
+}
+
+// Instance 服务实例
+type Instance struct {
+	ID             string                `json:"id"`
+	Version        string                `json:"version,omitempty"`
+	Language       string                `json:"language,omitempty"`
+	Address        string                `json:"address"`
+	Port           int                   `json:"port"`
+	Protocols      []Protocol            `json:"protocols"`
+	Serializations []string              `json:"serializations,omitempty"`
+	Health         registry.HealthStatus `json:"health"`
+	RegisteredAt   time.Time             `json:"registeredAt,omitempty"`
+	Metadata       map[string]string     `json:"metadata,omitempty"`
+}
+
+// Protocol 实例支持的协议及其端口，元数据中没有该协议的端口时为实例端口
+type Protocol struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+// Service 同名服务的全部实例
+type Service struct {
+	Name      string     `json:"name"`
+	Versions  []string   `json:"versions"`
+	Languages []string   `json:"languages"`
+	Healthy   int        `json:"healthy"`
+	Instances []Instance `json:"instances"`
+}
+
+// CallRequest 页面发起的调用
+type CallRequest struct {
+	Service string          `json:"service"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// CallResult 调用结果，失败时 Error 为跨语言传输格式的框架错误
+type CallResult struct {
+	Result   json.RawMessage               `json:"result,omitempty"`
+	Error    *frameworkerrors.ErrorPayload `json:"error,omitempty"`
+	Duration string                        `json:"duration"`
+}
+
+// Catalog 服务目录，实现 http.Handler，挂载在以 / 结尾的路径下：
+//
+//	GET  <path>              页面
+//	GET  <path>api/services  服务列表
+//	POST <path>api/call      调用方法，请求体为 CallRequest
+type Catalog struct {
+	options Options
+}
+
+// New 创建服务目录
+func New(options *Options) *Catalog {
+	c := &Catalog{}
+	if options != nil {
+		c.options = *options
+	}
+	if c.options.CallTimeout <= 0 {
+		c.options.CallTimeout = DefaultCallTimeout
+	}
+	return c
+}
+
+// ServeHTTP 按路径的结尾分派请求，挂载路径不影响分派；页面以相对路径请求接口，须以 / 结尾的路径访问
+func (c *Catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/api/services"):
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, r.Method)
+			return
+		}
+		if err := c.authorize(r, "list"); err != nil {
+			writeError(w, err)
+			return
+		}
+		services, err := c.Services(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, services)
+	case strings.HasSuffix(r.URL.Path, "/api/call"):
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, r.Method)
+			return
+		}
+		if err := c.authorize(r, "call"); err != nil {
+			writeError(w, err)
+			return
+		}
+		c.serveCall(w, r)
+	case strings.HasSuffix(r.URL.Path, "/"):
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeMethodNotAllowed(w, r.Method)
+			return
+		}
+		if err := c.authorize(r, "list"); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(indexHTML)
+	default:
+		writeError(w, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("%s not found", r.URL.Path)))
+	}
+}
+
+// Services 返回注册中心中的所有服务（按名称排序），实例带健康检查的结果
+func (c *Catalog) Services(ctx context.Context) ([]Service, error) {
+	if c.options.Registry == nil {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "registry is not configured")
+	}
+	view, err := registry.DiscoverByPrefix(ctx, c.options.Registry, "")
+	if errors.Is(err, registry.ErrPatternNotSupported) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotImplemented, err.Error())
+	}
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.ServiceUnavailable, "failed to list services")
+	}
+
+	names := make([]string, 0, len(view))
+	for name := range view {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	services := make([]Service, len(names))
+	for i, name := range names {
+		instances := view[name]
+		sort.Slice(instances, func(a, b int) bool { return instances[a].ID < instances[b].ID })
+		service := Service{Name: name, Versions: []string{}, Languages: []string{}, Instances: make([]Instance, len(instances))}
+		for j, info := range instances {
+			service.Instances[j] = newInstance(info)
+			service.Versions = appendMissing(service.Versions, info.Version)
+			service.Languages = appendMissing(service.Languages, info.Language)
+		}
+		sort.Strings(service.Versions)
+		sort.Strings(service.Languages)
+		services[i] = service
+	}
+	c.checkHealth(ctx, services)
+	return services, nil
+}
+
+// checkHealth 并发检查实例的健康状态，检查失败的实例为 unknown
+func (c *Catalog) checkHealth(ctx context.Context, services []Service) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, healthCheckConcurrency)
+	for i := range services {
+		for j := range services[i].Instances {
+			instance := &services[i].Instances[j]
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				status, err := c.options.Registry.HealthCheck(ctx, instance.ID)
+				if err != nil {
+					status = registry.HealthStatusUnknown
+				}
+				instance.Health = status
+			}()
+		}
+	}
+	wg.Wait()
+
+	for i := range services {
+		for _, instance := range services[i].Instances {
+			if instance.Health == registry.HealthStatusHealthy {
+				services[i].Healthy++
+			}
+		}
+	}
+}
+
+// serveCall 调用方法并返回结果和耗时，调用失败时仍返回 200，错误在 CallResult.Error 中
+func (c *Catalog) serveCall(w http.ResponseWriter, r *http.Request) {
+	var request CallRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxCallBody)).Decode(&request); err != nil {
+		writeError(w, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid call request"))
+		return
+	}
+	if request.Service == "" || request.Method == "" {
+		writeError(w, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "service and method are required"))
+		return
+	}
+	if c.options.Call == nil {
+		writeError(w, frameworkerrors.NewFrameworkError(frameworkerrors.NotImplemented, "catalog calls are not enabled"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), c.options.CallTimeout)
+	defer cancel()
+	var params interface{}
+	if len(request.Params) > 0 && string(request.Params) != "null" {
+		params = request.Params
+	}
+	var response json.RawMessage
+	start := time.Now()
+	err := c.options.Call(ctx, request.Service, request.Method, params, &response)
+	result := &CallResult{Duration: time.Since(start).Round(time.Microsecond).String()}
+	if err != nil {
+		result.Error = toFrameworkError(err).ToPayload()
+	} else if len(response) > 0 {
+		result.Result = response
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// authorize 认证请求，未配置认证时放行
+func (c *Catalog) authorize(r *http.Request, operation string) error {
+	if c.options.Authenticate == nil {
+		return nil
+	}
+	if err := c.options.Authenticate(r, operation); err != nil {
+		if _, ok := frameworkerrors.FromError(err); ok {
+			return err
+		}
+		return frameworkerrors.Wrap(err, frameworkerrors.Unauthorized, "catalog authentication failed")
+	}
+	return nil
+}
+
+// newInstance 返回实例的展示信息，协议按声明顺序并带各自的端口
+func newInstance(info *registry.ServiceInfo) Instance {
+	instance := Instance{
+		ID:             info.ID,
+		Version:        info.Version,
+		Language:       info.Language,
+		Address:        info.Address,
+		Port:           info.Port,
+		Protocols:      make([]Protocol, 0, len(info.Protocols)),
+		Serializations: info.Serializations,
+		Health:         registry.HealthStatusUnknown,
+		RegisteredAt:   info.RegisteredAt,
+		Metadata:       info.Metadata,
+	}
+	for _, name := range info.Protocols {
+		port := info.Port
+		if p, err := strconv.Atoi(info.Metadata[registry.MetadataPortPrefix+name]); err == nil && p > 0 {
+			port = p
+		}
+		instance.Protocols = append(instance.Protocols, Protocol{Name: name, Port: port})
+	}
+	return instance
+}
+
+// appendMissing 追加 list 中还没有的非空值
+func appendMissing(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// toFrameworkError 非框架错误视为内部错误
+func toFrameworkError(err error) *frameworkerrors.FrameworkError {
+	if fe, ok := frameworkerrors.FromError(err); ok {
+		return fe
+	}
+	return frameworkerrors.Wrap(err, frameworkerrors.InternalError, err.Error())
+}
+
+// writeMethodNotAllowed 请求方法不支持，返回 405
+func writeMethodNotAllowed(w http.ResponseWriter, method string) {
+	fe := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, fmt.Sprintf("method %s not allowed", method))
+	writeJSON(w, http.StatusMethodNotAllowed, fe.ToPayload())
+}
+
+// writeError 按错误码的 HTTP 状态返回跨语言传输格式的错误
+func writeError(w http.ResponseWriter, err error) {
+	fe := toFrameworkError(err)
+	writeJSON(w, fe.Code.ToHTTPStatus(), fe.ToPayload())
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestCatalogServices(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	ctx := context.Background()
+	reg.Register(ctx, &registry.ServiceInfo{
+		ID: "order-1", Name: "order-service", Version: "1.2.0", Language: "java", Address: "10.0.0.1", Port: 8080,
+		Protocols: []string{"rest", "jsonrpc"}, Metadata: map[string]string{registry.MetadataPortPrefix + "jsonrpc": "8081"},
+	})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "order-2", Name: "order-service", Version: "1.1.0", Language: "go", Address: "10.0.0.2", Port: 8080})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "user-1", Name: "user-service", Version: "2.0.0", Language: "php", Address: "10.0.0.3", Port: 9000})
+
+	c := New(&Options{Registry: reg})
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/catalog/api/services")
+	if err != nil {
+		t.Fatalf("GET services failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var services []Service
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		t.Fatalf("Failed to decode services: %v", err)
+	}
+	if len(services) != 2 || services[0].Name != "order-service" || services[1].Name != "user-service" {
+		t.Fatalf("Unexpected services: %+v", services)
+	}
+	order := services[0]
+	if strings.Join(order.Versions, ",") != "1.1.0,1.2.0" || strings.Join(order.Languages, ",") != "go,java" {
+		t.Errorf("Versions = %v, Languages = %v", order.Versions, order.Languages)
+	}
+	if order.Healthy != 2 || order.Instances[0].Health != registry.HealthStatusHealthy {
+		t.Errorf("Expected healthy instances, got %d: %+v", order.Healthy, order.Instances)
+	}
+	if protocols := order.Instances[0].Protocols; len(protocols) != 2 || protocols[0].Port != 8080 || protocols[1].Port != 8081 {
+		t.Errorf("Unexpected protocols: %+v", protocols)
+	}
+
+	page, err := http.Get(server.URL + "/catalog/")
+	if err != nil {
+		t.Fatalf("GET page failed: %v", err)
+	}
+	page.Body.Close()
+	if page.StatusCode != http.StatusOK || !strings.HasPrefix(page.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("Page status = %d, content type = %s", page.StatusCode, page.Header.Get("Content-Type"))
+	}
+}
+
+func TestCatalogCall(t *testing.T) {
+	call := func(ctx context.Context, service, method string, request, response interface{}) error {
+		if method == "hello.fail" {
+			return frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "user not found")
+		}
+		params := request.(json.RawMessage)
+		*response.(*json.RawMessage) = json.RawMessage(`{"service":"` + service + `","params":` + string(params) + `}`)
+		return nil
+	}
+	c := New(&Options{
+		Registry: registry.NewMemoryRegistry(nil),
+		Call:     call,
+		Authenticate: func(r *http.Request, operation string) error {
+			if operation == "call" && r.Header.Get("X-Token") != "secret" {
+				return errors.New("invalid token")
+			}
+			return nil
+		},
+	})
+
+	post := func(body, token string) (*httptest.ResponseRecorder, *CallResult) {
+		req := httptest.NewRequest(http.MethodPost, "/catalog/api/call", strings.NewReader(body))
+		req.Header.Set("X-Token", token)
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, req)
+		var result CallResult
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec, &result
+	}
+
+	rec, result := post(`{"service":"hello-service","method":"hello.sayHello","params":{"name":"Go"}}`, "secret")
+	if rec.Code != http.StatusOK || result.Error != nil {
+		t.Fatalf("Call failed: %d %s", rec.Code, rec.Body.String())
+	}
+	if string(result.Result) != `{"service":"hello-service","params":{"name":"Go"}}` {
+		t.Errorf("Result = %s", result.Result)
+	}
+
+	// 调用失败时错误为跨语言传输格式
+	_, result = post(`{"service":"hello-service","method":"hello.fail"}`, "secret")
+	if result.Error == nil || result.Error.Code != int(frameworkerrors.NotFound) {
+		t.Errorf("Expected NotFound error payload, got %+v", result.Error)
+	}
+
+	if rec, _ := post(`{"service":"hello-service","method":"hello.sayHello"}`, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+	if rec, _ := post(`{"method":"hello.sayHello"}`, "secret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without service, got %d", rec.Code)
+	}
+}
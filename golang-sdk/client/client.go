@@ -2,6 +2,13 @@ package client
 
 import (
 	"context"
+	"time"
+
+	"github.com/framework/golang-sdk/connection"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
 )
 
 // Response 异步调用响应
@@ -10,6 +17,14 @@ type Response struct {
 	Error error
 }
 
+// CallTarget 扇出调用中的单次调用
+type CallTarget struct {
+	Service  string
+	Method   string
+	Request  interface{}
+	Response interface{}
+}
+
 // ServiceHandler 服务处理器
 type ServiceHandler interface{}
 
@@ -18,6 +33,9 @@ type FrameworkClient interface {
 	// Call 同步调用服务
 	Call(ctx context.Context, service, method string, request interface{}, response interface{}) error
 
+	// CallAll 并发调用多个服务（扇出），部分调用失败时返回 *errors.MultiError
+	CallAll(ctx context.Context, targets []CallTarget) error
+
 	// CallAsync 异步调用服务
 	CallAsync(ctx context.Context, service, method string, request interface{}) (<-chan Response, error)
 
@@ -37,5 +55,47 @@ type FrameworkClient interface {
 // Config 客户端配置
 type Config struct {
 	ServiceRegistry string
+	// Registry 发现目标服务实例的注册中心，为 nil 时调用返回 NotFound
+	Registry registry.ServiceRegistry
+	// LoadBalancer 在服务实例间选择端点，为 nil 时使用轮询
+	LoadBalancer router.LoadBalancer
+	// Connection 到服务端点的连接池配置，为 nil 时使用 connection.DefaultConnectionConfig
+	Connection *connection.ConnectionConfig
+	// Services 按目标服务名的调用配置
+	Services map[string]ServiceOptions
+	// Broker 经消息中间件调用服务（ServiceOptions.Messaging）时使用的消息中间件
+	Broker messaging.Broker
+	// Rules 调用前按优先级应用的路由规则，生效且匹配的规则将调用重定向到其目标服务，并使用目标服务的调用配置
+	Rules []*router.RoutingRule
 	// 其他配置项...
 }
+
+// ServiceOptions 调用单个目标服务的配置
+type ServiceOptions struct {
+	// Timeout 单次调用超时（含重试），为 0 时只受调用方 context 控制
+	Timeout time.Duration
+	// AdaptiveTimeout 不为 nil 时每次尝试的超时按该服务方法的历史延迟计算，并记录每次尝试的延迟；
+	// 与 Timeout 同时设置时两者都生效
+	AdaptiveTimeout *resilience.AdaptiveTimeout
+	// RetryPolicy 重试策略，为 nil 时不重试；只重试幂等方法，除非设置 RetryPolicy.RetryNonIdempotent
+	RetryPolicy *resilience.RetryPolicy
+	// IdempotentMethods 调用方声明的幂等方法（完整方法名，如 user.getUser），
+	// 与服务实例注册时声明的幂等方法（registry.MetadataIdempotentMethods）合并
+	IdempotentMethods []string
+	// CircuitBreaker 熔断配置，为 nil 时不熔断
+	CircuitBreaker *CircuitBreakerOptions
+	// Messaging 为 true 时经 Config.Broker 以请求/响应消息调用服务，不需要与服务实例直连
+	Messaging bool
+	// DarkLaunch 不为 nil 时按比例将 Call 同时发给候选服务并比较响应，调用方只收到本服务的响应
+	DarkLaunch *DarkLaunchOptions
+}
+
+// CircuitBreakerOptions 熔断配置
+//
+// 连续 FailureThreshold 次服务端错误（5xx、超时、连接错误等）后熔断，Timeout 后放行探测请求，
+// 连续 SuccessThreshold 次成功后恢复；客户端错误不计入失败
+type CircuitBreakerOptions struct {
+	FailureThreshold int
+	SuccessThreshold int
+	Timeout          time.Duration
+}
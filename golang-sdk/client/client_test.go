@@ -2,9 +2,22 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
@@ -183,3 +196,447 @@ func TestStreamReturnsChannel(t *testing.T) {
 		t.Fatal("Stream should return a channel")
 	}
 }
+
+// TestServiceOptions 测试按服务查找调用配置
+func TestServiceOptions(t *testing.T) {
+	policy := resilience.NewRetryPolicy(5, 10*time.Millisecond, time.Second, 2.0)
+	config := &Config{
+		ServiceRegistry: "http://localhost:2379",
+		Services: map[string]ServiceOptions{
+			"payment-service": {Timeout: 2 * time.Second, RetryPolicy: policy},
+		},
+	}
+
+	client := NewFrameworkClient(config).(*DefaultFrameworkClient)
+
+	options := client.serviceOptions("payment-service")
+	if options.Timeout != 2*time.Second || options.RetryPolicy != policy {
+		t.Errorf("Unexpected options for payment-service: %+v", options)
+	}
+
+	if options := client.serviceOptions("other"); options.Timeout != 0 || options.RetryPolicy != nil {
+		t.Errorf("Expected zero options for unconfigured service, got %+v", options)
+	}
+
+	// 调用方 context 已取消时不再发起调用
+	if err := client.Start(); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Call(ctx, "payment-service", "pay", nil, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestCallAll 测试扇出调用的错误聚合
+func TestCallAll(t *testing.T) {
+	config := &Config{
+		ServiceRegistry: "http://localhost:2379",
+	}
+
+	client := NewFrameworkClient(config)
+	if err := client.CallAll(context.Background(), nil); err == nil {
+		t.Fatal("Expected error when client not started")
+	}
+	if err := client.Start(); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	err := client.CallAll(context.Background(), []CallTarget{
+		{Service: "user-service", Method: "get"},
+		{Service: "payment-service", Method: "pay"},
+	})
+	var multi *errors.MultiError
+	if !stderrors.As(err, &multi) {
+		t.Fatalf("Expected *MultiError, got %v", err)
+	}
+
+	errs := multi.Errors()
+	if multi.Total != 2 || len(errs) != 2 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	for i, service := range []string{"user-service", "payment-service"} {
+		if errs[i].Index != i || errs[i].Service != service || errs[i].Attempt != 1 {
+			t.Errorf("Unexpected error for %s: %+v", service, errs[i])
+		}
+	}
+
+	if err := client.CallAll(context.Background(), nil); err != nil {
+		t.Errorf("Expected nil for empty targets, got %v", err)
+	}
+}
+
+// newJsonRpcServer 启动处理 hello.sayHello 的 JSON-RPC 服务，fail 返回 true 时以 503 响应
+func newJsonRpcServer(t *testing.T, fail func() bool) (*httptest.Server, string, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != JsonRpcPath {
+			http.NotFound(w, r)
+			return
+		}
+		if fail != nil && fail() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var req struct {
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+			ID     int               `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if req.Method == "hello.sayHello" {
+			resp["result"] = map[string]string{"message": "Hello " + req.Params["name"]}
+		} else {
+			payload := errors.NewFrameworkError(errors.NotFound, "method not found: "+req.Method).ToPayload()
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "Method not found", "data": payload}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return server, host, port
+}
+
+// TestCallThroughRegistry 测试经服务发现、负载均衡和 JSON-RPC 传输的调用
+func TestCallThroughRegistry(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	_, host, port := newJsonRpcServer(t, nil)
+	if err := reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port}); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+
+	client := NewFrameworkClient(&Config{Registry: reg})
+	if err := client.Start(); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	t.Run("调用成功并解码结果", func(t *testing.T) {
+		var resp struct {
+			Message string `json:"message"`
+		}
+		if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, &resp); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if resp.Message != "Hello Go" {
+			t.Errorf("Expected Hello Go, got %q", resp.Message)
+		}
+	})
+
+	t.Run("还原服务端的结构化错误", func(t *testing.T) {
+		err := client.Call(ctx, "hello-service", "hello.unknown", nil, nil)
+		fe, ok := errors.FromError(err)
+		if !ok || fe.Code != errors.NotFound || fe.Message != "method not found: hello.unknown" {
+			t.Errorf("Expected NotFound framework error, got %v", err)
+		}
+	})
+
+	t.Run("未注册的服务", func(t *testing.T) {
+		err := client.Call(ctx, "missing-service", "hello.sayHello", nil, nil)
+		if fe, ok := errors.FromError(err); !ok || fe.Code != errors.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("未配置注册中心", func(t *testing.T) {
+		client := NewFrameworkClient(&Config{})
+		client.Start()
+		err := client.Call(ctx, "hello-service", "hello.sayHello", nil, nil)
+		if fe, ok := errors.FromError(err); !ok || fe.Code != errors.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+}
+
+// TestCallThroughMessaging 测试经消息中间件调用服务
+func TestCallThroughMessaging(t *testing.T) {
+	ctx := context.Background()
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	server := messaging.NewRPCServer(broker, "hello-service", func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		var params map[string]string
+		if err := json.Unmarshal(request.Payload, &params); err != nil {
+			return nil, err
+		}
+		return map[string]string{"message": "Hello " + params["name"]}, nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start rpc server: %v", err)
+	}
+	defer server.Stop(ctx)
+
+	t.Run("不需要注册中心", func(t *testing.T) {
+		client := NewFrameworkClient(&Config{
+			Broker:   broker,
+			Services: map[string]ServiceOptions{"hello-service": {Messaging: true}},
+		})
+		client.Start()
+		defer client.Shutdown(ctx)
+
+		var resp struct {
+			Message string `json:"message"`
+		}
+		if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, &resp); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if resp.Message != "Hello Go" {
+			t.Errorf("Expected Hello Go, got %q", resp.Message)
+		}
+	})
+
+	t.Run("未配置消息中间件", func(t *testing.T) {
+		client := NewFrameworkClient(&Config{
+			Services: map[string]ServiceOptions{"hello-service": {Messaging: true}},
+		})
+		client.Start()
+		err := client.Call(ctx, "hello-service", "hello.sayHello", nil, nil)
+		if fe, ok := errors.FromError(err); !ok || fe.Code != errors.InternalError {
+			t.Errorf("Expected InternalError, got %v", err)
+		}
+	})
+}
+
+// TestCallRetryAndCircuitBreaker 测试服务端故障时的重试和熔断
+func TestCallRetryAndCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	var requests, failures atomic.Int32
+	failures.Store(2)
+	_, host, port := newJsonRpcServer(t, func() bool {
+		requests.Add(1)
+		return failures.Add(-1) >= 0
+	})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port,
+		Metadata: map[string]string{registry.MetadataIdempotentMethods: "hello.sayHello"}})
+
+	client := NewFrameworkClient(&Config{
+		Registry: reg,
+		Services: map[string]ServiceOptions{
+			"hello-service": {
+				RetryPolicy:    resilience.NewRetryPolicy(3, time.Millisecond, 10*time.Millisecond, 2.0),
+				CircuitBreaker: &CircuitBreakerOptions{FailureThreshold: 3, SuccessThreshold: 1, Timeout: time.Minute},
+			},
+		},
+	})
+	client.Start()
+
+	// 前两次 503 后重试成功
+	if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, nil); err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+
+	// 客户端错误不计入熔断失败
+	for i := 0; i < 5; i++ {
+		client.Call(ctx, "hello-service", "hello.unknown", nil, nil)
+	}
+
+	// 连续服务端错误后熔断，不再发送请求
+	failures.Store(100)
+	client.Call(ctx, "hello-service", "hello.sayHello", nil, nil)
+	sent := requests.Load()
+	err := client.Call(ctx, "hello-service", "hello.sayHello", nil, nil)
+	if fe, ok := errors.FromError(err); !ok || fe.Code != errors.ServiceUnavailable {
+		t.Errorf("Expected ServiceUnavailable, got %v", err)
+	}
+	if got := requests.Load(); got != sent {
+		t.Errorf("Expected no requests while circuit is open, got %d more", got-sent)
+	}
+}
+
+// TestCallRetryOnlyIdempotent 测试只自动重试声明为幂等的方法
+func TestCallRetryOnlyIdempotent(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	var requests atomic.Int32
+	_, host, port := newJsonRpcServer(t, func() bool {
+		requests.Add(1)
+		return true
+	})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port,
+		Metadata: map[string]string{registry.MetadataIdempotentMethods: "hello.sayHello"}})
+
+	tests := []struct {
+		name               string
+		method             string
+		idempotentMethods  []string
+		retryNonIdempotent bool
+		wantCalls          int32
+	}{
+		{"实例声明幂等", "hello.sayHello", nil, false, 3},
+		{"未声明幂等", "hello.notify", nil, false, 1},
+		{"调用方声明幂等", "hello.notify", []string{"hello.notify"}, false, 3},
+		{"显式重试非幂等方法", "hello.notify", nil, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := resilience.NewRetryPolicyBuilder().
+				MaxAttempts(3).
+				InitialDelay(time.Millisecond).
+				RetryNonIdempotent(tt.retryNonIdempotent).
+				Build()
+			client := NewFrameworkClient(&Config{
+				Registry: reg,
+				Services: map[string]ServiceOptions{
+					"hello-service": {RetryPolicy: policy, IdempotentMethods: tt.idempotentMethods},
+				},
+			})
+			client.Start()
+
+			requests.Store(0)
+			if err := client.Call(ctx, "hello-service", tt.method, nil, nil); err == nil {
+				t.Fatal("Expected call to fail")
+			}
+			if got := requests.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+// TestCallAdaptiveTimeout 测试按历史延迟设置每次尝试的超时
+func TestCallAdaptiveTimeout(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	var delay atomic.Int64
+	_, host, port := newJsonRpcServer(t, func() bool {
+		time.Sleep(time.Duration(delay.Load()))
+		return false
+	})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port})
+
+	timeouts := resilience.NewAdaptiveTimeout(&resilience.AdaptiveTimeoutConfig{
+		Floor:      50 * time.Millisecond,
+		Ceiling:    5 * time.Second,
+		MinSamples: 5,
+	})
+	client := NewFrameworkClient(&Config{
+		Registry: reg,
+		Services: map[string]ServiceOptions{"hello-service": {AdaptiveTimeout: timeouts}},
+	})
+	client.Start()
+
+	for i := 0; i < 5; i++ {
+		if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, nil); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+	}
+	if got := timeouts.Timeout("hello-service", "hello.sayHello"); got != 50*time.Millisecond {
+		t.Fatalf("Expected timeout to drop to floor, got %v", got)
+	}
+
+	// 响应明显慢于历史延迟时按自适应超时失败，而不是等待上限
+	delay.Store(int64(time.Second))
+	start := time.Now()
+	if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, nil); err == nil {
+		t.Fatal("Expected slow call to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected call to fail near the adaptive timeout, took %v", elapsed)
+	}
+}
+
+// TestClientInspector 测试连接池统计和熔断器状态查询
+func TestClientInspector(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	var failing atomic.Bool
+	_, host, port := newJsonRpcServer(t, failing.Load)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port})
+
+	client := NewFrameworkClient(&Config{
+		Registry: reg,
+		Services: map[string]ServiceOptions{
+			"hello-service": {
+				CircuitBreaker: &CircuitBreakerOptions{FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute},
+			},
+		},
+	})
+	client.Start()
+	inspector := client.(Inspector)
+
+	if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, nil); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	pools := inspector.PoolStats()
+	if len(pools) != 1 || pools[0].Service != "hello-service" || pools[0].OpenConnections != 1 || pools[0].ActiveRequests != 0 {
+		t.Errorf("Unexpected pool stats: %+v", pools)
+	}
+
+	failing.Store(true)
+	client.Call(ctx, "hello-service", "hello.sayHello", nil, nil)
+	breakers := inspector.CircuitBreakers()
+	if len(breakers) != 1 || breakers[0].State != resilience.StateOpen.String() {
+		t.Fatalf("Expected open circuit breaker, got %+v", breakers)
+	}
+
+	if err := inspector.ResetCircuitBreaker("hello-service"); err != nil {
+		t.Fatalf("ResetCircuitBreaker failed: %v", err)
+	}
+	if state := inspector.CircuitBreakers()[0].State; state != resilience.StateClosed.String() {
+		t.Errorf("Expected closed circuit breaker after reset, got %s", state)
+	}
+	if err := inspector.ResetCircuitBreaker("missing-service"); err == nil {
+		t.Error("Expected error for service without circuit breaker")
+	}
+}
+
+// TestCallScheduledRedirect 测试路由规则在生效窗口内将调用重定向到目标服务，窗口外调用原服务
+func TestCallScheduledRedirect(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	_, host, port := newJsonRpcServer(t, nil)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port})
+	backupHost, backupPort := newCandidateServer(t)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-dr-1", Name: "hello-service-dr", Address: backupHost, Port: backupPort})
+
+	failover := func(schedule *router.Schedule) *router.RoutingRule {
+		return &router.RoutingRule{
+			Name:     "failover",
+			Matcher:  func(req *adapter.InternalRequest) bool { return req.Service == "hello-service" },
+			Target:   func(req *adapter.InternalRequest) string { return "hello-service-dr" },
+			Schedule: schedule,
+		}
+	}
+	call := func(rule *router.RoutingRule) string {
+		client := NewFrameworkClient(&Config{Registry: reg, Rules: []*router.RoutingRule{rule}})
+		client.Start()
+		defer client.Shutdown(ctx)
+
+		var resp struct {
+			Message string `json:"message"`
+		}
+		if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, &resp); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		return resp.Message
+	}
+
+	now := time.Now()
+	if got := call(failover(&router.Schedule{Start: now.Add(-time.Minute), End: now.Add(time.Hour)})); got != "Hi Go" {
+		t.Errorf("Expected redirect during window, got %q", got)
+	}
+	if got := call(failover(&router.Schedule{Start: now.Add(time.Hour)})); got != "Hello Go" {
+		t.Errorf("Expected original service before window, got %q", got)
+	}
+}
@@ -0,0 +1,347 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+const (
+	// DefaultDarkLaunchSampleRate 默认与候选服务比较的调用比例
+	DefaultDarkLaunchSampleRate = 0.1
+	// DefaultDarkLaunchMaxConcurrent 默认同时进行的候选调用上限，超过时跳过比较
+	DefaultDarkLaunchMaxConcurrent = 100
+	// DefaultDarkLaunchMaxDiffs 每次比较默认最多记录的差异数
+	DefaultDarkLaunchMaxDiffs = 20
+)
+
+// 差异类型
+const (
+	DiffChanged    = "changed"    // 值不同
+	DiffType       = "type"       // JSON 类型不同
+	DiffMissing    = "missing"    // 候选服务的响应缺少该字段或元素
+	DiffUnexpected = "unexpected" // 候选服务的响应多出该字段或元素
+)
+
+// DarkLaunchOptions 暗发布比较配置：按比例将调用同时发给候选服务（如用其他语言重写的服务），
+// 调用方始终收到主服务的响应，候选服务的响应在后台与之比较
+//
+// 候选调用与主调用并发执行，请求会被处理两次，只应对只读方法或候选服务连接隔离数据存储时启用
+type DarkLaunchOptions struct {
+	// Candidate 候选服务名，必填；候选服务须提供与主服务相同的方法名
+	Candidate string
+	// SampleRate 比较的调用比例，为 0 时使用 DefaultDarkLaunchSampleRate
+	SampleRate float64
+	// Methods 只比较这些方法（完整方法名），为空时比较所有方法
+	Methods []string
+	// IgnoreFields 比较时忽略的字段名（任意层级，不区分大小写），如时间戳、请求 ID
+	IgnoreFields []string
+	// Timeout 候选调用的超时，为 0 时使用候选服务的 ServiceOptions.Timeout；候选调用不受调用方 context 取消的影响
+	Timeout time.Duration
+	// MaxConcurrent 同时进行的候选调用上限，为 0 时使用 DefaultDarkLaunchMaxConcurrent
+	MaxConcurrent int
+	// MaxDiffs 每次比较最多记录的差异数，为 0 时使用 DefaultDarkLaunchMaxDiffs
+	MaxDiffs int
+	// OnResult 每次比较完成后调用，必填
+	OnResult func(ctx context.Context, result *DarkLaunchResult)
+
+	inFlight atomic.Int64
+}
+
+// DarkLaunchResult 一次暗发布比较的结果
+type DarkLaunchResult struct {
+	Service   string `json:"service"`
+	Candidate string `json:"candidate"`
+	Method    string `json:"method"`
+	// Match 两者的处理结果（ok 或错误码，见 adapter.ErrorCodeLabel）相同，且成功时响应没有差异
+	Match             bool          `json:"match"`
+	PrimaryStatus     string        `json:"primaryStatus"`
+	CandidateStatus   string        `json:"candidateStatus"`
+	PrimaryDuration   time.Duration `json:"primaryDuration"`
+	CandidateDuration time.Duration `json:"candidateDuration"`
+	Diffs             []Difference  `json:"diffs,omitempty"`
+	// Truncated 差异数超过 MaxDiffs，只记录了前 MaxDiffs 个
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Difference 响应中的一处结构差异，Path 形如 $.items[0].price
+type Difference struct {
+	Path      string      `json:"path"`
+	Kind      string      `json:"kind"`
+	Primary   interface{} `json:"primary,omitempty"`
+	Candidate interface{} `json:"candidate,omitempty"`
+}
+
+// String 返回差异的简短描述
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffMissing:
+		return fmt.Sprintf("%s missing in candidate", d.Path)
+	case DiffUnexpected:
+		return fmt.Sprintf("%s unexpected in candidate", d.Path)
+	}
+	return fmt.Sprintf("%s %s: %v != %v", d.Path, d.Kind, d.Primary, d.Candidate)
+}
+
+// sampled 判断本次调用是否需要比较
+func (o *DarkLaunchOptions) sampled(method string) bool {
+	if o.Candidate == "" || o.OnResult == nil {
+		return false
+	}
+	if len(o.Methods) > 0 && !containsMethod(o.Methods, method) {
+		return false
+	}
+	rate := o.SampleRate
+	if rate <= 0 {
+		rate = DefaultDarkLaunchSampleRate
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// acquire 占用一个候选调用名额，达到上限时返回 false
+func (o *DarkLaunchOptions) acquire() bool {
+	limit := int64(o.MaxConcurrent)
+	if limit <= 0 {
+		limit = DefaultDarkLaunchMaxConcurrent
+	}
+	if o.inFlight.Add(1) > limit {
+		o.inFlight.Add(-1)
+		return false
+	}
+	return true
+}
+
+// release 释放候选调用名额
+func (o *DarkLaunchOptions) release() {
+	o.inFlight.Add(-1)
+}
+
+// candidateCall 进行中的候选调用
+type candidateCall struct {
+	options  *DarkLaunchOptions
+	service  string
+	method   string
+	ctx      context.Context
+	start    time.Time
+	done     chan struct{}
+	response json.RawMessage
+	err      error
+	duration time.Duration
+}
+
+// startDarkLaunch 按服务的暗发布配置在后台调用候选服务，未抽中或请求无法编码时返回 nil
+//
+// 候选服务的响应解码到与 response 同类型的新值中，两者都只保留调用方读取的字段，候选服务多出的字段不算差异；
+// response 为 nil 时只比较处理结果
+func (c *DefaultFrameworkClient) startDarkLaunch(ctx context.Context, service, method string, request interface{}, response interface{}) *candidateCall {
+	options := c.serviceOptions(service).DarkLaunch
+	if options == nil || !options.sampled(method) {
+		return nil
+	}
+	// 先编码请求，调用方在 Call 返回后修改请求不影响候选调用
+	payload, err := json.Marshal(request)
+	if err != nil || !options.acquire() {
+		return nil
+	}
+
+	call := &candidateCall{
+		options: options,
+		service: service,
+		method:  method,
+		ctx:     context.WithoutCancel(ctx),
+		start:   time.Now(),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(call.done)
+		callCtx := call.ctx
+		if options.Timeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(callCtx, options.Timeout)
+			defer cancel()
+		}
+		target := newResponse(response)
+		_, call.err = c.call(callCtx, options.Candidate, method, json.RawMessage(payload), target)
+		call.duration = time.Since(call.start)
+		if call.err == nil && target != nil {
+			call.response, _ = json.Marshal(target)
+		}
+	}()
+	return call
+}
+
+// finish 在主调用返回后记录主服务的响应，等待候选调用完成后在后台比较
+func (call *candidateCall) finish(response interface{}, primaryErr error) {
+	primaryDuration := time.Since(call.start)
+	// 先编码主服务的响应，调用方在 Call 返回后修改响应不影响比较
+	var primary json.RawMessage
+	if primaryErr == nil && response != nil {
+		if data, err := json.Marshal(response); err == nil {
+			primary = data
+		}
+	}
+
+	go func() {
+		defer call.options.release()
+		<-call.done
+		result := &DarkLaunchResult{
+			Service:           call.service,
+			Candidate:         call.options.Candidate,
+			Method:            call.method,
+			PrimaryStatus:     adapter.ErrorCodeLabel(primaryErr),
+			CandidateStatus:   adapter.ErrorCodeLabel(call.err),
+			PrimaryDuration:   primaryDuration,
+			CandidateDuration: call.duration,
+		}
+		if primaryErr == nil && call.err == nil {
+			maxDiffs := call.options.MaxDiffs
+			if maxDiffs <= 0 {
+				maxDiffs = DefaultDarkLaunchMaxDiffs
+			}
+			result.Diffs, result.Truncated = CompareJSON(primary, call.response, call.options.IgnoreFields, maxDiffs)
+		}
+		result.Match = result.PrimaryStatus == result.CandidateStatus && len(result.Diffs) == 0
+		call.options.OnResult(call.ctx, result)
+	}()
+}
+
+// newResponse 创建与 response 同类型的新值，response 不是非 nil 指针时返回 nil
+func newResponse(response interface{}) interface{} {
+	v := reflect.ValueOf(response)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+	return reflect.New(v.Type().Elem()).Interface()
+}
+
+// CompareJSON 比较两个 JSON 文档的结构差异，忽略 ignoreFields 中的字段名（不区分大小写），
+// 最多返回 maxDiffs 个差异（不大于 0 时不限），超过时 truncated 为 true；数字按数值比较，1 与 1.0 相同
+func CompareJSON(primary, candidate []byte, ignoreFields []string, maxDiffs int) (diffs []Difference, truncated bool) {
+	var a, b interface{}
+	if len(primary) > 0 {
+		if err := json.Unmarshal(primary, &a); err != nil {
+			a = string(primary)
+		}
+	}
+	if len(candidate) > 0 {
+		if err := json.Unmarshal(candidate, &b); err != nil {
+			b = string(candidate)
+		}
+	}
+
+	cmp := &differ{ignore: make(map[string]bool, len(ignoreFields)), max: maxDiffs}
+	for _, field := range ignoreFields {
+		cmp.ignore[strings.ToLower(field)] = true
+	}
+	cmp.compare("$", a, b)
+	return cmp.diffs, cmp.truncated
+}
+
+// differ 递归比较 JSON 值
+type differ struct {
+	ignore    map[string]bool
+	max       int
+	diffs     []Difference
+	truncated bool
+}
+
+// add 记录一处差异
+func (d *differ) add(diff Difference) {
+	if d.max > 0 && len(d.diffs) >= d.max {
+		d.truncated = true
+		return
+	}
+	d.diffs = append(d.diffs, diff)
+}
+
+// compare 比较 path 处的两个值
+func (d *differ) compare(path string, a, b interface{}) {
+	if jsonKind(a) != jsonKind(b) {
+		d.add(Difference{Path: path, Kind: DiffType, Primary: a, Candidate: b})
+		return
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv := b.(map[string]interface{})
+		keys := make([]string, 0, len(av)+len(bv))
+		for key := range av {
+			keys = append(keys, key)
+		}
+		for key := range bv {
+			if _, ok := av[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if d.ignore[strings.ToLower(key)] {
+				continue
+			}
+			child := path + "." + key
+			primary, inPrimary := av[key]
+			candidate, inCandidate := bv[key]
+			switch {
+			case !inCandidate:
+				d.add(Difference{Path: child, Kind: DiffMissing, Primary: primary})
+			case !inPrimary:
+				d.add(Difference{Path: child, Kind: DiffUnexpected, Candidate: candidate})
+			default:
+				d.compare(child, primary, candidate)
+			}
+		}
+	case []interface{}:
+		bv := b.([]interface{})
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(bv):
+				d.add(Difference{Path: child, Kind: DiffMissing, Primary: av[i]})
+			case i >= len(av):
+				d.add(Difference{Path: child, Kind: DiffUnexpected, Candidate: bv[i]})
+			default:
+				d.compare(child, av[i], bv[i])
+			}
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			d.add(Difference{Path: path, Kind: DiffChanged, Primary: a, Candidate: b})
+		}
+	}
+}
+
+// jsonKind 返回解码后 JSON 值的类型
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// containsMethod 检查方法列表是否包含 method
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+)
+
+// newCandidateServer 启动处理 hello.sayHello 的候选 JSON-RPC 服务，响应与 newJsonRpcServer 不同
+func newCandidateServer(t *testing.T) (string, int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]string `json:"params"`
+			ID     int               `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{"message": "Hi " + req.Params["name"], "version": 2},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+// TestCallDarkLaunch 测试暗发布：调用方收到主服务的响应，候选服务的响应差异在后台记录
+func TestCallDarkLaunch(t *testing.T) {
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	_, host, port := newJsonRpcServer(t, nil)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-1", Name: "hello-service", Address: host, Port: port})
+	candidateHost, candidatePort := newCandidateServer(t)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "hello-go-1", Name: "hello-service-go", Address: candidateHost, Port: candidatePort})
+
+	results := make(chan *DarkLaunchResult, 4)
+	client := NewFrameworkClient(&Config{
+		Registry: reg,
+		Services: map[string]ServiceOptions{
+			"hello-service": {DarkLaunch: &DarkLaunchOptions{
+				Candidate:  "hello-service-go",
+				SampleRate: 1,
+				Methods:    []string{"hello.sayHello"},
+				OnResult: func(ctx context.Context, result *DarkLaunchResult) {
+					results <- result
+				},
+			}},
+		},
+	})
+	client.Start()
+	defer client.Shutdown(ctx)
+
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if err := client.Call(ctx, "hello-service", "hello.sayHello", map[string]string{"name": "Go"}, &resp); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if resp.Message != "Hello Go" {
+		t.Errorf("Expected primary response, got %q", resp.Message)
+	}
+
+	select {
+	case result := <-results:
+		if result.Match || result.Service != "hello-service" || result.Candidate != "hello-service-go" ||
+			result.PrimaryStatus != "ok" || result.CandidateStatus != "ok" {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		// 只比较调用方读取的字段，候选服务多出的 version 不算差异
+		if len(result.Diffs) != 1 || result.Diffs[0].Path != "$.message" || result.Diffs[0].Kind != DiffChanged {
+			t.Errorf("Unexpected diffs: %v", result.Diffs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected dark launch result")
+	}
+
+	// 不在 Methods 中的方法不比较
+	client.Call(ctx, "hello-service", "hello.unknown", nil, nil)
+	select {
+	case result := <-results:
+		t.Errorf("Unexpected comparison for hello.unknown: %+v", result)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCompareJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		primary   string
+		candidate string
+		ignore    []string
+		want      []string
+	}{
+		{
+			name:      "数字按数值比较",
+			primary:   `{"total":1,"items":[{"price":9.5}]}`,
+			candidate: `{"total":1.0,"items":[{"price":9.50}]}`,
+		},
+		{
+			name:      "忽略字段",
+			primary:   `{"id":"a","requestId":"r1","createdAt":"2024-01-01"}`,
+			candidate: `{"id":"a","RequestId":"r2","createdAt":"2024-01-02"}`,
+			ignore:    []string{"requestid", "createdAt"},
+		},
+		{
+			name:      "数组长度和类型不同",
+			primary:   `{"tags":["a","b"],"count":"2"}`,
+			candidate: `{"tags":["a"],"count":2}`,
+			want:      []string{"$.count type: 2 != 2", "$.tags[1] missing in candidate"},
+		},
+		{
+			name:      "PHP 空数组与空对象",
+			primary:   `{"attrs":{}}`,
+			candidate: `{"attrs":[]}`,
+			want:      []string{"$.attrs type: map[] != []"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, _ := CompareJSON([]byte(tt.primary), []byte(tt.candidate), tt.ignore, 0)
+			if len(diffs) != len(tt.want) {
+				t.Fatalf("CompareJSON = %v, want %v", diffs, tt.want)
+			}
+			for i, want := range tt.want {
+				if got := diffs[i].String(); got != want {
+					t.Errorf("diff %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+
+	diffs, truncated := CompareJSON([]byte(`[1,2,3]`), []byte(`[4,5,6]`), nil, 2)
+	if len(diffs) != 2 || !truncated {
+		t.Errorf("Expected 2 diffs and truncated, got %v %v", diffs, truncated)
+	}
+}
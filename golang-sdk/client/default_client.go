@@ -3,25 +3,67 @@ package client
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
 )
 
 // DefaultFrameworkClient 默认框架客户端实现
+//
+// 调用时从注册中心发现服务实例，经负载均衡选择端点，通过连接池以 JSON-RPC 发送请求，
+// 并按服务配置应用超时、重试和熔断
 type DefaultFrameworkClient struct {
-	config   *Config
-	services map[string]ServiceHandler
-	mu       sync.RWMutex
-	started  bool
+	config    *Config
+	services  map[string]ServiceHandler
+	mu        sync.RWMutex
+	started   bool
+	router    *registry.RegistryRouter
+	transport *jsonRpcTransport
+	inFlight  *lifecycle.InFlight
+	rules     []*router.RoutingRule
+
+	breakersMu sync.Mutex
+	breakers   map[string]*resilience.CircuitBreaker
+
+	rpcMu sync.Mutex
+	rpc   *messaging.RPCClient
 }
 
 // NewFrameworkClient 创建新的框架客户端
 func NewFrameworkClient(config *Config) FrameworkClient {
-	return &DefaultFrameworkClient{
+	client := &DefaultFrameworkClient{
 		config:   config,
 		services: make(map[string]ServiceHandler),
 		started:  false,
+		breakers: make(map[string]*resilience.CircuitBreaker),
+		inFlight: lifecycle.NewInFlight(),
 	}
+	if config == nil {
+		client.transport = newJsonRpcTransport(nil)
+		return client
+	}
+
+	// 按优先级从高到低排序，优先级相同时保持配置顺序
+	client.rules = append([]*router.RoutingRule(nil), config.Rules...)
+	sort.SliceStable(client.rules, func(i, j int) bool {
+		return client.rules[i].Priority > client.rules[j].Priority
+	})
+
+	if config.Registry != nil {
+		client.router = registry.NewRegistryRouter(config.Registry, config.LoadBalancer)
+		// 只能经 JSON-RPC 调用，路由到声明了 JSON-RPC 的实例及其 JSON-RPC 端口
+		client.router.SetProtocols(adapter.ProtocolJSONRPC)
+	}
+	client.transport = newJsonRpcTransport(config.Connection)
+	return client
 }
 
 // Call 同步调用服务
@@ -30,14 +72,228 @@ func (c *DefaultFrameworkClient) Call(ctx context.Context, service, method strin
 		return fmt.Errorf("client not started")
 	}
 
-	// TODO: 实现实际的服务调用逻辑
-	// 1. 从服务注册中心查询服务地址
-	// 2. 建立连接
-	// 3. 序列化请求
-	// 4. 发送请求并等待响应
-	// 5. 反序列化响应
+	candidate := c.startDarkLaunch(ctx, service, method, request, response)
+	_, err := c.call(ctx, service, method, request, response)
+	if candidate != nil {
+		candidate.finish(response, err)
+	}
+	return err
+}
 
-	return fmt.Errorf("not implemented")
+// CallAll 并发调用多个服务（扇出），每个调用使用各自服务的超时和重试配置
+//
+// 所有调用结束后返回；部分调用失败时返回 *errors.MultiError，按调用在 targets 中的序号记录服务、方法、尝试次数和错误
+func (c *DefaultFrameworkClient) CallAll(ctx context.Context, targets []CallTarget) error {
+	if !c.started {
+		return fmt.Errorf("client not started")
+	}
+
+	multi := frameworkerrors.NewMultiError(len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(index int, target CallTarget) {
+			defer wg.Done()
+			attempts, err := c.call(ctx, target.Service, target.Method, target.Request, target.Response)
+			multi.Add(frameworkerrors.TargetError{Index: index, Service: target.Service, Method: target.Method, Attempt: attempts, Err: err})
+		}(i, target)
+	}
+	wg.Wait()
+
+	return multi.ErrorOrNil()
+}
+
+// call 按服务配置执行调用（含超时、重试和熔断），返回发起调用的次数
+func (c *DefaultFrameworkClient) call(ctx context.Context, service, method string, request interface{}, response interface{}) (int, error) {
+	if !c.inFlight.Acquire() {
+		return 0, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "client is shutting down")
+	}
+	defer c.inFlight.Release()
+
+	service = c.redirect(service, method)
+	options := c.serviceOptions(service)
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	breaker := c.circuitBreaker(service, options.CircuitBreaker)
+	attempts := 0
+	invoke := func() error {
+		attempts++
+		if breaker == nil {
+			return c.invokeAttempt(ctx, options.AdaptiveTimeout, service, method, request, response)
+		}
+
+		// 只有服务端错误计入熔断失败，客户端错误原样返回
+		var callErr error
+		if err := breaker.Execute(func() error {
+			callErr = c.invokeAttempt(ctx, options.AdaptiveTimeout, service, method, request, response)
+			if isServerFailure(callErr) {
+				return callErr
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return callErr
+	}
+	if options.RetryPolicy == nil {
+		err := invoke()
+		return attempts, err
+	}
+	executor := resilience.NewRetryExecutor(options.RetryPolicy)
+	if !options.RetryPolicy.RetryNonIdempotent {
+		executor = executor.ForMethod(c.idempotent(ctx, service, method, options))
+	}
+	err := executor.Execute(invoke)
+	return attempts, err
+}
+
+// idempotent 判断方法是否可以安全重试：调用方在 ServiceOptions.IdempotentMethods 中声明，
+// 或服务的所有实例在注册时声明为幂等；经消息中间件调用的服务只看调用方的声明
+func (c *DefaultFrameworkClient) idempotent(ctx context.Context, service, method string, options ServiceOptions) bool {
+	for _, declared := range options.IdempotentMethods {
+		if declared == method {
+			return true
+		}
+	}
+	if options.Messaging || c.router == nil {
+		return false
+	}
+	return c.router.IsIdempotent(ctx, &adapter.InternalRequest{Service: service, Method: method})
+}
+
+// invokeAttempt 发起一次尝试，配置了自适应超时时按历史延迟设置本次尝试的超时并记录延迟；
+// 调用方 context 取消或超时导致的失败不计入样本
+func (c *DefaultFrameworkClient) invokeAttempt(ctx context.Context, adaptive *resilience.AdaptiveTimeout, service, method string, request interface{}, response interface{}) error {
+	if adaptive == nil {
+		return c.invoke(ctx, service, method, request, response)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, adaptive.Timeout(service, method))
+	defer cancel()
+	start := time.Now()
+	err := c.invoke(attemptCtx, service, method, request, response)
+	if ctx.Err() == nil {
+		adaptive.Observe(service, method, time.Since(start))
+	}
+	return err
+}
+
+// serviceOptions 返回调用 service 服务的配置
+func (c *DefaultFrameworkClient) serviceOptions(service string) ServiceOptions {
+	if c.config == nil {
+		return ServiceOptions{}
+	}
+	return c.config.Services[service]
+}
+
+// redirect 应用路由规则，返回实际调用的服务名
+func (c *DefaultFrameworkClient) redirect(service, method string) string {
+	if len(c.rules) == 0 {
+		return service
+	}
+	if target := router.ApplyRules(c.rules, &adapter.InternalRequest{Service: service, Method: method}, time.Now()); target != "" {
+		return target
+	}
+	return service
+}
+
+// circuitBreaker 返回 service 服务的熔断器，未配置熔断时返回 nil
+func (c *DefaultFrameworkClient) circuitBreaker(service string, options *CircuitBreakerOptions) *resilience.CircuitBreaker {
+	if options == nil {
+		return nil
+	}
+
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	breaker, ok := c.breakers[service]
+	if !ok {
+		breaker = resilience.NewCircuitBreaker(service, options.FailureThreshold, options.SuccessThreshold, options.Timeout)
+		c.breakers[service] = breaker
+	}
+	return breaker
+}
+
+// invoke 执行单次服务调用：发现服务实例、负载均衡选择端点，再通过连接池发送请求；
+// 配置为经消息中间件调用的服务发布请求消息并等待响应
+func (c *DefaultFrameworkClient) invoke(ctx context.Context, service, method string, request interface{}, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.serviceOptions(service).Messaging {
+		rpc, err := c.rpcClient()
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		err = rpc.Call(ctx, service, method, request, response)
+		recordCall(service, method, "", err, time.Since(start))
+		return err
+	}
+
+	if c.router == nil {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.NotFound,
+			fmt.Sprintf("no service registry configured to discover service %s", service))
+	}
+	endpoint, err := c.router.Route(ctx, &adapter.InternalRequest{Service: service, Method: method})
+	if err != nil {
+		return err
+	}
+
+	// 客户端 span 和调用指标带目标实例的实现语言，可按语言比较跨语言调用的延迟和错误率
+	callCtx, span := adapter.StartClientSpan(ctx, endpoint.Protocol, service, method, endpoint.Key())
+	span.SetAttributes(adapter.AttrPeerLanguage.String(languageLabel(endpoint.Language)))
+	start := time.Now()
+	err = c.transport.call(callCtx, service, endpoint, method, request, response)
+	duration := time.Since(start)
+	adapter.EndSpan(span, err)
+	c.router.ReportResult(endpoint, duration, isServerFailure(err))
+	recordCall(service, method, endpoint.Language, err, duration)
+	return err
+}
+
+// rpcClient 返回经消息中间件调用服务的客户端，首次使用时订阅响应主题
+func (c *DefaultFrameworkClient) rpcClient() (*messaging.RPCClient, error) {
+	c.rpcMu.Lock()
+	defer c.rpcMu.Unlock()
+
+	if c.rpc != nil {
+		return c.rpc, nil
+	}
+	if c.config == nil || c.config.Broker == nil {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.InternalError,
+			"no message broker configured for messaging calls")
+	}
+	rpc, err := messaging.NewRPCClient(c.config.Broker)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.ConnectionError, "failed to start messaging rpc client")
+	}
+	c.rpc = rpc
+	return rpc, nil
+}
+
+// closeRPCClient 取消订阅响应主题，等待中的消息调用返回错误
+func (c *DefaultFrameworkClient) closeRPCClient() {
+	c.rpcMu.Lock()
+	defer c.rpcMu.Unlock()
+	if c.rpc != nil {
+		c.rpc.Close()
+		c.rpc = nil
+	}
+}
+
+// isServerFailure 判断错误是否表示服务端故障（5xx、超时、连接错误等框架错误），用于熔断计数
+func isServerFailure(err error) bool {
+	fe, ok := frameworkerrors.FromError(err)
+	if !ok {
+		return false
+	}
+	return fe.Code == frameworkerrors.Timeout || fe.Code.IsServerError() || fe.Code.IsFrameworkError()
 }
 
 // CallAsync 异步调用服务
@@ -106,37 +362,24 @@ func (c *DefaultFrameworkClient) Start() error {
 	}
 
 	// TODO: 实现启动逻辑
-	// 1. 初始化连接池
-	// 2. 连接服务注册中心
-	// 3. 注册本地服务
-	// 4. 启动协议处理器
+	// 1. 注册本地服务
+	// 2. 启动协议处理器
 
 	c.started = true
 	return nil
 }
 
 // Shutdown 关闭客户端
+//
+// 拒绝新的调用，在 ctx 内等待进行中的调用完成后关闭连接池中的连接；关闭后客户端不能再次使用
 func (c *DefaultFrameworkClient) Shutdown(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if !c.started {
-		return nil
-	}
-
-	// TODO: 实现关闭逻辑
-	// 1. 注销所有服务
-	// 2. 关闭所有连接
-	// 3. 停止协议处理器
-
-	// 等待所有操作完成或超时
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(5 * time.Second):
-		// 优雅关闭完成
-	}
-
+	err := c.inFlight.Wait(ctx)
+	// 未调用 Start 也可能已经订阅了响应主题和建立了连接
+	c.closeRPCClient()
+	c.transport.closeIdleConnections()
 	c.started = false
-	return nil
+	return err
 }
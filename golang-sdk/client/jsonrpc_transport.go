@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/connection"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+)
+
+// JsonRpcPath 服务端点接收 JSON-RPC 请求的 HTTP 路径，与各语言 SDK 的服务端一致
+const JsonRpcPath = "/jsonrpc"
+
+// jsonRpcTransport 以 JSON-RPC over HTTP 调用服务端点
+//
+// 按目标服务维护 keep-alive 连接池，连接数、空闲超时和建连超时取自 ConnectionConfig.ForService
+type jsonRpcTransport struct {
+	config  *connection.ConnectionConfig
+	mu      sync.Mutex
+	clients map[string]*http.Client
+	pools   map[string]*poolCounters
+	nextID  atomic.Int64
+}
+
+// poolCounters 调用某个服务的连接和请求计数
+type poolCounters struct {
+	maxConnections int
+	open           atomic.Int64
+	active         atomic.Int64
+}
+
+// countedConn 关闭时减少打开的连接数
+type countedConn struct {
+	net.Conn
+	pool *poolCounters
+	once sync.Once
+}
+
+// Close 关闭连接
+func (c *countedConn) Close() error {
+	c.once.Do(func() { c.pool.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// jsonRpcResult JSON-RPC 响应，结果保留原始 JSON 以便解码到调用方的响应对象
+type jsonRpcResult struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *jsonRpcError   `json:"error,omitempty"`
+}
+
+// jsonRpcError JSON-RPC 错误对象，data 为结构化错误时携带框架错误
+type jsonRpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// newJsonRpcTransport 创建 JSON-RPC 传输，config 为 nil 时使用默认连接配置
+func newJsonRpcTransport(config *connection.ConnectionConfig) *jsonRpcTransport {
+	if config == nil {
+		config = connection.DefaultConnectionConfig()
+	}
+	return &jsonRpcTransport{
+		config:  config,
+		clients: make(map[string]*http.Client),
+		pools:   make(map[string]*poolCounters),
+	}
+}
+
+// call 调用端点上的方法，请求和结果按 JSON 序列化
+//
+// 请求包含 *adapter.Attachment 时以 multipart 发送，附件在独立的部分中；服务端以 multipart 返回的结果附件
+// 绑定到 response 中的 *adapter.Attachment，写入临时文件的附件由调用方使用完毕后调用 Close 删除
+func (t *jsonRpcTransport) call(ctx context.Context, service string, endpoint *router.ServiceEndpoint, method string, request interface{}, response interface{}) error {
+	payload := jsonRpcReq{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  request,
+		ID:      int(t.nextID.Add(1)),
+	}
+	var body bytes.Buffer
+	contentType := "application/json"
+	var err error
+	if attachments := adapter.CollectAttachments(request); len(attachments) > 0 {
+		contentType, err = adapter.WriteMultipart(&body, payload, attachments)
+	} else {
+		err = json.NewEncoder(&body).Encode(payload)
+	}
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to marshal request")
+	}
+
+	scheme := "http"
+	if t.config.ForService(service).TLSConfig != nil {
+		scheme = "https"
+	}
+	url := scheme + "://" + net.JoinHostPort(endpoint.Address, strconv.Itoa(endpoint.Port)) + JsonRpcPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.InternalError, "failed to create request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json, multipart/form-data")
+	for key, value := range requestHeaders(ctx) {
+		req.Header.Set(key, value)
+	}
+
+	client, pool := t.client(service)
+	pool.active.Add(1)
+	defer pool.active.Add(-1)
+	// 请求结束时更新端点负载统计，供 router.LeastLoadedLoadBalancer 选择端点
+	if stats := t.config.EndpointStats; stats != nil {
+		key := endpoint.Key()
+		stats.Begin(key)
+		start := time.Now()
+		defer func() { stats.End(key, time.Since(start)) }()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return frameworkerrors.Wrap(err, frameworkerrors.ConnectionError, fmt.Sprintf("failed to call %s at %s", service, url)).
+			WithServiceID(endpoint.ServiceId)
+	}
+	defer resp.Body.Close()
+
+	var data []byte
+	var attachments *adapter.Attachments
+	if boundary, ok := adapter.IsMultipart(resp.Header.Get("Content-Type")); ok {
+		data, attachments, err = adapter.ReadMultipart(resp.Body, boundary, nil)
+	} else {
+		data, err = io.ReadAll(resp.Body)
+	}
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.ConnectionError, "failed to read response")
+	}
+	// 绑定到 response 的附件由调用方关闭，其余附件的临时文件在返回时删除
+	var bound []*adapter.Attachment
+	defer func() { attachments.CloseExcept(bound) }()
+
+	var result jsonRpcResult
+	if err := json.Unmarshal(data, &result); err != nil || (result.Error == nil && resp.StatusCode >= http.StatusBadRequest) {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return httpError(resp, data, endpoint)
+		}
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to decode response")
+	}
+	if result.Error != nil {
+		return result.Error.toError(endpoint)
+	}
+
+	if response != nil && len(result.Result) > 0 {
+		if err := json.Unmarshal(result.Result, response); err != nil {
+			return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to decode result")
+		}
+		if err := attachments.Bind(response); err != nil {
+			return err
+		}
+		bound = adapter.CollectAttachments(response)
+	}
+	return nil
+}
+
+// client 返回调用 service 服务的 HTTP 客户端及其计数，同一服务的调用复用连接
+func (t *jsonRpcTransport) client(service string) (*http.Client, *poolCounters) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if client, ok := t.clients[service]; ok {
+		return client, t.pools[service]
+	}
+
+	config := t.config.ForService(service)
+	dialer := &net.Dialer{Timeout: config.ConnectTimeout, KeepAlive: -1}
+	if config.KeepAlive {
+		dialer.KeepAlive = 30 * time.Second
+	}
+	pool := &poolCounters{maxConnections: config.MaxConnections}
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		pool.open.Add(1)
+		return &countedConn{Conn: conn, pool: pool}, nil
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         dial,
+			MaxConnsPerHost:     config.MaxConnections,
+			MaxIdleConnsPerHost: config.MaxConnections,
+			IdleConnTimeout:     config.IdleTimeout,
+			TLSClientConfig:     config.TLSConfig,
+		},
+	}
+	t.clients[service] = client
+	t.pools[service] = pool
+	return client, pool
+}
+
+// stats 返回各服务的连接池统计，按服务名排序
+func (t *jsonRpcTransport) stats() []PoolStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(t.pools))
+	for service, pool := range t.pools {
+		stats = append(stats, PoolStats{
+			Service:         service,
+			MaxConnections:  pool.maxConnections,
+			OpenConnections: pool.open.Load(),
+			ActiveRequests:  pool.active.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Service < stats[j].Service
+	})
+	return stats
+}
+
+// closeIdleConnections 关闭所有服务的空闲连接
+func (t *jsonRpcTransport) closeIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, client := range t.clients {
+		client.CloseIdleConnections()
+	}
+}
+
+// requestHeaders 返回需要随请求转发的安全上下文、追踪上下文和剩余超时
+func requestHeaders(ctx context.Context) map[string]string {
+	headers := make(map[string]string)
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		headers = sc.ToHeaders()
+	}
+	adapter.InjectTraceContext(ctx, headers)
+	adapter.InjectDeadline(ctx, headers)
+	return headers
+}
+
+// toError 将 JSON-RPC 错误转换为框架错误，data 为结构化错误时原样还原
+func (e *jsonRpcError) toError(endpoint *router.ServiceEndpoint) error {
+	if len(e.Data) > 0 {
+		if fe, err := frameworkerrors.UnmarshalError(e.Data); err == nil {
+			return fe
+		}
+	}
+	return frameworkerrors.NewFrameworkErrorFromJSONRPCCode(e.Code, e.Message).WithServiceID(endpoint.ServiceId)
+}
+
+// httpError 将非 JSON-RPC 的 HTTP 错误响应转换为框架错误，响应体为 Problem Details 或结构化错误时原样还原
+func httpError(resp *http.Response, body []byte, endpoint *router.ServiceEndpoint) error {
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == frameworkerrors.ProblemJSONContentType {
+		var problem frameworkerrors.ProblemDetails
+		if err := json.Unmarshal(body, &problem); err == nil && problem.Code != 0 {
+			return problem.ToErrorPayload().ToFrameworkError()
+		}
+	}
+	if fe, err := frameworkerrors.UnmarshalError(body); err == nil {
+		return fe
+	}
+	return frameworkerrors.NewFrameworkErrorFromHTTPStatus(resp.StatusCode, fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode)).
+		WithServiceID(endpoint.ServiceId)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// LanguageUnknown 目标实例未在注册信息中声明实现语言或经消息中间件调用时的 language 标签值
+const LanguageUnknown = "unknown"
+
+var (
+	// 用于防止重复注册的锁
+	callMetricsOnce sync.Once
+	// 出站调用计数器，每次尝试计一次
+	callTotal *prometheus.CounterVec
+	// 出站调用耗时直方图，每次尝试单独计时
+	callDuration *prometheus.HistogramVec
+)
+
+// initCallMetrics 初始化出站调用指标
+func initCallMetrics() {
+	callMetricsOnce.Do(func() {
+		callTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_client_calls_total",
+				Help: "Total number of outbound service call attempts by target language",
+			},
+			[]string{"service", "method", "language", "error_code"},
+		)
+		callDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_client_call_duration_seconds",
+				Help:    "Outbound service call attempt duration in seconds by target language",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"service", "method", "language"},
+		)
+	})
+}
+
+// recordCall 记录一次调用尝试，language 为目标实例的实现语言
+func recordCall(service, method, language string, err error, duration time.Duration) {
+	initCallMetrics()
+
+	language = languageLabel(language)
+	callTotal.WithLabelValues(service, method, language, adapter.ErrorCodeLabel(err)).Inc()
+	callDuration.WithLabelValues(service, method, language).Observe(duration.Seconds())
+}
+
+// languageLabel 返回 language 标签值，为空时为 LanguageUnknown
+func languageLabel(language string) string {
+	if language == "" {
+		return LanguageUnknown
+	}
+	return language
+}
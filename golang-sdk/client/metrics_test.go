@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestCallRecordsTargetLanguage 测试调用指标和客户端 span 带目标实例的实现语言
+func TestCallRecordsTargetLanguage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx := context.Background()
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	_, host, port := newJsonRpcServer(t, nil)
+	reg.Register(ctx, &registry.ServiceInfo{ID: "java-1", Name: "java-service", Language: "java", Address: host, Port: port})
+	reg.Register(ctx, &registry.ServiceInfo{ID: "legacy-1", Name: "legacy-service", Address: host, Port: port})
+
+	client := NewFrameworkClient(&Config{Registry: reg})
+	client.Start()
+	if err := client.Call(ctx, "java-service", "hello.sayHello", map[string]string{"name": "Go"}, nil); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	client.Call(ctx, "legacy-service", "hello.unknown", nil, nil)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, family := range families {
+		if family.GetName() != "framework_client_calls_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			found[labels["service"]+"|"+labels["language"]+"|"+labels["error_code"]] = true
+		}
+	}
+	for _, key := range []string{"java-service|java|ok", "legacy-service|" + LanguageUnknown + "|404"} {
+		if !found[key] {
+			t.Errorf("Expected call metric with labels %s, got %v", key, found)
+		}
+	}
+
+	languages := make(map[string]string)
+	for _, span := range recorder.Ended() {
+		for _, attr := range span.Attributes() {
+			if attr.Key == adapter.AttrPeerLanguage {
+				languages[span.Name()] = attr.Value.AsString()
+			}
+		}
+	}
+	if languages["java-service/hello.sayHello"] != "java" || languages["legacy-service/hello.unknown"] != LanguageUnknown {
+		t.Errorf("Unexpected span languages: %v", languages)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Proxy 创建 T 类型的强类型服务代理，调用经 c 发往 service 服务
+//
+// Go 不能在运行时实现接口，T 以函数字段组成的结构体描述服务，每个导出的函数字段对应一个方法：
+//
+//	type HelloAPI struct {
+//	    SayHello func(ctx context.Context, req *HelloRequest) (*HelloReply, error)
+//	    Ping     func(ctx context.Context) (string, error)
+//	    Notify   func(ctx context.Context, req *Event) error `method:"events.notify"`
+//	}
+//
+//	hello, err := client.Proxy[HelloAPI](c, "hello-service", "hello")
+//	reply, err := hello.SayHello(ctx, &HelloRequest{Name: "Go"})
+//
+// 方法名为 <name>.<首字母小写的字段名>，与 framework.Server.Register 的命名一致；name 为空时为首字母小写的字段名，
+// method 标签指定完整的方法名，为 "-" 时忽略该字段。函数签名与 Register 支持的方法签名相同，
+// 签名不符合要求或存在非函数的导出字段时返回错误
+func Proxy[T any](c FrameworkClient, service, name string) (*T, error) {
+	proxy := new(T)
+	value := reflect.ValueOf(proxy).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("proxy type %T must be a struct of func fields", *proxy)
+	}
+
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		method := field.Tag.Get("method")
+		if method == "-" {
+			continue
+		}
+		if method == "" {
+			method = lowerFirst(field.Name)
+			if name != "" {
+				method = name + "." + method
+			}
+		}
+
+		fn, err := proxyFunc(c, service, method, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s field %s: %w", typ.Name(), field.Name, err)
+		}
+		value.Field(i).Set(fn)
+	}
+	return proxy, nil
+}
+
+// proxyFunc 创建以 JSON-RPC 调用 method 的函数，签名须为 func(context.Context[, request]) ([response, ]error)
+func proxyFunc(c FrameworkClient, service, method string, ft reflect.Type) (reflect.Value, error) {
+	if ft.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("must be a func, got %s", ft)
+	}
+	if ft.IsVariadic() || ft.NumIn() < 1 || ft.NumIn() > 2 || ft.In(0) != contextType ||
+		ft.NumOut() < 1 || ft.NumOut() > 2 || ft.Out(ft.NumOut()-1) != errorType {
+		return reflect.Value{}, fmt.Errorf("signature %s is not of the form func(context.Context[, request]) ([response, ]error)", ft)
+	}
+
+	var responseType reflect.Type
+	if ft.NumOut() == 2 {
+		responseType = ft.Out(0)
+	}
+
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		ctx, _ := args[0].Interface().(context.Context)
+		var request interface{}
+		if len(args) == 2 && !isNilValue(args[1]) {
+			request = args[1].Interface()
+		}
+
+		if responseType == nil {
+			return []reflect.Value{errorValue(c.Call(ctx, service, method, request, nil))}
+		}
+
+		// 响应为指针时解码到新分配的值，否则解码到零值后按值返回
+		var response reflect.Value
+		if responseType.Kind() == reflect.Ptr {
+			response = reflect.New(responseType.Elem())
+		} else {
+			response = reflect.New(responseType)
+		}
+		if err := c.Call(ctx, service, method, request, response.Interface()); err != nil {
+			return []reflect.Value{reflect.Zero(responseType), errorValue(err)}
+		}
+		if responseType.Kind() != reflect.Ptr {
+			response = response.Elem()
+		}
+		return []reflect.Value{response, errorValue(nil)}
+	}), nil
+}
+
+// isNilValue 判断参数是否为 nil 指针、映射、切片或接口
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// errorValue 将 err 转换为 error 类型的返回值
+func errorValue(err error) reflect.Value {
+	if err == nil {
+		return reflect.Zero(errorType)
+	}
+	return reflect.ValueOf(&err).Elem()
+}
+
+// lowerFirst 首字母小写，与 framework.Server.Register 的方法命名一致
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// proxyCall 代理发起的调用
+type proxyCall struct {
+	service string
+	method  string
+	request string
+}
+
+// fakeClient 记录调用并以 JSON 解码预设结果的客户端
+type fakeClient struct {
+	FrameworkClient
+	calls   []proxyCall
+	results map[string]string
+	err     error
+}
+
+// Call 记录调用，按方法名返回预设结果
+func (f *fakeClient) Call(ctx context.Context, service, method string, request interface{}, response interface{}) error {
+	data, _ := json.Marshal(request)
+	f.calls = append(f.calls, proxyCall{service: service, method: method, request: string(data)})
+	if f.err != nil {
+		return f.err
+	}
+	if result, ok := f.results[method]; ok && response != nil {
+		return json.Unmarshal([]byte(result), response)
+	}
+	return nil
+}
+
+type helloRequest struct {
+	Name string `json:"name"`
+}
+
+type helloReply struct {
+	Message string `json:"message"`
+}
+
+type helloAPI struct {
+	SayHello func(ctx context.Context, req *helloRequest) (*helloReply, error)
+	Greet    func(ctx context.Context, req helloRequest) (helloReply, error)
+	Ping     func(ctx context.Context) (string, error)
+	Notify   func(ctx context.Context, req *helloRequest) error `method:"events.notify"`
+	Ignored  func()                                             `method:"-"`
+	internal string
+}
+
+func TestProxy(t *testing.T) {
+	c := &fakeClient{results: map[string]string{
+		"hello.sayHello": `{"message":"Hello Go"}`,
+		"hello.greet":    `{"message":"Hi Go"}`,
+		"hello.ping":     `"pong"`,
+	}}
+	hello, err := Proxy[helloAPI](c, "hello-service", "hello")
+	if err != nil {
+		t.Fatalf("Proxy failed: %v", err)
+	}
+	ctx := context.Background()
+
+	reply, err := hello.SayHello(ctx, &helloRequest{Name: "Go"})
+	if err != nil || reply.Message != "Hello Go" {
+		t.Fatalf("SayHello: reply=%+v err=%v", reply, err)
+	}
+	greeting, err := hello.Greet(ctx, helloRequest{Name: "Go"})
+	if err != nil || greeting.Message != "Hi Go" {
+		t.Fatalf("Greet: reply=%+v err=%v", greeting, err)
+	}
+	pong, err := hello.Ping(ctx)
+	if err != nil || pong != "pong" {
+		t.Fatalf("Ping: result=%q err=%v", pong, err)
+	}
+	if err := hello.Notify(ctx, nil); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if hello.Ignored != nil {
+		t.Error("field tagged method:\"-\" should not be set")
+	}
+
+	expected := []proxyCall{
+		{"hello-service", "hello.sayHello", `{"name":"Go"}`},
+		{"hello-service", "hello.greet", `{"name":"Go"}`},
+		{"hello-service", "hello.ping", `null`},
+		{"hello-service", "events.notify", `null`},
+	}
+	if len(c.calls) != len(expected) {
+		t.Fatalf("expected %d calls, got %+v", len(expected), c.calls)
+	}
+	for i, call := range expected {
+		if c.calls[i] != call {
+			t.Errorf("call %d: expected %+v, got %+v", i, call, c.calls[i])
+		}
+	}
+}
+
+func TestProxyError(t *testing.T) {
+	callErr := frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "user not found")
+	hello, err := Proxy[helloAPI](&fakeClient{err: callErr}, "hello-service", "")
+	if err != nil {
+		t.Fatalf("Proxy failed: %v", err)
+	}
+
+	reply, err := hello.SayHello(context.Background(), &helloRequest{})
+	if reply != nil || !errors.Is(err, callErr) {
+		t.Errorf("expected nil reply and call error, got reply=%+v err=%v", reply, err)
+	}
+	greeting, err := hello.Greet(context.Background(), helloRequest{})
+	if greeting != (helloReply{}) || !errors.Is(err, callErr) {
+		t.Errorf("expected zero reply and call error, got reply=%+v err=%v", greeting, err)
+	}
+}
+
+func TestProxyInvalidType(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() error
+	}{
+		{"非结构体", func() error {
+			_, err := Proxy[func()](&fakeClient{}, "s", "")
+			return err
+		}},
+		{"非函数字段", func() error {
+			_, err := Proxy[struct{ Name string }](&fakeClient{}, "s", "")
+			return err
+		}},
+		{"缺少 context 参数", func() error {
+			_, err := Proxy[struct {
+				Get func(req *helloRequest) error
+			}](&fakeClient{}, "s", "")
+			return err
+		}},
+		{"最后一个返回值不是 error", func() error {
+			_, err := Proxy[struct {
+				Get func(ctx context.Context) string
+			}](&fakeClient{}, "s", "")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.build() == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
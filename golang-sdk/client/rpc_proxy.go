@@ -2,13 +2,22 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/framework/golang-sdk/connection"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,9 +27,20 @@ import (
 //
 //	rpc := client.NewRpcProxyFromConfig("config.yaml")
 //	msg := rpc.Call("php-service", "hello.sayHello", nil)
+//
+// 配置了注册中心时，未在 framework.services 中静态定义的服务从注册中心发现实例，经负载均衡选择端点
 type RpcProxy struct {
-	services map[string]serviceEndpoint
-	client   *http.Client
+	services  map[string]serviceEndpoint
+	client    *http.Client
+	router    *registry.RegistryRouter
+	transport *jsonRpcTransport
+	reg       registry.ServiceRegistry
+	// owned 注册中心由 NewRpcProxyFromConfig 创建，随 Close 关闭
+	owned bool
+	stop  chan struct{}
+
+	mu     sync.Mutex
+	onWait []func(WaitProgress) // WaitForService 进度监听器
 }
 
 type serviceEndpoint struct {
@@ -31,10 +51,22 @@ type serviceEndpoint struct {
 // 配置文件结构
 type proxyConfig struct {
 	Framework struct {
-		Services map[string]serviceEndpoint `yaml:"services"`
+		Services     map[string]serviceEndpoint `yaml:"services"`
+		Registry     *proxyRegistryConfig       `yaml:"registry"`
+		LoadBalancer string                     `yaml:"loadBalancer"`
 	} `yaml:"framework"`
 }
 
+// proxyRegistryConfig framework.registry 配置
+type proxyRegistryConfig struct {
+	// Type 注册中心类型：etcd 或 memory
+	Type      string   `yaml:"type"`
+	Endpoints []string `yaml:"endpoints"`
+	Namespace string   `yaml:"namespace"`
+	// Instances memory 注册中心预置的服务实例（host:port），etcd 的实例由各服务自行注册
+	Instances map[string][]string `yaml:"instances"`
+}
+
 // jsonRpcReq JSON-RPC 2.0 请求
 type jsonRpcReq struct {
 	Jsonrpc string      `json:"jsonrpc"`
@@ -59,7 +91,28 @@ func NewRpcProxy() *RpcProxy {
 	}
 }
 
+// NewRpcProxyWithRegistry 创建从注册中心发现服务的 RpcProxy，loadBalancer 为 nil 时使用轮询
+//
+// 以 AddService 静态添加的服务优先；注册中心由调用方管理，Close 不会关闭它
+func NewRpcProxyWithRegistry(reg registry.ServiceRegistry, loadBalancer router.LoadBalancer) *RpcProxy {
+	proxy := NewRpcProxy()
+	proxy.reg = reg
+	proxy.router = registry.NewRegistryRouter(reg, loadBalancer)
+	proxy.router.SetProtocols(adapter.ProtocolJSONRPC)
+	proxy.transport = newJsonRpcTransport(nil)
+	return proxy
+}
+
 // NewRpcProxyFromConfig 从配置文件创建 RpcProxy
+//
+// framework.services 定义静态服务地址；配置了 framework.registry 时其他服务从注册中心发现，
+// framework.loadBalancer 为 round_robin（默认）、random、weighted_round_robin、least_connection 或 least_loaded：
+//
+//	framework:
+//	  registry:
+//	    type: etcd
+//	    endpoints: [localhost:2379]
+//	  loadBalancer: round_robin
 func NewRpcProxyFromConfig(configPath string) (*RpcProxy, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -72,61 +125,157 @@ func NewRpcProxyFromConfig(configPath string) (*RpcProxy, error) {
 	}
 
 	proxy := NewRpcProxy()
+	if cfg.Framework.Registry != nil {
+		stats := router.NewEndpointStats()
+		loadBalancer, err := newLoadBalancer(cfg.Framework.LoadBalancer, stats)
+		if err != nil {
+			return nil, err
+		}
+		reg, err := proxy.openRegistry(cfg.Framework.Registry)
+		if err != nil {
+			return nil, err
+		}
+		proxy.reg = reg
+		proxy.router = registry.NewRegistryRouter(reg, loadBalancer)
+		proxy.router.SetProtocols(adapter.ProtocolJSONRPC)
+		// 调用的进行中请求数和延迟记录到 least_loaded 使用的统计
+		transportConfig := connection.DefaultConnectionConfig()
+		transportConfig.EndpointStats = stats
+		proxy.transport = newJsonRpcTransport(transportConfig)
+		proxy.owned = true
+	}
 	for name, ep := range cfg.Framework.Services {
 		proxy.AddService(name, ep.Host, ep.Port)
 	}
 	return proxy, nil
 }
 
-// AddService 手动添加远程服务
-func (p *RpcProxy) AddService(name, host string, port int) *RpcProxy {
-	p.services[name] = serviceEndpoint{Host: host, Port: port}
-	return p
-}
-
-// Call 调用远程服务，返回字符串结果
-func (p *RpcProxy) Call(service, method string, params interface{}) (string, error) {
-	ep, ok := p.services[service]
-	if !ok {
-		return "", fmt.Errorf("未知服务: %s，请在配置文件 framework.services 中定义", service)
+// openRegistry 按配置创建注册中心，memory 注册中心注册预置的实例并定期续约
+func (p *RpcProxy) openRegistry(cfg *proxyRegistryConfig) (registry.ServiceRegistry, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "etcd":
+		etcdConfig := registry.DefaultEtcdRegistryConfig()
+		if len(cfg.Endpoints) > 0 {
+			etcdConfig.Endpoints = cfg.Endpoints
+		}
+		if cfg.Namespace != "" {
+			etcdConfig.Namespace = cfg.Namespace
+		}
+		return registry.NewEtcdRegistry(etcdConfig)
+	case "memory", "":
+		memoryConfig := registry.DefaultMemoryRegistryConfig()
+		reg := registry.NewMemoryRegistry(memoryConfig)
+		var ids []string
+		for name, addresses := range cfg.Instances {
+			for _, address := range addresses {
+				host, portText, err := net.SplitHostPort(address)
+				port, convErr := strconv.Atoi(portText)
+				if err != nil || convErr != nil {
+					reg.Close()
+					return nil, fmt.Errorf("服务 %s 的实例地址无效: %s", name, address)
+				}
+				id := name + "-" + address
+				if err := reg.Register(context.Background(), &registry.ServiceInfo{
+					ID:        id,
+					Name:      name,
+					Address:   host,
+					Port:      port,
+					Protocols: []string{"jsonrpc"},
+				}); err != nil {
+					reg.Close()
+					return nil, err
+				}
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			p.stop = make(chan struct{})
+			go heartbeat(reg, ids, memoryConfig.HeartbeatInterval, p.stop)
+		}
+		return reg, nil
+	default:
+		return nil, fmt.Errorf("不支持的注册中心类型: %s", cfg.Type)
 	}
+}
 
-	url := fmt.Sprintf("http://%s:%d/jsonrpc", ep.Host, ep.Port)
-	reqBody, _ := json.Marshal(jsonRpcReq{
-		Jsonrpc: "2.0",
-		Method:  method,
-		Params:  params,
-		ID:      1,
-	})
+// heartbeat 定期为预置实例续约，直到 stop 关闭
+func heartbeat(reg *registry.MemoryRegistry, ids []string, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	resp, err := p.client.Post(url, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("RPC 调用失败: %w", err)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, id := range ids {
+				reg.Heartbeat(context.Background(), id)
+			}
+		}
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %w", err)
+// newLoadBalancer 按名称创建负载均衡器，名称为空时使用轮询；least_loaded 按 stats 中的端点负载选择
+func newLoadBalancer(name string, stats *router.EndpointStats) (router.LoadBalancer, error) {
+	switch strings.ToLower(name) {
+	case "", "round_robin":
+		return router.NewRoundRobinLoadBalancer(), nil
+	case "random":
+		return router.NewRandomLoadBalancer(), nil
+	case "weighted_round_robin":
+		return router.NewWeightedRoundRobinLoadBalancer(), nil
+	case "least_connection":
+		return router.NewLeastConnectionLoadBalancer(), nil
+	case "least_loaded":
+		return router.NewLeastLoadedLoadBalancer(stats), nil
+	default:
+		return nil, fmt.Errorf("不支持的负载均衡策略: %s", name)
 	}
+}
 
-	var rpcResp jsonRpcResp
-	if err := json.Unmarshal(body, &rpcResp); err != nil {
-		return "", fmt.Errorf("解析响应失败: %w", err)
+// Close 停止预置实例的续约并关闭由配置文件创建的注册中心
+func (p *RpcProxy) Close() error {
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
 	}
-
-	if rpcResp.Error != nil {
-		return "", fmt.Errorf("RPC 错误: %v", rpcResp.Error)
+	if p.transport != nil {
+		p.transport.closeIdleConnections()
+	}
+	if p.owned {
+		return p.reg.Close()
 	}
+	return nil
+}
+
+// Registry 返回发现服务使用的注册中心，未配置时返回 nil；可用于将本服务注册到同一注册中心供其他语言发现
+func (p *RpcProxy) Registry() registry.ServiceRegistry {
+	return p.reg
+}
+
+// AddService 手动添加远程服务
+func (p *RpcProxy) AddService(name, host string, port int) *RpcProxy {
+	p.services[name] = serviceEndpoint{Host: host, Port: port}
+	return p
+}
 
-	return fmt.Sprintf("%v", rpcResp.Result), nil
+// Call 调用远程服务，返回字符串结果
+func (p *RpcProxy) Call(service, method string, params interface{}) (string, error) {
+	result, err := p.CallResult(service, method, params)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", result), nil
 }
 
 // CallResult 调用远程服务，返回原始 interface{} 结果
 func (p *RpcProxy) CallResult(service, method string, params interface{}) (interface{}, error) {
 	ep, ok := p.services[service]
 	if !ok {
-		return nil, fmt.Errorf("未知服务: %s，请在配置文件 framework.services 中定义", service)
+		if p.router == nil {
+			return nil, fmt.Errorf("未知服务: %s，请在配置文件 framework.services 中定义", service)
+		}
+		return p.callDiscovered(service, method, params)
 	}
 
 	url := fmt.Sprintf("http://%s:%d/jsonrpc", ep.Host, ep.Port)
@@ -159,3 +308,22 @@ func (p *RpcProxy) CallResult(service, method string, params interface{}) (inter
 
 	return rpcResp.Result, nil
 }
+
+// callDiscovered 从注册中心发现服务实例，经负载均衡选择端点后调用
+func (p *RpcProxy) callDiscovered(service, method string, params interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.client.Timeout)
+	defer cancel()
+
+	endpoint, err := p.router.Route(ctx, &adapter.InternalRequest{Service: service, Method: method})
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	start := time.Now()
+	err = p.transport.call(ctx, service, endpoint, method, params, &result)
+	p.router.ReportResult(endpoint, time.Since(start), isServerFailure(err))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+)
+
+// helloEndpoint 模拟其他语言的 JSON-RPC 端点，返回带实例名的问候
+func helloEndpoint(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonRpcReq
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(jsonRpcResp{Jsonrpc: "2.0", Result: "Hello from " + name, ID: req.ID})
+	}))
+}
+
+// serverAddress 返回测试服务器的主机和端口
+func serverAddress(server *httptest.Server) (string, int) {
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return host, portNum
+}
+
+func TestRpcProxyWithRegistry(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	for _, name := range []string{"php-1", "php-2"} {
+		server := helloEndpoint(name)
+		defer server.Close()
+		host, port := serverAddress(server)
+		reg.Register(context.Background(), &registry.ServiceInfo{ID: name, Name: "php-service", Address: host, Port: port, Protocols: []string{"jsonrpc"}})
+	}
+
+	proxy := NewRpcProxyWithRegistry(reg, nil)
+	defer proxy.Close()
+
+	// 轮询在两个实例间交替
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		result, err := proxy.Call("php-service", "hello.sayHello", nil)
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		seen[result]++
+	}
+	if seen["Hello from php-1"] != 2 || seen["Hello from php-2"] != 2 {
+		t.Errorf("Expected round robin across instances, got %v", seen)
+	}
+
+	_, err := proxy.Call("java-service", "hello.sayHello", nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.NotFound {
+		t.Errorf("Expected NotFound for undiscovered service, got %v", err)
+	}
+}
+
+func TestRpcProxyFromConfigWithRegistry(t *testing.T) {
+	php := helloEndpoint("php")
+	defer php.Close()
+	java := helloEndpoint("java")
+	defer java.Close()
+	javaHost, javaPort := serverAddress(java)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`framework:
+  registry:
+    type: memory
+    instances:
+      php-service:
+        - `+php.Listener.Addr().String()+`
+  loadBalancer: random
+  services:
+    java-service:
+      host: `+javaHost+`
+      port: `+strconv.Itoa(javaPort)+`
+`), 0o644)
+
+	proxy, err := NewRpcProxyFromConfig(path)
+	if err != nil {
+		t.Fatalf("NewRpcProxyFromConfig failed: %v", err)
+	}
+	defer proxy.Close()
+
+	if result, err := proxy.Call("php-service", "hello.sayHello", nil); err != nil || result != "Hello from php" {
+		t.Errorf("php-service = %q, %v", result, err)
+	}
+	if result, err := proxy.Call("java-service", "hello.sayHello", nil); err != nil || result != "Hello from java" {
+		t.Errorf("java-service = %q, %v", result, err)
+	}
+}
+
+func TestRpcProxyFromConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"未知的负载均衡策略": "framework:\n  registry:\n    type: memory\n  loadBalancer: fastest\n",
+		"未知的注册中心类型": "framework:\n  registry:\n    type: zookeeper\n",
+		"实例地址无效":    "framework:\n  registry:\n    type: memory\n    instances:\n      php-service: [localhost]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			os.WriteFile(path, []byte(content), 0o644)
+			if _, err := NewRpcProxyFromConfig(path); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestJsonRpcTransportAttachments(t *testing.T) {
+	type fileParams struct {
+		File *adapter.Attachment `json:"file"`
+	}
+	// 模拟接受附件的端点：返回大写的附件内容，附件以 multipart 返回
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		boundary, ok := adapter.IsMultipart(r.Header.Get("Content-Type"))
+		if !ok || !adapter.AcceptsMultipart(r.Header.Get("Accept")) {
+			http.Error(w, "expected multipart request", http.StatusBadRequest)
+			return
+		}
+		payload, attachments, err := adapter.ReadMultipart(r.Body, boundary, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer attachments.Close()
+		var req struct {
+			ID     int        `json:"id"`
+			Params fileParams `json:"params"`
+		}
+		json.Unmarshal(payload, &req)
+		if err := attachments.Bind(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content, _ := req.Params.File.Bytes()
+		result := fileParams{File: adapter.NewAttachment("upper.txt", "text/plain", bytes.ToUpper(content))}
+		var body bytes.Buffer
+		contentType, _ := adapter.WriteMultipart(&body, jsonRpcResp{Jsonrpc: "2.0", Result: result, ID: req.ID}, []*adapter.Attachment{result.File})
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body.Bytes())
+	}))
+	defer server.Close()
+	host, port := serverAddress(server)
+
+	transport := newJsonRpcTransport(nil)
+	var response fileParams
+	request := &fileParams{File: adapter.NewAttachment("note.txt", "text/plain", []byte("hello"))}
+	endpoint := &router.ServiceEndpoint{ServiceId: "file-1", Address: host, Port: port}
+	if err := transport.call(context.Background(), "file-service", endpoint, "file.upper", request, &response); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	defer response.File.Close()
+	content, err := response.File.Bytes()
+	if err != nil || string(content) != "HELLO" || response.File.Name != "upper.txt" {
+		t.Errorf("Unexpected result attachment %+v: %q (%v)", response.File, content, err)
+	}
+}
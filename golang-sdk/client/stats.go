@@ -0,0 +1,76 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// Inspector 客户端的运行时状态，DefaultFrameworkClient 实现了该接口，供管理接口查询和控制
+type Inspector interface {
+	// PoolStats 返回调用各服务的连接池统计
+	PoolStats() []PoolStats
+	// CircuitBreakers 返回各服务熔断器的状态
+	CircuitBreakers() []BreakerStats
+	// ResetCircuitBreaker 将服务的熔断器重置为关闭状态
+	ResetCircuitBreaker(service string) error
+}
+
+// PoolStats 调用某个服务的连接池统计
+type PoolStats struct {
+	Service         string `json:"service"`
+	MaxConnections  int    `json:"maxConnections"`
+	OpenConnections int64  `json:"openConnections"`
+	ActiveRequests  int64  `json:"activeRequests"`
+}
+
+// BreakerStats 某个服务的熔断器状态
+type BreakerStats struct {
+	Service          string `json:"service"`
+	State            string `json:"state"`
+	FailureCount     int    `json:"failureCount"`
+	SuccessCount     int    `json:"successCount"`
+	FailureThreshold int    `json:"failureThreshold"`
+	SuccessThreshold int    `json:"successThreshold"`
+}
+
+// PoolStats 返回已调用过的各服务的 JSON-RPC 连接池统计，按服务名排序
+func (c *DefaultFrameworkClient) PoolStats() []PoolStats {
+	return c.transport.stats()
+}
+
+// CircuitBreakers 返回已创建的熔断器状态，按服务名排序
+func (c *DefaultFrameworkClient) CircuitBreakers() []BreakerStats {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	stats := make([]BreakerStats, 0, len(c.breakers))
+	for service, breaker := range c.breakers {
+		stats = append(stats, BreakerStats{
+			Service:          service,
+			State:            breaker.GetState().String(),
+			FailureCount:     breaker.GetFailureCount(),
+			SuccessCount:     breaker.GetSuccessCount(),
+			FailureThreshold: breaker.GetFailureThreshold(),
+			SuccessThreshold: breaker.GetSuccessThreshold(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Service < stats[j].Service
+	})
+	return stats
+}
+
+// ResetCircuitBreaker 将服务的熔断器重置为关闭状态，服务尚未创建熔断器时返回 NotFound
+func (c *DefaultFrameworkClient) ResetCircuitBreaker(service string) error {
+	c.breakersMu.Lock()
+	breaker, ok := c.breakers[service]
+	c.breakersMu.Unlock()
+	if !ok {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.NotFound,
+			fmt.Sprintf("no circuit breaker for service %s", service))
+	}
+	breaker.Reset()
+	return nil
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// WaitState WaitForService 的进度状态
+type WaitState string
+
+const (
+	// WaitStateWaiting 服务未就绪，等待重试
+	WaitStateWaiting WaitState = "waiting"
+	// WaitStateReady 服务已就绪
+	WaitStateReady WaitState = "ready"
+	// WaitStateTimeout 超时或 ctx 结束时服务仍未就绪
+	WaitStateTimeout WaitState = "timeout"
+)
+
+// WaitProgress WaitForService 的一次进度
+type WaitProgress struct {
+	Service string
+	State   WaitState
+	// Attempt 已检查的次数，从 1 开始
+	Attempt int
+	// Elapsed 从开始等待到本次进度经过的时间
+	Elapsed time.Duration
+	// Err 最后一次检查的错误，就绪时为 nil
+	Err error
+}
+
+// OnWaitProgress 注册 WaitForService 的进度监听器，每次检查失败、就绪和超时时调用
+//
+// WatchEvents 以此将进度发布为框架事件
+func (p *RpcProxy) OnWaitProgress(listener func(WaitProgress)) *RpcProxy {
+	if listener == nil {
+		return p
+	}
+	p.mu.Lock()
+	p.onWait = append(p.onWait, listener)
+	p.mu.Unlock()
+	return p
+}
+
+// WaitForService 的进度发布的框架事件类型，与 observability.EventService* 一致
+const (
+	EventServiceWaiting     = "service.waiting"
+	EventServiceReady       = "service.ready"
+	EventServiceWaitTimeout = "service.wait_timeout"
+)
+
+// WatchEvents 将 WaitForService 的进度以框架事件发布，实现 observability.EventSource，事件来源为服务名
+func (p *RpcProxy) WatchEvents(emit func(eventType, source string, attributes map[string]string)) {
+	p.OnWaitProgress(func(progress WaitProgress) {
+		eventType := EventServiceWaiting
+		switch progress.State {
+		case WaitStateReady:
+			eventType = EventServiceReady
+		case WaitStateTimeout:
+			eventType = EventServiceWaitTimeout
+		}
+
+		attributes := map[string]string{
+			"attempt": strconv.Itoa(progress.Attempt),
+			"elapsed": progress.Elapsed.Round(time.Millisecond).String(),
+		}
+		if progress.Err != nil {
+			attributes["error"] = progress.Err.Error()
+		}
+		emit(eventType, progress.Service, attributes)
+	})
+}
+
+// WaitForService 等待远程服务就绪，timeout 为 0 时使用 lifecycle.DefaultStartupTimeout
+//
+// 静态定义的服务检查其地址能否建立 TCP 连接；其他服务从注册中心发现实例，经负载均衡选择的端点能建立
+// 连接时视为就绪。检查失败时按指数退避重试（200ms 起，最长 5s），进度经 OnWaitProgress 通知。
+// 未定义且没有注册中心的服务立即返回错误
+func (p *RpcProxy) WaitForService(ctx context.Context, name string, timeout time.Duration) error {
+	if _, ok := p.services[name]; !ok && p.router == nil {
+		return fmt.Errorf("未知服务: %s，请在配置文件 framework.services 中定义", name)
+	}
+
+	start := time.Now()
+	attempts := 0
+	dep := &serviceDependency{proxy: p, name: name, attempts: &attempts}
+	err := lifecycle.WaitForDependencies(ctx, &lifecycle.StartupOptions{
+		Timeout: timeout,
+		OnWaiting: func(_ string, attempt int, err error) {
+			p.notifyWait(WaitProgress{Service: name, State: WaitStateWaiting, Attempt: attempt, Elapsed: time.Since(start), Err: err})
+		},
+	}, dep)
+
+	progress := WaitProgress{Service: name, State: WaitStateReady, Attempt: attempts, Elapsed: time.Since(start)}
+	if err != nil {
+		progress.State = WaitStateTimeout
+		progress.Err = err
+	}
+	p.notifyWait(progress)
+	return err
+}
+
+// notifyWait 通知进度监听器
+func (p *RpcProxy) notifyWait(progress WaitProgress) {
+	p.mu.Lock()
+	listeners := make([]func(WaitProgress), len(p.onWait))
+	copy(listeners, p.onWait)
+	p.mu.Unlock()
+	for _, listener := range listeners {
+		listener(progress)
+	}
+}
+
+// serviceDependency 远程服务的就绪检查
+type serviceDependency struct {
+	proxy    *RpcProxy
+	name     string
+	attempts *int
+}
+
+func (d *serviceDependency) Name() string {
+	return d.name
+}
+
+// Check 解析服务端点并尝试建立 TCP 连接
+func (d *serviceDependency) Check(ctx context.Context) error {
+	*d.attempts++
+
+	address, err := d.proxy.resolve(ctx, d.name)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("服务 %s（%s）不可达: %w", d.name, address, err)
+	}
+	return conn.Close()
+}
+
+// resolve 返回服务的端点地址，静态定义的服务优先
+func (p *RpcProxy) resolve(ctx context.Context, service string) (string, error) {
+	if ep, ok := p.services[service]; ok {
+		return net.JoinHostPort(ep.Host, strconv.Itoa(ep.Port)), nil
+	}
+	endpoint, err := p.router.Route(ctx, &adapter.InternalRequest{Service: service})
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(endpoint.Address, strconv.Itoa(endpoint.Port)), nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+)
+
+// progressRecorder 记录 WaitForService 的进度
+type progressRecorder struct {
+	mu       sync.Mutex
+	progress []WaitProgress
+}
+
+func (r *progressRecorder) record(progress WaitProgress) {
+	r.mu.Lock()
+	r.progress = append(r.progress, progress)
+	r.mu.Unlock()
+}
+
+func (r *progressRecorder) states() []WaitState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := make([]WaitState, len(r.progress))
+	for i, progress := range r.progress {
+		states[i] = progress.State
+	}
+	return states
+}
+
+// TestWaitForService 测试等待注册中心中的服务实例注册并开始监听
+func TestWaitForService(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	proxy := NewRpcProxyWithRegistry(reg, nil)
+	defer proxy.Close()
+	recorder := &progressRecorder{}
+	proxy.OnWaitProgress(recorder.record)
+
+	// 服务稍后注册
+	server := helloEndpoint("php-1")
+	defer server.Close()
+	host, port := serverAddress(server)
+	time.AfterFunc(300*time.Millisecond, func() {
+		reg.Register(context.Background(), &registry.ServiceInfo{ID: "php-1", Name: "php-service", Address: host, Port: port, Protocols: []string{"jsonrpc"}})
+	})
+
+	if err := proxy.WaitForService(context.Background(), "php-service", 5*time.Second); err != nil {
+		t.Fatalf("WaitForService failed: %v", err)
+	}
+	states := recorder.states()
+	if len(states) < 2 || states[0] != WaitStateWaiting || states[len(states)-1] != WaitStateReady {
+		t.Errorf("Expected waiting progress then ready, got %v", states)
+	}
+	last := recorder.progress[len(recorder.progress)-1]
+	if last.Err != nil || last.Attempt != len(states) {
+		t.Errorf("Unexpected ready progress: %+v", last)
+	}
+}
+
+// TestWaitForServiceTimeout 测试静态服务不可达时超时
+func TestWaitForServiceTimeout(t *testing.T) {
+	// 取得一个未监听的端口
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	proxy := NewRpcProxy().AddService("java-service", "127.0.0.1", port)
+	recorder := &progressRecorder{}
+	proxy.OnWaitProgress(recorder.record)
+
+	err := proxy.WaitForService(context.Background(), "java-service", 500*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "java-service") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	states := recorder.states()
+	if len(states) == 0 || states[len(states)-1] != WaitStateTimeout {
+		t.Errorf("Expected timeout progress, got %v", states)
+	}
+
+	// 未定义且没有注册中心的服务不等待
+	start := time.Now()
+	if err := proxy.WaitForService(context.Background(), "go-service", time.Minute); err == nil || time.Since(start) > time.Second {
+		t.Errorf("Expected immediate error for unknown service, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// runCompare 比较两份报告，存在退化时以状态码 1 退出
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 10, "ns/op increase in percent treated as a regression")
+	allocs := fs.Bool("allocs", false, "treat any allocs/op increase as a regression")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bench compare [-threshold 10] [-allocs] <old.json> <new.json>")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	old, err := readReport(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read report %s: %v\n", fs.Arg(0), err)
+		os.Exit(1)
+	}
+	current, err := readReport(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read report %s: %v\n", fs.Arg(1), err)
+		os.Exit(1)
+	}
+	if old.GOOS != current.GOOS || old.GOARCH != current.GOARCH || old.CPU != current.CPU {
+		fmt.Fprintf(os.Stderr, "Warning: reports come from different machines (%s/%s %s vs %s/%s %s)\n",
+			old.GOOS, old.GOARCH, old.CPU, current.GOOS, current.GOARCH, current.CPU)
+	}
+
+	comparisons := compareReports(old, current, *threshold, *allocs)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BENCHMARK\tOLD NS/OP\tNEW NS/OP\tDELTA\tOLD ALLOCS\tNEW ALLOCS\t")
+	regressions := 0
+	for _, c := range comparisons {
+		status := ""
+		if c.Regression {
+			status = "REGRESSION"
+			regressions++
+		}
+		switch {
+		case c.Old == nil:
+			fmt.Fprintf(w, "%s\t-\t%.1f\tnew\t-\t%.0f\t\n", c.Key, c.New.NsPerOp, c.New.AllocsPerOp)
+		case c.New == nil:
+			fmt.Fprintf(w, "%s\t%.1f\t-\tremoved\t%.0f\t-\t\n", c.Key, c.Old.NsPerOp, c.Old.AllocsPerOp)
+		default:
+			fmt.Fprintf(w, "%s\t%.1f\t%.1f\t%+.1f%%\t%.0f\t%.0f\t%s\n",
+				c.Key, c.Old.NsPerOp, c.New.NsPerOp, c.Delta, c.Old.AllocsPerOp, c.New.AllocsPerOp, status)
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d benchmarks compared (%s -> %s), %d regressions\n",
+		len(comparisons), commitLabel(old), commitLabel(current), regressions)
+	if regressions > 0 {
+		os.Exit(1)
+	}
+}
+
+// Comparison 一个基准测试在两份报告中的结果
+type Comparison struct {
+	Key string
+	// Old、New 为 nil 表示基准测试只存在于另一份报告中
+	Old, New *Benchmark
+	// Delta ns/op 的变化百分比
+	Delta      float64
+	Regression bool
+}
+
+// compareReports 按名称匹配两份报告的基准测试，结果按名称排序
+func compareReports(old, current *Report, threshold float64, allocs bool) []*Comparison {
+	byKey := make(map[string]*Comparison)
+	for _, b := range old.Benchmarks {
+		byKey[b.Key()] = &Comparison{Key: b.Key(), Old: b}
+	}
+	for _, b := range current.Benchmarks {
+		c, ok := byKey[b.Key()]
+		if !ok {
+			c = &Comparison{Key: b.Key()}
+			byKey[b.Key()] = c
+		}
+		c.New = b
+	}
+
+	comparisons := make([]*Comparison, 0, len(byKey))
+	for _, c := range byKey {
+		if c.Old != nil && c.New != nil {
+			if c.Old.NsPerOp > 0 {
+				c.Delta = (c.New.NsPerOp - c.Old.NsPerOp) / c.Old.NsPerOp * 100
+			}
+			c.Regression = c.Delta > threshold || (allocs && c.New.AllocsPerOp > c.Old.AllocsPerOp)
+		}
+		comparisons = append(comparisons, c)
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Key < comparisons[j].Key
+	})
+	return comparisons
+}
+
+// readReport 读取报告文件
+func readReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// commitLabel 返回报告的提交，没有记录时返回 unknown
+func commitLabel(report *Report) string {
+	if report.Commit == "" {
+		return "unknown"
+	}
+	return report.Commit
+}
@@ -0,0 +1,46 @@
+// bench 运行 SDK 的基准测试并生成可比较的报告，用于在性能相关的改动前后发现退化
+//
+// 用法:
+//
+//	bench run [-bench .] [-count 5] [-benchtime 1s] [-o report.json] [packages]
+//	bench run -input bench.txt [-o report.json]
+//	bench compare [-threshold 10] [-allocs] <old.json> <new.json>
+//
+// run 以 -benchmem 执行 go test 的基准测试（默认测试包为 DefaultPackages），或解析 -input 指定的 go test 输出，
+// 将每个基准测试多次运行的中位数连同 Go 版本、平台和提交写入 JSON 报告；
+// compare 比较两份报告，ns/op 增长超过阈值（或 -allocs 时 allocs/op 增加）的基准测试视为退化，存在退化时以状态码 1 退出
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "run":
+		runRun(os.Args[2:])
+	case "compare":
+		runCompare(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+// usage 输出命令列表
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: bench <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  run      run benchmarks (or parse go test output) and write a JSON report")
+	fmt.Fprintln(os.Stderr, "  compare  compare two reports and fail on regressions")
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPackages 默认运行基准测试的包，覆盖请求路径上的热点
+var DefaultPackages = []string{
+	"./protocol/adapter",
+	"./protocol/router",
+	"./protocol/internal/custom",
+	"./serializer",
+	"./connection",
+}
+
+// Report 基准测试报告
+type Report struct {
+	GoVersion  string       `json:"goVersion"`
+	GOOS       string       `json:"goos"`
+	GOARCH     string       `json:"goarch"`
+	CPU        string       `json:"cpu,omitempty"`
+	Commit     string       `json:"commit,omitempty"`
+	Time       time.Time    `json:"time"`
+	Benchmarks []*Benchmark `json:"benchmarks"`
+}
+
+// Benchmark 一个基准测试多次运行的结果，各指标取中位数
+type Benchmark struct {
+	Package string `json:"package"`
+	// Name 基准测试名称，去掉了 GOMAXPROCS 后缀，不同机器的报告可以按名称比较
+	Name        string  `json:"name"`
+	Procs       int     `json:"procs,omitempty"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  float64 `json:"bytesPerOp"`
+	AllocsPerOp float64 `json:"allocsPerOp"`
+	MBPerSec    float64 `json:"mbPerSec,omitempty"`
+	// Samples 每次运行的 ns/op
+	Samples []float64 `json:"samples"`
+}
+
+// Key 返回在报告之间匹配基准测试的键
+func (b *Benchmark) Key() string {
+	return b.Package + "." + b.Name
+}
+
+// runRun 执行基准测试或解析 go test 输出，写出报告
+func runRun(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	bench := fs.String("bench", ".", "benchmark regexp passed to go test -bench")
+	count := fs.Int("count", 5, "number of runs of each benchmark")
+	benchtime := fs.String("benchtime", "1s", "run time of each benchmark passed to go test -benchtime")
+	input := fs.String("input", "", "parse existing go test -bench output from this file (- for stdin) instead of running go test")
+	output := fs.String("o", "", "write the report to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: bench run [-bench .] [-count 5] [-benchtime 1s] [-input file|-] [-o report.json] [packages]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintf(os.Stderr, "Packages default to %s (relative to the golang-sdk module root).\n", strings.Join(DefaultPackages, " "))
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var raw []byte
+	var err error
+	switch *input {
+	case "":
+		packages := fs.Args()
+		if len(packages) == 0 {
+			packages = DefaultPackages
+		}
+		raw, err = goTest(packages, *bench, *count, *benchtime)
+	case "-":
+		raw, err = io.ReadAll(os.Stdin)
+	default:
+		raw, err = os.ReadFile(*input)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to run benchmarks: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := parseOutput(bytes.NewReader(raw))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse benchmark output: %v\n", err)
+		os.Exit(1)
+	}
+	if len(report.Benchmarks) == 0 {
+		fmt.Fprintln(os.Stderr, "No benchmark results found")
+		os.Exit(1)
+	}
+	report.Commit = gitCommit()
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create report: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d benchmarks to %s\n", len(report.Benchmarks), *output)
+	}
+}
+
+// goTest 执行基准测试，go test 的输出同时转发到标准错误以显示进度
+func goTest(packages []string, bench string, count int, benchtime string) ([]byte, error) {
+	args := []string{"test", "-run", "^$", "-bench", bench, "-benchmem",
+		"-count", strconv.Itoa(count), "-benchtime", benchtime}
+	cmd := exec.Command("go", append(args, packages...)...)
+	var out bytes.Buffer
+	cmd.Stdout = io.MultiWriter(&out, os.Stderr)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go test: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// gitCommit 返回当前提交，不在 git 仓库中时返回空字符串
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// parseOutput 解析 go test -bench 的输出，同名基准测试的多次运行合并为一个结果
+func parseOutput(r io.Reader) (*Report, error) {
+	report := &Report{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		Time:      time.Now().UTC(),
+	}
+	type samples struct {
+		bench                 *Benchmark
+		ns, bytes, allocs, mb []float64
+	}
+	results := make(map[string]*samples)
+	var order []string
+
+	pkg := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "goos: "):
+			report.GOOS = strings.TrimPrefix(line, "goos: ")
+		case strings.HasPrefix(line, "goarch: "):
+			report.GOARCH = strings.TrimPrefix(line, "goarch: ")
+		case strings.HasPrefix(line, "cpu: "):
+			report.CPU = strings.TrimPrefix(line, "cpu: ")
+		case strings.HasPrefix(line, "pkg: "):
+			pkg = strings.TrimPrefix(line, "pkg: ")
+		case strings.HasPrefix(line, "Benchmark"):
+			fields := strings.Fields(line)
+			// 名称、迭代次数和至少一组“值 单位”
+			if len(fields) < 4 || len(fields)%2 != 0 {
+				continue
+			}
+			if _, err := strconv.Atoi(fields[1]); err != nil {
+				continue
+			}
+			name, procs := splitProcs(fields[0])
+			key := pkg + "." + name
+			s, ok := results[key]
+			if !ok {
+				s = &samples{bench: &Benchmark{Package: pkg, Name: name, Procs: procs}}
+				results[key] = s
+				order = append(order, key)
+			}
+			s.bench.Runs++
+			for i := 2; i+1 < len(fields); i += 2 {
+				value, err := strconv.ParseFloat(fields[i], 64)
+				if err != nil {
+					continue
+				}
+				switch fields[i+1] {
+				case "ns/op":
+					s.ns = append(s.ns, value)
+				case "B/op":
+					s.bytes = append(s.bytes, value)
+				case "allocs/op":
+					s.allocs = append(s.allocs, value)
+				case "MB/s":
+					s.mb = append(s.mb, value)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range order {
+		s := results[key]
+		s.bench.Samples = s.ns
+		s.bench.NsPerOp = median(s.ns)
+		s.bench.BytesPerOp = median(s.bytes)
+		s.bench.AllocsPerOp = median(s.allocs)
+		s.bench.MBPerSec = median(s.mb)
+		report.Benchmarks = append(report.Benchmarks, s.bench)
+	}
+	return report, nil
+}
+
+// splitProcs 拆分基准测试名称末尾的 GOMAXPROCS 后缀，如 BenchmarkRoute-8
+func splitProcs(name string) (string, int) {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name, 0
+	}
+	procs, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return name, 0
+	}
+	return name[:i], procs
+}
+
+// median 返回中位数，values 为空时返回 0
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
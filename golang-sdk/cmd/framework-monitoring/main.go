@@ -0,0 +1,79 @@
+// framework-monitoring 为服务生成 Grafana 仪表盘和 Prometheus 告警规则
+//
+// 用法:
+//
+//	framework-monitoring -service order-service -output ./monitoring
+//
+// 生成 <service>-dashboard.json（通过 Grafana 导入）和 <service>-alerts.yaml（作为 Prometheus rule_files 加载）
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/framework/golang-sdk/observability"
+)
+
+func main() {
+	service := flag.String("service", "", "service name used to filter metrics (required)")
+	output := flag.String("output", ".", "output directory")
+	datasource := flag.String("datasource", "", "Prometheus datasource UID, prompt on import when empty")
+	window := flag.Duration("window", observability.DefaultDashboardRateWindow, "rate window")
+	errorRate := flag.Float64("error-rate", observability.DefaultAlertErrorRate, "error rate alert threshold")
+	latency := flag.Duration("latency", observability.DefaultAlertLatencyThreshold, "p99 latency alert threshold")
+	poolSaturation := flag.Float64("pool-saturation", observability.DefaultPoolSaturation, "connection pool saturation alert threshold")
+	flag.Parse()
+
+	if *service == "" {
+		fmt.Fprintln(os.Stderr, "-service is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config := &observability.DashboardConfig{
+		ServiceName:      *service,
+		Datasource:       *datasource,
+		RateWindow:       *window,
+		ErrorRate:        *errorRate,
+		LatencyThreshold: *latency,
+		PoolSaturation:   *poolSaturation,
+	}
+
+	if err := generate(config, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate monitoring files: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate 生成仪表盘和告警规则文件
+func generate(config *observability.DashboardConfig, output string) error {
+	dashboard, err := observability.GenerateGrafanaDashboard(config)
+	if err != nil {
+		return err
+	}
+
+	rules, err := observability.GenerateAlertRules(config)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(output, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	files := map[string][]byte{
+		config.ServiceName + "-dashboard.json": dashboard,
+		config.ServiceName + "-alerts.yaml":    rules,
+	}
+	for name, data := range files {
+		path := filepath.Join(output, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Println("Generated", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+// framework 框架命令行工具
+//
+// 用法:
+//
+//	framework init [-output config.yaml] [-name order-service] [-force]
+//	framework keygen
+//	FRAMEWORK_CONFIG_KEY=<key> framework encrypt -value <plaintext>
+//	framework schema-check [-mode backward] <old> <new>
+//	framework gen [-lang go] [-out .] [-package name] [-files a.proto,b.proto] <descriptor-set>
+//	framework mock [-config config.yaml] <descriptor-set|openapi|contract>...
+//
+// init 生成与框架默认值一致、带注释的配置文件，新服务无需复制示例文件；
+// keygen 生成配置加密密钥，encrypt 输出可写入配置文件的 ENC[...] 加密值；
+// schema-check 比较两个版本的描述符集或 JSON Schema，存在不兼容变更时以状态码 1 退出，供 CI 使用；
+// gen 根据描述符集中的服务定义生成 Go 桩代码或 PHP、Java 的 JSON-RPC 方法映射；
+// mock 按描述符集、OpenAPI 文档或契约文件启动模拟服务，为声明的方法返回预设或生成的响应
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/framework/golang-sdk/codegen"
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/framework"
+	"github.com/framework/golang-sdk/mock"
+	"github.com/framework/golang-sdk/serializer/compat"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "init":
+		runInit(os.Args[2:])
+	case "keygen":
+		runKeygen()
+	case "encrypt":
+		runEncrypt(os.Args[2:])
+	case "schema-check":
+		runSchemaCheck(os.Args[2:])
+	case "gen":
+		runGen(os.Args[2:])
+	case "mock":
+		runMock(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+// usage 输出命令列表
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: framework <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  init          generate a commented config.yaml with framework defaults")
+	fmt.Fprintln(os.Stderr, "  keygen        generate a key for FRAMEWORK_CONFIG_KEY")
+	fmt.Fprintln(os.Stderr, "  encrypt       encrypt a config value with FRAMEWORK_CONFIG_KEY")
+	fmt.Fprintln(os.Stderr, "  schema-check  check compatibility between two schema versions")
+	fmt.Fprintln(os.Stderr, "  gen           generate service stubs and JSON-RPC method maps from a descriptor set")
+	fmt.Fprintln(os.Stderr, "  mock          serve canned or generated responses for declared methods")
+}
+
+// runInit 生成默认配置文件
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	output := fs.String("output", "config.yaml", "config file path")
+	name := fs.String("name", "", "service name, defaults to the framework default")
+	version := fs.String("version", "", "service version, defaults to the framework default")
+	force := fs.Bool("force", false, "overwrite an existing config file")
+	fs.Parse(args)
+
+	cfg := config.DefaultFrameworkConfig()
+	if *name != "" {
+		cfg.Name = *name
+	}
+	if *version != "" {
+		cfg.Version = *version
+	}
+
+	if err := config.WriteConfig(*output, cfg, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated", *output)
+}
+
+// runKeygen 生成配置加密密钥
+func runKeygen() {
+	key, err := config.GenerateConfigKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate key: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(key)
+}
+
+// runEncrypt 使用 FRAMEWORK_CONFIG_KEY 加密配置值
+func runEncrypt(args []string) {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	value := fs.String("value", "", "plaintext value to encrypt (required)")
+	fs.Parse(args)
+
+	if *value == "" {
+		fmt.Fprintln(os.Stderr, "-value is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	key, err := config.ParseConfigKey(os.Getenv(config.EnvConfigKey))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid %s: %v\n", config.EnvConfigKey, err)
+		os.Exit(1)
+	}
+	cipher, err := config.NewAESCipher(key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create cipher: %v\n", err)
+		os.Exit(1)
+	}
+
+	encrypted, err := config.EncryptValue(cipher, *value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encrypt value: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(encrypted)
+}
+
+// runSchemaCheck 检查两个版本结构文件的兼容性
+func runSchemaCheck(args []string) {
+	fs := flag.NewFlagSet("schema-check", flag.ExitOnError)
+	modeFlag := fs.String("mode", "backward", "compatibility mode: backward, forward or full")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: framework schema-check [-mode backward] <old> <new>")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Schemas are JSON Schema (.json) or descriptor sets (.pb, .binpb, .desc)")
+		fmt.Fprintln(os.Stderr, "generated by protoc --include_imports --descriptor_set_out.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	mode, err := compat.ParseMode(*modeFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	report, err := compat.CheckFiles(fs.Arg(0), fs.Arg(1), mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check schemas: %v\n", err)
+		os.Exit(2)
+	}
+	fmt.Println(report)
+	if !report.Compatible() {
+		os.Exit(1)
+	}
+}
+
+// runGen 根据描述符集生成桩代码
+func runGen(args []string) {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	langFlag := fs.String("lang", "go", "target language: go, php or java")
+	out := fs.String("out", ".", "output directory")
+	pkg := fs.String("package", "", "Go package, PHP namespace or Java package, defaults to the proto file options")
+	files := fs.String("files", "", "comma-separated proto files to generate, defaults to all files except google/protobuf/")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: framework gen [-lang go] [-out .] [-package name] [-files a.proto,b.proto] <descriptor-set>")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "The descriptor set is generated by protoc --include_imports --include_source_info --descriptor_set_out.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	lang, err := codegen.ParseLanguage(*langFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read descriptor set: %v\n", err)
+		os.Exit(1)
+	}
+	set, err := compat.ParseDescriptorSet(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	opts := codegen.Options{Language: lang, Package: *pkg}
+	if *files != "" {
+		opts.Files = strings.Split(*files, ",")
+	}
+	generated, err := codegen.Generate(set, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate code: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, file := range generated {
+		path := filepath.Join(*out, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create directory: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Println("Generated", path)
+	}
+}
+
+// runMock 启动模拟服务，按配置文件监听协议端口，直到收到 SIGINT 或 SIGTERM
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	configPath := fs.String("config", "config.yaml", "framework config file for protocols and ports")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: framework mock [-config config.yaml] <descriptor-set|openapi|contract>...")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Descriptor sets (.pb, .binpb, .desc) and OpenAPI documents (.yaml, .yml, .json) generate responses;")
+		fmt.Fprintln(os.Stderr, "contract files (.json) recorded by protocol/contract provide canned responses matched by request.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	m := mock.New()
+	for _, path := range fs.Args() {
+		if err := m.AddFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	server, err := framework.NewServer(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create server: %v\n", err)
+		os.Exit(1)
+	}
+	m.Register(server)
+	for _, method := range m.Methods() {
+		fmt.Println("Mocking", method)
+	}
+	if err := server.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+}
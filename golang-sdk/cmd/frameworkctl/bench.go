@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// benchResult 单次调用的结果
+type benchResult struct {
+	latency time.Duration
+	err     error
+}
+
+// runBench 以 -c 个并发调用方法，共调用 -n 次或持续 -duration，输出吞吐量、延迟分布和错误统计
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	target := addTargetFlags(fs)
+	requests := fs.Int("n", 1000, "total number of calls, ignored when -duration is set")
+	concurrency := fs.Int("c", 10, "number of concurrent callers")
+	duration := fs.Duration("duration", 0, "run for this long instead of -n calls")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl bench [-addr host:port | -service name] [-n 1000] [-c 10] [-duration 0] [flags] <method> [params]")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	method, params := parseCallArgs(fs)
+	if *concurrency < 1 || (*duration <= 0 && *requests < 1) {
+		fmt.Fprintln(os.Stderr, "-c and -n must be positive")
+		os.Exit(2)
+	}
+
+	resolveCtx, cancel := context.WithTimeout(context.Background(), *target.timeout)
+	urls, err := target.endpoints(resolveCtx)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve endpoint: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	if *duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	results := bench(ctx, newCaller(target.auth), urls, method, params, *requests, *duration > 0, *concurrency, *target.timeout)
+	report(results)
+}
+
+// bench 并发调用方法，多个端点时轮询；untilDone 为 true 时持续调用直到 ctx 结束
+func bench(ctx context.Context, c *caller, urls []string, method string, params json.RawMessage,
+	requests int, untilDone bool, concurrency int, timeout time.Duration) *benchReport {
+	var (
+		issued atomic.Int64
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+	collected := make([]benchResult, 0, requests)
+
+	start := time.Now()
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				n := issued.Add(1)
+				if !untilDone && n > int64(requests) {
+					return
+				}
+				callCtx, cancel := context.WithTimeout(ctx, timeout)
+				begin := time.Now()
+				_, err := c.call(callCtx, urls[int(n)%len(urls)], method, params)
+				latency := time.Since(begin)
+				cancel()
+				// 持续时间结束时中断的调用不计入结果
+				if untilDone && ctx.Err() != nil {
+					return
+				}
+
+				mu.Lock()
+				collected = append(collected, benchResult{latency: latency, err: err})
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return newBenchReport(collected, time.Since(start))
+}
+
+// benchReport 压测统计
+type benchReport struct {
+	total     int
+	failed    int
+	elapsed   time.Duration
+	latencies []time.Duration // 已排序
+	errors    map[string]int  // 按错误码或错误信息计数
+}
+
+// newBenchReport 汇总调用结果
+func newBenchReport(results []benchResult, elapsed time.Duration) *benchReport {
+	r := &benchReport{
+		total:     len(results),
+		elapsed:   elapsed,
+		latencies: make([]time.Duration, 0, len(results)),
+		errors:    make(map[string]int),
+	}
+	for _, result := range results {
+		r.latencies = append(r.latencies, result.latency)
+		if result.err == nil {
+			continue
+		}
+		r.failed++
+		key := result.err.Error()
+		if fe, ok := frameworkerrors.FromError(result.err); ok {
+			key = fe.Code.String()
+		}
+		r.errors[key]++
+	}
+	sort.Slice(r.latencies, func(i, j int) bool {
+		return r.latencies[i] < r.latencies[j]
+	})
+	return r
+}
+
+// percentile 返回第 p 百分位的延迟
+func (r *benchReport) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	index := int(float64(len(r.latencies))*p/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(r.latencies) {
+		index = len(r.latencies) - 1
+	}
+	return r.latencies[index]
+}
+
+// report 输出压测统计，存在失败的调用时以状态码 1 退出
+func report(r *benchReport) {
+	printReport(r)
+	if r.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport 输出吞吐量、延迟分布和错误统计
+func printReport(r *benchReport) {
+	var sum time.Duration
+	for _, latency := range r.latencies {
+		sum += latency
+	}
+
+	fmt.Printf("Requests:    %d (%d failed)\n", r.total, r.failed)
+	fmt.Printf("Duration:    %s\n", r.elapsed.Round(time.Millisecond))
+	if r.elapsed > 0 {
+		fmt.Printf("Throughput:  %.1f req/s\n", float64(r.total)/r.elapsed.Seconds())
+	}
+	if r.total > 0 {
+		fmt.Println()
+		fmt.Println("Latency:")
+		fmt.Printf("  min   %s\n", r.latencies[0])
+		fmt.Printf("  mean  %s\n", sum/time.Duration(r.total))
+		fmt.Printf("  p50   %s\n", r.percentile(50))
+		fmt.Printf("  p90   %s\n", r.percentile(90))
+		fmt.Printf("  p99   %s\n", r.percentile(99))
+		fmt.Printf("  max   %s\n", r.latencies[len(r.latencies)-1])
+	}
+
+	if r.failed == 0 {
+		return
+	}
+	keys := make([]string, 0, len(r.errors))
+	for key := range r.errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Println()
+	fmt.Println("Errors:")
+	for _, key := range keys {
+		fmt.Printf("  %6d  %s\n", r.errors[key], key)
+	}
+}
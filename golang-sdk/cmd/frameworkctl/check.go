@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/framework"
+)
+
+// runCheck 检查配置文件并输出修改建议，存在错误（-strict 时包括警告）时以状态码 1 退出，可用于部署流水线
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	profiles := fs.String("profile", "", "comma-separated profiles to merge, defaults to FRAMEWORK_PROFILE")
+	overrides := config.FlagOverrides{}
+	fs.Var(overrides, "set", "override a config value, e.g. -set framework.network.port=9090 (repeatable)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	strict := fs.Bool("strict", false, "exit with status 1 on warnings as well as errors")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl check [-profile prod] [-set key=value] [-json] [-strict] <config.yaml|config-dir>")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Validates the config without starting the service: schema errors, unknown keys, port conflicts,")
+		fmt.Fprintln(os.Stderr, "TLS files, plaintext listeners and weak or default secrets.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts := &config.Options{Overrides: overrides}
+	if *profiles != "" {
+		opts.Profiles = strings.Split(*profiles, ",")
+	}
+	report, err := framework.CheckConfig(fs.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	errorCount := report.Count(framework.SeverityError)
+	warningCount := report.Count(framework.SeverityWarning)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		for _, f := range report.Findings {
+			fmt.Printf("%-7s %s\n", strings.ToUpper(f.Severity), f)
+			if f.Fix != "" {
+				fmt.Printf("        fix: %s\n", f.Fix)
+			}
+		}
+		if len(report.Findings) == 0 {
+			fmt.Printf("%s: OK\n", report.Path)
+		} else {
+			fmt.Printf("\n%s: %d error(s), %d warning(s)\n", report.Path, errorCount, warningCount)
+		}
+	}
+
+	if errorCount > 0 || (*strict && warningCount > 0) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// runErrorCodes 输出 Go SDK 的错误码映射表；-verify 时以其校验其他 SDK 导出的映射表，存在不一致时以状态码 1 退出
+func runErrorCodes(args []string) {
+	fs := flag.NewFlagSet("error-codes", flag.ExitOnError)
+	output := fs.String("o", "", "write the mapping table to this file instead of stdout")
+	verify := fs.String("verify", "", "verify a mapping table exported by another SDK instead of printing")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl error-codes [-o file] [-verify table.json]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Prints the error code mappings (HTTP/gRPC/JSON-RPC) of the Go SDK, which the Java and PHP SDKs must match.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *verify != "" {
+		data, err := os.ReadFile(*verify)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read mapping table: %v\n", err)
+			os.Exit(1)
+		}
+		var table frameworkerrors.MappingTable
+		if err := json.Unmarshal(data, &table); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse mapping table %s: %v\n", *verify, err)
+			os.Exit(1)
+		}
+		mismatches := frameworkerrors.VerifyMappings(&table)
+		for _, mismatch := range mismatches {
+			fmt.Println(mismatch)
+		}
+		if len(mismatches) > 0 {
+			fmt.Fprintf(os.Stderr, "%d mismatches\n", len(mismatches))
+			os.Exit(1)
+		}
+		fmt.Println("OK")
+		return
+	}
+
+	data, err := json.MarshalIndent(frameworkerrors.Mappings(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode mapping table: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,499 @@
+// frameworkctl 与运行中的框架服务交互的命令行工具
+//
+// 用法:
+//
+//	frameworkctl call [-addr host:port | -service name] [-token t] <method> [params]
+//	frameworkctl discover [-registry localhost:2379] [-json] <service>...
+//	frameworkctl health [-addr localhost:9090] [-check live|ready]
+//	frameworkctl routes [-addr localhost:9090] [-token t]
+//	frameworkctl bench [-addr host:port | -service name] [-n 1000] [-c 10] [-duration 0] <method> [params]
+//	frameworkctl replay [-addr host:port | -service name] [-c 10] [-speed 0] <file|->
+//	frameworkctl verify [-addr host:port | -service name] [-services a,b] <contract.json>...
+//	frameworkctl error-codes [-o file] [-verify table.json]
+//	frameworkctl check [-profile prod] [-set key=value] [-json] [-strict] <config.yaml>
+//
+// call 以 JSON-RPC 调用服务方法并输出结果；-service 时从 etcd 注册中心发现服务实例。
+// discover 列出注册中心中的服务实例；health 查询指标服务器的健康检查；
+// routes 经管理接口列出已注册的方法和协议端点；bench 并发调用方法并统计吞吐量和延迟分布；
+// replay 回放 framework.capture 录制的请求，报告处理结果与录制时不一致的调用；
+// verify 以 protocol/contract 录制的契约校验其他语言的实现，报告结果不一致的交互；
+// error-codes 输出错误码映射表，或以其校验其他语言 SDK 导出的映射表；
+// check 在部署前检查配置文件，输出错误、警告和修改建议，存在错误时以状态码 1 退出
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/framework/golang-sdk/client"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "call":
+		runCall(os.Args[2:])
+	case "discover":
+		runDiscover(os.Args[2:])
+	case "health":
+		runHealth(os.Args[2:])
+	case "routes":
+		runRoutes(os.Args[2:])
+	case "bench":
+		runBench(os.Args[2:])
+	case "replay":
+		runReplay(os.Args[2:])
+	case "verify":
+		runVerify(os.Args[2:])
+	case "error-codes":
+		runErrorCodes(os.Args[2:])
+	case "check":
+		runCheck(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+// usage 输出命令列表
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: frameworkctl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  call         invoke a service method via JSON-RPC")
+	fmt.Fprintln(os.Stderr, "  discover     list service instances in the registry")
+	fmt.Fprintln(os.Stderr, "  health       query the health check of a metrics server")
+	fmt.Fprintln(os.Stderr, "  routes       list registered methods and protocol endpoints via the admin API")
+	fmt.Fprintln(os.Stderr, "  bench        generate load against a service method")
+	fmt.Fprintln(os.Stderr, "  replay       replay captured requests against a service")
+	fmt.Fprintln(os.Stderr, "  verify       verify a service against recorded contracts")
+	fmt.Fprintln(os.Stderr, "  error-codes  print or verify the error code mapping tables")
+	fmt.Fprintln(os.Stderr, "  check        validate a config file and print a remediation report")
+}
+
+// registryFlags 连接 etcd 注册中心的参数
+type registryFlags struct {
+	endpoints *string
+	namespace *string
+}
+
+// addRegistryFlags 注册 etcd 注册中心参数
+func addRegistryFlags(fs *flag.FlagSet) *registryFlags {
+	defaults := registry.DefaultEtcdRegistryConfig()
+	return &registryFlags{
+		endpoints: fs.String("registry", strings.Join(defaults.Endpoints, ","), "comma-separated etcd endpoints"),
+		namespace: fs.String("namespace", defaults.Namespace, "registry namespace"),
+	}
+}
+
+// open 连接注册中心
+func (f *registryFlags) open() (registry.ServiceRegistry, error) {
+	config := registry.DefaultEtcdRegistryConfig()
+	config.Endpoints = strings.Split(*f.endpoints, ",")
+	config.Namespace = *f.namespace
+	return registry.NewEtcdRegistry(config)
+}
+
+// authFlags 认证请求头参数
+type authFlags struct {
+	token  *string
+	apiKey *string
+}
+
+// addAuthFlags 注册认证参数
+func addAuthFlags(fs *flag.FlagSet) *authFlags {
+	return &authFlags{
+		token:  fs.String("token", os.Getenv("FRAMEWORK_TOKEN"), "JWT sent as Authorization: Bearer, defaults to $FRAMEWORK_TOKEN"),
+		apiKey: fs.String("api-key", os.Getenv("FRAMEWORK_API_KEY"), "API key sent as X-API-Key, defaults to $FRAMEWORK_API_KEY"),
+	}
+}
+
+// apply 写入认证请求头
+func (f *authFlags) apply(header http.Header) {
+	if *f.token != "" {
+		header.Set("Authorization", "Bearer "+*f.token)
+	}
+	if *f.apiKey != "" {
+		header.Set("X-API-Key", *f.apiKey)
+	}
+}
+
+// targetFlags 调用目标参数，-addr 直接指定端点，否则从注册中心发现 -service 的实例
+type targetFlags struct {
+	addr     *string
+	service  *string
+	registry *registryFlags
+	auth     *authFlags
+	timeout  *time.Duration
+}
+
+// addTargetFlags 注册调用目标参数
+func addTargetFlags(fs *flag.FlagSet) *targetFlags {
+	return &targetFlags{
+		addr:     fs.String("addr", "", "JSON-RPC endpoint host:port, skips service discovery"),
+		service:  fs.String("service", "", "service name to discover in the registry"),
+		registry: addRegistryFlags(fs),
+		auth:     addAuthFlags(fs),
+		timeout:  fs.Duration("timeout", 10*time.Second, "request timeout"),
+	}
+}
+
+// endpoints 返回调用目标的 JSON-RPC 地址
+func (f *targetFlags) endpoints(ctx context.Context) ([]string, error) {
+	if *f.addr != "" {
+		return []string{jsonRpcURL(*f.addr)}, nil
+	}
+	if *f.service == "" {
+		return nil, fmt.Errorf("-addr or -service is required")
+	}
+
+	reg, err := f.registry.open()
+	if err != nil {
+		return nil, err
+	}
+	defer reg.Close()
+
+	services, err := reg.Discover(ctx, *f.service)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no instances of service %s", *f.service)
+	}
+	urls := make([]string, 0, len(services))
+	for _, service := range services {
+		urls = append(urls, jsonRpcURL(net.JoinHostPort(service.Address, strconv.Itoa(service.Port))))
+	}
+	return urls, nil
+}
+
+// jsonRpcURL 返回端点的 JSON-RPC 地址
+func jsonRpcURL(addr string) string {
+	return "http://" + addr + client.JsonRpcPath
+}
+
+// caller 以 JSON-RPC over HTTP 调用方法，复用连接
+type caller struct {
+	client *http.Client
+	auth   *authFlags
+	nextID atomic.Int64
+}
+
+// newCaller 创建调用器
+func newCaller(auth *authFlags) *caller {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 1024
+	return &caller{client: &http.Client{Transport: transport}, auth: auth}
+}
+
+// call 调用 url 上的方法，返回原始结果；调用失败时返回框架错误
+func (c *caller) call(ctx context.Context, url, method string, params json.RawMessage) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      c.nextID.Add(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.auth.apply(req.Header)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int             `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || (result.Error == nil && resp.StatusCode >= http.StatusBadRequest) {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return nil, decodeErrorBody(resp.StatusCode, data)
+		}
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to decode response")
+	}
+	if result.Error != nil {
+		if fe, err := frameworkerrors.UnmarshalError(result.Error.Data); err == nil {
+			return nil, fe
+		}
+		return nil, frameworkerrors.NewFrameworkErrorFromJSONRPCCode(result.Error.Code, result.Error.Message)
+	}
+	if len(result.Result) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return result.Result, nil
+}
+
+// parseCallArgs 解析 <method> [params] 参数，params 须为合法的 JSON
+func parseCallArgs(fs *flag.FlagSet) (string, json.RawMessage) {
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	var params json.RawMessage
+	if fs.NArg() == 2 {
+		params = json.RawMessage(fs.Arg(1))
+		if !json.Valid(params) {
+			fmt.Fprintln(os.Stderr, "params must be valid JSON")
+			os.Exit(2)
+		}
+	}
+	return fs.Arg(0), params
+}
+
+// runCall 调用服务方法
+func runCall(args []string) {
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	target := addTargetFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl call [-addr host:port | -service name] [flags] <method> [params]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, `Example: frameworkctl call -service order-service hello.sayHello '{"name":"Go"}'`)
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	method, params := parseCallArgs(fs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *target.timeout)
+	defer cancel()
+
+	urls, err := target.endpoints(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve endpoint: %v\n", err)
+		os.Exit(1)
+	}
+	caller := newCaller(target.auth)
+	result, err := caller.call(ctx, urls[rand.Intn(len(urls))], method, params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Call failed: %v\n", err)
+		os.Exit(1)
+	}
+	printJSON(result)
+}
+
+// runDiscover 列出服务实例
+func runDiscover(args []string) {
+	fs := flag.NewFlagSet("discover", flag.ExitOnError)
+	reg := addRegistryFlags(fs)
+	asJSON := fs.Bool("json", false, "print instances as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl discover [-registry localhost:2379] [-namespace /services] [-json] <service>...")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	r, err := reg.open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to registry: %v\n", err)
+		os.Exit(1)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var instances []*registry.ServiceInfo
+	for _, name := range fs.Args() {
+		services, err := r.Discover(ctx, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to discover %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		instances = append(instances, services...)
+	}
+
+	if *asJSON {
+		printJSON(instances)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tID\tADDRESS\tVERSION\tLANGUAGE\tPROTOCOLS")
+	for _, s := range instances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.ID,
+			net.JoinHostPort(s.Address, strconv.Itoa(s.Port)), s.Version, s.Language, strings.Join(s.Protocols, ","))
+	}
+	w.Flush()
+}
+
+// runHealth 查询健康检查，不健康时以状态码 1 退出
+func runHealth(args []string) {
+	fs := flag.NewFlagSet("health", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:9090", "metrics server host:port")
+	check := fs.String("check", "", "live or ready, defaults to the full health check")
+	timeout := fs.Duration("timeout", 10*time.Second, "request timeout")
+	fs.Parse(args)
+
+	path := "/health"
+	switch *check {
+	case "":
+	case "live", "ready":
+		path += "/" + *check
+	default:
+		fmt.Fprintf(os.Stderr, "unknown check %q\n", *check)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	status, body, err := get(ctx, "http://"+*addr+path, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to query health: %v\n", err)
+		os.Exit(1)
+	}
+	printBody(body)
+	if status != http.StatusOK {
+		os.Exit(1)
+	}
+}
+
+// runRoutes 经管理接口列出已注册的方法和协议端点
+func runRoutes(args []string) {
+	fs := flag.NewFlagSet("routes", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:9090", "metrics server host:port")
+	auth := addAuthFlags(fs)
+	asJSON := fs.Bool("json", false, "print routes as JSON")
+	timeout := fs.Duration("timeout", 10*time.Second, "request timeout")
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	status, body, err := get(ctx, "http://"+*addr+"/admin/routes", auth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to query routes: %v\n", err)
+		os.Exit(1)
+	}
+	if status != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Failed to query routes: %v\n", decodeErrorBody(status, body))
+		os.Exit(1)
+	}
+	if *asJSON {
+		printBody(body)
+		return
+	}
+
+	var routes struct {
+		Methods   []string `json:"methods"`
+		Protocols []struct {
+			Type     string `json:"type"`
+			Internal bool   `json:"internal"`
+			Port     int    `json:"port"`
+			Path     string `json:"path"`
+		} `json:"protocols"`
+	}
+	if err := json.Unmarshal(body, &routes); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode routes: %v\n", err)
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROTOCOL\tSCOPE\tPORT\tPATH")
+	for _, p := range routes.Protocols {
+		scope := "external"
+		if p.Internal {
+			scope = "internal"
+		}
+		port := "-"
+		if p.Port > 0 {
+			port = strconv.Itoa(p.Port)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Type, scope, port, p.Path)
+	}
+	w.Flush()
+	fmt.Println()
+	fmt.Println("METHODS")
+	for _, method := range routes.Methods {
+		fmt.Println(method)
+	}
+}
+
+// get 发送 GET 请求，返回状态码和响应体
+func get(ctx context.Context, url string, auth *authFlags) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if auth != nil {
+		auth.apply(req.Header)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
+
+// decodeErrorBody 将错误响应还原为框架错误，响应体不是框架错误格式时返回状态码和原始内容
+func decodeErrorBody(status int, body []byte) error {
+	if fe, err := frameworkerrors.UnmarshalError(body); err == nil {
+		return fe
+	}
+	return frameworkerrors.NewFrameworkErrorFromHTTPStatus(status, strings.TrimSpace(string(body)))
+}
+
+// printJSON 以缩进格式输出 v
+func printJSON(v interface{}) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode output: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+}
+
+// printBody 输出响应体，JSON 响应体缩进输出
+func printBody(body []byte) {
+	var out bytes.Buffer
+	if json.Indent(&out, body, "", "  ") != nil {
+		fmt.Println(strings.TrimSpace(string(body)))
+		return
+	}
+	fmt.Println(out.String())
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/capture"
+)
+
+// replayResult 单个录制请求的回放结果
+type replayResult struct {
+	benchResult
+	recorded string // 录制时的处理结果
+}
+
+// runReplay 将录制的请求以 JSON-RPC 重新发往目标服务，输出吞吐量、延迟分布以及处理结果与录制时不一致的调用，
+// 存在不一致时以状态码 1 退出
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	target := addTargetFlags(fs)
+	concurrency := fs.Int("c", 10, "maximum number of concurrent calls")
+	speed := fs.Float64("speed", 0, "replay at this multiple of the captured rate, 0 replays as fast as possible")
+	services := fs.String("services", "", "comma-separated services to replay, defaults to all captured services")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl replay [-addr host:port | -service name] [-c 10] [-speed 0] [flags] <file|->")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Replays requests recorded by framework.capture (JSON Lines file or the JSON array returned by /admin/capture).")
+		fmt.Fprintln(os.Stderr, "Captured headers are not replayed; use -token or -api-key to authenticate.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *concurrency < 1 || *speed < 0 {
+		fmt.Fprintln(os.Stderr, "-c must be positive and -speed must be non-negative")
+		os.Exit(2)
+	}
+
+	records, err := readCapture(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read capture: %v\n", err)
+		os.Exit(1)
+	}
+	records, skipped := replayable(records, *services)
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d records without a captured payload\n", skipped)
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(os.Stderr, "No records to replay")
+		os.Exit(1)
+	}
+
+	resolveCtx, cancel := context.WithTimeout(context.Background(), *target.timeout)
+	urls, err := target.endpoints(resolveCtx)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve endpoint: %v\n", err)
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	results := replay(context.Background(), newCaller(target.auth), urls, records, *concurrency, *speed, *target.timeout)
+	elapsed := time.Since(start)
+
+	collected := make([]benchResult, len(results))
+	for i, result := range results {
+		collected[i] = result.benchResult
+	}
+	printReport(newBenchReport(collected, elapsed))
+	if reportChanges(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+// readCapture 读取录制文件，path 为 - 时从标准输入读取
+func readCapture(path string) ([]*capture.Record, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	return capture.ReadRecords(r)
+}
+
+// replayable 按录制时间排序并过滤记录，返回可回放的记录和因未录制负载而跳过的记录数
+func replayable(records []*capture.Record, services string) ([]*capture.Record, int) {
+	allowed := make(map[string]bool)
+	for _, service := range strings.Split(services, ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			allowed[service] = true
+		}
+	}
+
+	filtered := make([]*capture.Record, 0, len(records))
+	skipped := 0
+	for _, record := range records {
+		if len(allowed) > 0 && !allowed[record.Service] {
+			continue
+		}
+		if record.PayloadOmitted {
+			skipped++
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Time.Before(filtered[j].Time)
+	})
+	return filtered, skipped
+}
+
+// replay 以最多 concurrency 个并发回放记录，多个端点时轮询；speed 大于 0 时按录制的请求间隔除以 speed 发出请求
+func replay(ctx context.Context, c *caller, urls []string, records []*capture.Record,
+	concurrency int, speed float64, timeout time.Duration) []replayResult {
+	results := make([]replayResult, len(records))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				record := records[n]
+				callCtx, cancel := context.WithTimeout(ctx, timeout)
+				begin := time.Now()
+				_, err := c.call(callCtx, urls[n%len(urls)], record.Service+"."+record.Method, json.RawMessage(record.Payload))
+				latency := time.Since(begin)
+				cancel()
+				results[n] = replayResult{benchResult: benchResult{latency: latency, err: err}, recorded: record.Status}
+			}
+		}()
+	}
+
+	start := time.Now()
+	for n, record := range records {
+		if speed > 0 {
+			offset := time.Duration(float64(record.Time.Sub(records[0].Time)) / speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		jobs <- n
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// reportChanges 输出处理结果与录制时不一致的调用，返回不一致的调用数
+func reportChanges(results []replayResult) int {
+	changes := make(map[string]int)
+	total := 0
+	for _, result := range results {
+		status := adapter.ErrorCodeLabel(result.err)
+		if result.recorded == "" || status == result.recorded {
+			continue
+		}
+		changes[result.recorded+" -> "+status]++
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Println()
+	fmt.Println("Status changes (captured -> replayed):")
+	for _, key := range keys {
+		fmt.Printf("  %6d  %s\n", changes[key], key)
+	}
+	return total
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/contract"
+)
+
+// runVerify 以 JSON-RPC 调用目标服务，逐个校验契约文件中的交互，存在不通过的交互时以状态码 1 退出
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	target := addTargetFlags(fs)
+	services := fs.String("services", "", "comma-separated services to verify, defaults to all services in the contract")
+	verbose := fs.Bool("v", false, "print passed interactions as well")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: frameworkctl verify [-addr host:port | -service name] [-services a,b] [-v] [flags] <contract.json>...")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Verifies an implementation (e.g. the Java or PHP SDK) against contracts recorded by the Go side with protocol/contract.")
+		fmt.Fprintln(os.Stderr)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	merged := &contract.Contract{Version: contract.FormatVersion}
+	for _, path := range fs.Args() {
+		c, err := contract.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read contract %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		merged.Merge(c)
+	}
+	var allowed []string
+	for _, service := range strings.Split(*services, ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			allowed = append(allowed, service)
+		}
+	}
+	merged = merged.Filter(allowed...)
+	if len(merged.Interactions) == 0 {
+		fmt.Fprintln(os.Stderr, "No interactions to verify")
+		os.Exit(1)
+	}
+
+	resolveCtx, cancel := context.WithTimeout(context.Background(), *target.timeout)
+	urls, err := target.endpoints(resolveCtx)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve endpoint: %v\n", err)
+		os.Exit(1)
+	}
+
+	c := newCaller(target.auth)
+	call := func(ctx context.Context, service, method string, params json.RawMessage) (json.RawMessage, error) {
+		callCtx, cancel := context.WithTimeout(ctx, *target.timeout)
+		defer cancel()
+		return c.call(callCtx, urls[0], service+"."+method, params)
+	}
+
+	start := time.Now()
+	report := contract.Verify(context.Background(), merged, call)
+	for _, result := range report.Results {
+		if result.Passed() {
+			if *verbose {
+				fmt.Printf("PASS  %s\n", result.Interaction.Name())
+			}
+			continue
+		}
+		fmt.Printf("FAIL  %s\n", result.Interaction.Name())
+		for _, mismatch := range result.Mismatches {
+			fmt.Printf("        %s\n", mismatch)
+		}
+	}
+	failed := report.Failed()
+	fmt.Printf("\n%d interactions, %d passed, %d failed in %s\n",
+		len(report.Results), len(report.Results)-failed, failed, time.Since(start).Round(time.Millisecond))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,439 @@
+// Package codegen 根据 protobuf 服务定义生成绑定到框架的多语言桩代码，各语言使用相同的 JSON-RPC 方法名和负载结构。
+//
+// 输入为 protoc --include_imports --descriptor_set_out 生成的描述符集，生成内容：
+//
+//   - Go：消息结构体、服务接口、注册到 framework.Server 的函数和基于 client.FrameworkClient 的客户端
+//   - PHP、Java：每个服务一个方法映射类，包含方法名常量以及请求、响应消息的字段结构
+//
+// JSON-RPC 方法名为 <首字母小写的服务名>.<首字母小写的方法名>，如 Greeter.SayHello 为 greeter.sayHello，
+// 与 framework.Server.Register 的命名一致；负载字段名为 protobuf 的 JSON 名称。
+package codegen
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Language 生成的目标语言
+type Language string
+
+const (
+	LanguageGo   Language = "go"
+	LanguagePHP  Language = "php"
+	LanguageJava Language = "java"
+)
+
+// ParseLanguage 解析目标语言：go、php 或 java，不区分大小写
+func ParseLanguage(s string) (Language, error) {
+	switch lang := Language(strings.ToLower(strings.TrimSpace(s))); lang {
+	case LanguageGo, LanguagePHP, LanguageJava:
+		return lang, nil
+	default:
+		return "", fmt.Errorf("unsupported language %q, expected go, php or java", s)
+	}
+}
+
+// Options 生成选项
+type Options struct {
+	// Language 目标语言
+	Language Language
+	// Files 生成的 proto 文件名（描述符集中的名称，如 hello.proto），为空时生成除 google/protobuf/ 外的所有文件
+	Files []string
+	// Package Go 包名、PHP 命名空间或 Java 包名，为空时依次取 go_package/php_namespace/java_package 选项和 proto 包名
+	Package string
+}
+
+// File 生成的文件
+type File struct {
+	// Name 相对输出目录的路径
+	Name    string
+	Content []byte
+}
+
+// 特殊处理的 protobuf 内置类型
+const (
+	wellKnownPrefix = ".google.protobuf."
+	typeEmpty       = ".google.protobuf.Empty"
+	typeTimestamp   = ".google.protobuf.Timestamp"
+)
+
+// Generate 为描述符集中的 proto 文件生成目标语言代码
+//
+// 不支持流式方法：框架的本地方法分发只处理一元调用
+func Generate(set *descriptorpb.FileDescriptorSet, opts Options) ([]File, error) {
+	if set == nil {
+		return nil, fmt.Errorf("descriptor set cannot be nil")
+	}
+	s := newSchema(set)
+
+	files, err := s.selectFiles(opts.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	var generated []File
+	for _, file := range files {
+		var (
+			out []File
+			err error
+		)
+		switch opts.Language {
+		case LanguageGo:
+			out, err = generateGo(s, file, opts.Package)
+		case LanguagePHP:
+			out, err = generatePHP(s, file, opts.Package)
+		case LanguageJava:
+			out, err = generateJava(s, file, opts.Package)
+		default:
+			return nil, fmt.Errorf("unsupported language %q, expected go, php or java", opts.Language)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.GetName(), err)
+		}
+		generated = append(generated, out...)
+	}
+	return generated, nil
+}
+
+// schema 描述符集中所有消息和枚举的索引，键为以 . 开头的全名
+type schema struct {
+	files    []*descriptorpb.FileDescriptorProto
+	messages map[string]*messageType
+	enums    map[string]*enumType
+}
+
+// messageType 消息及其在生成代码中的名称
+type messageType struct {
+	fullName string
+	// goName 嵌套消息以 _ 连接外层消息名，与 protoc-gen-go 一致
+	goName  string
+	file    string
+	comment string
+	desc    *descriptorpb.DescriptorProto
+}
+
+// enumType 枚举及其在生成代码中的名称
+type enumType struct {
+	fullName string
+	goName   string
+	file     string
+	comment  string
+	desc     *descriptorpb.EnumDescriptorProto
+}
+
+// 源码位置路径中的字段号，见 descriptor.proto
+const (
+	pathMessageType   = 4
+	pathEnumType      = 5
+	pathService       = 6
+	pathMessageNested = 3
+	pathMessageEnum   = 4
+	pathServiceMethod = 2
+)
+
+// newSchema 索引描述符集中的消息和枚举，包括嵌套类型
+func newSchema(set *descriptorpb.FileDescriptorSet) *schema {
+	s := &schema{
+		files:    set.GetFile(),
+		messages: make(map[string]*messageType),
+		enums:    make(map[string]*enumType),
+	}
+	for _, file := range set.GetFile() {
+		comments := fileComments(file)
+		prefix := "." + file.GetPackage()
+		if file.GetPackage() == "" {
+			prefix = ""
+		}
+		for i, msg := range file.GetMessageType() {
+			s.addMessage(file.GetName(), comments, []int32{pathMessageType, int32(i)}, prefix, "", msg)
+		}
+		for i, enum := range file.GetEnumType() {
+			s.addEnum(file.GetName(), comments, []int32{pathEnumType, int32(i)}, prefix, "", enum)
+		}
+	}
+	return s
+}
+
+// addMessage 索引消息及其嵌套类型
+func (s *schema) addMessage(file string, comments map[string]string, loc []int32, prefix, goPrefix string, msg *descriptorpb.DescriptorProto) {
+	fullName := prefix + "." + msg.GetName()
+	goName := goPrefix + camelCase(msg.GetName())
+	s.messages[fullName] = &messageType{
+		fullName: fullName,
+		goName:   goName,
+		file:     file,
+		comment:  comments[pathKey(loc)],
+		desc:     msg,
+	}
+	for i, nested := range msg.GetNestedType() {
+		s.addMessage(file, comments, appendPath(loc, pathMessageNested, i), fullName, goName+"_", nested)
+	}
+	for i, enum := range msg.GetEnumType() {
+		s.addEnum(file, comments, appendPath(loc, pathMessageEnum, i), fullName, goName+"_", enum)
+	}
+}
+
+// addEnum 索引枚举
+func (s *schema) addEnum(file string, comments map[string]string, loc []int32, prefix, goPrefix string, enum *descriptorpb.EnumDescriptorProto) {
+	fullName := prefix + "." + enum.GetName()
+	s.enums[fullName] = &enumType{
+		fullName: fullName,
+		goName:   goPrefix + camelCase(enum.GetName()),
+		file:     file,
+		comment:  comments[pathKey(loc)],
+		desc:     enum,
+	}
+}
+
+// selectFiles 返回需要生成的文件，names 为空时返回除 google/protobuf/ 外的所有文件
+func (s *schema) selectFiles(names []string) ([]*descriptorpb.FileDescriptorProto, error) {
+	if len(names) == 0 {
+		var files []*descriptorpb.FileDescriptorProto
+		for _, file := range s.files {
+			if !strings.HasPrefix(file.GetName(), "google/protobuf/") {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	byName := make(map[string]*descriptorpb.FileDescriptorProto, len(s.files))
+	for _, file := range s.files {
+		byName[file.GetName()] = file
+	}
+	files := make([]*descriptorpb.FileDescriptorProto, 0, len(names))
+	for _, name := range names {
+		file, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("file %s not found in descriptor set", name)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// service 服务及其方法，已校验为一元调用
+type service struct {
+	name    string
+	comment string
+	// rpcName JSON-RPC 服务名
+	rpcName string
+	methods []*method
+}
+
+// method 服务方法
+type method struct {
+	name    string
+	comment string
+	// rpcName JSON-RPC 方法名，如 greeter.sayHello
+	rpcName string
+	input   string
+	output  string
+}
+
+// services 返回文件中的服务，存在流式方法或引用未知消息类型时返回错误
+func (s *schema) services(file *descriptorpb.FileDescriptorProto) ([]*service, error) {
+	comments := fileComments(file)
+	var services []*service
+	for i, sd := range file.GetService() {
+		svc := &service{
+			name:    camelCase(sd.GetName()),
+			comment: comments[pathKey([]int32{pathService, int32(i)})],
+			rpcName: lowerFirst(camelCase(sd.GetName())),
+		}
+		for j, md := range sd.GetMethod() {
+			if md.GetClientStreaming() || md.GetServerStreaming() {
+				return nil, fmt.Errorf("%s.%s: streaming methods are not supported", sd.GetName(), md.GetName())
+			}
+			for _, typ := range []string{md.GetInputType(), md.GetOutputType()} {
+				if _, ok := s.messages[typ]; !ok && !strings.HasPrefix(typ, wellKnownPrefix) {
+					return nil, fmt.Errorf("%s.%s: unknown message type %s", sd.GetName(), md.GetName(), typ)
+				}
+			}
+			svc.methods = append(svc.methods, &method{
+				name:    camelCase(md.GetName()),
+				comment: comments[pathKey([]int32{pathService, int32(i), pathServiceMethod, int32(j)})],
+				rpcName: svc.rpcName + "." + lowerFirst(camelCase(md.GetName())),
+				input:   md.GetInputType(),
+				output:  md.GetOutputType(),
+			})
+		}
+		services = append(services, svc)
+	}
+	return services, nil
+}
+
+// mapEntry 返回 map 字段的键值字段，非 map 字段返回 nil
+func (s *schema) mapEntry(field *descriptorpb.FieldDescriptorProto) (key, value *descriptorpb.FieldDescriptorProto) {
+	if field.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || field.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return nil, nil
+	}
+	msg, ok := s.messages[field.GetTypeName()]
+	if !ok || !msg.desc.GetOptions().GetMapEntry() || len(msg.desc.GetField()) != 2 {
+		return nil, nil
+	}
+	return msg.desc.GetField()[0], msg.desc.GetField()[1]
+}
+
+// reachable 返回服务方法的请求、响应消息直接或间接引用的消息和枚举全名（已排序，不含 google.protobuf 内置类型和 map Entry）
+func (s *schema) reachable(services []*service) (messages, enums []string) {
+	seenMessages := make(map[string]bool)
+	seenEnums := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		msg, ok := s.messages[name]
+		if !ok || seenMessages[name] || strings.HasPrefix(name, wellKnownPrefix) {
+			return
+		}
+		seenMessages[name] = true
+		for _, field := range msg.desc.GetField() {
+			switch field.GetType() {
+			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+				visit(field.GetTypeName())
+			case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+				seenEnums[field.GetTypeName()] = true
+			}
+		}
+	}
+	for _, svc := range services {
+		for _, m := range svc.methods {
+			visit(m.input)
+			visit(m.output)
+		}
+	}
+
+	for name := range seenMessages {
+		if !s.messages[name].desc.GetOptions().GetMapEntry() {
+			messages = append(messages, name)
+		}
+	}
+	for name := range seenEnums {
+		enums = append(enums, name)
+	}
+	sort.Strings(messages)
+	sort.Strings(enums)
+	return messages, enums
+}
+
+// shapeType 返回字段在 PHP、Java 映射表中的类型描述，如 string、repeated hello.User、map<string, int32>
+func (s *schema) shapeType(field *descriptorpb.FieldDescriptorProto) string {
+	if key, value := s.mapEntry(field); key != nil {
+		return fmt.Sprintf("map<%s, %s>", s.elementShape(key), s.elementShape(value))
+	}
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return "repeated " + s.elementShape(field)
+	}
+	return s.elementShape(field)
+}
+
+// elementShape 返回单个值的类型描述，消息和枚举为不带前导 . 的全名
+func (s *schema) elementShape(field *descriptorpb.FieldDescriptorProto) string {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_ENUM, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return strings.TrimPrefix(field.GetTypeName(), ".")
+	default:
+		return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	}
+}
+
+// jsonName 返回字段的 JSON 名称，描述符未设置 json_name 时按 protoc 的规则转换为小驼峰
+func jsonName(field *descriptorpb.FieldDescriptorProto) string {
+	if field.JsonName != nil {
+		return field.GetJsonName()
+	}
+	var b strings.Builder
+	upper := false
+	for _, r := range field.GetName() {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// fileComments 返回文件中各位置的前置注释，键为 pathKey 编码的位置路径
+func fileComments(file *descriptorpb.FileDescriptorProto) map[string]string {
+	comments := make(map[string]string)
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		if comment := strings.TrimSpace(loc.GetLeadingComments()); comment != "" {
+			comments[pathKey(loc.GetPath())] = comment
+		}
+	}
+	return comments
+}
+
+func pathKey(path []int32) string {
+	return fmt.Sprint(path)
+}
+
+func appendPath(loc []int32, field int32, index int) []int32 {
+	path := make([]int32, 0, len(loc)+2)
+	return append(append(path, loc...), field, int32(index))
+}
+
+// camelCase 将 proto 名称转换为大驼峰，如 user_id -> UserId，与 protoc-gen-go 一致
+func camelCase(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// lowerFirst 首字母小写，与 framework.Server.Register 的方法命名一致
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// constantName 将方法名转换为常量名，如 SayHello -> SAY_HELLO
+func constantName(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// baseName 返回不含目录和 .proto 后缀的文件名
+func baseName(file *descriptorpb.FileDescriptorProto) string {
+	return strings.TrimSuffix(path.Base(file.GetName()), ".proto")
+}
+
+// commentLines 将注释拆分为行，去除每行首尾空白
+func commentLines(comment string) []string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines
+}
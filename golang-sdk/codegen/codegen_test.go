@@ -0,0 +1,323 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+)
+
+// field 创建字段描述符，typeName 为消息或枚举类型的全名
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   typ.Enum(),
+		Label:  label.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// helloDescriptorSet 创建测试用的描述符集，modify 修改 Greeter 服务
+func helloDescriptorSet(modify func(service *descriptorpb.ServiceDescriptorProto)) *descriptorpb.FileDescriptorSet {
+	service := &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String("Greeter"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			{Name: proto.String("SayHello"), InputType: proto.String(".hello.HelloRequest"), OutputType: proto.String(".hello.HelloReply")},
+			{Name: proto.String("Ping"), InputType: proto.String(".google.protobuf.Empty"), OutputType: proto.String(".google.protobuf.Empty")},
+		},
+	}
+	if modify != nil {
+		modify(service)
+	}
+
+	request := &descriptorpb.DescriptorProto{
+		Name: proto.String("HelloRequest"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+			field("user_id", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+			field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+			field("labels", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".hello.HelloRequest.LabelsEntry"),
+			field("mood", 5, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".hello.Mood"),
+			field("sent_at", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+		},
+		NestedType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("LabelsEntry"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+			},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}},
+	}
+	reply := &descriptorpb.DescriptorProto{
+		Name: proto.String("HelloReply"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			field("message", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+		},
+	}
+	mood := &descriptorpb.EnumDescriptorProto{
+		Name: proto.String("Mood"),
+		Value: []*descriptorpb.EnumValueDescriptorProto{
+			{Name: proto.String("MOOD_UNSPECIFIED"), Number: proto.Int32(0)},
+			{Name: proto.String("HAPPY"), Number: proto.Int32(1)},
+		},
+	}
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("google/protobuf/empty.proto"),
+				Package: proto.String("google.protobuf"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("Empty")},
+				},
+			},
+			{
+				Name:        proto.String("hello.proto"),
+				Package:     proto.String("hello"),
+				Syntax:      proto.String("proto3"),
+				Dependency:  []string{"google/protobuf/empty.proto"},
+				MessageType: []*descriptorpb.DescriptorProto{request, reply},
+				EnumType:    []*descriptorpb.EnumDescriptorProto{mood},
+				Service:     []*descriptorpb.ServiceDescriptorProto{service},
+				Options: &descriptorpb.FileOptions{
+					GoPackage:   proto.String("example.com/hello/hellopb"),
+					JavaPackage: proto.String("com.example.hello"),
+				},
+				SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+					Location: []*descriptorpb.SourceCodeInfo_Location{
+						{Path: []int32{6, 0}, LeadingComments: proto.String(" Greeter 问候服务\n")},
+						{Path: []int32{6, 0, 2, 0}, LeadingComments: proto.String(" 返回问候语\n")},
+					},
+				},
+			},
+		},
+	}
+}
+
+// generate 生成 hello.proto 并返回文件名到内容的映射
+func generate(t *testing.T, lang Language, pkg string) map[string]string {
+	t.Helper()
+	files, err := Generate(helloDescriptorSet(nil), Options{Language: lang, Package: pkg})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	out := make(map[string]string, len(files))
+	for _, f := range files {
+		out[f.Name] = string(f.Content)
+	}
+	return out
+}
+
+func assertContains(t *testing.T, content string, want []string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(content, w) {
+			t.Errorf("Generated code missing %q:\n%s", w, content)
+		}
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	files := generate(t, LanguageGo, "")
+	content, ok := files["hello.framework.go"]
+	if !ok || len(files) != 1 {
+		t.Fatalf("Expected only hello.framework.go, got %v", files)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "hello.framework.go", content, parser.ParseComments); err != nil {
+		t.Fatalf("Generated code does not parse: %v", err)
+	}
+	assertContains(t, content, []string{
+		"// Code generated by framework gen from hello.proto. DO NOT EDIT.",
+		"package hellopb",
+		`GreeterServiceName = "greeter"`,
+		`GreeterSayHelloMethod = "greeter.sayHello"`,
+		`GreeterPingMethod = "greeter.ping"`,
+		"type Mood string",
+		`Mood_HAPPY            Mood = "HAPPY"`,
+		"Name   string           `json:\"name,omitempty\"`",
+		"UserId int64            `json:\"userId,omitempty\"`",
+		"Tags   []string         `json:\"tags,omitempty\"`",
+		"Labels map[string]int32 `json:\"labels,omitempty\"`",
+		"Mood   Mood             `json:\"mood,omitempty\"`",
+		"SentAt *time.Time       `json:\"sentAt,omitempty\"`",
+		"// GreeterServer Greeter 问候服务",
+		"// SayHello 返回问候语",
+		"SayHello(ctx context.Context, req *HelloRequest) (*HelloReply, error)",
+		"Ping(ctx context.Context) error",
+		"return server.Register(GreeterServiceName, struct{ GreeterServer }{impl})",
+		"func NewGreeterClient(c client.FrameworkClient, service string) *GreeterClient",
+		"if err := c.client.Call(ctx, c.service, GreeterSayHelloMethod, req, resp); err != nil {",
+		"return c.client.Call(ctx, c.service, GreeterPingMethod, nil, nil)",
+	})
+	if strings.Contains(content, "LabelsEntry") || strings.Contains(content, "type Empty") {
+		t.Errorf("Map entries and google/protobuf types should not be generated:\n%s", content)
+	}
+}
+
+func TestGenerateGo_Package(t *testing.T) {
+	content := generate(t, LanguageGo, "greeter")["hello.framework.go"]
+	assertContains(t, content, []string{"package greeter"})
+}
+
+func TestGeneratePHP(t *testing.T) {
+	files := generate(t, LanguagePHP, "")
+	content, ok := files["GreeterMethods.php"]
+	if !ok {
+		t.Fatalf("Expected GreeterMethods.php, got %v", files)
+	}
+	assertContains(t, content, []string{
+		"namespace Hello;",
+		"final class GreeterMethods",
+		" * Greeter 问候服务",
+		"const SERVICE = 'greeter';",
+		"const SAY_HELLO = 'greeter.sayHello';",
+		"const PING = 'greeter.ping';",
+		"self::SAY_HELLO => ['request' => 'hello.HelloRequest', 'response' => 'hello.HelloReply'],",
+		"self::PING => ['request' => 'google.protobuf.Empty', 'response' => 'google.protobuf.Empty'],",
+		"'userId' => 'int64',",
+		"'tags' => 'repeated string',",
+		"'labels' => 'map<string, int32>',",
+		"'mood' => 'hello.Mood',",
+		"'hello.Mood' => ['MOOD_UNSPECIFIED', 'HAPPY'],",
+	})
+	if strings.Contains(content, "'google.protobuf.Empty' => [") {
+		t.Errorf("google/protobuf types should not be expanded:\n%s", content)
+	}
+}
+
+func TestGenerateJava(t *testing.T) {
+	files := generate(t, LanguageJava, "")
+	content, ok := files["com/example/hello/GreeterMethods.java"]
+	if !ok {
+		t.Fatalf("Expected com/example/hello/GreeterMethods.java, got %v", files)
+	}
+	assertContains(t, content, []string{
+		"package com.example.hello;",
+		"public final class GreeterMethods {",
+		`public static final String SERVICE = "greeter";`,
+		`public static final String SAY_HELLO = "greeter.sayHello";`,
+		`Map.entry(SAY_HELLO, new MethodType("hello.HelloRequest", "hello.HelloReply"))`,
+		`Map.entry("labels", "map<string, int32>")`,
+		`Map.entry("hello.Mood", List.of("MOOD_UNSPECIFIED", "HAPPY"))`,
+	})
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(service *descriptorpb.ServiceDescriptorProto)
+		opts    Options
+		wantErr string
+	}{
+		{
+			name: "流式方法",
+			modify: func(service *descriptorpb.ServiceDescriptorProto) {
+				service.Method[0].ServerStreaming = proto.Bool(true)
+			},
+			opts:    Options{Language: LanguageGo},
+			wantErr: "Greeter.SayHello: streaming methods are not supported",
+		},
+		{
+			name: "未知消息类型",
+			modify: func(service *descriptorpb.ServiceDescriptorProto) {
+				service.Method[0].InputType = proto.String(".hello.Missing")
+			},
+			opts:    Options{Language: LanguagePHP},
+			wantErr: "unknown message type .hello.Missing",
+		},
+		{
+			name:    "文件不存在",
+			opts:    Options{Language: LanguageJava, Files: []string{"missing.proto"}},
+			wantErr: "file missing.proto not found",
+		},
+		{
+			name:    "不支持的语言",
+			opts:    Options{Language: "rust"},
+			wantErr: `unsupported language "rust"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(helloDescriptorSet(tt.modify), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(string) string
+		input    string
+		expected string
+	}{
+		{"camelCase 下划线", camelCase, "user_id", "UserId"},
+		{"camelCase 已是大驼峰", camelCase, "SayHello", "SayHello"},
+		{"lowerFirst", lowerFirst, "SayHello", "sayHello"},
+		{"constantName 大驼峰", constantName, "SayHello", "SAY_HELLO"},
+		{"constantName 连续大写", constantName, "GetHTTPStatus", "GET_HTTP_STATUS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.input); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if lang, err := ParseLanguage(" Java "); err != nil || lang != LanguageJava {
+		t.Errorf("ParseLanguage = %q, %v; want java", lang, err)
+	}
+	if _, err := ParseLanguage("rust"); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+}
+
+func TestExamples(t *testing.T) {
+	set := helloDescriptorSet(func(service *descriptorpb.ServiceDescriptorProto) {
+		service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+			Name: proto.String("Echo"), InputType: proto.String(".hello.HelloRequest"), OutputType: proto.String(".hello.HelloRequest"),
+		})
+	})
+	examples, err := Examples(set, nil)
+	if err != nil {
+		t.Fatalf("Examples failed: %v", err)
+	}
+	got := make(map[string]string, len(examples))
+	for _, example := range examples {
+		got[example.Method] = string(example.Response)
+	}
+	want := map[string]string{
+		"greeter.sayHello": `{"message":"message"}`,
+		"greeter.ping":     `null`,
+		"greeter.echo":     `{"labels":{"key":1},"mood":"HAPPY","name":"name","sentAt":"2024-01-01T00:00:00Z","tags":["tags"],"userId":1}`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Examples = %v", got)
+	}
+	for method, response := range want {
+		if got[method] != response {
+			t.Errorf("%s = %s, want %s", method, got[method], response)
+		}
+	}
+}
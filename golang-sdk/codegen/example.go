@@ -0,0 +1,134 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// 内置类型的示例值
+const (
+	typeDuration      = ".google.protobuf.Duration"
+	exampleTimestamp  = "2024-01-01T00:00:00Z"
+	exampleDuration   = "1s"
+	exampleBytesValue = "ZXhhbXBsZQ==" // base64("example")
+)
+
+// Example 服务方法的示例响应，按响应消息的字段类型生成
+type Example struct {
+	// Method JSON-RPC 方法名，如 greeter.sayHello
+	Method string
+	// Response 响应的 JSON，google.protobuf.Empty 响应为 null
+	Response json.RawMessage
+}
+
+// Examples 为描述符集中服务方法的响应生成示例值，供 mock 服务在后端实现之前返回，files 为空时使用除 google/protobuf/ 外的所有文件
+//
+// 字段名与生成的桩代码一致（json_name 或小驼峰）；字符串为字段的 JSON 名称，整数为 1，浮点数为 1.5，布尔值为 true，
+// 枚举为第一个非零值的名称，重复字段和 map 各含一个元素，Timestamp 为 RFC 3339 时间；递归引用的消息在第二层省略
+func Examples(set *descriptorpb.FileDescriptorSet, files []string) ([]Example, error) {
+	if set == nil {
+		return nil, fmt.Errorf("descriptor set cannot be nil")
+	}
+	s := newSchema(set)
+	selected, err := s.selectFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var examples []Example
+	for _, file := range selected {
+		services, err := s.services(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.GetName(), err)
+		}
+		for _, svc := range services {
+			for _, m := range svc.methods {
+				var value interface{}
+				if m.output != typeEmpty {
+					value = s.exampleMessage(m.output, map[string]bool{})
+				}
+				data, err := json.Marshal(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", m.rpcName, err)
+				}
+				examples = append(examples, Example{Method: m.rpcName, Response: data})
+			}
+		}
+	}
+	return examples, nil
+}
+
+// exampleMessage 返回消息的示例值，visiting 为正在生成的外层消息，用于截断递归引用
+func (s *schema) exampleMessage(name string, visiting map[string]bool) interface{} {
+	if strings.HasPrefix(name, wellKnownPrefix) {
+		switch name {
+		case typeTimestamp:
+			return exampleTimestamp
+		case typeDuration:
+			return exampleDuration
+		default:
+			return map[string]interface{}{}
+		}
+	}
+	msg, ok := s.messages[name]
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	visiting[name] = true
+	defer delete(visiting, name)
+	value := make(map[string]interface{}, len(msg.desc.GetField()))
+	for _, field := range msg.desc.GetField() {
+		if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && visiting[field.GetTypeName()] {
+			continue
+		}
+		if key, val := s.mapEntry(field); key != nil {
+			if val.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && visiting[val.GetTypeName()] {
+				continue
+			}
+			value[jsonName(field)] = map[string]interface{}{
+				fmt.Sprint(s.exampleValue(key, visiting)): s.exampleValue(val, visiting),
+			}
+			continue
+		}
+		element := s.exampleValue(field, visiting)
+		if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+			value[jsonName(field)] = []interface{}{element}
+		} else {
+			value[jsonName(field)] = element
+		}
+	}
+	return value
+}
+
+// exampleValue 返回字段单个值的示例
+func (s *schema) exampleValue(field *descriptorpb.FieldDescriptorProto, visiting map[string]bool) interface{} {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return 1.5
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return true
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return jsonName(field)
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return exampleBytesValue
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		enum, ok := s.enums[field.GetTypeName()]
+		if !ok || len(enum.desc.GetValue()) == 0 {
+			return ""
+		}
+		for _, value := range enum.desc.GetValue() {
+			if value.GetNumber() != 0 {
+				return value.GetName()
+			}
+		}
+		return enum.desc.GetValue()[0].GetName()
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return s.exampleMessage(field.GetTypeName(), visiting)
+	default:
+		return 1
+	}
+}
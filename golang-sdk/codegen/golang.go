@@ -0,0 +1,396 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// 生成的 Go 代码引用的框架包
+const (
+	importClient    = "github.com/framework/golang-sdk/client"
+	importFramework = "github.com/framework/golang-sdk/framework"
+)
+
+// goGenerator 生成单个 proto 文件的 Go 代码
+type goGenerator struct {
+	schema  *schema
+	file    *descriptorpb.FileDescriptorProto
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+// generateGo 生成 <文件名>.framework.go，包含文件中定义的消息、枚举和服务桩代码
+//
+// 同一描述符集的所有文件应生成到同一个 Go 包中，跨文件引用的消息类型按名称直接引用
+func generateGo(s *schema, file *descriptorpb.FileDescriptorProto, pkg string) ([]File, error) {
+	services, err := s.services(file)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == "" {
+		pkg = goPackageName(file)
+	}
+
+	g := &goGenerator{schema: s, file: file, imports: make(map[string]bool)}
+	if err := g.generateBody(services); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by framework gen from %s. DO NOT EDIT.\n\n", file.GetName())
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		// 标准库在前，框架包在后，与 goimports 的分组一致
+		sort.Slice(imports, func(i, j int) bool {
+			if isStdlib(imports[i]) != isStdlib(imports[j]) {
+				return isStdlib(imports[i])
+			}
+			return imports[i] < imports[j]
+		})
+		out.WriteString("import (\n")
+		for i, imp := range imports {
+			if i > 0 && isStdlib(imp) != isStdlib(imports[i-1]) {
+				out.WriteString("\n")
+			}
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	content, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	name := path.Join(path.Dir(file.GetName()), baseName(file)+".framework.go")
+	return []File{{Name: name, Content: content}}, nil
+}
+
+// generateBody 按方法名常量、枚举、消息、服务的顺序生成代码
+func (g *goGenerator) generateBody(services []*service) error {
+	if len(services) > 0 {
+		g.p("// JSON-RPC 服务名和方法名，与 PHP、Java 生成的方法映射一致")
+		g.p("const (")
+		for _, svc := range services {
+			g.p("\t// %sServiceName %s 服务注册的名称", svc.name, svc.name)
+			g.p("\t%sServiceName = %q", svc.name, svc.rpcName)
+			for _, m := range svc.methods {
+				g.p("\t// %s%sMethod %s.%s 的方法名", svc.name, m.name, svc.name, m.name)
+				g.p("\t%s%sMethod = %q", svc.name, m.name, m.rpcName)
+			}
+		}
+		g.p(")")
+		g.p("")
+	}
+
+	for _, enum := range g.fileEnums() {
+		g.generateEnum(enum)
+	}
+	for _, msg := range g.fileMessages() {
+		if err := g.generateMessage(msg); err != nil {
+			return err
+		}
+	}
+	for _, svc := range services {
+		if err := g.generateService(svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileMessages 返回文件中定义的消息（含嵌套消息，不含 map Entry），按全名排序
+func (g *goGenerator) fileMessages() []*messageType {
+	var messages []*messageType
+	for _, msg := range g.schema.messages {
+		if msg.file == g.file.GetName() && !msg.desc.GetOptions().GetMapEntry() {
+			messages = append(messages, msg)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].fullName < messages[j].fullName })
+	return messages
+}
+
+// fileEnums 返回文件中定义的枚举，按全名排序
+func (g *goGenerator) fileEnums() []*enumType {
+	var enums []*enumType
+	for _, enum := range g.schema.enums {
+		if enum.file == g.file.GetName() {
+			enums = append(enums, enum)
+		}
+	}
+	sort.Slice(enums, func(i, j int) bool { return enums[i].fullName < enums[j].fullName })
+	return enums
+}
+
+// generateEnum 生成字符串枚举类型，JSON 中以枚举值名称表示，与 protobuf JSON 映射一致
+func (g *goGenerator) generateEnum(enum *enumType) {
+	g.comment("", enum.goName, enum.comment, enum.goName+" 枚举，JSON 中以枚举值名称表示")
+	g.p("type %s string", enum.goName)
+	g.p("")
+	g.p("const (")
+	for _, value := range enum.desc.GetValue() {
+		g.p("\t%s_%s %s = %q", enum.goName, value.GetName(), enum.goName, value.GetName())
+	}
+	g.p(")")
+	g.p("")
+}
+
+// generateMessage 生成消息结构体，字段的 JSON 名称与 protobuf JSON 映射一致，零值字段省略
+func (g *goGenerator) generateMessage(msg *messageType) error {
+	g.comment("", msg.goName, msg.comment, msg.goName+" 消息")
+	g.p("type %s struct {", msg.goName)
+	for _, field := range msg.desc.GetField() {
+		typ, err := g.fieldType(field)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", strings.TrimPrefix(msg.fullName, "."), field.GetName(), err)
+		}
+		g.p("\t%s %s `json:\"%s,omitempty\"`", camelCase(field.GetName()), typ, jsonName(field))
+	}
+	g.p("}")
+	g.p("")
+	return nil
+}
+
+// generateService 生成服务接口、注册函数和客户端
+func (g *goGenerator) generateService(svc *service) error {
+	g.imports["context"] = true
+	g.imports[importClient] = true
+	g.imports[importFramework] = true
+
+	server := svc.name + "Server"
+	g.comment("", server, svc.comment, server+" "+svc.name+" 服务接口")
+	g.p("type %s interface {", server)
+	for _, m := range svc.methods {
+		signature, err := g.signature(m)
+		if err != nil {
+			return err
+		}
+		g.comment("\t", m.name, m.comment, "")
+		g.p("\t%s%s", m.name, signature)
+	}
+	g.p("}")
+	g.p("")
+
+	g.p("// Register%s 将 %s 的实现注册到 server，只暴露接口中的方法", server, server)
+	g.p("func Register%s(server *framework.Server, impl %s) error {", server, server)
+	g.p("\treturn server.Register(%sServiceName, struct{ %s }{impl})", svc.name, server)
+	g.p("}")
+	g.p("")
+
+	clientType := svc.name + "Client"
+	g.p("// %s 通过 client.FrameworkClient 调用 %s 服务", clientType, svc.name)
+	g.p("type %s struct {", clientType)
+	g.p("\tclient  client.FrameworkClient")
+	g.p("\tservice string")
+	g.p("}")
+	g.p("")
+	g.p("// New%s 创建 %s 服务的客户端，service 为服务实例注册到注册中心的名称（framework.name）", clientType, svc.name)
+	g.p("func New%s(c client.FrameworkClient, service string) *%s {", clientType, clientType)
+	g.p("\treturn &%s{client: c, service: service}", clientType)
+	g.p("}")
+	g.p("")
+
+	for _, m := range svc.methods {
+		signature, err := g.signature(m)
+		if err != nil {
+			return err
+		}
+		g.comment("", m.name, m.comment, m.name+" 调用 "+m.rpcName)
+		g.p("func (c *%s) %s%s {", clientType, m.name, signature)
+		request := "req"
+		if m.input == typeEmpty {
+			request = "nil"
+		}
+		constant := svc.name + m.name + "Method"
+		if m.output == typeEmpty {
+			g.p("\treturn c.client.Call(ctx, c.service, %s, %s, nil)", constant, request)
+		} else {
+			output, err := g.messageType(m.output)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(output, "*") {
+				g.p("\tresp := &%s{}", strings.TrimPrefix(output, "*"))
+				g.p("\tif err := c.client.Call(ctx, c.service, %s, %s, resp); err != nil {", constant, request)
+			} else {
+				g.p("\tvar resp %s", output)
+				g.p("\tif err := c.client.Call(ctx, c.service, %s, %s, &resp); err != nil {", constant, request)
+			}
+			g.p("\t\treturn nil, err")
+			g.p("\t}")
+			g.p("\treturn resp, nil")
+		}
+		g.p("}")
+		g.p("")
+	}
+	return nil
+}
+
+// signature 返回方法的参数和返回值，google.protobuf.Empty 请求省略参数、响应只返回 error，
+// 与 framework.Server.Register 支持的方法签名一致
+func (g *goGenerator) signature(m *method) (string, error) {
+	params := "(ctx context.Context)"
+	if m.input != typeEmpty {
+		input, err := g.messageType(m.input)
+		if err != nil {
+			return "", err
+		}
+		params = fmt.Sprintf("(ctx context.Context, req %s)", input)
+	}
+	if m.output == typeEmpty {
+		return params + " error", nil
+	}
+	output, err := g.messageType(m.output)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s (%s, error)", params, output), nil
+}
+
+// fieldType 返回字段的 Go 类型
+func (g *goGenerator) fieldType(field *descriptorpb.FieldDescriptorProto) (string, error) {
+	if key, value := g.schema.mapEntry(field); key != nil {
+		keyType, err := g.elementType(key)
+		if err != nil {
+			return "", err
+		}
+		valueType, err := g.elementType(value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map[%s]%s", keyType, valueType), nil
+	}
+
+	typ, err := g.elementType(field)
+	if err != nil {
+		return "", err
+	}
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return "[]" + typ, nil
+	}
+	// proto3 optional 标量字段以指针区分未设置和零值
+	if field.GetProto3Optional() && !strings.HasPrefix(typ, "*") && field.GetType() != descriptorpb.FieldDescriptorProto_TYPE_BYTES {
+		return "*" + typ, nil
+	}
+	return typ, nil
+}
+
+// elementType 返回单个值的 Go 类型
+func (g *goGenerator) elementType(field *descriptorpb.FieldDescriptorProto) (string, error) {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "float64", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return "float32", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_SINT32, descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return "int32", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_SINT64, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return "int64", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		return "uint32", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		return "uint64", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return "bool", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return "string", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return "[]byte", nil
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		enum, ok := g.schema.enums[field.GetTypeName()]
+		if !ok {
+			return "", fmt.Errorf("unknown enum type %s", field.GetTypeName())
+		}
+		return enum.goName, nil
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		return g.messageType(field.GetTypeName())
+	default:
+		return "", fmt.Errorf("unsupported field type %s", field.GetType())
+	}
+}
+
+// messageType 返回消息的 Go 类型：Timestamp 为 *time.Time，其他内置类型为 json.RawMessage，自定义消息为结构体指针
+func (g *goGenerator) messageType(name string) (string, error) {
+	if strings.HasPrefix(name, wellKnownPrefix) {
+		if name == typeTimestamp {
+			g.imports["time"] = true
+			return "*time.Time", nil
+		}
+		g.imports["encoding/json"] = true
+		return "json.RawMessage", nil
+	}
+	msg, ok := g.schema.messages[name]
+	if !ok {
+		return "", fmt.Errorf("unknown message type %s", name)
+	}
+	return "*" + msg.goName, nil
+}
+
+// comment 输出文档注释，proto 注释不以名称开头时补上名称；没有 proto 注释时使用 fallback，fallback 为空时不输出
+func (g *goGenerator) comment(indent, name, comment, fallback string) {
+	if comment == "" {
+		if fallback != "" {
+			g.p("%s// %s", indent, fallback)
+		}
+		return
+	}
+	for i, line := range commentLines(comment) {
+		if i == 0 && !strings.HasPrefix(line, name) {
+			line = name + " " + line
+		}
+		g.p("%s// %s", indent, strings.TrimRight(line, " "))
+	}
+}
+
+// p 输出一行代码
+func (g *goGenerator) p(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// goPackageName 由 go_package 选项或 proto 包名推导 Go 包名
+func goPackageName(file *descriptorpb.FileDescriptorProto) string {
+	name := file.GetOptions().GetGoPackage()
+	if i := strings.LastIndex(name, ";"); i >= 0 {
+		name = name[i+1:]
+	} else if name != "" {
+		name = path.Base(name)
+	}
+	if name == "" || name == "." {
+		name = file.GetPackage()
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+	}
+	if name == "" {
+		name = baseName(file)
+	}
+
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r)):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// isStdlib 判断导入路径是否为标准库
+func isStdlib(importPath string) bool {
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
@@ -0,0 +1,116 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// generateJava 为文件中的每个服务生成 <服务名>Methods.java 方法映射类，路径按包名分目录
+func generateJava(s *schema, file *descriptorpb.FileDescriptorProto, pkg string) ([]File, error) {
+	services, err := s.services(file)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == "" {
+		pkg = file.GetOptions().GetJavaPackage()
+	}
+	if pkg == "" {
+		pkg = file.GetPackage()
+	}
+
+	var files []File
+	for _, svc := range services {
+		class := svc.name + "Methods"
+		var b bytes.Buffer
+		p := func(format string, args ...interface{}) {
+			fmt.Fprintf(&b, format, args...)
+			b.WriteByte('\n')
+		}
+
+		p("// Code generated by framework gen from %s. DO NOT EDIT.", file.GetName())
+		p("")
+		if pkg != "" {
+			p("package %s;", pkg)
+			p("")
+		}
+		p("import java.util.List;")
+		p("import java.util.Map;")
+		p("")
+		p("/**")
+		for _, line := range commentLines(firstNonEmpty(svc.comment, svc.name+" 服务")) {
+			p(" * %s", line)
+		}
+		p(" *")
+		p(" * <p>JSON-RPC 方法名及请求、响应的字段结构，与 Go、PHP 生成的代码一致</p>")
+		p(" */")
+		p("public final class %s {", class)
+		p("")
+		p("    /** 方法的请求、响应消息类型 */")
+		p("    public record MethodType(String request, String response) {}")
+		p("")
+		p("    /** 服务名 */")
+		p("    public static final String SERVICE = %q;", svc.rpcName)
+		for _, m := range svc.methods {
+			p("")
+			p("    /** %s */", m.name)
+			p("    public static final String %s = %q;", constantName(m.name), m.rpcName)
+		}
+
+		var methods []string
+		for _, m := range svc.methods {
+			methods = append(methods, fmt.Sprintf("Map.entry(%s, new MethodType(%q, %q))",
+				constantName(m.name), strings.TrimPrefix(m.input, "."), strings.TrimPrefix(m.output, ".")))
+		}
+		p("")
+		p("    /** 方法名到请求、响应消息类型 */")
+		p("    public static final Map<String, MethodType> METHODS = %s;", javaMap(methods, "        "))
+
+		messageNames, enumNames := s.reachable([]*service{svc})
+		var messages []string
+		for _, name := range messageNames {
+			var fields []string
+			for _, field := range s.messages[name].desc.GetField() {
+				fields = append(fields, fmt.Sprintf("Map.entry(%q, %q)", jsonName(field), s.shapeType(field)))
+			}
+			messages = append(messages, fmt.Sprintf("Map.entry(%q, %s)", strings.TrimPrefix(name, "."), javaMap(fields, "            ")))
+		}
+		p("")
+		p("    /** 消息类型到字段 JSON 名称和类型 */")
+		p("    public static final Map<String, Map<String, String>> MESSAGES = %s;", javaMap(messages, "        "))
+
+		var enums []string
+		for _, name := range enumNames {
+			var values []string
+			for _, value := range s.enums[name].desc.GetValue() {
+				values = append(values, fmt.Sprintf("%q", value.GetName()))
+			}
+			enums = append(enums, fmt.Sprintf("Map.entry(%q, List.of(%s))", strings.TrimPrefix(name, "."), strings.Join(values, ", ")))
+		}
+		p("")
+		p("    /** 枚举类型到枚举值名称 */")
+		p("    public static final Map<String, List<String>> ENUMS = %s;", javaMap(enums, "        "))
+		p("")
+		p("    private %s() {", class)
+		p("    }")
+		p("}")
+
+		name := class + ".java"
+		if pkg != "" {
+			name = path.Join(strings.ReplaceAll(pkg, ".", "/"), name)
+		}
+		files = append(files, File{Name: name, Content: b.Bytes()})
+	}
+	return files, nil
+}
+
+// javaMap 生成不可变 Map 表达式，entries 为 Map.entry(...) 表达式；Map.of 最多支持 10 对键值，因此使用 Map.ofEntries
+func javaMap(entries []string, indent string) string {
+	if len(entries) == 0 {
+		return "Map.of()"
+	}
+	return "Map.ofEntries(\n" + indent + strings.Join(entries, ",\n"+indent) + ")"
+}
@@ -0,0 +1,117 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// generatePHP 为文件中的每个服务生成 <服务名>Methods.php 方法映射类
+func generatePHP(s *schema, file *descriptorpb.FileDescriptorProto, namespace string) ([]File, error) {
+	services, err := s.services(file)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = phpNamespace(file)
+	}
+
+	var files []File
+	for _, svc := range services {
+		class := svc.name + "Methods"
+		var b bytes.Buffer
+		p := func(format string, args ...interface{}) {
+			fmt.Fprintf(&b, format, args...)
+			b.WriteByte('\n')
+		}
+
+		p("<?php")
+		p("")
+		p("// Code generated by framework gen from %s. DO NOT EDIT.", file.GetName())
+		p("")
+		p("declare(strict_types=1);")
+		p("")
+		if namespace != "" {
+			p("namespace %s;", namespace)
+			p("")
+		}
+		p("/**")
+		for _, line := range commentLines(firstNonEmpty(svc.comment, svc.name+" 服务")) {
+			p(" * %s", line)
+		}
+		p(" *")
+		p(" * JSON-RPC 方法名及请求、响应的字段结构，与 Go、Java 生成的代码一致")
+		p(" */")
+		p("final class %s", class)
+		p("{")
+		p("    /** 服务名 */")
+		p("    const SERVICE = '%s';", svc.rpcName)
+		for _, m := range svc.methods {
+			p("")
+			p("    /** %s */", m.name)
+			p("    const %s = '%s';", constantName(m.name), m.rpcName)
+		}
+		p("")
+		p("    /** 方法名 => 请求、响应消息类型 */")
+		p("    const METHODS = [")
+		for _, m := range svc.methods {
+			p("        self::%s => ['request' => '%s', 'response' => '%s'],", constantName(m.name), strings.TrimPrefix(m.input, "."), strings.TrimPrefix(m.output, "."))
+		}
+		p("    ];")
+
+		messages, enums := s.reachable([]*service{svc})
+		p("")
+		p("    /** 消息类型 => [字段 JSON 名称 => 类型] */")
+		p("    const MESSAGES = [")
+		for _, name := range messages {
+			p("        '%s' => [", strings.TrimPrefix(name, "."))
+			for _, field := range s.messages[name].desc.GetField() {
+				p("            '%s' => '%s',", jsonName(field), s.shapeType(field))
+			}
+			p("        ],")
+		}
+		p("    ];")
+		p("")
+		p("    /** 枚举类型 => 枚举值名称 */")
+		p("    const ENUMS = [")
+		for _, name := range enums {
+			var values []string
+			for _, value := range s.enums[name].desc.GetValue() {
+				values = append(values, "'"+value.GetName()+"'")
+			}
+			p("        '%s' => [%s],", strings.TrimPrefix(name, "."), strings.Join(values, ", "))
+		}
+		p("    ];")
+		p("}")
+
+		files = append(files, File{Name: class + ".php", Content: b.Bytes()})
+	}
+	return files, nil
+}
+
+// phpNamespace 由 php_namespace 选项或 proto 包名推导命名空间，如 hello.v1 -> Hello\V1
+func phpNamespace(file *descriptorpb.FileDescriptorProto) string {
+	if ns := file.GetOptions().GetPhpNamespace(); ns != "" {
+		return ns
+	}
+	if file.GetPackage() == "" {
+		return ""
+	}
+	var parts []string
+	for _, part := range strings.Split(file.GetPackage(), ".") {
+		parts = append(parts, camelCase(part))
+	}
+	return strings.Join(parts, `\`)
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// AcceptQueueConfig 协议处理器接收队列配置，每个协议处理器各有一个队列
+//
+// 处理中的请求达到 maxConcurrent 时新请求排队，排队期间截止时间已过的请求不再处理，返回 Timeout：
+//
+//	framework:
+//	  acceptQueue:
+//	    enabled: true
+//	    maxConcurrent: 200
+//	    queueSize: 500
+//	    maxWait: 2s
+type AcceptQueueConfig struct {
+	Enabled       bool          `json:"enabled" config:"enabled"`
+	MaxConcurrent int           `json:"maxConcurrent,omitempty" config:"maxConcurrent"` // 每个协议处理器同时处理的请求数上限，为 0 时使用 resilience.DefaultAcceptQueueConcurrency
+	QueueSize     int           `json:"queueSize,omitempty" config:"queueSize"`         // 等待处理的请求数上限，为 0 时使用 resilience.DefaultAcceptQueueSize
+	MaxWait       time.Duration `json:"maxWait,omitempty" config:"maxWait"`             // 没有截止时间的请求最长等待时间，为 0 时不限制
+}
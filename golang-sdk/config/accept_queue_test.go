@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_AcceptQueue(t *testing.T) {
+	path := configDirWith(t, `framework:
+  acceptQueue:
+    enabled: true
+    maxConcurrent: 200
+    queueSize: 500
+    maxWait: 2s
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	queue := fc.AcceptQueue
+	if !queue.Enabled || queue.MaxConcurrent != 200 || queue.QueueSize != 500 || queue.MaxWait != 2*time.Second {
+		t.Errorf("Unexpected accept queue config: %+v", queue)
+	}
+}
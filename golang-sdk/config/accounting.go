@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// 用量的导出目标
+const (
+	AccountingSinkCSV   = "csv"
+	AccountingSinkHTTP  = "http"
+	AccountingSinkKafka = "kafka"
+)
+
+// AccountingConfig 用量计量配置，按调用方（API 密钥或 JWT 认证的用户 ID）、租户和方法统计请求数、字节数和处理时间，
+// 定期导出用于多团队共用网关时的费用分摊：
+//
+//	framework:
+//	  accounting:
+//	    enabled: true
+//	    interval: 1m
+//	    sink: http
+//	    url: https://billing.internal/usage
+//	    headers:
+//	      Authorization: Bearer ${BILLING_TOKEN}
+type AccountingConfig struct {
+	Enabled  bool              `json:"enabled" config:"enabled"`
+	Interval time.Duration     `json:"interval,omitempty" config:"interval"` // 汇总和导出周期，为 0 时使用 accounting.DefaultInterval
+	Sink     string            `json:"sink,omitempty" config:"sink"`         // csv、http 或 kafka
+	File     string            `json:"file,omitempty" config:"file"`         // sink 为 csv 时追加写入的文件
+	URL      string            `json:"url,omitempty" config:"url"`           // sink 为 http 时 POST 用量的地址
+	Headers  map[string]string `json:"headers,omitempty" config:"headers"`   // sink 为 http 时附加的请求头
+	Topic    string            `json:"topic,omitempty" config:"topic"`       // sink 为 kafka 时发布的主题，须启用 Kafka 协议
+}
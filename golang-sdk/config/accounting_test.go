@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_Accounting(t *testing.T) {
+	path := configDirWith(t, `framework:
+  accounting:
+    enabled: true
+    interval: 5m
+    sink: http
+    url: https://billing.internal/usage
+    headers:
+      Authorization: Bearer token
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	accounting := fc.Accounting
+	if !accounting.Enabled || accounting.Interval != 5*time.Minute || accounting.Sink != AccountingSinkHTTP ||
+		accounting.URL != "https://billing.internal/usage" || accounting.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Unexpected accounting config: %+v", accounting)
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+// CaptureConfig 请求录制配置，录制的请求可用 frameworkctl replay 回放
+//
+//	framework:
+//	  capture:
+//	    enabled: true
+//	    sampleRate: 0.05
+//	    services: [order-service]
+//	    bufferSize: 1000
+//	    file: /var/log/framework/capture.jsonl
+//	    redactFields: [idCard, phone]
+type CaptureConfig struct {
+	Enabled        bool     `json:"enabled" config:"enabled"`
+	SampleRate     float64  `json:"sampleRate,omitempty" config:"sampleRate"`         // 采样比例，为 0 时使用 capture.DefaultSampleRate
+	Services       []string `json:"services,omitempty" config:"services"`             // 为空时录制所有服务
+	BufferSize     int      `json:"bufferSize,omitempty" config:"bufferSize"`         // 管理接口可查询的最近记录数
+	File           string   `json:"file,omitempty" config:"file"`                     // 不为空时同时以 JSON Lines 追加写入该文件
+	RedactFields   []string `json:"redactFields,omitempty" config:"redactFields"`     // 额外脱敏的负载字段名
+	MaxPayloadSize ByteSize `json:"maxPayloadSize,omitempty" config:"maxPayloadSize"` // 超过时不录制负载
+}
+
+// PayloadLogConfig 负载日志配置，按方法限流记录脱敏后的请求和响应负载，用于排查跨语言序列化不一致
+//
+// 未启用时也可以经管理接口 payloadLog 操作在运行时开启：
+//
+//	framework:
+//	  payloadLog:
+//	    enabled: true
+//	    perMinute: 5
+//	    maxSize: 2KB
+//	    services: [order-service]
+type PayloadLogConfig struct {
+	Enabled      bool     `json:"enabled" config:"enabled"`
+	PerMinute    int      `json:"perMinute,omitempty" config:"perMinute"`       // 每个方法每分钟最多记录的请求数，为 0 时使用 capture.DefaultPayloadLogsPerMinute
+	MaxSize      ByteSize `json:"maxSize,omitempty" config:"maxSize"`           // 每个负载记录的最大字节数，超过时截断
+	Services     []string `json:"services,omitempty" config:"services"`         // 为空时记录所有服务
+	RedactFields []string `json:"redactFields,omitempty" config:"redactFields"` // 额外脱敏的负载字段名
+}
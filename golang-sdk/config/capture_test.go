@@ -0,0 +1,80 @@
+package config
+
+import "testing"
+
+func TestLoadFrameworkConfig_Capture(t *testing.T) {
+	path := configDirWith(t, `framework:
+  capture:
+    enabled: true
+    sampleRate: 0.05
+    services: [order-service]
+    bufferSize: 500
+    file: /tmp/capture.jsonl
+    redactFields: [idCard]
+    maxPayloadSize: 16KB
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	capture := fc.Capture
+	if !capture.Enabled || capture.SampleRate != 0.05 || capture.BufferSize != 500 || capture.File != "/tmp/capture.jsonl" {
+		t.Errorf("Unexpected capture config: %+v", capture)
+	}
+	if len(capture.Services) != 1 || capture.Services[0] != "order-service" ||
+		len(capture.RedactFields) != 1 || capture.RedactFields[0] != "idCard" {
+		t.Errorf("Unexpected capture lists: %+v", capture)
+	}
+	if capture.MaxPayloadSize != 16*1024 {
+		t.Errorf("Expected 16KB max payload size, got %v", capture.MaxPayloadSize)
+	}
+}
+
+func TestLoadFrameworkConfig_CaptureDisabledByDefault(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	if fc.Capture.Enabled {
+		t.Error("Expected capture to be disabled by default")
+	}
+}
+
+func TestLoadFrameworkConfig_PayloadLog(t *testing.T) {
+	path := configDirWith(t, `framework:
+  payloadLog:
+    enabled: true
+    perMinute: 3
+    maxSize: 4KB
+    services: [order-service]
+    redactFields: [idCard]
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	payloadLog := fc.PayloadLog
+	if !payloadLog.Enabled || payloadLog.PerMinute != 3 || payloadLog.MaxSize != 4*1024 {
+		t.Errorf("Unexpected payload log config: %+v", payloadLog)
+	}
+	if len(payloadLog.Services) != 1 || payloadLog.Services[0] != "order-service" ||
+		len(payloadLog.RedactFields) != 1 || payloadLog.RedactFields[0] != "idCard" {
+		t.Errorf("Unexpected payload log lists: %+v", payloadLog)
+	}
+}
@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// CatalogConfig 服务目录页面配置，启用后在指标服务器的 /catalog/ 下列出注册中心中的服务，并可在页面上调用服务的方法：
+//
+//	framework:
+//	  catalog:
+//	    enabled: true
+//	    callTimeout: 10s
+type CatalogConfig struct {
+	Enabled     bool          `json:"enabled" config:"enabled"`
+	CallTimeout time.Duration `json:"callTimeout,omitempty" config:"callTimeout"` // 页面调用的超时，为 0 时使用 catalog.DefaultCallTimeout
+}
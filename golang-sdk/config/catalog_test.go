@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_Catalog(t *testing.T) {
+	path := configDirWith(t, `framework:
+  catalog:
+    enabled: true
+    callTimeout: 10s
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	if !fc.Catalog.Enabled || fc.Catalog.CallTimeout != 10*time.Second {
+		t.Errorf("Unexpected catalog config: %+v", fc.Catalog)
+	}
+}
@@ -13,29 +13,67 @@ import (
 
 // ConfigManager 配置管理器
 type ConfigManager struct {
-	adapter *gcfg.AdapterFile
-	config  *gcfg.Config
+	adapter   gcfg.Adapter
+	config    *gcfg.Config
+	path      string
+	profiles  []string
+	overrides map[string]string
+	envPrefix string
+	sources   map[string]string // 配置键 -> 提供该值的配置文件
+	encrypted map[string]bool   // 加载时解密的配置键
 }
 
-// NewConfigManager 创建配置管理器
+// NewConfigManager 创建配置管理器，按 FRAMEWORK_PROFILE 合并环境配置文件
 func NewConfigManager(configPath string) (*ConfigManager, error) {
+	return NewConfigManagerWithOptions(configPath, nil)
+}
+
+// NewConfigManagerWithOptions 使用加载选项创建配置管理器，配置优先级见 profile.go
+func NewConfigManagerWithOptions(configPath string, opts *Options) (*ConfigManager, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
 	// 检查配置文件是否存在
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config file not found: %s", configPath)
 	}
 
-	// 创建配置适配器
-	adapter, err := gcfg.NewAdapterFile(configPath)
+	profiles := opts.Profiles
+	if len(profiles) == 0 {
+		profiles = ProfilesFromEnv()
+	}
+
+	envPrefix, err := normalizeEnvPrefix(opts.EnvPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypter := opts.Decrypter
+	if decrypter == nil {
+		if decrypter, err = DecrypterFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 创建配置适配器，合并环境配置文件并解密加密值
+	layered, err := loadLayered(configPath, profiles, decrypter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create config adapter: %w", err)
+		return nil, err
 	}
 
 	// 创建配置对象
-	config := gcfg.NewWithAdapter(adapter)
+	config := gcfg.NewWithAdapter(layered.adapter)
 
 	cm := &ConfigManager{
-		adapter: adapter,
-		config:  config,
+		adapter:   layered.adapter,
+		config:    config,
+		path:      configPath,
+		profiles:  profiles,
+		overrides: opts.Overrides,
+		envPrefix: envPrefix,
+		sources:   layered.sources,
+		encrypted: layered.encrypted,
 	}
 
 	// 应用环境变量覆盖
@@ -44,6 +82,9 @@ func NewConfigManager(configPath string) (*ConfigManager, error) {
 	}
 
 	// 验证配置
+	if opts.SkipValidation {
+		return cm, nil
+	}
 	if err := cm.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -53,22 +94,17 @@ func NewConfigManager(configPath string) (*ConfigManager, error) {
 
 // GetString 获取字符串配置
 func (cm *ConfigManager) GetString(pattern string, def ...interface{}) string {
-	// 先检查环境变量
-	envValue := cm.getEnvValue(pattern)
-	if envValue != "" {
-		return envValue
-	}
-	// 如果环境变量存在但为空字符串，也返回空字符串（覆盖配置文件）
-	if _, exists := os.LookupEnv(strings.ToUpper(strings.ReplaceAll(pattern, ".", "_"))); exists {
-		return envValue
+	// 先检查命令行参数和环境变量，存在但为空字符串时也覆盖配置文件
+	if value, exists := cm.lookupOverride(pattern); exists {
+		return value
 	}
 	return cm.config.MustGet(context.Background(), pattern, def...).String()
 }
 
 // GetInt 获取整数配置
 func (cm *ConfigManager) GetInt(pattern string, def ...interface{}) int {
-	// 先检查环境变量
-	if envValue := cm.getEnvValue(pattern); envValue != "" {
+	// 先检查命令行参数和环境变量
+	if envValue := cm.getOverride(pattern); envValue != "" {
 		if val, err := strconv.Atoi(envValue); err == nil {
 			return val
 		}
@@ -78,8 +114,8 @@ func (cm *ConfigManager) GetInt(pattern string, def ...interface{}) int {
 
 // GetBool 获取布尔配置
 func (cm *ConfigManager) GetBool(pattern string, def ...interface{}) bool {
-	// 先检查环境变量
-	if envValue := cm.getEnvValue(pattern); envValue != "" {
+	// 先检查命令行参数和环境变量
+	if envValue := cm.getOverride(pattern); envValue != "" {
 		if val, err := strconv.ParseBool(envValue); err == nil {
 			return val
 		}
@@ -89,8 +125,8 @@ func (cm *ConfigManager) GetBool(pattern string, def ...interface{}) bool {
 
 // GetDuration 获取时间间隔配置
 func (cm *ConfigManager) GetDuration(pattern string, def ...interface{}) time.Duration {
-	// 先检查环境变量
-	if envValue := cm.getEnvValue(pattern); envValue != "" {
+	// 先检查命令行参数和环境变量
+	if envValue := cm.getOverride(pattern); envValue != "" {
 		if val, err := time.ParseDuration(envValue); err == nil {
 			return val
 		}
@@ -100,13 +136,18 @@ func (cm *ConfigManager) GetDuration(pattern string, def ...interface{}) time.Du
 
 // GetStringSlice 获取字符串切片配置
 func (cm *ConfigManager) GetStringSlice(pattern string, def ...interface{}) []string {
-	// 先检查环境变量
-	if envValue := cm.getEnvValue(pattern); envValue != "" {
+	// 先检查命令行参数和环境变量
+	if envValue := cm.getOverride(pattern); envValue != "" {
 		return strings.Split(envValue, ",")
 	}
 	return cm.config.MustGet(context.Background(), pattern, def...).Strings()
 }
 
+// Profiles 获取已加载的环境列表
+func (cm *ConfigManager) Profiles() []string {
+	return cm.profiles
+}
+
 // GetConfig 获取原始配置对象
 func (cm *ConfigManager) GetConfig() *gcfg.Config {
 	return cm.config
@@ -114,85 +155,41 @@ func (cm *ConfigManager) GetConfig() *gcfg.Config {
 
 // applyEnvOverrides 应用环境变量覆盖
 func (cm *ConfigManager) applyEnvOverrides() error {
-	// 环境变量命名规则: FRAMEWORK_SECTION_KEY
+	// 环境变量命名规则: <EnvPrefix>_SECTION_KEY，见 env.go
 	// 例如: FRAMEWORK_NETWORK_HOST, FRAMEWORK_REGISTRY_TYPE
 	
-	// 这里不需要手动设置，getEnvValue 会在获取时自动检查环境变量
+	// 这里不需要手动设置，lookupOverride 会在获取时自动检查环境变量
 	return nil
 }
 
-// getEnvValue 获取环境变量值
-func (cm *ConfigManager) getEnvValue(pattern string) string {
-	// 将配置路径转换为环境变量名
-	// 例如: framework.network.host -> FRAMEWORK_NETWORK_HOST
-	envKey := strings.ToUpper(strings.ReplaceAll(pattern, ".", "_"))
-	return os.Getenv(envKey)
+// lookupOverride 查找覆盖配置文件的值，命令行参数优先于环境变量
+func (cm *ConfigManager) lookupOverride(pattern string) (string, bool) {
+	value, _, _, ok := cm.resolveOverride(pattern)
+	return value, ok
 }
 
-// Validate 验证配置
-func (cm *ConfigManager) Validate() error {
-	// 验证必需的配置项
-	if cm.GetString("framework.name") == "" {
-		return fmt.Errorf("framework.name is required")
-	}
-
-	if cm.GetString("framework.version") == "" {
-		return fmt.Errorf("framework.version is required")
-	}
-
-	if cm.GetString("framework.language") == "" {
-		return fmt.Errorf("framework.language is required")
+// resolveOverride 查找覆盖值及其来源，origin 为命令行参数名或环境变量名
+func (cm *ConfigManager) resolveOverride(pattern string) (value string, source ConfigSource, origin string, ok bool) {
+	if value, ok := cm.overrides[pattern]; ok {
+		return value, SourceFlag, pattern, true
 	}
 
-	// 验证网络配置
-	if cm.GetString("framework.network.host") == "" {
-		return fmt.Errorf("framework.network.host is required")
-	}
-
-	port := cm.GetInt("framework.network.port")
-	if port <= 0 || port > 65535 {
-		return fmt.Errorf("framework.network.port must be between 1 and 65535, got %d", port)
-	}
-
-	maxConnections := cm.GetInt("framework.network.maxConnections")
-	if maxConnections <= 0 {
-		return fmt.Errorf("framework.network.maxConnections must be positive, got %d", maxConnections)
-	}
-
-	// 验证注册中心配置
-	registryType := cm.GetString("framework.registry.type")
-	if registryType == "" {
-		return fmt.Errorf("framework.registry.type is required")
-	}
-
-	endpoints := cm.GetStringSlice("framework.registry.endpoints")
-	if len(endpoints) == 0 {
-		return fmt.Errorf("framework.registry.endpoints is required")
-	}
-
-	// 验证连接池配置
-	maxPoolConnections := cm.GetInt("framework.connectionPool.maxConnections")
-	minPoolConnections := cm.GetInt("framework.connectionPool.minConnections")
-	
-	if maxPoolConnections <= 0 {
-		return fmt.Errorf("framework.connectionPool.maxConnections must be positive, got %d", maxPoolConnections)
-	}
-
-	if minPoolConnections < 0 {
-		return fmt.Errorf("framework.connectionPool.minConnections must be non-negative, got %d", minPoolConnections)
-	}
-
-	if minPoolConnections > maxPoolConnections {
-		return fmt.Errorf("framework.connectionPool.minConnections (%d) cannot be greater than maxConnections (%d)", 
-			minPoolConnections, maxPoolConnections)
+	// 将配置路径转换为环境变量名
+	// 例如: framework.network.host -> FRAMEWORK_NETWORK_HOST
+	envKey := EnvName(cm.envPrefix, pattern)
+	if value, ok := os.LookupEnv(envKey); ok {
+		return value, SourceEnv, envKey, true
 	}
+	return "", "", "", false
+}
 
-	// 验证日志级别
-	logLevel := cm.GetString("framework.observability.logging.level")
-	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
-	if !validLevels[logLevel] {
-		return fmt.Errorf("framework.observability.logging.level must be one of [debug, info, warn, error], got %s", logLevel)
-	}
+// getOverride 获取覆盖值，不存在时返回空字符串
+func (cm *ConfigManager) getOverride(pattern string) string {
+	value, _ := cm.lookupOverride(pattern)
+	return value
+}
 
-	return nil
+// Validate 按 FrameworkSchema 验证配置，一次返回全部违规项
+func (cm *ConfigManager) Validate() error {
+	return FrameworkSchema().Validate(cm)
 }
@@ -0,0 +1,33 @@
+package config
+
+// 死信存储
+const (
+	DeadLetterStoreMemory = "memory"
+	DeadLetterStoreFile   = "file"
+	DeadLetterStoreRedis  = "redis"
+)
+
+// DeadLetterConfig 死信队列配置，MQ 协议中不需要响应的请求处理失败时写入死信存储，可经管理接口列出和重放：
+//
+//	framework:
+//	  deadLetter:
+//	    enabled: true
+//	    store: redis
+//	    redis:
+//	      address: 127.0.0.1:6379
+//	      key: order-service:dead-letters
+type DeadLetterConfig struct {
+	Enabled bool                  `json:"enabled" config:"enabled"`
+	Store   string                `json:"store,omitempty" config:"store"` // memory（默认）、file 或 redis
+	Dir     string                `json:"dir,omitempty" config:"dir"`     // store 为 file 时存放死信文件的目录
+	Redis   DeadLetterRedisConfig `json:"redis,omitempty" config:"redis"` // store 为 redis 时的连接配置
+}
+
+// DeadLetterRedisConfig Redis 死信存储配置
+type DeadLetterRedisConfig struct {
+	Address  string `json:"address,omitempty" config:"address"` // 默认为 127.0.0.1:6379
+	Username string `json:"username,omitempty" config:"username"`
+	Password string `json:"password,omitempty" config:"password"`
+	DB       int    `json:"db,omitempty" config:"db"`
+	Key      string `json:"key,omitempty" config:"key"` // 存放死信的哈希键，默认为 messaging.DefaultDeadLetterKey
+}
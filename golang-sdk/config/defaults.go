@@ -0,0 +1,413 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultFrameworkConfig 返回框架默认配置，framework init 生成的配置文件与之一致
+func DefaultFrameworkConfig() *FrameworkConfig {
+	samplingRate := 1.0
+	return &FrameworkConfig{
+		Name:     "golang-service",
+		Version:  "1.0.0",
+		Language: "golang",
+		Network: NetworkConfig{
+			Host:           "0.0.0.0",
+			Port:           8081,
+			MaxConnections: 1000,
+			ReadTimeout:    30 * time.Second,
+			WriteTimeout:   30 * time.Second,
+			KeepAlive:      true,
+			ReusePort:      false,
+		},
+		Registry: RegistryConfig{
+			Type:                "etcd",
+			Endpoints:           []string{"http://localhost:2379"},
+			Namespace:           "/framework/services",
+			TTL:                 30,
+			HeartbeatInterval:   10,
+			HealthCheckInterval: 10,
+			UnhealthyThreshold:  3,
+		},
+		Protocols: ProtocolsConfig{
+			External: []ExternalProtocolConfig{
+				{Type: "REST", Enabled: true, Port: 8081, Path: "/api"},
+				{Type: "WebSocket", Enabled: true, Port: 8081, Path: "/ws"},
+				{Type: "JSON-RPC", Enabled: true, Port: 8081, Path: "/jsonrpc"},
+				{Type: "MQTT", Enabled: false, Port: 1883},
+				{Type: "Kafka", Enabled: false, Options: map[string]interface{}{
+					"brokers": []interface{}{"localhost:9092"},
+					"routes":  map[string]interface{}{},
+				}},
+				{Type: "MQ", Enabled: false},
+			},
+			Internal: []InternalProtocolConfig{
+				{Type: "gRPC", Enabled: true, Port: 9001, Serialization: "PROTOBUF", Compression: true},
+				{Type: "JSON-RPC", Enabled: true, Port: 9002, Serialization: "JSON"},
+				{Type: "Custom", Enabled: false},
+			},
+		},
+		ConnectionPool: ConnectionPoolConfig{
+			MaxConnections:    100,
+			MinConnections:    10,
+			IdleTimeout:       5 * time.Minute,
+			MaxLifetime:       30 * time.Minute,
+			ConnectionTimeout: 5 * time.Second,
+		},
+		Security: SecurityConfig{
+			TLS:            TLSConfig{Enabled: false},
+			Authentication: AuthenticationConfig{Enabled: false, Type: "jwt"},
+			Authorization:  AuthorizationConfig{Enabled: false, Type: "rbac"},
+		},
+		Observability: ObservabilityConfig{
+			Logging: LoggingConfig{Level: "info", Format: "json", Output: "stdout"},
+			Metrics: MetricsConfig{Enabled: true, Port: 9090, Path: "/metrics"},
+			Tracing: TracingConfig{
+				Enabled:      true,
+				Exporter:     "otlp-grpc",
+				Endpoint:     "http://localhost:4317",
+				SamplingRate: &samplingRate,
+				BatchTimeout: 5 * time.Second,
+			},
+		},
+	}
+}
+
+// configTemplate 带注释的配置文件模板
+var configTemplate = template.Must(template.New("config").Funcs(template.FuncMap{
+	"str":      yamlString,
+	"duration": formatDuration,
+	"float":    formatFloat,
+	"flow":     yamlFlow,
+}).Parse(`# 框架配置，由 framework init 生成
+#
+# 加载优先级（从低到高）：本文件 < config.<profile>.yaml（FRAMEWORK_PROFILE）< 环境变量 < 命令行参数
+# 环境变量命名规则：配置路径转大写并以下划线连接，如 FRAMEWORK_NETWORK_PORT
+framework:
+  name: {{str .Name}}
+  version: {{str .Version}}
+  language: {{str .Language}}
+
+  # 服务监听配置
+  network:
+    host: {{str .Network.Host}}
+    port: {{.Network.Port}}
+    maxConnections: {{.Network.MaxConnections}}
+    readTimeout: {{duration .Network.ReadTimeout}}
+    writeTimeout: {{duration .Network.WriteTimeout}}
+    keepAlive: {{.Network.KeepAlive}}
+    reusePort: {{.Network.ReusePort}}  # SO_REUSEPORT，同一主机上的多个进程监听相同端口
+
+  # 服务注册中心
+  registry:
+    type: {{str .Registry.Type}}
+    endpoints:
+{{- range .Registry.Endpoints}}
+      - {{str .}}
+{{- end}}
+    namespace: {{str .Registry.Namespace}}
+    ttl: {{.Registry.TTL}}  # 注册租约（秒）
+    heartbeatInterval: {{.Registry.HeartbeatInterval}}  # 心跳间隔（秒）
+    healthCheckInterval: {{.Registry.HealthCheckInterval}}  # 就绪检查周期（秒），不健康时从注册中心注销，0 为不同步
+    unhealthyThreshold: {{.Registry.UnhealthyThreshold}}  # 连续失败多少次后注销
+    # username: edge-gateway  # etcd 认证
+    # password: ENC[...]
+    # readOnly: true  # 只查询和监听，不注册本实例；应同时为该用户分配只读角色
+    # mirrors:  # 同时注册的其他注册中心，单个注册中心故障时服务发现仍然可用
+    #   - type: etcd
+    #     endpoints: [etcd-dr-1:2379]
+    #     namespace: /framework
+    # quorum: 2  # 注册和注销需要成功的注册中心数（含本注册中心），默认多数
+    # repairInterval: 10  # 后台重试落后注册中心的周期（秒）
+
+  # 协议配置，external 面向客户端，internal 用于服务间通信；
+  # 同一协议可配置多次以监听多个端口，host 为空时使用 network.host
+  protocols:
+    external:
+{{- range .Protocols.External}}
+      - type: {{str .Type}}
+        enabled: {{.Enabled}}
+{{- if .Host}}
+        host: {{str .Host}}
+{{- end}}
+{{- if .Port}}
+        port: {{.Port}}
+{{- end}}
+{{- if .Path}}
+        path: {{str .Path}}
+{{- end}}
+{{- if .Options}}
+        options: {{flow .Options}}
+{{- end}}
+{{- end}}
+
+    internal:
+{{- range .Protocols.Internal}}
+      - type: {{str .Type}}
+        enabled: {{.Enabled}}
+{{- if .Host}}
+        host: {{str .Host}}
+{{- end}}
+{{- if .Port}}
+        port: {{.Port}}
+{{- end}}
+{{- if .Serialization}}
+        serialization: {{str .Serialization}}  # PROTOBUF 或 JSON
+{{- end}}
+{{- if .Compression}}
+        compression: {{.Compression}}
+{{- end}}
+{{- if .IdleTimeout}}
+        idleTimeout: {{.IdleTimeout}}
+{{- end}}
+{{- if .StreamIdleTimeout}}
+        streamIdleTimeout: {{.StreamIdleTimeout}}
+{{- end}}
+{{- end}}
+      # Custom 协议可设置 idleTimeout（关闭空闲连接）和 streamIdleTimeout（结束空闲的流），如 5m、30s
+
+  # 服务间连接池
+  connectionPool:
+    maxConnections: {{.ConnectionPool.MaxConnections}}
+    minConnections: {{.ConnectionPool.MinConnections}}
+    idleTimeout: {{duration .ConnectionPool.IdleTimeout}}
+    maxLifetime: {{duration .ConnectionPool.MaxLifetime}}
+    connectionTimeout: {{duration .ConnectionPool.ConnectionTimeout}}
+
+  # 按目标服务的覆盖配置，未设置的字段沿用全局配置
+  # services:
+  #   payment-service:
+  #     timeout: 2s
+  #     protocol: gRPC  # 首选协议
+  #     retry:
+  #       maxAttempts: 5
+  #       initialDelay: 50ms
+  #       maxDelay: 2s
+  #       multiplier: 2.0
+  #       nonIdempotent: false  # 默认只重试幂等方法（实例注册时声明或 idempotentMethods 中列出）
+  #     idempotentMethods: [payment.getOrder]
+  #     connectionPool:
+  #       maxConnections: 20
+  #     adaptiveTimeout:  # 按各方法最近调用的 P99 延迟设置每次调用的超时
+  #       enabled: true
+  #       percentile: 0.99
+  #       multiplier: 1.5
+  #       floor: 100ms
+  #       ceiling: 5s
+
+  # 按服务方法的请求转换规则，用于兼容旧版本的调用方
+  # transforms:
+  #   - service: user-service
+  #     method: getUser
+  #     path: /api/users/{id:int}  # REST 路径参数写入负载
+  #     rename:
+  #       userName: name
+  #     defaults:
+  #       locale: zh-CN
+  #     stripHeaders: [X-Legacy-Token]
+
+  # 客户端调用的路由规则，在生效窗口内将调用重定向到目标服务，窗口结束后自动恢复
+  # routes:
+  #   - name: orders-maintenance
+  #     service: order-service
+  #     target: order-service-dr
+  #     start: 2024-06-01T02:00:00+08:00  # RFC 3339，start 和 end 可只设置其一
+  #     end: 2024-06-01T04:00:00+08:00
+  #   - name: weekly-failover
+  #     service: payment-service
+  #     methods: [charge, refund]  # 为空时匹配所有方法
+  #     target: payment-service-backup
+  #     cron: "0 2 * * SUN"  # 每次触发后生效 duration
+  #     duration: 2h
+  #     timezone: Asia/Shanghai
+
+  # 按比例录制业务方法收到的请求（已脱敏），可用 frameworkctl replay 回放
+  # capture:
+  #   enabled: true
+  #   sampleRate: 0.05
+  #   file: /var/log/framework/capture.jsonl
+
+  # 按方法限流记录脱敏后的请求和响应负载，排查跨语言序列化不一致；也可经管理接口在运行时开启
+  # payloadLog:
+  #   enabled: true
+  #   perMinute: 5
+  #   maxSize: 2KB
+
+  # 每个协议处理器的接收队列：处理中的请求达到上限时排队，排队期间截止时间已过的请求返回 Timeout 而不处理
+  # acceptQueue:
+  #   enabled: true
+  #   maxConcurrent: 200
+  #   queueSize: 500
+  #   maxWait: 2s
+
+  # 内置 DNS 服务器：以注册中心中的实例应答 SRV/A 查询，如 _grpc._tcp.order-service.framework.local
+  # dns:
+  #   enabled: true
+  #   address: ":8053"
+  #   domain: framework.local
+  #   ttl: 5s
+
+  # 用量计量：按调用方、租户和方法统计请求数、字节数和处理时间，定期导出到 CSV 文件、HTTP 地址或 Kafka 主题
+  # accounting:
+  #   enabled: true
+  #   interval: 1m
+  #   sink: csv
+  #   file: /var/log/framework/usage.csv
+
+  # ID 生成策略：w3c（随机，默认）、uuidv7 或 snowflake，后两者生成的追踪 ID 和请求 ID 按时间排序
+  # idGenerator:
+  #   strategy: snowflake
+  #   node: 12  # 雪花 ID 节点号 1-1023，同时运行的实例必须不同
+
+  # 死信队列：MQ 协议中不需要响应的请求处理失败时写入死信存储（memory、file 或 redis），可经管理接口列出和重放
+  # deadLetter:
+  #   enabled: true
+  #   store: file
+  #   dir: /var/lib/framework/dead-letters
+
+  # 服务目录页面：在指标服务器的 /catalog/ 下列出注册中心中的服务、版本、健康状态和协议，并可在页面上调用方法
+  # catalog:
+  #   enabled: true
+  #   callTimeout: 10s
+
+  # 启动自检：检查注册中心、依赖的服务、序列化和 TLS 证书，gateReadiness 为 true 时检查失败则启动失败
+  # selfTest:
+  #   enabled: true
+  #   gateReadiness: true
+  #   certMinValidity: 168h
+
+  security:
+    tls:
+      enabled: {{.Security.TLS.Enabled}}
+      certFile: {{str .Security.TLS.CertFile}}
+      keyFile: {{str .Security.TLS.KeyFile}}
+      caFile: {{str .Security.TLS.CAFile}}
+    authentication:
+      enabled: {{.Security.Authentication.Enabled}}
+      type: {{str .Security.Authentication.Type}}  # jwt 或 apikey
+    authorization:
+      enabled: {{.Security.Authorization.Enabled}}
+      type: {{str .Security.Authorization.Type}}
+
+  observability:
+    logging:
+      level: {{str .Observability.Logging.Level}}  # debug、info、warn 或 error
+      format: {{str .Observability.Logging.Format}}  # text 或 json
+      output: {{str .Observability.Logging.Output}}  # stdout、stderr、file 或 syslog
+      # filePath: /var/log/framework/service.log
+      # maxSizeMB: 100
+      # maxBackups: 5
+    metrics:
+      enabled: {{.Observability.Metrics.Enabled}}
+      port: {{.Observability.Metrics.Port}}
+      path: {{str .Observability.Metrics.Path}}
+      # 请求延迟直方图桶（秒），默认使用 Prometheus 默认桶
+      # buckets: [0.005, 0.01, 0.05, 0.1, 0.5, 1, 5]
+      # 保留的标签白名单，为空时保留全部标签；去掉 method 可控制高基数
+      # labels: [service, protocol, status, error_code, direction]
+    tracing:
+      enabled: {{.Observability.Tracing.Enabled}}
+      exporter: {{str .Observability.Tracing.Exporter}}  # otlp-grpc 或 otlp-http
+      endpoint: {{str .Observability.Tracing.Endpoint}}
+      headers: {}
+      {{with .Observability.Tracing.SamplingRate}}samplingRate: {{float .}}{{else}}# samplingRate: 1.0  # 未配置时全部采样{{end}}
+      batchTimeout: {{duration .Observability.Tracing.BatchTimeout}}
+`))
+
+// RenderConfig 将框架配置渲染为带注释的 YAML
+func RenderConfig(config *FrameworkConfig) ([]byte, error) {
+	if config == nil {
+		return nil, fmt.Errorf("framework config cannot be nil")
+	}
+
+	var buf bytes.Buffer
+	if err := configTemplate.Execute(&buf, config); err != nil {
+		return nil, fmt.Errorf("failed to render config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteDefault 将默认配置写入 path，文件已存在时返回错误而不是覆盖
+func WriteDefault(path string) error {
+	return WriteConfig(path, DefaultFrameworkConfig(), false)
+}
+
+// WriteConfig 将框架配置写入 path，overwrite 为 false 时不覆盖已存在的文件
+func WriteConfig(path string, config *FrameworkConfig, overwrite bool) error {
+	data, err := RenderConfig(config)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if os.IsExist(err) {
+		return fmt.Errorf("config file already exists: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// yamlString 输出 YAML 字符串标量，可能被解析为其他类型或包含特殊字符时加引号
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// yamlFlow 以 JSON 兼容的流式风格输出 YAML 映射或序列，用于协议的 options 等嵌套值
+func yamlFlow(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// formatDuration 输出紧凑的时间间隔，如 5m0s -> 5m
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatFloat 输出浮点数，整数值保留一位小数
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
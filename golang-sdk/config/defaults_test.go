@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// assertDefaults 检查加载的配置与默认配置一致
+func assertDefaults(t *testing.T, cm *ConfigManager) {
+	t.Helper()
+
+	loaded, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	want := DefaultFrameworkConfig()
+
+	if len(loaded.Observability.Tracing.Headers) == 0 {
+		loaded.Observability.Tracing.Headers = want.Observability.Tracing.Headers
+	}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Errorf("loaded config = %+v\nwant %+v", loaded, want)
+	}
+}
+
+func TestWriteDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := WriteDefault(path); err != nil {
+		t.Fatalf("WriteDefault failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read generated config: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# 框架配置") {
+		t.Error("Generated config should start with a comment header")
+	}
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Generated config failed to load: %v", err)
+	}
+	assertDefaults(t, cm)
+
+	// 不覆盖已存在的文件
+	if err := WriteDefault(path); err == nil {
+		t.Error("Expected error when config file already exists")
+	}
+}
+
+func TestWriteConfig_Overwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := DefaultFrameworkConfig()
+	config.Name = "order-service"
+	if err := WriteConfig(path, config, true); err != nil {
+		t.Fatalf("WriteConfig failed: %v", err)
+	}
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Generated config failed to load: %v", err)
+	}
+	if name := cm.GetString("framework.name"); name != "order-service" {
+		t.Errorf("Expected name order-service, got %s", name)
+	}
+}
+
+func TestDefaultFrameworkConfig_MatchesExample(t *testing.T) {
+	// 示例配置文件与默认配置保持一致
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	assertDefaults(t, cm)
+}
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: `""`},
+		{input: "golang-service", want: "golang-service"},
+		{input: "http://localhost:2379", want: "http://localhost:2379"},
+		{input: "1.0", want: `"1.0"`},
+		{input: "true", want: `"true"`},
+		{input: "-x", want: `"-x"`},
+		{input: "a: b", want: `"a: b"`},
+	}
+
+	for _, tt := range tests {
+		if got := yamlString(tt.input); got != tt.want {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
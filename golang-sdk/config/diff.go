@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ConfigChange 两份框架配置间一个配置项的差异，配置项在一方不存在时对应的值为 nil
+type ConfigChange struct {
+	// Key 配置路径，列表项以下标表示，如 framework.observability.logging.level、framework.protocols.external[1].enabled
+	Key string      `json:"key"`
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// DiffFrameworkConfig 比较两份框架配置，返回按配置路径排序的差异；密钥类配置项的值以 ****** 代替
+//
+// 配置按 JSON 字段名展开到叶子配置项后逐项比较，空列表和空映射视为一个配置项
+func DiffFrameworkConfig(old, new *FrameworkConfig) ([]ConfigChange, error) {
+	before, err := flattenFrameworkConfig(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flattenFrameworkConfig(new)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(before)+len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	var changes []ConfigChange
+	for key := range keys {
+		oldValue, newValue := before[key], after[key]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if isSecretKey(key) {
+			if oldValue != nil {
+				oldValue = RedactedValue
+			}
+			if newValue != nil {
+				newValue = RedactedValue
+			}
+		}
+		changes = append(changes, ConfigChange{Key: key, Old: oldValue, New: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+// flattenFrameworkConfig 将框架配置按 JSON 字段名展开为配置路径到叶子值的映射
+func flattenFrameworkConfig(cfg *FrameworkConfig) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if cfg == nil {
+		return values, nil
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode framework config: %w", err)
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode framework config: %w", err)
+	}
+	flattenValue("framework", tree, values)
+	return values, nil
+}
+
+// flattenValue 递归展开映射和列表，空映射和空列表作为叶子值
+func flattenValue(path string, value interface{}, out map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			out[path] = v
+			return
+		}
+		for key, child := range v {
+			flattenValue(path+"."+key, child, out)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[path] = v
+			return
+		}
+		for i, child := range v {
+			flattenValue(path+"["+strconv.Itoa(i)+"]", child, out)
+		}
+	default:
+		out[path] = v
+	}
+}
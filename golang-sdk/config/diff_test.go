@@ -0,0 +1,52 @@
+package config
+
+import "testing"
+
+func TestDiffFrameworkConfig(t *testing.T) {
+	old := &FrameworkConfig{Name: "order-service"}
+	old.Observability.Logging.Level = "info"
+	old.Protocols.External = []ExternalProtocolConfig{{Type: "rest", Enabled: true, Port: 8080}}
+
+	updated := *old
+	updated.Observability.Logging.Level = "debug"
+	updated.Protocols.External = []ExternalProtocolConfig{{Type: "rest", Enabled: false, Port: 8080}, {Type: "websocket", Enabled: true, Port: 8090}}
+
+	changes, err := DiffFrameworkConfig(old, &updated)
+	if err != nil {
+		t.Fatalf("DiffFrameworkConfig failed: %v", err)
+	}
+	got := make(map[string]ConfigChange, len(changes))
+	for _, change := range changes {
+		got[change.Key] = change
+	}
+	if change := got["framework.observability.logging.level"]; change.Old != "info" || change.New != "debug" {
+		t.Errorf("Unexpected logging level change: %+v", change)
+	}
+	if change := got["framework.protocols.external[0].enabled"]; change.Old != true || change.New != false {
+		t.Errorf("Unexpected enabled change: %+v", change)
+	}
+	if change, ok := got["framework.protocols.external[1].port"]; !ok || change.Old != nil || change.New != float64(8090) {
+		t.Errorf("Expected added protocol port, got %+v", change)
+	}
+	if _, ok := got["framework.name"]; ok {
+		t.Error("Unchanged keys should not be reported")
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i-1].Key >= changes[i].Key {
+			t.Fatalf("Changes not sorted: %s before %s", changes[i-1].Key, changes[i].Key)
+		}
+	}
+
+	// 密钥类配置项的值不出现在差异中
+	updated.Security.Authentication.Options = map[string]interface{}{"secret": "new-secret"}
+	changes, _ = DiffFrameworkConfig(old, &updated)
+	redacted := false
+	for _, change := range changes {
+		if change.Key == "framework.security.authentication.options.secret" {
+			redacted = change.Old == nil && change.New == RedactedValue
+		}
+	}
+	if !redacted {
+		t.Errorf("Expected redacted secret change, got %+v", changes)
+	}
+}
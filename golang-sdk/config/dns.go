@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+// DNSConfig 内置 DNS 服务器配置，以注册中心中的实例应答 SRV、A、AAAA 查询，供只能通过 DNS 发现服务的旧工具使用：
+//
+//	framework:
+//	  dns:
+//	    enabled: true
+//	    address: ":8053"
+//	    domain: framework.local
+//	    ttl: 5s
+type DNSConfig struct {
+	Enabled bool          `json:"enabled" config:"enabled"`
+	Address string        `json:"address,omitempty" config:"address"` // 监听地址（UDP 和 TCP），为空时使用 registry.DefaultDNSAddress
+	Domain  string        `json:"domain,omitempty" config:"domain"`   // 服务域名的后缀，为空时使用 registry.DefaultDNSDomain
+	TTL     time.Duration `json:"ttl,omitempty" config:"ttl"`         // 应答记录的 TTL，为 0 时使用 registry.DefaultDNSTTL
+}
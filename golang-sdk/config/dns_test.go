@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_DNS(t *testing.T) {
+	path := configDirWith(t, `framework:
+  dns:
+    enabled: true
+    address: ":5353"
+    domain: svc.internal
+    ttl: 10s
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	dns := fc.DNS
+	if !dns.Enabled || dns.Address != ":5353" || dns.Domain != "svc.internal" || dns.TTL != 10*time.Second {
+		t.Errorf("Unexpected DNS config: %+v", dns)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ConfigSource 配置值来源
+type ConfigSource string
+
+const (
+	// SourceFile 配置文件（含环境配置文件和 conf.d 片段）
+	SourceFile ConfigSource = "file"
+	// SourceEnv 环境变量
+	SourceEnv ConfigSource = "env"
+	// SourceFlag 命令行参数
+	SourceFlag ConfigSource = "flag"
+)
+
+// RedactedValue 敏感配置值的替代文本
+const RedactedValue = "******"
+
+// 配置键最后一段包含以下词时视为敏感配置（不区分大小写）
+var secretKeyWords = []string{"password", "passwd", "secret", "token", "credential", "apikey", "privatekey", "accesskey", "authorization"}
+
+// ConfigEntry 生效配置项
+type ConfigEntry struct {
+	Key      string       `json:"key"`
+	Value    interface{}  `json:"value"`
+	Source   ConfigSource `json:"source"`
+	Origin   string       `json:"origin,omitempty"` // 配置文件路径、环境变量名或命令行参数名
+	Redacted bool         `json:"redacted,omitempty"`
+}
+
+// EffectiveConfig 生效配置快照
+type EffectiveConfig struct {
+	Profiles []string      `json:"profiles,omitempty"`
+	Entries  []ConfigEntry `json:"entries"`
+}
+
+// EffectiveConfig 返回合并所有来源后的生效配置，按配置键排序，敏感值和加密值已脱敏
+//
+// prefix 非空时只返回该前缀下的配置，如 framework.network
+func (cm *ConfigManager) EffectiveConfig(prefix string) (*EffectiveConfig, error) {
+	data, err := cm.config.Data(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config data: %w", err)
+	}
+
+	leaves := make(map[string]interface{})
+	flattenConfig("", data, leaves)
+	// 配置文件中不存在的命令行参数同样生效
+	for key, value := range cm.overrides {
+		if _, ok := leaves[key]; !ok {
+			leaves[key] = value
+		}
+	}
+
+	entries := make([]ConfigEntry, 0, len(leaves))
+	for key, value := range leaves {
+		if !hasKeyPrefix(key, prefix) {
+			continue
+		}
+
+		entry := ConfigEntry{Key: key, Value: value, Source: SourceFile, Origin: cm.path}
+		if file, ok := cm.sources[key]; ok {
+			entry.Origin = file
+		}
+		if override, source, origin, ok := cm.resolveOverride(key); ok {
+			entry.Value, entry.Source, entry.Origin = override, source, origin
+		}
+		if isSecretKey(key) || cm.encrypted[key] {
+			entry.Value, entry.Redacted = RedactedValue, true
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return &EffectiveConfig{Profiles: cm.profiles, Entries: entries}, nil
+}
+
+// DumpHandler 返回生效配置的 HTTP 处理器，支持 ?prefix=framework.network 过滤
+//
+// authenticate 返回错误时拒绝请求；为 nil 时拒绝所有请求，避免未经认证暴露配置
+func (cm *ConfigManager) DumpHandler(authenticate func(r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		if authenticate == nil {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "config dump authentication is not configured"})
+			return
+		}
+		if err := authenticate(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		effective, err := cm.EffectiveConfig(r.URL.Query().Get("prefix"))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(effective)
+	}
+}
+
+// hasKeyPrefix 检查配置键是否位于 prefix 之下
+func hasKeyPrefix(key, prefix string) bool {
+	return prefix == "" || key == prefix || strings.HasPrefix(key, prefix+".")
+}
+
+// isSecretKey 检查配置键是否为敏感配置
+func isSecretKey(key string) bool {
+	last := key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		last = key[i+1:]
+	}
+	last = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(last))
+
+	for _, word := range secretKeyWords {
+		if strings.Contains(last, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "framework.security.jwt.secret", want: true},
+		{key: "framework.database.password", want: true},
+		{key: "framework.cache.api_key", want: true},
+		{key: "framework.observability.tracing.headers.Authorization", want: true},
+		{key: "framework.security.tls.keyFile", want: false},
+		{key: "framework.network.port", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isSecretKey(tt.key); got != tt.want {
+			t.Errorf("isSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func findEntry(t *testing.T, effective *EffectiveConfig, key string) ConfigEntry {
+	t.Helper()
+	for _, entry := range effective.Entries {
+		if entry.Key == key {
+			return entry
+		}
+	}
+	t.Fatalf("Entry %s not found", key)
+	return ConfigEntry{}
+}
+
+func TestConfigManager_EffectiveConfig(t *testing.T) {
+	os.Setenv("FRAMEWORK_NETWORK_HOST", "127.0.0.1")
+	defer os.Unsetenv("FRAMEWORK_NETWORK_HOST")
+
+	cm, err := NewConfigManagerWithOptions("config.yaml", &Options{
+		Profiles: []string{"dev"},
+		Overrides: map[string]string{
+			"framework.network.maxConnections": "500",
+			"framework.security.jwt.secret":    "s3cr3t",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	effective, err := cm.EffectiveConfig("")
+	if err != nil {
+		t.Fatalf("EffectiveConfig failed: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		source ConfigSource
+		origin string
+	}{
+		{key: "framework.name", source: SourceFile, origin: "config.yaml"},
+		{key: "framework.network.port", source: SourceFile, origin: "config.dev.yaml"},
+		{key: "framework.network.host", source: SourceEnv, origin: "FRAMEWORK_NETWORK_HOST"},
+		{key: "framework.network.maxConnections", source: SourceFlag, origin: "framework.network.maxConnections"},
+	}
+	for _, tt := range tests {
+		entry := findEntry(t, effective, tt.key)
+		if entry.Source != tt.source || entry.Origin != tt.origin {
+			t.Errorf("%s: source = %s (%s), want %s (%s)", tt.key, entry.Source, entry.Origin, tt.source, tt.origin)
+		}
+	}
+
+	// 命令行参数中的敏感值同样脱敏
+	secret := findEntry(t, effective, "framework.security.jwt.secret")
+	if secret.Value != RedactedValue || !secret.Redacted {
+		t.Errorf("Expected secret to be redacted, got %v", secret.Value)
+	}
+
+	// 前缀过滤
+	network, err := cm.EffectiveConfig("framework.network")
+	if err != nil {
+		t.Fatalf("EffectiveConfig failed: %v", err)
+	}
+	for _, entry := range network.Entries {
+		if !hasKeyPrefix(entry.Key, "framework.network") {
+			t.Errorf("Unexpected entry %s outside prefix", entry.Key)
+		}
+	}
+	if len(network.Entries) == 0 {
+		t.Error("Expected network entries")
+	}
+}
+
+func TestConfigManager_DumpHandler(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	tokenAuth := func(r *http.Request) error {
+		if r.Header.Get("X-API-Key") != "admin" {
+			return errors.New("invalid api key")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		auth       func(r *http.Request) error
+		method     string
+		apiKey     string
+		wantStatus int
+	}{
+		{name: "no authenticator", auth: nil, method: http.MethodGet, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", auth: tokenAuth, method: http.MethodGet, wantStatus: http.StatusUnauthorized},
+		{name: "wrong method", auth: tokenAuth, method: http.MethodPost, apiKey: "admin", wantStatus: http.StatusMethodNotAllowed},
+		{name: "authenticated", auth: tokenAuth, method: http.MethodGet, apiKey: "admin", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/config?prefix=framework.network", nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
+			rec := httptest.NewRecorder()
+
+			cm.DumpHandler(tt.auth)(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var effective EffectiveConfig
+			if err := json.NewDecoder(rec.Body).Decode(&effective); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			entry := findEntry(t, &effective, "framework.network.port")
+			if entry.Source != SourceFile {
+				t.Errorf("Expected port from file, got %s", entry.Source)
+			}
+		})
+	}
+}
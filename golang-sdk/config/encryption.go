@@ -0,0 +1,193 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// 加密配置值
+//
+// 配置文件中形如 ENC[<base64 密文>] 的字符串在加载时解密，其余代码读取到的都是明文：
+//
+//	security:
+//	  jwt:
+//	    secret: ENC[...]
+//
+// 默认使用 FRAMEWORK_CONFIG_KEY 中的 AES-256-GCM 密钥；接入 KMS 时实现 Decrypter 并通过 Options.Decrypter 传入。
+
+// EnvConfigKey 配置解密密钥的环境变量，值为 base64 编码的 32 字节 AES 密钥
+const EnvConfigKey = "FRAMEWORK_CONFIG_KEY"
+
+const (
+	encryptedPrefix = "ENC["
+	encryptedSuffix = "]"
+)
+
+// Decrypter 配置值解密器
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AESCipher AES-256-GCM 加解密器，密文格式为 nonce || ciphertext
+type AESCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher 创建 AES-256-GCM 加解密器，key 必须为 32 字节
+func NewAESCipher(key []byte) (*AESCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("config key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &AESCipher{aead: aead}, nil
+}
+
+// Encrypt 加密明文
+func (c *AESCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt 实现 Decrypter
+func (c *AESCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+// GenerateConfigKey 生成 base64 编码的随机 AES-256 密钥，用于 FRAMEWORK_CONFIG_KEY
+func GenerateConfigKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate config key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseConfigKey 解析 base64 编码的 AES-256 密钥
+func ParseConfigKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid config key encoding: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("config key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// DecrypterFromEnv 根据 FRAMEWORK_CONFIG_KEY 创建解密器，未设置时返回 nil
+func DecrypterFromEnv() (Decrypter, error) {
+	encoded := os.Getenv(EnvConfigKey)
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := ParseConfigKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvConfigKey, err)
+	}
+	return NewAESCipher(key)
+}
+
+// EncryptValue 加密明文并返回可写入配置文件的 ENC[...] 字符串
+func EncryptValue(c *AESCipher, plaintext string) (string, error) {
+	ciphertext, err := c.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext) + encryptedSuffix, nil
+}
+
+// IsEncryptedValue 检查配置值是否为 ENC[...] 加密值
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// decryptValue 解密 ENC[...] 加密值
+func decryptValue(value string, decrypter Decrypter) (string, error) {
+	encoded := strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix)
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value encoding: %w", err)
+	}
+
+	plaintext, err := decrypter.Decrypt(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt config value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// decryptConfig 就地解密配置中的加密值，并记录被解密的配置键（列表中的加密值记录为列表本身的键）
+func decryptConfig(path string, value interface{}, decrypter Decrypter, encrypted map[string]bool) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if s, ok := child.(string); ok && IsEncryptedValue(s) {
+				plaintext, err := decryptLeaf(childPath, s, decrypter)
+				if err != nil {
+					return err
+				}
+				v[key] = plaintext
+				encrypted[childPath] = true
+				continue
+			}
+			if err := decryptConfig(childPath, child, decrypter, encrypted); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok && IsEncryptedValue(s) {
+				plaintext, err := decryptLeaf(fmt.Sprintf("%s[%d]", path, i), s, decrypter)
+				if err != nil {
+					return err
+				}
+				v[i] = plaintext
+				encrypted[path] = true
+				continue
+			}
+			if err := decryptConfig(fmt.Sprintf("%s[%d]", path, i), item, decrypter, encrypted); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decryptLeaf 解密单个配置值，错误信息包含配置路径
+func decryptLeaf(path, value string, decrypter Decrypter) (string, error) {
+	if decrypter == nil {
+		return "", fmt.Errorf("%s: encrypted config value found but no decryption key is configured (set %s)", path, EnvConfigKey)
+	}
+
+	plaintext, err := decryptValue(value, decrypter)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T) (*AESCipher, string) {
+	t.Helper()
+	encoded, err := GenerateConfigKey()
+	if err != nil {
+		t.Fatalf("GenerateConfigKey failed: %v", err)
+	}
+	key, err := ParseConfigKey(encoded)
+	if err != nil {
+		t.Fatalf("ParseConfigKey failed: %v", err)
+	}
+	c, err := NewAESCipher(key)
+	if err != nil {
+		t.Fatalf("NewAESCipher failed: %v", err)
+	}
+	return c, encoded
+}
+
+func TestEncryptValue_RoundTrip(t *testing.T) {
+	c, _ := newTestCipher(t)
+
+	encrypted, err := EncryptValue(c, "s3cr3t")
+	if err != nil {
+		t.Fatalf("EncryptValue failed: %v", err)
+	}
+	if !IsEncryptedValue(encrypted) {
+		t.Fatalf("Expected ENC[...] value, got %s", encrypted)
+	}
+
+	plaintext, err := decryptValue(encrypted, c)
+	if err != nil {
+		t.Fatalf("decryptValue failed: %v", err)
+	}
+	if plaintext != "s3cr3t" {
+		t.Errorf("Expected s3cr3t, got %s", plaintext)
+	}
+
+	// 使用其他密钥解密失败
+	other, _ := newTestCipher(t)
+	if _, err := decryptValue(encrypted, other); err == nil {
+		t.Error("Expected error when decrypting with a different key")
+	}
+}
+
+func TestParseConfigKey_Invalid(t *testing.T) {
+	for _, input := range []string{"not base64!", "c2hvcnQ="} {
+		if _, err := ParseConfigKey(input); err == nil {
+			t.Errorf("ParseConfigKey(%q) expected error", input)
+		}
+	}
+}
+
+func TestDecryptConfig(t *testing.T) {
+	c, _ := newTestCipher(t)
+	secret, _ := EncryptValue(c, "jwt-secret")
+	node, _ := EncryptValue(c, "node-b")
+
+	values := map[string]interface{}{
+		"framework": map[string]interface{}{
+			"name": "svc",
+			"security": map[string]interface{}{
+				"jwt": map[string]interface{}{"secret": secret},
+			},
+			"nodes": []interface{}{"node-a", node},
+		},
+	}
+
+	encrypted := make(map[string]bool)
+	if err := decryptConfig("", values, c, encrypted); err != nil {
+		t.Fatalf("decryptConfig failed: %v", err)
+	}
+
+	framework := values["framework"].(map[string]interface{})
+	if got := framework["security"].(map[string]interface{})["jwt"].(map[string]interface{})["secret"]; got != "jwt-secret" {
+		t.Errorf("Expected decrypted secret, got %v", got)
+	}
+	if got := framework["nodes"].([]interface{})[1]; got != "node-b" {
+		t.Errorf("Expected decrypted list item, got %v", got)
+	}
+	if !encrypted["framework.security.jwt.secret"] || !encrypted["framework.nodes"] || encrypted["framework.name"] {
+		t.Errorf("Unexpected encrypted keys: %v", encrypted)
+	}
+
+	// 未配置密钥时返回带配置路径的错误
+	values = map[string]interface{}{"framework": map[string]interface{}{"secret": secret}}
+	err := decryptConfig("", values, nil, make(map[string]bool))
+	if err == nil || !strings.Contains(err.Error(), "framework.secret") {
+		t.Errorf("Expected error mentioning framework.secret, got %v", err)
+	}
+}
+
+func TestNewConfigManager_EncryptedValues(t *testing.T) {
+	c, encodedKey := newTestCipher(t)
+	merchantID, err := EncryptValue(c, "m-123456")
+	if err != nil {
+		t.Fatalf("EncryptValue failed: %v", err)
+	}
+
+	base, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read config.yaml: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := string(base) + "\n  payment:\n    merchantId: " + merchantID + "\n    region: eu\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// 未配置密钥时加载失败
+	os.Unsetenv(EnvConfigKey)
+	if _, err := NewConfigManager(path); err == nil {
+		t.Fatal("Expected error without decryption key")
+	}
+
+	os.Setenv(EnvConfigKey, encodedKey)
+	defer os.Unsetenv(EnvConfigKey)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if got := cm.GetString("framework.payment.merchantId"); got != "m-123456" {
+		t.Errorf("Expected decrypted merchant id, got %s", got)
+	}
+
+	// 生效配置中加密值始终脱敏
+	effective, err := cm.EffectiveConfig("framework.payment")
+	if err != nil {
+		t.Fatalf("EffectiveConfig failed: %v", err)
+	}
+	for _, entry := range effective.Entries {
+		if entry.Key == "framework.payment.merchantId" && !entry.Redacted {
+			t.Error("Expected decrypted value to be redacted in effective config")
+		}
+		if entry.Key == "framework.payment.region" && entry.Redacted {
+			t.Error("Plain value should not be redacted")
+		}
+	}
+}
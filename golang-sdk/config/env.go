@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 环境变量命名
+//
+// framework 下的配置键映射为 <前缀>_<其余路径>，路径各段转大写后以单个下划线连接，
+// 配置键中原有的下划线写作双下划线，保证不同配置键不会映射到同一个环境变量：
+//
+//	framework.network.port     -> FRAMEWORK_NETWORK_PORT
+//	framework.cache.max_size   -> FRAMEWORK_CACHE_MAX__SIZE
+//	framework.cache.max.size   -> FRAMEWORK_CACHE_MAX_SIZE
+//
+// 前缀通过 Options.EnvPrefix 修改，如 ORDERS 时为 ORDERS_NETWORK_PORT。
+// framework 之外的配置键不加前缀，如 app.name -> APP_NAME，前缀不应与其他顶层配置键同名。
+
+// DefaultEnvPrefix 默认环境变量前缀
+const DefaultEnvPrefix = "FRAMEWORK"
+
+// rootKey 替换为环境变量前缀的顶层配置键
+const rootKey = "framework"
+
+// EnvName 返回配置键对应的环境变量名，prefix 为空时使用 DefaultEnvPrefix
+func EnvName(prefix, key string) string {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	if key == rootKey {
+		return prefix
+	}
+	if rest, ok := strings.CutPrefix(key, rootKey+"."); ok {
+		return prefix + "_" + escapeEnvPath(rest)
+	}
+	return escapeEnvPath(key)
+}
+
+// KeyFromEnvName 将带前缀的环境变量名还原为配置键，配置键各段为小写
+//
+// 环境变量名不区分大小写，还原结果与原配置键按 strings.EqualFold 比较
+func KeyFromEnvName(prefix, name string) (string, bool) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	rest, ok := strings.CutPrefix(strings.ToUpper(name), prefix+"_")
+	if !ok || rest == "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(rootKey)
+	b.WriteByte('.')
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != '_' {
+			b.WriteByte(rest[i])
+			continue
+		}
+		if i+1 < len(rest) && rest[i+1] == '_' {
+			b.WriteByte('_')
+			i++
+			continue
+		}
+		b.WriteByte('.')
+	}
+
+	key := strings.ToLower(b.String())
+	for _, segment := range strings.Split(key, ".") {
+		if segment == "" {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// normalizeEnvPrefix 校验并规范化环境变量前缀，为空时返回 DefaultEnvPrefix
+func normalizeEnvPrefix(prefix string) (string, error) {
+	prefix = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(prefix)), "_")
+	if prefix == "" {
+		return DefaultEnvPrefix, nil
+	}
+
+	for i, c := range prefix {
+		isLetter := c >= 'A' && c <= 'Z'
+		if !isLetter && (i == 0 || (c != '_' && (c < '0' || c > '9'))) {
+			return "", fmt.Errorf("invalid env prefix %q: must start with a letter and contain only letters, digits and underscores", prefix)
+		}
+	}
+	return prefix, nil
+}
+
+// escapeEnvPath 将配置路径转换为环境变量名，原有的下划线写作双下划线
+func escapeEnvPath(path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segments[i] = strings.ToUpper(strings.ReplaceAll(segment, "_", "__"))
+	}
+	return strings.Join(segments, "_")
+}
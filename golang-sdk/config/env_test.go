@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		prefix string
+		key    string
+		want   string
+	}{
+		{prefix: "", key: "framework.network.port", want: "FRAMEWORK_NETWORK_PORT"},
+		{prefix: "", key: "framework.network.keepAlive", want: "FRAMEWORK_NETWORK_KEEPALIVE"},
+		{prefix: "", key: "framework.cache.max_size", want: "FRAMEWORK_CACHE_MAX__SIZE"},
+		{prefix: "", key: "framework.cache.max.size", want: "FRAMEWORK_CACHE_MAX_SIZE"},
+		{prefix: "ORDERS", key: "framework.network.port", want: "ORDERS_NETWORK_PORT"},
+		{prefix: "MY_APP", key: "framework.name", want: "MY_APP_NAME"},
+		{prefix: "ORDERS", key: "framework", want: "ORDERS"},
+		// framework 之外的配置键不加前缀
+		{prefix: "ORDERS", key: "app.name", want: "APP_NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := EnvName(tt.prefix, tt.key); got != tt.want {
+				t.Errorf("EnvName(%q, %q) = %s, want %s", tt.prefix, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvName_NoCollisions(t *testing.T) {
+	// 下划线和层级分隔不会映射到同一个环境变量
+	keys := []string{
+		"framework.cache.max_size",
+		"framework.cache.max.size",
+		"framework.cache_max.size",
+		"framework.cache_max_size",
+	}
+
+	seen := make(map[string]string)
+	for _, key := range keys {
+		name := EnvName("", key)
+		if other, ok := seen[name]; ok {
+			t.Errorf("%s and %s both map to %s", key, other, name)
+		}
+		seen[name] = key
+	}
+}
+
+func TestKeyFromEnvName_RoundTrip(t *testing.T) {
+	keys := []string{
+		"framework.name",
+		"framework.network.keepAlive",
+		"framework.cache.max_size",
+		"framework.cache_max.size",
+		"framework.services.orders.retry.maxAttempts",
+	}
+
+	for _, prefix := range []string{"", "ORDERS", "MY_APP"} {
+		for _, key := range keys {
+			name := EnvName(prefix, key)
+			got, ok := KeyFromEnvName(prefix, name)
+			if !ok {
+				t.Errorf("KeyFromEnvName(%q, %s) failed", prefix, name)
+				continue
+			}
+			if !strings.EqualFold(got, key) {
+				t.Errorf("Round trip %s -> %s -> %s", key, name, got)
+			}
+		}
+	}
+}
+
+func TestKeyFromEnvName_Invalid(t *testing.T) {
+	tests := []struct {
+		prefix string
+		name   string
+	}{
+		{prefix: "", name: "PATH"},
+		{prefix: "", name: "FRAMEWORK_"},
+		{prefix: "", name: "FRAMEWORK_NETWORK_"},
+		{prefix: "ORDERS", name: "FRAMEWORK_NETWORK_PORT"},
+	}
+
+	for _, tt := range tests {
+		if key, ok := KeyFromEnvName(tt.prefix, tt.name); ok {
+			t.Errorf("KeyFromEnvName(%q, %s) = %s, expected failure", tt.prefix, tt.name, key)
+		}
+	}
+}
+
+func TestNormalizeEnvPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{prefix: "", want: "FRAMEWORK"},
+		{prefix: "orders", want: "ORDERS"},
+		{prefix: "ORDERS_", want: "ORDERS"},
+		{prefix: "APP2", want: "APP2"},
+		{prefix: "MY-APP", wantErr: true},
+		{prefix: "2APP", wantErr: true},
+		{prefix: "_APP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeEnvPrefix(tt.prefix)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeEnvPrefix(%q) error = %v, wantErr %v", tt.prefix, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeEnvPrefix(%q) = %s, want %s", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestConfigManager_EnvPrefix(t *testing.T) {
+	path := configDirWith(t, "framework:\n  cache:\n    max_size: 1MB\n")
+
+	os.Setenv("ORDERS_NAME", "from-prefix")
+	os.Setenv("FRAMEWORK_NAME", "from-default")
+	os.Setenv("ORDERS_CACHE_MAX__SIZE", "2MB")
+	defer func() {
+		os.Unsetenv("ORDERS_NAME")
+		os.Unsetenv("FRAMEWORK_NAME")
+		os.Unsetenv("ORDERS_CACHE_MAX__SIZE")
+	}()
+
+	cm, err := NewConfigManagerWithOptions(path, &Options{EnvPrefix: "orders"})
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	if name := cm.GetString("framework.name"); name != "from-prefix" {
+		t.Errorf("Expected name from ORDERS_NAME, got %s", name)
+	}
+	if size := cm.GetString("framework.cache.max_size"); size != "2MB" {
+		t.Errorf("Expected max_size from ORDERS_CACHE_MAX__SIZE, got %s", size)
+	}
+
+	if _, err := NewConfigManagerWithOptions(path, &Options{EnvPrefix: "my-app"}); err == nil {
+		t.Error("Expected error for invalid env prefix")
+	}
+}
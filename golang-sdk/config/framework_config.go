@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -17,6 +18,18 @@ type FrameworkConfig struct {
 	ConnectionPool ConnectionPoolConfig `json:"connectionPool"`
 	Security       SecurityConfig       `json:"security"`
 	Observability  ObservabilityConfig  `json:"observability"`
+	Services       map[string]ServiceConfig `json:"services,omitempty"` // 按目标服务名的覆盖配置
+	Transforms     []TransformConfig        `json:"transforms,omitempty"` // 按服务方法的请求转换规则
+	Routes         []RouteConfig            `json:"routes,omitempty"`     // 客户端调用的定时路由规则
+	Capture        CaptureConfig            `json:"capture"`              // 请求录制
+	PayloadLog     PayloadLogConfig         `json:"payloadLog"`           // 负载日志
+	AcceptQueue    AcceptQueueConfig        `json:"acceptQueue"`          // 协议处理器接收队列
+	DNS            DNSConfig                `json:"dns"`                  // 内置 DNS 服务器
+	Accounting     AccountingConfig         `json:"accounting"`           // 用量计量
+	IDGenerator    IDGeneratorConfig        `json:"idGenerator"`          // ID 生成策略
+	DeadLetter     DeadLetterConfig         `json:"deadLetter"`           // 死信队列
+	Catalog        CatalogConfig            `json:"catalog"`              // 服务目录页面
+	SelfTest       SelfTestConfig           `json:"selfTest"`             // 启动自检
 }
 
 // NetworkConfig 网络配置
@@ -27,6 +40,8 @@ type NetworkConfig struct {
 	ReadTimeout    time.Duration `json:"readTimeout"`
 	WriteTimeout   time.Duration `json:"writeTimeout"`
 	KeepAlive      bool          `json:"keepAlive"`
+	// ReusePort 监听器启用 SO_REUSEPORT，同一主机上的多个进程可以监听相同端口（Linux、BSD、macOS）
+	ReusePort bool `json:"reusePort"`
 }
 
 // RegistryConfig 注册中心配置
@@ -36,6 +51,21 @@ type RegistryConfig struct {
 	Namespace         string   `json:"namespace"`
 	TTL               int      `json:"ttl"`
 	HeartbeatInterval int      `json:"heartbeatInterval"`
+	// HealthCheckInterval 就绪检查周期（秒），不健康时从注册中心注销、恢复后重新注册，为 0 时不同步
+	HealthCheckInterval int `json:"healthCheckInterval"`
+	// UnhealthyThreshold 连续多少次就绪检查失败后注销，默认 3
+	UnhealthyThreshold int `json:"unhealthyThreshold"`
+	// Username 和 Password 为 etcd 认证的用户名和密码，为空时不认证
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// ReadOnly 只查询和监听服务，不注册本实例，用于只需消费服务拓扑的边缘网关；应同时为 Username 分配只读角色
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Mirrors 同时注册的其他注册中心（如另一个 etcd 集群），单个注册中心故障时服务发现仍然可用；各项的 mirrors、readOnly 被忽略
+	Mirrors []RegistryConfig `json:"mirrors,omitempty"`
+	// Quorum 配置了 Mirrors 时注册和注销需要成功的注册中心数（含本注册中心），为 0 时为多数
+	Quorum int `json:"quorum,omitempty"`
+	// RepairInterval 后台重试落后注册中心的周期（秒），为 0 时为 10 秒
+	RepairInterval int `json:"repairInterval,omitempty"`
 }
 
 // ProtocolsConfig 协议配置
@@ -45,21 +75,33 @@ type ProtocolsConfig struct {
 }
 
 // ExternalProtocolConfig 外部协议配置
+//
+// 同一协议可配置多次以监听多个端口或网卡，如对外的 REST 监听 8080、管理用的 REST 只监听 127.0.0.1:8081
 type ExternalProtocolConfig struct {
-	Type    string                 `json:"type"`
-	Enabled bool                   `json:"enabled"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	// Host 监听地址，为空时使用 network.host；为回环地址而 network.host 不是时只供本机访问，不写入注册中心
+	Host    string                 `json:"host,omitempty"`
 	Port    int                    `json:"port"`
 	Path    string                 `json:"path,omitempty"`
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
 // InternalProtocolConfig 内部协议配置
+//
+// 同一协议可配置多次以监听多个端口或网卡
 type InternalProtocolConfig struct {
-	Type          string `json:"type"`
-	Enabled       bool   `json:"enabled"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	// Host 监听地址，为空时使用 network.host；为回环地址而 network.host 不是时只供本机访问，不写入注册中心
+	Host          string `json:"host,omitempty"`
 	Port          int    `json:"port,omitempty"`
 	Serialization string `json:"serialization,omitempty"`
 	Compression   bool   `json:"compression,omitempty"`
+	// IdleTimeout 和 StreamIdleTimeout 只对 Custom 协议生效：关闭超过 IdleTimeout 没有请求的连接，
+	// 结束超过 StreamIdleTimeout 没有帧的流，为 0 时不限制
+	IdleTimeout       time.Duration `json:"idleTimeout,omitempty"`
+	StreamIdleTimeout time.Duration `json:"streamIdleTimeout,omitempty"`
 }
 
 // ConnectionPoolConfig 连接池配置
@@ -108,24 +150,35 @@ type ObservabilityConfig struct {
 
 // LoggingConfig 日志配置
 type LoggingConfig struct {
-	Level  string `json:"level"`
-	Format string `json:"format"`
-	Output string `json:"output"`
+	Level         string `json:"level"`
+	Format        string `json:"format"`
+	Output        string `json:"output"`
+	FilePath      string `json:"filePath,omitempty"`
+	MaxSizeMB     int    `json:"maxSizeMB,omitempty"`
+	MaxBackups    int    `json:"maxBackups,omitempty"`
+	SyslogNetwork string `json:"syslogNetwork,omitempty"`
+	SyslogAddress string `json:"syslogAddress,omitempty"`
 }
 
 // MetricsConfig 指标配置
 type MetricsConfig struct {
-	Enabled bool   `json:"enabled"`
-	Port    int    `json:"port"`
-	Path    string `json:"path"`
+	Enabled bool      `json:"enabled"`
+	Port    int       `json:"port"`
+	Path    string    `json:"path"`
+	Buckets []float64 `json:"buckets,omitempty"` // 请求延迟直方图桶（秒）
+	Labels  []string  `json:"labels,omitempty"`  // 保留的标签白名单，为空时保留全部标签
 }
 
 // TracingConfig 追踪配置
 type TracingConfig struct {
-	Enabled      bool    `json:"enabled"`
-	Exporter     string  `json:"exporter"`
-	Endpoint     string  `json:"endpoint"`
-	SamplingRate float64 `json:"samplingRate"`
+	Enabled      bool              `json:"enabled"`
+	Exporter     string            `json:"exporter"`
+	Endpoint     string            `json:"endpoint"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Insecure     bool              `json:"insecure"`
+	// SamplingRate 采样率 0~1，未配置时为 nil（全部采样），为 0 时不采样
+	SamplingRate *float64          `json:"samplingRate,omitempty"`
+	BatchTimeout time.Duration     `json:"batchTimeout"`
 }
 
 // LoadFrameworkConfig 加载框架配置
@@ -145,15 +198,35 @@ func (cm *ConfigManager) LoadFrameworkConfig() (*FrameworkConfig, error) {
 		ReadTimeout:    cm.GetDuration("framework.network.readTimeout"),
 		WriteTimeout:   cm.GetDuration("framework.network.writeTimeout"),
 		KeepAlive:      cm.GetBool("framework.network.keepAlive"),
+		ReusePort:      cm.GetBool("framework.network.reusePort"),
 	}
 	
 	// 注册中心配置
 	config.Registry = RegistryConfig{
-		Type:              cm.GetString("framework.registry.type"),
-		Endpoints:         cm.GetStringSlice("framework.registry.endpoints"),
-		Namespace:         cm.GetString("framework.registry.namespace"),
-		TTL:               cm.GetInt("framework.registry.ttl"),
-		HeartbeatInterval: cm.GetInt("framework.registry.heartbeatInterval"),
+		Type:                cm.GetString("framework.registry.type"),
+		Endpoints:           cm.GetStringSlice("framework.registry.endpoints"),
+		Namespace:           cm.GetString("framework.registry.namespace"),
+		TTL:                 cm.GetInt("framework.registry.ttl"),
+		HeartbeatInterval:   cm.GetInt("framework.registry.heartbeatInterval"),
+		HealthCheckInterval: cm.GetInt("framework.registry.healthCheckInterval"),
+		UnhealthyThreshold:  cm.GetInt("framework.registry.unhealthyThreshold"),
+		Username:            cm.GetString("framework.registry.username"),
+		Password:            cm.GetString("framework.registry.password"),
+		ReadOnly:            cm.GetBool("framework.registry.readOnly"),
+		Quorum:              cm.GetInt("framework.registry.quorum"),
+		RepairInterval:      cm.GetInt("framework.registry.repairInterval"),
+	}
+	var mirrors struct {
+		Mirrors []RegistryConfig `config:"mirrors"`
+	}
+	if err := cm.UnmarshalKey("framework.registry", &mirrors); err != nil {
+		return nil, fmt.Errorf("failed to load registry mirrors: %w", err)
+	}
+	config.Registry.Mirrors = mirrors.Mirrors
+	
+	// 协议配置
+	if err := cm.UnmarshalKey("framework.protocols", &config.Protocols); err != nil {
+		return nil, fmt.Errorf("failed to load protocols config: %w", err)
 	}
 	
 	// 连接池配置
@@ -186,22 +259,98 @@ func (cm *ConfigManager) LoadFrameworkConfig() (*FrameworkConfig, error) {
 	// 可观测性配置
 	config.Observability = ObservabilityConfig{
 		Logging: LoggingConfig{
-			Level:  cm.GetString("framework.observability.logging.level"),
-			Format: cm.GetString("framework.observability.logging.format"),
-			Output: cm.GetString("framework.observability.logging.output"),
+			Level:         cm.GetString("framework.observability.logging.level"),
+			Format:        cm.GetString("framework.observability.logging.format"),
+			Output:        cm.GetString("framework.observability.logging.output"),
+			FilePath:      cm.GetString("framework.observability.logging.filePath"),
+			MaxSizeMB:     cm.GetInt("framework.observability.logging.maxSizeMB"),
+			MaxBackups:    cm.GetInt("framework.observability.logging.maxBackups"),
+			SyslogNetwork: cm.GetString("framework.observability.logging.syslogNetwork"),
+			SyslogAddress: cm.GetString("framework.observability.logging.syslogAddress"),
 		},
 		Metrics: MetricsConfig{
 			Enabled: cm.GetBool("framework.observability.metrics.enabled"),
 			Port:    cm.GetInt("framework.observability.metrics.port"),
 			Path:    cm.GetString("framework.observability.metrics.path"),
+			Buckets: cm.GetConfig().MustGet(context.Background(), "framework.observability.metrics.buckets").Float64s(),
+			Labels:  cm.GetStringSlice("framework.observability.metrics.labels"),
 		},
 		Tracing: TracingConfig{
 			Enabled:      cm.GetBool("framework.observability.tracing.enabled"),
 			Exporter:     cm.GetString("framework.observability.tracing.exporter"),
 			Endpoint:     cm.GetString("framework.observability.tracing.endpoint"),
-			SamplingRate: cm.GetConfig().MustGet(context.Background(), "framework.observability.tracing.samplingRate").Float64(),
+			Headers:      cm.GetConfig().MustGet(context.Background(), "framework.observability.tracing.headers").MapStrStr(),
+			Insecure:     cm.GetBool("framework.observability.tracing.insecure"),
+			SamplingRate: cm.getOptionalFloat("framework.observability.tracing.samplingRate"),
+			BatchTimeout: cm.GetDuration("framework.observability.tracing.batchTimeout"),
 		},
 	}
 	
+	// 按服务覆盖配置
+	services, err := cm.loadServices()
+	if err != nil {
+		return nil, err
+	}
+	config.Services = services
+	
+	// 请求转换规则
+	transforms, err := cm.loadTransforms()
+	if err != nil {
+		return nil, err
+	}
+	config.Transforms = transforms
+	
+	// 客户端调用的路由规则
+	routes, err := cm.loadRoutes()
+	if err != nil {
+		return nil, err
+	}
+	config.Routes = routes
+	
+	// 请求录制
+	if err := cm.UnmarshalKey("framework.capture", &config.Capture); err != nil {
+		return nil, err
+	}
+	
+	// 负载日志
+	if err := cm.UnmarshalKey("framework.payloadLog", &config.PayloadLog); err != nil {
+		return nil, err
+	}
+	
+	// 接收队列
+	if err := cm.UnmarshalKey("framework.acceptQueue", &config.AcceptQueue); err != nil {
+		return nil, err
+	}
+	
+	// 内置 DNS 服务器
+	if err := cm.UnmarshalKey("framework.dns", &config.DNS); err != nil {
+		return nil, err
+	}
+	
+	// 用量计量
+	if err := cm.UnmarshalKey("framework.accounting", &config.Accounting); err != nil {
+		return nil, err
+	}
+	
+	// ID 生成策略
+	if err := cm.UnmarshalKey("framework.idGenerator", &config.IDGenerator); err != nil {
+		return nil, err
+	}
+	
+	// 死信队列
+	if err := cm.UnmarshalKey("framework.deadLetter", &config.DeadLetter); err != nil {
+		return nil, err
+	}
+	
+	// 服务目录页面
+	if err := cm.UnmarshalKey("framework.catalog", &config.Catalog); err != nil {
+		return nil, err
+	}
+	
+	// 启动自检
+	if err := cm.UnmarshalKey("framework.selfTest", &config.SelfTest); err != nil {
+		return nil, err
+	}
+	
 	return config, nil
 }
@@ -0,0 +1,21 @@
+package config
+
+// ID 生成策略，见 idgen 包
+const (
+	IDGeneratorW3C       = "w3c"
+	IDGeneratorUUIDv7    = "uuidv7"
+	IDGeneratorSnowflake = "snowflake"
+)
+
+// IDGeneratorConfig 追踪 ID、span ID 和请求 ID 的生成策略，为空时使用 w3c（随机 ID）；
+// uuidv7 和 snowflake 生成的追踪 ID 和请求 ID 按时间排序：
+//
+//	framework:
+//	  idGenerator:
+//	    strategy: snowflake
+//	    node: 12
+type IDGeneratorConfig struct {
+	Strategy string `json:"strategy,omitempty" config:"strategy"` // w3c、uuidv7 或 snowflake
+	// Node 雪花 ID 的节点号（1-1023），同时运行的实例必须不同；为 0 时由主机名和进程号推导，实例较多时可能重复
+	Node int64 `json:"node,omitempty" config:"node"`
+}
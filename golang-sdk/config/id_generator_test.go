@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestLoadFrameworkConfig_IDGenerator(t *testing.T) {
+	path := configDirWith(t, `framework:
+  idGenerator:
+    strategy: snowflake
+    node: 12
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	if fc.IDGenerator.Strategy != IDGeneratorSnowflake || fc.IDGenerator.Node != 12 {
+		t.Errorf("Unexpected ID generator config: %+v", fc.IDGenerator)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownKeys 返回配置文件中 prefix 下没有对应 target 字段的配置键，按配置键排序，用于发现拼写错误和已废弃的配置
+//
+// target 为结构体或结构体指针，字段名取 config 标签，其次 mapstructure、json 标签，不区分大小写；
+// 映射字段接受任意子键（值为结构体时检查每个值），interface{} 字段不检查，结构体列表逐个元素检查，
+// 键形如 framework.protocols.external[0].hostname。
+// 只检查配置文件，环境变量和命令行参数不在其中
+func (cm *ConfigManager) UnknownKeys(prefix string, target interface{}) ([]string, error) {
+	t := reflect.TypeOf(target)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("target must be a struct or a pointer to struct, got %T", target)
+	}
+
+	data, err := cm.config.Data(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config data: %w", err)
+	}
+
+	var value interface{} = data
+	if prefix != "" {
+		for _, part := range strings.Split(prefix, ".") {
+			m, ok := toStringMap(value)
+			if !ok {
+				return nil, nil
+			}
+			if value, ok = lookupKey(m, part); !ok {
+				return nil, nil
+			}
+		}
+	}
+
+	var unknown []string
+	collectUnknownKeys(prefix, value, t, &unknown)
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// IsEncrypted 检查配置键的值是否在加载时由 ENC[...] 解密得到
+func (cm *ConfigManager) IsEncrypted(key string) bool {
+	return cm.encrypted[key]
+}
+
+// collectUnknownKeys 递归比较配置值与类型 t 的字段
+func collectUnknownKeys(path string, value interface{}, t reflect.Type, unknown *[]string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case isStruct(t):
+		m, ok := toStringMap(value)
+		if !ok {
+			return
+		}
+		fields := knownFields(t)
+		for key, child := range m {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			fieldType, ok := fields[strings.ToLower(key)]
+			if !ok {
+				*unknown = append(*unknown, childPath)
+				continue
+			}
+			collectUnknownKeys(childPath, child, fieldType, unknown)
+		}
+	case t.Kind() == reflect.Map:
+		if !isStruct(t.Elem()) {
+			return
+		}
+		m, ok := toStringMap(value)
+		if !ok {
+			return
+		}
+		for key, child := range m {
+			collectUnknownKeys(path+"."+key, child, t.Elem(), unknown)
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if !isStruct(t.Elem()) {
+			return
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			collectUnknownKeys(fmt.Sprintf("%s[%d]", path, i), item, t.Elem(), unknown)
+		}
+	}
+}
+
+// knownFields 返回结构体接受的配置键（小写）及其类型，展开 squash 的嵌入结构体
+func knownFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, squash, skip := fieldKey(field)
+		if skip {
+			continue
+		}
+		if squash {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			for key, fieldType := range knownFields(embedded) {
+				fields[key] = fieldType
+			}
+			continue
+		}
+		// 只有 json 标签的字段由 LoadFrameworkConfig 按 json 标签中的键读取
+		if _, ok := field.Tag.Lookup(tagConfig); !ok && field.Tag.Get(tagMapstructure) == "" {
+			if jsonName := strings.Split(field.Tag.Get("json"), ",")[0]; jsonName == "-" {
+				continue
+			} else if jsonName != "" {
+				name = jsonName
+			}
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	path := configDirWith(t, `framework:
+  netwrok:
+    port: 8080
+  network:
+    readTimeOut: 30s
+    keepalive: true
+  protocols:
+    external:
+      - type: REST
+        enabled: true
+        port: 8080
+        hostname: 0.0.0.0
+        options:
+          anything: goes
+  security:
+    authentication:
+      options:
+        secret: any-key-is-accepted
+  services:
+    orders:
+      timeout: 1s
+      retyr:
+        maxAttempts: 3
+  accounting:
+    sink: csv
+    files: usage.csv
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	unknown, err := cm.UnknownKeys("framework", &FrameworkConfig{})
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	// 映射字段（options、services 的服务名）接受任意子键，字段名不区分大小写
+	want := []string{
+		"framework.accounting.files",
+		"framework.netwrok",
+		"framework.protocols.external[0].hostname",
+		"framework.services.orders.retyr",
+	}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("UnknownKeys = %v, want %v", unknown, want)
+	}
+}
+
+func TestUnknownKeys_DefaultConfig(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	unknown, err := cm.UnknownKeys("framework", &FrameworkConfig{})
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	if len(unknown) > 0 {
+		t.Errorf("Expected no unknown keys in config.yaml, got %v", unknown)
+	}
+	if _, err := cm.UnknownKeys("framework", "not a struct"); err == nil {
+		t.Error("Expected error for non-struct target")
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gogf/gf/v2/os/gcfg"
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的配置文件格式，按扩展名识别
+var configDecoders = map[string]func(data []byte, v *map[string]interface{}) error{
+	".yaml": func(data []byte, v *map[string]interface{}) error { return yaml.Unmarshal(data, v) },
+	".yml":  func(data []byte, v *map[string]interface{}) error { return yaml.Unmarshal(data, v) },
+	".json": func(data []byte, v *map[string]interface{}) error { return json.Unmarshal(data, v) },
+	".toml": func(data []byte, v *map[string]interface{}) error { return toml.Unmarshal(data, v) },
+}
+
+// layeredConfig 分层加载结果
+type layeredConfig struct {
+	adapter   gcfg.Adapter
+	sources   map[string]string // 配置键 -> 提供该值的配置文件
+	encrypted map[string]bool   // 加载时解密的配置键
+}
+
+// loadLayered 加载基础配置并依次合并环境配置，解密加密值，同时记录每个配置键来自哪个文件
+//
+// configPath 为单个配置文件时，环境配置为同目录下的 config.<profile>.<ext>；
+// 为目录时（conf.d 风格），加载目录下所有配置片段，环境配置为子目录 <profile>/ 下的片段
+func loadLayered(configPath string, profiles []string, decrypter Decrypter) (*layeredConfig, error) {
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat config path %s: %w", configPath, err)
+	}
+	single := !info.IsDir() && len(profiles) == 0
+
+	files, err := configFiles(configPath, info.IsDir())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		path := profileConfigPath(configPath, profile, info.IsDir())
+		profileInfo, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("config for profile %q not found: %s", profile, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat config path %s: %w", path, err)
+		}
+
+		profileFiles, err := configFiles(path, profileInfo.IsDir())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, profileFiles...)
+	}
+
+	var merged map[string]interface{}
+	result := &layeredConfig{sources: make(map[string]string), encrypted: make(map[string]bool)}
+	for _, file := range files {
+		layer, err := readConfigFile(file)
+		if err != nil {
+			// 单个配置文件可以是 gcfg 支持但此处无法解析的格式，此时不记录来源也不解密
+			if single {
+				return newFileConfig(configPath, result)
+			}
+			return nil, err
+		}
+		merged = mergeConfig(merged, layer)
+		recordSources(result.sources, layer, file)
+	}
+
+	if err := decryptConfig("", merged, decrypter, result.encrypted); err != nil {
+		return nil, err
+	}
+
+	// 单个配置文件且不含加密值时直接由 gcfg 加载
+	if single && len(result.encrypted) == 0 {
+		return newFileConfig(configPath, result)
+	}
+
+	content, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+
+	result.adapter, err = gcfg.NewAdapterContent(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config adapter: %w", err)
+	}
+	return result, nil
+}
+
+// newFileConfig 使用 gcfg 文件适配器加载单个配置文件
+func newFileConfig(configPath string, result *layeredConfig) (*layeredConfig, error) {
+	adapter, err := gcfg.NewAdapterFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config adapter: %w", err)
+	}
+	result.adapter = adapter
+	return result, nil
+}
+
+// recordSources 记录配置文件中每个叶子配置键的来源，后加载的文件覆盖先加载的
+func recordSources(sources map[string]string, values map[string]interface{}, file string) {
+	leaves := make(map[string]interface{})
+	flattenConfig("", values, leaves)
+	for key := range leaves {
+		sources[key] = file
+	}
+}
+
+// flattenConfig 将嵌套配置展开为以点分隔的叶子配置键，列表和空映射作为叶子
+func flattenConfig(prefix string, value interface{}, out map[string]interface{}) {
+	m, ok := toStringMap(value)
+	if !ok || (len(m) == 0 && prefix != "") {
+		out[prefix] = value
+		return
+	}
+
+	for key, child := range m {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenConfig(key, child, out)
+	}
+}
+
+// configFiles 返回需要加载的配置文件，目录下的片段按文件名排序，忽略隐藏文件、子目录和不支持的格式
+func configFiles(path string, isDir bool) ([]string, error) {
+	if !isDir {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", path, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if _, ok := configDecoders[strings.ToLower(filepath.Ext(name))]; ok {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files found in directory: %s", path)
+	}
+	return files, nil
+}
+
+// profileConfigPath 返回环境配置路径，如 config.yaml -> config.prod.yaml，conf.d -> conf.d/prod
+func profileConfigPath(configPath, profile string, isDir bool) string {
+	if isDir {
+		return filepath.Join(configPath, profile)
+	}
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + profile + ext
+}
+
+// readConfigFile 按扩展名读取 YAML、JSON 或 TOML 配置文件
+func readConfigFile(path string) (map[string]interface{}, error) {
+	decode, ok := configDecoders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported config file format: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	values := make(map[string]interface{})
+	if err := decode(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestProfileConfigPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		profile string
+		isDir   bool
+		want    string
+	}{
+		{path: "config.yaml", profile: "dev", want: "config.dev.yaml"},
+		{path: "/etc/app/config.toml", profile: "prod", want: "/etc/app/config.prod.toml"},
+		{path: "config", profile: "dev", want: "config.dev"},
+		{path: "/etc/app/conf.d", profile: "prod", isDir: true, want: filepath.Join("/etc/app/conf.d", "prod")},
+	}
+
+	for _, tt := range tests {
+		if got := profileConfigPath(tt.path, tt.profile, tt.isDir); got != tt.want {
+			t.Errorf("profileConfigPath(%q, %q) = %q, want %q", tt.path, tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	want := map[string]interface{}{
+		"framework": map[string]interface{}{"name": "svc"},
+	}
+
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{file: "config.yaml", content: "framework:\n  name: svc\n"},
+		{file: "config.yml", content: "framework:\n  name: svc\n"},
+		{file: "config.json", content: `{"framework": {"name": "svc"}}`},
+		{file: "config.toml", content: "[framework]\nname = \"svc\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			writeConfigFile(t, path, tt.content)
+
+			got, err := readConfigFile(path)
+			if err != nil {
+				t.Fatalf("readConfigFile failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("readConfigFile() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	path := filepath.Join(dir, "config.ini")
+	writeConfigFile(t, path, "name=svc")
+	if _, err := readConfigFile(path); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestConfigFiles_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "20-logging.toml"), "")
+	writeConfigFile(t, filepath.Join(dir, "00-base.yaml"), "")
+	writeConfigFile(t, filepath.Join(dir, "10-network.json"), "{}")
+	writeConfigFile(t, filepath.Join(dir, "README.md"), "")
+	writeConfigFile(t, filepath.Join(dir, ".hidden.yaml"), "")
+	writeConfigFile(t, filepath.Join(dir, "prod", "network.yaml"), "")
+
+	files, err := configFiles(dir, true)
+	if err != nil {
+		t.Fatalf("configFiles failed: %v", err)
+	}
+
+	// 按文件名排序，忽略隐藏文件、子目录和不支持的格式
+	want := []string{
+		filepath.Join(dir, "00-base.yaml"),
+		filepath.Join(dir, "10-network.json"),
+		filepath.Join(dir, "20-logging.toml"),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("configFiles() = %v, want %v", files, want)
+	}
+
+	if _, err := configFiles(t.TempDir(), true); err == nil {
+		t.Error("Expected error for empty directory")
+	}
+}
+
+func TestNewConfigManager_Directory(t *testing.T) {
+	base, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read config.yaml: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "00-base.yaml"), string(base))
+	writeConfigFile(t, filepath.Join(dir, "10-network.json"), `{"framework": {"network": {"port": 9090}}}`)
+	writeConfigFile(t, filepath.Join(dir, "20-logging.toml"), "[framework.observability.logging]\nlevel = \"warn\"\n")
+	writeConfigFile(t, filepath.Join(dir, "prod", "network.yaml"), "framework:\n  network:\n    port: 443\n")
+
+	cm, err := NewConfigManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if port := cm.GetInt("framework.network.port"); port != 9090 {
+		t.Errorf("Expected port 9090 from JSON fragment, got %d", port)
+	}
+	if level := cm.GetString("framework.observability.logging.level"); level != "warn" {
+		t.Errorf("Expected level warn from TOML fragment, got %s", level)
+	}
+	if name := cm.GetString("framework.name"); name != "golang-service" {
+		t.Errorf("Expected name from base fragment, got %s", name)
+	}
+
+	// 环境子目录中的片段最后合并
+	cm, err = NewConfigManagerWithOptions(dir, &Options{Profiles: []string{"prod"}})
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if port := cm.GetInt("framework.network.port"); port != 443 {
+		t.Errorf("Expected port 443 from prod profile, got %d", port)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 配置分层
+//
+// 配置值按以下优先级从低到高覆盖，同一配置键以优先级最高的来源为准：
+//
+//  1. 基础配置文件，如 config.yaml，或 conf.d 目录下按文件名排序的配置片段
+//  2. 环境配置文件，如 config.dev.yaml、config.prod.yaml 或 conf.d/prod/，按 FRAMEWORK_PROFILE 中的顺序依次合并
+//  3. 环境变量，如 FRAMEWORK_NETWORK_PORT（前缀可通过 Options.EnvPrefix 修改）
+//  4. 命令行参数，如 -set framework.network.port=9090
+//
+// 配置文件在加载时深度合并：映射逐键合并，列表和标量整体替换。
+// 环境变量和命令行参数在读取时由 lookupOverride 统一处理。
+
+// EnvProfile 选择环境配置的环境变量，多个环境以逗号分隔，如 FRAMEWORK_PROFILE=prod,eu
+const EnvProfile = "FRAMEWORK_PROFILE"
+
+// Options 配置加载选项
+type Options struct {
+	// Profiles 环境列表，为空时读取 FRAMEWORK_PROFILE
+	Profiles []string
+	// Overrides 命令行覆盖的配置，键为配置路径，优先级高于环境变量
+	Overrides map[string]string
+	// Decrypter 加密配置值的解密器，为空时使用 FRAMEWORK_CONFIG_KEY 中的 AES 密钥
+	Decrypter Decrypter
+	// EnvPrefix 环境变量前缀，替换配置键开头的 framework，为空时使用 FRAMEWORK，命名规则见 env.go
+	EnvPrefix string
+	// SkipValidation 加载时不按 FrameworkSchema 验证，由调用方调用 Validate 获取全部错误，如 frameworkctl check
+	SkipValidation bool
+}
+
+// FlagOverrides 命令行配置覆盖，实现 flag.Value，可重复指定：
+//
+//	overrides := config.FlagOverrides{}
+//	flag.Var(overrides, "set", "override config value, e.g. -set framework.network.port=9090")
+//	flag.Parse()
+//	cm, err := config.NewConfigManagerWithOptions("config.yaml", &config.Options{Overrides: overrides})
+type FlagOverrides map[string]string
+
+// String 实现 flag.Value
+func (f FlagOverrides) String() string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+f[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set 实现 flag.Value，解析 key=value
+func (f FlagOverrides) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("invalid config override %q, expected key=value", value)
+	}
+	f[key] = val
+	return nil
+}
+
+// ProfilesFromEnv 从 FRAMEWORK_PROFILE 读取环境列表
+func ProfilesFromEnv() []string {
+	return splitProfiles(os.Getenv(EnvProfile))
+}
+
+// splitProfiles 解析逗号分隔的环境列表
+func splitProfiles(value string) []string {
+	var profiles []string
+	for _, profile := range strings.Split(value, ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// mergeConfig 将 override 深度合并到 base，映射逐键合并，其他值整体替换
+func mergeConfig(base, override map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{}, len(override))
+	}
+
+	for key, value := range override {
+		overrideMap, ok := toStringMap(value)
+		if !ok || value == nil {
+			base[key] = value
+			continue
+		}
+		baseMap, _ := toStringMap(base[key])
+		base[key] = mergeConfig(baseMap, overrideMap)
+	}
+	return base
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMergeConfig(t *testing.T) {
+	base := map[string]interface{}{
+		"framework": map[string]interface{}{
+			"name": "svc",
+			"network": map[string]interface{}{
+				"host": "0.0.0.0",
+				"port": 8081,
+			},
+			"registry": map[string]interface{}{
+				"endpoints": []interface{}{"a", "b"},
+			},
+		},
+	}
+	override := map[string]interface{}{
+		"framework": map[string]interface{}{
+			"network": map[string]interface{}{
+				"port": 9090,
+			},
+			"registry": map[string]interface{}{
+				"endpoints": []interface{}{"c"},
+			},
+		},
+	}
+
+	want := map[string]interface{}{
+		"framework": map[string]interface{}{
+			"name": "svc",
+			"network": map[string]interface{}{
+				"host": "0.0.0.0",
+				"port": 9090,
+			},
+			"registry": map[string]interface{}{
+				// 列表整体替换
+				"endpoints": []interface{}{"c"},
+			},
+		},
+	}
+
+	if got := mergeConfig(base, override); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeConfig() = %v, want %v", got, want)
+	}
+}
+
+func TestFlagOverrides(t *testing.T) {
+	overrides := FlagOverrides{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(overrides, "set", "override config value")
+
+	err := fs.Parse([]string{"-set", "framework.network.port=9090", "-set", "framework.name=a=b"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := FlagOverrides{"framework.network.port": "9090", "framework.name": "a=b"}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Errorf("overrides = %v, want %v", overrides, want)
+	}
+
+	if err := overrides.Set("invalid"); err == nil {
+		t.Error("Expected error for value without '='")
+	}
+}
+
+func TestNewConfigManager_Profile(t *testing.T) {
+	os.Setenv(EnvProfile, "dev")
+	defer os.Unsetenv(EnvProfile)
+
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	if !reflect.DeepEqual(cm.Profiles(), []string{"dev"}) {
+		t.Errorf("Expected profiles [dev], got %v", cm.Profiles())
+	}
+	// 环境配置覆盖基础配置
+	if port := cm.GetInt("framework.network.port"); port != 18081 {
+		t.Errorf("Expected port 18081 from profile, got %d", port)
+	}
+	if level := cm.GetString("framework.observability.logging.level"); level != "debug" {
+		t.Errorf("Expected level debug from profile, got %s", level)
+	}
+	// 未覆盖的值保留基础配置
+	if host := cm.GetString("framework.network.host"); host != "0.0.0.0" {
+		t.Errorf("Expected host from base config, got %s", host)
+	}
+}
+
+func TestNewConfigManager_MissingProfile(t *testing.T) {
+	_, err := NewConfigManagerWithOptions("config.yaml", &Options{Profiles: []string{"nonexistent"}})
+	if err == nil {
+		t.Error("Expected error for missing profile config file")
+	}
+}
+
+func TestConfigManager_Precedence(t *testing.T) {
+	os.Setenv("FRAMEWORK_NETWORK_PORT", "9090")
+	os.Setenv("FRAMEWORK_NETWORK_HOST", "127.0.0.1")
+	defer func() {
+		os.Unsetenv("FRAMEWORK_NETWORK_PORT")
+		os.Unsetenv("FRAMEWORK_NETWORK_HOST")
+	}()
+
+	cm, err := NewConfigManagerWithOptions("config.yaml", &Options{
+		Profiles:  []string{"dev"},
+		Overrides: map[string]string{"framework.network.port": "7070"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	// 命令行参数 > 环境变量 > 环境配置文件 > 基础配置文件
+	if port := cm.GetInt("framework.network.port"); port != 7070 {
+		t.Errorf("Expected port 7070 from flags, got %d", port)
+	}
+	if host := cm.GetString("framework.network.host"); host != "127.0.0.1" {
+		t.Errorf("Expected host 127.0.0.1 from env, got %s", host)
+	}
+	if level := cm.GetString("framework.observability.logging.level"); level != "debug" {
+		t.Errorf("Expected level debug from profile, got %s", level)
+	}
+	if name := cm.GetString("framework.name"); name != "golang-service" {
+		t.Errorf("Expected name from base config, got %s", name)
+	}
+}
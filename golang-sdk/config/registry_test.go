@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestLoadFrameworkConfig_RegistryMirrors(t *testing.T) {
+	path := configDirWith(t, `framework:
+  registry:
+    type: etcd
+    endpoints: [etcd-a:2379]
+    quorum: 2
+    repairInterval: 5
+    mirrors:
+      - type: etcd
+        endpoints: [etcd-b:2379]
+        namespace: /mirror
+      - type: memory
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	if fc.Registry.Quorum != 2 || fc.Registry.RepairInterval != 5 {
+		t.Errorf("Unexpected quorum or repair interval: %+v", fc.Registry)
+	}
+	mirrors := fc.Registry.Mirrors
+	if len(mirrors) != 2 || mirrors[0].Type != "etcd" || len(mirrors[0].Endpoints) != 1 || mirrors[0].Endpoints[0] != "etcd-b:2379" ||
+		mirrors[0].Namespace != "/mirror" || mirrors[1].Type != "memory" {
+		t.Errorf("Unexpected mirrors: %+v", mirrors)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/cron"
+)
+
+// maxRouteDuration cron 窗口持续时间的上限，与 router.MaxScheduleDuration 一致
+const maxRouteDuration = 31 * 24 * time.Hour
+
+// RouteConfig 客户端调用的路由规则，在生效窗口内将调用重定向到另一个服务，窗口结束后自动恢复，
+// 用于提前安排维护期间的重定向和区域切换
+//
+//	framework:
+//	  routes:
+//	    - name: orders-maintenance
+//	      service: order-service
+//	      target: order-service-dr
+//	      start: 2024-06-01T02:00:00+08:00
+//	      end: 2024-06-01T04:00:00+08:00
+//	    - name: weekly-failover
+//	      service: payment-service
+//	      methods: [charge, refund]
+//	      target: payment-service-backup
+//	      cron: "0 2 * * SUN"
+//	      duration: 2h
+//	      timezone: Asia/Shanghai
+type RouteConfig struct {
+	Name     string   `json:"name,omitempty" config:"name"`
+	Service  string   `json:"service" config:"service"`
+	Methods  []string `json:"methods,omitempty" config:"methods"` // 为空时匹配服务的所有方法
+	Target   string   `json:"target" config:"target"`
+	Priority int      `json:"priority,omitempty" config:"priority"` // 数字越大越先匹配，相同时按配置顺序
+	// Start 和 End 为 RFC 3339 时间，限定规则生效的时间范围（左闭右开），不设置时不限
+	Start time.Time `json:"start,omitempty" config:"start"`
+	End   time.Time `json:"end,omitempty" config:"end"`
+	// Cron 不为空时规则只在每次触发后的 Duration 内生效，按 Timezone 计算，Timezone 为空时使用本地时区
+	Cron     string        `json:"cron,omitempty" config:"cron"`
+	Duration time.Duration `json:"duration,omitempty" config:"duration"`
+	Timezone string        `json:"timezone,omitempty" config:"timezone"`
+}
+
+// loadRoutes 解码 framework.routes 并校验各条规则
+func (cm *ConfigManager) loadRoutes() ([]RouteConfig, error) {
+	var section struct {
+		Routes []RouteConfig `config:"routes"`
+	}
+	if err := cm.UnmarshalKey("framework", &section); err != nil {
+		return nil, err
+	}
+
+	for i, route := range section.Routes {
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("framework.routes[%d].%w", i, err)
+		}
+	}
+	return section.Routes, nil
+}
+
+// validate 校验路由规则，错误信息以相对配置路径开头
+func (r *RouteConfig) validate() error {
+	if r.Service == "" {
+		return fmt.Errorf("service is required")
+	}
+	if r.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+	if r.Target == r.Service {
+		return fmt.Errorf("target must differ from service %s", r.Service)
+	}
+	if !r.Start.IsZero() && !r.End.IsZero() && !r.End.After(r.Start) {
+		return fmt.Errorf("end must be after start")
+	}
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+	if r.Cron == "" {
+		if r.Duration != 0 {
+			return fmt.Errorf("duration requires cron")
+		}
+		return nil
+	}
+	if _, err := cron.Parse(r.Cron); err != nil {
+		return fmt.Errorf("cron: %w", err)
+	}
+	if r.Duration <= 0 || r.Duration > maxRouteDuration {
+		return fmt.Errorf("duration must be positive and at most %s when cron is set", maxRouteDuration)
+	}
+	return nil
+}
+
+// Location 返回计算 Cron 使用的时区，Timezone 为空或无效时返回本地时区
+func (r *RouteConfig) Location() *time.Location {
+	if r.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_Routes(t *testing.T) {
+	path := configDirWith(t, `framework:
+  routes:
+    - name: orders-maintenance
+      service: order-service
+      target: order-service-dr
+      start: 2024-06-01T02:00:00+08:00
+      end: "2024-06-01T04:00:00+08:00"
+    - name: weekly-failover
+      service: payment-service
+      methods: [charge, refund]
+      target: payment-service-backup
+      priority: 5
+      cron: "0 2 * * SUN"
+      duration: 2h
+      timezone: Asia/Shanghai
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	if len(fc.Routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %+v", fc.Routes)
+	}
+	maintenance := fc.Routes[0]
+	wantStart := time.Date(2024, 5, 31, 18, 0, 0, 0, time.UTC)
+	if maintenance.Target != "order-service-dr" || !maintenance.Start.Equal(wantStart) || !maintenance.End.Equal(wantStart.Add(2*time.Hour)) {
+		t.Errorf("Unexpected route: %+v", maintenance)
+	}
+	failover := fc.Routes[1]
+	if failover.Cron != "0 2 * * SUN" || failover.Duration != 2*time.Hour || failover.Priority != 5 || len(failover.Methods) != 2 {
+		t.Errorf("Unexpected route: %+v", failover)
+	}
+	if loc := failover.Location(); loc.String() != "Asia/Shanghai" {
+		t.Errorf("Expected Asia/Shanghai, got %s", loc)
+	}
+}
+
+func TestLoadFrameworkConfig_InvalidRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   string
+		wantErr string
+	}{
+		{name: "missing service", route: "target: b", wantErr: "framework.routes[0].service is required"},
+		{name: "missing target", route: "service: a", wantErr: "framework.routes[0].target is required"},
+		{name: "same target", route: "service: a\n      target: a", wantErr: "target must differ"},
+		{name: "end before start", route: "service: a\n      target: b\n      start: 2024-06-01T04:00:00Z\n      end: 2024-06-01T02:00:00Z", wantErr: "end must be after start"},
+		{name: "invalid cron", route: "service: a\n      target: b\n      cron: \"0 2 * *\"\n      duration: 1h", wantErr: "framework.routes[0].cron"},
+		{name: "cron without duration", route: "service: a\n      target: b\n      cron: \"@daily\"", wantErr: "duration must be positive"},
+		{name: "duration without cron", route: "service: a\n      target: b\n      duration: 1h", wantErr: "duration requires cron"},
+		{name: "unknown timezone", route: "service: a\n      target: b\n      timezone: Mars/Olympus", wantErr: "framework.routes[0].timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := configDirWith(t, "framework:\n  routes:\n    - "+tt.route+"\n")
+
+			cm, err := NewConfigManager(path)
+			if err != nil {
+				t.Fatalf("Failed to create config manager: %v", err)
+			}
+			_, err = cm.LoadFrameworkConfig()
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error to mention %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
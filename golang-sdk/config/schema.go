@@ -0,0 +1,361 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldType 配置值类型
+type FieldType int
+
+const (
+	// FieldString 字符串
+	FieldString FieldType = iota
+	// FieldInt 整数
+	FieldInt
+	// FieldFloat 浮点数
+	FieldFloat
+	// FieldBool 布尔值
+	FieldBool
+	// FieldDuration 时间间隔，必须带单位，如 500ms、30s、5m
+	FieldDuration
+	// FieldList 列表，环境变量中以逗号分隔
+	FieldList
+	// FieldByteSize 字节大小，如 512、64KB、10MB
+	FieldByteSize
+)
+
+// String 返回类型名称
+func (t FieldType) String() string {
+	switch t {
+	case FieldInt:
+		return "an integer"
+	case FieldFloat:
+		return "a number"
+	case FieldBool:
+		return "a boolean"
+	case FieldDuration:
+		return "a duration"
+	case FieldList:
+		return "a list"
+	case FieldByteSize:
+		return "a byte size"
+	default:
+		return "a string"
+	}
+}
+
+// SchemaField 单个配置键的校验规则
+type SchemaField struct {
+	// Key 配置路径，如 framework.network.port
+	Key string
+	// Type 值类型
+	Type FieldType
+	// Required 是否必需，空字符串和空列表视为缺失
+	Required bool
+	// Min 数值下限，nil 表示不限制
+	Min *float64
+	// ExclusiveMin 为 true 时要求值严格大于 Min
+	ExclusiveMin bool
+	// Max 数值上限（含），nil 表示不限制
+	Max *float64
+	// Enum 允许的取值
+	Enum []string
+}
+
+// CrossFieldRule 跨字段校验规则，仅在相关配置键自身校验通过后执行
+type CrossFieldRule struct {
+	// Keys 规则涉及的配置路径，错误报告在第一个键上
+	Keys []string
+	// Check 返回违规描述，通过时返回空字符串
+	Check func(cm *ConfigManager) string
+}
+
+// Schema 声明式配置校验规则
+type Schema struct {
+	Fields []SchemaField
+	Rules  []CrossFieldRule
+}
+
+// Bound 返回数值边界指针，用于 SchemaField.Min 和 SchemaField.Max
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// ValidationError 单个配置项的校验错误
+type ValidationError struct {
+	Key     string
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return e.Key + " " + e.Message
+}
+
+// ValidationErrors 全部校验错误
+type ValidationErrors []*ValidationError
+
+// Error 实现 error 接口，逐行列出全部错误
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("%d config errors:", len(e)))
+	for _, err := range e {
+		lines = append(lines, "  - "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FrameworkSchema 返回框架配置的校验规则，可追加业务配置后使用 Schema.Validate 校验
+func FrameworkSchema() *Schema {
+	return &Schema{
+		Fields: []SchemaField{
+			{Key: "framework.name", Required: true},
+			{Key: "framework.version", Required: true},
+			{Key: "framework.language", Required: true},
+			{Key: "framework.network.host", Required: true},
+			{Key: "framework.network.port", Type: FieldInt, Required: true, Min: Bound(1), Max: Bound(65535)},
+			{Key: "framework.network.maxConnections", Type: FieldInt, Required: true, Min: Bound(0), ExclusiveMin: true},
+			{Key: "framework.network.readTimeout", Type: FieldDuration},
+			{Key: "framework.network.writeTimeout", Type: FieldDuration},
+			{Key: "framework.network.keepAlive", Type: FieldBool},
+			{Key: "framework.registry.type", Required: true},
+			{Key: "framework.registry.endpoints", Type: FieldList, Required: true},
+			{Key: "framework.registry.quorum", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.registry.repairInterval", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.connectionPool.maxConnections", Type: FieldInt, Min: Bound(0), ExclusiveMin: true},
+			{Key: "framework.connectionPool.minConnections", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.connectionPool.idleTimeout", Type: FieldDuration},
+			{Key: "framework.connectionPool.maxLifetime", Type: FieldDuration},
+			{Key: "framework.connectionPool.connectionTimeout", Type: FieldDuration},
+			{Key: "framework.observability.logging.level", Required: true, Enum: []string{"debug", "info", "warn", "error"}},
+			{Key: "framework.observability.tracing.samplingRate", Type: FieldFloat, Min: Bound(0), Max: Bound(1)},
+			{Key: "framework.capture.enabled", Type: FieldBool},
+			{Key: "framework.capture.sampleRate", Type: FieldFloat, Min: Bound(0), Max: Bound(1)},
+			{Key: "framework.capture.bufferSize", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.capture.maxPayloadSize", Type: FieldByteSize},
+			{Key: "framework.payloadLog.enabled", Type: FieldBool},
+			{Key: "framework.payloadLog.perMinute", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.payloadLog.maxSize", Type: FieldByteSize},
+			{Key: "framework.acceptQueue.enabled", Type: FieldBool},
+			{Key: "framework.acceptQueue.maxConcurrent", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.acceptQueue.queueSize", Type: FieldInt},
+			{Key: "framework.acceptQueue.maxWait", Type: FieldDuration},
+			{Key: "framework.dns.enabled", Type: FieldBool},
+			{Key: "framework.dns.address"},
+			{Key: "framework.dns.domain"},
+			{Key: "framework.dns.ttl", Type: FieldDuration},
+			{Key: "framework.accounting.enabled", Type: FieldBool},
+			{Key: "framework.accounting.interval", Type: FieldDuration},
+			{Key: "framework.accounting.sink", Enum: []string{AccountingSinkCSV, AccountingSinkHTTP, AccountingSinkKafka}},
+			{Key: "framework.idGenerator.strategy", Enum: []string{IDGeneratorW3C, IDGeneratorUUIDv7, IDGeneratorSnowflake}},
+			{Key: "framework.idGenerator.node", Type: FieldInt, Min: Bound(0), Max: Bound(1023)},
+			{Key: "framework.deadLetter.enabled", Type: FieldBool},
+			{Key: "framework.deadLetter.store", Enum: []string{DeadLetterStoreMemory, DeadLetterStoreFile, DeadLetterStoreRedis}},
+			{Key: "framework.deadLetter.redis.db", Type: FieldInt, Min: Bound(0)},
+			{Key: "framework.catalog.enabled", Type: FieldBool},
+			{Key: "framework.catalog.callTimeout", Type: FieldDuration},
+			{Key: "framework.selfTest.enabled", Type: FieldBool},
+			{Key: "framework.selfTest.gateReadiness", Type: FieldBool},
+			{Key: "framework.selfTest.timeout", Type: FieldDuration},
+			{Key: "framework.selfTest.certMinValidity", Type: FieldDuration},
+		},
+		Rules: []CrossFieldRule{
+			{
+				Keys: []string{"framework.connectionPool.minConnections", "framework.connectionPool.maxConnections"},
+				Check: func(cm *ConfigManager) string {
+					minConns := cm.GetInt("framework.connectionPool.minConnections")
+					maxConns := cm.GetInt("framework.connectionPool.maxConnections")
+					// 未配置 maxConnections 时由 framework.Server 按可用 CPU 数确定，且不小于 minConnections
+					if maxConns > 0 && minConns > maxConns {
+						return fmt.Sprintf("(%d) cannot be greater than maxConnections (%d)", minConns, maxConns)
+					}
+					return ""
+				},
+			},
+			{
+				Keys: []string{"framework.accounting.sink", "framework.accounting.enabled"},
+				Check: func(cm *ConfigManager) string {
+					if !cm.GetBool("framework.accounting.enabled") {
+						return ""
+					}
+					target := map[string]string{
+						AccountingSinkCSV:   "file",
+						AccountingSinkHTTP:  "url",
+						AccountingSinkKafka: "topic",
+					}
+					sink := cm.GetString("framework.accounting.sink")
+					key, ok := target[sink]
+					if !ok {
+						return "is required when accounting is enabled (csv, http or kafka)"
+					}
+					if cm.GetString("framework.accounting."+key) == "" {
+						return fmt.Sprintf("%s requires framework.accounting.%s", sink, key)
+					}
+					return ""
+				},
+			},
+			{
+				Keys: []string{"framework.deadLetter.dir", "framework.deadLetter.store", "framework.deadLetter.enabled"},
+				Check: func(cm *ConfigManager) string {
+					if cm.GetBool("framework.deadLetter.enabled") && cm.GetString("framework.deadLetter.store") == DeadLetterStoreFile &&
+						cm.GetString("framework.deadLetter.dir") == "" {
+						return "is required when the dead letter store is file"
+					}
+					return ""
+				},
+			},
+		},
+	}
+}
+
+// Validate 按规则校验配置，返回包含全部违规项的 ValidationErrors，通过时返回 nil
+func (s *Schema) Validate(cm *ConfigManager) error {
+	var errs ValidationErrors
+	failed := make(map[string]bool)
+
+	for _, field := range s.Fields {
+		if msg := field.validate(cm); msg != "" {
+			errs = append(errs, &ValidationError{Key: field.Key, Message: msg})
+			failed[field.Key] = true
+		}
+	}
+
+	for _, rule := range s.Rules {
+		if len(rule.Keys) == 0 || anyFailed(failed, rule.Keys) {
+			continue
+		}
+		if msg := rule.Check(cm); msg != "" {
+			errs = append(errs, &ValidationError{Key: rule.Keys[0], Message: msg})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate 校验单个配置键，返回违规描述
+func (f *SchemaField) validate(cm *ConfigManager) string {
+	if f.Type == FieldList {
+		if f.Required && len(cm.GetStringSlice(f.Key)) == 0 {
+			return "is required"
+		}
+		return ""
+	}
+
+	raw, ok := cm.rawString(f.Key)
+	if !ok || raw == "" {
+		if f.Required {
+			return "is required"
+		}
+		return ""
+	}
+
+	var number float64
+	switch f.Type {
+	case FieldInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Sprintf("must be %s, got %q", f.Type, raw)
+		}
+		number = float64(n)
+	case FieldFloat:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Sprintf("must be %s, got %q", f.Type, raw)
+		}
+		number = n
+	case FieldBool:
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return fmt.Sprintf("must be %s, got %q", f.Type, raw)
+		}
+	case FieldDuration:
+		if _, err := ParseDuration(raw); err != nil {
+			return unitMessage(f.Type, raw, err)
+		}
+	case FieldByteSize:
+		if _, err := ParseByteSize(raw); err != nil {
+			return unitMessage(f.Type, raw, err)
+		}
+	}
+
+	if f.Type == FieldInt || f.Type == FieldFloat {
+		if msg := f.checkRange(number, raw); msg != "" {
+			return msg
+		}
+	}
+
+	if len(f.Enum) > 0 {
+		for _, allowed := range f.Enum {
+			if raw == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s], got %s", strings.Join(f.Enum, ", "), raw)
+	}
+
+	return ""
+}
+
+// checkRange 校验数值范围
+func (f *SchemaField) checkRange(n float64, raw string) string {
+	belowMin := f.Min != nil && (n < *f.Min || (f.ExclusiveMin && n == *f.Min))
+	aboveMax := f.Max != nil && n > *f.Max
+	if !belowMin && !aboveMax {
+		return ""
+	}
+
+	switch {
+	case f.Min != nil && f.Max != nil:
+		return fmt.Sprintf("must be between %v and %v, got %s", *f.Min, *f.Max, raw)
+	case f.Max != nil:
+		return fmt.Sprintf("must be at most %v, got %s", *f.Max, raw)
+	case *f.Min == 0 && f.ExclusiveMin:
+		return fmt.Sprintf("must be positive, got %s", raw)
+	case *f.Min == 0:
+		return fmt.Sprintf("must be non-negative, got %s", raw)
+	case f.ExclusiveMin:
+		return fmt.Sprintf("must be greater than %v, got %s", *f.Min, raw)
+	default:
+		return fmt.Sprintf("must be at least %v, got %s", *f.Min, raw)
+	}
+}
+
+// unitMessage 生成时间间隔或字节大小的违规描述，附带期望的写法
+func unitMessage(t FieldType, raw string, err error) string {
+	if ue, ok := err.(*unitError); ok {
+		return fmt.Sprintf("must be %s, got %q: %s", t, raw, ue.hint)
+	}
+	return fmt.Sprintf("must be %s, got %q", t, raw)
+}
+
+// anyFailed 检查配置键是否已有校验错误
+func anyFailed(failed map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if failed[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// rawString 获取配置的原始字符串值，命令行参数和环境变量优先
+func (cm *ConfigManager) rawString(pattern string) (string, bool) {
+	if value, ok := cm.lookupOverride(pattern); ok {
+		return strings.TrimSpace(value), true
+	}
+
+	v := cm.config.MustGet(context.Background(), pattern)
+	if v == nil || v.IsNil() {
+		return "", false
+	}
+	return strings.TrimSpace(v.String()), true
+}
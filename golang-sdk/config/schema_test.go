@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantKeys  []string
+	}{
+		{
+			name: "valid config",
+		},
+		{
+			name: "all violations reported at once",
+			overrides: map[string]string{
+				"framework.name":                        "",
+				"framework.network.port":                "99999",
+				"framework.observability.logging.level": "verbose",
+			},
+			wantKeys: []string{"framework.name", "framework.network.port", "framework.observability.logging.level"},
+		},
+		{
+			name: "type mismatch",
+			overrides: map[string]string{
+				"framework.network.port":        "http",
+				"framework.network.readTimeout": "soon",
+				"framework.network.keepAlive":   "maybe",
+			},
+			wantKeys: []string{"framework.network.port", "framework.network.readTimeout", "framework.network.keepAlive"},
+		},
+		{
+			name: "cross-field rule",
+			overrides: map[string]string{
+				"framework.connectionPool.minConnections": "200",
+			},
+			wantKeys: []string{"framework.connectionPool.minConnections"},
+		},
+		{
+			name: "cross-field rule skipped when field invalid",
+			overrides: map[string]string{
+				"framework.connectionPool.minConnections": "200",
+				"framework.connectionPool.maxConnections": "0",
+			},
+			wantKeys: []string{"framework.connectionPool.maxConnections"},
+		},
+		{
+			name: "sampling rate out of range",
+			overrides: map[string]string{
+				"framework.observability.tracing.samplingRate": "1.5",
+			},
+			wantKeys: []string{"framework.observability.tracing.samplingRate"},
+		},
+		{
+			name: "invalid capture config",
+			overrides: map[string]string{
+				"framework.capture.sampleRate":     "2",
+				"framework.capture.maxPayloadSize": "large",
+			},
+			wantKeys: []string{"framework.capture.sampleRate", "framework.capture.maxPayloadSize"},
+		},
+		{
+			name: "accounting sink without target",
+			overrides: map[string]string{
+				"framework.accounting.enabled": "true",
+				"framework.accounting.sink":    "http",
+			},
+			wantKeys: []string{"framework.accounting.sink"},
+		},
+		{
+			name: "unknown accounting sink",
+			overrides: map[string]string{
+				"framework.accounting.sink": "s3",
+			},
+			wantKeys: []string{"framework.accounting.sink"},
+		},
+		{
+			name: "invalid ID generator",
+			overrides: map[string]string{
+				"framework.idGenerator.strategy": "ulid",
+				"framework.idGenerator.node":     "2048",
+			},
+			wantKeys: []string{"framework.idGenerator.strategy", "framework.idGenerator.node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, err := NewConfigManager("config.yaml")
+			if err != nil {
+				t.Fatalf("Failed to create config manager: %v", err)
+			}
+			cm.overrides = tt.overrides
+
+			err = FrameworkSchema().Validate(cm)
+			if len(tt.wantKeys) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			if len(errs) != len(tt.wantKeys) {
+				t.Fatalf("Expected %d errors, got %d: %v", len(tt.wantKeys), len(errs), err)
+			}
+			for i, key := range tt.wantKeys {
+				if errs[i].Key != key {
+					t.Errorf("errs[%d].Key = %s, want %s", i, errs[i].Key, key)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	single := ValidationErrors{{Key: "framework.name", Message: "is required"}}
+	if got := single.Error(); got != "framework.name is required" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	multiple := ValidationErrors{
+		{Key: "framework.name", Message: "is required"},
+		{Key: "framework.network.port", Message: "must be between 1 and 65535, got 0"},
+	}
+	got := multiple.Error()
+	if !strings.HasPrefix(got, "2 config errors:") ||
+		!strings.Contains(got, "  - framework.name is required") ||
+		!strings.Contains(got, "  - framework.network.port must be between 1 and 65535, got 0") {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestSchema_CustomFields(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	cm.overrides = map[string]string{"framework.cache.ttl": "0"}
+
+	// 业务配置追加到框架规则之后
+	schema := FrameworkSchema()
+	schema.Fields = append(schema.Fields,
+		SchemaField{Key: "framework.cache.size", Type: FieldInt, Required: true},
+		SchemaField{Key: "framework.cache.ttl", Type: FieldInt, Min: Bound(1)},
+	)
+
+	err = schema.Validate(cm)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "framework.cache.size is required") {
+		t.Errorf("Expected missing key error, got %q", msg)
+	}
+	if !strings.Contains(msg, "framework.cache.ttl must be at least 1, got 0") {
+		t.Errorf("Expected range error, got %q", msg)
+	}
+}
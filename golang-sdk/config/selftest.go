@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// SelfTestConfig 启动自检配置，启用后服务启动时检查注册中心连通性、framework.services 中各服务可被发现、
+// 已启用的序列化格式可以往返编解码以及 TLS 证书有效，结果写入日志、指标和 /health/selftest 端点：
+//
+//	framework:
+//	  selfTest:
+//	    enabled: true
+//	    gateReadiness: true
+//	    certMinValidity: 168h
+type SelfTestConfig struct {
+	Enabled bool `json:"enabled" config:"enabled"`
+	// GateReadiness 为 true 时在注册到注册中心前执行自检，有检查失败时启动失败；否则在启动完成后于后台执行，只报告结果
+	GateReadiness bool          `json:"gateReadiness,omitempty" config:"gateReadiness"`
+	Timeout       time.Duration `json:"timeout,omitempty" config:"timeout"` // 单个检查的超时，为 0 时使用 observability.DefaultSelfTestTimeout
+	// CertMinValidity TLS 证书至少还需有效的时间，为 0 时只检查证书当前有效
+	CertMinValidity time.Duration `json:"certMinValidity,omitempty" config:"certMinValidity"`
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadFrameworkConfig_SelfTest(t *testing.T) {
+	path := configDirWith(t, `framework:
+  selfTest:
+    enabled: true
+    gateReadiness: true
+    timeout: 5s
+    certMinValidity: 168h
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+	want := SelfTestConfig{Enabled: true, GateReadiness: true, Timeout: 5 * time.Second, CertMinValidity: 168 * time.Hour}
+	if fc.SelfTest != want {
+		t.Errorf("SelfTest = %+v, want %+v", fc.SelfTest, want)
+	}
+}
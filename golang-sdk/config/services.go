@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ServiceConfig 调用单个目标服务时的覆盖配置，零值字段沿用全局配置
+//
+//	framework:
+//	  services:
+//	    payment-service:
+//	      timeout: 2s
+//	      protocol: gRPC
+//	      retry:
+//	        maxAttempts: 5
+//	        initialDelay: 50ms
+//	      connectionPool:
+//	        maxConnections: 20
+type ServiceConfig struct {
+	Timeout        time.Duration        `json:"timeout,omitempty" config:"timeout"`
+	Protocol       string               `json:"protocol,omitempty" config:"protocol"` // 首选协议，存在该协议的端点时优先路由；MQ 表示经消息中间件调用
+	Retry          RetryConfig          `json:"retry,omitempty" config:"retry"`
+	ConnectionPool ConnectionPoolConfig `json:"connectionPool,omitempty" config:"connectionPool"`
+	// AdaptiveTimeout 按各方法的历史延迟设置每次调用的超时
+	AdaptiveTimeout AdaptiveTimeoutConfig `json:"adaptiveTimeout,omitempty" config:"adaptiveTimeout"`
+	// IdempotentMethods 调用方声明的幂等方法（完整方法名），与服务实例注册时声明的幂等方法一起决定哪些方法可以自动重试
+	IdempotentMethods []string `json:"idempotentMethods,omitempty" config:"idempotentMethods"`
+	// DarkLaunch 按比例将调用同时发给候选服务并比较响应，用于验证用其他语言重写的服务
+	DarkLaunch DarkLaunchConfig `json:"darkLaunch,omitempty" config:"darkLaunch"`
+}
+
+// DarkLaunchConfig 暗发布比较配置，candidate 为空时不启用；调用方始终收到原服务的响应，
+// 候选服务的请求会被处理两次，只应对只读方法或候选服务连接隔离数据存储时启用：
+//
+//	framework:
+//	  services:
+//	    order-service:
+//	      darkLaunch:
+//	        candidate: order-service-go
+//	        sampleRate: 0.05
+//	        methods: [order.get, order.list]
+//	        ignoreFields: [requestId, generatedAt]
+//	        timeout: 2s
+type DarkLaunchConfig struct {
+	Candidate    string        `json:"candidate,omitempty" config:"candidate"`
+	SampleRate   float64       `json:"sampleRate,omitempty" config:"sampleRate"`     // 为 0 时使用 client.DefaultDarkLaunchSampleRate
+	Methods      []string      `json:"methods,omitempty" config:"methods"`           // 为空时比较所有方法
+	IgnoreFields []string      `json:"ignoreFields,omitempty" config:"ignoreFields"` // 比较时忽略的字段名（任意层级）
+	Timeout      time.Duration `json:"timeout,omitempty" config:"timeout"`           // 候选调用的超时
+}
+
+// AdaptiveTimeoutConfig 自适应超时配置，每次调用的超时为最近调用延迟的 percentile 分位数乘以 multiplier，
+// 限制在 [floor, ceiling] 内；未设置的字段使用 resilience 的默认值（P99、1.5 倍、100ms~30s）
+type AdaptiveTimeoutConfig struct {
+	Enabled    bool          `json:"enabled,omitempty" config:"enabled"`
+	Percentile float64       `json:"percentile,omitempty" config:"percentile"`
+	Multiplier float64       `json:"multiplier,omitempty" config:"multiplier"`
+	Floor      time.Duration `json:"floor,omitempty" config:"floor"`
+	Ceiling    time.Duration `json:"ceiling,omitempty" config:"ceiling"`
+}
+
+// RetryConfig 重试配置，MaxAttempts 为 0 时表示未配置
+type RetryConfig struct {
+	MaxAttempts  int           `json:"maxAttempts,omitempty" config:"maxAttempts"`
+	InitialDelay time.Duration `json:"initialDelay,omitempty" config:"initialDelay"`
+	MaxDelay     time.Duration `json:"maxDelay,omitempty" config:"maxDelay"`
+	Multiplier   float64       `json:"multiplier,omitempty" config:"multiplier"`
+	// NonIdempotent 为 true 时同样重试未声明幂等的方法，默认只重试幂等方法
+	NonIdempotent bool `json:"nonIdempotent,omitempty" config:"nonIdempotent"`
+}
+
+// serviceProtocols 服务覆盖配置允许的首选协议
+var serviceProtocols = []string{"gRPC", "JSON-RPC", "REST", "WebSocket", "MQTT", "InternalRPC", "CustomBinary", "MQ"}
+
+// Service 返回调用 name 服务的生效配置：未覆盖的连接池字段沿用 framework.connectionPool
+func (c *FrameworkConfig) Service(name string) ServiceConfig {
+	service := c.Services[name]
+	service.ConnectionPool = mergePoolConfig(c.ConnectionPool, service.ConnectionPool)
+	return service
+}
+
+// ServiceNames 返回配置了覆盖项的服务名称，按名称排序
+func (c *FrameworkConfig) ServiceNames() []string {
+	names := make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadServices 解码 framework.services 并校验各服务的覆盖配置
+func (cm *ConfigManager) loadServices() (map[string]ServiceConfig, error) {
+	var section struct {
+		Services map[string]ServiceConfig `config:"services"`
+	}
+	if err := cm.UnmarshalKey("framework", &section); err != nil {
+		return nil, err
+	}
+
+	for name, service := range section.Services {
+		if err := service.validate(); err != nil {
+			return nil, fmt.Errorf("framework.services.%s.%w", name, err)
+		}
+	}
+	return section.Services, nil
+}
+
+// validate 校验覆盖配置，错误信息以相对配置路径开头
+func (s *ServiceConfig) validate() error {
+	if s.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %v", s.Timeout)
+	}
+	if s.Protocol != "" && !containsString(serviceProtocols, s.Protocol) {
+		return fmt.Errorf("protocol must be one of %v, got %s", serviceProtocols, s.Protocol)
+	}
+	if s.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("retry.maxAttempts must be non-negative, got %d", s.Retry.MaxAttempts)
+	}
+	if s.Retry.Multiplier != 0 && s.Retry.Multiplier < 1 {
+		return fmt.Errorf("retry.multiplier must be at least 1, got %v", s.Retry.Multiplier)
+	}
+	if p := s.AdaptiveTimeout.Percentile; p < 0 || p > 1 {
+		return fmt.Errorf("adaptiveTimeout.percentile must be between 0 and 1, got %v", p)
+	}
+	if m := s.AdaptiveTimeout.Multiplier; m != 0 && m < 1 {
+		return fmt.Errorf("adaptiveTimeout.multiplier must be at least 1, got %v", m)
+	}
+	if floor, ceiling := s.AdaptiveTimeout.Floor, s.AdaptiveTimeout.Ceiling; floor < 0 || ceiling < 0 || (ceiling > 0 && floor > ceiling) {
+		return fmt.Errorf("adaptiveTimeout.floor (%v) and ceiling (%v) must be non-negative with floor <= ceiling", floor, ceiling)
+	}
+	if r := s.DarkLaunch.SampleRate; r < 0 || r > 1 {
+		return fmt.Errorf("darkLaunch.sampleRate must be between 0 and 1, got %v", r)
+	}
+	if s.ConnectionPool.MaxConnections < 0 {
+		return fmt.Errorf("connectionPool.maxConnections must be non-negative, got %d", s.ConnectionPool.MaxConnections)
+	}
+	if s.ConnectionPool.MinConnections < 0 {
+		return fmt.Errorf("connectionPool.minConnections must be non-negative, got %d", s.ConnectionPool.MinConnections)
+	}
+	if max := s.ConnectionPool.MaxConnections; max > 0 && s.ConnectionPool.MinConnections > max {
+		return fmt.Errorf("connectionPool.minConnections (%d) cannot be greater than maxConnections (%d)",
+			s.ConnectionPool.MinConnections, max)
+	}
+	return nil
+}
+
+// mergePoolConfig 用覆盖配置中的非零字段替换全局连接池配置
+func mergePoolConfig(base, override ConnectionPoolConfig) ConnectionPoolConfig {
+	if override.MaxConnections > 0 {
+		base.MaxConnections = override.MaxConnections
+	}
+	if override.MinConnections > 0 {
+		base.MinConnections = override.MinConnections
+	}
+	if override.IdleTimeout > 0 {
+		base.IdleTimeout = override.IdleTimeout
+	}
+	if override.MaxLifetime > 0 {
+		base.MaxLifetime = override.MaxLifetime
+	}
+	if override.ConnectionTimeout > 0 {
+		base.ConnectionTimeout = override.ConnectionTimeout
+	}
+	return base
+}
+
+// containsString 检查列表是否包含 s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
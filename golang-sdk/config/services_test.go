@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configDirWith 创建以 config.yaml 为基础配置、fragment 为追加片段的配置目录
+func configDirWith(t *testing.T, fragment string) string {
+	t.Helper()
+	base, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read config.yaml: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "00-base.yaml"), string(base))
+	writeConfigFile(t, filepath.Join(dir, "10-test.yaml"), fragment)
+	return dir
+}
+
+func TestLoadFrameworkConfig_Services(t *testing.T) {
+	path := configDirWith(t, `framework:
+  services:
+    payment-service:
+      timeout: 2s
+      protocol: gRPC
+      retry:
+        maxAttempts: 5
+        initialDelay: 50ms
+        multiplier: 2
+        nonIdempotent: true
+      idempotentMethods: [payment.getOrder, payment.listOrders]
+      connectionPool:
+        maxConnections: 20
+      adaptiveTimeout:
+        enabled: true
+        percentile: 0.95
+        ceiling: 3s
+      darkLaunch:
+        candidate: payment-service-go
+        sampleRate: 0.05
+        methods: [payment.getOrder]
+        ignoreFields: [requestId]
+        timeout: 1s
+    inventory:
+      timeout: 500ms
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	if names := fc.ServiceNames(); strings.Join(names, ",") != "inventory,payment-service" {
+		t.Errorf("Expected sorted service names, got %v", names)
+	}
+
+	payment := fc.Service("payment-service")
+	if payment.Timeout != 2*time.Second || payment.Protocol != "gRPC" {
+		t.Errorf("Unexpected payment overrides: %+v", payment)
+	}
+	if payment.Retry.MaxAttempts != 5 || payment.Retry.InitialDelay != 50*time.Millisecond || payment.Retry.Multiplier != 2 ||
+		!payment.Retry.NonIdempotent {
+		t.Errorf("Unexpected retry config: %+v", payment.Retry)
+	}
+	if strings.Join(payment.IdempotentMethods, ",") != "payment.getOrder,payment.listOrders" {
+		t.Errorf("Unexpected idempotent methods: %v", payment.IdempotentMethods)
+	}
+	if at := payment.AdaptiveTimeout; !at.Enabled || at.Percentile != 0.95 || at.Ceiling != 3*time.Second || at.Floor != 0 {
+		t.Errorf("Unexpected adaptive timeout config: %+v", at)
+	}
+	if dl := payment.DarkLaunch; dl.Candidate != "payment-service-go" || dl.SampleRate != 0.05 || dl.Timeout != time.Second ||
+		strings.Join(dl.Methods, ",") != "payment.getOrder" || strings.Join(dl.IgnoreFields, ",") != "requestId" {
+		t.Errorf("Unexpected dark launch config: %+v", dl)
+	}
+	// 未覆盖的连接池字段沿用全局配置
+	if payment.ConnectionPool.MaxConnections != 20 || payment.ConnectionPool.MinConnections != 10 ||
+		payment.ConnectionPool.IdleTimeout != 5*time.Minute {
+		t.Errorf("Unexpected pool config: %+v", payment.ConnectionPool)
+	}
+
+	// 未配置的服务使用全局配置
+	other := fc.Service("unknown")
+	if other.Timeout != 0 || other.ConnectionPool.MaxConnections != 100 {
+		t.Errorf("Expected global defaults for unknown service, got %+v", other)
+	}
+}
+
+func TestLoadFrameworkConfig_InvalidService(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		wantErr string
+	}{
+		{name: "invalid duration", service: "timeout: fast", wantErr: "framework.services.payment.timeout"},
+		{name: "unknown protocol", service: "protocol: SOAP", wantErr: "framework.services.payment.protocol must be one of"},
+		{name: "negative attempts", service: "retry:\n        maxAttempts: -1", wantErr: "framework.services.payment.retry.maxAttempts"},
+		{name: "invalid percentile", service: "adaptiveTimeout:\n        percentile: 99", wantErr: "framework.services.payment.adaptiveTimeout.percentile"},
+		{name: "floor above ceiling", service: "adaptiveTimeout:\n        floor: 2s\n        ceiling: 1s", wantErr: "framework.services.payment.adaptiveTimeout.floor"},
+		{name: "invalid dark launch sample rate", service: "darkLaunch:\n        candidate: payment-go\n        sampleRate: 5", wantErr: "framework.services.payment.darkLaunch.sampleRate"},
+		{name: "min above max", service: "connectionPool:\n        maxConnections: 2\n        minConnections: 5", wantErr: "framework.services.payment.connectionPool.minConnections"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := configDirWith(t, "framework:\n  services:\n    payment:\n      "+tt.service+"\n")
+
+			cm, err := NewConfigManager(path)
+			if err != nil {
+				t.Fatalf("Failed to create config manager: %v", err)
+			}
+			_, err = cm.LoadFrameworkConfig()
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error to mention %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadFrameworkConfig_SamplingRate 测试未配置采样率时为 nil，配置为 0 时保留 0
+func TestLoadFrameworkConfig_SamplingRate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want *float64
+	}{
+		{"未配置", "framework:\n  observability:\n    tracing:\n      enabled: true\n", nil},
+		{"不采样", "framework:\n  observability:\n    tracing:\n      samplingRate: 0\n", Bound(0)},
+		{"部分采样", "framework:\n  observability:\n    tracing:\n      samplingRate: 0.25\n", Bound(0.25)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 基础配置文件去掉采样率后与测试的配置合并
+			base, err := os.ReadFile("config.yaml")
+			if err != nil {
+				t.Fatalf("Failed to read config.yaml: %v", err)
+			}
+			lines := strings.Split(string(base), "\n")
+			kept := lines[:0]
+			for _, line := range lines {
+				if !strings.Contains(line, "samplingRate:") {
+					kept = append(kept, line)
+				}
+			}
+			dir := t.TempDir()
+			writeConfigFile(t, filepath.Join(dir, "00-base.yaml"), strings.Join(kept, "\n"))
+			writeConfigFile(t, filepath.Join(dir, "10-test.yaml"), tt.yaml)
+			cm, err := NewConfigManager(dir)
+			if err != nil {
+				t.Fatalf("Failed to create config manager: %v", err)
+			}
+			fc, err := cm.LoadFrameworkConfig()
+			if err != nil {
+				t.Fatalf("LoadFrameworkConfig failed: %v", err)
+			}
+			got := fc.Observability.Tracing.SamplingRate
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("SamplingRate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
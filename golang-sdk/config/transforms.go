@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransformConfig 单个服务方法的请求转换规则，用于服务接口不兼容的版本之间迁移
+//
+//	framework:
+//	  transforms:
+//	    - service: user-service
+//	      method: getUser
+//	      path: /api/users/{id:int}
+//	      rename:
+//	        userName: name
+//	      defaults:
+//	        locale: zh-CN
+//	      stripHeaders:
+//	        - X-Legacy-Token
+type TransformConfig struct {
+	Service      string                 `json:"service" config:"service"`
+	Method       string                 `json:"method,omitempty" config:"method"` // 为空或 * 时匹配服务的所有方法
+	Path         string                 `json:"path,omitempty" config:"path"`     // REST 路径模板，路径参数写入负载
+	Rename       map[string]string      `json:"rename,omitempty" config:"rename"`
+	Defaults     map[string]interface{} `json:"defaults,omitempty" config:"defaults"`
+	StripHeaders []string               `json:"stripHeaders,omitempty" config:"stripHeaders"`
+}
+
+// loadTransforms 解码 framework.transforms 并校验各条规则
+func (cm *ConfigManager) loadTransforms() ([]TransformConfig, error) {
+	var section struct {
+		Transforms []TransformConfig `config:"transforms"`
+	}
+	if err := cm.UnmarshalKey("framework", &section); err != nil {
+		return nil, err
+	}
+
+	for i, transform := range section.Transforms {
+		if err := transform.validate(); err != nil {
+			return nil, fmt.Errorf("framework.transforms[%d].%w", i, err)
+		}
+	}
+	return section.Transforms, nil
+}
+
+// validate 校验转换规则，错误信息以相对配置路径开头
+func (t *TransformConfig) validate() error {
+	if t.Service == "" {
+		return fmt.Errorf("service is required")
+	}
+	for from, to := range t.Rename {
+		if to == "" {
+			return fmt.Errorf("rename.%s must not be empty", from)
+		}
+	}
+	if t.Path != "" && !strings.HasPrefix(t.Path, "/") {
+		return fmt.Errorf("path must start with /, got %s", t.Path)
+	}
+	return nil
+}
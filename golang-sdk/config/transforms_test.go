@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadFrameworkConfig_Transforms(t *testing.T) {
+	path := configDirWith(t, `framework:
+  transforms:
+    - service: user-service
+      method: getUser
+      path: /api/users/{id:int}
+      rename:
+        userName: name
+      defaults:
+        locale: zh-CN
+        options:
+          verbose: false
+      stripHeaders:
+        - X-Legacy-Token
+    - service: order-service
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	fc, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		t.Fatalf("LoadFrameworkConfig failed: %v", err)
+	}
+
+	if len(fc.Transforms) != 2 {
+		t.Fatalf("Expected 2 transforms, got %+v", fc.Transforms)
+	}
+	user := fc.Transforms[0]
+	if user.Service != "user-service" || user.Method != "getUser" || user.Path != "/api/users/{id:int}" {
+		t.Errorf("Unexpected transform: %+v", user)
+	}
+	if user.Rename["userName"] != "name" || user.Defaults["locale"] != "zh-CN" {
+		t.Errorf("Unexpected rename or defaults: %+v", user)
+	}
+	if options, ok := user.Defaults["options"].(map[string]interface{}); !ok || options["verbose"] != false {
+		t.Errorf("Expected nested default, got %#v", user.Defaults["options"])
+	}
+	if len(user.StripHeaders) != 1 || user.StripHeaders[0] != "X-Legacy-Token" {
+		t.Errorf("Unexpected strip headers: %v", user.StripHeaders)
+	}
+}
+
+func TestLoadFrameworkConfig_InvalidTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform string
+		wantErr   string
+	}{
+		{name: "missing service", transform: "method: getUser", wantErr: "framework.transforms[0].service is required"},
+		{name: "relative path", transform: "service: user\n      path: users/{id}", wantErr: "framework.transforms[0].path must start with /"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := configDirWith(t, "framework:\n  transforms:\n    - "+tt.transform+"\n")
+
+			cm, err := NewConfigManager(path)
+			if err != nil {
+				t.Fatalf("Failed to create config manager: %v", err)
+			}
+			_, err = cm.LoadFrameworkConfig()
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error to mention %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
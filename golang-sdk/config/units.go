@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unitError 时间间隔或字节大小格式错误，hint 说明期望的写法
+type unitError struct {
+	kind  string
+	value string
+	hint  string
+}
+
+// Error 实现 error 接口
+func (e *unitError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.kind, e.value, e.hint)
+}
+
+const (
+	byteSizeHint = `expected e.g. "512", "64KB", "10MB"`
+	durationHint = `expected e.g. "500ms", "2s", "1h"`
+)
+
+// ByteSize 字节大小，支持 512、64KB、10MiB、1.5GB 等写法
+//
+// KB/MB/GB/TB 与 KiB/MiB/GiB/TiB 均按 1024 进制计算
+type ByteSize int64
+
+// 字节大小单位
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+	"T":   1 << 40,
+	"TB":  1 << 40,
+	"TIB": 1 << 40,
+}
+
+// ParseByteSize 解析字节大小
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, &unitError{kind: "byte size", value: s, hint: fmt.Sprintf("unknown unit %q, expected B, KB, MB, GB or TB", s[i:])}
+	}
+	if number == "" {
+		return 0, &unitError{kind: "byte size", value: s, hint: "missing number, " + byteSizeHint}
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, &unitError{kind: "byte size", value: s, hint: byteSizeHint}
+	}
+	return ByteSize(value * float64(multiplier)), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// String 返回可读的字节大小，如 64MB
+func (b ByteSize) String() string {
+	for _, unit := range []struct {
+		name string
+		size ByteSize
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if b >= unit.size && b%unit.size == 0 {
+			return strconv.FormatInt(int64(b/unit.size), 10) + unit.name
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// ParseDuration 严格解析时间间隔，必须带单位，如 500ms、2s、1h30m；仅 0 可以省略单位
+//
+// 与 time.ParseDuration 不同，错误信息会说明期望的写法，便于定位配置问题
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, &unitError{kind: "duration", value: s, hint: "empty value, " + durationHint}
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return 0, &unitError{kind: "duration", value: s, hint: fmt.Sprintf("missing unit, expected e.g. %q or %q", s+"ms", s+"s")}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, &unitError{kind: "duration", value: s, hint: durationHint}
+	}
+	return d, nil
+}
+
+// parseDurationValue 严格解析配置中的时间间隔值，数字只接受 0
+func parseDurationValue(raw interface{}) (time.Duration, error) {
+	return ParseDuration(toString(raw))
+}
+
+// GetDurationE 严格获取时间间隔配置，值缺失时返回 def（未指定时为 0），格式错误时返回包含配置路径的错误
+//
+// GetDuration 对格式错误的值静默返回 0，新代码应优先使用本方法
+func (cm *ConfigManager) GetDurationE(pattern string, def ...time.Duration) (time.Duration, error) {
+	raw, ok := cm.rawString(pattern)
+	if !ok || raw == "" {
+		if len(def) > 0 {
+			return def[0], nil
+		}
+		return 0, nil
+	}
+
+	d, err := ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", pattern, err)
+	}
+	return d, nil
+}
+
+// getOptionalFloat 获取浮点数配置，值缺失或格式错误时返回 nil，用于区分未配置和配置为 0
+func (cm *ConfigManager) getOptionalFloat(pattern string) *float64 {
+	raw, ok := cm.rawString(pattern)
+	if !ok || raw == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// GetByteSize 获取字节大小配置，值缺失或格式错误时返回 def（未指定时为 0）
+func (cm *ConfigManager) GetByteSize(pattern string, def ...ByteSize) ByteSize {
+	size, err := cm.GetByteSizeE(pattern, def...)
+	if err != nil {
+		if len(def) > 0 {
+			return def[0]
+		}
+		return 0
+	}
+	return size
+}
+
+// GetByteSizeE 严格获取字节大小配置，值缺失时返回 def（未指定时为 0），格式错误时返回包含配置路径的错误
+func (cm *ConfigManager) GetByteSizeE(pattern string, def ...ByteSize) (ByteSize, error) {
+	raw, ok := cm.rawString(pattern)
+	if !ok || raw == "" {
+		if len(def) > 0 {
+			return def[0], nil
+		}
+		return 0, nil
+	}
+
+	size, err := ParseByteSize(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", pattern, err)
+	}
+	return size, nil
+}
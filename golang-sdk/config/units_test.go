@@ -0,0 +1,150 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{input: "512", want: 512},
+		{input: "512B", want: 512},
+		{input: "64KB", want: 64 << 10},
+		{input: "10MiB", want: 10 << 20},
+		{input: "1.5GB", want: 3 << 29},
+		{input: "2 tb", want: 2 << 40},
+		{input: "", wantErr: true},
+		{input: "MB", wantErr: true},
+		{input: "10XB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{size: 0, want: "0B"},
+		{size: 512, want: "512B"},
+		{size: 64 << 10, want: "64KB"},
+		{size: 1536, want: "1536B"},
+		{size: 10 << 20, want: "10MB"},
+		{size: 2 << 40, want: "2TB"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %s, want %s", int64(tt.size), got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr string
+	}{
+		{input: "500ms", want: 500 * time.Millisecond},
+		{input: "2s", want: 2 * time.Second},
+		{input: " 1h30m ", want: 90 * time.Minute},
+		{input: "0", want: 0},
+		{input: "30", wantErr: `missing unit, expected e.g. "30ms" or "30s"`},
+		{input: "1.5", wantErr: "missing unit"},
+		{input: "", wantErr: "empty value"},
+		{input: "soon", wantErr: `expected e.g. "500ms", "2s", "1h"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseDuration(%q) error = %v, want containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigManager_StrictGetters(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	cm.overrides = map[string]string{
+		"framework.cache.timeout": "30",
+		"framework.cache.maxSize": "64KB",
+		"framework.cache.minSize": "lots",
+	}
+
+	if d, err := cm.GetDurationE("framework.network.readTimeout"); err != nil || d != 30*time.Second {
+		t.Errorf("GetDurationE(readTimeout) = %v, %v", d, err)
+	}
+	if d, err := cm.GetDurationE("framework.cache.missing", time.Minute); err != nil || d != time.Minute {
+		t.Errorf("Expected default for missing key, got %v, %v", d, err)
+	}
+	if _, err := cm.GetDurationE("framework.cache.timeout"); err == nil || !strings.HasPrefix(err.Error(), "framework.cache.timeout: ") {
+		t.Errorf("Expected error with key path, got %v", err)
+	}
+
+	if size, err := cm.GetByteSizeE("framework.cache.maxSize"); err != nil || size != 64<<10 {
+		t.Errorf("GetByteSizeE(maxSize) = %v, %v", size, err)
+	}
+	if _, err := cm.GetByteSizeE("framework.cache.minSize"); err == nil {
+		t.Error("Expected error for malformed byte size")
+	}
+	if size := cm.GetByteSize("framework.cache.minSize", 1<<20); size != 1<<20 {
+		t.Errorf("Expected default for malformed byte size, got %v", size)
+	}
+}
+
+func TestSchema_UnitFields(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	cm.overrides = map[string]string{
+		"framework.network.readTimeout": "30",
+		"framework.cache.maxSize":       "10XB",
+	}
+
+	schema := FrameworkSchema()
+	schema.Fields = append(schema.Fields, SchemaField{Key: "framework.cache.maxSize", Type: FieldByteSize})
+
+	err = schema.Validate(cm)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `framework.network.readTimeout must be a duration, got "30": missing unit`) {
+		t.Errorf("Expected duration hint, got %q", msg)
+	}
+	if !strings.Contains(msg, `framework.cache.maxSize must be a byte size, got "10XB": unknown unit`) {
+		t.Errorf("Expected byte size hint, got %q", msg)
+	}
+}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 结构体标签
+//
+//	Timeout time.Duration `config:"timeout" default:"5s"`
+//	Limits  LimitsConfig  `config:",squash"`
+//	Secret  string        `config:"-"`
+//
+// 未设置 config 标签时兼容 mapstructure 标签，都未设置时按字段名不区分大小写匹配
+const (
+	tagConfig       = "config"
+	tagMapstructure = "mapstructure"
+	tagDefault      = "default"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnmarshalKey 将配置节解码到结构体
+//
+// out 必须为非 nil 的结构体指针。字段按标签名匹配配置键，命令行参数和环境变量覆盖规则与 GetString 等方法一致，
+// 配置和环境变量中都不存在的字段使用 default 标签的值：
+//
+//	type CacheConfig struct {
+//	    Enabled bool            `config:"enabled" default:"true"`
+//	    TTL     time.Duration   `config:"ttl" default:"5m"`
+//	    MaxSize config.ByteSize `config:"maxSize" default:"64MB"`
+//	}
+//
+//	var cache CacheConfig
+//	err := cm.UnmarshalKey("framework.cache", &cache)
+func (cm *ConfigManager) UnmarshalKey(pattern string, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer to struct, got %T", out)
+	}
+
+	raw := cm.config.MustGet(context.Background(), pattern).Val()
+	d := &decoder{env: cm.getOverride}
+	return d.decodeStruct(pattern, raw, rv.Elem())
+}
+
+// decoder 配置解码器
+type decoder struct {
+	// env 按配置路径查找命令行参数或环境变量覆盖值
+	env func(path string) string
+}
+
+// decodeStruct 解码结构体，raw 为配置中的映射或 nil
+func (d *decoder) decodeStruct(path string, raw interface{}, out reflect.Value) error {
+	var values map[string]interface{}
+	if raw != nil {
+		var ok bool
+		if values, ok = toStringMap(raw); !ok {
+			return fmt.Errorf("%s: expected a map, got %T", path, raw)
+		}
+	}
+
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// 未导出的嵌入结构体仍需展开其导出字段
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		name, squash, skip := fieldKey(field)
+		if skip || (!field.IsExported() && !squash) {
+			continue
+		}
+
+		fv := out.Field(i)
+		if squash {
+			if err := d.decodeStruct(path, raw, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldPath := path + "." + name
+		value, found := lookupKey(values, name)
+
+		if env := d.env(fieldPath); env != "" && isScalar(field.Type) {
+			value, found = env, true
+		}
+
+		if !found {
+			def, hasDefault := field.Tag.Lookup(tagDefault)
+			switch {
+			case hasDefault:
+				value = def
+			case isStruct(field.Type):
+				// 配置节缺失时仍需为嵌套结构体填充默认值
+				value = nil
+			default:
+				continue
+			}
+		}
+
+		if err := d.decodeValue(fieldPath, value, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeValue 按目标类型解码单个值
+func (d *decoder) decodeValue(path string, raw interface{}, out reflect.Value) error {
+	if out.Kind() == reflect.Ptr {
+		if raw == nil && !isStruct(out.Type()) {
+			return nil
+		}
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+		return d.decodeValue(path, raw, out.Elem())
+	}
+
+	// 实现 TextUnmarshaler 的类型（如 ByteSize）优先按文本解析
+	if out.CanAddr() && out.Addr().Type().Implements(textUnmarshalerType) {
+		if raw == nil {
+			return nil
+		}
+		if err := out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(toString(raw))); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	if out.Type() == durationType {
+		return decodeDuration(path, raw, out)
+	}
+
+	switch out.Kind() {
+	case reflect.Struct:
+		return d.decodeStruct(path, raw, out)
+	case reflect.Slice:
+		return d.decodeSlice(path, raw, out)
+	case reflect.Map:
+		return d.decodeMap(path, raw, out)
+	case reflect.Interface:
+		if raw != nil {
+			out.Set(reflect.ValueOf(raw))
+		}
+		return nil
+	}
+
+	if raw == nil {
+		return nil
+	}
+	return decodeScalar(path, raw, out)
+}
+
+// decodeSlice 解码切片，支持列表或逗号分隔的字符串
+func (d *decoder) decodeSlice(path string, raw interface{}, out reflect.Value) error {
+	var items []interface{}
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items = v
+	case string:
+		if v == "" {
+			break
+		}
+		for _, item := range strings.Split(v, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+	default:
+		rv := reflect.ValueOf(raw)
+		if rv.Kind() != reflect.Slice {
+			return fmt.Errorf("%s: expected a list, got %T", path, raw)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, rv.Index(i).Interface())
+		}
+	}
+
+	slice := reflect.MakeSlice(out.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := d.decodeValue(fmt.Sprintf("%s[%d]", path, i), item, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	out.Set(slice)
+	return nil
+}
+
+// decodeMap 解码字符串键的映射
+func (d *decoder) decodeMap(path string, raw interface{}, out reflect.Value) error {
+	if raw == nil {
+		return nil
+	}
+	if out.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%s: map key must be string, got %s", path, out.Type().Key())
+	}
+
+	values, ok := toStringMap(raw)
+	if !ok {
+		return fmt.Errorf("%s: expected a map, got %T", path, raw)
+	}
+
+	m := reflect.MakeMapWithSize(out.Type(), len(values))
+	for key, value := range values {
+		elem := reflect.New(out.Type().Elem()).Elem()
+		if err := d.decodeValue(path+"."+key, value, elem); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(out.Type().Key()), elem)
+	}
+	out.Set(m)
+	return nil
+}
+
+// decodeDuration 严格解码时间间隔，必须带单位（0 除外）
+func decodeDuration(path string, raw interface{}, out reflect.Value) error {
+	if raw == nil {
+		return nil
+	}
+
+	duration, err := parseDurationValue(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	out.SetInt(int64(duration))
+	return nil
+}
+
+// decodeScalar 解码字符串、布尔和数值
+func decodeScalar(path string, raw interface{}, out reflect.Value) error {
+	s := toString(raw)
+
+	switch out.Kind() {
+	case reflect.String:
+		out.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%s: invalid bool %q", path, s)
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, out.Type().Bits())
+		if err != nil {
+			// 兼容 YAML 中写成 1e3 或 10.0 的整数
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return fmt.Errorf("%s: invalid integer %q", path, s)
+			}
+			n = int64(f)
+		}
+		if out.OverflowInt(n) {
+			return fmt.Errorf("%s: integer %d overflows %s", path, n, out.Type())
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, out.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: invalid unsigned integer %q", path, s)
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, out.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", path, s)
+		}
+		out.SetFloat(f)
+	default:
+		return fmt.Errorf("%s: unsupported field type %s", path, out.Type())
+	}
+
+	return nil
+}
+
+// fieldKey 返回字段对应的配置键，以及是否展开（squash）或跳过
+func fieldKey(field reflect.StructField) (name string, squash, skip bool) {
+	tag, ok := field.Tag.Lookup(tagConfig)
+	if !ok {
+		tag = field.Tag.Get(tagMapstructure)
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		if opt == "squash" {
+			squash = true
+		}
+	}
+	if field.Anonymous && isStruct(field.Type) && name == "" {
+		squash = true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, squash, false
+}
+
+// lookupKey 查找配置键，精确匹配失败时不区分大小写
+func lookupKey(values map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := values[name]; ok {
+		return value, true
+	}
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// toStringMap 将配置中的映射统一为 map[string]interface{}
+func toStringMap(raw interface{}) (map[string]interface{}, bool) {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = value
+		}
+		return m, true
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m, true
+}
+
+// toString 将标量值转换为字符串
+func toString(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// isStruct 检查类型是否为结构体或结构体指针（不含 time.Duration 等标量）
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// isScalar 检查字段是否可以被单个环境变量覆盖
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Interface:
+		return reflect.PtrTo(t).Implements(textUnmarshalerType)
+	}
+	return true
+}
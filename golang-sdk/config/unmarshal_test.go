@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type unmarshalLimits struct {
+	QPS   int `config:"qps" default:"1000"`
+	Burst int `config:"burst"`
+}
+
+type unmarshalBase struct {
+	Name string `config:"name"`
+}
+
+type unmarshalTarget struct {
+	unmarshalBase
+	Enabled  bool                       `config:"enabled" default:"true"`
+	Timeout  time.Duration              `config:"timeout" default:"5s"`
+	MaxSize  ByteSize                   `config:"maxSize" default:"64MB"`
+	Ratio    float64                    `mapstructure:"ratio"`
+	Retries  uint8                      `config:"retries"`
+	Nodes    []string                   `config:"nodes"`
+	Ports    []int                      `config:"ports"`
+	Tags     map[string]string          `config:"tags"`
+	Backends map[string]unmarshalLimits `config:"backends"`
+	Limits   unmarshalLimits            `config:"limits"`
+	Optional *unmarshalLimits           `config:"optional"`
+	Secret   string                     `config:"-"`
+	Region   string
+}
+
+func TestDecoder(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":    "cache",
+		"timeout": "30s",
+		"maxSize": "1KB",
+		"ratio":   0.5,
+		"retries": 3,
+		"nodes":   []interface{}{"a", "b"},
+		"ports":   "8080, 8081",
+		"tags":    map[string]interface{}{"env": "prod"},
+		"backends": map[string]interface{}{
+			"primary": map[string]interface{}{"qps": 50},
+		},
+		"limits": map[interface{}]interface{}{"burst": 20},
+		"secret": "ignored",
+		"REGION": "eu",
+	}
+
+	var out unmarshalTarget
+	d := &decoder{env: func(string) string { return "" }}
+	if err := d.decodeStruct("framework.cache", raw, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	want := unmarshalTarget{
+		unmarshalBase: unmarshalBase{Name: "cache"},
+		Enabled:       true,
+		Timeout:       30 * time.Second,
+		MaxSize:       1024,
+		Ratio:         0.5,
+		Retries:       3,
+		Nodes:         []string{"a", "b"},
+		Ports:         []int{8080, 8081},
+		Tags:          map[string]string{"env": "prod"},
+		Backends:      map[string]unmarshalLimits{"primary": {QPS: 50}},
+		Limits:        unmarshalLimits{QPS: 1000, Burst: 20},
+		Optional:      &unmarshalLimits{QPS: 1000},
+		Region:        "eu",
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("decoded = %+v\nwant    %+v", out, want)
+	}
+}
+
+func TestDecoder_EnvOverride(t *testing.T) {
+	env := map[string]string{
+		"framework.cache.timeout":      "1m",
+		"framework.cache.limits.burst": "7",
+	}
+
+	var out unmarshalTarget
+	d := &decoder{env: func(path string) string { return env[path] }}
+	raw := map[string]interface{}{"timeout": "30s"}
+	if err := d.decodeStruct("framework.cache", raw, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if out.Timeout != time.Minute {
+		t.Errorf("Expected timeout 1m from env, got %v", out.Timeout)
+	}
+	if out.Limits.Burst != 7 {
+		t.Errorf("Expected burst 7 from env, got %d", out.Limits.Burst)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr string
+	}{
+		{name: "invalid duration", raw: map[string]interface{}{"timeout": "abc"}, wantErr: "framework.cache.timeout"},
+		{name: "duration without unit", raw: map[string]interface{}{"timeout": 30}, wantErr: "framework.cache.timeout: invalid duration \"30\": missing unit"},
+		{name: "invalid size", raw: map[string]interface{}{"maxSize": "lots"}, wantErr: "framework.cache.maxSize"},
+		{name: "invalid bool", raw: map[string]interface{}{"enabled": "maybe"}, wantErr: "framework.cache.enabled"},
+		{name: "overflow", raw: map[string]interface{}{"retries": 300}, wantErr: "framework.cache.retries"},
+		{name: "invalid list item", raw: map[string]interface{}{"ports": []interface{}{80, "http"}}, wantErr: "framework.cache.ports[1]"},
+		{name: "nested not a map", raw: map[string]interface{}{"limits": "fast"}, wantErr: "framework.cache.limits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out unmarshalTarget
+			d := &decoder{env: func(string) string { return "" }}
+			err := d.decodeStruct("framework.cache", tt.raw, reflect.ValueOf(&out).Elem())
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error to mention %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigManager_UnmarshalKey(t *testing.T) {
+	cm, err := NewConfigManager("config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	type network struct {
+		Host         string        `config:"host"`
+		Port         int           `config:"port"`
+		ReadTimeout  time.Duration `config:"readTimeout"`
+		KeepAlive    bool          `config:"keepAlive"`
+		BufferSize   ByteSize      `config:"bufferSize" default:"32KB"`
+		WriteTimeout time.Duration
+	}
+
+	os.Setenv("FRAMEWORK_NETWORK_PORT", "9090")
+	defer os.Unsetenv("FRAMEWORK_NETWORK_PORT")
+
+	var out network
+	if err := cm.UnmarshalKey("framework.network", &out); err != nil {
+		t.Fatalf("UnmarshalKey failed: %v", err)
+	}
+
+	if out.Host != "0.0.0.0" {
+		t.Errorf("Expected host 0.0.0.0, got %s", out.Host)
+	}
+	if out.Port != 9090 {
+		t.Errorf("Expected port 9090 from env, got %d", out.Port)
+	}
+	if out.ReadTimeout != 30*time.Second || out.WriteTimeout != 30*time.Second {
+		t.Errorf("Expected 30s timeouts, got %v/%v", out.ReadTimeout, out.WriteTimeout)
+	}
+	if !out.KeepAlive {
+		t.Error("Expected keepAlive to be true")
+	}
+	if out.BufferSize != 32<<10 {
+		t.Errorf("Expected default buffer size 32KB, got %d", out.BufferSize)
+	}
+
+	// 不存在的配置节只填充默认值
+	var missing unmarshalTarget
+	if err := cm.UnmarshalKey("framework.nonexistent", &missing); err != nil {
+		t.Fatalf("UnmarshalKey on missing key failed: %v", err)
+	}
+	if missing.Timeout != 5*time.Second || missing.Limits.QPS != 1000 {
+		t.Errorf("Expected defaults, got %+v", missing)
+	}
+
+	if err := cm.UnmarshalKey("framework.network", out); err == nil {
+		t.Error("Expected error for non-pointer target")
+	}
+}
@@ -0,0 +1,76 @@
+package connection
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolActiveDesc = prometheus.NewDesc(
+		"framework_connection_pool_active_connections",
+		"Number of connections in use across all connection pools",
+		[]string{"service"}, nil,
+	)
+	poolIdleDesc = prometheus.NewDesc(
+		"framework_connection_pool_idle_connections",
+		"Number of idle connections across all connection pools",
+		[]string{"service"}, nil,
+	)
+	poolMaxDesc = prometheus.NewDesc(
+		"framework_connection_pool_max_connections",
+		"Maximum number of connections across all connection pools",
+		[]string{"service"}, nil,
+	)
+)
+
+// connectionPoolCollector 连接池采集器
+type connectionPoolCollector struct {
+	serviceName string
+	manager     ConnectionManager
+}
+
+// NewConnectionPoolCollector 创建连接池采集器，在采集时读取连接池的当前状态，导出活跃、空闲和最大连接数，
+// 通过 prometheus.MustRegister 注册；observability 生成的仪表盘以此计算连接池饱和度
+func NewConnectionPoolCollector(serviceName string, manager ConnectionManager) prometheus.Collector {
+	return &connectionPoolCollector{
+		serviceName: serviceName,
+		manager:     manager,
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *connectionPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolActiveDesc
+	ch <- poolIdleDesc
+	ch <- poolMaxDesc
+}
+
+// Collect 实现 prometheus.Collector
+func (c *connectionPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.manager.GetTotalStats()
+	if stats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(poolActiveDesc, prometheus.GaugeValue, float64(stats.ActiveConnections), c.serviceName)
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stats.IdleConnections), c.serviceName)
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(stats.MaxConnections), c.serviceName)
+}
+
+// PoolUsage 以基本类型提供连接池全局统计，实现 observability.PoolStatsProvider，用于慢请求诊断
+type PoolUsage struct {
+	manager ConnectionManager
+}
+
+// NewPoolUsage 创建连接池统计来源
+func NewPoolUsage(manager ConnectionManager) *PoolUsage {
+	return &PoolUsage{manager: manager}
+}
+
+// PoolStats 返回活跃、空闲和最大连接数，统计不可用时均为 0
+func (u *PoolUsage) PoolStats() (active, idle, max int) {
+	stats := u.manager.GetTotalStats()
+	if stats == nil {
+		return 0, 0, 0
+	}
+	return stats.ActiveConnections, stats.IdleConnections, stats.MaxConnections
+}
@@ -0,0 +1,45 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestConnectionPoolCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewConnectionPoolCollector("order-service", &stubConnectionManager{
+		stats: &ConnectionPoolStats{ActiveConnections: 3, IdleConnections: 2, MaxConnections: 10},
+	}))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	if values["framework_connection_pool_active_connections"] != 3 ||
+		values["framework_connection_pool_idle_connections"] != 2 ||
+		values["framework_connection_pool_max_connections"] != 10 {
+		t.Errorf("Unexpected pool metrics: %v", values)
+	}
+}
+
+func TestPoolUsage(t *testing.T) {
+	usage := NewPoolUsage(&stubConnectionManager{
+		stats: &ConnectionPoolStats{ActiveConnections: 3, IdleConnections: 2, MaxConnections: 10},
+	})
+	if active, idle, max := usage.PoolStats(); active != 3 || idle != 2 || max != 10 {
+		t.Errorf("PoolStats() = %d, %d, %d", active, idle, max)
+	}
+
+	if active, idle, max := NewPoolUsage(&stubConnectionManager{}).PoolStats(); active != 0 || idle != 0 || max != 0 {
+		t.Errorf("PoolStats() without stats = %d, %d, %d", active, idle, max)
+	}
+}
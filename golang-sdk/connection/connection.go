@@ -37,6 +37,13 @@ func (s ConnectionState) String() string {
 	}
 }
 
+// HealthChecker 自行检测健康状态的底层连接，如带心跳的自定义协议客户端
+//
+// 底层连接实现该接口时，IsHealthy 以 Healthy 的结果为准，连接池在获取、释放和定期清理时回收失效的连接
+type HealthChecker interface {
+	Healthy() bool
+}
+
 // ManagedConnection 受管连接
 //
 // 封装底层网络连接，提供状态管理和生命周期控制
@@ -50,6 +57,7 @@ type ManagedConnection struct {
 	state      int32 // 使用 atomic 操作
 	createdAt  time.Time
 	lastUsedAt time.Time
+	acquiredAt time.Time // 最近一次从连接管理器获取的时间，用于计算请求延迟
 	mu         sync.RWMutex
 	closeOnce  sync.Once
 	closeErr   error
@@ -108,6 +116,20 @@ func (mc *ManagedConnection) LastUsedAt() time.Time {
 	return mc.lastUsedAt
 }
 
+// markAcquired 记录从连接管理器获取连接的时间
+func (mc *ManagedConnection) markAcquired() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.acquiredAt = time.Now()
+}
+
+// sinceAcquired 返回距最近一次获取连接的时间
+func (mc *ManagedConnection) sinceAcquired() time.Duration {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return time.Since(mc.acquiredAt)
+}
+
 // UpdateLastUsed 更新最后使用时间
 func (mc *ManagedConnection) UpdateLastUsed() {
 	mc.mu.Lock()
@@ -136,6 +158,11 @@ func (mc *ManagedConnection) IsHealthy() bool {
 		return false
 	}
 
+	// 底层连接自行检测健康状态
+	if checker, ok := mc.conn.(HealthChecker); ok {
+		return checker.Healthy()
+	}
+
 	// 检查 gRPC 连接状态
 	if mc.grpcConn != nil {
 		state := mc.grpcConn.GetState()
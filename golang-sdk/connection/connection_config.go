@@ -1,6 +1,12 @@
 package connection
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/router"
+	"google.golang.org/grpc"
+)
 
 // ConnectionConfig 连接池配置
 //
@@ -20,6 +26,10 @@ type ConnectionConfig struct {
 	// MaxLifetime 连接最大存活时间
 	MaxLifetime time.Duration
 
+	// BatchReserve 保留给交互请求的连接比例（0~1），批处理请求（metadata.PriorityBatch）不能使用这部分连接，
+	// 连接池饱和时先拒绝批处理请求；为 0 时不区分优先级
+	BatchReserve float64
+
 	// ConnectionTimeout 获取连接超时时间
 	ConnectionTimeout time.Duration
 
@@ -40,6 +50,19 @@ type ConnectionConfig struct {
 
 	// TCPNoDelay 是否启用 TCP NoDelay
 	TCPNoDelay bool
+
+	// GrpcDialOptions 创建 gRPC 连接时追加的拨号选项，如 transport.GrpcClientDialOptions 返回的客户端拦截器
+	GrpcDialOptions []grpc.DialOption
+
+	// TLSConfig 不为 nil 时 gRPC 和 HTTP 连接使用 TLS（如 mTLS 引导提供的客户端配置），为 nil 时使用明文连接
+	TLSConfig *tls.Config
+
+	// EndpointStats 不为 nil 时连接管理器在获取连接时记录端点开始处理请求，释放连接时记录完成及延迟（获取到释放的时间），
+	// 与 router.NewLeastLoadedLoadBalancer 共用同一统计即可按运行时负载选择端点，通常为 router.DefaultEndpointStats
+	EndpointStats *router.EndpointStats
+
+	// Services 按服务名（ServiceEndpoint.Name）的连接池配置，未配置的服务使用当前配置
+	Services map[string]*ConnectionConfig
 }
 
 // DefaultConnectionConfig 返回默认连接配置
@@ -47,6 +70,7 @@ func DefaultConnectionConfig() *ConnectionConfig {
 	return &ConnectionConfig{
 		MaxConnections:       100,
 		MinConnections:       10,
+		BatchReserve:         0.2,
 		IdleTimeout:          5 * time.Minute,
 		MaxLifetime:          30 * time.Minute,
 		ConnectionTimeout:    5 * time.Second,
@@ -58,3 +82,11 @@ func DefaultConnectionConfig() *ConnectionConfig {
 		TCPNoDelay:           true,
 	}
 }
+
+// ForService 返回连接到 serviceName 服务时使用的配置
+func (c *ConnectionConfig) ForService(serviceName string) *ConnectionConfig {
+	if override, ok := c.Services[serviceName]; ok && override != nil {
+		return override
+	}
+	return c
+}
@@ -6,6 +6,10 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ConnectionManager 连接管理器接口
@@ -77,12 +81,29 @@ func (m *DefaultConnectionManager) GetConnection(ctx context.Context, endpoint *
 	}
 
 	// 获取或创建连接池
+	m.mu.RLock()
+	config := m.config.ForService(endpoint.Name)
+	m.mu.RUnlock()
+
 	key := endpointKey(endpoint)
-	poolInterface, _ := m.pools.LoadOrStore(key, NewConnectionPool(endpoint, m.config))
+	poolInterface, _ := m.pools.LoadOrStore(key, NewConnectionPool(endpoint, config))
 	pool := poolInterface.(*ConnectionPool)
 
-	// 从连接池获取连接
-	return pool.Acquire(ctx)
+	// 从连接池获取连接，获取过程记录为独立的子 span
+	_, span := adapter.StartInternalSpan(ctx, "connection.Acquire",
+		adapter.AttrEndpoint.String(endpoint.Key()),
+		adapter.AttrProtocol.String(endpoint.Protocol),
+	)
+	conn, err := pool.Acquire(ctx)
+	if err == nil {
+		span.SetAttributes(attribute.String("connection.id", conn.ID()))
+		if stats := m.endpointStats(); stats != nil {
+			conn.markAcquired()
+			stats.Begin(key)
+		}
+	}
+	adapter.EndSpan(span, err)
+	return conn, err
 }
 
 // ReleaseConnection 释放连接回连接池
@@ -92,6 +113,10 @@ func (m *DefaultConnectionManager) ReleaseConnection(conn *ManagedConnection) {
 	}
 
 	key := endpointKey(conn.Endpoint())
+	// 连接被释放即请求完成，更新端点负载统计
+	if stats := m.endpointStats(); stats != nil {
+		stats.End(key, conn.sinceAcquired())
+	}
 	if poolInterface, ok := m.pools.Load(key); ok {
 		pool := poolInterface.(*ConnectionPool)
 		pool.Release(conn)
@@ -104,6 +129,9 @@ func (m *DefaultConnectionManager) ReleaseConnection(conn *ManagedConnection) {
 // CloseConnections 关闭到指定端点的所有连接
 func (m *DefaultConnectionManager) CloseConnections(endpoint *ServiceEndpoint) error {
 	key := endpointKey(endpoint)
+	if stats := m.endpointStats(); stats != nil {
+		stats.Remove(key)
+	}
 	if poolInterface, ok := m.pools.LoadAndDelete(key); ok {
 		pool := poolInterface.(*ConnectionPool)
 		return pool.Close()
@@ -156,8 +184,10 @@ func (m *DefaultConnectionManager) GetPoolStats(endpoint *ServiceEndpoint) *Conn
 		pool := poolInterface.(*ConnectionPool)
 		return pool.GetStats()
 	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return &ConnectionPoolStats{
-		MaxConnections: m.config.MaxConnections,
+		MaxConnections: m.config.ForService(endpoint.Name).MaxConnections,
 	}
 }
 
@@ -187,7 +217,7 @@ func (m *DefaultConnectionManager) UpdateConfig(config *ConnectionConfig) {
 	m.config = config
 	m.pools.Range(func(key, value interface{}) bool {
 		pool := value.(*ConnectionPool)
-		pool.UpdateConfig(config)
+		pool.UpdateConfig(config.ForService(pool.endpoint.Name))
 		return true
 	})
 }
@@ -207,6 +237,13 @@ func (m *DefaultConnectionManager) GetPoolCount() int {
 	return count
 }
 
+// endpointStats 返回配置的端点负载统计
+func (m *DefaultConnectionManager) endpointStats() *router.EndpointStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.EndpointStats
+}
+
 // endpointKey 生成端点的唯一 key
 func endpointKey(endpoint *ServiceEndpoint) string {
 	return fmt.Sprintf("%s:%d", endpoint.Address, endpoint.Port)
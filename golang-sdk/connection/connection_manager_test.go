@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/framework/golang-sdk/protocol/router"
 )
 
 // TestConnectionManagerCreation 测试连接管理器创建
@@ -318,3 +320,105 @@ func TestConnectionManagerUpdateConfig(t *testing.T) {
 		t.Errorf("Expected max connections to be 10, got %d", stats.MaxConnections)
 	}
 }
+
+// TestConnectionManagerServiceConfig 测试按服务覆盖连接池配置
+func TestConnectionManagerServiceConfig(t *testing.T) {
+	config := DefaultConnectionConfig()
+	config.MaxConnections = 5
+
+	payment := DefaultConnectionConfig()
+	payment.MaxConnections = 2
+	config.Services = map[string]*ConnectionConfig{"payment-service": payment}
+
+	if config.ForService("payment-service") != payment {
+		t.Error("Expected override config for payment-service")
+	}
+	if config.ForService("other") != config {
+		t.Error("Expected base config for unconfigured service")
+	}
+
+	manager := NewConnectionManager(config)
+	defer manager.CloseAll()
+
+	paymentEndpoint := &ServiceEndpoint{Name: "payment-service", Address: "localhost", Port: 50061, Protocol: "gRPC"}
+	otherEndpoint := &ServiceEndpoint{Name: "other", Address: "localhost", Port: 50062, Protocol: "gRPC"}
+
+	if stats := manager.GetPoolStats(paymentEndpoint); stats.MaxConnections != 2 {
+		t.Errorf("Expected payment max connections to be 2, got %d", stats.MaxConnections)
+	}
+	if stats := manager.GetPoolStats(otherEndpoint); stats.MaxConnections != 5 {
+		t.Errorf("Expected default max connections to be 5, got %d", stats.MaxConnections)
+	}
+}
+
+// stubHealthChecker 自行报告健康状态的底层连接
+type stubHealthChecker struct {
+	healthy bool
+	closed  bool
+}
+
+func (c *stubHealthChecker) Healthy() bool { return c.healthy }
+
+func (c *stubHealthChecker) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestManagedConnectionHealthChecker 测试底层连接自行检测的健康状态
+func TestManagedConnectionHealthChecker(t *testing.T) {
+	endpoint := &ServiceEndpoint{Address: "localhost", Port: 50071, Protocol: "custom"}
+	conn := &stubHealthChecker{healthy: true}
+	pool := NewConnectionPool(endpoint, DefaultConnectionConfig())
+	defer pool.Close()
+
+	mc := NewManagedConnection("custom-1", endpoint, conn)
+	pool.connections = append(pool.connections, mc)
+	if !mc.IsHealthy() {
+		t.Error("Expected connection to be healthy")
+	}
+
+	// 心跳判定对端失效后，释放时从连接池移除并关闭
+	conn.healthy = false
+	pool.Release(mc)
+	if stats := pool.GetStats(); stats.TotalConnections != 0 {
+		t.Errorf("Expected broken connection to be removed, got %d connections", stats.TotalConnections)
+	}
+	if !conn.closed {
+		t.Error("Expected broken connection to be closed")
+	}
+}
+
+// TestConnectionManagerEndpointStats 测试获取和释放连接时更新端点负载统计
+func TestConnectionManagerEndpointStats(t *testing.T) {
+	config := DefaultConnectionConfig()
+	config.EndpointStats = router.NewEndpointStats()
+	manager := NewConnectionManager(config).(*DefaultConnectionManager)
+	defer manager.CloseAll()
+
+	// 连接池中预置空闲连接，避免建立真实连接
+	endpoint := &ServiceEndpoint{Address: "localhost", Port: 50072, Protocol: "custom"}
+	pool := NewConnectionPool(endpoint, config)
+	pool.connections = append(pool.connections, NewManagedConnection("custom-1", endpoint, &stubHealthChecker{healthy: true}))
+	manager.pools.Store(endpointKey(endpoint), pool)
+
+	conn, err := manager.GetConnection(context.Background(), endpoint)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	if load := config.EndpointStats.Load(endpoint.Key()); load.InFlight != 1 {
+		t.Errorf("Expected 1 in-flight request, got %+v", load)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	manager.ReleaseConnection(conn)
+	load := config.EndpointStats.Load(endpoint.Key())
+	if load.InFlight != 0 || load.Requests != 1 || load.Latency < 5*time.Millisecond {
+		t.Errorf("Expected 1 completed request of at least 5ms, got %+v", load)
+	}
+
+	// 关闭端点的连接后删除统计
+	manager.CloseConnections(endpoint)
+	if load := config.EndpointStats.Load(endpoint.Key()); load != (router.EndpointLoad{}) {
+		t.Errorf("Expected stats to be removed, got %+v", load)
+	}
+}
@@ -3,11 +3,14 @@ package connection
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/framework/golang-sdk/metadata"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -46,11 +49,18 @@ func NewConnectionPool(endpoint *ServiceEndpoint, config *ConnectionConfig) *Con
 }
 
 // Acquire 获取连接
+//
+// context 中的优先级为 metadata.PriorityBatch 时，使用中的连接数达到 MaxConnections 减去 BatchReserve
+// 保留的连接数后拒绝请求，保留的连接只供交互请求使用
 func (p *ConnectionPool) Acquire(ctx context.Context) (*ManagedConnection, error) {
 	if p.closed.Load() {
 		return nil, fmt.Errorf("connection pool is closed")
 	}
 
+	if metadata.PriorityFromContext(ctx) == metadata.PriorityBatch && p.batchSaturated() {
+		return nil, fmt.Errorf("connection pool is saturated for batch requests")
+	}
+
 	// 首先尝试复用空闲连接
 	if conn := p.findIdleConnection(); conn != nil {
 		conn.SetState(StateActive)
@@ -213,10 +223,15 @@ func (p *ConnectionPool) createConnection(ctx context.Context) (*ManagedConnecti
 func (p *ConnectionPool) createGrpcConnection(ctx context.Context) (*ManagedConnection, error) {
 	target := fmt.Sprintf("%s:%d", p.endpoint.Address, p.endpoint.Port)
 
+	creds := insecure.NewCredentials()
+	if p.config.TLSConfig != nil {
+		creds = credentials.NewTLS(p.config.TLSConfig)
+	}
 	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 	}
+	opts = append(opts, p.config.GrpcDialOptions...)
 
 	conn, err := grpc.DialContext(ctx, target, opts...)
 	if err != nil {
@@ -227,6 +242,24 @@ func (p *ConnectionPool) createGrpcConnection(ctx context.Context) (*ManagedConn
 	return NewManagedConnection(id, p.endpoint, conn), nil
 }
 
+// batchSaturated 检查使用中的连接数是否已达到批处理请求可用的上限
+func (p *ConnectionPool) batchSaturated() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.config.BatchReserve <= 0 {
+		return false
+	}
+	limit := p.config.MaxConnections - int(math.Ceil(float64(p.config.MaxConnections)*p.config.BatchReserve))
+	active := 0
+	for _, conn := range p.connections {
+		if conn.IsActive() {
+			active++
+		}
+	}
+	return active >= limit
+}
+
 // findIdleConnection 查找空闲连接（无锁版本）
 func (p *ConnectionPool) findIdleConnection() *ManagedConnection {
 	p.mu.RLock()
@@ -0,0 +1,72 @@
+package connection
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/transport/memory"
+	"google.golang.org/grpc"
+)
+
+// newBenchmarkPool 创建连接到进程内 gRPC 服务器的连接池
+func newBenchmarkPool(b *testing.B, maxConnections int) *ConnectionPool {
+	listener := memory.Listen()
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	b.Cleanup(server.Stop)
+
+	config := DefaultConnectionConfig()
+	config.MaxConnections = maxConnections
+	config.GrpcDialOptions = append(config.GrpcDialOptions, listener.GrpcDialOption())
+	pool := NewConnectionPool(&ServiceEndpoint{
+		ServiceID: "bench-service",
+		Name:      "bench",
+		Address:   "127.0.0.1",
+		Port:      listener.Port(),
+		Protocol:  "gRPC",
+	}, config)
+	b.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+// BenchmarkPoolAcquireRelease 复用空闲连接的获取和释放基准测试
+func BenchmarkPoolAcquireRelease(b *testing.B) {
+	pool := newBenchmarkPool(b, 4)
+	ctx := context.Background()
+	// 预先建立连接，基准测试只统计复用的开销
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	pool.Release(conn)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pool.Release(conn)
+	}
+}
+
+// BenchmarkPoolAcquireReleaseParallel 并发获取和释放连接的基准测试
+func BenchmarkPoolAcquireReleaseParallel(b *testing.B) {
+	// 每个并发协程最多同时持有一个连接，连接数上限不会成为瓶颈
+	pool := newBenchmarkPool(b, runtime.GOMAXPROCS(0)*2)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			conn, err := pool.Acquire(ctx)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			pool.Release(conn)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package connection
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/framework/golang-sdk/metadata"
+	"google.golang.org/grpc"
+)
+
+// TestConnectionPoolBatchReserve 测试批处理请求不能使用保留给交互请求的连接
+func TestConnectionPoolBatchReserve(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	config := DefaultConnectionConfig()
+	config.MaxConnections = 4
+	config.BatchReserve = 0.5
+	endpoint := &ServiceEndpoint{
+		ServiceID: "test-service",
+		Name:      "test",
+		Address:   "127.0.0.1",
+		Port:      listener.Addr().(*net.TCPAddr).Port,
+		Protocol:  "gRPC",
+	}
+	pool := NewConnectionPool(endpoint, config)
+	defer pool.Close()
+
+	batch := metadata.WithPriority(context.Background(), metadata.PriorityBatch)
+	var conns []*ManagedConnection
+	for i := 0; i < 2; i++ {
+		conn, err := pool.Acquire(batch)
+		if err != nil {
+			t.Fatalf("batch acquire %d failed: %v", i, err)
+		}
+		conns = append(conns, conn)
+	}
+	if _, err := pool.Acquire(batch); err == nil {
+		t.Fatal("Expected batch request to be rejected when only reserved connections remain")
+	}
+
+	// 保留的连接供交互请求使用
+	for i := 0; i < 2; i++ {
+		conn, err := pool.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("interactive acquire %d failed: %v", i, err)
+		}
+		conns = append(conns, conn)
+	}
+
+	// 释放后批处理请求可以复用空闲连接
+	pool.Release(conns[0])
+	pool.Release(conns[2])
+	pool.Release(conns[3])
+	if _, err := pool.Acquire(batch); err != nil {
+		t.Errorf("Expected batch request to reuse an idle connection, got %v", err)
+	}
+}
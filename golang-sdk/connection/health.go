@@ -0,0 +1,45 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultPoolSaturation 默认连接池饱和阈值
+const DefaultPoolSaturation = 0.9
+
+// PoolSaturationCheck 连接池饱和度检查，实现 observability.HealthCheck，可通过 HealthChecker.RegisterCheck 注册
+type PoolSaturationCheck struct {
+	manager       ConnectionManager
+	maxSaturation float64
+}
+
+// NewPoolSaturationCheck 创建连接池饱和度检查
+//
+// 活跃连接数占最大连接数的比例达到 maxSaturation（0~1，默认 0.9）时检查失败
+func NewPoolSaturationCheck(manager ConnectionManager, maxSaturation float64) *PoolSaturationCheck {
+	if maxSaturation <= 0 || maxSaturation > 1 {
+		maxSaturation = DefaultPoolSaturation
+	}
+	return &PoolSaturationCheck{manager: manager, maxSaturation: maxSaturation}
+}
+
+// Name 返回检查名称 connection-pool
+func (c *PoolSaturationCheck) Name() string {
+	return "connection-pool"
+}
+
+// Check 连接池未饱和或未限制最大连接数时返回 nil
+func (c *PoolSaturationCheck) Check(ctx context.Context) error {
+	stats := c.manager.GetTotalStats()
+	if stats == nil || stats.MaxConnections <= 0 {
+		return nil
+	}
+
+	saturation := float64(stats.ActiveConnections) / float64(stats.MaxConnections)
+	if saturation >= c.maxSaturation {
+		return fmt.Errorf("connection pool saturated: %d/%d active connections",
+			stats.ActiveConnections, stats.MaxConnections)
+	}
+	return nil
+}
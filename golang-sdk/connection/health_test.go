@@ -0,0 +1,37 @@
+package connection
+
+import (
+	"context"
+	"testing"
+)
+
+// stubConnectionManager 仅返回固定统计信息的连接管理器
+type stubConnectionManager struct {
+	ConnectionManager
+	stats *ConnectionPoolStats
+}
+
+func (m *stubConnectionManager) GetTotalStats() *ConnectionPoolStats {
+	return m.stats
+}
+
+func TestPoolSaturationCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		stats   *ConnectionPoolStats
+		wantErr bool
+	}{
+		{name: "no limit", stats: &ConnectionPoolStats{ActiveConnections: 10}, wantErr: false},
+		{name: "below threshold", stats: &ConnectionPoolStats{ActiveConnections: 5, MaxConnections: 10}, wantErr: false},
+		{name: "saturated", stats: &ConnectionPoolStats{ActiveConnections: 9, MaxConnections: 10}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewPoolSaturationCheck(&stubConnectionManager{stats: tt.stats}, 0.9)
+			if err := check.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
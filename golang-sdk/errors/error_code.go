@@ -1,5 +1,10 @@
 package errors
 
+import (
+	"context"
+	stderrors "errors"
+)
+
 // ErrorCode 统一错误码类型
 type ErrorCode int
 
@@ -10,6 +15,8 @@ const (
 	Forbidden ErrorCode = 403
 	NotFound ErrorCode = 404
 	Timeout ErrorCode = 408
+	// ClientClosedRequest 调用方取消请求（context.Canceled），与后端超时区分，对应 HTTP 499
+	ClientClosedRequest ErrorCode = 499
 
 	// 服务端错误 (5xx)
 	InternalError ErrorCode = 500
@@ -23,36 +30,9 @@ const (
 	ConnectionError ErrorCode = 603
 )
 
-// String 返回错误码的字符串表示
+// String 返回错误码的字符串表示，自定义错误码返回注册时的名称
 func (e ErrorCode) String() string {
-	switch e {
-	case BadRequest:
-		return "Bad Request"
-	case Unauthorized:
-		return "Unauthorized"
-	case Forbidden:
-		return "Forbidden"
-	case NotFound:
-		return "Not Found"
-	case Timeout:
-		return "Timeout"
-	case InternalError:
-		return "Internal Error"
-	case NotImplemented:
-		return "Not Implemented"
-	case ServiceUnavailable:
-		return "Service Unavailable"
-	case ProtocolError:
-		return "Protocol Error"
-	case SerializationError:
-		return "Serialization Error"
-	case RoutingError:
-		return "Routing Error"
-	case ConnectionError:
-		return "Connection Error"
-	default:
-		return "Unknown Error"
-	}
+	return e.definition().Name
 }
 
 // Code 返回错误码的整数值
@@ -75,41 +55,22 @@ func (e ErrorCode) IsFrameworkError() bool {
 	return e >= 600
 }
 
-// IsRetryable 判断是否为可重试的错误
+// IsRetryable 判断是否为可重试的错误，自定义错误码按注册时的声明判断
 func (e ErrorCode) IsRetryable() bool {
-	return e == Timeout || e == ServiceUnavailable || e == ConnectionError
+	return e.definition().Retryable
 }
 
-// FromCode 根据错误码整数值获取 ErrorCode
+// Severity 返回错误的严重程度，未注册的错误码为 SeverityError
+func (e ErrorCode) Severity() Severity {
+	return e.definition().Severity
+}
+
+// FromCode 根据错误码整数值获取 ErrorCode，未注册的错误码返回 InternalError
 func FromCode(code int) ErrorCode {
-	switch code {
-	case 400:
-		return BadRequest
-	case 401:
-		return Unauthorized
-	case 403:
-		return Forbidden
-	case 404:
-		return NotFound
-	case 408:
-		return Timeout
-	case 500:
-		return InternalError
-	case 501:
-		return NotImplemented
-	case 503:
-		return ServiceUnavailable
-	case 600:
-		return ProtocolError
-	case 601:
-		return SerializationError
-	case 602:
-		return RoutingError
-	case 603:
-		return ConnectionError
-	default:
-		return InternalError
+	if _, ok := LookupCode(ErrorCode(code)); ok {
+		return ErrorCode(code)
 	}
+	return InternalError
 }
 
 // FromHTTPStatus 从 HTTP 状态码映射到 ErrorCode
@@ -125,6 +86,8 @@ func FromHTTPStatus(httpStatus int) ErrorCode {
 		return NotFound
 	case 408:
 		return Timeout
+	case 499:
+		return ClientClosedRequest
 	case 500:
 		return InternalError
 	case 501:
@@ -148,7 +111,7 @@ func FromGRPCStatus(grpcStatus int) ErrorCode {
 	case 0: // OK
 		return 0
 	case 1: // CANCELLED
-		return InternalError
+		return ClientClosedRequest
 	case 2: // UNKNOWN
 		return InternalError
 	case 3: // INVALID_ARGUMENT
@@ -192,48 +155,30 @@ func FromJSONRPCCode(jsonRpcCode int) ErrorCode {
 
 // ToHTTPStatus 将 ErrorCode 映射到 HTTP 状态码
 func (e ErrorCode) ToHTTPStatus() int {
-	switch e {
-	case BadRequest:
-		return 400
-	case Unauthorized:
-		return 401
-	case Forbidden:
-		return 403
-	case NotFound:
-		return 404
-	case Timeout:
-		return 408
-	case InternalError:
-		return 500
-	case NotImplemented:
-		return 501
-	case ServiceUnavailable:
-		return 503
-	case ProtocolError:
-		return 502
-	case SerializationError:
-		return 400
-	case RoutingError:
-		return 502
-	case ConnectionError:
-		return 503
-	default:
-		return 500
-	}
+	return e.definition().HTTPStatus
 }
 
 // ToJSONRPCCode 将 ErrorCode 映射到 JSON-RPC 错误码
 func (e ErrorCode) ToJSONRPCCode() int {
-	switch e {
-	case BadRequest:
-		return -32600
-	case NotFound:
-		return -32601
-	case InternalError, Timeout, ServiceUnavailable:
-		return -32603
-	case SerializationError:
-		return -32700
+	return e.definition().JSONRPCCode
+}
+
+// ToGRPCStatus 将 ErrorCode 映射到 gRPC 状态码
+func (e ErrorCode) ToGRPCStatus() int {
+	return e.definition().GRPCStatus
+}
+
+// ContextErrorCode 将 context 错误映射到 ErrorCode
+//
+// context.DeadlineExceeded 为 Timeout（后端处理慢），context.Canceled 为 ClientClosedRequest（调用方放弃），
+// 其他错误返回 false
+func ContextErrorCode(err error) (ErrorCode, bool) {
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return Timeout, true
+	case stderrors.Is(err, context.Canceled):
+		return ClientClosedRequest, true
 	default:
-		return -32603
+		return 0, false
 	}
 }
@@ -1,6 +1,9 @@
 package errors
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -14,6 +17,7 @@ func TestErrorCode_String(t *testing.T) {
 		{Forbidden, "Forbidden"},
 		{NotFound, "Not Found"},
 		{Timeout, "Timeout"},
+		{ClientClosedRequest, "Client Closed Request"},
 		{InternalError, "Internal Error"},
 		{NotImplemented, "Not Implemented"},
 		{ServiceUnavailable, "Service Unavailable"},
@@ -131,6 +135,7 @@ func TestFromHTTPStatus(t *testing.T) {
 		{403, Forbidden},
 		{404, NotFound},
 		{408, Timeout},
+		{499, ClientClosedRequest},
 		{500, InternalError},
 		{501, NotImplemented},
 		{503, ServiceUnavailable},
@@ -152,6 +157,7 @@ func TestFromGRPCStatus(t *testing.T) {
 		grpcStatus int
 		expected   ErrorCode
 	}{
+		{1, ClientClosedRequest},
 		{3, BadRequest},
 		{4, Timeout},
 		{5, NotFound},
@@ -241,3 +247,66 @@ func TestErrorCode_ToJSONRPCCode(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCode_ToGRPCStatus(t *testing.T) {
+	tests := []struct {
+		code     ErrorCode
+		expected int
+	}{
+		{BadRequest, 3},
+		{Unauthorized, 16},
+		{Forbidden, 7},
+		{NotFound, 5},
+		{Timeout, 4},
+		{ClientClosedRequest, 1},
+		{InternalError, 13},
+		{NotImplemented, 12},
+		{ServiceUnavailable, 14},
+		{ProtocolError, 13},
+		{ConnectionError, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := tt.code.ToGRPCStatus(); got != tt.expected {
+				t.Errorf("ErrorCode.ToGRPCStatus() = %v, want %v", got, tt.expected)
+			}
+			// 与 FromGRPCStatus 往返后错误码类别一致
+			if back := FromGRPCStatus(tt.code.ToGRPCStatus()); tt.code.IsClientError() && !back.IsClientError() {
+				t.Errorf("FromGRPCStatus(%v) = %v, want client error", tt.code.ToGRPCStatus(), back)
+			}
+		})
+	}
+}
+
+func TestContextErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorCode
+		ok       bool
+	}{
+		{"超时", context.DeadlineExceeded, Timeout, true},
+		{"取消", context.Canceled, ClientClosedRequest, true},
+		{"包装的取消", fmt.Errorf("call user-service: %w", context.Canceled), ClientClosedRequest, true},
+		{"其他错误", errors.New("boom"), 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := ContextErrorCode(tt.err)
+			if code != tt.expected || ok != tt.ok {
+				t.Errorf("ContextErrorCode() = %v, %v, want %v, %v", code, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+
+	// 取消与超时在各协议下使用不同的状态码
+	if ClientClosedRequest.ToHTTPStatus() != 499 || Timeout.ToHTTPStatus() != 408 {
+		t.Error("ClientClosedRequest and Timeout should map to distinct HTTP status")
+	}
+	if ClientClosedRequest.IsRetryable() || ClientClosedRequest.Severity() != SeverityInfo {
+		t.Error("ClientClosedRequest should not be retryable and should be informational")
+	}
+}
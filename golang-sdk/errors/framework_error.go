@@ -1,10 +1,15 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"math"
 	"time"
 )
 
+// FieldRetryAfter 建议的重试等待秒数的详情键，REST 响应以 Retry-After 响应头返回
+const FieldRetryAfter = "retryAfter"
+
 // FrameworkError 框架统一错误类型
 type FrameworkError struct {
 	Code       ErrorCode
@@ -14,42 +19,44 @@ type FrameworkError struct {
 	Timestamp  int64
 	ErrorChain []string
 	Cause      error
+	// TraceID 发生错误的请求所属的追踪 ID
+	TraceID string
+	// Fields 结构化的错误详情，通过 WithDetail 添加
+	Fields map[string]interface{}
+	// stack 创建错误时的调用栈
+	stack []uintptr
 }
 
 // NewFrameworkError 创建新的框架错误
 func NewFrameworkError(code ErrorCode, message string) *FrameworkError {
-	return &FrameworkError{
-		Code:       code,
-		Message:    message,
-		Timestamp:  time.Now().UnixMilli(),
-		ErrorChain: []string{formatChainEntry(code, message, "")},
-	}
+	return newFrameworkError(code, message, "", "", nil)
 }
 
 // NewFrameworkErrorWithDetails 创建带详情的框架错误
 func NewFrameworkErrorWithDetails(code ErrorCode, message, details string) *FrameworkError {
-	return &FrameworkError{
-		Code:       code,
-		Message:    message,
-		Details:    details,
-		Timestamp:  time.Now().UnixMilli(),
-		ErrorChain: []string{formatChainEntry(code, message, "")},
-	}
+	return newFrameworkError(code, message, details, "", nil)
 }
 
 // NewFrameworkErrorWithCause 创建带原因的框架错误
 func NewFrameworkErrorWithCause(code ErrorCode, message string, cause error) *FrameworkError {
-	return &FrameworkError{
-		Code:       code,
-		Message:    message,
-		Timestamp:  time.Now().UnixMilli(),
-		ErrorChain: buildErrorChain(code, message, "", cause),
-		Cause:      cause,
-	}
+	return newFrameworkError(code, message, "", "", cause)
 }
 
 // NewFrameworkErrorFull 创建完整的框架错误
 func NewFrameworkErrorFull(code ErrorCode, message, details, serviceID string, cause error) *FrameworkError {
+	return newFrameworkError(code, message, details, serviceID, cause)
+}
+
+// Wrap 用框架错误包装 err，err 为 nil 时返回 nil
+func Wrap(err error, code ErrorCode, message string) *FrameworkError {
+	if err == nil {
+		return nil
+	}
+	return newFrameworkError(code, message, "", "", err)
+}
+
+// newFrameworkError 创建框架错误并记录调用栈，只能由导出的构造函数直接调用
+func newFrameworkError(code ErrorCode, message, details, serviceID string, cause error) *FrameworkError {
 	return &FrameworkError{
 		Code:       code,
 		Message:    message,
@@ -58,6 +65,8 @@ func NewFrameworkErrorFull(code ErrorCode, message, details, serviceID string, c
 		Timestamp:  time.Now().UnixMilli(),
 		ErrorChain: buildErrorChain(code, message, serviceID, cause),
 		Cause:      cause,
+		// 跳过 newFrameworkError 和导出的构造函数
+		stack: callers(2),
 	}
 }
 
@@ -74,17 +83,95 @@ func (e *FrameworkError) Unwrap() error {
 	return e.Cause
 }
 
+// Is 支持 errors.Is，target 为 FrameworkError 时按错误码匹配，target 的 Message 非空时还需消息一致
+//
+// 可以用只有错误码的错误作为哨兵值：errors.Is(err, NewFrameworkError(NotFound, ""))。
+// 其他包的错误类型（如 adapter.FrameworkError）实现 As 方法可转换为 FrameworkError 时同样按错误码匹配
+func (e *FrameworkError) Is(target error) bool {
+	t, ok := target.(*FrameworkError)
+	if !ok {
+		converter, isConverter := target.(interface{ As(interface{}) bool })
+		if !isConverter || !converter.As(&t) {
+			return false
+		}
+	}
+	if t == nil {
+		return false
+	}
+	return e.Code == t.Code && (t.Message == "" || t.Message == e.Message)
+}
+
+// FromError 沿错误链查找框架错误，可转换为 FrameworkError 的其他错误类型同样会被找到
+func FromError(err error) (*FrameworkError, bool) {
+	var fe *FrameworkError
+	if stderrors.As(err, &fe) {
+		return fe, true
+	}
+	return nil, false
+}
+
 // WithServiceID 添加服务 ID 上下文
 func (e *FrameworkError) WithServiceID(serviceID string) *FrameworkError {
-	return &FrameworkError{
-		Code:       e.Code,
-		Message:    e.Message,
-		Details:    e.Details,
-		ServiceID:  serviceID,
-		Timestamp:  e.Timestamp,
-		ErrorChain: e.ErrorChain,
-		Cause:      e.Cause,
+	clone := e.clone()
+	clone.ServiceID = serviceID
+	return clone
+}
+
+// WithTraceID 添加追踪 ID，返回新的错误，原错误不变
+func (e *FrameworkError) WithTraceID(traceID string) *FrameworkError {
+	clone := e.clone()
+	clone.TraceID = traceID
+	return clone
+}
+
+// WithDetail 添加一项结构化详情，返回新的错误，原错误不变
+func (e *FrameworkError) WithDetail(key string, value interface{}) *FrameworkError {
+	clone := e.clone()
+	clone.Fields = make(map[string]interface{}, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
 	}
+	clone.Fields[key] = value
+	return clone
+}
+
+// WithRetryAfter 添加建议调用方等待后重试的时间（向上取整到秒），返回新的错误，原错误不变
+func (e *FrameworkError) WithRetryAfter(d time.Duration) *FrameworkError {
+	return e.WithDetail(FieldRetryAfter, int(math.Ceil(d.Seconds())))
+}
+
+// RetryAfter 返回错误链中框架错误建议的重试等待时间，包括经传输还原的错误
+func RetryAfter(err error) (time.Duration, bool) {
+	fe, ok := FromError(err)
+	if !ok {
+		return 0, false
+	}
+	return RetryAfterFromFields(fe.Fields)
+}
+
+// RetryAfterFromFields 从结构化详情中读取建议的重试等待时间
+func RetryAfterFromFields(fields map[string]interface{}) (time.Duration, bool) {
+	var seconds float64
+	switch value := fields[FieldRetryAfter].(type) {
+	case int:
+		seconds = float64(value)
+	case int64:
+		seconds = float64(value)
+	case float64:
+		seconds = value
+	default:
+		return 0, false
+	}
+	if seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// clone 浅拷贝错误，调用栈和详情与原错误共享
+func (e *FrameworkError) clone() *FrameworkError {
+	clone := *e
+	return &clone
 }
 
 // ToErrorResponse 转换为标准化的错误响应
@@ -93,6 +180,7 @@ func (e *FrameworkError) ToErrorResponse() map[string]interface{} {
 		"code":      e.Code.Code(),
 		"error":     e.Code.String(),
 		"message":   e.Message,
+		"retryable": e.Code.IsRetryable(),
 		"timestamp": e.Timestamp,
 	}
 
@@ -102,6 +190,12 @@ func (e *FrameworkError) ToErrorResponse() map[string]interface{} {
 	if e.ServiceID != "" {
 		response["serviceId"] = e.ServiceID
 	}
+	if e.TraceID != "" {
+		response["traceId"] = e.TraceID
+	}
+	if len(e.Fields) > 0 {
+		response["fields"] = e.Fields
+	}
 	if len(e.ErrorChain) > 0 {
 		response["errorChain"] = e.ErrorChain
 	}
@@ -111,17 +205,17 @@ func (e *FrameworkError) ToErrorResponse() map[string]interface{} {
 
 // NewFrameworkErrorFromHTTPStatus 从 HTTP 状态码创建 FrameworkError
 func NewFrameworkErrorFromHTTPStatus(httpStatus int, message string) *FrameworkError {
-	return NewFrameworkError(FromHTTPStatus(httpStatus), message)
+	return newFrameworkError(FromHTTPStatus(httpStatus), message, "", "", nil)
 }
 
 // NewFrameworkErrorFromGRPCStatus 从 gRPC 状态码创建 FrameworkError
 func NewFrameworkErrorFromGRPCStatus(grpcStatus int, message string) *FrameworkError {
-	return NewFrameworkError(FromGRPCStatus(grpcStatus), message)
+	return newFrameworkError(FromGRPCStatus(grpcStatus), message, "", "", nil)
 }
 
 // NewFrameworkErrorFromJSONRPCCode 从 JSON-RPC 错误码创建 FrameworkError
 func NewFrameworkErrorFromJSONRPCCode(jsonRpcCode int, message string) *FrameworkError {
-	return NewFrameworkError(FromJSONRPCCode(jsonRpcCode), message)
+	return newFrameworkError(FromJSONRPCCode(jsonRpcCode), message, "", "", nil)
 }
 
 // buildErrorChain 构建错误链
@@ -2,7 +2,10 @@ package errors
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewFrameworkError(t *testing.T) {
@@ -168,3 +171,208 @@ func TestNewFrameworkErrorFromJSONRPCCode(t *testing.T) {
 		t.Errorf("Code = %v, want %v", err.Code, NotFound)
 	}
 }
+
+func TestFrameworkError_StackTrace(t *testing.T) {
+	err := NewFrameworkError(InternalError, "错误")
+
+	trace := err.StackTrace()
+	if len(trace) == 0 {
+		t.Fatal("StackTrace should not be empty")
+	}
+	// 第一帧为调用构造函数的位置
+	if !strings.HasSuffix(trace[0].Function, "TestFrameworkError_StackTrace") {
+		t.Errorf("trace[0].Function = %v, want caller of constructor", trace[0].Function)
+	}
+
+	// 协议错误码构造函数同样从调用方开始记录
+	httpErr := NewFrameworkErrorFromHTTPStatus(404, "页面未找到")
+	if !strings.HasSuffix(httpErr.StackTrace()[0].Function, "TestFrameworkError_StackTrace") {
+		t.Errorf("trace[0].Function = %v, want caller of constructor", httpErr.StackTrace()[0].Function)
+	}
+
+	// 复制出的错误保留原调用栈
+	if len(err.WithServiceID("service-1").StackTrace()) != len(trace) {
+		t.Error("WithServiceID should keep stack trace")
+	}
+}
+
+func TestFrameworkError_Format(t *testing.T) {
+	cause := NewFrameworkError(ConnectionError, "连接失败")
+	err := Wrap(cause, ServiceUnavailable, "服务不可用")
+
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("%%v = %v, want %v", got, err.Error())
+	}
+	if got := fmt.Sprintf("%s", err); got != err.Error() {
+		t.Errorf("%%s = %v, want %v", got, err.Error())
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	for _, want := range []string{err.Error(), "caused by: " + cause.Error(), "framework_error_test.go"} {
+		if !strings.Contains(verbose, want) {
+			t.Errorf("%%+v output missing %q:\n%s", want, verbose)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil, InternalError, "错误") != nil {
+		t.Error("Wrap(nil) should return nil")
+	}
+
+	cause := errors.New("io timeout")
+	err := Wrap(cause, Timeout, "调用超时")
+
+	if err.Code != Timeout {
+		t.Errorf("Code = %v, want %v", err.Code, Timeout)
+	}
+	if err.Cause != cause {
+		t.Errorf("Cause = %v, want %v", err.Cause, cause)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is should find wrapped cause")
+	}
+	if len(err.ErrorChain) != 2 {
+		t.Errorf("ErrorChain length = %v, want 2", len(err.ErrorChain))
+	}
+}
+
+func TestFrameworkError_Is(t *testing.T) {
+	inner := NewFrameworkError(NotFound, "用户未找到")
+	outer := fmt.Errorf("load profile: %w", Wrap(inner, InternalError, "操作失败"))
+
+	tests := []struct {
+		name   string
+		target error
+		want   bool
+	}{
+		{"同一错误", inner, true},
+		{"外层错误码", NewFrameworkError(InternalError, ""), true},
+		{"内层错误码", NewFrameworkError(NotFound, ""), true},
+		{"错误码和消息", NewFrameworkError(NotFound, "用户未找到"), true},
+		{"消息不一致", NewFrameworkError(NotFound, "订单未找到"), false},
+		{"错误码不存在", NewFrameworkError(Timeout, ""), false},
+		{"非框架错误", errors.New("用户未找到"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(outer, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameworkError_As(t *testing.T) {
+	err := fmt.Errorf("request failed: %w", NewFrameworkError(Unauthorized, "令牌过期"))
+
+	var fe *FrameworkError
+	if !errors.As(err, &fe) {
+		t.Fatal("errors.As should find FrameworkError")
+	}
+	if fe.Code != Unauthorized {
+		t.Errorf("Code = %v, want %v", fe.Code, Unauthorized)
+	}
+}
+
+func TestFrameworkError_WithDetail(t *testing.T) {
+	err := NewFrameworkError(BadRequest, "参数错误")
+	detailed := err.WithDetail("field", "email").WithDetail("attempt", 3)
+
+	if len(err.Fields) != 0 {
+		t.Errorf("original Fields = %v, want empty", err.Fields)
+	}
+	if detailed.Fields["field"] != "email" || detailed.Fields["attempt"] != 3 {
+		t.Errorf("Fields = %v", detailed.Fields)
+	}
+
+	// 基于同一错误添加的详情互不影响
+	other := detailed.WithDetail("field", "phone")
+	if detailed.Fields["field"] != "email" {
+		t.Errorf("Fields[field] = %v, want email", detailed.Fields["field"])
+	}
+	if other.Fields["field"] != "phone" {
+		t.Errorf("Fields[field] = %v, want phone", other.Fields["field"])
+	}
+
+	response := detailed.ToErrorResponse()
+	fields, ok := response["fields"].(map[string]interface{})
+	if !ok || fields["attempt"] != 3 {
+		t.Errorf("response[fields] = %v", response["fields"])
+	}
+	if _, ok := err.ToErrorResponse()["fields"]; ok {
+		t.Error("response should not contain fields when empty")
+	}
+}
+
+// convertibleError 可通过 As 方法转换为 FrameworkError 的其他包错误类型
+type convertibleError struct {
+	code ErrorCode
+}
+
+func (e *convertibleError) Error() string { return e.code.String() }
+
+func (e *convertibleError) As(target interface{}) bool {
+	if t, ok := target.(**FrameworkError); ok {
+		*t = NewFrameworkError(e.code, "")
+		return true
+	}
+	return false
+}
+
+func TestFrameworkError_IsConvertibleTarget(t *testing.T) {
+	err := NewFrameworkError(RoutingError, "没有可用端点")
+
+	if !errors.Is(err, &convertibleError{code: RoutingError}) {
+		t.Error("errors.Is should match convertible target with same code")
+	}
+	if errors.Is(err, &convertibleError{code: NotFound}) {
+		t.Error("errors.Is should not match convertible target with different code")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantOK   bool
+		wantCode ErrorCode
+	}{
+		{"nil", nil, false, 0},
+		{"普通错误", errors.New("boom"), false, 0},
+		{"框架错误", NewFrameworkError(Timeout, "超时"), true, Timeout},
+		{"包装的框架错误", fmt.Errorf("call: %w", NewFrameworkError(ConnectionError, "连接失败")), true, ConnectionError},
+		{"可转换的错误", fmt.Errorf("route: %w", &convertibleError{code: RoutingError}), true, RoutingError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fe, ok := FromError(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("FromError() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && fe.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", fe.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestFrameworkError_WithRetryAfter(t *testing.T) {
+	err := NewFrameworkError(ServiceUnavailable, "过载").WithRetryAfter(1500 * time.Millisecond)
+	if err.Fields[FieldRetryAfter] != 2 {
+		t.Errorf("retryAfter = %v, want 2", err.Fields[FieldRetryAfter])
+	}
+	if d, ok := RetryAfter(fmt.Errorf("call: %w", err)); !ok || d != 2*time.Second {
+		t.Errorf("RetryAfter = %v, %v, want 2s", d, ok)
+	}
+
+	// 经 JSON 传输后数值为 float64
+	if d, ok := RetryAfterFromFields(map[string]interface{}{FieldRetryAfter: float64(3)}); !ok || d != 3*time.Second {
+		t.Errorf("RetryAfterFromFields = %v, %v, want 3s", d, ok)
+	}
+	if _, ok := RetryAfter(NewFrameworkError(ServiceUnavailable, "过载")); ok {
+		t.Error("Expected no retry-after without detail")
+	}
+}
@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 内置支持的语言
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEnUS = "en-US"
+)
+
+// DefaultLocale 未指定语言或请求的语言都不受支持时使用的语言
+const DefaultLocale = LocaleEnUS
+
+// messageCatalog 各语言下错误码对应的消息
+var (
+	catalogMu      sync.RWMutex
+	messageCatalog = map[string]map[ErrorCode]string{
+		LocaleEnUS: {
+			BadRequest:          "The request is invalid",
+			Unauthorized:        "Authentication is required",
+			Forbidden:           "You do not have permission to perform this operation",
+			NotFound:            "The requested resource was not found",
+			Timeout:             "The request timed out",
+			ClientClosedRequest: "The client cancelled the request",
+			InternalError:       "An internal error occurred",
+			NotImplemented:      "The operation is not implemented",
+			ServiceUnavailable:  "The service is temporarily unavailable",
+			ProtocolError:       "A protocol error occurred",
+			SerializationError:  "Failed to serialize or deserialize data",
+			RoutingError:        "No route to the target service",
+			ConnectionError:     "Failed to connect to the target service",
+		},
+		LocaleZhCN: {
+			BadRequest:          "请求参数无效",
+			Unauthorized:        "需要身份认证",
+			Forbidden:           "没有执行该操作的权限",
+			NotFound:            "请求的资源不存在",
+			Timeout:             "请求超时",
+			ClientClosedRequest: "客户端已取消请求",
+			InternalError:       "服务内部错误",
+			NotImplemented:      "该操作尚未实现",
+			ServiceUnavailable:  "服务暂时不可用",
+			ProtocolError:       "协议错误",
+			SerializationError:  "数据序列化或反序列化失败",
+			RoutingError:        "无法路由到目标服务",
+			ConnectionError:     "无法连接目标服务",
+		},
+	}
+)
+
+// RegisterMessages 注册或覆盖某种语言下错误码对应的消息，可用于新增语言
+func RegisterMessages(locale string, messages map[ErrorCode]string) {
+	locale = canonicalLocale(locale)
+	if locale == "" {
+		return
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	catalog, ok := messageCatalog[locale]
+	if !ok {
+		catalog = make(map[ErrorCode]string, len(messages))
+		messageCatalog[locale] = catalog
+	}
+	for code, message := range messages {
+		catalog[code] = message
+	}
+}
+
+// SupportedLocales 返回已注册消息的语言，按名称排序
+func SupportedLocales() []string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	locales := make([]string, 0, len(messageCatalog))
+	for locale := range messageCatalog {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Message 返回错误码在指定语言下的消息
+//
+// 该语言没有对应消息时使用 DefaultLocale，仍然没有时返回错误码名称
+func (e ErrorCode) Message(locale string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	if message, ok := messageCatalog[canonicalLocale(locale)][e]; ok {
+		return message
+	}
+	if message, ok := messageCatalog[DefaultLocale][e]; ok {
+		return message
+	}
+	return e.String()
+}
+
+// MatchLocale 按 Accept-Language 的权重选择支持的语言，都不支持时返回 DefaultLocale
+//
+// 只写语言不写地区时（如 zh）匹配该语言的第一个地区
+func MatchLocale(acceptLanguage string) string {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+	// 权重相同时保持请求中的顺序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	supported := SupportedLocales()
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return DefaultLocale
+		}
+		tag := canonicalLocale(c.tag)
+		for _, locale := range supported {
+			if strings.EqualFold(locale, tag) {
+				return locale
+			}
+		}
+		language := strings.SplitN(tag, "-", 2)[0]
+		for _, locale := range supported {
+			if strings.EqualFold(strings.SplitN(locale, "-", 2)[0], language) {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// localeKey context 中语言的键
+type localeKey struct{}
+
+// WithLocale 将错误消息使用的语言写入 context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext 获取 context 中的语言，未设置时返回空字符串，消息按 DefaultLocale 选择
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// canonicalLocale 规范化语言标签：下划线替换为连字符，语言小写，地区大写（zh_cn -> zh-CN）
+func canonicalLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	if parts[0] == "" {
+		return ""
+	}
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
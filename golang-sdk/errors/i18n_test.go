@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestErrorCode_Message(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     ErrorCode
+		locale   string
+		expected string
+	}{
+		{"中文", NotFound, LocaleZhCN, "请求的资源不存在"},
+		{"英文", NotFound, LocaleEnUS, "The requested resource was not found"},
+		{"非规范写法", Timeout, "zh_cn", "请求超时"},
+		{"不支持的语言回退到默认语言", Timeout, "fr-FR", "The request timed out"},
+		{"空语言使用默认语言", InternalError, "", "An internal error occurred"},
+		{"未知错误码返回名称", ErrorCode(999), LocaleZhCN, "Unknown Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.code.Message(tt.locale); got != tt.expected {
+				t.Errorf("Message(%q) = %v, want %v", tt.locale, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"精确匹配", "zh-CN", LocaleZhCN},
+		{"忽略大小写", "ZH-cn", LocaleZhCN},
+		{"按权重选择", "en-US;q=0.5, zh-CN;q=0.9", LocaleZhCN},
+		{"只写语言", "zh", LocaleZhCN},
+		{"语言匹配其他地区", "zh-TW,zh;q=0.9", LocaleZhCN},
+		{"跳过不支持的语言", "fr-FR, en;q=0.8", LocaleEnUS},
+		{"权重为 0 的语言不可用", "zh-CN;q=0, fr", DefaultLocale},
+		{"通配符", "*", DefaultLocale},
+		{"空值", "", DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchLocale(tt.acceptLanguage); got != tt.expected {
+				t.Errorf("MatchLocale(%q) = %v, want %v", tt.acceptLanguage, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	RegisterMessages("ja_jp", map[ErrorCode]string{NotFound: "リソースが見つかりません"})
+	defer func() {
+		catalogMu.Lock()
+		delete(messageCatalog, "ja-JP")
+		catalogMu.Unlock()
+	}()
+
+	if got := NotFound.Message("ja-JP"); got != "リソースが見つかりません" {
+		t.Errorf("Message() = %v", got)
+	}
+	// 新语言未注册的错误码回退到默认语言
+	if got := Timeout.Message("ja-JP"); got != "The request timed out" {
+		t.Errorf("Message() = %v", got)
+	}
+	if got := MatchLocale("ja"); got != "ja-JP" {
+		t.Errorf("MatchLocale() = %v, want ja-JP", got)
+	}
+	if got := SupportedLocales(); !reflect.DeepEqual(got, []string{LocaleEnUS, "ja-JP", LocaleZhCN}) {
+		t.Errorf("SupportedLocales() = %v", got)
+	}
+}
+
+func TestLocaleContext(t *testing.T) {
+	if got := LocaleFromContext(context.Background()); got != "" {
+		t.Errorf("LocaleFromContext() = %v, want empty", got)
+	}
+	ctx := WithLocale(context.Background(), LocaleZhCN)
+	if got := LocaleFromContext(ctx); got != LocaleZhCN {
+		t.Errorf("LocaleFromContext() = %v, want %v", got, LocaleZhCN)
+	}
+}
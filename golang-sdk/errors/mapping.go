@@ -0,0 +1,157 @@
+package errors
+
+import "fmt"
+
+// MappingFormatVersion 错误码映射表格式的版本，格式不兼容地变化时递增
+const MappingFormatVersion = 1
+
+// jsonRpcProbeCodes 映射表中列出的 JSON-RPC 错误码：规范定义的错误码和实现自定义服务端错误的上下界
+var jsonRpcProbeCodes = []int{-32700, -32600, -32601, -32602, -32603, -32000, -32099}
+
+// CodeMapping 一个错误码的定义及其在各协议下的映射
+type CodeMapping struct {
+	Code        int    `json:"code"`
+	Name        string `json:"name"`
+	Retryable   bool   `json:"retryable"`
+	Severity    string `json:"severity"`
+	HTTPStatus  int    `json:"httpStatus"`
+	GRPCStatus  int    `json:"grpcStatus"`
+	JSONRPCCode int    `json:"jsonRpcCode"`
+	// Builtin 是否为框架内置错误码，其他语言的 SDK 必须与内置错误码完全一致
+	Builtin bool `json:"builtin"`
+}
+
+// InboundMapping 收到其他协议的状态码时映射到的错误码
+type InboundMapping struct {
+	Status int `json:"status"`
+	Code   int `json:"code"`
+}
+
+// MappingTable 错误码映射表，Go SDK 是各语言 SDK 错误码映射的基准
+//
+// Codes 为 ToHTTPStatus、ToGRPCStatus、ToJSONRPCCode 的结果；FromHTTP 覆盖 HTTP 400~599，FromGRPC 覆盖
+// gRPC 状态码 0~16，FromJSONRPC 覆盖规范定义的错误码，分别为 FromHTTPStatus、FromGRPCStatus、FromJSONRPCCode 的结果
+type MappingTable struct {
+	Version     int              `json:"version"`
+	Codes       []CodeMapping    `json:"codes"`
+	FromHTTP    []InboundMapping `json:"fromHttp"`
+	FromGRPC    []InboundMapping `json:"fromGrpc"`
+	FromJSONRPC []InboundMapping `json:"fromJsonRpc"`
+}
+
+// Mappings 返回当前的错误码映射表，包括已注册的自定义错误码
+func Mappings() *MappingTable {
+	table := &MappingTable{Version: MappingFormatVersion}
+	for _, code := range RegisteredCodes() {
+		def := code.definition()
+		table.Codes = append(table.Codes, CodeMapping{
+			Code:        int(code),
+			Name:        def.Name,
+			Retryable:   def.Retryable,
+			Severity:    def.Severity.String(),
+			HTTPStatus:  def.HTTPStatus,
+			GRPCStatus:  def.GRPCStatus,
+			JSONRPCCode: def.JSONRPCCode,
+			Builtin:     IsBuiltinCode(code),
+		})
+	}
+	for status := 400; status < 600; status++ {
+		table.FromHTTP = append(table.FromHTTP, InboundMapping{Status: status, Code: int(FromHTTPStatus(status))})
+	}
+	for status := 0; status <= 16; status++ {
+		table.FromGRPC = append(table.FromGRPC, InboundMapping{Status: status, Code: int(FromGRPCStatus(status))})
+	}
+	for _, status := range jsonRpcProbeCodes {
+		table.FromJSONRPC = append(table.FromJSONRPC, InboundMapping{Status: status, Code: int(FromJSONRPCCode(status))})
+	}
+	return table
+}
+
+// MappingMismatch 其他 SDK 的映射表与 Go SDK 不一致的一项
+type MappingMismatch struct {
+	// Table 不一致的表：codes、fromHttp、fromGrpc 或 fromJsonRpc，格式版本不同时为 version
+	Table string `json:"table"`
+	// Key 错误码（codes）或其他协议的状态码
+	Key int `json:"key"`
+	// Field 不一致的字段，缺少或多出整项时为空
+	Field string      `json:"field,omitempty"`
+	Want  interface{} `json:"want"`
+	Got   interface{} `json:"got"`
+}
+
+// String 返回不一致项的说明
+func (m MappingMismatch) String() string {
+	if m.Table == "version" {
+		return fmt.Sprintf("version: want %v, got %v", m.Want, m.Got)
+	}
+	if m.Field == "" {
+		return fmt.Sprintf("%s[%d]: want %v, got %v", m.Table, m.Key, m.Want, m.Got)
+	}
+	return fmt.Sprintf("%s[%d].%s: want %v, got %v", m.Table, m.Key, m.Field, m.Want, m.Got)
+}
+
+// VerifyMappings 以当前的映射表为准校验其他 SDK 导出的映射表，返回所有不一致项，一致时返回空
+//
+// 内置错误码和各协议状态码的映射必须完全一致，缺少时报告为不一致；自定义错误码只在双方都注册时比较，
+// 因为各服务注册的自定义错误码可以不同。other 中多出的错误码不报告
+func VerifyMappings(other *MappingTable) []MappingMismatch {
+	want := Mappings()
+	var mismatches []MappingMismatch
+	if other.Version != want.Version {
+		mismatches = append(mismatches, MappingMismatch{Table: "version", Want: want.Version, Got: other.Version})
+	}
+
+	got := make(map[int]CodeMapping, len(other.Codes))
+	for _, mapping := range other.Codes {
+		got[mapping.Code] = mapping
+	}
+	for _, w := range want.Codes {
+		g, ok := got[w.Code]
+		if !ok {
+			if w.Builtin {
+				mismatches = append(mismatches, MappingMismatch{Table: "codes", Key: w.Code, Want: w.Name, Got: nil})
+			}
+			continue
+		}
+		fields := []struct {
+			name      string
+			want, got interface{}
+		}{
+			{"name", w.Name, g.Name},
+			{"retryable", w.Retryable, g.Retryable},
+			{"severity", w.Severity, g.Severity},
+			{"httpStatus", w.HTTPStatus, g.HTTPStatus},
+			{"grpcStatus", w.GRPCStatus, g.GRPCStatus},
+			{"jsonRpcCode", w.JSONRPCCode, g.JSONRPCCode},
+		}
+		for _, f := range fields {
+			if f.want != f.got {
+				mismatches = append(mismatches, MappingMismatch{Table: "codes", Key: w.Code, Field: f.name, Want: f.want, Got: f.got})
+			}
+		}
+	}
+
+	mismatches = append(mismatches, verifyInbound("fromHttp", want.FromHTTP, other.FromHTTP)...)
+	mismatches = append(mismatches, verifyInbound("fromGrpc", want.FromGRPC, other.FromGRPC)...)
+	mismatches = append(mismatches, verifyInbound("fromJsonRpc", want.FromJSONRPC, other.FromJSONRPC)...)
+	return mismatches
+}
+
+// verifyInbound 比较一张入站映射表
+func verifyInbound(table string, want, other []InboundMapping) []MappingMismatch {
+	got := make(map[int]int, len(other))
+	for _, mapping := range other {
+		got[mapping.Status] = mapping.Code
+	}
+	var mismatches []MappingMismatch
+	for _, w := range want {
+		g, ok := got[w.Status]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, MappingMismatch{Table: table, Key: w.Status, Want: w.Code, Got: nil})
+		case g != w.Code:
+			mismatches = append(mismatches, MappingMismatch{Table: table, Key: w.Status, Field: "code", Want: w.Code, Got: g})
+		}
+	}
+	return mismatches
+}
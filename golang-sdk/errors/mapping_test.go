@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestMappingsMatchFile error_codes.json 是供其他语言 SDK 测试时读取的映射表，必须与代码一致
+func TestMappingsMatchFile(t *testing.T) {
+	data, err := os.ReadFile("error_codes.json")
+	if err != nil {
+		t.Fatalf("Failed to read error_codes.json: %v", err)
+	}
+	var table MappingTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		t.Fatalf("Failed to decode error_codes.json: %v", err)
+	}
+
+	for _, mismatch := range VerifyMappings(&table) {
+		t.Errorf("error_codes.json is out of date (regenerate with frameworkctl error-codes): %s", mismatch)
+	}
+	builtin := 0
+	for _, mapping := range table.Codes {
+		if mapping.Builtin {
+			builtin++
+		}
+	}
+	if builtin != len(builtinDefinitions) || builtin != len(table.Codes) {
+		t.Errorf("Expected only the %d builtin codes, got %d codes (%d builtin)", len(builtinDefinitions), len(table.Codes), builtin)
+	}
+}
+
+func TestVerifyMappings(t *testing.T) {
+	if mismatches := VerifyMappings(Mappings()); len(mismatches) != 0 {
+		t.Fatalf("Expected no mismatches against itself, got %v", mismatches)
+	}
+
+	other := Mappings()
+	var codes []CodeMapping
+	for _, mapping := range other.Codes {
+		switch code := ErrorCode(mapping.Code); {
+		case code == NotFound:
+			mapping.GRPCStatus = 2
+			mapping.Retryable = true
+		case code == Forbidden:
+			continue // 缺少内置错误码
+		case !mapping.Builtin:
+			continue // 缺少自定义错误码不报告
+		}
+		codes = append(codes, mapping)
+	}
+	other.Codes = codes
+	other.FromHTTP[29].Code = int(InternalError) // 429
+	other.FromGRPC = other.FromGRPC[:16]         // 缺少 UNAUTHENTICATED
+
+	got := make(map[string]bool)
+	for _, mismatch := range VerifyMappings(other) {
+		got[mismatch.String()] = true
+	}
+	for _, want := range []string{
+		"codes[404].grpcStatus: want 5, got 2",
+		"codes[404].retryable: want false, got true",
+		"codes[403]: want Forbidden, got <nil>",
+		"fromHttp[429].code: want 400, got 500",
+		"fromGrpc[16]: want 401, got <nil>",
+	} {
+		if !got[want] {
+			t.Errorf("Expected mismatch %q, got %v", want, got)
+		}
+	}
+	if len(got) != 5 {
+		t.Errorf("Expected 5 mismatches, got %v", got)
+	}
+}
@@ -0,0 +1,240 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TargetError 批量或扇出调用中单个目标的错误
+type TargetError struct {
+	Index    int    // 在批量请求中的序号，从 0 开始
+	Service  string // 目标服务
+	Method   string // 调用的方法
+	Endpoint string // 目标端点地址
+	Attempt  int    // 已尝试次数，包括重试
+	Err      error  // 错误
+}
+
+// Error 实现 error 接口
+func (e *TargetError) Error() string {
+	target := e.Service
+	if e.Method != "" {
+		if target != "" {
+			target += "."
+		}
+		target += e.Method
+	}
+	if e.Endpoint != "" {
+		if target != "" {
+			target += "@"
+		}
+		target += e.Endpoint
+	}
+	if target == "" {
+		return fmt.Sprintf("#%d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("#%d %s: %v", e.Index, target, e.Err)
+}
+
+// Unwrap 返回目标的错误
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// Code 返回目标错误的错误码，非框架错误为 InternalError
+func (e *TargetError) Code() ErrorCode {
+	if fe, ok := FromError(e.Err); ok {
+		return fe.Code
+	}
+	return InternalError
+}
+
+// MultiError 批量或扇出调用的聚合错误，按目标记录每个失败的调用
+//
+// 可以并发调用 Add。errors.Is 对任一目标的错误匹配；errors.As 转换为 *FrameworkError 时得到聚合后的框架错误，
+// 错误码为各目标共同的错误码，不一致时为 InternalError
+type MultiError struct {
+	Total int // 批量请求的总数
+
+	mu     sync.Mutex
+	errors []*TargetError
+}
+
+// NewMultiError 创建聚合错误，total 为批量请求的总数
+func NewMultiError(total int) *MultiError {
+	return &MultiError{Total: total}
+}
+
+// Add 记录一个目标的错误，err 为 nil 时忽略
+func (m *MultiError) Add(target TargetError) {
+	if target.Err == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.errors = append(m.errors, &target)
+}
+
+// Errors 返回各目标的错误，按序号排序
+func (m *MultiError) Errors() []*TargetError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errs := make([]*TargetError, len(m.errors))
+	copy(errs, m.errors)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+	return errs
+}
+
+// Len 返回失败的目标数
+func (m *MultiError) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.errors)
+}
+
+// ErrorOrNil 没有失败的目标时返回 nil，避免返回非 nil 的空聚合错误
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+// Error 实现 error 接口
+func (m *MultiError) Error() string {
+	errs := m.Errors()
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s: %s", m.summary(len(errs)), strings.Join(messages, "; "))
+}
+
+// Unwrap 返回各目标的错误，供 errors.Is 逐个匹配
+func (m *MultiError) Unwrap() []error {
+	errs := m.Errors()
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// As 支持 errors.As 转换为聚合后的 *FrameworkError
+func (m *MultiError) As(target interface{}) bool {
+	if fe, ok := target.(**FrameworkError); ok {
+		*fe = m.ToFrameworkError()
+		return true
+	}
+	return false
+}
+
+// Code 返回聚合错误码：各目标错误码一致时为该错误码，否则为 InternalError
+func (m *MultiError) Code() ErrorCode {
+	errs := m.Errors()
+	if len(errs) == 0 {
+		return InternalError
+	}
+	code := errs[0].Code()
+	for _, err := range errs[1:] {
+		if err.Code() != code {
+			return InternalError
+		}
+	}
+	return code
+}
+
+// ToFrameworkError 转换为框架错误，原因错误为 m，fields 中记录总数和失败数
+func (m *MultiError) ToFrameworkError() *FrameworkError {
+	failed := m.Len()
+	fe := newFrameworkError(m.Code(), m.summary(failed), "", "", m)
+	fe.Fields = map[string]interface{}{
+		"total":  m.Total,
+		"failed": failed,
+	}
+	return fe
+}
+
+// summary 返回聚合错误的概要消息
+func (m *MultiError) summary(failed int) string {
+	if m.Total > 0 {
+		return fmt.Sprintf("%d of %d requests failed", failed, m.Total)
+	}
+	return fmt.Sprintf("%d requests failed", failed)
+}
+
+// ItemPayload 聚合错误中单个目标错误的传输格式
+type ItemPayload struct {
+	Index    int           `json:"index"`
+	Service  string        `json:"service,omitempty"`
+	Method   string        `json:"method,omitempty"`
+	Endpoint string        `json:"endpoint,omitempty"`
+	Attempt  int           `json:"attempt,omitempty"`
+	Status   int           `json:"status"`
+	Error    *ErrorPayload `json:"error"`
+}
+
+// itemPayloads 转换各目标错误为传输格式，status 为目标错误码对应的 HTTP 状态码
+func (m *MultiError) itemPayloads() []*ItemPayload {
+	errs := m.Errors()
+	items := make([]*ItemPayload, len(errs))
+	for i, err := range errs {
+		payload := PayloadFromError(err.Err)
+		items[i] = &ItemPayload{
+			Index:    err.Index,
+			Service:  err.Service,
+			Method:   err.Method,
+			Endpoint: err.Endpoint,
+			Attempt:  err.Attempt,
+			Status:   ErrorCode(payload.Code).ToHTTPStatus(),
+			Error:    payload,
+		}
+	}
+	return items
+}
+
+// multiErrorFromPayload 从传输格式还原聚合错误
+func multiErrorFromPayload(p *ErrorPayload) *MultiError {
+	total := len(p.Items)
+	if value, ok := p.Fields["total"]; ok {
+		switch v := value.(type) {
+		case int:
+			total = v
+		case float64:
+			total = int(v)
+		}
+	}
+
+	multi := NewMultiError(total)
+	for _, item := range p.Items {
+		var err error = NewFrameworkError(InternalError, "")
+		if item.Error != nil {
+			err = item.Error.ToFrameworkError()
+		}
+		multi.Add(TargetError{
+			Index:    item.Index,
+			Service:  item.Service,
+			Method:   item.Method,
+			Endpoint: item.Endpoint,
+			Attempt:  item.Attempt,
+			Err:      err,
+		})
+	}
+	return multi
+}
+
+// findMultiError 在错误链上查找聚合错误
+func findMultiError(err error) (*MultiError, bool) {
+	var multi *MultiError
+	if err == nil || !stderrors.As(err, &multi) {
+		return nil, false
+	}
+	return multi, true
+}
@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMultiError_Add(t *testing.T) {
+	multi := NewMultiError(5)
+	if multi.ErrorOrNil() != nil {
+		t.Fatal("ErrorOrNil() should return nil without errors")
+	}
+
+	// 并发记录各目标的错误
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			var err error
+			if index%2 == 0 {
+				err = NewFrameworkError(Timeout, fmt.Sprintf("call %d timed out", index))
+			}
+			multi.Add(TargetError{Index: index, Service: "inventory", Endpoint: "10.0.0.1:8080", Attempt: 3, Err: err})
+		}(i)
+	}
+	wg.Wait()
+
+	errs := multi.Errors()
+	if len(errs) != 3 || errs[0].Index != 0 || errs[1].Index != 2 || errs[2].Index != 4 {
+		t.Fatalf("Errors() = %v", errs)
+	}
+	if multi.ErrorOrNil() == nil {
+		t.Error("ErrorOrNil() should return error")
+	}
+	if multi.Code() != Timeout {
+		t.Errorf("Code() = %v, want Timeout", multi.Code())
+	}
+	if !strings.HasPrefix(multi.Error(), "3 of 5 requests failed: #0 inventory@10.0.0.1:8080: ") {
+		t.Errorf("Error() = %v", multi.Error())
+	}
+}
+
+func TestMultiError_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		errs     []error
+		expected ErrorCode
+	}{
+		{"错误码一致", []error{NewFrameworkError(NotFound, "a"), NewFrameworkError(NotFound, "b")}, NotFound},
+		{"错误码不一致", []error{NewFrameworkError(NotFound, "a"), NewFrameworkError(Timeout, "b")}, InternalError},
+		{"非框架错误", []error{errors.New("boom")}, InternalError},
+		{"无错误", nil, InternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multi := NewMultiError(len(tt.errs))
+			for i, err := range tt.errs {
+				multi.Add(TargetError{Index: i, Err: err})
+			}
+			if got := multi.Code(); got != tt.expected {
+				t.Errorf("Code() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMultiError_IsAs(t *testing.T) {
+	multi := NewMultiError(2)
+	multi.Add(TargetError{Index: 0, Service: "user", Err: NewFrameworkError(NotFound, "user not found")})
+	multi.Add(TargetError{Index: 1, Service: "order", Err: NewFrameworkError(ConnectionError, "connection refused")})
+	err := fmt.Errorf("fan-out: %w", multi)
+
+	// errors.Is 对任一目标的错误匹配
+	if !errors.Is(err, NewFrameworkError(ConnectionError, "")) {
+		t.Error("errors.Is should match target error")
+	}
+	if errors.Is(err, NewFrameworkError(Timeout, "")) {
+		t.Error("errors.Is should not match absent code")
+	}
+
+	// errors.As 得到聚合后的框架错误
+	fe, ok := FromError(err)
+	if !ok || fe.Code != InternalError || fe.Message != "2 of 2 requests failed" {
+		t.Fatalf("FromError() = %v, %v", fe, ok)
+	}
+	var got *MultiError
+	if !errors.As(fe, &got) || got != multi {
+		t.Error("aggregated error should wrap MultiError")
+	}
+}
+
+func TestMultiError_Payload(t *testing.T) {
+	multi := NewMultiError(3)
+	multi.Add(TargetError{Index: 2, Service: "order", Method: "create", Endpoint: "10.0.0.2:9090", Attempt: 3, Err: NewFrameworkError(ServiceUnavailable, "no healthy instance")})
+	multi.Add(TargetError{Index: 0, Service: "user", Attempt: 1, Err: errors.New("boom")})
+
+	data, err := MarshalError(multi)
+	if err != nil {
+		t.Fatalf("MarshalError failed: %v", err)
+	}
+
+	var wire struct {
+		Code   int                    `json:"code"`
+		Fields map[string]interface{} `json:"fields"`
+		Items  []struct {
+			Index    int    `json:"index"`
+			Service  string `json:"service"`
+			Method   string `json:"method"`
+			Endpoint string `json:"endpoint"`
+			Attempt  int    `json:"attempt"`
+			Status   int    `json:"status"`
+			Error    struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if wire.Code != 500 || wire.Fields["total"] != float64(3) || wire.Fields["failed"] != float64(2) || len(wire.Items) != 2 {
+		t.Fatalf("payload = %s", data)
+	}
+	first, second := wire.Items[0], wire.Items[1]
+	if first.Index != 0 || first.Status != 500 || first.Error.Code != 500 || first.Error.Message != "boom" {
+		t.Errorf("items[0] = %+v", first)
+	}
+	if second.Index != 2 || second.Service != "order" || second.Method != "create" || second.Endpoint != "10.0.0.2:9090" || second.Attempt != 3 || second.Status != 503 {
+		t.Errorf("items[1] = %+v", second)
+	}
+
+	// 解码后还原聚合错误
+	fe, err := UnmarshalError(data)
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+	var restored *MultiError
+	if !errors.As(fe.Cause, &restored) {
+		t.Fatalf("Cause = %v, want *MultiError", fe.Cause)
+	}
+	errs := restored.Errors()
+	if restored.Total != 3 || len(errs) != 2 || errs[1].Service != "order" || errs[1].Method != "create" || errs[1].Code() != ServiceUnavailable {
+		t.Errorf("restored = %v", restored)
+	}
+	if !errors.Is(fe, NewFrameworkError(ServiceUnavailable, "")) {
+		t.Error("restored error should match item code")
+	}
+	if !strings.Contains(restored.Error(), "#2 order.create@10.0.0.2:9090: ") {
+		t.Errorf("Error() = %v", restored.Error())
+	}
+}
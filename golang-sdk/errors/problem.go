@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// RFC 7807 Problem Details 媒体类型
+const (
+	ProblemJSONContentType = "application/problem+json"
+	ProblemXMLContentType  = "application/problem+xml"
+)
+
+// DefaultProblemTypeBase problem 类型 URI 的默认前缀，后接错误码，如 urn:framework:error:404
+const DefaultProblemTypeBase = "urn:framework:error:"
+
+// ProblemDetails RFC 7807 Problem Details 格式的错误
+//
+// type、title、status、detail、instance 为标准成员，instance 为追踪 ID；
+// 其余为扩展成员，与 ErrorPayload 的同名字段一致，转换回 ErrorPayload 时不丢失信息
+type ProblemDetails struct {
+	XMLName  xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string   `json:"type" xml:"type"`
+	Title    string   `json:"title" xml:"title"`
+	Status   int      `json:"status" xml:"status"`
+	Detail   string   `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty" xml:"instance,omitempty"`
+
+	Code             int                    `json:"code" xml:"code"`
+	Retryable        bool                   `json:"retryable" xml:"retryable"`
+	Details          string                 `json:"details,omitempty" xml:"details,omitempty"`
+	Fields           map[string]interface{} `json:"fields,omitempty" xml:"-"`
+	ServiceID        string                 `json:"serviceId,omitempty" xml:"serviceId,omitempty"`
+	Timestamp        int64                  `json:"timestamp" xml:"timestamp"`
+	ErrorChain       []string               `json:"errorChain,omitempty" xml:"errorChain>i,omitempty"`
+	LocalizedMessage string                 `json:"localizedMessage,omitempty" xml:"localizedMessage,omitempty"`
+	Locale           string                 `json:"locale,omitempty" xml:"locale,omitempty"`
+	Items            []*ItemPayload         `json:"items,omitempty" xml:"-"`
+}
+
+// ToProblem 转换为 RFC 7807 Problem Details
+//
+// type 为 typeBase 后接错误码，typeBase 为空时使用 DefaultProblemTypeBase；title 为错误码名称，
+// status 为错误码对应的 HTTP 状态码，detail 为错误消息
+func (p *ErrorPayload) ToProblem(typeBase string) *ProblemDetails {
+	if typeBase == "" {
+		typeBase = DefaultProblemTypeBase
+	}
+	return &ProblemDetails{
+		Type:             typeBase + strconv.Itoa(p.Code),
+		Title:            p.Error,
+		Status:           ErrorCode(p.Code).ToHTTPStatus(),
+		Detail:           p.Message,
+		Instance:         p.TraceID,
+		Code:             p.Code,
+		Retryable:        p.Retryable,
+		Details:          p.Details,
+		Fields:           p.Fields,
+		ServiceID:        p.ServiceID,
+		Timestamp:        p.Timestamp,
+		ErrorChain:       p.ErrorChain,
+		LocalizedMessage: p.LocalizedMessage,
+		Locale:           p.Locale,
+		Items:            p.Items,
+	}
+}
+
+// ToErrorPayload 转换回错误传输格式，可再通过 ToFrameworkError 还原为框架错误
+func (pd *ProblemDetails) ToErrorPayload() *ErrorPayload {
+	return &ErrorPayload{
+		Code:             pd.Code,
+		Error:            pd.Title,
+		Message:          pd.Detail,
+		Details:          pd.Details,
+		Fields:           pd.Fields,
+		Retryable:        pd.Retryable,
+		TraceID:          pd.Instance,
+		ServiceID:        pd.ServiceID,
+		Timestamp:        pd.Timestamp,
+		ErrorChain:       pd.ErrorChain,
+		LocalizedMessage: pd.LocalizedMessage,
+		Locale:           pd.Locale,
+		Items:            pd.Items,
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrorPayload_ToProblem(t *testing.T) {
+	payload := NewFrameworkErrorFull(ServiceUnavailable, "inventory unavailable", "no healthy instance", "inventory", nil).
+		WithTraceID("4bf92f3577b34da6a3ce929d0e0e4736").
+		WithDetail("region", "cn-east").
+		ToPayload()
+
+	data, err := json.Marshal(payload.ToProblem(""))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var wire map[string]interface{}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"type":      "urn:framework:error:503",
+		"title":     "Service Unavailable",
+		"status":    float64(503),
+		"detail":    "inventory unavailable",
+		"instance":  "4bf92f3577b34da6a3ce929d0e0e4736",
+		"code":      float64(503),
+		"retryable": true,
+		"details":   "no healthy instance",
+		"serviceId": "inventory",
+	}
+	for key, want := range expected {
+		if !reflect.DeepEqual(wire[key], want) {
+			t.Errorf("%s = %v, want %v", key, wire[key], want)
+		}
+	}
+
+	// 自定义类型前缀
+	if got := payload.ToProblem("https://errors.example.com/").Type; got != "https://errors.example.com/503" {
+		t.Errorf("Type = %v", got)
+	}
+
+	// 状态码按错误码映射，而非错误码本身
+	if got := NewFrameworkError(RoutingError, "no route").ToPayload().ToProblem("").Status; got != 502 {
+		t.Errorf("Status = %v, want 502", got)
+	}
+}
+
+func TestProblemDetails_RoundTrip(t *testing.T) {
+	original := NewFrameworkErrorWithDetails(Timeout, "调用超时", "3s").
+		WithServiceID("order-service").
+		WithTraceID("trace-1").
+		WithDetail("attempt", 2).
+		ToPayload()
+
+	data, _ := json.Marshal(original.ToProblem(""))
+	var problem ProblemDetails
+	if err := json.Unmarshal(data, &problem); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	fe := problem.ToErrorPayload().ToFrameworkError()
+	if fe.Code != Timeout || fe.Message != "调用超时" || fe.Details != "3s" || fe.ServiceID != "order-service" || fe.TraceID != "trace-1" {
+		t.Errorf("restored = %+v", fe)
+	}
+	if fe.Fields["attempt"] != float64(2) || fe.Timestamp != original.Timestamp {
+		t.Errorf("restored = %+v", fe)
+	}
+}
+
+func TestProblemDetails_XML(t *testing.T) {
+	problem := NewFrameworkError(NotFound, "user not found").WithTraceID("trace-1").ToPayload().ToProblem("")
+
+	data, err := xml.Marshal(problem)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, want := range []string{`<problem xmlns="urn:ietf:rfc:7807">`, "<status>404</status>", "<title>Not Found</title>", "<instance>trace-1</instance>"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("xml %s missing %s", data, want)
+		}
+	}
+}
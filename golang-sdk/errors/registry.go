@@ -0,0 +1,186 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Severity 错误的严重程度，用于日志级别和告警
+type Severity int
+
+const (
+	SeverityInfo Severity = iota + 1
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// String 返回严重程度的字符串表示
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// CodeDefinition 错误码定义：名称、可重试性、各协议下的映射和严重程度
+//
+// 映射为零值时使用默认值：HTTPStatus 为错误码本身（4xx、5xx 时）或 500，GRPCStatus 为 13（INTERNAL），
+// JSONRPCCode 为 -32603（Internal error），Severity 为 SeverityError
+type CodeDefinition struct {
+	Code        ErrorCode
+	Name        string
+	Retryable   bool
+	HTTPStatus  int
+	GRPCStatus  int
+	JSONRPCCode int
+	Severity    Severity
+	// Messages 各语言下的消息，注册时写入消息目录，等同于调用 RegisterMessages
+	Messages map[string]string
+}
+
+// builtinDefinitions 内置错误码定义
+var builtinDefinitions = []CodeDefinition{
+	{Code: BadRequest, Name: "Bad Request", HTTPStatus: 400, GRPCStatus: 3, JSONRPCCode: -32600, Severity: SeverityWarning},
+	{Code: Unauthorized, Name: "Unauthorized", HTTPStatus: 401, GRPCStatus: 16, JSONRPCCode: -32603, Severity: SeverityWarning},
+	{Code: Forbidden, Name: "Forbidden", HTTPStatus: 403, GRPCStatus: 7, JSONRPCCode: -32603, Severity: SeverityWarning},
+	{Code: NotFound, Name: "Not Found", HTTPStatus: 404, GRPCStatus: 5, JSONRPCCode: -32601, Severity: SeverityWarning},
+	{Code: Timeout, Name: "Timeout", Retryable: true, HTTPStatus: 408, GRPCStatus: 4, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: ClientClosedRequest, Name: "Client Closed Request", HTTPStatus: 499, GRPCStatus: 1, JSONRPCCode: -32603, Severity: SeverityInfo},
+	{Code: InternalError, Name: "Internal Error", HTTPStatus: 500, GRPCStatus: 13, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: NotImplemented, Name: "Not Implemented", HTTPStatus: 501, GRPCStatus: 12, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: ServiceUnavailable, Name: "Service Unavailable", Retryable: true, HTTPStatus: 503, GRPCStatus: 14, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: ProtocolError, Name: "Protocol Error", HTTPStatus: 502, GRPCStatus: 13, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: SerializationError, Name: "Serialization Error", HTTPStatus: 400, GRPCStatus: 3, JSONRPCCode: -32700, Severity: SeverityError},
+	{Code: RoutingError, Name: "Routing Error", HTTPStatus: 502, GRPCStatus: 14, JSONRPCCode: -32603, Severity: SeverityError},
+	{Code: ConnectionError, Name: "Connection Error", Retryable: true, HTTPStatus: 503, GRPCStatus: 14, JSONRPCCode: -32603, Severity: SeverityError},
+}
+
+// codeRegistry 已注册的错误码定义，包括内置错误码
+var (
+	registryMu   sync.RWMutex
+	codeRegistry = make(map[ErrorCode]CodeDefinition)
+)
+
+func init() {
+	for _, def := range builtinDefinitions {
+		codeRegistry[def.Code] = def
+	}
+}
+
+// RegisterCode 注册应用自定义的错误码
+//
+// 注册后 ErrorCode 的 String、IsRetryable、Severity 以及 HTTP、gRPC、JSON-RPC 映射按定义返回，
+// 重试策略和协议适配器据此处理该错误码。内置错误码和已注册的错误码不能重复注册
+func RegisterCode(def CodeDefinition) error {
+	if def.Code <= 0 {
+		return fmt.Errorf("invalid error code: %d", def.Code)
+	}
+	if def.Name == "" {
+		return fmt.Errorf("error code %d: name is required", def.Code)
+	}
+	if IsBuiltinCode(def.Code) {
+		return fmt.Errorf("error code %d is reserved", def.Code)
+	}
+
+	if def.HTTPStatus == 0 {
+		def.HTTPStatus = defaultHTTPStatus(def.Code)
+	}
+	if def.GRPCStatus == 0 {
+		def.GRPCStatus = 13 // INTERNAL
+	}
+	if def.JSONRPCCode == 0 {
+		def.JSONRPCCode = -32603
+	}
+	if def.Severity == 0 {
+		def.Severity = SeverityError
+	}
+	messages := def.Messages
+	def.Messages = nil
+
+	registryMu.Lock()
+	if _, exists := codeRegistry[def.Code]; exists {
+		registryMu.Unlock()
+		return fmt.Errorf("error code %d already registered", def.Code)
+	}
+	codeRegistry[def.Code] = def
+	registryMu.Unlock()
+
+	for locale, message := range messages {
+		RegisterMessages(locale, map[ErrorCode]string{def.Code: message})
+	}
+	return nil
+}
+
+// LookupCode 获取错误码定义，包括内置错误码
+func LookupCode(code ErrorCode) (CodeDefinition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	def, ok := codeRegistry[code]
+	return def, ok
+}
+
+// RegisteredCodes 返回所有已注册的错误码，包括内置错误码，按数值排序
+func RegisteredCodes() []ErrorCode {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	codes := make([]ErrorCode, 0, len(codeRegistry))
+	for code := range codeRegistry {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// IsBuiltinCode 判断是否为框架内置错误码
+func IsBuiltinCode(code ErrorCode) bool {
+	for _, def := range builtinDefinitions {
+		if def.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCustomCode 判断是否为通过 RegisterCode 注册的自定义错误码
+func IsCustomCode(code ErrorCode) bool {
+	if IsBuiltinCode(code) {
+		return false
+	}
+	_, ok := LookupCode(code)
+	return ok
+}
+
+// definition 返回错误码定义，未注册的错误码按未知错误处理
+func (e ErrorCode) definition() CodeDefinition {
+	if def, ok := LookupCode(e); ok {
+		return def
+	}
+	return CodeDefinition{
+		Code:        e,
+		Name:        "Unknown Error",
+		HTTPStatus:  500,
+		GRPCStatus:  13,
+		JSONRPCCode: -32603,
+		Severity:    SeverityError,
+	}
+}
+
+// defaultHTTPStatus 自定义错误码未指定 HTTP 状态码时使用的状态码
+func defaultHTTPStatus(code ErrorCode) int {
+	if code >= 400 && code < 600 {
+		return int(code)
+	}
+	return 500
+}
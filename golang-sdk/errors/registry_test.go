@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"testing"
+)
+
+// unregisterCode 测试结束后移除注册的错误码
+func unregisterCode(t *testing.T, code ErrorCode) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(codeRegistry, code)
+		registryMu.Unlock()
+	})
+}
+
+func TestRegisterCode(t *testing.T) {
+	const quotaExceeded ErrorCode = 1001
+	unregisterCode(t, quotaExceeded)
+
+	err := RegisterCode(CodeDefinition{
+		Code:       quotaExceeded,
+		Name:       "Quota Exceeded",
+		Retryable:  true,
+		HTTPStatus: 429,
+		GRPCStatus: 8, // RESOURCE_EXHAUSTED
+		Severity:   SeverityWarning,
+		Messages:   map[string]string{LocaleZhCN: "配额已用尽"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"String", quotaExceeded.String(), "Quota Exceeded"},
+		{"IsRetryable", quotaExceeded.IsRetryable(), true},
+		{"Severity", quotaExceeded.Severity(), SeverityWarning},
+		{"ToHTTPStatus", quotaExceeded.ToHTTPStatus(), 429},
+		{"ToGRPCStatus", quotaExceeded.ToGRPCStatus(), 8},
+		{"ToJSONRPCCode 默认值", quotaExceeded.ToJSONRPCCode(), -32603},
+		{"FromCode", FromCode(1001), quotaExceeded},
+		{"IsCustomCode", IsCustomCode(quotaExceeded), true},
+		{"中文消息", quotaExceeded.Message(LocaleZhCN), "配额已用尽"},
+		{"未注册的语言使用名称", quotaExceeded.Message(LocaleEnUS), "Quota Exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %v, want %v", tt.got, tt.expected)
+			}
+		})
+	}
+
+	if err := RegisterCode(CodeDefinition{Code: quotaExceeded, Name: "Duplicate"}); err == nil {
+		t.Error("RegisterCode should reject duplicate code")
+	}
+}
+
+func TestRegisterCode_Defaults(t *testing.T) {
+	const conflict ErrorCode = 409
+	unregisterCode(t, conflict)
+
+	if err := RegisterCode(CodeDefinition{Code: conflict, Name: "Conflict"}); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+
+	def, ok := LookupCode(conflict)
+	if !ok {
+		t.Fatal("LookupCode should find registered code")
+	}
+	if def.HTTPStatus != 409 || def.GRPCStatus != 13 || def.JSONRPCCode != -32603 || def.Severity != SeverityError || def.Retryable {
+		t.Errorf("definition = %+v", def)
+	}
+}
+
+func TestRegisterCode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		def  CodeDefinition
+	}{
+		{"错误码为 0", CodeDefinition{Name: "Zero"}},
+		{"缺少名称", CodeDefinition{Code: 1002}},
+		{"内置错误码", CodeDefinition{Code: Timeout, Name: "My Timeout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterCode(tt.def); err == nil {
+				t.Errorf("RegisterCode(%+v) should fail", tt.def)
+			}
+		})
+	}
+}
+
+func TestErrorCode_Severity(t *testing.T) {
+	tests := []struct {
+		code     ErrorCode
+		expected Severity
+	}{
+		{BadRequest, SeverityWarning},
+		{NotFound, SeverityWarning},
+		{InternalError, SeverityError},
+		{ConnectionError, SeverityError},
+		{ErrorCode(999), SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := tt.code.Severity(); got != tt.expected {
+				t.Errorf("Severity() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisteredCodes(t *testing.T) {
+	codes := RegisteredCodes()
+	if len(codes) != len(builtinDefinitions) || codes[0] != BadRequest || codes[len(codes)-1] != ConnectionError {
+		t.Errorf("RegisteredCodes() = %v", codes)
+	}
+	if IsCustomCode(BadRequest) || !IsBuiltinCode(BadRequest) {
+		t.Error("BadRequest should be builtin")
+	}
+}
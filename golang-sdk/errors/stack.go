@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// maxStackDepth 记录调用栈的最大深度
+const maxStackDepth = 32
+
+// callers 记录当前调用栈，skip 为从调用 callers 的函数开始需要跳过的层数
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// 跳过 runtime.Callers 和 callers 本身
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+// StackTrace 返回创建错误时的调用栈，第一帧为调用构造函数的位置
+func (e *FrameworkError) StackTrace() []runtime.Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(e.stack)
+	trace := make([]runtime.Frame, 0, len(e.stack))
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, frame)
+		if !more {
+			break
+		}
+	}
+	return trace
+}
+
+// Format 实现 fmt.Formatter，%+v 输出错误、调用栈以及原因链上各错误的调用栈
+func (e *FrameworkError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.Error())
+			for _, frame := range e.StackTrace() {
+				fmt.Fprintf(s, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+			if e.Cause != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", e.Cause)
+			}
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrorPayload 框架错误的跨语言传输格式
+//
+// 字段与 ToErrorResponse 以及 Java、PHP SDK 的错误响应一致。各协议统一使用该结构传递错误：
+// REST 错误响应体、JSON-RPC error.data、gRPC status details 和自定义协议 ERROR 帧的帧体
+type ErrorPayload struct {
+	Code       int                    `json:"code"`
+	Error      string                 `json:"error"`
+	Message    string                 `json:"message"`
+	Details    string                 `json:"details,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Retryable  bool                   `json:"retryable"`
+	TraceID    string                 `json:"traceId,omitempty"`
+	ServiceID  string                 `json:"serviceId,omitempty"`
+	Timestamp  int64                  `json:"timestamp"`
+	ErrorChain []string               `json:"errorChain,omitempty"`
+	// LocalizedMessage 错误码在 Locale 语言下的消息，供展示给最终用户
+	LocalizedMessage string `json:"localizedMessage,omitempty"`
+	Locale           string `json:"locale,omitempty"`
+	// Items 聚合错误（MultiError）中各目标的错误
+	Items []*ItemPayload `json:"items,omitempty"`
+}
+
+// ToPayload 转换为跨语言传输格式，原因错误只以错误链的形式传递
+//
+// 原因错误为聚合错误时，各目标的错误放在 items 中
+func (e *FrameworkError) ToPayload() *ErrorPayload {
+	payload := &ErrorPayload{
+		Code:       e.Code.Code(),
+		Error:      e.Code.String(),
+		Message:    e.Message,
+		Details:    e.Details,
+		Fields:     e.Fields,
+		Retryable:  e.Code.IsRetryable(),
+		TraceID:    e.TraceID,
+		ServiceID:  e.ServiceID,
+		Timestamp:  e.Timestamp,
+		ErrorChain: e.ErrorChain,
+	}
+	if multi, ok := findMultiError(e.Cause); ok {
+		payload.Items = multi.itemPayloads()
+	}
+	return payload
+}
+
+// Localize 按指定语言填写 LocalizedMessage，Message 为空时同样使用该消息，返回 p 本身
+//
+// locale 为空时使用 DefaultLocale。error 字段始终为英文的错误码名称，便于各语言按名称识别错误。
+// items 中各目标的错误同样按该语言填写
+func (p *ErrorPayload) Localize(locale string) *ErrorPayload {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	p.Locale = locale
+	p.LocalizedMessage = ErrorCode(p.Code).Message(locale)
+	if p.Message == "" {
+		p.Message = p.LocalizedMessage
+	}
+	for _, item := range p.Items {
+		if item.Error != nil {
+			item.Error.Localize(locale)
+		}
+	}
+	return p
+}
+
+// ToFrameworkError 还原为框架错误，调用栈从调用 ToFrameworkError 的位置记录
+//
+// 错误码原样保留，即使本端不认识该错误码；Retryable 由错误码决定，不读取传输的值。
+// 有 items 时原因错误为还原的 *MultiError
+func (p *ErrorPayload) ToFrameworkError() *FrameworkError {
+	var cause error
+	if len(p.Items) > 0 {
+		cause = multiErrorFromPayload(p)
+	}
+	fe := newFrameworkError(ErrorCode(p.Code), p.Message, p.Details, p.ServiceID, cause)
+	fe.TraceID = p.TraceID
+	fe.Fields = p.Fields
+	if p.Timestamp != 0 {
+		fe.Timestamp = p.Timestamp
+	}
+	if len(p.ErrorChain) > 0 {
+		fe.ErrorChain = p.ErrorChain
+	}
+	return fe
+}
+
+// PayloadFromError 将任意错误转换为跨语言传输格式
+//
+// 错误链上有框架错误时使用该错误；context 错误按 ContextErrorCode 映射为 Timeout 或 ClientClosedRequest；
+// 其他错误作为 InternalError 传递。后两种情况消息为 err.Error()
+func PayloadFromError(err error) *ErrorPayload {
+	if err == nil {
+		return nil
+	}
+	if fe, ok := FromError(err); ok {
+		return fe.ToPayload()
+	}
+	if code, ok := ContextErrorCode(err); ok {
+		return NewFrameworkError(code, err.Error()).ToPayload()
+	}
+	return NewFrameworkError(InternalError, err.Error()).ToPayload()
+}
+
+// MarshalError 将错误编码为 JSON 传输格式
+func MarshalError(err error) ([]byte, error) {
+	if err == nil {
+		return nil, fmt.Errorf("error cannot be nil")
+	}
+	return json.Marshal(PayloadFromError(err))
+}
+
+// UnmarshalError 解码 JSON 传输格式的错误
+func UnmarshalError(data []byte) (*FrameworkError, error) {
+	var payload ErrorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid error payload: %w", err)
+	}
+	if payload.Code == 0 {
+		return nil, fmt.Errorf("invalid error payload: missing code")
+	}
+	return payload.ToFrameworkError(), nil
+}
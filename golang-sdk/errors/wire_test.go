@@ -0,0 +1,154 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFrameworkError_ToPayload(t *testing.T) {
+	err := NewFrameworkErrorFull(ServiceUnavailable, "服务不可用", "无可用实例", "order-service", NewFrameworkError(ConnectionError, "连接失败")).
+		WithTraceID("4bf92f3577b34da6a3ce929d0e0e4736").
+		WithDetail("instances", 0)
+
+	data, marshalErr := MarshalError(err)
+	if marshalErr != nil {
+		t.Fatalf("MarshalError failed: %v", marshalErr)
+	}
+
+	var wire map[string]interface{}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"code":       float64(503),
+		"error":      "Service Unavailable",
+		"message":    "服务不可用",
+		"details":    "无可用实例",
+		"fields":     map[string]interface{}{"instances": float64(0)},
+		"retryable":  true,
+		"traceId":    "4bf92f3577b34da6a3ce929d0e0e4736",
+		"serviceId":  "order-service",
+		"timestamp":  float64(err.Timestamp),
+		"errorChain": []interface{}{err.ErrorChain[0], err.ErrorChain[1]},
+	}
+	if !reflect.DeepEqual(wire, expected) {
+		t.Errorf("Unexpected payload:\n got: %v\nwant: %v", wire, expected)
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	original := NewFrameworkErrorWithCause(Timeout, "调用超时", errors.New("deadline exceeded")).
+		WithServiceID("user-service").
+		WithTraceID("trace-1").
+		WithDetail("timeoutMs", 3000)
+
+	data, err := MarshalError(fmt.Errorf("invoke: %w", original))
+	if err != nil {
+		t.Fatalf("MarshalError failed: %v", err)
+	}
+
+	decoded, err := UnmarshalError(data)
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+
+	if decoded.Code != Timeout || decoded.Message != "调用超时" || decoded.ServiceID != "user-service" || decoded.TraceID != "trace-1" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if decoded.Timestamp != original.Timestamp {
+		t.Errorf("Timestamp = %v, want %v", decoded.Timestamp, original.Timestamp)
+	}
+	if !reflect.DeepEqual(decoded.ErrorChain, original.ErrorChain) {
+		t.Errorf("ErrorChain = %v, want %v", decoded.ErrorChain, original.ErrorChain)
+	}
+	if decoded.Fields["timeoutMs"] != float64(3000) {
+		t.Errorf("Fields = %v", decoded.Fields)
+	}
+	// 原因错误不跨进程传递
+	if decoded.Cause != nil {
+		t.Errorf("Cause = %v, want nil", decoded.Cause)
+	}
+	if !errors.Is(decoded, NewFrameworkError(Timeout, "")) {
+		t.Error("decoded error should match by code")
+	}
+}
+
+func TestUnmarshalError_UnknownCode(t *testing.T) {
+	// 其他语言定义的错误码原样保留
+	decoded, err := UnmarshalError([]byte(`{"code":429,"error":"Too Many Requests","message":"限流","retryable":true}`))
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+	if decoded.Code != ErrorCode(429) {
+		t.Errorf("Code = %v, want 429", decoded.Code.Code())
+	}
+	if decoded.Timestamp == 0 {
+		t.Error("Timestamp should default to decode time")
+	}
+}
+
+func TestUnmarshalError_Invalid(t *testing.T) {
+	for _, data := range []string{`not json`, `{"message":"缺少错误码"}`, `[]`} {
+		if _, err := UnmarshalError([]byte(data)); err == nil {
+			t.Errorf("UnmarshalError(%s) should fail", data)
+		}
+	}
+}
+
+func TestPayloadFromError(t *testing.T) {
+	if PayloadFromError(nil) != nil {
+		t.Error("PayloadFromError(nil) should return nil")
+	}
+
+	payload := PayloadFromError(errors.New("disk full"))
+	if payload.Code != 500 || payload.Error != "Internal Error" || payload.Message != "disk full" || payload.Retryable {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	if _, err := MarshalError(nil); err == nil {
+		t.Error("MarshalError(nil) should fail")
+	}
+}
+
+func TestErrorPayload_Localize(t *testing.T) {
+	payload := NewFrameworkError(ServiceUnavailable, "inventory unavailable").ToPayload().Localize(LocaleZhCN)
+	if payload.Locale != LocaleZhCN || payload.LocalizedMessage != "服务暂时不可用" {
+		t.Errorf("payload = %+v", payload)
+	}
+	// 原消息和错误码名称保持不变
+	if payload.Message != "inventory unavailable" || payload.Error != "Service Unavailable" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// 消息为空时使用本地化消息
+	payload = NewFrameworkError(NotFound, "").ToPayload().Localize("")
+	if payload.Locale != DefaultLocale || payload.Message != "The requested resource was not found" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestPayloadFromError_Context(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   int
+		errStr string
+	}{
+		{"超时", fmt.Errorf("call inventory: %w", context.DeadlineExceeded), 408, "Timeout"},
+		{"取消", context.Canceled, 499, "Client Closed Request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := PayloadFromError(tt.err)
+			if payload.Code != tt.code || payload.Error != tt.errStr || payload.Message != tt.err.Error() {
+				t.Errorf("payload = %+v", payload)
+			}
+		})
+	}
+}
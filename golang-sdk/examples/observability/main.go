@@ -86,8 +86,7 @@ func main() {
 
 func processRequest(obs *observability.ObservabilityManager, requestID int) {
 	// 创建带请求ID的上下文
-	ctx := context.WithValue(context.Background(), "request_id", fmt.Sprintf("req-%d", requestID))
-	ctx = context.WithValue(ctx, "timestamp", time.Now().Format(time.RFC3339))
+	ctx := observability.WithRequestID(context.Background(), fmt.Sprintf("req-%d", requestID))
 
 	// 记录请求开始
 	obs.Logger().Info(ctx, "收到请求",
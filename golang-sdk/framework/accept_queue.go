@@ -0,0 +1,54 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// newAcceptQueue 按 framework.acceptQueue 为协议处理器创建接收队列，队列名为 <协议>:<端口>；未启用时返回 nil
+func (s *Server) newAcceptQueue(protocol string, port int) *resilience.AcceptQueue {
+	cfg := s.config.AcceptQueue
+	if !cfg.Enabled {
+		return nil
+	}
+	name := fmt.Sprintf("%s:%d", strings.ToLower(strings.ReplaceAll(protocol, " ", "-")), port)
+	return resilience.NewAcceptQueue(name, &resilience.AcceptQueueConfig{
+		MaxConcurrent: cfg.MaxConcurrent,
+		QueueSize:     cfg.QueueSize,
+		MaxWait:       cfg.MaxWait,
+	})
+}
+
+// queuedDispatcher 在接收队列中占用名额后调用 dispatch，queue 为 nil 时返回 dispatch
+func queuedDispatcher(queue *resilience.AcceptQueue, dispatch adapter.Dispatcher) adapter.Dispatcher {
+	if queue == nil {
+		return dispatch
+	}
+	return func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		release, err := queue.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return dispatch(ctx, request)
+	}
+}
+
+// queuedHandler 在接收队列中占用名额后调用业务方法，queue 为 nil 时返回 handler
+func queuedHandler(queue *resilience.AcceptQueue, handler Handler) Handler {
+	if queue == nil {
+		return handler
+	}
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		release, err := queue.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, params)
+	}
+}
@@ -0,0 +1,40 @@
+package framework
+
+import (
+	"context"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+func TestQueuedDispatcher(t *testing.T) {
+	calls := 0
+	dispatch := func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		calls++
+		return "ok", nil
+	}
+	request := &adapter.InternalRequest{Service: "greeter", Method: "hello"}
+
+	// 未启用接收队列时直接调用
+	if _, err := queuedDispatcher(nil, dispatch)(context.Background(), request); err != nil || calls != 1 {
+		t.Fatalf("Unqueued dispatch = %v, calls %d", err, calls)
+	}
+
+	queued := queuedDispatcher(resilience.NewAcceptQueue("test-dispatch", &resilience.AcceptQueueConfig{MaxConcurrent: 1}), dispatch)
+	if result, err := queued(context.Background(), request); err != nil || result != "ok" {
+		t.Fatalf("Queued dispatch = %v, %v", result, err)
+	}
+
+	// 截止时间已过的请求不处理
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, err := queued(expired, request)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.Timeout {
+		t.Fatalf("Expected Timeout for expired request, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected expired request not to be dispatched, calls = %d", calls)
+	}
+}
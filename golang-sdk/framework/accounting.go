@@ -0,0 +1,69 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/framework/golang-sdk/accounting"
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/observability"
+)
+
+// newMeter 按 framework.accounting 创建用量计量器，Options.AccountingSink 不为 nil 时代替配置的导出目标；
+// 未启用时返回 nil。返回的关闭函数停止计量、导出剩余的用量并关闭导出文件
+func (s *Server) newMeter(cfg *config.AccountingConfig) (*accounting.Meter, func(ctx context.Context) error, error) {
+	if !cfg.Enabled && s.options.AccountingSink == nil {
+		return nil, nil, nil
+	}
+
+	sink := s.options.AccountingSink
+	closeSink := func() error { return nil }
+	if sink == nil {
+		switch strings.ToLower(cfg.Sink) {
+		case config.AccountingSinkCSV:
+			file, err := accounting.NewCSVFileSink(cfg.File)
+			if err != nil {
+				return nil, nil, err
+			}
+			sink, closeSink = file, file.Close
+		case config.AccountingSinkHTTP:
+			sink = &accounting.HTTPSink{URL: cfg.URL, Headers: cfg.Headers}
+		case config.AccountingSinkKafka:
+			if s.kafka == nil {
+				return nil, nil, fmt.Errorf("accounting sink kafka requires the Kafka protocol")
+			}
+			sink = &accounting.KafkaSink{Publisher: s.kafka, Topic: cfg.Topic}
+		default:
+			return nil, nil, fmt.Errorf("unsupported accounting sink: %q", cfg.Sink)
+		}
+	}
+
+	meter := accounting.NewMeter(&accounting.Options{
+		Sink:     sink,
+		Interval: cfg.Interval,
+		Service:  s.config.Name,
+		Instance: s.service.ID,
+		OnError: func(err error) {
+			s.observability.Logger().Warn(context.Background(), "Failed to export usage",
+				observability.Field{Key: "error", Value: err.Error()})
+		},
+	})
+	closeMeter := func(ctx context.Context) error {
+		return errors.Join(meter.Close(ctx), closeSink())
+	}
+	return meter, closeMeter, nil
+}
+
+// metered 包装业务方法处理器，将请求计入调用方的用量；未启用用量计量时原样返回
+func (s *Server) metered(method string, handler Handler) Handler {
+	if s.accounting == nil {
+		return handler
+	}
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		return s.accounting.Measure(ctx, method, params, func() (interface{}, error) {
+			return handler(ctx, params)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/accounting"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestServerAccounting(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	var mu sync.Mutex
+	var exported []*accounting.Usage
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:          reg,
+		AdminAuthenticate: func(r *http.Request, operation string) error { return nil },
+		AccountingSink: accounting.SinkFunc(func(ctx context.Context, usages []*accounting.Usage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			exported = append(exported, usages...)
+			return nil
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		var greeting string
+		if err := server.Client().Call(context.Background(), "greeter-service", "greeter.hello", map[string]string{"name": "Bob"}, &greeting); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+	}
+
+	// 当前周期的用量可经管理接口查询
+	rec := httptest.NewRecorder()
+	server.Admin().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/accounting", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"caller":"anonymous"`) {
+		t.Errorf("accounting query = %d %s", rec.Code, rec.Body.String())
+	}
+
+	// 关闭时导出剩余的用量
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(exported) != 1 {
+		t.Fatalf("Expected 1 usage record, got %+v", exported)
+	}
+	usage := exported[0]
+	if usage.Method != "greeter.hello" || usage.Requests != 2 || usage.Service != "greeter-service" ||
+		usage.Instance == "" || usage.RequestBytes != 2*int64(len(`{"name":"Bob"}`)) || usage.ResponseBytes != 2*int64(len(`"Hello"`)) {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+}
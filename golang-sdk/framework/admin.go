@@ -0,0 +1,433 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/framework/golang-sdk/admin"
+	"github.com/framework/golang-sdk/client"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/registry"
+)
+
+// AdminPath 管理接口在指标服务器上的挂载路径
+const AdminPath = "/admin/"
+
+// AdminStatus 服务实例的运行状态
+type AdminStatus struct {
+	Service      *registry.ServiceInfo `json:"service"`
+	Started      bool                  `json:"started"`
+	Draining     bool                  `json:"draining"`
+	ShuttingDown bool                  `json:"shuttingDown"`
+	InFlight     int                   `json:"inFlight"`
+}
+
+// AdminRoutes 服务提供的方法和协议端点
+type AdminRoutes struct {
+	Methods   []string        `json:"methods"`
+	Protocols []AdminProtocol `json:"protocols"`
+}
+
+// ErrorCodesVerification 其他语言 SDK 的错误码映射表与本服务的校验结果
+type ErrorCodesVerification struct {
+	OK         bool                              `json:"ok"`
+	Mismatches []frameworkerrors.MappingMismatch `json:"mismatches"`
+}
+
+// AdminProtocol 已启用的协议
+type AdminProtocol struct {
+	Type     string `json:"type"`
+	Internal bool   `json:"internal"`
+	Port     int    `json:"port,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// newAdmin 创建管理接口并注册内置的查询和操作
+//
+// 查询：status、diagnostics、routes、protocols、registry、pools、breakers、config、capture、payloadLog、accounting、selfTest、requests、
+// deadLetters、errorCodes、errorCodes.verify；
+// 操作：breakers.reset、requests.cancel、deadLetters.replay、deadLetters.delete、drain、resume、protocols.disable、protocols.enable、
+// logLevel、capture.reset、payloadLog、selfTest.run、config.reload
+func (s *Server) newAdmin() *admin.Admin {
+	a := admin.New(&admin.Options{Authenticate: s.authenticateAdmin})
+
+	a.Query("status", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.status(), nil
+	})
+	a.Query("diagnostics", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.Diagnostics(), nil
+	})
+	a.Query("routes", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.routes(), nil
+	})
+	a.Query("protocols", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.Protocols(), nil
+	})
+	a.Query("registry", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Service string `json:"service"`
+			Prefix  string `json:"prefix"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Prefix != "" {
+			return s.registryPrefixView(ctx, p.Prefix)
+		}
+		return s.registryView(ctx, p.Service)
+	})
+	a.Query("pools", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		inspector, err := s.clientInspector()
+		if err != nil {
+			return nil, err
+		}
+		return inspector.PoolStats(), nil
+	})
+	a.Query("breakers", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		inspector, err := s.clientInspector()
+		if err != nil {
+			return nil, err
+		}
+		return inspector.CircuitBreakers(), nil
+	})
+	a.Query("config", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Prefix string `json:"prefix"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.configManager.EffectiveConfig(p.Prefix)
+	})
+	a.Query("capture", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.capture == nil {
+			return nil, captureDisabled()
+		}
+		return s.capture.Records(), nil
+	})
+	a.Query("payloadLog", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.payloadLog == nil {
+			return nil, payloadLogUnavailable()
+		}
+		return s.payloadLog.Status(), nil
+	})
+	a.Query("accounting", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.accounting == nil {
+			return nil, accountingDisabled()
+		}
+		return s.accounting.Current(), nil
+	})
+	a.Query("selfTest", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.selfTest == nil {
+			return nil, selfTestDisabled()
+		}
+		return s.selfTest.Report(), nil
+	})
+	a.Action("selfTest.run", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.selfTest == nil {
+			return nil, selfTestDisabled()
+		}
+		return s.selfTest.Run(ctx), nil
+	})
+	a.Query("requests", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.ActiveRequests(), nil
+	})
+	a.Query("deadLetters", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Topic string      `json:"topic"`
+			Limit json.Number `json:"limit"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		var limit int64
+		if p.Limit != "" {
+			var err error
+			if limit, err = p.Limit.Int64(); err != nil || limit < 0 {
+				return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+					fmt.Sprintf("invalid limit %s", p.Limit))
+			}
+		}
+		if s.deadLetters == nil {
+			return nil, deadLettersDisabled()
+		}
+		return s.deadLetters.List(ctx, p.Topic, int(limit))
+	})
+
+	a.Query("errorCodes", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return frameworkerrors.Mappings(), nil
+	})
+	a.Query("errorCodes.verify", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var table frameworkerrors.MappingTable
+		if err := admin.DecodeParams(params, &table); err != nil {
+			return nil, err
+		}
+		if table.Version == 0 {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+				"params must be an error code mapping table, see GET /admin/errorCodes")
+		}
+		mismatches := frameworkerrors.VerifyMappings(&table)
+		return &ErrorCodesVerification{OK: len(mismatches) == 0, Mismatches: mismatches}, nil
+	})
+
+	a.Action("breakers.reset", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Service string `json:"service"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Service == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "service is required")
+		}
+		inspector, err := s.clientInspector()
+		if err != nil {
+			return nil, err
+		}
+		if err := inspector.ResetCircuitBreaker(p.Service); err != nil {
+			return nil, err
+		}
+		return inspector.CircuitBreakers(), nil
+	})
+	a.Action("requests.cancel", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.ID == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "id is required")
+		}
+		return s.CancelRequest(p.ID, p.Reason)
+	})
+	a.Action("deadLetters.replay", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		ids, err := deadLetterIDs(params)
+		if err != nil {
+			return nil, err
+		}
+		if s.deadLetters == nil {
+			return nil, deadLettersDisabled()
+		}
+		return s.deadLetters.Replay(ctx, ids...)
+	})
+	a.Action("deadLetters.delete", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		ids, err := deadLetterIDs(params)
+		if err != nil {
+			return nil, err
+		}
+		if s.deadLetters == nil {
+			return nil, deadLettersDisabled()
+		}
+		if err := s.deadLetters.Delete(ctx, ids...); err != nil {
+			return nil, err
+		}
+		return map[string]int{"deleted": len(ids)}, nil
+	})
+	a.Action("drain", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if err := s.Drain(ctx); err != nil {
+			return nil, err
+		}
+		return s.status(), nil
+	})
+	a.Action("resume", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if err := s.Resume(ctx); err != nil {
+			return nil, err
+		}
+		return s.status(), nil
+	})
+	a.Action("protocols.disable", s.switchProtocol(false))
+	a.Action("protocols.enable", s.switchProtocol(true))
+	a.Action("logLevel", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Level observability.LogLevel `json:"level"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		switch p.Level {
+		case observability.LogLevelDebug, observability.LogLevelInfo, observability.LogLevelWarn, observability.LogLevelError:
+		default:
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+				fmt.Sprintf("invalid log level %q", p.Level))
+		}
+		s.observability.SetLogLevel(p.Level)
+		return map[string]string{"level": string(p.Level)}, nil
+	})
+	a.Action("capture.reset", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		if s.capture == nil {
+			return nil, captureDisabled()
+		}
+		s.capture.Reset()
+		return s.capture.Records(), nil
+	})
+	a.Action("payloadLog", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Enabled   *bool `json:"enabled"`
+			PerMinute int   `json:"perMinute"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if s.payloadLog == nil {
+			return nil, payloadLogUnavailable()
+		}
+		if p.PerMinute < 0 {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+				fmt.Sprintf("invalid perMinute %d", p.PerMinute))
+		}
+		if p.PerMinute > 0 {
+			s.payloadLog.SetPerMinute(p.PerMinute)
+		}
+		if p.Enabled != nil {
+			s.payloadLog.SetEnabled(*p.Enabled)
+		}
+		return s.payloadLog.Status(), nil
+	})
+	a.Action("config.reload", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.ReloadConfig(ctx)
+	})
+	return a
+}
+
+// switchProtocol 返回停用或启用 {"type": "MQTT", "port": 1883} 指定的外部协议的操作，port 可省略
+func (s *Server) switchProtocol(enabled bool) admin.Operation {
+	return func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Type string `json:"type"`
+			Port int    `json:"port"`
+		}
+		if err := admin.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Type == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "type is required")
+		}
+		return s.SetProtocolEnabled(ctx, p.Type, p.Port, enabled)
+	}
+}
+
+// captureDisabled 未启用请求录制时的错误
+func captureDisabled() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "request capture is not enabled")
+}
+
+// accountingDisabled 未启用用量计量时的错误
+func accountingDisabled() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "accounting is not enabled")
+}
+
+// selfTestDisabled 未启用自检时的错误
+func selfTestDisabled() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "self-test is not enabled")
+}
+
+// payloadLogUnavailable 服务未启动、负载日志尚未创建时的错误
+func payloadLogUnavailable() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "payload log is not available before the server starts")
+}
+
+// authenticateAdmin 认证管理接口请求
+//
+// 设置了 Options.AdminAuthenticate 时使用该函数；否则在启用认证时按 framework.security 认证，
+// 以 admin.<操作名> 为操作进行 RBAC 检查；两者均未配置时拒绝所有请求
+func (s *Server) authenticateAdmin(r *http.Request, operation string) error {
+	if s.options.AdminAuthenticate != nil {
+		return s.options.AdminAuthenticate(r, operation)
+	}
+	if s.security == nil || !s.config.Security.Authentication.Enabled {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.Forbidden, "admin authentication is not configured")
+	}
+	headers := make(map[string]string, len(r.Header))
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+	_, err := s.authenticate(r.Context(), headers, "admin."+operation)
+	return err
+}
+
+// status 返回服务实例的运行状态
+func (s *Server) status() *AdminStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &AdminStatus{
+		Service:      s.service,
+		Started:      s.started != nil,
+		Draining:     s.draining,
+		ShuttingDown: s.lifecycle.ShuttingDown(),
+		InFlight:     s.inFlight.Count(),
+	}
+}
+
+// routes 返回已注册的方法和已启用的协议
+func (s *Server) routes() *AdminRoutes {
+	s.methodsMu.RLock()
+	methods := make([]string, 0, len(s.methods))
+	for method := range s.methods {
+		methods = append(methods, method)
+	}
+	s.methodsMu.RUnlock()
+	sort.Strings(methods)
+
+	routes := &AdminRoutes{Methods: methods, Protocols: []AdminProtocol{}}
+	for _, p := range s.config.Protocols.External {
+		if p.Enabled {
+			routes.Protocols = append(routes.Protocols, AdminProtocol{Type: p.Type, Port: p.Port, Path: p.Path})
+		}
+	}
+	for _, p := range s.config.Protocols.Internal {
+		if p.Enabled {
+			routes.Protocols = append(routes.Protocols, AdminProtocol{Type: p.Type, Internal: true, Port: p.Port})
+		}
+	}
+	return routes
+}
+
+// registryView 返回注册中心中的服务实例，service 为空时返回本服务和 framework.services 中配置的服务
+func (s *Server) registryView(ctx context.Context, service string) (map[string][]*registry.ServiceInfo, error) {
+	names := []string{service}
+	if service == "" {
+		names = append([]string{s.config.Name}, s.config.ServiceNames()...)
+	}
+
+	view := make(map[string][]*registry.ServiceInfo, len(names))
+	for _, name := range names {
+		instances, err := s.registry.Discover(ctx, name)
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.ServiceUnavailable,
+				fmt.Sprintf("failed to discover service %s", name))
+		}
+		view[name] = instances
+	}
+	return view, nil
+}
+
+// registryPrefixView 返回注册中心中服务名以 prefix 开头的所有服务实例，注册中心需实现 registry.PatternDiscoverer
+func (s *Server) registryPrefixView(ctx context.Context, prefix string) (map[string][]*registry.ServiceInfo, error) {
+	view, err := registry.DiscoverByPrefix(ctx, s.registry, prefix)
+	if errors.Is(err, registry.ErrPatternNotSupported) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotImplemented, err.Error())
+	}
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.ServiceUnavailable,
+			fmt.Sprintf("failed to discover services with prefix %s", prefix))
+	}
+	return view, nil
+}
+
+// clientInspector 返回客户端的运行时状态
+func (s *Server) clientInspector() (client.Inspector, error) {
+	inspector, ok := s.Client().(client.Inspector)
+	if !ok {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotImplemented, "client does not expose runtime stats")
+	}
+	return inspector, nil
+}
@@ -0,0 +1,28 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/framework/golang-sdk/catalog"
+)
+
+// CatalogPath 服务目录页面在指标服务器上的挂载路径，framework.catalog.enabled 为 true 时挂载
+const CatalogPath = "/catalog/"
+
+// newCatalog 创建服务目录页面，页面上的调用经服务的客户端发出
+//
+// 请求与管理接口相同地认证，操作名为 catalog.list 和 catalog.call，按 framework.security 认证时 RBAC 检查的操作为
+// admin.catalog.list 和 admin.catalog.call
+func (s *Server) newCatalog() *catalog.Catalog {
+	return catalog.New(&catalog.Options{
+		Registry: s.registry,
+		Call: func(ctx context.Context, service, method string, request, response interface{}) error {
+			return s.Client().Call(ctx, service, method, request, response)
+		},
+		Authenticate: func(r *http.Request, operation string) error {
+			return s.authenticateAdmin(r, "catalog."+operation)
+		},
+		CallTimeout: s.config.Catalog.CallTimeout,
+	})
+}
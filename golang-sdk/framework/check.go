@@ -0,0 +1,227 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/framework/golang-sdk/config"
+)
+
+// 配置检查问题的严重程度
+const (
+	// SeverityError 服务无法启动或存在明显的安全问题
+	SeverityError = "error"
+	// SeverityWarning 服务可以启动，但配置可能不符合预期或不安全
+	SeverityWarning = "warning"
+)
+
+// MinJWTSecretLength JWT HMAC 密钥的最小建议长度（字节），与 HS256 的摘要长度相同
+const MinJWTSecretLength = 32
+
+// defaultSecrets 示例、文档和脚手架中常见的默认密钥，小写
+var defaultSecrets = []string{
+	"your-secret-key-change-in-production", "your-secret-key", "secret", "changeme", "change-me",
+	"password", "default", "test", "example", "jwt-secret",
+}
+
+// ConfigFinding 配置检查发现的一个问题
+type ConfigFinding struct {
+	Severity string `json:"severity"`
+	// Key 相关的配置路径，问题涉及多个配置项时为空，配置路径见 Problem
+	Key     string `json:"key,omitempty"`
+	Problem string `json:"problem"`
+	// Fix 修改建议
+	Fix string `json:"fix,omitempty"`
+}
+
+// String 返回问题的单行描述
+func (f ConfigFinding) String() string {
+	if f.Key == "" {
+		return f.Problem
+	}
+	return f.Key + ": " + f.Problem
+}
+
+// ConfigReport 配置检查报告，错误排在警告之前
+type ConfigReport struct {
+	Path     string          `json:"path"`
+	Findings []ConfigFinding `json:"findings"`
+}
+
+// Count 返回指定严重程度的问题数
+func (r *ConfigReport) Count(severity string) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// add 记录一个问题
+func (r *ConfigReport) add(severity, key, problem, fix string) {
+	r.Findings = append(r.Findings, ConfigFinding{Severity: severity, Key: key, Problem: problem, Fix: fix})
+}
+
+// CheckConfig 在不启动服务的情况下检查配置文件，供部署流水线在发布前发现问题，见 frameworkctl check
+//
+// 错误：Schema 校验失败、配置无法解码、监听端口缺失或冲突、TLS 证书不可用、密钥使用示例中的默认值；
+// 警告：配置文件中框架不识别的键、明文监听的端口、外部协议未启用认证、JWT 密钥过短、密钥以明文保存在配置文件中。
+// 未配置解密密钥时加密值不解密，也不检查其强度。配置文件不存在或无法解析时返回 error
+func CheckConfig(path string, opts *config.Options) (*ConfigReport, error) {
+	loadOpts := config.Options{}
+	if opts != nil {
+		loadOpts = *opts
+	}
+	loadOpts.SkipValidation = true
+	undecrypted := false
+	if loadOpts.Decrypter == nil {
+		decrypter, err := config.DecrypterFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		if decrypter == nil {
+			decrypter, undecrypted = keepCiphertext{}, true
+		}
+		loadOpts.Decrypter = decrypter
+	}
+
+	cm, err := config.NewConfigManagerWithOptions(path, &loadOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConfigReport{Path: path}
+	var verrs config.ValidationErrors
+	if err := cm.Validate(); errors.As(err, &verrs) {
+		for _, e := range verrs {
+			fix := "correct the value of " + e.Key
+			if e.Message == "is required" {
+				fix = "set " + e.Key
+			}
+			report.add(SeverityError, e.Key, e.Message, fix)
+		}
+	}
+
+	cfg, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		report.add(SeverityError, "", err.Error(), "fix the value types of the reported keys")
+		return report.sorted(), nil
+	}
+	for _, problem := range portProblems(cfg) {
+		report.addProblem(SeverityError, problem)
+	}
+	if err := validateTLS(&cfg.Security.TLS); err != nil {
+		report.add(SeverityError, "framework.security.tls", err.Error(), "")
+	}
+
+	unknown, err := cm.UnknownKeys("framework", &config.FrameworkConfig{})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range unknown {
+		report.add(SeverityWarning, key, "unknown key, ignored by the framework", "remove it or correct its spelling")
+	}
+
+	// Options.MTLS 在代码中设置，检查时按未设置处理
+	for _, warning := range listenerWarnings(cfg, false) {
+		report.addProblem(SeverityWarning, warning)
+	}
+	if !cfg.Security.Authentication.Enabled && hasExternalListener(cfg) {
+		report.add(SeverityWarning, "framework.security.authentication.enabled",
+			"authentication is disabled, external protocols accept requests from any caller",
+			"set it to true unless an upstream gateway authenticates every request")
+	}
+
+	if err := checkSecrets(cm, report, undecrypted); err != nil {
+		return nil, err
+	}
+	return report.sorted(), nil
+}
+
+// addProblem 记录形如 "问题: 修改方法" 的问题
+func (r *ConfigReport) addProblem(severity, problem string) {
+	fix := ""
+	if i := strings.LastIndex(problem, ": "); i >= 0 {
+		problem, fix = problem[:i], problem[i+2:]
+	}
+	r.add(severity, "", problem, fix)
+}
+
+// sorted 将错误排在警告之前，同一严重程度保持检查顺序
+func (r *ConfigReport) sorted() *ConfigReport {
+	findings := make([]ConfigFinding, 0, len(r.Findings))
+	for _, severity := range []string{SeverityError, SeverityWarning} {
+		for _, f := range r.Findings {
+			if f.Severity == severity {
+				findings = append(findings, f)
+			}
+		}
+	}
+	r.Findings = findings
+	return r
+}
+
+// checkSecrets 检查敏感配置：默认值、JWT 密钥长度和明文保存
+func checkSecrets(cm *config.ConfigManager, report *ConfigReport, undecrypted bool) error {
+	effective, err := cm.EffectiveConfig("")
+	if err != nil {
+		return err
+	}
+	for _, entry := range effective.Entries {
+		if !entry.Redacted {
+			continue
+		}
+		encrypted := cm.IsEncrypted(entry.Key)
+		value := cm.GetString(entry.Key)
+		if value == "" || (encrypted && undecrypted) {
+			continue
+		}
+		switch {
+		case isDefaultSecret(value):
+			report.add(SeverityError, entry.Key, "uses a well-known default value",
+				"generate a random secret, e.g. openssl rand -base64 32, and store it encrypted as ENC[...]")
+			continue
+		case strings.Contains(strings.ToLower(entry.Key), "jwt") && len(value) < MinJWTSecretLength:
+			report.add(SeverityWarning, entry.Key,
+				fmt.Sprintf("JWT secret is %d bytes, shorter than %d", len(value), MinJWTSecretLength),
+				fmt.Sprintf("use a random secret of at least %d bytes", MinJWTSecretLength))
+		}
+		if !encrypted && entry.Source == config.SourceFile {
+			report.add(SeverityWarning, entry.Key, "secret is stored in plaintext in "+entry.Origin,
+				"store it encrypted as ENC[...] or provide it through an environment variable")
+		}
+	}
+	return nil
+}
+
+// isDefaultSecret 检查密钥是否为常见的默认值
+func isDefaultSecret(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, secret := range defaultSecrets {
+		if value == secret {
+			return true
+		}
+	}
+	return strings.Contains(value, "change-in-production") || strings.Contains(value, "changeme")
+}
+
+// hasExternalListener 检查是否启用了在本地监听的外部协议
+func hasExternalListener(cfg *config.FrameworkConfig) bool {
+	for _, l := range listeners(cfg) {
+		if !l.internal && l.owner != "metrics" {
+			return true
+		}
+	}
+	return false
+}
+
+// keepCiphertext 未配置解密密钥时使用，保留密文使配置可以加载
+type keepCiphertext struct{}
+
+// Decrypt 实现 config.Decrypter
+func (keepCiphertext) Decrypt(ciphertext []byte) ([]byte, error) {
+	return ciphertext, nil
+}
@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	report, err := CheckConfig(writeTestConfig(t, testConfig), nil)
+	if err != nil {
+		t.Fatalf("CheckConfig failed: %v", err)
+	}
+	// 只监听回环地址，只有未启用认证的警告
+	if len(report.Findings) != 1 || report.Findings[0].Key != "framework.security.authentication.enabled" {
+		t.Errorf("Unexpected findings: %+v", report.Findings)
+	}
+
+	broken := strings.Replace(testConfig, "port: 18402", "port: 18401", 1)
+	broken = strings.Replace(broken, "level: error", "level: verbose", 1)
+	broken = strings.Replace(broken, "  connectionPool:", "  conectionPool:", 1)
+	broken += `app:
+  jwt:
+    secret: your-secret-key-change-in-production
+  signing:
+    secret: short-but-not-default
+`
+	report, err = CheckConfig(writeTestConfig(t, broken), nil)
+	if err != nil {
+		t.Fatalf("CheckConfig failed: %v", err)
+	}
+	if report.Count(SeverityError) != 3 || report.Count(SeverityWarning) != 3 {
+		t.Errorf("Expected 3 errors and 3 warnings, got %+v", report.Findings)
+	}
+	wants := []struct {
+		severity, text string
+	}{
+		{SeverityError, "framework.observability.logging.level: must be one of"},
+		{SeverityError, "port 18401 is used by both HTTP and internal JSON-RPC"},
+		{SeverityError, "app.jwt.secret: uses a well-known default value"},
+		{SeverityWarning, "framework.conectionPool: unknown key"},
+		{SeverityWarning, "app.signing.secret: secret is stored in plaintext"},
+	}
+	for _, want := range wants {
+		found := false
+		for _, f := range report.Findings {
+			found = found || (f.Severity == want.severity && strings.Contains(f.String(), want.text))
+		}
+		if !found {
+			t.Errorf("Expected %s containing %q, got %+v", want.severity, want.text, report.Findings)
+		}
+	}
+	// 错误排在警告之前，修改建议与问题分开
+	if report.Findings[0].Severity != SeverityError || report.Findings[len(report.Findings)-1].Severity != SeverityWarning {
+		t.Errorf("Expected errors before warnings, got %+v", report.Findings)
+	}
+	for _, f := range report.Findings {
+		if f.Fix == "" {
+			t.Errorf("Expected a fix for %s", f)
+		}
+	}
+
+	if _, err := CheckConfig("missing.yaml", nil); err == nil {
+		t.Error("Expected error for a missing config file")
+	}
+}
@@ -0,0 +1,1117 @@
+package framework
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/client"
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/connection"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/capture"
+	"github.com/framework/golang-sdk/protocol/dedup"
+	"github.com/framework/golang-sdk/protocol/external/grpcweb"
+	externaljsonrpc "github.com/framework/golang-sdk/protocol/external/jsonrpc"
+	"github.com/framework/golang-sdk/protocol/external/kafka"
+	"github.com/framework/golang-sdk/protocol/external/mqtt"
+	"github.com/framework/golang-sdk/protocol/external/rest"
+	"github.com/framework/golang-sdk/protocol/external/websocket"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/protocol/transport"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// MetadataPortPrefix 服务实例元数据中各协议监听端口的键前缀，如 port.grpc=9001
+const MetadataPortPrefix = registry.MetadataPortPrefix
+
+// 配置文件中的协议类型
+const (
+	protocolREST      = "REST"
+	protocolWebSocket = "WebSocket"
+	protocolJSONRPC   = "JSON-RPC"
+	protocolMQTT      = "MQTT"
+	protocolKafka     = "Kafka"
+	protocolMQ        = "MQ"
+	protocolGRPC      = "gRPC"
+	protocolCustom    = "Custom"
+	protocolGRPCWeb   = "gRPC-Web"
+)
+
+// httpServerSeq 区分各 Server 创建的 HTTP 服务器，g.Server 按名称复用实例，同一进程中先后创建的 Server 不能共用路由
+var httpServerSeq atomic.Uint64
+
+// component 随服务启动和关闭的组件
+type component struct {
+	name  string
+	start func() error
+	stop  func(ctx context.Context) error
+}
+
+// protocolHandler 协议处理器
+type protocolHandler interface {
+	Start() error
+	Stop(ctx context.Context) error
+}
+
+// newHandlerComponent 包装协议处理器
+func newHandlerComponent(name string, handler protocolHandler) component {
+	return component{name: name, start: handler.Start, stop: handler.Stop}
+}
+
+// newComponents 按协议配置创建协议处理器
+//
+// 同一端口的 REST、WebSocket、JSON-RPC 和 gRPC-Web 共用一个 HTTP 服务器，路由注册完成后再启动服务器
+func (s *Server) newComponents() ([]component, error) {
+	cfg := s.config
+
+	dedupStore := s.options.Dedup
+	if dedupStore == nil {
+		dedupStore = dedup.NewCache(dedup.DefaultCapacity, dedup.DefaultTTL)
+	}
+
+	// gRPC-Web 请求转换后交给内部 gRPC 服务器，该服务器在处理内部协议时创建
+	var grpcServer *transport.GrpcServer
+	grpcWebEnabled := false
+
+	var components []component
+
+	// 请求依次经过转换、录制、负载日志后调用业务方法，录制和记录的是业务方法收到的请求；
+	// 外部 JSON-RPC 直接调用注册的方法，不经过转换、录制和负载日志
+	var transformer *adapter.Transformer
+	if len(cfg.Transforms) > 0 {
+		var err error
+		if transformer, err = adapter.NewTransformer(transformRules(cfg.Transforms)); err != nil {
+			return nil, err
+		}
+	}
+	// 负载日志未启用时也创建，管理接口可在运行时开启
+	s.payloadLog = s.newPayloadLogger(&cfg.PayloadLog)
+	dispatch := s.payloadLog.Dispatcher(s.dispatch)
+	if cfg.Capture.Enabled {
+		recorder, closeCapture, err := s.newRecorder(&cfg.Capture)
+		if err != nil {
+			return nil, err
+		}
+		dispatch = recorder.Dispatcher(dispatch)
+		// 录制文件在协议处理器停止后关闭
+		components = append(components, component{
+			name:  "request capture",
+			start: func() error { return nil },
+			stop:  closeCapture,
+		})
+	}
+	if transformer != nil {
+		dispatch = transformer.Dispatcher(dispatch)
+	}
+
+	// 共用的 HTTP 服务器按监听地址（host:port）区分
+	httpServers := make(map[string]*ghttp.Server)
+	var httpEndpoints []endpoint
+	sharedServer := func(host string, port int) *ghttp.Server {
+		// 嵌入应用的服务器时所有 HTTP 协议共用一个不监听端口的服务器
+		if s.options.EmbedHTTP {
+			if s.embedded == nil {
+				s.embedded = &embeddedServer{server: g.Server(fmt.Sprintf("framework-embedded-%d", httpServerSeq.Add(1)))}
+			}
+			return s.embedded.server
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(port))
+		if server, ok := httpServers[address]; ok {
+			return server
+		}
+		server := g.Server(fmt.Sprintf("framework-%s-%d-%d", host, port, httpServerSeq.Add(1)))
+		server.SetAddr(address)
+		if cfg.Network.ReadTimeout > 0 {
+			server.SetReadTimeout(cfg.Network.ReadTimeout)
+		}
+		if cfg.Network.WriteTimeout > 0 {
+			server.SetWriteTimeout(cfg.Network.WriteTimeout)
+		}
+		server.SetKeepAlive(cfg.Network.KeepAlive)
+		httpServers[address] = server
+		httpEndpoints = append(httpEndpoints, endpoint{host: host, port: port})
+		return server
+	}
+
+	for _, p := range cfg.Protocols.External {
+		if !p.Enabled {
+			continue
+		}
+		host := protocolHost(cfg.Network.Host, p.Host)
+		switch {
+		case strings.EqualFold(p.Type, protocolREST):
+			cachePolicies, err := restCachePolicies(p.Options)
+			if err != nil {
+				return nil, err
+			}
+			envelope, err := restEnvelope(p.Options)
+			if err != nil {
+				return nil, err
+			}
+			compression, err := restCompression(p.Options)
+			if err != nil {
+				return nil, err
+			}
+			handler := rest.NewRestProtocolHandler(&rest.RestConfig{
+				Host:          host,
+				Port:          p.Port,
+				Path:          p.Path,
+				Server:        sharedServer(host, p.Port),
+				Dispatcher:    queuedDispatcher(s.newAcceptQueue(protocolREST, p.Port), dispatch),
+				CachePolicies: cachePolicies,
+				Envelope:      envelope,
+				Compression:   compression,
+			})
+			s.addProtocol(protocolREST, host, p.Port, p.Path, handler)
+			components = append(components, newHandlerComponent(protocolREST, handler))
+		case strings.EqualFold(p.Type, protocolWebSocket):
+			if s.hub == nil {
+				// 多个 WebSocket 端点共用一个 Hub，Hub 在协议处理器停止后关闭
+				s.hub = websocket.NewHub(&websocket.HubOptions{Broker: s.options.HubBroker})
+				hub := s.hub
+				components = append(components, component{
+					name:  "WebSocket hub",
+					start: func() error { return nil },
+					stop:  func(ctx context.Context) error { return hub.Close() },
+				})
+			}
+			wsConfig := &websocket.WebSocketConfig{
+				Host:       host,
+				Port:       p.Port,
+				Path:       p.Path,
+				Server:     sharedServer(host, p.Port),
+				Dispatcher: queuedDispatcher(s.newAcceptQueue(protocolWebSocket, p.Port), dispatch),
+				Dedup:      dedupStore,
+				Hub:        s.hub,
+				Sessions:   s.sessions,
+			}
+			if s.security != nil {
+				wsConfig.AuthorizeSubscribe = s.authorizeSubscribe
+			}
+			handler := websocket.NewWebSocketProtocolHandler(wsConfig)
+			s.addProtocol(protocolWebSocket, host, p.Port, p.Path, handler)
+			components = append(components, newHandlerComponent(protocolWebSocket, handler))
+		case strings.EqualFold(p.Type, protocolJSONRPC):
+			attachments, err := jsonRpcAttachments(p.Options)
+			if err != nil {
+				return nil, err
+			}
+			jsonRpcConfig := &externaljsonrpc.JsonRpcConfig{
+				Host:        host,
+				Port:        p.Port,
+				Path:        p.Path,
+				Server:      sharedServer(host, p.Port),
+				Attachments: attachments,
+			}
+			if s.security != nil {
+				jsonRpcConfig.Authenticate = s.authenticate
+			}
+			handler := externaljsonrpc.NewJsonRpcProtocolHandler(jsonRpcConfig)
+			s.jsonRpcs = append(s.jsonRpcs, handler)
+			s.acceptQueues[handler] = s.newAcceptQueue(protocolJSONRPC, p.Port)
+			s.addProtocol(protocolJSONRPC, host, p.Port, p.Path, handler)
+			components = append(components, newHandlerComponent(protocolJSONRPC, handler))
+		case strings.EqualFold(p.Type, protocolGRPCWeb):
+			handler := grpcweb.NewGrpcWebProtocolHandler(&grpcweb.GrpcWebConfig{
+				Host:   host,
+				Port:   p.Port,
+				Path:   p.Path,
+				Server: sharedServer(host, p.Port),
+				Backend: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					grpcServer.ServeHTTP(w, r)
+				}),
+				Options: &grpcweb.Options{
+					AllowedOrigins: optionStrings(p.Options, "allowedOrigins"),
+					Connect:        optionBool(p.Options, "connect"),
+				},
+			})
+			grpcWebEnabled = true
+			s.addProtocol(protocolGRPCWeb, host, p.Port, p.Path, handler)
+			components = append(components, newHandlerComponent(protocolGRPCWeb, handler))
+		case strings.EqualFold(p.Type, protocolMQTT):
+			rateLimits, err := mqttRateLimits(p.Options)
+			if err != nil {
+				return nil, err
+			}
+			handler := mqtt.NewMqttProtocolHandler(&mqtt.MqttConfig{
+				Broker:       optionString(p.Options, "broker", "localhost"),
+				Port:         p.Port,
+				ClientId:     optionString(p.Options, "clientId", cfg.Name),
+				Username:     optionString(p.Options, "username", ""),
+				Password:     optionString(p.Options, "password", ""),
+				Topics:       optionStrings(p.Options, "topics"),
+				Dedup:        dedupStore,
+				RateLimits:   rateLimits,
+				OnDisconnect: s.mqttDisconnect,
+			})
+			s.addProtocol(protocolMQTT, "", p.Port, "", handler)
+			components = append(components, newHandlerComponent(protocolMQTT, handler))
+		case strings.EqualFold(p.Type, protocolKafka):
+			if s.options.KafkaDialer == nil {
+				return nil, fmt.Errorf("Kafka protocol requires Options.KafkaDialer")
+			}
+			s.kafka = kafka.NewKafkaProtocolHandler(&kafka.KafkaConfig{
+				Brokers:          optionStrings(p.Options, "brokers"),
+				GroupId:          optionString(p.Options, "groupId", cfg.Name),
+				Routes:           optionStringMap(p.Options, "routes"),
+				Dialer:           s.options.KafkaDialer,
+				Dispatcher:       dispatch,
+				DeadLetterSuffix: optionString(p.Options, "deadLetterSuffix", ""),
+				IdempotencyStore: dedupStore,
+			})
+			s.addProtocol(protocolKafka, "", 0, "", s.kafka)
+			components = append(components, newHandlerComponent(protocolKafka, s.kafka))
+		case strings.EqualFold(p.Type, protocolMQ):
+			if s.options.Broker == nil {
+				return nil, fmt.Errorf("MQ protocol requires Options.Broker")
+			}
+			handler := messaging.NewRPCServer(s.options.Broker, cfg.Name, dispatch)
+			if s.deadLetters != nil {
+				handler.SetDeadLetterQueue(s.deadLetters)
+			}
+			s.addProtocol(protocolMQ, "", 0, "", handler)
+			components = append(components, newHandlerComponent(protocolMQ, handler))
+		default:
+			return nil, fmt.Errorf("unsupported external protocol: %s", p.Type)
+		}
+	}
+
+	if s.embedded != nil {
+		for _, p := range s.protocols {
+			if p.status.Type != protocolMQTT && p.status.Type != protocolKafka && p.status.Type != protocolMQ {
+				s.embedded.addPath(p.status.Path)
+			}
+		}
+	}
+	if s.options.HealthPath != "" {
+		var server *ghttp.Server
+		if s.embedded != nil {
+			server = s.embedded.server
+			s.embedded.addPath(s.options.HealthPath)
+		}
+		for _, e := range httpEndpoints {
+			if e.port == cfg.Network.Port {
+				server = httpServers[e.address()]
+				break
+			}
+		}
+		if server == nil {
+			return nil, fmt.Errorf("Options.HealthPath requires REST, WebSocket, JSON-RPC or gRPC-Web on network.port %d", cfg.Network.Port)
+		}
+		s.mountProbes(server, s.options.HealthPath)
+	}
+
+	// 路由注册完成后启动共用的 HTTP 服务器；启用 mTLS 时浏览器和探针无需客户端证书
+	httpTLS := s.serverTLSConfig(tls.VerifyClientCertIfGiven)
+	checkNames := make(map[string]bool)
+	for _, e := range httpEndpoints {
+		address := e.address()
+		server := httpServers[address]
+		components = append(components, component{
+			name:  "HTTP server on " + address,
+			start: func() error { return startHTTPServer(server, address, httpTLS) },
+			stop:  func(ctx context.Context) error { return server.Shutdown() },
+		})
+		s.observability.HealthChecker().RegisterCheck(observability.NewProtocolHandlerHealthCheck(
+			uniqueCheckName(checkNames, fmt.Sprintf("http-%d", e.port), e.host), localAddress(e.host, e.port)))
+	}
+
+	if s.embedded != nil {
+		embedded := s.embedded
+		components = append(components, component{
+			name:  "embedded HTTP server",
+			start: embedded.start,
+			stop:  func(ctx context.Context) error { return embedded.stop() },
+		})
+	}
+
+	// 内部协议只供服务实例间调用，启用 mTLS 时要求对端出示证书
+	internalTLS := s.serverTLSConfig(tls.RequireAndVerifyClientCert)
+	var grpcConfig *transport.GrpcServerConfig
+	for _, p := range cfg.Protocols.Internal {
+		if !p.Enabled {
+			continue
+		}
+		host := protocolHost(cfg.Network.Host, p.Host)
+		s.observability.HealthChecker().RegisterCheck(observability.NewProtocolHandlerHealthCheck(
+			uniqueCheckName(checkNames, "internal-"+strings.ToLower(p.Type), strconv.Itoa(p.Port)), localAddress(host, p.Port)))
+
+		var handler protocolHandler
+		switch {
+		case strings.EqualFold(p.Type, protocolGRPC):
+			// 多个 gRPC 端口共用一个 gRPC 服务器，GRPC() 注册的服务在所有端口上提供
+			if grpcServer != nil {
+				grpcConfig.ExtraAddresses = append(grpcConfig.ExtraAddresses, net.JoinHostPort(host, strconv.Itoa(p.Port)))
+				continue
+			}
+			grpcConfig = &transport.GrpcServerConfig{
+				Host:      host,
+				Port:      p.Port,
+				UseTLS:    cfg.Security.TLS.Enabled,
+				CertFile:  cfg.Security.TLS.CertFile,
+				KeyFile:   cfg.Security.TLS.KeyFile,
+				TLSConfig: internalTLS,
+			}
+			grpcServer = transport.NewGrpcServer(grpcConfig)
+			s.grpc = grpcServer
+			handler = grpcServer
+		case strings.EqualFold(p.Type, protocolJSONRPC):
+			internalJsonRpc := transport.NewInternalJsonRpcHandler(&transport.InternalJsonRpcConfig{
+				Host:      host,
+				Port:      p.Port,
+				TLSConfig: internalTLS,
+			})
+			s.internalJsonRpcs = append(s.internalJsonRpcs, internalJsonRpc)
+			s.acceptQueues[internalJsonRpc] = s.newAcceptQueue("internal "+p.Type, p.Port)
+			handler = internalJsonRpc
+		case strings.EqualFold(p.Type, protocolCustom):
+			handler = transport.NewCustomProtocolHandler(&transport.CustomProtocolConfig{
+				Host:              host,
+				Port:              p.Port,
+				Dispatcher:        queuedDispatcher(s.newAcceptQueue("internal "+p.Type, p.Port), dispatch),
+				TLSConfig:         internalTLS,
+				Features:          []string{transport.CustomFeatureDeadline},
+				IdleTimeout:       p.IdleTimeout,
+				StreamIdleTimeout: p.StreamIdleTimeout,
+			})
+		default:
+			return nil, fmt.Errorf("unsupported internal protocol: %s", p.Type)
+		}
+		components = append(components, newHandlerComponent("internal "+p.Type, handler))
+	}
+	if grpcWebEnabled && grpcServer == nil {
+		return nil, fmt.Errorf("gRPC-Web protocol requires the internal gRPC protocol")
+	}
+
+	// 内置 DNS 服务器供只能通过 DNS 发现服务的旧工具使用，应答时查询注册中心
+	if cfg.DNS.Enabled {
+		dnsServer := registry.NewDNSServer(s.registry, &registry.DNSConfig{
+			Address: cfg.DNS.Address,
+			Domain:  cfg.DNS.Domain,
+			TTL:     cfg.DNS.TTL,
+		})
+		components = append(components, newHandlerComponent("DNS server", dnsServer))
+	}
+
+	return components, nil
+}
+
+// startHTTPServer 在 lifecycle.Listen 创建的监听器上启动共用的 HTTP 服务器，热重启时监听器交接给新进程；
+// tlsConfig 不为 nil 时使用 HTTPS
+func startHTTPServer(server *ghttp.Server, address string, tlsConfig *tls.Config) error {
+	listener, err := lifecycle.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	if err := server.SetListener(listener); err != nil {
+		listener.Close()
+		return err
+	}
+	if err := server.Start(); err != nil {
+		listener.Close()
+		return err
+	}
+	return nil
+}
+
+// serviceInfo 构造注册到注册中心的服务实例信息
+//
+// 端口为外部 JSON-RPC 端口（client 包通过该端口调用服务），未启用时为 network.port；
+// 各协议的监听端口写入 port.<协议> 元数据，同一协议监听多个端口时写入第一个，只供本机访问的端口不写入
+func (s *Server) serviceInfo() (*registry.ServiceInfo, error) {
+	cfg := s.config
+	address := s.options.AdvertiseAddress
+	if address == "" {
+		var err error
+		if address, err = advertiseAddress(cfg.Network.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	port := cfg.Network.Port
+	metadata := make(map[string]string)
+	var protocols []string
+	for _, p := range cfg.Protocols.External {
+		if !p.Enabled || localOnly(cfg.Network.Host, p.Host) {
+			continue
+		}
+		if isBrokerProtocol(p.Type) {
+			protocols = append(protocols, p.Type)
+			continue
+		}
+		key := MetadataPortPrefix + p.Type
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		protocols = append(protocols, p.Type)
+		metadata[key] = strconv.Itoa(p.Port)
+		if strings.EqualFold(p.Type, protocolJSONRPC) {
+			port = p.Port
+		}
+	}
+
+	serializations := make(map[string]bool)
+	for _, p := range cfg.Protocols.Internal {
+		if !p.Enabled || localOnly(cfg.Network.Host, p.Host) {
+			continue
+		}
+		protocol := p.Type
+		switch {
+		case strings.EqualFold(p.Type, protocolJSONRPC):
+			protocol = string(adapter.ProtocolInternalRPC)
+		case strings.EqualFold(p.Type, protocolCustom):
+			protocol = string(adapter.ProtocolCustomBinary)
+		}
+		key := MetadataPortPrefix + protocol
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		protocols = append(protocols, protocol)
+		metadata[key] = strconv.Itoa(p.Port)
+		if p.Serialization != "" {
+			serializations[strings.ToLower(p.Serialization)] = true
+		}
+	}
+
+	var formats []string
+	for format := range serializations {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return &registry.ServiceInfo{
+		ID:             fmt.Sprintf("%s-%s-%d", cfg.Name, address, port),
+		Name:           cfg.Name,
+		Version:        cfg.Version,
+		Language:       cfg.Language,
+		Address:        address,
+		Port:           port,
+		Protocols:      protocols,
+		Serializations: formats,
+		Metadata:       metadata,
+	}, nil
+}
+
+// advertiseAddress 返回注册到注册中心的地址，host 为通配地址时使用本机第一个非回环 IPv4 地址
+func advertiseAddress(host string) (string, error) {
+	if host != "" && host != "0.0.0.0" && host != "::" {
+		return host, nil
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve advertise address: %w", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "127.0.0.1", nil
+}
+
+// localAddress 返回健康检查连接本地监听端口使用的地址
+func localAddress(host string, port int) string {
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// endpoint 协议的监听地址
+type endpoint struct {
+	host string
+	port int
+}
+
+// address 返回 host:port
+func (e endpoint) address() string {
+	return net.JoinHostPort(e.host, strconv.Itoa(e.port))
+}
+
+// protocolHost 返回协议的监听地址，协议未配置 host 时使用 network.host
+func protocolHost(networkHost, host string) string {
+	if host != "" {
+		return host
+	}
+	return networkHost
+}
+
+// localOnly 返回协议是否只供本机访问：协议的 host 为回环地址而 network.host 不是
+func localOnly(networkHost, host string) bool {
+	return host != "" && isLoopback(host) && !isLoopback(networkHost)
+}
+
+// isLoopback 返回 host 是否为回环地址
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// uniqueCheckName 返回未使用的健康检查名称，name 已被同类协议的其他端口使用时追加 suffix
+func uniqueCheckName(used map[string]bool, name, suffix string) string {
+	if used[name] {
+		name += "-" + suffix
+	}
+	used[name] = true
+	return name
+}
+
+// newRegistry 按 framework.registry 创建注册中心，支持 etcd 和 memory；配置了 mirrors 时同时写入多个注册中心，
+// readOnly 时包装为只读注册中心
+func newRegistry(cfg *config.RegistryConfig) (registry.ServiceRegistry, error) {
+	reg, err := newRegistryBackend(cfg)
+	if err == nil && len(cfg.Mirrors) > 0 {
+		reg, err = newMultiRegistry(cfg, reg)
+	}
+	if err != nil || !cfg.ReadOnly {
+		return reg, err
+	}
+	return registry.NewReadOnlyRegistry(reg)
+}
+
+// newMultiRegistry 以 primary 和 framework.registry.mirrors 创建多注册中心，任一注册中心创建失败时关闭已创建的
+func newMultiRegistry(cfg *config.RegistryConfig, primary registry.ServiceRegistry) (registry.ServiceRegistry, error) {
+	backends := []registry.RegistryBackend{{Name: "primary", Registry: primary}}
+	closeAll := func() {
+		for _, backend := range backends {
+			backend.Registry.Close()
+		}
+	}
+	for i := range cfg.Mirrors {
+		reg, err := newRegistryBackend(&cfg.Mirrors[i])
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("registry mirror %d: %w", i+1, err)
+		}
+		backends = append(backends, registry.RegistryBackend{Name: fmt.Sprintf("mirror-%d", i+1), Registry: reg})
+	}
+	multi, err := registry.NewMultiRegistry(backends, &registry.MultiRegistryConfig{
+		Quorum:         cfg.Quorum,
+		RepairInterval: time.Duration(cfg.RepairInterval) * time.Second,
+	})
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return multi, nil
+}
+
+// newRegistryBackend 按 framework.registry.type 创建注册中心
+func newRegistryBackend(cfg *config.RegistryConfig) (registry.ServiceRegistry, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "etcd":
+		etcdConfig := registry.DefaultEtcdRegistryConfig()
+		if len(cfg.Endpoints) > 0 {
+			etcdConfig.Endpoints = cfg.Endpoints
+		}
+		if cfg.Namespace != "" {
+			etcdConfig.Namespace = cfg.Namespace
+		}
+		if cfg.TTL > 0 {
+			etcdConfig.TTL = int64(cfg.TTL)
+		}
+		if cfg.HeartbeatInterval > 0 {
+			etcdConfig.HeartbeatInterval = time.Duration(cfg.HeartbeatInterval) * time.Second
+		}
+		etcdConfig.Username = cfg.Username
+		etcdConfig.Password = cfg.Password
+		return registry.NewEtcdRegistry(etcdConfig)
+	case "memory":
+		memoryConfig := registry.DefaultMemoryRegistryConfig()
+		if cfg.TTL > 0 {
+			memoryConfig.TTL = time.Duration(cfg.TTL) * time.Second
+		}
+		if cfg.HeartbeatInterval > 0 {
+			memoryConfig.HeartbeatInterval = time.Duration(cfg.HeartbeatInterval) * time.Second
+		}
+		return registry.NewMemoryRegistry(memoryConfig), nil
+	default:
+		return nil, fmt.Errorf("unsupported registry type: %s", cfg.Type)
+	}
+}
+
+// observabilityConfig 按 framework.observability 构造可观测性配置
+func observabilityConfig(cfg *config.FrameworkConfig) observability.Config {
+	logging := cfg.Observability.Logging
+	tracing := cfg.Observability.Tracing
+	return observability.Config{
+		ServiceName:    cfg.Name,
+		ServiceVersion: cfg.Version,
+		MetricsPort:    cfg.Observability.Metrics.Port,
+		LogLevel:       observability.LogLevel(logging.Level),
+		Logging: &observability.LoggerConfig{
+			Level:         observability.LogLevel(logging.Level),
+			Format:        logging.Format,
+			Output:        logging.Output,
+			FilePath:      logging.FilePath,
+			MaxSizeMB:     logging.MaxSizeMB,
+			MaxBackups:    logging.MaxBackups,
+			SyslogNetwork: logging.SyslogNetwork,
+			SyslogAddress: logging.SyslogAddress,
+		},
+		Metrics: &observability.MetricsConfig{
+			DurationBuckets: cfg.Observability.Metrics.Buckets,
+			Labels:          cfg.Observability.Metrics.Labels,
+		},
+		Exporter: &observability.ExporterConfig{
+			Enabled:      tracing.Enabled,
+			Exporter:     tracing.Exporter,
+			Endpoint:     tracing.Endpoint,
+			Headers:      tracing.Headers,
+			Insecure:     tracing.Insecure,
+			SamplingRate: tracing.SamplingRate,
+			BatchTimeout: tracing.BatchTimeout,
+		},
+	}
+}
+
+// clientConfig 按 framework.connectionPool 和 framework.services 构造客户端配置，
+// protocol 为 MQ 的服务经 broker 调用，配置了 darkLaunch 的服务比较完成后调用 onDarkLaunch
+func clientConfig(cfg *config.FrameworkConfig, reg registry.ServiceRegistry, broker messaging.Broker,
+	onDarkLaunch func(ctx context.Context, result *client.DarkLaunchResult)) *client.Config {
+	conn := connectionConfig(cfg.ConnectionPool)
+	services := make(map[string]client.ServiceOptions)
+	for _, name := range cfg.ServiceNames() {
+		service := cfg.Service(name)
+		options := client.ServiceOptions{
+			Timeout:           service.Timeout,
+			Messaging:         strings.EqualFold(service.Protocol, protocolMQ),
+			IdempotentMethods: service.IdempotentMethods,
+		}
+		if retry := service.Retry; retry.MaxAttempts > 0 {
+			options.RetryPolicy = resilience.NewRetryPolicy(retry.MaxAttempts, retry.InitialDelay, retry.MaxDelay, retry.Multiplier)
+			options.RetryPolicy.RetryNonIdempotent = retry.NonIdempotent
+		}
+		if adaptive := service.AdaptiveTimeout; adaptive.Enabled {
+			options.AdaptiveTimeout = resilience.NewAdaptiveTimeout(&resilience.AdaptiveTimeoutConfig{
+				Percentile: adaptive.Percentile,
+				Multiplier: adaptive.Multiplier,
+				Floor:      adaptive.Floor,
+				Ceiling:    adaptive.Ceiling,
+			})
+		}
+		if dark := service.DarkLaunch; dark.Candidate != "" {
+			options.DarkLaunch = &client.DarkLaunchOptions{
+				Candidate:    dark.Candidate,
+				SampleRate:   dark.SampleRate,
+				Methods:      dark.Methods,
+				IgnoreFields: dark.IgnoreFields,
+				Timeout:      dark.Timeout,
+				OnResult:     onDarkLaunch,
+			}
+		}
+		services[name] = options
+
+		if conn.Services == nil {
+			conn.Services = make(map[string]*connection.ConnectionConfig)
+		}
+		conn.Services[name] = connectionConfig(service.ConnectionPool)
+	}
+
+	return &client.Config{
+		Registry:   reg,
+		Connection: conn,
+		Services:   services,
+		Broker:     broker,
+		Rules:      routingRules(cfg.Routes),
+	}
+}
+
+// routingRules 将 framework.routes 转换为客户端的路由规则
+func routingRules(routes []config.RouteConfig) []*router.RoutingRule {
+	rules := make([]*router.RoutingRule, 0, len(routes))
+	for _, route := range routes {
+		route := route
+		rule := &router.RoutingRule{
+			Name:     route.Name,
+			Priority: route.Priority,
+			Matcher: func(req *adapter.InternalRequest) bool {
+				if req.Service != route.Service {
+					return false
+				}
+				if len(route.Methods) == 0 {
+					return true
+				}
+				for _, method := range route.Methods {
+					if method == req.Method {
+						return true
+					}
+				}
+				return false
+			},
+			Target: func(req *adapter.InternalRequest) string { return route.Target },
+		}
+		if !route.Start.IsZero() || !route.End.IsZero() || route.Cron != "" {
+			rule.Schedule = &router.Schedule{
+				Start:    route.Start,
+				End:      route.End,
+				Cron:     route.Cron,
+				Duration: route.Duration,
+				Location: route.Location(),
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// transformRules 将 framework.transforms 转换为适配器的转换规则
+func transformRules(transforms []config.TransformConfig) []adapter.TransformRule {
+	rules := make([]adapter.TransformRule, 0, len(transforms))
+	for _, t := range transforms {
+		rules = append(rules, adapter.TransformRule{
+			Service:      t.Service,
+			Method:       t.Method,
+			Path:         t.Path,
+			Rename:       t.Rename,
+			Defaults:     t.Defaults,
+			StripHeaders: t.StripHeaders,
+		})
+	}
+	return rules
+}
+
+// newRecorder 按 framework.capture 创建请求录制器，最近的记录保存在 s.capture 中，返回关闭录制文件的函数
+func (s *Server) newRecorder(cfg *config.CaptureConfig) (*capture.Recorder, func(ctx context.Context) error, error) {
+	s.capture = capture.NewRingBuffer(cfg.BufferSize)
+	var sink capture.Sink = s.capture
+	closeFile := func(ctx context.Context) error { return nil }
+	if cfg.File != "" {
+		file, err := capture.NewFileSink(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink = capture.MultiSink{s.capture, file}
+		closeFile = func(ctx context.Context) error { return file.Close() }
+	}
+
+	recorder := capture.NewRecorder(&capture.Options{
+		Sink:           sink,
+		SampleRate:     cfg.SampleRate,
+		Services:       cfg.Services,
+		RedactFields:   cfg.RedactFields,
+		MaxPayloadSize: int(cfg.MaxPayloadSize),
+		OnError: func(err error) {
+			s.observability.Logger().Warn(context.Background(), "Failed to capture request",
+				observability.Field{Key: "error", Value: err.Error()})
+		},
+	})
+	return recorder, closeFile, nil
+}
+
+// newPayloadLogger 按 framework.payloadLog 创建负载日志，记录写入框架日志
+func (s *Server) newPayloadLogger(cfg *config.PayloadLogConfig) *capture.PayloadLogger {
+	return capture.NewPayloadLogger(&capture.PayloadLogOptions{
+		Enabled:      cfg.Enabled,
+		PerMinute:    cfg.PerMinute,
+		MaxSize:      int(cfg.MaxSize),
+		Services:     cfg.Services,
+		RedactFields: cfg.RedactFields,
+		Handler: func(ctx context.Context, log *capture.PayloadLog) {
+			fields := []observability.Field{
+				{Key: "service", Value: log.Service},
+				{Key: "method", Value: log.Method},
+				{Key: "status", Value: log.Status},
+				{Key: "duration", Value: log.Duration.String()},
+				{Key: "request", Value: log.Request},
+			}
+			if log.Response != "" {
+				fields = append(fields, observability.Field{Key: "response", Value: log.Response})
+			}
+			if log.Encoding != "" {
+				fields = append(fields, observability.Field{Key: "encoding", Value: log.Encoding})
+			}
+			if log.TraceId != "" {
+				fields = append(fields, observability.Field{Key: "trace_id", Value: log.TraceId})
+			}
+			if log.Truncated {
+				fields = append(fields, observability.Field{Key: "truncated", Value: true})
+			}
+			s.observability.Logger().Info(ctx, "Request payload", fields...)
+		},
+	})
+}
+
+// connectionConfig 将连接池配置转换为连接配置，未设置的字段使用默认值
+func connectionConfig(pool config.ConnectionPoolConfig) *connection.ConnectionConfig {
+	conn := connection.DefaultConnectionConfig()
+	if pool.MaxConnections > 0 {
+		conn.MaxConnections = pool.MaxConnections
+	}
+	if pool.MinConnections > 0 {
+		conn.MinConnections = pool.MinConnections
+	}
+	if pool.IdleTimeout > 0 {
+		conn.IdleTimeout = pool.IdleTimeout
+	}
+	if pool.MaxLifetime > 0 {
+		conn.MaxLifetime = pool.MaxLifetime
+	}
+	if pool.ConnectionTimeout > 0 {
+		conn.ConnectionTimeout = pool.ConnectionTimeout
+		conn.ConnectTimeout = pool.ConnectionTimeout
+	}
+	return conn
+}
+
+// optionString 读取协议选项中的字符串，不存在时返回 def
+func optionString(options map[string]interface{}, key, def string) string {
+	if value, ok := options[key]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return def
+}
+
+// optionBool 读取协议选项中的布尔值，不存在或不是布尔值时返回 false
+func optionBool(options map[string]interface{}, key string) bool {
+	value, _ := options[key].(bool)
+	return value
+}
+
+// restCachePolicies 读取 REST 协议选项中按业务方法的 cache 策略
+//
+//	options:
+//	  cache:
+//	    - method: catalog.*
+//	      maxAge: 60s
+//	      sharedMaxAge: 5m
+//	    - method: user.getProfile
+//	      maxAge: 30s
+//	      private: true
+func restCachePolicies(options map[string]interface{}) ([]rest.CachePolicy, error) {
+	values, ok := options["cache"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	policies := make([]rest.CachePolicy, 0, len(values))
+	for i, value := range values {
+		item, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("REST cache[%d] must be an object", i)
+		}
+		policy := rest.CachePolicy{
+			Method:  optionString(item, "method", ""),
+			Private: optionBool(item, "private"),
+			NoStore: optionBool(item, "noStore"),
+		}
+		if policy.Method == "" {
+			return nil, fmt.Errorf("REST cache[%d].method is required", i)
+		}
+		durations := map[string]*time.Duration{
+			"maxAge":               &policy.MaxAge,
+			"sharedMaxAge":         &policy.SharedMaxAge,
+			"staleWhileRevalidate": &policy.StaleWhileRevalidate,
+		}
+		for key, target := range durations {
+			var err error
+			if *target, err = time.ParseDuration(optionString(item, key, "0s")); err != nil {
+				return nil, fmt.Errorf("REST cache[%d].%s: %w", i, key, err)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// restEnvelope 读取 REST 协议选项中的统一响应信封配置，未配置或未启用时返回 nil（不包装响应）
+//
+//	options:
+//	  envelope:
+//	    enabled: true
+//	    successCode: 0
+//	    successMessage: success
+//	    raw: [files.download, callback.*]
+func restEnvelope(options map[string]interface{}) (*rest.EnvelopeConfig, error) {
+	item, ok := options["envelope"].(map[string]interface{})
+	if !ok || !optionBool(item, "enabled") {
+		return nil, nil
+	}
+	envelope := &rest.EnvelopeConfig{SuccessMessage: optionString(item, "successMessage", "")}
+	if value, ok := item["successCode"]; ok {
+		code, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("REST envelope.successCode must be an integer: %v", value)
+		}
+		envelope.SuccessCode = code
+	}
+	if value, ok := item["raw"]; ok {
+		methods, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("REST envelope.raw must be a list of methods")
+		}
+		for i, method := range methods {
+			name := fmt.Sprint(method)
+			if method == nil || name == "" {
+				return nil, fmt.Errorf("REST envelope.raw[%d] is empty", i)
+			}
+			envelope.Raw = append(envelope.Raw, name)
+		}
+	}
+	return envelope, nil
+}
+
+// restCompression 读取 REST 协议选项中的压缩配置，未配置或未启用时返回 nil（不压缩）
+//
+//	options:
+//	  compression:
+//	    enabled: true
+//	    encodings: [zstd, gzip]
+//	    minSize: 1KB
+//	    maxDecodedSize: 8MB
+func restCompression(options map[string]interface{}) (*rest.CompressionConfig, error) {
+	item, ok := options["compression"].(map[string]interface{})
+	if !ok || !optionBool(item, "enabled") {
+		return nil, nil
+	}
+	compression := &rest.CompressionConfig{Encodings: optionStrings(item, "encodings")}
+	minSize, err := config.ParseByteSize(optionString(item, "minSize", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("REST compression.minSize: %w", err)
+	}
+	maxDecodedSize, err := config.ParseByteSize(optionString(item, "maxDecodedSize", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("REST compression.maxDecodedSize: %w", err)
+	}
+	compression.MinSize = int(minSize)
+	compression.MaxDecodedSize = int64(maxDecodedSize)
+	return compression, nil
+}
+
+// jsonRpcAttachments 读取 JSON-RPC 协议选项中的 attachments 限制，未配置时返回 nil（不接受 multipart 请求）
+//
+//	options:
+//	  attachments:
+//	    maxSize: 32MB
+//	    maxTotalSize: 64MB
+//	    memoryThreshold: 1MB
+//	    tempDir: /var/tmp/uploads
+func jsonRpcAttachments(options map[string]interface{}) (*adapter.AttachmentLimits, error) {
+	item, ok := options["attachments"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	limits := &adapter.AttachmentLimits{TempDir: optionString(item, "tempDir", "")}
+	sizes := map[string]*int64{
+		"maxSize":         &limits.MaxSize,
+		"maxTotalSize":    &limits.MaxTotalSize,
+		"memoryThreshold": &limits.MemoryThreshold,
+	}
+	for key, target := range sizes {
+		size, err := config.ParseByteSize(optionString(item, key, "0"))
+		if err != nil {
+			return nil, fmt.Errorf("JSON-RPC attachments.%s: %w", key, err)
+		}
+		*target = int64(size)
+	}
+	return limits, nil
+}
+
+// mqttRateLimits 读取 MQTT 协议选项中的 rateLimits 规则
+//
+//	options:
+//	  rateLimits:
+//	    - topic: devices/+/telemetry
+//	      perDevice: true
+//	      rate: 10
+//	      burst: 20
+//	      action: disconnect
+//	      blockFor: 5m
+func mqttRateLimits(options map[string]interface{}) ([]mqtt.RateLimitRule, error) {
+	values, ok := options["rateLimits"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	rules := make([]mqtt.RateLimitRule, 0, len(values))
+	for i, value := range values {
+		item, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("MQTT rateLimits[%d] must be an object", i)
+		}
+		rule := mqtt.RateLimitRule{
+			Topic:     optionString(item, "topic", ""),
+			PerDevice: optionBool(item, "perDevice"),
+			Action:    mqtt.RateLimitAction(strings.ToLower(optionString(item, "action", ""))),
+		}
+		var err error
+		if rule.Rate, err = strconv.ParseFloat(optionString(item, "rate", "0"), 64); err != nil {
+			return nil, fmt.Errorf("MQTT rateLimits[%d].rate: %w", i, err)
+		}
+		if rule.Burst, err = strconv.Atoi(optionString(item, "burst", "0")); err != nil {
+			return nil, fmt.Errorf("MQTT rateLimits[%d].burst: %w", i, err)
+		}
+		if rule.MaxWait, err = time.ParseDuration(optionString(item, "maxWait", "0s")); err != nil {
+			return nil, fmt.Errorf("MQTT rateLimits[%d].maxWait: %w", i, err)
+		}
+		if rule.BlockFor, err = time.ParseDuration(optionString(item, "blockFor", "0s")); err != nil {
+			return nil, fmt.Errorf("MQTT rateLimits[%d].blockFor: %w", i, err)
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("MQTT rateLimits[%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// mqttDisconnect 记录以 disconnect 限流的设备并调用 Options.MQTTDisconnect
+func (s *Server) mqttDisconnect(topic, device string) {
+	s.observability.Logger().Warn(context.Background(), "MQTT device exceeded rate limit, blocking its messages",
+		observability.Field{Key: "topic", Value: topic},
+		observability.Field{Key: "device", Value: device})
+	if s.options.MQTTDisconnect != nil {
+		s.options.MQTTDisconnect(topic, device)
+	}
+}
+
+// isBrokerProtocol 判断协议是否连接消息中间件而不监听本地端口
+func isBrokerProtocol(protocol string) bool {
+	return strings.EqualFold(protocol, protocolMQTT) || strings.EqualFold(protocol, protocolKafka) ||
+		strings.EqualFold(protocol, protocolMQ)
+}
+
+// optionStrings 读取协议选项中的字符串列表
+func optionStrings(options map[string]interface{}, key string) []string {
+	values, ok := options[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, fmt.Sprint(value))
+	}
+	return result
+}
+
+// optionStringMap 读取协议选项中的字符串映射
+func optionStringMap(options map[string]interface{}, key string) map[string]string {
+	values, ok := options[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for k, value := range values {
+		result[k] = fmt.Sprint(value)
+	}
+	return result
+}
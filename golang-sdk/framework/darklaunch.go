@@ -0,0 +1,38 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/framework/golang-sdk/client"
+	"github.com/framework/golang-sdk/observability"
+)
+
+// logDarkLaunch 将暗发布比较中不一致的结果写入框架日志，一致的结果只在 debug 级别记录
+func (s *Server) logDarkLaunch(ctx context.Context, result *client.DarkLaunchResult) {
+	fields := []observability.Field{
+		{Key: "service", Value: result.Service},
+		{Key: "candidate", Value: result.Candidate},
+		{Key: "method", Value: result.Method},
+		{Key: "primary_duration", Value: result.PrimaryDuration.String()},
+		{Key: "candidate_duration", Value: result.CandidateDuration.String()},
+	}
+	if result.Match {
+		s.observability.Logger().Debug(ctx, "Dark launch match", fields...)
+		return
+	}
+
+	fields = append(fields,
+		observability.Field{Key: "primary_status", Value: result.PrimaryStatus},
+		observability.Field{Key: "candidate_status", Value: result.CandidateStatus})
+	if len(result.Diffs) > 0 {
+		diffs := make([]string, 0, len(result.Diffs))
+		for _, diff := range result.Diffs {
+			diffs = append(diffs, diff.String())
+		}
+		fields = append(fields, observability.Field{Key: "diffs", Value: diffs})
+	}
+	if result.Truncated {
+		fields = append(fields, observability.Field{Key: "truncated", Value: true})
+	}
+	s.observability.Logger().Warn(ctx, "Dark launch mismatch", fields...)
+}
@@ -0,0 +1,74 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/framework/golang-sdk/admin"
+	"github.com/framework/golang-sdk/config"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/messaging"
+)
+
+// newDeadLetterQueue 按 framework.deadLetter 创建死信队列，Options.DeadLetterStore 不为 nil 时代替配置的存储；
+// 返回的关闭函数关闭按配置创建的存储
+func (s *Server) newDeadLetterQueue(cfg *config.DeadLetterConfig) (*messaging.DeadLetterQueue, func() error, error) {
+	if !cfg.Enabled && s.options.DeadLetterStore == nil {
+		return nil, nil, nil
+	}
+
+	store := s.options.DeadLetterStore
+	closeStore := func() error { return nil }
+	if store == nil {
+		switch strings.ToLower(cfg.Store) {
+		case "", config.DeadLetterStoreMemory:
+			store = messaging.NewMemoryDeadLetterStore()
+		case config.DeadLetterStoreFile:
+			file, err := messaging.NewFileDeadLetterStore(cfg.Dir)
+			if err != nil {
+				return nil, nil, err
+			}
+			store = file
+		case config.DeadLetterStoreRedis:
+			redis, err := messaging.NewRedisDeadLetterStore(&messaging.RedisConfig{
+				Address:  cfg.Redis.Address,
+				Username: cfg.Redis.Username,
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.DB,
+			}, cfg.Redis.Key)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to connect dead letter store: %w", err)
+			}
+			store, closeStore = redis, redis.Close
+		default:
+			return nil, nil, fmt.Errorf("unsupported dead letter store: %q", cfg.Store)
+		}
+	}
+	return messaging.NewDeadLetterQueue(store), closeStore, nil
+}
+
+// DeadLetters 返回死信队列，未启用 framework.deadLetter 且未设置 Options.DeadLetterStore 时为 nil；
+// 传给 messaging.Options.DeadLetters 可使业务的事件订阅共用死信队列和管理接口
+func (s *Server) DeadLetters() *messaging.DeadLetterQueue {
+	return s.deadLetters
+}
+
+// deadLetterIDs 解析 {"ids": ["..."]} 指定的死信
+func deadLetterIDs(params json.RawMessage) ([]string, error) {
+	var p struct {
+		IDs []string `json:"ids"`
+	}
+	if err := admin.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.IDs) == 0 {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "ids is required")
+	}
+	return p.IDs, nil
+}
+
+// deadLettersDisabled 未启用死信队列时的错误
+func deadLettersDisabled() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "dead letter queue is not enabled")
+}
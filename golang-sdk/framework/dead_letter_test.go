@@ -0,0 +1,96 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/registry"
+)
+
+// TestDeadLetters 测试 MQ 协议的单向请求处理失败时写入死信存储，经管理接口列出、重放和删除
+func TestDeadLetters(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	cfg := strings.Replace(testConfig, "  protocols:\n    external:\n", "  deadLetter:\n    enabled: true\n    store: file\n    dir: "+
+		t.TempDir()+"\n  protocols:\n    external:\n      - type: MQ\n        enabled: true\n", 1)
+	server, err := NewServerWithOptions(writeTestConfig(t, cfg), &Options{
+		Registry:          reg,
+		Broker:            broker,
+		AdminAuthenticate: func(r *http.Request, operation string) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if server.DeadLetters() == nil {
+		t.Fatal("Expected dead letter queue")
+	}
+	healthy := false
+	server.Handle("greeter.notify", func(ctx context.Context, params interface{}) (interface{}, error) {
+		if !healthy {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "mail server unavailable")
+		}
+		return nil, nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	call := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Admin().ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	broker.Publish(context.Background(), &messaging.Message{
+		ID:      "m-1",
+		Topic:   messaging.RequestTopic("greeter-service"),
+		Headers: map[string]string{messaging.HeaderMethod: "greeter.notify"},
+		Payload: []byte(`{"to":"alice"}`),
+	})
+
+	code, body := call(http.MethodGet, "/admin/deadLetters?limit=10", "")
+	if code != http.StatusOK {
+		t.Fatalf("deadLetters returned %d: %s", code, body)
+	}
+	var letters []messaging.DeadLetter
+	if err := json.Unmarshal([]byte(body), &letters); err != nil || len(letters) != 1 {
+		t.Fatalf("Unexpected dead letters: %s", body)
+	}
+	if letters[0].ID != "m-1" || letters[0].Error != "mail server unavailable" || string(letters[0].Payload) != `{"to":"alice"}` {
+		t.Errorf("Unexpected dead letter: %+v", letters[0])
+	}
+	if code, _ := call(http.MethodGet, "/admin/deadLetters?limit=x", ""); code != http.StatusBadRequest {
+		t.Errorf("invalid limit returned %d, want 400", code)
+	}
+	if code, _ := call(http.MethodPost, "/admin/deadLetters.replay", `{}`); code != http.StatusBadRequest {
+		t.Errorf("replay without ids returned %d, want 400", code)
+	}
+
+	healthy = true
+	code, body = call(http.MethodPost, "/admin/deadLetters.replay", `{"ids":["m-1"]}`)
+	if code != http.StatusOK || !strings.Contains(body, `"id":"m-1"`) || strings.Contains(body, `"error"`) {
+		t.Fatalf("deadLetters.replay returned %d: %s", code, body)
+	}
+	if letters, _ := server.DeadLetters().List(context.Background(), "", 0); len(letters) != 0 {
+		t.Errorf("Expected dead letter removed after replay, got %v", letters)
+	}
+
+	server.DeadLetters().Store().Save(context.Background(), &messaging.DeadLetter{ID: "m-2", Topic: "greeter.events"})
+	if code, body := call(http.MethodPost, "/admin/deadLetters.delete", `{"ids":["m-2"]}`); code != http.StatusOK {
+		t.Fatalf("deadLetters.delete returned %d: %s", code, body)
+	}
+	if letters, _ := server.DeadLetters().List(context.Background(), "", 0); len(letters) != 0 {
+		t.Errorf("Expected dead letter deleted, got %v", letters)
+	}
+}
@@ -0,0 +1,138 @@
+package framework
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// embeddedServer Options.EmbedHTTP 时 REST、WebSocket、JSON-RPC 和 gRPC-Web 共用的 HTTP 服务器，
+// 只注册路由，不在端口上接受连接，请求由应用的服务器经 Handler 或 Middleware 转入
+type embeddedServer struct {
+	server *ghttp.Server
+	// paths 转入的路径前缀，为协议的 path 和 HealthPath
+	paths []string
+
+	mu      sync.RWMutex
+	running bool
+}
+
+// addPath 记录转入的路径前缀，空路径或 / 匹配所有请求
+func (e *embeddedServer) addPath(path string) {
+	e.paths = append(e.paths, "/"+strings.Trim(path, "/"))
+}
+
+// matches 请求路径是否属于框架的协议
+func (e *embeddedServer) matches(path string) bool {
+	for _, prefix := range e.paths {
+		if prefix == "/" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// start 在不接受连接的监听器上启动服务器，使路由生效
+func (e *embeddedServer) start() error {
+	if err := e.server.SetListener(newIdleListener()); err != nil {
+		return err
+	}
+	if err := e.server.Start(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.running = true
+	e.mu.Unlock()
+	return nil
+}
+
+// stop 停止转入请求并关闭服务器
+func (e *embeddedServer) stop() error {
+	e.mu.Lock()
+	e.running = false
+	e.mu.Unlock()
+	return e.server.Shutdown()
+}
+
+// ServeHTTP 服务未启动或已关闭时返回 ServiceUnavailable
+func (e *embeddedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	running := e.running
+	e.mu.RUnlock()
+	if !running {
+		fe := frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "framework HTTP protocols are not running")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(fe.Code.ToHTTPStatus())
+		json.NewEncoder(w).Encode(fe.ToPayload())
+		return
+	}
+	e.server.ServeHTTP(w, r)
+}
+
+// Handler 返回处理框架 HTTP 协议（REST、WebSocket、JSON-RPC、gRPC-Web 和 HealthPath）请求的 http.Handler，
+// 供挂载到应用已运行的 net/http 服务器，须启用 Options.EmbedHTTP：
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/api/", server.Handler())
+//	mux.Handle("/rpc", server.Handler())
+//
+// 请求路径不经改写，挂载路径应与协议的 path 一致；Start 完成前和 Shutdown 后返回 503。
+// 未启用 Options.EmbedHTTP 时返回 nil
+func (s *Server) Handler() http.Handler {
+	if s.embedded == nil {
+		return nil
+	}
+	return s.embedded
+}
+
+// Middleware 返回 GoFrame 中间件，将路径属于框架 HTTP 协议（协议的 path 和 HealthPath 及其子路径）的请求
+// 交给框架处理，其他请求交给应用的路由，须启用 Options.EmbedHTTP：
+//
+//	s := g.Server()
+//	s.Use(server.Middleware())
+//
+// 协议的 path 为空或 / 时所有请求都交给框架。未启用 Options.EmbedHTTP 时中间件直接交给应用的路由
+func (s *Server) Middleware() ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		if s.embedded == nil || !s.embedded.matches(r.URL.Path) {
+			r.Middleware.Next()
+			return
+		}
+		// 直接写入底层连接，流式响应和 WebSocket 升级不经应用服务器的缓冲
+		s.embedded.ServeHTTP(r.Response.RawWriter(), r.Request)
+		r.ExitAll()
+	}
+}
+
+// idleListener 不接受连接的监听器，Accept 阻塞到 Close
+type idleListener struct {
+	once   sync.Once
+	closed chan struct{}
+}
+
+// newIdleListener 创建不接受连接的监听器
+func newIdleListener() *idleListener {
+	return &idleListener{closed: make(chan struct{})}
+}
+
+// Accept 阻塞到 Close，返回 net.ErrClosed
+func (l *idleListener) Accept() (net.Conn, error) {
+	<-l.closed
+	return nil, net.ErrClosed
+}
+
+// Close 使 Accept 返回
+func (l *idleListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+// Addr 返回回环地址的 0 端口，ghttp 要求监听器的地址为 *net.TCPAddr
+func (l *idleListener) Addr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
@@ -0,0 +1,114 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+func TestServerEmbedHTTP(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:   reg,
+		HealthPath: "/health",
+		EmbedHTTP:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/jsonrpc", server.Handler())
+	mux.Handle("/health/", server.Handler())
+	app := httptest.NewServer(mux)
+	defer app.Close()
+
+	call := func(url string) (int, string) {
+		resp, err := http.Post(url, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"greeter.hello","id":1}`))
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", url, err)
+		}
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		greeting, _ := result["result"].(string)
+		return resp.StatusCode, greeting
+	}
+
+	// Start 前返回 503
+	if code, _ := call(app.URL + "/jsonrpc"); code != http.StatusServiceUnavailable {
+		t.Errorf("Before start = %d, want 503", code)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// 协议端口不监听，请求经应用的 net/http 服务器转入
+	if conn, err := net.DialTimeout("tcp", "127.0.0.1:18401", time.Second); err == nil {
+		conn.Close()
+		t.Error("Expected network.port not to be listening")
+	}
+	if code, greeting := call(app.URL + "/jsonrpc"); code != http.StatusOK || greeting != "Hello" {
+		t.Errorf("net/http JSON-RPC = %d %q, want Hello", code, greeting)
+	}
+	resp, err := http.Get(app.URL + "/health/live")
+	if err != nil {
+		t.Fatalf("GET /health/live failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("live = %d, want 200", resp.StatusCode)
+	}
+
+	// GoFrame 服务器：框架的路径交给框架，其他路径交给应用的路由
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	gf := g.Server("framework-embed-test")
+	gf.SetListener(listener)
+	gf.SetDumpRouterMap(false)
+	gf.Use(server.Middleware())
+	gf.BindHandler("/app", func(r *ghttp.Request) {
+		r.Response.Write("app")
+	})
+	if err := gf.Start(); err != nil {
+		t.Fatalf("GoFrame server start failed: %v", err)
+	}
+	defer gf.Shutdown()
+	gfURL := "http://" + listener.Addr().String()
+	if code, greeting := call(gfURL + "/jsonrpc"); code != http.StatusOK || greeting != "Hello" {
+		t.Errorf("GoFrame JSON-RPC = %d %q, want Hello", code, greeting)
+	}
+	resp, err = http.Get(gfURL + "/app")
+	if err != nil {
+		t.Fatalf("GET /app failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "app" {
+		t.Errorf("/app = %q, want app", body)
+	}
+
+	// 注册中心中的端口仍为配置的端口
+	services, _ := reg.Discover(context.Background(), "greeter-service")
+	if len(services) != 1 || services[0].Port != 18401 {
+		t.Errorf("Unexpected registered instances: %+v", services)
+	}
+}
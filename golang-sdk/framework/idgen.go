@@ -0,0 +1,20 @@
+package framework
+
+import (
+	"github.com/framework/golang-sdk/idgen"
+)
+
+// initIDGenerator 按 framework.idGenerator 创建 ID 生成器并设为进程的默认生成器，Options.IDGenerator 不为 nil 时代替配置；
+// 协议适配器生成的追踪 ID、span ID 和请求 ID 以及 OpenTelemetry 的 span 都使用该生成器
+func (s *Server) initIDGenerator() error {
+	ids := s.options.IDGenerator
+	if ids == nil {
+		var err error
+		if ids, err = idgen.New(s.config.IDGenerator.Strategy, s.config.IDGenerator.Node); err != nil {
+			return err
+		}
+	}
+	idgen.SetDefault(ids)
+	s.ids = ids
+	return nil
+}
@@ -0,0 +1,39 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/framework/golang-sdk/idgen"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestServerIDGenerator(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	defer idgen.SetDefault(nil)
+
+	content := testConfig + `  idGenerator:
+    strategy: snowflake
+    node: 7
+`
+	server, err := NewServerWithOptions(writeTestConfig(t, content), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if _, ok := server.IDGenerator().(*idgen.Snowflake); !ok {
+		t.Fatalf("Expected snowflake generator, got %T", server.IDGenerator())
+	}
+	// 协议适配器经 idgen.Default 使用同一生成器
+	if idgen.Default() != server.IDGenerator() {
+		t.Error("Expected the server generator to be the process default")
+	}
+
+	// Options.IDGenerator 代替配置的策略
+	server, err = NewServerWithOptions(writeTestConfig(t, content), &Options{Registry: reg, IDGenerator: idgen.UUIDv7{}})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if _, ok := idgen.Default().(idgen.UUIDv7); !ok {
+		t.Errorf("Expected UUIDv7 default, got %T", idgen.Default())
+	}
+}
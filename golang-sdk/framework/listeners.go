@@ -0,0 +1,194 @@
+package framework
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/framework/golang-sdk/config"
+)
+
+// listener 配置中的一个本地监听端口
+type listener struct {
+	// key 配置路径，如 framework.protocols.internal[1]
+	key      string
+	protocol string
+	// owner 端口的使用者，同一端口的 REST、WebSocket、JSON-RPC 和 gRPC-Web 共用 HTTP 服务器，owner 均为 HTTP
+	owner    string
+	host     string
+	port     int
+	internal bool
+}
+
+// listeners 返回启用的协议和指标端点的监听端口，MQTT、Kafka 和 MQ 连接消息中间件，不在本地监听
+func listeners(cfg *config.FrameworkConfig) []listener {
+	var result []listener
+	for i, p := range cfg.Protocols.External {
+		if !p.Enabled || isBrokerProtocol(p.Type) {
+			continue
+		}
+		result = append(result, listener{
+			key:      fmt.Sprintf("framework.protocols.external[%d]", i),
+			protocol: p.Type,
+			owner:    "HTTP",
+			host:     protocolHost(cfg.Network.Host, p.Host),
+			port:     p.Port,
+		})
+	}
+	for i, p := range cfg.Protocols.Internal {
+		if !p.Enabled {
+			continue
+		}
+		result = append(result, listener{
+			key:      fmt.Sprintf("framework.protocols.internal[%d]", i),
+			protocol: p.Type,
+			owner:    "internal " + p.Type,
+			host:     protocolHost(cfg.Network.Host, p.Host),
+			port:     p.Port,
+			internal: true,
+		})
+	}
+	if cfg.Observability.Metrics.Enabled {
+		result = append(result, listener{
+			key:      "framework.observability.metrics",
+			protocol: "metrics",
+			owner:    "metrics",
+			port:     cfg.Observability.Metrics.Port,
+		})
+	}
+	return result
+}
+
+// validatePorts 检查各监听端口是否缺失、越界或冲突，一次返回所有问题
+func validatePorts(cfg *config.FrameworkConfig) error {
+	if problems := portProblems(cfg); len(problems) > 0 {
+		return fmt.Errorf("invalid listener configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// portProblems 返回监听端口的问题，每条形如 "问题: 修改方法"
+//
+// 同一端口的 HTTP 协议共用服务器不视为冲突；不同使用者监听同一端口的不同网卡（都不是通配地址）也不冲突
+func portProblems(cfg *config.FrameworkConfig) []string {
+	var problems []string
+	var claimed []listener
+	for _, l := range listeners(cfg) {
+		if l.port <= 0 || l.port > 65535 {
+			if l.port == 0 {
+				problems = append(problems, fmt.Sprintf("%s requires a port: set %s.port", l.owner, l.key))
+			} else {
+				problems = append(problems, fmt.Sprintf("%s port %d out of range 1-65535: fix %s.port", l.owner, l.port, l.key))
+			}
+			continue
+		}
+		for _, other := range claimed {
+			if other.port == l.port && other.owner != l.owner && hostsOverlap(other.host, l.host) {
+				problems = append(problems, fmt.Sprintf("port %d is used by both %s and %s: change %s.port or %s.port",
+					l.port, other.owner, l.owner, other.key, l.key))
+				break
+			}
+		}
+		claimed = append(claimed, l)
+	}
+	return problems
+}
+
+// validateTLS 检查 framework.security.tls：启用时证书和私钥必须存在且匹配，CA 文件必须存在
+func validateTLS(tlsConfig *config.TLSConfig) error {
+	if !tlsConfig.Enabled {
+		return nil
+	}
+
+	var problems []string
+	if tlsConfig.CertFile == "" {
+		problems = append(problems, "framework.security.tls.certFile is empty")
+	}
+	if tlsConfig.KeyFile == "" {
+		problems = append(problems, "framework.security.tls.keyFile is empty")
+	}
+	files := []struct{ key, path string }{
+		{"certFile", tlsConfig.CertFile},
+		{"keyFile", tlsConfig.KeyFile},
+		{"caFile", tlsConfig.CAFile},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			problems = append(problems, fmt.Sprintf("framework.security.tls.%s %s is not readable: %v", f.key, f.path, err))
+		}
+	}
+	if len(problems) == 0 {
+		if _, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile); err != nil {
+			problems = append(problems, fmt.Sprintf("framework.security.tls certFile and keyFile cannot be loaded as a key pair: %v", err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("TLS is enabled but %s; provide the certificate files or set framework.security.tls.enabled to false",
+			strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// listenerWarnings 返回不阻止启动的监听配置问题：在非回环网卡上以明文提供服务的端口，以及不生效的 TLS 配置
+//
+// framework.security.tls 只用于内部 gRPC；mtls 为 true（设置了 Options.MTLS）时 HTTP 和所有内部协议都启用 TLS。
+// 共用同一 HTTP 服务器的协议合并为一条警告
+func listenerWarnings(cfg *config.FrameworkConfig, mtls bool) []string {
+	var warnings []string
+	httpProtocols := make(map[string][]string)
+	var httpAddresses []string
+	grpcTLS := false
+	for _, l := range listeners(cfg) {
+		grpc := l.internal && strings.EqualFold(l.protocol, protocolGRPC)
+		if grpc && cfg.Security.TLS.Enabled {
+			grpcTLS = true
+			continue
+		}
+		if mtls || l.owner == "metrics" || isLoopback(l.host) {
+			continue
+		}
+		host := l.host
+		if host == "" {
+			host = "0.0.0.0"
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(l.port))
+		switch {
+		case !l.internal:
+			if _, ok := httpProtocols[address]; !ok {
+				httpAddresses = append(httpAddresses, address)
+			}
+			httpProtocols[address] = append(httpProtocols[address], l.protocol)
+		case grpc:
+			warnings = append(warnings, fmt.Sprintf("internal gRPC (%s) serves plaintext on %s: enable framework.security.tls or set Options.MTLS",
+				l.key, address))
+		default:
+			warnings = append(warnings, fmt.Sprintf("internal %s (%s) serves plaintext on %s: set Options.MTLS or bind it to a private interface",
+				l.protocol, l.key, address))
+		}
+	}
+	for _, address := range httpAddresses {
+		warnings = append(warnings, fmt.Sprintf("HTTP (%s) serves plaintext on %s: set Options.MTLS or bind it to 127.0.0.1 behind a TLS-terminating proxy",
+			strings.Join(httpProtocols[address], ", "), address))
+	}
+	if cfg.Security.TLS.Enabled && !grpcTLS {
+		warnings = append(warnings, "framework.security.tls is enabled but only applies to internal gRPC, which is not enabled: "+
+			"set Options.MTLS to encrypt the other protocols")
+	}
+	return warnings
+}
+
+// hostsOverlap 判断两个监听地址是否可能占用同一端口，通配地址与所有地址重叠
+func hostsOverlap(a, b string) bool {
+	return a == b || isWildcard(a) || isWildcard(b)
+}
+
+// isWildcard 判断监听地址是否为通配地址
+func isWildcard(host string) bool {
+	return host == "" || host == "0.0.0.0" || host == "::"
+}
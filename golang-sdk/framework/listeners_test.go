@@ -0,0 +1,152 @@
+package framework
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/config"
+)
+
+// writeTestKeyPair 生成自签名证书和私钥，返回 PEM 文件路径
+func writeTestKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+func TestValidateTLS(t *testing.T) {
+	certFile, keyFile := writeTestKeyPair(t)
+	otherCert, _ := writeTestKeyPair(t)
+
+	tests := []struct {
+		name    string
+		tls     config.TLSConfig
+		wantErr string
+	}{
+		{name: "未启用不检查", tls: config.TLSConfig{CertFile: "missing.crt"}},
+		{name: "证书和私钥有效", tls: config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}},
+		{name: "缺少证书", tls: config.TLSConfig{Enabled: true, KeyFile: keyFile}, wantErr: "framework.security.tls.certFile is empty"},
+		{
+			name:    "证书文件不存在",
+			tls:     config.TLSConfig{Enabled: true, CertFile: filepath.Join(t.TempDir(), "missing.crt"), KeyFile: keyFile},
+			wantErr: "framework.security.tls.certFile",
+		},
+		{
+			name:    "证书与私钥不匹配",
+			tls:     config.TLSConfig{Enabled: true, CertFile: otherCert, KeyFile: keyFile},
+			wantErr: "cannot be loaded as a key pair",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTLS(&tt.tls)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "framework.security.tls.enabled to false") {
+				t.Errorf("Expected actionable error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListenerWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.FrameworkConfig)
+		mtls   bool
+		want   []string
+	}{
+		{
+			name:   "默认配置在通配地址上明文监听",
+			modify: func(cfg *config.FrameworkConfig) {},
+			want: []string{
+				"HTTP (REST, WebSocket, JSON-RPC) serves plaintext on 0.0.0.0:8081",
+				"internal gRPC (framework.protocols.internal[0]) serves plaintext on 0.0.0.0:9001: enable framework.security.tls",
+				"internal JSON-RPC (framework.protocols.internal[1]) serves plaintext on 0.0.0.0:9002",
+			},
+		},
+		{
+			name: "内部 gRPC 启用 TLS",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Network.Host = "127.0.0.1"
+				cfg.Protocols.Internal[0].Host = "10.0.0.5"
+				cfg.Security.TLS.Enabled = true
+			},
+		},
+		{
+			name:   "启用 mTLS",
+			modify: func(cfg *config.FrameworkConfig) {},
+			mtls:   true,
+		},
+		{
+			name: "只监听回环地址",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Network.Host = "127.0.0.1"
+			},
+		},
+		{
+			name: "TLS 未被任何协议使用",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Network.Host = "127.0.0.1"
+				cfg.Protocols.Internal[0].Enabled = false
+				cfg.Security.TLS.Enabled = true
+			},
+			want: []string{"framework.security.tls is enabled but only applies to internal gRPC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultFrameworkConfig()
+			tt.modify(cfg)
+			warnings := listenerWarnings(cfg, tt.mtls)
+			joined := strings.Join(warnings, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("Expected warning containing %q, got %v", want, warnings)
+				}
+			}
+			if len(tt.want) == 0 && len(warnings) > 0 {
+				t.Errorf("Expected no warnings, got %v", warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package framework
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/security"
+)
+
+// newMTLS 按 Options.MTLS 获取证书并开始自动续期，续期结果记录到日志
+func (s *Server) newMTLS() (*security.MTLSBootstrapper, error) {
+	config := *s.options.MTLS
+	onRotate, onError := config.OnRotate, config.OnError
+	config.OnRotate = func(bundle *security.CertificateBundle) {
+		s.observability.Logger().Info(context.Background(), "Certificate rotated",
+			observability.Field{Key: "notAfter", Value: bundle.Certificate.Leaf.NotAfter.Format(time.RFC3339)})
+		if onRotate != nil {
+			onRotate(bundle)
+		}
+	}
+	config.OnError = func(err error) {
+		s.observability.Logger().Error(context.Background(), "Failed to rotate certificate",
+			observability.Field{Key: "error", Value: err.Error()})
+		if onError != nil {
+			onError(err)
+		}
+	}
+
+	b, err := security.NewMTLSBootstrapper(context.Background(), &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap mTLS: %w", err)
+	}
+	s.observability.Logger().Info(context.Background(), "Certificate loaded",
+		observability.Field{Key: "notAfter", Value: b.NotAfter().Format(time.RFC3339)})
+	return b, nil
+}
+
+// serverTLSConfig 返回监听器的 TLS 配置，未配置 Options.MTLS 时为 nil
+func (s *Server) serverTLSConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	if s.mtls == nil {
+		return nil
+	}
+	return s.mtls.ServerTLSConfig(clientAuth)
+}
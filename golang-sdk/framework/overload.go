@@ -0,0 +1,42 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// inFlightQueue 过载控制中处理中请求数的队列名称
+const inFlightQueue = "in-flight"
+
+// newOverload 按 Options.Overload 创建过载控制器，处理中的请求数作为队列深度参与判断，过载级别变化记录到日志
+func (s *Server) newOverload() *resilience.OverloadController {
+	config := *s.options.Overload
+	queues := make(map[string]func() int, len(config.QueueDepths)+1)
+	queues[inFlightQueue] = s.inFlight.Count
+	for name, depth := range config.QueueDepths {
+		queues[name] = depth
+	}
+	config.QueueDepths = queues
+
+	onChange := config.OnChange
+	config.OnChange = func(from, to resilience.OverloadLevel, pressure resilience.Pressure) {
+		fields := []observability.Field{
+			{Key: "level", Value: to.String()},
+			{Key: "cpu", Value: fmt.Sprintf("%.2f", pressure.CPU)},
+			{Key: "goroutines", Value: pressure.Goroutines},
+			{Key: "queues", Value: pressure.QueueDepths},
+		}
+		if to > from {
+			s.observability.Logger().Warn(context.Background(), "Server overloaded, shedding requests", fields...)
+		} else {
+			s.observability.Logger().Info(context.Background(), "Server overload level lowered", fields...)
+		}
+		if onChange != nil {
+			onChange(from, to, pressure)
+		}
+	}
+	return resilience.NewOverloadController(s.config.Name, &config)
+}
@@ -0,0 +1,72 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/framework/golang-sdk/observability"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// DefaultPreStopDelay <HealthPath>/prestop 摘除流量后等待的默认时间
+const DefaultPreStopDelay = 5 * time.Second
+
+// servingCheckName 服务就绪检查的名称
+const servingCheckName = "serving"
+
+// errNotServing Start 尚未完成或已摘除流量
+var errNotServing = errors.New("server is not serving: starting, draining or shutting down")
+
+// servingCheck 就绪检查：Start 完成预热和注册后通过，Drain 和关闭时失败，
+// 使 Kubernetes 只在服务实例可以处理请求时转发流量
+func (s *Server) servingCheck() observability.HealthCheck {
+	return observability.NewSimpleHealthCheck(servingCheckName, func(ctx context.Context) error {
+		if !s.serving.Load() {
+			return errNotServing
+		}
+		return nil
+	})
+}
+
+// mountProbes 在 HTTP 服务器上挂载健康检查端点，供 Kubernetes 探针和 preStop 钩子访问：
+//
+//	<HealthPath>          全部检查
+//	<HealthPath>/live     存活检查
+//	<HealthPath>/ready    就绪检查
+//	<HealthPath>/prestop  摘除流量并等待 PreStopDelay
+func (s *Server) mountProbes(server *ghttp.Server, path string) {
+	path = "/" + strings.Trim(path, "/")
+	checker := s.observability.HealthChecker()
+	mount := func(pattern string, handler http.HandlerFunc) {
+		server.BindHandler(pattern, func(r *ghttp.Request) {
+			handler.ServeHTTP(r.Response.Writer, r.Request)
+		})
+	}
+	mount(path, checker.Handler())
+	mount(path+"/live", checker.LivenessHandler())
+	mount(path+"/ready", checker.ReadinessHandler())
+	mount(path+"/prestop", s.preStop)
+}
+
+// preStop 处理 Kubernetes preStop 钩子：从注册中心注销并使就绪检查失败，然后等待 PreStopDelay 再返回，
+// 使 Service 端点和调用方的服务实例列表在收到 SIGTERM 前完成刷新，期间继续处理请求
+func (s *Server) preStop(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := s.Drain(ctx); err != nil {
+		s.observability.Logger().Warn(ctx, "Failed to drain before stop",
+			observability.Field{Key: "error", Value: err.Error()})
+	}
+
+	delay := s.options.PreStopDelay
+	if delay <= 0 {
+		delay = DefaultPreStopDelay
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,123 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+)
+
+// ProtocolStatus 外部协议的运行时状态
+type ProtocolStatus struct {
+	Type    string `json:"type"`
+	Host    string `json:"host,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// switchableHandler 挂在共用 HTTP 服务器上的协议处理器，停用时拒绝新请求，服务器继续监听
+type switchableHandler interface {
+	SetEnabled(enabled bool)
+}
+
+// runtimeProtocol 可在运行时停用和重新启用的外部协议
+//
+// REST、WebSocket、JSON-RPC 和 gRPC-Web 与同端口的其他协议及探针共用 HTTP 服务器，停用时由处理器拒绝新请求并关闭连接；
+// MQTT、Kafka 和 MQ 停用时停止处理器（断开 Broker 连接、等待处理中的消息），启用时重新启动
+type runtimeProtocol struct {
+	status  ProtocolStatus
+	handler protocolHandler
+}
+
+// setEnabled 启用或停用协议
+func (p *runtimeProtocol) setEnabled(ctx context.Context, enabled bool) error {
+	if handler, ok := p.handler.(switchableHandler); ok {
+		handler.SetEnabled(enabled)
+	} else if enabled {
+		if err := p.handler.Start(); err != nil {
+			return fmt.Errorf("failed to start %s: %w", p.status.Type, err)
+		}
+	} else if err := p.handler.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop %s: %w", p.status.Type, err)
+	}
+	p.status.Enabled = enabled
+	return nil
+}
+
+// addProtocol 记录外部协议的处理器，供运行时启用或停用
+func (s *Server) addProtocol(protocolType, host string, port int, path string, handler protocolHandler) {
+	s.protocols = append(s.protocols, &runtimeProtocol{
+		status:  ProtocolStatus{Type: protocolType, Host: host, Port: port, Path: path, Enabled: true},
+		handler: handler,
+	})
+}
+
+// Protocols 返回配置中启用的外部协议及其运行时状态
+func (s *Server) Protocols() []ProtocolStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]ProtocolStatus, 0, len(s.protocols))
+	for _, p := range s.protocols {
+		statuses = append(statuses, p.status)
+	}
+	return statuses
+}
+
+// SetProtocolEnabled 在运行时停用或重新启用外部协议，无需重启服务，用于处置故障（如关闭异常的 MQTT 入口）
+//
+// protocolType 不区分大小写；同一协议配置了多个端口时须指定 port，为 0 时匹配唯一的一个。
+// 只能切换配置中启用的协议，服务未运行、协议不存在或不唯一时返回错误。停用不影响注册中心中的实例信息，
+// 需要调用方不再路由到本实例时使用 Drain
+func (s *Server) SetProtocolEnabled(ctx context.Context, protocolType string, port int, enabled bool) (*ProtocolStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started == nil || s.lifecycle.ShuttingDown() {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "server not running")
+	}
+
+	var matched []*runtimeProtocol
+	for _, p := range s.protocols {
+		if strings.EqualFold(p.status.Type, protocolType) && (port == 0 || p.status.Port == port) {
+			matched = append(matched, p)
+		}
+	}
+	switch {
+	case len(matched) == 0:
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound,
+			fmt.Sprintf("protocol %s is not configured", protocolLabel(protocolType, port)))
+	case len(matched) > 1:
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+			fmt.Sprintf("protocol %s is configured on multiple ports, port is required", protocolType))
+	}
+
+	p := matched[0]
+	if p.status.Enabled == enabled {
+		status := p.status
+		return &status, nil
+	}
+	if err := p.setEnabled(ctx, enabled); err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.InternalError,
+			fmt.Sprintf("failed to switch protocol %s", protocolLabel(p.status.Type, p.status.Port)))
+	}
+
+	message := "Protocol disabled"
+	if enabled {
+		message = "Protocol enabled"
+	}
+	s.observability.Logger().Info(ctx, message,
+		observability.Field{Key: "protocol", Value: p.status.Type},
+		observability.Field{Key: "port", Value: p.status.Port})
+	status := p.status
+	return &status, nil
+}
+
+// protocolLabel 返回日志和错误中的协议名称，如 REST:8080
+func protocolLabel(protocolType string, port int) string {
+	if port == 0 {
+		return protocolType
+	}
+	return fmt.Sprintf("%s:%d", protocolType, port)
+}
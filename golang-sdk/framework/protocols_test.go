@@ -0,0 +1,87 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestServerProtocolSwitch(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:          reg,
+		AdminAuthenticate: func(r *http.Request, operation string) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+
+	// 未启动时不能切换
+	if _, err := server.SetProtocolEnabled(context.Background(), "REST", 0, false); err == nil {
+		t.Error("Expected error before the server starts")
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	time.Sleep(100 * time.Millisecond)
+
+	admin := func(path, body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Admin().ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+	restStatus := func() int {
+		resp, err := http.Get("http://127.0.0.1:18401/api/greeter/hello")
+		if err != nil {
+			t.Fatalf("REST request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	call := func() error {
+		var greeting string
+		return server.Client().Call(context.Background(), "greeter-service", "greeter.hello", nil, &greeting)
+	}
+
+	if code, body := admin("/admin/protocols.disable", `{"type":"rest"}`); code != http.StatusOK || !strings.Contains(body, `"type":"REST"`) || !strings.Contains(body, `"enabled":false`) {
+		t.Fatalf("protocols.disable = %d %s", code, body)
+	}
+	// 停用的协议拒绝新请求，同端口的 JSON-RPC 不受影响
+	if code := restStatus(); code != http.StatusServiceUnavailable {
+		t.Errorf("REST status after disabling = %d, want 503", code)
+	}
+	if err := call(); err != nil {
+		t.Errorf("JSON-RPC call on the shared port failed: %v", err)
+	}
+	for _, p := range server.Protocols() {
+		if p.Enabled != (p.Type != protocolREST) {
+			t.Errorf("Unexpected protocol status %+v", p)
+		}
+	}
+
+	if code, body := admin("/admin/protocols.enable", `{"type":"REST","port":18401}`); code != http.StatusOK || !strings.Contains(body, `"enabled":true`) {
+		t.Fatalf("protocols.enable = %d %s", code, body)
+	}
+	if code := restStatus(); code == http.StatusServiceUnavailable {
+		t.Errorf("REST status after enabling = %d", code)
+	}
+
+	if code, body := admin("/admin/protocols.disable", `{"type":"MQTT"}`); code != http.StatusNotFound {
+		t.Errorf("disable unconfigured protocol = %d %s, want 404", code, body)
+	}
+	if code, body := admin("/admin/protocols.disable", `{}`); code != http.StatusBadRequest {
+		t.Errorf("disable without type = %d %s, want 400", code, body)
+	}
+}
@@ -0,0 +1,210 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/framework/golang-sdk/config"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+)
+
+// ReloadAction 配置项变更的生效方式
+type ReloadAction string
+
+const (
+	// ReloadInPlace 在运行中的组件上直接修改，不中断请求（如日志级别、负载日志）
+	ReloadInPlace ReloadAction = "in-place"
+	// ReloadRestartHandler 只重启受影响的协议处理器，其他协议不受影响（如外部协议的 enabled）
+	ReloadRestartHandler ReloadAction = "restart-handler"
+	// ReloadRestartProcess 须重启进程才能生效（如监听端口、注册中心），热加载时只报告
+	ReloadRestartProcess ReloadAction = "restart-process"
+)
+
+// externalEnabledKey 外部协议的 enabled 配置项，如 framework.protocols.external[1].enabled
+var externalEnabledKey = regexp.MustCompile(`^framework\.protocols\.external\[(\d+)\]\.enabled$`)
+
+// ReloadChange 热加载的一个配置项变更及其生效结果
+type ReloadChange struct {
+	config.ConfigChange
+	Action  ReloadAction `json:"action"`
+	Applied bool         `json:"applied"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// ReloadResult 一次热加载的结果
+type ReloadResult struct {
+	// Changes 与上次加载的配置相比变更的配置项
+	Changes []ReloadChange `json:"changes"`
+	// Pending 与启动时的配置相比变更、须重启进程才能生效的配置项
+	Pending []config.ConfigChange `json:"pending,omitempty"`
+}
+
+// ReloadConfig 重新读取配置文件，只应用变更的配置项，并将差异以 config.reloaded 事件发布到事件总线
+//
+// 日志级别和负载日志在运行中修改；外部协议的 enabled 只启用或停用对应的协议处理器（须为启动时启用的协议，
+// 见 SetProtocolEnabled）；其他配置项须重启进程才能生效，列在 ReloadResult.Pending 中直到进程重启。
+// 新配置无法加载或验证失败时返回错误，不应用任何变更
+func (s *Server) ReloadConfig(ctx context.Context) (*ReloadResult, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cm, err := config.NewConfigManagerWithOptions(s.configPath, s.options.Config)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to reload config")
+	}
+	cfg, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to reload config")
+	}
+	base := s.reloadBase
+	if base == nil {
+		base = s.config
+	}
+	changes, err := config.DiffFrameworkConfig(base, cfg)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.InternalError, "failed to diff config")
+	}
+	sinceStart, err := config.DiffFrameworkConfig(s.config, cfg)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.InternalError, "failed to diff config")
+	}
+
+	result := &ReloadResult{Changes: make([]ReloadChange, 0, len(changes))}
+	for _, change := range changes {
+		result.Changes = append(result.Changes, s.applyChange(ctx, cfg, change))
+	}
+	for _, change := range sinceStart {
+		if s.reloadAction(cfg, change) == ReloadRestartProcess {
+			result.Pending = append(result.Pending, change)
+		}
+	}
+	s.reloadBase = cfg
+
+	if len(result.Changes) > 0 {
+		s.publishReload(ctx, result)
+	}
+	return result, nil
+}
+
+// applyChange 按生效方式应用一个配置项变更，须重启进程的变更不应用
+func (s *Server) applyChange(ctx context.Context, cfg *config.FrameworkConfig, change config.ConfigChange) ReloadChange {
+	result := ReloadChange{ConfigChange: change, Action: s.reloadAction(cfg, change)}
+	var err error
+	switch {
+	case result.Action == ReloadRestartProcess:
+		return result
+	case change.Key == "framework.observability.logging.level":
+		s.observability.SetLogLevel(observability.LogLevel(cfg.Observability.Logging.Level))
+	case change.Key == "framework.payloadLog.enabled":
+		s.payloadLog.SetEnabled(cfg.PayloadLog.Enabled)
+	case change.Key == "framework.payloadLog.perMinute":
+		s.payloadLog.SetPerMinute(cfg.PayloadLog.PerMinute)
+	default:
+		protocol := cfg.Protocols.External[externalIndex(change.Key)]
+		_, err = s.SetProtocolEnabled(ctx, protocol.Type, protocol.Port, protocol.Enabled)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Applied = true
+	}
+	return result
+}
+
+// reloadAction 返回配置项变更的生效方式；协议增删或启动时未启用的协议须重启进程
+func (s *Server) reloadAction(cfg *config.FrameworkConfig, change config.ConfigChange) ReloadAction {
+	switch change.Key {
+	case "framework.observability.logging.level":
+		return ReloadInPlace
+	case "framework.payloadLog.enabled", "framework.payloadLog.perMinute":
+		if s.payloadLog != nil {
+			return ReloadInPlace
+		}
+		return ReloadRestartProcess
+	}
+	if !externalEnabledKey.MatchString(change.Key) || change.Old == nil || change.New == nil {
+		return ReloadRestartProcess
+	}
+	protocol := cfg.Protocols.External[externalIndex(change.Key)]
+	if !s.hasRuntimeProtocol(protocol.Type, protocol.Port) {
+		return ReloadRestartProcess
+	}
+	return ReloadRestartHandler
+}
+
+// hasRuntimeProtocol 启动时启用、可在运行时切换的外部协议中是否有 protocolType（port 为 0 时不比较端口）
+func (s *Server) hasRuntimeProtocol(protocolType string, port int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.protocols {
+		if strings.EqualFold(p.status.Type, protocolType) && (port == 0 || p.status.Port == port) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalIndex 返回外部协议 enabled 配置项中的下标
+func externalIndex(key string) int {
+	index, _ := strconv.Atoi(externalEnabledKey.FindStringSubmatch(key)[1])
+	return index
+}
+
+// publishReload 记录并发布热加载的结果，属性为逗号分隔的配置项：applied 已生效、failed 应用失败、restart_required 须重启进程
+func (s *Server) publishReload(ctx context.Context, result *ReloadResult) {
+	var applied, failed, restart []string
+	for _, change := range result.Changes {
+		switch {
+		case change.Applied:
+			applied = append(applied, change.Key)
+		case change.Error != "":
+			failed = append(failed, change.Key)
+		}
+	}
+	for _, change := range result.Pending {
+		restart = append(restart, change.Key)
+	}
+
+	s.observability.Logger().Info(ctx, "Config reloaded",
+		observability.Field{Key: "applied", Value: strings.Join(applied, ",")},
+		observability.Field{Key: "failed", Value: strings.Join(failed, ",")},
+		observability.Field{Key: "restart_required", Value: strings.Join(restart, ",")})
+	s.observability.Events().Publish(observability.Event{
+		Type:   observability.EventConfigReloaded,
+		Source: "framework",
+		Attributes: map[string]string{
+			"path":             s.configPath,
+			"applied":          strings.Join(applied, ","),
+			"failed":           strings.Join(failed, ","),
+			"restart_required": strings.Join(restart, ","),
+		},
+	})
+}
+
+// watchReloadSignal 收到 Options.ReloadSignal 时热加载配置，服务关闭时停止
+func (s *Server) watchReloadSignal() {
+	if s.options.ReloadSignal == nil {
+		return
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, s.options.ReloadSignal)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-signals:
+				if _, err := s.ReloadConfig(context.Background()); err != nil {
+					s.observability.Logger().Error(context.Background(), "Config reload failed",
+						observability.Field{Key: "error", Value: err.Error()})
+				}
+			case <-s.lifecycle.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestServerReloadConfig(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	path := writeTestConfig(t, testConfig)
+	server, err := NewServerWithOptions(path, &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	events := make(chan observability.Event, 4)
+	unsubscribe := server.observability.Events().Subscribe(func(ctx context.Context, event observability.Event) {
+		events <- event
+	}, observability.EventConfigReloaded)
+	defer unsubscribe()
+
+	// 日志级别在运行中修改，REST 只停用处理器，内部协议端口须重启进程
+	updated := strings.Replace(testConfig, "level: error", "level: debug", 1)
+	updated = strings.Replace(updated, "enabled: true\n        port: 18401\n        path: /api", "enabled: false\n        port: 18401\n        path: /api", 1)
+	updated = strings.Replace(updated, "port: 18402", "port: 18403", 1)
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	result, err := server.ReloadConfig(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	changes := make(map[string]ReloadChange)
+	for _, change := range result.Changes {
+		changes[change.Key] = change
+	}
+	if change := changes["framework.observability.logging.level"]; change.Action != ReloadInPlace || !change.Applied {
+		t.Errorf("Unexpected logging level change: %+v", change)
+	}
+	if change := changes["framework.protocols.external[1].enabled"]; change.Action != ReloadRestartHandler || !change.Applied {
+		t.Errorf("Unexpected REST change: %+v", change)
+	}
+	if change := changes["framework.protocols.internal[0].port"]; change.Action != ReloadRestartProcess || change.Applied {
+		t.Errorf("Unexpected internal port change: %+v", change)
+	}
+	if len(result.Changes) != 3 || len(result.Pending) != 1 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	for _, status := range server.Protocols() {
+		if status.Type == "REST" && status.Enabled {
+			t.Error("Expected REST disabled after reload")
+		}
+	}
+
+	select {
+	case event := <-events:
+		if !strings.Contains(event.Attributes["applied"], "framework.observability.logging.level") ||
+			event.Attributes["restart_required"] != "framework.protocols.internal[0].port" {
+			t.Errorf("Unexpected event attributes: %v", event.Attributes)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for config.reloaded event")
+	}
+
+	// 没有新的变更时不再应用，须重启进程的变更仍然列出
+	result, err = server.ReloadConfig(context.Background())
+	if err != nil || len(result.Changes) != 0 || len(result.Pending) != 1 {
+		t.Fatalf("Unexpected second reload: %+v, %v", result, err)
+	}
+
+	// 新配置验证失败时不应用任何变更
+	os.WriteFile(path, []byte(strings.Replace(updated, "level: debug", "level: verbose", 1)), 0o644)
+	_, err = server.ReloadConfig(context.Background())
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Errorf("Expected BadRequest for invalid config, got %v", err)
+	}
+}
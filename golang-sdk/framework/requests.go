@@ -0,0 +1,134 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/idgen"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ActiveRequest 处理中的请求
+type ActiveRequest struct {
+	// ID 请求 ID，经协议适配器转换的请求为其 request_id 元数据，否则由 ID 生成器生成
+	ID      string `json:"id"`
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	// Caller 认证后的调用方（用户 ID），未认证时为调用方地址，都没有时为空
+	Caller   string        `json:"caller,omitempty"`
+	Protocol string        `json:"protocol,omitempty"`
+	TraceID  string        `json:"traceId,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+}
+
+// activeRequest 处理中的请求及其取消函数
+type activeRequest struct {
+	info   ActiveRequest
+	cancel context.CancelCauseFunc
+}
+
+// requestTracker 处理中请求的并发登记表，供管理接口列出和取消请求
+type requestTracker struct {
+	mu       sync.Mutex
+	requests map[string]*activeRequest
+}
+
+// newRequestTracker 创建处理中请求的登记表
+func newRequestTracker() *requestTracker {
+	return &requestTracker{requests: make(map[string]*activeRequest)}
+}
+
+// internalRequestKey context 中经协议适配器转换的请求的键
+type internalRequestKey struct{}
+
+// withInternalRequest 将经协议适配器转换的请求写入 context，登记时据此读取请求 ID、调用方地址和协议
+func withInternalRequest(ctx context.Context, request *adapter.InternalRequest) context.Context {
+	return context.WithValue(ctx, internalRequestKey{}, request)
+}
+
+// start 登记请求，返回可由 cancel 取消的 context 和请求结束时调用的函数
+func (t *requestTracker) start(ctx context.Context, method string) (context.Context, func()) {
+	info := ActiveRequest{Method: method, Started: time.Now()}
+	if i := strings.LastIndex(method, "."); i >= 0 {
+		info.Service, info.Method = method[:i], method[i+1:]
+	}
+	if request, ok := ctx.Value(internalRequestKey{}).(*adapter.InternalRequest); ok {
+		info.ID = request.Metadata["request_id"]
+		info.Caller = request.Metadata["client_addr"]
+		info.Protocol = request.Metadata["original_protocol"]
+	}
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil && sc.UserID != "" {
+		info.Caller = sc.UserID
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		info.TraceID = spanContext.TraceID().String()
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	t.mu.Lock()
+	// 调用方提供的请求 ID 可能重复，重复时另行生成
+	if info.ID == "" || t.requests[info.ID] != nil {
+		info.ID = idgen.RequestID()
+	}
+	t.requests[info.ID] = &activeRequest{info: info, cancel: cancel}
+	t.mu.Unlock()
+
+	return ctx, func() {
+		t.mu.Lock()
+		delete(t.requests, info.ID)
+		t.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// list 返回处理中的请求，按开始时间排序，最早的在前
+func (t *requestTracker) list() []ActiveRequest {
+	now := time.Now()
+	t.mu.Lock()
+	requests := make([]ActiveRequest, 0, len(t.requests))
+	for _, request := range t.requests {
+		info := request.info
+		info.Duration = now.Sub(info.Started)
+		requests = append(requests, info)
+	}
+	t.mu.Unlock()
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Started.Before(requests[j].Started) })
+	return requests
+}
+
+// cancel 取消请求，请求的 context 以 ClientClosedRequest 错误取消，经 Client() 发出的下游调用随之取消
+func (t *requestTracker) cancel(id, reason string) (ActiveRequest, error) {
+	t.mu.Lock()
+	request, ok := t.requests[id]
+	t.mu.Unlock()
+	if !ok {
+		return ActiveRequest{}, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("request %s not found", id))
+	}
+	message := "request cancelled by operator"
+	if reason != "" {
+		message += ": " + reason
+	}
+	request.cancel(frameworkerrors.NewFrameworkError(frameworkerrors.ClientClosedRequest, message))
+	info := request.info
+	info.Duration = time.Since(info.Started)
+	return info, nil
+}
+
+// ActiveRequests 返回处理中的请求，按开始时间排序
+func (s *Server) ActiveRequests() []ActiveRequest {
+	return s.requests.list()
+}
+
+// CancelRequest 取消处理中的请求，用于停止失控的调用：请求的 context 被取消，
+// context.Cause 为 ClientClosedRequest 错误，经 Client() 发出的下游调用随之取消；
+// 业务方法须检查 ctx 才能提前返回。请求不存在（可能已结束）时返回 NotFound 错误
+func (s *Server) CancelRequest(id, reason string) (ActiveRequest, error) {
+	return s.requests.cancel(id, reason)
+}
@@ -0,0 +1,85 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/registry"
+)
+
+// TestCancelRequest 测试登记处理中的请求，经管理接口列出并取消，取消传播到业务方法的 context
+func TestCancelRequest(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:          reg,
+		AdminAuthenticate: func(r *http.Request, operation string) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	started := make(chan struct{})
+	server.Handle("report.generate", func(ctx context.Context, params interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	call := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		server.Admin().ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := server.dispatch(context.Background(), &adapter.InternalRequest{
+			Service:  "report",
+			Method:   "generate",
+			Metadata: map[string]string{"request_id": "req-1", "client_addr": "10.0.0.8:5123", "original_protocol": "REST"},
+		})
+		result <- err
+	}()
+	<-started
+
+	requests := server.ActiveRequests()
+	if len(requests) != 1 || requests[0].ID != "req-1" || requests[0].Service != "report" || requests[0].Method != "generate" ||
+		requests[0].Caller != "10.0.0.8:5123" || requests[0].Protocol != "REST" {
+		t.Fatalf("Unexpected active requests: %+v", requests)
+	}
+	if code, body := call(http.MethodGet, "/admin/requests", ""); code != http.StatusOK || !strings.Contains(body, `"id":"req-1"`) {
+		t.Errorf("requests = %d %s", code, body)
+	}
+	if code, _ := call(http.MethodPost, "/admin/requests.cancel", `{"id":"req-unknown"}`); code != http.StatusNotFound {
+		t.Errorf("requests.cancel for unknown request = %d, want 404", code)
+	}
+	if code, body := call(http.MethodPost, "/admin/requests.cancel", `{"id":"req-1","reason":"runaway"}`); code != http.StatusOK {
+		t.Errorf("requests.cancel = %d %s", code, body)
+	}
+
+	select {
+	case err := <-result:
+		var fe *frameworkerrors.FrameworkError
+		if !errors.As(err, &fe) || fe.Code != frameworkerrors.ClientClosedRequest || !strings.Contains(fe.Message, "runaway") {
+			t.Errorf("Expected ClientClosedRequest, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected cancelled request to return")
+	}
+	if requests := server.ActiveRequests(); len(requests) != 0 {
+		t.Errorf("Expected no active requests, got %+v", requests)
+	}
+}
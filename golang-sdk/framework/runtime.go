@@ -0,0 +1,164 @@
+package framework
+
+import (
+	"context"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/connection"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// 按可用 CPU 数调整默认值时每个 CPU 分配的数量，结果不超过各自的默认值
+const (
+	// poolConnectionsPerCPU 未配置 framework.connectionPool.maxConnections 时每个 CPU 的连接数
+	poolConnectionsPerCPU = 25
+	// acceptQueueConcurrencyPerCPU 未配置 framework.acceptQueue.maxConcurrent 时每个 CPU 同时处理的请求数
+	acceptQueueConcurrencyPerCPU = 50
+	// minTunedConcurrency 按 CPU 数调整后的下限
+	minTunedConcurrency = 10
+)
+
+// StartupDiagnostics 启动诊断记录，Start 时以 "Startup diagnostics" 日志输出，也可通过管理接口 diagnostics 查询
+type StartupDiagnostics struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	PID       int    `json:"pid"`
+	// Limits 检测到的 CPU 和内存限制
+	Limits *lifecycle.ResourceLimits `json:"limits"`
+	// Runtime 调整后的 Go 运行时参数，设置了 Options.DisableRuntimeTuning 时为 nil
+	Runtime *lifecycle.RuntimeTuning `json:"runtime,omitempty"`
+	// ConnectionPoolSize 生效的连接池最大连接数
+	ConnectionPoolSize int `json:"connectionPoolSize"`
+	// AcceptQueueConcurrency 每个协议处理器同时处理的请求数上限，未启用接收队列时为 0
+	AcceptQueueConcurrency int `json:"acceptQueueConcurrency,omitempty"`
+	// ConfigSources 提供配置值的来源及各自提供的配置项数
+	ConfigSources []DiagnosticsConfigSource `json:"configSources"`
+	// Listeners 本地监听的端口
+	Listeners []DiagnosticsListener `json:"listeners"`
+}
+
+// DiagnosticsConfigSource 配置来源
+type DiagnosticsConfigSource struct {
+	Source config.ConfigSource `json:"source"`
+	Origin string              `json:"origin"` // 配置文件路径、环境变量名或命令行参数名
+	Keys   int                 `json:"keys"`
+}
+
+// DiagnosticsListener 监听端口
+type DiagnosticsListener struct {
+	Protocol string `json:"protocol"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port"`
+	Internal bool   `json:"internal,omitempty"`
+	TLS      bool   `json:"tls"`
+}
+
+// tuneRuntime 检测容器的 CPU 和内存限制，按其调整 GOMAXPROCS、GOMEMLIMIT 以及未配置的连接池和接收队列大小
+//
+// 设置了 Options.DisableRuntimeTuning 时只检测限制，用于启动诊断
+func (s *Server) tuneRuntime() {
+	s.limits = lifecycle.DetectResourceLimits()
+	if s.options.DisableRuntimeTuning {
+		return
+	}
+	s.tuning = lifecycle.TuneRuntime(s.limits)
+
+	pool := &s.config.ConnectionPool
+	if pool.MaxConnections == 0 {
+		pool.MaxConnections = max(tunedSize(s.limits.CPUs, poolConnectionsPerCPU, connection.DefaultConnectionConfig().MaxConnections),
+			pool.MinConnections)
+	}
+	if queue := &s.config.AcceptQueue; queue.Enabled && queue.MaxConcurrent == 0 {
+		queue.MaxConcurrent = tunedSize(s.limits.CPUs, acceptQueueConcurrencyPerCPU, resilience.DefaultAcceptQueueConcurrency)
+	}
+}
+
+// tunedSize 返回 cpus × perCPU，不小于 minTunedConcurrency、不大于 def
+func tunedSize(cpus float64, perCPU, def int) int {
+	size := int(math.Ceil(cpus * float64(perCPU)))
+	return min(max(size, minTunedConcurrency), def)
+}
+
+// Diagnostics 返回启动诊断记录
+func (s *Server) Diagnostics() *StartupDiagnostics {
+	diagnostics := &StartupDiagnostics{
+		Service:            s.config.Name,
+		Version:            s.config.Version,
+		GoVersion:          runtime.Version(),
+		PID:                os.Getpid(),
+		Limits:             s.limits,
+		Runtime:            s.tuning,
+		ConnectionPoolSize: connectionConfig(s.config.ConnectionPool).MaxConnections,
+		ConfigSources:      s.configSources(),
+		Listeners:          make([]DiagnosticsListener, 0),
+	}
+	if s.config.AcceptQueue.Enabled {
+		diagnostics.AcceptQueueConcurrency = s.config.AcceptQueue.MaxConcurrent
+		if diagnostics.AcceptQueueConcurrency == 0 {
+			diagnostics.AcceptQueueConcurrency = resilience.DefaultAcceptQueueConcurrency
+		}
+	}
+	for _, l := range listeners(s.config) {
+		diagnostics.Listeners = append(diagnostics.Listeners, DiagnosticsListener{
+			Protocol: l.protocol,
+			Host:     l.host,
+			Port:     l.port,
+			Internal: l.internal,
+			TLS:      s.listenerTLS(l),
+		})
+	}
+	return diagnostics
+}
+
+// listenerTLS 判断监听端口是否启用 TLS：设置了 Options.MTLS 时指标以外的端口都启用，
+// framework.security.tls 只用于内部 gRPC，见 listenerWarnings
+func (s *Server) listenerTLS(l listener) bool {
+	if l.owner == "metrics" {
+		return false
+	}
+	if s.options.MTLS != nil {
+		return true
+	}
+	return l.internal && strings.EqualFold(l.protocol, protocolGRPC) && s.config.Security.TLS.Enabled
+}
+
+// configSources 按来源汇总生效配置，读取失败时返回 nil
+func (s *Server) configSources() []DiagnosticsConfigSource {
+	effective, err := s.configManager.EffectiveConfig("")
+	if err != nil {
+		return nil
+	}
+	type sourceKey struct {
+		source config.ConfigSource
+		origin string
+	}
+	counts := make(map[sourceKey]int)
+	for _, entry := range effective.Entries {
+		counts[sourceKey{entry.Source, entry.Origin}]++
+	}
+	sources := make([]DiagnosticsConfigSource, 0, len(counts))
+	for key, count := range counts {
+		sources = append(sources, DiagnosticsConfigSource{Source: key.source, Origin: key.origin, Keys: count})
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Source != sources[j].Source {
+			return sources[i].Source < sources[j].Source
+		}
+		return sources[i].Origin < sources[j].Origin
+	})
+	return sources
+}
+
+// logDiagnostics 以一条结构化日志输出启动诊断记录
+func (s *Server) logDiagnostics() {
+	s.observability.Logger().Info(context.Background(), "Startup diagnostics",
+		observability.Field{Key: "diagnostics", Value: s.Diagnostics()})
+}
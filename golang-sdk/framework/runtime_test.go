@@ -0,0 +1,94 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/framework/golang-sdk/config"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func TestTunedSize(t *testing.T) {
+	tests := []struct {
+		cpus float64
+		want int
+	}{
+		{0.25, 10}, // 不小于下限
+		{1, 25},
+		{1.5, 38},
+		{16, 100}, // 不超过默认值
+	}
+	for _, tt := range tests {
+		if got := tunedSize(tt.cpus, poolConnectionsPerCPU, 100); got != tt.want {
+			t.Errorf("tunedSize(%v) = %d, want %d", tt.cpus, got, tt.want)
+		}
+	}
+}
+
+func TestServerDiagnostics(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	previous := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(previous)
+
+	// 未配置连接池大小时按可用 CPU 数调整
+	content := strings.Replace(testConfig, "  connectionPool:\n    maxConnections: 10\n", "", 1)
+	server, err := NewServerWithOptions(writeTestConfig(t, content), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	diagnostics := server.Diagnostics()
+	if diagnostics.Service != "greeter-service" || diagnostics.Limits == nil || diagnostics.Runtime == nil {
+		t.Fatalf("Unexpected diagnostics: %+v", diagnostics)
+	}
+	if want := tunedSize(diagnostics.Limits.CPUs, poolConnectionsPerCPU, 100); diagnostics.ConnectionPoolSize != want {
+		t.Errorf("ConnectionPoolSize = %d, want %d", diagnostics.ConnectionPoolSize, want)
+	}
+	if diagnostics.Runtime.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("Runtime.GOMAXPROCS = %d, want %d", diagnostics.Runtime.GOMAXPROCS, runtime.GOMAXPROCS(0))
+	}
+
+	ports := make(map[string]int)
+	for _, l := range diagnostics.Listeners {
+		ports[l.Protocol] = l.Port
+		if l.TLS {
+			t.Errorf("Expected plaintext listener, got %+v", l)
+		}
+	}
+	if ports["JSON-RPC"] == 0 || ports["REST"] != 18401 {
+		t.Errorf("Unexpected listeners: %+v", diagnostics.Listeners)
+	}
+	if len(diagnostics.ConfigSources) != 1 || diagnostics.ConfigSources[0].Source != config.SourceFile || diagnostics.ConfigSources[0].Keys == 0 {
+		t.Errorf("Unexpected config sources: %+v", diagnostics.ConfigSources)
+	}
+
+	// 记录可序列化为一条 JSON 日志
+	if _, err := json.Marshal(diagnostics); err != nil {
+		t.Errorf("Failed to marshal diagnostics: %v", err)
+	}
+}
+
+func TestServerDiagnosticsWithoutTuning(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{Registry: reg, DisableRuntimeTuning: true})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	diagnostics := server.Diagnostics()
+	if diagnostics.Runtime != nil || diagnostics.Limits == nil {
+		t.Errorf("Expected limits without runtime tuning, got %+v", diagnostics)
+	}
+	if diagnostics.ConnectionPoolSize != 10 {
+		t.Errorf("ConnectionPoolSize = %d, want configured 10", diagnostics.ConnectionPoolSize)
+	}
+}
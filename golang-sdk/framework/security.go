@@ -0,0 +1,112 @@
+package framework
+
+import (
+	"context"
+	"strings"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// 认证请求头
+const (
+	HeaderAuthorization = "Authorization"
+	HeaderAPIKey        = "X-API-Key"
+)
+
+// authenticate 按 framework.security 认证外部请求并授权调用 method，返回携带调用方安全上下文的 context
+//
+// authentication.type 为 jwt 时读取 Authorization: Bearer 令牌，为 apikey 时读取 X-API-Key；
+// authorization 启用时以服务名为资源、方法名为操作进行 RBAC 检查。未启用认证时信任上游服务传递的安全上下文
+func (s *Server) authenticate(ctx context.Context, headers map[string]string, method string) (context.Context, error) {
+	auth := s.config.Security.Authentication
+	if !auth.Enabled {
+		sc := adapter.SecurityContextFromHeaders(headers)
+		if s.config.Security.Authorization.Enabled {
+			var roles []string
+			if sc != nil {
+				roles = sc.Roles
+			}
+			if err := s.security.Authorize(roles, s.config.Name, method); err != nil {
+				return nil, frameworkerrors.Wrap(err, frameworkerrors.Forbidden, "access denied")
+			}
+		}
+		if sc != nil {
+			ctx = adapter.WithSecurityContext(ctx, sc)
+		}
+		return ctx, nil
+	}
+
+	var sc *adapter.SecurityContext
+	switch strings.ToLower(auth.Type) {
+	case adapter.AuthMethodAPIKey:
+		key := header(headers, HeaderAPIKey)
+		if key == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.Unauthorized, "missing API key")
+		}
+		apiKey, err := s.security.AuthenticateAPIKey(key)
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.Unauthorized, "invalid API key")
+		}
+		if err := s.security.AuthorizeAPIKey(apiKey, s.config.Name, method); err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.Forbidden, "access denied")
+		}
+		sc = &adapter.SecurityContext{
+			UserID:     apiKey.UserID,
+			Roles:      apiKey.Roles,
+			TenantID:   apiKey.TenantID,
+			AuthMethod: adapter.AuthMethodAPIKey,
+		}
+	default:
+		token, ok := bearerToken(header(headers, HeaderAuthorization))
+		if !ok {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.Unauthorized, "missing bearer token")
+		}
+		claims, err := s.security.AuthenticateJWT(token)
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.Unauthorized, "invalid token")
+		}
+		if s.config.Security.Authorization.Enabled {
+			if err := s.security.Authorize(claims.Roles, s.config.Name, method); err != nil {
+				return nil, frameworkerrors.Wrap(err, frameworkerrors.Forbidden, "access denied")
+			}
+		}
+		sc = &adapter.SecurityContext{
+			UserID:     claims.UserID,
+			Roles:      claims.Roles,
+			TenantID:   claims.TenantID,
+			AuthMethod: adapter.AuthMethodJWT,
+		}
+	}
+
+	return adapter.WithSecurityContext(ctx, sc), nil
+}
+
+// subscribeOperation WebSocket 订阅主题时 RBAC 检查的操作名
+const subscribeOperation = "subscribe"
+
+// authorizeSubscribe 以 WebSocket 握手请求头认证订阅方，并以服务名为资源、subscribe 为操作进行 RBAC 检查
+func (s *Server) authorizeSubscribe(ctx context.Context, headers map[string]string, topic string) error {
+	_, err := s.authenticate(ctx, headers, subscribeOperation)
+	return err
+}
+
+// header 按名称查找请求头，名称大小写不敏感
+func header(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// bearerToken 解析 Authorization 请求头中的 Bearer 令牌
+func bearerToken(value string) (string, bool) {
+	const prefix = "bearer "
+	if len(value) <= len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(value[len(prefix):])
+	return token, token != ""
+}
@@ -0,0 +1,76 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/serializer"
+)
+
+// SelfTestPath 最近一次自检报告在指标服务器上的路径，尚未执行或有检查失败时返回 503
+const SelfTestPath = "/health/selftest"
+
+// newSelfTest 创建启动自检，未启用 framework.selfTest 且没有 Options.SelfTests 时返回 nil
+//
+// 启用 framework.selfTest 时注册内置检查：注册中心连通性、framework.services 中各服务可被发现、
+// 服务实例声明的 JSON 和 XML 序列化可以往返编解码、framework.security.tls 的证书有效；Options.SelfTests 排在其后
+func (s *Server) newSelfTest() *observability.SelfTest {
+	cfg := &s.config.SelfTest
+	if !cfg.Enabled && len(s.options.SelfTests) == 0 {
+		return nil
+	}
+	selfTest := observability.NewSelfTest(s.observability.Logger(), cfg.Timeout)
+	if cfg.Enabled {
+		selfTest.Register(registry.NewConnectivityCheck(s.registry))
+
+		services := make([]string, 0, len(s.config.Services))
+		for name := range s.config.Services {
+			services = append(services, name)
+		}
+		sort.Strings(services)
+		for _, name := range services {
+			selfTest.Register(registry.NewDiscoveryCheck(s.registry, name))
+		}
+
+		selfTest.Register(serializer.NewRoundTripCheck(serializer.NewJsonSerializer(), nil))
+		for _, format := range s.service.Serializations {
+			if serializer.SerializationFormat(format) == serializer.XML {
+				if xml, err := serializer.NewXmlSerializer(serializer.DefaultXmlConfig()); err == nil {
+					selfTest.Register(serializer.NewRoundTripCheck(xml, nil))
+				}
+			}
+		}
+
+		if tls := s.config.Security.TLS; tls.Enabled && tls.CertFile != "" {
+			selfTest.Register(observability.NewCertificateCheck(tls.CertFile, tls.KeyFile, cfg.CertMinValidity))
+		}
+	}
+	selfTest.Register(s.options.SelfTests...)
+	return selfTest
+}
+
+// runSelfTest 执行自检，有检查失败时返回列出失败检查的错误
+func (s *Server) runSelfTest(ctx context.Context) error {
+	report := s.selfTest.Run(ctx)
+	if report.Passed {
+		return nil
+	}
+	failed := report.Failed()
+	messages := make([]string, len(failed))
+	for i, result := range failed {
+		messages[i] = result.Name + ": " + result.Error
+	}
+	return fmt.Errorf("self-test failed: %s", strings.Join(messages, "; "))
+}
+
+// SelfTestReport 返回最近一次自检的报告，未启用自检或尚未执行时返回 nil
+func (s *Server) SelfTestReport() *observability.SelfTestReport {
+	if s.selfTest == nil {
+		return nil
+	}
+	return s.selfTest.Report()
+}
@@ -0,0 +1,906 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/accounting"
+	"github.com/framework/golang-sdk/admin"
+	"github.com/framework/golang-sdk/client"
+	"github.com/framework/golang-sdk/config"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/idgen"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/protocol/capture"
+	"github.com/framework/golang-sdk/protocol/dedup"
+	externaljsonrpc "github.com/framework/golang-sdk/protocol/external/jsonrpc"
+	"github.com/framework/golang-sdk/protocol/external/kafka"
+	"github.com/framework/golang-sdk/protocol/external/websocket"
+	"github.com/framework/golang-sdk/protocol/transport"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+	"github.com/framework/golang-sdk/session"
+)
+
+// DefaultShutdownTimeout Run 收到退出信号后等待请求处理完成的默认时间
+const DefaultShutdownTimeout = lifecycle.DefaultTimeout
+
+// Handler 业务方法处理器，params 为 JSON 解码后的请求参数
+type Handler func(ctx context.Context, params interface{}) (interface{}, error)
+
+// Options 服务启动选项
+type Options struct {
+	// Config 配置加载选项（环境配置文件、命令行覆盖等），为 nil 时使用默认选项
+	Config *config.Options
+	// Registry 服务注册中心，为 nil 时按 framework.registry 创建；传入的注册中心不随服务关闭
+	Registry registry.ServiceRegistry
+	// Security JWT、API 密钥和 RBAC 配置，framework.security.authentication 启用时必须提供
+	Security *security.SecurityConfig
+	// AdvertiseAddress 注册到注册中心的服务地址，为空时 network.host 为通配地址则使用本机非回环 IPv4 地址
+	AdvertiseAddress string
+	// ShutdownTimeout Run 收到退出信号后的关闭超时，为 0 时使用 DefaultShutdownTimeout
+	ShutdownTimeout time.Duration
+	// DeregisterDelay 关闭时从注册中心注销后继续接受请求的时间，使调用方在停止接受请求前刷新服务实例列表
+	DeregisterDelay time.Duration
+	// UpgradeSignal Run 收到后热重启的信号（如 syscall.SIGUSR2），为 nil 时不支持热重启，见 lifecycle.Upgrade
+	UpgradeSignal os.Signal
+	// ReloadSignal 收到后热加载配置文件的信号（如 syscall.SIGHUP），为 nil 时只能通过 ReloadConfig 或管理接口的 config.reload 热加载
+	ReloadSignal os.Signal
+	// AdminAuthenticate 认证管理接口请求，为 nil 时按 framework.security 认证，见 AdminPath
+	AdminAuthenticate func(r *http.Request, operation string) error
+	// KafkaDialer Kafka 客户端，启用 Kafka 协议时必须提供，框架不内置 Kafka 客户端库
+	KafkaDialer kafka.Dialer
+	// Broker 消息中间件，启用 MQ 协议或 framework.services 中 protocol 为 MQ 时必须提供
+	Broker messaging.Broker
+	// Dedup WebSocket、MQTT 和 Kafka 共用的已处理幂等键存储，为 nil 时使用进程内的 dedup.Cache；
+	// 多实例部署时传入共享存储才能跨实例去重
+	Dedup dedup.Store
+	// HubBroker WebSocket 主题广播的消息中间件（如 messaging.RedisPubSubBroker），为 nil 时只广播到本实例的连接；
+	// 多实例部署时传入才能将广播送达所有实例上的订阅方，见 Hub
+	HubBroker messaging.Broker
+	// Dependencies Start 启动协议处理器前按顺序等待就绪的依赖（如 registry.NewDiscoveryCheck），
+	// 见 lifecycle.WaitForDependencies
+	Dependencies []lifecycle.Dependency
+	// StartupTimeout 等待 Dependencies 就绪的最长时间，为 0 时使用 lifecycle.DefaultStartupTimeout
+	StartupTimeout time.Duration
+	// WarmUp Start 启动协议处理器后、注册到注册中心前执行的预热（如加载缓存、建立数据库连接），返回错误时启动失败
+	WarmUp func(ctx context.Context) error
+	// HealthPath 在 network.port 的 HTTP 服务器上挂载健康检查端点的路径（如 /health），供 Kubernetes 探针访问；
+	// 为空时健康检查端点只在指标服务器上提供
+	HealthPath string
+	// PreStopDelay <HealthPath>/prestop 摘除流量后等待的时间，为 0 时使用 DefaultPreStopDelay
+	PreStopDelay time.Duration
+	// MTLS 服务实例间双向 TLS 的证书来源和续期配置，不为 nil 时启动时获取证书：
+	// 内部协议（gRPC、内部 JSON-RPC、自定义协议）要求对端出示证书，network.port 等共用 HTTP 端口的对外协议
+	// 在对端出示证书时验证，Client 调用其他服务时出示证书；证书在过期前自动续期，见 security.MTLSBootstrapper
+	MTLS *security.MTLSConfig
+	// Sessions WebSocket 连接的会话状态存储，为 nil 时使用进程内的 session.MemoryStore；
+	// 多实例部署时传入共享存储（如 session.RedisStore），客户端重连到其他实例时才能恢复会话
+	Sessions session.Store
+	// Overload 过载控制配置，不为 nil 时按 CPU、goroutine 数和队列深度（包括处理中的请求数）判断过载，
+	// 过载时先拒绝批处理请求，严重过载时拒绝所有请求，返回 ServiceUnavailable 和 Retry-After，见 resilience.OverloadController
+	Overload *resilience.OverloadConfig
+	// MQTTDisconnect MQTT 协议的 rateLimits 规则以 disconnect 限流设备时调用，device 为设备标识（不按设备限流时为空）；
+	// 处理器作为订阅方无法断开设备，可在此调用 Broker 的管理接口，见 mqtt.RateLimitRule
+	MQTTDisconnect func(topic, device string)
+	// AccountingSink 用量的导出目标，不为 nil 时启用用量计量并代替 framework.accounting 配置的 CSV、HTTP 或 Kafka，
+	// 见 accounting.Meter
+	AccountingSink accounting.Sink
+	// DeadLetterStore 死信存储，不为 nil 时启用死信队列并代替 framework.deadLetter 配置的存储，见 messaging.DeadLetterQueue
+	DeadLetterStore messaging.DeadLetterStore
+	// DarkLaunchResult framework.services 中配置了 darkLaunch 的服务每次比较完成后调用，
+	// 为 nil 时将不一致的结果写入框架日志，见 client.DarkLaunchOptions
+	DarkLaunchResult func(ctx context.Context, result *client.DarkLaunchResult)
+	// IDGenerator 追踪 ID、span ID 和请求 ID 的生成器，不为 nil 时代替 framework.idGenerator 配置的策略；
+	// 服务创建时设为进程的默认生成器，见 idgen.SetDefault
+	IDGenerator idgen.Generator
+	// SelfTests 启动自检的业务检查（如能连接数据库、能读取必需的配置），不为空时即使未启用 framework.selfTest 也执行自检，
+	// 见 SelfTestPath
+	SelfTests []observability.HealthCheck
+	// DisableRuntimeTuning 为 true 时不按容器的 CPU 和内存限制调整 GOMAXPROCS、GOMEMLIMIT
+	// 以及未配置的连接池和接收队列大小，见 lifecycle.TuneRuntime
+	DisableRuntimeTuning bool
+	// EmbedHTTP 为 true 时 REST、WebSocket、JSON-RPC、gRPC-Web 和 HealthPath 不监听端口，
+	// 由应用已运行的服务器转入请求：net/http 服务器挂载 Handler()，GoFrame 服务器使用 Middleware()。
+	// 协议的 port 仍写入注册中心，应配置为应用服务器的端口；TLS 和读写超时由应用的服务器负责
+	EmbedHTTP bool
+}
+
+// Server 框架服务
+//
+// 按 config.yaml 创建协议处理器、注册中心、安全管理器和可观测性组件，
+// Start 时启动协议处理器并将服务实例注册到注册中心，Shutdown 时按 lifecycle 的关闭阶段优雅关闭：
+//
+//	server, err := framework.NewServer("config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	server.Handle("hello.sayHello", func(ctx context.Context, params interface{}) (interface{}, error) {
+//	    return "Hello", nil
+//	})
+//	if err := server.Run(); err != nil {
+//	    log.Fatal(err)
+//	}
+type Server struct {
+	configManager *config.ConfigManager
+	configPath    string
+	config        *config.FrameworkConfig // 启动时的配置，热加载不修改
+	options       *Options
+
+	// reloadBase 上次热加载的配置，为 nil 时为 config
+	reloadMu   sync.Mutex
+	reloadBase *config.FrameworkConfig
+
+	registry      registry.ServiceRegistry
+	ownsRegistry  bool
+	security      *security.SecurityManager
+	mtls          *security.MTLSBootstrapper
+	overload      *resilience.OverloadController
+	observability *observability.ObservabilityManager
+
+	jsonRpcs         []*externaljsonrpc.JsonRpcProtocolHandler
+	internalJsonRpcs []*transport.InternalJsonRpcHandler
+	acceptQueues     map[protocolHandler]*resilience.AcceptQueue // JSON-RPC 处理器的接收队列
+	kafka            *kafka.KafkaProtocolHandler
+	grpc             *transport.GrpcServer
+	capture          *capture.RingBuffer
+	payloadLog       *capture.PayloadLogger
+	accounting       *accounting.Meter
+	closeAccounting  func(ctx context.Context) error
+	deadLetters      *messaging.DeadLetterQueue
+	closeDeadLetters func() error
+	selfTest         *observability.SelfTest
+	ids              idgen.Generator
+	hub              *websocket.Hub
+	embedded         *embeddedServer // Options.EmbedHTTP 时的 HTTP 服务器
+	sessions         session.Store
+	components       []component
+	protocols        []*runtimeProtocol // 可在运行时启用或停用的外部协议，由 mu 保护
+	service          *registry.ServiceInfo
+
+	methodsMu  sync.RWMutex
+	methods    map[string]Handler
+	idempotent map[string]bool
+
+	lifecycle *lifecycle.Manager
+	inFlight  *lifecycle.InFlight
+	requests  *requestTracker
+	admin     *admin.Admin
+
+	clientOnce sync.Once
+	client     client.FrameworkClient
+
+	mu            sync.Mutex
+	started       []component
+	draining      bool
+	stopHeartbeat context.CancelFunc
+	heartbeatDone chan struct{}
+	// unhealthy 就绪检查连续失败后已从注册中心注销，恢复后重新注册
+	unhealthy     bool
+	healthMonitor *observability.HealthMonitor
+	// limits、tuning 启动时检测的资源限制和调整后的运行时参数，见 Diagnostics
+	limits *lifecycle.ResourceLimits
+	tuning *lifecycle.RuntimeTuning
+	// serving 完成预热和注册、未摘除流量，见 servingCheck
+	serving atomic.Bool
+}
+
+// NewServer 按配置文件创建服务
+func NewServer(configPath string) (*Server, error) {
+	return NewServerWithOptions(configPath, nil)
+}
+
+// NewServerWithOptions 按配置文件和启动选项创建服务
+func NewServerWithOptions(configPath string, opts *Options) (*Server, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	cm, err := config.NewConfigManagerWithOptions(configPath, opts.Config)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := cm.LoadFrameworkConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		configManager: cm,
+		configPath:    configPath,
+		config:        cfg,
+		options:       opts,
+		methods:       make(map[string]Handler),
+		inFlight:      lifecycle.NewInFlight(),
+		requests:      newRequestTracker(),
+		acceptQueues:  make(map[protocolHandler]*resilience.AcceptQueue),
+		sessions:      opts.Sessions,
+	}
+	if s.sessions == nil {
+		s.sessions = session.NewMemoryStore(session.DefaultTTL)
+	}
+	if err := s.init(); err != nil {
+		s.closeResources()
+		if s.observability != nil {
+			s.observability.Shutdown(context.Background())
+		}
+		return nil, err
+	}
+	s.lifecycle = lifecycle.NewManager(&lifecycle.Options{
+		Timeout:       opts.ShutdownTimeout,
+		UpgradeSignal: opts.UpgradeSignal,
+		OnUpgradeError: func(err error) {
+			s.observability.Logger().Error(context.Background(), "Upgrade failed",
+				observability.Field{Key: "error", Value: err.Error()})
+		},
+	})
+	s.registerShutdownHooks()
+	return s, nil
+}
+
+// init 创建安全管理器、可观测性组件、注册中心和协议处理器
+func (s *Server) init() error {
+	if err := validatePorts(s.config); err != nil {
+		return err
+	}
+	if err := validateTLS(&s.config.Security.TLS); err != nil {
+		return err
+	}
+	lifecycle.SetReusePort(s.config.Network.ReusePort)
+	s.tuneRuntime()
+	if err := s.initIDGenerator(); err != nil {
+		return err
+	}
+
+	if s.config.Security.Authentication.Enabled || s.config.Security.Authorization.Enabled {
+		if s.options.Security == nil {
+			return fmt.Errorf("security is enabled but no security config is provided")
+		}
+		manager, err := security.NewSecurityManager(s.options.Security)
+		if err != nil {
+			return err
+		}
+		s.security = manager
+	}
+
+	obsConfig := observabilityConfig(s.config)
+	obsConfig.Exporter.IDGenerator = s.ids
+	s.observability = observability.NewObservabilityManager(obsConfig)
+	for _, warning := range listenerWarnings(s.config, s.options.MTLS != nil) {
+		s.observability.Logger().Warn(context.Background(), "Insecure listener configuration",
+			observability.Field{Key: "warning", Value: warning})
+	}
+
+	if s.options.Registry != nil {
+		s.registry = s.options.Registry
+		if s.config.Registry.ReadOnly && !registry.IsReadOnly(s.registry) {
+			s.registry, _ = registry.NewReadOnlyRegistry(s.registry)
+		}
+	} else {
+		reg, err := newRegistry(&s.config.Registry)
+		if err != nil {
+			return err
+		}
+		s.registry = reg
+		s.ownsRegistry = true
+	}
+	s.observability.HealthChecker().RegisterCheck(registry.NewConnectivityCheck(s.registry))
+	s.observability.HealthChecker().RegisterCheck(s.servingCheck())
+
+	if s.options.MTLS != nil {
+		mtls, err := s.newMTLS()
+		if err != nil {
+			return err
+		}
+		s.mtls = mtls
+	}
+	if s.options.Overload != nil {
+		s.overload = s.newOverload()
+	}
+	s.admin = s.newAdmin()
+	s.observability.RegisterHandler(AdminPath, s.admin)
+	if s.config.Catalog.Enabled {
+		s.observability.RegisterHandler(CatalogPath, s.newCatalog())
+	}
+
+	deadLetters, closeDeadLetters, err := s.newDeadLetterQueue(&s.config.DeadLetter)
+	if err != nil {
+		return err
+	}
+	s.deadLetters, s.closeDeadLetters = deadLetters, closeDeadLetters
+
+	components, err := s.newComponents()
+	if err != nil {
+		return err
+	}
+	s.components = components
+
+	service, err := s.serviceInfo()
+	if err != nil {
+		return err
+	}
+	s.service = service
+	if s.selfTest = s.newSelfTest(); s.selfTest != nil {
+		s.observability.RegisterHandler(SelfTestPath, s.selfTest.Handler())
+	}
+
+	meter, closeMeter, err := s.newMeter(&s.config.Accounting)
+	if err != nil {
+		return err
+	}
+	s.accounting, s.closeAccounting = meter, closeMeter
+	return nil
+}
+
+// Config 返回加载的框架配置
+func (s *Server) Config() *config.FrameworkConfig {
+	return s.config
+}
+
+// ConfigManager 返回配置管理器，用于读取业务配置
+func (s *Server) ConfigManager() *config.ConfigManager {
+	return s.configManager
+}
+
+// Registry 返回服务注册中心
+func (s *Server) Registry() registry.ServiceRegistry {
+	return s.registry
+}
+
+// Observability 返回可观测性管理器
+func (s *Server) Observability() *observability.ObservabilityManager {
+	return s.observability
+}
+
+// Security 返回安全管理器，未启用认证和授权时为 nil
+func (s *Server) Security() *security.SecurityManager {
+	return s.security
+}
+
+// ServiceInfo 返回注册到注册中心的服务实例信息
+func (s *Server) ServiceInfo() *registry.ServiceInfo {
+	return s.service
+}
+
+// Lifecycle 返回生命周期管理器，用于在服务关闭的各阶段注册业务的关闭钩子
+func (s *Server) Lifecycle() *lifecycle.Manager {
+	return s.lifecycle
+}
+
+// Admin 返回挂载在指标服务器 AdminPath 下的管理接口，可注册业务的查询和操作
+func (s *Server) Admin() *admin.Admin {
+	return s.admin
+}
+
+// Kafka 返回 Kafka 协议处理器，用于向 Kafka 发布记录，未启用 Kafka 协议时为 nil
+func (s *Server) Kafka() *kafka.KafkaProtocolHandler {
+	return s.kafka
+}
+
+// GRPC 返回内部 gRPC 服务器，未启用 gRPC 协议时为 nil；gRPC 服务须在 Start 之前注册，
+// 经 gRPC 端口和 gRPC-Web 协议提供
+func (s *Server) GRPC() *transport.GrpcServer {
+	return s.grpc
+}
+
+// Hub 返回 WebSocket 主题订阅与广播，未启用 WebSocket 协议时为 nil；
+// 连接以 {"action": "subscribe", "topic"} 订阅主题后收到 Broadcast 推送的消息
+func (s *Server) Hub() *websocket.Hub {
+	return s.hub
+}
+
+// Sessions 返回会话状态存储，业务方法也可以用 session.ForIdentity 按认证身份读写会话
+func (s *Server) Sessions() session.Store {
+	return s.sessions
+}
+
+// MTLS 返回服务实例间双向 TLS 的证书，未配置 Options.MTLS 时为 nil；
+// 可用于业务自行创建的客户端和服务器（ClientTLSConfig、ServerTLSConfig）
+func (s *Server) MTLS() *security.MTLSBootstrapper {
+	return s.mtls
+}
+
+// Overload 返回过载控制器，未配置 Options.Overload 时为 nil
+func (s *Server) Overload() *resilience.OverloadController {
+	return s.overload
+}
+
+// Capture 返回最近录制的请求，未启用 framework.capture 时为 nil
+func (s *Server) Capture() *capture.RingBuffer {
+	return s.capture
+}
+
+// IDGenerator 返回追踪 ID、span ID 和请求 ID 的生成器
+func (s *Server) IDGenerator() idgen.Generator {
+	return s.ids
+}
+
+// Accounting 返回用量计量器，未启用 framework.accounting 且未设置 Options.AccountingSink 时为 nil
+func (s *Server) Accounting() *accounting.Meter {
+	return s.accounting
+}
+
+// PayloadLog 返回负载日志，可在运行时以 SetEnabled 开启或关闭；Start 之前为 nil
+func (s *Server) PayloadLog() *capture.PayloadLogger {
+	return s.payloadLog
+}
+
+// Handle 注册业务方法，通过外部 JSON-RPC、内部 JSON-RPC、REST、WebSocket、Kafka 和自定义协议提供，须在 Start 之前调用
+func (s *Server) Handle(method string, handler Handler) {
+	handler = s.track(method, s.metered(method, handler))
+
+	s.methodsMu.Lock()
+	s.methods[method] = handler
+	s.methodsMu.Unlock()
+
+	for _, jsonRpc := range s.jsonRpcs {
+		jsonRpc.RegisterMethod(method, externaljsonrpc.MethodHandler(queuedHandler(s.acceptQueues[jsonRpc], handler)))
+	}
+	for _, internalJsonRpc := range s.internalJsonRpcs {
+		internalJsonRpc.RegisterMethod(method, transport.JsonRpcMethodHandler(queuedHandler(s.acceptQueues[internalJsonRpc], handler)))
+	}
+}
+
+// Client 返回调用其他服务的客户端，通过注册中心发现服务实例，按 framework.services 配置超时和重试
+func (s *Server) Client() client.FrameworkClient {
+	s.clientOnce.Do(func() {
+		onDarkLaunch := s.options.DarkLaunchResult
+		if onDarkLaunch == nil {
+			onDarkLaunch = s.logDarkLaunch
+		}
+		cfg := clientConfig(s.config, s.registry, s.options.Broker, onDarkLaunch)
+		if s.mtls != nil {
+			tlsConfig := s.mtls.ClientTLSConfig()
+			cfg.Connection.TLSConfig = tlsConfig
+			for _, conn := range cfg.Connection.Services {
+				conn.TLSConfig = tlsConfig
+			}
+		}
+		c := client.NewFrameworkClient(cfg)
+		c.Start()
+		s.client = c
+	})
+	return s.client
+}
+
+// Start 等待 Options.Dependencies 就绪后启动指标服务器和协议处理器，执行 Options.WarmUp，
+// 然后将服务实例注册到注册中心，此后就绪检查通过；启用了自检时在注册前（framework.selfTest.gateReadiness）或启动完成后执行自检
+//
+// 依赖未在 StartupTimeout 内就绪时返回错误；任一组件启动、预热或注册前的自检失败时关闭已启动的组件并返回错误
+func (s *Server) Start() error {
+	if err := s.waitForDependencies(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started != nil {
+		return fmt.Errorf("server already started")
+	}
+	if s.lifecycle.ShuttingDown() {
+		return fmt.Errorf("server is shut down")
+	}
+	s.started = make([]component, 0, len(s.components))
+
+	if s.config.Observability.Metrics.Enabled {
+		if err := s.observability.StartMetricsServer(); err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}
+
+	for _, c := range s.components {
+		if err := c.start(); err != nil {
+			s.stopComponents(context.Background())
+			s.started = nil
+			return fmt.Errorf("failed to start %s: %w", c.name, err)
+		}
+		s.started = append(s.started, c)
+	}
+
+	if s.options.WarmUp != nil {
+		if err := s.options.WarmUp(context.Background()); err != nil {
+			s.stopComponents(context.Background())
+			s.started = nil
+			return fmt.Errorf("failed to warm up: %w", err)
+		}
+	}
+
+	if s.selfTest != nil && s.config.SelfTest.GateReadiness {
+		if err := s.runSelfTest(context.Background()); err != nil {
+			s.stopComponents(context.Background())
+			s.started = nil
+			return err
+		}
+	}
+
+	// 注册的服务对象在 NewServer 之后才声明幂等方法，注册前写入元数据
+	registry.SetIdempotentMethods(s.service, s.idempotentMethods())
+	if s.registersSelf() {
+		if err := s.registry.Register(context.Background(), s.service); err != nil {
+			s.stopComponents(context.Background())
+			s.started = nil
+			return fmt.Errorf("failed to register service: %w", err)
+		}
+		s.startHeartbeat()
+	}
+	s.serving.Store(true)
+	s.startHealthMonitor()
+
+	s.observability.Logger().Info(context.Background(), "Server started",
+		observability.Field{Key: "service", Value: s.service.Name},
+		observability.Field{Key: "id", Value: s.service.ID},
+		observability.Field{Key: "address", Value: fmt.Sprintf("%s:%d", s.service.Address, s.service.Port)})
+	s.logDiagnostics()
+	if s.selfTest != nil && !s.config.SelfTest.GateReadiness {
+		go s.selfTest.Run(context.Background())
+	}
+	s.watchReloadSignal()
+
+	// 由热重启启动时通知旧进程停止接受请求
+	if err := lifecycle.Ready(); err != nil {
+		s.observability.Logger().Warn(context.Background(), "Failed to notify previous process",
+			observability.Field{Key: "error", Value: err.Error()})
+	}
+	return nil
+}
+
+// waitForDependencies 等待启动依赖就绪，服务关闭时停止等待
+func (s *Server) waitForDependencies() error {
+	if len(s.options.Dependencies) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.lifecycle.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := lifecycle.WaitForDependencies(ctx, &lifecycle.StartupOptions{
+		Timeout: s.options.StartupTimeout,
+		OnWaiting: func(name string, attempt int, err error) {
+			s.observability.Logger().Info(ctx, "Waiting for dependency",
+				observability.Field{Key: "dependency", Value: name},
+				observability.Field{Key: "attempt", Value: attempt},
+				observability.Field{Key: "error", Value: err.Error()})
+		},
+	}, s.options.Dependencies...)
+	if err != nil {
+		return fmt.Errorf("failed to wait for dependencies: %w", err)
+	}
+	return nil
+}
+
+// Shutdown 优雅关闭服务，只执行一次
+//
+// 依次从注册中心注销、停止接受新请求、在 ctx 内等待处理中的请求完成、
+// 按启动的相反顺序停止协议处理器并关闭客户端连接池、刷新追踪和指标，最后关闭注册中心和安全管理器
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.lifecycle.Shutdown(ctx)
+}
+
+// Run 启动服务并阻塞，收到 SIGINT 或 SIGTERM 后在 ShutdownTimeout 内优雅关闭；
+// 设置了 UpgradeSignal 时，收到该信号后启动新进程并交接监听端口，新进程就绪后关闭当前进程
+func (s *Server) Run() error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+	return s.lifecycle.Wait()
+}
+
+// registerShutdownHooks 在各关闭阶段注册服务组件的关闭钩子
+func (s *Server) registerShutdownHooks() {
+	s.lifecycle.Register(lifecycle.PhaseDeregister, "registry", s.deregister)
+	s.lifecycle.Register(lifecycle.PhaseStopAccepting, "requests", func(ctx context.Context) error {
+		s.inFlight.Close()
+		return nil
+	})
+	s.lifecycle.Register(lifecycle.PhaseDrain, "in-flight requests", s.inFlight.Wait)
+	// 处理中的请求完成后、Kafka 协议停止前导出剩余的用量
+	if s.closeAccounting != nil {
+		s.lifecycle.Register(lifecycle.PhaseDrain, "accounting", s.closeAccounting)
+	}
+	s.lifecycle.Register(lifecycle.PhaseDrain, "protocol handlers", func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		err := s.stopComponents(ctx)
+		s.started = nil
+		return err
+	})
+	s.lifecycle.Register(lifecycle.PhaseDrain, "client", func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.client == nil {
+			return nil
+		}
+		return s.client.Shutdown(ctx)
+	})
+	s.lifecycle.Register(lifecycle.PhaseFlush, "observability", s.observability.Shutdown)
+	s.lifecycle.Register(lifecycle.PhaseClose, "resources", func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.closeResources()
+	})
+}
+
+// deregister 停止心跳并从注册中心注销，随后在 DeregisterDelay 内继续接受请求
+//
+// 已通过 Drain 注销或热重启时（新进程已注册相同 ID 的服务实例）不再注销
+func (s *Server) deregister(ctx context.Context) error {
+	s.serving.Store(false)
+	s.stopHealthMonitor()
+
+	s.mu.Lock()
+	if s.started == nil || s.draining || s.unhealthy || !s.registersSelf() || lifecycle.Upgrading(ctx) {
+		s.stopHeartbeatLoop()
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopHeartbeatLoop()
+	err := s.registry.Deregister(ctx, s.service.ID)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to deregister service: %w", err)
+	}
+
+	if s.options.DeregisterDelay > 0 {
+		select {
+		case <-time.After(s.options.DeregisterDelay):
+		case <-ctx.Done():
+		}
+	}
+	return nil
+}
+
+// Drain 从注册中心注销服务实例并停止心跳，使调用方不再路由到本实例，已建立的连接和请求继续处理
+func (s *Server) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started == nil {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "server not started")
+	}
+	if s.draining {
+		return nil
+	}
+	if s.unhealthy || !s.registersSelf() {
+		// 已因就绪检查失败注销，或使用只读注册中心未注册
+		s.draining = true
+		s.serving.Store(false)
+		return nil
+	}
+	s.stopHeartbeatLoop()
+	if err := s.registry.Deregister(ctx, s.service.ID); err != nil {
+		s.startHeartbeat()
+		return fmt.Errorf("failed to deregister service: %w", err)
+	}
+	s.draining = true
+	s.serving.Store(false)
+	s.observability.Logger().Info(ctx, "Server draining",
+		observability.Field{Key: "id", Value: s.service.ID})
+	return nil
+}
+
+// Resume 重新注册 Drain 注销的服务实例，就绪检查仍未恢复时由健康监控在恢复后注册
+func (s *Server) Resume(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started == nil || s.lifecycle.ShuttingDown() {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "server not running")
+	}
+	if !s.draining {
+		return nil
+	}
+	if s.unhealthy || !s.registersSelf() {
+		s.draining = false
+		s.serving.Store(true)
+		return nil
+	}
+	if err := s.registry.Register(ctx, s.service); err != nil {
+		return fmt.Errorf("failed to register service: %w", err)
+	}
+	s.draining = false
+	s.serving.Store(true)
+	s.startHeartbeat()
+	s.observability.Logger().Info(ctx, "Server resumed",
+		observability.Field{Key: "id", Value: s.service.ID})
+	return nil
+}
+
+// track 包装业务方法处理器，统计和登记处理中的请求，服务关闭后或过载时拒绝新请求；
+// 请求经 CancelRequest 取消后处理器返回的 context.Canceled 替换为取消原因
+func (s *Server) track(method string, handler Handler) Handler {
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		if s.overload != nil {
+			if err := s.overload.Allow(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if !s.inFlight.Acquire() {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "server is shutting down")
+		}
+		defer s.inFlight.Release()
+
+		ctx, done := s.requests.start(ctx, method)
+		defer done()
+		result, err := handler(ctx, params)
+		if err != nil && errors.Is(err, context.Canceled) {
+			var cause *frameworkerrors.FrameworkError
+			if errors.As(context.Cause(ctx), &cause) {
+				err = cause
+			}
+		}
+		return result, err
+	}
+}
+
+// stopComponents 按启动的相反顺序停止已启动的组件
+func (s *Server) stopComponents(ctx context.Context) error {
+	var errs []error
+	for i := len(s.started) - 1; i >= 0; i-- {
+		c := s.started[i]
+		if err := c.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeResources 关闭服务创建的注册中心和安全管理器，停止证书续期和过载采样
+func (s *Server) closeResources() error {
+	var errs []error
+	if s.overload != nil {
+		s.overload.Close()
+	}
+	if s.mtls != nil {
+		errs = append(errs, s.mtls.Close())
+	}
+	if s.ownsRegistry && s.registry != nil {
+		errs = append(errs, s.registry.Close())
+		s.registry = nil
+	}
+	if s.security != nil {
+		errs = append(errs, s.security.Close())
+		s.security = nil
+	}
+	if s.closeDeadLetters != nil {
+		errs = append(errs, s.closeDeadLetters())
+		s.closeDeadLetters = nil
+	}
+	return errors.Join(errs...)
+}
+
+// registersSelf 判断是否在注册中心注册本实例；只读注册中心（framework.registry.readOnly）只用于发现其他服务
+func (s *Server) registersSelf() bool {
+	return !registry.IsReadOnly(s.registry)
+}
+
+// startHeartbeat 注册中心需要心跳时（如 MemoryRegistry，EtcdRegistry 自行续约）按 framework.registry.heartbeatInterval 定期发送，
+// 注册中心重启等原因导致实例丢失时自动重新注册
+func (s *Server) startHeartbeat() {
+	if _, ok := s.registry.(registry.Heartbeater); !ok {
+		return
+	}
+
+	interval := time.Duration(s.config.Registry.HeartbeatInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopHeartbeat = cancel
+	s.heartbeatDone = make(chan struct{})
+	go func(done chan<- struct{}) {
+		defer close(done)
+		registry.KeepRegistered(ctx, s.registry, s.service, &registry.KeepRegisteredOptions{
+			Interval:      interval,
+			OnStateChange: s.onRegistrationStateChange,
+		})
+	}(s.heartbeatDone)
+}
+
+// onRegistrationStateChange 记录心跳失败、实例丢失和恢复
+func (s *Server) onRegistrationStateChange(service *registry.ServiceInfo, state registry.RegistrationState, err error) {
+	ctx := context.Background()
+	switch state {
+	case registry.RegistrationActive:
+		s.observability.Logger().Info(ctx, "Service registration restored",
+			observability.Field{Key: "id", Value: service.ID})
+	case registry.RegistrationLost:
+		s.observability.Logger().Warn(ctx, "Service instance lost from registry, re-registering",
+			observability.Field{Key: "id", Value: service.ID})
+	default:
+		s.observability.Logger().Warn(ctx, "Heartbeat failed",
+			observability.Field{Key: "error", Value: err.Error()})
+	}
+}
+
+// stopHeartbeatLoop 停止心跳并等待心跳协程退出
+func (s *Server) stopHeartbeatLoop() {
+	if s.stopHeartbeat == nil {
+		return
+	}
+	s.stopHeartbeat()
+	<-s.heartbeatDone
+	s.stopHeartbeat = nil
+	s.heartbeatDone = nil
+}
+
+// startHealthMonitor 按 framework.registry.healthCheckInterval 周期执行就绪检查，
+// 连续 unhealthyThreshold 次失败时从注册中心注销并停止心跳，使调用方不再路由到本实例，恢复后重新注册
+func (s *Server) startHealthMonitor() {
+	interval := time.Duration(s.config.Registry.HealthCheckInterval) * time.Second
+	if interval <= 0 || !s.registersSelf() {
+		return
+	}
+	s.healthMonitor = observability.NewHealthMonitor(s.observability.HealthChecker(), &observability.HealthMonitorConfig{
+		Interval:         interval,
+		FailureThreshold: s.config.Registry.UnhealthyThreshold,
+		OnChange:         s.onHealthChange,
+	})
+}
+
+// stopHealthMonitor 停止健康监控，不持有 s.mu 调用，避免与进行中的 onHealthChange 死锁
+func (s *Server) stopHealthMonitor() {
+	s.mu.Lock()
+	monitor := s.healthMonitor
+	s.healthMonitor = nil
+	s.mu.Unlock()
+	if monitor != nil {
+		monitor.Close()
+	}
+}
+
+// onHealthChange 就绪状态变化时同步注册中心中的服务实例；Drain 期间只记录状态，由 Resume 决定是否注册
+func (s *Server) onHealthChange(ctx context.Context, healthy bool, response observability.HealthResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started == nil || s.lifecycle.ShuttingDown() {
+		return
+	}
+
+	if !healthy {
+		s.unhealthy = true
+		if s.draining {
+			return
+		}
+		s.stopHeartbeatLoop()
+		if err := s.registry.Deregister(ctx, s.service.ID); err != nil {
+			s.observability.Logger().Warn(ctx, "Failed to deregister unhealthy service",
+				observability.Field{Key: "error", Value: err.Error()})
+		}
+		s.observability.Logger().Warn(ctx, "Service unhealthy, deregistered",
+			observability.Field{Key: "id", Value: s.service.ID},
+			observability.Field{Key: "checks", Value: response.Checks})
+		return
+	}
+
+	if !s.unhealthy {
+		return
+	}
+	if !s.draining {
+		if err := s.registry.Register(ctx, s.service); err != nil {
+			// 保持不健康状态，下次检查时重试
+			s.observability.Logger().Warn(ctx, "Failed to register recovered service",
+				observability.Field{Key: "error", Value: err.Error()})
+			return
+		}
+		s.startHeartbeat()
+	}
+	s.unhealthy = false
+	s.observability.Logger().Info(ctx, "Service healthy, registered",
+		observability.Field{Key: "id", Value: s.service.ID})
+}
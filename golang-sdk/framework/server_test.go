@@ -0,0 +1,1156 @@
+package framework
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/config"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/external/mqtt"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+)
+
+// testConfig 只启用外部 JSON-RPC 和内部 JSON-RPC 的最小配置
+const testConfig = `framework:
+  name: greeter-service
+  version: 1.0.0
+  language: golang
+  network:
+    host: 127.0.0.1
+    port: 18401
+    maxConnections: 100
+    keepAlive: true
+  registry:
+    type: memory
+    endpoints: [memory]
+    heartbeatInterval: 1
+  protocols:
+    external:
+      - type: JSON-RPC
+        enabled: true
+        port: 18401
+        path: /jsonrpc
+      - type: REST
+        enabled: true
+        port: 18401
+        path: /api
+    internal:
+      - type: JSON-RPC
+        enabled: true
+        port: 18402
+        serialization: JSON
+  connectionPool:
+    maxConnections: 10
+  observability:
+    logging:
+      level: error
+    metrics:
+      enabled: false
+    tracing:
+      enabled: false
+`
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestServerLifecycle(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		args, _ := params.(map[string]interface{})
+		name, _ := args["name"].(string)
+		if name == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "name is required")
+		}
+		return "Hello " + name, nil
+	})
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// 启动后注册到注册中心
+	services, err := reg.Discover(context.Background(), "greeter-service")
+	if err != nil || len(services) != 1 {
+		t.Fatalf("Expected 1 registered instance, got %d (err=%v)", len(services), err)
+	}
+	if services[0].Port != 18401 || services[0].Metadata[MetadataPortPrefix+"internalrpc"] != "18402" {
+		t.Errorf("Unexpected service info: %+v", services[0])
+	}
+
+	// 通过客户端经注册中心调用
+	var greeting string
+	err = server.Client().Call(context.Background(), "greeter-service", "greeter.hello", map[string]string{"name": "Go"}, &greeting)
+	if err != nil || greeting != "Hello Go" {
+		t.Errorf("Call = %q, %v; want Hello Go", greeting, err)
+	}
+
+	// 业务错误以结构化错误返回
+	err = server.Client().Call(context.Background(), "greeter-service", "greeter.hello", map[string]string{}, &greeting)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Errorf("Expected BadRequest, got %v", err)
+	}
+
+	// 未注册的方法
+	err = server.Client().Call(context.Background(), "greeter-service", "greeter.missing", nil, nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	if err := server.Start(); err == nil {
+		t.Error("Expected error when starting twice")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	// 关闭时先注销
+	services, _ = reg.Discover(context.Background(), "greeter-service")
+	if len(services) != 0 {
+		t.Errorf("Expected instance to be deregistered, got %d", len(services))
+	}
+}
+
+func TestServerShutdownDrainsRequests(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.Handle("greeter.slow", func(ctx context.Context, params interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+	server.methodsMu.RLock()
+	handler := server.methods["greeter.slow"]
+	server.methodsMu.RUnlock()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := handler(context.Background(), nil)
+		result <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown(ctx)
+	}()
+
+	// 停止接受请求后拒绝新请求
+	deadline := time.Now().Add(time.Second)
+	for server.inFlight.Acquire() {
+		server.inFlight.Release()
+		if time.Now().After(deadline) {
+			t.Fatal("Expected server to stop accepting requests")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err = handler(context.Background(), nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ServiceUnavailable {
+		t.Errorf("Expected ServiceUnavailable after shutdown started, got %v", err)
+	}
+
+	// 等待处理中的请求完成
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned before in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-result; err != nil {
+		t.Errorf("In-flight request failed: %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if err := server.Start(); err == nil {
+		t.Error("Expected error when starting after shutdown")
+	}
+}
+
+func TestServerOverload(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	// 处理中的请求数达到 1 时过载
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry: reg,
+		Overload: &resilience.OverloadConfig{MaxQueueDepth: 1, Interval: time.Hour, RetryAfter: 3 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.Handle("greeter.slow", func(ctx context.Context, params interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	call := func(method, priority string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:18401/api", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Service-Name", "greeter")
+		req.Header.Set("X-Method-Name", method)
+		if priority != "" {
+			req.Header.Set("X-Metadata-Priority", priority)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		call("slow", "")
+	}()
+	<-started
+	if pressure := server.Overload().Sample(); pressure.QueueDepths[inFlightQueue] != 1 {
+		t.Errorf("unexpected pressure: %+v", pressure)
+	}
+
+	// 过载时拒绝批处理请求并返回 Retry-After，交互请求继续处理
+	resp := call("hello", "batch")
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") != "3" {
+		t.Errorf("batch request: status=%d Retry-After=%q, want 503 and 3", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp := call("hello", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("interactive request: status=%d, want 200", resp.StatusCode)
+	}
+
+	close(release)
+	<-done
+	server.Overload().Sample()
+	if resp := call("hello", "batch"); resp.StatusCode != http.StatusOK {
+		t.Errorf("batch request after recovery: status=%d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServerAdmin(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry: reg,
+		AdminAuthenticate: func(r *http.Request, operation string) error {
+			if r.Header.Get("X-Admin-Token") != "secret" {
+				return frameworkerrors.NewFrameworkError(frameworkerrors.Unauthorized, "invalid admin token")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	call := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Admin-Token", "secret")
+		rec := httptest.NewRecorder()
+		server.Admin().ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+	discover := func() int {
+		services, _ := reg.Discover(context.Background(), "greeter-service")
+		return len(services)
+	}
+
+	if code, body := call(http.MethodGet, "/admin/routes", ""); code != http.StatusOK || !strings.Contains(body, `"greeter.hello"`) {
+		t.Errorf("routes = %d %s", code, body)
+	}
+	if code, body := call(http.MethodGet, "/admin/registry", ""); code != http.StatusOK || !strings.Contains(body, `"greeter-service":[{`) {
+		t.Errorf("registry = %d %s", code, body)
+	}
+	if code, body := call(http.MethodGet, "/admin/registry?prefix=greeter-", ""); code != http.StatusOK || !strings.Contains(body, `"greeter-service":[{`) {
+		t.Errorf("registry by prefix = %d %s", code, body)
+	}
+	if code, body := call(http.MethodGet, "/admin/config?prefix=framework.network", ""); code != http.StatusOK || !strings.Contains(body, "framework.network.port") {
+		t.Errorf("config = %d %s", code, body)
+	}
+	if code, _ := call(http.MethodPost, "/admin/breakers.reset", `{"service":"unknown"}`); code != http.StatusNotFound {
+		t.Errorf("breakers.reset for unknown service = %d, want 404", code)
+	}
+	if code, body := call(http.MethodPost, "/admin/logLevel", `{"level":"verbose"}`); code != http.StatusBadRequest {
+		t.Errorf("logLevel = %d %s, want 400", code, body)
+	}
+	if code, body := call(http.MethodGet, "/admin/capture", ""); code != http.StatusBadRequest {
+		t.Errorf("capture without framework.capture = %d %s, want 400", code, body)
+	}
+	if code, body := call(http.MethodGet, "/admin/errorCodes", ""); code != http.StatusOK || !strings.Contains(body, `"name":"Not Found"`) {
+		t.Errorf("errorCodes = %d %s", code, body)
+	}
+	if code, body := call(http.MethodPost, "/admin/errorCodes.verify", `{"version":1,"codes":[]}`); code != http.StatusOK || !strings.Contains(body, `"ok":false`) {
+		t.Errorf("errorCodes.verify = %d %s", code, body)
+	}
+	if code, body := call(http.MethodPost, "/admin/payloadLog", `{"enabled":true,"perMinute":3}`); code != http.StatusOK || !strings.Contains(body, `"enabled":true,"perMinute":3`) {
+		t.Errorf("payloadLog = %d %s", code, body)
+	}
+	if !server.PayloadLog().Status().Enabled {
+		t.Error("Expected payload log to be enabled at runtime")
+	}
+
+	// 摘除流量后从注册中心注销，恢复后重新注册
+	if code, body := call(http.MethodPost, "/admin/drain", ""); code != http.StatusOK || !strings.Contains(body, `"draining":true`) {
+		t.Fatalf("drain = %d %s", code, body)
+	}
+	if n := discover(); n != 0 {
+		t.Errorf("Expected instance to be deregistered after drain, got %d", n)
+	}
+	if code, body := call(http.MethodPost, "/admin/resume", ""); code != http.StatusOK || !strings.Contains(body, `"draining":false`) {
+		t.Fatalf("resume = %d %s", code, body)
+	}
+	if n := discover(); n != 1 {
+		t.Errorf("Expected instance to be registered after resume, got %d", n)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/status", nil)
+	rec := httptest.NewRecorder()
+	server.Admin().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want 401", rec.Code)
+	}
+}
+
+func TestServerHealthPropagation(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	content := strings.Replace(testConfig, "heartbeatInterval: 1", "heartbeatInterval: 1\n    healthCheckInterval: 1\n    unhealthyThreshold: 1", 1)
+	server, err := NewServerWithOptions(writeTestConfig(t, content), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	var failing atomic.Bool
+	server.Observability().HealthChecker().RegisterCheck(observability.NewSimpleHealthCheck("db", func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	waitFor := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			services, _ := reg.Discover(context.Background(), "greeter-service")
+			if len(services) == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d registered instances, got %d", want, len(services))
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	// 就绪检查失败后注销，恢复后重新注册
+	failing.Store(true)
+	waitFor(0)
+	failing.Store(false)
+	waitFor(1)
+
+	// 不健康期间 Drain 后恢复不注册，由 Resume 注册
+	failing.Store(true)
+	waitFor(0)
+	if err := server.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	failing.Store(false)
+	time.Sleep(1500 * time.Millisecond)
+	waitFor(0)
+	if err := server.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	waitFor(1)
+}
+
+func TestServerReadOnlyRegistry(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	ctx := context.Background()
+	reg.Register(ctx, &registry.ServiceInfo{ID: "order-1", Name: "order-service", Address: "127.0.0.1", Port: 18499})
+
+	cfg := strings.Replace(testConfig, "    heartbeatInterval: 1\n", "    heartbeatInterval: 1\n    readOnly: true\n", 1)
+	server, err := NewServerWithOptions(writeTestConfig(t, cfg), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if !registry.IsReadOnly(server.Registry()) {
+		t.Fatalf("Expected read-only registry, got %T", server.Registry())
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// 只发现其他服务，不注册本实例
+	if services, _ := reg.Discover(ctx, "greeter-service"); len(services) != 0 {
+		t.Errorf("Expected no registered instance, got %d", len(services))
+	}
+	if services, err := server.Registry().Discover(ctx, "order-service"); err != nil || len(services) != 1 {
+		t.Errorf("Expected to discover order-service, got %v, %v", services, err)
+	}
+	if err := server.Registry().Register(ctx, &registry.ServiceInfo{ID: "fake", Name: "order-service"}); !errors.Is(err, registry.ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+
+	if err := server.Drain(ctx); err != nil {
+		t.Errorf("Drain failed: %v", err)
+	}
+	if err := server.Resume(ctx); err != nil {
+		t.Errorf("Resume failed: %v", err)
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+func TestServerStartupDependencies(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	// 依赖未就绪时启动失败，不注册
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:       reg,
+		Dependencies:   []lifecycle.Dependency{registry.NewDiscoveryCheck(reg, "order-service")},
+		StartupTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if err := server.Start(); err == nil || !strings.Contains(err.Error(), "order-service") {
+		t.Fatalf("Expected order-service dependency error, got %v", err)
+	}
+	if services, _ := reg.Discover(context.Background(), "greeter-service"); len(services) != 0 {
+		t.Errorf("Expected no registered instance, got %d", len(services))
+	}
+	server.Shutdown(context.Background())
+
+	// 依赖就绪后启动
+	server, err = NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:     reg,
+		Dependencies: []lifecycle.Dependency{registry.NewDiscoveryCheck(reg, "order-service")},
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		reg.Register(context.Background(), &registry.ServiceInfo{ID: "order-1", Name: "order-service", Address: "127.0.0.1", Port: 18500})
+	}()
+	started := time.Now()
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 200*time.Millisecond {
+		t.Errorf("Start returned after %v, before order-service registered", elapsed)
+	}
+	if services, _ := reg.Discover(context.Background(), "greeter-service"); len(services) != 1 {
+		t.Errorf("Expected 1 registered instance, got %d", len(services))
+	}
+}
+
+func TestServerSelfTest(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	selfTestConfig := strings.Replace(testConfig, "  connectionPool:", `  services:
+    order-service:
+      timeout: 1s
+  selfTest:
+    enabled: true
+    gateReadiness: true
+    timeout: 100ms
+  connectionPool:`, 1)
+
+	// 依赖的服务不可发现时注册前的自检失败，启动失败且不注册
+	server, err := NewServerWithOptions(writeTestConfig(t, selfTestConfig), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	if err := server.Start(); err == nil || !strings.Contains(err.Error(), "service:order-service") {
+		t.Fatalf("Expected self-test failure for order-service, got %v", err)
+	}
+	if services, _ := reg.Discover(context.Background(), "greeter-service"); len(services) != 0 {
+		t.Errorf("Expected no registered instance, got %d", len(services))
+	}
+	report := server.SelfTestReport()
+	if report == nil || report.Passed || len(report.Results) != 3 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	server.Shutdown(context.Background())
+
+	// 不阻塞启动时在后台执行，结果可经管理接口查询
+	reg.Register(context.Background(), &registry.ServiceInfo{ID: "order-1", Name: "order-service", Address: "127.0.0.1", Port: 18500})
+	var calls atomic.Int32
+	server, err = NewServerWithOptions(writeTestConfig(t, strings.Replace(selfTestConfig, "gateReadiness: true", "gateReadiness: false", 1)), &Options{
+		Registry: reg,
+		SelfTests: []observability.HealthCheck{observability.NewSimpleHealthCheck("database", func(ctx context.Context) error {
+			calls.Add(1)
+			return errors.New("connection refused")
+		})},
+		AdminAuthenticate: func(r *http.Request, operation string) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for server.SelfTestReport() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	report = server.SelfTestReport()
+	if report == nil || report.Passed || len(report.Failed()) != 1 || report.Failed()[0].Name != "database" {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+
+	result, err := server.Admin().Invoke(context.Background(), "selfTest.run", nil)
+	if err != nil {
+		t.Fatalf("selfTest.run failed: %v", err)
+	}
+	if rerun := result.(*observability.SelfTestReport); len(rerun.Results) != 4 || calls.Load() != 2 {
+		t.Errorf("Unexpected rerun report %+v after %d calls", rerun, calls.Load())
+	}
+}
+
+func TestServerProbes(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	warming := make(chan struct{})
+	warmed := make(chan struct{})
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:   reg,
+		HealthPath: "/health",
+		WarmUp: func(ctx context.Context) error {
+			close(warming)
+			<-warmed
+			return nil
+		},
+		PreStopDelay: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	started := make(chan error, 1)
+	go func() {
+		started <- server.Start()
+	}()
+	get := func(path string) int {
+		resp, err := http.Get("http://127.0.0.1:18401" + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// 预热期间协议处理器已启动，但未就绪
+	<-warming
+	if code := get("/health/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("ready during warm-up = %d, want 503", code)
+	}
+	if code := get("/health/live"); code != http.StatusOK {
+		t.Errorf("live during warm-up = %d, want 200", code)
+	}
+	close(warmed)
+	if err := <-started; err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if code := get("/health/ready"); code != http.StatusOK {
+		t.Errorf("ready after start = %d, want 200", code)
+	}
+
+	// preStop 注销并等待 PreStopDelay
+	begin := time.Now()
+	if code := get("/health/prestop"); code != http.StatusOK {
+		t.Errorf("prestop = %d, want 200", code)
+	}
+	if elapsed := time.Since(begin); elapsed < 50*time.Millisecond {
+		t.Errorf("prestop returned after %v, want at least 50ms", elapsed)
+	}
+	if code := get("/health/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("ready after prestop = %d, want 503", code)
+	}
+	if services, _ := reg.Discover(context.Background(), "greeter-service"); len(services) != 0 {
+		t.Errorf("Expected instance to be deregistered after prestop, got %d", len(services))
+	}
+}
+
+// newTestCASource 创建自签名 CA 并返回用其签发证书的 LocalCASource
+func newTestCASource(t *testing.T) *security.LocalCASource {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+	return &security.LocalCASource{CA: ca, CAKey: key, CommonName: "greeter-service"}
+}
+
+func TestServerMTLS(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, testConfig), &Options{
+		Registry:   reg,
+		HealthPath: "/health",
+		MTLS:       &security.MTLSConfig{Source: newTestCASource(t)},
+	})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// 客户端经 HTTPS 出示证书调用
+	var greeting string
+	err = server.Client().Call(context.Background(), "greeter-service", "greeter.hello", nil, &greeting)
+	if err != nil || greeting != "Hello" {
+		t.Errorf("Call = %q, %v; want Hello", greeting, err)
+	}
+
+	// 明文 HTTP 被拒绝，没有客户端证书的探针仍可访问
+	if resp, err := http.Get("http://127.0.0.1:18401/health/live"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("Expected plain HTTP to fail")
+		}
+	}
+	probe := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := probe.Get("https://127.0.0.1:18401/health/live")
+	if err != nil {
+		t.Fatalf("GET /health/live failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("live = %d, want 200", resp.StatusCode)
+	}
+
+	// 内部协议要求客户端证书
+	call := func(config *tls.Config) error {
+		conn, err := tls.Dial("tcp", "127.0.0.1:18402", config)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if _, err := conn.Write([]byte(`{"jsonrpc":"2.0","method":"greeter.hello","id":1}` + "\n")); err != nil {
+			return err
+		}
+		var response map[string]interface{}
+		if err := json.NewDecoder(conn).Decode(&response); err != nil {
+			return err
+		}
+		if response["result"] != "Hello" {
+			return fmt.Errorf("unexpected response: %v", response)
+		}
+		return nil
+	}
+	if err := call(server.MTLS().ClientTLSConfig()); err != nil {
+		t.Errorf("internal call with certificate failed: %v", err)
+	}
+	if err := call(&tls.Config{InsecureSkipVerify: true}); err == nil {
+		t.Error("Expected internal call without certificate to fail")
+	}
+}
+
+// multiPortConfig 外部 JSON-RPC 同时监听所有网卡和只供本机访问的端口，内部 JSON-RPC 和 gRPC 各监听两个端口
+const multiPortConfig = `framework:
+  name: multi-port-service
+  version: 1.0.0
+  language: golang
+  network:
+    host: 0.0.0.0
+    port: 18411
+    maxConnections: 100
+    keepAlive: true
+    reusePort: true
+  registry:
+    type: memory
+    endpoints: [memory]
+  protocols:
+    external:
+      - type: JSON-RPC
+        enabled: true
+        port: 18411
+        path: /jsonrpc
+      - type: JSON-RPC
+        enabled: true
+        host: 127.0.0.1
+        port: 18412
+        path: /jsonrpc
+    internal:
+      - type: JSON-RPC
+        enabled: true
+        port: 18413
+      - type: JSON-RPC
+        enabled: true
+        port: 18414
+      - type: gRPC
+        enabled: true
+        port: 18415
+      - type: gRPC
+        enabled: true
+        host: 127.0.0.1
+        port: 18416
+  connectionPool:
+    maxConnections: 10
+  observability:
+    logging:
+      level: error
+    metrics:
+      enabled: false
+    tracing:
+      enabled: false
+`
+
+func TestServerMultiPort(t *testing.T) {
+	defer lifecycle.SetReusePort(false)
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+
+	server, err := NewServerWithOptions(writeTestConfig(t, multiPortConfig), &Options{Registry: reg})
+	if err != nil {
+		t.Fatalf("NewServerWithOptions failed: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+	server.Handle("greeter.hello", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "Hello", nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// 业务方法在每个 JSON-RPC 端口上提供
+	for _, port := range []string{"18411", "18412"} {
+		resp, err := http.Post("http://127.0.0.1:"+port+"/jsonrpc", "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","method":"greeter.hello","id":1}`))
+		if err != nil {
+			t.Fatalf("POST to %s failed: %v", port, err)
+		}
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if result["result"] != "Hello" {
+			t.Errorf("JSON-RPC on %s = %v, want Hello", port, result)
+		}
+	}
+	for _, port := range []string{"18413", "18414"} {
+		conn, err := net.DialTimeout("tcp", "127.0.0.1:"+port, time.Second)
+		if err != nil {
+			t.Fatalf("Dial %s failed: %v", port, err)
+		}
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		conn.Write([]byte(`{"jsonrpc":"2.0","method":"greeter.hello","id":1}` + "\n"))
+		var result map[string]interface{}
+		json.NewDecoder(conn).Decode(&result)
+		conn.Close()
+		if result["result"] != "Hello" {
+			t.Errorf("internal JSON-RPC on %s = %v, want Hello", port, result)
+		}
+	}
+	for _, port := range []string{"18415", "18416"} {
+		conn, err := net.DialTimeout("tcp", "127.0.0.1:"+port, time.Second)
+		if err != nil {
+			t.Errorf("gRPC on %s not listening: %v", port, err)
+			continue
+		}
+		conn.Close()
+	}
+
+	// 只供本机访问的端口不写入注册中心，同一协议写入第一个端口；注册中心保存规范化的协议名称
+	services, _ := reg.Discover(context.Background(), "multi-port-service")
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 registered instance, got %d", len(services))
+	}
+	info := services[0]
+	if info.Port != 18411 || info.Metadata[MetadataPortPrefix+"jsonrpc"] != "18411" ||
+		info.Metadata[MetadataPortPrefix+"internalrpc"] != "18413" || info.Metadata[MetadataPortPrefix+"grpc"] != "18415" {
+		t.Errorf("Unexpected service info: port=%d metadata=%v", info.Port, info.Metadata)
+	}
+
+	// SO_REUSEPORT：其他进程（这里用同一进程模拟）可以监听相同端口
+	ln, err := lifecycle.Listen("tcp", "0.0.0.0:18411")
+	if err != nil {
+		t.Errorf("Listen on a reused port failed: %v", err)
+	} else {
+		ln.Close()
+	}
+}
+
+func TestValidatePorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *config.FrameworkConfig)
+		wantErr string
+	}{
+		{
+			name:   "默认配置无冲突",
+			modify: func(cfg *config.FrameworkConfig) {},
+		},
+		{
+			name: "指标端口与 gRPC 冲突",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Observability.Metrics.Port = 9001
+			},
+			wantErr: "port 9001 is used by both internal gRPC and metrics",
+		},
+		{
+			name: "内部协议与 HTTP 冲突",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.Internal[1].Port = 8081
+			},
+			wantErr: "port 8081 is used by both HTTP and internal JSON-RPC",
+		},
+		{
+			name: "内部协议缺少端口",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.Internal[1].Port = 0
+			},
+			wantErr: "internal JSON-RPC requires a port",
+		},
+		{
+			name: "Kafka 不监听本地端口",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.External = append(cfg.Protocols.External, config.ExternalProtocolConfig{
+					Type:    "Kafka",
+					Enabled: true,
+				})
+			},
+		},
+		{
+			name: "MQ 不监听本地端口",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.External = append(cfg.Protocols.External, config.ExternalProtocolConfig{
+					Type:    "MQ",
+					Enabled: true,
+				})
+			},
+		},
+		{
+			name: "未启用的协议不检查",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.Internal[1].Enabled = false
+				cfg.Protocols.Internal[1].Port = 8081
+			},
+		},
+		{
+			name: "不同网卡的同一端口不冲突",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Network.Host = "10.0.0.5"
+				cfg.Protocols.Internal[1].Host = "127.0.0.1"
+				cfg.Protocols.Internal[1].Port = 8081
+			},
+		},
+		{
+			name: "端口越界",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Protocols.Internal[0].Port = 70000
+			},
+			wantErr: "internal gRPC port 70000 out of range 1-65535: fix framework.protocols.internal[0].port",
+		},
+		{
+			name: "错误信息指出配置路径",
+			modify: func(cfg *config.FrameworkConfig) {
+				cfg.Observability.Metrics.Port = 9002
+			},
+			wantErr: "change framework.protocols.internal[1].port or framework.observability.metrics.port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultFrameworkConfig()
+			tt.modify(cfg)
+			err := validatePorts(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	reg, err := newRegistry(&config.RegistryConfig{Type: "memory", TTL: 5})
+	if err != nil {
+		t.Fatalf("newRegistry failed: %v", err)
+	}
+	defer reg.Close()
+	if _, ok := reg.(*registry.MemoryRegistry); !ok {
+		t.Errorf("Expected MemoryRegistry, got %T", reg)
+	}
+
+	readOnly, err := newRegistry(&config.RegistryConfig{Type: "memory", ReadOnly: true})
+	if err != nil {
+		t.Fatalf("newRegistry failed: %v", err)
+	}
+	defer readOnly.Close()
+	if !registry.IsReadOnly(readOnly) {
+		t.Errorf("Expected read-only registry, got %T", readOnly)
+	}
+
+	mirrored, err := newRegistry(&config.RegistryConfig{Type: "memory", Mirrors: []config.RegistryConfig{{Type: "memory"}}, Quorum: 1})
+	if err != nil {
+		t.Fatalf("newRegistry failed: %v", err)
+	}
+	defer mirrored.Close()
+	if multi, ok := mirrored.(*registry.MultiRegistry); !ok || multi.Quorum() != 1 {
+		t.Errorf("Expected MultiRegistry with quorum 1, got %T", mirrored)
+	}
+
+	if _, err := newRegistry(&config.RegistryConfig{Type: "consul"}); err == nil {
+		t.Error("Expected error for unsupported registry type")
+	}
+	if _, err := newRegistry(&config.RegistryConfig{Type: "memory", Mirrors: []config.RegistryConfig{{Type: "consul"}}}); err == nil {
+		t.Error("Expected error for unsupported mirror type")
+	}
+}
+
+func TestRestCachePolicies(t *testing.T) {
+	policies, err := restCachePolicies(map[string]interface{}{
+		"cache": []interface{}{
+			map[string]interface{}{"method": "catalog.*", "maxAge": "60s", "sharedMaxAge": "5m", "staleWhileRevalidate": "30s"},
+			map[string]interface{}{"method": "user.getProfile", "maxAge": "30s", "private": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("restCachePolicies failed: %v", err)
+	}
+	if len(policies) != 2 || policies[0].MaxAge != time.Minute || policies[0].SharedMaxAge != 5*time.Minute ||
+		policies[0].StaleWhileRevalidate != 30*time.Second || !policies[1].Private {
+		t.Errorf("Unexpected policies: %+v", policies)
+	}
+
+	for _, invalid := range []map[string]interface{}{
+		{"maxAge": "60s"},
+		{"method": "catalog.*", "maxAge": "a minute"},
+	} {
+		if _, err := restCachePolicies(map[string]interface{}{"cache": []interface{}{invalid}}); err == nil {
+			t.Errorf("Expected error for %v", invalid)
+		}
+	}
+}
+
+func TestRestEnvelope(t *testing.T) {
+	envelope, err := restEnvelope(map[string]interface{}{
+		"envelope": map[string]interface{}{"enabled": true, "successCode": 200, "successMessage": "ok", "raw": []interface{}{"files.*"}},
+	})
+	if err != nil {
+		t.Fatalf("restEnvelope failed: %v", err)
+	}
+	if envelope == nil || envelope.SuccessCode != 200 || envelope.SuccessMessage != "ok" || len(envelope.Raw) != 1 || envelope.Raw[0] != "files.*" {
+		t.Errorf("Unexpected envelope: %+v", envelope)
+	}
+	if envelope, _ := restEnvelope(map[string]interface{}{"envelope": map[string]interface{}{"successCode": 200}}); envelope != nil {
+		t.Errorf("Expected nil envelope when not enabled, got %+v", envelope)
+	}
+
+	for _, invalid := range []map[string]interface{}{
+		{"enabled": true, "successCode": "zero"},
+		{"enabled": true, "raw": "files.*"},
+	} {
+		if _, err := restEnvelope(map[string]interface{}{"envelope": invalid}); err == nil {
+			t.Errorf("Expected error for %v", invalid)
+		}
+	}
+}
+
+func TestRestCompression(t *testing.T) {
+	compression, err := restCompression(map[string]interface{}{
+		"compression": map[string]interface{}{"enabled": true, "encodings": []interface{}{"gzip"}, "minSize": "2KB", "maxDecodedSize": "1MB"},
+	})
+	if err != nil {
+		t.Fatalf("restCompression failed: %v", err)
+	}
+	if compression == nil || len(compression.Encodings) != 1 || compression.MinSize != 2048 || compression.MaxDecodedSize != 1<<20 {
+		t.Errorf("Unexpected compression config: %+v", compression)
+	}
+	if compression, _ := restCompression(map[string]interface{}{"compression": map[string]interface{}{"minSize": "1KB"}}); compression != nil {
+		t.Errorf("Expected nil compression when not enabled, got %+v", compression)
+	}
+	if _, err := restCompression(map[string]interface{}{"compression": map[string]interface{}{"enabled": true, "minSize": "lots"}}); err == nil {
+		t.Error("Expected error for invalid minSize")
+	}
+}
+
+func TestRoutingRules(t *testing.T) {
+	now := time.Now()
+	rules := routingRules([]config.RouteConfig{
+		{Service: "payment-service", Methods: []string{"charge"}, Target: "payment-service-dr"},
+		{Service: "order-service", Target: "order-service-dr", Start: now.Add(time.Hour)},
+	})
+	if len(rules) != 2 || rules[0].Schedule != nil || rules[1].Schedule == nil {
+		t.Fatalf("Unexpected rules: %+v", rules)
+	}
+
+	tests := []struct {
+		service, method, want string
+	}{
+		{"payment-service", "charge", "payment-service-dr"},
+		{"payment-service", "refund", ""},
+		// 维护窗口尚未开始
+		{"order-service", "create", ""},
+	}
+	for _, tt := range tests {
+		got := router.ApplyRules(rules, &adapter.InternalRequest{Service: tt.service, Method: tt.method}, now)
+		if got != tt.want {
+			t.Errorf("%s.%s routed to %q, want %q", tt.service, tt.method, got, tt.want)
+		}
+	}
+	if got := router.ApplyRules(rules, &adapter.InternalRequest{Service: "order-service"}, now.Add(2*time.Hour)); got != "order-service-dr" {
+		t.Errorf("Expected redirect during window, got %q", got)
+	}
+}
+
+func TestMqttRateLimits(t *testing.T) {
+	rules, err := mqttRateLimits(map[string]interface{}{
+		"rateLimits": []interface{}{
+			map[string]interface{}{"topic": "devices/+/telemetry", "perDevice": true, "rate": 10, "burst": 20, "action": "Disconnect", "blockFor": "5m"},
+			map[string]interface{}{"topic": "alerts/#", "rate": 0.5, "action": "queue", "maxWait": "2s"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("mqttRateLimits failed: %v", err)
+	}
+	if len(rules) != 2 || !rules[0].PerDevice || rules[0].Rate != 10 || rules[0].Burst != 20 ||
+		rules[0].Action != mqtt.RateLimitDisconnect || rules[0].BlockFor != 5*time.Minute {
+		t.Errorf("Unexpected first rule: %+v", rules)
+	}
+	if rules[1].Rate != 0.5 || rules[1].Action != mqtt.RateLimitQueue || rules[1].MaxWait != 2*time.Second {
+		t.Errorf("Unexpected second rule: %+v", rules[1])
+	}
+
+	for _, invalid := range []map[string]interface{}{
+		{"topic": "devices/+/telemetry", "rate": 10, "action": "kick"},
+		{"topic": "devices/telemetry", "perDevice": true, "rate": 10},
+		{"topic": "devices/#", "rate": 10, "maxWait": "soon"},
+	} {
+		if _, err := mqttRateLimits(map[string]interface{}{"rateLimits": []interface{}{invalid}}); err == nil {
+			t.Errorf("Expected error for %v", invalid)
+		}
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		value  string
+		token  string
+		wantOK bool
+	}{
+		{"Bearer abc", "abc", true},
+		{"bearer abc ", "abc", true},
+		{"Bearer ", "", false},
+		{"Basic abc", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		token, ok := bearerToken(tt.value)
+		if token != tt.token || ok != tt.wantOK {
+			t.Errorf("bearerToken(%q) = %q, %v; want %q, %v", tt.value, token, ok, tt.token, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/internal/rpcbind"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// Register 注册服务对象，其导出方法以 <name>.<首字母小写的方法名> 通过所有已启用的协议提供，须在 Start 之前调用
+//
+// 方法签名须为以下之一，其他导出方法被忽略：
+//
+//	func (s *HelloService) SayHello(ctx context.Context, req *HelloRequest) (*HelloReply, error)
+//	func (s *HelloService) SayHello(ctx context.Context, req HelloRequest) (HelloReply, error)
+//	func (s *HelloService) Ping(ctx context.Context) (string, error)
+//	func (s *HelloService) Notify(ctx context.Context, req *Event) error
+//
+// 具名参数（JSON 对象）解码到请求参数；位置参数（JSON 数组）按请求结构体导出字段的声明顺序绑定，
+// 只有一个对象元素时解码到整个请求参数。请求参数按 validate 标签校验（见 validate 包），
+// 违规时不调用方法，各协议都返回带字段路径的 BadRequest
+//
+// 服务对象实现 IdempotentService 时，其声明的方法按 MarkIdempotent 标记为幂等
+func (s *Server) Register(name string, service interface{}) error {
+	handlers, err := rpcbind.Methods(name, service)
+	if err != nil {
+		return err
+	}
+	for method, handler := range handlers {
+		s.Handle(method, Handler(handler))
+	}
+	if declared, ok := service.(IdempotentService); ok {
+		for _, method := range declared.IdempotentMethods() {
+			if method == "" {
+				continue
+			}
+			method = name + "." + strings.ToLower(method[:1]) + method[1:]
+			if _, ok := handlers[method]; !ok {
+				return fmt.Errorf("idempotent method %s is not a service method", method)
+			}
+			s.MarkIdempotent(method)
+		}
+	}
+	return nil
+}
+
+// IdempotentService 声明幂等方法的服务对象，方法名为 Go 方法名（如 GetUser）或首字母小写的方法名
+type IdempotentService interface {
+	IdempotentMethods() []string
+}
+
+// MarkIdempotent 将方法（<服务>.<方法>）标记为幂等或安全，可以重复执行而不产生额外的副作用，须在 Start 之前调用
+//
+// 幂等方法写入注册信息的 registry.MetadataIdempotentMethods 元数据，各语言 SDK 的客户端只自动重试这些方法
+func (s *Server) MarkIdempotent(methods ...string) {
+	s.methodsMu.Lock()
+	defer s.methodsMu.Unlock()
+	if s.idempotent == nil {
+		s.idempotent = make(map[string]bool)
+	}
+	for _, method := range methods {
+		s.idempotent[method] = true
+	}
+}
+
+// idempotentMethods 返回标记为幂等的方法
+func (s *Server) idempotentMethods() []string {
+	s.methodsMu.RLock()
+	defer s.methodsMu.RUnlock()
+	methods := make([]string, 0, len(s.idempotent))
+	for method := range s.idempotent {
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// dispatch 分发 REST、WebSocket、Kafka 和自定义协议请求，方法名为 <服务>.<方法>
+func (s *Server) dispatch(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+	method := request.Service + "." + request.Method
+	s.methodsMu.RLock()
+	handler, ok := s.methods[method]
+	s.methodsMu.RUnlock()
+	if !ok {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("method %s not found", method))
+	}
+
+	if s.security != nil {
+		authenticated, err := s.authenticate(ctx, request.Headers, method)
+		if err != nil {
+			return nil, err
+		}
+		ctx = authenticated
+	}
+
+	var params interface{}
+	if payload := strings.TrimSpace(string(request.Payload)); payload != "" {
+		if err := json.Unmarshal(request.Payload, &params); err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("invalid params for %s", method))
+		}
+	}
+	return handler(withInternalRequest(ctx, request), params)
+}
@@ -0,0 +1,151 @@
+package framework
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/registry"
+)
+
+type helloRequest struct {
+	Name     string `json:"name"`
+	Times    int    `json:"times" validate:"max=10"`
+	internal string
+	Skipped  string `json:"-"`
+}
+
+type helloReply struct {
+	Message string `json:"message"`
+}
+
+type helloService struct {
+	notified []string
+}
+
+func (h *helloService) SayHello(ctx context.Context, req *helloRequest) (*helloReply, error) {
+	if req.Name == "" {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "name is required")
+	}
+	return &helloReply{Message: "Hello " + req.Name}, nil
+}
+
+func (h *helloService) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+func (h *helloService) Notify(ctx context.Context, event string) error {
+	h.notified = append(h.notified, event)
+	return nil
+}
+
+// 不符合签名要求的导出方法被忽略
+func (h *helloService) Reset()                            {}
+func (h *helloService) Format(name string) string         { return name }
+func (h *helloService) Sum(ctx context.Context, n ...int) {}
+
+func TestRegister(t *testing.T) {
+	s := &Server{methods: make(map[string]Handler), inFlight: lifecycle.NewInFlight(), requests: newRequestTracker()}
+	service := &helloService{}
+	if err := s.Register("hello", service); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	var names []string
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	if len(names) != 3 {
+		t.Fatalf("Expected 3 methods, got %v", names)
+	}
+
+	tests := []struct {
+		name     string
+		service  string
+		method   string
+		payload  string
+		want     interface{}
+		wantCode frameworkerrors.ErrorCode
+	}{
+		{name: "具名参数", service: "hello", method: "sayHello", payload: `{"name":"Go"}`, want: &helloReply{Message: "Hello Go"}},
+		{name: "位置参数", service: "hello", method: "sayHello", payload: `["Go", 2]`, want: &helloReply{Message: "Hello Go"}},
+		{name: "无请求参数", service: "hello", method: "ping", want: "pong"},
+		{name: "只返回错误", service: "hello", method: "notify", payload: `["started"]`},
+		{name: "业务错误", service: "hello", method: "sayHello", payload: `{}`, wantCode: frameworkerrors.BadRequest},
+		{name: "参数类型错误", service: "hello", method: "sayHello", payload: `{"name":1}`, wantCode: frameworkerrors.BadRequest},
+		{name: "参数校验失败", service: "hello", method: "sayHello", payload: `{"name":"Go","times":11}`, wantCode: frameworkerrors.BadRequest},
+		{name: "未注册的方法", service: "hello", method: "reset", wantCode: frameworkerrors.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.dispatch(context.Background(), &adapter.InternalRequest{
+				Service: tt.service,
+				Method:  tt.method,
+				Payload: []byte(tt.payload),
+			})
+			if tt.wantCode != 0 {
+				if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != tt.wantCode {
+					t.Errorf("Expected code %d, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("Result = %#v, want %#v", result, tt.want)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(service.notified, []string{"started"}) {
+		t.Errorf("Notify not called, got %v", service.notified)
+	}
+}
+
+// idempotentHelloService 声明幂等方法的服务对象
+type idempotentHelloService struct {
+	helloService
+	methods []string
+}
+
+func (h *idempotentHelloService) IdempotentMethods() []string {
+	return h.methods
+}
+
+func TestRegisterIdempotent(t *testing.T) {
+	s := &Server{methods: make(map[string]Handler), inFlight: lifecycle.NewInFlight(), requests: newRequestTracker()}
+	if err := s.Register("hello", &idempotentHelloService{methods: []string{"SayHello", "ping"}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	s.MarkIdempotent("admin.status")
+
+	service := &registry.ServiceInfo{Metadata: map[string]string{}}
+	registry.SetIdempotentMethods(service, s.idempotentMethods())
+	if got := service.Metadata[registry.MetadataIdempotentMethods]; got != "admin.status,hello.ping,hello.sayHello" {
+		t.Errorf("Expected idempotent methods metadata, got %q", got)
+	}
+
+	// 声明的方法不是服务方法时返回错误
+	s = &Server{methods: make(map[string]Handler), inFlight: lifecycle.NewInFlight(), requests: newRequestTracker()}
+	if err := s.Register("hello", &idempotentHelloService{methods: []string{"Reset"}}); err == nil {
+		t.Error("Expected error for idempotent method that is not a service method")
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	s := &Server{methods: make(map[string]Handler), inFlight: lifecycle.NewInFlight(), requests: newRequestTracker()}
+	if err := s.Register("", &helloService{}); err == nil {
+		t.Error("Expected error for empty service name")
+	}
+	if err := s.Register("hello", nil); err == nil {
+		t.Error("Expected error for nil service")
+	}
+	if err := s.Register("hello", struct{}{}); err == nil {
+		t.Error("Expected error for service without methods")
+	}
+}
@@ -0,0 +1,329 @@
+// Package frameworktest 提供注册中心和消息路由器的测试替身，供下游服务的单元测试使用
+//
+// MockRegistry 实现 registry.ServiceRegistry，MockRouter 实现 router.MessageRouter；两者都记录每次调用，
+// 可以预设按顺序返回的结果并注入失败，不需要在每个测试套件中手写脆弱的假实现
+package frameworktest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/framework/golang-sdk/registry"
+)
+
+// Call 一次被记录的调用
+type Call struct {
+	Method string        // 方法名，如 "Discover"
+	Args   []interface{} // 除 ctx 外的参数
+	Err    error         // 返回的错误
+}
+
+// discoverResult 预设的 Discover 结果
+type discoverResult struct {
+	services []*registry.ServiceInfo
+	err      error
+}
+
+// MockRegistry 记录调用并支持预设结果的注册中心
+//
+// 没有预设结果时行为与内存注册中心相同：Register 和 Deregister 修改服务列表并同步通知 Watch 的回调，
+// Discover 按 ID 顺序返回已注册的实例。可以并发使用
+type MockRegistry struct {
+	mu       sync.Mutex
+	services map[string]map[string]*registry.ServiceInfo // 服务名 -> 实例 ID -> 实例
+	health   map[string]registry.HealthStatus            // 实例 ID -> 预设的健康状态
+	watchers map[string][]func([]*registry.ServiceInfo)  // 服务名 -> 回调
+	discover map[string][]discoverResult                 // 服务名 -> 按顺序返回的 Discover 结果
+	failures map[string][]error                          // 方法名 -> 按顺序返回的错误
+	errs     map[string]error                            // 方法名 -> 持续返回的错误
+	calls    []Call
+	closed   bool
+}
+
+// NewMockRegistry 创建注册中心替身，services 为初始注册的实例
+func NewMockRegistry(services ...*registry.ServiceInfo) *MockRegistry {
+	m := &MockRegistry{
+		services: make(map[string]map[string]*registry.ServiceInfo),
+		health:   make(map[string]registry.HealthStatus),
+		watchers: make(map[string][]func([]*registry.ServiceInfo)),
+		discover: make(map[string][]discoverResult),
+		failures: make(map[string][]error),
+		errs:     make(map[string]error),
+	}
+	for _, service := range services {
+		m.add(service)
+	}
+	return m
+}
+
+// Register 注册实例并通知监听该服务的回调
+func (m *MockRegistry) Register(ctx context.Context, service *registry.ServiceInfo) error {
+	m.mu.Lock()
+	err := m.injected("Register")
+	if err == nil && (service == nil || service.ID == "") {
+		err = fmt.Errorf("service ID is empty")
+	}
+	m.record("Register", err, service)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	m.add(service)
+	m.mu.Unlock()
+
+	m.Notify(service.Name)
+	return nil
+}
+
+// Deregister 注销实例并通知监听该服务的回调，实例不存在时返回错误
+func (m *MockRegistry) Deregister(ctx context.Context, serviceID string) error {
+	m.mu.Lock()
+	err := m.injected("Deregister")
+	var serviceName string
+	if err == nil {
+		for name, instances := range m.services {
+			if _, ok := instances[serviceID]; ok {
+				serviceName = name
+				delete(instances, serviceID)
+			}
+		}
+		if serviceName == "" {
+			err = fmt.Errorf("service not found: %s", serviceID)
+		}
+	}
+	m.record("Deregister", err, serviceID)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.Notify(serviceName)
+	return nil
+}
+
+// Discover 返回预设的下一个结果，没有预设结果时返回已注册的实例
+func (m *MockRegistry) Discover(ctx context.Context, serviceName string) ([]*registry.ServiceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	services, err := m.discoverLocked(serviceName)
+	m.record("Discover", err, serviceName)
+	return services, err
+}
+
+// HealthCheck 返回 SetHealth 预设的状态，没有预设时已注册的实例为健康，未注册的实例返回错误
+func (m *MockRegistry) HealthCheck(ctx context.Context, serviceID string) (registry.HealthStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, err := registry.HealthStatusUnknown, m.injected("HealthCheck")
+	if err == nil {
+		if s, ok := m.health[serviceID]; ok {
+			status = s
+		} else if m.lookup(serviceID) != nil {
+			status = registry.HealthStatusHealthy
+		} else {
+			err = fmt.Errorf("service not found: %s", serviceID)
+		}
+	}
+	m.record("HealthCheck", err, serviceID)
+	return status, err
+}
+
+// Watch 登记回调，服务变化或调用 Notify 时同步调用
+func (m *MockRegistry) Watch(ctx context.Context, serviceName string, callback func([]*registry.ServiceInfo)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.injected("Watch")
+	if err == nil && callback == nil {
+		err = fmt.Errorf("callback is nil")
+	}
+	m.record("Watch", err, serviceName)
+	if err != nil {
+		return err
+	}
+	m.watchers[serviceName] = append(m.watchers[serviceName], callback)
+	return nil
+}
+
+// Close 标记注册中心已关闭
+func (m *MockRegistry) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.injected("Close")
+	m.record("Close", err)
+	if err == nil {
+		m.closed = true
+	}
+	return err
+}
+
+// SetServices 替换服务的实例列表并通知监听该服务的回调
+func (m *MockRegistry) SetServices(serviceName string, services ...*registry.ServiceInfo) {
+	m.mu.Lock()
+	m.services[serviceName] = make(map[string]*registry.ServiceInfo, len(services))
+	for _, service := range services {
+		m.services[serviceName][service.ID] = service
+	}
+	m.mu.Unlock()
+
+	m.Notify(serviceName)
+}
+
+// QueueDiscover 追加一次 Discover 的预设结果，对该服务的 Discover 按追加顺序逐个返回，用完后恢复返回已注册的实例
+func (m *MockRegistry) QueueDiscover(serviceName string, services []*registry.ServiceInfo, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.discover[serviceName] = append(m.discover[serviceName], discoverResult{services: services, err: err})
+}
+
+// FailNext 使方法（如 "Discover"）的下一次调用返回 err，多次调用时按顺序逐次生效
+func (m *MockRegistry) FailNext(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures[method] = append(m.failures[method], err)
+}
+
+// SetError 使方法的每次调用都返回 err，err 为 nil 时取消
+func (m *MockRegistry) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// SetHealth 预设实例的健康状态
+func (m *MockRegistry) SetHealth(serviceID string, status registry.HealthStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.health[serviceID] = status
+}
+
+// Notify 以服务当前的实例列表同步调用监听该服务的回调，不消耗预设的 Discover 结果
+func (m *MockRegistry) Notify(serviceName string) {
+	m.mu.Lock()
+	callbacks := append([]func([]*registry.ServiceInfo){}, m.watchers[serviceName]...)
+	services := m.registered(serviceName)
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(services)
+	}
+}
+
+// Calls 返回记录的调用，method 不为空时只返回该方法的调用
+func (m *MockRegistry) Calls(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return filterCalls(m.calls, method)
+}
+
+// CallCount 返回方法被调用的次数
+func (m *MockRegistry) CallCount(method string) int {
+	return len(m.Calls(method))
+}
+
+// Reset 清空记录的调用、预设结果和注入的错误，保留已注册的实例和回调
+func (m *MockRegistry) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+	m.health = make(map[string]registry.HealthStatus)
+	m.discover = make(map[string][]discoverResult)
+	m.failures = make(map[string][]error)
+	m.errs = make(map[string]error)
+}
+
+// Closed 判断是否已调用 Close
+func (m *MockRegistry) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.closed
+}
+
+// discoverLocked 返回 Discover 的结果（需要持有锁）
+func (m *MockRegistry) discoverLocked(serviceName string) ([]*registry.ServiceInfo, error) {
+	if err := m.injected("Discover"); err != nil {
+		return nil, err
+	}
+	if queue := m.discover[serviceName]; len(queue) > 0 {
+		m.discover[serviceName] = queue[1:]
+		return queue[0].services, queue[0].err
+	}
+	return m.registered(serviceName), nil
+}
+
+// injected 返回注入到方法的错误，一次性错误优先并被消耗（需要持有锁）
+func (m *MockRegistry) injected(method string) error {
+	if queue := m.failures[method]; len(queue) > 0 {
+		m.failures[method] = queue[1:]
+		return queue[0]
+	}
+	return m.errs[method]
+}
+
+// record 记录调用（需要持有锁）
+func (m *MockRegistry) record(method string, err error, args ...interface{}) {
+	m.calls = append(m.calls, Call{Method: method, Args: args, Err: err})
+}
+
+// add 添加实例（需要持有锁）
+func (m *MockRegistry) add(service *registry.ServiceInfo) {
+	instances := m.services[service.Name]
+	if instances == nil {
+		instances = make(map[string]*registry.ServiceInfo)
+		m.services[service.Name] = instances
+	}
+	instances[service.ID] = service
+}
+
+// lookup 按 ID 查找实例（需要持有锁）
+func (m *MockRegistry) lookup(serviceID string) *registry.ServiceInfo {
+	for _, instances := range m.services {
+		if service, ok := instances[serviceID]; ok {
+			return service
+		}
+	}
+	return nil
+}
+
+// registered 按 ID 顺序返回服务已注册的实例（需要持有锁）
+func (m *MockRegistry) registered(serviceName string) []*registry.ServiceInfo {
+	instances := m.services[serviceName]
+	services := make([]*registry.ServiceInfo, 0, len(instances))
+	for _, service := range instances {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ID < services[j].ID
+	})
+	return services
+}
+
+// filterCalls 返回方法名为 method 的调用，method 为空时返回全部调用的副本
+func filterCalls(calls []Call, method string) []Call {
+	result := make([]Call, 0, len(calls))
+	for _, call := range calls {
+		if method == "" || call.Method == method {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// 确保 MockRegistry 实现 registry.ServiceRegistry
+var _ registry.ServiceRegistry = (*MockRegistry)(nil)
@@ -0,0 +1,140 @@
+package frameworktest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/registry"
+)
+
+func newService(id string, port int) *registry.ServiceInfo {
+	return &registry.ServiceInfo{
+		ID:        id,
+		Name:      "user-service",
+		Address:   "127.0.0.1",
+		Port:      port,
+		Protocols: []string{"gRPC"},
+	}
+}
+
+// TestMockRegistryDiscover 测试预设的 Discover 结果按顺序返回，用完后返回已注册的实例
+func TestMockRegistryDiscover(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockRegistry(newService("user-2", 9002), newService("user-1", 9001))
+	unavailable := errors.New("registry unavailable")
+	m.QueueDiscover("user-service", nil, unavailable)
+	m.QueueDiscover("user-service", []*registry.ServiceInfo{newService("user-9", 9009)}, nil)
+
+	if _, err := m.Discover(ctx, "user-service"); !errors.Is(err, unavailable) {
+		t.Fatalf("expected scripted error, got %v", err)
+	}
+	services, err := m.Discover(ctx, "user-service")
+	if err != nil || len(services) != 1 || services[0].ID != "user-9" {
+		t.Fatalf("expected scripted instance, got %v, %v", services, err)
+	}
+	services, err = m.Discover(ctx, "user-service")
+	if err != nil || len(services) != 2 || services[0].ID != "user-1" || services[1].ID != "user-2" {
+		t.Fatalf("expected registered instances ordered by ID, got %v, %v", services, err)
+	}
+
+	calls := m.Calls("Discover")
+	if len(calls) != 3 || calls[0].Err != unavailable || calls[0].Args[0] != "user-service" {
+		t.Errorf("unexpected recorded calls: %+v", calls)
+	}
+}
+
+// TestMockRegistryFailureInjection 测试一次性错误和持续错误
+func TestMockRegistryFailureInjection(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockRegistry()
+	once, always := errors.New("once"), errors.New("always")
+
+	m.FailNext("Register", once)
+	if err := m.Register(ctx, newService("user-1", 9001)); err != once {
+		t.Fatalf("expected one-shot error, got %v", err)
+	}
+	if err := m.Register(ctx, newService("user-1", 9001)); err != nil {
+		t.Fatalf("expected second Register to succeed, got %v", err)
+	}
+
+	m.SetError("HealthCheck", always)
+	for i := 0; i < 2; i++ {
+		if _, err := m.HealthCheck(ctx, "user-1"); err != always {
+			t.Fatalf("expected persistent error, got %v", err)
+		}
+	}
+	m.SetError("HealthCheck", nil)
+	if status, err := m.HealthCheck(ctx, "user-1"); err != nil || status != registry.HealthStatusHealthy {
+		t.Fatalf("expected healthy, got %s, %v", status, err)
+	}
+	m.SetHealth("user-1", registry.HealthStatusUnhealthy)
+	if status, _ := m.HealthCheck(ctx, "user-1"); status != registry.HealthStatusUnhealthy {
+		t.Errorf("expected scripted unhealthy status, got %s", status)
+	}
+
+	if m.CallCount("Register") != 2 || m.CallCount("HealthCheck") != 4 {
+		t.Errorf("unexpected call counts: %+v", m.Calls(""))
+	}
+	m.Reset()
+	if m.CallCount("") != 0 {
+		t.Errorf("expected no calls after Reset, got %d", m.CallCount(""))
+	}
+}
+
+// TestMockRegistryWatch 测试注册、注销和 SetServices 同步通知监听者
+func TestMockRegistryWatch(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockRegistry()
+	var notified [][]*registry.ServiceInfo
+	if err := m.Watch(ctx, "user-service", func(services []*registry.ServiceInfo) {
+		notified = append(notified, services)
+	}); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	m.Register(ctx, newService("user-1", 9001))
+	m.SetServices("user-service", newService("user-2", 9002), newService("user-3", 9003))
+	if err := m.Deregister(ctx, "user-2"); err != nil {
+		t.Fatalf("Deregister failed: %v", err)
+	}
+	if err := m.Deregister(ctx, "user-2"); err == nil {
+		t.Error("expected error deregistering unknown instance")
+	}
+
+	if len(notified) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(notified))
+	}
+	if len(notified[0]) != 1 || len(notified[1]) != 2 || len(notified[2]) != 1 || notified[2][0].ID != "user-3" {
+		t.Errorf("unexpected notifications: %v", notified)
+	}
+}
+
+// TestMockRegistryWithRegistryRouter 测试 RegistryRouter 在 Discover 失败后恢复并跟随服务变化
+func TestMockRegistryWithRegistryRouter(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockRegistry(newService("user-1", 9001))
+	rr := registry.NewRegistryRouter(m, nil)
+	defer rr.Close()
+
+	request := &adapter.InternalRequest{Service: "user-service", Method: "getUser"}
+	m.FailNext("Discover", errors.New("registry unavailable"))
+	if _, err := rr.Route(ctx, request); err == nil {
+		t.Fatal("expected Route to fail while Discover fails")
+	}
+
+	endpoint, err := rr.Route(ctx, request)
+	if err != nil || endpoint.Port != 9001 {
+		t.Fatalf("expected user-1, got %v, %v", endpoint, err)
+	}
+
+	m.SetServices("user-service", newService("user-2", 9002))
+	endpoint, err = rr.Route(ctx, request)
+	if err != nil || endpoint.Port != 9002 {
+		t.Fatalf("expected user-2 after change, got %v, %v", endpoint, err)
+	}
+	if m.CallCount("Discover") != 2 {
+		t.Errorf("expected routes after the snapshot to skip Discover, got %d calls", m.CallCount("Discover"))
+	}
+}
@@ -0,0 +1,207 @@
+package frameworktest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+)
+
+// routeResult 预设的 Route 结果
+type routeResult struct {
+	endpoint *router.ServiceEndpoint
+	err      error
+}
+
+// MockRouter 记录调用并支持预设结果的消息路由器
+//
+// 没有预设结果时委托给使用轮询负载均衡的 router.DefaultMessageRouter，路由规则和路由表照常生效。可以并发使用
+type MockRouter struct {
+	mu       sync.Mutex
+	router   *router.DefaultMessageRouter
+	routes   map[string][]routeResult // 服务名 -> 按顺序返回的 Route 结果
+	fixed    map[string]routeResult   // 服务名 -> 持续返回的 Route 结果
+	failures map[string][]error       // 方法名 -> 按顺序返回的错误
+	errs     map[string]error         // 方法名 -> 持续返回的错误
+	requests []*adapter.InternalRequest
+	calls    []Call
+}
+
+// NewMockRouter 创建消息路由器替身，services 为初始路由表，可以为 nil
+func NewMockRouter(services map[string][]*router.ServiceEndpoint) *MockRouter {
+	r := &MockRouter{
+		router:   router.NewDefaultMessageRouter(nil),
+		routes:   make(map[string][]routeResult),
+		fixed:    make(map[string]routeResult),
+		failures: make(map[string][]error),
+		errs:     make(map[string]error),
+	}
+	if services != nil {
+		r.router.UpdateRoutingTable(services)
+	}
+	return r
+}
+
+// Route 返回请求服务的预设结果，没有预设结果时由默认路由器路由
+func (r *MockRouter) Route(ctx context.Context, request *adapter.InternalRequest) (*router.ServiceEndpoint, error) {
+	r.mu.Lock()
+	r.requests = append(r.requests, request)
+	err := r.injected("Route")
+	var result *routeResult
+	if err == nil && request != nil {
+		if queue := r.routes[request.Service]; len(queue) > 0 {
+			r.routes[request.Service] = queue[1:]
+			result = &queue[0]
+		} else if fixed, ok := r.fixed[request.Service]; ok {
+			result = &fixed
+		}
+	}
+	r.mu.Unlock()
+
+	var endpoint *router.ServiceEndpoint
+	switch {
+	case err != nil:
+	case result != nil:
+		endpoint, err = result.endpoint, result.err
+	default:
+		endpoint, err = r.router.Route(ctx, request)
+	}
+
+	r.mu.Lock()
+	r.record("Route", err, request)
+	r.mu.Unlock()
+	return endpoint, err
+}
+
+// RegisterRule 注册路由规则
+func (r *MockRouter) RegisterRule(rule *router.RoutingRule) error {
+	return r.delegate("RegisterRule", rule, func() error {
+		return r.router.RegisterRule(rule)
+	})
+}
+
+// UpdateRoutingTable 更新路由表
+func (r *MockRouter) UpdateRoutingTable(services map[string][]*router.ServiceEndpoint) error {
+	return r.delegate("UpdateRoutingTable", services, func() error {
+		return r.router.UpdateRoutingTable(services)
+	})
+}
+
+// GetServiceEndpoints 获取服务的所有端点
+func (r *MockRouter) GetServiceEndpoints(serviceName string) ([]*router.ServiceEndpoint, error) {
+	var endpoints []*router.ServiceEndpoint
+	err := r.delegate("GetServiceEndpoints", serviceName, func() error {
+		var err error
+		endpoints, err = r.router.GetServiceEndpoints(serviceName)
+		return err
+	})
+	return endpoints, err
+}
+
+// SetRoute 使服务的每次 Route 都返回 endpoint 和 err，两者都为 nil 时取消
+func (r *MockRouter) SetRoute(serviceName string, endpoint *router.ServiceEndpoint, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if endpoint == nil && err == nil {
+		delete(r.fixed, serviceName)
+		return
+	}
+	r.fixed[serviceName] = routeResult{endpoint: endpoint, err: err}
+}
+
+// QueueRoute 追加一次 Route 的预设结果，对该服务的 Route 按追加顺序逐个返回，优先于 SetRoute
+func (r *MockRouter) QueueRoute(serviceName string, endpoint *router.ServiceEndpoint, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[serviceName] = append(r.routes[serviceName], routeResult{endpoint: endpoint, err: err})
+}
+
+// FailNext 使方法（如 "Route"）的下一次调用返回 err，多次调用时按顺序逐次生效
+func (r *MockRouter) FailNext(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[method] = append(r.failures[method], err)
+}
+
+// SetError 使方法的每次调用都返回 err，err 为 nil 时取消
+func (r *MockRouter) SetError(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		delete(r.errs, method)
+		return
+	}
+	r.errs[method] = err
+}
+
+// Requests 返回 Route 收到的请求
+func (r *MockRouter) Requests() []*adapter.InternalRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*adapter.InternalRequest(nil), r.requests...)
+}
+
+// Calls 返回记录的调用，method 不为空时只返回该方法的调用
+func (r *MockRouter) Calls(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return filterCalls(r.calls, method)
+}
+
+// CallCount 返回方法被调用的次数
+func (r *MockRouter) CallCount(method string) int {
+	return len(r.Calls(method))
+}
+
+// Reset 清空记录的调用、请求、预设结果和注入的错误，保留路由表和路由规则
+func (r *MockRouter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+	r.requests = nil
+	r.routes = make(map[string][]routeResult)
+	r.fixed = make(map[string]routeResult)
+	r.failures = make(map[string][]error)
+	r.errs = make(map[string]error)
+}
+
+// delegate 注入的错误不为 nil 时直接返回，否则调用 fn，并记录调用
+func (r *MockRouter) delegate(method string, arg interface{}, fn func() error) error {
+	r.mu.Lock()
+	err := r.injected(method)
+	r.mu.Unlock()
+
+	if err == nil {
+		err = fn()
+	}
+
+	r.mu.Lock()
+	r.record(method, err, arg)
+	r.mu.Unlock()
+	return err
+}
+
+// injected 返回注入到方法的错误，一次性错误优先并被消耗（需要持有锁）
+func (r *MockRouter) injected(method string) error {
+	if queue := r.failures[method]; len(queue) > 0 {
+		r.failures[method] = queue[1:]
+		return queue[0]
+	}
+	return r.errs[method]
+}
+
+// record 记录调用（需要持有锁）
+func (r *MockRouter) record(method string, err error, args ...interface{}) {
+	r.calls = append(r.calls, Call{Method: method, Args: args, Err: err})
+}
+
+// 确保 MockRouter 实现 router.MessageRouter
+var _ router.MessageRouter = (*MockRouter)(nil)
@@ -0,0 +1,85 @@
+package frameworktest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+)
+
+// TestMockRouterScriptedRoutes 测试预设结果优先于路由表，用完后由默认路由器路由
+func TestMockRouterScriptedRoutes(t *testing.T) {
+	ctx := context.Background()
+	tableEndpoint := &router.ServiceEndpoint{ServiceId: "order-1", Address: "127.0.0.1", Port: 9001, Protocol: adapter.ProtocolGRPC}
+	r := NewMockRouter(map[string][]*router.ServiceEndpoint{"order-service": {tableEndpoint}})
+
+	queued := &router.ServiceEndpoint{ServiceId: "order-9", Address: "10.0.0.9", Port: 9009}
+	fixed := &router.ServiceEndpoint{ServiceId: "order-8", Address: "10.0.0.8", Port: 9008}
+	r.QueueRoute("order-service", queued, nil)
+	r.SetRoute("order-service", fixed, nil)
+
+	request := &adapter.InternalRequest{Service: "order-service", Method: "create"}
+	for i, expected := range []*router.ServiceEndpoint{queued, fixed, fixed} {
+		endpoint, err := r.Route(ctx, request)
+		if err != nil || endpoint != expected {
+			t.Fatalf("route %d: expected %s, got %v, %v", i, expected.ServiceId, endpoint, err)
+		}
+	}
+
+	r.SetRoute("order-service", nil, nil)
+	endpoint, err := r.Route(ctx, request)
+	if err != nil || endpoint != tableEndpoint {
+		t.Fatalf("expected routing table endpoint, got %v, %v", endpoint, err)
+	}
+	if _, err := r.Route(ctx, &adapter.InternalRequest{Service: "missing"}); err == nil {
+		t.Error("expected error for service without endpoints")
+	}
+
+	if len(r.Requests()) != 5 || r.CallCount("Route") != 5 {
+		t.Errorf("expected 5 recorded routes, got %d requests and %d calls", len(r.Requests()), r.CallCount("Route"))
+	}
+}
+
+// TestMockRouterFailureInjection 测试注入的错误和按服务预设的错误
+func TestMockRouterFailureInjection(t *testing.T) {
+	ctx := context.Background()
+	r := NewMockRouter(nil)
+	injected := errors.New("injected")
+	request := &adapter.InternalRequest{Service: "order-service"}
+
+	r.FailNext("UpdateRoutingTable", injected)
+	if err := r.UpdateRoutingTable(map[string][]*router.ServiceEndpoint{}); err != injected {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if err := r.UpdateRoutingTable(map[string][]*router.ServiceEndpoint{
+		"order-service": {{ServiceId: "order-1", Address: "127.0.0.1", Port: 9001}},
+	}); err != nil {
+		t.Fatalf("UpdateRoutingTable failed: %v", err)
+	}
+
+	r.SetError("Route", injected)
+	if _, err := r.Route(ctx, request); err != injected {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	r.SetError("Route", nil)
+
+	unavailable := &adapter.FrameworkError{Code: adapter.ErrorServiceUnavailable, Message: "down"}
+	r.QueueRoute("order-service", nil, unavailable)
+	if _, err := r.Route(ctx, request); err != unavailable {
+		t.Fatalf("expected scripted error, got %v", err)
+	}
+	if endpoint, err := r.Route(ctx, request); err != nil || endpoint.ServiceId != "order-1" {
+		t.Fatalf("expected order-1, got %v, %v", endpoint, err)
+	}
+
+	calls := r.Calls("Route")
+	if len(calls) != 3 || calls[0].Err != injected || calls[1].Err != unavailable || calls[2].Err != nil {
+		t.Errorf("unexpected recorded calls: %+v", calls)
+	}
+	r.Reset()
+	if r.CallCount("") != 0 || len(r.Requests()) != 0 {
+		t.Error("expected no calls after Reset")
+	}
+}
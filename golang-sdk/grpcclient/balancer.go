@@ -0,0 +1,126 @@
+package grpcclient
+
+import (
+	"sort"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/router"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 以 router.LoadBalancer 实现的 gRPC 负载均衡策略名称，用于 Config.LoadBalancer 或服务配置的 loadBalancingConfig
+const (
+	RoundRobin         = "framework_round_robin"
+	Random             = "framework_random"
+	WeightedRoundRobin = "framework_weighted_round_robin"
+	LeastConnection    = "framework_least_connection"
+)
+
+func init() {
+	RegisterBalancer(RoundRobin, func() router.LoadBalancer { return router.NewRoundRobinLoadBalancer() })
+	RegisterBalancer(Random, func() router.LoadBalancer { return router.NewRandomLoadBalancer() })
+	RegisterBalancer(WeightedRoundRobin, func() router.LoadBalancer { return router.NewWeightedRoundRobinLoadBalancer() })
+	RegisterBalancer(LeastConnection, func() router.LoadBalancer { return router.NewLeastConnectionLoadBalancer() })
+}
+
+// RegisterBalancer 将 router.LoadBalancer 注册为名为 name 的 gRPC 负载均衡策略，只能在 init 中调用
+//
+// 每个连接以 newLoadBalancer 创建一个负载均衡器，每次调用从就绪的实例中选择一个；
+// 负载均衡器实现 ReleaseConnection(endpointId string) 时（如 LeastConnectionLoadBalancer）在调用结束后调用；
+// 实现 router.ResultReporter 时（如 WeightedRoundRobinLoadBalancer）报告调用的延迟和是否因服务端故障失败
+func RegisterBalancer(name string, newLoadBalancer func() router.LoadBalancer) {
+	balancer.Register(&balancerBuilder{name: name, newLoadBalancer: newLoadBalancer})
+}
+
+// balancerBuilder 为每个连接创建使用独立负载均衡器的 base 负载均衡器
+type balancerBuilder struct {
+	name            string
+	newLoadBalancer func() router.LoadBalancer
+}
+
+// Name 返回负载均衡策略名称
+func (b *balancerBuilder) Name() string {
+	return b.name
+}
+
+// Build 创建连接的负载均衡器，连接管理和健康检查由 base 负载均衡器完成
+func (b *balancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pb := &pickerBuilder{loadBalancer: b.newLoadBalancer()}
+	return base.NewBalancerBuilder(b.name, pb, base.Config{HealthCheck: true}).Build(cc, opts)
+}
+
+// pickerBuilder 在就绪实例变化时创建选择器，负载均衡器的状态（如加权轮询的当前权重）跨选择器保留
+type pickerBuilder struct {
+	loadBalancer router.LoadBalancer
+}
+
+// Build 以就绪的实例创建选择器
+func (pb *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &picker{
+		loadBalancer: pb.loadBalancer,
+		endpoints:    make([]*router.ServiceEndpoint, 0, len(info.ReadySCs)),
+		subConns:     make(map[*router.ServiceEndpoint]balancer.SubConn, len(info.ReadySCs)),
+	}
+	for sc, sci := range info.ReadySCs {
+		endpoint := endpointOf(sci.Address)
+		p.endpoints = append(p.endpoints, endpoint)
+		p.subConns[endpoint] = sc
+	}
+	// 固定实例顺序，轮询类负载均衡器在选择器重建后保持均匀
+	sort.Slice(p.endpoints, func(i, j int) bool { return p.endpoints[i].ServiceId < p.endpoints[j].ServiceId })
+	return p
+}
+
+// connectionReleaser 在调用结束后释放连接计数的负载均衡器
+type connectionReleaser interface {
+	ReleaseConnection(endpointId string)
+}
+
+// picker 以负载均衡器从就绪实例中选择每次调用使用的连接
+type picker struct {
+	loadBalancer router.LoadBalancer
+	endpoints    []*router.ServiceEndpoint
+	subConns     map[*router.ServiceEndpoint]balancer.SubConn
+}
+
+// Pick 选择调用使用的连接，负载均衡器返回错误时调用以 Unavailable 失败
+func (p *picker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	endpoint, err := p.loadBalancer.Select(p.endpoints)
+	if err != nil {
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable, "failed to select endpoint: %v", err)
+	}
+	sc, ok := p.subConns[endpoint]
+	if !ok {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	result := balancer.PickResult{SubConn: sc}
+	releaser, releases := p.loadBalancer.(connectionReleaser)
+	reporter, reports := p.loadBalancer.(router.ResultReporter)
+	if releases || reports {
+		start := time.Now()
+		result.Done = func(info balancer.DoneInfo) {
+			if releases {
+				releaser.ReleaseConnection(endpoint.ServiceId)
+			}
+			if reports {
+				reporter.ReportResult(endpoint, time.Since(start), serverFailure(info.Err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// serverFailure 判断调用错误是否表示服务端故障，业务错误不降低端点的权重
+func serverFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted, codes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+// Package grpcclient 从注册中心发现服务实例并在客户端负载均衡的 gRPC 客户端
+//
+// NewResolverBuilder 返回与 grpc-go 名称解析兼容的解析器，将 framework:///<服务名> 解析为注册中心中支持 gRPC 的实例；
+// RegisterBalancer 将 router.LoadBalancer 注册为 gRPC 负载均衡策略，包引入时已注册框架内置的四种策略。
+// Dial 组合二者，并以 gRPC 服务配置设置等待就绪和重试，生成的客户端存根可直接使用返回的连接
+package grpcclient
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// maxRetryAttempts gRPC 重试策略允许的最大尝试次数，超过时 gRPC 按该值处理
+const maxRetryAttempts = 5
+
+// Config 客户端配置
+type Config struct {
+	// Registry 服务注册中心，为 nil 时目标为 grpc-go 能解析的地址，如 localhost:9091、dns:///greeter.internal:9091
+	Registry registry.ServiceRegistry
+	// LoadBalancer 负载均衡策略：round_robin（默认）、random、weighted_round_robin、least_connection，
+	// 或以 RegisterBalancer 注册的策略名称
+	LoadBalancer string
+	// TLSConfig 不为 nil 时以 TLS 连接（如 mTLS 引导提供的客户端配置），否则使用明文连接
+	TLSConfig *tls.Config
+	// WaitForReady 为 true 时没有就绪实例的调用等待实例就绪，直到调用的截止时间，而不是立即返回 Unavailable
+	WaitForReady bool
+	// Retry 不为 nil 时重试返回 Unavailable 的调用，转换为 gRPC 服务配置的 retryPolicy，尝试次数最多为 5
+	Retry *resilience.RetryPolicy
+	// DialOptions 附加的连接选项，如拦截器
+	DialOptions []grpc.DialOption
+}
+
+// Dial 创建连接到 service 的客户端连接，config 为 nil 时使用默认配置
+//
+// 配置了 Registry 时 service 为服务名称，从注册中心解析实例并按 LoadBalancer 在实例间分配调用；
+// 连接在后台建立，不等待实例就绪。负载均衡策略未注册时返回错误
+//
+//	conn, err := grpcclient.Dial("greeter", &grpcclient.Config{Registry: reg, WaitForReady: true})
+//	reply, err := hellopb.NewGreeterClient(conn).SayHello(ctx, &hellopb.HelloRequest{Name: name})
+func Dial(service string, config *Config) (*grpc.ClientConn, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	serviceConfig, err := newServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	target := service
+	opts := []grpc.DialOption{grpc.WithDefaultServiceConfig(serviceConfig)}
+	if config.Registry != nil {
+		target = Scheme + ":///" + service
+		opts = append(opts, grpc.WithResolvers(NewResolverBuilder(config.Registry)))
+	}
+	if config.TLSConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(config.TLSConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	opts = append(opts, config.DialOptions...)
+
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial gRPC service %s: %w", service, err)
+	}
+	return conn, nil
+}
+
+// balancerName 返回负载均衡策略的注册名称，框架内置策略可省略 framework_ 前缀
+func balancerName(name string) (string, error) {
+	switch name {
+	case "", "round_robin":
+		return RoundRobin, nil
+	case "random":
+		return Random, nil
+	case "weighted_round_robin":
+		return WeightedRoundRobin, nil
+	case "least_connection":
+		return LeastConnection, nil
+	}
+	if balancer.Get(name) == nil {
+		return "", fmt.Errorf("unknown load balancer %q: use round_robin, random, weighted_round_robin, least_connection or a name registered with RegisterBalancer", name)
+	}
+	return name, nil
+}
+
+// serviceConfig gRPC 服务配置中客户端使用的部分
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+	MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+}
+
+// methodConfig 适用于所有方法的调用配置
+type methodConfig struct {
+	Name         []struct{}   `json:"name"`
+	WaitForReady bool         `json:"waitForReady,omitempty"`
+	RetryPolicy  *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// retryPolicy gRPC 重试策略
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// newServiceConfig 按配置生成 gRPC 服务配置
+func newServiceConfig(config *Config) (string, error) {
+	name, err := balancerName(config.LoadBalancer)
+	if err != nil {
+		return "", err
+	}
+	sc := serviceConfig{LoadBalancingConfig: []map[string]struct{}{{name: {}}}}
+
+	method := methodConfig{Name: []struct{}{{}}, WaitForReady: config.WaitForReady}
+	// 只尝试一次的策略不需要重试，gRPC 也不接受 maxAttempts 小于 2 的重试策略
+	if policy := config.Retry; policy != nil && policy.MaxAttempts > 1 {
+		method.RetryPolicy = &retryPolicy{
+			MaxAttempts:          min(policy.MaxAttempts, maxRetryAttempts),
+			InitialBackoff:       durationString(policy.InitialDelay, 100*time.Millisecond),
+			MaxBackoff:           durationString(max(policy.MaxDelay, policy.InitialDelay), 5*time.Second),
+			BackoffMultiplier:    policy.Multiplier,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}
+		if method.RetryPolicy.BackoffMultiplier <= 0 {
+			method.RetryPolicy.BackoffMultiplier = 1
+		}
+	}
+	if method.WaitForReady || method.RetryPolicy != nil {
+		sc.MethodConfig = []methodConfig{method}
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// durationString 将时长格式化为服务配置的秒数，如 0.1s；不大于 0 时使用 fallback
+func durationString(d, fallback time.Duration) string {
+	if d <= 0 {
+		d = fallback
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,139 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startInstance 启动带健康检查服务的 gRPC 实例，返回端口和收到的调用数
+func startInstance(t *testing.T) (int, *atomic.Int32) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	calls := &atomic.Int32{}
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		calls.Add(1)
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().(*net.TCPAddr).Port, calls
+}
+
+// TestDialBalancesAcrossInstances 测试从注册中心解析实例、在实例间轮询，并在实例下线后不再调用
+func TestDialBalancesAcrossInstances(t *testing.T) {
+	reg := registry.NewMemoryRegistry(nil)
+	defer reg.Close()
+	ctx := context.Background()
+
+	ports := make([]int, 2)
+	counters := make([]*atomic.Int32, 2)
+	for i, id := range []string{"greeter-1", "greeter-2"} {
+		ports[i], counters[i] = startInstance(t)
+		if err := reg.Register(ctx, &registry.ServiceInfo{
+			ID: id, Name: "greeter", Address: "127.0.0.1", Port: ports[i], Protocols: []string{"grpc"},
+		}); err != nil {
+			t.Fatalf("Failed to register %s: %v", id, err)
+		}
+	}
+	// 不支持 gRPC 的实例不参与负载均衡
+	if err := reg.Register(ctx, &registry.ServiceInfo{
+		ID: "greeter-rest", Name: "greeter", Address: "127.0.0.1", Port: 1, Protocols: []string{"rest"},
+	}); err != nil {
+		t.Fatalf("Failed to register REST instance: %v", err)
+	}
+
+	conn, err := Dial("greeter", &Config{Registry: reg, WaitForReady: true})
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	check := func() {
+		t.Helper()
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if _, err := client.Check(callCtx, &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+	}
+
+	// 两个实例都就绪后调用在实例间分配
+	deadline := time.Now().Add(5 * time.Second)
+	for counters[0].Load() == 0 || counters[1].Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected calls on both instances, got %d and %d", counters[0].Load(), counters[1].Load())
+		}
+		check()
+	}
+
+	// 实例下线后只调用剩余的实例
+	if err := reg.Deregister(ctx, "greeter-1"); err != nil {
+		t.Fatalf("Failed to deregister: %v", err)
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		before := counters[0].Load()
+		for i := 0; i < 4; i++ {
+			check()
+		}
+		if counters[0].Load() == before {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected deregistered instance to stop receiving calls")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestNewServiceConfig 测试负载均衡策略和重试策略转换为 gRPC 服务配置
+func TestNewServiceConfig(t *testing.T) {
+	data, err := newServiceConfig(&Config{
+		LoadBalancer: "weighted_round_robin",
+		WaitForReady: true,
+		Retry:        resilience.NewRetryPolicy(8, 50*time.Millisecond, time.Second, 2),
+	})
+	if err != nil {
+		t.Fatalf("newServiceConfig failed: %v", err)
+	}
+	var sc serviceConfig
+	if err := json.Unmarshal([]byte(data), &sc); err != nil {
+		t.Fatalf("Invalid service config %s: %v", data, err)
+	}
+	if _, ok := sc.LoadBalancingConfig[0][WeightedRoundRobin]; !ok {
+		t.Errorf("loadBalancingConfig = %v, want %s", sc.LoadBalancingConfig, WeightedRoundRobin)
+	}
+	method := sc.MethodConfig[0]
+	if !method.WaitForReady || method.RetryPolicy == nil {
+		t.Fatalf("methodConfig = %+v, want waitForReady and retryPolicy", method)
+	}
+	if method.RetryPolicy.MaxAttempts != maxRetryAttempts || method.RetryPolicy.InitialBackoff != "0.05s" || method.RetryPolicy.MaxBackoff != "1s" {
+		t.Errorf("retryPolicy = %+v", method.RetryPolicy)
+	}
+
+	// 默认只设置负载均衡策略
+	data, _ = newServiceConfig(&Config{})
+	if data != `{"loadBalancingConfig":[{"framework_round_robin":{}}]}` {
+		t.Errorf("default service config = %s", data)
+	}
+
+	if _, err := newServiceConfig(&Config{LoadBalancer: "fastest"}); err == nil || !strings.Contains(err.Error(), "unknown load balancer") {
+		t.Errorf("Expected unknown load balancer error, got %v", err)
+	}
+}
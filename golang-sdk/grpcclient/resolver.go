@@ -0,0 +1,151 @@
+package grpcclient
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme 从注册中心解析服务的目标 scheme，目标格式为 framework:///<服务名>
+const Scheme = "framework"
+
+// NewResolverBuilder 创建从注册中心解析服务实例的 gRPC 名称解析器
+//
+// 解析器只返回支持 gRPC 的实例，端口为实例元数据中 gRPC 的端口（port.grpc）；监听服务变化并在实例上下线时更新地址。
+// 以 grpc.WithResolvers 传给连接，或在 init 中以 resolver.Register 注册为全局解析器
+func NewResolverBuilder(reg registry.ServiceRegistry) resolver.Builder {
+	return &resolverBuilder{registry: reg}
+}
+
+// resolverBuilder 注册中心名称解析器的构建器
+type resolverBuilder struct {
+	registry registry.ServiceRegistry
+}
+
+// Scheme 返回解析器处理的 scheme
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// Build 开始监听目标服务并查询当前实例
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	service := strings.TrimPrefix(target.Endpoint(), "/")
+	if service == "" {
+		return nil, fmt.Errorf("grpc target %s has no service name", target.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &registryResolver{registry: b.registry, service: service, cc: cc, ctx: ctx, cancel: cancel}
+	// 先监听再查询，查询期间的变化不会丢失
+	if err := b.registry.Watch(ctx, service, r.update); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to watch service %s: %w", service, err)
+	}
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	return r, nil
+}
+
+// registryResolver 一个连接对目标服务的名称解析
+type registryResolver struct {
+	registry registry.ServiceRegistry
+	service  string
+	cc       resolver.ClientConn
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	mu sync.Mutex
+	// version 每次以监听结果更新地址时递增，查询期间有更新时丢弃查询结果
+	version int
+}
+
+// ResolveNow 重新查询服务实例，连接失败时由 gRPC 调用
+func (r *registryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.mu.Lock()
+	version := r.version
+	r.mu.Unlock()
+
+	go func() {
+		services, err := r.registry.Discover(r.ctx, r.service)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.ctx.Err() != nil || r.version != version {
+			return
+		}
+		if err != nil {
+			r.cc.ReportError(fmt.Errorf("failed to discover service %s: %w", r.service, err))
+			return
+		}
+		r.updateLocked(services)
+	}()
+}
+
+// Close 停止监听，注册中心不移除回调时之后的回调被忽略
+func (r *registryResolver) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancel()
+}
+
+// update 以监听到的服务实例更新地址
+func (r *registryResolver) update(services []*registry.ServiceInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ctx.Err() != nil {
+		return
+	}
+	r.version++
+	r.updateLocked(services)
+}
+
+// updateLocked 将服务实例转换为地址并交给连接（需要持有锁）
+//
+// 没有支持 gRPC 的实例时同样更新为空地址列表，负载均衡器关闭已下线实例的连接
+func (r *registryResolver) updateLocked(services []*registry.ServiceInfo) {
+	endpoints := registry.ProtocolEndpoints(services, adapter.ProtocolGRPC)
+	addresses := make([]resolver.Address, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		addresses = append(addresses, resolver.Address{
+			Addr:       net.JoinHostPort(endpoint.Address, strconv.Itoa(endpoint.Port)),
+			Attributes: attributes.New(endpointKey{}, endpointAttribute{endpoint}),
+		})
+	}
+	_ = r.cc.UpdateState(resolver.State{Addresses: addresses})
+}
+
+// endpointKey 地址属性中服务端点的键
+type endpointKey struct{}
+
+// endpointAttribute 地址属性中的服务端点，实例和元数据不变时视为同一地址，gRPC 复用已建立的连接
+type endpointAttribute struct {
+	endpoint *router.ServiceEndpoint
+}
+
+// Equal 比较实例 ID 和元数据，元数据（如 weight）变化时重新建立连接以使用新的元数据
+func (a endpointAttribute) Equal(o any) bool {
+	other, ok := o.(endpointAttribute)
+	return ok && a.endpoint.ServiceId == other.endpoint.ServiceId && maps.Equal(a.endpoint.Metadata, other.endpoint.Metadata)
+}
+
+// endpointOf 返回地址对应的服务端点，其他解析器返回的地址以 host:port 作为实例 ID
+func endpointOf(address resolver.Address) *router.ServiceEndpoint {
+	if attr, ok := address.Attributes.Value(endpointKey{}).(endpointAttribute); ok {
+		return attr.endpoint
+	}
+	endpoint := &router.ServiceEndpoint{ServiceId: address.Addr, Address: address.Addr, Protocol: adapter.ProtocolGRPC}
+	if host, port, err := net.SplitHostPort(address.Addr); err == nil {
+		endpoint.Address = host
+		endpoint.Port, _ = strconv.Atoi(port)
+	}
+	return endpoint
+}
@@ -0,0 +1,146 @@
+// Package httpclient 框架的出站 HTTP 客户端，供 webhook、REST 透传和 OIDC 元数据获取等调用外部 HTTP 服务的场景使用
+//
+// Client 在标准 http.Client 之上统一提供请求签名、代理、单次尝试超时、按目标主机熔断、按重试策略重试、
+// 追踪上下文传播和 Prometheus 指标；HTTPClient 返回的 *http.Client 可以交给只接受标准客户端的第三方库
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+)
+
+// DefaultTimeout 单次尝试的默认超时
+const DefaultTimeout = 10 * time.Second
+
+// ProxyDirect 作为 Config.Proxy 时不使用代理，也不读取代理环境变量
+const ProxyDirect = "direct"
+
+// HeaderIdempotencyKey 幂等键请求头，带该请求头的非幂等方法请求也按重试策略重试
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Config 客户端配置
+type Config struct {
+	// Name 客户端名称，作为指标的 client 标签和客户端 span 的服务名，如 webhook、oidc
+	Name string
+	// Timeout 单次尝试的超时，为 0 时使用 DefaultTimeout；包括重试在内的总耗时由请求的 context 控制
+	Timeout time.Duration
+	// Proxy 代理地址，如 http://proxy.internal:3128；为空时按 HTTP_PROXY、HTTPS_PROXY、NO_PROXY 环境变量选择代理，
+	// 为 ProxyDirect 时直连
+	Proxy string
+	// NoProxy 不经过 Proxy 的主机，以 . 开头时匹配该域名的子域名；只在 Proxy 为代理地址时生效
+	NoProxy []string
+	// TLSConfig 访问 HTTPS 服务的 TLS 配置，如服务实例间的 mTLS 客户端证书
+	TLSConfig *tls.Config
+	// Signer 不为 nil 时每次尝试以 security.SignatureHeader 请求头对请求体签名，接收方以 PayloadSigner.VerifyRequest 校验
+	Signer *security.PayloadSigner
+	// Retry 重试策略，为 nil 时不重试；只重试幂等方法（GET、HEAD、OPTIONS、PUT、DELETE）和带 HeaderIdempotencyKey 的请求
+	Retry *resilience.RetryPolicy
+	// Breaker 为每个目标主机（host:port）创建熔断器，为 nil 时不熔断；连接失败、超时和 5xx 响应计为失败
+	Breaker func(host string) *resilience.CircuitBreaker
+	// Headers 每个请求默认携带的请求头，如 User-Agent；请求已设置的同名请求头优先
+	Headers map[string]string
+	// Transport 底层传输，为 nil 时按 Proxy 和 TLSConfig 创建；设置后忽略 Proxy、NoProxy 和 TLSConfig，
+	// 如测试时使用 memory.Listen 返回的监听器的 HTTPClient().Transport
+	Transport http.RoundTripper
+}
+
+// Client 出站 HTTP 客户端，可在多个协程中并发使用
+type Client struct {
+	http *http.Client
+}
+
+// New 创建出站 HTTP 客户端，config 为 nil 时使用默认配置；Proxy 不是合法的代理地址时返回错误
+func New(config *Config) (*Client, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	t, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{http: &http.Client{Transport: t}}, nil
+}
+
+// HTTPClient 返回以该客户端的签名、重试、熔断和指标发送请求的标准客户端
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// Do 发送请求；连接失败、超时和熔断返回的错误可经 errors.FromError 取得框架错误（ConnectionError、Timeout、ServiceUnavailable），
+// 非 2xx 响应不视为错误，由调用方检查状态码
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.http.Do(req)
+}
+
+// Get 发送 GET 请求
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to create request")
+	}
+	return c.Do(req)
+}
+
+// PostJSON 以 JSON 编码 body 后发送 POST 请求
+func (c *Client) PostJSON(ctx context.Context, url string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to serialize request body")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.Do(req)
+}
+
+// proxyFunc 按配置返回选择代理的函数，返回 nil 时直连
+func proxyFunc(config *Config) (func(*http.Request) (*url.URL, error), error) {
+	switch config.Proxy {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case ProxyDirect:
+		return nil, nil
+	}
+	proxy, err := url.Parse(config.Proxy)
+	if err != nil || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %q", config.Proxy)
+	}
+	noProxy := config.NoProxy
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxy, nil
+	}, nil
+}
+
+// bypassProxy 判断主机是否在 NoProxy 中
+func bypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "."):
+			if strings.HasSuffix(host, entry) || host == entry[1:] {
+				return true
+			}
+		case host == entry:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+)
+
+// TestClientSignsRequests 测试每个请求都带签名，接收方可以校验
+func TestClientSignsRequests(t *testing.T) {
+	signer, err := security.NewPayloadSigner(&security.SignatureConfig{Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	verifier, _ := security.NewPayloadSigner(&security.SignatureConfig{Secret: "s3cret"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := verifier.VerifyRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("User-Agent") != "framework" {
+			http.Error(w, "missing default header", http.StatusBadRequest)
+			return
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client, err := New(&Config{Name: "test", Signer: signer, Headers: map[string]string{"User-Agent": "framework"}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	resp, err := client.PostJSON(context.Background(), server.URL, map[string]string{"event": "created"})
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected signed request to be accepted, got status %d", resp.StatusCode)
+	}
+}
+
+// TestClientRetry 测试幂等请求按重试策略重试，非幂等请求只在带幂等键时重试
+func TestClientRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := New(&Config{Retry: resilience.NewRetryPolicy(3, time.Millisecond, 10*time.Millisecond, 2)})
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("expected GET to succeed on the third attempt, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	resp, err = client.PostJSON(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("expected POST not to be retried, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+	req.Header.Set(HeaderIdempotencyKey, "order-7")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("expected POST with idempotency key to be retried, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+// TestClientErrors 测试超时和熔断返回框架错误
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := New(&Config{
+		Timeout: 50 * time.Millisecond,
+		Breaker: func(host string) *resilience.CircuitBreaker {
+			return resilience.NewCircuitBreaker(host, 2, 1, time.Minute)
+		},
+	})
+
+	_, err := client.Get(context.Background(), server.URL+"/slow")
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.Timeout {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+
+	// 超时和 5xx 响应计为失败，熔断器打开后不再发送请求
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	_, err = client.Get(context.Background(), server.URL)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ServiceUnavailable {
+		t.Errorf("expected circuit breaker error, got %v", err)
+	}
+}
+
+// TestProxy 测试代理选择
+func TestProxy(t *testing.T) {
+	if _, err := New(&Config{Proxy: "://bad"}); err == nil {
+		t.Error("expected invalid proxy address to be rejected")
+	}
+
+	proxy, err := proxyFunc(&Config{Proxy: "http://proxy.internal:3128", NoProxy: []string{"localhost", ".svc.cluster.local"}})
+	if err != nil {
+		t.Fatalf("Failed to create proxy func: %v", err)
+	}
+	tests := []struct {
+		target string
+		direct bool
+	}{
+		{"https://api.example.com/hook", false},
+		{"http://localhost:8080/", true},
+		{"http://order.default.svc.cluster.local/", true},
+		{"http://svc.cluster.local.evil.com/", false},
+	}
+	for _, tt := range tests {
+		target, _ := url.Parse(tt.target)
+		got, _ := proxy(&http.Request{URL: target})
+		if (got == nil) != tt.direct {
+			t.Errorf("%s: expected direct=%v, got proxy %v", tt.target, tt.direct, got)
+		}
+	}
+
+	direct, _ := proxyFunc(&Config{Proxy: ProxyDirect})
+	if direct != nil {
+		t.Error("expected no proxy func for direct connections")
+	}
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	clientMetricsOnce sync.Once
+	// 出站请求计数器
+	requestTotal *prometheus.CounterVec
+	// 出站请求耗时直方图，包括重试
+	requestDuration *prometheus.HistogramVec
+	// 重试计数器
+	retryTotal *prometheus.CounterVec
+)
+
+// initClientMetrics 初始化出站 HTTP 客户端指标
+func initClientMetrics() {
+	clientMetricsOnce.Do(func() {
+		requestTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_http_client_requests_total",
+				Help: "Total number of outbound HTTP requests",
+			},
+			[]string{"client", "host", "method", "status", "error_code"},
+		)
+		requestDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_http_client_request_duration_seconds",
+				Help:    "Outbound HTTP request duration in seconds, including retries",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"client", "host", "method"},
+		)
+		retryTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_http_client_retries_total",
+				Help: "Total number of outbound HTTP request retries",
+			},
+			[]string{"client", "host"},
+		)
+	})
+}
+
+// recordRequest 记录一次请求的最终结果，status 为响应状态码，没有响应时为空
+func recordRequest(client, host, method string, resp *http.Response, err error, duration time.Duration) {
+	initClientMetrics()
+
+	var status string
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	requestTotal.WithLabelValues(client, host, method, status, adapter.ErrorCodeLabel(err)).Inc()
+	requestDuration.WithLabelValues(client, host, method).Observe(duration.Seconds())
+}
+
+// recordRetry 记录一次重试
+func recordRetry(client, host string) {
+	initClientMetrics()
+	retryTotal.WithLabelValues(client, host).Inc()
+}
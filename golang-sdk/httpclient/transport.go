@@ -0,0 +1,255 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+)
+
+// transport 在底层传输之上签名、重试、熔断并记录指标
+type transport struct {
+	name    string
+	base    http.RoundTripper
+	timeout time.Duration
+	signer  *security.PayloadSigner
+	retry   *resilience.RetryPolicy
+	headers map[string]string
+
+	newBreaker func(host string) *resilience.CircuitBreaker
+	mu         sync.Mutex
+	breakers   map[string]*resilience.CircuitBreaker
+}
+
+// newTransport 按配置创建传输
+func newTransport(config *Config) (*transport, error) {
+	base := config.Transport
+	if base == nil {
+		proxy, err := proxyFunc(config)
+		if err != nil {
+			return nil, err
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = proxy
+		if config.TLSConfig != nil {
+			t.TLSClientConfig = config.TLSConfig
+		}
+		base = t
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &transport{
+		name:       config.Name,
+		base:       base,
+		timeout:    timeout,
+		signer:     config.Signer,
+		retry:      config.Retry,
+		headers:    config.Headers,
+		newBreaker: config.Breaker,
+		breakers:   make(map[string]*resilience.CircuitBreaker),
+	}, nil
+}
+
+// RoundTrip 发送请求，按重试策略重试失败的尝试
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	host := req.URL.Host
+
+	// 签名和重试需要请求体的副本
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to read request body")
+		}
+		body = data
+	}
+
+	ctx, span := adapter.StartClientSpan(req.Context(), adapter.ProtocolREST, t.name, req.Method, host)
+	resp, err := t.roundTrip(ctx, req, body, host)
+	adapter.EndSpan(span, err)
+	recordRequest(t.name, host, req.Method, resp, err, time.Since(start))
+	return resp, err
+}
+
+// roundTrip 依次尝试，直到成功、错误不可重试、达到最大尝试次数或 ctx 结束
+func (t *transport) roundTrip(ctx context.Context, req *http.Request, body []byte, host string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(ctx, req, body, host)
+		if t.retry == nil || attempt >= t.retry.MaxAttempts || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.retry.CalculateDelay(attempt - 1)
+		if after, ok := retryAfter(resp); ok && after > delay {
+			delay = after
+			if t.retry.MaxDelay > 0 && delay > t.retry.MaxDelay {
+				delay = t.retry.MaxDelay
+			}
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		recordRetry(t.name, host)
+	}
+}
+
+// attempt 经熔断器发送一次请求，响应体关闭时结束该次尝试的超时
+func (t *transport) attempt(ctx context.Context, req *http.Request, body []byte, host string) (*http.Response, error) {
+	breaker := t.breaker(host)
+	if breaker != nil && !breaker.AllowRequest() {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable,
+			fmt.Sprintf("circuit breaker for %s is open", host))
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	out := req.Clone(attemptCtx)
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	for key, value := range t.headers {
+		if out.Header.Get(key) == "" {
+			out.Header.Set(key, value)
+		}
+	}
+	traceHeaders := make(map[string]string)
+	adapter.InjectTraceContext(attemptCtx, traceHeaders)
+	for key, value := range traceHeaders {
+		if out.Header.Get(key) == "" {
+			out.Header.Set(key, value)
+		}
+	}
+	if t.signer != nil {
+		// 每次尝试重新签名，重试的请求使用新的时间戳
+		t.signer.SignRequest(out, body)
+	}
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		cancel()
+		err = attemptError(ctx, attemptCtx, err)
+		// 调用方取消的请求不计入熔断
+		if breaker != nil && ctx.Err() == nil {
+			breaker.RecordFailure()
+		}
+		return nil, err
+	}
+	if breaker != nil {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			breaker.RecordFailure()
+		} else {
+			breaker.RecordSuccess()
+		}
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// breaker 返回目标主机的熔断器，未配置熔断时返回 nil
+func (t *transport) breaker(host string) *resilience.CircuitBreaker {
+	if t.newBreaker == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	breaker, ok := t.breakers[host]
+	if !ok {
+		breaker = t.newBreaker(host)
+		t.breakers[host] = breaker
+	}
+	return breaker
+}
+
+// shouldRetry 判断失败的尝试是否重试：请求可以安全重放，且错误或响应状态按重试策略可重试
+func (t *transport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		fe, ok := frameworkerrors.FromError(err)
+		return ok && t.retry.IsRetryable(fe.Code)
+	}
+	return t.retry.IsRetryable(statusCode(resp.StatusCode))
+}
+
+// idempotent 判断请求是否可以安全重放
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(HeaderIdempotencyKey) != ""
+}
+
+// statusCode 将响应状态映射为错误码，成功响应返回 0；网关错误和限流视为 ServiceUnavailable
+func statusCode(status int) frameworkerrors.ErrorCode {
+	switch {
+	case status < http.StatusBadRequest:
+		return 0
+	case status == http.StatusTooManyRequests, status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout:
+		return frameworkerrors.ServiceUnavailable
+	default:
+		return frameworkerrors.FromHTTPStatus(status)
+	}
+}
+
+// retryAfter 解析响应的 Retry-After 秒数
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// attemptError 将传输错误转换为框架错误：调用方的 ctx 结束时按 ContextErrorCode，单次尝试超时为 Timeout，其他为 ConnectionError
+func attemptError(ctx, attemptCtx context.Context, err error) error {
+	if ctx.Err() != nil {
+		code, _ := frameworkerrors.ContextErrorCode(ctx.Err())
+		return frameworkerrors.Wrap(err, code, "request canceled")
+	}
+	if errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return frameworkerrors.Wrap(err, frameworkerrors.Timeout, "request timed out")
+	}
+	return frameworkerrors.Wrap(err, frameworkerrors.ConnectionError, "request failed")
+}
+
+// cancelOnClose 关闭响应体时取消该次尝试的 context
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
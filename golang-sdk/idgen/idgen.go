@@ -0,0 +1,142 @@
+// Package idgen 生成追踪 ID、span ID 和请求 ID
+//
+// 框架默认按 W3C Trace Context 生成随机 ID；选择 uuidv7 或 snowflake 后追踪 ID 和请求 ID 按生成时间排序，
+// 便于按 ID 范围查询日志、与使用同类 ID 的追踪系统互通。所有策略生成的追踪 ID 都是合法的 W3C trace-id
+// （16 字节、不全为 0），可以直接写入 traceparent 请求头。
+package idgen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ID 生成策略
+const (
+	// StrategyW3C 随机 ID，符合 W3C Trace Context 对 trace-id 随机性的要求，默认策略
+	StrategyW3C = "w3c"
+	// StrategyUUIDv7 追踪 ID 为 UUIDv7（RFC 9562），前 48 位为毫秒时间戳，请求 ID 为带连字符的 UUID 字符串
+	StrategyUUIDv7 = "uuidv7"
+	// StrategySnowflake 追踪 ID 前 8 字节为雪花 ID，请求 ID 为十进制雪花 ID，需要为每个实例分配不同的节点号
+	StrategySnowflake = "snowflake"
+)
+
+// Generator ID 生成器，实现须可并发使用
+type Generator interface {
+	// TraceID 返回 16 字节的追踪 ID，不全为 0
+	TraceID() [16]byte
+	// SpanID 返回 8 字节的 span ID，不全为 0
+	SpanID() [8]byte
+	// RequestID 返回请求 ID
+	RequestID() string
+}
+
+// New 按策略名创建生成器，strategy 为空时使用 StrategyW3C；node 为雪花 ID 的节点号（1-1023），为 0 时由主机名和进程号推导
+func New(strategy string, node int64) (Generator, error) {
+	switch strings.ToLower(strategy) {
+	case "", StrategyW3C:
+		return W3C{}, nil
+	case StrategyUUIDv7:
+		return UUIDv7{}, nil
+	case StrategySnowflake:
+		if node == 0 {
+			node = DefaultNode()
+		}
+		return NewSnowflake(node)
+	}
+	return nil, fmt.Errorf("unknown ID generator strategy %q (w3c, uuidv7 or snowflake)", strategy)
+}
+
+// generatorHolder 保存默认生成器，atomic.Value 要求存入的具体类型一致
+type generatorHolder struct {
+	Generator
+}
+
+var defaultGenerator atomic.Value
+
+func init() {
+	defaultGenerator.Store(generatorHolder{W3C{}})
+}
+
+// Default 返回进程的默认生成器，协议适配器和框架服务用它生成 ID
+func Default() Generator {
+	return defaultGenerator.Load().(generatorHolder).Generator
+}
+
+// SetDefault 替换进程的默认生成器，g 为 nil 时恢复为 W3C；framework.Server 按 framework.idGenerator 设置
+func SetDefault(g Generator) {
+	if g == nil {
+		g = W3C{}
+	}
+	defaultGenerator.Store(generatorHolder{g})
+}
+
+// TraceIDHex 以默认生成器生成 32 位十六进制的追踪 ID
+func TraceIDHex() string {
+	id := Default().TraceID()
+	return hex.EncodeToString(id[:])
+}
+
+// SpanIDHex 以默认生成器生成 16 位十六进制的 span ID
+func SpanIDHex() string {
+	id := Default().SpanID()
+	return hex.EncodeToString(id[:])
+}
+
+// RequestID 以默认生成器生成请求 ID
+func RequestID() string {
+	return Default().RequestID()
+}
+
+// W3C 随机生成全部 ID
+type W3C struct{}
+
+// TraceID 实现 Generator
+func (W3C) TraceID() [16]byte {
+	var id [16]byte
+	for id == [16]byte{} {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// SpanID 实现 Generator
+func (W3C) SpanID() [8]byte {
+	return randomSpanID()
+}
+
+// RequestID 实现 Generator，为 32 位十六进制
+func (g W3C) RequestID() string {
+	id := g.TraceID()
+	return hex.EncodeToString(id[:])
+}
+
+// randomSpanID 生成不全为 0 的随机 span ID
+func randomSpanID() [8]byte {
+	var id [8]byte
+	for id == [8]byte{} {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// OTelGenerator 以 Generator 为 OpenTelemetry SDK 生成 ID，实现 sdktrace.IDGenerator，
+// 通过 sdktrace.WithIDGenerator 使用
+type OTelGenerator struct {
+	Generator Generator
+}
+
+// NewIDs 实现 sdktrace.IDGenerator
+func (g *OTelGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	return g.Generator.TraceID(), g.Generator.SpanID()
+}
+
+// NewSpanID 实现 sdktrace.IDGenerator
+func (g *OTelGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return g.Generator.SpanID()
+}
@@ -0,0 +1,129 @@
+package idgen
+
+import (
+	"context"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew(t *testing.T) {
+	for _, strategy := range []string{"", StrategyW3C, StrategyUUIDv7, "UUIDv7", StrategySnowflake} {
+		g, err := New(strategy, 0)
+		if err != nil {
+			t.Fatalf("New(%q) failed: %v", strategy, err)
+		}
+		// 所有策略的 ID 都是合法的 W3C trace-id 和 span-id
+		traceID, spanID := g.TraceID(), g.SpanID()
+		if !trace.TraceID(traceID).IsValid() || !trace.SpanID(spanID).IsValid() {
+			t.Errorf("%s generated invalid IDs %x %x", strategy, traceID, spanID)
+		}
+		if g.RequestID() == g.RequestID() {
+			t.Errorf("%s generated duplicate request IDs", strategy)
+		}
+	}
+	if _, err := New("ulid", 0); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+	if _, err := New(StrategySnowflake, MaxNode+1); err == nil {
+		t.Error("Expected error for out-of-range node")
+	}
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := UUIDv7{}.TraceID()
+	if id[6]>>4 != 7 || id[8]>>6 != 2 {
+		t.Errorf("Unexpected version or variant: %x", id)
+	}
+	if ts := UUIDTime(id); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("UUIDTime = %v, want around %v", ts, before)
+	}
+
+	requestID := UUIDv7{}.RequestID()
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(requestID) {
+		t.Errorf("Unexpected UUID format: %s", requestID)
+	}
+
+	// 不同毫秒生成的 ID 按时间排序
+	a, b := newUUIDv7(before), newUUIDv7(before.Add(time.Millisecond))
+	if first, second := hex.EncodeToString(a[:]), hex.EncodeToString(b[:]); first >= second {
+		t.Errorf("Expected %s < %s", first, second)
+	}
+}
+
+func TestSnowflake(t *testing.T) {
+	g, err := NewSnowflake(5)
+	if err != nil {
+		t.Fatalf("NewSnowflake failed: %v", err)
+	}
+
+	const workers, perWorker = 8, 2000
+	var mu sync.Mutex
+	seen := make(map[int64]bool, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]int64, perWorker)
+			for j := range ids {
+				ids[j] = g.Next()
+			}
+			// 同一节点生成的 ID 严格递增
+			if !sort.SliceIsSorted(ids, func(a, b int) bool { return ids[a] < ids[b] }) {
+				t.Error("Expected increasing IDs")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("Duplicate ID %d", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	id, err := strconv.ParseInt(g.RequestID(), 10, 64)
+	if err != nil {
+		t.Fatalf("Expected decimal request ID: %v", err)
+	}
+	if node := id >> sequenceBits & MaxNode; node != 5 {
+		t.Errorf("node = %d, want 5", node)
+	}
+	if ts := SnowflakeTime(id); time.Since(ts) > time.Second || ts.After(time.Now()) {
+		t.Errorf("SnowflakeTime = %v, want now", ts)
+	}
+}
+
+func TestDefaultAndOTel(t *testing.T) {
+	defer SetDefault(nil)
+
+	g, _ := NewSnowflake(1)
+	SetDefault(g)
+	if _, err := strconv.ParseInt(RequestID(), 10, 64); err != nil {
+		t.Errorf("Expected snowflake request ID from default generator: %v", err)
+	}
+	if len(TraceIDHex()) != 32 || len(SpanIDHex()) != 16 {
+		t.Errorf("Unexpected hex lengths: %s %s", TraceIDHex(), SpanIDHex())
+	}
+
+	otel := &OTelGenerator{Generator: UUIDv7{}}
+	traceID, spanID := otel.NewIDs(context.Background())
+	if !traceID.IsValid() || !spanID.IsValid() || !otel.NewSpanID(context.Background(), traceID).IsValid() {
+		t.Errorf("Unexpected OTel IDs %s %s", traceID, spanID)
+	}
+
+	SetDefault(nil)
+	if _, ok := Default().(W3C); !ok {
+		t.Errorf("Expected W3C default, got %T", Default())
+	}
+}
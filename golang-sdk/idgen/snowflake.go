@@ -0,0 +1,103 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 雪花 ID 的位布局：1 位符号（恒为 0）、41 位毫秒时间戳、10 位节点号、12 位序列号
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	// MaxNode 节点号上限
+	MaxNode     = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// SnowflakeEpoch 雪花 ID 时间戳的起点，可使用到 2089 年
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake 生成 64 位雪花 ID，同一节点生成的 ID 严格递增，不同节点的节点号必须不同
+//
+// 追踪 ID 为 8 字节雪花 ID 加 8 字节随机数，span ID 为雪花 ID；同一毫秒的序列号用尽时等待下一毫秒，
+// 时钟回拨时沿用上次的时间戳直到时钟追上
+type Snowflake struct {
+	mu       sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+}
+
+// NewSnowflake 创建节点号为 node（0-1023）的雪花 ID 生成器
+func NewSnowflake(node int64) (*Snowflake, error) {
+	if node < 0 || node > MaxNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d, got %d", MaxNode, node)
+	}
+	return &Snowflake{node: node}, nil
+}
+
+// DefaultNode 由主机名和进程号推导节点号，同一主机的多个进程通常不同；实例较多时应在配置中显式分配
+func DefaultNode() int64 {
+	host, _ := os.Hostname()
+	h := fnv.New32a()
+	h.Write([]byte(host + "/" + strconv.Itoa(os.Getpid())))
+	return int64(h.Sum32() % (MaxNode + 1))
+}
+
+// Next 返回下一个雪花 ID
+func (s *Snowflake) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Since(SnowflakeEpoch).Milliseconds()
+	if now < s.last {
+		now = s.last
+	}
+	if now == s.last {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			for now <= s.last {
+				time.Sleep(100 * time.Microsecond)
+				now = time.Since(SnowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.last = now
+	return now<<(nodeBits+sequenceBits) | s.node<<sequenceBits | s.sequence
+}
+
+// TraceID 实现 Generator
+func (s *Snowflake) TraceID() [16]byte {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(s.Next()))
+	rand.Read(id[8:])
+	return id
+}
+
+// SpanID 实现 Generator
+func (s *Snowflake) SpanID() [8]byte {
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], uint64(s.Next()))
+	if id == [8]byte{} {
+		return randomSpanID()
+	}
+	return id
+}
+
+// RequestID 实现 Generator，为十进制雪花 ID
+func (s *Snowflake) RequestID() string {
+	return strconv.FormatInt(s.Next(), 10)
+}
+
+// SnowflakeTime 返回雪花 ID 中的时间戳（毫秒精度）
+func SnowflakeTime(id int64) time.Time {
+	return SnowflakeEpoch.Add(time.Duration(id>>(nodeBits+sequenceBits)) * time.Millisecond)
+}
@@ -0,0 +1,60 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// UUIDv7 生成 RFC 9562 UUIDv7：48 位毫秒时间戳、版本 7、74 位随机数，按毫秒排序，同一毫秒内的顺序不确定
+type UUIDv7 struct{}
+
+// TraceID 实现 Generator
+func (UUIDv7) TraceID() [16]byte {
+	return newUUIDv7(time.Now())
+}
+
+// SpanID 实现 Generator，span ID 随机生成
+func (UUIDv7) SpanID() [8]byte {
+	return randomSpanID()
+}
+
+// RequestID 实现 Generator，为 8-4-4-4-12 格式的 UUID 字符串
+func (UUIDv7) RequestID() string {
+	return FormatUUID(newUUIDv7(time.Now()))
+}
+
+// newUUIDv7 生成 now 时刻的 UUIDv7
+func newUUIDv7(now time.Time) [16]byte {
+	var id [16]byte
+	rand.Read(id[6:])
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixMilli()))
+	copy(id[:6], ms[2:])
+	id[6] = id[6]&0x0f | 0x70 // 版本 7
+	id[8] = id[8]&0x3f | 0x80 // RFC 9562 变体
+	return id
+}
+
+// UUIDTime 返回 UUIDv7 中的时间戳（毫秒精度）
+func UUIDTime(id [16]byte) time.Time {
+	var ms [8]byte
+	copy(ms[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+}
+
+// FormatUUID 将 16 字节格式化为 8-4-4-4-12 的 UUID 字符串
+func FormatUUID(id [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf)
+}
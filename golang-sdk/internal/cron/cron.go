@@ -0,0 +1,178 @@
+// Package cron 解析标准 5 段 cron 表达式（分 时 日 月 周），供路由规则的定时生效窗口和配置校验共用
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr 解析后的 cron 表达式，按分钟精度匹配时间
+type Expr struct {
+	minute, hour, dom, month, dow uint64
+	// 日和周都不是 * 时按 cron 惯例任一匹配即可
+	domStar, dowStar bool
+	source           string
+}
+
+// field 单个字段的取值范围和名称
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 周日可写作 0 或 7
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// macros 常用的简写
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse 解析 cron 表达式，支持 *、列表（1,15）、范围（1-5）、步长（*/10、8-18/2）、月份和星期名称（JAN、MON）
+// 以及 @daily 等简写
+func Parse(expr string) (*Expr, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday), got %d", expr, len(parts))
+	}
+
+	e := &Expr{source: expr}
+	var err error
+	if e.minute, err = parseField(parts[0], minuteField); err != nil {
+		return nil, err
+	}
+	if e.hour, err = parseField(parts[1], hourField); err != nil {
+		return nil, err
+	}
+	if e.dom, err = parseField(parts[2], domField); err != nil {
+		return nil, err
+	}
+	if e.month, err = parseField(parts[3], monthField); err != nil {
+		return nil, err
+	}
+	if e.dow, err = parseField(parts[4], dowField); err != nil {
+		return nil, err
+	}
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+	e.domStar = parts[2] == "*" || parts[2] == "?"
+	e.dowStar = parts[4] == "*" || parts[4] == "?"
+	return e, nil
+}
+
+// String 返回原始表达式
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Match 判断 t 所在的分钟是否匹配表达式，按 t 自身的时区计算
+func (e *Expr) Match(t time.Time) bool {
+	if e.minute&(1<<uint(t.Minute())) == 0 || e.hour&(1<<uint(t.Hour())) == 0 || e.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := e.dom&(1<<uint(t.Day())) != 0
+	dowMatch := e.dow&(1<<uint(t.Weekday())) != 0
+	if e.domStar || e.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Prev 返回不晚于 t 的最近一次触发时间（整分钟），在 t 之前 limit 范围内没有触发时返回零值
+func (e *Expr) Prev(t time.Time, limit time.Duration) time.Time {
+	earliest := t.Add(-limit)
+	for at := t.Truncate(time.Minute); !at.Before(earliest); at = at.Add(-time.Minute) {
+		if e.Match(at) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// parseField 将逗号分隔的字段解析为位图
+func parseField(spec string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(spec, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rangeSpec := part
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in cron %s field %q", f.name, part)
+			}
+			step = n
+			rangeSpec = part[:i]
+		}
+
+		switch {
+		case rangeSpec == "*" || rangeSpec == "?":
+		case strings.Contains(rangeSpec, "-"):
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in cron %s field %q", f.name, part)
+			}
+		default:
+			v, err := f.value(rangeSpec)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// 单个值带步长时（如 5/15）表示从该值到最大值
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value 解析单个数值或名称并检查范围
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in cron %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron %s value %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
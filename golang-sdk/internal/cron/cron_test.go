@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAndMatch(t *testing.T) {
+	// 2024-03-02 是周六
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 30, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"* * * * *", at(2, 3, 4), true},
+		{"0 2 * * *", at(2, 2, 0), true},
+		{"0 2 * * *", at(2, 2, 1), false},
+		{"*/15 * * * *", at(2, 9, 45), true},
+		{"*/15 * * * *", at(2, 9, 40), false},
+		{"0 8-18/2 * * *", at(2, 12, 0), true},
+		{"0 8-18/2 * * *", at(2, 13, 0), false},
+		{"30 1 * * SAT,SUN", at(2, 1, 30), true},
+		{"30 1 * * mon-fri", at(2, 1, 30), false},
+		{"0 0 * * 7", at(3, 0, 0), true},
+		{"0 0 1,15 MAR *", at(15, 0, 0), true},
+		{"0 0 1,15 apr *", at(15, 0, 0), false},
+		// 日和周都指定时任一匹配即可
+		{"0 0 1 * 6", at(2, 0, 0), true},
+		{"@daily", at(2, 0, 0), true},
+		{"@hourly", at(2, 5, 1), false},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+		}
+		if got := expr.Match(tt.t); got != tt.want {
+			t.Errorf("%q.Match(%v) = %v, want %v", tt.expr, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"* * * *", "must have 5 fields"},
+		{"60 * * * *", "minute value 60 out of range"},
+		{"* * 0 * *", "day of month value 0 out of range"},
+		{"* * * foo *", "invalid value \"foo\""},
+		{"*/0 * * * *", "invalid step"},
+		{"10-5 * * * *", "invalid range"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPrev(t *testing.T) {
+	expr, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	now := time.Date(2024, 3, 2, 3, 15, 20, 0, time.UTC)
+	want := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	if got := expr.Prev(now, 2*time.Hour); !got.Equal(want) {
+		t.Errorf("Prev = %v, want %v", got, want)
+	}
+	if got := expr.Prev(now, time.Hour); !got.IsZero() {
+		t.Errorf("Expected no fire within an hour, got %v", got)
+	}
+}
@@ -0,0 +1,192 @@
+// Package resp 提供最小的 Redis 客户端，以 RESP 协议发送命令并读取回复，供 messaging 和 session 的 Redis 实现共用
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Error Redis 返回的错误回复
+type Error string
+
+// Error 返回错误信息
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// ErrorOf 返回 err 中的 Redis 错误回复，不是错误回复时返回空；返回空的错误通常表示连接已不可用
+func ErrorOf(err error) Error {
+	var redisErr Error
+	if errors.As(err, &redisErr) {
+		return redisErr
+	}
+	return ""
+}
+
+// Config 连接配置
+type Config struct {
+	// Address 服务器地址
+	Address string
+	// Username/Password 认证信息，Username 为空时使用旧版 AUTH password
+	Username string
+	Password string
+	// DB 数据库编号
+	DB int
+	// Timeout 连接和命令超时
+	Timeout time.Duration
+}
+
+// Conn Redis 连接，非并发安全
+type Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	timeout time.Duration
+}
+
+// Dial 连接 Redis，按配置认证并选择数据库
+func Dial(cfg *Config) (*Conn, error) {
+	conn, err := net.DialTimeout("tcp", cfg.Address, cfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", cfg.Address, err)
+	}
+	c := &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		writer:  bufio.NewWriter(conn),
+		timeout: cfg.Timeout,
+	}
+
+	if cfg.Password != "" {
+		args := []interface{}{"AUTH", cfg.Password}
+		if cfg.Username != "" {
+			args = []interface{}{"AUTH", cfg.Username, cfg.Password}
+		}
+		if _, err := c.Do(0, args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if cfg.DB != 0 {
+		if _, err := c.Do(0, "SELECT", cfg.DB); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Do 发送命令并读取回复，block 为命令自身的阻塞时间（如 XREAD BLOCK），读取超时在此基础上增加连接超时；
+// 错误回复以 Error 返回
+func (c *Conn) Do(block time.Duration, args ...interface{}) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(block + c.timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	if err := writeCommand(c.writer, args...); err != nil {
+		return nil, err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+	reply, err := readReply(c.reader)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// Receive 读取服务器推送的回复（如 SUBSCRIBE 后的频道消息），不设超时，关闭连接可中断读取
+func (c *Conn) Receive() (interface{}, error) {
+	return readReply(c.reader)
+}
+
+// Close 关闭连接
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// writeCommand 以 RESP 数组编码命令，参数支持 string、[]byte 和整数
+func writeCommand(w *bufio.Writer, args ...interface{}) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var data []byte
+		switch v := arg.(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			data = v
+		case int:
+			data = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			data = strconv.AppendInt(nil, v, 10)
+		default:
+			return fmt.Errorf("unsupported Redis argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n", len(data))
+		w.Write(data)
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readReply 读取一个 RESP 回复：简单字符串为 string，错误为 Error，整数为 int64，
+// 批量字符串为 []byte，数组为 []interface{}，空值为 nil
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid Redis reply: %q", line)
+	}
+	prefix, body := line[0], line[1:len(line)-2]
+
+	switch prefix {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis bulk length: %q", body)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis array length: %q", body)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unsupported Redis reply type %q", prefix)
+	}
+}
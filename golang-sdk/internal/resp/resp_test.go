@@ -0,0 +1,58 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteCommand(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := writeCommand(w, "XADD", "orders", []byte("payload"), 42); err != nil {
+		t.Fatalf("writeCommand failed: %v", err)
+	}
+	w.Flush()
+
+	want := "*4\r\n$4\r\nXADD\r\n$6\r\norders\r\n$7\r\npayload\r\n$2\r\n42\r\n"
+	if buf.String() != want {
+		t.Errorf("writeCommand = %q, want %q", buf.String(), want)
+	}
+
+	if err := writeCommand(w, 1.5); err == nil {
+		t.Error("expected error for unsupported argument type")
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "简单字符串", input: "+OK\r\n", want: "OK"},
+		{name: "错误", input: "-ERR unknown command\r\n", want: Error("ERR unknown command")},
+		{name: "整数", input: ":12\r\n", want: int64(12)},
+		{name: "批量字符串", input: "$5\r\nhello\r\n", want: []byte("hello")},
+		{name: "空批量字符串", input: "$-1\r\n", want: nil},
+		{name: "数组", input: "*2\r\n$1\r\na\r\n:1\r\n", want: []interface{}{[]byte("a"), int64(1)}},
+		{name: "空数组", input: "*-1\r\n", want: nil},
+		{name: "未知类型", input: "?x\r\n", wantErr: true},
+		{name: "缺少行尾", input: "+OK\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readReply(bufio.NewReader(strings.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readReply error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readReply = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
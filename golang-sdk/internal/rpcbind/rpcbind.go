@@ -0,0 +1,186 @@
+// Package rpcbind 将服务对象的导出方法反射为按 JSON 参数调用的处理器，供 framework 和内部 JSON-RPC 协议共用
+package rpcbind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/validate"
+)
+
+// Handler 以解码后的 JSON 参数调用服务方法
+type Handler func(ctx context.Context, params interface{}) (interface{}, error)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Methods 反射服务对象的导出方法，返回以 <name>.<首字母小写的方法名> 为键的处理器
+//
+// 方法签名须为以下之一，其他导出方法被忽略：
+//
+//	func (s *HelloService) SayHello(ctx context.Context, req *HelloRequest) (*HelloReply, error)
+//	func (s *HelloService) SayHello(ctx context.Context, req HelloRequest) (HelloReply, error)
+//	func (s *HelloService) Ping(ctx context.Context) (string, error)
+//	func (s *HelloService) Notify(ctx context.Context, req *Event) error
+//
+// 具名参数（JSON 对象）解码到请求参数；位置参数（JSON 数组）按请求结构体导出字段的声明顺序绑定，
+// 只有一个对象元素时解码到整个请求参数。请求参数中的 *adapter.Attachment 按 adapter.BindAttachments 绑定到请求的附件，
+// 然后按 validate 标签校验，违规时不调用方法，返回 BadRequest。没有符合要求的方法时返回错误
+func Methods(name string, service interface{}) (map[string]Handler, error) {
+	if name == "" {
+		return nil, fmt.Errorf("service name cannot be empty")
+	}
+
+	value := reflect.ValueOf(service)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("service %s cannot be nil", name)
+	}
+
+	handlers := make(map[string]Handler)
+	for i := 0; i < value.NumMethod(); i++ {
+		method := name + "." + lowerFirst(value.Type().Method(i).Name)
+		if handler, ok := methodHandler(method, value.Method(i)); ok {
+			handlers[method] = handler
+		}
+	}
+	if len(handlers) == 0 {
+		return nil, fmt.Errorf("service %s (%T) has no exported methods of the form func(context.Context[, request]) ([response, ]error)", name, service)
+	}
+	return handlers, nil
+}
+
+// methodHandler 将签名符合要求的方法包装为 Handler
+func methodHandler(name string, method reflect.Value) (Handler, bool) {
+	mt := method.Type()
+	if mt.IsVariadic() || mt.NumIn() < 1 || mt.NumIn() > 2 || mt.In(0) != contextType {
+		return nil, false
+	}
+	if mt.NumOut() < 1 || mt.NumOut() > 2 || mt.Out(mt.NumOut()-1) != errorType {
+		return nil, false
+	}
+
+	var requestType reflect.Type
+	if mt.NumIn() == 2 {
+		requestType = mt.In(1)
+	}
+
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		args := []reflect.Value{reflect.ValueOf(ctx)}
+		if requestType != nil {
+			request, err := bindParams(params, requestType)
+			if err != nil {
+				return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("invalid params for %s", name))
+			}
+			if err := adapter.BindAttachments(ctx, request.Interface()); err != nil {
+				return nil, err
+			}
+			if err := validate.Struct(request.Interface()); err != nil {
+				return nil, err
+			}
+			args = append(args, request)
+		}
+
+		results := method.Call(args)
+		if err, _ := results[len(results)-1].Interface().(error); err != nil {
+			return nil, err
+		}
+		if len(results) == 1 {
+			return nil, nil
+		}
+		return results[0].Interface(), nil
+	}, true
+}
+
+// bindParams 将 JSON-RPC 参数绑定到请求参数类型，params 为 nil 时返回零值（指针类型为指向零值的指针）
+func bindParams(params interface{}, typ reflect.Type) (reflect.Value, error) {
+	base := typ
+	if typ.Kind() == reflect.Ptr {
+		base = typ.Elem()
+	}
+	target := reflect.New(base)
+
+	if positional, ok := params.([]interface{}); ok {
+		if err := bindPositional(positional, target); err != nil {
+			return reflect.Value{}, err
+		}
+	} else if params != nil {
+		if err := decodeInto(params, target.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		return target, nil
+	}
+	return target.Elem(), nil
+}
+
+// bindPositional 绑定位置参数
+//
+// 请求参数为结构体时按导出字段的声明顺序逐个绑定，只有一个对象元素时解码到整个结构体；
+// 为切片或数组时整体解码；其他类型只接受一个元素
+func bindPositional(values []interface{}, target reflect.Value) error {
+	base := target.Elem()
+	switch base.Kind() {
+	case reflect.Struct:
+		if len(values) == 1 {
+			if _, ok := values[0].(map[string]interface{}); ok {
+				return decodeInto(values[0], target.Interface())
+			}
+		}
+		fields := positionalFields(base.Type())
+		if len(values) > len(fields) {
+			return fmt.Errorf("too many positional params: got %d, want at most %d", len(values), len(fields))
+		}
+		for i, value := range values {
+			field := base.Field(fields[i])
+			if err := decodeInto(value, field.Addr().Interface()); err != nil {
+				return fmt.Errorf("param %d (%s): %w", i, base.Type().Field(fields[i]).Name, err)
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		return decodeInto(values, target.Interface())
+	default:
+		if len(values) != 1 {
+			return fmt.Errorf("expected 1 positional param, got %d", len(values))
+		}
+		return decodeInto(values[0], target.Interface())
+	}
+}
+
+// positionalFields 返回可按位置绑定的字段下标：导出且未标记 json:"-" 的字段
+func positionalFields(typ reflect.Type) []int {
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	return fields
+}
+
+// decodeInto 经 JSON 编码将解码后的参数转换为目标类型
+func decodeInto(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// lowerFirst 方法名首字母小写，如 SayHello -> sayHello
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
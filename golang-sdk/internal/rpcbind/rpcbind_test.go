@@ -0,0 +1,111 @@
+package rpcbind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type helloRequest struct {
+	Name     string `json:"name"`
+	Times    int    `json:"times"`
+	internal string
+	Skipped  string `json:"-"`
+}
+
+type helloService struct{}
+
+func (s *helloService) SayHello(ctx context.Context, req *helloRequest) (string, error) {
+	return "Hello, " + req.Name, nil
+}
+
+func (s *helloService) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// Ignored 签名不符合要求，不注册
+func (s *helloService) Ignored(name string) string {
+	return name
+}
+
+func TestMethods(t *testing.T) {
+	handlers, err := Methods("hello", &helloService{})
+	if err != nil {
+		t.Fatalf("Methods failed: %v", err)
+	}
+	if len(handlers) != 2 || handlers["hello.sayHello"] == nil || handlers["hello.ping"] == nil {
+		t.Fatalf("Methods = %v, want hello.sayHello and hello.ping", handlers)
+	}
+
+	result, err := handlers["hello.sayHello"](context.Background(), map[string]interface{}{"name": "Go"})
+	if err != nil || result != "Hello, Go" {
+		t.Errorf("hello.sayHello = %v, %v", result, err)
+	}
+
+	if _, err := Methods("", &helloService{}); err == nil {
+		t.Error("Expected error for empty service name")
+	}
+	if _, err := Methods("hello", nil); err == nil {
+		t.Error("Expected error for nil service")
+	}
+	if _, err := Methods("hello", struct{}{}); err == nil {
+		t.Error("Expected error for service without methods")
+	}
+}
+
+func TestBindParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		typ     reflect.Type
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "具名参数绑定到结构体指针", params: `{"name":"Go","times":2}`, typ: reflect.TypeOf(&helloRequest{}), want: &helloRequest{Name: "Go", Times: 2}},
+		{name: "具名参数绑定到结构体", params: `{"name":"Go"}`, typ: reflect.TypeOf(helloRequest{}), want: helloRequest{Name: "Go"}},
+		{name: "位置参数按字段顺序绑定", params: `["Go",2]`, typ: reflect.TypeOf(&helloRequest{}), want: &helloRequest{Name: "Go", Times: 2}},
+		{name: "位置参数少于字段数", params: `["Go"]`, typ: reflect.TypeOf(&helloRequest{}), want: &helloRequest{Name: "Go"}},
+		{name: "单个对象元素绑定到整个结构体", params: `[{"name":"Go"}]`, typ: reflect.TypeOf(&helloRequest{}), want: &helloRequest{Name: "Go"}},
+		{name: "位置参数过多", params: `["Go",2,"x"]`, typ: reflect.TypeOf(&helloRequest{}), wantErr: true},
+		{name: "位置参数类型错误", params: `[1]`, typ: reflect.TypeOf(&helloRequest{}), wantErr: true},
+		{name: "切片整体绑定", params: `[1,2,3]`, typ: reflect.TypeOf([]int{}), want: []int{1, 2, 3}},
+		{name: "标量取唯一元素", params: `["Go"]`, typ: reflect.TypeOf(""), want: "Go"},
+		{name: "标量多个元素", params: `["Go","Rust"]`, typ: reflect.TypeOf(""), wantErr: true},
+		{name: "无参数返回零值", params: `null`, typ: reflect.TypeOf(&helloRequest{}), want: &helloRequest{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params interface{}
+			if err := json.Unmarshal([]byte(tt.params), &params); err != nil {
+				t.Fatalf("Invalid test params: %v", err)
+			}
+			got, err := bindParams(params, tt.typ)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %#v", got.Interface())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("bindParams = %#v, want %#v", got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMethodHandlerError(t *testing.T) {
+	failure := errors.New("boom")
+	handler, ok := methodHandler("test.fail", reflect.ValueOf(func(ctx context.Context) error { return failure }))
+	if !ok {
+		t.Fatal("Expected method to be accepted")
+	}
+	if _, err := handler(context.Background(), nil); !errors.Is(err, failure) {
+		t.Errorf("Expected original error, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// InFlight 处理中请求的计数器
+//
+// 每个请求开始时调用 Acquire，结束时调用 Release；Close 后 Acquire 返回 false，
+// Wait 等待已开始的请求全部结束
+type InFlight struct {
+	mu      sync.Mutex
+	count   int
+	closed  bool
+	drained chan struct{}
+}
+
+// NewInFlight 创建处理中请求的计数器
+func NewInFlight() *InFlight {
+	return &InFlight{drained: make(chan struct{})}
+}
+
+// Acquire 登记一个请求，Close 之后返回 false，调用方应拒绝该请求
+func (f *InFlight) Acquire() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return false
+	}
+	f.count++
+	return true
+}
+
+// Release 结束一个 Acquire 成功的请求
+func (f *InFlight) Release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.closed && f.count == 0 {
+		close(f.drained)
+	}
+}
+
+// Close 停止接受新请求，可重复调用
+func (f *InFlight) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	if f.count == 0 {
+		close(f.drained)
+	}
+}
+
+// Wait 停止接受新请求并等待处理中的请求全部结束，ctx 取消时返回剩余请求数
+func (f *InFlight) Wait(ctx context.Context) error {
+	f.Close()
+	select {
+	case <-f.drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d requests still in flight: %w", f.Count(), ctx.Err())
+	}
+}
+
+// Count 返回处理中的请求数
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
@@ -0,0 +1,237 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout Wait 收到退出信号后完成关闭的默认时间
+const DefaultTimeout = 30 * time.Second
+
+// Phase 关闭阶段，Shutdown 按阶段从小到大依次执行
+type Phase int
+
+const (
+	// PhaseDeregister 从注册中心注销，使调用方不再路由到本实例
+	PhaseDeregister Phase = iota
+	// PhaseStopAccepting 停止接受新请求
+	PhaseStopAccepting
+	// PhaseDrain 等待处理中的请求完成，停止协议处理器并排空连接池
+	PhaseDrain
+	// PhaseFlush 刷新追踪和指标等遥测数据
+	PhaseFlush
+	// PhaseClose 关闭注册中心、安全管理器等资源
+	PhaseClose
+)
+
+// String 返回阶段名称
+func (p Phase) String() string {
+	switch p {
+	case PhaseDeregister:
+		return "deregister"
+	case PhaseStopAccepting:
+		return "stop-accepting"
+	case PhaseDrain:
+		return "drain"
+	case PhaseFlush:
+		return "flush"
+	case PhaseClose:
+		return "close"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+// Hook 关闭钩子，应在 ctx 取消时尽快返回
+type Hook func(ctx context.Context) error
+
+// hook 已注册的钩子
+type hook struct {
+	phase Phase
+	name  string
+	fn    Hook
+}
+
+// Options 生命周期管理器选项
+type Options struct {
+	// Timeout Wait 收到退出信号后的关闭超时，为 0 时使用 DefaultTimeout
+	Timeout time.Duration
+	// Signals Wait 等待的信号，为空时使用 SIGINT 和 SIGTERM
+	Signals []os.Signal
+	// UpgradeSignal Wait 收到后热重启的信号（如 syscall.SIGUSR2），为 nil 时不通过信号热重启
+	UpgradeSignal os.Signal
+	// OnUpgradeError 通过信号热重启失败时调用，当前进程继续提供服务
+	OnUpgradeError func(err error)
+}
+
+// Manager 生命周期管理器
+//
+// 各组件按关闭阶段注册钩子，Shutdown 按阶段顺序执行，同一阶段内按注册顺序执行：
+// 注销服务 -> 停止接受新请求 -> 等待处理中的请求和连接池排空 -> 刷新遥测数据 -> 关闭资源。
+// 钩子失败不影响后续钩子，错误合并返回
+type Manager struct {
+	timeout        time.Duration
+	signals        []os.Signal
+	upgradeSignal  os.Signal
+	onUpgradeError func(err error)
+
+	mu        sync.Mutex
+	hooks     []hook
+	upgrading bool
+
+	once     sync.Once
+	done     chan struct{}
+	shutdown chan struct{}
+	err      error
+}
+
+// NewManager 创建生命周期管理器
+func NewManager(options *Options) *Manager {
+	if options == nil {
+		options = &Options{}
+	}
+	m := &Manager{
+		timeout:        options.Timeout,
+		signals:        options.Signals,
+		upgradeSignal:  options.UpgradeSignal,
+		onUpgradeError: options.OnUpgradeError,
+		done:           make(chan struct{}),
+		shutdown:       make(chan struct{}),
+	}
+	if m.timeout <= 0 {
+		m.timeout = DefaultTimeout
+	}
+	if len(m.signals) == 0 {
+		m.signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	return m
+}
+
+// Register 在 phase 阶段注册名为 name 的关闭钩子，Shutdown 开始后注册的钩子不会执行
+func (m *Manager) Register(phase Phase, name string, fn Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{phase: phase, name: name, fn: fn})
+}
+
+// Shutdown 按阶段执行关闭钩子，只执行一次，重复调用等待首次关闭完成并返回相同的结果
+//
+// ctx 超时后剩余的钩子仍会执行，但收到的是已取消的 ctx
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() {
+		close(m.shutdown)
+		m.err = m.run(ctx)
+		close(m.done)
+	})
+	<-m.done
+	return m.err
+}
+
+// Wait 阻塞直到收到退出信号或 Shutdown 被调用，收到信号时在 Timeout 内执行 Shutdown
+//
+// 设置了 UpgradeSignal 时，收到该信号后执行 Upgrade，失败时调用 OnUpgradeError 并继续等待
+func (m *Manager) Wait() error {
+	signals := m.signals
+	if m.upgradeSignal != nil {
+		signals = append(append([]os.Signal(nil), signals...), m.upgradeSignal)
+	}
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	defer signal.Stop(quit)
+
+	for {
+		select {
+		case sig := <-quit:
+			if m.upgradeSignal == nil || sig != m.upgradeSignal {
+				ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+				defer cancel()
+				return m.Shutdown(ctx)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+			err := m.Upgrade(ctx)
+			cancel()
+			if m.ShuttingDown() {
+				<-m.done
+				return m.err
+			}
+			if m.onUpgradeError != nil {
+				m.onUpgradeError(err)
+			}
+		case <-m.shutdown:
+			<-m.done
+			return m.err
+		}
+	}
+}
+
+// Upgrade 热重启：以相同的命令行启动新进程并交接通过 Listen 创建的监听器，
+// 在 ctx 内等待新进程调用 Ready，随后在 Timeout 内关闭当前进程并返回关闭的结果
+//
+// 关闭钩子收到的 context 满足 Upgrading；新进程未就绪时返回错误，当前进程继续提供服务
+func (m *Manager) Upgrade(ctx context.Context) error {
+	if m.ShuttingDown() {
+		return fmt.Errorf("shutdown already started")
+	}
+	m.mu.Lock()
+	if m.upgrading {
+		m.mu.Unlock()
+		return fmt.Errorf("upgrade already in progress")
+	}
+	m.upgrading = true
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.upgrading = false
+		m.mu.Unlock()
+	}()
+
+	if err := Upgrade(ctx); err != nil {
+		return err
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	return m.Shutdown(context.WithValue(shutdownCtx, upgradingKey{}, true))
+}
+
+// ShuttingDown 返回 Shutdown 是否已经开始
+func (m *Manager) ShuttingDown() bool {
+	select {
+	case <-m.shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done 返回 Shutdown 完成时关闭的通道
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
+
+// run 按阶段顺序执行钩子
+func (m *Manager) run(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	// 稳定排序，同一阶段保持注册顺序
+	for i := 1; i < len(hooks); i++ {
+		for j := i; j > 0 && hooks[j].phase < hooks[j-1].phase; j-- {
+			hooks[j], hooks[j-1] = hooks[j-1], hooks[j]
+		}
+	}
+
+	var errs []error
+	for _, h := range hooks {
+		if err := h.fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", h.phase, h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
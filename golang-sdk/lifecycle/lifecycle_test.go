@@ -0,0 +1,162 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestManagerShutdownOrder(t *testing.T) {
+	m := NewManager(nil)
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string, err error) Hook {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	failure := errors.New("flush failed")
+	m.Register(PhaseClose, "registry", record("registry", nil))
+	m.Register(PhaseFlush, "tracing", record("tracing", failure))
+	m.Register(PhaseDrain, "requests", record("requests", nil))
+	m.Register(PhaseDrain, "pool", record("pool", nil))
+	m.Register(PhaseDeregister, "service", record("service", nil))
+
+	err := m.Shutdown(context.Background())
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "flush tracing") {
+		t.Errorf("Shutdown error = %v, want flush tracing error", err)
+	}
+	want := []string{"service", "requests", "pool", "tracing", "registry"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	// 重复调用不再执行钩子，返回相同的结果
+	if err2 := m.Shutdown(context.Background()); err2 != err {
+		t.Errorf("second Shutdown = %v, want %v", err2, err)
+	}
+	if len(calls) != len(want) {
+		t.Errorf("hooks ran again: %v", calls)
+	}
+	if !m.ShuttingDown() {
+		t.Error("expected ShuttingDown after Shutdown")
+	}
+	select {
+	case <-m.Done():
+	default:
+		t.Error("expected Done to be closed")
+	}
+}
+
+func TestManagerWait(t *testing.T) {
+	t.Run("收到信号后在超时内关闭", func(t *testing.T) {
+		m := NewManager(&Options{Timeout: time.Second, Signals: []os.Signal{syscall.SIGUSR1}})
+		deadlines := make(chan bool, 1)
+		m.Register(PhaseDrain, "requests", func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			deadlines <- ok
+			return nil
+		})
+
+		result := make(chan error, 1)
+		go func() {
+			result <- m.Wait()
+		}()
+		// 等待 Wait 开始监听信号
+		time.Sleep(50 * time.Millisecond)
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatalf("Kill failed: %v", err)
+		}
+
+		select {
+		case err := <-result:
+			if err != nil {
+				t.Errorf("Wait = %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Wait did not return after signal")
+		}
+		if !<-deadlines {
+			t.Error("expected hook context to carry the shutdown timeout")
+		}
+	})
+
+	t.Run("Shutdown 后返回", func(t *testing.T) {
+		m := NewManager(&Options{Signals: []os.Signal{syscall.SIGUSR2}})
+		failure := errors.New("close failed")
+		m.Register(PhaseClose, "registry", func(ctx context.Context) error { return failure })
+
+		result := make(chan error, 1)
+		go func() {
+			result <- m.Wait()
+		}()
+		m.Shutdown(context.Background())
+
+		select {
+		case err := <-result:
+			if !errors.Is(err, failure) {
+				t.Errorf("Wait = %v, want %v", err, failure)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Wait did not return after Shutdown")
+		}
+	})
+}
+
+func TestInFlight(t *testing.T) {
+	t.Run("等待处理中的请求结束", func(t *testing.T) {
+		f := NewInFlight()
+		if !f.Acquire() || !f.Acquire() {
+			t.Fatal("expected Acquire to succeed before Close")
+		}
+
+		result := make(chan error, 1)
+		go func() {
+			result <- f.Wait(context.Background())
+		}()
+		time.Sleep(20 * time.Millisecond)
+		if f.Acquire() {
+			t.Error("expected Acquire to fail while draining")
+		}
+
+		f.Release()
+		select {
+		case err := <-result:
+			t.Fatalf("Wait returned with a request in flight: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+		f.Release()
+		if err := <-result; err != nil {
+			t.Errorf("Wait = %v", err)
+		}
+	})
+
+	t.Run("超时返回剩余请求数", func(t *testing.T) {
+		f := NewInFlight()
+		f.Acquire()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := f.Wait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "1 requests") {
+			t.Errorf("Wait = %v, want deadline exceeded with 1 request", err)
+		}
+	})
+
+	t.Run("没有请求时立即返回", func(t *testing.T) {
+		f := NewInFlight()
+		f.Close()
+		if err := f.Wait(context.Background()); err != nil {
+			t.Errorf("Wait = %v", err)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package lifecycle
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// reusePortControl 当前平台不支持 SO_REUSEPORT
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return fmt.Errorf("SO_REUSEPORT is not supported on this platform")
+}
@@ -0,0 +1,153 @@
+package lifecycle
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// MemoryLimitRatio TuneRuntime 设置的 Go 软内存上限（GOMEMLIMIT）占容器内存上限的比例，其余留给栈和非 Go 堆内存
+const MemoryLimitRatio = 0.9
+
+// cgroupRoot cgroup 文件系统的挂载点，测试时替换
+var cgroupRoot = "/sys/fs/cgroup"
+
+// ResourceLimits 进程可用的 CPU 和内存
+type ResourceLimits struct {
+	// CPUs 可用的 CPU 数，容器设置了 CPU 配额时为 quota / period（可能为小数），否则为主机 CPU 数
+	CPUs float64 `json:"cpus"`
+	// HostCPUs 主机的 CPU 数
+	HostCPUs int `json:"hostCpus"`
+	// CPUQuota CPUs 来自容器的 CPU 配额
+	CPUQuota bool `json:"cpuQuota"`
+	// MemoryBytes 容器的内存上限，未限制时为 0
+	MemoryBytes int64 `json:"memoryBytes,omitempty"`
+	// Cgroup 读取限制的 cgroup 版本（v1、v2），不在 cgroup 中运行（如非 Linux 系统）时为空
+	Cgroup string `json:"cgroup,omitempty"`
+}
+
+// RuntimeTuning TuneRuntime 调整后的 Go 运行时参数
+type RuntimeTuning struct {
+	// GOMAXPROCS 生效的 GOMAXPROCS
+	GOMAXPROCS int `json:"gomaxprocs"`
+	// PreviousGOMAXPROCS 调整前的 GOMAXPROCS
+	PreviousGOMAXPROCS int `json:"previousGomaxprocs"`
+	// MemoryLimit 生效的软内存上限，未设置时为 0
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+	// Overridden 由环境变量指定、未自动调整的参数（GOMAXPROCS、GOMEMLIMIT）
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// DetectResourceLimits 读取 cgroup v2 或 v1 的 CPU 配额和内存上限，不在 cgroup 中运行时按主机 CPU 数返回
+func DetectResourceLimits() *ResourceLimits {
+	return detectResourceLimits(cgroupRoot)
+}
+
+func detectResourceLimits(root string) *ResourceLimits {
+	limits := &ResourceLimits{
+		CPUs:     float64(runtime.NumCPU()),
+		HostCPUs: runtime.NumCPU(),
+	}
+
+	var cpus float64
+	var memory int64
+	if data, err := os.ReadFile(filepath.Join(root, "cgroup.controllers")); err == nil && len(data) > 0 {
+		limits.Cgroup = "v2"
+		cpus = readCPUMax(filepath.Join(root, "cpu.max"))
+		memory = readMemoryLimit(filepath.Join(root, "memory.max"))
+	} else if _, err := os.Stat(filepath.Join(root, "cpu")); err == nil {
+		limits.Cgroup = "v1"
+		quota := readInt(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+		period := readInt(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+		if quota > 0 && period > 0 {
+			cpus = float64(quota) / float64(period)
+		}
+		memory = readMemoryLimit(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	}
+
+	if cpus > 0 && cpus < limits.CPUs {
+		limits.CPUs = cpus
+		limits.CPUQuota = true
+	}
+	limits.MemoryBytes = memory
+	return limits
+}
+
+// readCPUMax 解析 cgroup v2 的 cpu.max（"<quota> <period>"），未限制时返回 0
+func readCPUMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err1 := strconv.ParseInt(fields[0], 10, 64)
+	period, err2 := strconv.ParseInt(fields[1], 10, 64)
+	if err1 != nil || err2 != nil || quota <= 0 || period <= 0 {
+		return 0
+	}
+	return float64(quota) / float64(period)
+}
+
+// readMemoryLimit 解析内存上限文件，未限制时返回 0；cgroup v1 以接近 int64 上限的值表示不限制
+func readMemoryLimit(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit <= 0 || limit >= 1<<62 {
+		return 0
+	}
+	return limit
+}
+
+// readInt 读取只包含一个整数的文件，读取失败时返回 0
+func readInt(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// TuneRuntime 按资源限制调整 Go 运行时
+//
+// GOMAXPROCS 设为 CPU 配额向上取整（至少为 1），避免在只分到少量 CPU 的容器中按主机 CPU 数调度而被限流；
+// 容器限制了内存时将软内存上限设为 MemoryBytes × MemoryLimitRatio，使 GC 在接近上限时更积极回收而不是被 OOM 终止。
+// 设置了 GOMAXPROCS 或 GOMEMLIMIT 环境变量时不调整对应的参数
+func TuneRuntime(limits *ResourceLimits) *RuntimeTuning {
+	tuning := &RuntimeTuning{PreviousGOMAXPROCS: runtime.GOMAXPROCS(0)}
+
+	if _, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		tuning.Overridden = append(tuning.Overridden, "GOMAXPROCS")
+	} else {
+		runtime.GOMAXPROCS(max(int(math.Ceil(limits.CPUs)), 1))
+	}
+	tuning.GOMAXPROCS = runtime.GOMAXPROCS(0)
+
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); ok {
+		tuning.Overridden = append(tuning.Overridden, "GOMEMLIMIT")
+	} else if limits.MemoryBytes > 0 {
+		debug.SetMemoryLimit(int64(float64(limits.MemoryBytes) * MemoryLimitRatio))
+	}
+	// 读取当前值，未设置时为 math.MaxInt64
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		tuning.MemoryLimit = limit
+	}
+	return tuning
+}
@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeCgroupFiles 在临时目录中按相对路径写入 cgroup 文件
+func writeCgroupFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestDetectResourceLimits(t *testing.T) {
+	hostCPUs := float64(runtime.NumCPU())
+
+	t.Run("cgroup v2", func(t *testing.T) {
+		root := writeCgroupFiles(t, map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"cpu.max":            "50000 100000\n",
+			"memory.max":         "536870912\n",
+		})
+		limits := detectResourceLimits(root)
+		if limits.Cgroup != "v2" || limits.MemoryBytes != 512<<20 {
+			t.Errorf("limits = %+v, want cgroup v2 with 512MiB", limits)
+		}
+		if hostCPUs > 0.5 && (!limits.CPUQuota || limits.CPUs != 0.5) {
+			t.Errorf("CPUs = %v (quota %v), want 0.5", limits.CPUs, limits.CPUQuota)
+		}
+	})
+
+	t.Run("cgroup v2 未限制", func(t *testing.T) {
+		root := writeCgroupFiles(t, map[string]string{
+			"cgroup.controllers": "cpu memory\n",
+			"cpu.max":            "max 100000\n",
+			"memory.max":         "max\n",
+		})
+		limits := detectResourceLimits(root)
+		if limits.CPUQuota || limits.CPUs != hostCPUs || limits.MemoryBytes != 0 {
+			t.Errorf("limits = %+v, want host CPUs without memory limit", limits)
+		}
+	})
+
+	t.Run("cgroup v1", func(t *testing.T) {
+		root := writeCgroupFiles(t, map[string]string{
+			"cpu/cpu.cfs_quota_us":         "-1\n",
+			"cpu/cpu.cfs_period_us":        "100000\n",
+			"memory/memory.limit_in_bytes": "9223372036854771712\n",
+		})
+		limits := detectResourceLimits(root)
+		if limits.Cgroup != "v1" || limits.CPUQuota || limits.MemoryBytes != 0 {
+			t.Errorf("limits = %+v, want cgroup v1 without limits", limits)
+		}
+	})
+
+	t.Run("不在 cgroup 中", func(t *testing.T) {
+		limits := detectResourceLimits(t.TempDir())
+		if limits.Cgroup != "" || limits.CPUs != hostCPUs || limits.HostCPUs != runtime.NumCPU() {
+			t.Errorf("limits = %+v, want host CPUs", limits)
+		}
+	})
+}
+
+func TestTuneRuntime(t *testing.T) {
+	previous := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(previous)
+
+	t.Setenv("GOMEMLIMIT", "1GiB")
+	tuning := TuneRuntime(&ResourceLimits{CPUs: 1.5, MemoryBytes: 256 << 20})
+	if tuning.GOMAXPROCS != 2 || runtime.GOMAXPROCS(0) != 2 {
+		t.Errorf("GOMAXPROCS = %d, want 2", tuning.GOMAXPROCS)
+	}
+	if tuning.PreviousGOMAXPROCS != previous {
+		t.Errorf("PreviousGOMAXPROCS = %d, want %d", tuning.PreviousGOMAXPROCS, previous)
+	}
+	if len(tuning.Overridden) != 1 || tuning.Overridden[0] != "GOMEMLIMIT" {
+		t.Errorf("Overridden = %v, want [GOMEMLIMIT]", tuning.Overridden)
+	}
+
+	t.Setenv("GOMAXPROCS", "3")
+	tuning = TuneRuntime(&ResourceLimits{CPUs: 0.5})
+	if tuning.GOMAXPROCS != 2 {
+		t.Errorf("GOMAXPROCS = %d, want unchanged 2 when GOMAXPROCS is set", tuning.GOMAXPROCS)
+	}
+}
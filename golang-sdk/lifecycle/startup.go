@@ -0,0 +1,90 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 启动依赖等待的默认值
+const (
+	// DefaultStartupTimeout WaitForDependencies 等待所有依赖就绪的默认最长时间
+	DefaultStartupTimeout = 60 * time.Second
+	// DefaultStartupInitialBackoff 依赖检查失败后首次重试的默认间隔
+	DefaultStartupInitialBackoff = 200 * time.Millisecond
+	// DefaultStartupMaxBackoff 依赖检查重试间隔的默认上限
+	DefaultStartupMaxBackoff = 5 * time.Second
+)
+
+// Dependency 启动依赖，Check 返回 nil 表示就绪
+// observability.HealthCheck 以及 registry.ConnectivityCheck、registry.DiscoveryCheck 等组件检查满足该接口，可直接作为依赖
+// observability.HealthCheck 满足该接口，注册中心连通性、下游服务发现和数据库等检查可直接作为依赖
+type Dependency interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// StartupOptions 启动依赖等待选项
+type StartupOptions struct {
+	// Timeout 等待所有依赖就绪的最长时间，默认 DefaultStartupTimeout
+	Timeout time.Duration
+	// InitialBackoff 检查失败后首次重试的间隔，之后每次翻倍，默认 DefaultStartupInitialBackoff
+	InitialBackoff time.Duration
+	// MaxBackoff 重试间隔的上限，默认 DefaultStartupMaxBackoff
+	MaxBackoff time.Duration
+	// OnWaiting 依赖检查失败、等待重试前调用，attempt 从 1 开始，用于输出启动进度
+	OnWaiting func(name string, attempt int, err error)
+}
+
+// WaitForDependencies 按声明顺序等待依赖就绪，前一个依赖就绪后才检查下一个
+//
+// 依赖检查失败时按指数退避重试；超过 Timeout 或 ctx 结束时返回未就绪的依赖和最后一次检查的错误
+func WaitForDependencies(ctx context.Context, options *StartupOptions, deps ...Dependency) error {
+	if options == nil {
+		options = &StartupOptions{}
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultStartupTimeout
+	}
+	initialBackoff := options.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = DefaultStartupInitialBackoff
+	}
+	maxBackoff := options.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultStartupMaxBackoff
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, dep := range deps {
+		backoff := initialBackoff
+		for attempt := 1; ; attempt++ {
+			err := dep.Check(ctx)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return fmt.Errorf("dependency %s not ready: %w", dep.Name(), err)
+			}
+			if options.OnWaiting != nil {
+				options.OnWaiting(dep.Name(), attempt, err)
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("dependency %s not ready: %w", dep.Name(), err)
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+	return nil
+}
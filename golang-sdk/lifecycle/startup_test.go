@@ -0,0 +1,80 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDependency 前 failures 次检查失败的依赖，calls 记录检查顺序
+type testDependency struct {
+	name     string
+	failures int
+	attempts int
+	calls    *[]string
+}
+
+func (d *testDependency) Name() string {
+	return d.name
+}
+
+func (d *testDependency) Check(ctx context.Context) error {
+	d.attempts++
+	*d.calls = append(*d.calls, d.name)
+	if d.attempts <= d.failures {
+		return errors.New(d.name + " unavailable")
+	}
+	return nil
+}
+
+func TestWaitForDependencies(t *testing.T) {
+	options := &StartupOptions{Timeout: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	t.Run("按声明顺序等待", func(t *testing.T) {
+		var calls []string
+		var waiting []int
+		opts := *options
+		opts.OnWaiting = func(name string, attempt int, err error) {
+			waiting = append(waiting, attempt)
+		}
+		err := WaitForDependencies(context.Background(), &opts,
+			&testDependency{name: "registry", failures: 2, calls: &calls},
+			&testDependency{name: "db", calls: &calls},
+		)
+		if err != nil {
+			t.Fatalf("WaitForDependencies = %v", err)
+		}
+		if want := []string{"registry", "registry", "registry", "db"}; !reflect.DeepEqual(calls, want) {
+			t.Errorf("calls = %v, want %v", calls, want)
+		}
+		if want := []int{1, 2}; !reflect.DeepEqual(waiting, want) {
+			t.Errorf("waiting = %v, want %v", waiting, want)
+		}
+	})
+
+	t.Run("超时返回未就绪的依赖", func(t *testing.T) {
+		var calls []string
+		opts := *options
+		opts.Timeout = 20 * time.Millisecond
+		err := WaitForDependencies(context.Background(), &opts,
+			&testDependency{name: "registry", calls: &calls},
+			&testDependency{name: "order-service", failures: 1 << 30, calls: &calls},
+		)
+		if err == nil || !strings.Contains(err.Error(), "order-service unavailable") {
+			t.Errorf("WaitForDependencies = %v, want order-service error", err)
+		}
+	})
+
+	t.Run("ctx 取消", func(t *testing.T) {
+		var calls []string
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := WaitForDependencies(ctx, options, &testDependency{name: "db", failures: 1, calls: &calls})
+		if err == nil || len(calls) != 1 {
+			t.Errorf("WaitForDependencies = %v after %d checks, want error after 1", err, len(calls))
+		}
+	})
+}
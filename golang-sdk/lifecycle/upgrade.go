@@ -0,0 +1,264 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 热重启时传递给新进程的环境变量
+const (
+	// EnvListeners 继承的监听器，逗号分隔的 network/address，依次对应文件描述符 3、4……
+	EnvListeners = "FRAMEWORK_LISTENERS"
+	// EnvReadyFD 新进程就绪后通知旧进程的管道文件描述符
+	EnvReadyFD = "FRAMEWORK_READY_FD"
+)
+
+// upgradingKey 热重启关闭时 context 中的标记
+type upgradingKey struct{}
+
+// Upgrading 返回本次关闭是否由热重启触发；此时新进程已注册相同的服务实例，关闭钩子不应注销服务
+func Upgrading(ctx context.Context) bool {
+	upgrading, _ := ctx.Value(upgradingKey{}).(bool)
+	return upgrading
+}
+
+// defaultListeners 进程内的监听器集合，Listen、Ready 和 Upgrade 共用
+var defaultListeners = &listenerSet{}
+
+// Listen 监听 network/address，由热重启启动时优先使用旧进程交接的监听器
+//
+// 协议处理器和指标服务器均应通过 Listen 创建监听器，热重启时才能交接给新进程
+func Listen(network, address string) (net.Listener, error) {
+	return defaultListeners.listen(network, address)
+}
+
+// SetReusePort 设置之后 Listen 新建的监听器是否启用 SO_REUSEPORT，启用后同一主机上的多个进程可以监听同一端口，
+// 由内核在进程间分配连接；仅支持 Linux 和 BSD（包括 macOS），热重启继承的监听器不受影响
+func SetReusePort(enabled bool) {
+	defaultListeners.mu.Lock()
+	defer defaultListeners.mu.Unlock()
+	defaultListeners.reusePort = enabled
+}
+
+// Inherited 返回当前进程是否由热重启启动
+func Inherited() bool {
+	defaultListeners.load()
+	return defaultListeners.ready != nil
+}
+
+// Ready 通知旧进程当前进程已就绪，旧进程随后停止接受请求并退出；未被交接的监听器同时关闭
+//
+// 服务启动完成后调用，不是由热重启启动时不做任何操作
+func Ready() error {
+	return defaultListeners.notifyReady()
+}
+
+// Upgrade 以当前的可执行文件和命令行参数启动新进程，并交接所有通过 Listen 创建的监听器
+//
+// 新进程调用 Ready 后返回 nil；新进程提前退出或 ctx 取消时结束新进程并返回错误，当前进程继续提供服务。
+// 仅支持 Linux 和 macOS
+func Upgrade(ctx context.Context) error {
+	return defaultListeners.upgrade(ctx)
+}
+
+// listenerSet 可交接的监听器
+type listenerSet struct {
+	mu        sync.Mutex
+	loaded    bool
+	inherited map[string]*os.File
+	ready     *os.File
+	active    map[string]net.Listener
+	reusePort bool
+}
+
+// load 读取旧进程交接的监听器，只执行一次；读取后清除环境变量，避免传给业务启动的子进程
+func (s *listenerSet) load() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.inherited = make(map[string]*os.File)
+	s.active = make(map[string]net.Listener)
+
+	if keys := os.Getenv(EnvListeners); keys != "" {
+		for i, key := range strings.Split(keys, ",") {
+			s.inherited[key] = os.NewFile(uintptr(3+i), key)
+		}
+	}
+	if fd, err := strconv.Atoi(os.Getenv(EnvReadyFD)); err == nil {
+		s.ready = os.NewFile(uintptr(fd), "ready")
+	}
+	os.Unsetenv(EnvListeners)
+	os.Unsetenv(EnvReadyFD)
+}
+
+// listen 优先使用继承的监听器
+func (s *listenerSet) listen(network, address string) (net.Listener, error) {
+	s.load()
+	key := network + "/" + address
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ln net.Listener
+	var err error
+	if f, ok := s.inherited[key]; ok {
+		delete(s.inherited, key)
+		ln, err = net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inherit listener %s: %w", key, err)
+		}
+	} else {
+		var lc net.ListenConfig
+		if s.reusePort {
+			lc.Control = reusePortControl
+		}
+		if ln, err = lc.Listen(context.Background(), network, address); err != nil {
+			return nil, err
+		}
+	}
+	s.active[key] = ln
+	return &trackedListener{Listener: ln, set: s, key: key}, nil
+}
+
+// notifyReady 通知旧进程并关闭未使用的继承监听器
+func (s *listenerSet) notifyReady() error {
+	s.load()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, f := range s.inherited {
+		f.Close()
+		delete(s.inherited, key)
+	}
+	if s.ready == nil {
+		return nil
+	}
+	_, err := s.ready.Write([]byte{1})
+	s.ready.Close()
+	s.ready = nil
+	return err
+}
+
+// upgrade 启动新进程并等待其就绪
+func (s *listenerSet) upgrade(ctx context.Context) error {
+	s.load()
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	files, keys, err := s.files()
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyReader.Close()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	cmd.Env = append(upgradeEnviron(),
+		EnvListeners+"="+strings.Join(keys, ","),
+		EnvReadyFD+"="+strconv.Itoa(3+len(files)))
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start new process: %w", err)
+	}
+
+	// 新进程退出时管道的写端随之关闭，Read 返回 EOF
+	ready := make(chan bool, 1)
+	go func() {
+		buf := make([]byte, 1)
+		n, _ := readyReader.Read(buf)
+		ready <- n == 1
+	}()
+
+	select {
+	case ok := <-ready:
+		if ok {
+			return cmd.Process.Release()
+		}
+		err := cmd.Wait()
+		return fmt.Errorf("new process exited before ready: %v", err)
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("new process not ready: %w", ctx.Err())
+	}
+}
+
+// files 复制活动监听器的文件描述符
+func (s *listenerSet) files() ([]*os.File, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var files []*os.File
+	var keys []string
+	for key, ln := range s.active {
+		filer, ok := ln.(interface{ File() (*os.File, error) })
+		if !ok {
+			return files, nil, fmt.Errorf("listener %s cannot be handed over", key)
+		}
+		f, err := filer.File()
+		if err != nil {
+			return files, nil, fmt.Errorf("failed to duplicate listener %s: %w", key, err)
+		}
+		files = append(files, f)
+		keys = append(keys, key)
+	}
+	return files, keys, nil
+}
+
+// remove 监听器关闭后不再交接
+func (s *listenerSet) remove(key string, ln net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active[key] == ln {
+		delete(s.active, key)
+	}
+}
+
+// trackedListener 关闭时从监听器集合中移除
+type trackedListener struct {
+	net.Listener
+	set  *listenerSet
+	key  string
+	once sync.Once
+}
+
+// Close 关闭监听器；已交接给新进程的监听器只关闭当前进程的文件描述符，新进程继续接受连接
+func (l *trackedListener) Close() error {
+	l.once.Do(func() { l.set.remove(l.key, l.Listener) })
+	return l.Listener.Close()
+}
+
+// upgradeEnviron 返回去除热重启变量后的环境变量
+func upgradeEnviron() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, EnvListeners+"=") || strings.HasPrefix(kv, EnvReadyFD+"=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
@@ -0,0 +1,128 @@
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// envChildMode 测试进程作为热重启的新进程时的行为：serve 或 fail
+const envChildMode = "LIFECYCLE_TEST_CHILD"
+
+func TestMain(m *testing.M) {
+	if Inherited() {
+		runChild()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runChild 新进程：使用继承的监听器应答一个连接
+func runChild() {
+	if os.Getenv(envChildMode) == "fail" {
+		os.Exit(1)
+	}
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.Exit(2)
+	}
+	if err := Ready(); err != nil {
+		os.Exit(3)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		os.Exit(4)
+	}
+	conn.Write([]byte("new"))
+	conn.Close()
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("交接监听器", func(t *testing.T) {
+		t.Setenv(envChildMode, "serve")
+		ln, err := Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Listen failed: %v", err)
+		}
+		defer ln.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := Upgrade(ctx); err != nil {
+			t.Fatalf("Upgrade failed: %v", err)
+		}
+
+		// 旧进程关闭监听器后，新进程在同一端口继续接受连接
+		ln.Close()
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		reply, err := io.ReadAll(conn)
+		if err != nil || string(reply) != "new" {
+			t.Errorf("reply = %q, %v; want new", reply, err)
+		}
+	})
+
+	t.Run("新进程未就绪", func(t *testing.T) {
+		t.Setenv(envChildMode, "fail")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := Upgrade(ctx); err == nil {
+			t.Error("expected Upgrade to fail when the new process exits")
+		}
+	})
+}
+
+func TestUpgrading(t *testing.T) {
+	m := NewManager(nil)
+	upgrading := make(chan bool, 1)
+	m.Register(PhaseDeregister, "registry", func(ctx context.Context) error {
+		upgrading <- Upgrading(ctx)
+		return nil
+	})
+	m.Shutdown(context.Background())
+	if <-upgrading {
+		t.Error("expected Upgrading to be false for a regular shutdown")
+	}
+	if err := m.Upgrade(context.Background()); err == nil {
+		t.Error("expected Upgrade after Shutdown to fail")
+	}
+}
+
+func TestListenReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT is not supported on windows")
+	}
+	first, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer first.Close()
+	address := first.Addr().String()
+
+	// 未启用时端口已被占用
+	if ln, err := Listen("tcp", address); err == nil {
+		ln.Close()
+		t.Fatal("expected second Listen without SO_REUSEPORT to fail")
+	}
+
+	SetReusePort(true)
+	defer SetReusePort(false)
+	a, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen with SO_REUSEPORT failed: %v", err)
+	}
+	defer a.Close()
+	b, err := Listen("tcp", a.Addr().String())
+	if err != nil {
+		t.Fatalf("second Listen with SO_REUSEPORT failed: %v", err)
+	}
+	defer b.Close()
+}
@@ -0,0 +1,78 @@
+package longrunning
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// 查询和取消操作的方法名后缀，完整方法名为 <prefix>.get 等
+const (
+	MethodGet    = "get"
+	MethodPoll   = "poll"
+	MethodCancel = "cancel"
+)
+
+// Handlers 返回查询和取消操作的处理函数，键为方法名，逐个通过 server.Handle 注册
+//
+// 参数为 {"id": "...", "waitMs": 5000} 或按位置传递的 ["...", 5000]，waitMs 只对 poll 有效
+func (m *Manager) Handlers(prefix string) map[string]func(ctx context.Context, params interface{}) (interface{}, error) {
+	return map[string]func(ctx context.Context, params interface{}) (interface{}, error){
+		prefix + "." + MethodGet: func(ctx context.Context, params interface{}) (interface{}, error) {
+			req, err := decodeRequest(params)
+			if err != nil {
+				return nil, err
+			}
+			return m.Get(ctx, req.ID)
+		},
+		prefix + "." + MethodPoll: func(ctx context.Context, params interface{}) (interface{}, error) {
+			req, err := decodeRequest(params)
+			if err != nil {
+				return nil, err
+			}
+			return m.Poll(ctx, req.ID, time.Duration(req.WaitMs)*time.Millisecond)
+		},
+		prefix + "." + MethodCancel: func(ctx context.Context, params interface{}) (interface{}, error) {
+			req, err := decodeRequest(params)
+			if err != nil {
+				return nil, err
+			}
+			return m.Cancel(ctx, req.ID)
+		},
+	}
+}
+
+// request get/poll/cancel 方法的参数
+type request struct {
+	ID     string `json:"id"`
+	WaitMs int64  `json:"waitMs"`
+}
+
+// decodeRequest 解析命名或按位置传递的参数
+func decodeRequest(params interface{}) (*request, error) {
+	req := &request{}
+	switch p := params.(type) {
+	case string:
+		req.ID = p
+	case []interface{}:
+		if len(p) > 0 {
+			req.ID, _ = p[0].(string)
+		}
+		if len(p) > 1 {
+			if wait, ok := p[1].(float64); ok {
+				req.WaitMs = int64(wait)
+			}
+		}
+	default:
+		data, err := json.Marshal(params)
+		if err != nil || json.Unmarshal(data, req) != nil {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "invalid operation request")
+		}
+	}
+	if req.ID == "" {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "operation id is required")
+	}
+	return req, nil
+}
@@ -0,0 +1,393 @@
+// Package longrunning 长时间运行操作的服务端辅助
+//
+// 处理函数立即返回操作 ID，任务在后台执行，调用方通过 get/poll/cancel 方法查询结果或取消操作。
+// 操作状态保存在可替换的 Store 中，操作结束时向事件总线发布完成事件
+package longrunning
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// 默认配置
+const (
+	DefaultTopic        = "operation.completed"
+	DefaultRetention    = 24 * time.Hour
+	DefaultPollInterval = time.Second
+	DefaultMaxWait      = 30 * time.Second
+)
+
+// Job 在后台执行的任务，返回值以 JSON 编码保存为操作结果
+type Job func(ctx context.Context, params interface{}) (interface{}, error)
+
+// Publisher 发布完成事件，*messaging.Bus 实现了该接口
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// Options Manager 配置
+type Options struct {
+	// Store 操作状态存储，默认为 MemoryStore；多实例部署时应使用共享存储
+	Store Store
+	// Publisher 操作结束时发布完成事件，事件内容为 Operation，为 nil 时不发布
+	Publisher Publisher
+	// Topic 完成事件的主题，默认为 DefaultTopic
+	Topic string
+	// Retention 操作结束后保留的时间，之后从 Store 中删除；默认为 DefaultRetention，小于 0 时不删除
+	Retention time.Duration
+	// PollInterval 长轮询时查询 Store 的间隔，操作在本实例执行时结束后立即返回
+	PollInterval time.Duration
+	// MaxWait 单次长轮询的最长等待时间，默认为 DefaultMaxWait
+	MaxWait time.Duration
+}
+
+// Manager 管理长时间运行的操作
+type Manager struct {
+	store        Store
+	publisher    Publisher
+	topic        string
+	retention    time.Duration
+	pollInterval time.Duration
+	maxWait      time.Duration
+
+	mu      sync.Mutex
+	running map[string]*execution
+	timers  map[string]*time.Timer
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// execution 本实例正在执行的操作
+type execution struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewManager 创建操作管理器
+func NewManager(opts *Options) *Manager {
+	if opts == nil {
+		opts = &Options{}
+	}
+	m := &Manager{
+		store:        opts.Store,
+		publisher:    opts.Publisher,
+		topic:        opts.Topic,
+		retention:    opts.Retention,
+		pollInterval: opts.PollInterval,
+		maxWait:      opts.MaxWait,
+		running:      make(map[string]*execution),
+		timers:       make(map[string]*time.Timer),
+	}
+	if m.store == nil {
+		m.store = NewMemoryStore()
+	}
+	if m.topic == "" {
+		m.topic = DefaultTopic
+	}
+	if m.retention == 0 {
+		m.retention = DefaultRetention
+	}
+	if m.pollInterval <= 0 {
+		m.pollInterval = DefaultPollInterval
+	}
+	if m.maxWait <= 0 {
+		m.maxWait = DefaultMaxWait
+	}
+	return m
+}
+
+// Async 将任务包装为处理函数，处理函数立即返回 running 状态的操作，任务在后台执行
+//
+// method 记录在 Operation.Method 中，通常与注册的方法名相同
+func (m *Manager) Async(method string, job Job) func(ctx context.Context, params interface{}) (interface{}, error) {
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		return m.Start(ctx, method, params, job)
+	}
+}
+
+// Start 创建操作并在后台执行任务
+//
+// 任务的 context 保留 ctx 中的值（追踪上下文、元数据等），但不随请求结束而取消
+func (m *Manager) Start(ctx context.Context, method string, params interface{}, job Job) (*Operation, error) {
+	now := time.Now()
+	op := &Operation{
+		ID:        newOperationID(),
+		Method:    method,
+		Status:    StatusRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "operation manager is closed")
+	}
+	if err := m.store.Save(ctx, op); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	exec := &execution{cancel: cancel, done: make(chan struct{})}
+	m.running[op.ID] = exec
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	jobCtx = context.WithValue(jobCtx, operationKey{}, &operationRef{manager: m, id: op.ID, cancel: cancel})
+	go m.run(jobCtx, op.clone(), exec, params, job)
+	return op, nil
+}
+
+// run 执行任务并保存结果
+func (m *Manager) run(ctx context.Context, op *Operation, exec *execution, params interface{}, job Job) {
+	defer m.wg.Done()
+	defer close(exec.done)
+	defer exec.cancel()
+
+	result, err := m.invoke(ctx, params, job)
+	if err == nil {
+		op.Status = StatusSucceeded
+		op.Progress = 100
+		if op.Result, err = json.Marshal(result); err != nil {
+			err = frameworkerrors.NewFrameworkErrorWithCause(frameworkerrors.SerializationError, "failed to encode operation result", err)
+		}
+	}
+	if err != nil {
+		op.Status = StatusFailed
+		op.Result = nil
+		if m.isClosed() && ctx.Err() != nil {
+			err = frameworkerrors.NewFrameworkErrorWithCause(frameworkerrors.ServiceUnavailable, "operation interrupted by shutdown", err)
+		}
+		op.Error = frameworkerrors.PayloadFromError(err)
+	}
+	m.finish(context.WithoutCancel(ctx), op)
+
+	m.mu.Lock()
+	delete(m.running, op.ID)
+	m.mu.Unlock()
+}
+
+// invoke 调用任务，任务 panic 时返回内部错误
+func (m *Manager) invoke(ctx context.Context, params interface{}, job Job) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = frameworkerrors.NewFrameworkError(frameworkerrors.InternalError, fmt.Sprintf("operation panicked: %v", r))
+		}
+	}()
+	return job(ctx, params)
+}
+
+// finish 保存结束状态，操作已被取消时保留取消状态；随后发布完成事件并安排删除
+func (m *Manager) finish(ctx context.Context, op *Operation) {
+	m.mu.Lock()
+	current, err := m.store.Load(ctx, op.ID)
+	if err == nil && current.Status.Finished() {
+		m.mu.Unlock()
+		return
+	}
+	if err == nil {
+		op.CreatedAt = current.CreatedAt
+	}
+	op.UpdatedAt = time.Now()
+	err = m.store.Save(ctx, op)
+	m.mu.Unlock()
+	if err != nil {
+		return
+	}
+	m.completed(ctx, op)
+}
+
+// completed 发布完成事件并安排在保留时间后删除操作
+func (m *Manager) completed(ctx context.Context, op *Operation) {
+	if m.publisher != nil {
+		m.publisher.Publish(ctx, m.topic, op)
+	}
+	if m.retention < 0 {
+		return
+	}
+	id := op.ID
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.timers[id] = time.AfterFunc(m.retention, func() {
+		m.mu.Lock()
+		delete(m.timers, id)
+		m.mu.Unlock()
+		m.store.Delete(context.Background(), id)
+	})
+}
+
+// Get 返回操作的当前状态，不存在时返回 NotFound 错误
+func (m *Manager) Get(ctx context.Context, id string) (*Operation, error) {
+	op, err := m.store.Load(ctx, id)
+	if errors.Is(err, ErrOperationNotFound) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("operation %s not found", id))
+	}
+	return op, err
+}
+
+// Poll 等待操作结束，最多等待 wait（不超过 MaxWait），返回当时的状态
+//
+// 超时返回 running 状态的操作而不是错误，调用方据此再次轮询；wait 为 0 时等同于 Get
+func (m *Manager) Poll(ctx context.Context, id string, wait time.Duration) (*Operation, error) {
+	if wait > m.maxWait {
+		wait = m.maxWait
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		op, err := m.Get(ctx, id)
+		if err != nil || op.Status.Finished() || wait <= 0 {
+			return op, err
+		}
+
+		var done chan struct{}
+		m.mu.Lock()
+		if exec, ok := m.running[id]; ok {
+			done = exec.done
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ticker.C:
+		case <-deadline.C:
+			return m.Get(ctx, id)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Cancel 取消操作，操作在本实例执行时同时取消任务的 context；已结束的操作保持原状态
+//
+// 操作在其他实例执行时只更新 Store 中的状态，任务下一次调用 ReportProgress 时被取消
+func (m *Manager) Cancel(ctx context.Context, id string) (*Operation, error) {
+	m.mu.Lock()
+	op, err := m.store.Load(ctx, id)
+	if err != nil {
+		m.mu.Unlock()
+		if errors.Is(err, ErrOperationNotFound) {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("operation %s not found", id))
+		}
+		return nil, err
+	}
+	if op.Status.Finished() {
+		m.mu.Unlock()
+		return op, nil
+	}
+	op.Status = StatusCancelled
+	op.UpdatedAt = time.Now()
+	if err := m.store.Save(ctx, op); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if exec, ok := m.running[id]; ok {
+		exec.cancel()
+	}
+	m.mu.Unlock()
+
+	m.completed(ctx, op)
+	return op, nil
+}
+
+// Close 取消本实例正在执行的任务并等待其结束，这些操作以 ServiceUnavailable 错误标记为失败
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	for _, exec := range m.running {
+		exec.cancel()
+	}
+	for id, timer := range m.timers {
+		timer.Stop()
+		delete(m.timers, id)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isClosed 判断管理器是否已关闭
+func (m *Manager) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// operationKey 任务 context 中当前操作的键
+type operationKey struct{}
+
+// operationRef 任务 context 中的当前操作
+type operationRef struct {
+	manager *Manager
+	id      string
+	cancel  context.CancelFunc
+}
+
+// OperationID 返回任务 context 中的操作 ID，不在任务中时返回空字符串
+func OperationID(ctx context.Context) string {
+	if ref, ok := ctx.Value(operationKey{}).(*operationRef); ok {
+		return ref.id
+	}
+	return ""
+}
+
+// ReportProgress 在任务中更新操作进度（0-100）
+//
+// 操作已被取消时（包括在其他实例上取消）取消任务的 context 并返回 context.Canceled，任务应随即返回
+func ReportProgress(ctx context.Context, progress int) error {
+	ref, ok := ctx.Value(operationKey{}).(*operationRef)
+	if !ok {
+		return nil
+	}
+	if progress < 0 {
+		progress = 0
+	} else if progress > 100 {
+		progress = 100
+	}
+
+	m := ref.manager
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	op, err := m.store.Load(ctx, ref.id)
+	if err != nil {
+		return err
+	}
+	if op.Status.Finished() {
+		ref.cancel()
+		return context.Canceled
+	}
+	op.Progress = progress
+	op.UpdatedAt = time.Now()
+	return m.store.Save(ctx, op)
+}
+
+// newOperationID 生成随机的操作 ID
+func newOperationID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
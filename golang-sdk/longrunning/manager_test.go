@@ -0,0 +1,306 @@
+package longrunning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// recordingPublisher 记录发布的完成事件
+type recordingPublisher struct {
+	mu     sync.Mutex
+	topics []string
+	events []*Operation
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, topic string, event interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topics = append(p.topics, topic)
+	p.events = append(p.events, event.(*Operation).clone())
+	return nil
+}
+
+func (p *recordingPublisher) snapshot() ([]string, []*Operation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.topics...), append([]*Operation(nil), p.events...)
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	declined := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "report too large")
+
+	tests := []struct {
+		name       string
+		job        Job
+		wantStatus Status
+		wantResult string
+		wantCode   frameworkerrors.ErrorCode
+	}{
+		{
+			name: "执行成功",
+			job: func(ctx context.Context, params interface{}) (interface{}, error) {
+				return map[string]string{"url": "/reports/1"}, nil
+			},
+			wantStatus: StatusSucceeded,
+			wantResult: `{"url":"/reports/1"}`,
+		},
+		{
+			name: "执行失败",
+			job: func(ctx context.Context, params interface{}) (interface{}, error) {
+				return nil, declined
+			},
+			wantStatus: StatusFailed,
+			wantCode:   frameworkerrors.BadRequest,
+		},
+		{
+			name: "任务 panic",
+			job: func(ctx context.Context, params interface{}) (interface{}, error) {
+				panic("boom")
+			},
+			wantStatus: StatusFailed,
+			wantCode:   frameworkerrors.InternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			m := NewManager(&Options{Publisher: publisher, PollInterval: 10 * time.Millisecond})
+			defer m.Close(context.Background())
+
+			result, err := m.Async("report.generate", tt.job)(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			started := result.(*Operation)
+			if started.ID == "" || started.Status != StatusRunning || started.Method != "report.generate" {
+				t.Fatalf("unexpected operation: %+v", started)
+			}
+
+			op, err := m.Poll(context.Background(), started.ID, time.Second)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if op.Status != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s", tt.wantStatus, op.Status)
+			}
+			if tt.wantResult != "" && string(op.Result) != tt.wantResult {
+				t.Errorf("expected result %s, got %s", tt.wantResult, op.Result)
+			}
+			if tt.wantCode != 0 && (op.Error == nil || op.Error.Code != tt.wantCode.Code()) {
+				t.Errorf("expected error code %d, got %+v", tt.wantCode, op.Error)
+			}
+
+			topics, events := publisher.snapshot()
+			if len(events) != 1 || topics[0] != DefaultTopic || events[0].ID != started.ID || events[0].Status != tt.wantStatus {
+				t.Errorf("unexpected completion events: %v %+v", topics, events)
+			}
+		})
+	}
+}
+
+func TestManagerPollTimeout(t *testing.T) {
+	m := NewManager(&Options{PollInterval: 10 * time.Millisecond})
+	defer m.Close(context.Background())
+
+	release := make(chan struct{})
+	op, _ := m.Start(context.Background(), "slow", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		<-release
+		return "done", nil
+	})
+
+	polled, err := m.Poll(context.Background(), op.ID, 30*time.Millisecond)
+	if err != nil || polled.Status != StatusRunning {
+		t.Fatalf("expected running operation after timeout, got %+v, %v", polled, err)
+	}
+
+	close(release)
+	polled, err = m.Poll(context.Background(), op.ID, time.Second)
+	if err != nil || polled.Status != StatusSucceeded || string(polled.Result) != `"done"` {
+		t.Fatalf("expected succeeded operation, got %+v, %v", polled, err)
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	publisher := &recordingPublisher{}
+	m := NewManager(&Options{Publisher: publisher})
+	defer m.Close(context.Background())
+
+	started := make(chan struct{})
+	op, _ := m.Start(context.Background(), "export", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	cancelled, err := m.Cancel(context.Background(), op.ID)
+	if err != nil || cancelled.Status != StatusCancelled {
+		t.Fatalf("expected cancelled operation, got %+v, %v", cancelled, err)
+	}
+
+	// 任务返回后不覆盖取消状态，也不再发布完成事件
+	polled, err := m.Poll(context.Background(), op.ID, time.Second)
+	if err != nil || polled.Status != StatusCancelled || polled.Error != nil {
+		t.Fatalf("cancelled status should be kept, got %+v, %v", polled, err)
+	}
+	m.Close(context.Background())
+	if _, events := publisher.snapshot(); len(events) != 1 || events[0].Status != StatusCancelled {
+		t.Errorf("expected one cancelled event, got %+v", events)
+	}
+
+	if _, err := m.Cancel(context.Background(), "missing"); !isCode(err, frameworkerrors.NotFound) {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestReportProgress(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewManager(&Options{Store: store})
+	defer m.Close(context.Background())
+
+	reported := make(chan struct{})
+	resume := make(chan struct{})
+	result := make(chan error, 1)
+	op, _ := m.Start(context.Background(), "import", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		if OperationID(ctx) == "" {
+			t.Error("operation id should be available in job context")
+		}
+		ReportProgress(ctx, 40)
+		close(reported)
+		<-resume
+		err := ReportProgress(ctx, 80)
+		result <- err
+		return nil, err
+	})
+
+	<-reported
+	if got, _ := m.Get(context.Background(), op.ID); got.Progress != 40 {
+		t.Errorf("expected progress 40, got %d", got.Progress)
+	}
+
+	// 模拟其他实例取消操作：只修改存储中的状态
+	stored, _ := store.Load(context.Background(), op.ID)
+	stored.Status = StatusCancelled
+	store.Save(context.Background(), stored)
+	close(resume)
+
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	m := NewManager(nil)
+
+	op, _ := m.Start(context.Background(), "long", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := m.Get(context.Background(), op.ID)
+	if got.Status != StatusFailed || got.Error == nil || got.Error.Code != frameworkerrors.ServiceUnavailable.Code() {
+		t.Errorf("interrupted operation should fail with ServiceUnavailable, got %+v", got)
+	}
+	if _, err := m.Start(context.Background(), "long", nil, nil); !isCode(err, frameworkerrors.ServiceUnavailable) {
+		t.Errorf("expected ServiceUnavailable after close, got %v", err)
+	}
+}
+
+func TestManagerRetention(t *testing.T) {
+	m := NewManager(&Options{Retention: 20 * time.Millisecond})
+	defer m.Close(context.Background())
+
+	op, _ := m.Start(context.Background(), "quick", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if polled, _ := m.Poll(context.Background(), op.ID, time.Second); polled.Status != StatusSucceeded {
+		t.Fatalf("expected succeeded operation, got %+v", polled)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := m.Get(context.Background(), op.ID); isCode(err, frameworkerrors.NotFound) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("operation should be deleted after retention")
+}
+
+func TestHandlers(t *testing.T) {
+	m := NewManager(nil)
+	defer m.Close(context.Background())
+
+	op, _ := m.Start(context.Background(), "quick", nil, func(ctx context.Context, params interface{}) (interface{}, error) {
+		return 42, nil
+	})
+	handlers := m.Handlers("operations")
+
+	tests := []struct {
+		name     string
+		method   string
+		params   string
+		wantCode frameworkerrors.ErrorCode
+	}{
+		{"命名参数", "operations.poll", `{"id":"` + op.ID + `","waitMs":1000}`, 0},
+		{"位置参数", "operations.poll", `["` + op.ID + `",1000]`, 0},
+		{"查询", "operations.get", `{"id":"` + op.ID + `"}`, 0},
+		{"取消已结束的操作", "operations.cancel", `{"id":"` + op.ID + `"}`, 0},
+		{"缺少 ID", "operations.get", `{}`, frameworkerrors.BadRequest},
+		{"操作不存在", "operations.get", `{"id":"missing"}`, frameworkerrors.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params interface{}
+			json.Unmarshal([]byte(tt.params), &params)
+
+			result, err := handlers[tt.method](context.Background(), params)
+			if tt.wantCode != 0 {
+				if !isCode(err, tt.wantCode) {
+					t.Errorf("expected %s, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := result.(*Operation); got.Status != StatusSucceeded || string(got.Result) != "42" {
+				t.Errorf("unexpected operation: %+v", got)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	op := &Operation{ID: "op-1", Status: StatusSucceeded, Result: json.RawMessage(`{"a":1}`)}
+	store.Save(ctx, op)
+	op.Result[1] = 'x'
+
+	loaded, err := store.Load(ctx, "op-1")
+	if err != nil || string(loaded.Result) != `{"a":1}` {
+		t.Fatalf("store should keep a copy, got %+v, %v", loaded, err)
+	}
+
+	store.Delete(ctx, "op-1")
+	if _, err := store.Load(ctx, "op-1"); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("expected ErrOperationNotFound, got %v", err)
+	}
+}
+
+func isCode(err error, code frameworkerrors.ErrorCode) bool {
+	fe, ok := frameworkerrors.FromError(err)
+	return ok && fe.Code == code
+}
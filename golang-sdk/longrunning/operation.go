@@ -0,0 +1,109 @@
+package longrunning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// ErrOperationNotFound 操作不存在或已过保留时间被删除
+var ErrOperationNotFound = errors.New("operation not found")
+
+// Status 操作状态
+type Status string
+
+const (
+	// StatusRunning 正在执行
+	StatusRunning Status = "running"
+	// StatusSucceeded 执行成功，Result 为结果
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed 执行失败，Error 为原因
+	StatusFailed Status = "failed"
+	// StatusCancelled 已被取消
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished 判断操作是否已结束
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Operation 长时间运行的操作，以 JSON 返回给调用方并保存到 Store
+type Operation struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	Status Status `json:"status"`
+	// Progress 执行进度（0-100），由任务通过 ReportProgress 更新
+	Progress int `json:"progress"`
+	// Result 执行成功时的结果
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error 执行失败时的结构化错误，与同步调用返回的错误格式相同
+	Error     *frameworkerrors.ErrorPayload `json:"error,omitempty"`
+	CreatedAt time.Time                     `json:"createdAt"`
+	UpdatedAt time.Time                     `json:"updatedAt"`
+}
+
+// clone 深拷贝操作
+func (o *Operation) clone() *Operation {
+	copied := *o
+	copied.Result = append(json.RawMessage(nil), o.Result...)
+	if o.Error != nil {
+		payload := *o.Error
+		copied.Error = &payload
+	}
+	return &copied
+}
+
+// Store 操作状态存储
+//
+// 多实例部署时应使用共享存储，任一实例都能查询和取消操作
+type Store interface {
+	// Save 保存操作状态，已存在时覆盖
+	Save(ctx context.Context, op *Operation) error
+	// Load 读取操作状态，不存在时返回 ErrOperationNotFound
+	Load(ctx context.Context, id string) (*Operation, error)
+	// Delete 删除操作，不存在时返回 nil
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore 进程内操作存储，用于测试和单实例部署，进程退出后状态丢失
+type MemoryStore struct {
+	mu         sync.RWMutex
+	operations map[string]*Operation
+}
+
+// NewMemoryStore 创建进程内操作存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{operations: make(map[string]*Operation)}
+}
+
+// Save 保存操作状态的副本
+func (s *MemoryStore) Save(ctx context.Context, op *Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations[op.ID] = op.clone()
+	return nil
+}
+
+// Load 读取操作状态的副本
+func (s *MemoryStore) Load(ctx context.Context, id string) (*Operation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	op, ok := s.operations[id]
+	if !ok {
+		return nil, ErrOperationNotFound
+	}
+	return op.clone(), nil
+}
+
+// Delete 删除操作
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.operations, id)
+	return nil
+}
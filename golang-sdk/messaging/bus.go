@@ -0,0 +1,142 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/serializer"
+)
+
+// Options Bus 选项
+type Options struct {
+	// Serializers 序列化器注册表，为 nil 时使用默认注册表
+	Serializers *serializer.SerializerRegistry
+	// Format 发布事件使用的序列化格式，默认为 JSON
+	Format serializer.SerializationFormat
+	// Source 发布方服务名，写入 source 消息头
+	Source string
+	// OnError 处理器返回错误时调用，可用于记录日志
+	OnError func(ctx context.Context, msg *Message, err error)
+	// DeadLetters 死信队列，不为 nil 时处理失败的事件写入死信队列而不由 Broker 重新投递，见 DeadLetterQueue
+	DeadLetters *DeadLetterQueue
+}
+
+// Bus 事件总线，按序列化器注册表编码事件，并通过消息头传播追踪上下文、baggage 和语言偏好
+type Bus struct {
+	broker      Broker
+	serializers *serializer.SerializerRegistry
+	format      serializer.SerializationFormat
+	source      string
+	onError     func(ctx context.Context, msg *Message, err error)
+	deadLetters *DeadLetterQueue
+}
+
+// NewBus 创建事件总线
+func NewBus(broker Broker, opts *Options) *Bus {
+	if opts == nil {
+		opts = &Options{}
+	}
+	bus := &Bus{
+		broker:      broker,
+		serializers: opts.Serializers,
+		format:      opts.Format,
+		source:      opts.Source,
+		onError:     opts.OnError,
+		deadLetters: opts.DeadLetters,
+	}
+	if bus.serializers == nil {
+		bus.serializers = serializer.NewSerializerRegistry()
+	}
+	if bus.format == "" {
+		bus.format = serializer.JSON
+	}
+	return bus
+}
+
+// Broker 返回底层消息中间件
+func (b *Bus) Broker() Broker {
+	return b.broker
+}
+
+// Publish 编码事件并发布到 topic，ctx 中的追踪上下文写入消息头
+func (b *Bus) Publish(ctx context.Context, topic string, event interface{}) error {
+	s, err := b.serializers.Get(b.format)
+	if err != nil {
+		return err
+	}
+	payload, err := s.Serialize(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event for %s: %w", topic, err)
+	}
+
+	ctx, span := adapter.StartProducerSpan(ctx, b.broker.Name(), topic)
+	msg := &Message{
+		ID:    newMessageID(),
+		Topic: topic,
+		Headers: map[string]string{
+			HeaderContentType: string(b.format),
+		},
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}
+	msg.Headers[HeaderMessageID] = msg.ID
+	msg.Headers[HeaderTimestamp] = msg.Timestamp.UTC().Format(time.RFC3339Nano)
+	if b.source != "" {
+		msg.Headers[HeaderSource] = b.source
+	}
+	adapter.InjectTraceContext(ctx, msg.Headers)
+
+	err = b.broker.Publish(ctx, msg)
+	adapter.EndSpan(span, err)
+	return err
+}
+
+// EventHandler 事件处理器，ctx 携带发布方的追踪上下文
+type EventHandler func(ctx context.Context, event *Event) error
+
+// Subscribe 订阅 topic，group 的含义见 Broker
+func (b *Bus) Subscribe(topic, group string, handler EventHandler) (Subscription, error) {
+	var h Handler = func(ctx context.Context, msg *Message) error {
+		ctx = adapter.ExtractTraceContext(ctx, msg.Headers)
+		ctx, span := adapter.StartConsumerSpan(ctx, b.broker.Name(), msg.Topic, group)
+		err := handler(ctx, &Event{Message: msg, serializers: b.serializers})
+		adapter.EndSpan(span, err)
+		if err != nil && b.onError != nil {
+			b.onError(ctx, msg, err)
+		}
+		return err
+	}
+	if b.deadLetters != nil {
+		h = b.deadLetters.Wrap(topic, group, h)
+	}
+	return b.broker.Subscribe(topic, group, h)
+}
+
+// Close 关闭底层消息中间件
+func (b *Bus) Close() error {
+	return b.broker.Close()
+}
+
+// Event 订阅方收到的事件
+type Event struct {
+	*Message
+	serializers *serializer.SerializerRegistry
+}
+
+// Decode 按发布方写入的 content-type 消息头解码负载，消息头缺失时按 JSON 解码
+func (e *Event) Decode(target interface{}) error {
+	format := serializer.SerializationFormat(e.Headers[HeaderContentType])
+	if format == "" {
+		format = serializer.JSON
+	}
+	s, err := e.serializers.Get(format)
+	if err != nil {
+		return err
+	}
+	if err := s.Deserialize(e.Payload, target); err != nil {
+		return fmt.Errorf("failed to decode event from %s: %w", e.Topic, err)
+	}
+	return nil
+}
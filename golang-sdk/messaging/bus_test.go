@@ -0,0 +1,107 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/serializer"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type orderCreated struct {
+	OrderID string `json:"orderId"`
+	Amount  int    `json:"amount"`
+}
+
+func TestBusPublishSubscribe(t *testing.T) {
+	bus := NewBus(NewMemoryBroker(), &Options{Source: "order-service"})
+	defer bus.Close()
+
+	var received orderCreated
+	var event *Event
+	_, err := bus.Subscribe("order.created", "", func(ctx context.Context, e *Event) error {
+		event = e
+		return e.Decode(&received)
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	if err := bus.Publish(context.Background(), "order.created", orderCreated{OrderID: "o-1", Amount: 42}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if event == nil {
+		t.Fatal("event was not delivered")
+	}
+	if received.OrderID != "o-1" || received.Amount != 42 {
+		t.Errorf("unexpected event: %+v", received)
+	}
+	if event.ID == "" || event.Headers[HeaderMessageID] != event.ID {
+		t.Errorf("message id = %q, header = %q", event.ID, event.Headers[HeaderMessageID])
+	}
+	if event.Headers[HeaderContentType] != string(serializer.JSON) {
+		t.Errorf("content-type = %q, want json", event.Headers[HeaderContentType])
+	}
+	if event.Headers[HeaderSource] != "order-service" {
+		t.Errorf("source = %q, want order-service", event.Headers[HeaderSource])
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("timestamp should be set")
+	}
+}
+
+func TestBusPropagatesTraceContext(t *testing.T) {
+	bus := NewBus(NewMemoryBroker(), nil)
+	defer bus.Close()
+
+	var traceID string
+	bus.Subscribe("order.created", "", func(ctx context.Context, e *Event) error {
+		traceID = trace.SpanContextFromContext(ctx).TraceID().String()
+		return nil
+	})
+
+	ctx := adapter.ExtractTraceContext(context.Background(), map[string]string{
+		adapter.HeaderTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	if err := bus.Publish(ctx, "order.created", orderCreated{OrderID: "o-1"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want 4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	}
+}
+
+func TestBusOnError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	var reported error
+	bus := NewBus(NewMemoryBroker(), &Options{
+		OnError: func(ctx context.Context, msg *Message, err error) {
+			reported = err
+		},
+	})
+	defer bus.Close()
+
+	bus.Subscribe("order.created", "", func(ctx context.Context, e *Event) error {
+		return handlerErr
+	})
+	if err := bus.Publish(context.Background(), "order.created", orderCreated{}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if !errors.Is(reported, handlerErr) {
+		t.Errorf("reported error = %v, want %v", reported, handlerErr)
+	}
+}
+
+func TestBusUnsupportedFormat(t *testing.T) {
+	bus := NewBus(NewMemoryBroker(), &Options{Format: "yaml"})
+	defer bus.Close()
+
+	if err := bus.Publish(context.Background(), "order.created", orderCreated{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
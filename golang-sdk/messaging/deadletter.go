@@ -0,0 +1,281 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// HeaderDeadLetterReplay 重放死信时写入的消息头，值为第几次重放，处理器可据此区分重放的消息
+const HeaderDeadLetterReplay = "dlq-replay"
+
+// ErrDeadLetterNotFound 死信不存在（可能已重放成功或被删除）
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter 处理失败的消息及其错误上下文
+type DeadLetter struct {
+	// ID 死信 ID，即消息 ID，消息没有 ID 时另行生成
+	ID      string            `json:"id"`
+	Topic   string            `json:"topic"`
+	Group   string            `json:"group,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload []byte            `json:"payload"`
+	// Error 最近一次处理失败的错误消息，ErrorCode 为其框架错误码
+	Error     string `json:"error"`
+	ErrorCode int    `json:"errorCode,omitempty"`
+	// Attempts 处理失败的次数，首次失败为 1，每次重放失败加 1
+	Attempts  int       `json:"attempts"`
+	Timestamp time.Time `json:"timestamp"` // 消息的发布时间
+	FailedAt  time.Time `json:"failedAt"`  // 最近一次处理失败的时间
+}
+
+// message 还原为消息，用于重放
+func (d *DeadLetter) message() *Message {
+	headers := make(map[string]string, len(d.Headers)+1)
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	return &Message{
+		ID:        d.ID,
+		Topic:     d.Topic,
+		Headers:   headers,
+		Payload:   d.Payload,
+		Timestamp: d.Timestamp,
+	}
+}
+
+// clone 复制死信，存储内外的修改互不影响
+func (d *DeadLetter) clone() *DeadLetter {
+	copied := *d
+	copied.Headers = d.message().Headers
+	copied.Payload = append([]byte(nil), d.Payload...)
+	return &copied
+}
+
+// fail 记录处理失败的错误上下文
+func (d *DeadLetter) fail(err error) {
+	payload := frameworkerrors.PayloadFromError(err)
+	d.Error = payload.Message
+	d.ErrorCode = payload.Code
+	d.FailedAt = time.Now()
+}
+
+// DeadLetterStore 死信存储
+//
+// 多实例部署时应使用共享的持久化存储（如 RedisDeadLetterStore），任一实例都能列出和重放全部死信
+type DeadLetterStore interface {
+	// Save 保存死信，ID 已存在时覆盖
+	Save(ctx context.Context, letter *DeadLetter) error
+	// Get 读取死信，不存在时返回 ErrDeadLetterNotFound
+	Get(ctx context.Context, id string) (*DeadLetter, error)
+	// List 按失败时间列出死信，最早的在前；topic 为空时列出全部主题，limit 大于 0 时最多返回 limit 条
+	List(ctx context.Context, topic string, limit int) ([]*DeadLetter, error)
+	// Delete 删除死信，不存在时不返回错误
+	Delete(ctx context.Context, id string) error
+}
+
+// sortDeadLetters 按失败时间排序并按主题和数量筛选
+func sortDeadLetters(letters []*DeadLetter, topic string, limit int) []*DeadLetter {
+	filtered := letters[:0]
+	for _, letter := range letters {
+		if topic == "" || letter.Topic == topic {
+			filtered = append(filtered, letter)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].FailedAt.Before(filtered[j].FailedAt) })
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
+
+// MemoryDeadLetterStore 进程内死信存储，用于测试和单进程部署，进程退出后死信丢失
+type MemoryDeadLetterStore struct {
+	mu      sync.RWMutex
+	letters map[string]*DeadLetter
+}
+
+// NewMemoryDeadLetterStore 创建进程内死信存储
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{letters: make(map[string]*DeadLetter)}
+}
+
+// Save 保存死信的副本
+func (s *MemoryDeadLetterStore) Save(ctx context.Context, letter *DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters[letter.ID] = letter.clone()
+	return nil
+}
+
+// Get 读取死信的副本
+func (s *MemoryDeadLetterStore) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	letter, ok := s.letters[id]
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+	return letter.clone(), nil
+}
+
+// List 按失败时间列出死信的副本
+func (s *MemoryDeadLetterStore) List(ctx context.Context, topic string, limit int) ([]*DeadLetter, error) {
+	s.mu.RLock()
+	letters := make([]*DeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		letters = append(letters, letter.clone())
+	}
+	s.mu.RUnlock()
+	return sortDeadLetters(letters, topic, limit), nil
+}
+
+// Delete 删除死信
+func (s *MemoryDeadLetterStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.letters, id)
+	return nil
+}
+
+// ReplayResult 一条死信的重放结果
+type ReplayResult struct {
+	ID string `json:"id"`
+	// Error 重放失败的原因，为空表示处理成功、死信已删除
+	Error string `json:"error,omitempty"`
+}
+
+// DeadLetterQueue 死信队列，捕获异步处理器处理失败的消息并支持重放
+//
+// Wrap 包装的处理器返回错误时，消息连同错误上下文写入 DeadLetterStore，处理器视为成功，
+// Broker 不再重新投递（写入失败时仍返回原错误，由 Broker 决定是否重新投递）。
+// 修复处理器的缺陷或下游故障恢复后，Replay 将选中的死信交给原主题和订阅组的处理器重新处理。
+// 重放不经过 Broker，只能在订阅了该主题的实例上进行；处理器应当幂等
+type DeadLetterQueue struct {
+	store DeadLetterStore
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewDeadLetterQueue 创建死信队列，store 为 nil 时使用进程内存储
+func NewDeadLetterQueue(store DeadLetterStore) *DeadLetterQueue {
+	if store == nil {
+		store = NewMemoryDeadLetterStore()
+	}
+	return &DeadLetterQueue{
+		store:    store,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Store 返回死信存储
+func (q *DeadLetterQueue) Store() DeadLetterStore {
+	return q.store
+}
+
+// handlerKey 返回主题和订阅组对应的处理器键
+func handlerKey(topic, group string) string {
+	return topic + "\x00" + group
+}
+
+// Wrap 包装订阅 topic 的处理器，处理失败的消息写入死信存储；同一主题和订阅组后包装的处理器用于重放
+func (q *DeadLetterQueue) Wrap(topic, group string, handler Handler) Handler {
+	q.mu.Lock()
+	q.handlers[handlerKey(topic, group)] = handler
+	q.mu.Unlock()
+
+	return func(ctx context.Context, msg *Message) error {
+		err := handler(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		letter := &DeadLetter{
+			ID:        msg.ID,
+			Topic:     msg.Topic,
+			Group:     group,
+			Headers:   msg.Headers,
+			Payload:   msg.Payload,
+			Attempts:  1,
+			Timestamp: msg.Timestamp,
+		}
+		if letter.ID == "" {
+			letter.ID = newMessageID()
+		}
+		if letter.Topic == "" {
+			letter.Topic = topic
+		}
+		letter.fail(err)
+		if saveErr := q.store.Save(context.Background(), letter); saveErr != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// List 按失败时间列出死信，参数含义见 DeadLetterStore.List
+func (q *DeadLetterQueue) List(ctx context.Context, topic string, limit int) ([]*DeadLetter, error) {
+	return q.store.List(ctx, topic, limit)
+}
+
+// Delete 删除死信，不再重放
+func (q *DeadLetterQueue) Delete(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		if err := q.store.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay 将死信交给原主题和订阅组的处理器重新处理，按 ids 的顺序返回每条死信的结果
+//
+// 处理成功的死信被删除；失败的死信更新错误上下文和失败次数后保留。重放的消息去掉 RPC 的响应主题和截止时间
+// （原请求方已不再等待），并带上 HeaderDeadLetterReplay 消息头。读写存储出错时返回错误
+func (q *DeadLetterQueue) Replay(ctx context.Context, ids ...string) ([]ReplayResult, error) {
+	results := make([]ReplayResult, 0, len(ids))
+	for _, id := range ids {
+		letter, err := q.store.Get(ctx, id)
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			results = append(results, ReplayResult{ID: id, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+
+		q.mu.RLock()
+		handler := q.handlers[handlerKey(letter.Topic, letter.Group)]
+		q.mu.RUnlock()
+		if handler == nil {
+			results = append(results, ReplayResult{ID: id,
+				Error: fmt.Sprintf("no handler subscribed to %s in this instance", letter.Topic)})
+			continue
+		}
+
+		msg := letter.message()
+		delete(msg.Headers, HeaderReplyTo)
+		delete(msg.Headers, HeaderDeadline)
+		msg.Headers[HeaderDeadLetterReplay] = strconv.Itoa(letter.Attempts)
+		if err := handler(ctx, msg); err != nil {
+			letter.Attempts++
+			letter.fail(err)
+			if saveErr := q.store.Save(ctx, letter); saveErr != nil {
+				return results, saveErr
+			}
+			results = append(results, ReplayResult{ID: id, Error: letter.Error})
+			continue
+		}
+		if err := q.store.Delete(ctx, id); err != nil {
+			return results, err
+		}
+		results = append(results, ReplayResult{ID: id})
+	}
+	return results, nil
+}
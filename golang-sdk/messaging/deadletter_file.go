@@ -0,0 +1,112 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileDeadLetterStore 基于本地目录的死信存储，进程重启后死信保留
+//
+// 每条死信以 JSON 写入目录下的一个文件（<转义后的 ID>.json），先写临时文件再重命名，进程崩溃不会留下不完整的死信
+type FileDeadLetterStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileDeadLetterStore 创建死信存储，目录不存在时创建
+func NewFileDeadLetterStore(dir string) (*FileDeadLetterStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("dead letter directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dead letter directory: %w", err)
+	}
+	return &FileDeadLetterStore{dir: dir}, nil
+}
+
+// path 返回死信的文件路径，ID 经转义后不含路径分隔符
+func (s *FileDeadLetterStore) path(id string) string {
+	return filepath.Join(s.dir, url.PathEscape(id)+".json")
+}
+
+// Save 写入死信文件
+func (s *FileDeadLetterStore) Save(ctx context.Context, letter *DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(s.dir, ".dead-letter-*")
+	if err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(letter.ID)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	return nil
+}
+
+// Get 读取死信文件
+func (s *FileDeadLetterStore) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, fmt.Errorf("failed to decode dead letter %s: %w", id, err)
+	}
+	return &letter, nil
+}
+
+// List 读取目录下的全部死信文件，按失败时间排序；无法解析的文件被跳过
+func (s *FileDeadLetterStore) List(ctx context.Context, topic string, limit int) ([]*DeadLetter, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	letters := make([]*DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			continue // 已被并发删除
+		}
+		var letter DeadLetter
+		if json.Unmarshal(data, &letter) == nil {
+			letters = append(letters, &letter)
+		}
+	}
+	return sortDeadLetters(letters, topic, limit), nil
+}
+
+// Delete 删除死信文件
+func (s *FileDeadLetterStore) Delete(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	return nil
+}
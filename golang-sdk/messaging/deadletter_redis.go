@@ -0,0 +1,142 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/resp"
+)
+
+// DefaultDeadLetterKey RedisDeadLetterStore 存放死信的默认哈希键
+const DefaultDeadLetterKey = "framework:dead-letters"
+
+// RedisDeadLetterStore 基于 Redis 的死信存储，多个实例共用
+//
+// 全部死信存放在一个哈希中，字段为死信 ID，值为死信的 JSON；List 以 HGETALL 读取后排序，适用于死信数量有限的场景
+type RedisDeadLetterStore struct {
+	config RedisConfig
+	key    string
+
+	mu     sync.Mutex
+	conn   *resp.Conn
+	closed bool
+}
+
+// NewRedisDeadLetterStore 连接 Redis，config 中只使用连接相关的配置，key 为空时使用 DefaultDeadLetterKey
+func NewRedisDeadLetterStore(config *RedisConfig, key string) (*RedisDeadLetterStore, error) {
+	cfg := RedisConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:6379"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if key == "" {
+		key = DefaultDeadLetterKey
+	}
+
+	conn, err := dialRedis(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisDeadLetterStore{config: cfg, key: key, conn: conn}, nil
+}
+
+// Save 以 HSET 写入死信
+func (s *RedisDeadLetterStore) Save(ctx context.Context, letter *DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+	_, err = s.do("HSET", s.key, letter.ID, data)
+	return err
+}
+
+// Get 以 HGET 读取死信
+func (s *RedisDeadLetterStore) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	reply, err := s.do("HGET", s.key, id)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, fmt.Errorf("failed to decode dead letter %s: %w", id, err)
+	}
+	return &letter, nil
+}
+
+// List 以 HGETALL 读取全部死信，按失败时间排序；无法解析的死信被跳过
+func (s *RedisDeadLetterStore) List(ctx context.Context, topic string, limit int) ([]*DeadLetter, error) {
+	reply, err := s.do("HGETALL", s.key)
+	if err != nil {
+		return nil, err
+	}
+	fields, _ := reply.([]interface{})
+	letters := make([]*DeadLetter, 0, len(fields)/2)
+	for i := 1; i < len(fields); i += 2 {
+		data, _ := fields[i].([]byte)
+		var letter DeadLetter
+		if json.Unmarshal(data, &letter) == nil {
+			letters = append(letters, &letter)
+		}
+	}
+	return sortDeadLetters(letters, topic, limit), nil
+}
+
+// Delete 以 HDEL 删除死信
+func (s *RedisDeadLetterStore) Delete(ctx context.Context, id string) error {
+	_, err := s.do("HDEL", s.key, id)
+	return err
+}
+
+// Close 关闭连接
+func (s *RedisDeadLetterStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// do 发送命令，连接断开时重新连接
+func (s *RedisDeadLetterStore) do(args ...interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.New("dead letter store closed")
+	}
+	if s.conn == nil {
+		conn, err := dialRedis(&s.config)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	reply, err := s.conn.Do(0, args...)
+	if err != nil {
+		if resp.ErrorOf(err) == "" {
+			s.conn.Close()
+			s.conn = nil
+		}
+		return nil, fmt.Errorf("dead letter store: %w", err)
+	}
+	return reply, nil
+}
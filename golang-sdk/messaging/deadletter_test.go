@@ -0,0 +1,179 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// testDeadLetterStore 测试死信存储的通用行为
+func testDeadLetterStore(t *testing.T, store DeadLetterStore) {
+	ctx := context.Background()
+	now := time.Now()
+	letters := []*DeadLetter{
+		{ID: "m/2", Topic: "order.created", Payload: []byte("b"), Error: "boom", Attempts: 1, FailedAt: now.Add(time.Second)},
+		{ID: "m-1", Topic: "order.created", Headers: map[string]string{"k": "v"}, Payload: []byte("a"), Attempts: 1, FailedAt: now},
+		{ID: "m-3", Topic: "order.paid", Payload: []byte("c"), Attempts: 2, FailedAt: now.Add(2 * time.Second)},
+	}
+	for _, letter := range letters {
+		if err := store.Save(ctx, letter); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	letter, err := store.Get(ctx, "m-1")
+	if err != nil || string(letter.Payload) != "a" || letter.Headers["k"] != "v" {
+		t.Fatalf("Get = %+v, %v", letter, err)
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("Expected ErrDeadLetterNotFound, got %v", err)
+	}
+
+	list, err := store.List(ctx, "order.created", 0)
+	if err != nil || len(list) != 2 || list[0].ID != "m-1" || list[1].ID != "m/2" {
+		t.Fatalf("List = %v, %v", list, err)
+	}
+	if list, _ := store.List(ctx, "", 2); len(list) != 2 || list[1].ID != "m/2" {
+		t.Errorf("Expected the 2 earliest letters, got %v", list)
+	}
+
+	if err := store.Delete(ctx, "m/2"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := store.Delete(ctx, "m/2"); err != nil {
+		t.Errorf("Delete of missing letter failed: %v", err)
+	}
+	if list, _ := store.List(ctx, "", 0); len(list) != 2 {
+		t.Errorf("Expected 2 letters after delete, got %d", len(list))
+	}
+	store.Delete(ctx, "m-1")
+	store.Delete(ctx, "m-3")
+}
+
+func TestMemoryDeadLetterStore(t *testing.T) {
+	testDeadLetterStore(t, NewMemoryDeadLetterStore())
+}
+
+func TestFileDeadLetterStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileDeadLetterStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileDeadLetterStore failed: %v", err)
+	}
+	testDeadLetterStore(t, store)
+
+	// 死信在重新打开存储后保留
+	store.Save(context.Background(), &DeadLetter{ID: "m-4", Topic: "order.created", FailedAt: time.Now()})
+	reopened, _ := NewFileDeadLetterStore(dir)
+	if letter, err := reopened.Get(context.Background(), "m-4"); err != nil || letter.Topic != "order.created" {
+		t.Errorf("Get after reopen = %+v, %v", letter, err)
+	}
+}
+
+func TestRedisDeadLetterStore(t *testing.T) {
+	store, err := NewRedisDeadLetterStore(nil, fmt.Sprintf("framework-test:dead-letters:%d", time.Now().UnixNano()))
+	if err != nil {
+		t.Skipf("Skipping test: redis not available: %v", err)
+	}
+	defer store.Close()
+
+	testDeadLetterStore(t, store)
+}
+
+func TestDeadLetterQueueReplay(t *testing.T) {
+	queue := NewDeadLetterQueue(nil)
+	bus := NewBus(NewMemoryBroker(), &Options{DeadLetters: queue})
+	defer bus.Close()
+	ctx := context.Background()
+
+	healthy := false
+	var replayed *Event
+	if _, err := bus.Subscribe("order.created", "billing", func(ctx context.Context, e *Event) error {
+		if !healthy {
+			return frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "billing database unavailable")
+		}
+		replayed = e
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	if err := bus.Publish(ctx, "order.created", orderCreated{OrderID: "o-1", Amount: 42}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	letters, err := queue.List(ctx, "", 0)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %v, %v", letters, err)
+	}
+	letter := letters[0]
+	if letter.Topic != "order.created" || letter.Group != "billing" || letter.Attempts != 1 ||
+		letter.Error != "billing database unavailable" || letter.ErrorCode != int(frameworkerrors.ServiceUnavailable) {
+		t.Errorf("Unexpected dead letter: %+v", letter)
+	}
+
+	// 仍然失败时更新失败次数并保留
+	results, err := queue.Replay(ctx, letter.ID, "missing")
+	if err != nil || len(results) != 2 || results[0].Error == "" || results[1].Error == "" {
+		t.Fatalf("Replay = %v, %v", results, err)
+	}
+	if letter, _ := queue.Store().Get(ctx, letter.ID); letter.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", letter.Attempts)
+	}
+
+	healthy = true
+	results, err = queue.Replay(ctx, letter.ID)
+	if err != nil || len(results) != 1 || results[0].Error != "" {
+		t.Fatalf("Replay = %v, %v", results, err)
+	}
+	var order orderCreated
+	if replayed == nil || replayed.Decode(&order) != nil || order.OrderID != "o-1" {
+		t.Fatalf("Replayed event = %+v", replayed)
+	}
+	if replayed.Headers[HeaderDeadLetterReplay] != "2" {
+		t.Errorf("%s = %q, want 2", HeaderDeadLetterReplay, replayed.Headers[HeaderDeadLetterReplay])
+	}
+	if letters, _ := queue.List(ctx, "", 0); len(letters) != 0 {
+		t.Errorf("Expected dead letter removed after successful replay, got %v", letters)
+	}
+}
+
+func TestRPCServerDeadLetters(t *testing.T) {
+	broker := NewMemoryBroker()
+	defer broker.Close()
+	queue := NewDeadLetterQueue(nil)
+
+	server := NewRPCServer(broker, "billing", func(ctx context.Context, r *adapter.InternalRequest) (interface{}, error) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.InternalError, "charge failed")
+	})
+	server.SetDeadLetterQueue(queue)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Stop(context.Background())
+
+	// 需要响应的请求的错误返回给请求方
+	client := newRPCClient(t, broker)
+	if err := client.Call(context.Background(), "billing", "billing.charge", orderCreated{OrderID: "o-1"}, nil); err == nil {
+		t.Fatal("Expected call error")
+	}
+	if letters, _ := queue.List(context.Background(), "", 0); len(letters) != 0 {
+		t.Fatalf("Expected no dead letters for request-reply, got %v", letters)
+	}
+
+	// 单向请求处理失败时写入死信队列
+	broker.Publish(context.Background(), &Message{
+		ID:      "m-1",
+		Topic:   RequestTopic("billing"),
+		Headers: map[string]string{HeaderMethod: "billing.charge"},
+		Payload: []byte(`{"orderId":"o-2"}`),
+	})
+	letters, _ := queue.List(context.Background(), RequestTopic("billing"), 0)
+	if len(letters) != 1 || letters[0].ID != "m-1" || letters[0].Group != "billing" || letters[0].Error != "charge failed" {
+		t.Fatalf("Unexpected dead letters: %v", letters)
+	}
+}
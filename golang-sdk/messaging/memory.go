@@ -0,0 +1,115 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker 进程内消息中间件，Publish 同步调用订阅方的处理器，用于测试和单进程部署
+//
+// 订阅组内按轮询选择订阅；处理器返回的错误被忽略，不会重新投递
+type MemoryBroker struct {
+	mu     sync.Mutex
+	subs   map[string][]*memorySubscription
+	cursor map[string]int
+	closed bool
+}
+
+// memorySubscription 进程内订阅
+type memorySubscription struct {
+	broker  *MemoryBroker
+	topic   string
+	group   string
+	handler Handler
+}
+
+// NewMemoryBroker 创建进程内消息中间件
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs:   make(map[string][]*memorySubscription),
+		cursor: make(map[string]int),
+	}
+}
+
+// Name 返回中间件名称
+func (b *MemoryBroker) Name() string {
+	return "memory"
+}
+
+// Publish 将消息投递给主题的订阅方，返回时处理器均已执行完毕
+func (b *MemoryBroker) Publish(ctx context.Context, msg *Message) error {
+	targets, err := b.targets(msg.Topic)
+	if err != nil {
+		return err
+	}
+	for _, sub := range targets {
+		sub.handler(context.Background(), copyMessage(msg))
+	}
+	return nil
+}
+
+// targets 选择接收消息的订阅：无订阅组的订阅全部接收，每个订阅组轮询选择一个
+func (b *MemoryBroker) targets(topic string) ([]*memorySubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	var targets []*memorySubscription
+	groups := make(map[string][]*memorySubscription)
+	var groupOrder []string
+	for _, sub := range b.subs[topic] {
+		if sub.group == "" {
+			targets = append(targets, sub)
+			continue
+		}
+		if _, ok := groups[sub.group]; !ok {
+			groupOrder = append(groupOrder, sub.group)
+		}
+		groups[sub.group] = append(groups[sub.group], sub)
+	}
+	for _, group := range groupOrder {
+		members := groups[group]
+		key := topic + "\x00" + group
+		targets = append(targets, members[b.cursor[key]%len(members)])
+		b.cursor[key]++
+	}
+	return targets, nil
+}
+
+// Subscribe 订阅主题
+func (b *MemoryBroker) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+	sub := &memorySubscription{broker: b, topic: topic, group: group, handler: handler}
+	b.subs[topic] = append(b.subs[topic], sub)
+	return sub, nil
+}
+
+// Close 取消所有订阅
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.subs = make(map[string][]*memorySubscription)
+	return nil
+}
+
+// Unsubscribe 取消订阅
+func (s *memorySubscription) Unsubscribe() error {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subs[s.topic]
+	for i, sub := range subs {
+		if sub == s {
+			b.subs[s.topic] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
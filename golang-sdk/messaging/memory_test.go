@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// recordingHandler 记录收到消息数量的处理器
+func recordingHandler(counts map[string]int, name string) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		counts[name]++
+		return nil
+	}
+}
+
+func TestMemoryBrokerDelivery(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups map[string]string // 订阅名 -> 订阅组
+		want   map[string]int
+	}{
+		{
+			name:   "无订阅组时广播",
+			groups: map[string]string{"a": "", "b": ""},
+			want:   map[string]int{"a": 4, "b": 4},
+		},
+		{
+			name:   "订阅组内轮询",
+			groups: map[string]string{"a": "billing", "b": "billing"},
+			want:   map[string]int{"a": 2, "b": 2},
+		},
+		{
+			name:   "不同订阅组各收到一份",
+			groups: map[string]string{"a": "billing", "b": "billing", "c": "audit", "d": ""},
+			want:   map[string]int{"a": 2, "b": 2, "c": 4, "d": 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := NewMemoryBroker()
+			defer broker.Close()
+
+			counts := make(map[string]int)
+			for name, group := range tt.groups {
+				if _, err := broker.Subscribe("order.created", group, recordingHandler(counts, name)); err != nil {
+					t.Fatalf("Subscribe failed: %v", err)
+				}
+			}
+			for i := 0; i < 4; i++ {
+				if err := broker.Publish(context.Background(), &Message{Topic: "order.created"}); err != nil {
+					t.Fatalf("Publish failed: %v", err)
+				}
+			}
+
+			for name, want := range tt.want {
+				if counts[name] != want {
+					t.Errorf("%s received %d messages, want %d", name, counts[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryBrokerUnsubscribe(t *testing.T) {
+	broker := NewMemoryBroker()
+	counts := make(map[string]int)
+
+	sub, _ := broker.Subscribe("order.created", "", recordingHandler(counts, "a"))
+	broker.Publish(context.Background(), &Message{Topic: "order.created"})
+	sub.Unsubscribe()
+	broker.Publish(context.Background(), &Message{Topic: "order.created"})
+
+	if counts["a"] != 1 {
+		t.Errorf("received %d messages, want 1", counts["a"])
+	}
+
+	broker.Close()
+	if err := broker.Publish(context.Background(), &Message{Topic: "order.created"}); !errors.Is(err, ErrBrokerClosed) {
+		t.Errorf("Publish after Close = %v, want ErrBrokerClosed", err)
+	}
+	if _, err := broker.Subscribe("order.created", "", recordingHandler(counts, "a")); !errors.Is(err, ErrBrokerClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrBrokerClosed", err)
+	}
+}
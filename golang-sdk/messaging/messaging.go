@@ -0,0 +1,103 @@
+// Package messaging 提供基于消息中间件的异步发布/订阅，服务除 RPC 外还可以发布和订阅事件。
+//
+// Broker 抽象具体的消息中间件，内置实现：
+//
+//   - MemoryBroker：进程内投递，用于测试和单进程部署
+//   - NATSBroker：NATS 核心协议，订阅组对应队列组
+//   - RedisStreamBroker：Redis Streams，订阅组对应消费者组，处理成功后确认
+//   - RedisPubSubBroker：Redis 发布/订阅，只投递给在线的订阅，用于多实例间的广播
+//
+// Kafka 的实现为 protocol/external/kafka 包的 Broker，经应用提供的 Kafka 客户端读写记录。
+//
+// Bus 在 Broker 之上按序列化器注册表编码事件，并通过消息头传播追踪上下文。
+package messaging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// 框架写入的消息头
+const (
+	// HeaderContentType 负载的序列化格式，订阅方按此格式解码
+	HeaderContentType = "content-type"
+	// HeaderMessageID 消息 ID
+	HeaderMessageID = "message-id"
+	// HeaderTimestamp 发布时间，RFC 3339 格式
+	HeaderTimestamp = "timestamp"
+	// HeaderSource 发布方服务名
+	HeaderSource = "source"
+)
+
+// ErrBrokerClosed Broker 已关闭
+var ErrBrokerClosed = errors.New("broker closed")
+
+// Message 消息中间件传输的消息
+type Message struct {
+	ID        string            // 消息 ID
+	Topic     string            // 主题
+	Headers   map[string]string // 消息头，包括追踪上下文
+	Payload   []byte            // 负载
+	Timestamp time.Time         // 发布时间
+}
+
+// Handler 消息处理器，返回错误时消息视为处理失败，是否重新投递取决于 Broker
+type Handler func(ctx context.Context, msg *Message) error
+
+// Subscription 订阅
+type Subscription interface {
+	// Unsubscribe 取消订阅，返回后不再投递新的消息
+	Unsubscribe() error
+}
+
+// Broker 消息中间件
+//
+// Subscribe 的 group 为空时每个订阅都收到主题的全部消息（广播）；
+// 不为空时同一组内的订阅共同消费，每条消息只投递给组内的一个订阅
+type Broker interface {
+	// Name 返回中间件名称，用于追踪属性，如 nats
+	Name() string
+	// Publish 发布消息
+	Publish(ctx context.Context, msg *Message) error
+	// Subscribe 订阅主题
+	Subscribe(topic, group string, handler Handler) (Subscription, error)
+	// Close 关闭连接并取消所有订阅
+	Close() error
+}
+
+// copyMessage 复制消息，各订阅方修改消息头互不影响
+func copyMessage(msg *Message) *Message {
+	copied := *msg
+	copied.Headers = make(map[string]string, len(msg.Headers))
+	for k, v := range msg.Headers {
+		copied.Headers[k] = v
+	}
+	return &copied
+}
+
+// messageFromHeaders 由消息头还原消息 ID 和发布时间，用于只传输消息头和负载的中间件
+func messageFromHeaders(topic string, headers map[string]string, payload []byte) *Message {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	msg := &Message{
+		ID:      headers[HeaderMessageID],
+		Topic:   topic,
+		Headers: headers,
+		Payload: payload,
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, headers[HeaderTimestamp]); err == nil {
+		msg.Timestamp = ts
+	}
+	return msg
+}
+
+// newMessageID 生成 128 位随机消息 ID
+func newMessageID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
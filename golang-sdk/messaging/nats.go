@@ -0,0 +1,495 @@
+package messaging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NATSConfig NATS 连接配置
+type NATSConfig struct {
+	// Address 服务器地址，默认为 127.0.0.1:4222
+	Address string
+	// Name 连接名称，显示在 NATS 监控中
+	Name string
+	// Username/Password 用户名密码认证
+	Username string
+	Password string
+	// Token 令牌认证
+	Token string
+	// Timeout 连接和握手超时，默认为 5 秒
+	Timeout time.Duration
+	// BufferSize 每个订阅待处理消息的缓冲数量，默认为 256
+	BufferSize int
+}
+
+// natsInfo 服务器 INFO 中使用的字段
+type natsInfo struct {
+	Headers    bool  `json:"headers"`
+	MaxPayload int64 `json:"max_payload"`
+}
+
+// NATSBroker 基于 NATS 核心协议的消息中间件，需要 NATS 2.2 及以上版本（支持消息头）
+//
+// 订阅组对应 NATS 队列组。核心协议不持久化消息，订阅前发布或处理失败的消息不会重新投递；
+// 连接断开或写入失败后连接被关闭，Publish 返回错误，订阅停止接收消息
+type NATSBroker struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	info   natsInfo
+	config NATSConfig
+
+	writeMu sync.Mutex
+	writer  *bufio.Writer
+
+	mu      sync.Mutex
+	subs    map[uint64]*natsSubscription
+	nextSID uint64
+	closed  bool
+	err     error
+	done    chan struct{}
+}
+
+// natsSubscription NATS 订阅，消息经缓冲队列由独立的 goroutine 处理，避免阻塞读取
+type natsSubscription struct {
+	broker  *NATSBroker
+	sid     uint64
+	topic   string
+	handler Handler
+	queue   chan *Message
+	quit    chan struct{}
+	stopped atomic.Bool
+	once    sync.Once
+}
+
+// NewNATSBroker 连接 NATS 服务器
+func NewNATSBroker(config *NATSConfig) (*NATSBroker, error) {
+	cfg := NATSConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:4222"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 256
+	}
+
+	conn, err := net.DialTimeout("tcp", cfg.Address, cfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.Address, err)
+	}
+	b := &NATSBroker{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+		config: cfg,
+		subs:   make(map[uint64]*natsSubscription),
+		done:   make(chan struct{}),
+	}
+	if err := b.handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go b.readLoop()
+	return b, nil
+}
+
+// handshake 读取 INFO，发送 CONNECT 并以 PING/PONG 确认连接可用
+func (b *NATSBroker) handshake() error {
+	b.conn.SetDeadline(time.Now().Add(b.config.Timeout))
+	defer b.conn.SetDeadline(time.Time{})
+
+	line, err := b.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read NATS INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting: %q", line)
+	}
+	if err := json.Unmarshal([]byte(line[len("INFO "):]), &b.info); err != nil {
+		return fmt.Errorf("invalid NATS INFO: %w", err)
+	}
+	if !b.info.Headers {
+		return fmt.Errorf("NATS server at %s does not support headers, version 2.2 or later is required", b.config.Address)
+	}
+
+	connect, _ := json.Marshal(map[string]interface{}{
+		"verbose":    false,
+		"pedantic":   false,
+		"lang":       "go",
+		"version":    "framework",
+		"protocol":   1,
+		"headers":    true,
+		"name":       b.config.Name,
+		"user":       b.config.Username,
+		"pass":       b.config.Password,
+		"auth_token": b.config.Token,
+	})
+	if _, err := fmt.Fprintf(b.writer, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return err
+	}
+	if err := b.writer.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		line, err := b.readLine()
+		if err != nil {
+			return fmt.Errorf("NATS handshake failed: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS handshake failed: %s", strings.TrimSpace(line[len("-ERR"):]))
+		}
+	}
+}
+
+// Name 返回中间件名称
+func (b *NATSBroker) Name() string {
+	return "nats"
+}
+
+// Publish 以 HPUB 发布消息，消息头随消息传输
+func (b *NATSBroker) Publish(ctx context.Context, msg *Message) error {
+	if err := validateSubject(msg.Topic); err != nil {
+		return err
+	}
+	header := encodeNATSHeaders(msg.Headers)
+	total := len(header) + len(msg.Payload)
+	if b.info.MaxPayload > 0 && int64(total) > b.info.MaxPayload {
+		return fmt.Errorf("message size %d exceeds NATS max payload %d", total, b.info.MaxPayload)
+	}
+
+	if err := b.checkOpen(); err != nil {
+		return err
+	}
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		b.conn.SetWriteDeadline(deadline)
+		defer b.conn.SetWriteDeadline(time.Time{})
+	}
+	fmt.Fprintf(b.writer, "HPUB %s %d %d\r\n", msg.Topic, len(header), total)
+	b.writer.Write(header)
+	b.writer.Write(msg.Payload)
+	b.writer.WriteString("\r\n")
+	// bufio.Writer 保留第一次写入的错误，Flush 返回写入或刷新中的任一错误
+	if err := b.writer.Flush(); err != nil {
+		b.fail(err)
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe 订阅主题，group 不为空时加入同名队列组；主题支持 NATS 通配符 * 和 >
+func (b *NATSBroker) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	if err := validateSubject(topic); err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(group, " \t\r\n") {
+		return nil, fmt.Errorf("invalid NATS queue group %q", group)
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrBrokerClosed
+	}
+	b.nextSID++
+	sub := &natsSubscription{
+		broker:  b,
+		sid:     b.nextSID,
+		topic:   topic,
+		handler: handler,
+		queue:   make(chan *Message, b.config.BufferSize),
+		quit:    make(chan struct{}),
+	}
+	b.subs[sub.sid] = sub
+	b.mu.Unlock()
+
+	command := fmt.Sprintf("SUB %s %d\r\n", topic, sub.sid)
+	if group != "" {
+		command = fmt.Sprintf("SUB %s %s %d\r\n", topic, group, sub.sid)
+	}
+	if err := b.write(command); err != nil {
+		b.removeSubscription(sub)
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	go sub.run()
+	return sub, nil
+}
+
+// Close 关闭连接并取消所有订阅
+func (b *NATSBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	err := b.conn.Close()
+	<-b.done
+	return err
+}
+
+// checkOpen 检查连接是否可用
+func (b *NATSBroker) checkOpen() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		if b.err != nil {
+			return fmt.Errorf("NATS connection lost: %w", b.err)
+		}
+		return ErrBrokerClosed
+	}
+	return nil
+}
+
+// write 发送协议命令
+func (b *NATSBroker) write(command string) error {
+	if err := b.checkOpen(); err != nil {
+		return err
+	}
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.writer.WriteString(command)
+	if err := b.writer.Flush(); err != nil {
+		b.fail(err)
+		return err
+	}
+	return nil
+}
+
+// fail 写入失败后连接中可能残留不完整的命令，关闭连接并将 Broker 标记为已关闭，之后的调用返回该错误
+func (b *NATSBroker) fail(err error) {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		b.err = err
+	}
+	b.mu.Unlock()
+	b.conn.Close()
+}
+
+// readLoop 读取服务器消息直到连接关闭，连接断开后停止所有订阅
+func (b *NATSBroker) readLoop() {
+	defer close(b.done)
+
+	err := b.read()
+
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		b.err = err
+		b.conn.Close()
+	}
+	subs := b.subs
+	b.subs = make(map[uint64]*natsSubscription)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+	}
+}
+
+// read 解析服务器发送的 MSG、HMSG、PING 等协议消息
+func (b *NATSBroker) read() error {
+	for {
+		line, err := b.readLine()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case line == "PING":
+			if err := b.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			args := strings.Fields(line[len("MSG "):])
+			if len(args) < 3 {
+				return fmt.Errorf("invalid NATS MSG: %q", line)
+			}
+			size, ok := b.payloadSize(args[len(args)-1])
+			if !ok {
+				return fmt.Errorf("invalid NATS MSG: %q", line)
+			}
+			payload, err := b.readPayload(size)
+			if err != nil {
+				return err
+			}
+			b.deliver(args[1], messageFromHeaders(args[0], nil, payload))
+		case strings.HasPrefix(line, "HMSG "):
+			// HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+			args := strings.Fields(line[len("HMSG "):])
+			if len(args) < 4 {
+				return fmt.Errorf("invalid NATS HMSG: %q", line)
+			}
+			headerSize, ok1 := b.payloadSize(args[len(args)-2])
+			total, ok2 := b.payloadSize(args[len(args)-1])
+			if !ok1 || !ok2 || headerSize > total {
+				return fmt.Errorf("invalid NATS HMSG: %q", line)
+			}
+			data, err := b.readPayload(total)
+			if err != nil {
+				return err
+			}
+			b.deliver(args[1], messageFromHeaders(args[0], decodeNATSHeaders(data[:headerSize]), data[headerSize:]))
+		case strings.HasPrefix(line, "-ERR"):
+			b.mu.Lock()
+			b.err = fmt.Errorf("NATS error: %s", strings.TrimSpace(line[len("-ERR"):]))
+			b.mu.Unlock()
+		}
+		// INFO、PONG、+OK 无需处理
+	}
+}
+
+// payloadSize 解析 MSG、HMSG 中的字节数，须不小于 0 且不超过服务器的 max_payload
+func (b *NATSBroker) payloadSize(s string) (int, bool) {
+	size, err := strconv.Atoi(s)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	if b.info.MaxPayload > 0 && int64(size) > b.info.MaxPayload {
+		return 0, false
+	}
+	return size, true
+}
+
+// deliver 将消息放入订阅的处理队列
+func (b *NATSBroker) deliver(sid string, msg *Message) {
+	id, err := strconv.ParseUint(sid, 10, 64)
+	if err != nil {
+		return
+	}
+	b.mu.Lock()
+	sub, ok := b.subs[id]
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+	// 队列已满时阻塞读取，由服务器按慢消费者处理
+	select {
+	case sub.queue <- msg:
+	case <-sub.quit:
+	}
+}
+
+// readLine 读取一行协议命令，不含行尾的 \r\n
+func (b *NATSBroker) readLine() (string, error) {
+	line, err := b.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPayload 读取指定长度的负载及其后的 \r\n
+func (b *NATSBroker) readPayload(size int) ([]byte, error) {
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(b.reader, data); err != nil {
+		return nil, err
+	}
+	return data[:size], nil
+}
+
+// removeSubscription 移除订阅并停止其处理 goroutine
+func (b *NATSBroker) removeSubscription(sub *natsSubscription) {
+	b.mu.Lock()
+	delete(b.subs, sub.sid)
+	b.mu.Unlock()
+	sub.stop()
+}
+
+// Unsubscribe 取消订阅，连接已关闭时直接返回
+func (s *natsSubscription) Unsubscribe() error {
+	if s.stopped.Load() {
+		return nil
+	}
+	s.broker.removeSubscription(s)
+	if s.broker.checkOpen() != nil {
+		return nil
+	}
+	return s.broker.write(fmt.Sprintf("UNSUB %d\r\n", s.sid))
+}
+
+// run 依次处理队列中的消息，取消订阅后丢弃尚未处理的消息
+func (s *natsSubscription) run() {
+	for {
+		select {
+		case msg := <-s.queue:
+			if s.stopped.Load() {
+				return
+			}
+			s.handler(context.Background(), msg)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// stop 停止接收消息
+func (s *natsSubscription) stop() {
+	s.once.Do(func() {
+		s.stopped.Store(true)
+		close(s.quit)
+	})
+}
+
+// validateSubject 检查 NATS 主题不包含空白字符
+func validateSubject(subject string) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject %q", subject)
+	}
+	return nil
+}
+
+// encodeNATSHeaders 编码 NATS/1.0 消息头
+func encodeNATSHeaders(headers map[string]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("NATS/1.0\r\n")
+	for k, v := range headers {
+		// 消息头不能包含换行
+		k = strings.NewReplacer("\r", "", "\n", "", ":", "").Replace(k)
+		v = strings.NewReplacer("\r", "", "\n", "").Replace(v)
+		buf.WriteString(k)
+		buf.WriteString(": ")
+		buf.WriteString(v)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// decodeNATSHeaders 解析 NATS/1.0 消息头，忽略状态行
+func decodeNATSHeaders(data []byte) map[string]string {
+	headers := make(map[string]string)
+	lines := strings.Split(string(data), "\r\n")
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
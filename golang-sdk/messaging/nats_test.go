@@ -0,0 +1,336 @@
+package messaging
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATSServer 实现 NATS 核心协议子集的测试服务器，主题精确匹配，队列组内轮询投递
+type fakeNATSServer struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	subs   []*fakeNATSSub
+	cursor map[string]int
+}
+
+// fakeNATSSub 测试服务器上的订阅
+type fakeNATSSub struct {
+	client *fakeNATSClient
+	sid    string
+	topic  string
+	group  string
+}
+
+// fakeNATSClient 测试服务器上的客户端连接
+type fakeNATSClient struct {
+	mu     sync.Mutex
+	writer *bufio.Writer
+}
+
+func (c *fakeNATSClient) send(format string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(c.writer, format, args...)
+	c.writer.Flush()
+}
+
+func startFakeNATSServer(t *testing.T) *fakeNATSServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeNATSServer{listener: listener, cursor: make(map[string]int)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	client := &fakeNATSClient{writer: bufio.NewWriter(conn)}
+	client.send("INFO {\"headers\":true,\"max_payload\":1048576}\r\n")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "PING":
+			client.send("PONG\r\n")
+		case "SUB":
+			sub := &fakeNATSSub{client: client, topic: args[1], sid: args[len(args)-1]}
+			if len(args) == 4 {
+				sub.group = args[2]
+			}
+			s.mu.Lock()
+			s.subs = append(s.subs, sub)
+			s.mu.Unlock()
+		case "UNSUB":
+			s.mu.Lock()
+			for i, sub := range s.subs {
+				if sub.client == client && sub.sid == args[1] {
+					s.subs = append(s.subs[:i], s.subs[i+1:]...)
+					break
+				}
+			}
+			s.mu.Unlock()
+		case "HPUB":
+			headerSize, _ := strconv.Atoi(args[2])
+			total, _ := strconv.Atoi(args[3])
+			data := make([]byte, total+2)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return
+			}
+			for _, sub := range s.targets(args[1]) {
+				sub.client.send("HMSG %s %s %d %d\r\n%s\r\n", args[1], sub.sid, headerSize, total, data[:total])
+			}
+		}
+	}
+}
+
+// targets 选择接收消息的订阅
+func (s *fakeNATSServer) targets(topic string) []*fakeNATSSub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var targets []*fakeNATSSub
+	groups := make(map[string][]*fakeNATSSub)
+	for _, sub := range s.subs {
+		if sub.topic != topic {
+			continue
+		}
+		if sub.group == "" {
+			targets = append(targets, sub)
+		} else {
+			groups[sub.group] = append(groups[sub.group], sub)
+		}
+	}
+	for group, members := range groups {
+		targets = append(targets, members[s.cursor[group]%len(members)])
+		s.cursor[group]++
+	}
+	return targets
+}
+
+// subscriptions 返回主题上的订阅数量
+func (s *fakeNATSServer) subscriptions(topic string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, sub := range s.subs {
+		if sub.topic == topic {
+			count++
+		}
+	}
+	return count
+}
+
+// waitFor 等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNATSBrokerPublishSubscribe(t *testing.T) {
+	server := startFakeNATSServer(t)
+	broker, err := NewNATSBroker(&NATSConfig{Address: server.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewNATSBroker failed: %v", err)
+	}
+	defer broker.Close()
+
+	received := make(chan *Message, 1)
+	_, err = broker.Subscribe("order.created", "", func(ctx context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	// 等待服务器处理 SUB
+	waitFor(t, func() bool { return server.subscriptions("order.created") == 1 })
+
+	err = broker.Publish(context.Background(), &Message{
+		Topic:   "order.created",
+		Headers: map[string]string{HeaderMessageID: "m-1", "traceparent": "00-abc-def-01"},
+		Payload: []byte(`{"orderId":"o-1"}`),
+	})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if msg.ID != "m-1" || msg.Topic != "order.created" {
+			t.Errorf("unexpected message: id=%q topic=%q", msg.ID, msg.Topic)
+		}
+		if msg.Headers["traceparent"] != "00-abc-def-01" {
+			t.Errorf("traceparent = %q", msg.Headers["traceparent"])
+		}
+		if string(msg.Payload) != `{"orderId":"o-1"}` {
+			t.Errorf("payload = %s", msg.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestNATSBrokerQueueGroup(t *testing.T) {
+	server := startFakeNATSServer(t)
+	broker, err := NewNATSBroker(&NATSConfig{Address: server.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewNATSBroker failed: %v", err)
+	}
+	defer broker.Close()
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	for _, name := range []string{"a", "b"} {
+		name := name
+		broker.Subscribe("order.created", "billing", func(ctx context.Context, msg *Message) error {
+			mu.Lock()
+			counts[name]++
+			mu.Unlock()
+			return nil
+		})
+	}
+	waitFor(t, func() bool { return server.subscriptions("order.created") == 2 })
+
+	for i := 0; i < 4; i++ {
+		broker.Publish(context.Background(), &Message{Topic: "order.created"})
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return counts["a"]+counts["b"] == 4
+	})
+	if counts["a"] != 2 || counts["b"] != 2 {
+		t.Errorf("counts = %v, want 2 each", counts)
+	}
+}
+
+func TestNATSBrokerClose(t *testing.T) {
+	server := startFakeNATSServer(t)
+	broker, err := NewNATSBroker(&NATSConfig{Address: server.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewNATSBroker failed: %v", err)
+	}
+
+	sub, _ := broker.Subscribe("order.created", "", func(ctx context.Context, msg *Message) error { return nil })
+	if err := broker.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := broker.Publish(context.Background(), &Message{Topic: "order.created"}); err != ErrBrokerClosed {
+		t.Errorf("Publish after Close = %v, want ErrBrokerClosed", err)
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe after Close = %v", err)
+	}
+}
+
+func TestNATSBrokerInvalidMessageSize(t *testing.T) {
+	for _, size := range []string{"-1", "1048577"} {
+		t.Run(size, func(t *testing.T) {
+			server := startFakeNATSServer(t)
+			broker, err := NewNATSBroker(&NATSConfig{Address: server.listener.Addr().String()})
+			if err != nil {
+				t.Fatalf("NewNATSBroker failed: %v", err)
+			}
+			defer broker.Close()
+
+			broker.Subscribe("order.created", "", func(ctx context.Context, msg *Message) error { return nil })
+			waitFor(t, func() bool { return server.subscriptions("order.created") == 1 })
+			server.mu.Lock()
+			client := server.subs[0].client
+			server.mu.Unlock()
+
+			// 超出 max_payload 或为负数的字节数使连接断开，不按该长度分配缓冲区
+			client.send("MSG order.created 1 %s\r\n", size)
+			waitFor(t, func() bool {
+				err := broker.Publish(context.Background(), &Message{Topic: "order.created"})
+				return err != nil && strings.Contains(err.Error(), "invalid NATS MSG")
+			})
+		})
+	}
+}
+
+func TestNATSBrokerPublishWriteFailure(t *testing.T) {
+	server := startFakeNATSServer(t)
+	broker, err := NewNATSBroker(&NATSConfig{Address: server.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewNATSBroker failed: %v", err)
+	}
+	defer broker.Close()
+
+	// 已过期的截止时间使写入失败，连接中可能残留不完整的 HPUB，之后的发布不再使用该连接
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if err := broker.Publish(ctx, &Message{Topic: "order.created", Payload: []byte("{}")}); err == nil {
+		t.Fatal("Expected Publish to fail after the write deadline")
+	}
+	err = broker.Publish(context.Background(), &Message{Topic: "order.created", Payload: []byte("{}")})
+	if err == nil || !strings.Contains(err.Error(), "NATS connection lost") {
+		t.Errorf("Publish after write failure = %v, want connection lost", err)
+	}
+	if _, err := broker.Subscribe("order.created", "", func(ctx context.Context, msg *Message) error { return nil }); err != ErrBrokerClosed {
+		t.Errorf("Subscribe after write failure = %v, want ErrBrokerClosed", err)
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		wantErr bool
+	}{
+		{name: "普通主题", subject: "order.created", wantErr: false},
+		{name: "通配符", subject: "order.>", wantErr: false},
+		{name: "空主题", subject: "", wantErr: true},
+		{name: "包含空格", subject: "order created", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSubject(tt.subject); (err != nil) != tt.wantErr {
+				t.Errorf("validateSubject(%q) error = %v, wantErr %v", tt.subject, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNATSHeadersRoundTrip(t *testing.T) {
+	headers := map[string]string{"traceparent": "00-abc-def-01", "content-type": "json"}
+	decoded := decodeNATSHeaders(encodeNATSHeaders(headers))
+	for k, v := range headers {
+		if decoded[k] != v {
+			t.Errorf("header %s = %q, want %q", k, decoded[k], v)
+		}
+	}
+}
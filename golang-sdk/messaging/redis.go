@@ -0,0 +1,442 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/resp"
+)
+
+// Redis Streams 消息条目的字段名
+const (
+	redisFieldHeaders = "headers"
+	redisFieldPayload = "payload"
+)
+
+// redisReadCount 每次 XREAD/XREADGROUP 读取的最大条目数
+const redisReadCount = 16
+
+// redisRetryInterval 订阅连接出错后的重连间隔
+const redisRetryInterval = time.Second
+
+// RedisConfig Redis Streams 连接配置
+type RedisConfig struct {
+	// Address 服务器地址，默认为 127.0.0.1:6379
+	Address string
+	// Username/Password 认证信息，Username 为空时使用旧版 AUTH password
+	Username string
+	Password string
+	// DB 数据库编号
+	DB int
+	// Consumer 消费者组内的消费者名称，默认为 主机名-进程号
+	Consumer string
+	// Block 订阅每次阻塞读取的最长时间，默认为 1 秒
+	Block time.Duration
+	// MaxLen 流的近似最大长度，大于 0 时 XADD 使用 MAXLEN ~ 裁剪旧消息
+	MaxLen int64
+	// Timeout 连接和命令超时，默认为 5 秒
+	Timeout time.Duration
+}
+
+// RedisStreamBroker 基于 Redis Streams 的消息中间件，需要 Redis 5.0 及以上版本
+//
+// 每个主题对应一个流，消息头以 JSON 写入 headers 字段，负载写入 payload 字段。
+// 无订阅组的订阅以 XREAD 从订阅时刻起读取；订阅组对应消费者组，处理成功后 XACK 确认，
+// 处理失败的消息保留在待处理列表中，消费者重新订阅时再次投递
+type RedisStreamBroker struct {
+	config RedisConfig
+
+	mu     sync.Mutex
+	conn   *resp.Conn
+	subs   map[*redisSubscription]struct{}
+	closed bool
+}
+
+// redisSubscription Redis Streams 订阅，使用独立的连接阻塞读取
+type redisSubscription struct {
+	broker  *RedisStreamBroker
+	topic   string
+	group   string
+	handler Handler
+	stopped atomic.Bool
+
+	mu   sync.Mutex
+	conn *resp.Conn
+}
+
+// NewRedisStreamBroker 连接 Redis
+func NewRedisStreamBroker(config *RedisConfig) (*RedisStreamBroker, error) {
+	cfg := RedisConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:6379"
+	}
+	if cfg.Consumer == "" {
+		hostname, _ := os.Hostname()
+		cfg.Consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	conn, err := dialRedis(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStreamBroker{
+		config: cfg,
+		conn:   conn,
+		subs:   make(map[*redisSubscription]struct{}),
+	}, nil
+}
+
+// Name 返回中间件名称
+func (b *RedisStreamBroker) Name() string {
+	return "redis"
+}
+
+// Publish 以 XADD 将消息追加到主题对应的流，连接断开时重新连接
+func (b *RedisStreamBroker) Publish(ctx context.Context, msg *Message) error {
+	if msg.Topic == "" {
+		return fmt.Errorf("invalid Redis stream %q", msg.Topic)
+	}
+	headers, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode message headers: %w", err)
+	}
+	args := []interface{}{"XADD", msg.Topic}
+	if b.config.MaxLen > 0 {
+		args = append(args, "MAXLEN", "~", b.config.MaxLen)
+	}
+	args = append(args, "*", redisFieldHeaders, headers, redisFieldPayload, msg.Payload)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	if b.conn == nil {
+		if b.conn, err = dialRedis(&b.config); err != nil {
+			return err
+		}
+	}
+	if _, err := b.conn.Do(0, args...); err != nil {
+		if resp.ErrorOf(err) == "" {
+			b.conn.Close()
+			b.conn = nil
+		}
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe 订阅主题，group 不为空时加入同名消费者组，消费者组不存在时自动创建
+func (b *RedisStreamBroker) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("invalid Redis stream %q", topic)
+	}
+	sub := &redisSubscription{
+		broker:  b,
+		topic:   topic,
+		group:   group,
+		handler: handler,
+	}
+	conn, err := sub.connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+	sub.conn = conn
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		conn.Close()
+		return nil, ErrBrokerClosed
+	}
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	// 无订阅组时在启动读取前确定起始位置，订阅返回后发布的消息都能收到
+	startID := "0"
+	if group == "" {
+		if startID, err = sub.lastID(conn); err != nil {
+			sub.Unsubscribe()
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+	go sub.run(startID)
+	return sub, nil
+}
+
+// Close 关闭连接并取消所有订阅
+func (b *RedisStreamBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[*redisSubscription]struct{})
+	var err error
+	if b.conn != nil {
+		err = b.conn.Close()
+		b.conn = nil
+	}
+	b.mu.Unlock()
+
+	for sub := range subs {
+		sub.stop()
+	}
+	return err
+}
+
+// Unsubscribe 取消订阅并关闭订阅连接
+func (s *redisSubscription) Unsubscribe() error {
+	b := s.broker
+	b.mu.Lock()
+	delete(b.subs, s)
+	b.mu.Unlock()
+	s.stop()
+	return nil
+}
+
+// stop 停止读取，关闭连接以中断阻塞中的读取
+func (s *redisSubscription) stop() {
+	s.stopped.Store(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// connect 建立订阅连接，有订阅组时确保消费者组存在
+func (s *redisSubscription) connect() (*resp.Conn, error) {
+	conn, err := dialRedis(&s.broker.config)
+	if err != nil {
+		return nil, err
+	}
+	if s.group != "" {
+		_, err := conn.Do(0, "XGROUP", "CREATE", s.topic, s.group, "$", "MKSTREAM")
+		if err != nil && !strings.HasPrefix(string(resp.ErrorOf(err)), "BUSYGROUP") {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// lastID 返回流中最新条目的 ID，流为空时返回 0-0
+func (s *redisSubscription) lastID(conn *resp.Conn) (string, error) {
+	reply, err := conn.Do(0, "XREVRANGE", s.topic, "+", "-", "COUNT", 1)
+	if err != nil {
+		return "", err
+	}
+	entries, err := parseStreamEntries(reply)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "0-0", nil
+	}
+	return entries[0].id, nil
+}
+
+// run 循环读取并处理消息直到取消订阅，连接出错时按固定间隔重连
+//
+// 订阅组先从 ID 0 起读取本消费者的待处理消息，读完后改为读取新消息（>）
+func (s *redisSubscription) run(cursor string) {
+	block := s.broker.config.Block
+	blockMillis := int64(block / time.Millisecond)
+	if blockMillis <= 0 {
+		blockMillis = 1
+	}
+
+	for !s.stopped.Load() {
+		conn := s.currentConn()
+		if conn == nil {
+			if !s.reconnect() {
+				time.Sleep(redisRetryInterval)
+			}
+			continue
+		}
+
+		var args []interface{}
+		if s.group == "" {
+			args = []interface{}{"XREAD", "COUNT", redisReadCount, "BLOCK", blockMillis, "STREAMS", s.topic, cursor}
+		} else {
+			args = []interface{}{"XREADGROUP", "GROUP", s.group, s.broker.config.Consumer,
+				"COUNT", redisReadCount, "BLOCK", blockMillis, "STREAMS", s.topic, cursor}
+		}
+		reply, err := conn.Do(block, args...)
+		if err == nil {
+			var entries []redisEntry
+			if entries, err = parseReadReply(reply); err == nil {
+				cursor = s.process(conn, cursor, entries)
+				continue
+			}
+		}
+		if s.stopped.Load() {
+			return
+		}
+		s.dropConn(conn)
+		time.Sleep(redisRetryInterval)
+	}
+}
+
+// process 依次处理读取到的条目并返回下一次读取的游标
+func (s *redisSubscription) process(conn *resp.Conn, cursor string, entries []redisEntry) string {
+	if s.group != "" && cursor != ">" && len(entries) == 0 {
+		return ">"
+	}
+	for _, entry := range entries {
+		if s.stopped.Load() {
+			break
+		}
+		if cursor != ">" {
+			cursor = entry.id
+		}
+		// 已被裁剪的待处理条目没有字段，直接确认
+		if entry.fields == nil {
+			if s.group != "" {
+				conn.Do(0, "XACK", s.topic, s.group, entry.id)
+			}
+			continue
+		}
+		if err := s.handler(context.Background(), entry.message(s.topic)); err != nil || s.group == "" {
+			continue
+		}
+		conn.Do(0, "XACK", s.topic, s.group, entry.id)
+	}
+	return cursor
+}
+
+// currentConn 返回订阅连接
+func (s *redisSubscription) currentConn() *resp.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn
+}
+
+// reconnect 重新建立订阅连接，成功返回 true
+func (s *redisSubscription) reconnect() bool {
+	conn, err := s.connect()
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped.Load() {
+		conn.Close()
+		return true
+	}
+	s.conn = conn
+	return true
+}
+
+// dropConn 关闭出错的订阅连接，下一轮循环重连
+func (s *redisSubscription) dropConn(conn *resp.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.Close()
+	if s.conn == conn {
+		s.conn = nil
+	}
+}
+
+// redisEntry 流中的条目
+type redisEntry struct {
+	id     string
+	fields map[string][]byte
+}
+
+// message 由条目字段还原消息，字段缺失或消息头无法解析时使用空消息头
+func (e redisEntry) message(topic string) *Message {
+	var headers map[string]string
+	if data, ok := e.fields[redisFieldHeaders]; ok {
+		json.Unmarshal(data, &headers)
+	}
+	msg := messageFromHeaders(topic, headers, e.fields[redisFieldPayload])
+	if msg.ID == "" {
+		msg.ID = e.id
+	}
+	return msg
+}
+
+// parseReadReply 解析 XREAD/XREADGROUP 的回复，超时无消息时回复为空
+func parseReadReply(reply interface{}) ([]redisEntry, error) {
+	if reply == nil {
+		return nil, nil
+	}
+	streams, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected Redis stream reply %T", reply)
+	}
+	var entries []redisEntry
+	for _, item := range streams {
+		stream, ok := item.([]interface{})
+		if !ok || len(stream) != 2 {
+			return nil, fmt.Errorf("unexpected Redis stream reply %v", item)
+		}
+		parsed, err := parseStreamEntries(stream[1])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, parsed...)
+	}
+	return entries, nil
+}
+
+// parseStreamEntries 解析条目列表，每个条目为 [id, [field, value, ...]]
+func parseStreamEntries(reply interface{}) ([]redisEntry, error) {
+	items, ok := reply.([]interface{})
+	if !ok && reply != nil {
+		return nil, fmt.Errorf("unexpected Redis stream entries %T", reply)
+	}
+	entries := make([]redisEntry, 0, len(items))
+	for _, item := range items {
+		pair, ok := item.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected Redis stream entry %v", item)
+		}
+		id, ok := pair[0].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected Redis stream entry id %T", pair[0])
+		}
+		entry := redisEntry{id: string(id)}
+		if values, ok := pair[1].([]interface{}); ok {
+			entry.fields = make(map[string][]byte, len(values)/2)
+			for i := 0; i+1 < len(values); i += 2 {
+				field, _ := values[i].([]byte)
+				value, _ := values[i+1].([]byte)
+				entry.fields[string(field)] = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// dialRedis 按配置连接 Redis
+func dialRedis(cfg *RedisConfig) (*resp.Conn, error) {
+	return resp.Dial(&resp.Config{
+		Address:  cfg.Address,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		Timeout:  cfg.Timeout,
+	})
+}
@@ -0,0 +1,278 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/resp"
+)
+
+// redisEnvelope Redis 发布/订阅消息的编码，频道消息只有一个值，消息头和负载一起以 JSON 编码
+type redisEnvelope struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload []byte            `json:"payload"`
+}
+
+// RedisPubSubBroker 基于 Redis 发布/订阅（PUBLISH/SUBSCRIBE）的消息中间件
+//
+// 消息只投递给发布时在线的订阅，不持久化也不重新投递，适合多实例间的实时广播（如 WebSocket 主题）。
+// 发布/订阅没有消费者组，Subscribe 的 group 必须为空；需要持久化或订阅组时使用 RedisStreamBroker
+type RedisPubSubBroker struct {
+	config RedisConfig
+
+	mu     sync.Mutex
+	conn   *resp.Conn
+	subs   map[*redisChannelSubscription]struct{}
+	closed bool
+}
+
+// redisChannelSubscription Redis 频道订阅，订阅状态的连接只能接收消息，每个订阅使用独立的连接
+type redisChannelSubscription struct {
+	broker  *RedisPubSubBroker
+	channel string
+	handler Handler
+	stopped atomic.Bool
+
+	mu   sync.Mutex
+	conn *resp.Conn
+}
+
+// NewRedisPubSubBroker 连接 Redis，config 中的 Consumer、Block 和 MaxLen 不使用
+func NewRedisPubSubBroker(config *RedisConfig) (*RedisPubSubBroker, error) {
+	cfg := RedisConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:6379"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	conn, err := dialRedis(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisPubSubBroker{
+		config: cfg,
+		conn:   conn,
+		subs:   make(map[*redisChannelSubscription]struct{}),
+	}, nil
+}
+
+// Name 返回中间件名称
+func (b *RedisPubSubBroker) Name() string {
+	return "redis"
+}
+
+// Publish 以 PUBLISH 将消息发布到主题对应的频道，连接断开时重新连接
+func (b *RedisPubSubBroker) Publish(ctx context.Context, msg *Message) error {
+	if msg.Topic == "" {
+		return fmt.Errorf("invalid Redis channel %q", msg.Topic)
+	}
+	data, err := json.Marshal(redisEnvelope{Headers: msg.Headers, Payload: msg.Payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	if b.conn == nil {
+		if b.conn, err = dialRedis(&b.config); err != nil {
+			return err
+		}
+	}
+	if _, err := b.conn.Do(0, "PUBLISH", msg.Topic, data); err != nil {
+		if resp.ErrorOf(err) == "" {
+			b.conn.Close()
+			b.conn = nil
+		}
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe 订阅主题对应的频道，返回时订阅已生效
+func (b *RedisPubSubBroker) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("invalid Redis channel %q", topic)
+	}
+	if group != "" {
+		return nil, errors.New("redis pub/sub does not support subscription groups")
+	}
+	sub := &redisChannelSubscription{
+		broker:  b,
+		channel: topic,
+		handler: handler,
+	}
+	conn, err := sub.connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+	sub.conn = conn
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		conn.Close()
+		return nil, ErrBrokerClosed
+	}
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run()
+	return sub, nil
+}
+
+// Close 关闭连接并取消所有订阅
+func (b *RedisPubSubBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[*redisChannelSubscription]struct{})
+	var err error
+	if b.conn != nil {
+		err = b.conn.Close()
+		b.conn = nil
+	}
+	b.mu.Unlock()
+
+	for sub := range subs {
+		sub.stop()
+	}
+	return err
+}
+
+// Unsubscribe 取消订阅并关闭订阅连接
+func (s *redisChannelSubscription) Unsubscribe() error {
+	b := s.broker
+	b.mu.Lock()
+	delete(b.subs, s)
+	b.mu.Unlock()
+	s.stop()
+	return nil
+}
+
+// stop 停止接收，关闭连接以中断阻塞中的读取
+func (s *redisChannelSubscription) stop() {
+	s.stopped.Store(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// connect 建立订阅连接并发送 SUBSCRIBE，读取到订阅确认后返回
+func (s *redisChannelSubscription) connect() (*resp.Conn, error) {
+	conn, err := dialRedis(&s.broker.config)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.Do(0, "SUBSCRIBE", s.channel)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if kind, _, _ := parsePubSubReply(reply); kind != "subscribe" {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected Redis subscribe reply %v", reply)
+	}
+	return conn, nil
+}
+
+// run 循环接收并处理消息直到取消订阅，连接出错时按固定间隔重连，断开期间发布的消息会丢失
+func (s *redisChannelSubscription) run() {
+	for !s.stopped.Load() {
+		conn := s.currentConn()
+		if conn == nil {
+			if !s.reconnect() {
+				time.Sleep(redisRetryInterval)
+			}
+			continue
+		}
+
+		// 订阅连接上没有请求，读取不设超时
+		reply, err := conn.Receive()
+		if err == nil {
+			if kind, channel, data := parsePubSubReply(reply); kind == "message" && channel == s.channel {
+				s.handler(context.Background(), decodeRedisEnvelope(channel, data))
+			}
+			continue
+		}
+		if s.stopped.Load() {
+			return
+		}
+		s.dropConn(conn)
+		time.Sleep(redisRetryInterval)
+	}
+}
+
+// currentConn 返回订阅连接
+func (s *redisChannelSubscription) currentConn() *resp.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn
+}
+
+// reconnect 重新建立订阅连接，成功返回 true
+func (s *redisChannelSubscription) reconnect() bool {
+	conn, err := s.connect()
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped.Load() {
+		conn.Close()
+		return true
+	}
+	s.conn = conn
+	return true
+}
+
+// dropConn 关闭出错的订阅连接，下一轮循环重连
+func (s *redisChannelSubscription) dropConn(conn *resp.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.Close()
+	if s.conn == conn {
+		s.conn = nil
+	}
+}
+
+// parsePubSubReply 解析订阅连接收到的推送，消息为 [message, channel, data]，订阅确认为 [subscribe, channel, count]
+func parsePubSubReply(reply interface{}) (kind, channel string, data []byte) {
+	items, ok := reply.([]interface{})
+	if !ok || len(items) != 3 {
+		return "", "", nil
+	}
+	k, _ := items[0].([]byte)
+	c, _ := items[1].([]byte)
+	data, _ = items[2].([]byte)
+	return string(k), string(c), data
+}
+
+// decodeRedisEnvelope 由频道消息还原消息，不是框架编码的消息时整体作为负载
+func decodeRedisEnvelope(channel string, data []byte) *Message {
+	var fields map[string]json.RawMessage
+	var envelope redisEnvelope
+	if json.Unmarshal(data, &fields) != nil || fields["payload"] == nil || json.Unmarshal(data, &envelope) != nil {
+		return messageFromHeaders(channel, nil, data)
+	}
+	return messageFromHeaders(channel, envelope.Headers, envelope.Payload)
+}
@@ -0,0 +1,104 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParsePubSubReply(t *testing.T) {
+	tests := []struct {
+		name        string
+		reply       interface{}
+		wantKind    string
+		wantChannel string
+		wantData    string
+	}{
+		{name: "消息", reply: []interface{}{[]byte("message"), []byte("orders"), []byte("hi")}, wantKind: "message", wantChannel: "orders", wantData: "hi"},
+		{name: "订阅确认", reply: []interface{}{[]byte("subscribe"), []byte("orders"), int64(1)}, wantKind: "subscribe", wantChannel: "orders"},
+		{name: "长度不符", reply: []interface{}{[]byte("message")}},
+		{name: "不是数组", reply: "OK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, channel, data := parsePubSubReply(tt.reply)
+			if kind != tt.wantKind || channel != tt.wantChannel || string(data) != tt.wantData {
+				t.Errorf("parsePubSubReply = %q, %q, %q", kind, channel, data)
+			}
+		})
+	}
+}
+
+func TestDecodeRedisEnvelope(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantID      string
+		wantPayload string
+	}{
+		{name: "框架编码", data: `{"headers":{"message-id":"m-1"},"payload":"aGVsbG8="}`, wantID: "m-1", wantPayload: "hello"},
+		{name: "空负载", data: `{"payload":""}`, wantPayload: ""},
+		{name: "其他 JSON", data: `{"event":"created"}`, wantPayload: `{"event":"created"}`},
+		{name: "纯文本", data: `hello`, wantPayload: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := decodeRedisEnvelope("orders", []byte(tt.data))
+			if msg.Topic != "orders" || msg.ID != tt.wantID || string(msg.Payload) != tt.wantPayload {
+				t.Errorf("decodeRedisEnvelope = topic %q id %q payload %q", msg.Topic, msg.ID, msg.Payload)
+			}
+		})
+	}
+}
+
+func TestRedisPubSubBroker(t *testing.T) {
+	broker, err := NewRedisPubSubBroker(nil)
+	if err != nil {
+		t.Skipf("Skipping test: redis not available: %v", err)
+	}
+	defer broker.Close()
+
+	if _, err := broker.Subscribe("orders", "billing", func(ctx context.Context, msg *Message) error {
+		return nil
+	}); err == nil {
+		t.Error("expected error for subscription group")
+	}
+
+	topic := fmt.Sprintf("framework-test-%d", time.Now().UnixNano())
+	received := make(chan *Message, 4)
+	sub, err := broker.Subscribe(topic, "", func(ctx context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	err = broker.Publish(context.Background(), &Message{
+		Topic:   topic,
+		Headers: map[string]string{HeaderMessageID: "m-1"},
+		Payload: []byte("hello"),
+	})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	select {
+	case msg := <-received:
+		if msg.ID != "m-1" || string(msg.Payload) != "hello" {
+			t.Errorf("received unexpected message: id=%q payload=%s", msg.ID, msg.Payload)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("subscription did not receive the message")
+	}
+
+	sub.Unsubscribe()
+	broker.Publish(context.Background(), &Message{Topic: topic, Payload: []byte("late")})
+	select {
+	case msg := <-received:
+		t.Errorf("received message after unsubscribe: %s", msg.Payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
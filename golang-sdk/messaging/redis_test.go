@@ -0,0 +1,103 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseReadReply(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("orders"),
+			[]interface{}{
+				[]interface{}{
+					[]byte("1-0"),
+					[]interface{}{
+						[]byte("headers"), []byte(`{"message-id":"m-1","traceparent":"00-abc-def-01"}`),
+						[]byte("payload"), []byte(`{"orderId":"o-1"}`),
+					},
+				},
+				// 已被裁剪的待处理条目
+				[]interface{}{[]byte("2-0"), nil},
+			},
+		},
+	}
+
+	entries, err := parseReadReply(reply)
+	if err != nil {
+		t.Fatalf("parseReadReply failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].id != "2-0" || entries[1].fields != nil {
+		t.Errorf("unexpected trimmed entry: %+v", entries[1])
+	}
+
+	msg := entries[0].message("orders")
+	if msg.ID != "m-1" || msg.Topic != "orders" {
+		t.Errorf("unexpected message: id=%q topic=%q", msg.ID, msg.Topic)
+	}
+	if msg.Headers["traceparent"] != "00-abc-def-01" {
+		t.Errorf("traceparent = %q", msg.Headers["traceparent"])
+	}
+	if string(msg.Payload) != `{"orderId":"o-1"}` {
+		t.Errorf("payload = %s", msg.Payload)
+	}
+
+	if entries, err := parseReadReply(nil); err != nil || entries != nil {
+		t.Errorf("parseReadReply(nil) = %v, %v", entries, err)
+	}
+}
+
+func TestRedisStreamBroker(t *testing.T) {
+	broker, err := NewRedisStreamBroker(&RedisConfig{Block: 100 * time.Millisecond})
+	if err != nil {
+		t.Skipf("Skipping test: redis not available: %v", err)
+	}
+	defer broker.Close()
+
+	topic := fmt.Sprintf("framework-test-%d", time.Now().UnixNano())
+	broadcast := make(chan *Message, 4)
+	grouped := make(chan *Message, 4)
+	if _, err := broker.Subscribe(topic, "", func(ctx context.Context, msg *Message) error {
+		broadcast <- msg
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if _, err := broker.Subscribe(topic, "billing", func(ctx context.Context, msg *Message) error {
+		grouped <- msg
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	err = broker.Publish(context.Background(), &Message{
+		Topic:   topic,
+		Headers: map[string]string{HeaderMessageID: "m-1"},
+		Payload: []byte("hello"),
+	})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	for name, ch := range map[string]chan *Message{"broadcast": broadcast, "group": grouped} {
+		select {
+		case msg := <-ch:
+			if msg.ID != "m-1" || string(msg.Payload) != "hello" {
+				t.Errorf("%s received unexpected message: id=%q payload=%s", name, msg.ID, msg.Payload)
+			}
+		case <-time.After(3 * time.Second):
+			t.Errorf("%s subscription did not receive the message", name)
+		}
+	}
+
+	conn, err := dialRedis(&broker.config)
+	if err == nil {
+		conn.Do(0, "DEL", topic)
+		conn.Close()
+	}
+}
@@ -0,0 +1,320 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/serializer"
+)
+
+// RPC 请求和响应的消息头
+const (
+	// HeaderReplyTo 接收响应的主题，为空时服务端不发送响应
+	HeaderReplyTo = "reply-to"
+	// HeaderCorrelationID 关联 ID，响应原样带回，请求方据此匹配请求
+	HeaderCorrelationID = "correlation-id"
+	// HeaderMethod 调用的方法名，如 hello.sayHello
+	HeaderMethod = "rpc-method"
+	// HeaderDeadline 请求截止时间，RFC 3339 格式，服务端跳过已过期的请求
+	HeaderDeadline = "rpc-deadline"
+)
+
+// DefaultRPCTimeout ctx 未设置截止时间时 RPC 调用的默认超时
+const DefaultRPCTimeout = 30 * time.Second
+
+// RequestTopic 返回 service 服务接收 RPC 请求的主题
+func RequestTopic(service string) string {
+	return "rpc." + service
+}
+
+// rpcResponse RPC 响应负载，result 与 error 二选一
+type rpcResponse struct {
+	Result json.RawMessage               `json:"result,omitempty"`
+	Error  *frameworkerrors.ErrorPayload `json:"error,omitempty"`
+}
+
+// RPCServer 通过消息中间件接收 RPC 请求并将响应发布到请求方的响应主题
+//
+// 以服务名为订阅组订阅 RequestTopic(service)，同一服务的多个实例分摊请求。
+// 服务端只需连接消息中间件，调用方不需要与其直连
+type RPCServer struct {
+	broker     Broker
+	service    string
+	dispatcher adapter.Dispatcher
+
+	mu          sync.Mutex
+	sub         Subscription
+	deadLetters *DeadLetterQueue
+}
+
+// NewRPCServer 创建 RPC 服务端，请求经 dispatcher 分发给业务方法
+func NewRPCServer(broker Broker, service string, dispatcher adapter.Dispatcher) *RPCServer {
+	return &RPCServer{
+		broker:     broker,
+		service:    service,
+		dispatcher: dispatcher,
+	}
+}
+
+// SetDeadLetterQueue 设置死信队列，在 Start 之前调用
+//
+// 不需要响应（未带响应主题）的请求处理失败时写入死信队列，可经 DeadLetterQueue.Replay 重放；
+// 需要响应的请求的错误返回给请求方，不进入死信队列
+func (s *RPCServer) SetDeadLetterQueue(q *DeadLetterQueue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = q
+}
+
+// Start 订阅请求主题
+func (s *RPCServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sub != nil {
+		return fmt.Errorf("rpc server for %s already started", s.service)
+	}
+	handler := Handler(s.handle)
+	if s.deadLetters != nil {
+		handler = s.deadLetters.Wrap(RequestTopic(s.service), s.service, handler)
+	}
+	sub, err := s.broker.Subscribe(RequestTopic(s.service), s.service, handler)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to rpc requests for %s: %w", s.service, err)
+	}
+	s.sub = sub
+	return nil
+}
+
+// Stop 取消订阅，不再接收新的请求
+func (s *RPCServer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	sub := s.sub
+	s.sub = nil
+	s.mu.Unlock()
+	if sub == nil {
+		return nil
+	}
+	return sub.Unsubscribe()
+}
+
+// handle 处理一个请求并发布响应
+func (s *RPCServer) handle(ctx context.Context, msg *Message) error {
+	if deadline, err := time.Parse(time.RFC3339Nano, msg.Headers[HeaderDeadline]); err == nil {
+		if time.Now().After(deadline) {
+			return nil // 请求方已超时，不再处理
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	ctx = adapter.ExtractTraceContext(ctx, msg.Headers)
+	ctx, span := adapter.StartConsumerSpan(ctx, s.broker.Name(), msg.Topic, s.service)
+
+	// 方法名格式与 JSON-RPC 相同: "ServiceName.MethodName"
+	service, method := "default", msg.Headers[HeaderMethod]
+	if i := strings.Index(method, "."); i >= 0 {
+		service, method = method[:i], method[i+1:]
+	}
+	correlationID := msg.Headers[HeaderCorrelationID]
+
+	var result interface{}
+	var err error
+	if method == "" {
+		err = frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "method not specified in rpc request")
+	} else {
+		result, err = s.dispatcher(ctx, &adapter.InternalRequest{
+			Service:  service,
+			Method:   method,
+			Payload:  msg.Payload,
+			Headers:  msg.Headers,
+			Metadata: map[string]string{"request_id": correlationID},
+		})
+	}
+	adapter.EndSpan(span, err)
+
+	replyTo := msg.Headers[HeaderReplyTo]
+	if replyTo == "" {
+		return err
+	}
+	response := rpcResponse{Error: frameworkerrors.PayloadFromError(err)}
+	if err == nil {
+		if response.Result, err = json.Marshal(result); err != nil {
+			response.Error = frameworkerrors.PayloadFromError(frameworkerrors.Wrap(err,
+				frameworkerrors.SerializationError, "failed to serialize rpc result"))
+		}
+	}
+	payload, _ := json.Marshal(response)
+
+	reply := &Message{
+		ID:    newMessageID(),
+		Topic: replyTo,
+		Headers: map[string]string{
+			HeaderContentType:   string(serializer.JSON),
+			HeaderCorrelationID: correlationID,
+		},
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}
+	reply.Headers[HeaderMessageID] = reply.ID
+	return s.broker.Publish(context.Background(), reply)
+}
+
+// RPCClient 通过消息中间件调用服务
+//
+// 创建时订阅本实例独占的响应主题，请求带上响应主题和关联 ID，响应按关联 ID 交给等待的调用
+type RPCClient struct {
+	broker     Broker
+	replyTopic string
+	sub        Subscription
+
+	mu      sync.Mutex
+	pending map[string]chan *Message
+	closed  bool
+}
+
+// NewRPCClient 创建 RPC 客户端并订阅响应主题
+func NewRPCClient(broker Broker) (*RPCClient, error) {
+	c := &RPCClient{
+		broker:     broker,
+		replyTopic: "rpc.reply." + newMessageID(),
+		pending:    make(map[string]chan *Message),
+	}
+	sub, err := broker.Subscribe(c.replyTopic, "", c.handleReply)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to rpc reply topic: %w", err)
+	}
+	c.sub = sub
+	return c, nil
+}
+
+// Call 调用 service 服务的方法，请求和结果按 JSON 序列化
+//
+// 超过 ctx 的截止时间（未设置时为 DefaultRPCTimeout）未收到响应时返回 Timeout 错误；
+// 服务端返回的错误还原为框架错误
+func (c *RPCClient) Call(ctx context.Context, service, method string, request interface{}, response interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultRPCTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to serialize rpc request")
+	}
+
+	correlationID := newMessageID()
+	replies := make(chan *Message, 1)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrBrokerClosed
+	}
+	c.pending[correlationID] = replies
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, correlationID)
+		c.mu.Unlock()
+	}()
+
+	topic := RequestTopic(service)
+	ctx, span := adapter.StartProducerSpan(ctx, c.broker.Name(), topic)
+	headers := make(map[string]string)
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		headers = sc.ToHeaders()
+	}
+	msg := &Message{
+		ID:        correlationID,
+		Topic:     topic,
+		Headers:   headers,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}
+	headers[HeaderContentType] = string(serializer.JSON)
+	headers[HeaderMessageID] = msg.ID
+	headers[HeaderReplyTo] = c.replyTopic
+	headers[HeaderCorrelationID] = correlationID
+	headers[HeaderMethod] = method
+	headers[HeaderDeadline] = deadline.UTC().Format(time.RFC3339Nano)
+	adapter.InjectTraceContext(ctx, headers)
+
+	err = c.call(ctx, msg, replies, response)
+	adapter.EndSpan(span, err)
+	return err
+}
+
+// call 发布请求并等待响应
+func (c *RPCClient) call(ctx context.Context, msg *Message, replies chan *Message, response interface{}) error {
+	if err := c.broker.Publish(ctx, msg); err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.ConnectionError,
+			fmt.Sprintf("failed to publish rpc request to %s", msg.Topic))
+	}
+
+	var reply *Message
+	select {
+	case reply = <-replies:
+	case <-ctx.Done():
+		code, _ := frameworkerrors.ContextErrorCode(ctx.Err())
+		return frameworkerrors.NewFrameworkError(code,
+			fmt.Sprintf("rpc call %s to %s: %v", msg.Headers[HeaderMethod], msg.Topic, ctx.Err()))
+	}
+	if reply == nil {
+		return ErrBrokerClosed
+	}
+
+	var result rpcResponse
+	if err := json.Unmarshal(reply.Payload, &result); err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "invalid rpc response")
+	}
+	if result.Error != nil {
+		return result.Error.ToFrameworkError()
+	}
+	if response == nil || len(result.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Result, response); err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to decode rpc result")
+	}
+	return nil
+}
+
+// handleReply 将响应交给等待的调用，已超时的响应被丢弃
+func (c *RPCClient) handleReply(ctx context.Context, msg *Message) error {
+	c.mu.Lock()
+	replies, ok := c.pending[msg.Headers[HeaderCorrelationID]]
+	c.mu.Unlock()
+	if ok {
+		select {
+		case replies <- msg:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close 取消订阅响应主题，等待中的调用返回 ErrBrokerClosed
+func (c *RPCClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	pending := c.pending
+	c.pending = make(map[string]chan *Message)
+	c.mu.Unlock()
+
+	for _, replies := range pending {
+		close(replies)
+	}
+	return c.sub.Unsubscribe()
+}
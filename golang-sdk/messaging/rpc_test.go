@@ -0,0 +1,160 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// startRPCServer 在 broker 上启动 billing 服务的 RPC 服务端
+func startRPCServer(t *testing.T, broker Broker, dispatcher adapter.Dispatcher) {
+	server := NewRPCServer(broker, "billing", dispatcher)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { server.Stop(context.Background()) })
+}
+
+// newRPCClient 创建 RPC 客户端，测试结束时关闭
+func newRPCClient(t *testing.T, broker Broker) *RPCClient {
+	client, err := NewRPCClient(broker)
+	if err != nil {
+		t.Fatalf("NewRPCClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRPCCall(t *testing.T) {
+	broker := NewMemoryBroker()
+	defer broker.Close()
+
+	var request *adapter.InternalRequest
+	startRPCServer(t, broker, func(ctx context.Context, r *adapter.InternalRequest) (interface{}, error) {
+		request = r
+		var order orderCreated
+		if err := json.Unmarshal(r.Payload, &order); err != nil {
+			return nil, err
+		}
+		return map[string]int{"charged": order.Amount}, nil
+	})
+	client := newRPCClient(t, broker)
+
+	var result map[string]int
+	err := client.Call(context.Background(), "billing", "billing.charge", orderCreated{OrderID: "o-1", Amount: 42}, &result)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if result["charged"] != 42 {
+		t.Errorf("unexpected result: %v", result)
+	}
+	if request.Service != "billing" || request.Method != "charge" {
+		t.Errorf("dispatched to %s.%s, want billing.charge", request.Service, request.Method)
+	}
+	if request.Metadata["request_id"] == "" || request.Metadata["request_id"] != request.Headers[HeaderCorrelationID] {
+		t.Errorf("request_id = %q, correlation-id = %q", request.Metadata["request_id"], request.Headers[HeaderCorrelationID])
+	}
+}
+
+func TestRPCCallErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		err      error
+		wantCode frameworkerrors.ErrorCode
+	}{
+		{
+			name:     "服务端框架错误原样返回",
+			method:   "billing.charge",
+			err:      frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "order not found"),
+			wantCode: frameworkerrors.NotFound,
+		},
+		{
+			name:     "缺少方法名",
+			method:   "",
+			wantCode: frameworkerrors.BadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := NewMemoryBroker()
+			defer broker.Close()
+
+			startRPCServer(t, broker, func(ctx context.Context, r *adapter.InternalRequest) (interface{}, error) {
+				return nil, tt.err
+			})
+			client := newRPCClient(t, broker)
+
+			err := client.Call(context.Background(), "billing", tt.method, struct{}{}, nil)
+			fe, ok := frameworkerrors.FromError(err)
+			if !ok {
+				t.Fatalf("expected framework error, got %v", err)
+			}
+			if fe.Code != tt.wantCode {
+				t.Errorf("code = %v, want %v", fe.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRPCCallTimeout(t *testing.T) {
+	broker := NewMemoryBroker()
+	defer broker.Close()
+
+	// 没有服务端订阅请求主题
+	client := newRPCClient(t, broker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.Call(ctx, "billing", "billing.charge", struct{}{}, nil)
+	fe, ok := frameworkerrors.FromError(err)
+	if !ok || fe.Code != frameworkerrors.Timeout {
+		t.Fatalf("expected Timeout error, got %v", err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.pending) != 0 {
+		t.Errorf("pending calls = %d, want 0", len(client.pending))
+	}
+}
+
+func TestRPCServerSkipsExpiredRequests(t *testing.T) {
+	broker := NewMemoryBroker()
+	defer broker.Close()
+
+	calls := 0
+	startRPCServer(t, broker, func(ctx context.Context, r *adapter.InternalRequest) (interface{}, error) {
+		calls++
+		return nil, nil
+	})
+
+	err := broker.Publish(context.Background(), &Message{
+		Topic: RequestTopic("billing"),
+		Headers: map[string]string{
+			HeaderMethod:   "billing.charge",
+			HeaderDeadline: time.Now().Add(-time.Second).UTC().Format(time.RFC3339Nano),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("dispatcher called %d times, want 0", calls)
+	}
+}
+
+func TestRPCClientClose(t *testing.T) {
+	client := newRPCClient(t, NewMemoryBroker())
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := client.Call(context.Background(), "billing", "billing.charge", struct{}{}, nil); err != ErrBrokerClosed {
+		t.Errorf("Call after Close = %v, want ErrBrokerClosed", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package metadata 随请求在服务间传递的键值对
+//
+// 与 gRPC metadata 类似，但不区分入站和出站：服务端收到的元数据写入处理请求的 context，
+// 用该 context 发起的下游调用自动带上，因此元数据在多跳调用中一直传递，直到被覆盖。
+// 各传输协议（REST、JSON-RPC、gRPC、自定义二进制协议、消息中间件）以 X-Metadata-<键> 请求头传输，
+// 值进行百分号编码，其他语言的服务直接读写这些请求头即可
+package metadata
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HeaderPrefix 元数据请求头前缀，键 tenant 以 X-Metadata-Tenant 传输
+const HeaderPrefix = "X-Metadata-"
+
+// 元数据限制，超出时多余的键值对不传输
+const (
+	MaxEntries = 64
+	MaxBytes   = 8192
+)
+
+// MD 元数据，键为小写
+type MD map[string]string
+
+// New 由 m 创建元数据，键转换为小写，不合法的键被忽略
+func New(m map[string]string) MD {
+	md := make(MD, len(m))
+	for key, value := range m {
+		md.Set(key, value)
+	}
+	return md
+}
+
+// Pairs 由键值对创建元数据，kv 的个数为奇数时 panic
+func Pairs(kv ...string) MD {
+	if len(kv)%2 == 1 {
+		panic("metadata: Pairs got an odd number of arguments")
+	}
+	md := make(MD, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		md.Set(kv[i], kv[i+1])
+	}
+	return md
+}
+
+// Get 返回键的值，不存在时返回空字符串
+func (md MD) Get(key string) string {
+	return md[strings.ToLower(key)]
+}
+
+// Set 设置键的值，键须为 HTTP token（字母、数字和 !#$%&'*+-.^_`|~），不合法的键被忽略
+func (md MD) Set(key, value string) {
+	if !isKey(key) {
+		return
+	}
+	md[strings.ToLower(key)] = value
+}
+
+// Delete 删除键
+func (md MD) Delete(key string) {
+	delete(md, strings.ToLower(key))
+}
+
+// Copy 返回元数据的副本
+func (md MD) Copy() MD {
+	result := make(MD, len(md))
+	for key, value := range md {
+		result[key] = value
+	}
+	return result
+}
+
+// mdKey 元数据的 context 键
+type mdKey struct{}
+
+// FromContext 返回 context 中元数据的副本，包括上游传来的和本进程设置的，没有元数据时返回空的 MD
+func FromContext(ctx context.Context) MD {
+	return fromContext(ctx).Copy()
+}
+
+// ValueFromContext 返回 context 中元数据键的值，不存在时返回空字符串
+func ValueFromContext(ctx context.Context, key string) string {
+	return fromContext(ctx).Get(key)
+}
+
+// NewOutgoingContext 返回携带 md 的 context，md 与 context 中已有的元数据合并，同名键以 md 为准；
+// 用返回的 context 发起的调用将元数据传给下游服务
+func NewOutgoingContext(ctx context.Context, md MD) context.Context {
+	if len(md) == 0 {
+		return ctx
+	}
+	merged := fromContext(ctx).Copy()
+	for key, value := range md {
+		merged.Set(key, value)
+	}
+	return context.WithValue(ctx, mdKey{}, merged)
+}
+
+// AppendToOutgoingContext 在 context 的元数据中追加键值对，kv 的个数为奇数时 panic
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context {
+	return NewOutgoingContext(ctx, Pairs(kv...))
+}
+
+// fromContext 返回 context 中的元数据，调用方不得修改
+func fromContext(ctx context.Context) MD {
+	if ctx == nil {
+		return nil
+	}
+	md, _ := ctx.Value(mdKey{}).(MD)
+	return md
+}
+
+// Inject 将 context 中的元数据写入请求头，按键排序写入，超过 MaxEntries 或 MaxBytes 的部分被丢弃
+func Inject(ctx context.Context, headers map[string]string) {
+	md := fromContext(ctx)
+	if len(md) == 0 || headers == nil {
+		return
+	}
+
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	size := 0
+	for i, key := range keys {
+		value := url.PathEscape(md[key])
+		size += len(key) + len(value)
+		if i >= MaxEntries || size > MaxBytes {
+			return
+		}
+		headers[headerName(key)] = value
+	}
+}
+
+// Extract 解析请求头中的元数据并与 context 中已有的元数据合并，请求头中的值优先；请求头名称不区分大小写
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	incoming := make(MD)
+	size := 0
+	for name, value := range headers {
+		if len(name) <= len(HeaderPrefix) || !strings.EqualFold(name[:len(HeaderPrefix)], HeaderPrefix) {
+			continue
+		}
+		size += len(name) - len(HeaderPrefix) + len(value)
+		if len(incoming) >= MaxEntries || size > MaxBytes {
+			break
+		}
+		decoded, err := url.PathUnescape(value)
+		if err != nil {
+			continue
+		}
+		incoming.Set(name[len(HeaderPrefix):], decoded)
+	}
+	return NewOutgoingContext(ctx, incoming)
+}
+
+// headerName 返回键的请求头名称，如 tenant-id -> X-Metadata-Tenant-Id
+func headerName(key string) string {
+	parts := strings.Split(key, "-")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return HeaderPrefix + strings.Join(parts, "-")
+}
+
+// isKey 检查键是否为合法的 HTTP token
+func isKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
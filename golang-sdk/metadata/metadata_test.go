@@ -0,0 +1,133 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMD(t *testing.T) {
+	md := Pairs("Tenant", "acme", "region", "eu-1", "bad key", "x")
+	if md.Get("tenant") != "acme" || md.Get("TENANT") != "acme" || md.Get("Region") != "eu-1" {
+		t.Errorf("unexpected metadata: %v", md)
+	}
+	if len(md) != 2 {
+		t.Errorf("invalid key should be ignored: %v", md)
+	}
+
+	clone := md.Copy()
+	clone.Set("tenant", "other")
+	clone.Delete("region")
+	if md.Get("tenant") != "acme" || md.Get("region") != "eu-1" {
+		t.Error("Copy should not share storage")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Pairs with odd arguments should panic")
+		}
+	}()
+	Pairs("tenant")
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if md := FromContext(ctx); md == nil || len(md) != 0 {
+		t.Fatalf("expected empty metadata, got %v", md)
+	}
+
+	ctx = NewOutgoingContext(ctx, Pairs("tenant", "acme", "region", "eu-1"))
+	child := AppendToOutgoingContext(ctx, "region", "us-1", "user", "u-1")
+
+	if got := FromContext(child); got.Get("tenant") != "acme" || got.Get("region") != "us-1" || got.Get("user") != "u-1" {
+		t.Errorf("metadata should be merged, got %v", got)
+	}
+	if ValueFromContext(ctx, "region") != "eu-1" || ValueFromContext(ctx, "user") != "" {
+		t.Error("parent context should not be modified")
+	}
+
+	FromContext(child).Set("tenant", "changed")
+	if ValueFromContext(child, "tenant") != "acme" {
+		t.Error("FromContext should return a copy")
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      MD
+		headers map[string]string
+	}{
+		{"普通值", Pairs("tenant-id", "acme"), map[string]string{"X-Metadata-Tenant-Id": "acme"}},
+		{"需要编码的值", Pairs("note", "a b/中文"), map[string]string{"X-Metadata-Note": "a%20b%2F%E4%B8%AD%E6%96%87"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := make(map[string]string)
+			Inject(NewOutgoingContext(context.Background(), tt.md), headers)
+			for name, value := range tt.headers {
+				if headers[name] != value {
+					t.Errorf("header %s: expected %q, got %q", name, value, headers[name])
+				}
+			}
+
+			// gRPC 等传输的请求头名称为小写
+			lower := make(map[string]string)
+			for name, value := range headers {
+				lower[strings.ToLower(name)] = value
+			}
+			got := FromContext(Extract(context.Background(), lower))
+			for key, value := range tt.md {
+				if got.Get(key) != value {
+					t.Errorf("key %s: expected %q, got %q", key, value, got.Get(key))
+				}
+			}
+		})
+	}
+
+	t.Run("请求头中的值优先", func(t *testing.T) {
+		ctx := NewOutgoingContext(context.Background(), Pairs("tenant", "local", "region", "eu-1"))
+		ctx = Extract(ctx, map[string]string{"X-Metadata-Tenant": "remote", "Content-Type": "application/json"})
+		if md := FromContext(ctx); md.Get("tenant") != "remote" || md.Get("region") != "eu-1" || len(md) != 2 {
+			t.Errorf("unexpected metadata: %v", md)
+		}
+	})
+
+	t.Run("超过数量上限的部分不传输", func(t *testing.T) {
+		md := make(MD)
+		for i := 0; i < MaxEntries+10; i++ {
+			md.Set("k"+strings.Repeat("x", i), "v")
+		}
+		headers := make(map[string]string)
+		Inject(NewOutgoingContext(context.Background(), md), headers)
+		if len(headers) != MaxEntries {
+			t.Errorf("expected %d headers, got %d", MaxEntries, len(headers))
+		}
+	})
+}
+
+func TestPriority(t *testing.T) {
+	ctx := context.Background()
+	if p := PriorityFromContext(ctx); p != PriorityInteractive {
+		t.Errorf("expected interactive by default, got %v", p)
+	}
+
+	ctx = WithPriority(ctx, PriorityBatch)
+	if p := PriorityFromContext(ctx); p != PriorityBatch {
+		t.Errorf("expected batch, got %v", p)
+	}
+
+	// 优先级随元数据传给下游
+	headers := make(map[string]string)
+	Inject(ctx, headers)
+	if headers["X-Metadata-Priority"] != "batch" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if p := PriorityFromContext(Extract(context.Background(), headers)); p != PriorityBatch {
+		t.Errorf("expected batch after extract, got %v", p)
+	}
+
+	if ParsePriority("BATCH") != PriorityBatch || ParsePriority("unknown") != PriorityInteractive {
+		t.Error("unexpected ParsePriority result")
+	}
+}
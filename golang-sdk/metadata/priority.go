@@ -0,0 +1,49 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+)
+
+// PriorityKey 请求优先级的元数据键，以 X-Metadata-Priority 请求头传输
+const PriorityKey = "priority"
+
+// Priority 请求的优先级类别
+//
+// 舱壁、限流器和连接池在资源饱和时先拒绝批处理请求，为交互请求保留容量
+type Priority int
+
+const (
+	// PriorityInteractive 交互请求（用户等待结果），未标记优先级的请求按交互请求处理
+	PriorityInteractive Priority = iota
+	// PriorityBatch 批处理请求（后台任务、数据同步等），饱和时最先被拒绝
+	PriorityBatch
+)
+
+// String 返回优先级的名称
+func (p Priority) String() string {
+	switch p {
+	case PriorityBatch:
+		return "batch"
+	default:
+		return "interactive"
+	}
+}
+
+// ParsePriority 解析优先级名称，不区分大小写，无法识别时返回 PriorityInteractive
+func ParsePriority(s string) Priority {
+	if strings.EqualFold(strings.TrimSpace(s), "batch") {
+		return PriorityBatch
+	}
+	return PriorityInteractive
+}
+
+// WithPriority 返回携带优先级的 context，用该 context 发起的下游调用带上同一优先级
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return AppendToOutgoingContext(ctx, PriorityKey, p.String())
+}
+
+// PriorityFromContext 返回 context 中的优先级，包括上游传来的，未设置时返回 PriorityInteractive
+func PriorityFromContext(ctx context.Context) Priority {
+	return ParsePriority(ValueFromContext(ctx, PriorityKey))
+}
@@ -0,0 +1,246 @@
+// Package mock 根据服务描述为尚未实现的方法返回预设或生成的响应，前端和其他语言的团队可以在后端就绪前对接网关
+//
+// 方法的响应来自三种描述：描述符集（按响应消息的字段类型生成，见 codegen.Examples）、OpenAPI 文档（操作的示例或按
+// 响应结构生成）和 protocol/contract 契约文件（录制或手写的请求/响应对）。Register 将方法注册到 framework.Server，
+// 经 REST、WebSocket、JSON-RPC 等协议调用时与真实实现的方法相同
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/framework/golang-sdk/codegen"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/framework"
+	"github.com/framework/golang-sdk/protocol/contract"
+	"github.com/framework/golang-sdk/serializer/compat"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// canned 预设的响应
+type canned struct {
+	// request 请求参数与之相等时返回，为空时匹配任意请求
+	request json.RawMessage
+	status  string
+	result  json.RawMessage
+}
+
+// methodMock 一个方法的预设响应和生成的响应
+type methodMock struct {
+	canned    []*canned
+	generated json.RawMessage
+}
+
+// Mock 模拟的服务方法集合
+//
+// 调用时依次返回：请求参数相等的预设响应、匹配任意请求的预设响应、生成的响应、该方法的第一个预设响应。
+// 预设响应的状态不是 contract.StatusOK 时返回对应错误码的框架错误
+type Mock struct {
+	mu      sync.RWMutex
+	methods map[string]*methodMock
+}
+
+// New 创建空的方法集合
+func New() *Mock {
+	return &Mock{methods: make(map[string]*methodMock)}
+}
+
+// method 返回方法的响应，不存在时创建，调用方须持有写锁
+func (m *Mock) method(name string) *methodMock {
+	mm, ok := m.methods[name]
+	if !ok {
+		mm = &methodMock{}
+		m.methods[name] = mm
+	}
+	return mm
+}
+
+// setGenerated 设置方法生成的响应，后添加的描述覆盖先添加的
+func (m *Mock) setGenerated(method string, response json.RawMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.method(method).generated = response
+}
+
+// AddDescriptorSet 为描述符集中服务的方法生成响应，files 为空时使用除 google/protobuf/ 外的所有文件
+func (m *Mock) AddDescriptorSet(set *descriptorpb.FileDescriptorSet, files []string) error {
+	examples, err := codegen.Examples(set, files)
+	if err != nil {
+		return err
+	}
+	for _, example := range examples {
+		m.setGenerated(example.Method, example.Response)
+	}
+	return nil
+}
+
+// AddContract 添加契约中的交互作为预设响应，同一方法的交互按契约中的顺序匹配
+func (m *Mock) AddContract(c *contract.Contract) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, interaction := range c.Interactions {
+		mm := m.method(interaction.Service + "." + interaction.Method)
+		mm.canned = append(mm.canned, &canned{
+			request: interaction.Request,
+			status:  interaction.Status,
+			result:  interaction.Response,
+		})
+	}
+}
+
+// SetResponse 添加匹配任意请求的预设响应，result 以 JSON 编码
+func (m *Mock) SetResponse(method string, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode response for %s: %w", method, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mm := m.method(method)
+	mm.canned = append(mm.canned, &canned{status: contract.StatusOK, result: data})
+	return nil
+}
+
+// AddFile 按文件类型添加描述：.pb、.binpb、.desc 为描述符集，.yaml、.yml 为 OpenAPI 文档，
+// .json 中带 openapi 或 swagger 字段的为 OpenAPI 文档，其余为契约文件
+func (m *Mock) AddFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pb", ".binpb", ".desc":
+		set, err := compat.ParseDescriptorSet(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := m.AddDescriptorSet(set, nil); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	case ".yaml", ".yml":
+		if err := m.AddOpenAPI(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	var probe struct {
+		OpenAPI string `json:"openapi"`
+		Swagger string `json:"swagger"`
+	}
+	if json.Unmarshal(data, &probe) == nil && (probe.OpenAPI != "" || probe.Swagger != "") {
+		if err := m.AddOpenAPI(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+	c, err := contract.Read(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	m.AddContract(c)
+	return nil
+}
+
+// Methods 返回已添加的方法名（已排序）
+func (m *Mock) Methods() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	methods := make([]string, 0, len(m.methods))
+	for method := range m.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Handler 返回方法的处理器，方法未添加时返回 NotImplemented 错误
+func (m *Mock) Handler(method string) framework.Handler {
+	return func(ctx context.Context, params interface{}) (interface{}, error) {
+		return m.respond(method, params)
+	}
+}
+
+// Register 将已添加的方法注册到服务，已注册的同名方法被替换
+func (m *Mock) Register(server *framework.Server) {
+	for _, method := range m.Methods() {
+		server.Handle(method, m.Handler(method))
+	}
+}
+
+// respond 选择方法的响应
+func (m *Mock) respond(method string, params interface{}) (interface{}, error) {
+	m.mu.RLock()
+	mm, ok := m.methods[method]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotImplemented, fmt.Sprintf("no mock response for %s", method))
+	}
+
+	var request json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("invalid params for %s", method))
+		}
+		request = data
+	}
+
+	var wildcard *canned
+	for _, c := range mm.canned {
+		if len(c.request) == 0 {
+			if wildcard == nil {
+				wildcard = c
+			}
+			continue
+		}
+		if len(request) > 0 && len(contract.Compare(c.request, request, contract.MatchExact, nil)) == 0 {
+			return c.reply(method)
+		}
+	}
+	switch {
+	case wildcard != nil:
+		return wildcard.reply(method)
+	case mm.generated != nil:
+		return decode(mm.generated)
+	case len(mm.canned) > 0:
+		return mm.canned[0].reply(method)
+	}
+	return nil, nil
+}
+
+// reply 返回预设的结果，状态为错误码时返回对应的框架错误（无法识别的状态为 InternalError）
+func (c *canned) reply(method string) (interface{}, error) {
+	if c.status != "" && c.status != contract.StatusOK {
+		code := frameworkerrors.InternalError
+		if n, err := strconv.Atoi(c.status); err == nil {
+			code = frameworkerrors.FromCode(n)
+		}
+		return nil, frameworkerrors.NewFrameworkError(code, fmt.Sprintf("mock error %s for %s", c.status, method))
+	}
+	return decode(c.result)
+}
+
+// decode 解码响应的 JSON，数字保留为 json.Number，各协议按自身的序列化方式编码结果
+func decode(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "invalid mock response")
+	}
+	return value, nil
+}
@@ -0,0 +1,157 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/contract"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// call 调用模拟的方法并返回结果的 JSON
+func call(t *testing.T, m *Mock, method string, params interface{}) (string, error) {
+	t.Helper()
+	result, err := m.Handler(method)(context.Background(), params)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to encode result: %v", err)
+	}
+	return string(data), nil
+}
+
+func userDescriptorSet() *descriptorpb.FileDescriptorSet {
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user.proto"),
+			Package: proto.String("user"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("user_id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+					{Name: proto.String("name"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				},
+			}},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("UserService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetUser"), InputType: proto.String(".user.User"), OutputType: proto.String(".user.User")},
+				},
+			}},
+		}},
+	}
+}
+
+func TestMockDescriptorSetAndContract(t *testing.T) {
+	m := New()
+	if err := m.AddDescriptorSet(userDescriptorSet(), nil); err != nil {
+		t.Fatalf("AddDescriptorSet failed: %v", err)
+	}
+	if got, err := call(t, m, "userService.getUser", map[string]interface{}{"userId": 7}); err != nil || got != `{"name":"name","userId":1}` {
+		t.Fatalf("Generated response = %s, %v", got, err)
+	}
+
+	m.AddContract(&contract.Contract{Version: contract.FormatVersion, Interactions: []*contract.Interaction{
+		{Service: "userService", Method: "getUser", Request: json.RawMessage(`{"userId":7}`), Status: contract.StatusOK, Response: json.RawMessage(`{"userId":7,"name":"Alice"}`)},
+		{Service: "userService", Method: "getUser", Request: json.RawMessage(`{"userId":404}`), Status: "404"},
+	}})
+	if got, err := call(t, m, "userService.getUser", map[string]interface{}{"userId": 7}); err != nil || got != `{"name":"Alice","userId":7}` {
+		t.Errorf("Canned response = %s, %v", got, err)
+	}
+	_, err := call(t, m, "userService.getUser", map[string]interface{}{"userId": 404})
+	if payload := frameworkerrors.PayloadFromError(err); payload.Code != int(frameworkerrors.NotFound) {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+	// 没有相等的预设请求时返回生成的响应
+	if got, _ := call(t, m, "userService.getUser", map[string]interface{}{"userId": 8}); got != `{"name":"name","userId":1}` {
+		t.Errorf("Fallback response = %s", got)
+	}
+
+	if err := m.SetResponse("userService.getUser", map[string]string{"name": "Bob"}); err != nil {
+		t.Fatalf("SetResponse failed: %v", err)
+	}
+	if got, _ := call(t, m, "userService.getUser", map[string]interface{}{"userId": 8}); got != `{"name":"Bob"}` {
+		t.Errorf("Wildcard response = %s", got)
+	}
+
+	_, err = call(t, m, "orderService.get", nil)
+	if payload := frameworkerrors.PayloadFromError(err); payload.Code != int(frameworkerrors.NotImplemented) {
+		t.Errorf("Expected NotImplemented, got %v", err)
+	}
+}
+
+const openAPIDocument = `
+openapi: 3.0.3
+info: {title: orders, version: "1.0"}
+paths:
+  /orders/{id}:
+    get:
+      operationId: order.get
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+  /orders:
+    post:
+      x-framework-method: order.create
+      responses:
+        "201":
+          content:
+            application/json:
+              example: {id: o-1, status: created}
+    delete:
+      operationId: deleteOrders
+      responses:
+        "204": {}
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        id: {type: string}
+        amount: {type: number}
+        status: {type: string, enum: [paid, shipped]}
+        createdAt: {type: string, format: date-time}
+        items:
+          type: array
+          items: {$ref: '#/components/schemas/Item'}
+        parent: {$ref: '#/components/schemas/Order'}
+    Item:
+      type: object
+      properties:
+        sku: {type: string, example: SKU-1}
+        quantity: {type: integer}
+`
+
+func TestMockOpenAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	if err := os.WriteFile(path, []byte(openAPIDocument), 0o644); err != nil {
+		t.Fatalf("Failed to write document: %v", err)
+	}
+	m := New()
+	if err := m.AddFile(path); err != nil {
+		t.Fatalf("AddFile failed: %v", err)
+	}
+	if methods := m.Methods(); len(methods) != 2 || methods[0] != "order.create" || methods[1] != "order.get" {
+		t.Fatalf("Methods = %v", methods)
+	}
+
+	want := map[string]string{
+		"order.get":    `{"amount":1.5,"createdAt":"2024-01-01T00:00:00Z","id":"id","items":[{"quantity":1,"sku":"SKU-1"}],"status":"paid"}`,
+		"order.create": `{"id":"o-1","status":"created"}`,
+	}
+	for method, response := range want {
+		if got, err := call(t, m, method, nil); err != nil || got != response {
+			t.Errorf("%s = %s, %v, want %s", method, got, err, response)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtensionMethod OpenAPI 操作中指定框架方法名的扩展字段，未设置时使用形如 <服务>.<方法> 的 operationId
+const ExtensionMethod = "x-framework-method"
+
+// 按顺序选择的成功响应状态
+var successStatuses = []string{"200", "201", "202", "2XX", "default"}
+
+// openAPIOperations OpenAPI 路径项中的操作
+var openAPIOperations = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// AddOpenAPI 为 OpenAPI 3 或 Swagger 2 文档（JSON 或 YAML）中的操作生成响应
+//
+// 只处理能确定框架方法名的操作（ExtensionMethod 扩展或带 . 的 operationId）。响应取 200、201、202、2XX、default
+// 中第一个存在的状态的 JSON 内容，依次使用 example、examples 中的第一个和按 schema 生成的示例；
+// schema 中的 $ref 在 components/schemas（Swagger 2 为 definitions）中解析
+func (m *Mock) AddOpenAPI(data []byte) error {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	doc, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("OpenAPI document must be an object")
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	if len(paths) == 0 {
+		return fmt.Errorf("OpenAPI document has no paths")
+	}
+
+	g := &schemaGenerator{doc: doc, visiting: make(map[string]bool)}
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		item, _ := paths[name].(map[string]interface{})
+		for _, op := range openAPIOperations {
+			operation, ok := item[op].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			method := operationMethod(operation)
+			if method == "" {
+				continue
+			}
+			response, err := json.Marshal(g.response(operation))
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", strings.ToUpper(op), name, err)
+			}
+			m.setGenerated(method, response)
+		}
+	}
+	return nil
+}
+
+// operationMethod 返回操作对应的框架方法名，无法确定时返回空字符串
+func operationMethod(operation map[string]interface{}) string {
+	if method, _ := operation[ExtensionMethod].(string); method != "" {
+		return method
+	}
+	if id, _ := operation["operationId"].(string); strings.Contains(id, ".") {
+		return id
+	}
+	return ""
+}
+
+// normalize 将 YAML 解码出的非字符串键的映射转换为字符串键，如未加引号的状态码 200
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalize(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// schemaGenerator 按 OpenAPI schema 生成示例值
+type schemaGenerator struct {
+	doc map[string]interface{}
+	// visiting 正在展开的 $ref，用于截断递归引用
+	visiting map[string]bool
+}
+
+// response 返回操作的成功响应示例，没有成功响应时返回 nil
+func (g *schemaGenerator) response(operation map[string]interface{}) interface{} {
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, status := range successStatuses {
+		response, ok := g.resolve(responses[status]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Swagger 2：响应直接带 schema 和按媒体类型的 examples
+		if examples, ok := response["examples"].(map[string]interface{}); ok {
+			if example, ok := examples["application/json"]; ok {
+				return example
+			}
+		}
+		if schema, ok := response["schema"]; ok {
+			return g.example(schema, "")
+		}
+
+		content, _ := response["content"].(map[string]interface{})
+		media, ok := content["application/json"].(map[string]interface{})
+		if !ok {
+			for mediaType, item := range content {
+				if strings.Contains(mediaType, "json") {
+					media, _ = item.(map[string]interface{})
+					break
+				}
+			}
+		}
+		if media == nil {
+			return nil
+		}
+		if example, ok := media["example"]; ok {
+			return example
+		}
+		if examples, ok := media["examples"].(map[string]interface{}); ok {
+			keys := make([]string, 0, len(examples))
+			for key := range examples {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if example, ok := g.resolve(examples[key]).(map[string]interface{}); ok {
+					if value, ok := example["value"]; ok {
+						return value
+					}
+				}
+			}
+		}
+		return g.example(media["schema"], "")
+	}
+	return nil
+}
+
+// resolve 解析文档内的 $ref，无法解析时返回原值
+func (g *schemaGenerator) resolve(value interface{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	ref, _ := object["$ref"].(string)
+	if !strings.HasPrefix(ref, "#/") {
+		return value
+	}
+	var current interface{} = g.doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		next, ok := current.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		current = next[part]
+	}
+	if current == nil {
+		return value
+	}
+	return current
+}
+
+// example 按 schema 生成示例值，name 为所在属性名，用作字符串的示例
+func (g *schemaGenerator) example(value interface{}, name string) interface{} {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if ref, _ := schema["$ref"].(string); ref != "" {
+		if g.visiting[ref] {
+			return nil
+		}
+		g.visiting[ref] = true
+		defer delete(g.visiting, ref)
+		resolved, ok := g.resolve(schema).(map[string]interface{})
+		if !ok || resolved["$ref"] == ref {
+			return nil
+		}
+		schema = resolved
+	}
+
+	for _, key := range []string{"example", "default"} {
+		if example, ok := schema[key]; ok {
+			return example
+		}
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, part := range all {
+			if object, ok := g.example(part, name).(map[string]interface{}); ok {
+				for key, item := range object {
+					merged[key] = item
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			return g.example(options[0], name)
+		}
+	}
+
+	typ, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]interface{}); ok {
+		// OpenAPI 3.1 的类型数组，取第一个非 null 类型
+		for _, t := range types {
+			if s, _ := t.(string); s != "" && s != "null" {
+				typ = s
+				break
+			}
+		}
+	}
+	switch typ {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "byte":
+			return "ZXhhbXBsZQ=="
+		}
+		if name != "" {
+			return name
+		}
+		return "string"
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	case "array":
+		item := g.example(schema["items"], name)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "object", "":
+		object := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, property := range properties {
+			if item := g.example(property, key); item != nil {
+				object[key] = item
+			}
+		}
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok && len(properties) == 0 {
+			if item := g.example(additional, "value"); item != nil {
+				object["key"] = item
+			}
+		}
+		if typ == "" && len(object) == 0 {
+			return nil
+		}
+		return object
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package observability
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+)
+
+// 协议处理器和 TLS 证书的内置健康检查，可直接通过 RegisterCheck 注册；注册中心、连接池、熔断器和序列化器的检查
+// 由各自的包提供（registry.NewConnectivityCheck、connection.NewPoolSaturationCheck、
+// resilience.NewCircuitBreakerCheck、serializer.NewRoundTripCheck），同样实现 HealthCheck
+
+// NewProtocolHandlerHealthCheck 创建协议处理器存活检查，通过建立 TCP 连接确认监听端口可用
+func NewProtocolHandlerHealthCheck(name, address string) HealthCheck {
+	return NewSimpleHealthCheck(name, func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("protocol handler %s not accepting connections: %w", name, err)
+		}
+		return conn.Close()
+	})
+}
+
+// NewCertificateCheck 创建 TLS 证书检查，证书和私钥可以加载、匹配，且证书已生效并在 minValidity 之后仍未过期时通过
+func NewCertificateCheck(certFile, keyFile string, minValidity time.Duration) HealthCheck {
+	return NewSimpleHealthCheck("tls:"+certFile, func(ctx context.Context) error {
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate: %w", err)
+		}
+		leaf, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		now := time.Now()
+		if now.Before(leaf.NotBefore) {
+			return fmt.Errorf("certificate is not valid until %s", leaf.NotBefore.Format(time.RFC3339))
+		}
+		if now.Add(minValidity).After(leaf.NotAfter) {
+			return fmt.Errorf("certificate expires at %s, less than %s from now", leaf.NotAfter.Format(time.RFC3339), minValidity)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package observability
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProtocolHandlerHealthCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	check := NewProtocolHandlerHealthCheck("custom", addr)
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("expected handler to be alive: %v", err)
+	}
+
+	// 关闭监听后检查失败
+	listener.Close()
+	if err := check.Check(context.Background()); err == nil {
+		t.Error("expected check to fail after listener closed")
+	}
+}
+
+func TestCertificateCheck(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "selftest"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	ctx := context.Background()
+	if err := NewCertificateCheck(certFile, keyFile, 0).Check(ctx); err != nil {
+		t.Errorf("Expected valid certificate, got %v", err)
+	}
+	if err := NewCertificateCheck(certFile, keyFile, 2*time.Hour).Check(ctx); err == nil {
+		t.Error("Expected failure for certificate expiring within minValidity")
+	}
+	if err := NewCertificateCheck(filepath.Join(dir, "missing.pem"), keyFile, 0).Check(ctx); err == nil {
+		t.Error("Expected failure for missing certificate")
+	}
+}
@@ -0,0 +1,343 @@
+package observability
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 仪表盘和告警规则默认阈值
+const (
+	DefaultDashboardRateWindow    = 5 * time.Minute
+	DefaultAlertErrorRate         = 0.05
+	DefaultAlertLatencyThreshold  = time.Second
+	DefaultPoolSaturation         = 0.9 // 与 connection.DefaultPoolSaturation 一致
+	dashboardDatasourceVariable   = "${datasource}"
+	dashboardPanelWidth           = 12
+	dashboardPanelHeight          = 8
+	grafanaDashboardSchemaVersion = 39
+	dashboardUIDMaxLength         = 40
+)
+
+// dashboardUIDPattern Grafana UID 不允许的字符
+var dashboardUIDPattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// DashboardConfig Grafana 仪表盘和 Prometheus 告警规则生成配置
+type DashboardConfig struct {
+	ServiceName string
+	// Datasource Prometheus 数据源 UID，为空时生成 datasource 变量在导入时选择
+	Datasource string
+	// RateWindow 速率计算窗口，默认 5m
+	RateWindow time.Duration
+	// ErrorRate 错误率告警阈值，默认 0.05
+	ErrorRate float64
+	// LatencyThreshold P99 延迟告警阈值，默认 1s
+	LatencyThreshold time.Duration
+	// PoolSaturation 连接池饱和度告警阈值，默认 DefaultPoolSaturation
+	PoolSaturation float64
+	// BudgetWarning 错误预算剩余比例告警阈值，默认 DefaultSLOBudgetWarning
+	BudgetWarning float64
+}
+
+// withDefaults 返回填充默认值后的配置副本
+func (c DashboardConfig) withDefaults() DashboardConfig {
+	if c.RateWindow <= 0 {
+		c.RateWindow = DefaultDashboardRateWindow
+	}
+	if c.ErrorRate <= 0 {
+		c.ErrorRate = DefaultAlertErrorRate
+	}
+	if c.LatencyThreshold <= 0 {
+		c.LatencyThreshold = DefaultAlertLatencyThreshold
+	}
+	if c.PoolSaturation <= 0 || c.PoolSaturation > 1 {
+		c.PoolSaturation = DefaultPoolSaturation
+	}
+	if c.BudgetWarning <= 0 {
+		c.BudgetWarning = DefaultSLOBudgetWarning
+	}
+	return c
+}
+
+// monitoringQueries 按服务过滤的 PromQL 查询
+type monitoringQueries struct {
+	requestRate    string
+	errorRatio     string
+	latencyP50     string
+	latencyP99     string
+	errorsByCode   string
+	breakerState   string
+	poolSaturation string
+	budgetLeft     string
+}
+
+// newMonitoringQueries 生成服务的 PromQL 查询
+func newMonitoringQueries(config DashboardConfig) monitoringQueries {
+	service := "service=" + strconv.Quote(config.ServiceName)
+	window := "[" + promDuration(config.RateWindow) + "]"
+
+	total := "sum(rate(framework_request_total{" + service + "}" + window + "))"
+	latency := func(quantile string) string {
+		return "histogram_quantile(" + quantile + ", sum by (le) (rate(framework_request_duration_seconds_bucket{" + service + "}" + window + ")))"
+	}
+
+	return monitoringQueries{
+		requestRate:    "sum by (method) (rate(framework_request_total{" + service + "}" + window + "))",
+		errorRatio:     "sum(rate(framework_request_total{" + service + `,status!="` + RequestStatusSuccess + `"}` + window + ")) / " + total,
+		latencyP50:     latency("0.5"),
+		latencyP99:     latency("0.99"),
+		errorsByCode:   "sum by (error_code) (rate(framework_error_total{" + service + "}" + window + "))",
+		breakerState:   "max by (name) (framework_circuit_breaker_state{" + service + "})",
+		poolSaturation: "sum(framework_connection_pool_active_connections{" + service + "}) / sum(framework_connection_pool_max_connections{" + service + "})",
+		budgetLeft:     "min by (objective) (framework_slo_error_budget_remaining_ratio{" + service + "})",
+	}
+}
+
+// grafanaDashboard Grafana 仪表盘 JSON 模型
+type grafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	Timezone      string            `json:"timezone"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Refresh       string            `json:"refresh"`
+	Time          grafanaTimeRange  `json:"time"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+}
+
+type grafanaTimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type grafanaPanel struct {
+	ID          int                `json:"id"`
+	Type        string             `json:"type"`
+	Title       string             `json:"title"`
+	GridPos     grafanaGridPos     `json:"gridPos"`
+	Datasource  grafanaDatasource  `json:"datasource"`
+	FieldConfig grafanaFieldConfig `json:"fieldConfig"`
+	Targets     []grafanaTarget    `json:"targets"`
+}
+
+type grafanaGridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type grafanaFieldConfig struct {
+	Defaults grafanaFieldDefaults `json:"defaults"`
+}
+
+type grafanaFieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+type grafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+// GenerateGrafanaDashboard 生成服务的 Grafana 仪表盘 JSON，可直接通过 Grafana 导入
+//
+// 包含请求速率、错误率、延迟、错误码、熔断器状态、连接池饱和度和错误预算面板；
+// 熔断器和连接池面板需注册 resilience.NewCircuitBreakerCollector 和 connection.NewConnectionPoolCollector
+func GenerateGrafanaDashboard(config *DashboardConfig) ([]byte, error) {
+	if config == nil {
+		return nil, fmt.Errorf("dashboard config cannot be nil")
+	}
+	if config.ServiceName == "" {
+		return nil, fmt.Errorf("dashboard service name cannot be empty")
+	}
+
+	cfg := config.withDefaults()
+	queries := newMonitoringQueries(cfg)
+
+	datasource := grafanaDatasource{Type: "prometheus", UID: cfg.Datasource}
+	variables := []grafanaVariable{}
+	if datasource.UID == "" {
+		datasource.UID = dashboardDatasourceVariable
+		variables = append(variables, grafanaVariable{
+			Name:  "datasource",
+			Label: "Prometheus",
+			Type:  "datasource",
+			Query: "prometheus",
+		})
+	}
+
+	panels := []struct {
+		title   string
+		unit    string
+		targets []grafanaTarget
+	}{
+		{"Request rate", "reqps", []grafanaTarget{{Expr: queries.requestRate, LegendFormat: "{{method}}"}}},
+		{"Error rate", "percentunit", []grafanaTarget{{Expr: queries.errorRatio, LegendFormat: "errors"}}},
+		{"Latency", "s", []grafanaTarget{
+			{Expr: queries.latencyP50, LegendFormat: "p50"},
+			{Expr: queries.latencyP99, LegendFormat: "p99"},
+		}},
+		{"Errors by code", "reqps", []grafanaTarget{{Expr: queries.errorsByCode, LegendFormat: "{{error_code}}"}}},
+		{"Circuit breaker state (0 closed, 1 open, 2 half-open)", "short", []grafanaTarget{{Expr: queries.breakerState, LegendFormat: "{{name}}"}}},
+		{"Connection pool saturation", "percentunit", []grafanaTarget{{Expr: queries.poolSaturation, LegendFormat: "saturation"}}},
+		{"Error budget remaining", "percentunit", []grafanaTarget{{Expr: queries.budgetLeft, LegendFormat: "{{objective}}"}}},
+	}
+
+	dashboard := grafanaDashboard{
+		UID:           dashboardUID(cfg.ServiceName),
+		Title:         cfg.ServiceName + " - Framework",
+		Tags:          []string{"framework", cfg.ServiceName},
+		Timezone:      "browser",
+		SchemaVersion: grafanaDashboardSchemaVersion,
+		Refresh:       "30s",
+		Time:          grafanaTimeRange{From: "now-6h", To: "now"},
+		Templating:    grafanaTemplating{List: variables},
+		Panels:        make([]grafanaPanel, 0, len(panels)),
+	}
+
+	for i, p := range panels {
+		for j := range p.targets {
+			p.targets[j].RefID = string(rune('A' + j))
+		}
+		dashboard.Panels = append(dashboard.Panels, grafanaPanel{
+			ID:    i + 1,
+			Type:  "timeseries",
+			Title: p.title,
+			GridPos: grafanaGridPos{
+				X: (i % 2) * dashboardPanelWidth,
+				Y: (i / 2) * dashboardPanelHeight,
+				W: dashboardPanelWidth,
+				H: dashboardPanelHeight,
+			},
+			Datasource:  datasource,
+			FieldConfig: grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: p.unit}},
+			Targets:     p.targets,
+		})
+	}
+
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// alertRuleFile Prometheus 告警规则文件
+type alertRuleFile struct {
+	Groups []alertRuleGroup `yaml:"groups"`
+}
+
+type alertRuleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []alertRule `yaml:"rules"`
+}
+
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// GenerateAlertRules 生成服务的 Prometheus 告警规则 YAML，可直接作为 rule_files 加载
+func GenerateAlertRules(config *DashboardConfig) ([]byte, error) {
+	if config == nil {
+		return nil, fmt.Errorf("dashboard config cannot be nil")
+	}
+	if config.ServiceName == "" {
+		return nil, fmt.Errorf("dashboard service name cannot be empty")
+	}
+
+	cfg := config.withDefaults()
+	queries := newMonitoringQueries(cfg)
+
+	rule := func(name, expr, duration, severity, summary string) alertRule {
+		return alertRule{
+			Alert:  name,
+			Expr:   expr,
+			For:    duration,
+			Labels: map[string]string{"severity": severity, "service": cfg.ServiceName},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s: %s", cfg.ServiceName, summary),
+			},
+		}
+	}
+
+	file := alertRuleFile{
+		Groups: []alertRuleGroup{{
+			Name: cfg.ServiceName + "-framework",
+			Rules: []alertRule{
+				rule("FrameworkHighErrorRate",
+					fmt.Sprintf("%s > %s", queries.errorRatio, formatFloat(cfg.ErrorRate)),
+					"5m", "critical", fmt.Sprintf("error rate above %s%%", formatFloat(cfg.ErrorRate*100))),
+				rule("FrameworkHighLatency",
+					fmt.Sprintf("%s > %s", queries.latencyP99, formatFloat(cfg.LatencyThreshold.Seconds())),
+					"10m", "warning", fmt.Sprintf("p99 latency above %s", cfg.LatencyThreshold)),
+				rule("FrameworkCircuitBreakerOpen",
+					fmt.Sprintf("%s == %d", queries.breakerState, 1),
+					"1m", "warning", "circuit breaker {{ $labels.name }} is open"),
+				rule("FrameworkConnectionPoolSaturated",
+					fmt.Sprintf("%s > %s", queries.poolSaturation, formatFloat(cfg.PoolSaturation)),
+					"5m", "warning", fmt.Sprintf("connection pool saturation above %s%%", formatFloat(cfg.PoolSaturation*100))),
+				rule("FrameworkErrorBudgetLow",
+					fmt.Sprintf("%s < %s", queries.budgetLeft, formatFloat(cfg.BudgetWarning)),
+					"5m", "warning", "error budget for {{ $labels.objective }} nearly exhausted"),
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, fmt.Errorf("failed to encode alert rules: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode alert rules: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// dashboardUID 根据服务名生成 Grafana 仪表盘 UID
+func dashboardUID(serviceName string) string {
+	uid := "framework-" + strings.Trim(dashboardUIDPattern.ReplaceAllString(serviceName, "-"), "-")
+	if len(uid) > dashboardUIDMaxLength {
+		uid = uid[:dashboardUIDMaxLength]
+	}
+	return uid
+}
+
+// promDuration 将时长格式化为 PromQL 时长
+func promDuration(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
+
+// formatFloat 以最短形式格式化浮点数
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,97 @@
+package observability
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateGrafanaDashboard(t *testing.T) {
+	data, err := GenerateGrafanaDashboard(&DashboardConfig{ServiceName: "order-service"})
+	if err != nil {
+		t.Fatalf("GenerateGrafanaDashboard failed: %v", err)
+	}
+
+	var dashboard grafanaDashboard
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		t.Fatalf("Dashboard is not valid JSON: %v", err)
+	}
+
+	if dashboard.UID != "framework-order-service" {
+		t.Errorf("Unexpected uid: %s", dashboard.UID)
+	}
+	// 未指定数据源时生成数据源变量
+	if len(dashboard.Templating.List) != 1 || dashboard.Templating.List[0].Type != "datasource" {
+		t.Errorf("Expected datasource variable, got %+v", dashboard.Templating.List)
+	}
+
+	exprs := make([]string, 0)
+	for _, panel := range dashboard.Panels {
+		for _, target := range panel.Targets {
+			exprs = append(exprs, target.Expr)
+		}
+	}
+	joined := strings.Join(exprs, "\n")
+	for _, metric := range []string{
+		"framework_request_total",
+		"framework_request_duration_seconds_bucket",
+		"framework_circuit_breaker_state",
+		"framework_connection_pool_active_connections",
+	} {
+		if !strings.Contains(joined, metric) {
+			t.Errorf("Dashboard does not query %s", metric)
+		}
+	}
+	if strings.Count(joined, `service="order-service"`) != strings.Count(joined, "framework_") {
+		t.Error("Every query should be filtered by service")
+	}
+}
+
+func TestGenerateAlertRules(t *testing.T) {
+	data, err := GenerateAlertRules(&DashboardConfig{
+		ServiceName:      "order-service",
+		ErrorRate:        0.01,
+		LatencyThreshold: 500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAlertRules failed: %v", err)
+	}
+
+	var file alertRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Rules are not valid YAML: %v", err)
+	}
+
+	if len(file.Groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(file.Groups))
+	}
+
+	rules := make(map[string]alertRule)
+	for _, rule := range file.Groups[0].Rules {
+		rules[rule.Alert] = rule
+	}
+
+	if !strings.HasSuffix(rules["FrameworkHighErrorRate"].Expr, "> 0.01") {
+		t.Errorf("Unexpected error rate expr: %s", rules["FrameworkHighErrorRate"].Expr)
+	}
+	if !strings.HasSuffix(rules["FrameworkHighLatency"].Expr, "> 0.5") {
+		t.Errorf("Unexpected latency expr: %s", rules["FrameworkHighLatency"].Expr)
+	}
+	for _, name := range []string{"FrameworkCircuitBreakerOpen", "FrameworkConnectionPoolSaturated", "FrameworkErrorBudgetLow"} {
+		if _, ok := rules[name]; !ok {
+			t.Errorf("Missing alert %s", name)
+		}
+	}
+}
+
+func TestGenerateInvalidConfig(t *testing.T) {
+	if _, err := GenerateGrafanaDashboard(nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+	if _, err := GenerateAlertRules(&DashboardConfig{}); err == nil {
+		t.Error("Expected error for empty service name")
+	}
+}
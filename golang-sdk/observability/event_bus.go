@@ -0,0 +1,233 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/os/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// EventType 框架事件类型
+type EventType string
+
+const (
+	// EventCircuitBreakerOpened 熔断器打开
+	EventCircuitBreakerOpened EventType = "circuit_breaker.opened"
+	// EventCircuitBreakerHalfOpen 熔断器进入半开状态
+	EventCircuitBreakerHalfOpen EventType = "circuit_breaker.half_open"
+	// EventCircuitBreakerClosed 熔断器关闭
+	EventCircuitBreakerClosed EventType = "circuit_breaker.closed"
+	// EventEndpointEjected 端点被移出路由表
+	EventEndpointEjected EventType = "endpoint.ejected"
+	// EventInstanceExpired 注册中心实例心跳超时被清理
+	EventInstanceExpired EventType = "registry.instance_expired"
+	// EventConfigReloaded 配置（如 TLS 证书）重新加载
+	EventConfigReloaded EventType = "config.reloaded"
+	// EventServiceWaiting 等待的远程服务未就绪，即将重试
+	EventServiceWaiting EventType = "service.waiting"
+	// EventServiceReady 等待的远程服务已就绪
+	EventServiceReady EventType = "service.ready"
+	// EventServiceWaitTimeout 等待远程服务超时
+	EventServiceWaitTimeout EventType = "service.wait_timeout"
+)
+
+// DefaultEventBufferSize 默认事件缓冲区大小
+const DefaultEventBufferSize = 256
+
+var (
+	// 用于防止重复注册的锁
+	eventMetricsOnce sync.Once
+	// 事件计数器
+	eventTotal *prometheus.CounterVec
+	// 缓冲区满被丢弃的事件计数器
+	eventDroppedTotal *prometheus.CounterVec
+)
+
+// initEventMetrics 初始化事件指标
+func initEventMetrics() {
+	eventMetricsOnce.Do(func() {
+		eventTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_events_total",
+				Help: "Total number of framework events",
+			},
+			[]string{"type"},
+		)
+		eventDroppedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_events_dropped_total",
+				Help: "Total number of framework events dropped because the event buffer was full",
+			},
+			[]string{"type"},
+		)
+	})
+}
+
+// Event 框架事件
+type Event struct {
+	Type EventType `json:"type"`
+	// Source 事件来源，如熔断器名、服务名或证书文件
+	Source     string            `json:"source"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// EventHandler 事件处理函数
+type EventHandler func(ctx context.Context, event Event)
+
+// EventBusConfig 事件总线配置
+type EventBusConfig struct {
+	// BufferSize 事件缓冲区大小，缓冲区满时新事件被丢弃，默认 DefaultEventBufferSize
+	BufferSize int
+}
+
+// EventBus 框架事件总线
+//
+// 事件异步投递，单个订阅者处理缓慢或 panic 不会阻塞发布方
+type EventBus struct {
+	events      chan Event
+	subscribers map[int]*eventSubscriber
+	nextID      int
+	mu          sync.RWMutex
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+	closeOnce   sync.Once
+}
+
+// eventSubscriber 事件订阅者
+type eventSubscriber struct {
+	handler EventHandler
+	types   map[EventType]bool
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus(config *EventBusConfig) (*EventBus, error) {
+	if config == nil {
+		return nil, fmt.Errorf("event bus config cannot be nil")
+	}
+
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+
+	initEventMetrics()
+
+	b := &EventBus{
+		events:      make(chan Event, bufferSize),
+		subscribers: make(map[int]*eventSubscriber),
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+
+	go b.dispatch()
+
+	return b, nil
+}
+
+// Subscribe 订阅事件，不指定类型时订阅全部事件，返回取消订阅函数
+func (b *EventBus) Subscribe(handler EventHandler, types ...EventType) func() {
+	sub := &eventSubscriber{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish 发布事件，不会阻塞；总线已关闭或缓冲区满时事件被丢弃
+func (b *EventBus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	eventTotal.WithLabelValues(string(event.Type)).Inc()
+
+	select {
+	case <-b.stopCh:
+		eventDroppedTotal.WithLabelValues(string(event.Type)).Inc()
+		return
+	default:
+	}
+
+	select {
+	case b.events <- event:
+	default:
+		eventDroppedTotal.WithLabelValues(string(event.Type)).Inc()
+	}
+}
+
+// Close 停止事件投递，已缓冲的事件在返回前投递完毕
+func (b *EventBus) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.stopCh)
+		<-b.doneCh
+	})
+	return nil
+}
+
+// dispatch 将事件投递给订阅者
+func (b *EventBus) dispatch() {
+	defer close(b.doneCh)
+
+	for {
+		select {
+		case event := <-b.events:
+			b.deliver(event)
+		case <-b.stopCh:
+			// 投递剩余事件
+			for {
+				select {
+				case event := <-b.events:
+					b.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver 投递单个事件
+func (b *EventBus) deliver(event Event) {
+	b.mu.RLock()
+	subscribers := make([]*eventSubscriber, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		if sub.types == nil || sub.types[event.Type] {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		b.invoke(sub.handler, event)
+	}
+}
+
+// invoke 调用订阅者，恢复订阅者的 panic
+func (b *EventBus) invoke(handler EventHandler, event Event) {
+	ctx := context.Background()
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Errorf(ctx, "Event handler panic for %s: %v", event.Type, r)
+		}
+	}()
+	handler(ctx, event)
+}
@@ -0,0 +1,199 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/client"
+	"github.com/framework/golang-sdk/httpclient"
+	"github.com/framework/golang-sdk/protocol/router"
+	"github.com/framework/golang-sdk/registry"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/framework/golang-sdk/security"
+)
+
+// eventRecorder 记录收到的事件
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) handle(ctx context.Context, event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) types() []EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]EventType, 0, len(r.events))
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestNewEventBus(t *testing.T) {
+	if _, err := NewEventBus(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+
+	bus, err := NewEventBus(&EventBusConfig{})
+	if err != nil {
+		t.Fatalf("NewEventBus() error = %v", err)
+	}
+	bus.Close()
+}
+
+func TestEventBusSubscribeByType(t *testing.T) {
+	bus, _ := NewEventBus(&EventBusConfig{})
+
+	all := &eventRecorder{}
+	breakers := &eventRecorder{}
+	bus.Subscribe(all.handle)
+	bus.Subscribe(breakers.handle, EventCircuitBreakerOpened)
+
+	bus.Publish(Event{Type: EventCircuitBreakerOpened, Source: "user-service"})
+	bus.Publish(Event{Type: EventConfigReloaded, Source: "tls"})
+	bus.Close()
+
+	if got := all.types(); len(got) != 2 {
+		t.Errorf("expected 2 events for catch-all subscriber, got %v", got)
+	}
+	if got := breakers.types(); len(got) != 1 || got[0] != EventCircuitBreakerOpened {
+		t.Errorf("expected only breaker event, got %v", got)
+	}
+}
+
+func TestEventBusUnsubscribeAndPanic(t *testing.T) {
+	bus, _ := NewEventBus(&EventBusConfig{})
+
+	// 订阅者 panic 不影响其他订阅者
+	bus.Subscribe(func(ctx context.Context, event Event) { panic("boom") })
+
+	recorder := &eventRecorder{}
+	unsubscribe := bus.Subscribe(recorder.handle)
+	unsubscribe()
+
+	survivor := &eventRecorder{}
+	bus.Subscribe(survivor.handle)
+
+	bus.Publish(Event{Type: EventEndpointEjected})
+	bus.Close()
+
+	if got := recorder.types(); len(got) != 0 {
+		t.Errorf("unsubscribed handler received events: %v", got)
+	}
+	if got := survivor.types(); len(got) != 1 {
+		t.Errorf("expected survivor to receive 1 event, got %v", got)
+	}
+
+	// 关闭后发布的事件被丢弃
+	bus.Publish(Event{Type: EventEndpointEjected})
+}
+
+func TestEventBusWatchCircuitBreaker(t *testing.T) {
+	bus, _ := NewEventBus(&EventBusConfig{})
+	recorder := &eventRecorder{}
+	bus.Subscribe(recorder.handle)
+
+	cb := resilience.NewCircuitBreaker("inventory", 1, 1, time.Millisecond)
+	bus.Watch(cb)
+
+	cb.RecordFailure()
+	time.Sleep(2 * time.Millisecond)
+	cb.AllowRequest()
+	cb.RecordSuccess()
+	bus.Close()
+
+	want := []EventType{EventCircuitBreakerOpened, EventCircuitBreakerHalfOpen, EventCircuitBreakerClosed}
+	got := recorder.types()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEventBusWatchRpcProxy(t *testing.T) {
+	bus, _ := NewEventBus(&EventBusConfig{})
+	recorder := &eventRecorder{}
+	bus.Subscribe(recorder.handle)
+
+	// 取得一个未监听的端口
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	proxy := client.NewRpcProxy().AddService("php-service", "127.0.0.1", port)
+	bus.Watch(proxy)
+	proxy.WaitForService(context.Background(), "php-service", 300*time.Millisecond)
+	bus.Close()
+
+	got := recorder.types()
+	if len(got) < 2 || got[0] != EventServiceWaiting || got[len(got)-1] != EventServiceWaitTimeout {
+		t.Errorf("events = %v, want waiting then wait_timeout", got)
+	}
+}
+
+// 组件以字符串常量发布事件，须与 EventType 常量保持一致
+func TestEventSourceTypes(t *testing.T) {
+	var _ EventSource = (*resilience.CircuitBreaker)(nil)
+	var _ EventSource = (*router.DefaultMessageRouter)(nil)
+	var _ EventSource = (*registry.MemoryRegistry)(nil)
+	var _ EventSource = (*security.TLSManager)(nil)
+	var _ EventSource = (*client.RpcProxy)(nil)
+	var _ WebhookClient = (*httpclient.Client)(nil)
+
+	tests := []struct {
+		got  string
+		want EventType
+	}{
+		{resilience.EventCircuitBreakerOpened, EventCircuitBreakerOpened},
+		{resilience.EventCircuitBreakerHalfOpen, EventCircuitBreakerHalfOpen},
+		{resilience.EventCircuitBreakerClosed, EventCircuitBreakerClosed},
+		{router.EventEndpointEjected, EventEndpointEjected},
+		{registry.EventInstanceExpired, EventInstanceExpired},
+		{security.EventConfigReloaded, EventConfigReloaded},
+		{client.EventServiceWaiting, EventServiceWaiting},
+		{client.EventServiceReady, EventServiceReady},
+		{client.EventServiceWaitTimeout, EventServiceWaitTimeout},
+	}
+	for _, tt := range tests {
+		if EventType(tt.got) != tt.want {
+			t.Errorf("component event type %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestWebhookEventHandler(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+	}))
+	defer server.Close()
+
+	handler := NewWebhookEventHandler(server.URL, time.Second)
+	handler(context.Background(), Event{Type: EventInstanceExpired, Source: "order-service"})
+
+	select {
+	case event := <-received:
+		if event.Type != EventInstanceExpired || event.Source != "order-service" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("webhook did not receive event")
+	}
+}
@@ -0,0 +1,103 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gogf/gf/v2/os/glog"
+)
+
+// DefaultWebhookTimeout 默认 webhook 请求超时
+const DefaultWebhookTimeout = 5 * time.Second
+
+// EventSource 框架组件事件源，组件在 WatchEvents 中注册自身的监听器，经 emit 发布事件
+//
+// 方法签名只使用内置类型，组件无需依赖 observability 即可实现；eventType 取值与 EventType 常量一致。
+// resilience.CircuitBreaker、protocol/router.DefaultMessageRouter、registry.MemoryRegistry、
+// security.TLSManager 和 client.RpcProxy 实现了该接口
+type EventSource interface {
+	WatchEvents(emit func(eventType, source string, attributes map[string]string))
+}
+
+// Watch 订阅事件源，将其事件发布到事件总线
+func (b *EventBus) Watch(sources ...EventSource) {
+	for _, source := range sources {
+		source.WatchEvents(func(eventType, source string, attributes map[string]string) {
+			b.Publish(Event{Type: EventType(eventType), Source: source, Attributes: attributes})
+		})
+	}
+}
+
+// NewLogEventHandler 创建将事件写入日志的订阅者
+func NewLogEventHandler(logger Logger) EventHandler {
+	return func(ctx context.Context, event Event) {
+		fields := make([]Field, 0, len(event.Attributes)+2)
+		fields = append(fields,
+			Field{Key: "event_type", Value: string(event.Type)},
+			Field{Key: "source", Value: event.Source},
+		)
+		for k, v := range event.Attributes {
+			fields = append(fields, Field{Key: k, Value: v})
+		}
+		logger.Info(ctx, "Framework event", fields...)
+	}
+}
+
+// WebhookClient webhook 使用的出站客户端，httpclient.Client 满足该接口，可用于为 webhook 配置签名、代理或重试
+type WebhookClient interface {
+	PostJSON(ctx context.Context, url string, body interface{}) (*http.Response, error)
+}
+
+// defaultWebhookClient 基于 net/http 的默认 webhook 客户端
+type defaultWebhookClient struct {
+	client *http.Client
+}
+
+// PostJSON 以 JSON 形式 POST body
+func (c *defaultWebhookClient) PostJSON(ctx context.Context, url string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.client.Do(req)
+}
+
+// NewWebhookEventHandler 创建以 JSON 形式 POST 事件到指定地址的订阅者
+func NewWebhookEventHandler(url string, timeout time.Duration) EventHandler {
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return NewWebhookEventHandlerWithClient(url, &defaultWebhookClient{client: &http.Client{Timeout: timeout}})
+}
+
+// NewWebhookEventHandlerWithClient 创建经指定出站客户端 POST 事件的订阅者，用于为 webhook 配置签名、代理或重试
+func NewWebhookEventHandlerWithClient(url string, client WebhookClient) EventHandler {
+	return func(ctx context.Context, event Event) {
+		if err := postEvent(ctx, client, url, event); err != nil {
+			glog.Warningf(ctx, "Failed to deliver event %s to webhook: %v", event.Type, err)
+		}
+	}
+}
+
+// postEvent 发送事件到 webhook
+func postEvent(ctx context.Context, client WebhookClient, url string, event Event) error {
+	resp, err := client.PostJSON(ctx, url, event)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -38,8 +38,7 @@ func Example_basicUsage() {
 func Example_logging() {
 	logger := observability.NewLogger("my-service")
 
-	ctx := context.WithValue(context.Background(), "request_id", "req-12345")
-	ctx = context.WithValue(ctx, "timestamp", "2024-01-01T12:00:00Z")
+	ctx := observability.WithRequestID(context.Background(), "req-12345")
 
 	// 不同级别的日志
 	logger.Debug(ctx, "Debug information", observability.Field{Key: "detail", Value: "some detail"})
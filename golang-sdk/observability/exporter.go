@@ -0,0 +1,258 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/framework/golang-sdk/idgen"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// OTLP 导出协议
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+)
+
+// 导出默认值
+const (
+	DefaultBatchTimeout         = 5 * time.Second
+	DefaultMetricExportInterval = 60 * time.Second
+)
+
+// ExporterConfig OTLP 导出配置，对应 framework.observability.tracing
+type ExporterConfig struct {
+	Enabled  bool
+	Exporter string // otlp-grpc 或 otlp-http
+	// Endpoint 收集器地址，如 localhost:4317 或 http://collector:4318；http:// 前缀表示不使用 TLS
+	Endpoint     string
+	Headers      map[string]string // 附加请求头，如认证令牌
+	Insecure     bool              // 不使用 TLS
+	// SamplingRate 没有上游 span 的请求的采样率 0~1，为 nil 时全部采样，为 0 时不采样；有上游 span 时沿用上游的采样决定
+	SamplingRate *float64
+	// BatchTimeout span 批量导出的最大等待时间
+	BatchTimeout time.Duration
+	// MetricExportInterval 指标导出周期
+	MetricExportInterval time.Duration
+	// IDGenerator span 的追踪 ID 和 span ID 生成器，为 nil 时使用 OpenTelemetry SDK 的随机生成器
+	IDGenerator idgen.Generator
+	// Resource span 和指标的资源属性，为 nil 时以 DetectResource 检测（只设置 service.name）
+	Resource *resource.Resource
+}
+
+// SamplingRate 返回 rate 的指针，用于设置 ExporterConfig.SamplingRate
+func SamplingRate(rate float64) *float64 {
+	return &rate
+}
+
+// TelemetryExporter OTLP 追踪和指标导出器
+//
+// 创建后注册为全局 TracerProvider 和 MeterProvider，Tracer 产生的 span 会批量导出到收集器
+type TelemetryExporter struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+}
+
+// NewTelemetryExporter 创建 OTLP 导出器
+func NewTelemetryExporter(ctx context.Context, serviceName string, config *ExporterConfig) (*TelemetryExporter, error) {
+	if config == nil {
+		return nil, fmt.Errorf("exporter config cannot be nil")
+	}
+
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("exporter endpoint cannot be empty")
+	}
+
+	endpoint, urlPath, insecure, err := parseExporterEndpoint(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	insecure = insecure || config.Insecure
+
+	batchTimeout := config.BatchTimeout
+	if batchTimeout <= 0 {
+		batchTimeout = DefaultBatchTimeout
+	}
+
+	exportInterval := config.MetricExportInterval
+	if exportInterval <= 0 {
+		exportInterval = DefaultMetricExportInterval
+	}
+
+	res := config.Resource
+	if res == nil {
+		res = DetectResource(ctx, &ResourceConfig{ServiceName: serviceName})
+	}
+
+	var (
+		spanExporter   sdktrace.SpanExporter
+		metricExporter sdkmetric.Exporter
+	)
+
+	switch config.Exporter {
+	case ExporterOTLPGRPC, "otlp", "":
+		traceOpts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithHeaders(config.Headers),
+		}
+		if insecure {
+			traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+		}
+
+		spanExporter, err = otlptracegrpc.New(ctx, traceOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP gRPC trace exporter: %w", err)
+		}
+	case ExporterOTLPHTTP:
+		traceOpts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithHeaders(config.Headers),
+		}
+		if urlPath != "" {
+			traceOpts = append(traceOpts, otlptracehttp.WithURLPath(urlPath))
+		}
+		if insecure {
+			traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		}
+
+		spanExporter, err = otlptracehttp.New(ctx, traceOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP HTTP trace exporter: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported exporter: %s", config.Exporter)
+	}
+
+	metricExporter, err = newOTLPMetricExporter(ctx, config.Exporter, endpoint, config.Headers, insecure)
+	if err != nil {
+		spanExporter.Shutdown(ctx)
+		return nil, err
+	}
+
+	providerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(spanExporter, sdktrace.WithBatchTimeout(batchTimeout)),
+		sdktrace.WithSampler(newSampler(config.SamplingRate)),
+		sdktrace.WithResource(res),
+	}
+	if config.IDGenerator != nil {
+		providerOpts = append(providerOpts, sdktrace.WithIDGenerator(&idgen.OTelGenerator{Generator: config.IDGenerator}))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(providerOpts...)
+
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(exportInterval))),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+
+	return &TelemetryExporter{
+		tracerProvider: tracerProvider,
+		meterProvider:  meterProvider,
+	}, nil
+}
+
+// newSampler 按采样率创建采样器，有上游 span 时沿用上游的采样决定；rate 为 nil 时全部采样，不大于 0 时不采样
+func newSampler(rate *float64) sdktrace.Sampler {
+	root := sdktrace.AlwaysSample()
+	if rate != nil {
+		if *rate <= 0 {
+			root = sdktrace.NeverSample()
+		} else {
+			root = sdktrace.TraceIDRatioBased(*rate)
+		}
+	}
+	return sdktrace.ParentBased(root)
+}
+
+// RegisterSpanProcessor 向 TracerProvider 追加 span 处理器，如慢请求检测的 span 记录器
+func (e *TelemetryExporter) RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	e.tracerProvider.RegisterSpanProcessor(processor)
+}
+
+// ForceFlush 立即导出所有缓冲的 span 和指标
+func (e *TelemetryExporter) ForceFlush(ctx context.Context) error {
+	return errors.Join(
+		e.tracerProvider.ForceFlush(ctx),
+		e.meterProvider.ForceFlush(ctx),
+	)
+}
+
+// Shutdown 导出剩余数据并关闭导出器
+func (e *TelemetryExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		e.tracerProvider.Shutdown(ctx),
+		e.meterProvider.Shutdown(ctx),
+	)
+}
+
+// newOTLPMetricExporter 创建 OTLP 指标导出器
+func newOTLPMetricExporter(ctx context.Context, exporter, endpoint string, headers map[string]string, insecure bool) (sdkmetric.Exporter, error) {
+	switch exporter {
+	case ExporterOTLPGRPC, "otlp", "":
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(endpoint),
+			otlpmetricgrpc.WithHeaders(headers),
+		}
+		if insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+
+		metricExporter, err := otlpmetricgrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP gRPC metric exporter: %w", err)
+		}
+		return metricExporter, nil
+	case ExporterOTLPHTTP:
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(endpoint),
+			otlpmetrichttp.WithHeaders(headers),
+		}
+		if insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+
+		metricExporter, err := otlpmetrichttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP HTTP metric exporter: %w", err)
+		}
+		return metricExporter, nil
+	default:
+		return nil, fmt.Errorf("unsupported exporter: %s", exporter)
+	}
+}
+
+// parseExporterEndpoint 解析收集器地址，返回 host:port、URL 路径及是否明文传输
+func parseExporterEndpoint(endpoint string) (string, string, bool, error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, "", false, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid exporter endpoint: %w", err)
+	}
+
+	if u.Host == "" {
+		return "", "", false, fmt.Errorf("invalid exporter endpoint: %s", endpoint)
+	}
+
+	urlPath := u.Path
+	if urlPath == "/" {
+		urlPath = ""
+	}
+
+	return u.Host, urlPath, u.Scheme == "http", nil
+}
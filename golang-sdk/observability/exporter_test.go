@@ -0,0 +1,130 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseExporterEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		endpoint     string
+		wantHost     string
+		wantPath     string
+		wantInsecure bool
+		wantErr      bool
+	}{
+		{name: "host and port", endpoint: "localhost:4317", wantHost: "localhost:4317"},
+		{name: "http url", endpoint: "http://collector:4318", wantHost: "collector:4318", wantInsecure: true},
+		{name: "https url with path", endpoint: "https://collector:4318/custom/v1/traces", wantHost: "collector:4318", wantPath: "/custom/v1/traces"},
+		{name: "missing host", endpoint: "http://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, urlPath, insecure, err := parseExporterEndpoint(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExporterEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if host != tt.wantHost || urlPath != tt.wantPath || insecure != tt.wantInsecure {
+				t.Errorf("parseExporterEndpoint() = (%s, %s, %v), want (%s, %s, %v)",
+					host, urlPath, insecure, tt.wantHost, tt.wantPath, tt.wantInsecure)
+			}
+		})
+	}
+}
+
+func TestNewTelemetryExporterInvalidConfig(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewTelemetryExporter(ctx, "test-service", nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+
+	if _, err := NewTelemetryExporter(ctx, "test-service", &ExporterConfig{Enabled: true}); err == nil {
+		t.Error("Expected error for empty endpoint")
+	}
+
+	if _, err := NewTelemetryExporter(ctx, "test-service", &ExporterConfig{
+		Enabled:  true,
+		Exporter: "zipkin",
+		Endpoint: "localhost:9411",
+	}); err == nil {
+		t.Error("Expected error for unsupported exporter")
+	}
+}
+
+func TestTelemetryExporterShutdown(t *testing.T) {
+	ctx := context.Background()
+	// 导出器替换全局 TracerProvider，关闭后恢复，避免影响其他测试
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	// 导出器连接是惰性的，收集器不可用时也能创建
+	exporter, err := NewTelemetryExporter(ctx, "test-service", &ExporterConfig{
+		Enabled:      true,
+		Exporter:     ExporterOTLPHTTP,
+		Endpoint:     "http://127.0.0.1:4318",
+		SamplingRate: SamplingRate(1),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+
+	tracer := NewTracer("test-service")
+	_, span := tracer.StartSpan(ctx, "exported-operation")
+	tracer.EndSpan(span, nil)
+
+	if !span.SpanContext().IsSampled() {
+		t.Error("Expected span to be sampled")
+	}
+
+	// 收集器不可用时关闭也不应阻塞超过超时时间
+	shutdownCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	exporter.Shutdown(shutdownCtx)
+}
+
+// TestNewSampler 测试未设置采样率时全部采样，采样率为 0 时不采样，有上游 span 时沿用上游的采样决定
+func TestNewSampler(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sampledParent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+
+	tests := []struct {
+		name       string
+		rate       *float64
+		ctx        context.Context
+		wantSample bool
+	}{
+		{"未设置", nil, context.Background(), true},
+		{"全部采样", SamplingRate(1), context.Background(), true},
+		{"不采样", SamplingRate(0), context.Background(), false},
+		{"不采样时沿用上游的采样决定", SamplingRate(0), sampledParent, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newSampler(tt.rate).ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: tt.ctx,
+				TraceID:       traceID,
+				Name:          "operation",
+			})
+			if sampled := result.Decision == sdktrace.RecordAndSample; sampled != tt.wantSample {
+				t.Errorf("sampled = %v, want %v", sampled, tt.wantSample)
+			}
+		})
+	}
+}
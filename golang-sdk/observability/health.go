@@ -3,6 +3,7 @@ package observability
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -23,9 +24,42 @@ type HealthCheck interface {
 	Check(ctx context.Context) error
 }
 
+// CheckKind 健康检查类别
+type CheckKind string
+
+const (
+	// CheckKindLiveness 存活检查，失败表示进程需要重启
+	CheckKindLiveness CheckKind = "liveness"
+	// CheckKindReadiness 就绪检查，失败表示暂时不应接收流量
+	CheckKindReadiness CheckKind = "readiness"
+)
+
+// DefaultCheckTimeout 默认单次检查超时
+const DefaultCheckTimeout = 5 * time.Second
+
+// CheckOptions 健康检查选项
+type CheckOptions struct {
+	Kind CheckKind
+	// Critical 关键检查失败时整体状态为 unhealthy，非关键检查失败时为 degraded
+	Critical bool
+	// Timeout 单次检查超时，默认 DefaultCheckTimeout
+	Timeout time.Duration
+	// CacheTTL 检查结果缓存时间，0 表示每次都重新检查
+	CacheTTL time.Duration
+}
+
+// registeredCheck 已注册的健康检查
+type registeredCheck struct {
+	check     HealthCheck
+	options   CheckOptions
+	cached    CheckResult
+	expiresAt time.Time
+	cacheMu   sync.Mutex
+}
+
 // HealthChecker 健康检查器
 type HealthChecker struct {
-	checks      map[string]HealthCheck
+	checks      map[string]*registeredCheck
 	mu          sync.RWMutex
 	serviceName string
 }
@@ -47,22 +81,101 @@ type CheckResult struct {
 // NewHealthChecker 创建新的健康检查器
 func NewHealthChecker(serviceName string) *HealthChecker {
 	return &HealthChecker{
-		checks:      make(map[string]HealthCheck),
+		checks:      make(map[string]*registeredCheck),
 		serviceName: serviceName,
 	}
 }
 
-// RegisterCheck 注册健康检查
+// RegisterCheck 注册健康检查，作为关键的就绪检查
 func (h *HealthChecker) RegisterCheck(check HealthCheck) {
+	h.RegisterCheckWithOptions(check, CheckOptions{
+		Kind:     CheckKindReadiness,
+		Critical: true,
+	})
+}
+
+// RegisterLivenessCheck 注册存活检查
+func (h *HealthChecker) RegisterLivenessCheck(check HealthCheck) {
+	h.RegisterCheckWithOptions(check, CheckOptions{
+		Kind:     CheckKindLiveness,
+		Critical: true,
+	})
+}
+
+// RegisterCheckWithOptions 按选项注册健康检查
+func (h *HealthChecker) RegisterCheckWithOptions(check HealthCheck, options CheckOptions) {
+	if options.Kind == "" {
+		options.Kind = CheckKindReadiness
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultCheckTimeout
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.checks[check.Name()] = check
+	h.checks[check.Name()] = &registeredCheck{
+		check:   check,
+		options: options,
+	}
 }
 
 // Check 执行所有健康检查
 func (h *HealthChecker) Check(ctx context.Context) HealthResponse {
+	return h.run(ctx, "")
+}
+
+// CheckLiveness 执行存活检查
+func (h *HealthChecker) CheckLiveness(ctx context.Context) HealthResponse {
+	return h.run(ctx, CheckKindLiveness)
+}
+
+// CheckReadiness 执行就绪检查
+func (h *HealthChecker) CheckReadiness(ctx context.Context) HealthResponse {
+	return h.run(ctx, CheckKindReadiness)
+}
+
+// Handler 返回 HTTP 处理器，汇总所有检查
+func (h *HealthChecker) Handler() http.HandlerFunc {
+	return h.handler(h.Check)
+}
+
+// LivenessHandler 返回存活检查 HTTP 处理器（/health/live）
+func (h *HealthChecker) LivenessHandler() http.HandlerFunc {
+	return h.handler(h.CheckLiveness)
+}
+
+// ReadinessHandler 返回就绪检查 HTTP 处理器（/health/ready）
+func (h *HealthChecker) ReadinessHandler() http.HandlerFunc {
+	return h.handler(h.CheckReadiness)
+}
+
+// handler 构造 HTTP 处理器，degraded 状态仍返回 200
+func (h *HealthChecker) handler(check func(ctx context.Context) HealthResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		response := check(ctx)
+
+		w.Header().Set("Content-Type", "application/json")
+		if response.Status == HealthStatusUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// run 并发执行指定类别的检查，kind 为空时执行全部检查
+func (h *HealthChecker) run(ctx context.Context, kind CheckKind) HealthResponse {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
+	checks := make(map[string]*registeredCheck, len(h.checks))
+	for name, rc := range h.checks {
+		if kind == "" || rc.options.Kind == kind {
+			checks[name] = rc
+		}
+	}
+	h.mu.RUnlock()
 
 	response := HealthResponse{
 		Status:    HealthStatusHealthy,
@@ -71,20 +184,33 @@ func (h *HealthChecker) Check(ctx context.Context) HealthResponse {
 		Checks:    make(map[string]CheckResult),
 	}
 
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]CheckResult, len(checks))
+	)
+	for name, rc := range checks {
+		wg.Add(1)
+		go func(name string, rc *registeredCheck) {
+			defer wg.Done()
+			result := rc.execute(ctx)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, rc)
+	}
+	wg.Wait()
+
 	hasUnhealthy := false
 	hasDegraded := false
 
-	for name, check := range h.checks {
-		err := check.Check(ctx)
-		if err != nil {
-			hasUnhealthy = true
-			response.Checks[name] = CheckResult{
-				Status:  HealthStatusUnhealthy,
-				Message: err.Error(),
-			}
-		} else {
-			response.Checks[name] = CheckResult{
-				Status: HealthStatusHealthy,
+	for name, result := range results {
+		response.Checks[name] = result
+		if result.Status != HealthStatusHealthy {
+			if checks[name].options.Critical {
+				hasUnhealthy = true
+			} else {
+				hasDegraded = true
 			}
 		}
 	}
@@ -98,20 +224,47 @@ func (h *HealthChecker) Check(ctx context.Context) HealthResponse {
 	return response
 }
 
-// Handler 返回 HTTP 处理器
-func (h *HealthChecker) Handler() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		response := h.Check(ctx)
+// execute 执行单个检查，命中缓存时直接返回缓存结果
+func (rc *registeredCheck) execute(ctx context.Context) CheckResult {
+	if rc.options.CacheTTL > 0 {
+		rc.cacheMu.Lock()
+		defer rc.cacheMu.Unlock()
+		if time.Now().Before(rc.expiresAt) {
+			return rc.cached
+		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		if response.Status == HealthStatusHealthy {
-			w.WriteHeader(http.StatusOK)
-		} else {
-			w.WriteHeader(http.StatusServiceUnavailable)
+	result := CheckResult{Status: HealthStatusHealthy}
+	if err := rc.checkWithTimeout(ctx); err != nil {
+		result = CheckResult{
+			Status:  HealthStatusUnhealthy,
+			Message: err.Error(),
 		}
+	}
 
-		json.NewEncoder(w).Encode(response)
+	if rc.options.CacheTTL > 0 {
+		rc.cached = result
+		rc.expiresAt = time.Now().Add(rc.options.CacheTTL)
+	}
+
+	return result
+}
+
+// checkWithTimeout 在超时时间内执行检查
+func (rc *registeredCheck) checkWithTimeout(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, rc.options.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rc.check.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out after %v", rc.options.Timeout)
 	}
 }
 
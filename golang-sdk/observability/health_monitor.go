@@ -0,0 +1,138 @@
+package observability
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 健康监控默认值
+const (
+	DefaultHealthMonitorInterval  = 10 * time.Second
+	DefaultHealthFailureThreshold = 3
+	DefaultHealthSuccessThreshold = 1
+)
+
+// HealthMonitorConfig 健康监控配置
+type HealthMonitorConfig struct {
+	// Interval 就绪检查周期，默认 DefaultHealthMonitorInterval
+	Interval time.Duration
+	// FailureThreshold 连续多少次就绪检查为 unhealthy 后变为不健康，默认 DefaultHealthFailureThreshold
+	FailureThreshold int
+	// SuccessThreshold 不健康后连续多少次就绪检查通过后恢复，默认 DefaultHealthSuccessThreshold
+	SuccessThreshold int
+	// OnChange 健康状态变化时在监控协程中调用，response 为触发变化的检查结果
+	OnChange func(ctx context.Context, healthy bool, response HealthResponse)
+}
+
+// HealthMonitor 周期执行就绪检查，健康状态变化时通知订阅方（如将服务实例从注册中心摘除）
+//
+// degraded 视为健康；连续失败和连续成功的阈值避免状态在检查结果抖动时反复变化
+type HealthMonitor struct {
+	checker   *HealthChecker
+	config    HealthMonitorConfig
+	healthy   atomic.Bool
+	failures  int
+	successes int
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHealthMonitor 创建健康监控并开始周期检查，初始状态为健康
+func NewHealthMonitor(checker *HealthChecker, config *HealthMonitorConfig) *HealthMonitor {
+	m := &HealthMonitor{
+		checker: checker,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	if config != nil {
+		m.config = *config
+	}
+	if m.config.Interval <= 0 {
+		m.config.Interval = DefaultHealthMonitorInterval
+	}
+	if m.config.FailureThreshold <= 0 {
+		m.config.FailureThreshold = DefaultHealthFailureThreshold
+	}
+	if m.config.SuccessThreshold <= 0 {
+		m.config.SuccessThreshold = DefaultHealthSuccessThreshold
+	}
+	m.healthy.Store(true)
+
+	go m.loop()
+	return m
+}
+
+// Healthy 返回当前的健康状态
+func (m *HealthMonitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Close 停止周期检查，等待进行中的检查和 OnChange 返回
+func (m *HealthMonitor) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stopCh)
+		<-m.doneCh
+	})
+}
+
+// loop 周期执行就绪检查
+func (m *HealthMonitor) loop() {
+	defer close(m.doneCh)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithCancel(context.Background())
+			// 关闭时取消进行中的检查
+			go func() {
+				select {
+				case <-m.stopCh:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			m.observe(ctx, m.checker.CheckReadiness(ctx))
+			cancel()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// observe 记录一次检查结果，达到阈值时切换状态并调用 OnChange
+func (m *HealthMonitor) observe(ctx context.Context, response HealthResponse) {
+	select {
+	case <-m.stopCh:
+		return
+	default:
+	}
+
+	if response.Status == HealthStatusUnhealthy {
+		m.successes = 0
+		m.failures++
+		if m.healthy.Load() && m.failures >= m.config.FailureThreshold {
+			m.change(ctx, false, response)
+		}
+		return
+	}
+
+	m.failures = 0
+	m.successes++
+	if !m.healthy.Load() && m.successes >= m.config.SuccessThreshold {
+		m.change(ctx, true, response)
+	}
+}
+
+// change 切换健康状态
+func (m *HealthMonitor) change(ctx context.Context, healthy bool, response HealthResponse) {
+	m.healthy.Store(healthy)
+	if m.config.OnChange != nil {
+		m.config.OnChange(ctx, healthy, response)
+	}
+}
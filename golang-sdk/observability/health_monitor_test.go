@@ -0,0 +1,84 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	var failing atomic.Bool
+	checker := NewHealthChecker("test-service")
+	checker.RegisterCheck(NewSimpleHealthCheck("db", func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+
+	changes := make(chan bool, 4)
+	monitor := NewHealthMonitor(checker, &HealthMonitorConfig{
+		Interval:         10 * time.Millisecond,
+		FailureThreshold: 2,
+		OnChange: func(ctx context.Context, healthy bool, response HealthResponse) {
+			if !healthy && response.Checks["db"].Message != "connection refused" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+			changes <- healthy
+		},
+	})
+	defer monitor.Close()
+
+	expect := func(want bool) {
+		t.Helper()
+		select {
+		case healthy := <-changes:
+			if healthy != want || monitor.Healthy() != want {
+				t.Fatalf("healthy = %v, want %v", healthy, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for healthy = %v", want)
+		}
+	}
+
+	failing.Store(true)
+	expect(false)
+	failing.Store(false)
+	expect(true)
+
+	// 状态不变时不通知
+	select {
+	case healthy := <-changes:
+		t.Errorf("Unexpected change to %v", healthy)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHealthMonitorThreshold(t *testing.T) {
+	var changes []bool
+	m := &HealthMonitor{
+		config: HealthMonitorConfig{
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+			OnChange: func(ctx context.Context, healthy bool, response HealthResponse) {
+				changes = append(changes, healthy)
+			},
+		},
+		stopCh: make(chan struct{}),
+	}
+	m.healthy.Store(true)
+
+	// 失败未连续达到阈值，degraded 视为健康
+	for _, status := range []HealthStatus{
+		HealthStatusUnhealthy, HealthStatusUnhealthy, HealthStatusDegraded,
+		HealthStatusUnhealthy, HealthStatusUnhealthy, HealthStatusUnhealthy,
+		HealthStatusHealthy, HealthStatusUnhealthy, HealthStatusHealthy, HealthStatusHealthy,
+	} {
+		m.observe(context.Background(), HealthResponse{Status: status})
+	}
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Errorf("changes = %v, want [false true]", changes)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestHealthChecker(t *testing.T) {
@@ -151,3 +153,108 @@ func TestSimpleHealthCheck(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 }
+
+func TestHealthCheckerLivenessAndReadiness(t *testing.T) {
+	checker := NewHealthChecker("test-service")
+
+	checker.RegisterLivenessCheck(NewSimpleHealthCheck("process", func(ctx context.Context) error {
+		return nil
+	}))
+	checker.RegisterCheck(NewSimpleHealthCheck("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	// 就绪检查失败不影响存活检查
+	live := checker.CheckLiveness(context.Background())
+	if live.Status != HealthStatusHealthy || len(live.Checks) != 1 {
+		t.Errorf("Expected healthy liveness with 1 check, got %s with %d checks", live.Status, len(live.Checks))
+	}
+
+	ready := checker.CheckReadiness(context.Background())
+	if ready.Status != HealthStatusUnhealthy || len(ready.Checks) != 1 {
+		t.Errorf("Expected unhealthy readiness with 1 check, got %s with %d checks", ready.Status, len(ready.Checks))
+	}
+
+	// 汇总检查包含全部检查
+	if all := checker.Check(context.Background()); len(all.Checks) != 2 {
+		t.Errorf("Expected 2 checks, got %d", len(all.Checks))
+	}
+
+	w := httptest.NewRecorder()
+	checker.LivenessHandler()(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected liveness status code 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	checker.ReadinessHandler()(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness status code 503, got %d", w.Code)
+	}
+}
+
+func TestHealthCheckerDegraded(t *testing.T) {
+	checker := NewHealthChecker("test-service")
+
+	checker.RegisterCheck(NewSimpleHealthCheck("database", func(ctx context.Context) error {
+		return nil
+	}))
+	checker.RegisterCheckWithOptions(NewSimpleHealthCheck("cache", func(ctx context.Context) error {
+		return errors.New("cache unavailable")
+	}), CheckOptions{Kind: CheckKindReadiness, Critical: false})
+
+	response := checker.CheckReadiness(context.Background())
+	if response.Status != HealthStatusDegraded {
+		t.Errorf("Expected status degraded, got %s", response.Status)
+	}
+	if response.Checks["cache"].Status != HealthStatusUnhealthy {
+		t.Errorf("Expected cache check unhealthy, got %s", response.Checks["cache"].Status)
+	}
+
+	// 降级状态仍可接收流量
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler()(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code 200 for degraded, got %d", w.Code)
+	}
+}
+
+func TestHealthCheckerTimeout(t *testing.T) {
+	checker := NewHealthChecker("test-service")
+
+	checker.RegisterCheckWithOptions(NewSimpleHealthCheck("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}), CheckOptions{Critical: true, Timeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	response := checker.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected check to time out quickly, took %v", elapsed)
+	}
+	if response.Status != HealthStatusUnhealthy {
+		t.Errorf("Expected status unhealthy, got %s", response.Status)
+	}
+}
+
+func TestHealthCheckerCache(t *testing.T) {
+	checker := NewHealthChecker("test-service")
+
+	var calls int32
+	checker.RegisterCheckWithOptions(NewSimpleHealthCheck("cached", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}), CheckOptions{Critical: true, CacheTTL: 100 * time.Millisecond})
+
+	checker.Check(context.Background())
+	checker.Check(context.Background())
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 call within TTL, got %d", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	checker.Check(context.Background())
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 calls after TTL expiry, got %d", got)
+	}
+}
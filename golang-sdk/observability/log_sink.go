@@ -0,0 +1,232 @@
+package observability
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 日志格式
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// 日志输出
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+	LogOutputFile   = "file"
+	LogOutputSyslog = "syslog"
+)
+
+// 文件输出默认值
+const (
+	DefaultLogMaxSizeMB  = 100
+	DefaultLogMaxBackups = 5
+)
+
+// LoggerConfig 日志配置，对应 framework.observability.logging
+type LoggerConfig struct {
+	Level  LogLevel
+	Format string // text 或 json
+	Output string // stdout、stderr、file 或 syslog
+
+	// 文件输出
+	FilePath   string
+	MaxSizeMB  int // 单个文件最大大小(MB)，超出后轮转
+	MaxBackups int // 保留的历史文件数量
+
+	// syslog 输出，Network 为空时连接本地 syslog
+	SyslogNetwork string
+	SyslogAddress string
+
+	// Sinks 额外的输出目标，与 Output 同时写入
+	Sinks []io.Writer
+}
+
+// NewLogSink 根据配置创建日志输出目标
+func NewLogSink(serviceName string, config *LoggerConfig) (io.Writer, error) {
+	if config == nil {
+		return nil, fmt.Errorf("logger config cannot be nil")
+	}
+
+	var sink io.Writer
+	switch config.Output {
+	case LogOutputStdout, "":
+		sink = os.Stdout
+	case LogOutputStderr:
+		sink = os.Stderr
+	case LogOutputFile:
+		writer, err := NewRotatingFileWriter(config.FilePath, config.MaxSizeMB, config.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		sink = writer
+	case LogOutputSyslog:
+		writer, err := newSyslogWriter(config.SyslogNetwork, config.SyslogAddress, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		sink = writer
+	default:
+		return nil, fmt.Errorf("unsupported log output: %s", config.Output)
+	}
+
+	if len(config.Sinks) == 0 {
+		return sink, nil
+	}
+
+	return &multiSink{writers: append([]io.Writer{sink}, config.Sinks...)}, nil
+}
+
+// multiSink 同时写入多个输出目标
+type multiSink struct {
+	writers []io.Writer
+}
+
+// Write 写入所有输出目标，单个目标失败不影响其他目标
+func (m *multiSink) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, w := range m.writers {
+		if _, err := w.Write(p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(p), firstErr
+}
+
+// Close 关闭所有可关闭的输出目标
+func (m *multiSink) Close() error {
+	var firstErr error
+	for _, w := range m.writers {
+		if closer, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// RotatingFileWriter 按大小轮转的日志文件
+//
+// 当前文件超出大小限制时依次重命名为 <path>.1、<path>.2 ...，超出保留数量的历史文件被删除
+type RotatingFileWriter struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mu         sync.Mutex
+}
+
+// NewRotatingFileWriter 创建轮转日志文件
+func NewRotatingFileWriter(path string, maxSizeMB, maxBackups int) (*RotatingFileWriter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("log file path cannot be empty")
+	}
+
+	if maxSizeMB <= 0 {
+		maxSizeMB = DefaultLogMaxSizeMB
+	}
+
+	if maxBackups <= 0 {
+		maxBackups = DefaultLogMaxBackups
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	w := &RotatingFileWriter{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write 写入日志，必要时先轮转文件
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, fmt.Errorf("log file is closed")
+	}
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close 关闭日志文件
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open 打开或创建当前日志文件
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate 轮转日志文件
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	w.file = nil
+
+	// 删除最旧的备份，其余备份依次后移
+	os.Remove(w.backupName(w.maxBackups))
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		os.Rename(w.backupName(i), w.backupName(i+1))
+	}
+
+	if err := os.Rename(w.path, w.backupName(1)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	return w.open()
+}
+
+// backupName 返回第 n 个备份文件名
+func (w *RotatingFileWriter) backupName(n int) string {
+	return fmt.Sprintf("%s.%d", w.path, n)
+}
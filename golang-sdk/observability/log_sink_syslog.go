@@ -0,0 +1,18 @@
+//go:build !windows && !plan9
+
+package observability
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+)
+
+// newSyslogWriter 连接 syslog，network 为空时使用本地 syslog
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return writer, nil
+}
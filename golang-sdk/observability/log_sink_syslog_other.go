@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package observability
+
+import (
+	"fmt"
+	"io"
+)
+
+// newSyslogWriter 当前平台不支持 syslog
+func newSyslogWriter(network, address, tag string) (io.Writer, error) {
+	return nil, fmt.Errorf("syslog output is not supported on this platform")
+}
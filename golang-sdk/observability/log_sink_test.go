@@ -0,0 +1,110 @@
+package observability
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "service.log")
+
+	writer, err := NewRotatingFileWriter(path, 1, 2)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	defer writer.Close()
+
+	// 使用较小的大小限制便于测试
+	writer.maxSize = 10
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := writer.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "fourth\n" {
+		t.Errorf("Expected current file 'fourth', got %q", current)
+	}
+
+	backup1, _ := os.ReadFile(path + ".1")
+	if string(backup1) != "third\n" {
+		t.Errorf("Expected backup 1 'third', got %q", backup1)
+	}
+
+	backup2, _ := os.ReadFile(path + ".2")
+	if string(backup2) != "second\n" {
+		t.Errorf("Expected backup 2 'second', got %q", backup2)
+	}
+
+	// 超出保留数量的备份被删除
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected backup 3 to be removed")
+	}
+}
+
+func TestRotatingFileWriterClosed(t *testing.T) {
+	writer, err := NewRotatingFileWriter(filepath.Join(t.TempDir(), "service.log"), 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+
+	if writer.maxSize != DefaultLogMaxSizeMB*1024*1024 || writer.maxBackups != DefaultLogMaxBackups {
+		t.Errorf("Expected default limits, got %d/%d", writer.maxSize, writer.maxBackups)
+	}
+
+	writer.Close()
+	if _, err := writer.Write([]byte("late\n")); err == nil {
+		t.Error("Expected error writing to closed writer")
+	}
+}
+
+func TestNewLogSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *LoggerConfig
+		wantErr bool
+	}{
+		{name: "nil config", config: nil, wantErr: true},
+		{name: "default stdout", config: &LoggerConfig{}, wantErr: false},
+		{name: "stderr", config: &LoggerConfig{Output: LogOutputStderr}, wantErr: false},
+		{name: "file without path", config: &LoggerConfig{Output: LogOutputFile}, wantErr: true},
+		{name: "unsupported output", config: &LoggerConfig{Output: "kafka"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLogSink("test-service", tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLogSink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewLogSinkWithExtraSinks(t *testing.T) {
+	var extra bytes.Buffer
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	sink, err := NewLogSink("test-service", &LoggerConfig{
+		Output:   LogOutputFile,
+		FilePath: path,
+		Sinks:    []io.Writer{&extra},
+	})
+	if err != nil {
+		t.Fatalf("NewLogSink() error = %v", err)
+	}
+
+	sink.Write([]byte("hello\n"))
+	sink.(io.Closer).Close()
+
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "hello") || !strings.Contains(extra.String(), "hello") {
+		t.Errorf("Expected both sinks to receive log, file=%q extra=%q", content, extra.String())
+	}
+}
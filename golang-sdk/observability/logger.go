@@ -2,9 +2,14 @@ package observability
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/gogf/gf/v2/os/glog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Logger 日志记录器接口
@@ -32,10 +37,35 @@ type Field struct {
 	Value interface{}
 }
 
+// levelPriority 日志级别优先级
+var levelPriority = map[LogLevel]int{
+	LogLevelDebug: 0,
+	LogLevelInfo:  1,
+	LogLevelWarn:  2,
+	LogLevelError: 3,
+}
+
+// requestIDKey 请求 ID 的 context 键
+type requestIDKey struct{}
+
+// legacyRequestIDKey 旧版本使用的字符串 context 键，仅为兼容保留
+const legacyRequestIDKey = "request_id"
+
+// WithRequestID 将请求 ID 写入 context，日志自动附带该字段
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
 // FrameworkLogger 基于 GoFrame glog 的日志记录器
+//
+// trace_id、span_id 取自 ctx 中的 OpenTelemetry span，调用方无需手动传入。
+// text 格式由 glog 输出，只附带 ctx 中存在的关联字段；json 格式每条日志输出一行 JSON，始终包含 trace_id、span_id、request_id 字段
 type FrameworkLogger struct {
 	logger      *glog.Logger
 	serviceName string
+	format      string
+	level       LogLevel
+	sink        io.Writer
 }
 
 // NewLogger 创建新的日志记录器
@@ -45,31 +75,76 @@ func NewLogger(serviceName string) Logger {
 	return &FrameworkLogger{
 		logger:      logger,
 		serviceName: serviceName,
+		format:      LogFormatText,
+		level:       LogLevelDebug,
+	}
+}
+
+// NewLoggerWithConfig 根据配置创建日志记录器
+func NewLoggerWithConfig(serviceName string, config *LoggerConfig) (Logger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("logger config cannot be nil")
+	}
+
+	format := config.Format
+	if format == "" {
+		format = LogFormatText
 	}
+	if format != LogFormatText && format != LogFormatJSON {
+		return nil, fmt.Errorf("unsupported log format: %s", config.Format)
+	}
+
+	sink, err := NewLogSink(serviceName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	l := NewLogger(serviceName).(*FrameworkLogger)
+	l.format = format
+	l.sink = sink
+	l.logger.SetWriter(sink)
+
+	if config.Level != "" {
+		l.SetLevel(config.Level)
+	}
+
+	return l, nil
 }
 
 // Debug 记录调试级别日志
 func (l *FrameworkLogger) Debug(ctx context.Context, msg string, fields ...Field) {
-	l.logWithFields(ctx, l.logger.Debug, msg, fields...)
+	l.logWithFields(ctx, LogLevelDebug, l.logger.Debug, msg, fields...)
 }
 
 // Info 记录信息级别日志
 func (l *FrameworkLogger) Info(ctx context.Context, msg string, fields ...Field) {
-	l.logWithFields(ctx, l.logger.Info, msg, fields...)
+	l.logWithFields(ctx, LogLevelInfo, l.logger.Info, msg, fields...)
 }
 
 // Warn 记录警告级别日志
 func (l *FrameworkLogger) Warn(ctx context.Context, msg string, fields ...Field) {
-	l.logWithFields(ctx, l.logger.Warning, msg, fields...)
+	l.logWithFields(ctx, LogLevelWarn, l.logger.Warning, msg, fields...)
 }
 
 // Error 记录错误级别日志
 func (l *FrameworkLogger) Error(ctx context.Context, msg string, fields ...Field) {
-	l.logWithFields(ctx, l.logger.Error, msg, fields...)
+	l.logWithFields(ctx, LogLevelError, l.logger.Error, msg, fields...)
+}
+
+// Close 关闭日志输出目标（文件、syslog 等），标准输出和标准错误不关闭
+func (l *FrameworkLogger) Close() error {
+	if closer, ok := l.sink.(io.Closer); ok && l.sink != os.Stdout && l.sink != os.Stderr {
+		return closer.Close()
+	}
+	return nil
 }
 
 // SetLevel 设置日志级别
 func (l *FrameworkLogger) SetLevel(level LogLevel) {
+	if _, ok := levelPriority[level]; ok {
+		l.level = level
+	}
+
 	switch level {
 	case LogLevelDebug:
 		l.logger.SetLevel(glog.LEVEL_ALL)
@@ -83,14 +158,21 @@ func (l *FrameworkLogger) SetLevel(level LogLevel) {
 }
 
 // logWithFields 记录带字段的日志
-func (l *FrameworkLogger) logWithFields(ctx context.Context, logFunc func(ctx context.Context, v ...interface{}), msg string, fields ...Field) {
-	// 提取上下文信息
-	requestID := extractRequestID(ctx)
-	timestamp := extractTimestamp(ctx)
+func (l *FrameworkLogger) logWithFields(ctx context.Context, level LogLevel, logFunc func(ctx context.Context, v ...interface{}), msg string, fields ...Field) {
+	if l.format == LogFormatJSON {
+		l.logJSON(ctx, level, msg, fields...)
+		return
+	}
 
-	// 构建日志消息
-	logMsg := fmt.Sprintf("[RequestID: %s] [Timestamp: %s] [Service: %s] %s",
-		requestID, timestamp, l.serviceName, msg)
+	// 构建日志消息，附带 ctx 中的追踪和请求关联字段
+	logMsg := fmt.Sprintf("[Service: %s]", l.serviceName)
+	if traceID, spanID := extractTraceIDs(ctx); traceID != "" {
+		logMsg += fmt.Sprintf(" [TraceID: %s] [SpanID: %s]", traceID, spanID)
+	}
+	if requestID := extractRequestID(ctx); requestID != "" {
+		logMsg += fmt.Sprintf(" [RequestID: %s]", requestID)
+	}
+	logMsg += " " + msg
 
 	// 添加字段
 	if len(fields) > 0 {
@@ -103,24 +185,79 @@ func (l *FrameworkLogger) logWithFields(ctx context.Context, logFunc func(ctx co
 	logFunc(ctx, logMsg)
 }
 
-// extractRequestID 从上下文提取请求ID
-func extractRequestID(ctx context.Context) string {
+// logJSON 以单行 JSON 输出日志
+func (l *FrameworkLogger) logJSON(ctx context.Context, level LogLevel, msg string, fields ...Field) {
+	if levelPriority[level] < levelPriority[l.level] {
+		return
+	}
+
+	traceID, spanID := extractTraceIDs(ctx)
+	entry := map[string]interface{}{
+		"time":       time.Now().Format(time.RFC3339Nano),
+		"level":      string(level),
+		"service":    l.serviceName,
+		"msg":        msg,
+		"trace_id":   traceID,
+		"span_id":    spanID,
+		"request_id": extractRequestID(ctx),
+	}
+
+	for _, field := range fields {
+		key := field.Key
+		// 不覆盖保留字段
+		if _, reserved := entry[key]; reserved {
+			key = "field." + key
+		}
+		if err, ok := field.Value.(error); ok {
+			entry[key] = err.Error()
+		} else {
+			entry[key] = field.Value
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{
+			"time":       entry["time"],
+			"level":      entry["level"],
+			"service":    l.serviceName,
+			"msg":        msg,
+			"trace_id":   traceID,
+			"span_id":    spanID,
+			"request_id": entry["request_id"],
+			"error":      fmt.Sprintf("failed to marshal log fields: %v", err),
+		})
+	}
+
+	sink := l.sink
+	if sink == nil {
+		sink = os.Stdout
+	}
+	sink.Write(append(data, '\n'))
+}
+
+// extractTraceIDs 从上下文提取 trace ID 和 span ID
+func extractTraceIDs(ctx context.Context) (string, string) {
 	if ctx == nil {
-		return "unknown"
+		return "", ""
 	}
-	if requestID := ctx.Value("request_id"); requestID != nil {
-		return fmt.Sprintf("%v", requestID)
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return "", ""
 	}
-	return "unknown"
+	return spanCtx.TraceID().String(), spanCtx.SpanID().String()
 }
 
-// extractTimestamp 从上下文提取时间戳
-func extractTimestamp(ctx context.Context) string {
+// extractRequestID 从上下文提取请求 ID，未设置时返回空字符串
+func extractRequestID(ctx context.Context) string {
 	if ctx == nil {
-		return "unknown"
+		return ""
 	}
-	if timestamp := ctx.Value("timestamp"); timestamp != nil {
-		return fmt.Sprintf("%v", timestamp)
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	if requestID := ctx.Value(legacyRequestIDKey); requestID != nil {
+		return fmt.Sprintf("%v", requestID)
 	}
-	return "unknown"
+	return ""
 }
@@ -1,15 +1,21 @@
 package observability
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestLogger(t *testing.T) {
 	logger := NewLogger("test-service")
 
-	ctx := context.WithValue(context.Background(), "request_id", "req-123")
-	ctx = context.WithValue(ctx, "timestamp", "2024-01-01T12:00:00Z")
+	ctx := WithRequestID(context.Background(), "req-123")
 
 	// 测试不同级别的日志
 	logger.Debug(ctx, "Debug message", Field{Key: "key1", Value: "value1"})
@@ -53,3 +59,150 @@ func TestLoggerWithFields(t *testing.T) {
 		Field{Key: "field3", Value: true},
 	)
 }
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLoggerWithConfig("test-service", &LoggerConfig{
+		Level:  LogLevelInfo,
+		Format: LogFormatJSON,
+		Sinks:  []io.Writer{&buf},
+		Output: LogOutputStderr,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	ctx = WithRequestID(ctx, "req-123")
+
+	// 低于日志级别的日志被过滤
+	logger.Debug(ctx, "Debug message")
+	logger.Info(ctx, "Info message",
+		Field{Key: "user", Value: "alice"},
+		Field{Key: "msg", Value: "shadowed"},
+		Field{Key: "error", Value: errors.New("boom")},
+	)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 log line, got %d: %s", len(lines), buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Log line is not valid JSON: %v", err)
+	}
+
+	expected := map[string]string{
+		"level":      "info",
+		"service":    "test-service",
+		"msg":        "Info message",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":    "00f067aa0ba902b7",
+		"request_id": "req-123",
+		"user":       "alice",
+		"field.msg":  "shadowed",
+		"error":      "boom",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Errorf("Expected %s=%s, got %v", key, want, entry[key])
+		}
+	}
+}
+
+func TestJSONLoggerWithoutTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLoggerWithConfig("test-service", &LoggerConfig{
+		Format: LogFormatJSON,
+		Output: LogOutputStderr,
+		Sinks:  []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	logger.Info(nil, "Message without context")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Log line is not valid JSON: %v", err)
+	}
+
+	// 追踪字段始终存在
+	for _, key := range []string{"trace_id", "span_id", "request_id"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("Expected field %s to be present", key)
+		}
+	}
+}
+
+func TestTextLoggerTraceCorrelation(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLoggerWithConfig("test-service", &LoggerConfig{
+		Format: LogFormatText,
+		Output: LogOutputStderr,
+		Sinks:  []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	// 调用方只传入带 span 的 ctx，日志自动附带追踪字段
+	logger.Info(ctx, "Traced message")
+	logger.Info(context.Background(), "Untraced message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "[TraceID: 4bf92f3577b34da6a3ce929d0e0e4736] [SpanID: 00f067aa0ba902b7]") {
+		t.Errorf("Expected trace fields in traced log line: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "TraceID") {
+		t.Errorf("Expected no trace fields in untraced log line: %s", lines[1])
+	}
+}
+
+func TestExtractRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "nil context", ctx: nil, want: ""},
+		{name: "not set", ctx: context.Background(), want: ""},
+		{name: "typed key", ctx: WithRequestID(context.Background(), "req-1"), want: "req-1"},
+		{name: "legacy string key", ctx: context.WithValue(context.Background(), legacyRequestIDKey, "req-2"), want: "req-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractRequestID(tt.ctx); got != tt.want {
+				t.Errorf("extractRequestID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLoggerWithConfigInvalid(t *testing.T) {
+	if _, err := NewLoggerWithConfig("test-service", nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+
+	if _, err := NewLoggerWithConfig("test-service", &LoggerConfig{Format: "xml"}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -1,26 +1,63 @@
 package observability
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gogf/gf/v2/os/glog"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
 	// 用于防止重复注册的锁
 	metricsOnce sync.Once
 	// 全局指标实例
-	globalRequestDuration   *prometheus.HistogramVec
-	globalRequestTotal      *prometheus.CounterVec
-	globalErrorTotal        *prometheus.CounterVec
-	globalThroughput        *prometheus.CounterVec
-	globalActiveConnections prometheus.Gauge
+	globalMetrics *metricVectors
+	// 注册全局指标的错误
+	globalMetricsErr error
 )
 
+// RequestStatusSuccess 成功请求的状态标签，其他状态在 SLO 统计中视为失败
+const RequestStatusSuccess = "success"
+
+// 请求指标的完整标签
+var (
+	requestDurationLabels = []string{"service", "method", "protocol"}
+	requestTotalLabels    = []string{"service", "method", "protocol", "status"}
+	errorTotalLabels      = []string{"service", "method", "error_code"}
+	throughputLabels      = []string{"service", "direction"} // direction: in/out
+)
+
+// MetricsConfig 指标配置，对应 framework.observability.metrics
+type MetricsConfig struct {
+	// DurationBuckets 请求延迟直方图的桶边界（秒），须严格递增，默认 prometheus.DefBuckets
+	DurationBuckets []float64
+	// Labels 保留的标签白名单，为空时保留全部标签
+	//
+	// 可选 service、method、protocol、status、error_code、direction；
+	// 未列出的标签从请求、错误和吞吐量指标中去除，用于控制 method 等高基数标签
+	Labels []string
+	// Registerer 指标注册器，为空时注册到全局注册器
+	//
+	// 全局注册器上的指标只创建一次，之后以不同的桶或标签创建收集器时返回错误；
+	// 同一注册器上已注册的指标被复用，此时桶边界以首次注册的为准
+	Registerer prometheus.Registerer
+}
+
 // MetricsCollector 指标收集器
 type MetricsCollector struct {
+	*metricVectors
+	// SLO 跟踪器，设置后请求结果同时计入 SLO 统计
+	sloTracker atomic.Pointer[SLOTracker]
+}
+
+// metricVectors 已注册的指标及其标签选择
+type metricVectors struct {
 	// 请求延迟直方图
 	requestDuration *prometheus.HistogramVec
 	// 请求计数器
@@ -31,72 +68,262 @@ type MetricsCollector struct {
 	throughput *prometheus.CounterVec
 	// 活跃连接数
 	activeConnections prometheus.Gauge
+
+	requestDurationLabels labelSelector
+	requestTotalLabels    labelSelector
+	errorTotalLabels      labelSelector
+	throughputLabels      labelSelector
+
+	// 延迟直方图的桶边界
+	buckets []float64
 }
 
-// NewMetricsCollector 创建新的指标收集器
+// NewMetricsCollector 创建新的指标收集器，全局注册器上已有同名但不兼容的指标时 panic
 func NewMetricsCollector(serviceName string) *MetricsCollector {
+	v, err := globalMetricVectors(prometheus.DefBuckets, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &MetricsCollector{metricVectors: v}
+}
+
+// globalMetricVectors 返回注册到全局注册器的指标，首次调用时按 buckets 和 allowed 创建
+func globalMetricVectors(buckets []float64, allowed map[string]bool) (*metricVectors, error) {
 	// 使用 sync.Once 确保指标只注册一次
 	metricsOnce.Do(func() {
-		globalRequestDuration = promauto.NewHistogramVec(
-			prometheus.HistogramOpts{
-				Name:    "framework_request_duration_seconds",
-				Help:    "Request duration in seconds",
-				Buckets: prometheus.DefBuckets,
-			},
-			[]string{"service", "method", "protocol"},
-		)
-		globalRequestTotal = promauto.NewCounterVec(
-			prometheus.CounterOpts{
-				Name: "framework_request_total",
-				Help: "Total number of requests",
-			},
-			[]string{"service", "method", "protocol", "status"},
-		)
-		globalErrorTotal = promauto.NewCounterVec(
-			prometheus.CounterOpts{
-				Name: "framework_error_total",
-				Help: "Total number of errors",
-			},
-			[]string{"service", "method", "error_code"},
-		)
-		globalThroughput = promauto.NewCounterVec(
-			prometheus.CounterOpts{
-				Name: "framework_throughput_bytes_total",
-				Help: "Total throughput in bytes",
-			},
-			[]string{"service", "direction"}, // direction: in/out
-		)
-		globalActiveConnections = promauto.NewGauge(
-			prometheus.GaugeOpts{
-				Name: "framework_active_connections",
-				Help: "Number of active connections",
-			},
-		)
+		globalMetrics, globalMetricsErr = registerMetricVectors(prometheus.DefaultRegisterer, buckets, allowed)
 	})
+	if globalMetricsErr != nil {
+		return nil, globalMetricsErr
+	}
+	if !globalMetrics.matches(buckets, allowed) {
+		return nil, fmt.Errorf("metrics already registered on the default registerer with buckets %v and labels %v, "+
+			"set MetricsConfig.Registerer to use a different config", globalMetrics.buckets, globalMetrics.labelNames())
+	}
+	return globalMetrics, nil
+}
 
-	return &MetricsCollector{
-		requestDuration:   globalRequestDuration,
-		requestTotal:      globalRequestTotal,
-		errorTotal:        globalErrorTotal,
-		throughput:        globalThroughput,
-		activeConnections: globalActiveConnections,
+// NewMetricsCollectorWithConfig 根据配置创建指标收集器
+func NewMetricsCollectorWithConfig(serviceName string, config *MetricsConfig) (*MetricsCollector, error) {
+	if config == nil {
+		return nil, fmt.Errorf("metrics config cannot be nil")
 	}
+
+	buckets := config.DurationBuckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return nil, fmt.Errorf("duration buckets must be strictly increasing: %v", buckets)
+		}
+	}
+
+	var allowed map[string]bool
+	if len(config.Labels) > 0 {
+		allowed = make(map[string]bool, len(config.Labels))
+		for _, label := range config.Labels {
+			if !isMetricLabel(label) {
+				return nil, fmt.Errorf("unknown metric label: %s", label)
+			}
+			allowed[label] = true
+		}
+	}
+
+	var (
+		v   *metricVectors
+		err error
+	)
+	if config.Registerer != nil {
+		v, err = registerMetricVectors(config.Registerer, buckets, allowed)
+	} else {
+		v, err = globalMetricVectors(buckets, allowed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsCollector{metricVectors: v}, nil
+}
+
+// registerMetricVectors 创建并注册指标，allowed 为空时保留全部标签；注册器上已有的同名指标被复用
+func registerMetricVectors(registerer prometheus.Registerer, buckets []float64, allowed map[string]bool) (*metricVectors, error) {
+	v := &metricVectors{
+		buckets:               buckets,
+		requestDurationLabels: newLabelSelector(requestDurationLabels, allowed),
+		requestTotalLabels:    newLabelSelector(requestTotalLabels, allowed),
+		errorTotalLabels:      newLabelSelector(errorTotalLabels, allowed),
+		throughputLabels:      newLabelSelector(throughputLabels, allowed),
+	}
+
+	v.requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "framework_request_duration_seconds",
+			Help:    "Request duration in seconds",
+			Buckets: buckets,
+		},
+		v.requestDurationLabels.names,
+	)
+	v.requestTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "framework_request_total",
+			Help: "Total number of requests",
+		},
+		v.requestTotalLabels.names,
+	)
+	v.errorTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "framework_error_total",
+			Help: "Total number of errors",
+		},
+		v.errorTotalLabels.names,
+	)
+	v.throughput = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "framework_throughput_bytes_total",
+			Help: "Total throughput in bytes",
+		},
+		v.throughputLabels.names,
+	)
+	v.activeConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "framework_active_connections",
+			Help: "Number of active connections",
+		},
+	)
+
+	if err := registerOrReuse(registerer, &v.requestDuration); err != nil {
+		return nil, err
+	}
+	if err := registerOrReuse(registerer, &v.requestTotal); err != nil {
+		return nil, err
+	}
+	if err := registerOrReuse(registerer, &v.errorTotal); err != nil {
+		return nil, err
+	}
+	if err := registerOrReuse(registerer, &v.throughput); err != nil {
+		return nil, err
+	}
+	if err := registerOrReuse(registerer, &v.activeConnections); err != nil {
+		return nil, err
+	}
+
+	// 运行时指标注册失败不影响业务指标
+	if err := registerRuntimeCollectors(registerer); err != nil {
+		glog.Warningf(context.Background(), "Failed to register runtime metrics: %v", err)
+	}
+
+	return v, nil
+}
+
+// registerOrReuse 注册指标，注册器上已有同名同标签的指标时将 *collector 替换为已注册的指标
+func registerOrReuse[T prometheus.Collector](registerer prometheus.Registerer, collector *T) error {
+	err := registerer.Register(*collector)
+	if err == nil {
+		return nil
+	}
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if !errors.As(err, &alreadyRegistered) {
+		return fmt.Errorf("failed to register metrics: %w", err)
+	}
+	existing, ok := alreadyRegistered.ExistingCollector.(T)
+	if !ok {
+		return fmt.Errorf("failed to register metrics: existing collector has type %T", alreadyRegistered.ExistingCollector)
+	}
+	*collector = existing
+	return nil
+}
+
+// matches 指标是否按 buckets 和 allowed 创建
+func (v *metricVectors) matches(buckets []float64, allowed map[string]bool) bool {
+	return slices.Equal(v.buckets, buckets) &&
+		slices.Equal(v.requestDurationLabels.names, newLabelSelector(requestDurationLabels, allowed).names) &&
+		slices.Equal(v.requestTotalLabels.names, newLabelSelector(requestTotalLabels, allowed).names) &&
+		slices.Equal(v.errorTotalLabels.names, newLabelSelector(errorTotalLabels, allowed).names) &&
+		slices.Equal(v.throughputLabels.names, newLabelSelector(throughputLabels, allowed).names)
+}
+
+// labelNames 返回指标保留的全部标签
+func (v *metricVectors) labelNames() []string {
+	var names []string
+	for _, selector := range []labelSelector{v.requestDurationLabels, v.requestTotalLabels, v.errorTotalLabels, v.throughputLabels} {
+		for _, name := range selector.names {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// labelSelector 按白名单从完整标签中选择保留的标签
+type labelSelector struct {
+	names []string // 保留的标签名
+	index []int    // 保留的标签在完整标签中的位置
+}
+
+// newLabelSelector 创建标签选择器，allowed 为空时保留全部标签
+func newLabelSelector(all []string, allowed map[string]bool) labelSelector {
+	s := labelSelector{
+		names: make([]string, 0, len(all)),
+		index: make([]int, 0, len(all)),
+	}
+	for i, name := range all {
+		if allowed == nil || allowed[name] {
+			s.names = append(s.names, name)
+			s.index = append(s.index, i)
+		}
+	}
+	return s
+}
+
+// values 返回保留标签对应的值
+func (s labelSelector) values(values ...string) []string {
+	if len(s.index) == len(values) {
+		return values
+	}
+
+	selected := make([]string, len(s.index))
+	for i, idx := range s.index {
+		selected[i] = values[idx]
+	}
+	return selected
+}
+
+// isMetricLabel 检查是否为可配置的指标标签
+func isMetricLabel(label string) bool {
+	for _, labels := range [][]string{requestTotalLabels, errorTotalLabels, throughputLabels} {
+		for _, name := range labels {
+			if name == label {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // RecordRequest 记录请求指标
 func (m *MetricsCollector) RecordRequest(service, method, protocol, status string, duration time.Duration) {
-	m.requestDuration.WithLabelValues(service, method, protocol).Observe(duration.Seconds())
-	m.requestTotal.WithLabelValues(service, method, protocol, status).Inc()
+	m.requestDuration.WithLabelValues(m.requestDurationLabels.values(service, method, protocol)...).Observe(duration.Seconds())
+	m.requestTotal.WithLabelValues(m.requestTotalLabels.values(service, method, protocol, status)...).Inc()
+
+	if tracker := m.sloTracker.Load(); tracker != nil {
+		tracker.Record(service, status == RequestStatusSuccess, duration)
+	}
+}
+
+// SetSLOTracker 设置 SLO 跟踪器，RecordRequest 记录的请求将计入 SLO 统计
+func (m *MetricsCollector) SetSLOTracker(tracker *SLOTracker) {
+	m.sloTracker.Store(tracker)
 }
 
 // RecordError 记录错误指标
 func (m *MetricsCollector) RecordError(service, method, errorCode string) {
-	m.errorTotal.WithLabelValues(service, method, errorCode).Inc()
+	m.errorTotal.WithLabelValues(m.errorTotalLabels.values(service, method, errorCode)...).Inc()
 }
 
 // RecordThroughput 记录吞吐量
 func (m *MetricsCollector) RecordThroughput(service, direction string, bytes int64) {
-	m.throughput.WithLabelValues(service, direction).Add(float64(bytes))
+	m.throughput.WithLabelValues(m.throughputLabels.values(service, direction)...).Add(float64(bytes))
 }
 
 // SetActiveConnections 设置活跃连接数
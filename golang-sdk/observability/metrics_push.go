@@ -0,0 +1,332 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// 指标推送方式
+const (
+	MetricsPushPushgateway = "pushgateway"
+	MetricsPushOTLP        = "otlp"
+)
+
+// 指标推送默认值
+const (
+	DefaultMetricsPushInterval = 15 * time.Second
+	DefaultMetricsPushTimeout  = 10 * time.Second
+	// metricsPushScope 转换后指标的 instrumentation scope
+	metricsPushScope = "github.com/framework/golang-sdk/observability"
+)
+
+// MetricsPushConfig 指标推送配置，用于无法被 Prometheus 拉取的环境
+//
+// 推送与 /metrics 拉取端点并存，推送内容与拉取端点一致
+type MetricsPushConfig struct {
+	Enabled bool
+	Mode    string // pushgateway 或 otlp
+	// Endpoint Pushgateway 地址（如 http://pushgateway:9091）或 OTLP 收集器地址
+	Endpoint string
+	// Exporter OTLP 协议 otlp-grpc 或 otlp-http，仅 otlp 模式使用
+	Exporter string
+	Headers  map[string]string // 附加请求头，仅 otlp 模式使用
+	Insecure bool              // 不使用 TLS，仅 otlp 模式使用
+	// Job Pushgateway 作业名，默认使用服务名
+	Job string
+	// Interval 推送周期
+	Interval time.Duration
+	// Timeout 单次推送超时
+	Timeout time.Duration
+	// Gatherer 指标来源，默认 prometheus.DefaultGatherer
+	Gatherer prometheus.Gatherer
+	// Resource otlp 模式下指标的资源属性，为 nil 时以 DetectResource 检测（只设置 service.name）
+	Resource *resource.Resource
+}
+
+// MetricsPusher 周期性推送 Prometheus 指标到 Pushgateway 或 OTLP 收集器
+type MetricsPusher struct {
+	push      func(ctx context.Context) error
+	shutdown  func(ctx context.Context) error
+	timeout   time.Duration
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMetricsPusher 创建指标推送器并开始周期推送
+func NewMetricsPusher(serviceName string, config *MetricsPushConfig) (*MetricsPusher, error) {
+	if config == nil {
+		return nil, fmt.Errorf("metrics push config cannot be nil")
+	}
+
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("metrics push endpoint cannot be empty")
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultMetricsPushInterval
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultMetricsPushTimeout
+	}
+
+	gatherer := config.Gatherer
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	p := &MetricsPusher{
+		timeout: timeout,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	switch config.Mode {
+	case MetricsPushPushgateway, "":
+		job := config.Job
+		if job == "" {
+			job = serviceName
+		}
+		if job == "" {
+			return nil, fmt.Errorf("pushgateway job cannot be empty")
+		}
+
+		pusher := push.New(config.Endpoint, job).
+			Gatherer(gatherer).
+			Grouping("instance", pushInstance()).
+			Client(&http.Client{Timeout: timeout})
+		p.push = pusher.PushContext
+		p.shutdown = pusher.PushContext
+	case MetricsPushOTLP:
+		endpoint, _, insecure, err := parseExporterEndpoint(config.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := newOTLPMetricExporter(context.Background(), config.Exporter, endpoint, config.Headers, insecure || config.Insecure)
+		if err != nil {
+			return nil, err
+		}
+
+		// 由读取器定时导出，推送循环只负责关闭
+		reader := sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(interval),
+			sdkmetric.WithTimeout(timeout),
+			sdkmetric.WithProducer(newPrometheusProducer(gatherer)),
+		)
+		res := config.Resource
+		if res == nil {
+			res = DetectResource(context.Background(), &ResourceConfig{ServiceName: serviceName})
+		}
+		meterProvider := sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+			sdkmetric.WithResource(res),
+		)
+		p.push = meterProvider.ForceFlush
+		p.shutdown = meterProvider.Shutdown
+		close(p.doneCh)
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unsupported metrics push mode: %s", config.Mode)
+	}
+
+	go p.pushLoop(interval)
+
+	return p, nil
+}
+
+// Push 立即推送一次指标
+func (p *MetricsPusher) Push(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.push(ctx)
+}
+
+// Close 停止周期推送，并在返回前推送最后一次指标
+func (p *MetricsPusher) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.stopCh)
+		<-p.doneCh
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		defer cancel()
+		err = p.shutdown(ctx)
+	})
+	return err
+}
+
+// pushLoop 周期推送指标
+func (p *MetricsPusher) pushLoop(interval time.Duration) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 推送失败在下一周期重试，期间的数据仍累积在计数器中
+			p.Push(context.Background())
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// pushInstance 返回 Pushgateway 分组使用的实例标识
+func pushInstance() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "unknown"
+	}
+	return hostname
+}
+
+// prometheusProducer 将 Prometheus 指标转换为 OpenTelemetry 指标
+//
+// 计数器转换为单调累计 Sum，仪表盘和未知类型转换为 Gauge，直方图转换为累计 Histogram；
+// 摘要（Summary）在 OpenTelemetry 中没有对应类型，不导出
+type prometheusProducer struct {
+	gatherer  prometheus.Gatherer
+	startTime time.Time
+}
+
+// newPrometheusProducer 创建 Prometheus 指标转换器
+func newPrometheusProducer(gatherer prometheus.Gatherer) *prometheusProducer {
+	return &prometheusProducer{
+		gatherer:  gatherer,
+		startTime: time.Now(),
+	}
+}
+
+// Produce 收集并转换 Prometheus 指标
+func (p *prometheusProducer) Produce(ctx context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, gatherErr := p.gatherer.Gather()
+	if len(families) == 0 {
+		return nil, gatherErr
+	}
+
+	now := time.Now()
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		if m, ok := p.convert(family, now); ok {
+			metrics = append(metrics, m)
+		}
+	}
+
+	// 部分采集器出错时仍导出其余指标
+	return []metricdata.ScopeMetrics{{
+		Scope:   instrumentation.Scope{Name: metricsPushScope},
+		Metrics: metrics,
+	}}, gatherErr
+}
+
+// convert 转换单个指标族
+func (p *prometheusProducer) convert(family *dto.MetricFamily, now time.Time) (metricdata.Metrics, bool) {
+	m := metricdata.Metrics{
+		Name:        family.GetName(),
+		Description: family.GetHelp(),
+	}
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			points = append(points, p.dataPoint(metric, metric.GetCounter().GetValue(), now))
+		}
+		m.Data = metricdata.Sum[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+	case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+		points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			value := metric.GetGauge().GetValue()
+			if family.GetType() == dto.MetricType_UNTYPED {
+				value = metric.GetUntyped().GetValue()
+			}
+			points = append(points, p.dataPoint(metric, value, now))
+		}
+		m.Data = metricdata.Gauge[float64]{DataPoints: points}
+	case dto.MetricType_HISTOGRAM:
+		points := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			points = append(points, p.histogramDataPoint(metric, now))
+		}
+		m.Data = metricdata.Histogram[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+		}
+	default:
+		return m, false
+	}
+
+	return m, true
+}
+
+// dataPoint 构造数值数据点
+func (p *prometheusProducer) dataPoint(metric *dto.Metric, value float64, now time.Time) metricdata.DataPoint[float64] {
+	return metricdata.DataPoint[float64]{
+		Attributes: labelAttributes(metric.GetLabel()),
+		StartTime:  p.startTime,
+		Time:       now,
+		Value:      value,
+	}
+}
+
+// histogramDataPoint 构造直方图数据点，Prometheus 的累计桶计数转换为逐桶计数
+func (p *prometheusProducer) histogramDataPoint(metric *dto.Metric, now time.Time) metricdata.HistogramDataPoint[float64] {
+	histogram := metric.GetHistogram()
+
+	bounds := make([]float64, 0, len(histogram.GetBucket()))
+	counts := make([]uint64, 0, len(histogram.GetBucket())+1)
+	var previous uint64
+	for _, bucket := range histogram.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		bounds = append(bounds, bucket.GetUpperBound())
+		counts = append(counts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	// 最后一个桶为 +Inf
+	counts = append(counts, histogram.GetSampleCount()-previous)
+
+	return metricdata.HistogramDataPoint[float64]{
+		Attributes:   labelAttributes(metric.GetLabel()),
+		StartTime:    p.startTime,
+		Time:         now,
+		Count:        histogram.GetSampleCount(),
+		Bounds:       bounds,
+		BucketCounts: counts,
+		Sum:          histogram.GetSampleSum(),
+	}
+}
+
+// labelAttributes 将 Prometheus 标签转换为属性集合
+func labelAttributes(labels []*dto.LabelPair) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		kvs = append(kvs, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(kvs...)
+}
@@ -0,0 +1,149 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewMetricsPusherInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *MetricsPushConfig
+	}{
+		{name: "nil config", config: nil},
+		{name: "empty endpoint", config: &MetricsPushConfig{Enabled: true}},
+		{name: "unsupported mode", config: &MetricsPushConfig{Enabled: true, Mode: "statsd", Endpoint: "localhost:8125"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMetricsPusher("test-service", tt.config); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestMetricsPusherPushgateway(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+		body  string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		body = string(data)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "push_test_total", Help: "test counter"})
+	registry.MustRegister(counter)
+	counter.Add(3)
+
+	pusher, err := NewMetricsPusher("test-service", &MetricsPushConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Interval: time.Hour,
+		Gatherer: registry,
+	})
+	if err != nil {
+		t.Fatalf("NewMetricsPusher failed: %v", err)
+	}
+
+	if err := pusher.Push(context.Background()); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	// 关闭时推送最后一次
+	if err := pusher.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 pushes, got %d", len(paths))
+	}
+	if !strings.HasPrefix(paths[0], "/metrics/job/test-service/instance/") {
+		t.Errorf("Unexpected push path: %s", paths[0])
+	}
+	if !strings.Contains(body, "push_test_total") {
+		t.Error("Pushed body does not contain the registered counter")
+	}
+}
+
+func TestPrometheusProducer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "producer_requests_total", Help: "requests"}, []string{"method"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "producer_active", Help: "active"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "producer_latency_seconds", Help: "latency", Buckets: []float64{0.1, 1}})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{Name: "producer_summary", Help: "summary"})
+	registry.MustRegister(counter, gauge, histogram, summary)
+
+	counter.WithLabelValues("get").Add(2)
+	gauge.Set(5)
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(2)
+	summary.Observe(1)
+
+	scopes, err := newPrometheusProducer(registry).Produce(context.Background())
+	if err != nil {
+		t.Fatalf("Produce failed: %v", err)
+	}
+	if len(scopes) != 1 {
+		t.Fatalf("Expected 1 scope, got %d", len(scopes))
+	}
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range scopes[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	// Summary 不导出
+	if _, ok := metrics["producer_summary"]; ok {
+		t.Error("Summary should not be exported")
+	}
+
+	sum, ok := metrics["producer_requests_total"].Data.(metricdata.Sum[float64])
+	if !ok || !sum.IsMonotonic || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 2 {
+		t.Errorf("Unexpected counter data: %+v", metrics["producer_requests_total"].Data)
+	} else if v, _ := sum.DataPoints[0].Attributes.Value("method"); v.AsString() != "get" {
+		t.Errorf("Expected method=get attribute, got %v", v.AsString())
+	}
+
+	g, ok := metrics["producer_active"].Data.(metricdata.Gauge[float64])
+	if !ok || len(g.DataPoints) != 1 || g.DataPoints[0].Value != 5 {
+		t.Errorf("Unexpected gauge data: %+v", metrics["producer_active"].Data)
+	}
+
+	h, ok := metrics["producer_latency_seconds"].Data.(metricdata.Histogram[float64])
+	if !ok || len(h.DataPoints) != 1 {
+		t.Fatalf("Unexpected histogram data: %+v", metrics["producer_latency_seconds"].Data)
+	}
+	point := h.DataPoints[0]
+	if point.Count != 3 || len(point.Bounds) != 2 {
+		t.Errorf("Unexpected histogram point: %+v", point)
+	}
+	// 累计桶 [1, 2] 加上 +Inf 桶应转换为逐桶计数 [1, 1, 1]
+	want := []uint64{1, 1, 1}
+	for i, c := range want {
+		if i >= len(point.BucketCounts) || point.BucketCounts[i] != c {
+			t.Errorf("BucketCounts = %v, want %v", point.BucketCounts, want)
+			break
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package observability
 
 import (
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestMetricsCollector(t *testing.T) {
@@ -64,3 +67,147 @@ func TestMetricsCollectorErrorCodes(t *testing.T) {
 		metrics.RecordError("test-service", "method", code)
 	}
 }
+
+func TestRuntimeMetricsExported(t *testing.T) {
+	NewMetricsCollector("test-service")
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	names := make(map[string]bool, len(families))
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+
+	expected := []string{
+		"go_goroutines",
+		"go_gc_duration_seconds",
+		"go_memstats_heap_alloc_bytes",
+	}
+	// 进程指标依赖 /proc
+	if runtime.GOOS == "linux" {
+		expected = append(expected,
+			"process_cpu_seconds_total",
+			"process_resident_memory_bytes",
+			"process_open_fds",
+		)
+	}
+
+	for _, name := range expected {
+		if !names[name] {
+			t.Errorf("Expected metric %s to be exported", name)
+		}
+	}
+}
+
+func TestRegisterRuntimeCollectorsIdempotent(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	if err := registerRuntimeCollectors(registry); err != nil {
+		t.Fatalf("First registration failed: %v", err)
+	}
+	if err := registerRuntimeCollectors(registry); err != nil {
+		t.Errorf("Second registration should be a no-op, got: %v", err)
+	}
+}
+
+func TestMetricsCollectorWithConfig(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetricsCollectorWithConfig("test-service", &MetricsConfig{
+		DurationBuckets: []float64{0.1, 1},
+		Labels:          []string{"service", "status"},
+		Registerer:      registry,
+	})
+	if err != nil {
+		t.Fatalf("NewMetricsCollectorWithConfig failed: %v", err)
+	}
+
+	metrics.RecordRequest("test-service", "getUser", "http", "success", 50*time.Millisecond)
+	metrics.RecordError("test-service", "getUser", "500")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		switch family.GetName() {
+		case "framework_request_total", "framework_request_duration_seconds", "framework_error_total":
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "method" || label.GetName() == "protocol" {
+						t.Errorf("%s should not have label %s", family.GetName(), label.GetName())
+					}
+				}
+			}
+		}
+
+		if family.GetName() == "framework_request_duration_seconds" {
+			// 未列出 status，延迟直方图只保留 service
+			metric := family.GetMetric()[0]
+			if len(metric.GetLabel()) != 1 || metric.GetLabel()[0].GetName() != "service" {
+				t.Errorf("Unexpected histogram labels: %v", metric.GetLabel())
+			}
+			if buckets := metric.GetHistogram().GetBucket(); len(buckets) != 2 {
+				t.Errorf("Expected 2 buckets, got %d", len(buckets))
+			}
+		}
+	}
+}
+
+func TestMetricsCollectorWithConfigReusesRegisterer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	config := &MetricsConfig{Labels: []string{"service", "status"}, Registerer: registry}
+
+	first, err := NewMetricsCollectorWithConfig("test-service", config)
+	if err != nil {
+		t.Fatalf("First NewMetricsCollectorWithConfig failed: %v", err)
+	}
+	second, err := NewMetricsCollectorWithConfig("test-service", config)
+	if err != nil {
+		t.Fatalf("Second NewMetricsCollectorWithConfig on the same registerer failed: %v", err)
+	}
+	if first.requestTotal != second.requestTotal {
+		t.Error("Expected the second collector to reuse the registered metrics")
+	}
+
+	// 同名指标的标签不同时无法复用
+	if _, err := NewMetricsCollectorWithConfig("test-service", &MetricsConfig{Registerer: registry}); err == nil {
+		t.Error("Expected error for conflicting labels on the same registerer")
+	}
+}
+
+func TestMetricsCollectorWithConfigDefaultRegisterer(t *testing.T) {
+	NewMetricsCollector("test-service")
+
+	if _, err := NewMetricsCollectorWithConfig("test-service", &MetricsConfig{}); err != nil {
+		t.Errorf("Default config should reuse the global metrics: %v", err)
+	}
+	if _, err := NewMetricsCollectorWithConfig("test-service", &MetricsConfig{DurationBuckets: []float64{0.1, 1}}); err == nil {
+		t.Error("Expected error for buckets that cannot take effect on the global registerer")
+	}
+	if _, err := NewMetricsCollectorWithConfig("test-service", &MetricsConfig{Labels: []string{"service"}}); err == nil {
+		t.Error("Expected error for labels that cannot take effect on the global registerer")
+	}
+}
+
+func TestMetricsCollectorWithInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *MetricsConfig
+	}{
+		{name: "nil config", config: nil},
+		{name: "unordered buckets", config: &MetricsConfig{DurationBuckets: []float64{1, 0.5}}},
+		{name: "unknown label", config: &MetricsConfig{Labels: []string{"user_id"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMetricsCollectorWithConfig("test-service", tt.config); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package observability
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/framework/golang-sdk/lifecycle"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 // ObservabilityManager 可观测性管理器
@@ -13,28 +17,148 @@ type ObservabilityManager struct {
 	logger        Logger
 	metrics       *MetricsCollector
 	tracer        *Tracer
+	exporter      *TelemetryExporter
+	pusher        *MetricsPusher
+	sloTracker    *SLOTracker
+	slowRequests  *SlowRequestDetector
+	events        *EventBus
 	healthChecker *HealthChecker
+	handlers      []adminHandler
 	serviceName   string
 	metricsPort   int
+	metricsServer *http.Server
+}
+
+// adminHandler 指标服务器上的额外端点
+type adminHandler struct {
+	pattern string
+	handler http.Handler
 }
 
 // Config 可观测性配置
 type Config struct {
 	ServiceName string
-	MetricsPort int
-	LogLevel    LogLevel
+	// ServiceVersion 导出的 span 和指标的 service.version 资源属性，为空时不设置
+	ServiceVersion string
+	MetricsPort    int
+	LogLevel       LogLevel
+	Logging        *LoggerConfig      // 日志格式和输出配置，为空时输出文本日志到标准输出
+	Metrics        *MetricsConfig     // 直方图桶和标签白名单配置，为空时使用默认桶并保留全部标签
+	Exporter       *ExporterConfig    // OTLP 导出配置，为空或未启用时只产生本地 span
+	MetricsPush    *MetricsPushConfig // 指标推送配置，为空或未启用时只提供 /metrics 拉取端点
+	SLO            *SLOConfig         // SLO 目标配置，为空时不跟踪
+	SlowRequest    *SlowRequestConfig // 慢请求检测配置，为空时不检测
 }
 
 // NewObservabilityManager 创建可观测性管理器
 func NewObservabilityManager(config Config) *ObservabilityManager {
 	logger := NewLogger(config.ServiceName)
+	if config.Logging != nil {
+		configured, err := NewLoggerWithConfig(config.ServiceName, config.Logging)
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create configured logger, falling back to stdout",
+				Field{Key: "error", Value: err.Error()})
+		} else {
+			logger = configured
+		}
+	}
 	logger.SetLevel(config.LogLevel)
 
+	// 导出的 span 和指标使用同一组自动检测的资源属性
+	var res *resource.Resource
+	if (config.Exporter != nil && config.Exporter.Enabled) || (config.MetricsPush != nil && config.MetricsPush.Enabled) {
+		res = DetectResource(context.Background(), &ResourceConfig{
+			ServiceName:    config.ServiceName,
+			ServiceVersion: config.ServiceVersion,
+		})
+	}
+
+	// 导出器需先于追踪器创建，以便追踪器使用注册后的全局 TracerProvider
+	var exporter *TelemetryExporter
+	if config.Exporter != nil && config.Exporter.Enabled {
+		exporterConfig := *config.Exporter
+		if exporterConfig.Resource == nil {
+			exporterConfig.Resource = res
+		}
+		var err error
+		exporter, err = NewTelemetryExporter(context.Background(), config.ServiceName, &exporterConfig)
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create OTLP exporter, spans will not be exported",
+				Field{Key: "error", Value: err.Error()})
+		}
+	}
+
+	// 指标只注册一次，配置须在首次创建收集器时传入
+	var metrics *MetricsCollector
+	if config.Metrics != nil {
+		var err error
+		metrics, err = NewMetricsCollectorWithConfig(config.ServiceName, config.Metrics)
+		if err != nil {
+			logger.Warn(context.Background(), "Invalid metrics config, falling back to default buckets and labels",
+				Field{Key: "error", Value: err.Error()})
+		}
+	}
+	if metrics == nil {
+		metrics = NewMetricsCollector(config.ServiceName)
+	}
+
+	// 指标推送与拉取端点并存
+	var pusher *MetricsPusher
+	if config.MetricsPush != nil && config.MetricsPush.Enabled {
+		var err error
+		pushConfig := *config.MetricsPush
+		if pushConfig.Resource == nil {
+			pushConfig.Resource = res
+		}
+		pusher, err = NewMetricsPusher(config.ServiceName, &pushConfig)
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create metrics pusher, metrics will only be available for scraping",
+				Field{Key: "error", Value: err.Error()})
+		}
+	}
+	healthChecker := NewHealthChecker(config.ServiceName)
+
+	// SLO 统计来自 RecordRequest，预算即将耗尽时就绪状态降级
+	var sloTracker *SLOTracker
+	if config.SLO != nil {
+		var err error
+		sloTracker, err = NewSLOTracker(config.SLO)
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create SLO tracker, SLOs will not be tracked",
+				Field{Key: "error", Value: err.Error()})
+		} else {
+			metrics.SetSLOTracker(sloTracker)
+			healthChecker.RegisterCheckWithOptions(sloTracker.HealthCheck(), CheckOptions{Kind: CheckKindReadiness})
+		}
+	}
+
+	// 慢请求的 span 树依赖导出器的 TracerProvider
+	var slowRequests *SlowRequestDetector
+	if config.SlowRequest != nil {
+		var err error
+		slowRequests, err = NewSlowRequestDetector(logger, config.SlowRequest)
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create slow request detector, slow requests will not be diagnosed",
+				Field{Key: "error", Value: err.Error()})
+		} else if exporter != nil {
+			exporter.RegisterSpanProcessor(slowRequests.SpanRecorder())
+		}
+	}
+
+	// 框架事件默认写入日志
+	events, _ := NewEventBus(&EventBusConfig{})
+	events.Subscribe(NewLogEventHandler(logger))
+
 	return &ObservabilityManager{
 		logger:        logger,
-		metrics:       NewMetricsCollector(config.ServiceName),
+		metrics:       metrics,
 		tracer:        NewTracer(config.ServiceName),
-		healthChecker: NewHealthChecker(config.ServiceName),
+		exporter:      exporter,
+		pusher:        pusher,
+		sloTracker:    sloTracker,
+		slowRequests:  slowRequests,
+		events:        events,
+		healthChecker: healthChecker,
 		serviceName:   config.ServiceName,
 		metricsPort:   config.MetricsPort,
 	}
@@ -55,12 +179,42 @@ func (o *ObservabilityManager) Tracer() *Tracer {
 	return o.tracer
 }
 
+// Exporter 获取 OTLP 导出器，未启用时返回 nil
+func (o *ObservabilityManager) Exporter() *TelemetryExporter {
+	return o.exporter
+}
+
+// MetricsPusher 获取指标推送器，未启用时返回 nil
+func (o *ObservabilityManager) MetricsPusher() *MetricsPusher {
+	return o.pusher
+}
+
+// SLOTracker 获取 SLO 跟踪器，未配置时返回 nil
+func (o *ObservabilityManager) SLOTracker() *SLOTracker {
+	return o.sloTracker
+}
+
+// SlowRequests 获取慢请求检测器，未配置时返回 nil
+func (o *ObservabilityManager) SlowRequests() *SlowRequestDetector {
+	return o.slowRequests
+}
+
+// Events 获取框架事件总线
+func (o *ObservabilityManager) Events() *EventBus {
+	return o.events
+}
+
 // HealthChecker 获取健康检查器
 func (o *ObservabilityManager) HealthChecker() *HealthChecker {
 	return o.healthChecker
 }
 
-// StartMetricsServer 启动指标暴露服务器
+// RegisterHandler 在指标服务器上注册额外的 HTTP 端点（如 /config），须在 StartMetricsServer 之前调用
+func (o *ObservabilityManager) RegisterHandler(pattern string, handler http.Handler) {
+	o.handlers = append(o.handlers, adminHandler{pattern: pattern, handler: handler})
+}
+
+// StartMetricsServer 启动指标暴露服务器，监听失败时返回错误；监听器经 lifecycle.Listen 创建，热重启时交接给新进程
 func (o *ObservabilityManager) StartMetricsServer() error {
 	mux := http.NewServeMux()
 
@@ -69,13 +223,25 @@ func (o *ObservabilityManager) StartMetricsServer() error {
 
 	// 健康检查端点
 	mux.HandleFunc("/health", o.healthChecker.Handler())
+	mux.HandleFunc("/health/live", o.healthChecker.LivenessHandler())
+	mux.HandleFunc("/health/ready", o.healthChecker.ReadinessHandler())
+
+	for _, h := range o.handlers {
+		mux.Handle(h.pattern, h.handler)
+	}
 
 	addr := fmt.Sprintf(":%d", o.metricsPort)
+	listener, err := lifecycle.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
 	o.logger.Info(context.Background(), "Starting metrics server",
 		Field{Key: "address", Value: addr})
 
+	server := &http.Server{Handler: mux}
+	o.metricsServer = server
 	go func() {
-		if err := http.ListenAndServe(addr, mux); err != nil {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			o.logger.Error(context.Background(), "Metrics server error",
 				Field{Key: "error", Value: err.Error()})
 		}
@@ -90,3 +256,25 @@ func (o *ObservabilityManager) SetLogLevel(level LogLevel) {
 	o.logger.Info(context.Background(), "Log level changed",
 		Field{Key: "new_level", Value: string(level)})
 }
+
+// Shutdown 停止指标服务器、SLO 跟踪和事件投递，推送最后一次指标，刷新并关闭 OTLP 导出器和日志输出目标，应在服务退出前调用
+func (o *ObservabilityManager) Shutdown(ctx context.Context) error {
+	var errs []error
+	if o.metricsServer != nil {
+		errs = append(errs, o.metricsServer.Shutdown(ctx))
+	}
+	if o.sloTracker != nil {
+		errs = append(errs, o.sloTracker.Close())
+	}
+	errs = append(errs, o.events.Close())
+	if o.pusher != nil {
+		errs = append(errs, o.pusher.Close())
+	}
+	if o.exporter != nil {
+		errs = append(errs, o.exporter.Shutdown(ctx))
+	}
+	if closer, ok := o.logger.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
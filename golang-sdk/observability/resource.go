@@ -0,0 +1,115 @@
+package observability
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// kubernetesNamespaceFile Pod 内服务账号的命名空间文件，未通过 downward API 注入命名空间时读取
+const kubernetesNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// ResourceConfig OpenTelemetry 资源属性配置
+type ResourceConfig struct {
+	ServiceName    string // service.name，对应 framework.name
+	ServiceVersion string // service.version，对应 framework.version
+	// ServiceInstanceID service.instance.id，为空时不设置
+	ServiceInstanceID string
+}
+
+// DetectResource 检测导出的 span 和指标所带的资源属性，属性名遵循 OpenTelemetry 语义约定，使各语言服务的遥测数据在后端按服务、主机和 Pod 正确归组
+//
+// 依次合并（后者覆盖前者）：telemetry.sdk.*；host.name；os.type；process.pid、process.executable.name、
+// process.runtime.*；容器内的 container.id；Kubernetes 中的 k8s.pod.name、k8s.pod.uid、k8s.namespace.name、k8s.node.name；
+// 配置的 service.name、service.version；OTEL_RESOURCE_ATTRIBUTES 和 OTEL_SERVICE_NAME 环境变量。
+// 检测失败的属性被忽略，不影响其他属性
+func DetectResource(ctx context.Context, config *ResourceConfig) *resource.Resource {
+	if config == nil {
+		config = &ResourceConfig{}
+	}
+	var service []attribute.KeyValue
+	if config.ServiceName != "" {
+		service = append(service, semconv.ServiceName(config.ServiceName))
+	}
+	if config.ServiceVersion != "" {
+		service = append(service, semconv.ServiceVersion(config.ServiceVersion))
+	}
+	if config.ServiceInstanceID != "" {
+		service = append(service, semconv.ServiceInstanceID(config.ServiceInstanceID))
+	}
+
+	// 不检测命令行参数，避免把参数中的密钥导出到后端
+	res, _ := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithOSType(),
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithContainerID(),
+		resource.WithDetectors(kubernetesDetector{}),
+		resource.WithAttributes(service...),
+		resource.WithFromEnv(),
+	)
+	if res == nil {
+		// 属性的 schema 冲突等无法合并的错误，只保留服务属性
+		return resource.NewSchemaless(service...)
+	}
+	return res
+}
+
+// kubernetesDetector 检测 Pod 的资源属性
+//
+// 优先读取通过 downward API 注入的环境变量 K8S_POD_NAME、K8S_POD_UID、K8S_NAMESPACE_NAME、K8S_NODE_NAME
+// （或 POD_NAME、POD_UID、POD_NAMESPACE、NODE_NAME）；在 Kubernetes 中（KUBERNETES_SERVICE_HOST 已设置）未注入时，
+// Pod 名称取主机名，命名空间取服务账号的命名空间文件
+type kubernetesDetector struct{}
+
+// Detect 实现 resource.Detector，不在 Kubernetes 中时返回空资源
+func (kubernetesDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	podName := firstEnv("K8S_POD_NAME", "POD_NAME")
+	namespace := firstEnv("K8S_NAMESPACE_NAME", "POD_NAMESPACE")
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		if podName == "" {
+			podName, _ = os.Hostname()
+		}
+		if namespace == "" {
+			if data, err := os.ReadFile(kubernetesNamespaceFile); err == nil {
+				namespace = strings.TrimSpace(string(data))
+			}
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	if podName != "" {
+		attrs = append(attrs, semconv.K8SPodName(podName))
+	}
+	if uid := firstEnv("K8S_POD_UID", "POD_UID"); uid != "" {
+		attrs = append(attrs, semconv.K8SPodUID(uid))
+	}
+	if namespace != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(namespace))
+	}
+	if node := firstEnv("K8S_NODE_NAME", "NODE_NAME"); node != "" {
+		attrs = append(attrs, semconv.K8SNodeName(node))
+	}
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// firstEnv 返回第一个非空的环境变量
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package observability
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// TestDetectResource 测试自动检测的资源属性：服务名和版本来自配置，主机和进程属性自动检测，环境变量覆盖配置
+func TestDetectResource(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=staging")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	res := DetectResource(context.Background(), &ResourceConfig{ServiceName: "order-service", ServiceVersion: "1.2.0"})
+	attrs := resourceAttributes(res)
+	hostname, _ := os.Hostname()
+	for key, want := range map[string]string{
+		"service.name":           "order-service",
+		"service.version":        "1.2.0",
+		"telemetry.sdk.language": "go",
+		"host.name":              hostname,
+		"process.runtime.name":   "go",
+		"deployment.environment": "staging",
+	} {
+		if attrs[key] != want {
+			t.Errorf("%s = %q, want %q", key, attrs[key], want)
+		}
+	}
+	if attrs["process.pid"] == "" {
+		t.Error("Expected process.pid")
+	}
+	if _, ok := attrs["k8s.pod.name"]; ok {
+		t.Errorf("Unexpected k8s.pod.name outside Kubernetes: %s", attrs["k8s.pod.name"])
+	}
+
+	t.Setenv("OTEL_SERVICE_NAME", "order-service-canary")
+	if attrs := resourceAttributes(DetectResource(context.Background(), &ResourceConfig{ServiceName: "order-service"})); attrs["service.name"] != "order-service-canary" {
+		t.Errorf("Expected OTEL_SERVICE_NAME to override service.name, got %s", attrs["service.name"])
+	}
+}
+
+// TestKubernetesDetector 测试 downward API 注入的 Pod 属性和在 Kubernetes 中以主机名作为 Pod 名称
+func TestKubernetesDetector(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	for _, name := range []string{"K8S_POD_NAME", "POD_NAME", "K8S_POD_UID", "POD_UID", "K8S_NAMESPACE_NAME", "POD_NAMESPACE", "K8S_NODE_NAME", "NODE_NAME"} {
+		t.Setenv(name, "")
+	}
+	res, err := kubernetesDetector{}.Detect(context.Background())
+	if err != nil || res.Len() != 0 {
+		t.Fatalf("Expected empty resource outside Kubernetes, got %v, %v", res, err)
+	}
+
+	t.Setenv("POD_NAME", "order-7d9f-x2k")
+	t.Setenv("K8S_NAMESPACE_NAME", "shop")
+	t.Setenv("NODE_NAME", "node-3")
+	t.Setenv("K8S_POD_UID", "0b5c-uid")
+	res, _ = kubernetesDetector{}.Detect(context.Background())
+	attrs := resourceAttributes(res)
+	if attrs["k8s.pod.name"] != "order-7d9f-x2k" || attrs["k8s.namespace.name"] != "shop" ||
+		attrs["k8s.node.name"] != "node-3" || attrs["k8s.pod.uid"] != "0b5c-uid" {
+		t.Errorf("Unexpected Kubernetes attributes: %v", attrs)
+	}
+
+	t.Setenv("POD_NAME", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	hostname, _ := os.Hostname()
+	res, _ = kubernetesDetector{}.Detect(context.Background())
+	if attrs := resourceAttributes(res); attrs["k8s.pod.name"] != hostname {
+		t.Errorf("Expected hostname as pod name, got %q", attrs["k8s.pod.name"])
+	}
+}
+
+func resourceAttributes(res *resource.Resource) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
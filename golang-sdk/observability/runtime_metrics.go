@@ -0,0 +1,36 @@
+package observability
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// registerRuntimeCollectors 注册 Go 运行时和进程指标
+//
+// 默认的 Go 采集器只导出 memstats，这里替换为额外包含 GC 停顿、堆内存细分和调度器延迟的版本；
+// 进程采集器导出 CPU 时间、RSS 以及打开的文件描述符数（process_open_fds，仅 Linux）
+func registerRuntimeCollectors(registerer prometheus.Registerer) error {
+	registerer.Unregister(collectors.NewGoCollector())
+
+	goCollector := collectors.NewGoCollector(
+		collectors.WithGoCollectorRuntimeMetrics(
+			collectors.MetricsGC,
+			collectors.MetricsMemory,
+			collectors.MetricsScheduler,
+		),
+	)
+	processCollector := collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})
+
+	for _, collector := range []prometheus.Collector{goCollector, processCollector} {
+		if err := registerer.Register(collector); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if !errors.As(err, &alreadyRegistered) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
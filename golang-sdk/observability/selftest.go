@@ -0,0 +1,207 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultSelfTestTimeout 单个自检的默认超时
+const DefaultSelfTestTimeout = 10 * time.Second
+
+var (
+	// 用于防止重复注册的锁
+	selfTestMetricsOnce sync.Once
+	// 最近一次自检各检查是否通过（1 通过，0 失败）
+	selfTestPassed *prometheus.GaugeVec
+	// 最近一次自检各检查的耗时
+	selfTestDuration *prometheus.GaugeVec
+	// 自检执行次数
+	selfTestRunsTotal *prometheus.CounterVec
+)
+
+// initSelfTestMetrics 初始化自检指标
+func initSelfTestMetrics() {
+	selfTestMetricsOnce.Do(func() {
+		selfTestPassed = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_selftest_check_passed",
+				Help: "Whether the check passed in the latest self-test run (1 passed, 0 failed)",
+			},
+			[]string{"check"},
+		)
+		selfTestDuration = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_selftest_check_duration_seconds",
+				Help: "Duration of the check in the latest self-test run",
+			},
+			[]string{"check"},
+		)
+		selfTestRunsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_selftest_runs_total",
+				Help: "Total number of self-test runs by result",
+			},
+			[]string{"result"},
+		)
+	})
+}
+
+// SelfTestResult 单个自检的结果
+type SelfTestResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// SelfTestReport 一次自检的报告，Results 按注册顺序排列
+type SelfTestReport struct {
+	Passed    bool             `json:"passed"`
+	StartedAt time.Time        `json:"startedAt"`
+	Duration  time.Duration    `json:"duration"`
+	Results   []SelfTestResult `json:"results"`
+}
+
+// Failed 返回失败的检查
+func (r *SelfTestReport) Failed() []SelfTestResult {
+	var failed []SelfTestResult
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// SelfTest 启动自检：服务启动后执行一次注册的检查（如能发现依赖的服务、序列化器能往返编解码、TLS 证书有效），
+// 结果写入日志和指标，并保留最近一次的报告供端点查询
+//
+// 与健康检查不同，自检只在启动时（或经管理接口手动）执行，适合代价较高或只需确认一次的检查；
+// 检查并发执行，单个检查超过超时或 panic 时视为失败
+type SelfTest struct {
+	logger  Logger
+	timeout time.Duration
+
+	mu     sync.Mutex
+	checks []HealthCheck
+	report *SelfTestReport
+}
+
+// NewSelfTest 创建自检，timeout 为单个检查的超时，为 0 时使用 DefaultSelfTestTimeout
+func NewSelfTest(logger Logger, timeout time.Duration) *SelfTest {
+	initSelfTestMetrics()
+	if timeout <= 0 {
+		timeout = DefaultSelfTestTimeout
+	}
+	return &SelfTest{logger: logger, timeout: timeout}
+}
+
+// Register 注册检查，HealthCheck 和 lifecycle.Dependency 的实现均可作为检查
+func (t *SelfTest) Register(checks ...HealthCheck) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checks = append(t.checks, checks...)
+}
+
+// Run 并发执行所有检查，记录日志和指标并返回报告
+func (t *SelfTest) Run(ctx context.Context) *SelfTestReport {
+	t.mu.Lock()
+	checks := append([]HealthCheck(nil), t.checks...)
+	t.mu.Unlock()
+
+	report := &SelfTestReport{Passed: true, StartedAt: time.Now(), Results: make([]SelfTestResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			report.Results[i] = t.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	report.Duration = time.Since(report.StartedAt)
+
+	for _, result := range report.Results {
+		passed := 0.0
+		if result.Passed {
+			passed = 1
+		} else {
+			report.Passed = false
+			t.logger.Warn(ctx, "Self-test check failed",
+				Field{Key: "check", Value: result.Name},
+				Field{Key: "error", Value: result.Error},
+				Field{Key: "duration", Value: result.Duration.String()})
+		}
+		selfTestPassed.WithLabelValues(result.Name).Set(passed)
+		selfTestDuration.WithLabelValues(result.Name).Set(result.Duration.Seconds())
+	}
+	outcome := "passed"
+	if !report.Passed {
+		outcome = "failed"
+	}
+	selfTestRunsTotal.WithLabelValues(outcome).Inc()
+	t.logger.Info(ctx, "Self-test completed",
+		Field{Key: "result", Value: outcome},
+		Field{Key: "checks", Value: len(report.Results)},
+		Field{Key: "failed", Value: len(report.Failed())},
+		Field{Key: "duration", Value: report.Duration.String()})
+
+	t.mu.Lock()
+	t.report = report
+	t.mu.Unlock()
+	return report
+}
+
+// runCheck 在超时内执行单个检查，panic 视为失败
+func (t *SelfTest) runCheck(ctx context.Context, check HealthCheck) (result SelfTestResult) {
+	result.Name = check.Name()
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Passed, result.Error = false, fmt.Sprintf("panic: %v", r)
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	if err := check.Check(ctx); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Passed = true
+	return result
+}
+
+// Report 返回最近一次自检的报告，尚未执行时返回 nil
+func (t *SelfTest) Report() *SelfTestReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.report
+}
+
+// Handler 返回最近一次自检报告的 HTTP 处理器，尚未执行或有检查失败时返回 503
+func (t *SelfTest) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := t.Report()
+		w.Header().Set("Content-Type", "application/json")
+		if report == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"status": "pending"})
+			return
+		}
+		if report.Passed {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,54 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	selfTest := NewSelfTest(NewLogger("test-service"), 50*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	selfTest.Handler()(rec, httptest.NewRequest(http.MethodGet, "/health/selftest", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before first run, got %d", rec.Code)
+	}
+
+	selfTest.Register(
+		NewSimpleHealthCheck("ok", func(ctx context.Context) error { return nil }),
+		NewSimpleHealthCheck("broken", func(ctx context.Context) error { return errors.New("dependency missing") }),
+		NewSimpleHealthCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		NewSimpleHealthCheck("panics", func(ctx context.Context) error { panic("boom") }),
+	)
+	report := selfTest.Run(context.Background())
+	if report.Passed {
+		t.Fatal("Expected self-test to fail")
+	}
+	want := []bool{true, false, false, false}
+	for i, result := range report.Results {
+		if result.Passed != want[i] {
+			t.Errorf("%s passed = %v, want %v (%s)", result.Name, result.Passed, want[i], result.Error)
+		}
+	}
+	if failed := report.Failed(); len(failed) != 3 || failed[2].Error != "panic: boom" {
+		t.Errorf("Unexpected failed checks: %+v", failed)
+	}
+	if report.Duration > time.Second {
+		t.Errorf("Checks should run concurrently within the timeout, took %v", report.Duration)
+	}
+
+	rec = httptest.NewRecorder()
+	selfTest.Handler()(rec, httptest.NewRequest(http.MethodGet, "/health/selftest", nil))
+	var decoded SelfTestReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil || rec.Code != http.StatusServiceUnavailable || len(decoded.Results) != 4 {
+		t.Errorf("Handler = %d %s", rec.Code, rec.Body.String())
+	}
+}
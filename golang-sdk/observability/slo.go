@@ -0,0 +1,350 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SLO 目标类型
+const (
+	SLOKindAvailability = "availability"
+	SLOKindLatency      = "latency"
+)
+
+// SLO 默认值
+const (
+	DefaultSLOWindow             = time.Hour
+	DefaultSLOEvaluationInterval = 10 * time.Second
+	DefaultSLOBudgetWarning      = 0.1
+	// sloBucketCount 滚动窗口划分的桶数
+	sloBucketCount = 60
+)
+
+var (
+	// 用于防止重复注册的锁
+	sloMetricsOnce sync.Once
+	// SLO 达成率
+	sloCompliance *prometheus.GaugeVec
+	// 错误预算剩余比例
+	sloBudgetRemaining *prometheus.GaugeVec
+	// 错误预算消耗速率
+	sloBurnRate *prometheus.GaugeVec
+)
+
+// initSLOMetrics 初始化 SLO 指标
+func initSLOMetrics() {
+	sloMetricsOnce.Do(func() {
+		sloCompliance = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_slo_compliance_ratio",
+				Help: "Ratio of good requests in the SLO window",
+			},
+			[]string{"service", "objective"},
+		)
+		sloBudgetRemaining = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_slo_error_budget_remaining_ratio",
+				Help: "Remaining error budget in the SLO window, 1 means untouched and 0 means exhausted",
+			},
+			[]string{"service", "objective"},
+		)
+		sloBurnRate = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_slo_burn_rate",
+				Help: "Error budget burn rate, 1 means the budget is consumed exactly at the end of the window",
+			},
+			[]string{"service", "objective"},
+		)
+	})
+}
+
+// SLOObjective 单个服务的服务等级目标
+type SLOObjective struct {
+	Service string
+	// Availability 可用性目标，如 0.999；为 0 时不跟踪可用性
+	Availability float64
+	// LatencyThreshold 延迟阈值，低于该值的请求视为达标；为 0 时不跟踪延迟
+	LatencyThreshold time.Duration
+	// LatencyTarget 延迟达标请求的目标比例，如 0.99
+	LatencyTarget float64
+	// Window 滚动统计窗口，默认 DefaultSLOWindow
+	Window time.Duration
+}
+
+// SLOConfig SLO 跟踪配置
+type SLOConfig struct {
+	Objectives []SLOObjective
+	// BudgetWarning 错误预算剩余比例低于该值时健康检查降级，默认 0.1
+	BudgetWarning float64
+	// EvaluationInterval 指标刷新周期
+	EvaluationInterval time.Duration
+}
+
+// SLOStatus 单个目标的当前状态
+type SLOStatus struct {
+	Service         string  `json:"service"`
+	Objective       string  `json:"objective"`
+	Target          float64 `json:"target"`
+	Compliance      float64 `json:"compliance"`
+	BurnRate        float64 `json:"burn_rate"`
+	BudgetRemaining float64 `json:"budget_remaining"`
+	Requests        uint64  `json:"requests"`
+}
+
+// SLOTracker 服务等级目标跟踪器
+//
+// 按服务统计滚动窗口内的请求成功率和延迟达标率，计算错误预算消耗并定期刷新到指标
+type SLOTracker struct {
+	objectives    map[string]*sloState
+	budgetWarning float64
+	now           func() time.Time
+	stopCh        chan struct{}
+	closeOnce     sync.Once
+}
+
+// sloState 单个服务的目标和统计窗口
+type sloState struct {
+	objective SLOObjective
+	width     time.Duration
+	buckets   [sloBucketCount]sloBucket
+	mu        sync.Mutex
+}
+
+// sloBucket 统计桶
+type sloBucket struct {
+	index  int64
+	total  uint64
+	errors uint64
+	slow   uint64
+}
+
+// NewSLOTracker 创建 SLO 跟踪器
+func NewSLOTracker(config *SLOConfig) (*SLOTracker, error) {
+	if config == nil {
+		return nil, fmt.Errorf("slo config cannot be nil")
+	}
+
+	budgetWarning := config.BudgetWarning
+	if budgetWarning <= 0 {
+		budgetWarning = DefaultSLOBudgetWarning
+	}
+
+	interval := config.EvaluationInterval
+	if interval <= 0 {
+		interval = DefaultSLOEvaluationInterval
+	}
+
+	objectives := make(map[string]*sloState, len(config.Objectives))
+	for _, objective := range config.Objectives {
+		if err := validateSLOObjective(objective); err != nil {
+			return nil, err
+		}
+		if _, exists := objectives[objective.Service]; exists {
+			return nil, fmt.Errorf("duplicate slo objective for service %s", objective.Service)
+		}
+
+		if objective.Window <= 0 {
+			objective.Window = DefaultSLOWindow
+		}
+		objectives[objective.Service] = &sloState{
+			objective: objective,
+			width:     objective.Window / sloBucketCount,
+		}
+	}
+
+	initSLOMetrics()
+
+	t := &SLOTracker{
+		objectives:    objectives,
+		budgetWarning: budgetWarning,
+		now:           time.Now,
+		stopCh:        make(chan struct{}),
+	}
+
+	go t.refreshLoop(interval)
+
+	return t, nil
+}
+
+// validateSLOObjective 校验目标配置
+func validateSLOObjective(objective SLOObjective) error {
+	if objective.Service == "" {
+		return fmt.Errorf("slo objective service cannot be empty")
+	}
+
+	if objective.Availability < 0 || objective.Availability >= 1 {
+		return fmt.Errorf("invalid availability target for service %s: %v", objective.Service, objective.Availability)
+	}
+
+	if objective.LatencyThreshold > 0 && (objective.LatencyTarget <= 0 || objective.LatencyTarget >= 1) {
+		return fmt.Errorf("invalid latency target for service %s: %v", objective.Service, objective.LatencyTarget)
+	}
+
+	if objective.Availability == 0 && objective.LatencyThreshold <= 0 {
+		return fmt.Errorf("slo objective for service %s has no availability or latency target", objective.Service)
+	}
+
+	// 窗口划分为 sloBucketCount 个桶，过短的窗口使桶宽为 0
+	if objective.Window > 0 && objective.Window < sloBucketCount {
+		return fmt.Errorf("slo window for service %s must be at least %v: %v", objective.Service, time.Duration(sloBucketCount), objective.Window)
+	}
+
+	return nil
+}
+
+// Record 记录一次请求结果，未声明目标的服务被忽略
+func (t *SLOTracker) Record(service string, success bool, latency time.Duration) {
+	state, ok := t.objectives[service]
+	if !ok {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	bucket := state.bucket(t.now())
+	bucket.total++
+	if !success {
+		bucket.errors++
+	}
+	if state.objective.LatencyThreshold > 0 && latency > state.objective.LatencyThreshold {
+		bucket.slow++
+	}
+}
+
+// Status 返回所有目标的当前状态，按服务名排序
+func (t *SLOTracker) Status() []SLOStatus {
+	services := make([]string, 0, len(t.objectives))
+	for service := range t.objectives {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	now := t.now()
+	statuses := make([]SLOStatus, 0, len(services))
+	for _, service := range services {
+		statuses = append(statuses, t.objectives[service].status(now)...)
+	}
+	return statuses
+}
+
+// HealthCheck 返回错误预算健康检查，任一目标的剩余预算低于告警阈值时检查失败
+//
+// 建议以非关键就绪检查注册，使预算即将耗尽时服务状态为 degraded
+func (t *SLOTracker) HealthCheck() HealthCheck {
+	return NewSimpleHealthCheck("slo", func(ctx context.Context) error {
+		var exhausted []string
+		for _, status := range t.Status() {
+			if status.BudgetRemaining < t.budgetWarning {
+				exhausted = append(exhausted, fmt.Sprintf("%s/%s (%.1f%% remaining)",
+					status.Service, status.Objective, status.BudgetRemaining*100))
+			}
+		}
+
+		if len(exhausted) > 0 {
+			return fmt.Errorf("error budget nearly exhausted: %s", strings.Join(exhausted, ", "))
+		}
+		return nil
+	})
+}
+
+// Close 停止指标刷新
+func (t *SLOTracker) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.stopCh)
+	})
+	return nil
+}
+
+// refreshLoop 定期刷新 SLO 指标
+func (t *SLOTracker) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	t.refresh()
+	for {
+		select {
+		case <-ticker.C:
+			t.refresh()
+		case <-t.stopCh:
+			return
+		}
+	}
+}
+
+// refresh 将当前状态写入指标
+func (t *SLOTracker) refresh() {
+	for _, status := range t.Status() {
+		sloCompliance.WithLabelValues(status.Service, status.Objective).Set(status.Compliance)
+		sloBudgetRemaining.WithLabelValues(status.Service, status.Objective).Set(status.BudgetRemaining)
+		sloBurnRate.WithLabelValues(status.Service, status.Objective).Set(status.BurnRate)
+	}
+}
+
+// bucket 返回当前时间对应的统计桶，过期的桶会被重置
+func (s *sloState) bucket(now time.Time) *sloBucket {
+	index := now.UnixNano() / int64(s.width)
+	bucket := &s.buckets[index%sloBucketCount]
+	if bucket.index != index {
+		*bucket = sloBucket{index: index}
+	}
+	return bucket
+}
+
+// status 计算窗口内各目标的状态
+func (s *sloState) status(now time.Time) []SLOStatus {
+	s.mu.Lock()
+	current := now.UnixNano() / int64(s.width)
+	var total, failed, slow uint64
+	for _, bucket := range s.buckets {
+		if bucket.index > current-sloBucketCount && bucket.index <= current {
+			total += bucket.total
+			failed += bucket.errors
+			slow += bucket.slow
+		}
+	}
+	s.mu.Unlock()
+
+	var statuses []SLOStatus
+	if s.objective.Availability > 0 {
+		statuses = append(statuses, newSLOStatus(s.objective.Service, SLOKindAvailability, s.objective.Availability, total, failed))
+	}
+	if s.objective.LatencyThreshold > 0 {
+		statuses = append(statuses, newSLOStatus(s.objective.Service, SLOKindLatency, s.objective.LatencyTarget, total, slow))
+	}
+	return statuses
+}
+
+// newSLOStatus 根据请求总数和不达标数计算目标状态
+//
+// 消耗速率 = 不达标比例 / 错误预算比例，速率为 1 表示窗口结束时恰好用完预算
+func newSLOStatus(service, objective string, target float64, total, bad uint64) SLOStatus {
+	status := SLOStatus{
+		Service:         service,
+		Objective:       objective,
+		Target:          target,
+		Compliance:      1,
+		BudgetRemaining: 1,
+		Requests:        total,
+	}
+
+	if total == 0 {
+		return status
+	}
+
+	badRatio := float64(bad) / float64(total)
+	status.Compliance = 1 - badRatio
+	status.BurnRate = badRatio / (1 - target)
+	status.BudgetRemaining = 1 - status.BurnRate
+	if status.BudgetRemaining < 0 {
+		status.BudgetRemaining = 0
+	}
+	return status
+}
@@ -0,0 +1,143 @@
+package observability
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewSLOTracker(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SLOConfig
+		wantErr bool
+	}{
+		{name: "nil config", config: nil, wantErr: true},
+		{name: "valid availability", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", Availability: 0.999}}}, wantErr: false},
+		{name: "valid latency", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", LatencyThreshold: 100 * time.Millisecond, LatencyTarget: 0.99}}}, wantErr: false},
+		{name: "empty service", config: &SLOConfig{Objectives: []SLOObjective{{Availability: 0.999}}}, wantErr: true},
+		{name: "availability out of range", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", Availability: 1}}}, wantErr: true},
+		{name: "latency without target", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", LatencyThreshold: time.Second}}}, wantErr: true},
+		{name: "no objective", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user"}}}, wantErr: true},
+		{name: "window too short", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", Availability: 0.99, Window: 10}}}, wantErr: true},
+		{name: "duplicate service", config: &SLOConfig{Objectives: []SLOObjective{{Service: "user", Availability: 0.99}, {Service: "user", Availability: 0.9}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker, err := NewSLOTracker(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSLOTracker() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tracker != nil {
+				tracker.Close()
+			}
+		})
+	}
+}
+
+func TestSLOTrackerBurnRate(t *testing.T) {
+	tracker, err := NewSLOTracker(&SLOConfig{
+		Objectives: []SLOObjective{{
+			Service:          "order",
+			Availability:     0.99,
+			LatencyThreshold: 100 * time.Millisecond,
+			LatencyTarget:    0.9,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewSLOTracker() error = %v", err)
+	}
+	defer tracker.Close()
+
+	// 100 个请求：1 个失败，5 个慢请求
+	for i := 0; i < 100; i++ {
+		latency := 10 * time.Millisecond
+		if i < 5 {
+			latency = 200 * time.Millisecond
+		}
+		tracker.Record("order", i != 99, latency)
+	}
+	// 未声明目标的服务被忽略
+	tracker.Record("unknown", false, time.Second)
+
+	statuses := tracker.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+
+	availability, latency := statuses[0], statuses[1]
+	if availability.Objective != SLOKindAvailability || latency.Objective != SLOKindLatency {
+		t.Fatalf("unexpected objectives: %s, %s", availability.Objective, latency.Objective)
+	}
+
+	// 可用性：错误率 1%，预算 1%，速率为 1，预算耗尽
+	if math.Abs(availability.BurnRate-1) > 1e-9 || availability.BudgetRemaining > 1e-9 {
+		t.Errorf("availability burn rate = %v, remaining = %v", availability.BurnRate, availability.BudgetRemaining)
+	}
+
+	// 延迟：慢请求 5%，预算 10%，剩余一半
+	if math.Abs(latency.BudgetRemaining-0.5) > 1e-9 || math.Abs(latency.Compliance-0.95) > 1e-9 {
+		t.Errorf("latency compliance = %v, remaining = %v", latency.Compliance, latency.BudgetRemaining)
+	}
+
+	if err := tracker.HealthCheck().Check(context.Background()); err == nil {
+		t.Error("expected health check to fail when error budget is exhausted")
+	}
+}
+
+func TestSLOTrackerNoTraffic(t *testing.T) {
+	tracker, _ := NewSLOTracker(&SLOConfig{
+		Objectives: []SLOObjective{{Service: "order", Availability: 0.999}},
+	})
+	defer tracker.Close()
+
+	status := tracker.Status()[0]
+	if status.Compliance != 1 || status.BudgetRemaining != 1 || status.BurnRate != 0 {
+		t.Errorf("unexpected status without traffic: %+v", status)
+	}
+
+	if err := tracker.HealthCheck().Check(context.Background()); err != nil {
+		t.Errorf("expected health check to pass without traffic: %v", err)
+	}
+}
+
+func TestSLOWindowExpiry(t *testing.T) {
+	state := &sloState{
+		objective: SLOObjective{Service: "order", Availability: 0.99, Window: time.Minute},
+		width:     time.Minute / sloBucketCount,
+	}
+
+	start := time.Unix(1700000000, 0)
+	state.bucket(start).total = 10
+	state.bucket(start).errors = 10
+
+	if got := state.status(start.Add(30 * time.Second))[0].Requests; got != 10 {
+		t.Errorf("requests within window = %d, want 10", got)
+	}
+
+	// 超出窗口后旧数据不再计入
+	if got := state.status(start.Add(2 * time.Minute))[0].Requests; got != 0 {
+		t.Errorf("requests after window = %d, want 0", got)
+	}
+}
+
+func TestMetricsCollectorFeedsSLOTracker(t *testing.T) {
+	tracker, _ := NewSLOTracker(&SLOConfig{
+		Objectives: []SLOObjective{{Service: "slo-service", Availability: 0.5}},
+	})
+	defer tracker.Close()
+
+	metrics := NewMetricsCollector("slo-service")
+	metrics.SetSLOTracker(tracker)
+	defer metrics.SetSLOTracker(nil)
+
+	metrics.RecordRequest("slo-service", "method", "http", RequestStatusSuccess, time.Millisecond)
+	metrics.RecordRequest("slo-service", "method", "http", "error", time.Millisecond)
+
+	status := tracker.Status()[0]
+	if status.Requests != 2 || status.Compliance != 0.5 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
@@ -0,0 +1,452 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 慢请求检测默认值
+const (
+	DefaultSlowRequestThreshold  = time.Second
+	DefaultSlowCapturesPerMinute = 10
+	// maxDiagnosticSpansPerTrace 每个追踪最多记录的 span 数
+	maxDiagnosticSpansPerTrace = 256
+	// maxGoroutineDumpSize 全部 goroutine 堆栈的最大缓冲区
+	maxGoroutineDumpSize = 8 << 20
+)
+
+var (
+	// 用于防止重复注册的锁
+	slowRequestMetricsOnce sync.Once
+	// 慢请求计数器
+	slowRequestTotal *prometheus.CounterVec
+)
+
+// initSlowRequestMetrics 初始化慢请求指标
+func initSlowRequestMetrics() {
+	slowRequestMetricsOnce.Do(func() {
+		slowRequestTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_slow_requests_total",
+				Help: "Total number of requests exceeding the slow request threshold",
+			},
+			[]string{"service", "method"},
+		)
+	})
+}
+
+// SlowRequestConfig 慢请求检测配置
+type SlowRequestConfig struct {
+	// Threshold 请求耗时超过该值时采集诊断信息，默认 1s
+	Threshold time.Duration
+	// MaxCapturesPerMinute 每分钟最多采集次数，避免持续变慢时堆栈转储拖慢服务，默认 10
+	MaxCapturesPerMinute int
+	// PoolStats 连接池统计来源（如 connection.NewPoolUsage），设置后诊断信息包含连接池统计
+	PoolStats PoolStatsProvider
+	// Handler 诊断信息处理函数，为空时写入日志
+	Handler DiagnosticsHandler
+}
+
+// Diagnostics 慢请求诊断信息
+type Diagnostics struct {
+	Service    string        `json:"service"`
+	Method     string        `json:"method"`
+	TraceID    string        `json:"trace_id,omitempty"`
+	Elapsed    time.Duration `json:"elapsed"`
+	CapturedAt time.Time     `json:"captured_at"`
+	// Spans 采集时该追踪已产生的 span，按调用树深度优先排列
+	Spans []SpanSnapshot `json:"spans,omitempty"`
+	// Goroutine 处理请求的 goroutine 在采集时的堆栈
+	Goroutine string `json:"goroutine,omitempty"`
+	// PoolStats 采集时的连接池统计
+	PoolStats *PoolStats `json:"pool_stats,omitempty"`
+}
+
+// PoolStatsProvider 连接池统计来源，connection.PoolUsage 满足该接口
+type PoolStatsProvider interface {
+	PoolStats() (active, idle, max int)
+}
+
+// PoolStats 采集时的连接池统计
+type PoolStats struct {
+	ActiveConnections int `json:"active_connections"`
+	IdleConnections   int `json:"idle_connections"`
+	MaxConnections    int `json:"max_connections"`
+}
+
+// SpanSnapshot 采集时的 span 状态
+type SpanSnapshot struct {
+	Name     string        `json:"name"`
+	SpanID   string        `json:"span_id"`
+	ParentID string        `json:"parent_id,omitempty"`
+	Depth    int           `json:"depth"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Running 采集时 span 尚未结束，Duration 为截至采集时的耗时
+	Running bool `json:"running"`
+}
+
+// DiagnosticsHandler 诊断信息处理函数
+type DiagnosticsHandler func(ctx context.Context, diagnostics *Diagnostics)
+
+// SlowRequestDetector 慢请求检测器
+//
+// 请求耗时超过阈值时，在请求仍在处理期间采集 span 树、处理 goroutine 的堆栈和连接池统计，
+// 用于定位偶发的长尾延迟
+type SlowRequestDetector struct {
+	threshold   time.Duration
+	maxCaptures int
+	poolStats   PoolStatsProvider
+	handler     DiagnosticsHandler
+	recorder    *DiagnosticSpanRecorder
+
+	mu          sync.Mutex
+	windowStart time.Time
+	captures    int
+}
+
+// NewSlowRequestDetector 创建慢请求检测器
+func NewSlowRequestDetector(logger Logger, config *SlowRequestConfig) (*SlowRequestDetector, error) {
+	if config == nil {
+		return nil, fmt.Errorf("slow request config cannot be nil")
+	}
+
+	threshold := config.Threshold
+	if threshold <= 0 {
+		threshold = DefaultSlowRequestThreshold
+	}
+
+	maxCaptures := config.MaxCapturesPerMinute
+	if maxCaptures <= 0 {
+		maxCaptures = DefaultSlowCapturesPerMinute
+	}
+
+	handler := config.Handler
+	if handler == nil {
+		if logger == nil {
+			return nil, fmt.Errorf("logger cannot be nil when no diagnostics handler is set")
+		}
+		handler = NewLogDiagnosticsHandler(logger)
+	}
+
+	initSlowRequestMetrics()
+
+	return &SlowRequestDetector{
+		threshold:   threshold,
+		maxCaptures: maxCaptures,
+		poolStats:   config.PoolStats,
+		handler:     handler,
+		recorder:    NewDiagnosticSpanRecorder(),
+	}, nil
+}
+
+// SpanRecorder 返回记录慢请求 span 的处理器，需注册到 TracerProvider 才能采集 span 树
+func (d *SlowRequestDetector) SpanRecorder() *DiagnosticSpanRecorder {
+	return d.recorder
+}
+
+// Track 开始跟踪一个请求，请求结束时调用返回的函数
+//
+// 必须在处理请求的 goroutine 中调用，以便采集该 goroutine 的堆栈：
+//
+//	defer detector.Track(ctx, "order-service", "createOrder")()
+func (d *SlowRequestDetector) Track(ctx context.Context, service, method string) func() {
+	start := time.Now()
+	goroutineID := currentGoroutineID()
+
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	if traceID.IsValid() {
+		d.recorder.watch(traceID, trace.SpanFromContext(ctx))
+	}
+
+	timer := time.AfterFunc(d.threshold, func() {
+		d.capture(ctx, service, method, start, goroutineID)
+	})
+
+	return func() {
+		timer.Stop()
+		if traceID.IsValid() {
+			d.recorder.unwatch(traceID)
+		}
+	}
+}
+
+// capture 采集并处理诊断信息
+func (d *SlowRequestDetector) capture(ctx context.Context, service, method string, start time.Time, goroutineID string) {
+	slowRequestTotal.WithLabelValues(service, method).Inc()
+
+	if !d.allowCapture() {
+		return
+	}
+
+	now := time.Now()
+	diagnostics := &Diagnostics{
+		Service:    service,
+		Method:     method,
+		Elapsed:    now.Sub(start),
+		CapturedAt: now,
+		Goroutine:  goroutineStack(goroutineID),
+	}
+
+	if traceID := trace.SpanContextFromContext(ctx).TraceID(); traceID.IsValid() {
+		diagnostics.TraceID = traceID.String()
+		diagnostics.Spans = d.recorder.snapshot(traceID, now)
+	}
+
+	if d.poolStats != nil {
+		active, idle, max := d.poolStats.PoolStats()
+		diagnostics.PoolStats = &PoolStats{ActiveConnections: active, IdleConnections: idle, MaxConnections: max}
+	}
+
+	d.handler(ctx, diagnostics)
+}
+
+// allowCapture 限制每分钟的采集次数
+func (d *SlowRequestDetector) allowCapture() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(d.windowStart) >= time.Minute {
+		d.windowStart = now
+		d.captures = 0
+	}
+
+	if d.captures >= d.maxCaptures {
+		return false
+	}
+	d.captures++
+	return true
+}
+
+// NewLogDiagnosticsHandler 创建将诊断信息写入日志的处理函数
+func NewLogDiagnosticsHandler(logger Logger) DiagnosticsHandler {
+	return func(ctx context.Context, diagnostics *Diagnostics) {
+		fields := []Field{
+			{Key: "service", Value: diagnostics.Service},
+			{Key: "method", Value: diagnostics.Method},
+			{Key: "elapsed", Value: diagnostics.Elapsed.String()},
+		}
+		if len(diagnostics.Spans) > 0 {
+			fields = append(fields, Field{Key: "spans", Value: formatSpanTree(diagnostics.Spans)})
+		}
+		if diagnostics.PoolStats != nil {
+			fields = append(fields, Field{Key: "pool", Value: fmt.Sprintf("%d active, %d idle, %d max",
+				diagnostics.PoolStats.ActiveConnections, diagnostics.PoolStats.IdleConnections, diagnostics.PoolStats.MaxConnections)})
+		}
+		if diagnostics.Goroutine != "" {
+			fields = append(fields, Field{Key: "goroutine", Value: diagnostics.Goroutine})
+		}
+		logger.Warn(ctx, "Slow request detected", fields...)
+	}
+}
+
+// formatSpanTree 将 span 树格式化为缩进文本
+func formatSpanTree(spans []SpanSnapshot) string {
+	var b strings.Builder
+	for _, span := range spans {
+		b.WriteString(strings.Repeat("  ", span.Depth))
+		b.WriteString(span.Name)
+		b.WriteString(" ")
+		b.WriteString(span.Duration.String())
+		if span.Running {
+			b.WriteString(" (running)")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// DiagnosticSpanRecorder 记录被跟踪请求的 span，实现 sdktrace.SpanProcessor
+//
+// 只记录 SlowRequestDetector 正在跟踪的追踪，请求结束后释放
+type DiagnosticSpanRecorder struct {
+	mu     sync.Mutex
+	traces map[trace.TraceID]*recordedTrace
+}
+
+// recordedTrace 单个追踪已记录的 span
+type recordedTrace struct {
+	refs  int
+	spans []sdktrace.ReadOnlySpan
+}
+
+// NewDiagnosticSpanRecorder 创建诊断 span 记录器
+func NewDiagnosticSpanRecorder() *DiagnosticSpanRecorder {
+	return &DiagnosticSpanRecorder{
+		traces: make(map[trace.TraceID]*recordedTrace),
+	}
+}
+
+// OnStart 实现 sdktrace.SpanProcessor
+func (r *DiagnosticSpanRecorder) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded, ok := r.traces[s.SpanContext().TraceID()]
+	if !ok || len(recorded.spans) >= maxDiagnosticSpansPerTrace {
+		return
+	}
+	recorded.spans = append(recorded.spans, s)
+}
+
+// OnEnd 实现 sdktrace.SpanProcessor，结束时间在快照时从 span 读取
+func (r *DiagnosticSpanRecorder) OnEnd(s sdktrace.ReadOnlySpan) {}
+
+// Shutdown 实现 sdktrace.SpanProcessor
+func (r *DiagnosticSpanRecorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// ForceFlush 实现 sdktrace.SpanProcessor
+func (r *DiagnosticSpanRecorder) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// watch 开始记录追踪的 span，current 为开始跟踪时的当前 span（通常是请求的服务端 span）
+func (r *DiagnosticSpanRecorder) watch(traceID trace.TraceID, current trace.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded, ok := r.traces[traceID]
+	if !ok {
+		recorded = &recordedTrace{}
+		// 当前 span 在跟踪开始前已创建，OnStart 不会记录
+		if span, ok := current.(sdktrace.ReadOnlySpan); ok {
+			recorded.spans = append(recorded.spans, span)
+		}
+		r.traces[traceID] = recorded
+	}
+	recorded.refs++
+}
+
+// unwatch 停止记录追踪的 span，最后一个跟踪者结束时释放
+func (r *DiagnosticSpanRecorder) unwatch(traceID trace.TraceID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded, ok := r.traces[traceID]
+	if !ok {
+		return
+	}
+	recorded.refs--
+	if recorded.refs <= 0 {
+		delete(r.traces, traceID)
+	}
+}
+
+// snapshot 返回追踪当前的 span 树
+func (r *DiagnosticSpanRecorder) snapshot(traceID trace.TraceID, now time.Time) []SpanSnapshot {
+	r.mu.Lock()
+	var spans []sdktrace.ReadOnlySpan
+	if recorded, ok := r.traces[traceID]; ok {
+		spans = append(spans, recorded.spans...)
+	}
+	r.mu.Unlock()
+
+	return buildSpanTree(spans, now)
+}
+
+// buildSpanTree 按父子关系深度优先排列 span，同级按开始时间排序
+func buildSpanTree(spans []sdktrace.ReadOnlySpan, now time.Time) []SpanSnapshot {
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].StartTime().Before(spans[j].StartTime())
+	})
+
+	known := make(map[trace.SpanID]bool, len(spans))
+	for _, s := range spans {
+		known[s.SpanContext().SpanID()] = true
+	}
+
+	children := make(map[trace.SpanID][]sdktrace.ReadOnlySpan)
+	var roots []sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		parent := s.Parent().SpanID()
+		if parent.IsValid() && known[parent] {
+			children[parent] = append(children[parent], s)
+		} else {
+			roots = append(roots, s)
+		}
+	}
+
+	result := make([]SpanSnapshot, 0, len(spans))
+	var visit func(s sdktrace.ReadOnlySpan, depth int)
+	visit = func(s sdktrace.ReadOnlySpan, depth int) {
+		snapshot := SpanSnapshot{
+			Name:   s.Name(),
+			SpanID: s.SpanContext().SpanID().String(),
+			Depth:  depth,
+			Start:  s.StartTime(),
+		}
+		if parent := s.Parent().SpanID(); parent.IsValid() {
+			snapshot.ParentID = parent.String()
+		}
+		if end := s.EndTime(); end.IsZero() {
+			snapshot.Running = true
+			snapshot.Duration = now.Sub(s.StartTime())
+		} else {
+			snapshot.Duration = end.Sub(s.StartTime())
+		}
+		result = append(result, snapshot)
+
+		for _, child := range children[s.SpanContext().SpanID()] {
+			visit(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		visit(root, 0)
+	}
+	return result
+}
+
+// currentGoroutineID 返回当前 goroutine 的 ID
+func currentGoroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// 格式: goroutine 123 [running]:
+	fields := bytes.Fields(buf)
+	if len(fields) < 2 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(string(fields[1]), 10, 64); err != nil {
+		return ""
+	}
+	return string(fields[1])
+}
+
+// goroutineStack 返回指定 goroutine 的堆栈，goroutine 已退出时返回空字符串
+func goroutineStack(goroutineID string) string {
+	if goroutineID == "" {
+		return ""
+	}
+
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDumpSize {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	prefix := "goroutine " + goroutineID + " ["
+	for _, block := range strings.Split(string(buf), "\n\n") {
+		if strings.HasPrefix(block, prefix) {
+			return block
+		}
+	}
+	return ""
+}
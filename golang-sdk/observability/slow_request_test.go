@@ -0,0 +1,148 @@
+package observability
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slowHandler 模拟处理缓慢的请求，在等待 release 期间有一个未结束的 span
+func slowHandler(ctx context.Context, detector *SlowRequestDetector, tracer trace.Tracer, release <-chan struct{}) {
+	defer detector.Track(ctx, "order-service", "createOrder")()
+
+	_, routed := tracer.Start(ctx, "router.Route")
+	routed.End()
+
+	_, pending := tracer.Start(ctx, "connection.Acquire")
+	<-release
+	pending.End()
+}
+
+// stubPoolStats 返回固定值的连接池统计来源
+type stubPoolStats struct {
+	active, idle, max int
+}
+
+func (s stubPoolStats) PoolStats() (int, int, int) {
+	return s.active, s.idle, s.max
+}
+
+func TestSlowRequestDetector(t *testing.T) {
+	captured := make(chan *Diagnostics, 1)
+	detector, err := NewSlowRequestDetector(nil, &SlowRequestConfig{
+		Threshold: 20 * time.Millisecond,
+		PoolStats: stubPoolStats{active: 4, max: 10},
+		Handler: func(ctx context.Context, diagnostics *Diagnostics) {
+			captured <- diagnostics
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSlowRequestDetector failed: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(detector.SpanRecorder()))
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "order-service/createOrder")
+	defer root.End()
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		slowHandler(ctx, detector, tracer, release)
+		close(done)
+	}()
+
+	var diagnostics *Diagnostics
+	select {
+	case diagnostics = <-captured:
+	case <-time.After(time.Second):
+		t.Fatal("Slow request was not captured")
+	}
+	close(release)
+	<-done
+
+	if diagnostics.Elapsed < 20*time.Millisecond {
+		t.Errorf("Elapsed = %v, want >= 20ms", diagnostics.Elapsed)
+	}
+	if !strings.Contains(diagnostics.Goroutine, "slowHandler") {
+		t.Errorf("Goroutine stack does not contain the handler:\n%s", diagnostics.Goroutine)
+	}
+	if diagnostics.PoolStats == nil || diagnostics.PoolStats.ActiveConnections != 4 {
+		t.Errorf("Unexpected pool stats: %+v", diagnostics.PoolStats)
+	}
+
+	// span 树：根 span 下有两个子 span，未结束的标记为 running
+	if len(diagnostics.Spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %+v", diagnostics.Spans)
+	}
+	if diagnostics.Spans[0].Depth != 0 || !diagnostics.Spans[0].Running {
+		t.Errorf("Unexpected root span: %+v", diagnostics.Spans[0])
+	}
+	if diagnostics.Spans[1].Name != "router.Route" || diagnostics.Spans[1].Depth != 1 || diagnostics.Spans[1].Running {
+		t.Errorf("Unexpected child span: %+v", diagnostics.Spans[1])
+	}
+	if diagnostics.Spans[2].Name != "connection.Acquire" || !diagnostics.Spans[2].Running {
+		t.Errorf("Unexpected pending span: %+v", diagnostics.Spans[2])
+	}
+
+	// 请求结束后释放记录的 span
+	if len(detector.SpanRecorder().traces) != 0 {
+		t.Error("Recorded spans should be released after the request completes")
+	}
+}
+
+func TestSlowRequestDetectorFastRequest(t *testing.T) {
+	captured := make(chan *Diagnostics, 1)
+	detector, err := NewSlowRequestDetector(nil, &SlowRequestConfig{
+		Threshold: 50 * time.Millisecond,
+		Handler: func(ctx context.Context, diagnostics *Diagnostics) {
+			captured <- diagnostics
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSlowRequestDetector failed: %v", err)
+	}
+
+	detector.Track(context.Background(), "order-service", "getOrder")()
+
+	select {
+	case <-captured:
+		t.Error("Fast request should not be captured")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSlowRequestDetectorCaptureLimit(t *testing.T) {
+	detector, err := NewSlowRequestDetector(nil, &SlowRequestConfig{
+		MaxCapturesPerMinute: 2,
+		Handler:              func(ctx context.Context, diagnostics *Diagnostics) {},
+	})
+	if err != nil {
+		t.Fatalf("NewSlowRequestDetector failed: %v", err)
+	}
+
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if detector.allowCapture() {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed = %d, want 2", allowed)
+	}
+}
+
+func TestNewSlowRequestDetectorInvalidConfig(t *testing.T) {
+	if _, err := NewSlowRequestDetector(NewLogger("test-service"), nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+	if _, err := NewSlowRequestDetector(nil, &SlowRequestConfig{}); err == nil {
+		t.Error("Expected error without logger or handler")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
 )
 
 // ProtocolType 协议类型
@@ -15,6 +17,7 @@ const (
 	ProtocolWebSocket ProtocolType = "WebSocket"
 	ProtocolJSONRPC   ProtocolType = "JSON-RPC"
 	ProtocolMQTT      ProtocolType = "MQTT"
+	ProtocolKafka     ProtocolType = "Kafka"
 
 	// 内部协议
 	ProtocolGRPC         ProtocolType = "gRPC"
@@ -22,6 +25,18 @@ const (
 	ProtocolCustomBinary ProtocolType = "CustomBinary"
 )
 
+// 租户标识的请求头和元数据键
+const (
+	HeaderTenantID   = "X-Tenant-Id"
+	MetadataTenantID = "tenant_id"
+)
+
+// 内部响应元数据中的服务名和方法名，用于响应转换指标的标签
+const (
+	MetadataService = "service"
+	MetadataMethod  = "method"
+)
+
 // ExternalRequest 外部协议请求
 type ExternalRequest struct {
 	Protocol  ProtocolType       // 协议类型
@@ -71,6 +86,9 @@ type RequestMetadata struct {
 }
 
 // FrameworkError 框架错误
+//
+// 与 errors.FrameworkError 使用同一套错误码，可通过 ToUnifiedError / FromUnifiedError 互相转换，
+// errors.Is 和 errors.As 在两种类型之间同样适用
 type FrameworkError struct {
 	Code       ErrorCode         // 错误码
 	Message    string            // 错误消息
@@ -79,29 +97,32 @@ type FrameworkError struct {
 	StackTrace []string          // 堆栈追踪
 	Timestamp  int64             // 发生时间
 	ServiceId  string            // 发生服务
+
+	origin *frameworkerrors.FrameworkError // 由 FromUnifiedError 转换时的原错误
 }
 
-// ErrorCode 错误码
-type ErrorCode int
+// ErrorCode 错误码，与 errors 包共用同一套错误码
+type ErrorCode = frameworkerrors.ErrorCode
 
 const (
 	// 客户端错误 (4xx)
-	ErrorBadRequest    ErrorCode = 400
-	ErrorUnauthorized  ErrorCode = 401
-	ErrorForbidden     ErrorCode = 403
-	ErrorNotFound      ErrorCode = 404
-	ErrorTimeout       ErrorCode = 408
+	ErrorBadRequest    = frameworkerrors.BadRequest
+	ErrorUnauthorized  = frameworkerrors.Unauthorized
+	ErrorForbidden     = frameworkerrors.Forbidden
+	ErrorNotFound      = frameworkerrors.NotFound
+	ErrorTimeout       = frameworkerrors.Timeout
+	ErrorClientClosedRequest = frameworkerrors.ClientClosedRequest
 
 	// 服务端错误 (5xx)
-	ErrorInternal         ErrorCode = 500
-	ErrorNotImplemented   ErrorCode = 501
-	ErrorServiceUnavailable ErrorCode = 503
+	ErrorInternal         = frameworkerrors.InternalError
+	ErrorNotImplemented   = frameworkerrors.NotImplemented
+	ErrorServiceUnavailable = frameworkerrors.ServiceUnavailable
 
 	// 框架错误 (6xx)
-	ErrorProtocol       ErrorCode = 600
-	ErrorSerialization  ErrorCode = 601
-	ErrorRouting        ErrorCode = 602
-	ErrorConnection     ErrorCode = 603
+	ErrorProtocol       = frameworkerrors.ProtocolError
+	ErrorSerialization  = frameworkerrors.SerializationError
+	ErrorRouting        = frameworkerrors.RoutingError
+	ErrorConnection     = frameworkerrors.ConnectionError
 )
 
 // Error 实现 error 接口
@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+)
+
+// benchmarkExternalRequest 返回基准测试使用的 REST 请求
+func benchmarkExternalRequest() *ExternalRequest {
+	return &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user",
+			"X-Method-Name":  "get",
+			"traceparent":    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"Content-Type":   "application/json",
+		},
+		Body: map[string]interface{}{"id": 42, "fields": []string{"name", "email"}},
+		Metadata: &RequestMetadata{
+			RequestId:  "req-1",
+			ClientAddr: "127.0.0.1:52000",
+		},
+	}
+}
+
+// BenchmarkTransformRequest 外部请求转换为内部请求的基准测试
+func BenchmarkTransformRequest(b *testing.B) {
+	a := NewDefaultProtocolAdapter()
+	external := benchmarkExternalRequest()
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.TransformRequest(ctx, external); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransformResponse 内部响应转换为外部响应的基准测试
+func BenchmarkTransformResponse(b *testing.B) {
+	a := NewDefaultProtocolAdapter()
+	internal := &InternalResponse{
+		Payload:  []byte(`{"id":42,"name":"alice","email":"alice@example.com"}`),
+		Headers:  map[string]string{"Content-Type": "application/json"},
+		Metadata: map[string]string{"request_id": "req-1"},
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := a.TransformResponse(ctx, internal, ProtocolREST); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransformRequestParallel 并发转换请求的基准测试
+func BenchmarkTransformRequestParallel(b *testing.B) {
+	a := NewDefaultProtocolAdapter()
+	external := benchmarkExternalRequest()
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := a.TransformRequest(ctx, external); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
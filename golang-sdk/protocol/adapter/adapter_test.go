@@ -3,7 +3,11 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
 )
 
 func TestDefaultProtocolAdapter_TransformRequest_REST(t *testing.T) {
@@ -134,6 +138,37 @@ func TestDefaultProtocolAdapter_TransformRequest_MQTT(t *testing.T) {
 	}
 }
 
+func TestDefaultProtocolAdapter_TransformRequest_Kafka(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	ctx := context.Background()
+
+	// Kafka 处理器按主题路由写入服务名和方法名
+	external := &ExternalRequest{
+		Protocol: ProtocolKafka,
+		Headers: map[string]string{
+			"X-Service-Name": "billing",
+			"X-Method-Name":  "charge",
+		},
+		Body: []byte(`{"orderId":"o-1"}`),
+	}
+
+	internal, err := adapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if internal.Service != "billing" || internal.Method != "charge" {
+		t.Errorf("Expected billing.charge, got %s.%s", internal.Service, internal.Method)
+	}
+	if string(internal.Payload) != `{"orderId":"o-1"}` {
+		t.Errorf("Expected record value as payload, got %s", internal.Payload)
+	}
+
+	// 未路由的记录
+	if _, err := adapter.TransformRequest(ctx, &ExternalRequest{Protocol: ProtocolKafka, Body: []byte("{}")}); err == nil {
+		t.Error("Expected error for Kafka record without route")
+	}
+}
+
 func TestDefaultProtocolAdapter_TransformResponse_Success(t *testing.T) {
 	adapter := NewDefaultProtocolAdapter()
 	ctx := context.Background()
@@ -194,6 +229,102 @@ func TestDefaultProtocolAdapter_TransformResponse_Error(t *testing.T) {
 	if external.Error == nil {
 		t.Error("Error should not be nil")
 	}
+
+	// 响应体为跨语言传输格式的结构化错误
+	data, err := json.Marshal(external.Body)
+	if err != nil {
+		t.Fatalf("Marshal body failed: %v", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("Unmarshal body failed: %v", err)
+	}
+	if body["code"] != float64(404) || body["error"] != "Not Found" || body["message"] != "User not found" || body["retryable"] != false {
+		t.Errorf("Unexpected error body: %v", body)
+	}
+}
+
+func TestDefaultProtocolAdapter_TransformResponse_CustomErrorCode(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	const quotaExceeded ErrorCode = 1201
+	if err := frameworkerrors.RegisterCode(frameworkerrors.CodeDefinition{
+		Code:       quotaExceeded,
+		Name:       "Quota Exceeded",
+		Retryable:  true,
+		HTTPStatus: 429,
+	}); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+
+	// 状态码和可重试性使用注册时的声明
+	external, err := adapter.TransformResponse(context.Background(), &InternalResponse{
+		Error: &FrameworkError{Code: quotaExceeded, Message: "daily quota exceeded"},
+	}, ProtocolREST)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	if external.StatusCode != 429 {
+		t.Errorf("Expected status code 429, got %d", external.StatusCode)
+	}
+	payload := external.Body.(*frameworkerrors.ErrorPayload)
+	if payload.Error != "Quota Exceeded" || !payload.Retryable {
+		t.Errorf("Unexpected error body: %+v", payload)
+	}
+
+	// 内置框架错误码同样按错误码定义映射
+	external, _ = adapter.TransformResponse(context.Background(), &InternalResponse{
+		Error: &FrameworkError{Code: ErrorConnection, Message: "connection refused"},
+	}, ProtocolREST)
+	if external.StatusCode != 503 {
+		t.Errorf("Expected status code 503, got %d", external.StatusCode)
+	}
+}
+
+func TestDefaultProtocolAdapter_TransformResponse_JSONRPCError(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	ctx := context.Background()
+
+	internal := &InternalResponse{
+		Error: &FrameworkError{
+			Code:    ErrorTimeout,
+			Message: "upstream timeout",
+			Details: map[string]interface{}{"timeoutMs": 3000},
+		},
+	}
+
+	external, err := adapter.TransformResponse(ctx, internal, ProtocolJSONRPC)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+
+	data, err := json.Marshal(external.Body)
+	if err != nil {
+		t.Fatalf("Marshal body failed: %v", err)
+	}
+	var body struct {
+		Error struct {
+			Code    int             `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("Unmarshal body failed: %v", err)
+	}
+	if body.Error.Code != 408 || body.Error.Message != "upstream timeout" {
+		t.Errorf("Unexpected error: %+v", body.Error)
+	}
+
+	// data 可还原为框架错误，非字符串详情保存在 details 字段中
+	fe, err := frameworkerrors.UnmarshalError(body.Error.Data)
+	if err != nil {
+		t.Fatalf("UnmarshalError failed: %v", err)
+	}
+	restored := FromUnifiedError(fe)
+	if restored.Code != ErrorTimeout || !reflect.DeepEqual(restored.Details, map[string]interface{}{"timeoutMs": float64(3000)}) {
+		t.Errorf("Unexpected restored error: %+v", restored)
+	}
 }
 
 func TestDefaultProtocolAdapter_TransformResponse_JSONRPC(t *testing.T) {
@@ -244,6 +375,7 @@ func TestDefaultProtocolAdapter_GetSupportedProtocols(t *testing.T) {
 		ProtocolWebSocket,
 		ProtocolJSONRPC,
 		ProtocolMQTT,
+		ProtocolKafka,
 		ProtocolGRPC,
 		ProtocolInternalRPC,
 		ProtocolCustomBinary,
@@ -284,3 +416,105 @@ func TestDefaultProtocolAdapter_TransformResponse_NilResponse(t *testing.T) {
 		t.Error("Should return error for nil response")
 	}
 }
+
+func TestDefaultProtocolAdapter_TransformRequest_TenantID(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	ctx := context.Background()
+
+	// 调用方在请求头和元数据中携带的租户标识被丢弃
+	external := &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "getUser",
+			HeaderTenantID:   "tenant-b",
+		},
+		Metadata: &RequestMetadata{Extra: map[string]string{MetadataTenantID: "tenant-b"}},
+	}
+
+	internal, err := adapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if tenantID, ok := internal.Metadata[MetadataTenantID]; ok {
+		t.Errorf("Expected no tenant without security context, got '%s'", tenantID)
+	}
+
+	// 租户标识取自认证后的安全上下文
+	ctx = WithSecurityContext(ctx, &SecurityContext{UserID: "user-1", TenantID: "tenant-a"})
+	internal, err = adapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if internal.Metadata[MetadataTenantID] != "tenant-a" {
+		t.Errorf("Expected tenant 'tenant-a', got '%s'", internal.Metadata[MetadataTenantID])
+	}
+}
+
+func TestDefaultProtocolAdapter_PortableEncoding(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	adapter.SetEncodingProfile(EncodingPortable)
+	ctx := context.Background()
+
+	// 请求体中的 int64、时间和字节数组按跨语言编码序列化
+	external := &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "order-service",
+			"X-Method-Name":  "create",
+		},
+		Body: map[string]interface{}{
+			"orderId":   int64(9007199254740993),
+			"createdAt": time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+			"raw":       []byte("ok"),
+		},
+	}
+
+	internal, err := adapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+
+	expected := `{"createdAt":"2024-03-01T10:30:00Z","orderId":"9007199254740993","raw":"b2s="}`
+	if string(internal.Payload) != expected {
+		t.Errorf("Expected payload %s, got %s", expected, internal.Payload)
+	}
+	if internal.Metadata[MetadataEncoding] != string(EncodingPortable) {
+		t.Errorf("Expected encoding metadata '%s', got '%s'", EncodingPortable, internal.Metadata[MetadataEncoding])
+	}
+
+	// 响应体中的大整数不因转换为 float64 丢失精度
+	response, err := adapter.TransformResponse(ctx, &InternalResponse{Payload: []byte(`{"total":9007199254740993}`)}, ProtocolREST)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	body, _ := json.Marshal(response.Body)
+	if string(body) != `{"total":9007199254740993}` {
+		t.Errorf("Expected precise response body, got %s", body)
+	}
+}
+
+func TestDefaultProtocolAdapter_StandardEncodingByDefault(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	if adapter.GetEncodingProfile() != EncodingStandard {
+		t.Errorf("Expected default encoding '%s', got '%s'", EncodingStandard, adapter.GetEncodingProfile())
+	}
+
+	internal, err := adapter.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "order-service",
+			"X-Method-Name":  "create",
+		},
+		Body: map[string]interface{}{"orderId": int64(42)},
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if string(internal.Payload) != `{"orderId":42}` {
+		t.Errorf("Expected standard payload, got %s", internal.Payload)
+	}
+	if _, ok := internal.Metadata[MetadataEncoding]; ok {
+		t.Error("Standard encoding should not be recorded in metadata")
+	}
+}
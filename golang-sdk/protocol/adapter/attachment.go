@@ -0,0 +1,595 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"reflect"
+	"strings"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// AttachmentField 附件在 JSON 中以包含该字段的对象表示，字段值为附件名
+//
+// 内联附件的 data 字段为 Base64 编码的内容：
+//
+//	{"$attachment": "photo.png", "contentType": "image/png", "size": 1024, "data": "iVBORw0KGgo..."}
+//
+// multipart 请求中以引用表示，内容在同名的部分中：
+//
+//	{"$attachment": "photo.png", "contentType": "image/png", "size": 1024}
+const AttachmentField = "$attachment"
+
+// AttachmentRequestPart multipart 请求中 JSON-RPC 请求（或响应）所在部分的名称，其他部分为附件
+const AttachmentRequestPart = "request"
+
+// 附件的默认限制
+const (
+	// DefaultMaxAttachmentSize 单个附件的默认字节数上限
+	DefaultMaxAttachmentSize = 32 << 20
+	// DefaultMaxAttachmentsSize 一个请求中所有附件的默认字节数上限
+	DefaultMaxAttachmentsSize = 64 << 20
+	// DefaultAttachmentMemoryThreshold 附件超过该字节数时写入临时文件，不在内存中缓冲
+	DefaultAttachmentMemoryThreshold = 1 << 20
+)
+
+// AttachmentLimits 附件的大小限制和临时文件位置，字段为 0 时使用对应的默认值
+type AttachmentLimits struct {
+	// MaxSize 单个附件的字节数上限，默认 DefaultMaxAttachmentSize
+	MaxSize int64
+	// MaxTotalSize 一个请求中所有附件的字节数上限，默认 DefaultMaxAttachmentsSize
+	MaxTotalSize int64
+	// MemoryThreshold 附件超过该字节数时写入临时文件，默认 DefaultAttachmentMemoryThreshold
+	MemoryThreshold int64
+	// TempDir 临时文件所在目录，为空时使用 os.TempDir
+	TempDir string
+}
+
+// withDefaults 返回填充默认值后的限制
+func (l *AttachmentLimits) withDefaults() AttachmentLimits {
+	var limits AttachmentLimits
+	if l != nil {
+		limits = *l
+	}
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = DefaultMaxAttachmentSize
+	}
+	if limits.MaxTotalSize <= 0 {
+		limits.MaxTotalSize = DefaultMaxAttachmentsSize
+	}
+	if limits.MemoryThreshold <= 0 {
+		limits.MemoryThreshold = DefaultAttachmentMemoryThreshold
+	}
+	return limits
+}
+
+// MaxRequestSize 返回 multipart 请求的字节数上限：所有附件的上限加上请求 JSON 的上限（一个附件的上限）
+func (l *AttachmentLimits) MaxRequestSize() int64 {
+	limits := l.withDefaults()
+	return limits.MaxTotalSize + limits.MaxSize
+}
+
+// Attachment 二进制附件，用于跨语言传递文件、图片等，避免 Base64 内联使 JSON 膨胀三分之一
+//
+// 附件作为参数或结果中的 *Attachment 字段传递：客户端在参数包含附件时以 multipart 发送，
+// 附件在独立的部分中；结果中的附件在客户端接受 multipart 时同样以独立的部分返回，否则以 Base64 内联。
+// 超过 AttachmentLimits.MemoryThreshold 的附件写入临时文件，使用完毕后调用 Close 删除
+type Attachment struct {
+	Name        string
+	ContentType string
+
+	size int64
+	data []byte
+	// path 内容所在的文件，temp 为 true 时为接收时写入的临时文件，Close 时删除
+	path string
+	temp bool
+	// pending 只有引用、尚未绑定到 multipart 部分的附件
+	pending bool
+	// reference 编码 multipart 请求或响应期间为 true，MarshalJSON 只输出引用
+	reference bool
+}
+
+// attachmentJSON 附件的 JSON 表示
+type attachmentJSON struct {
+	Name        string  `json:"$attachment"`
+	ContentType string  `json:"contentType,omitempty"`
+	Size        int64   `json:"size"`
+	Data        *string `json:"data,omitempty"`
+}
+
+// NewAttachment 创建内存中的附件
+func NewAttachment(name, contentType string, data []byte) *Attachment {
+	return &Attachment{Name: name, ContentType: contentType, size: int64(len(data)), data: data}
+}
+
+// NewFileAttachment 创建内容为文件的附件，发送时从文件读取；Close 不删除该文件
+func NewFileAttachment(name, contentType, path string) (*Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("attachment %s: %s is a directory", name, path)
+	}
+	return &Attachment{Name: name, ContentType: contentType, size: info.Size(), path: path}, nil
+}
+
+// Size 返回附件的字节数
+func (a *Attachment) Size() int64 {
+	return a.size
+}
+
+// Open 打开附件内容，调用方负责关闭
+func (a *Attachment) Open() (io.ReadCloser, error) {
+	if a.pending {
+		return nil, fmt.Errorf("attachment %s is not bound to a multipart part", a.Name)
+	}
+	if a.path != "" {
+		return os.Open(a.path)
+	}
+	return io.NopCloser(bytes.NewReader(a.data)), nil
+}
+
+// Bytes 读取附件的全部内容
+func (a *Attachment) Bytes() ([]byte, error) {
+	if a.path == "" && !a.pending {
+		return a.data, nil
+	}
+	reader, err := a.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// Close 删除接收时写入的临时文件，内存中的附件和 NewFileAttachment 创建的附件无需关闭
+func (a *Attachment) Close() error {
+	if !a.temp {
+		return nil
+	}
+	a.temp = false
+	if err := os.Remove(a.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// MarshalJSON 以 Base64 内联附件内容，编码 multipart 请求或响应时只输出引用
+func (a *Attachment) MarshalJSON() ([]byte, error) {
+	value := attachmentJSON{Name: a.Name, ContentType: a.ContentType, Size: a.size}
+	if !a.reference {
+		data, err := a.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(data)
+		value.Data = &encoded
+	}
+	return json.Marshal(value)
+}
+
+// UnmarshalJSON 解码内联附件的内容；引用由 Attachments.Bind 绑定到 multipart 部分
+func (a *Attachment) UnmarshalJSON(data []byte) error {
+	var value attachmentJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value.Name == "" {
+		return fmt.Errorf("attachment must be an object with a non-empty %s field", AttachmentField)
+	}
+	*a = Attachment{Name: value.Name, ContentType: value.ContentType, size: value.Size, pending: true}
+	if value.Data != nil {
+		decoded, err := base64.StdEncoding.DecodeString(*value.Data)
+		if err != nil {
+			return fmt.Errorf("attachment %s: invalid base64 data: %w", value.Name, err)
+		}
+		a.data, a.size, a.pending = decoded, int64(len(decoded)), false
+	}
+	return nil
+}
+
+// Attachments 一个 multipart 请求（或响应）中的附件，按部分名称索引
+type Attachments struct {
+	limits AttachmentLimits
+	items  map[string]*Attachment
+	names  []string
+}
+
+// NewAttachments 创建空的附件集合，limits 为 nil 时使用默认限制
+func NewAttachments(limits *AttachmentLimits) *Attachments {
+	return &Attachments{limits: limits.withDefaults(), items: make(map[string]*Attachment)}
+}
+
+// Get 返回指定名称的附件，不存在时返回 nil
+func (s *Attachments) Get(name string) *Attachment {
+	if s == nil {
+		return nil
+	}
+	return s.items[name]
+}
+
+// Names 按接收顺序返回附件名称
+func (s *Attachments) Names() []string {
+	if s == nil {
+		return nil
+	}
+	return s.names
+}
+
+// Close 删除所有附件的临时文件
+func (s *Attachments) Close() error {
+	return s.CloseExcept(nil)
+}
+
+// CloseExcept 删除 keep 以外的附件的临时文件，keep 中的附件由调用方关闭
+func (s *Attachments) CloseExcept(keep []*Attachment) error {
+	if s == nil {
+		return nil
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, attachment := range keep {
+		kept[attachment.Name] = true
+	}
+	var errs []error
+	for name, attachment := range s.items {
+		if !kept[name] {
+			errs = append(errs, attachment.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Bind 将 v 中引用 multipart 部分的附件绑定到对应的内容，并检查内联附件的大小；v 通常为解码后的参数指针
+//
+// 绑定的附件调用 Close 时同样删除临时文件。
+// 引用的附件不存在或内联附件超过 MaxSize 时返回 BadRequest
+func (s *Attachments) Bind(v interface{}) error {
+	limits := (*AttachmentLimits)(nil).withDefaults()
+	if s != nil {
+		limits = s.limits
+	}
+	var err error
+	walkAttachments(reflect.ValueOf(v), func(a *Attachment) {
+		if err != nil {
+			return
+		}
+		if !a.pending {
+			if a.size > limits.MaxSize {
+				err = attachmentTooLarge(a.Name, limits.MaxSize)
+			}
+			return
+		}
+		part := s.Get(a.Name)
+		if part == nil {
+			err = frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+				fmt.Sprintf("attachment %s not found in request", a.Name))
+			return
+		}
+		// 绑定后的附件与所属的 Attachments 都可以删除临时文件，重复删除被忽略
+		contentType := a.ContentType
+		*a = *part
+		if contentType != "" && a.ContentType == "" {
+			a.ContentType = contentType
+		}
+	})
+	return err
+}
+
+// attachmentsKey 附件集合在 context 中的键
+type attachmentsKey struct{}
+
+// WithAttachments 将请求的附件集合放入 context，供业务方法通过 ResolveAttachment 或 BindAttachments 读取
+func WithAttachments(ctx context.Context, attachments *Attachments) context.Context {
+	return context.WithValue(ctx, attachmentsKey{}, attachments)
+}
+
+// AttachmentsFromContext 返回请求的附件集合，不存在时返回 nil
+func AttachmentsFromContext(ctx context.Context) *Attachments {
+	attachments, _ := ctx.Value(attachmentsKey{}).(*Attachments)
+	return attachments
+}
+
+// BindAttachments 按 context 中请求的附件集合绑定 v 中的附件，见 Attachments.Bind
+func BindAttachments(ctx context.Context, v interface{}) error {
+	return AttachmentsFromContext(ctx).Bind(v)
+}
+
+// ResolveAttachment 将解码为 map 的附件参数（如 params["photo"]）转换为附件，
+// 内联附件解码其内容，引用从 context 中请求的附件集合查找；value 不是附件时返回 BadRequest
+func ResolveAttachment(ctx context.Context, value interface{}) (*Attachment, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok || object[AttachmentField] == nil {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+			fmt.Sprintf("attachment must be an object with a %s field", AttachmentField))
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid attachment")
+	}
+	attachment := &Attachment{}
+	if err := json.Unmarshal(data, attachment); err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid attachment")
+	}
+	if err := BindAttachments(ctx, attachment); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// CollectAttachments 返回 v 中所有的 *Attachment（包括结构体字段、map 值和切片元素），按名称去重
+func CollectAttachments(v interface{}) []*Attachment {
+	var attachments []*Attachment
+	seen := make(map[string]bool)
+	walkAttachments(reflect.ValueOf(v), func(a *Attachment) {
+		if !seen[a.Name] {
+			seen[a.Name] = true
+			attachments = append(attachments, a)
+		}
+	})
+	return attachments
+}
+
+var attachmentType = reflect.TypeOf((*Attachment)(nil))
+
+// maxAttachmentDepth 查找附件时的最大嵌套深度，避免循环引用导致无限递归
+const maxAttachmentDepth = 32
+
+// walkAttachments 对 v 中的每个非 nil *Attachment 调用 fn
+func walkAttachments(v reflect.Value, fn func(*Attachment)) {
+	walkAttachmentsDepth(v, fn, 0)
+}
+
+func walkAttachmentsDepth(v reflect.Value, fn func(*Attachment), depth int) {
+	if !v.IsValid() || depth > maxAttachmentDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if v.Type() == attachmentType {
+			fn(v.Interface().(*Attachment))
+			return
+		}
+		walkAttachmentsDepth(v.Elem(), fn, depth+1)
+	case reflect.Interface:
+		if !v.IsNil() {
+			walkAttachmentsDepth(v.Elem(), fn, depth+1)
+		}
+	case reflect.Struct:
+		if v.Type() == attachmentType.Elem() {
+			if v.CanAddr() {
+				fn(v.Addr().Interface().(*Attachment))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkAttachmentsDepth(v.Field(i), fn, depth+1)
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walkAttachmentsDepth(iter.Value(), fn, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkAttachmentsDepth(v.Index(i), fn, depth+1)
+		}
+	}
+}
+
+// IsMultipart 判断 Content-Type 是否为 multipart，返回其分隔符
+func IsMultipart(contentType string) (boundary string, ok bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// AcceptsMultipart 判断 Accept 请求头是否接受 multipart/form-data 响应
+func AcceptsMultipart(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mediaType == "multipart/form-data" {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadMultipart 读取 multipart 消息，返回名为 AttachmentRequestPart 的部分（JSON-RPC 请求或响应）和其他部分组成的附件集合
+//
+// 附件以部分的表单名称（没有时为文件名）命名；超过 MemoryThreshold 的附件写入临时文件，出错时已写入的临时文件被删除。
+// 单个附件超过 MaxSize、所有附件超过 MaxTotalSize、名称重复或缺少请求部分时返回 BadRequest
+func ReadMultipart(r io.Reader, boundary string, limits *AttachmentLimits) ([]byte, *Attachments, error) {
+	attachments := NewAttachments(limits)
+	payload, err := readMultipart(multipart.NewReader(r, boundary), attachments)
+	if err != nil {
+		attachments.Close()
+		return nil, nil, err
+	}
+	return payload, attachments, nil
+}
+
+func readMultipart(reader *multipart.Reader, attachments *Attachments) ([]byte, error) {
+	limits := attachments.limits
+	var payload []byte
+	var total int64
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "invalid multipart body")
+		}
+
+		name := part.FormName()
+		if name == "" {
+			name = part.FileName()
+		}
+		if name == AttachmentRequestPart {
+			if payload != nil {
+				return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "duplicate request part")
+			}
+			data, err := io.ReadAll(io.LimitReader(part, limits.MaxSize+1))
+			if err != nil {
+				return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, "failed to read request part")
+			}
+			if int64(len(data)) > limits.MaxSize {
+				return nil, attachmentTooLarge(AttachmentRequestPart, limits.MaxSize)
+			}
+			payload = data
+			continue
+		}
+		if name == "" {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "multipart part has no name")
+		}
+		if attachments.items[name] != nil {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, fmt.Sprintf("duplicate attachment %s", name))
+		}
+
+		attachment, err := readPart(part, name, limits, limits.MaxTotalSize-total)
+		if err != nil {
+			return nil, err
+		}
+		total += attachment.size
+		attachments.items[name] = attachment
+		attachments.names = append(attachments.names, name)
+	}
+	if payload == nil {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+			fmt.Sprintf("multipart body has no %q part", AttachmentRequestPart))
+	}
+	return payload, nil
+}
+
+// readPart 读取一个附件部分，remaining 为所有附件还可以使用的字节数
+func readPart(part *multipart.Part, name string, limits AttachmentLimits, remaining int64) (*Attachment, error) {
+	limit := min(limits.MaxSize, remaining)
+	tooLarge := func() error {
+		if limit < limits.MaxSize {
+			return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+				fmt.Sprintf("attachments exceed %d bytes in total", limits.MaxTotalSize))
+		}
+		return attachmentTooLarge(name, limits.MaxSize)
+	}
+	attachment := &Attachment{Name: name, ContentType: part.Header.Get("Content-Type")}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, part, limits.MemoryThreshold+1)
+	if err != nil && err != io.EOF {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("failed to read attachment %s", name))
+	}
+	if n > limit {
+		return nil, tooLarge()
+	}
+	if n <= limits.MemoryThreshold {
+		attachment.data, attachment.size = buf.Bytes(), n
+		return attachment, nil
+	}
+
+	// 超过内存阈值，已读取的部分和剩余内容写入临时文件
+	file, err := os.CreateTemp(limits.TempDir, "attachment-*")
+	if err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.InternalError, "failed to create attachment file")
+	}
+	written, err := io.Copy(file, io.MultiReader(&buf, io.LimitReader(part, limit-n+1)))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || written > limit {
+		os.Remove(file.Name())
+		if err != nil {
+			return nil, frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("failed to read attachment %s", name))
+		}
+		return nil, tooLarge()
+	}
+	attachment.path, attachment.temp, attachment.size = file.Name(), true, written
+	return attachment, nil
+}
+
+// attachmentTooLarge 附件超过大小上限的错误
+func attachmentTooLarge(name string, maxSize int64) error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+		fmt.Sprintf("attachment %s exceeds maximum size of %d bytes", name, maxSize))
+}
+
+// WriteMultipart 将 payload 编码为 JSON 作为 AttachmentRequestPart 部分，attachments 中的附件各为一个部分写入 w，
+// payload 中的附件只输出引用；返回写入的 multipart 消息的 Content-Type
+//
+// 写入期间 attachments 中的附件不能同时被其他请求编码
+func WriteMultipart(w io.Writer, payload interface{}, attachments []*Attachment) (string, error) {
+	writer := multipart.NewWriter(w)
+
+	for _, attachment := range attachments {
+		attachment.reference = true
+	}
+	data, err := json.Marshal(payload)
+	for _, attachment := range attachments {
+		attachment.reference = false
+	}
+	if err != nil {
+		return "", err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, AttachmentRequestPart))
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+
+	for _, attachment := range attachments {
+		if err := writeAttachmentPart(writer, attachment); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return writer.FormDataContentType(), nil
+}
+
+// writeAttachmentPart 写入一个附件部分
+func writeAttachmentPart(writer *multipart.Writer, attachment *Attachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     attachment.Name,
+		"filename": attachment.Name,
+	}))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	reader, err := attachment.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(part, reader)
+	return err
+}
@@ -0,0 +1,207 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// uploadParams 测试用的上传参数
+type uploadParams struct {
+	Title  string        `json:"title"`
+	Photo  *Attachment   `json:"photo"`
+	Extras []*Attachment `json:"extras,omitempty"`
+}
+
+// isBadRequest 判断 err 是否为 BadRequest 框架错误
+func isBadRequest(err error) bool {
+	frameworkErr, ok := frameworkerrors.FromError(err)
+	return ok && frameworkErr.Code == frameworkerrors.BadRequest
+}
+
+func TestAttachmentInline(t *testing.T) {
+	params := uploadParams{Title: "cat", Photo: NewAttachment("cat.png", "image/png", []byte("png-bytes"))}
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"title":"cat","photo":{"$attachment":"cat.png","contentType":"image/png","size":9,"data":"cG5nLWJ5dGVz"}}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var decoded uploadParams
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if err := BindAttachments(context.Background(), &decoded); err != nil {
+		t.Fatalf("BindAttachments failed: %v", err)
+	}
+	content, _ := decoded.Photo.Bytes()
+	if string(content) != "png-bytes" || decoded.Photo.Size() != 9 || decoded.Photo.ContentType != "image/png" {
+		t.Errorf("Unexpected attachment: %+v", decoded.Photo)
+	}
+
+	// 内联附件同样受单个附件的大小限制
+	attachments := NewAttachments(&AttachmentLimits{MaxSize: 4})
+	if err := attachments.Bind(&decoded); !isBadRequest(err) {
+		t.Errorf("Expected BadRequest for oversized inline attachment, got %v", err)
+	}
+}
+
+func TestAttachmentMultipartRoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte("x"), 2048)
+	params := &uploadParams{
+		Title:  "album",
+		Photo:  NewAttachment("cover.jpg", "image/jpeg", []byte("small")),
+		Extras: []*Attachment{NewAttachment("raw.bin", "", large)},
+	}
+
+	var body bytes.Buffer
+	contentType, err := WriteMultipart(&body, params, CollectAttachments(params))
+	if err != nil {
+		t.Fatalf("WriteMultipart failed: %v", err)
+	}
+	boundary, ok := IsMultipart(contentType)
+	if !ok {
+		t.Fatalf("Expected multipart content type, got %s", contentType)
+	}
+	// 请求部分只包含引用
+	if strings.Contains(body.String(), `"data"`) {
+		t.Errorf("Expected references only in request part, got %s", body.String())
+	}
+
+	tempDir := t.TempDir()
+	payload, attachments, err := ReadMultipart(&body, boundary, &AttachmentLimits{MemoryThreshold: 1024, TempDir: tempDir})
+	if err != nil {
+		t.Fatalf("ReadMultipart failed: %v", err)
+	}
+	if names := attachments.Names(); len(names) != 2 || names[0] != "cover.jpg" || names[1] != "raw.bin" {
+		t.Errorf("Names = %v, want [cover.jpg raw.bin]", names)
+	}
+
+	var decoded uploadParams
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	ctx := WithAttachments(context.Background(), attachments)
+	if err := BindAttachments(ctx, &decoded); err != nil {
+		t.Fatalf("BindAttachments failed: %v", err)
+	}
+	if content, _ := decoded.Photo.Bytes(); string(content) != "small" || decoded.Photo.ContentType != "image/jpeg" {
+		t.Errorf("Unexpected photo: %+v", decoded.Photo)
+	}
+	if content, _ := decoded.Extras[0].Bytes(); !bytes.Equal(content, large) {
+		t.Errorf("Extras[0] has %d bytes, want %d", len(content), len(large))
+	}
+
+	// 超过内存阈值的附件写入临时文件，关闭后删除
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 temp file, got %d", len(entries))
+	}
+	if err := attachments.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := decoded.Extras[0].Close(); err != nil {
+		t.Errorf("Closing a removed attachment should succeed, got %v", err)
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Errorf("Expected temp files removed, got %d", len(entries))
+	}
+}
+
+func TestReadMultipartLimits(t *testing.T) {
+	write := func(attachments ...*Attachment) (*bytes.Buffer, string) {
+		t.Helper()
+		var body bytes.Buffer
+		contentType, err := WriteMultipart(&body, map[string]interface{}{"n": 1}, attachments)
+		if err != nil {
+			t.Fatalf("WriteMultipart failed: %v", err)
+		}
+		boundary, _ := IsMultipart(contentType)
+		return &body, boundary
+	}
+
+	tests := []struct {
+		name        string
+		limits      AttachmentLimits
+		attachments []*Attachment
+		message     string
+	}{
+		{
+			name:        "单个附件超限",
+			limits:      AttachmentLimits{MaxSize: 8, MemoryThreshold: 4},
+			attachments: []*Attachment{NewAttachment("a", "", bytes.Repeat([]byte("a"), 16))},
+			message:     "attachment a exceeds maximum size of 8 bytes",
+		},
+		{
+			name:   "总大小超限",
+			limits: AttachmentLimits{MaxSize: 8, MaxTotalSize: 10},
+			attachments: []*Attachment{
+				NewAttachment("a", "", bytes.Repeat([]byte("a"), 6)),
+				NewAttachment("b", "", bytes.Repeat([]byte("b"), 6)),
+			},
+			message: "attachments exceed 10 bytes in total",
+		},
+		{
+			name:   "名称重复",
+			limits: AttachmentLimits{},
+			attachments: []*Attachment{
+				NewAttachment("a", "", []byte("1")),
+				NewAttachment("a", "", []byte("2")),
+			},
+			message: "duplicate attachment a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.limits.TempDir = t.TempDir()
+			body, boundary := write(tt.attachments...)
+			_, _, err := ReadMultipart(body, boundary, &tt.limits)
+			frameworkErr, ok := frameworkerrors.FromError(err)
+			if !ok || frameworkErr.Code != frameworkerrors.BadRequest || frameworkErr.Message != tt.message {
+				t.Errorf("ReadMultipart error = %v, want BadRequest %q", err, tt.message)
+			}
+			// 出错时已写入的临时文件被删除
+			if entries, _ := os.ReadDir(tt.limits.TempDir); len(entries) != 0 {
+				t.Errorf("Expected no temp files left, got %d", len(entries))
+			}
+		})
+	}
+}
+
+func TestResolveAttachment(t *testing.T) {
+	attachments := NewAttachments(nil)
+	attachments.items["doc.pdf"] = NewAttachment("doc.pdf", "application/pdf", []byte("%PDF"))
+	ctx := WithAttachments(context.Background(), attachments)
+
+	attachment, err := ResolveAttachment(ctx, map[string]interface{}{AttachmentField: "doc.pdf"})
+	if err != nil {
+		t.Fatalf("ResolveAttachment failed: %v", err)
+	}
+	if content, _ := attachment.Bytes(); string(content) != "%PDF" || attachment.ContentType != "application/pdf" {
+		t.Errorf("Unexpected attachment: %+v", attachment)
+	}
+
+	if _, err := ResolveAttachment(ctx, map[string]interface{}{AttachmentField: "missing.pdf"}); !isBadRequest(err) {
+		t.Errorf("Expected BadRequest for missing attachment, got %v", err)
+	}
+	if _, err := ResolveAttachment(ctx, "doc.pdf"); !isBadRequest(err) {
+		t.Errorf("Expected BadRequest for non-attachment value, got %v", err)
+	}
+}
+
+func TestAcceptsMultipart(t *testing.T) {
+	if !AcceptsMultipart("application/json, multipart/form-data") {
+		t.Error("Expected multipart/form-data to be accepted")
+	}
+	if AcceptsMultipart("application/json") || AcceptsMultipart("") {
+		t.Error("Expected application/json alone not to accept multipart")
+	}
+}
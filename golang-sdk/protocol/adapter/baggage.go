@@ -0,0 +1,162 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HeaderBaggage W3C Baggage 请求头
+const HeaderBaggage = "baggage"
+
+// W3C Baggage 限制
+const (
+	MaxBaggageMembers = 64
+	MaxBaggageBytes   = 8192
+)
+
+// baggageKey baggage 的 context 键
+type baggageKey struct{}
+
+// WithBaggage 在 context 中设置一项 baggage，跨进程调用时随请求头自动传播
+//
+// 适合携带租户、实验分组等业务上下文；值会进行百分号编码，键必须是合法的 HTTP token
+func WithBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	if !isBaggageKey(key) {
+		return ctx, fmt.Errorf("invalid baggage key: %q", key)
+	}
+
+	current := baggageFromContext(ctx)
+	if _, exists := current[key]; !exists && len(current) >= MaxBaggageMembers {
+		return ctx, fmt.Errorf("baggage exceeds %d members", MaxBaggageMembers)
+	}
+
+	next := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	next[key] = value
+
+	if len(formatBaggage(next)) > MaxBaggageBytes {
+		return ctx, fmt.Errorf("baggage exceeds %d bytes", MaxBaggageBytes)
+	}
+
+	return context.WithValue(ctx, baggageKey{}, next), nil
+}
+
+// BaggageValue 获取 context 中的 baggage 值，不存在时返回空字符串
+func BaggageValue(ctx context.Context, key string) string {
+	return baggageFromContext(ctx)[key]
+}
+
+// BaggageFromContext 返回 context 中所有 baggage 的副本
+func BaggageFromContext(ctx context.Context) map[string]string {
+	current := baggageFromContext(ctx)
+	result := make(map[string]string, len(current))
+	for k, v := range current {
+		result[k] = v
+	}
+	return result
+}
+
+// baggageFromContext 返回 context 中的 baggage，调用方不得修改
+func baggageFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	bag, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return bag
+}
+
+// injectBaggage 将 context 中的 baggage 写入请求头
+func injectBaggage(ctx context.Context, headers map[string]string) {
+	bag := baggageFromContext(ctx)
+	if len(bag) == 0 {
+		return
+	}
+	headerCarrier(headers).Set(HeaderBaggage, formatBaggage(bag))
+}
+
+// extractBaggage 解析请求头中的 baggage 并与 context 中已有的 baggage 合并，请求头中的值优先
+func extractBaggage(ctx context.Context, headers map[string]string) context.Context {
+	header := headerCarrier(headers).Get(HeaderBaggage)
+	if header == "" || len(header) > MaxBaggageBytes {
+		return ctx
+	}
+
+	incoming := parseBaggage(header)
+	if len(incoming) == 0 {
+		return ctx
+	}
+
+	current := baggageFromContext(ctx)
+	merged := make(map[string]string, len(current)+len(incoming))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range incoming {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, baggageKey{}, merged)
+}
+
+// formatBaggage 按 W3C 格式序列化 baggage，键排序以保证输出稳定
+func formatBaggage(bag map[string]string) string {
+	keys := make([]string, 0, len(bag))
+	for k := range bag {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	members := make([]string, 0, len(keys))
+	for _, k := range keys {
+		members = append(members, k+"="+url.PathEscape(bag[k]))
+	}
+	return strings.Join(members, ",")
+}
+
+// parseBaggage 解析 W3C baggage 请求头，忽略属性和格式错误的成员
+func parseBaggage(header string) map[string]string {
+	bag := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		if len(bag) >= MaxBaggageMembers {
+			break
+		}
+
+		// 成员格式: key=value;property
+		if i := strings.IndexByte(member, ';'); i >= 0 {
+			member = member[:i]
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		if !isBaggageKey(key) {
+			continue
+		}
+
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		bag[key] = decoded
+	}
+	return bag
+}
+
+// isBaggageKey 检查是否为合法的 baggage 键（RFC 7230 token）
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if c <= ' ' || c >= 0x7f || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", c) {
+			return false
+		}
+	}
+	return true
+}
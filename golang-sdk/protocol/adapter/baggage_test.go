@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithBaggage(t *testing.T) {
+	ctx, err := WithBaggage(context.Background(), "tenant", "acme")
+	if err != nil {
+		t.Fatalf("WithBaggage failed: %v", err)
+	}
+	ctx, err = WithBaggage(ctx, "bucket", "b 2")
+	if err != nil {
+		t.Fatalf("WithBaggage failed: %v", err)
+	}
+
+	if got := BaggageValue(ctx, "tenant"); got != "acme" {
+		t.Errorf("tenant = %q, want acme", got)
+	}
+	if got := BaggageValue(ctx, "missing"); got != "" {
+		t.Errorf("missing = %q, want empty", got)
+	}
+
+	// 返回的是副本，修改不影响 context
+	all := BaggageFromContext(ctx)
+	all["tenant"] = "other"
+	if got := BaggageValue(ctx, "tenant"); got != "acme" {
+		t.Errorf("tenant changed through copy: %q", got)
+	}
+
+	if _, err := WithBaggage(ctx, "bad key", "v"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+	if _, err := WithBaggage(ctx, "big", strings.Repeat("x", MaxBaggageBytes)); err == nil {
+		t.Error("expected error for oversized baggage")
+	}
+}
+
+func TestBaggagePropagation(t *testing.T) {
+	ctx, _ := WithBaggage(context.Background(), "tenant", "acme")
+	ctx, _ = WithBaggage(ctx, "note", "a,b=c")
+
+	headers := make(map[string]string)
+	InjectTraceContext(ctx, headers)
+	if headers[HeaderBaggage] != "note=a%2Cb=c,tenant=acme" {
+		t.Fatalf("baggage header = %q", headers[HeaderBaggage])
+	}
+
+	// 下游进程从空 context 提取
+	remote := ExtractTraceContext(context.Background(), map[string]string{"Baggage": headers[HeaderBaggage]})
+	if got := BaggageValue(remote, "note"); got != "a,b=c" {
+		t.Errorf("note = %q, want a,b=c", got)
+	}
+	if got := BaggageValue(remote, "tenant"); got != "acme" {
+		t.Errorf("tenant = %q, want acme", got)
+	}
+}
+
+func TestParseBaggage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{
+			name:   "multiple members with spaces",
+			header: "tenant=acme , bucket = b2",
+			want:   map[string]string{"tenant": "acme", "bucket": "b2"},
+		},
+		{
+			name:   "properties are ignored",
+			header: "tenant=acme;ttl=30",
+			want:   map[string]string{"tenant": "acme"},
+		},
+		{
+			name:   "malformed members are skipped",
+			header: "novalue,=empty,tenant=acme,bad=%zz",
+			want:   map[string]string{"tenant": "acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBaggage(tt.header)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseBaggage(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformRequestPropagatesBaggage(t *testing.T) {
+	a := NewDefaultProtocolAdapter()
+	ctx, _ := WithBaggage(context.Background(), "tenant", "acme")
+
+	internal, err := a.TransformRequest(ctx, &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "getUser",
+			"baggage":        "bucket=b2,tenant=upstream",
+		},
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+
+	// 请求头中的值优先于本地 context
+	if got := internal.Headers[HeaderBaggage]; got != "bucket=b2,tenant=upstream" {
+		t.Errorf("baggage header = %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package adapter
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// HeaderTimeout 调用方剩余超时的毫秒数，随请求元数据传递，接收方以收到请求的时间加该超时作为处理的截止时间；
+// 传递剩余时间而不是绝对时间，不要求双方时钟同步，经多跳调用时每一跳重新计算
+const HeaderTimeout = "X-Timeout-Ms"
+
+// MaxTimeout 接收方接受的最长剩余超时，更大的值按此截断，避免换算为 time.Duration 时溢出为负值
+const MaxTimeout = 24 * time.Hour
+
+// InjectDeadline 将 context 的剩余超时写入请求头，context 没有截止时间时不写入；已过期的截止时间写入 1 毫秒
+func InjectDeadline(ctx context.Context, headers map[string]string) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	timeout := time.Until(deadline).Milliseconds()
+	if timeout < 1 {
+		timeout = 1
+	}
+	headerCarrier(headers).Set(HeaderTimeout, strconv.FormatInt(timeout, 10))
+}
+
+// ExtractDeadline 按请求头中的剩余超时返回带截止时间的 context，请求头中没有或不是正整数时返回可取消的 ctx；
+// 超过 MaxTimeout 的剩余超时按 MaxTimeout 处理。调用方须在处理完成后调用返回的 CancelFunc
+func ExtractDeadline(ctx context.Context, headers map[string]string) (context.Context, context.CancelFunc) {
+	timeout, err := strconv.ParseInt(headerCarrier(headers).Get(HeaderTimeout), 10, 64)
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	if timeout > MaxTimeout.Milliseconds() {
+		timeout = MaxTimeout.Milliseconds()
+	}
+	return context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+}
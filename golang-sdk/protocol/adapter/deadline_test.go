@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	headers := make(map[string]string)
+	InjectDeadline(ctx, headers)
+	timeout, err := strconv.Atoi(headers[HeaderTimeout])
+	if err != nil || timeout <= 1000 || timeout > 2000 {
+		t.Fatalf("%s = %q, want remaining milliseconds", HeaderTimeout, headers[HeaderTimeout])
+	}
+
+	// 请求头名称不区分大小写
+	extracted, cancelExtracted := ExtractDeadline(context.Background(), map[string]string{"x-timeout-ms": "1500"})
+	defer cancelExtracted()
+	deadline, ok := extracted.Deadline()
+	if !ok || time.Until(deadline) > 1500*time.Millisecond || time.Until(deadline) < time.Second {
+		t.Errorf("Deadline = %v, %v; want about 1.5s from now", deadline, ok)
+	}
+
+	// 没有截止时间时不写入，无效的值被忽略
+	empty := make(map[string]string)
+	InjectDeadline(context.Background(), empty)
+	if _, ok := empty[HeaderTimeout]; ok {
+		t.Errorf("Unexpected %s %q", HeaderTimeout, empty[HeaderTimeout])
+	}
+	invalid, cancelInvalid := ExtractDeadline(context.Background(), map[string]string{HeaderTimeout: "-5"})
+	defer cancelInvalid()
+	if _, ok := invalid.Deadline(); ok {
+		t.Error("Expected no deadline for an invalid timeout")
+	}
+
+	// 过大的超时按 MaxTimeout 截断，换算时不溢出为已过期的截止时间
+	for _, value := range []string{"9223372036854775807", strconv.FormatInt(MaxTimeout.Milliseconds()*2, 10)} {
+		clamped, cancelClamped := ExtractDeadline(context.Background(), map[string]string{HeaderTimeout: value})
+		deadline, ok := clamped.Deadline()
+		if remaining := time.Until(deadline); !ok || remaining > MaxTimeout || remaining < MaxTimeout-time.Minute {
+			t.Errorf("%s = %s: deadline in %v, %v; want about %v", HeaderTimeout, value, remaining, ok, MaxTimeout)
+		}
+		if clamped.Err() != nil {
+			t.Errorf("%s = %s: context already done: %v", HeaderTimeout, value, clamped.Err())
+		}
+		cancelClamped()
+	}
+}
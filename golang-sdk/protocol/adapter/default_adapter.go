@@ -1,28 +1,84 @@
 package adapter
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/gogf/gf/v2/util/guid"
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/idgen"
+	"github.com/framework/golang-sdk/serializer"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultRequestTimeout 未设置超时策略时内部请求的超时
+const DefaultRequestTimeout = 30 * time.Second
+
+// TimeoutPolicy 按服务方法决定内部请求的超时，如 resilience.AdaptiveTimeout
+type TimeoutPolicy interface {
+	Timeout(service, method string) time.Duration
+}
+
 // DefaultProtocolAdapter 默认协议适配器实现
 type DefaultProtocolAdapter struct {
 	defaultTimeout time.Duration
+	timeoutPolicy  TimeoutPolicy
+	encoding       EncodingProfile
+	serializers    *serializer.SerializerRegistry
 }
 
 // NewDefaultProtocolAdapter 创建默认协议适配器
 func NewDefaultProtocolAdapter() *DefaultProtocolAdapter {
 	return &DefaultProtocolAdapter{
-		defaultTimeout: 30 * time.Second,
+		defaultTimeout: DefaultRequestTimeout,
+		encoding:       EncodingStandard,
+	}
+}
+
+// SetEncodingProfile 设置负载编码方式，应在开始处理请求前调用
+//
+// EncodingPortable 下请求体按 MarshalPortable 编码并在元数据中记录编码方式，
+// 响应体解码时保留整数精度
+func (a *DefaultProtocolAdapter) SetEncodingProfile(profile EncodingProfile) {
+	if profile == "" {
+		profile = EncodingStandard
 	}
+	a.encoding = profile
+}
+
+// SetTimeoutPolicy 设置内部请求的超时策略，为 nil 时所有请求使用 DefaultRequestTimeout；应在开始处理请求前调用
+func (a *DefaultProtocolAdapter) SetTimeoutPolicy(policy TimeoutPolicy) {
+	a.timeoutPolicy = policy
+}
+
+// GetEncodingProfile 获取负载编码方式
+func (a *DefaultProtocolAdapter) GetEncodingProfile() EncodingProfile {
+	return a.encoding
 }
 
 // TransformRequest 将外部协议请求转换为内部协议请求
 func (a *DefaultProtocolAdapter) TransformRequest(ctx context.Context, external *ExternalRequest) (*InternalRequest, error) {
+	start := time.Now()
+	internal, err := a.transformRequest(ctx, external)
+
+	var protocol ProtocolType
+	if external != nil {
+		protocol = external.Protocol
+	}
+	var service, method string
+	if internal != nil {
+		service, method = internal.Service, internal.Method
+	}
+	recordTransform(stageRequest, protocol, service, method, err, time.Since(start))
+
+	return internal, err
+}
+
+// transformRequest 执行请求转换
+func (a *DefaultProtocolAdapter) transformRequest(ctx context.Context, external *ExternalRequest) (*InternalRequest, error) {
 	if external == nil {
 		return nil, &FrameworkError{
 			Code:    ErrorBadRequest,
@@ -30,9 +86,13 @@ func (a *DefaultProtocolAdapter) TransformRequest(ctx context.Context, external
 		}
 	}
 
+	// 解析上游追踪上下文（W3C 优先，B3 回退）和 baggage
+	traceCtx := ExtractTraceContext(ctx, external.Headers)
+	remote := trace.SpanContextFromContext(traceCtx)
+
 	// 生成追踪 ID
-	traceId := a.getOrGenerateTraceId(external)
-	spanId := guid.S()
+	traceId := a.getOrGenerateTraceId(external, remote)
+	spanId := newSpanID()
 
 	// 提取服务名称和方法名称
 	service, method, err := a.extractServiceAndMethod(external)
@@ -58,10 +118,15 @@ func (a *DefaultProtocolAdapter) TransformRequest(ctx context.Context, external
 		Headers:  a.copyHeaders(external.Headers),
 		TraceId:  traceId,
 		SpanId:   spanId,
-		Timeout:  a.defaultTimeout,
+		Timeout:  a.requestTimeout(service, method),
 		Metadata: make(map[string]string),
 	}
 
+	// 向下游传播 W3C 追踪上下文
+	a.propagateTraceContext(internal, remote)
+	// 合并本地和上游的 baggage 后向下游传播
+	injectBaggage(traceCtx, internal.Headers)
+
 	// 添加协议类型到元数据
 	internal.Metadata["original_protocol"] = string(external.Protocol)
 
@@ -73,12 +138,61 @@ func (a *DefaultProtocolAdapter) TransformRequest(ctx context.Context, external
 			internal.Metadata[k] = v
 		}
 	}
+	// 调用方未提供请求 ID 时按 idgen.Default 生成
+	if internal.Metadata["request_id"] == "" {
+		internal.Metadata["request_id"] = idgen.RequestID()
+	}
+
+	// 记录负载编码方式，下游据此选择解码方式
+	if a.encoding == EncodingPortable {
+		internal.Metadata[MetadataEncoding] = string(a.encoding)
+	}
+
+	// 记录错误消息使用的语言，元数据中已指定时优先
+	if locale := internal.Metadata[MetadataLocale]; locale != "" {
+		internal.Metadata[MetadataLocale] = frameworkerrors.MatchLocale(locale)
+	} else if locale := frameworkerrors.LocaleFromContext(traceCtx); locale != "" {
+		internal.Metadata[MetadataLocale] = locale
+	}
+
+	// 注入安全上下文，供下游服务直接做授权判断；租户标识同样只取自认证后的安全上下文，
+	// 调用方在请求头或元数据中携带的租户标识会被丢弃，不能借此发现其他租户的服务
+	stripIdentityHeaders(internal.Headers)
+	delete(internal.Metadata, MetadataTenantID)
+	if sc := SecurityContextFromContext(ctx); sc != nil {
+		for k, v := range sc.ToHeaders() {
+			internal.Headers[k] = v
+		}
+		if sc.TenantID != "" {
+			internal.Metadata[MetadataTenantID] = sc.TenantID
+		}
+	}
 
 	return internal, nil
 }
 
 // TransformResponse 将内部协议响应转换为外部协议响应
+//
+// 指标的 error_code 标签取转换错误，转换成功时取响应携带的业务错误
 func (a *DefaultProtocolAdapter) TransformResponse(ctx context.Context, internal *InternalResponse, originalProtocol ProtocolType) (*ExternalResponse, error) {
+	start := time.Now()
+	external, err := a.transformResponse(ctx, internal, originalProtocol)
+
+	recordErr := err
+	var service, method string
+	if internal != nil {
+		if recordErr == nil && internal.Error != nil {
+			recordErr = internal.Error
+		}
+		service, method = internal.Metadata[MetadataService], internal.Metadata[MetadataMethod]
+	}
+	recordTransform(stageResponse, originalProtocol, service, method, recordErr, time.Since(start))
+
+	return external, err
+}
+
+// transformResponse 执行响应转换
+func (a *DefaultProtocolAdapter) transformResponse(ctx context.Context, internal *InternalResponse, originalProtocol ProtocolType) (*ExternalResponse, error) {
 	if internal == nil {
 		return nil, &FrameworkError{
 			Code:    ErrorInternal,
@@ -89,15 +203,20 @@ func (a *DefaultProtocolAdapter) TransformResponse(ctx context.Context, internal
 	// 反序列化响应体
 	var body interface{}
 	if len(internal.Payload) > 0 {
-		if err := json.Unmarshal(internal.Payload, &body); err != nil {
+		if err := a.deserializePayload(internal.Payload, &body); err != nil {
 			// 如果无法解析为 JSON，返回原始字节
 			body = internal.Payload
 		}
 	}
 
-	// 确定状态码
+	// 确定状态码，gRPC 为 gRPC 状态码
 	statusCode := 200
-	if internal.Error != nil {
+	if originalProtocol == ProtocolGRPC {
+		statusCode = 0
+		if internal.Error != nil {
+			statusCode = internal.Error.Code.ToGRPCStatus()
+		}
+	} else if internal.Error != nil {
 		statusCode = a.mapErrorCodeToHttpStatus(internal.Error.Code)
 	}
 
@@ -110,17 +229,19 @@ func (a *DefaultProtocolAdapter) TransformResponse(ctx context.Context, internal
 		Error:      internal.Error,
 	}
 
+	// 错误消息语言：ctx 未指定时使用响应元数据中的语言
+	if locale := internal.Metadata[MetadataLocale]; locale != "" && frameworkerrors.LocaleFromContext(ctx) == "" {
+		ctx = frameworkerrors.WithLocale(ctx, locale)
+	}
+
 	// 根据协议类型调整响应格式
 	switch originalProtocol {
 	case ProtocolJSONRPC:
-		external.Body = a.formatJsonRpcResponse(body, internal.Error)
-	case ProtocolREST:
+		external.Body = a.formatJsonRpcResponse(ctx, body, internal.Error)
+	case ProtocolREST, ProtocolGRPC:
+		// gRPC 服务端将结构化错误放入 status details，响应头作为 trailer 发送
 		if internal.Error != nil {
-			external.Body = map[string]interface{}{
-				"error":   internal.Error.Message,
-				"code":    internal.Error.Code,
-				"details": internal.Error.Details,
-			}
+			external.Body = NewErrorPayload(ctx, internal.Error)
 		}
 	}
 
@@ -134,6 +255,7 @@ func (a *DefaultProtocolAdapter) GetSupportedProtocols() []ProtocolType {
 		ProtocolWebSocket,
 		ProtocolJSONRPC,
 		ProtocolMQTT,
+		ProtocolKafka,
 		ProtocolGRPC,
 		ProtocolInternalRPC,
 		ProtocolCustomBinary,
@@ -151,6 +273,10 @@ func (a *DefaultProtocolAdapter) extractServiceAndMethod(external *ExternalReque
 		return a.extractFromWebSocket(external)
 	case ProtocolMQTT:
 		return a.extractFromMQTT(external)
+	case ProtocolKafka:
+		return a.extractFromKafka(external)
+	case ProtocolGRPC:
+		return a.extractFromGRPC(external)
 	default:
 		return "", "", &FrameworkError{
 			Code:    ErrorProtocol,
@@ -271,21 +397,81 @@ func (a *DefaultProtocolAdapter) extractFromMQTT(external *ExternalRequest) (str
 	return service, method, nil
 }
 
+// extractFromKafka 从 Kafka 记录中提取服务和方法，由 Kafka 处理器按主题路由写入 X-Service-Name 和 X-Method-Name
+func (a *DefaultProtocolAdapter) extractFromKafka(external *ExternalRequest) (string, string, error) {
+	service := external.Headers["X-Service-Name"]
+	method := external.Headers["X-Method-Name"]
+	if service == "" || method == "" {
+		return "", "", &FrameworkError{
+			Code:    ErrorBadRequest,
+			Message: "service or method not specified in Kafka record",
+		}
+	}
+	return service, method, nil
+}
+
+// extractFromGRPC 从 gRPC 请求中提取服务和方法，完整方法名优先，其次为 X-Service-Name 和 X-Method-Name
+func (a *DefaultProtocolAdapter) extractFromGRPC(external *ExternalRequest) (string, string, error) {
+	service := external.Headers["X-Service-Name"]
+	method := external.Headers["X-Method-Name"]
+	if fullMethod := external.Headers[HeaderGRPCMethod]; fullMethod != "" {
+		service, method = splitGRPCMethod(fullMethod)
+	}
+	if service == "" || method == "" {
+		return "", "", &FrameworkError{
+			Code:    ErrorBadRequest,
+			Message: "service or method not specified in gRPC request",
+		}
+	}
+	return service, method, nil
+}
+
 // serializePayload 序列化负载
 func (a *DefaultProtocolAdapter) serializePayload(body interface{}) ([]byte, error) {
 	if body == nil {
 		return []byte{}, nil
 	}
 
+	// 已编码的负载，JSON 格式原样返回，其他格式转码为 JSON
+	if raw, ok := serializer.AsRawPayload(body); ok {
+		if raw.Format == serializer.JSON {
+			return raw.Data, nil
+		}
+		if a.serializers == nil {
+			return nil, fmt.Errorf("cannot convert %s payload to json without a serializer registry", raw.Format)
+		}
+		return a.serializers.Transcode(raw, serializer.JSON)
+	}
+
 	// 如果已经是字节数组，直接返回
 	if bytes, ok := body.([]byte); ok {
 		return bytes, nil
 	}
 
 	// 序列化为 JSON
+	if a.encoding == EncodingPortable {
+		return MarshalPortable(body)
+	}
 	return json.Marshal(body)
 }
 
+// deserializePayload 反序列化负载，EncodingPortable 下以 json.Number 保留数值原文
+func (a *DefaultProtocolAdapter) deserializePayload(payload []byte, body *interface{}) error {
+	if a.encoding != EncodingPortable {
+		return json.Unmarshal(payload, body)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(body); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON payload")
+	}
+	return nil
+}
+
 // copyHeaders 复制请求头
 func (a *DefaultProtocolAdapter) copyHeaders(headers map[string]string) map[string]string {
 	if headers == nil {
@@ -300,9 +486,14 @@ func (a *DefaultProtocolAdapter) copyHeaders(headers map[string]string) map[stri
 }
 
 // getOrGenerateTraceId 获取或生成追踪 ID
-func (a *DefaultProtocolAdapter) getOrGenerateTraceId(external *ExternalRequest) string {
-	// 尝试从请求头获取
-	if traceId := external.Headers["X-Trace-Id"]; traceId != "" {
+func (a *DefaultProtocolAdapter) getOrGenerateTraceId(external *ExternalRequest, remote trace.SpanContext) string {
+	// 优先使用 W3C/B3 追踪上下文
+	if remote.IsValid() {
+		return remote.TraceID().String()
+	}
+
+	// 兼容旧的 X-Trace-Id 请求头
+	if traceId := external.Headers[HeaderTraceID]; traceId != "" {
 		return traceId
 	}
 
@@ -312,37 +503,55 @@ func (a *DefaultProtocolAdapter) getOrGenerateTraceId(external *ExternalRequest)
 	}
 
 	// 生成新的追踪 ID
-	return guid.S()
+	return newTraceID()
 }
 
-// mapErrorCodeToHttpStatus 将错误码映射到 HTTP 状态码
-func (a *DefaultProtocolAdapter) mapErrorCodeToHttpStatus(code ErrorCode) int {
-	switch code {
-	case ErrorBadRequest:
-		return 400
-	case ErrorUnauthorized:
-		return 401
-	case ErrorForbidden:
-		return 403
-	case ErrorNotFound:
-		return 404
-	case ErrorTimeout:
-		return 408
-	case ErrorInternal:
-		return 500
-	case ErrorNotImplemented:
-		return 501
-	case ErrorServiceUnavailable:
-		return 503
-	case ErrorProtocol, ErrorSerialization, ErrorRouting, ErrorConnection:
-		return 500
-	default:
-		return 500
+// propagateTraceContext 将追踪上下文以 W3C 格式写入内部请求头
+//
+// 内部请求的 span 作为上游 span 的子 span，B3 请求头会被替换为 traceparent
+func (a *DefaultProtocolAdapter) propagateTraceContext(internal *InternalRequest, remote trace.SpanContext) {
+	for _, name := range []string{HeaderB3, HeaderB3TraceID, HeaderB3SpanID, HeaderB3Sampled} {
+		for key := range internal.Headers {
+			if strings.EqualFold(key, name) {
+				delete(internal.Headers, key)
+			}
+		}
+	}
+
+	// 旧格式的追踪 ID 无法转换为 traceparent
+	if _, err := trace.TraceIDFromHex(internal.TraceId); err != nil {
+		return
 	}
+
+	sampled := true
+	if remote.IsValid() {
+		sampled = remote.IsSampled()
+	}
+
+	carrier := headerCarrier(internal.Headers)
+	carrier.Set(HeaderTraceParent, formatTraceParent(internal.TraceId, internal.SpanId, sampled))
+	if remote.IsValid() && remote.TraceState().Len() > 0 {
+		carrier.Set(HeaderTraceState, remote.TraceState().String())
+	}
+}
+
+// requestTimeout 返回内部请求的超时，有超时策略时按服务方法计算
+func (a *DefaultProtocolAdapter) requestTimeout(service, method string) time.Duration {
+	if a.timeoutPolicy != nil {
+		if timeout := a.timeoutPolicy.Timeout(service, method); timeout > 0 {
+			return timeout
+		}
+	}
+	return a.defaultTimeout
+}
+
+// mapErrorCodeToHttpStatus 将错误码映射到 HTTP 状态码，自定义错误码使用注册时声明的状态码
+func (a *DefaultProtocolAdapter) mapErrorCodeToHttpStatus(code ErrorCode) int {
+	return code.ToHTTPStatus()
 }
 
-// formatJsonRpcResponse 格式化 JSON-RPC 响应
-func (a *DefaultProtocolAdapter) formatJsonRpcResponse(body interface{}, err *FrameworkError) interface{} {
+// formatJsonRpcResponse 格式化 JSON-RPC 响应，错误的 data 为跨语言传输格式
+func (a *DefaultProtocolAdapter) formatJsonRpcResponse(ctx context.Context, body interface{}, err *FrameworkError) interface{} {
 	response := map[string]interface{}{
 		"jsonrpc": "2.0",
 	}
@@ -351,7 +560,7 @@ func (a *DefaultProtocolAdapter) formatJsonRpcResponse(body interface{}, err *Fr
 		response["error"] = map[string]interface{}{
 			"code":    err.Code,
 			"message": err.Message,
-			"data":    err.Details,
+			"data":    NewErrorPayload(ctx, err),
 		}
 	} else {
 		response["result"] = body
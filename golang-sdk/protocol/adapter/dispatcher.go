@@ -0,0 +1,9 @@
+package adapter
+
+import "context"
+
+// Dispatcher 将经 TransformRequest 转换的请求分发给本地注册的业务方法
+//
+// request.Payload 为 JSON 编码的请求参数，request.Headers 中可读取认证信息；
+// 返回值按各协议的响应格式编码，未注册的方法应返回 NotFound 错误
+type Dispatcher func(ctx context.Context, request *InternalRequest) (interface{}, error)
@@ -0,0 +1,501 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodingProfile 负载的 JSON 编码方式
+type EncodingProfile string
+
+const (
+	// EncodingStandard 标准编码，与 encoding/json 一致
+	EncodingStandard EncodingProfile = "standard"
+
+	// EncodingPortable 跨语言编码，与 proto3 JSON 映射一致：
+	// 64 位整数编码为十进制字符串，时间编码为 UTC 的 RFC 3339 字符串，
+	// 时长编码为以 s 结尾的秒数字符串，字节数组编码为标准 base64。
+	// JavaScript 等只有双精度浮点数的语言可以无损读取 64 位整数
+	EncodingPortable EncodingProfile = "portable"
+)
+
+// MetadataEncoding 内部请求元数据中的负载编码方式
+const MetadataEncoding = "encoding"
+
+// maxSafeInteger 双精度浮点数能精确表示的最大整数（2^53）
+const maxSafeInteger = 1 << 53
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	durationType    = reflect.TypeOf(time.Duration(0))
+	numberType      = reflect.TypeOf(json.Number(""))
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// MarshalPortable 按 EncodingPortable 编码值
+//
+// 结构体字段遵循 json 标签（字段名、omitempty、string 和 -），实现了 json.Marshaler
+// 或 encoding.TextMarshaler 的类型使用其自身的编码。
+// json.Number 只在整数超出 2^53 时编码为字符串，因为无法得知其原始类型
+func MarshalPortable(v interface{}) ([]byte, error) {
+	tree, err := portableValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// UnmarshalPortable 解码 EncodingPortable 编码的数据
+//
+// 目标字段为数值类型时同时接受 JSON 数字和十进制字符串，time.Duration 字段接受
+// 以 s 结尾的秒数字符串，其余规则与 json.Unmarshal 一致，标准编码的数据同样可以解码
+func UnmarshalPortable(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return err
+	}
+
+	normalized, err := fromPortable(tree, rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(normalized)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// portableValue 将值转换为可直接用 encoding/json 编码的通用结构
+func portableValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	t := v.Type()
+	if v.CanInterface() {
+		switch t {
+		case timeType:
+			return v.Interface().(time.Time).UTC().Format(time.RFC3339Nano), nil
+		case durationType:
+			return formatDuration(time.Duration(v.Int())), nil
+		case numberType:
+			return portableNumber(json.Number(v.String())), nil
+		}
+
+		if t.Implements(marshalerType) && !isNilValue(v) {
+			data, err := v.Interface().(json.Marshaler).MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			return json.RawMessage(data), nil
+		}
+		if t.Implements(textMarshalType) && !isNilValue(v) {
+			text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return string(text), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return portableValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return v.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return v.Uint(), nil
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return float32(v.Float()), nil
+	case reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		return portableList(v)
+	case reflect.Array:
+		return portableList(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return portableMap(v)
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		if err := addPortableFields(out, v); err != nil {
+			return nil, err
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", t)
+	}
+}
+
+// portableList 转换切片和数组
+func portableList(v reflect.Value) (interface{}, error) {
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		item, err := portableValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		list[i] = item
+	}
+	return list, nil
+}
+
+// portableMap 转换映射，键支持字符串、整数和 encoding.TextMarshaler
+func portableMap(v reflect.Value) (interface{}, error) {
+	out := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := portableValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// mapKey 将映射键转换为字符串
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if key.CanInterface() && key.Type().Implements(textMarshalType) {
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("unsupported map key type: %s", key.Type())
+	}
+}
+
+// addPortableFields 转换结构体字段，嵌入结构体的字段提升到外层，外层同名字段优先
+func addPortableFields(out map[string]interface{}, v reflect.Value) error {
+	t := v.Type()
+	var embedded []reflect.Value
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		if name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			embedded = append(embedded, fv)
+			continue
+		}
+
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		value, err := portableValue(fv)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if hasOption(opts, "string") {
+			value = quoteScalar(fv, value)
+		}
+		out[name] = value
+	}
+
+	for _, ev := range embedded {
+		inner := make(map[string]interface{}, ev.NumField())
+		if err := addPortableFields(inner, ev); err != nil {
+			return err
+		}
+		for k, value := range inner {
+			if _, ok := out[k]; !ok {
+				out[k] = value
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFieldName 解析字段的 json 标签，嵌入结构体返回空名称，skip 表示不参与编码
+func jsonFieldName(field reflect.StructField) (name, opts string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", "", true
+	}
+	name, opts, _ = strings.Cut(tag, ",")
+
+	if field.Anonymous && name == "" {
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			return "", opts, false
+		}
+	}
+	if !field.IsExported() {
+		return "", "", true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, opts, false
+}
+
+// fieldTypes 返回结构体 JSON 字段名到字段类型的映射，包括嵌入结构体的字段
+func fieldTypes(t reflect.Type) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, t.NumField())
+	var embedded []reflect.Type
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			embedded = append(embedded, ft)
+			continue
+		}
+		types[name] = field.Type
+	}
+
+	for _, et := range embedded {
+		for name, ft := range fieldTypes(et) {
+			if _, ok := types[name]; !ok {
+				types[name] = ft
+			}
+		}
+	}
+	return types
+}
+
+// fromPortable 按目标类型还原 EncodingPortable 编码的通用结构
+func fromPortable(node interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if s, ok := node.(string); ok && t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		return json.Number(strconv.FormatInt(int64(d), 10)), nil
+	}
+	if t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return node, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if s, ok := node.(string); ok {
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q", s)
+			}
+			return json.Number(s), nil
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := node.([]interface{}); ok {
+			for i, item := range list {
+				converted, err := fromPortable(item, t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				list[i] = converted
+			}
+		}
+	case reflect.Map:
+		if m, ok := node.(map[string]interface{}); ok {
+			for k, item := range m {
+				converted, err := fromPortable(item, t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				m[k] = converted
+			}
+		}
+	case reflect.Struct:
+		if m, ok := node.(map[string]interface{}); ok {
+			types := fieldTypes(t)
+			for k, item := range m {
+				ft, ok := lookupFieldType(types, k)
+				if !ok {
+					continue
+				}
+				converted, err := fromPortable(item, ft)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", k, err)
+				}
+				m[k] = converted
+			}
+		}
+	}
+	return node, nil
+}
+
+// lookupFieldType 查找字段类型，与 encoding/json 一样优先精确匹配，其次不区分大小写匹配
+func lookupFieldType(types map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if ft, ok := types[name]; ok {
+		return ft, true
+	}
+	for k, ft := range types {
+		if strings.EqualFold(k, name) {
+			return ft, true
+		}
+	}
+	return nil, false
+}
+
+// portableNumber 超出双精度浮点数精确范围的整数编码为字符串
+func portableNumber(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		if i > maxSafeInteger || i < -maxSafeInteger {
+			return n.String()
+		}
+		return n
+	}
+	if strings.TrimLeft(n.String(), "-0123456789") == "" {
+		// 超出 int64 范围的整数
+		return n.String()
+	}
+	return n
+}
+
+// formatDuration 将时长编码为以 s 结尾的秒数，小数部分保留 0、3、6 或 9 位
+func formatDuration(d time.Duration) string {
+	sign := ""
+	abs := uint64(d)
+	if d < 0 {
+		sign = "-"
+		abs = uint64(-d)
+	}
+
+	seconds := strconv.FormatUint(abs/uint64(time.Second), 10)
+	nanos := abs % uint64(time.Second)
+	if nanos == 0 {
+		return sign + seconds + "s"
+	}
+
+	frac := fmt.Sprintf("%09d", nanos)
+	switch {
+	case strings.HasSuffix(frac, "000000"):
+		frac = frac[:3]
+	case strings.HasSuffix(frac, "000"):
+		frac = frac[:6]
+	}
+	return sign + seconds + "." + frac + "s"
+}
+
+// quoteScalar 处理 json 标签的 string 选项，布尔值和数值编码为字符串
+func quoteScalar(v reflect.Value, value interface{}) interface{} {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Float32, reflect.Float64:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		return string(data)
+	default:
+		return value
+	}
+}
+
+// hasOption 检查 json 标签是否包含指定选项
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isNilValue 检查指针或接口类型的值是否为 nil
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 判断一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
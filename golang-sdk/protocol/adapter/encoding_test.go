@@ -0,0 +1,186 @@
+package adapter
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// portableOrder 测试用的订单结构
+type portableOrder struct {
+	ID        int64             `json:"id"`
+	Quantity  int32             `json:"quantity"`
+	Amount    uint64            `json:"amount"`
+	Price     float64           `json:"price"`
+	CreatedAt time.Time         `json:"created_at"`
+	TTL       time.Duration     `json:"ttl"`
+	Signature []byte            `json:"signature"`
+	Tags      []string          `json:"tags,omitempty"`
+	Counts    map[string]int64  `json:"counts,omitempty"`
+	Parent    *portableOrder    `json:"parent,omitempty"`
+	Note      string            `json:"-"`
+	Labels    map[int]string    `json:"labels,omitempty"`
+	Extra     map[string]string `json:"extra,omitempty"`
+	portableAudit
+}
+
+// portableAudit 测试嵌入结构体
+type portableAudit struct {
+	Operator string `json:"operator"`
+	Version  int64  `json:"version"`
+}
+
+func TestMarshalPortable(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 18, 30, 0, 123000000, time.FixedZone("CST", 8*3600))
+	order := portableOrder{
+		ID:            math.MaxInt64,
+		Quantity:      3,
+		Amount:        math.MaxUint64,
+		Price:         9.99,
+		CreatedAt:     createdAt,
+		TTL:           1500 * time.Millisecond,
+		Signature:     []byte{0xde, 0xad, 0xbe, 0xef},
+		Note:          "internal",
+		Labels:        map[int]string{1: "vip"},
+		portableAudit: portableAudit{Operator: "alice", Version: 7},
+	}
+
+	data, err := MarshalPortable(order)
+	if err != nil {
+		t.Fatalf("MarshalPortable failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":         "9223372036854775807",
+		"quantity":   float64(3),
+		"amount":     "18446744073709551615",
+		"price":      9.99,
+		"created_at": "2024-03-01T10:30:00.123Z",
+		"ttl":        "1.500s",
+		"signature":  "3q2+7w==",
+		"labels":     map[string]interface{}{"1": "vip"},
+		"operator":   "alice",
+		"version":    "7",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected encoding:\n got: %v\nwant: %v", got, expected)
+	}
+}
+
+func TestMarshalPortable_GenericValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"nil", nil, `null`},
+		{"int", 42, `"42"`},
+		{"int32", int32(42), `42`},
+		{"float32", float32(0.1), `0.1`},
+		{"bytes", []byte("hi"), `"aGk="`},
+		{"nil bytes", []byte(nil), `null`},
+		{"negative duration", -90 * time.Second, `"-90s"`},
+		{"microsecond duration", 2500 * time.Microsecond, `"0.002500s"`},
+		{"nanosecond duration", time.Nanosecond, `"0.000000001s"`},
+		{"safe json number", json.Number("12345"), `12345`},
+		{"large json number", json.Number("9007199254740993"), `"9007199254740993"`},
+		{"decimal json number", json.Number("1.25"), `1.25`},
+		{"nested map", map[string]interface{}{"id": int64(1), "list": []interface{}{int64(2)}}, `{"id":"1","list":["2"]}`},
+		{"string option", struct {
+			Enabled bool `json:"enabled,string"`
+		}{true}, `{"enabled":"true"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalPortable(tt.value)
+			if err != nil {
+				t.Fatalf("MarshalPortable failed: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestMarshalPortable_Unsupported(t *testing.T) {
+	if _, err := MarshalPortable(map[string]interface{}{"fn": func() {}}); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
+
+func TestUnmarshalPortable_RoundTrip(t *testing.T) {
+	parent := &portableOrder{ID: -math.MaxInt64, CreatedAt: time.Unix(0, 0).UTC()}
+	order := portableOrder{
+		ID:            math.MaxInt64,
+		Quantity:      -3,
+		Amount:        math.MaxUint64,
+		Price:         0.5,
+		CreatedAt:     time.Date(2024, 3, 1, 10, 30, 0, 1, time.UTC),
+		TTL:           -time.Nanosecond,
+		Signature:     []byte{0, 1, 2},
+		Tags:          []string{"a", "b"},
+		Counts:        map[string]int64{"max": math.MaxInt64},
+		Parent:        parent,
+		portableAudit: portableAudit{Operator: "bob", Version: 9},
+	}
+
+	data, err := MarshalPortable(order)
+	if err != nil {
+		t.Fatalf("MarshalPortable failed: %v", err)
+	}
+
+	var decoded portableOrder
+	if err := UnmarshalPortable(data, &decoded); err != nil {
+		t.Fatalf("UnmarshalPortable failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, order) {
+		t.Errorf("Round trip mismatch:\n got: %+v\nwant: %+v", decoded, order)
+	}
+}
+
+func TestUnmarshalPortable_AcceptsStandardJSON(t *testing.T) {
+	standard, err := json.Marshal(portableAudit{Operator: "carol", Version: 12})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded portableAudit
+	if err := UnmarshalPortable(standard, &decoded); err != nil {
+		t.Fatalf("UnmarshalPortable failed: %v", err)
+	}
+	if decoded.Operator != "carol" || decoded.Version != 12 {
+		t.Errorf("Unexpected result: %+v", decoded)
+	}
+}
+
+func TestUnmarshalPortable_Errors(t *testing.T) {
+	var order portableOrder
+	tests := []struct {
+		name   string
+		data   string
+		target interface{}
+	}{
+		{"non pointer", `{}`, order},
+		{"nil pointer", `{}`, (*portableOrder)(nil)},
+		{"invalid json", `{`, &order},
+		{"invalid number", `{"id":"abc"}`, &order},
+		{"invalid duration", `{"ttl":"soon"}`, &order},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UnmarshalPortable([]byte(tt.data), tt.target); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DetailsField 转换为 errors.FrameworkError 时，非字符串的 Details 存放在该结构化详情字段中
+const DetailsField = "details"
+
+// Unwrap 实现 errors.Unwrap 接口
+func (e *FrameworkError) Unwrap() error {
+	return e.Cause
+}
+
+// Is 支持 errors.Is，target 为 adapter 或 errors 包的框架错误时按错误码匹配，
+// target 的 Message 非空时还需消息一致
+func (e *FrameworkError) Is(target error) bool {
+	switch t := target.(type) {
+	case *FrameworkError:
+		return t != nil && e.Code == t.Code && (t.Message == "" || t.Message == e.Message)
+	case *frameworkerrors.FrameworkError:
+		return t != nil && e.Code == t.Code && (t.Message == "" || t.Message == e.Message)
+	default:
+		return false
+	}
+}
+
+// As 支持 errors.As 转换为 *errors.FrameworkError
+func (e *FrameworkError) As(target interface{}) bool {
+	if t, ok := target.(**frameworkerrors.FrameworkError); ok {
+		*t = e.ToUnifiedError()
+		return true
+	}
+	return false
+}
+
+// ToUnifiedError 转换为 errors.FrameworkError
+//
+// 字符串 Details 对应 Details，其他 Details 存放在结构化详情 details 字段中。
+// 由 FromUnifiedError 转换且未修改的错误返回原错误，保留其调用栈、错误链和结构化详情；
+// 否则新建的错误从调用 ToUnifiedError 的位置记录调用栈
+func (e *FrameworkError) ToUnifiedError() *frameworkerrors.FrameworkError {
+	if e.origin != nil && e.sameAs(FromUnifiedError(e.origin)) {
+		return e.origin
+	}
+
+	details, _ := e.Details.(string)
+	unified := frameworkerrors.NewFrameworkErrorFull(e.Code, e.Message, details, e.ServiceId, e.Cause)
+	if e.Timestamp != 0 {
+		unified.Timestamp = e.Timestamp
+	}
+	if _, ok := e.Details.(string); !ok && e.Details != nil {
+		unified = unified.WithDetail(DetailsField, e.Details)
+	}
+	return unified
+}
+
+// FromUnifiedError 从 errors.FrameworkError 转换，err 为 nil 时返回 nil
+//
+// 只有 details 一项结构化详情时还原为 Details，有其他结构化详情时 Details 为包含全部详情的 map，
+// 字符串详情放在其 details 字段中。调用栈格式化为 "函数 (文件:行号)"
+func FromUnifiedError(err *frameworkerrors.FrameworkError) *FrameworkError {
+	if err == nil {
+		return nil
+	}
+
+	fe := &FrameworkError{
+		Code:      err.Code,
+		Message:   err.Message,
+		Cause:     err.Cause,
+		Timestamp: err.Timestamp,
+		ServiceId: err.ServiceID,
+		origin:    err,
+	}
+
+	switch {
+	case len(err.Fields) == 0:
+		if err.Details != "" {
+			fe.Details = err.Details
+		}
+	case len(err.Fields) == 1 && err.Details == "" && err.Fields[DetailsField] != nil:
+		fe.Details = err.Fields[DetailsField]
+	default:
+		details := make(map[string]interface{}, len(err.Fields)+1)
+		for key, value := range err.Fields {
+			details[key] = value
+		}
+		if err.Details != "" {
+			details[DetailsField] = err.Details
+		}
+		fe.Details = details
+	}
+
+	for _, frame := range err.StackTrace() {
+		fe.StackTrace = append(fe.StackTrace, fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line))
+	}
+	return fe
+}
+
+// NewErrorPayload 将错误转换为跨语言传输格式
+//
+// 错误未携带追踪 ID 时使用 ctx 中 span 的追踪 ID，localizedMessage 使用 ctx 中的语言（见 ExtractTraceContext）
+func NewErrorPayload(ctx context.Context, err error) *frameworkerrors.ErrorPayload {
+	payload := frameworkerrors.PayloadFromError(err)
+	if payload == nil {
+		return nil
+	}
+	if payload.TraceID == "" {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			payload.TraceID = spanContext.TraceID().String()
+		}
+	}
+	return payload.Localize(frameworkerrors.LocaleFromContext(ctx))
+}
+
+// NewProblemDetails 将错误转换为 RFC 7807 Problem Details，err 为 nil 时返回 nil
+//
+// 与 NewErrorPayload 相同，instance 为 context 中的追踪 ID，消息按 context 中的语言本地化；
+// typeBase 为 problem 类型 URI 的前缀，为空时使用 errors.DefaultProblemTypeBase
+func NewProblemDetails(ctx context.Context, err error, typeBase string) *frameworkerrors.ProblemDetails {
+	payload := NewErrorPayload(ctx, err)
+	if payload == nil {
+		return nil
+	}
+	return payload.ToProblem(typeBase)
+}
+
+// ErrorFromPayload 从跨语言传输格式还原错误，payload 为 nil 时返回 nil
+func ErrorFromPayload(payload *frameworkerrors.ErrorPayload) *FrameworkError {
+	if payload == nil {
+		return nil
+	}
+	return FromUnifiedError(payload.ToFrameworkError())
+}
+
+// sameAs 检查两个错误的公开字段是否一致
+func (e *FrameworkError) sameAs(other *FrameworkError) bool {
+	return e.Code == other.Code &&
+		e.Message == other.Message &&
+		reflect.DeepEqual(e.Cause, other.Cause) &&
+		e.Timestamp == other.Timestamp &&
+		e.ServiceId == other.ServiceId &&
+		reflect.DeepEqual(e.Details, other.Details) &&
+		reflect.DeepEqual(e.StackTrace, other.StackTrace)
+}
@@ -0,0 +1,219 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFrameworkError_ToUnifiedError(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name        string
+		err         *FrameworkError
+		wantDetails string
+		wantFields  map[string]interface{}
+	}{
+		{
+			name:        "字符串详情",
+			err:         &FrameworkError{Code: ErrorConnection, Message: "连接失败", Details: "127.0.0.1:9090", Cause: cause, Timestamp: 1700000000000, ServiceId: "user-service"},
+			wantDetails: "127.0.0.1:9090",
+		},
+		{
+			name:       "结构化详情",
+			err:        &FrameworkError{Code: ErrorSerialization, Message: "序列化失败", Details: map[string]interface{}{"formats": []string{"xml"}}},
+			wantFields: map[string]interface{}{DetailsField: map[string]interface{}{"formats": []string{"xml"}}},
+		},
+		{
+			name: "无详情",
+			err:  &FrameworkError{Code: ErrorNotFound, Message: "服务未找到"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unified := tt.err.ToUnifiedError()
+
+			if unified.Code != tt.err.Code || unified.Message != tt.err.Message || unified.ServiceID != tt.err.ServiceId || unified.Cause != tt.err.Cause {
+				t.Errorf("ToUnifiedError() = %+v", unified)
+			}
+			if tt.err.Timestamp != 0 && unified.Timestamp != tt.err.Timestamp {
+				t.Errorf("Timestamp = %v, want %v", unified.Timestamp, tt.err.Timestamp)
+			}
+			if unified.Details != tt.wantDetails {
+				t.Errorf("Details = %v, want %v", unified.Details, tt.wantDetails)
+			}
+			if len(tt.wantFields) > 0 && !reflect.DeepEqual(unified.Fields, tt.wantFields) {
+				t.Errorf("Fields = %v, want %v", unified.Fields, tt.wantFields)
+			}
+
+			// 转换回 adapter 错误后字段不变
+			back := FromUnifiedError(unified)
+			if back.Code != tt.err.Code || back.Message != tt.err.Message || back.ServiceId != tt.err.ServiceId || back.Cause != tt.err.Cause {
+				t.Errorf("FromUnifiedError() = %+v", back)
+			}
+			if !reflect.DeepEqual(back.Details, tt.err.Details) {
+				t.Errorf("Details = %v, want %v", back.Details, tt.err.Details)
+			}
+		})
+	}
+}
+
+func TestFromUnifiedError(t *testing.T) {
+	if FromUnifiedError(nil) != nil {
+		t.Error("FromUnifiedError(nil) should return nil")
+	}
+
+	unified := frameworkerrors.NewFrameworkErrorWithDetails(frameworkerrors.Timeout, "调用超时", "3s").
+		WithServiceID("order-service").
+		WithDetail("attempt", 2)
+
+	fe := FromUnifiedError(unified)
+	if fe.Code != ErrorTimeout || fe.ServiceId != "order-service" || fe.Timestamp != unified.Timestamp {
+		t.Errorf("FromUnifiedError() = %+v", fe)
+	}
+	// 有其他结构化详情时，字符串详情放在 details 字段中
+	wantDetails := map[string]interface{}{"attempt": 2, DetailsField: "3s"}
+	if !reflect.DeepEqual(fe.Details, wantDetails) {
+		t.Errorf("Details = %v, want %v", fe.Details, wantDetails)
+	}
+	if len(fe.StackTrace) == 0 || !strings.Contains(fe.StackTrace[0], "TestFromUnifiedError") {
+		t.Errorf("StackTrace = %v", fe.StackTrace)
+	}
+
+	// 未修改时转换回原错误，保留调用栈和错误链
+	if fe.ToUnifiedError() != unified {
+		t.Error("ToUnifiedError() should return original error when unchanged")
+	}
+
+	fe.Message = "调用订单服务超时"
+	converted := fe.ToUnifiedError()
+	if converted == unified || converted.Message != fe.Message {
+		t.Errorf("ToUnifiedError() = %+v, want new error with updated message", converted)
+	}
+}
+
+func TestFrameworkError_IsAcrossPackages(t *testing.T) {
+	routeErr := &FrameworkError{Code: ErrorNotFound, Message: "服务未找到"}
+	wrapped := fmt.Errorf("invoke: %w", routeErr)
+	unified := frameworkerrors.Wrap(wrapped, frameworkerrors.ServiceUnavailable, "调用失败")
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"adapter 错误匹配 errors 哨兵", wrapped, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, ""), true},
+		{"adapter 错误匹配 adapter 哨兵", wrapped, &FrameworkError{Code: ErrorNotFound}, true},
+		{"errors 错误匹配 adapter 哨兵", unified, &FrameworkError{Code: ErrorServiceUnavailable}, true},
+		{"经 errors 错误包装后仍可匹配", unified, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "服务未找到"), true},
+		{"消息不一致", wrapped, &FrameworkError{Code: ErrorNotFound, Message: "端点未找到"}, false},
+		{"错误码不一致", unified, &FrameworkError{Code: ErrorTimeout}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameworkError_AsUnified(t *testing.T) {
+	err := fmt.Errorf("route: %w", &FrameworkError{Code: ErrorRouting, Message: "没有可用端点"})
+
+	var unified *frameworkerrors.FrameworkError
+	if !errors.As(err, &unified) {
+		t.Fatal("errors.As should convert adapter error")
+	}
+	if unified.Code != frameworkerrors.RoutingError || unified.Message != "没有可用端点" {
+		t.Errorf("unified = %+v", unified)
+	}
+
+	fe, ok := frameworkerrors.FromError(err)
+	if !ok || !fe.Code.IsFrameworkError() {
+		t.Errorf("FromError() = %v, %v", fe, ok)
+	}
+}
+
+func TestNewErrorPayload(t *testing.T) {
+	if NewErrorPayload(context.Background(), nil) != nil {
+		t.Error("NewErrorPayload(nil) should return nil")
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	payload := NewErrorPayload(ctx, &FrameworkError{Code: ErrorConnection, Message: "连接失败", ServiceId: "user-service"})
+	if payload.Code != 603 || payload.Error != "Connection Error" || !payload.Retryable || payload.ServiceID != "user-service" {
+		t.Errorf("payload = %+v", payload)
+	}
+	if payload.TraceID != traceID.String() {
+		t.Errorf("TraceID = %v, want %v", payload.TraceID, traceID)
+	}
+
+	// 错误自带的追踪 ID 优先
+	unified := frameworkerrors.NewFrameworkError(ErrorInternal, "错误").WithTraceID("origin-trace")
+	if got := NewErrorPayload(ctx, unified).TraceID; got != "origin-trace" {
+		t.Errorf("TraceID = %v, want origin-trace", got)
+	}
+
+	restored := ErrorFromPayload(payload)
+	if restored.Code != ErrorConnection || restored.ServiceId != "user-service" {
+		t.Errorf("ErrorFromPayload() = %+v", restored)
+	}
+	if ErrorFromPayload(nil) != nil {
+		t.Error("ErrorFromPayload(nil) should return nil")
+	}
+}
+
+func TestNewErrorPayload_MultiError(t *testing.T) {
+	multi := frameworkerrors.NewMultiError(3)
+	multi.Add(frameworkerrors.TargetError{Index: 0, Service: "user-service", Endpoint: "10.0.0.1:8080", Attempt: 1, Err: &FrameworkError{Code: ErrorNotFound, Message: "用户不存在"}})
+	multi.Add(frameworkerrors.TargetError{Index: 2, Service: "order-service", Attempt: 3, Err: &FrameworkError{Code: ErrorTimeout, Message: "调用超时"}})
+
+	ctx := frameworkerrors.WithLocale(context.Background(), frameworkerrors.LocaleZhCN)
+	payload := NewErrorPayload(ctx, fmt.Errorf("fan-out: %w", multi))
+	if payload.Code != 500 || payload.Message != "2 of 3 requests failed" || len(payload.Items) != 2 {
+		t.Fatalf("payload = %+v", payload)
+	}
+
+	tests := []struct {
+		item    *frameworkerrors.ItemPayload
+		index   int
+		service string
+		status  int
+		code    int
+	}{
+		{payload.Items[0], 0, "user-service", 404, 404},
+		{payload.Items[1], 2, "order-service", 408, 408},
+	}
+	for _, tt := range tests {
+		if tt.item.Index != tt.index || tt.item.Service != tt.service || tt.item.Status != tt.status || tt.item.Error.Code != tt.code {
+			t.Errorf("item = %+v", tt.item)
+		}
+		// 各目标的错误同样本地化
+		if tt.item.Error.Locale != frameworkerrors.LocaleZhCN {
+			t.Errorf("item locale = %q", tt.item.Error.Locale)
+		}
+	}
+
+	// 转换为 adapter 错误后仍携带各目标的错误
+	adapterErr := FromUnifiedError(multi.ToFrameworkError())
+	if items := NewErrorPayload(context.Background(), adapterErr).Items; len(items) != 2 {
+		t.Errorf("items = %v", items)
+	}
+}
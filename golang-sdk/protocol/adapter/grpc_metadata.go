@@ -0,0 +1,114 @@
+package adapter
+
+import (
+	"encoding/base64"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// HeaderGRPCMethod gRPC 请求的完整方法名，如 /user.UserService/GetUser，由 gRPC 服务端写入外部请求
+const HeaderGRPCMethod = "X-Grpc-Method"
+
+// grpcBinarySuffix gRPC 二进制元数据键的后缀，值以原始字节传输
+const grpcBinarySuffix = "-bin"
+
+// HeadersFromGRPCMetadata 将 gRPC 入站元数据转换为外部请求头
+//
+// 键转为规范格式（如 x-tenant-id 为 X-Tenant-Id），多个值以 ", " 连接；-bin 键的值以标准 Base64 编码；
+// 伪首部（:authority 等）、grpc- 前缀的保留键和 HTTP/2 传输首部不传递
+func HeadersFromGRPCMetadata(md map[string][]string) map[string]string {
+	headers := make(map[string]string, len(md))
+	for key, values := range md {
+		key = strings.ToLower(key)
+		if len(values) == 0 || reservedGRPCKey(key) {
+			continue
+		}
+		if strings.HasSuffix(key, grpcBinarySuffix) {
+			encoded := make([]string, len(values))
+			for i, value := range values {
+				encoded[i] = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			values = encoded
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(key)] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// GRPCMetadataFromHeaders 将响应头转换为 gRPC 元数据，用作 header 或 trailer
+//
+// 键转为小写；-bin 键的值按标准 Base64 解码，解码失败时原样发送；值含非可打印 ASCII 字符时
+// 改用 <键>-bin 以原始字节发送，避免 gRPC 拒绝整个响应。保留键和不合法的键被忽略
+func GRPCMetadataFromHeaders(headers map[string]string) map[string][]string {
+	md := make(map[string][]string, len(headers))
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	// 排序使大小写不同的同名键按确定顺序合并
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := headers[key]
+		lower := strings.ToLower(key)
+		if reservedGRPCKey(lower) || !validGRPCKey(lower) {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(lower, grpcBinarySuffix):
+			if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+				value = string(decoded)
+			}
+		case !printableASCII(value):
+			lower += grpcBinarySuffix
+		}
+		md[lower] = append(md[lower], value)
+	}
+	return md
+}
+
+// reservedGRPCKey 判断元数据键是否由 gRPC 或 HTTP/2 传输层使用
+func reservedGRPCKey(key string) bool {
+	if strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") {
+		return true
+	}
+	switch key {
+	case "content-type", "te", "connection", "transfer-encoding", "upgrade", "keep-alive", "proxy-connection":
+		return true
+	}
+	return false
+}
+
+// validGRPCKey 判断是否为合法的 gRPC 元数据键（小写字母、数字、-、_、.）
+func validGRPCKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// printableASCII 判断值是否只包含可打印 ASCII 字符，非 -bin 元数据值的要求
+func printableASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// splitGRPCMethod 将 /package.Service/Method 拆分为服务名和方法名
+func splitGRPCMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
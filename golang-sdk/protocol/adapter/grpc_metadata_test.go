@@ -0,0 +1,127 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestHeadersFromGRPCMetadata 测试 gRPC 入站元数据转换为请求头
+func TestHeadersFromGRPCMetadata(t *testing.T) {
+	headers := HeadersFromGRPCMetadata(map[string][]string{
+		"x-tenant-id":   {"tenant-a"},
+		"x-roles":       {"admin", "ops"},
+		"x-token-bin":   {"\x00\x01"},
+		"traceparent":   {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		":authority":    {"localhost:9090"},
+		"grpc-timeout":  {"1S"},
+		"content-type":  {"application/grpc"},
+		"x-empty-value": {},
+	})
+
+	want := map[string]string{
+		"X-Tenant-Id": "tenant-a",
+		"X-Roles":     "admin, ops",
+		"X-Token-Bin": "AAE=",
+		"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	if len(headers) != len(want) {
+		t.Errorf("Expected %d headers, got %v", len(want), headers)
+	}
+	for key, value := range want {
+		if headers[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, headers[key])
+		}
+	}
+}
+
+// TestGRPCMetadataFromHeaders 测试响应头转换为 gRPC 元数据
+func TestGRPCMetadataFromHeaders(t *testing.T) {
+	md := GRPCMetadataFromHeaders(map[string]string{
+		"X-Request-Id": "req-1",
+		"X-Token-Bin":  "AAE=",
+		"X-Note":       "库存不足",
+		"Content-Type": "application/json",
+		"Bad Key":      "value",
+	})
+
+	if got := md["x-request-id"]; len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("Expected x-request-id req-1, got %v", got)
+	}
+	if got := md["x-token-bin"]; len(got) != 1 || got[0] != "\x00\x01" {
+		t.Errorf("Expected decoded binary value, got %q", got)
+	}
+	// 非 ASCII 值改用 -bin 键发送原始字节
+	if got := md["x-note-bin"]; len(got) != 1 || got[0] != "库存不足" {
+		t.Errorf("Expected non-ASCII value under x-note-bin, got %v", md)
+	}
+	if _, ok := md["content-type"]; ok {
+		t.Error("Expected reserved key to be dropped")
+	}
+	if len(md) != 3 {
+		t.Errorf("Expected 3 keys, got %v", md)
+	}
+}
+
+// TestDefaultProtocolAdapter_GRPC 测试 gRPC 请求和响应的转换
+func TestDefaultProtocolAdapter_GRPC(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	ctx := context.Background()
+
+	external := &ExternalRequest{
+		Protocol: ProtocolGRPC,
+		Headers: HeadersFromGRPCMetadata(map[string][]string{
+			"x-client-version": {"1.2.0"},
+		}),
+		Body: map[string]interface{}{"userId": "123"},
+	}
+	external.Headers[HeaderGRPCMethod] = "/user.UserService/GetUser"
+
+	internal, err := adapter.TransformRequest(WithSecurityContext(ctx, &SecurityContext{UserID: "user-1", TenantID: "tenant-a"}), external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if internal.Service != "user.UserService" || internal.Method != "GetUser" {
+		t.Errorf("Expected user.UserService.GetUser, got %s.%s", internal.Service, internal.Method)
+	}
+	if internal.Headers["X-Client-Version"] != "1.2.0" || internal.Metadata[MetadataTenantID] != "tenant-a" {
+		t.Errorf("Expected metadata to be mapped, got %v %v", internal.Headers, internal.Metadata)
+	}
+
+	// 没有方法名的请求
+	if _, err := adapter.TransformRequest(ctx, &ExternalRequest{Protocol: ProtocolGRPC, Headers: map[string]string{}}); err == nil {
+		t.Error("Expected error for gRPC request without method")
+	}
+
+	// 成功响应的状态码为 OK
+	response, err := adapter.TransformResponse(ctx, &InternalResponse{Payload: []byte(`{"name":"John"}`)}, ProtocolGRPC)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	if response.StatusCode != 0 {
+		t.Errorf("Expected gRPC status OK, got %d", response.StatusCode)
+	}
+
+	// 错误响应的状态码为 gRPC 状态码，响应体为结构化错误
+	response, err = adapter.TransformResponse(ctx, &InternalResponse{
+		Headers: map[string]string{"X-Request-Id": "req-1"},
+		Error:   &FrameworkError{Code: ErrorNotFound, Message: "User not found", Details: map[string]interface{}{"userId": "123"}},
+	}, ProtocolGRPC)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	if response.StatusCode != 5 {
+		t.Errorf("Expected gRPC status NOT_FOUND, got %d", response.StatusCode)
+	}
+	if response.Headers["X-Request-Id"] != "req-1" {
+		t.Errorf("Expected response headers to be kept, got %v", response.Headers)
+	}
+	data, _ := json.Marshal(response.Body)
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("Unmarshal body failed: %v", err)
+	}
+	if body["code"] != float64(404) || body["message"] != "User not found" {
+		t.Errorf("Unexpected error body: %v", body)
+	}
+}
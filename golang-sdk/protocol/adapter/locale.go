@@ -0,0 +1,29 @@
+package adapter
+
+import (
+	"context"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// HeaderAcceptLanguage 客户端的语言偏好，决定错误消息使用的语言，随追踪上下文在内部调用间传递
+const HeaderAcceptLanguage = "Accept-Language"
+
+// MetadataLocale 内部请求元数据中协商得到的语言，如 zh-CN
+const MetadataLocale = "locale"
+
+// injectLocale 将 context 中的语言写入请求头
+func injectLocale(ctx context.Context, headers map[string]string) {
+	if locale := frameworkerrors.LocaleFromContext(ctx); locale != "" {
+		headerCarrier(headers).Set(HeaderAcceptLanguage, locale)
+	}
+}
+
+// extractLocale 按请求头 Accept-Language 选择支持的语言并写入 context
+func extractLocale(ctx context.Context, headers map[string]string) context.Context {
+	header := headerCarrier(headers).Get(HeaderAcceptLanguage)
+	if header == "" {
+		return ctx
+	}
+	return frameworkerrors.WithLocale(ctx, frameworkerrors.MatchLocale(header))
+}
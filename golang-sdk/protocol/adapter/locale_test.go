@@ -0,0 +1,91 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+func TestLocalePropagation(t *testing.T) {
+	ctx := ExtractTraceContext(context.Background(), map[string]string{
+		"accept-language": "fr-FR, zh;q=0.8",
+	})
+	if got := frameworkerrors.LocaleFromContext(ctx); got != frameworkerrors.LocaleZhCN {
+		t.Fatalf("locale = %q, want %q", got, frameworkerrors.LocaleZhCN)
+	}
+
+	headers := make(map[string]string)
+	InjectTraceContext(ctx, headers)
+	if headers[HeaderAcceptLanguage] != frameworkerrors.LocaleZhCN {
+		t.Errorf("Accept-Language = %q, want %q", headers[HeaderAcceptLanguage], frameworkerrors.LocaleZhCN)
+	}
+
+	// 未指定语言时不写入
+	empty := make(map[string]string)
+	InjectTraceContext(context.Background(), empty)
+	if _, ok := empty[HeaderAcceptLanguage]; ok {
+		t.Errorf("unexpected Accept-Language %q", empty[HeaderAcceptLanguage])
+	}
+}
+
+func TestTransformRequest_Locale(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		extra    map[string]string
+		expected string
+	}{
+		{"按 Accept-Language 选择", map[string]string{"Accept-Language": "zh-CN,zh;q=0.9"}, nil, frameworkerrors.LocaleZhCN},
+		{"元数据优先", map[string]string{"Accept-Language": "zh-CN"}, map[string]string{MetadataLocale: "en"}, frameworkerrors.LocaleEnUS},
+		{"未指定", nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"X-Service-Name": "user-service", "X-Method-Name": "GetUser"}
+			for k, v := range tt.headers {
+				headers[k] = v
+			}
+			internal, err := adapter.TransformRequest(context.Background(), &ExternalRequest{
+				Protocol: ProtocolREST,
+				Headers:  headers,
+				Metadata: &RequestMetadata{Extra: tt.extra},
+			})
+			if err != nil {
+				t.Fatalf("TransformRequest failed: %v", err)
+			}
+			if got := internal.Metadata[MetadataLocale]; got != tt.expected {
+				t.Errorf("locale = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTransformResponse_LocalizedError(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	ctx := frameworkerrors.WithLocale(context.Background(), frameworkerrors.LocaleZhCN)
+
+	resp, err := adapter.TransformResponse(ctx, &InternalResponse{
+		Error: &FrameworkError{Code: ErrorNotFound, Message: "user 42 not found"},
+	}, ProtocolREST)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	payload := resp.Body.(*frameworkerrors.ErrorPayload)
+	if payload.Locale != frameworkerrors.LocaleZhCN || payload.LocalizedMessage != "请求的资源不存在" || payload.Message != "user 42 not found" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// ctx 未指定语言时使用响应元数据中的语言
+	resp, _ = adapter.TransformResponse(context.Background(), &InternalResponse{
+		Error:    &FrameworkError{Code: ErrorTimeout, Message: "timeout"},
+		Metadata: map[string]string{MetadataLocale: frameworkerrors.LocaleZhCN},
+	}, ProtocolJSONRPC)
+	data := resp.Body.(map[string]interface{})["error"].(map[string]interface{})["data"].(*frameworkerrors.ErrorPayload)
+	if data.LocalizedMessage != "请求超时" {
+		t.Errorf("LocalizedMessage = %q, want 请求超时", data.LocalizedMessage)
+	}
+}
@@ -0,0 +1,76 @@
+package adapter
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 协议转换阶段
+const (
+	stageRequest  = "request"
+	stageResponse = "response"
+)
+
+var (
+	// 用于防止重复注册的锁
+	adapterMetricsOnce sync.Once
+	// 协议转换计数器
+	transformTotal *prometheus.CounterVec
+	// 协议转换耗时直方图
+	transformDuration *prometheus.HistogramVec
+)
+
+// initAdapterMetrics 初始化协议适配器指标
+func initAdapterMetrics() {
+	adapterMetricsOnce.Do(func() {
+		transformTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_adapter_transform_total",
+				Help: "Total number of protocol transformations",
+			},
+			[]string{"protocol", "service", "method", "stage", "error_code"},
+		)
+		transformDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_adapter_transform_duration_seconds",
+				Help:    "Protocol transformation duration in seconds",
+				Buckets: []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05},
+			},
+			[]string{"protocol", "service", "method", "stage"},
+		)
+	})
+}
+
+// recordTransform 记录一次协议转换
+func recordTransform(stage string, protocol ProtocolType, service, method string, err error, duration time.Duration) {
+	initAdapterMetrics()
+	transformTotal.WithLabelValues(string(protocol), service, method, stage, ErrorCodeLabel(err)).Inc()
+	transformDuration.WithLabelValues(string(protocol), service, method, stage).Observe(duration.Seconds())
+}
+
+// ErrorCodeLabel 返回错误对应的指标标签值
+//
+// 成功为 "ok"，adapter 或 errors 包的框架错误为错误码，context 超时为 "408"、取消为 "499"，其他错误为 "unknown"
+func ErrorCodeLabel(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	var fe *FrameworkError
+	if errors.As(err, &fe) {
+		return strconv.Itoa(int(fe.Code))
+	}
+	if unified, ok := frameworkerrors.FromError(err); ok {
+		return strconv.Itoa(unified.Code.Code())
+	}
+	if code, ok := frameworkerrors.ContextErrorCode(err); ok {
+		return strconv.Itoa(code.Code())
+	}
+	return "unknown"
+}
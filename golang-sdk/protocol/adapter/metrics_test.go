@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestErrorCodeLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", err: nil, want: "ok"},
+		{name: "framework error", err: &FrameworkError{Code: ErrorRouting}, want: "602"},
+		{name: "wrapped framework error", err: fmt.Errorf("route: %w", &FrameworkError{Code: ErrorNotFound}), want: "404"},
+		{name: "unified framework error", err: frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "熔断"), want: "503"},
+		{name: "deadline exceeded", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: "408"},
+		{name: "canceled", err: context.Canceled, want: "499"},
+		{name: "other error", err: errors.New("boom"), want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeLabel(tt.err); got != tt.want {
+				t.Errorf("ErrorCodeLabel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformRequestRecordsMetrics(t *testing.T) {
+	a := NewDefaultProtocolAdapter()
+
+	// 一次成功转换，一次不支持的协议
+	_, err := a.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers:  map[string]string{"X-Service-Name": "user-service", "X-Method-Name": "GetUser"},
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest() error = %v", err)
+	}
+	a.TransformRequest(context.Background(), &ExternalRequest{Protocol: "unknown"})
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, family := range families {
+		if family.GetName() != "framework_adapter_transform_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			found[labels["protocol"]+"|"+labels["service"]+"|"+labels["error_code"]] = true
+		}
+	}
+
+	for _, key := range []string{"REST|user-service|ok", "unknown||600"} {
+		if !found[key] {
+			t.Errorf("expected transform metric with labels %s", key)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+)
+
+// 跨语言安全上下文的标准请求头
+//
+// Java/PHP 服务读取同名请求头（gRPC 中为小写元数据键）即可获得调用方身份，无需重复认证
+const (
+	HeaderAuthUserID = "X-Auth-User-Id"
+	HeaderAuthRoles  = "X-Auth-Roles"
+	HeaderAuthMethod = "X-Auth-Method"
+)
+
+// 认证方式
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "apikey"
+	AuthMethodMTLS   = "mtls"
+)
+
+// SecurityContext 安全上下文
+type SecurityContext struct {
+	UserID     string   // 用户 ID
+	Roles      []string // 角色列表
+	TenantID   string   // 租户 ID
+	AuthMethod string   // 认证方式
+}
+
+// SecurityHeaders 返回所有安全上下文请求头名称
+func SecurityHeaders() []string {
+	return []string{HeaderAuthUserID, HeaderAuthRoles, HeaderTenantID, HeaderAuthMethod}
+}
+
+// ToHeaders 转换为标准请求头
+func (s *SecurityContext) ToHeaders() map[string]string {
+	headers := make(map[string]string)
+	if s == nil {
+		return headers
+	}
+	if s.UserID != "" {
+		headers[HeaderAuthUserID] = s.UserID
+	}
+	if len(s.Roles) > 0 {
+		headers[HeaderAuthRoles] = strings.Join(s.Roles, ",")
+	}
+	if s.TenantID != "" {
+		headers[HeaderTenantID] = s.TenantID
+	}
+	if s.AuthMethod != "" {
+		headers[HeaderAuthMethod] = s.AuthMethod
+	}
+	return headers
+}
+
+// SecurityContextFromHeaders 从请求头解析安全上下文，未携带身份信息时返回 nil
+//
+// 请求头名称大小写不敏感，兼容 gRPC 小写元数据键
+func SecurityContextFromHeaders(headers map[string]string) *SecurityContext {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	sc := &SecurityContext{}
+	for key, value := range headers {
+		switch {
+		case strings.EqualFold(key, HeaderAuthUserID):
+			sc.UserID = value
+		case strings.EqualFold(key, HeaderAuthRoles):
+			for _, role := range strings.Split(value, ",") {
+				if role = strings.TrimSpace(role); role != "" {
+					sc.Roles = append(sc.Roles, role)
+				}
+			}
+		case strings.EqualFold(key, HeaderTenantID):
+			sc.TenantID = value
+		case strings.EqualFold(key, HeaderAuthMethod):
+			sc.AuthMethod = value
+		}
+	}
+
+	if sc.UserID == "" && len(sc.Roles) == 0 && sc.TenantID == "" && sc.AuthMethod == "" {
+		return nil
+	}
+	return sc
+}
+
+// securityContextKey 安全上下文的上下文键
+type securityContextKey struct{}
+
+// WithSecurityContext 将安全上下文写入 context
+func WithSecurityContext(ctx context.Context, sc *SecurityContext) context.Context {
+	return context.WithValue(ctx, securityContextKey{}, sc)
+}
+
+// SecurityContextFromContext 从 context 读取安全上下文
+func SecurityContextFromContext(ctx context.Context) *SecurityContext {
+	if ctx == nil {
+		return nil
+	}
+	sc, _ := ctx.Value(securityContextKey{}).(*SecurityContext)
+	return sc
+}
+
+// stripIdentityHeaders 删除外部请求中可能被伪造的身份请求头
+//
+// 身份信息只能来自认证后写入 context 的 SecurityContext
+func stripIdentityHeaders(headers map[string]string) {
+	for key := range headers {
+		if strings.EqualFold(key, HeaderAuthUserID) ||
+			strings.EqualFold(key, HeaderAuthRoles) ||
+			strings.EqualFold(key, HeaderTenantID) ||
+			strings.EqualFold(key, HeaderAuthMethod) {
+			delete(headers, key)
+		}
+	}
+}
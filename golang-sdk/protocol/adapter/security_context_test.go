@@ -0,0 +1,107 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSecurityContext_HeadersRoundTrip(t *testing.T) {
+	sc := &SecurityContext{
+		UserID:     "user-1",
+		Roles:      []string{"admin", "user"},
+		TenantID:   "tenant-a",
+		AuthMethod: AuthMethodJWT,
+	}
+
+	headers := sc.ToHeaders()
+	if headers[HeaderAuthRoles] != "admin,user" {
+		t.Errorf("Expected roles 'admin,user', got '%s'", headers[HeaderAuthRoles])
+	}
+
+	parsed := SecurityContextFromHeaders(headers)
+	if parsed == nil {
+		t.Fatal("SecurityContextFromHeaders returned nil")
+	}
+	if parsed.UserID != sc.UserID || parsed.TenantID != sc.TenantID || parsed.AuthMethod != sc.AuthMethod {
+		t.Errorf("Parsed security context mismatch: %+v", parsed)
+	}
+	if len(parsed.Roles) != 2 {
+		t.Errorf("Expected 2 roles, got %d", len(parsed.Roles))
+	}
+}
+
+func TestSecurityContextFromHeaders_CaseInsensitive(t *testing.T) {
+	// gRPC 元数据键为小写
+	parsed := SecurityContextFromHeaders(map[string]string{
+		"x-auth-user-id": "user-1",
+		"x-auth-roles":   "user",
+	})
+	if parsed == nil || parsed.UserID != "user-1" {
+		t.Errorf("Expected user-1, got %+v", parsed)
+	}
+
+	if SecurityContextFromHeaders(map[string]string{"Content-Type": "application/json"}) != nil {
+		t.Error("Expected nil security context without identity headers")
+	}
+}
+
+func TestDefaultProtocolAdapter_TransformRequest_SecurityContext(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	ctx := WithSecurityContext(context.Background(), &SecurityContext{
+		UserID:     "user-1",
+		Roles:      []string{"user"},
+		TenantID:   "tenant-a",
+		AuthMethod: AuthMethodAPIKey,
+	})
+
+	external := &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "getUser",
+			HeaderAuthRoles:  "admin", // 伪造的角色应被丢弃
+		},
+	}
+
+	internal, err := adapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+
+	if internal.Headers[HeaderAuthUserID] != "user-1" {
+		t.Errorf("Expected user 'user-1', got '%s'", internal.Headers[HeaderAuthUserID])
+	}
+	if internal.Headers[HeaderAuthRoles] != "user" {
+		t.Errorf("Expected roles 'user', got '%s'", internal.Headers[HeaderAuthRoles])
+	}
+	if internal.Metadata[MetadataTenantID] != "tenant-a" {
+		t.Errorf("Expected tenant 'tenant-a', got '%s'", internal.Metadata[MetadataTenantID])
+	}
+}
+
+func TestDefaultProtocolAdapter_TransformRequest_StripsSpoofedIdentity(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	external := &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "getUser",
+			HeaderAuthUserID: "attacker",
+			HeaderTenantID:   "tenant-b",
+		},
+	}
+
+	internal, err := adapter.TransformRequest(context.Background(), external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+
+	if _, exists := internal.Headers[HeaderAuthUserID]; exists {
+		t.Error("Unauthenticated identity header should be stripped")
+	}
+	if _, exists := internal.Headers[HeaderTenantID]; exists {
+		t.Error("Unauthenticated tenant header should be stripped")
+	}
+}
@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/framework/golang-sdk/serializer"
+)
+
+// MetadataSerialization 内部请求元数据中协商得到的序列化格式
+const MetadataSerialization = "serialization"
+
+// SetSerializerRegistry 设置协商序列化格式时可用的序列化器，应在开始处理请求前调用
+//
+// 未设置时只支持 JSON
+func (a *DefaultProtocolAdapter) SetSerializerRegistry(registry *serializer.SerializerRegistry) {
+	a.serializers = registry
+}
+
+// NegotiateSerialization 按目标端点支持的序列化格式重新序列化请求体
+//
+// formats 为端点声明的序列化格式（如 router.ServiceEndpoint.Serializations），为空时视为只支持 JSON。
+// 按 protobuf > msgpack > json 依次尝试双方都支持的格式，使用第一个能序列化请求体的格式；
+// 请求体为 serializer.RawPayload 且对方支持其格式时直接使用该格式，不重新编码。
+// 结果写入 internal.Payload 并记录在元数据 serialization 中。JSON 格式遵循编码方式设置
+func (a *DefaultProtocolAdapter) NegotiateSerialization(internal *InternalRequest, body interface{}, formats []string) error {
+	if internal == nil {
+		return &FrameworkError{
+			Code:    ErrorBadRequest,
+			Message: "internal request is nil",
+		}
+	}
+
+	local := a.localFormats()
+	raw, isRaw := serializer.AsRawPayload(body)
+	if isRaw {
+		// 已编码的负载无需本地序列化器即可原样转发
+		local = append(local, raw.Format)
+	}
+	candidates := serializer.Candidates(local, serializer.ParseFormats(formats))
+	if isRaw {
+		candidates = preferFormat(candidates, raw.Format)
+	}
+	if len(candidates) == 0 {
+		return &FrameworkError{
+			Code:    ErrorSerialization,
+			Message: fmt.Sprintf("no mutually supported serialization format for service %s", internal.Service),
+			Details: map[string]interface{}{"formats": formats},
+		}
+	}
+
+	var lastErr error
+	for _, format := range candidates {
+		payload, err := a.serializeAs(format, body)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		internal.Payload = payload
+		if internal.Metadata == nil {
+			internal.Metadata = make(map[string]string)
+		}
+		internal.Metadata[MetadataSerialization] = string(format)
+		return nil
+	}
+
+	return &FrameworkError{
+		Code:    ErrorSerialization,
+		Message: fmt.Sprintf("failed to serialize request body for service %s", internal.Service),
+		Cause:   lastErr,
+	}
+}
+
+// localFormats 返回本地支持的序列化格式
+func (a *DefaultProtocolAdapter) localFormats() []serializer.SerializationFormat {
+	if a.serializers == nil {
+		return []serializer.SerializationFormat{serializer.JSON}
+	}
+	return a.serializers.GetSupportedFormats()
+}
+
+// serializeAs 按指定格式序列化请求体
+func (a *DefaultProtocolAdapter) serializeAs(format serializer.SerializationFormat, body interface{}) ([]byte, error) {
+	if format == serializer.JSON {
+		return a.serializePayload(body)
+	}
+
+	if body == nil {
+		return []byte{}, nil
+	}
+	if a.serializers == nil {
+		if raw, ok := serializer.AsRawPayload(body); ok && raw.Format == format {
+			return raw.Data, nil
+		}
+		return nil, fmt.Errorf("serializer not found for format: %s", format)
+	}
+	return a.serializers.Serialize(format, body)
+}
+
+// preferFormat 将已编码负载的格式移到候选列表最前，格式一致时无需重新编码
+func preferFormat(candidates []serializer.SerializationFormat, format serializer.SerializationFormat) []serializer.SerializationFormat {
+	for i, candidate := range candidates {
+		if candidate == format {
+			preferred := make([]serializer.SerializationFormat, 0, len(candidates))
+			preferred = append(preferred, format)
+			preferred = append(preferred, candidates[:i]...)
+			return append(preferred, candidates[i+1:]...)
+		}
+	}
+	return candidates
+}
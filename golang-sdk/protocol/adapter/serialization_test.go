@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/framework/golang-sdk/serializer"
+)
+
+// protoStub 测试用的 protobuf 序列化器，只能序列化 protoMessage
+type protoStub struct{}
+
+// protoMessage 测试用的 protobuf 消息
+type protoMessage struct{ id string }
+
+func (s *protoStub) Serialize(data interface{}) ([]byte, error) {
+	msg, ok := data.(*protoMessage)
+	if !ok {
+		return nil, fmt.Errorf("expected proto message, got %T", data)
+	}
+	return []byte("pb:" + msg.id), nil
+}
+
+func (s *protoStub) Deserialize(data []byte, target interface{}) error {
+	return nil
+}
+
+func (s *protoStub) GetFormat() serializer.SerializationFormat {
+	return serializer.PROTOBUF
+}
+
+func TestDefaultProtocolAdapter_NegotiateSerialization(t *testing.T) {
+	registry := serializer.NewSerializerRegistry()
+	registry.Register(&protoStub{})
+
+	adapter := NewDefaultProtocolAdapter()
+	adapter.SetSerializerRegistry(registry)
+
+	tests := []struct {
+		name            string
+		body            interface{}
+		formats         []string
+		expectedFormat  string
+		expectedPayload string
+	}{
+		{
+			name:            "protobuf preferred",
+			body:            &protoMessage{id: "42"},
+			formats:         []string{"json", "protobuf"},
+			expectedFormat:  "protobuf",
+			expectedPayload: "pb:42",
+		},
+		{
+			name:            "fall back to json when body is not a proto message",
+			body:            map[string]interface{}{"id": "42"},
+			formats:         []string{"protobuf", "json"},
+			expectedFormat:  "json",
+			expectedPayload: `{"id":"42"}`,
+		},
+		{
+			name:            "endpoint without declaration",
+			body:            map[string]interface{}{"id": "42"},
+			expectedFormat:  "json",
+			expectedPayload: `{"id":"42"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			internal := &InternalRequest{Service: "order-service", Metadata: map[string]string{}}
+			if err := adapter.NegotiateSerialization(internal, tt.body, tt.formats); err != nil {
+				t.Fatalf("NegotiateSerialization failed: %v", err)
+			}
+			if internal.Metadata[MetadataSerialization] != tt.expectedFormat {
+				t.Errorf("Expected format '%s', got '%s'", tt.expectedFormat, internal.Metadata[MetadataSerialization])
+			}
+			if string(internal.Payload) != tt.expectedPayload {
+				t.Errorf("Expected payload %s, got %s", tt.expectedPayload, internal.Payload)
+			}
+		})
+	}
+}
+
+func TestDefaultProtocolAdapter_NegotiateSerialization_Errors(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	// 未设置序列化器时只支持 JSON
+	err := adapter.NegotiateSerialization(&InternalRequest{Service: "order-service"}, nil, []string{"protobuf"})
+	if fe, ok := err.(*FrameworkError); !ok || fe.Code != ErrorSerialization {
+		t.Errorf("Expected serialization error, got %v", err)
+	}
+
+	// protobuf 无法序列化且对方不支持 JSON
+	registry := serializer.NewSerializerRegistry()
+	registry.Register(&protoStub{})
+	adapter.SetSerializerRegistry(registry)
+	err = adapter.NegotiateSerialization(&InternalRequest{Service: "order-service"}, "plain", []string{"protobuf"})
+	if fe, ok := err.(*FrameworkError); !ok || fe.Code != ErrorSerialization || fe.Cause == nil {
+		t.Errorf("Expected serialization error with cause, got %v", err)
+	}
+
+	if err := adapter.NegotiateSerialization(nil, nil, nil); err == nil {
+		t.Error("Expected error for nil request")
+	}
+}
+
+func TestDefaultProtocolAdapter_RawPayload(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+	data := []byte(`{"id":9007199254740993}`)
+
+	// JSON 格式的已编码负载原样作为内部请求负载，不经过解码再编码
+	internal, err := adapter.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "order-service",
+			"X-Method-Name":  "create",
+		},
+		Body: serializer.NewRawPayload(serializer.JSON, data),
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if &internal.Payload[0] != &data[0] {
+		t.Error("Expected raw payload to be passed through without copying")
+	}
+
+	// 未设置序列化器时无法将其他格式转码为 JSON
+	_, err = adapter.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "order-service",
+			"X-Method-Name":  "create",
+		},
+		Body: serializer.NewRawPayload(serializer.PROTOBUF, []byte{0x08}),
+	})
+	if err == nil {
+		t.Error("Expected error for non-JSON raw payload without serializer registry")
+	}
+}
+
+func TestDefaultProtocolAdapter_NegotiateSerialization_RawPayload(t *testing.T) {
+	registry := serializer.NewSerializerRegistry()
+	registry.Register(&protoStub{})
+
+	adapter := NewDefaultProtocolAdapter()
+	adapter.SetSerializerRegistry(registry)
+
+	// 对方支持已编码负载的格式时优先使用该格式，即使存在更优的格式
+	raw := serializer.NewRawPayload(serializer.JSON, []byte(`{"id":"42"}`))
+	internal := &InternalRequest{Service: "order-service"}
+	if err := adapter.NegotiateSerialization(internal, raw, []string{"protobuf", "json"}); err != nil {
+		t.Fatalf("NegotiateSerialization failed: %v", err)
+	}
+	if internal.Metadata[MetadataSerialization] != "json" || string(internal.Payload) != `{"id":"42"}` {
+		t.Errorf("Expected json passthrough, got %s %s", internal.Metadata[MetadataSerialization], internal.Payload)
+	}
+
+	// 本地未注册的格式同样可以原样转发
+	msgpack := serializer.NewRawPayload(serializer.MSGPACK, []byte{0x81})
+	internal = &InternalRequest{Service: "order-service"}
+	if err := adapter.NegotiateSerialization(internal, msgpack, []string{"msgpack"}); err != nil {
+		t.Fatalf("NegotiateSerialization failed: %v", err)
+	}
+	if internal.Metadata[MetadataSerialization] != "msgpack" || len(internal.Payload) != 1 {
+		t.Errorf("Expected msgpack passthrough, got %s %v", internal.Metadata[MetadataSerialization], internal.Payload)
+	}
+}
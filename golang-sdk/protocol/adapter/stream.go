@@ -0,0 +1,86 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
+// NDJSONContentType 换行分隔的 JSON（每行一个 JSON 值）的媒体类型，客户端以 Accept 请求流式响应
+const NDJSONContentType = "application/x-ndjson"
+
+// Stream 流式结果，业务方法返回 *Stream 时由生产函数逐项产生元素，协议处理器边产生边发送，不缓冲整个结果集
+//
+// REST 和 JSON-RPC 在客户端请求流式响应时逐项发送；其他协议及未请求流式响应的客户端收到由所有元素组成的 JSON 数组
+type Stream struct {
+	produce func(ctx context.Context, send func(item interface{}) error) error
+}
+
+// NewStream 创建流式结果，produce 对每个元素调用 send，send 返回错误时应停止生产并返回该错误
+//
+// produce 在业务方法返回后、响应发送时执行，返回的错误按协议的错误格式发送给客户端
+func NewStream(produce func(ctx context.Context, send func(item interface{}) error) error) *Stream {
+	return &Stream{produce: produce}
+}
+
+// Each 执行生产函数，对每个元素调用 fn；fn 返回错误或 ctx 结束时停止生产并返回该错误
+//
+// 每次调用都重新执行生产函数
+func (s *Stream) Each(ctx context.Context, fn func(item interface{}) error) error {
+	return s.produce(ctx, func(item interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(item)
+	})
+}
+
+// Collect 收集所有元素，用于不支持流式响应的协议和客户端
+func (s *Stream) Collect(ctx context.Context) ([]interface{}, error) {
+	items := make([]interface{}, 0)
+	err := s.Each(ctx, func(item interface{}) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarshalJSON 将所有元素编码为 JSON 数组，使只按 JSON 编码结果的协议无需识别 Stream
+func (s *Stream) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	count := 0
+	err := s.Each(context.Background(), func(item interface{}) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(data)
+		count++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// AcceptsNDJSON 检查 Accept 请求头是否请求 NDJSON 流式响应
+func AcceptsNDJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// countStream 产生 0 到 n-1，产生 failAt 时返回错误，failAt 为负数时不出错
+func countStream(n, failAt int) *Stream {
+	return NewStream(func(ctx context.Context, send func(item interface{}) error) error {
+		for i := 0; i < n; i++ {
+			if i == failAt {
+				return errors.New("producer failed")
+			}
+			if err := send(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestStream(t *testing.T) {
+	items, err := countStream(3, -1).Collect(context.Background())
+	if err != nil || !reflect.DeepEqual(items, []interface{}{0, 1, 2}) {
+		t.Errorf("Collect = %v %v, want [0 1 2]", items, err)
+	}
+
+	// 编码为 JSON 数组，空流编码为 []
+	data, err := json.Marshal(map[string]interface{}{"items": countStream(3, -1), "empty": countStream(0, -1)})
+	if err != nil || string(data) != `{"empty":[],"items":[0,1,2]}` {
+		t.Errorf("Marshal = %s %v", data, err)
+	}
+	if _, err := json.Marshal(countStream(3, 1)); err == nil {
+		t.Error("Expected error from failing producer")
+	}
+
+	// ctx 结束后停止生产
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := 0
+	err = countStream(10, -1).Each(ctx, func(item interface{}) error {
+		sent++
+		if sent == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || sent != 2 {
+		t.Errorf("Each = %v after %d items, want context.Canceled after 2", err, sent)
+	}
+}
+
+func TestAcceptsNDJSON(t *testing.T) {
+	tests := map[string]bool{
+		"":                     false,
+		"application/json":     false,
+		"application/x-ndjson": true,
+		"application/json, application/x-ndjson;q=0.9": true,
+		"text/*;q=0.5, application/x-ndjson":           true,
+	}
+	for accept, want := range tests {
+		if got := AcceptsNDJSON(accept); got != want {
+			t.Errorf("AcceptsNDJSON(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/framework/golang-sdk/idgen"
+	"github.com/framework/golang-sdk/metadata"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 追踪上下文请求头
+//
+// 出站请求统一使用 W3C Trace Context；入站请求优先解析 traceparent，缺失时回退到 B3，
+// 最后兼容旧的 X-Trace-Id
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+	HeaderB3          = "b3"
+	HeaderB3TraceID   = "X-B3-TraceId"
+	HeaderB3SpanID    = "X-B3-SpanId"
+	HeaderB3Sampled   = "X-B3-Sampled"
+	HeaderTraceID     = "X-Trace-Id"
+)
+
+// traceContextPropagator W3C Trace Context 传播器
+var traceContextPropagator = propagation.TraceContext{}
+
+// TraceHeaders 返回所有追踪上下文请求头名称，包括 baggage、语言偏好和事务 ID；
+// 元数据请求头的名称不固定，以 metadata.HeaderPrefix 开头
+func TraceHeaders() []string {
+	return []string{HeaderTraceParent, HeaderTraceState, HeaderB3, HeaderB3TraceID, HeaderB3SpanID, HeaderB3Sampled, HeaderBaggage, HeaderAcceptLanguage, HeaderTransactionID}
+}
+
+// InjectTraceContext 将 context 中的 span 上下文以 W3C 格式写入请求头，同时写入 baggage、语言偏好、事务 ID 和元数据
+func InjectTraceContext(ctx context.Context, headers map[string]string) {
+	if headers == nil {
+		return
+	}
+	traceContextPropagator.Inject(ctx, headerCarrier(headers))
+	injectBaggage(ctx, headers)
+	injectLocale(ctx, headers)
+	injectTransaction(ctx, headers)
+	metadata.Inject(ctx, headers)
+}
+
+// ExtractTraceContext 从请求头提取远端 span 上下文、baggage、语言偏好、事务 ID 和元数据并写入 context
+//
+// 优先使用 traceparent/tracestate，缺失或无效时回退到 B3 单头或多头格式
+func ExtractTraceContext(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	ctx = extractBaggage(ctx, headers)
+	ctx = extractLocale(ctx, headers)
+	ctx = extractTransaction(ctx, headers)
+	ctx = metadata.Extract(ctx, headers)
+
+	remote := trace.SpanContextFromContext(traceContextPropagator.Extract(context.Background(), headerCarrier(headers)))
+	if remote.IsValid() {
+		return trace.ContextWithRemoteSpanContext(ctx, remote)
+	}
+
+	if sc, ok := extractB3(headers); ok {
+		return trace.ContextWithRemoteSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+// headerCarrier 大小写不敏感的请求头载体
+type headerCarrier map[string]string
+
+// Get 获取请求头
+func (c headerCarrier) Get(key string) string {
+	if value, ok := c[key]; ok {
+		return value
+	}
+	for k, v := range c {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// Set 设置请求头，覆盖大小写不同的同名请求头
+func (c headerCarrier) Set(key, value string) {
+	for k := range c {
+		if strings.EqualFold(k, key) {
+			delete(c, k)
+		}
+	}
+	c[key] = value
+}
+
+// Keys 返回所有请求头名称
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// extractB3 解析 B3 追踪头
+func extractB3(headers map[string]string) (trace.SpanContext, bool) {
+	carrier := headerCarrier(headers)
+
+	var traceID, spanID, sampled string
+	if single := carrier.Get(HeaderB3); single != "" {
+		// 格式: {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}
+		parts := strings.Split(single, "-")
+		if len(parts) < 2 {
+			return trace.SpanContext{}, false
+		}
+		traceID, spanID = parts[0], parts[1]
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+	} else {
+		traceID = carrier.Get(HeaderB3TraceID)
+		spanID = carrier.Get(HeaderB3SpanID)
+		sampled = carrier.Get(HeaderB3Sampled)
+	}
+
+	// B3 允许 64 位追踪 ID，左侧补零为 128 位
+	if len(traceID) == 16 {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	// 只有明确不采样时才不采样；没有采样标志表示上游推迟决定（B3 的 defer），按已采样处理，
+	// 否则 ParentBased 采样器会丢弃这类上游的全部追踪
+	flags := trace.FlagsSampled
+	if sampled == "0" || strings.EqualFold(sampled, "false") {
+		flags = 0
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return sc, sc.IsValid()
+}
+
+// formatTraceParent 生成 W3C traceparent 请求头
+func formatTraceParent(traceID, spanID string, sampled bool) string {
+	flags := "00"
+	if sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", traceID, spanID, flags)
+}
+
+// newTraceID 以 idgen.Default 生成 W3C 格式的追踪 ID（32 位十六进制）
+func newTraceID() string {
+	return idgen.TraceIDHex()
+}
+
+// newSpanID 以 idgen.Default 生成 W3C 格式的 span ID（16 位十六进制）
+func newSpanID() string {
+	return idgen.SpanIDHex()
+}
@@ -0,0 +1,192 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/framework/golang-sdk/metadata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+)
+
+func TestExtractTraceContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantTraceID string
+		wantSampled bool
+	}{
+		{
+			name:        "w3c traceparent",
+			headers:     map[string]string{"Traceparent": testTraceParent},
+			wantTraceID: testTraceID,
+			wantSampled: true,
+		},
+		{
+			name:        "b3 single header",
+			headers:     map[string]string{"b3": testTraceID + "-" + testSpanID + "-1"},
+			wantTraceID: testTraceID,
+			wantSampled: true,
+		},
+		{
+			name: "b3 multi headers with 64-bit trace id",
+			headers: map[string]string{
+				"X-B3-TraceId": "a3ce929d0e0e4736",
+				"X-B3-SpanId":  testSpanID,
+				"X-B3-Sampled": "0",
+			},
+			wantTraceID: "0000000000000000a3ce929d0e0e4736",
+			wantSampled: false,
+		},
+		{
+			name:        "b3 single header without sampling state",
+			headers:     map[string]string{"b3": testTraceID + "-" + testSpanID},
+			wantTraceID: testTraceID,
+			wantSampled: true,
+		},
+		{
+			name: "b3 multi headers without sampled flag",
+			headers: map[string]string{
+				"X-B3-TraceId": testTraceID,
+				"X-B3-SpanId":  testSpanID,
+			},
+			wantTraceID: testTraceID,
+			wantSampled: true,
+		},
+		{
+			name:        "b3 single header not sampled",
+			headers:     map[string]string{"b3": testTraceID + "-" + testSpanID + "-0"},
+			wantTraceID: testTraceID,
+			wantSampled: false,
+		},
+		{
+			name: "w3c takes precedence over b3",
+			headers: map[string]string{
+				"traceparent": testTraceParent,
+				"b3":          "11111111111111111111111111111111-" + testSpanID + "-1",
+			},
+			wantTraceID: testTraceID,
+			wantSampled: true,
+		},
+		{
+			name:    "invalid headers",
+			headers: map[string]string{"traceparent": "garbage", "b3": "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), tt.headers))
+			if tt.wantTraceID == "" {
+				if sc.IsValid() {
+					t.Errorf("Expected no span context, got %s", sc.TraceID())
+				}
+				return
+			}
+			if sc.TraceID().String() != tt.wantTraceID {
+				t.Errorf("Expected trace ID %s, got %s", tt.wantTraceID, sc.TraceID())
+			}
+			if sc.IsSampled() != tt.wantSampled {
+				t.Errorf("Expected sampled %v, got %v", tt.wantSampled, sc.IsSampled())
+			}
+			if !sc.IsRemote() {
+				t.Error("Expected remote span context")
+			}
+		})
+	}
+}
+
+func TestInjectTraceContext(t *testing.T) {
+	ctx := ExtractTraceContext(context.Background(), map[string]string{
+		"traceparent": testTraceParent,
+		"tracestate":  "vendor=value",
+	})
+
+	headers := map[string]string{}
+	InjectTraceContext(ctx, headers)
+
+	if headers[HeaderTraceParent] != testTraceParent {
+		t.Errorf("Expected traceparent %s, got %s", testTraceParent, headers[HeaderTraceParent])
+	}
+	if headers[HeaderTraceState] != "vendor=value" {
+		t.Errorf("Expected tracestate vendor=value, got %s", headers[HeaderTraceState])
+	}
+
+	// 没有 span 上下文时不写入
+	empty := map[string]string{}
+	InjectTraceContext(context.Background(), empty)
+	if len(empty) != 0 {
+		t.Errorf("Expected no headers, got %v", empty)
+	}
+}
+
+func TestTransformRequest_TraceContext(t *testing.T) {
+	adapter := NewDefaultProtocolAdapter()
+
+	// B3 上游请求转换为 W3C 内部请求
+	internal, err := adapter.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "GetUser",
+			"X-B3-TraceId":   testTraceID,
+			"X-B3-SpanId":    testSpanID,
+			"X-B3-Sampled":   "1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+
+	if internal.TraceId != testTraceID {
+		t.Errorf("Expected trace ID %s, got %s", testTraceID, internal.TraceId)
+	}
+	if _, ok := internal.Headers["X-B3-TraceId"]; ok {
+		t.Error("Expected B3 headers to be replaced")
+	}
+
+	want := "00-" + testTraceID + "-" + internal.SpanId + "-01"
+	if internal.Headers[HeaderTraceParent] != want {
+		t.Errorf("Expected traceparent %s, got %s", want, internal.Headers[HeaderTraceParent])
+	}
+
+	// 无追踪上下文时生成新的 W3C 追踪 ID
+	internal, err = adapter.TransformRequest(context.Background(), &ExternalRequest{
+		Protocol: ProtocolREST,
+		Headers: map[string]string{
+			"X-Service-Name": "user-service",
+			"X-Method-Name":  "GetUser",
+		},
+	})
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if !strings.HasPrefix(internal.Headers[HeaderTraceParent], "00-"+internal.TraceId+"-") {
+		t.Errorf("Unexpected traceparent %s for trace ID %s", internal.Headers[HeaderTraceParent], internal.TraceId)
+	}
+}
+
+func TestTraceContextMetadata(t *testing.T) {
+	// 上游服务设置的元数据经请求头到达本服务，本服务发起的调用继续传递
+	upstream := metadata.AppendToOutgoingContext(context.Background(), "tenant", "acme")
+	inbound := make(map[string]string)
+	InjectTraceContext(upstream, inbound)
+
+	ctx := ExtractTraceContext(context.Background(), inbound)
+	if got := metadata.ValueFromContext(ctx, "tenant"); got != "acme" {
+		t.Fatalf("tenant = %q, want acme", got)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "region", "eu-1")
+	outbound := make(map[string]string)
+	InjectTraceContext(ctx, outbound)
+	if outbound["X-Metadata-Tenant"] != "acme" || outbound["X-Metadata-Region"] != "eu-1" {
+		t.Errorf("unexpected outbound headers: %v", outbound)
+	}
+}
@@ -0,0 +1,113 @@
+package adapter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName 框架内置 span 使用的追踪器名称
+const TracerName = "github.com/framework/golang-sdk"
+
+// 框架 span 标准属性
+const (
+	AttrService  = attribute.Key("rpc.service")
+	AttrMethod   = attribute.Key("rpc.method")
+	AttrProtocol = attribute.Key("rpc.system")
+	AttrEndpoint = attribute.Key("server.address")
+	// AttrPeerLanguage 被调用服务实例的实现语言，来自注册信息
+	AttrPeerLanguage = attribute.Key("rpc.peer.language")
+)
+
+// 消息 span 标准属性
+const (
+	AttrMessagingSystem      = attribute.Key("messaging.system")
+	AttrMessagingDestination = attribute.Key("messaging.destination.name")
+	AttrMessagingGroup       = attribute.Key("messaging.consumer.group.name")
+)
+
+// frameworkTracer 返回框架追踪器
+//
+// 每次调用时从全局 TracerProvider 获取，确保导出器在处理器之后注册时也能生效
+func frameworkTracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// StartServerSpan 为入站请求创建服务端 span，ctx 中的远端 span 上下文作为父 span
+func StartServerSpan(ctx context.Context, protocol ProtocolType, service, method string) (context.Context, trace.Span) {
+	return frameworkTracer().Start(ctx, spanName(service, method),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			AttrService.String(service),
+			AttrMethod.String(method),
+			AttrProtocol.String(string(protocol)),
+		),
+	)
+}
+
+// StartClientSpan 为出站调用创建客户端 span
+func StartClientSpan(ctx context.Context, protocol ProtocolType, service, method, endpoint string) (context.Context, trace.Span) {
+	return frameworkTracer().Start(ctx, spanName(service, method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrService.String(service),
+			AttrMethod.String(method),
+			AttrProtocol.String(string(protocol)),
+			AttrEndpoint.String(endpoint),
+		),
+	)
+}
+
+// StartProducerSpan 为发布消息创建生产者 span，system 为消息中间件名称，如 nats
+func StartProducerSpan(ctx context.Context, system, topic string) (context.Context, trace.Span) {
+	return frameworkTracer().Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			AttrMessagingSystem.String(system),
+			AttrMessagingDestination.String(topic),
+		),
+	)
+}
+
+// StartConsumerSpan 为处理订阅的消息创建消费者 span，ctx 中由消息头提取的远端 span 上下文作为父 span
+func StartConsumerSpan(ctx context.Context, system, topic, group string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		AttrMessagingSystem.String(system),
+		AttrMessagingDestination.String(topic),
+	}
+	if group != "" {
+		attrs = append(attrs, AttrMessagingGroup.String(group))
+	}
+	return frameworkTracer().Start(ctx, "process "+topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// StartInternalSpan 为框架内部步骤（路由、负载均衡、获取连接等）创建子 span
+func StartInternalSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return frameworkTracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// EndSpan 记录调用结果并结束 span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// spanName 生成 span 名称，格式为 service/method
+func spanName(service, method string) string {
+	if service == "" {
+		return method
+	}
+	return service + "/" + method
+}
@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// withSpanRecorder 注册记录 span 的全局 TracerProvider，测试结束后恢复
+func withSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestStartServerSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	// 上游通过 traceparent 传入的 span 作为父 span
+	ctx := ExtractTraceContext(context.Background(), map[string]string{
+		HeaderTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+
+	_, span := StartServerSpan(ctx, ProtocolGRPC, "user.UserService", "GetUser")
+	EndSpan(span, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	got := spans[0]
+	if got.Name() != "user.UserService/GetUser" {
+		t.Errorf("span name = %s, want user.UserService/GetUser", got.Name())
+	}
+	if got.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span kind = %v, want server", got.SpanKind())
+	}
+	if got.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("parent span id = %s, want 00f067aa0ba902b7", got.Parent().SpanID())
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range got.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs[string(AttrService)] != "user.UserService" || attrs[string(AttrMethod)] != "GetUser" || attrs[string(AttrProtocol)] != "gRPC" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+}
+
+func TestClientSpanIsChildOfServerSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	ctx, server := StartServerSpan(context.Background(), ProtocolInternalRPC, "", "order.create")
+	_, client := StartClientSpan(ctx, ProtocolGRPC, "inventory", "Reserve", "10.0.0.1:9000")
+	EndSpan(client, errors.New("unavailable"))
+	EndSpan(server, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	clientSpan, serverSpan := spans[0], spans[1]
+	if clientSpan.Parent().SpanID() != serverSpan.SpanContext().SpanID() {
+		t.Error("client span should be a child of the server span")
+	}
+	if clientSpan.Status().Code != codes.Error {
+		t.Errorf("client span status = %v, want error", clientSpan.Status().Code)
+	}
+	if serverSpan.Name() != "order.create" {
+		t.Errorf("span name = %s, want order.create", serverSpan.Name())
+	}
+}
+
+func TestConsumerSpanIsChildOfProducerSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	ctx, producer := StartProducerSpan(context.Background(), "nats", "order.created")
+	headers := make(map[string]string)
+	InjectTraceContext(ctx, headers)
+	EndSpan(producer, nil)
+
+	_, consumer := StartConsumerSpan(ExtractTraceContext(context.Background(), headers), "nats", "order.created", "billing")
+	EndSpan(consumer, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	producerSpan, consumerSpan := spans[0], spans[1]
+	if producerSpan.SpanKind() != trace.SpanKindProducer || consumerSpan.SpanKind() != trace.SpanKindConsumer {
+		t.Errorf("span kinds = %v, %v; want producer, consumer", producerSpan.SpanKind(), consumerSpan.SpanKind())
+	}
+	if consumerSpan.Parent().SpanID() != producerSpan.SpanContext().SpanID() {
+		t.Error("consumer span should be a child of the producer span")
+	}
+	if producerSpan.Name() != "publish order.created" || consumerSpan.Name() != "process order.created" {
+		t.Errorf("span names = %s, %s", producerSpan.Name(), consumerSpan.Name())
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range consumerSpan.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs[string(AttrMessagingSystem)] != "nats" || attrs[string(AttrMessagingGroup)] != "billing" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+}
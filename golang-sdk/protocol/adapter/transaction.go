@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+)
+
+// HeaderTransactionID 分布式事务 ID，随追踪上下文在内部调用间传递，参与方据此关联同一事务的各阶段调用
+const HeaderTransactionID = "X-Transaction-Id"
+
+// MaxTransactionIDLength 事务 ID 的最大长度，超长或含非法字符的请求头被忽略
+const MaxTransactionIDLength = 128
+
+// transactionKey 事务 ID 的 context 键
+type transactionKey struct{}
+
+// WithTransactionID 在 context 中设置事务 ID，跨进程调用时随请求头自动传播
+func WithTransactionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, transactionKey{}, id)
+}
+
+// TransactionIDFromContext 获取 context 中的事务 ID，不在事务中时返回空字符串
+func TransactionIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(transactionKey{}).(string)
+	return id
+}
+
+// injectTransaction 将 context 中的事务 ID 写入请求头
+func injectTransaction(ctx context.Context, headers map[string]string) {
+	if id := TransactionIDFromContext(ctx); id != "" {
+		headerCarrier(headers).Set(HeaderTransactionID, id)
+	}
+}
+
+// extractTransaction 读取请求头中的事务 ID 并写入 context
+func extractTransaction(ctx context.Context, headers map[string]string) context.Context {
+	id := strings.TrimSpace(headerCarrier(headers).Get(HeaderTransactionID))
+	if !isTransactionID(id) {
+		return ctx
+	}
+	return WithTransactionID(ctx, id)
+}
+
+// isTransactionID 检查事务 ID 是否由可打印的 ASCII 字符组成且不超过最大长度
+func isTransactionID(id string) bool {
+	if id == "" || len(id) > MaxTransactionIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTransactionPropagation(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"小写请求头", "tx-0001", "tx-0001"},
+		{"去除首尾空白", "  tx-0002 ", "tx-0002"},
+		{"包含空格时忽略", "tx 0003", ""},
+		{"超长时忽略", strings.Repeat("a", MaxTransactionIDLength+1), ""},
+		{"未指定", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ExtractTraceContext(context.Background(), map[string]string{
+				"x-transaction-id": tt.header,
+			})
+			if got := TransactionIDFromContext(ctx); got != tt.expected {
+				t.Fatalf("transaction id = %q, want %q", got, tt.expected)
+			}
+
+			headers := make(map[string]string)
+			InjectTraceContext(ctx, headers)
+			if got, ok := headers[HeaderTransactionID]; ok != (tt.expected != "") || got != tt.expected {
+				t.Errorf("%s = %q, want %q", HeaderTransactionID, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetadataPath 内部请求元数据中 REST 请求的 URL 路径，转换规则据此提取路径参数
+const MetadataPath = "path"
+
+// TransformRule 单个服务方法的请求转换规则，用于服务接口不兼容的版本之间迁移
+//
+// 字段路径以 . 分隔嵌套字段，如 profile.nickname。规则按以下顺序应用：
+// 删除请求头、重命名字段、写入路径参数、补充默认值
+type TransformRule struct {
+	// Service 服务名称，必填
+	Service string
+	// Method 方法名称，为空或 * 时匹配服务的所有方法
+	Method string
+	// Path REST 路径模板，如 /api/users/{id}/orders/{orderId:int}；设置后只匹配该路径的请求，
+	// 路径参数写入负载的同名字段，:int 表示转换为整数
+	Path string
+	// Rename 字段重命名，键为原字段路径，值为新字段路径
+	Rename map[string]string
+	// Defaults 字段缺失或为 null 时写入的默认值
+	Defaults map[string]interface{}
+	// StripHeaders 删除的请求头，不区分大小写
+	StripHeaders []string
+}
+
+// Transformer 按服务方法应用转换规则
+type Transformer struct {
+	rules []*transformRule
+}
+
+// transformRule 校验后的转换规则
+type transformRule struct {
+	TransformRule
+	segments    []pathSegment
+	renameOrder []string
+}
+
+// pathSegment 路径模板的一段，param 不为空时为路径参数
+type pathSegment struct {
+	literal string
+	param   string
+	integer bool
+}
+
+// NewTransformer 校验并创建转换器，多条规则匹配同一请求时按顺序依次应用
+func NewTransformer(rules []TransformRule) (*Transformer, error) {
+	t := &Transformer{}
+	for i, rule := range rules {
+		if rule.Service == "" {
+			return nil, fmt.Errorf("transform rule %d: service is required", i)
+		}
+		renameOrder := make([]string, 0, len(rule.Rename))
+		for from, to := range rule.Rename {
+			if from == "" || to == "" {
+				return nil, fmt.Errorf("transform rule %d: rename %q -> %q has an empty field", i, from, to)
+			}
+			renameOrder = append(renameOrder, from)
+		}
+		sort.Strings(renameOrder)
+		segments, err := parsePathTemplate(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("transform rule %d: %w", i, err)
+		}
+		t.rules = append(t.rules, &transformRule{TransformRule: rule, segments: segments, renameOrder: renameOrder})
+	}
+	return t, nil
+}
+
+// Dispatcher 返回先应用转换规则再调用 next 的分发器
+func (t *Transformer) Dispatcher(next Dispatcher) Dispatcher {
+	return func(ctx context.Context, request *InternalRequest) (interface{}, error) {
+		if err := t.Apply(request); err != nil {
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
+
+// Apply 对请求应用匹配的规则，修改 request.Payload 和 request.Headers
+//
+// 负载须为 JSON；负载为空时视为空对象，不是 JSON 对象（如位置参数数组）时只删除请求头
+func (t *Transformer) Apply(request *InternalRequest) error {
+	if t == nil || request == nil {
+		return nil
+	}
+
+	var body map[string]interface{}
+	decoded, changed := false, false
+	for _, rule := range t.rules {
+		params, ok := rule.match(request)
+		if !ok {
+			continue
+		}
+
+		for _, name := range rule.StripHeaders {
+			deleteHeader(request.Headers, name)
+		}
+		if len(rule.Rename) == 0 && len(params) == 0 && len(rule.Defaults) == 0 {
+			continue
+		}
+
+		if !decoded {
+			decoded = true
+			var err error
+			if body, err = decodeObject(request.Payload); err != nil {
+				return &FrameworkError{
+					Code:    ErrorBadRequest,
+					Message: fmt.Sprintf("invalid payload for %s.%s", request.Service, request.Method),
+					Cause:   err,
+				}
+			}
+		}
+		if body == nil {
+			continue
+		}
+
+		for _, from := range rule.renameOrder {
+			if value, ok := getField(body, from); ok {
+				deleteField(body, from)
+				setField(body, rule.Rename[from], value)
+			}
+		}
+		for name, value := range params {
+			setField(body, name, value)
+		}
+		for name, value := range rule.Defaults {
+			if current, ok := getField(body, name); !ok || current == nil {
+				setField(body, name, copyValue(value))
+			}
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return &FrameworkError{
+			Code:    ErrorSerialization,
+			Message: "failed to serialize transformed payload",
+			Cause:   err,
+		}
+	}
+	request.Payload = payload
+	return nil
+}
+
+// match 判断规则是否匹配请求，匹配时返回路径参数
+func (r *transformRule) match(request *InternalRequest) (map[string]interface{}, bool) {
+	if r.Service != request.Service || (r.Method != "" && r.Method != "*" && r.Method != request.Method) {
+		return nil, false
+	}
+	if r.segments == nil {
+		return nil, true
+	}
+
+	parts := splitPath(request.Metadata[MetadataPath])
+	if len(parts) != len(r.segments) {
+		return nil, false
+	}
+	params := make(map[string]interface{})
+	for i, segment := range r.segments {
+		switch {
+		case segment.param == "":
+			if parts[i] != segment.literal {
+				return nil, false
+			}
+		case segment.integer:
+			n, err := strconv.ParseInt(parts[i], 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			params[segment.param] = n
+		default:
+			params[segment.param] = parts[i]
+		}
+	}
+	return params, true
+}
+
+// parsePathTemplate 解析路径模板，模板为空时返回 nil
+func parsePathTemplate(template string) ([]pathSegment, error) {
+	if template == "" {
+		return nil, nil
+	}
+	var segments []pathSegment
+	for _, part := range splitPath(template) {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			segments = append(segments, pathSegment{literal: part})
+			continue
+		}
+		name, kind, _ := strings.Cut(part[1:len(part)-1], ":")
+		if name == "" || (kind != "" && kind != "int") {
+			return nil, fmt.Errorf("invalid path parameter %q in %s", part, template)
+		}
+		segments = append(segments, pathSegment{param: name, integer: kind == "int"})
+	}
+	return segments, nil
+}
+
+// splitPath 按 / 分割路径，忽略首尾的 /
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// decodeObject 解码 JSON 对象负载，负载为空时返回空对象，不是对象时返回 nil
+func decodeObject(payload []byte) (map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return make(map[string]interface{}), nil
+	}
+	if trimmed[0] != '{' {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// getField 按字段路径读取值
+func getField(body map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	current := body
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	value, ok := current[keys[len(keys)-1]]
+	return value, ok
+}
+
+// setField 按字段路径写入值，创建缺失的中间对象，中间字段不是对象时覆盖
+func setField(body map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	current := body
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// deleteField 按字段路径删除值
+func deleteField(body map[string]interface{}, path string) {
+	keys := strings.Split(path, ".")
+	current := body
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = next
+	}
+	delete(current, keys[len(keys)-1])
+}
+
+// copyValue 深拷贝默认值中的对象和数组，避免请求修改规则中的默认值
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// deleteHeader 不区分大小写地删除请求头
+func deleteHeader(headers map[string]string, name string) {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			delete(headers, key)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTransformer(t *testing.T) {
+	transformer, err := NewTransformer([]TransformRule{
+		{
+			Service:      "user",
+			StripHeaders: []string{"X-Legacy-Token"},
+		},
+		{
+			Service: "user",
+			Method:  "getUser",
+			Path:    "/api/users/{id:int}/profile/{section}",
+			Rename:  map[string]string{"userName": "name", "profile.nick": "profile.nickname"},
+			Defaults: map[string]interface{}{
+				"locale":  "zh-CN",
+				"options": map[string]interface{}{"verbose": false},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		payload string
+		want    string
+	}{
+		{
+			name:    "重命名并写入路径参数和默认值",
+			method:  "getUser",
+			path:    "/api/users/42/profile/basic",
+			payload: `{"userName":"alice","profile":{"nick":"al"},"locale":"en-US"}`,
+			want:    `{"id":42,"locale":"en-US","name":"alice","options":{"verbose":false},"profile":{"nickname":"al"},"section":"basic"}`,
+		},
+		{
+			name:    "空负载",
+			method:  "getUser",
+			path:    "/api/users/7/profile/all",
+			payload: ``,
+			want:    `{"id":7,"locale":"zh-CN","options":{"verbose":false},"section":"all"}`,
+		},
+		{
+			name:    "路径不匹配时只删除请求头",
+			method:  "getUser",
+			path:    "/api/users/abc/profile/basic",
+			payload: `{"userName":"alice"}`,
+			want:    `{"userName":"alice"}`,
+		},
+		{
+			name:    "其他方法",
+			method:  "listUsers",
+			path:    "/api/users/42/profile/basic",
+			payload: `[1,2]`,
+			want:    `[1,2]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &InternalRequest{
+				Service:  "user",
+				Method:   tt.method,
+				Payload:  []byte(tt.payload),
+				Headers:  map[string]string{"x-legacy-token": "secret", "Authorization": "Bearer t"},
+				Metadata: map[string]string{MetadataPath: tt.path},
+			}
+			if err := transformer.Apply(request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(request.Payload) != tt.want {
+				t.Errorf("payload = %s, want %s", request.Payload, tt.want)
+			}
+			if _, ok := request.Headers["x-legacy-token"]; ok || request.Headers["Authorization"] == "" {
+				t.Errorf("unexpected headers: %v", request.Headers)
+			}
+		})
+	}
+
+	t.Run("null 字段写入默认值", func(t *testing.T) {
+		request := &InternalRequest{Service: "user", Method: "getUser", Payload: []byte(`{"options":null}`),
+			Metadata: map[string]string{MetadataPath: "/api/users/2/profile/a"}}
+		transformer.Apply(request)
+		if string(request.Payload) != `{"id":2,"locale":"zh-CN","options":{"verbose":false},"section":"a"}` {
+			t.Errorf("unexpected payload: %s", request.Payload)
+		}
+	})
+}
+
+func TestTransformerDispatcher(t *testing.T) {
+	transformer, _ := NewTransformer([]TransformRule{
+		{Service: "order", Method: "create", Rename: map[string]string{"qty": "quantity"}},
+	})
+
+	var got string
+	dispatch := transformer.Dispatcher(func(ctx context.Context, request *InternalRequest) (interface{}, error) {
+		got = string(request.Payload)
+		return nil, nil
+	})
+	dispatch(context.Background(), &InternalRequest{Service: "order", Method: "create", Payload: []byte(`{"qty":2}`)})
+	if got != `{"quantity":2}` {
+		t.Errorf("payload = %s", got)
+	}
+
+	_, err := dispatch(context.Background(), &InternalRequest{Service: "order", Method: "create", Payload: []byte(`{"qty":`)})
+	if fe, ok := err.(*FrameworkError); !ok || fe.Code != ErrorBadRequest {
+		t.Errorf("expected BadRequest for invalid payload, got %v", err)
+	}
+}
+
+func TestNewTransformerValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule TransformRule
+	}{
+		{"缺少服务", TransformRule{Method: "get"}},
+		{"空的重命名字段", TransformRule{Service: "user", Rename: map[string]string{"a": ""}}},
+		{"不支持的路径参数类型", TransformRule{Service: "user", Path: "/users/{id:uuid}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTransformer([]TransformRule{tt.rule}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
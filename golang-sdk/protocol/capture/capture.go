@@ -0,0 +1,216 @@
+// Package capture 按采样比例录制业务方法收到的内部请求，供问题排查和回放
+//
+// 录制的请求经过脱敏：认证相关的请求头以及名称包含 password、token、secret 等词的负载字段和元数据
+// 替换为 RedactedValue。录制结果写入 Sink，内置进程内的 RingBuffer 和按 JSON Lines 追加写入的 FileSink，
+// 可用 frameworkctl replay 将录制的请求重新发往目标环境。PayloadLogger 以同样的脱敏规则按方法限流记录请求和响应负载，
+// 用于排查跨语言的序列化不一致
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// RedactedValue 脱敏字段的替代文本
+const RedactedValue = "******"
+
+const (
+	// DefaultSampleRate 默认采样比例
+	DefaultSampleRate = 0.1
+	// DefaultMaxPayloadSize 默认录制的最大负载字节数，超过时只录制请求的其他部分
+	DefaultMaxPayloadSize = 64 * 1024
+)
+
+// 请求头名称包含以下词时脱敏（不区分大小写）
+var secretHeaderWords = []string{"authorization", "cookie", "api-key", "apikey", "token", "secret", "password"}
+
+// 负载字段和元数据键包含以下词时脱敏（不区分大小写）
+var secretFieldWords = []string{"password", "passwd", "secret", "token", "credential", "apikey", "api_key", "privatekey", "accesskey", "authorization"}
+
+// Record 录制的请求
+type Record struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Method  string    `json:"method"`
+	// Payload 脱敏后的 JSON 负载，负载不是 JSON 或超过最大字节数时为空且 PayloadOmitted 为 true
+	Payload        json.RawMessage   `json:"payload,omitempty"`
+	PayloadOmitted bool              `json:"payloadOmitted,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	TraceId        string            `json:"traceId,omitempty"`
+	Duration       time.Duration     `json:"duration"`
+	// Status 处理结果，成功为 ok，失败为错误码（见 adapter.ErrorCodeLabel）
+	Status string `json:"status"`
+}
+
+// Options 录制选项
+type Options struct {
+	// Sink 录制结果的存储，必填
+	Sink Sink
+	// SampleRate 采样比例，取值 0 到 1，为 0 时使用 DefaultSampleRate
+	SampleRate float64
+	// Services 只录制这些服务的请求，为空时录制所有服务
+	Services []string
+	// RedactFields 额外脱敏的负载字段名，不区分大小写
+	RedactFields []string
+	// MaxPayloadSize 录制的最大负载字节数，为 0 时使用 DefaultMaxPayloadSize
+	MaxPayloadSize int
+	// OnError 写入 Sink 失败时调用，录制失败不影响请求处理
+	OnError func(err error)
+}
+
+// Recorder 请求录制器
+type Recorder struct {
+	sink           Sink
+	sampleRate     float64
+	services       map[string]bool
+	redactFields   map[string]bool
+	maxPayloadSize int
+	onError        func(err error)
+}
+
+// NewRecorder 创建请求录制器
+func NewRecorder(options *Options) *Recorder {
+	r := &Recorder{
+		sink:           options.Sink,
+		sampleRate:     options.SampleRate,
+		redactFields:   make(map[string]bool, len(options.RedactFields)),
+		maxPayloadSize: options.MaxPayloadSize,
+		onError:        options.OnError,
+	}
+	if r.sampleRate <= 0 {
+		r.sampleRate = DefaultSampleRate
+	}
+	if r.maxPayloadSize <= 0 {
+		r.maxPayloadSize = DefaultMaxPayloadSize
+	}
+	if len(options.Services) > 0 {
+		r.services = make(map[string]bool, len(options.Services))
+		for _, service := range options.Services {
+			r.services[service] = true
+		}
+	}
+	for _, field := range options.RedactFields {
+		r.redactFields[strings.ToLower(field)] = true
+	}
+	return r
+}
+
+// Dispatcher 返回调用 next 并录制采样请求的分发器
+//
+// 请求在调用 next 之前复制，录制的是 next 收到的请求，不受 next 对请求的修改影响
+func (r *Recorder) Dispatcher(next adapter.Dispatcher) adapter.Dispatcher {
+	return func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		if !r.sampled(request) {
+			return next(ctx, request)
+		}
+
+		record := r.newRecord(request)
+		start := time.Now()
+		result, err := next(ctx, request)
+		record.Duration = time.Since(start)
+		record.Status = adapter.ErrorCodeLabel(err)
+		if writeErr := r.sink.Write(record); writeErr != nil && r.onError != nil {
+			r.onError(writeErr)
+		}
+		return result, err
+	}
+}
+
+// sampled 判断是否录制请求
+func (r *Recorder) sampled(request *adapter.InternalRequest) bool {
+	if r.services != nil && !r.services[request.Service] {
+		return false
+	}
+	return r.sampleRate >= 1 || rand.Float64() < r.sampleRate
+}
+
+// newRecord 复制并脱敏请求
+func (r *Recorder) newRecord(request *adapter.InternalRequest) *Record {
+	record := &Record{
+		Time:     time.Now(),
+		Service:  request.Service,
+		Method:   request.Method,
+		Headers:  redactMap(request.Headers, secretHeaderWords),
+		Metadata: redactMap(request.Metadata, secretFieldWords),
+		TraceId:  request.TraceId,
+	}
+	if payload, ok := r.redactPayload(request.Payload); ok {
+		record.Payload = payload
+	} else {
+		record.PayloadOmitted = true
+	}
+	return record
+}
+
+// redactPayload 脱敏 JSON 负载，负载为空时返回 nil；负载过大或不是 JSON 时返回 false
+func (r *Recorder) redactPayload(payload []byte) (json.RawMessage, bool) {
+	if len(payload) == 0 {
+		return nil, true
+	}
+	if len(payload) > r.maxPayloadSize {
+		return nil, false
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	redacted, err := json.Marshal(redactValue(value, r.redactFields))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// redactValue 递归替换敏感字段和 fields（小写）中字段的值
+func redactValue(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if fields[strings.ToLower(key)] || containsWord(key, secretFieldWords) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactValue(item, fields)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, fields)
+		}
+	}
+	return value
+}
+
+// redactMap 复制 values，键包含 words 中的词时替换值
+func redactMap(values map[string]string, words []string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		if containsWord(key, words) {
+			value = RedactedValue
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// containsWord 判断 name 是否包含 words 中的词（不区分大小写）
+func containsWord(name string, words []string) bool {
+	name = strings.ToLower(name)
+	for _, word := range words {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+func TestRecorder(t *testing.T) {
+	buffer := NewRingBuffer(10)
+	recorder := NewRecorder(&Options{Sink: buffer, SampleRate: 1, RedactFields: []string{"idCard"}})
+
+	dispatch := recorder.Dispatcher(func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		if request.Method == "fail" {
+			return nil, &adapter.FrameworkError{Code: adapter.ErrorNotFound, Message: "not found"}
+		}
+		return "ok", nil
+	})
+
+	request := &adapter.InternalRequest{
+		Service: "user",
+		Method:  "login",
+		Payload: []byte(`{"name":"alice","password":"p@ss","profile":{"IDCard":"110"},"items":[{"accessToken":"t"}],"age":30}`),
+		Headers: map[string]string{"Authorization": "Bearer x", "X-API-Key": "k", "Content-Type": "application/json"},
+		Metadata: map[string]string{
+			"request_id":        "r-1",
+			"security.token":    "t",
+			"original_protocol": "REST",
+		},
+		TraceId: "trace-1",
+	}
+	if result, err := dispatch(context.Background(), request); err != nil || result != "ok" {
+		t.Fatalf("unexpected result: %v %v", result, err)
+	}
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "fail"})
+
+	records := buffer.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Service != "user" || record.Method != "login" || record.TraceId != "trace-1" || record.Status != "ok" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	want := `{"age":30,"items":[{"accessToken":"******"}],"name":"alice","password":"******","profile":{"IDCard":"******"}}`
+	if string(record.Payload) != want {
+		t.Errorf("payload = %s, want %s", record.Payload, want)
+	}
+	if record.Headers["Authorization"] != RedactedValue || record.Headers["X-API-Key"] != RedactedValue ||
+		record.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected headers: %v", record.Headers)
+	}
+	if record.Metadata["security.token"] != RedactedValue || record.Metadata["request_id"] != "r-1" {
+		t.Errorf("unexpected metadata: %v", record.Metadata)
+	}
+	// 原请求不被修改
+	if !strings.Contains(string(request.Payload), "p@ss") || request.Headers["Authorization"] != "Bearer x" {
+		t.Errorf("request should not be modified: %s %v", request.Payload, request.Headers)
+	}
+
+	if want := adapter.ErrorCodeLabel(&adapter.FrameworkError{Code: adapter.ErrorNotFound}); records[1].Status != want {
+		t.Errorf("unexpected status: %s", records[1].Status)
+	}
+}
+
+func TestRecorderSampling(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		service string
+		payload string
+		want    int
+		omitted bool
+	}{
+		{name: "过滤服务", options: Options{SampleRate: 1, Services: []string{"order"}}, service: "user", want: 0},
+		{name: "匹配服务", options: Options{SampleRate: 1, Services: []string{"order"}}, service: "order", payload: `[1]`, want: 1},
+		{name: "负载过大", options: Options{SampleRate: 1, MaxPayloadSize: 4}, service: "user", payload: `{"a":1}`, want: 1, omitted: true},
+		{name: "负载不是 JSON", options: Options{SampleRate: 1}, service: "user", payload: `not json`, want: 1, omitted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewRingBuffer(10)
+			tt.options.Sink = buffer
+			dispatch := NewRecorder(&tt.options).Dispatcher(func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+				return nil, nil
+			})
+			dispatch(context.Background(), &adapter.InternalRequest{Service: tt.service, Method: "m", Payload: []byte(tt.payload)})
+
+			records := buffer.Records()
+			if len(records) != tt.want {
+				t.Fatalf("expected %d records, got %d", tt.want, len(records))
+			}
+			if tt.want > 0 && records[0].PayloadOmitted != tt.omitted {
+				t.Errorf("payloadOmitted = %v, want %v", records[0].PayloadOmitted, tt.omitted)
+			}
+		})
+	}
+}
+
+func TestRecorderSinkError(t *testing.T) {
+	var got error
+	recorder := NewRecorder(&Options{
+		Sink:       failingSink{},
+		SampleRate: 1,
+		OnError:    func(err error) { got = err },
+	})
+	dispatch := recorder.Dispatcher(func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		return "ok", nil
+	})
+	if result, err := dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "m"}); err != nil || result != "ok" {
+		t.Errorf("sink error should not fail the request: %v %v", result, err)
+	}
+	if got == nil {
+		t.Error("expected OnError to be called")
+	}
+}
+
+// failingSink 写入总是失败的存储
+type failingSink struct{}
+
+func (failingSink) Write(record *Record) error {
+	return errors.New("disk full")
+}
+
+func TestRingBuffer(t *testing.T) {
+	buffer := NewRingBuffer(3)
+	for _, method := range []string{"a", "b", "c", "d", "e"} {
+		buffer.Write(&Record{Method: method})
+	}
+
+	var methods []string
+	for _, record := range buffer.Records() {
+		methods = append(methods, record.Method)
+	}
+	if strings.Join(methods, ",") != "c,d,e" {
+		t.Errorf("expected oldest records to be overwritten, got %v", methods)
+	}
+
+	buffer.Reset()
+	if len(buffer.Records()) != 0 {
+		t.Error("expected empty buffer after reset")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sink.Write(&Record{Service: "user", Method: "get", Payload: []byte(`{"id":1}`), Status: "ok"})
+	sink.Write(&Record{Service: "user", Method: "list", Status: "404"})
+	sink.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+	records, err := ReadRecords(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 || records[0].Method != "get" || string(records[0].Payload) != `{"id":1}` || records[1].Status != "404" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "JSON 数组", input: ` [{"service":"a","method":"x"},{"service":"b","method":"y"}]`, want: 2},
+		{name: "JSON Lines", input: "{\"service\":\"a\"}\n\n{\"service\":\"b\"}\n", want: 2},
+		{name: "空输入", input: "\n", want: 0},
+		{name: "格式错误", input: `{"service":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ReadRecords(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(records) != tt.want {
+				t.Errorf("expected %d records, got %d", tt.want, len(records))
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+const (
+	// DefaultPayloadLogsPerMinute 每个服务方法每分钟默认最多记录的请求数
+	DefaultPayloadLogsPerMinute = 5
+	// DefaultPayloadLogSize 每个负载默认记录的最大字节数，超过时截断
+	DefaultPayloadLogSize = 2048
+	// binaryPreviewSize 非 JSON 负载以十六进制记录的前缀字节数
+	binaryPreviewSize = 32
+)
+
+// PayloadLog 采样记录的请求和响应负载，用于排查跨语言序列化不一致
+type PayloadLog struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Method  string    `json:"method"`
+	TraceId string    `json:"traceId,omitempty"`
+	// Encoding 请求负载的编码方式（元数据 encoding），为空时为标准 JSON
+	Encoding string `json:"encoding,omitempty"`
+	// Request 脱敏后的请求负载；不是 JSON 时为字节数和开头字节的十六进制，无法脱敏所以不记录全部内容
+	Request string `json:"request,omitempty"`
+	// Response 脱敏后的处理结果（JSON），处理失败时为空
+	Response string `json:"response,omitempty"`
+	// Truncated 请求或响应超过最大字节数被截断
+	Truncated bool          `json:"truncated,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	// Status 处理结果，成功为 ok，失败为错误码（见 adapter.ErrorCodeLabel）
+	Status string `json:"status"`
+}
+
+// PayloadLogHandler 负载日志处理函数
+type PayloadLogHandler func(ctx context.Context, log *PayloadLog)
+
+// PayloadLogOptions 负载日志选项
+type PayloadLogOptions struct {
+	// Handler 负载日志处理函数，必填
+	Handler PayloadLogHandler
+	// Enabled 初始是否启用，运行时以 SetEnabled 切换
+	Enabled bool
+	// PerMinute 每个服务方法每分钟最多记录的请求数，为 0 时使用 DefaultPayloadLogsPerMinute
+	PerMinute int
+	// MaxSize 每个负载记录的最大字节数，为 0 时使用 DefaultPayloadLogSize
+	MaxSize int
+	// Services 只记录这些服务的请求，为空时记录所有服务
+	Services []string
+	// RedactFields 额外脱敏的负载字段名，不区分大小写
+	RedactFields []string
+}
+
+// PayloadLogStatus 负载日志的运行时状态
+type PayloadLogStatus struct {
+	Enabled   bool `json:"enabled"`
+	PerMinute int  `json:"perMinute"`
+}
+
+// PayloadLogger 按服务方法限流的负载日志
+//
+// 与 Recorder 按比例录制供回放不同，PayloadLogger 每分钟只记录每个方法的少量请求和响应，
+// 用于在线排查某个方法的序列化问题而不淹没日志；默认关闭时只多一次原子读
+type PayloadLogger struct {
+	handler      PayloadLogHandler
+	maxSize      int
+	services     map[string]bool
+	redactFields map[string]bool
+
+	enabled   atomic.Bool
+	perMinute atomic.Int64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+// NewPayloadLogger 创建负载日志
+func NewPayloadLogger(options *PayloadLogOptions) *PayloadLogger {
+	l := &PayloadLogger{
+		handler:      options.Handler,
+		maxSize:      options.MaxSize,
+		redactFields: make(map[string]bool, len(options.RedactFields)),
+		counts:       make(map[string]int),
+	}
+	if l.maxSize <= 0 {
+		l.maxSize = DefaultPayloadLogSize
+	}
+	if len(options.Services) > 0 {
+		l.services = make(map[string]bool, len(options.Services))
+		for _, service := range options.Services {
+			l.services[service] = true
+		}
+	}
+	for _, field := range options.RedactFields {
+		l.redactFields[strings.ToLower(field)] = true
+	}
+	l.enabled.Store(options.Enabled)
+	l.SetPerMinute(options.PerMinute)
+	return l
+}
+
+// SetEnabled 启用或关闭负载日志，可在运行时调用
+func (l *PayloadLogger) SetEnabled(enabled bool) {
+	l.enabled.Store(enabled)
+}
+
+// SetPerMinute 设置每个服务方法每分钟最多记录的请求数，小于等于 0 时使用 DefaultPayloadLogsPerMinute
+func (l *PayloadLogger) SetPerMinute(perMinute int) {
+	if perMinute <= 0 {
+		perMinute = DefaultPayloadLogsPerMinute
+	}
+	l.perMinute.Store(int64(perMinute))
+}
+
+// Status 返回当前是否启用和每分钟的记录数
+func (l *PayloadLogger) Status() PayloadLogStatus {
+	return PayloadLogStatus{Enabled: l.enabled.Load(), PerMinute: int(l.perMinute.Load())}
+}
+
+// Dispatcher 返回调用 next 并记录采样请求负载的分发器
+func (l *PayloadLogger) Dispatcher(next adapter.Dispatcher) adapter.Dispatcher {
+	return func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		if !l.enabled.Load() || !l.sampled(request) {
+			return next(ctx, request)
+		}
+
+		entry := &PayloadLog{
+			Time:     time.Now(),
+			Service:  request.Service,
+			Method:   request.Method,
+			TraceId:  request.TraceId,
+			Encoding: request.Metadata[adapter.MetadataEncoding],
+		}
+		// 在调用 next 之前格式化，记录的是 next 收到的负载
+		var truncated bool
+		entry.Request, truncated = l.format(request.Payload)
+
+		start := time.Now()
+		result, err := next(ctx, request)
+		entry.Duration = time.Since(start)
+		entry.Status = adapter.ErrorCodeLabel(err)
+		if err == nil {
+			var responseTruncated bool
+			entry.Response, responseTruncated = l.formatResult(result)
+			truncated = truncated || responseTruncated
+		}
+		entry.Truncated = truncated
+		l.handler(ctx, entry)
+		return result, err
+	}
+}
+
+// sampled 判断服务是否需要记录，并限制每个方法每分钟的记录数
+func (l *PayloadLogger) sampled(request *adapter.InternalRequest) bool {
+	if l.services != nil && !l.services[request.Service] {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.windowStart) >= time.Minute {
+		l.windowStart = now
+		l.counts = make(map[string]int)
+	}
+	key := request.Service + "." + request.Method
+	if l.counts[key] >= int(l.perMinute.Load()) {
+		return false
+	}
+	l.counts[key]++
+	return true
+}
+
+// formatResult 将处理结果编码为 JSON 后格式化
+func (l *PayloadLogger) formatResult(result interface{}) (string, bool) {
+	if result == nil {
+		return "", false
+	}
+	if data, ok := result.([]byte); ok {
+		return l.format(data)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Sprintf("<%T>", result), false
+	}
+	return l.format(data)
+}
+
+// format 脱敏并截断负载，不是 JSON 的负载只记录字节数和开头字节
+func (l *PayloadLogger) format(payload []byte) (string, bool) {
+	if len(payload) == 0 {
+		return "", false
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		preview := payload
+		if len(preview) > binaryPreviewSize {
+			preview = preview[:binaryPreviewSize]
+		}
+		return fmt.Sprintf("<%d bytes, not JSON: %s>", len(payload), hex.EncodeToString(preview)), len(payload) > binaryPreviewSize
+	}
+	redacted, err := json.Marshal(redactValue(value, l.redactFields))
+	if err != nil {
+		return "", false
+	}
+	return truncate(string(redacted), l.maxSize)
+}
+
+// truncate 将 s 截断到 max 字节以内，不截断多字节字符
+func truncate(s string, max int) (string, bool) {
+	if len(s) <= max {
+		return s, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...", true
+}
@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// payloadLogCollector 收集负载日志
+type payloadLogCollector struct {
+	mu   sync.Mutex
+	logs []*PayloadLog
+}
+
+func (c *payloadLogCollector) handle(ctx context.Context, log *PayloadLog) {
+	c.mu.Lock()
+	c.logs = append(c.logs, log)
+	c.mu.Unlock()
+}
+
+func TestPayloadLogger(t *testing.T) {
+	collector := &payloadLogCollector{}
+	logger := NewPayloadLogger(&PayloadLogOptions{Handler: collector.handle, PerMinute: 2, MaxSize: 64, RedactFields: []string{"idCard"}})
+	dispatch := logger.Dispatcher(func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		switch request.Method {
+		case "fail":
+			return nil, &adapter.FrameworkError{Code: adapter.ErrorBadRequest, Message: "bad"}
+		case "large":
+			return map[string]string{"text": strings.Repeat("文", 100)}, nil
+		}
+		return map[string]interface{}{"token": "t-1", "ok": true}, nil
+	})
+
+	login := &adapter.InternalRequest{
+		Service:  "user",
+		Method:   "login",
+		Payload:  []byte(`{"name":"alice","password":"p@ss","idCard":"110"}`),
+		Metadata: map[string]string{adapter.MetadataEncoding: "portable"},
+		TraceId:  "trace-1",
+	}
+
+	// 默认关闭
+	dispatch(context.Background(), login)
+	if len(collector.logs) != 0 {
+		t.Fatalf("expected no logs while disabled, got %d", len(collector.logs))
+	}
+
+	// 运行时启用，每个方法每分钟最多 2 条
+	logger.SetEnabled(true)
+	for i := 0; i < 3; i++ {
+		dispatch(context.Background(), login)
+	}
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "fail", Payload: []byte{0x82, 0xa4, 0x6e}})
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "large"})
+	if len(collector.logs) != 4 {
+		t.Fatalf("expected 4 logs, got %d", len(collector.logs))
+	}
+
+	log := collector.logs[0]
+	if log.Service != "user" || log.Method != "login" || log.TraceId != "trace-1" || log.Encoding != "portable" || log.Status != "ok" {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.Request != `{"idCard":"******","name":"alice","password":"******"}` {
+		t.Errorf("unexpected request: %s", log.Request)
+	}
+	if log.Response != `{"ok":true,"token":"******"}` {
+		t.Errorf("unexpected response: %s", log.Response)
+	}
+
+	// 非 JSON 负载只记录字节数和开头字节，失败时不记录响应
+	failed := collector.logs[2]
+	if failed.Request != "<3 bytes, not JSON: 82a46e>" || failed.Response != "" || failed.Status == "ok" {
+		t.Errorf("unexpected failed log: %+v", failed)
+	}
+
+	// 超过最大字节数时在字符边界截断
+	large := collector.logs[3]
+	if !large.Truncated || len(large.Response) > 64+len("...") || !strings.HasSuffix(large.Response, "...") {
+		t.Errorf("expected truncated response, got %q", large.Response)
+	}
+
+	if status := logger.Status(); !status.Enabled || status.PerMinute != 2 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	logger.SetEnabled(false)
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "other"})
+	if len(collector.logs) != 4 {
+		t.Errorf("expected no logs after disabling, got %d", len(collector.logs))
+	}
+}
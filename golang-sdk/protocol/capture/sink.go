@@ -0,0 +1,167 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DefaultBufferSize RingBuffer 的默认容量
+const DefaultBufferSize = 1000
+
+// Sink 录制结果的存储，须支持并发调用
+type Sink interface {
+	Write(record *Record) error
+}
+
+// RingBuffer 保存最近录制的请求，容量已满时覆盖最早的记录
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []*Record
+	next    int
+	full    bool
+}
+
+// NewRingBuffer 创建容量为 size 的环形缓冲区，size 不大于 0 时使用 DefaultBufferSize
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &RingBuffer{records: make([]*Record, size)}
+}
+
+// Write 保存记录
+func (b *RingBuffer) Write(record *Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records[b.next] = record
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+	return nil
+}
+
+// Records 按录制时间顺序返回缓冲区中的记录
+func (b *RingBuffer) Records() []*Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]*Record(nil), b.records[:b.next]...)
+	}
+	records := make([]*Record, 0, len(b.records))
+	records = append(records, b.records[b.next:]...)
+	return append(records, b.records[:b.next]...)
+}
+
+// Reset 清空缓冲区
+func (b *RingBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.records {
+		b.records[i] = nil
+	}
+	b.next, b.full = 0, false
+}
+
+// FileSink 以 JSON Lines 格式将记录追加写入文件
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink 打开或创建录制文件，文件权限为 0600
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write 追加一行记录
+func (s *FileSink) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode capture record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write capture record: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// MultiSink 将记录依次写入多个存储，返回第一个错误
+type MultiSink []Sink
+
+// Write 写入所有存储
+func (m MultiSink) Write(record *Record) error {
+	var first error
+	for _, sink := range m {
+		if err := sink.Write(record); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// ReadRecords 读取录制的请求，支持 FileSink 写入的 JSON Lines 和管理接口返回的 JSON 数组
+func ReadRecords(r io.Reader) ([]*Record, error) {
+	reader := bufio.NewReader(r)
+	first, err := peekNonSpace(reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(reader)
+	if first == '[' {
+		var records []*Record
+		if err := decoder.Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid capture records: %w", err)
+		}
+		return records, nil
+	}
+
+	var records []*Record
+	for {
+		var record Record
+		if err := decoder.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid capture record %d: %w", len(records)+1, err)
+		}
+		records = append(records, &record)
+	}
+}
+
+// peekNonSpace 返回第一个非空白字节，不消耗该字节
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return b, reader.UnreadByte()
+		}
+	}
+}
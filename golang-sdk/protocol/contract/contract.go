@@ -0,0 +1,204 @@
+// Package contract 录制 Go 服务业务方法的请求/响应对，生成跨语言共享的契约文件，并以契约校验其他语言的实现
+//
+// 契约文件是 JSON 格式的 Contract，按服务和方法记录交互：请求参数、期望的结果或错误状态，以及比较规则。
+// Recorder 在 Go 服务的测试或预发环境中录制交互，Verify 以 JSON-RPC 等方式调用 Java、PHP 实现并逐个比较结果，
+// frameworkctl verify 在命令行中执行校验，用于发现各语言之间的序列化差异
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// FormatVersion 当前的契约文件格式版本
+const FormatVersion = 1
+
+// 结果的比较方式
+const (
+	// MatchExact 结果的 JSON 值必须相等，对象字段的顺序和数字的书写形式不影响比较
+	MatchExact = "exact"
+	// MatchType 结果的结构和 JSON 类型必须相同，字符串、数字和布尔值的具体值不比较
+	MatchType = "type"
+)
+
+// StatusOK 交互成功时的状态，失败时状态为错误码（见 adapter.ErrorCodeLabel）
+const StatusOK = "ok"
+
+// Contract 契约文件
+type Contract struct {
+	Version int `json:"version"`
+	// Provider 录制契约的服务，通常为 Go 实现的服务名
+	Provider     string         `json:"provider,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求/响应交互
+type Interaction struct {
+	// Description 交互说明，为空时以服务和方法名标识
+	Description string `json:"description,omitempty"`
+	Service     string `json:"service"`
+	Method      string `json:"method"`
+	// Request 请求参数（JSON），为空时不带参数调用
+	Request json.RawMessage `json:"request,omitempty"`
+	// Status 期望的处理结果，成功为 StatusOK，失败为错误码
+	Status string `json:"status"`
+	// Response 期望的结果（JSON），只在 Status 为 StatusOK 时比较
+	Response json.RawMessage `json:"response,omitempty"`
+	// Match 结果的比较方式，为空时使用 MatchExact
+	Match string `json:"match,omitempty"`
+	// Ignore 比较时忽略的字段路径，以点分隔，数组元素用 []，如 "createdAt"、"items[].id"
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// Name 返回交互的标识
+func (i *Interaction) Name() string {
+	if i.Description != "" {
+		return i.Description
+	}
+	return i.Service + "." + i.Method
+}
+
+// Validate 检查契约的格式版本和各交互的必填字段
+func (c *Contract) Validate() error {
+	if c.Version != FormatVersion {
+		return fmt.Errorf("unsupported contract version %d, expected %d", c.Version, FormatVersion)
+	}
+	for n, interaction := range c.Interactions {
+		if interaction.Service == "" || interaction.Method == "" {
+			return fmt.Errorf("interaction %d: service and method are required", n)
+		}
+		if interaction.Status == "" {
+			return fmt.Errorf("interaction %d (%s): status is required", n, interaction.Name())
+		}
+		switch interaction.Match {
+		case "", MatchExact, MatchType:
+		default:
+			return fmt.Errorf("interaction %d (%s): unknown match %q", n, interaction.Name(), interaction.Match)
+		}
+		if len(interaction.Request) > 0 && !json.Valid(interaction.Request) {
+			return fmt.Errorf("interaction %d (%s): request is not valid JSON", n, interaction.Name())
+		}
+		if len(interaction.Response) > 0 && !json.Valid(interaction.Response) {
+			return fmt.Errorf("interaction %d (%s): response is not valid JSON", n, interaction.Name())
+		}
+	}
+	return nil
+}
+
+// Filter 返回只包含 services 中服务的交互的契约，services 为空时返回 c
+func (c *Contract) Filter(services ...string) *Contract {
+	if len(services) == 0 {
+		return c
+	}
+	allowed := make(map[string]bool, len(services))
+	for _, service := range services {
+		allowed[service] = true
+	}
+	filtered := &Contract{Version: c.Version, Provider: c.Provider}
+	for _, interaction := range c.Interactions {
+		if allowed[interaction.Service] {
+			filtered.Interactions = append(filtered.Interactions, interaction)
+		}
+	}
+	return filtered
+}
+
+// Merge 追加 other 中的交互，服务、方法、请求和状态都相同的交互只保留已有的一个；结果按服务和方法排序
+func (c *Contract) Merge(other *Contract) {
+	seen := make(map[string]bool, len(c.Interactions))
+	for _, interaction := range c.Interactions {
+		seen[interactionKey(interaction)] = true
+	}
+	for _, interaction := range other.Interactions {
+		if key := interactionKey(interaction); !seen[key] {
+			seen[key] = true
+			c.Interactions = append(c.Interactions, interaction)
+		}
+	}
+	sortInteractions(c.Interactions)
+}
+
+// Read 读取并校验契约
+func Read(r io.Reader) (*Contract, error) {
+	var c Contract
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode contract: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ReadFile 读取并校验契约文件
+func ReadFile(path string) (*Contract, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Write 以缩进格式写出契约
+func (c *Contract) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteFile 将契约写入文件，文件已存在时覆盖
+func (c *Contract) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// interactionKey 返回用于去重的交互键，请求参数按规范化后的 JSON 比较
+func interactionKey(interaction *Interaction) string {
+	return interaction.Service + "\x00" + interaction.Method + "\x00" + canonical(interaction.Request) + "\x00" + interaction.Status
+}
+
+// canonical 返回 JSON 的规范形式（对象字段排序、去除空白），不是合法 JSON 时原样返回
+func canonical(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	value, err := decode(data)
+	if err != nil {
+		return string(data)
+	}
+	out, _ := json.Marshal(value)
+	return string(out)
+}
+
+// sortInteractions 按服务和方法稳定排序
+func sortInteractions(interactions []*Interaction) {
+	sort.SliceStable(interactions, func(i, j int) bool {
+		if interactions[i].Service != interactions[j].Service {
+			return interactions[i].Service < interactions[j].Service
+		}
+		return interactions[i].Method < interactions[j].Method
+	})
+}
+
+// decode 解码 JSON，数字保留为 json.Number
+func decode(data json.RawMessage) (interface{}, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,156 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(&RecorderOptions{Provider: "user-service-go", MaxPerMethod: 2, Ignore: []string{"createdAt"}})
+	dispatch := recorder.Dispatcher(func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		if request.Method == "delete" {
+			return nil, &adapter.FrameworkError{Code: adapter.ErrorNotFound, Message: "not found"}
+		}
+		return map[string]interface{}{"id": 1, "name": "alice"}, nil
+	})
+
+	for _, payload := range []string{`{"id":1}`, `{ "id": 1 }`, `{"id":2}`, `{"id":3}`} {
+		dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "get", Payload: []byte(payload)})
+	}
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "delete", Payload: []byte(`{"id":9}`)})
+	dispatch(context.Background(), &adapter.InternalRequest{Service: "user", Method: "get", Payload: []byte("not json")})
+
+	c := recorder.Contract()
+	if c.Version != FormatVersion || c.Provider != "user-service-go" {
+		t.Errorf("unexpected contract header: %+v", c)
+	}
+	if len(c.Interactions) != 3 {
+		t.Fatalf("expected 3 interactions (duplicate and over-limit dropped), got %d", len(c.Interactions))
+	}
+	deleted := c.Interactions[0]
+	if deleted.Method != "delete" || deleted.Status != "404" || deleted.Response != nil {
+		t.Errorf("unexpected error interaction: %+v", deleted)
+	}
+	get := c.Interactions[1]
+	if get.Status != StatusOK || string(get.Request) != `{"id":1}` || string(get.Response) != `{"id":1,"name":"alice"}` {
+		t.Errorf("unexpected interaction: %+v", get)
+	}
+	if len(get.Ignore) != 1 || get.Ignore[0] != "createdAt" {
+		t.Errorf("expected recorder ignore paths, got %v", get.Ignore)
+	}
+}
+
+func TestContractReadWrite(t *testing.T) {
+	recorder := NewRecorder(nil)
+	recorder.Record("order", "create", map[string]int{"qty": 2}, map[string]string{"status": "CREATED"}, nil)
+	recorder.Record("order", "list", nil, []int{1, 2}, nil)
+
+	path := filepath.Join(t.TempDir(), "order.contract.json")
+	if err := recorder.Contract().WriteFile(path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	c, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if len(c.Interactions) != 2 || c.Interactions[1].Request != nil {
+		t.Fatalf("unexpected contract: %+v", c.Interactions)
+	}
+
+	c.Merge(&Contract{Version: FormatVersion, Interactions: []*Interaction{
+		{Service: "order", Method: "create", Request: json.RawMessage(`{ "qty": 2 }`), Status: StatusOK},
+		{Service: "cart", Method: "add", Status: StatusOK},
+	}})
+	if len(c.Interactions) != 3 || c.Interactions[0].Service != "cart" {
+		t.Errorf("expected merged contract sorted by service, got %+v", c.Interactions)
+	}
+	if filtered := c.Filter("order"); len(filtered.Interactions) != 2 {
+		t.Errorf("expected 2 order interactions, got %d", len(filtered.Interactions))
+	}
+
+	for _, invalid := range []string{
+		`{"version":2,"interactions":[]}`,
+		`{"version":1,"interactions":[{"service":"order","status":"ok"}]}`,
+		`{"version":1,"interactions":[{"service":"order","method":"get","status":"ok","match":"fuzzy"}]}`,
+	} {
+		if _, err := Read(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected error reading %s", invalid)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil || !strings.Contains(buf.String(), "\n  \"interactions\"") {
+		t.Errorf("expected indented output, got %s (%v)", buf.String(), err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   string
+		actual     string
+		match      string
+		ignore     []string
+		mismatches []string
+	}{
+		{"相等", `{"id":1,"tags":["a"]}`, `{"tags":["a"],"id":1.0}`, "", nil, nil},
+		{"新增字段", `{"id":1}`, `{"id":1,"extra":true}`, "", nil, nil},
+		{"值不同", `{"id":1,"name":"alice"}`, `{"id":2,"name":"alice"}`, "", nil, []string{"id: expected 1, got 2"}},
+		{"缺少字段", `{"id":1,"name":"alice"}`, `{"id":1}`, "", nil, []string{"name: missing field"}},
+		{"类型不同", `{"amount":10}`, `{"amount":"10"}`, MatchType, nil, []string{"amount: expected number, got string"}},
+		{"只比较类型", `{"id":1,"items":[{"sku":"a"}]}`, `{"id":7,"items":[{"sku":"b"},{"sku":"c"}]}`, MatchType, nil, nil},
+		{"数组长度", `[1,2]`, `[1]`, "", nil, []string{"$: expected 2 elements, got 1"}},
+		{"忽略字段", `{"items":[{"id":1,"createdAt":"x"}]}`, `{"items":[{"id":1}]}`, "", []string{"items[].createdAt"}, nil},
+		{"空对象序列化为数组", `{"tags":{}}`, `{"tags":[]}`, "", nil, []string{"tags: expected object, got array"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mismatches := Compare(json.RawMessage(tt.expected), json.RawMessage(tt.actual), tt.match, tt.ignore)
+			if strings.Join(mismatches, "\n") != strings.Join(tt.mismatches, "\n") {
+				t.Errorf("mismatches = %q, want %q", mismatches, tt.mismatches)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	c := &Contract{Version: FormatVersion, Interactions: []*Interaction{
+		{Service: "user", Method: "get", Request: json.RawMessage(`{"id":1}`), Status: StatusOK, Response: json.RawMessage(`{"id":1,"name":"alice"}`)},
+		{Service: "user", Method: "get", Request: json.RawMessage(`{"id":9}`), Status: "404"},
+		{Service: "user", Method: "list", Status: StatusOK, Response: json.RawMessage(`[{"id":1}]`)},
+	}}
+
+	// 模拟其他语言的实现：list 把整数 ID 序列化为字符串
+	call := func(ctx context.Context, service, method string, params json.RawMessage) (json.RawMessage, error) {
+		switch {
+		case method == "list":
+			return json.RawMessage(`[{"id":"1"}]`), nil
+		case string(params) == `{"id":9}`:
+			return nil, &adapter.FrameworkError{Code: adapter.ErrorNotFound, Message: "not found"}
+		default:
+			return json.RawMessage(`{"name":"alice","id":1}`), nil
+		}
+	}
+
+	report := Verify(context.Background(), c, call)
+	if len(report.Results) != 3 || report.Failed() != 1 {
+		t.Fatalf("expected 1 of 3 interactions to fail, got %d", report.Failed())
+	}
+	failed := report.Results[2]
+	if failed.Passed() || len(failed.Mismatches) != 1 || failed.Mismatches[0] != "[].id: expected number, got string" {
+		t.Errorf("unexpected mismatches: %v", failed.Mismatches)
+	}
+
+	status := VerifyInteraction(context.Background(), c.Interactions[1], func(ctx context.Context, service, method string, params json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`null`), nil
+	})
+	if status.Passed() || status.Status != StatusOK || !strings.HasPrefix(status.Mismatches[0], "status: expected 404, got ok") {
+		t.Errorf("expected status mismatch, got %+v", status)
+	}
+}
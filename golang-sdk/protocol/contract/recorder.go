@@ -0,0 +1,143 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// DefaultMaxPerMethod 每个方法默认录制的最大交互数
+const DefaultMaxPerMethod = 10
+
+// RecorderOptions 录制选项
+type RecorderOptions struct {
+	// Provider 写入契约的 Provider
+	Provider string
+	// Services 只录制这些服务的交互，为空时录制所有服务
+	Services []string
+	// MaxPerMethod 每个方法录制的最大交互数，请求参数和状态都相同的交互只录制一次；为 0 时使用 DefaultMaxPerMethod
+	MaxPerMethod int
+	// Match 录制的交互使用的比较方式，为空时使用 MatchExact
+	Match string
+	// Ignore 录制的交互比较时忽略的字段路径，如时间戳和生成的 ID
+	Ignore []string
+}
+
+// Recorder 契约录制器，录制业务方法收到的请求参数和处理结果
+type Recorder struct {
+	options  RecorderOptions
+	services map[string]bool
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	seen         map[string]bool
+	counts       map[string]int // 服务.方法 -> 已录制的交互数
+}
+
+// NewRecorder 创建契约录制器，options 为 nil 时录制所有服务
+func NewRecorder(options *RecorderOptions) *Recorder {
+	r := &Recorder{
+		seen:   make(map[string]bool),
+		counts: make(map[string]int),
+	}
+	if options != nil {
+		r.options = *options
+	}
+	if r.options.MaxPerMethod <= 0 {
+		r.options.MaxPerMethod = DefaultMaxPerMethod
+	}
+	if len(r.options.Services) > 0 {
+		r.services = make(map[string]bool, len(r.options.Services))
+		for _, service := range r.options.Services {
+			r.services[service] = true
+		}
+	}
+	return r
+}
+
+// Dispatcher 返回调用 next 并录制交互的分发器
+//
+// 请求参数不是 JSON、结果为流式结果或无法编码为 JSON 时不录制
+func (r *Recorder) Dispatcher(next adapter.Dispatcher) adapter.Dispatcher {
+	return func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		if r.services != nil && !r.services[request.Service] {
+			return next(ctx, request)
+		}
+		// 复制请求参数，不受 next 对请求的修改影响
+		payload := append(json.RawMessage(nil), request.Payload...)
+		result, err := next(ctx, request)
+		r.record(request.Service, request.Method, payload, result, err)
+		return result, err
+	}
+}
+
+// Record 录制一次交互，供不经过分发器的调用（如测试中直接调用的客户端）使用
+func (r *Recorder) Record(service, method string, params interface{}, result interface{}, err error) {
+	payload, marshalErr := json.Marshal(params)
+	if marshalErr != nil {
+		return
+	}
+	if params == nil {
+		payload = nil
+	}
+	r.record(service, method, payload, result, err)
+}
+
+// Contract 返回已录制交互组成的契约，交互按服务和方法排序
+func (r *Recorder) Contract() *Contract {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := append([]*Interaction(nil), r.interactions...)
+	sortInteractions(interactions)
+	return &Contract{Version: FormatVersion, Provider: r.options.Provider, Interactions: interactions}
+}
+
+// Reset 清空已录制的交互
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = nil
+	r.seen = make(map[string]bool)
+	r.counts = make(map[string]int)
+}
+
+// record 保存交互，超过方法的录制上限或与已录制的交互重复时丢弃
+func (r *Recorder) record(service, method string, payload json.RawMessage, result interface{}, err error) {
+	if len(payload) > 0 && !json.Valid(payload) {
+		return
+	}
+	interaction := &Interaction{
+		Service: service,
+		Method:  method,
+		Request: payload,
+		Status:  adapter.ErrorCodeLabel(err),
+		Match:   r.options.Match,
+		Ignore:  r.options.Ignore,
+	}
+	if err == nil {
+		if _, ok := result.(*adapter.Stream); ok {
+			return
+		}
+		response, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			return
+		}
+		interaction.Response = response
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := interactionKey(interaction)
+	name := service + "." + method
+	if r.seen[key] || r.counts[name] >= r.options.MaxPerMethod {
+		return
+	}
+	r.seen[key] = true
+	r.counts[name]++
+	r.interactions = append(r.interactions, interaction)
+}
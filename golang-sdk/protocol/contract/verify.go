@@ -0,0 +1,206 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// Caller 调用被校验实现的方法，返回原始 JSON 结果；调用失败时返回的错误按 adapter.ErrorCodeLabel 转换为状态
+type Caller func(ctx context.Context, service, method string, params json.RawMessage) (json.RawMessage, error)
+
+// Result 单个交互的校验结果
+type Result struct {
+	Interaction *Interaction
+	// Status 实际的处理结果
+	Status string
+	// Mismatches 与契约不一致之处，为空时交互通过
+	Mismatches []string
+}
+
+// Passed 判断交互是否通过
+func (r *Result) Passed() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Report 契约的校验结果
+type Report struct {
+	Results []*Result
+}
+
+// Failed 返回未通过的交互数
+func (r *Report) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Verify 按顺序调用契约中的每个交互，比较处理结果和响应
+func Verify(ctx context.Context, c *Contract, call Caller) *Report {
+	report := &Report{Results: make([]*Result, 0, len(c.Interactions))}
+	for _, interaction := range c.Interactions {
+		report.Results = append(report.Results, VerifyInteraction(ctx, interaction, call))
+	}
+	return report
+}
+
+// VerifyInteraction 调用单个交互并比较处理结果和响应
+func VerifyInteraction(ctx context.Context, interaction *Interaction, call Caller) *Result {
+	response, err := call(ctx, interaction.Service, interaction.Method, interaction.Request)
+	result := &Result{Interaction: interaction, Status: adapter.ErrorCodeLabel(err)}
+	if result.Status != interaction.Status {
+		mismatch := fmt.Sprintf("status: expected %s, got %s", interaction.Status, result.Status)
+		if err != nil {
+			mismatch += fmt.Sprintf(" (%v)", err)
+		}
+		result.Mismatches = append(result.Mismatches, mismatch)
+		return result
+	}
+	if interaction.Status != StatusOK || len(interaction.Response) == 0 {
+		return result
+	}
+	result.Mismatches = Compare(interaction.Response, response, interaction.Match, interaction.Ignore)
+	return result
+}
+
+// Compare 按比较方式比较期望和实际的 JSON，返回以字段路径标识的不一致之处
+//
+// 期望中没有的对象字段视为实现新增的字段，不算不一致；ignore 中的路径（见 Interaction.Ignore）不比较
+func Compare(expected, actual json.RawMessage, match string, ignore []string) []string {
+	want, err := decode(expected)
+	if err != nil {
+		return []string{fmt.Sprintf("expected response is not valid JSON: %v", err)}
+	}
+	if len(actual) == 0 {
+		actual = json.RawMessage("null")
+	}
+	got, err := decode(actual)
+	if err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+
+	c := &comparer{typeOnly: match == MatchType, ignore: make(map[string]bool, len(ignore))}
+	for _, path := range ignore {
+		c.ignore[path] = true
+	}
+	c.compare("", want, got)
+	return c.mismatches
+}
+
+// comparer 递归比较 JSON 值
+type comparer struct {
+	typeOnly   bool
+	ignore     map[string]bool
+	mismatches []string
+}
+
+// compare 比较 path 处的值，path 中的数组元素记为 []
+func (c *comparer) compare(path string, want, got interface{}) {
+	if c.ignore[path] {
+		return
+	}
+	if jsonType(want) != jsonType(got) {
+		c.mismatch(path, "expected %s, got %s", jsonType(want), jsonType(got))
+		return
+	}
+
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g := got.(map[string]interface{})
+		keys := make([]string, 0, len(w))
+		for key := range w {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinPath(path, key)
+			value, ok := g[key]
+			if !ok {
+				if !c.ignore[child] {
+					c.mismatch(child, "missing field")
+				}
+				continue
+			}
+			c.compare(child, w[key], value)
+		}
+	case []interface{}:
+		g := got.([]interface{})
+		if !c.typeOnly && len(w) != len(g) {
+			c.mismatch(path, "expected %d elements, got %d", len(w), len(g))
+			return
+		}
+		n := len(w)
+		if len(g) < n {
+			n = len(g)
+		}
+		for i := 0; i < n; i++ {
+			c.compare(path+"[]", w[i], g[i])
+		}
+	case json.Number:
+		if !c.typeOnly && !numbersEqual(w, got.(json.Number)) {
+			c.mismatch(path, "expected %s, got %s", w, got)
+		}
+	default:
+		if !c.typeOnly && want != got {
+			c.mismatch(path, "expected %v, got %v", want, got)
+		}
+	}
+}
+
+// mismatch 记录 path 处的不一致
+func (c *comparer) mismatch(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "$"
+	}
+	c.mismatches = append(c.mismatches, path+": "+fmt.Sprintf(format, args...))
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonType 返回 JSON 值的类型名
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// numbersEqual 判断两个 JSON 数字是否相等，1 与 1.0 相等
+func numbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	if strings.ContainsAny(string(a)+string(b), ".eE") {
+		x, errX := strconv.ParseFloat(string(a), 64)
+		y, errY := strconv.ParseFloat(string(b), 64)
+		return errX == nil && errY == nil && x == y
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package dedup
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCapacity 默认最多保留的幂等键数量
+	DefaultCapacity = 100000
+	// DefaultTTL 默认幂等键保留时间
+	DefaultTTL = 24 * time.Hour
+	// FieldIdempotencyKey JSON 消息中幂等键的字段名，WebSocket、MQTT 等没有消息头的协议使用
+	FieldIdempotencyKey = "idempotencyKey"
+)
+
+// Store 已处理幂等键的存储
+type Store interface {
+	// Seen 判断幂等键是否已处理
+	Seen(key string) bool
+	// Mark 记录幂等键已处理
+	Mark(key string)
+}
+
+// entry 缓存中的幂等键
+type entry struct {
+	key    string
+	expiry time.Time
+}
+
+// Cache 进程内幂等键存储，最多保留 capacity 个幂等键，超出时淘汰最久未使用的；幂等键在 ttl 后过期
+//
+// 只能在单个进程内去重，多实例部署时需实现 Store 接入 Redis 等共享存储
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List // 队首为最近使用的幂等键
+}
+
+// NewCache 创建进程内幂等键存储，capacity 和 ttl 不大于 0 时使用 DefaultCapacity 和 DefaultTTL
+func NewCache(capacity int, ttl time.Duration) *Cache {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Seen 判断幂等键是否已处理且未过期，命中时将其标记为最近使用
+func (c *Cache) Seen(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if !time.Now().Before(elem.Value.(*entry).expiry) {
+		c.remove(elem)
+		return false
+	}
+	c.order.MoveToFront(elem)
+	return true
+}
+
+// Mark 记录幂等键已处理，超出容量时淘汰最久未使用的幂等键
+func (c *Cache) Mark(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiry := time.Now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry).expiry = expiry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, expiry: expiry})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len 返回保留的幂等键数量，包含已过期但尚未淘汰的
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove 删除幂等键
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry).key)
+}
+
+// Do 以幂等键 key 执行 fn：key 已处理时不执行并返回 true；fn 成功后记录 key，失败时不记录以便重新投递后再次执行。
+// key 为空或 store 为 nil 时直接执行 fn
+//
+// Seen 和 Mark 之间没有加锁，同一幂等键的消息并发到达时仍可能都执行
+func Do(store Store, key string, fn func() error) (bool, error) {
+	if store == nil || key == "" {
+		return false, fn()
+	}
+	if store.Seen(key) {
+		return true, nil
+	}
+	if err := fn(); err != nil {
+		return false, err
+	}
+	store.Mark(key)
+	return false, nil
+}
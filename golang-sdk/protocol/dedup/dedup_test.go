@@ -0,0 +1,96 @@
+package dedup
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("记录后命中", func(t *testing.T) {
+		cache := NewCache(10, time.Minute)
+		if cache.Seen("k-1") {
+			t.Fatal("unmarked key should not be seen")
+		}
+		cache.Mark("k-1")
+		if !cache.Seen("k-1") {
+			t.Fatal("marked key should be seen")
+		}
+	})
+
+	t.Run("超出容量淘汰最久未使用的", func(t *testing.T) {
+		cache := NewCache(2, time.Minute)
+		cache.Mark("k-1")
+		cache.Mark("k-2")
+		cache.Seen("k-1") // k-1 变为最近使用
+		cache.Mark("k-3")
+
+		if cache.Len() != 2 {
+			t.Fatalf("expected 2 keys, got %d", cache.Len())
+		}
+		if !cache.Seen("k-1") || !cache.Seen("k-3") {
+			t.Error("recently used keys should be kept")
+		}
+		if cache.Seen("k-2") {
+			t.Error("least recently used key should be evicted")
+		}
+	})
+
+	t.Run("过期后不再命中", func(t *testing.T) {
+		cache := NewCache(10, 20*time.Millisecond)
+		cache.Mark("k-1")
+		time.Sleep(40 * time.Millisecond)
+		if cache.Seen("k-1") {
+			t.Fatal("expired key should not be seen")
+		}
+		if cache.Len() != 0 {
+			t.Errorf("expired key should be removed, got %d keys", cache.Len())
+		}
+	})
+
+	t.Run("默认容量和保留时间", func(t *testing.T) {
+		cache := NewCache(0, 0)
+		if cache.capacity != DefaultCapacity || cache.ttl != DefaultTTL {
+			t.Errorf("expected defaults, got capacity %d ttl %v", cache.capacity, cache.ttl)
+		}
+	})
+}
+
+func TestDo(t *testing.T) {
+	cache := NewCache(10, time.Minute)
+	calls := 0
+	fn := func() error {
+		calls++
+		return nil
+	}
+
+	if duplicate, err := Do(cache, "k-1", fn); duplicate || err != nil {
+		t.Fatalf("first delivery: duplicate=%v err=%v", duplicate, err)
+	}
+	if duplicate, err := Do(cache, "k-1", fn); !duplicate || err != nil {
+		t.Fatalf("second delivery: duplicate=%v err=%v", duplicate, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected fn to run once, got %d", calls)
+	}
+
+	t.Run("失败时不记录", func(t *testing.T) {
+		failure := errors.New("boom")
+		if _, err := Do(cache, "k-2", func() error { return failure }); err != failure {
+			t.Fatalf("expected fn error, got %v", err)
+		}
+		if cache.Seen("k-2") {
+			t.Error("failed key should not be marked")
+		}
+	})
+
+	t.Run("没有幂等键时直接执行", func(t *testing.T) {
+		before := calls
+		Do(cache, "", fn)
+		Do(cache, "", fn)
+		Do(nil, "k-1", fn)
+		if calls != before+3 {
+			t.Errorf("expected 3 calls, got %d", calls-before)
+		}
+	})
+}
@@ -0,0 +1,503 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 请求的 Content-Type
+const (
+	ContentTypeGrpcWeb     = "application/grpc-web"
+	ContentTypeGrpcWebText = "application/grpc-web-text"
+	contentTypeGrpc        = "application/grpc"
+	contentTypeConnectJSON = "application/json"
+	contentTypeConnectPB   = "application/proto"
+)
+
+// DefaultMaxMessageSize Connect 请求体的默认大小上限，与 gRPC 服务端默认的接收上限一致
+const DefaultMaxMessageSize = 4 << 20
+
+// 帧头：1 字节标志和 4 字节大端长度
+const (
+	frameHeaderSize   = 5
+	frameCompressed   = 0x01
+	frameTrailer      = 0x80
+	grpcStatusOK      = 0
+	grpcStatusHeader  = "Grpc-Status"
+	grpcMessageHeader = "Grpc-Message"
+)
+
+// exposedHeaders 浏览器需要读取的响应头
+const exposedHeaders = "Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin"
+
+// allowedHeaders 预检请求未列出请求头时允许的请求头
+const allowedHeaders = "Content-Type, X-Grpc-Web, X-User-Agent, Grpc-Timeout, Authorization, X-API-Key, Connect-Protocol-Version, Connect-Timeout-Ms"
+
+// Options gRPC-Web 处理配置
+type Options struct {
+	// AllowedOrigins 允许跨域调用的来源，为空或包含 "*" 时允许所有来源
+	AllowedOrigins []string
+	// Connect 同时处理 Connect 协议的一元调用（Content-Type 为 application/proto 或 application/json）
+	Connect bool
+	// MaxMessageSize Connect 请求体的大小上限，默认为 DefaultMaxMessageSize
+	MaxMessageSize int64
+}
+
+// Handler 将 gRPC-Web 和 Connect 请求转换为 gRPC 请求交给后端处理
+//
+// 后端通常为 *grpc.Server（实现了 http.Handler），也可以是转发到其他 gRPC 服务的反向代理
+type Handler struct {
+	backend        http.Handler
+	allowAll       bool
+	origins        map[string]bool
+	connect        bool
+	maxMessageSize int64
+}
+
+// NewHandler 创建 gRPC-Web 处理器
+func NewHandler(backend http.Handler, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+	h := &Handler{
+		backend:        backend,
+		allowAll:       len(opts.AllowedOrigins) == 0,
+		origins:        make(map[string]bool),
+		connect:        opts.Connect,
+		maxMessageSize: opts.MaxMessageSize,
+	}
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			h.allowAll = true
+		}
+		h.origins[origin] = true
+	}
+	if h.maxMessageSize <= 0 {
+		h.maxMessageSize = DefaultMaxMessageSize
+	}
+	return h
+}
+
+// ServeHTTP 处理跨域预检、gRPC-Web 和 Connect 请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !h.allowAll && !h.origins[origin] {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions {
+			h.preflight(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType := mediaType(r.Header.Get("Content-Type"))
+	switch {
+	case IsGrpcWebRequest(r):
+		h.serveGrpcWeb(w, r, contentType)
+	case h.connect && (contentType == contentTypeConnectPB || contentType == contentTypeConnectJSON):
+		h.serveConnect(w, r, contentType)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+	}
+}
+
+// IsGrpcWebRequest 判断是否为 gRPC-Web 请求
+func IsGrpcWebRequest(r *http.Request) bool {
+	contentType := mediaType(r.Header.Get("Content-Type"))
+	return r.Method == http.MethodPost && strings.HasPrefix(contentType, ContentTypeGrpcWeb)
+}
+
+// preflight 响应跨域预检请求
+func (h *Handler) preflight(w http.ResponseWriter, r *http.Request) {
+	headers := r.Header.Get("Access-Control-Request-Headers")
+	if headers == "" {
+		headers = allowedHeaders
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "POST")
+	w.Header().Set("Access-Control-Allow-Headers", headers)
+	w.Header().Set("Access-Control-Max-Age", "86400")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveGrpcWeb 处理 gRPC-Web 请求，消息帧原样转发，尾部元数据以尾帧写入响应体
+func (h *Handler) serveGrpcWeb(w http.ResponseWriter, r *http.Request, contentType string) {
+	text := strings.HasPrefix(contentType, ContentTypeGrpcWebText)
+	subtype := codecSubtype(contentType)
+
+	var body io.Reader = r.Body
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+	req := backendRequest(r, contentTypeGrpc+"+"+subtype, body)
+
+	responseType := ContentTypeGrpcWeb
+	if text {
+		responseType = ContentTypeGrpcWebText
+	}
+	writer := newResponseWriter(w, responseType+"+"+subtype, text)
+	h.backend.ServeHTTP(writer, req)
+	writer.finish()
+}
+
+// serveConnect 处理 Connect 协议的一元调用：请求体封装为 gRPC 消息帧，响应解包为消息本身，错误以 JSON 返回
+func (h *Handler) serveConnect(w http.ResponseWriter, r *http.Request, contentType string) {
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		writeConnectError(w, codeUnimplemented, fmt.Sprintf("content encoding %q is not supported", encoding))
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, h.maxMessageSize+1))
+	if err != nil {
+		writeConnectError(w, codeInvalidArgument, "failed to read request body")
+		return
+	}
+	if int64(len(payload)) > h.maxMessageSize {
+		writeConnectError(w, codeResourceExhausted, "request message too large")
+		return
+	}
+
+	subtype := "proto"
+	if contentType == contentTypeConnectJSON {
+		subtype = "json"
+	}
+	frame := make([]byte, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[1:frameHeaderSize], uint32(len(payload)))
+	copy(frame[frameHeaderSize:], payload)
+
+	req := backendRequest(r, contentTypeGrpc+"+"+subtype, bytes.NewReader(frame))
+	if timeout := r.Header.Get("Connect-Timeout-Ms"); timeout != "" {
+		if ms, err := strconv.ParseInt(timeout, 10, 64); err == nil && ms > 0 {
+			req.Header.Set("Grpc-Timeout", strconv.FormatInt(ms, 10)+"m")
+		}
+	}
+
+	recorder := &recorder{header: make(http.Header), status: http.StatusOK}
+	h.backend.ServeHTTP(recorder, req)
+
+	if recorder.status != http.StatusOK {
+		writeConnectError(w, codeUnknown, strings.TrimSpace(recorder.body.String()))
+		return
+	}
+	headers, trailers := splitTrailers(recorder.header, recorder.sent)
+	code, message := grpcStatus(headers, trailers)
+	if code != grpcStatusOK {
+		copyMetadata(w.Header(), headers, "")
+		copyMetadata(w.Header(), trailers, "")
+		writeConnectError(w, code, message)
+		return
+	}
+
+	data := recorder.body.Bytes()
+	if len(data) < frameHeaderSize {
+		writeConnectError(w, codeInternal, "missing response message")
+		return
+	}
+	if data[0]&frameCompressed != 0 {
+		writeConnectError(w, codeInternal, "compressed response message is not supported")
+		return
+	}
+	length := binary.BigEndian.Uint32(data[1:frameHeaderSize])
+	if uint32(len(data)-frameHeaderSize) < length {
+		writeConnectError(w, codeInternal, "truncated response message")
+		return
+	}
+
+	copyMetadata(w.Header(), headers, "")
+	copyMetadata(w.Header(), trailers, "Trailer-")
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatUint(uint64(length), 10))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data[frameHeaderSize : frameHeaderSize+length])
+}
+
+// backendRequest 构造交给 gRPC 后端的 HTTP/2 请求
+func backendRequest(r *http.Request, contentType string, body io.Reader) *http.Request {
+	req := r.Clone(r.Context())
+	req.Proto = "HTTP/2.0"
+	req.ProtoMajor = 2
+	req.ProtoMinor = 0
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Te", "trailers")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	req.Body = io.NopCloser(body)
+	return req
+}
+
+// responseWriter 将 gRPC 响应转换为 gRPC-Web 响应
+type responseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	encoder     io.WriteCloser
+	status      int
+	sent        map[string]bool
+}
+
+// newResponseWriter 创建 gRPC-Web 响应写入器，text 为 true 时响应体以 base64 编码
+func newResponseWriter(w http.ResponseWriter, contentType string, text bool) *responseWriter {
+	return &responseWriter{w: w, header: make(http.Header), contentType: contentType, text: text}
+}
+
+// Header 返回后端写入的响应头，WriteHeader 之后新增的项作为尾部元数据
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+// WriteHeader 写入响应头
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status != 0 {
+		return
+	}
+	rw.status = status
+	rw.sent = make(map[string]bool)
+	for key, values := range rw.header {
+		if key == "Trailer" || strings.HasPrefix(key, http.TrailerPrefix) || key == "Content-Length" {
+			continue
+		}
+		rw.sent[key] = true
+		for _, value := range values {
+			rw.w.Header().Add(key, value)
+		}
+	}
+	if status == http.StatusOK {
+		rw.w.Header().Set("Content-Type", rw.contentType)
+	}
+	rw.w.WriteHeader(status)
+}
+
+// Write 写入消息帧
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.status != http.StatusOK || !rw.text {
+		return rw.w.Write(data)
+	}
+	if rw.encoder == nil {
+		rw.encoder = base64.NewEncoder(base64.StdEncoding, rw.w)
+	}
+	return rw.encoder.Write(data)
+}
+
+// Flush 将已写入的消息发送给客户端，文本格式下每次刷新结束一段带填充的 base64
+func (rw *responseWriter) Flush() {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.encoder != nil {
+		rw.encoder.Close()
+		rw.encoder = nil
+	}
+	if flusher, ok := rw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish 写入尾帧
+func (rw *responseWriter) finish() {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.status != http.StatusOK {
+		return
+	}
+	_, trailers := splitTrailers(rw.header, rw.sent)
+	rw.Write(trailerFrame(trailers))
+	rw.Flush()
+}
+
+// recorder 记录 Connect 调用的 gRPC 响应
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+	sent   map[string]bool
+}
+
+// Header 返回响应头
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader 记录状态码和已发送的响应头
+func (r *recorder) WriteHeader(status int) {
+	if r.sent != nil {
+		return
+	}
+	r.status = status
+	r.sent = make(map[string]bool)
+	for key := range r.header {
+		r.sent[key] = true
+	}
+}
+
+// Write 记录响应体
+func (r *recorder) Write(data []byte) (int, error) {
+	if r.sent == nil {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.body.Write(data)
+}
+
+// Flush gRPC 后端要求写入器实现 http.Flusher
+func (r *recorder) Flush() {
+	if r.sent == nil {
+		r.WriteHeader(http.StatusOK)
+	}
+}
+
+// splitTrailers 按 net/http 的约定区分响应头和尾部元数据：
+// 以 http.TrailerPrefix 开头、在 Trailer 中声明或写入响应头之后新增的项为尾部元数据
+func splitTrailers(header http.Header, sent map[string]bool) (http.Header, http.Header) {
+	declared := make(map[string]bool)
+	for _, value := range header["Trailer"] {
+		for _, key := range strings.Split(value, ",") {
+			declared[http.CanonicalHeaderKey(strings.TrimSpace(key))] = true
+		}
+	}
+
+	headers := make(http.Header)
+	trailers := make(http.Header)
+	for key, values := range header {
+		switch {
+		case key == "Trailer" || key == "Content-Length" || key == "Content-Type":
+		case strings.HasPrefix(key, http.TrailerPrefix):
+			trailers[http.CanonicalHeaderKey(key[len(http.TrailerPrefix):])] = values
+		case declared[key] || (sent != nil && !sent[key]):
+			trailers[key] = values
+		default:
+			headers[key] = values
+		}
+	}
+	return headers, trailers
+}
+
+// trailerFrame 编码 gRPC-Web 尾帧，键为小写
+func trailerFrame(trailers http.Header) []byte {
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, frameHeaderSize))
+	for _, key := range keys {
+		for _, value := range trailers[key] {
+			buf.WriteString(strings.ToLower(key) + ": " + value + "\r\n")
+		}
+	}
+	frame := buf.Bytes()
+	frame[0] = frameTrailer
+	binary.BigEndian.PutUint32(frame[1:frameHeaderSize], uint32(len(frame)-frameHeaderSize))
+	return frame
+}
+
+// grpcStatus 读取 gRPC 状态码和消息，只有响应头的响应（trailers-only）状态在响应头中
+func grpcStatus(headers, trailers http.Header) (int, string) {
+	value, message := trailers.Get(grpcStatusHeader), trailers.Get(grpcMessageHeader)
+	if value == "" {
+		value, message = headers.Get(grpcStatusHeader), headers.Get(grpcMessageHeader)
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return codeUnknown, "missing grpc-status"
+	}
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+	return code, message
+}
+
+// copyMetadata 将 gRPC 元数据复制到 Connect 响应头，gRPC 保留的头不复制
+func copyMetadata(dst, src http.Header, prefix string) {
+	for key, values := range src {
+		if strings.HasPrefix(strings.ToLower(key), "grpc-") {
+			continue
+		}
+		for _, value := range values {
+			dst.Add(prefix+key, value)
+		}
+	}
+}
+
+// gRPC 状态码
+const (
+	codeUnknown           = 2
+	codeInvalidArgument   = 3
+	codeResourceExhausted = 8
+	codeUnimplemented     = 12
+	codeInternal          = 13
+)
+
+// connectCodes gRPC 状态码对应的 Connect 错误码和 HTTP 状态码
+var connectCodes = map[int]struct {
+	name   string
+	status int
+}{
+	1:  {"canceled", 499},
+	2:  {"unknown", http.StatusInternalServerError},
+	3:  {"invalid_argument", http.StatusBadRequest},
+	4:  {"deadline_exceeded", http.StatusGatewayTimeout},
+	5:  {"not_found", http.StatusNotFound},
+	6:  {"already_exists", http.StatusConflict},
+	7:  {"permission_denied", http.StatusForbidden},
+	8:  {"resource_exhausted", http.StatusTooManyRequests},
+	9:  {"failed_precondition", http.StatusBadRequest},
+	10: {"aborted", http.StatusConflict},
+	11: {"out_of_range", http.StatusBadRequest},
+	12: {"unimplemented", http.StatusNotImplemented},
+	13: {"internal", http.StatusInternalServerError},
+	14: {"unavailable", http.StatusServiceUnavailable},
+	15: {"data_loss", http.StatusInternalServerError},
+	16: {"unauthenticated", http.StatusUnauthorized},
+}
+
+// writeConnectError 以 Connect 错误格式写入响应
+func writeConnectError(w http.ResponseWriter, code int, message string) {
+	connectCode, ok := connectCodes[code]
+	if !ok {
+		connectCode = connectCodes[codeUnknown]
+	}
+	w.Header().Set("Content-Type", contentTypeConnectJSON)
+	w.WriteHeader(connectCode.status)
+	json.NewEncoder(w).Encode(map[string]string{"code": connectCode.name, "message": message})
+}
+
+// mediaType 返回不含参数的小写媒体类型
+func mediaType(value string) string {
+	parsed, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+	return parsed
+}
+
+// codecSubtype 返回 gRPC-Web 媒体类型的编码子类型，如 application/grpc-web+json -> json，默认为 proto
+func codecSubtype(contentType string) string {
+	if i := strings.IndexByte(contentType, '+'); i >= 0 && i < len(contentType)-1 {
+		return contentType[i+1:]
+	}
+	return "proto"
+}
@@ -0,0 +1,115 @@
+package grpcweb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// GrpcWebProtocolHandler gRPC-Web 协议处理器，浏览器应用经 HTTP/1.1 调用 gRPC 服务，无需 Envoy 等代理
+type GrpcWebProtocolHandler struct {
+	server  *ghttp.Server
+	config  *GrpcWebConfig
+	handler http.Handler
+	// disabled 运行时停用，见 SetEnabled
+	disabled atomic.Bool
+}
+
+// GrpcWebConfig gRPC-Web 配置
+type GrpcWebConfig struct {
+	Host string
+	Port int
+	// Path 路由前缀，转发给后端时去掉该前缀，为空时为 /
+	Path string
+	// Server 与同端口的其他 HTTP 协议共用的服务器，不为 nil 时 Start 只注册路由，服务器由调用方启动和关闭
+	Server *ghttp.Server
+	// Listener 不为 nil 时在该监听器上提供服务并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器；
+	// 监听器的 Addr 须为 *net.TCPAddr
+	Listener net.Listener
+	// Backend 处理转换后 gRPC 请求的后端，通常为本进程的 gRPC 服务器
+	Backend http.Handler
+	// Options 跨域和 Connect 协议配置
+	Options *Options
+}
+
+// NewGrpcWebProtocolHandler 创建 gRPC-Web 协议处理器
+func NewGrpcWebProtocolHandler(config *GrpcWebConfig) *GrpcWebProtocolHandler {
+	server := config.Server
+	if server == nil {
+		serverName := fmt.Sprintf("grpcweb-%s-%d", config.Host, config.Port)
+		if config.Listener != nil {
+			serverName = fmt.Sprintf("grpcweb-%s", config.Listener.Addr())
+		}
+		server = g.Server(serverName)
+	}
+	var handler http.Handler = NewHandler(config.Backend, config.Options)
+	if prefix := strings.TrimSuffix(config.Path, "/"); prefix != "" {
+		handler = http.StripPrefix(prefix, handler)
+	}
+	return &GrpcWebProtocolHandler{
+		server:  server,
+		config:  config,
+		handler: handler,
+	}
+}
+
+// Start 注册路由并启动服务器
+func (h *GrpcWebProtocolHandler) Start() error {
+	if h.config.Backend == nil {
+		return fmt.Errorf("gRPC-Web requires a backend")
+	}
+
+	group := h.server.Group(h.config.Path)
+	group.POST("/*", h.handleRequest)
+	group.OPTIONS("/*", h.handleRequest)
+
+	// 共用的服务器由调用方启动
+	if h.config.Server != nil {
+		return nil
+	}
+
+	if h.config.Listener != nil {
+		if err := h.server.SetListener(h.config.Listener); err != nil {
+			return err
+		}
+	} else {
+		h.server.SetAddr(fmt.Sprintf("%s:%d", h.config.Host, h.config.Port))
+	}
+	// 监听成功后返回，端口被占用等错误返回给调用方；请求在服务器的协程中处理
+	return h.server.Start()
+}
+
+// Stop 停止服务器，共用的服务器由调用方关闭
+func (h *GrpcWebProtocolHandler) Stop(ctx context.Context) error {
+	if h.config.Server != nil {
+		return nil
+	}
+	return h.server.Shutdown()
+}
+
+// SetEnabled 在运行时启用或停用协议，停用后新请求返回 503（客户端映射为 UNAVAILABLE）并关闭连接，
+// 处理中的请求继续完成；共用的服务器继续监听，同端口的其他协议不受影响
+func (h *GrpcWebProtocolHandler) SetEnabled(enabled bool) {
+	h.disabled.Store(!enabled)
+}
+
+// Enabled 返回协议是否启用
+func (h *GrpcWebProtocolHandler) Enabled() bool {
+	return !h.disabled.Load()
+}
+
+// handleRequest 处理 gRPC-Web、Connect 和跨域预检请求
+func (h *GrpcWebProtocolHandler) handleRequest(r *ghttp.Request) {
+	if h.disabled.Load() {
+		r.Response.Header().Set("Connection", "close")
+		http.Error(r.Response.Writer, "gRPC-Web protocol is disabled", http.StatusServiceUnavailable)
+		return
+	}
+	h.handler.ServeHTTP(r.Response.Writer, r.Request)
+}
@@ -0,0 +1,260 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeGrpcBackend 按 grpc-go ServeHTTP 的方式写入响应：预先声明尾部元数据，刷新响应头后写入消息帧，最后设置状态
+type fakeGrpcBackend struct {
+	status  string
+	message string
+	reply   []byte
+	// request 收到的请求，用于检查转换结果
+	request *http.Request
+	body    []byte
+}
+
+func (b *fakeGrpcBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.request = r
+	b.body, _ = io.ReadAll(r.Body)
+
+	h := w.Header()
+	h.Set("Content-Type", r.Header.Get("Content-Type"))
+	h.Add("Trailer", "Grpc-Status")
+	h.Add("Trailer", "Grpc-Message")
+	h.Set("X-Request-Id", "req-1")
+	w.WriteHeader(http.StatusOK)
+	if b.reply != nil {
+		w.Write(frame(b.reply))
+	}
+	w.(http.Flusher).Flush()
+
+	h.Set("Grpc-Status", b.status)
+	if b.message != "" {
+		h.Set("Grpc-Message", b.message)
+	}
+	h.Add(http.TrailerPrefix+"X-Cost", "3")
+}
+
+// frame 编码 gRPC 消息帧
+func frame(message []byte) []byte {
+	buf := make([]byte, frameHeaderSize+len(message))
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], uint32(len(message)))
+	copy(buf[frameHeaderSize:], message)
+	return buf
+}
+
+// readFrames 解析 gRPC-Web 响应体中的消息帧和尾帧
+func readFrames(t *testing.T, data []byte) ([][]byte, string) {
+	var messages [][]byte
+	var trailer string
+	for len(data) > 0 {
+		if len(data) < frameHeaderSize {
+			t.Fatalf("truncated frame: %v", data)
+		}
+		length := binary.BigEndian.Uint32(data[1:frameHeaderSize])
+		payload := data[frameHeaderSize : frameHeaderSize+length]
+		if data[0]&frameTrailer != 0 {
+			trailer = string(payload)
+		} else {
+			messages = append(messages, payload)
+		}
+		data = data[frameHeaderSize+length:]
+	}
+	return messages, trailer
+}
+
+func TestGrpcWeb(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		encode      func([]byte) []byte
+		decode      func([]byte) []byte
+		wantType    string
+		wantBackend string
+	}{
+		{
+			name:        "二进制格式",
+			contentType: "application/grpc-web+proto",
+			encode:      func(b []byte) []byte { return b },
+			decode:      func(b []byte) []byte { return b },
+			wantType:    "application/grpc-web+proto",
+			wantBackend: "application/grpc+proto",
+		},
+		{
+			name:        "文本格式",
+			contentType: "application/grpc-web-text",
+			encode:      func(b []byte) []byte { return []byte(base64.StdEncoding.EncodeToString(b)) },
+			decode: func(b []byte) []byte {
+				// 每次刷新结束一段带填充的 base64
+				var out []byte
+				for len(b) > 0 {
+					end := bytes.IndexByte(b, '=')
+					for end >= 0 && end+1 < len(b) && b[end+1] == '=' {
+						end++
+					}
+					chunk := b
+					if end >= 0 && end+1 < len(b) {
+						chunk, b = b[:end+1], b[end+1:]
+					} else {
+						b = nil
+					}
+					decoded, err := base64.StdEncoding.DecodeString(string(chunk))
+					if err != nil {
+						t.Fatalf("invalid base64 %q: %v", chunk, err)
+					}
+					out = append(out, decoded...)
+				}
+				return out
+			},
+			wantType:    "application/grpc-web-text+proto",
+			wantBackend: "application/grpc+proto",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeGrpcBackend{status: "0", reply: []byte("pong")}
+			handler := NewHandler(backend, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/hello.Greeter/SayHello", bytes.NewReader(tt.encode(frame([]byte("ping")))))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if backend.request.ProtoMajor != 2 || backend.request.Header.Get("Content-Type") != tt.wantBackend ||
+				backend.request.Header.Get("Te") != "trailers" {
+				t.Errorf("unexpected backend request: %s %v", backend.request.Proto, backend.request.Header)
+			}
+			if !bytes.Equal(backend.body, frame([]byte("ping"))) {
+				t.Errorf("unexpected backend body: %v", backend.body)
+			}
+
+			if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != tt.wantType {
+				t.Fatalf("unexpected response: %d %v", rec.Code, rec.Header())
+			}
+			if rec.Header().Get("X-Request-Id") != "req-1" || rec.Header().Get("Trailer") != "" {
+				t.Errorf("unexpected response headers: %v", rec.Header())
+			}
+			messages, trailer := readFrames(t, tt.decode(rec.Body.Bytes()))
+			if len(messages) != 1 || string(messages[0]) != "pong" {
+				t.Errorf("unexpected messages: %q", messages)
+			}
+			if trailer != "grpc-status: 0\r\nx-cost: 3\r\n" {
+				t.Errorf("unexpected trailer: %q", trailer)
+			}
+		})
+	}
+}
+
+func TestConnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		backend    *fakeGrpcBackend
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "调用成功",
+			backend:    &fakeGrpcBackend{status: "0", reply: []byte("pong")},
+			wantStatus: http.StatusOK,
+			wantBody:   "pong",
+		},
+		{
+			name:       "调用失败",
+			backend:    &fakeGrpcBackend{status: "5", message: "user%20not%20found"},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":"not_found","message":"user not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(tt.backend, &Options{Connect: true})
+
+			req := httptest.NewRequest(http.MethodPost, "/hello.Greeter/SayHello", strings.NewReader("ping"))
+			req.Header.Set("Content-Type", "application/proto")
+			req.Header.Set("Connect-Timeout-Ms", "1500")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if !bytes.Equal(tt.backend.body, frame([]byte("ping"))) || tt.backend.request.Header.Get("Grpc-Timeout") != "1500m" {
+				t.Errorf("unexpected backend request: %v %v", tt.backend.body, tt.backend.request.Header)
+			}
+			if rec.Code != tt.wantStatus || strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantBody, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && rec.Header().Get("Trailer-X-Cost") != "3" {
+				t.Errorf("trailers should be sent as Trailer- headers: %v", rec.Header())
+			}
+		})
+	}
+
+	t.Run("未启用 Connect", func(t *testing.T) {
+		handler := NewHandler(&fakeGrpcBackend{status: "0"}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/hello.Greeter/SayHello", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("expected 415, got %d", rec.Code)
+		}
+	})
+}
+
+func TestCORS(t *testing.T) {
+	handler := NewHandler(&fakeGrpcBackend{status: "0"}, &Options{AllowedOrigins: []string{"https://app.example.com"}})
+
+	t.Run("预检请求", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/hello.Greeter/SayHello", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+			rec.Header().Get("Access-Control-Allow-Headers") != "content-type,x-grpc-web" {
+			t.Errorf("unexpected preflight response: %d %v", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("暴露状态响应头", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/hello.Greeter/SayHello", bytes.NewReader(frame(nil)))
+		req.Header.Set("Content-Type", "application/grpc-web")
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if !strings.Contains(rec.Header().Get("Access-Control-Expose-Headers"), "Grpc-Status") {
+			t.Errorf("grpc-status should be exposed: %v", rec.Header())
+		}
+	})
+
+	t.Run("不允许的来源", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/hello.Greeter/SayHello", nil)
+		req.Header.Set("Content-Type", "application/grpc-web")
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+	})
+}
+
+func TestConnectErrorCodes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeConnectError(rec, 16, "token expired")
+
+	var body map[string]string
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusUnauthorized || body["code"] != "unauthenticated" || body["message"] != "token expired" {
+		t.Errorf("unexpected error response: %d %v", rec.Code, body)
+	}
+}
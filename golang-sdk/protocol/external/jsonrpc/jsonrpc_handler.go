@@ -1,19 +1,29 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 )
 
 // JsonRpcProtocolHandler JSON-RPC 2.0 协议处理器
 type JsonRpcProtocolHandler struct {
-	server *ghttp.Server
-	config *JsonRpcConfig
+	server   *ghttp.Server
+	config   *JsonRpcConfig
+	handlers map[string]MethodHandler
+	mu       sync.RWMutex
+	// disabled 运行时停用，见 SetEnabled
+	disabled atomic.Bool
 }
 
 // JsonRpcConfig JSON-RPC 配置
@@ -21,48 +31,121 @@ type JsonRpcConfig struct {
 	Host string
 	Port int
 	Path string
+	// Server 与同端口的其他 HTTP 协议共用的服务器，不为 nil 时 Start 只注册路由，服务器由调用方启动和关闭
+	Server *ghttp.Server
+	// Listener 不为 nil 时在该监听器上提供服务并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器；
+	// 监听器的 Addr 须为 *net.TCPAddr
+	Listener net.Listener
+	// Authenticate 调用方法处理器前认证请求，返回携带调用方安全上下文的 context；
+	// 为 nil 时从请求头恢复上游服务传递的安全上下文
+	Authenticate func(ctx context.Context, headers map[string]string, method string) (context.Context, error)
+	// Attachments 不为 nil 时接受 multipart/form-data 请求：名为 request 的部分为 JSON-RPC 请求，其他部分为附件，
+	// 业务方法通过 adapter.ResolveAttachment 或 adapter.BindAttachments 读取；请求头 Accept 包含 multipart/form-data 时
+	// 结果中的附件同样以独立的部分返回。Start 时将服务器的请求体上限调整为 MaxRequestSize，见 adapter.Attachment
+	Attachments *adapter.AttachmentLimits
 }
 
+// defaultClientMaxBodySize HTTP 服务器默认的请求体字节数上限，与 ghttp 的默认值一致
+const defaultClientMaxBodySize = 8 << 20
+
+// MethodHandler 方法处理器
+type MethodHandler func(ctx context.Context, params interface{}) (interface{}, error)
+
 // NewJsonRpcProtocolHandler 创建 JSON-RPC 协议处理器
 func NewJsonRpcProtocolHandler(config *JsonRpcConfig) *JsonRpcProtocolHandler {
-	// 为每个handler创建独立的命名服务器实例
-	serverName := fmt.Sprintf("jsonrpc-%s-%d", config.Host, config.Port)
-	server := g.Server(serverName)
+	server := config.Server
+	if server == nil {
+		// 为每个handler创建独立的命名服务器实例
+		serverName := fmt.Sprintf("jsonrpc-%s-%d", config.Host, config.Port)
+		if config.Listener != nil {
+			serverName = fmt.Sprintf("jsonrpc-%s", config.Listener.Addr())
+		}
+		server = g.Server(serverName)
+	}
 	return &JsonRpcProtocolHandler{
-		server: server,
-		config: config,
+		server:   server,
+		config:   config,
+		handlers: make(map[string]MethodHandler),
 	}
 }
 
 // Start 启动 JSON-RPC 服务器
 func (h *JsonRpcProtocolHandler) Start() error {
-	// 配置服务器
-	h.server.SetAddr(fmt.Sprintf("%s:%d", h.config.Host, h.config.Port))
-	
 	// 注册 JSON-RPC 路由
 	h.server.BindHandler(h.config.Path, h.handleJsonRpc)
+	if h.config.Attachments != nil {
+		h.server.SetClientMaxBodySize(max(h.config.Attachments.MaxRequestSize(), defaultClientMaxBodySize))
+	}
 	
-	// 启动服务器
-	go h.server.Run()
+	// 共用的服务器由调用方启动
+	if h.config.Server != nil {
+		return nil
+	}
 	
-	return nil
+	// 配置并启动服务器
+	if h.config.Listener != nil {
+		if err := h.server.SetListener(h.config.Listener); err != nil {
+			return err
+		}
+	} else {
+		h.server.SetAddr(fmt.Sprintf("%s:%d", h.config.Host, h.config.Port))
+	}
+	// 监听成功后返回，端口被占用等错误返回给调用方；请求在服务器的协程中处理
+	return h.server.Start()
 }
 
-// Stop 停止 JSON-RPC 服务器
+// Stop 停止 JSON-RPC 服务器，共用的服务器由调用方关闭
 func (h *JsonRpcProtocolHandler) Stop(ctx context.Context) error {
+	if h.config.Server != nil {
+		return nil
+	}
 	return h.server.Shutdown()
 }
 
+// SetEnabled 在运行时启用或停用协议，停用后新请求返回 503 和 ServiceUnavailable 错误并关闭连接，
+// 处理中的请求继续完成；共用的服务器继续监听，同端口的其他协议不受影响
+func (h *JsonRpcProtocolHandler) SetEnabled(enabled bool) {
+	h.disabled.Store(!enabled)
+}
+
+// Enabled 返回协议是否启用
+func (h *JsonRpcProtocolHandler) Enabled() bool {
+	return !h.disabled.Load()
+}
+
+// RegisterMethod 注册方法处理器
+//
+// 注册任一方法后，调用未注册的方法返回 NotFound（JSON-RPC 错误码 -32601）
+func (h *JsonRpcProtocolHandler) RegisterMethod(method string, handler MethodHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	
+	h.handlers[method] = handler
+}
+
 // handleJsonRpc 处理 JSON-RPC 请求
 func (h *JsonRpcProtocolHandler) handleJsonRpc(r *ghttp.Request) {
+	if h.disabled.Load() {
+		rpcErr := MethodError(r.Context(), frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "JSON-RPC protocol is disabled"))
+		r.Response.Header().Set("Connection", "close")
+		r.Response.WriteHeader(http.StatusServiceUnavailable)
+		h.sendError(r, nil, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
 	// 只接受 POST 请求
 	if r.Method != http.MethodPost {
 		h.sendError(r, nil, -32600, "Invalid Request", "Only POST method is allowed")
 		return
 	}
 	
-	// 解析请求
-	body := r.GetBody()
+	// 解析请求，multipart 请求的附件在响应发送后删除临时文件
+	body, attachments, err := h.readBody(r)
+	if err != nil {
+		rpcErr := MethodError(r.Context(), err)
+		h.sendError(r, nil, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
+	defer attachments.Close()
 	var request JsonRpcRequest
 	if err := json.Unmarshal(body, &request); err != nil {
 		h.sendError(r, nil, -32700, "Parse error", err.Error())
@@ -82,14 +165,83 @@ func (h *JsonRpcProtocolHandler) handleJsonRpc(r *ghttp.Request) {
 	}
 	
 	// 处理请求
-	result := h.handleMethod(request.Method, request.Params)
+	headers := make(map[string]string)
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+	ctx := adapter.ExtractTraceContext(r.Context(), headers)
+	// 剩余超时可在请求头或 meta 中，两者都有时以较早的截止时间为准；客户端断开连接时 ctx 同样结束
+	ctx, cancelHeader := adapter.ExtractDeadline(ctx, headers)
+	defer cancelHeader()
+	ctx, cancelMeta := adapter.ExtractDeadline(ctx, request.Meta)
+	defer cancelMeta()
+	if ctx.Err() != nil {
+		code, _ := frameworkerrors.ContextErrorCode(ctx.Err())
+		rpcErr := MethodError(ctx, frameworkerrors.NewFrameworkError(code, fmt.Sprintf("method %s: %v before handling", request.Method, ctx.Err())))
+		h.sendError(r, request.Id, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
+	if attachments != nil {
+		ctx = adapter.WithAttachments(ctx, attachments)
+	}
+	ctx, span := adapter.StartServerSpan(ctx, adapter.ProtocolJSONRPC, "", request.Method)
+	result, err := h.handleMethod(ctx, headers, request.Method, request.Params)
+	adapter.EndSpan(span, err)
+	if err != nil {
+		rpcErr := MethodError(ctx, err)
+		h.sendError(r, request.Id, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
+	if stream, ok := result.(*adapter.Stream); ok {
+		h.sendStream(ctx, r, request.Id, stream)
+		return
+	}
 	
 	// 发送响应
 	h.sendResponse(r, request.Id, result)
 }
 
-// handleMethod 处理 JSON-RPC 方法调用
-func (h *JsonRpcProtocolHandler) handleMethod(method string, params interface{}) interface{} {
+// readBody 读取请求体，启用附件时 multipart 请求返回其中的 JSON-RPC 请求和附件，其他请求返回空的附件集合
+func (h *JsonRpcProtocolHandler) readBody(r *ghttp.Request) ([]byte, *adapter.Attachments, error) {
+	boundary, multipart := adapter.IsMultipart(r.Header.Get("Content-Type"))
+	if h.config.Attachments == nil {
+		if multipart {
+			return nil, nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "multipart requests are not enabled")
+		}
+		return r.GetBody(), nil, nil
+	}
+	if !multipart {
+		return r.GetBody(), adapter.NewAttachments(h.config.Attachments), nil
+	}
+	return adapter.ReadMultipart(r.Body, boundary, h.config.Attachments)
+}
+
+// handleMethod 认证请求并调用已注册的方法处理器，未注册任何方法时返回占位响应
+func (h *JsonRpcProtocolHandler) handleMethod(ctx context.Context, headers map[string]string, method string, params interface{}) (interface{}, error) {
+	h.mu.RLock()
+	handler, exists := h.handlers[method]
+	registered := len(h.handlers)
+	h.mu.RUnlock()
+	
+	if exists {
+		if h.config.Authenticate != nil {
+			authenticated, err := h.config.Authenticate(ctx, headers, method)
+			if err != nil {
+				return nil, err
+			}
+			ctx = authenticated
+		} else if sc := adapter.SecurityContextFromHeaders(headers); sc != nil {
+			ctx = adapter.WithSecurityContext(ctx, sc)
+		}
+		return handler(ctx, params)
+	}
+	
+	if registered > 0 {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, fmt.Sprintf("method %s not found", method))
+	}
+	
 	// TODO: 调用协议适配器转换请求
 	// TODO: 调用消息路由器路由到目标服务
 	// TODO: 获取响应并返回
@@ -99,7 +251,7 @@ func (h *JsonRpcProtocolHandler) handleMethod(method string, params interface{})
 		"message": "JSON-RPC handler is working",
 		"method":  method,
 		"params":  params,
-	}
+	}, nil
 }
 
 // sendResponse 发送 JSON-RPC 响应
@@ -110,10 +262,78 @@ func (h *JsonRpcProtocolHandler) sendResponse(r *ghttp.Request, id interface{},
 		Result:  result,
 	}
 	
+	// 客户端接受 multipart 时附件以独立的部分返回，否则以 Base64 内联
+	if h.config.Attachments != nil && adapter.AcceptsMultipart(r.Header.Get("Accept")) {
+		if attachments := adapter.CollectAttachments(result); len(attachments) > 0 {
+			var buf bytes.Buffer
+			contentType, err := adapter.WriteMultipart(&buf, response, attachments)
+			if err != nil {
+				h.sendError(r, id, -32603, "Internal error", err.Error())
+				return
+			}
+			r.Response.Header().Set("Content-Type", contentType)
+			r.Response.Write(buf.Bytes())
+			return
+		}
+	}
+	
 	r.Response.Header().Set("Content-Type", "application/json")
 	r.Response.WriteJson(response)
 }
 
+// sendStream 发送流式结果
+//
+// Accept 为 application/x-ndjson 时以 NDJSON 逐项发送 ProgressMethod 通知，每个通知发送后立即刷新，
+// 最后一行为结果为 StreamResult 的响应，中途出错时为错误响应；否则收集所有元素，结果为元素组成的数组
+func (h *JsonRpcProtocolHandler) sendStream(ctx context.Context, r *ghttp.Request, id interface{}, stream *adapter.Stream) {
+	if !adapter.AcceptsNDJSON(r.Header.Get("Accept")) {
+		items, err := stream.Collect(ctx)
+		if err != nil {
+			rpcErr := MethodError(ctx, err)
+			h.sendError(r, id, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+			return
+		}
+		h.sendResponse(r, id, items)
+		return
+	}
+	
+	r.Response.Header().Set("Content-Type", adapter.NDJSONContentType)
+	count := 0
+	err := stream.Each(ctx, func(item interface{}) error {
+		data, err := json.Marshal(JsonRpcNotification{
+			Jsonrpc: "2.0",
+			Method:  ProgressMethod,
+			Params:  ProgressParams{Id: id, Value: item},
+		})
+		if err != nil {
+			return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to serialize stream item")
+		}
+		r.Response.Write(data, "\n")
+		r.Response.Flush()
+		count++
+		return nil
+	})
+	
+	response := JsonRpcResponse{Jsonrpc: "2.0", Id: id, Result: &StreamResult{Count: count}}
+	if err != nil {
+		response.Result = nil
+		response.Error = MethodError(ctx, err)
+	}
+	data, _ := json.Marshal(response)
+	r.Response.Write(data, "\n")
+}
+
+// MethodError 将方法处理器返回的错误转换为 JSON-RPC 错误，data 为跨语言传输格式的结构化错误；
+// 其他承载 JSON-RPC 的传输（如 WebSocket）以相同方式转换错误
+func MethodError(ctx context.Context, err error) *JsonRpcError {
+	payload := adapter.NewErrorPayload(ctx, err)
+	return &JsonRpcError{
+		Code:    frameworkerrors.ErrorCode(payload.Code).ToJSONRPCCode(),
+		Message: payload.Message,
+		Data:    payload,
+	}
+}
+
 // sendError 发送 JSON-RPC 错误响应
 func (h *JsonRpcProtocolHandler) sendError(r *ghttp.Request, id interface{}, code int, message string, data interface{}) {
 	response := JsonRpcResponse{
@@ -136,6 +356,8 @@ type JsonRpcRequest struct {
 	Method  string      `json:"method"`
 	Params  interface{} `json:"params,omitempty"`
 	Id      interface{} `json:"id"`
+	// Meta 框架扩展字段，与内部 JSON-RPC 相同；目前只读取剩余超时 adapter.HeaderTimeout
+	Meta map[string]string `json:"meta,omitempty"`
 }
 
 // JsonRpcResponse JSON-RPC 2.0 响应
@@ -146,6 +368,29 @@ type JsonRpcResponse struct {
 	Id      interface{}   `json:"id"`
 }
 
+// ProgressMethod 流式结果中逐项发送的通知的方法名
+const ProgressMethod = "$/progress"
+
+// JsonRpcNotification JSON-RPC 2.0 通知
+type JsonRpcNotification struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// ProgressParams ProgressMethod 通知的参数
+type ProgressParams struct {
+	// Id 所属请求的 ID
+	Id    interface{} `json:"id"`
+	Value interface{} `json:"value"`
+}
+
+// StreamResult 流式结果发送完毕后响应的结果
+type StreamResult struct {
+	// Count 已发送的元素数
+	Count int `json:"count"`
+}
+
 // JsonRpcError JSON-RPC 2.0 错误
 type JsonRpcError struct {
 	Code    int         `json:"code"`
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/transport/memory"
 )
 
 // TestJsonRpcHandlerCreation 测试 JSON-RPC 处理器创建
@@ -25,10 +30,10 @@ func TestJsonRpcHandlerCreation(t *testing.T) {
 
 // TestJsonRpcHandlerStartStop 测试 JSON-RPC 处理器启动和停止
 func TestJsonRpcHandlerStartStop(t *testing.T) {
+	listener := memory.Listen()
 	config := &JsonRpcConfig{
-		Host: "127.0.0.1",
-		Port: 8097,
-		Path: "/jsonrpc",
+		Listener: listener,
+		Path:     "/jsonrpc",
 	}
 	
 	handler := NewJsonRpcProtocolHandler(config)
@@ -39,9 +44,6 @@ func TestJsonRpcHandlerStartStop(t *testing.T) {
 		t.Fatalf("Failed to start JSON-RPC handler: %v", err)
 	}
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 停止处理器
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -54,10 +56,10 @@ func TestJsonRpcHandlerStartStop(t *testing.T) {
 
 // TestJsonRpcValidRequest 测试有效的 JSON-RPC 请求
 func TestJsonRpcValidRequest(t *testing.T) {
+	listener := memory.Listen()
 	config := &JsonRpcConfig{
-		Host: "127.0.0.1",
-		Port: 8098,
-		Path: "/jsonrpc",
+		Listener: listener,
+		Path:     "/jsonrpc",
 	}
 	
 	handler := NewJsonRpcProtocolHandler(config)
@@ -67,9 +69,6 @@ func TestJsonRpcValidRequest(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 构造 JSON-RPC 请求
 	request := JsonRpcRequest{
 		Jsonrpc: "2.0",
@@ -81,7 +80,7 @@ func TestJsonRpcValidRequest(t *testing.T) {
 	requestBody, _ := json.Marshal(request)
 	
 	// 发送请求
-	resp, err := http.Post("http://127.0.0.1:8098/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		t.Fatalf("Failed to send JSON-RPC request: %v", err)
 	}
@@ -109,10 +108,10 @@ func TestJsonRpcValidRequest(t *testing.T) {
 
 // TestJsonRpcInvalidVersion 测试无效的 JSON-RPC 版本
 func TestJsonRpcInvalidVersion(t *testing.T) {
+	listener := memory.Listen()
 	config := &JsonRpcConfig{
-		Host: "127.0.0.1",
-		Port: 8099,
-		Path: "/jsonrpc",
+		Listener: listener,
+		Path:     "/jsonrpc",
 	}
 	
 	handler := NewJsonRpcProtocolHandler(config)
@@ -122,9 +121,6 @@ func TestJsonRpcInvalidVersion(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 构造无效版本的请求
 	request := JsonRpcRequest{
 		Jsonrpc: "1.0",
@@ -135,7 +131,7 @@ func TestJsonRpcInvalidVersion(t *testing.T) {
 	requestBody, _ := json.Marshal(request)
 	
 	// 发送请求
-	resp, err := http.Post("http://127.0.0.1:8099/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		t.Fatalf("Failed to send JSON-RPC request: %v", err)
 	}
@@ -159,10 +155,10 @@ func TestJsonRpcInvalidVersion(t *testing.T) {
 
 // TestJsonRpcMissingMethod 测试缺少方法名的请求
 func TestJsonRpcMissingMethod(t *testing.T) {
+	listener := memory.Listen()
 	config := &JsonRpcConfig{
-		Host: "127.0.0.1",
-		Port: 8100,
-		Path: "/jsonrpc",
+		Listener: listener,
+		Path:     "/jsonrpc",
 	}
 	
 	handler := NewJsonRpcProtocolHandler(config)
@@ -172,9 +168,6 @@ func TestJsonRpcMissingMethod(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 构造缺少方法名的请求
 	request := JsonRpcRequest{
 		Jsonrpc: "2.0",
@@ -185,7 +178,7 @@ func TestJsonRpcMissingMethod(t *testing.T) {
 	requestBody, _ := json.Marshal(request)
 	
 	// 发送请求
-	resp, err := http.Post("http://127.0.0.1:8100/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
 		t.Fatalf("Failed to send JSON-RPC request: %v", err)
 	}
@@ -205,10 +198,10 @@ func TestJsonRpcMissingMethod(t *testing.T) {
 
 // TestJsonRpcInvalidJson 测试无效的 JSON
 func TestJsonRpcInvalidJson(t *testing.T) {
+	listener := memory.Listen()
 	config := &JsonRpcConfig{
-		Host: "127.0.0.1",
-		Port: 8101,
-		Path: "/jsonrpc",
+		Listener: listener,
+		Path:     "/jsonrpc",
 	}
 	
 	handler := NewJsonRpcProtocolHandler(config)
@@ -218,13 +211,10 @@ func TestJsonRpcInvalidJson(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 发送无效的 JSON
 	invalidJson := []byte(`{invalid json}`)
 	
-	resp, err := http.Post("http://127.0.0.1:8101/jsonrpc", "application/json", bytes.NewBuffer(invalidJson))
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(invalidJson))
 	if err != nil {
 		t.Fatalf("Failed to send request: %v", err)
 	}
@@ -245,3 +235,327 @@ func TestJsonRpcInvalidJson(t *testing.T) {
 		t.Errorf("Expected error code -32700, got %d", response.Error.Code)
 	}
 }
+
+// TestJsonRpcRegisteredMethod 测试已注册方法的调用、错误和未注册方法
+func TestJsonRpcRegisteredMethod(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewJsonRpcProtocolHandler(&JsonRpcConfig{
+		Listener: listener,
+		Path:     "/jsonrpc",
+	})
+	handler.RegisterMethod("user.get", func(ctx context.Context, params interface{}) (interface{}, error) {
+		args, _ := params.(map[string]interface{})
+		if args["id"] == nil {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "id is required")
+		}
+		return map[string]interface{}{"id": args["id"], "name": "Alice"}, nil
+	})
+	handler.RegisterMethod("ctx.deadline", func(ctx context.Context, params interface{}) (interface{}, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "no deadline")
+		}
+		return time.Until(deadline).Milliseconds(), nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start JSON-RPC handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	call := func(method string, params interface{}) JsonRpcResponse {
+		t.Helper()
+		body, _ := json.Marshal(JsonRpcRequest{Jsonrpc: "2.0", Method: method, Params: params, Id: 1})
+		resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to send JSON-RPC request: %v", err)
+		}
+		defer resp.Body.Close()
+		
+		var response JsonRpcResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+	
+	t.Run("调用成功", func(t *testing.T) {
+		response := call("user.get", map[string]interface{}{"id": "1"})
+		result, ok := response.Result.(map[string]interface{})
+		if response.Error != nil || !ok || result["name"] != "Alice" {
+			t.Errorf("Unexpected response: %+v", response)
+		}
+	})
+	
+	t.Run("处理器错误", func(t *testing.T) {
+		response := call("user.get", map[string]interface{}{})
+		if response.Error == nil || response.Error.Code != frameworkerrors.BadRequest.ToJSONRPCCode() {
+			t.Fatalf("Expected BadRequest error, got %+v", response.Error)
+		}
+		data, _ := json.Marshal(response.Error.Data)
+		if fe, err := frameworkerrors.UnmarshalError(data); err != nil || fe.Code != frameworkerrors.BadRequest {
+			t.Errorf("Expected structured error data, got %s", data)
+		}
+	})
+	
+	t.Run("截止时间", func(t *testing.T) {
+		body, _ := json.Marshal(JsonRpcRequest{Jsonrpc: "2.0", Method: "ctx.deadline", Id: 1,
+			Meta: map[string]string{adapter.HeaderTimeout: "5000"}})
+		resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to send JSON-RPC request: %v", err)
+		}
+		defer resp.Body.Close()
+		var response JsonRpcResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		if remaining, ok := response.Result.(float64); !ok || remaining <= 0 || remaining > 5000 {
+			t.Errorf("Expected handler ctx deadline from meta, got %+v", response)
+		}
+	})
+	
+	t.Run("未注册的方法", func(t *testing.T) {
+		response := call("user.delete", nil)
+		if response.Error == nil || response.Error.Code != -32601 {
+			t.Errorf("Expected error code -32601, got %+v", response.Error)
+		}
+	})
+}
+
+// TestJsonRpcStream 测试流式结果的进度通知
+func TestJsonRpcStream(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewJsonRpcProtocolHandler(&JsonRpcConfig{
+		Listener: listener,
+		Path:     "/jsonrpc",
+	})
+	handler.RegisterMethod("item.list", func(ctx context.Context, params interface{}) (interface{}, error) {
+		args, _ := params.(map[string]interface{})
+		failAt, _ := args["failAt"].(float64)
+		return adapter.NewStream(func(ctx context.Context, send func(item interface{}) error) error {
+			for i := 1; i <= 3; i++ {
+				if i == int(failAt) {
+					return frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "backend unavailable")
+				}
+				if err := send(i); err != nil {
+					return err
+				}
+			}
+			return nil
+		}), nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start JSON-RPC handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	call := func(accept string, params interface{}) []json.RawMessage {
+		t.Helper()
+		body, _ := json.Marshal(JsonRpcRequest{Jsonrpc: "2.0", Method: "item.list", Params: params, Id: 7})
+		req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Address()+"/jsonrpc", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send JSON-RPC request: %v", err)
+		}
+		defer resp.Body.Close()
+		
+		var lines []json.RawMessage
+		decoder := json.NewDecoder(resp.Body)
+		for decoder.More() {
+			var line json.RawMessage
+			if err := decoder.Decode(&line); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+	
+	t.Run("进度通知", func(t *testing.T) {
+		lines := call(adapter.NDJSONContentType, nil)
+		if len(lines) != 4 {
+			t.Fatalf("Expected 3 notifications and a response, got %d lines", len(lines))
+		}
+		for i, line := range lines[:3] {
+			want := fmt.Sprintf(`{"jsonrpc":"2.0","method":"$/progress","params":{"id":7,"value":%d}}`, i+1)
+			if string(line) != want {
+				t.Errorf("line %d = %s, want %s", i, line, want)
+			}
+		}
+		if want := `{"jsonrpc":"2.0","result":{"count":3},"id":7}`; string(lines[3]) != want {
+			t.Errorf("response = %s, want %s", lines[3], want)
+		}
+	})
+	
+	t.Run("中途出错", func(t *testing.T) {
+		lines := call(adapter.NDJSONContentType, map[string]interface{}{"failAt": 2})
+		var response JsonRpcResponse
+		if len(lines) != 2 || json.Unmarshal(lines[1], &response) != nil {
+			t.Fatalf("Unexpected lines: %s", lines)
+		}
+		if response.Error == nil || response.Error.Code != frameworkerrors.ServiceUnavailable.ToJSONRPCCode() {
+			t.Errorf("Expected ServiceUnavailable error, got %+v", response)
+		}
+	})
+	
+	t.Run("未请求流式响应", func(t *testing.T) {
+		lines := call("application/json", nil)
+		if len(lines) != 1 || string(lines[0]) != `{"jsonrpc":"2.0","result":[1,2,3],"id":7}` {
+			t.Errorf("Unexpected response: %s", lines)
+		}
+	})
+}
+
+func TestJsonRpcAttachments(t *testing.T) {
+	newHandler := func(limits *adapter.AttachmentLimits) (*memory.Listener, *JsonRpcProtocolHandler) {
+		listener := memory.Listen()
+		handler := NewJsonRpcProtocolHandler(&JsonRpcConfig{
+			Listener:    listener,
+			Path:        "/jsonrpc",
+			Attachments: limits,
+		})
+		// 返回大写的附件内容
+		handler.RegisterMethod("file.upper", func(ctx context.Context, params interface{}) (interface{}, error) {
+			args, _ := params.(map[string]interface{})
+			file, err := adapter.ResolveAttachment(ctx, args["file"])
+			if err != nil {
+				return nil, err
+			}
+			content, err := file.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"file": adapter.NewAttachment("upper-"+file.Name, file.ContentType, bytes.ToUpper(content)),
+			}, nil
+		})
+		if err := handler.Start(); err != nil {
+			t.Fatalf("Failed to start JSON-RPC handler: %v", err)
+		}
+		return listener, handler
+	}
+	call := func(listener *memory.Listener, accept string, params map[string]interface{}) (*http.Response, []byte, *adapter.Attachments) {
+		t.Helper()
+		request := JsonRpcRequest{Jsonrpc: "2.0", Method: "file.upper", Params: params, Id: 3}
+		var body bytes.Buffer
+		contentType, err := adapter.WriteMultipart(&body, request, adapter.CollectAttachments(params))
+		if err != nil {
+			t.Fatalf("WriteMultipart failed: %v", err)
+		}
+		req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Address()+"/jsonrpc", &body)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", accept)
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send JSON-RPC request: %v", err)
+		}
+		defer resp.Body.Close()
+		if boundary, ok := adapter.IsMultipart(resp.Header.Get("Content-Type")); ok {
+			payload, attachments, err := adapter.ReadMultipart(resp.Body, boundary, nil)
+			if err != nil {
+				t.Fatalf("Failed to read multipart response: %v", err)
+			}
+			return resp, payload, attachments
+		}
+		var payload bytes.Buffer
+		payload.ReadFrom(resp.Body)
+		return resp, payload.Bytes(), nil
+	}
+	params := func() map[string]interface{} {
+		return map[string]interface{}{"file": adapter.NewAttachment("note.txt", "text/plain", []byte("hello"))}
+	}
+
+	listener, handler := newHandler(&adapter.AttachmentLimits{MaxSize: 1024})
+	defer handler.Stop(context.Background())
+
+	t.Run("multipart 请求和响应", func(t *testing.T) {
+		_, payload, attachments := call(listener, "application/json, multipart/form-data", params())
+		var response struct {
+			Result struct {
+				File *adapter.Attachment `json:"file"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(payload, &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if err := attachments.Bind(&response); err != nil {
+			t.Fatalf("Bind failed: %v", err)
+		}
+		content, _ := response.Result.File.Bytes()
+		if response.Result.File.Name != "upper-note.txt" || string(content) != "HELLO" {
+			t.Errorf("Unexpected result attachment %s: %q", response.Result.File.Name, content)
+		}
+	})
+
+	t.Run("结果附件内联", func(t *testing.T) {
+		_, payload, _ := call(listener, "application/json", params())
+		want := `{"jsonrpc":"2.0","result":{"file":{"$attachment":"upper-note.txt","contentType":"text/plain","size":5,"data":"SEVMTE8="}},"id":3}`
+		if string(payload) != want {
+			t.Errorf("response = %s, want %s", payload, want)
+		}
+	})
+
+	t.Run("附件超限", func(t *testing.T) {
+		large := map[string]interface{}{"file": adapter.NewAttachment("big.bin", "", make([]byte, 2048))}
+		_, payload, _ := call(listener, "application/json", large)
+		var response JsonRpcResponse
+		if err := json.Unmarshal(payload, &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != frameworkerrors.BadRequest.ToJSONRPCCode() {
+			t.Errorf("Expected BadRequest error, got %s", payload)
+		}
+	})
+
+	t.Run("未启用附件", func(t *testing.T) {
+		listener, handler := newHandler(nil)
+		defer handler.Stop(context.Background())
+		_, payload, _ := call(listener, "application/json", params())
+		var response JsonRpcResponse
+		if err := json.Unmarshal(payload, &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error == nil || response.Error.Code != frameworkerrors.BadRequest.ToJSONRPCCode() {
+			t.Errorf("Expected BadRequest error, got %s", payload)
+		}
+	})
+}
+
+func TestJsonRpcSetEnabled(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewJsonRpcProtocolHandler(&JsonRpcConfig{
+		Listener: listener,
+		Path:     "/jsonrpc",
+	})
+	handler.RegisterMethod("ping", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "pong", nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start JSON-RPC handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	call := func() (int, JsonRpcResponse) {
+		t.Helper()
+		body, _ := json.Marshal(JsonRpcRequest{Jsonrpc: "2.0", Method: "ping", Id: 1})
+		resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/jsonrpc", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to send JSON-RPC request: %v", err)
+		}
+		defer resp.Body.Close()
+		var response JsonRpcResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response
+	}
+
+	handler.SetEnabled(false)
+	status, response := call()
+	if status != http.StatusServiceUnavailable || response.Error == nil || response.Error.Code != frameworkerrors.ServiceUnavailable.ToJSONRPCCode() {
+		t.Errorf("Expected 503 with ServiceUnavailable while disabled, got %d %+v", status, response)
+	}
+
+	handler.SetEnabled(true)
+	if status, response := call(); status != http.StatusOK || response.Result != "pong" {
+		t.Errorf("Expected pong after enabling, got %d %+v", status, response)
+	}
+}
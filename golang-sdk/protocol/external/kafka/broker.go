@@ -0,0 +1,227 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/gogf/gf/v2/os/glog"
+)
+
+// broadcastGroupPrefix 广播订阅使用的消费者组前缀，每个订阅一个独立的消费者组
+const broadcastGroupPrefix = "broadcast-"
+
+// Broker 基于 Kafka 的 messaging.Broker，经 Dialer 使用应用选择的 Kafka 客户端库
+//
+// 订阅组对应消费者组；订阅组为空时每个订阅使用独立的消费者组，收到主题的全部消息，
+// 起始位点由客户端库的默认值决定（kafka-go 为最新位点）。记录按顺序处理，处理器返回 nil 后提交位点；
+// 处理失败时间隔 1 秒重试同一条记录直到成功或取消订阅，需要跳过失败的消息时为 messaging.Bus 配置死信队列
+type Broker struct {
+	brokers []string
+	dialer  Dialer
+	writer  Writer
+
+	mu     sync.Mutex
+	subs   map[*brokerSubscription]struct{}
+	closed bool
+}
+
+// brokerSubscription Kafka 订阅，独立的读取器和消费 goroutine
+type brokerSubscription struct {
+	broker  *Broker
+	reader  Reader
+	handler messaging.Handler
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewBroker 创建 Kafka 消息中间件
+func NewBroker(brokers []string, dialer Dialer) (*Broker, error) {
+	if dialer == nil {
+		return nil, fmt.Errorf("kafka dialer is not configured")
+	}
+	writer, err := dialer.NewWriter(brokers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka writer: %w", err)
+	}
+	return &Broker{
+		brokers: brokers,
+		dialer:  dialer,
+		writer:  writer,
+		subs:    make(map[*brokerSubscription]struct{}),
+	}, nil
+}
+
+// Name 返回中间件名称
+func (b *Broker) Name() string {
+	return "kafka"
+}
+
+// Publish 将消息写入同名主题，消息头写入记录头，消息 ID 作为记录键
+func (b *Broker) Publish(ctx context.Context, msg *messaging.Message) error {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		return messaging.ErrBrokerClosed
+	}
+
+	headers := make(map[string]string, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	record := &Record{
+		Topic:   msg.Topic,
+		Headers: headers,
+		Value:   msg.Payload,
+		Time:    msg.Timestamp,
+	}
+	if msg.ID != "" {
+		record.Key = []byte(msg.ID)
+	}
+	if err := b.writer.WriteMessages(ctx, record); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe 以消费者组 group 订阅主题，group 为空时广播
+func (b *Broker) Subscribe(topic, group string, handler messaging.Handler) (messaging.Subscription, error) {
+	if group == "" {
+		group = broadcastGroupPrefix + newIdempotencyKey()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, messaging.ErrBrokerClosed
+	}
+	reader, err := b.dialer.NewReader(b.brokers, topic, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka reader for %s: %w", topic, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &brokerSubscription{
+		broker:  b,
+		reader:  reader,
+		handler: handler,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	go sub.run(ctx)
+	return sub, nil
+}
+
+// Close 取消所有订阅并关闭写入器
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[*brokerSubscription]struct{})
+	b.mu.Unlock()
+
+	for sub := range subs {
+		sub.stop()
+	}
+	if err := b.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close kafka writer: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe 取消订阅，等待正在处理的记录完成后关闭读取器
+func (s *brokerSubscription) Unsubscribe() error {
+	s.broker.mu.Lock()
+	delete(s.broker.subs, s)
+	s.broker.mu.Unlock()
+	s.stop()
+	return nil
+}
+
+// stop 停止消费并关闭读取器
+func (s *brokerSubscription) stop() {
+	s.once.Do(func() {
+		s.cancel()
+		<-s.done
+		if err := s.reader.Close(); err != nil {
+			glog.Errorf(context.Background(), "Failed to close kafka reader: %v", err)
+		}
+	})
+}
+
+// run 循环读取记录直到取消订阅
+func (s *brokerSubscription) run(ctx context.Context) {
+	defer close(s.done)
+	for {
+		record, err := s.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			glog.Errorf(ctx, "Failed to fetch kafka record: %v", err)
+			if !sleep(ctx, retryInterval) {
+				return
+			}
+			continue
+		}
+
+		if !s.handle(ctx, record) {
+			return
+		}
+		if err := s.reader.CommitMessages(ctx, record); err != nil && ctx.Err() == nil {
+			glog.Errorf(ctx, "Failed to commit kafka offset %s/%d/%d: %v", record.Topic, record.Partition, record.Offset, err)
+		}
+	}
+}
+
+// handle 调用处理器直到成功，返回 false 表示订阅已取消且记录未处理完成，不提交位点
+func (s *brokerSubscription) handle(ctx context.Context, record *Record) bool {
+	for {
+		err := s.handler(ctx, recordMessage(record))
+		if err == nil {
+			return true
+		}
+		glog.Warningf(ctx, "Failed to handle kafka record %s/%d/%d, retrying: %v", record.Topic, record.Partition, record.Offset, err)
+		if !sleep(ctx, retryInterval) {
+			return false
+		}
+	}
+}
+
+// recordMessage 将记录转换为消息，由消息头还原消息 ID 和发布时间，各次处理使用独立的消息头副本
+func recordMessage(record *Record) *messaging.Message {
+	headers := make(map[string]string, len(record.Headers))
+	for k, v := range record.Headers {
+		headers[k] = v
+	}
+	msg := &messaging.Message{
+		ID:        headers[messaging.HeaderMessageID],
+		Topic:     record.Topic,
+		Headers:   headers,
+		Payload:   record.Value,
+		Timestamp: record.Time,
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, headers[messaging.HeaderTimestamp]); err == nil {
+		msg.Timestamp = ts
+	}
+	return msg
+}
+
+// sleep 等待 d，ctx 先结束时返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/messaging"
+)
+
+var _ messaging.Broker = (*Broker)(nil)
+
+func TestBrokerPublishSubscribe(t *testing.T) {
+	dialer := newFakeDialer()
+	broker, err := NewBroker([]string{"localhost:9092"}, dialer)
+	if err != nil {
+		t.Fatalf("NewBroker failed: %v", err)
+	}
+	defer broker.Close()
+
+	received := make(chan *messaging.Message, 1)
+	if _, err := broker.Subscribe("order.created", "billing", func(ctx context.Context, msg *messaging.Message) error {
+		received <- msg
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	published := &messaging.Message{
+		ID:      "msg-1",
+		Topic:   "order.created",
+		Headers: map[string]string{messaging.HeaderMessageID: "msg-1", "traceparent": "00-abc-def-01"},
+		Payload: []byte(`{"id":1}`),
+	}
+	if err := broker.Publish(context.Background(), published); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	written := dialer.writtenTo("order.created")
+	if len(written) != 1 || string(written[0].Key) != "msg-1" {
+		t.Fatalf("Unexpected written records: %+v", written)
+	}
+	dialer.send(written[0])
+
+	select {
+	case msg := <-received:
+		if msg.ID != "msg-1" || string(msg.Payload) != `{"id":1}` || msg.Headers["traceparent"] != "00-abc-def-01" {
+			t.Errorf("Unexpected message: %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscriber did not receive the message")
+	}
+	waitFor(t, func() bool { return dialer.committedCount() == 1 })
+}
+
+func TestBrokerRetriesFailedRecord(t *testing.T) {
+	dialer := newFakeDialer()
+	broker, _ := NewBroker(nil, dialer)
+	defer broker.Close()
+
+	var attempts atomic.Int32
+	broker.Subscribe("order.created", "billing", func(ctx context.Context, msg *messaging.Message) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	dialer.send(&Record{Topic: "order.created", Value: []byte("{}")})
+
+	// 处理失败时不提交位点，重试成功后提交
+	time.Sleep(100 * time.Millisecond)
+	if dialer.committedCount() != 0 {
+		t.Fatal("Failed record should not be committed")
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for dialer.committedCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Record was not committed after retry, attempts = %d", attempts.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	dialer := newFakeDialer()
+	broker, _ := NewBroker(nil, dialer)
+
+	sub, _ := broker.Subscribe("order.created", "", func(ctx context.Context, msg *messaging.Message) error { return nil })
+	if err := broker.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := broker.Publish(context.Background(), &messaging.Message{Topic: "order.created"}); err != messaging.ErrBrokerClosed {
+		t.Errorf("Publish after Close = %v, want ErrBrokerClosed", err)
+	}
+	if _, err := broker.Subscribe("order.created", "", nil); err != messaging.ErrBrokerClosed {
+		t.Errorf("Subscribe after Close = %v, want ErrBrokerClosed", err)
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe after Close = %v", err)
+	}
+
+	if _, err := NewBroker(nil, nil); err == nil {
+		t.Error("Expected error without dialer")
+	}
+}
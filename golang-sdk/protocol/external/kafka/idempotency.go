@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/dedup"
+)
+
+// IdempotencyStore 已处理幂等键的存储，与 WebSocket、MQTT 共用 dedup.Store
+type IdempotencyStore = dedup.Store
+
+// MemoryIdempotencyStore 进程内幂等键存储，幂等键在 ttl 后过期，不限制数量；需要限制内存时使用 dedup.Cache
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	keys      map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewMemoryIdempotencyStore 创建进程内幂等键存储
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		ttl:       ttl,
+		keys:      make(map[string]time.Time),
+		lastPurge: time.Now(),
+	}
+}
+
+// Seen 判断幂等键是否已处理且未过期
+func (s *MemoryIdempotencyStore) Seen(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.keys[key]
+	return ok && time.Now().Before(expiry)
+}
+
+// Mark 记录幂等键已处理，每经过一个 ttl 清理一次过期的幂等键
+func (s *MemoryIdempotencyStore) Mark(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.keys[key] = now.Add(s.ttl)
+	if now.Sub(s.lastPurge) < s.ttl {
+		return
+	}
+	for k, expiry := range s.keys {
+		if !now.Before(expiry) {
+			delete(s.keys, k)
+		}
+	}
+	s.lastPurge = now
+}
@@ -0,0 +1,427 @@
+package kafka
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/messaging"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/dedup"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/gogf/gf/v2/os/glog"
+)
+
+// 记录头
+const (
+	// HeaderIdempotencyKey 幂等键，相同幂等键的记录只处理一次；缺失时依次使用 message-id 头和 主题/分区/位点
+	HeaderIdempotencyKey = "idempotency-key"
+	// HeaderDeadLetterTopic 写入死信主题的记录的原主题
+	HeaderDeadLetterTopic = "dlq-original-topic"
+	// HeaderDeadLetterPartition 原分区
+	HeaderDeadLetterPartition = "dlq-original-partition"
+	// HeaderDeadLetterOffset 原位点
+	HeaderDeadLetterOffset = "dlq-original-offset"
+	// HeaderDeadLetterError 最后一次处理的错误
+	HeaderDeadLetterError = "dlq-error"
+	// HeaderDeadLetterAttempts 处理次数
+	HeaderDeadLetterAttempts = "dlq-attempts"
+)
+
+// DefaultDeadLetterSuffix 死信主题的默认后缀
+const DefaultDeadLetterSuffix = ".dlq"
+
+// retryInterval 读取记录或写入死信主题失败后的重试间隔
+const retryInterval = time.Second
+
+// Record Kafka 记录
+type Record struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Headers   map[string]string
+	Value     []byte
+	Time      time.Time
+}
+
+// Reader 消费者组内的主题读取器
+//
+// FetchMessage 阻塞直到读到记录或 ctx 结束，CommitMessages 提交已处理记录的位点；
+// 方法与 github.com/segmentio/kafka-go 的 Reader 对应
+type Reader interface {
+	FetchMessage(ctx context.Context) (*Record, error)
+	CommitMessages(ctx context.Context, records ...*Record) error
+	Close() error
+}
+
+// Writer 记录写入器
+type Writer interface {
+	WriteMessages(ctx context.Context, records ...*Record) error
+	Close() error
+}
+
+// Dialer 创建读取器和写入器，由具体的 Kafka 客户端库实现，框架不内置 Kafka 客户端
+type Dialer interface {
+	// NewReader 创建加入消费者组 groupId 的主题读取器
+	NewReader(brokers []string, topic, groupId string) (Reader, error)
+	// NewWriter 创建写入器，用于死信主题和 Publish
+	NewWriter(brokers []string) (Writer, error)
+}
+
+// KafkaConfig Kafka 配置
+type KafkaConfig struct {
+	Brokers []string
+	GroupId string
+	// Routes 主题到业务方法的映射，如 "order.created" -> "billing.charge"，只消费列出的主题
+	Routes map[string]string
+	// Dialer Kafka 客户端
+	Dialer Dialer
+	// Dispatcher 本地业务方法分发器，记录值为 JSON 编码的请求参数
+	Dispatcher adapter.Dispatcher
+	// RetryPolicy 处理失败的重试策略，为 nil 时使用 resilience.DefaultRetryPolicy
+	RetryPolicy *resilience.RetryPolicy
+	// DeadLetterSuffix 死信主题后缀，为空时使用 DefaultDeadLetterSuffix，死信主题为 原主题+后缀
+	DeadLetterSuffix string
+	// IdempotencyStore 已处理幂等键的存储，为 nil 时使用默认容量、保留 24 小时的 dedup.Cache；
+	// 多实例部署时分区会在实例间迁移，需要共享存储才能跨实例去重
+	IdempotencyStore IdempotencyStore
+}
+
+// KafkaProtocolHandler Kafka 协议处理器
+//
+// 按 Routes 为每个主题创建消费者组读取器，记录经协议适配器转换为内部请求后调用 Dispatcher。
+// 同一主题的记录按顺序处理；处理成功、重试耗尽后写入死信主题或幂等键已处理时提交位点
+type KafkaProtocolHandler struct {
+	config          *KafkaConfig
+	protocolAdapter *adapter.DefaultProtocolAdapter
+	retryPolicy     *resilience.RetryPolicy
+	idempotency     IdempotencyStore
+
+	mu      sync.Mutex
+	writer  Writer
+	readers []Reader
+	cancel  context.CancelFunc
+	done    sync.WaitGroup
+}
+
+// NewKafkaProtocolHandler 创建 Kafka 协议处理器
+func NewKafkaProtocolHandler(config *KafkaConfig) *KafkaProtocolHandler {
+	h := &KafkaProtocolHandler{
+		config:          config,
+		protocolAdapter: adapter.NewDefaultProtocolAdapter(),
+		retryPolicy:     config.RetryPolicy,
+		idempotency:     config.IdempotencyStore,
+	}
+	if h.retryPolicy == nil {
+		h.retryPolicy = resilience.DefaultRetryPolicy()
+	}
+	if h.idempotency == nil {
+		h.idempotency = dedup.NewCache(dedup.DefaultCapacity, dedup.DefaultTTL)
+	}
+	return h
+}
+
+// Start 创建写入器和各主题的读取器并开始消费
+func (h *KafkaProtocolHandler) Start() error {
+	if h.config.Dialer == nil {
+		return fmt.Errorf("kafka dialer is not configured")
+	}
+	if len(h.config.Routes) > 0 && h.config.Dispatcher == nil {
+		return fmt.Errorf("kafka dispatcher is not configured")
+	}
+	routes := make(map[string][2]string, len(h.config.Routes))
+	for topic, target := range h.config.Routes {
+		service, method, ok := strings.Cut(target, ".")
+		if !ok || service == "" || method == "" {
+			return fmt.Errorf("invalid kafka route %s -> %q, expected service.method", topic, target)
+		}
+		routes[topic] = [2]string{service, method}
+	}
+
+	writer, err := h.config.Dialer.NewWriter(h.config.Brokers)
+	if err != nil {
+		return fmt.Errorf("failed to create kafka writer: %w", err)
+	}
+
+	var readers []Reader
+	for topic := range routes {
+		reader, err := h.config.Dialer.NewReader(h.config.Brokers, topic, h.config.GroupId)
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			writer.Close()
+			return fmt.Errorf("failed to create kafka reader for %s: %w", topic, err)
+		}
+		readers = append(readers, reader)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.writer = writer
+	h.readers = readers
+	h.cancel = cancel
+	h.mu.Unlock()
+
+	for _, reader := range readers {
+		h.done.Add(1)
+		go h.consume(ctx, reader, routes)
+	}
+	glog.Infof(ctx, "Kafka consumer group %s started for %d topics", h.config.GroupId, len(readers))
+	return nil
+}
+
+// Stop 停止消费，等待正在处理的记录完成后关闭读取器和写入器
+func (h *KafkaProtocolHandler) Stop(ctx context.Context) error {
+	h.mu.Lock()
+	cancel := h.cancel
+	h.cancel = nil
+	h.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		h.done.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	h.mu.Lock()
+	readers, writer := h.readers, h.writer
+	h.readers, h.writer = nil, nil
+	h.mu.Unlock()
+
+	for _, reader := range readers {
+		if err := reader.Close(); err != nil {
+			glog.Errorf(ctx, "Failed to close kafka reader: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close kafka writer: %w", err)
+	}
+	glog.Info(ctx, "Kafka consumer stopped")
+	return nil
+}
+
+// Publish 将 value 编码为 JSON 写入 topic，写入幂等键并传播 ctx 中的追踪上下文；value 为 []byte 时原样写入
+func (h *KafkaProtocolHandler) Publish(ctx context.Context, topic string, key []byte, value interface{}) error {
+	h.mu.Lock()
+	writer := h.writer
+	h.mu.Unlock()
+	if writer == nil {
+		return fmt.Errorf("kafka handler is not started")
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return fmt.Errorf("failed to serialize kafka record for %s: %w", topic, err)
+		}
+	}
+
+	ctx, span := adapter.StartProducerSpan(ctx, "kafka", topic)
+	record := &Record{
+		Topic: topic,
+		Key:   key,
+		Headers: map[string]string{
+			HeaderIdempotencyKey:        newIdempotencyKey(),
+			messaging.HeaderContentType: "json",
+		},
+		Value: data,
+		Time:  time.Now(),
+	}
+	adapter.InjectTraceContext(ctx, record.Headers)
+
+	err := writer.WriteMessages(ctx, record)
+	adapter.EndSpan(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// consume 循环读取主题记录直到停止
+func (h *KafkaProtocolHandler) consume(ctx context.Context, reader Reader, routes map[string][2]string) {
+	defer h.done.Done()
+	for {
+		record, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			glog.Errorf(ctx, "Failed to fetch kafka record: %v", err)
+			select {
+			case <-time.After(retryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if !h.handleRecord(ctx, record, routes[record.Topic]) {
+			return
+		}
+		if err := reader.CommitMessages(ctx, record); err != nil && ctx.Err() == nil {
+			glog.Errorf(ctx, "Failed to commit kafka offset %s/%d/%d: %v", record.Topic, record.Partition, record.Offset, err)
+		}
+	}
+}
+
+// handleRecord 处理一条记录，返回 false 表示处理器停止且记录未处理完成，不提交位点
+func (h *KafkaProtocolHandler) handleRecord(ctx context.Context, record *Record, route [2]string) bool {
+	key := idempotencyKey(record)
+	if h.idempotency.Seen(key) {
+		return true
+	}
+
+	recordCtx := adapter.ExtractTraceContext(ctx, record.Headers)
+	recordCtx, span := adapter.StartConsumerSpan(recordCtx, "kafka", record.Topic, h.config.GroupId)
+
+	attempts, err := h.dispatchWithRetry(recordCtx, record, route, key)
+	adapter.EndSpan(span, err)
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		if !h.deadLetter(ctx, record, attempts, err) {
+			return false
+		}
+	}
+	h.idempotency.Mark(key)
+	return true
+}
+
+// dispatchWithRetry 按重试策略调用业务方法，返回处理次数和最后一次的错误
+func (h *KafkaProtocolHandler) dispatchWithRetry(ctx context.Context, record *Record, route [2]string, key string) (int, error) {
+	attempt := 0
+	for {
+		attempt++
+		err := h.dispatch(ctx, record, route, key)
+		if err == nil {
+			return attempt, nil
+		}
+		if attempt >= h.retryPolicy.MaxAttempts || !h.retryable(err) {
+			return attempt, err
+		}
+		select {
+		case <-time.After(h.retryPolicy.CalculateDelay(attempt - 1)):
+		case <-ctx.Done():
+			return attempt, err
+		}
+	}
+}
+
+// retryable 判断错误是否按重试策略重试，非框架错误不重试
+func (h *KafkaProtocolHandler) retryable(err error) bool {
+	fe, ok := frameworkerrors.FromError(err)
+	return ok && h.retryPolicy.IsRetryable(fe.Code)
+}
+
+// dispatch 经协议适配器将记录转换为内部请求并调用业务方法
+func (h *KafkaProtocolHandler) dispatch(ctx context.Context, record *Record, route [2]string, key string) error {
+	headers := make(map[string]string, len(record.Headers)+2)
+	for k, v := range record.Headers {
+		headers[k] = v
+	}
+	headers["X-Service-Name"] = route[0]
+	headers["X-Method-Name"] = route[1]
+
+	internal, err := h.protocolAdapter.TransformRequest(ctx, &adapter.ExternalRequest{
+		Protocol: adapter.ProtocolKafka,
+		Headers:  headers,
+		Body:     record.Value,
+		Metadata: &adapter.RequestMetadata{
+			RequestId: key,
+			Timestamp: record.Time.UnixMilli(),
+			Extra: map[string]string{
+				"kafka_topic":     record.Topic,
+				"kafka_partition": strconv.Itoa(int(record.Partition)),
+				"kafka_offset":    strconv.FormatInt(record.Offset, 10),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = h.config.Dispatcher(ctx, internal)
+	return err
+}
+
+// deadLetter 将处理失败的记录写入死信主题，写入失败时每秒重试直到成功或停止
+func (h *KafkaProtocolHandler) deadLetter(ctx context.Context, record *Record, attempts int, cause error) bool {
+	suffix := h.config.DeadLetterSuffix
+	if suffix == "" {
+		suffix = DefaultDeadLetterSuffix
+	}
+	headers := make(map[string]string, len(record.Headers)+5)
+	for k, v := range record.Headers {
+		headers[k] = v
+	}
+	headers[HeaderIdempotencyKey] = idempotencyKey(record)
+	headers[HeaderDeadLetterTopic] = record.Topic
+	headers[HeaderDeadLetterPartition] = strconv.Itoa(int(record.Partition))
+	headers[HeaderDeadLetterOffset] = strconv.FormatInt(record.Offset, 10)
+	headers[HeaderDeadLetterError] = cause.Error()
+	headers[HeaderDeadLetterAttempts] = strconv.Itoa(attempts)
+	dead := &Record{
+		Topic:   record.Topic + suffix,
+		Key:     record.Key,
+		Headers: headers,
+		Value:   record.Value,
+		Time:    time.Now(),
+	}
+
+	h.mu.Lock()
+	writer := h.writer
+	h.mu.Unlock()
+	if writer == nil {
+		return false
+	}
+	for {
+		err := writer.WriteMessages(ctx, dead)
+		if err == nil {
+			glog.Warningf(ctx, "Kafka record %s/%d/%d moved to %s after %d attempts: %v",
+				record.Topic, record.Partition, record.Offset, dead.Topic, attempts, cause)
+			return true
+		}
+		glog.Errorf(ctx, "Failed to write kafka record to %s: %v", dead.Topic, err)
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// idempotencyKey 返回记录的幂等键
+func idempotencyKey(record *Record) string {
+	if key := record.Headers[HeaderIdempotencyKey]; key != "" {
+		return key
+	}
+	if id := record.Headers[messaging.HeaderMessageID]; id != "" {
+		return id
+	}
+	return fmt.Sprintf("%s/%d/%d", record.Topic, record.Partition, record.Offset)
+}
+
+// newIdempotencyKey 生成 128 位随机幂等键
+func newIdempotencyKey() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
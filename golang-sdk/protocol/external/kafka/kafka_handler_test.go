@@ -0,0 +1,359 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// fakeDialer 进程内的 Kafka 客户端，每个主题一个读取器，写入的记录投递给同名主题的读取器
+type fakeDialer struct {
+	mu        sync.Mutex
+	readers   map[string]*fakeReader
+	written   []*Record
+	committed []*Record
+}
+
+func newFakeDialer() *fakeDialer {
+	return &fakeDialer{readers: make(map[string]*fakeReader)}
+}
+
+func (d *fakeDialer) NewReader(brokers []string, topic, groupId string) (Reader, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reader := &fakeReader{dialer: d, records: make(chan *Record, 16)}
+	d.readers[topic] = reader
+	return reader, nil
+}
+
+func (d *fakeDialer) NewWriter(brokers []string) (Writer, error) {
+	return &fakeWriter{dialer: d}, nil
+}
+
+// send 向主题投递记录
+func (d *fakeDialer) send(record *Record) {
+	d.mu.Lock()
+	reader := d.readers[record.Topic]
+	d.mu.Unlock()
+	reader.records <- record
+}
+
+func (d *fakeDialer) writtenTo(topic string) []*Record {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var records []*Record
+	for _, r := range d.written {
+		if r.Topic == topic {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func (d *fakeDialer) committedCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.committed)
+}
+
+type fakeReader struct {
+	dialer  *fakeDialer
+	records chan *Record
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (*Record, error) {
+	select {
+	case record := <-r.records:
+		return record, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, records ...*Record) error {
+	r.dialer.mu.Lock()
+	defer r.dialer.mu.Unlock()
+	r.dialer.committed = append(r.dialer.committed, records...)
+	return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+type fakeWriter struct {
+	dialer *fakeDialer
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, records ...*Record) error {
+	w.dialer.mu.Lock()
+	defer w.dialer.mu.Unlock()
+	w.dialer.written = append(w.dialer.written, records...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+// waitFor 等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// startHandler 启动路由 orders -> billing.charge 的处理器
+func startHandler(t *testing.T, dispatcher adapter.Dispatcher) (*KafkaProtocolHandler, *fakeDialer) {
+	dialer := newFakeDialer()
+	handler := NewKafkaProtocolHandler(&KafkaConfig{
+		GroupId:     "billing",
+		Routes:      map[string]string{"orders": "billing.charge"},
+		Dialer:      dialer,
+		Dispatcher:  dispatcher,
+		RetryPolicy: resilience.NewRetryPolicy(3, time.Millisecond, 5*time.Millisecond, 2.0),
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { handler.Stop(context.Background()) })
+	return handler, dialer
+}
+
+func TestKafkaHandlerDispatch(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*adapter.InternalRequest
+	_, dialer := startHandler(t, func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+		return nil, nil
+	})
+
+	dialer.send(&Record{Topic: "orders", Partition: 1, Offset: 7, Value: []byte(`{"orderId":"o-1"}`)})
+	waitFor(t, func() bool { return dialer.committedCount() == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	request := requests[0]
+	if request.Service != "billing" || request.Method != "charge" {
+		t.Errorf("routed to %s.%s, want billing.charge", request.Service, request.Method)
+	}
+	if string(request.Payload) != `{"orderId":"o-1"}` {
+		t.Errorf("payload = %s", request.Payload)
+	}
+	if request.Metadata["kafka_offset"] != "7" || request.Metadata["request_id"] != "orders/1/7" {
+		t.Errorf("unexpected metadata: %v", request.Metadata)
+	}
+}
+
+func TestKafkaHandlerRetryAndDeadLetter(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		wantAttempts int
+		wantDLQ      bool
+	}{
+		{
+			name:         "可重试错误重试后成功",
+			failures:     2,
+			err:          frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "unavailable"),
+			wantAttempts: 3,
+			wantDLQ:      false,
+		},
+		{
+			name:         "重试耗尽写入死信主题",
+			failures:     5,
+			err:          frameworkerrors.NewFrameworkError(frameworkerrors.Timeout, "timeout"),
+			wantAttempts: 3,
+			wantDLQ:      true,
+		},
+		{
+			name:         "不可重试错误直接写入死信主题",
+			failures:     5,
+			err:          frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "invalid order"),
+			wantAttempts: 1,
+			wantDLQ:      true,
+		},
+		{
+			name:         "非框架错误不重试",
+			failures:     5,
+			err:          errors.New("boom"),
+			wantAttempts: 1,
+			wantDLQ:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			attempts := 0
+			_, dialer := startHandler(t, func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts++
+				if attempts <= tt.failures {
+					return nil, tt.err
+				}
+				return nil, nil
+			})
+
+			dialer.send(&Record{
+				Topic:   "orders",
+				Key:     []byte("o-1"),
+				Headers: map[string]string{HeaderIdempotencyKey: "k-1"},
+				Value:   []byte(`{}`),
+			})
+			waitFor(t, func() bool { return dialer.committedCount() == 1 })
+
+			mu.Lock()
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			mu.Unlock()
+
+			dead := dialer.writtenTo("orders" + DefaultDeadLetterSuffix)
+			if (len(dead) == 1) != tt.wantDLQ {
+				t.Fatalf("dead letter records = %d, wantDLQ %v", len(dead), tt.wantDLQ)
+			}
+			if tt.wantDLQ {
+				headers := dead[0].Headers
+				if headers[HeaderDeadLetterTopic] != "orders" || headers[HeaderIdempotencyKey] != "k-1" {
+					t.Errorf("unexpected dead letter headers: %v", headers)
+				}
+				if headers[HeaderDeadLetterError] != tt.err.Error() {
+					t.Errorf("dlq-error = %q, want %q", headers[HeaderDeadLetterError], tt.err.Error())
+				}
+				if string(dead[0].Key) != "o-1" {
+					t.Errorf("dead letter key = %s, want o-1", dead[0].Key)
+				}
+			}
+		})
+	}
+}
+
+func TestKafkaHandlerIdempotency(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	_, dialer := startHandler(t, func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil, nil
+	})
+
+	// 相同幂等键的记录只处理一次，但都提交位点
+	for offset := int64(0); offset < 3; offset++ {
+		dialer.send(&Record{
+			Topic:   "orders",
+			Offset:  offset,
+			Headers: map[string]string{HeaderIdempotencyKey: "k-1"},
+			Value:   []byte(`{}`),
+		})
+	}
+	waitFor(t, func() bool { return dialer.committedCount() == 3 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("dispatcher called %d times, want 1", calls)
+	}
+}
+
+func TestKafkaHandlerPublish(t *testing.T) {
+	handler, dialer := startHandler(t, func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+		return nil, nil
+	})
+
+	ctx := adapter.ExtractTraceContext(context.Background(), map[string]string{
+		adapter.HeaderTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	if err := handler.Publish(ctx, "invoices", []byte("o-1"), map[string]string{"orderId": "o-1"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	records := dialer.writtenTo("invoices")
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Headers[HeaderIdempotencyKey] == "" {
+		t.Error("idempotency key should be set")
+	}
+	if record.Headers[adapter.HeaderTraceParent] == "" {
+		t.Error("traceparent should be propagated")
+	}
+	var body map[string]string
+	if err := json.Unmarshal(record.Value, &body); err != nil || body["orderId"] != "o-1" {
+		t.Errorf("unexpected value: %s", record.Value)
+	}
+}
+
+func TestKafkaHandlerInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *KafkaConfig
+	}{
+		{
+			name:   "缺少客户端",
+			config: &KafkaConfig{Routes: map[string]string{"orders": "billing.charge"}},
+		},
+		{
+			name: "缺少分发器",
+			config: &KafkaConfig{
+				Routes: map[string]string{"orders": "billing.charge"},
+				Dialer: newFakeDialer(),
+			},
+		},
+		{
+			name: "路由缺少方法名",
+			config: &KafkaConfig{
+				Routes:     map[string]string{"orders": "billing"},
+				Dialer:     newFakeDialer(),
+				Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) { return nil, nil },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewKafkaProtocolHandler(tt.config).Start(); err == nil {
+				t.Error("expected Start to fail")
+			}
+		})
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore(20 * time.Millisecond)
+	if store.Seen("k-1") {
+		t.Error("unmarked key should not be seen")
+	}
+	store.Mark("k-1")
+	if !store.Seen("k-1") {
+		t.Error("marked key should be seen")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if store.Seen("k-1") {
+		t.Error("expired key should not be seen")
+	}
+
+	// 过期的幂等键在下一次 Mark 时清理
+	store.Mark("k-2")
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.keys["k-1"]; ok {
+		t.Error("expired key should be purged")
+	}
+}
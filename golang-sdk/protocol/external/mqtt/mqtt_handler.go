@@ -2,9 +2,12 @@ package mqtt
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/dedup"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/gogf/gf/v2/os/glog"
 )
@@ -13,6 +16,7 @@ import (
 type MqttProtocolHandler struct {
 	client mqtt.Client
 	config *MqttConfig
+	limits *rateLimits
 }
 
 // MqttConfig MQTT 配置
@@ -23,12 +27,20 @@ type MqttConfig struct {
 	Username string
 	Password string
 	Topics   []string
+	// Dedup 已处理幂等键的存储，不为 nil 时 JSON 负载带 idempotencyKey 字段的消息只处理一次
+	Dedup dedup.Store
+	// RateLimits 按主题和设备的限流规则，消息须通过所有匹配的规则
+	RateLimits []RateLimitRule
+	// OnDisconnect 规则以 RateLimitDisconnect 限流时调用，device 为设备标识（不按设备限流时为空）；
+	// 处理器作为订阅方无法断开其他客户端，可在此调用 Broker 的管理接口断开设备
+	OnDisconnect func(topic, device string)
 }
 
 // NewMqttProtocolHandler 创建 MQTT 协议处理器
 func NewMqttProtocolHandler(config *MqttConfig) *MqttProtocolHandler {
 	return &MqttProtocolHandler{
 		config: config,
+		limits: newRateLimits(config.RateLimits, config.OnDisconnect),
 	}
 }
 
@@ -98,6 +110,11 @@ func (h *MqttProtocolHandler) handleMessage(client mqtt.Client, msg mqtt.Message
 	
 	glog.Infof(ctx, "Received MQTT message on topic %s: %s", msg.Topic(), string(msg.Payload()))
 	
+	if h.limits != nil && !h.limits.allow(ctx, msg.Topic()) {
+		glog.Debugf(ctx, "Rate limited MQTT message on topic %s", msg.Topic())
+		return
+	}
+	
 	// 创建消息对象
 	message := &MqttMessage{
 		Topic:   msg.Topic(),
@@ -106,11 +123,34 @@ func (h *MqttProtocolHandler) handleMessage(client mqtt.Client, msg mqtt.Message
 		Retained: msg.Retained(),
 	}
 	
-	// TODO: 调用协议适配器转换请求
-	// TODO: 调用消息路由器路由到目标服务
-	// TODO: 处理响应（如果需要）
-	
-	_ = message
+	// QoS 1 的消息可能重复投递，带幂等键的消息只处理一次
+	duplicate, err := dedup.Do(h.config.Dedup, idempotencyKey(msg.Payload()), func() error {
+		_, span := adapter.StartServerSpan(ctx, adapter.ProtocolMQTT, "", msg.Topic())
+		defer span.End()
+		
+		// TODO: 调用协议适配器转换请求
+		// TODO: 调用消息路由器路由到目标服务
+		// TODO: 处理响应（如果需要）
+		
+		_ = message
+		return nil
+	})
+	if err != nil {
+		glog.Errorf(ctx, "Failed to handle MQTT message on topic %s: %v", msg.Topic(), err)
+	} else if duplicate {
+		glog.Debugf(ctx, "Skipped duplicate MQTT message on topic %s", msg.Topic())
+	}
+}
+
+// idempotencyKey 返回 JSON 负载中 idempotencyKey 字段的值，负载不是 JSON 对象时返回空字符串
+func idempotencyKey(payload []byte) string {
+	var body struct {
+		Key string `json:"idempotencyKey"`
+	}
+	if json.Unmarshal(payload, &body) != nil {
+		return ""
+	}
+	return body.Key
 }
 
 // Publish 发布 MQTT 消息
@@ -121,3 +121,24 @@ func TestMqttPublishWithoutConnection(t *testing.T) {
 		t.Error("Expected error when publishing without connection")
 	}
 }
+
+// TestMqttIdempotencyKey 测试从 JSON 负载读取幂等键
+func TestMqttIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"带幂等键", `{"idempotencyKey":"k-1","value":1}`, "k-1"},
+		{"没有幂等键", `{"value":1}`, ""},
+		{"非 JSON 负载", `temperature=21`, ""},
+		{"JSON 数组", `[1,2]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotencyKey([]byte(tt.payload)); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
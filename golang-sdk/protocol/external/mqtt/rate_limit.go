@@ -0,0 +1,259 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RateLimitAction 消息超过限额时的处理方式
+type RateLimitAction string
+
+const (
+	// RateLimitDrop 丢弃超过限额的消息
+	RateLimitDrop RateLimitAction = "drop"
+	// RateLimitQueue 等待令牌后再处理，最长等待 MaxWait，超时后丢弃；等待期间阻塞后续消息的投递
+	RateLimitQueue RateLimitAction = "queue"
+	// RateLimitDisconnect 丢弃消息，并在 BlockFor 内丢弃该设备（或主题）的所有消息，同时调用 MqttConfig.OnDisconnect
+	RateLimitDisconnect RateLimitAction = "disconnect"
+)
+
+// 限流规则的默认配置
+const (
+	DefaultRateLimitMaxWait  = time.Second
+	DefaultRateLimitBlockFor = time.Minute
+	// rateLimitIdleTimeout 设备的令牌桶超过该时间未使用时回收
+	rateLimitIdleTimeout = 10 * time.Minute
+)
+
+// RateLimitRule 按主题过滤器的令牌桶限流规则
+type RateLimitRule struct {
+	// Topic MQTT 主题过滤器，支持 + 和 # 通配符，如 devices/+/telemetry
+	Topic string
+	// PerDevice 为 true 时按设备分别限流，设备标识为 Topic 中第一个 + 匹配的主题层级；否则匹配的消息共用一个令牌桶
+	PerDevice bool
+	// Rate 每秒补充的令牌数
+	Rate float64
+	// Burst 最多积累的令牌数，为 0 时为 1
+	Burst int
+	// Action 超过限额时的处理方式，为空时为 RateLimitDrop
+	Action RateLimitAction
+	// MaxWait RateLimitQueue 的最长等待时间，为 0 时使用 DefaultRateLimitMaxWait
+	MaxWait time.Duration
+	// BlockFor RateLimitDisconnect 丢弃后续消息的时长，为 0 时使用 DefaultRateLimitBlockFor
+	BlockFor time.Duration
+}
+
+// Validate 校验限流规则
+func (r *RateLimitRule) Validate() error {
+	if r.Topic == "" {
+		return fmt.Errorf("rate limit topic is required")
+	}
+	if r.PerDevice && deviceLevel(r.Topic) < 0 {
+		return fmt.Errorf("rate limit topic %s has no + level to identify devices", r.Topic)
+	}
+	if r.Rate <= 0 {
+		return fmt.Errorf("rate limit for %s requires a positive rate", r.Topic)
+	}
+	switch r.Action {
+	case "", RateLimitDrop, RateLimitQueue, RateLimitDisconnect:
+	default:
+		return fmt.Errorf("rate limit for %s has unknown action %q: use drop, queue or disconnect", r.Topic, r.Action)
+	}
+	return nil
+}
+
+var (
+	// 用于防止重复注册的锁
+	mqttMetricsOnce sync.Once
+	// 超过限额的消息数
+	rateLimitedTotal *prometheus.CounterVec
+)
+
+// recordRateLimited 记录一条超过限额的消息
+func recordRateLimited(topic string, action RateLimitAction) {
+	mqttMetricsOnce.Do(func() {
+		rateLimitedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_mqtt_rate_limited_total",
+				Help: "Total number of MQTT messages over a rate limit by rule topic and action (drop, queue, disconnect)",
+			},
+			[]string{"topic", "action"},
+		)
+	})
+	rateLimitedTotal.WithLabelValues(topic, string(action)).Inc()
+}
+
+// rateLimits 按规则顺序检查消息，消息须通过所有匹配的规则
+type rateLimits struct {
+	rules        []*rateLimit
+	onDisconnect func(topic, device string)
+}
+
+// newRateLimits 创建限流规则，没有规则时返回 nil
+func newRateLimits(rules []RateLimitRule, onDisconnect func(topic, device string)) *rateLimits {
+	if len(rules) == 0 {
+		return nil
+	}
+	l := &rateLimits{onDisconnect: onDisconnect}
+	for _, rule := range rules {
+		if rule.Action == "" {
+			rule.Action = RateLimitDrop
+		}
+		if rule.MaxWait <= 0 {
+			rule.MaxWait = DefaultRateLimitMaxWait
+		}
+		if rule.BlockFor <= 0 {
+			rule.BlockFor = DefaultRateLimitBlockFor
+		}
+		l.rules = append(l.rules, &rateLimit{
+			rule:    rule,
+			level:   deviceLevel(rule.Topic),
+			buckets: make(map[string]*bucket),
+			blocked: make(map[string]time.Time),
+		})
+	}
+	return l
+}
+
+// allow 判断主题上的消息是否处理，RateLimitQueue 时可能阻塞到取得令牌或 ctx 结束
+func (l *rateLimits) allow(ctx context.Context, topic string) bool {
+	for _, limit := range l.rules {
+		if !topicMatches(limit.rule.Topic, topic) {
+			continue
+		}
+		device := ""
+		if limit.rule.PerDevice {
+			device = strings.Split(topic, "/")[limit.level]
+		}
+		if !limit.allow(ctx, device, l.onDisconnect) {
+			return false
+		}
+	}
+	return true
+}
+
+// rateLimit 一条规则的令牌桶，按设备限流时每个设备一个
+type rateLimit struct {
+	rule  RateLimitRule
+	level int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	blocked   map[string]time.Time
+	lastSweep time.Time
+}
+
+// bucket 一个设备（或整个主题）的令牌桶
+type bucket struct {
+	limiter  *resilience.RateLimiter
+	lastSeen time.Time
+}
+
+// allow 从设备的令牌桶取得令牌，取不到时按规则的处理方式处理
+func (r *rateLimit) allow(ctx context.Context, device string, onDisconnect func(topic, device string)) bool {
+	now := time.Now()
+	r.mu.Lock()
+	if until, ok := r.blocked[device]; ok {
+		if now.Before(until) {
+			r.mu.Unlock()
+			recordRateLimited(r.rule.Topic, RateLimitDisconnect)
+			return false
+		}
+		delete(r.blocked, device)
+	}
+	b := r.bucketLocked(device, now)
+	r.mu.Unlock()
+
+	if b.limiter.Allow(ctx) {
+		return true
+	}
+	recordRateLimited(r.rule.Topic, r.rule.Action)
+
+	switch r.rule.Action {
+	case RateLimitQueue:
+		return r.wait(ctx, b.limiter)
+	case RateLimitDisconnect:
+		r.mu.Lock()
+		r.blocked[device] = now.Add(r.rule.BlockFor)
+		r.mu.Unlock()
+		if onDisconnect != nil {
+			onDisconnect(r.rule.Topic, device)
+		}
+	}
+	return false
+}
+
+// wait 按令牌补充间隔重试，直到取得令牌、超过 MaxWait 或 ctx 结束
+func (r *rateLimit) wait(ctx context.Context, limiter *resilience.RateLimiter) bool {
+	interval := time.Duration(float64(time.Second) / r.rule.Rate)
+	timer := time.NewTimer(r.rule.MaxWait)
+	defer timer.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if limiter.Allow(ctx) {
+				return true
+			}
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// bucketLocked 返回设备的令牌桶，并回收长时间未使用的令牌桶（需要持有锁）
+func (r *rateLimit) bucketLocked(device string, now time.Time) *bucket {
+	if now.Sub(r.lastSweep) >= time.Minute {
+		r.lastSweep = now
+		for key, b := range r.buckets {
+			if now.Sub(b.lastSeen) >= rateLimitIdleTimeout {
+				delete(r.buckets, key)
+			}
+		}
+	}
+	b, ok := r.buckets[device]
+	if !ok {
+		b = &bucket{limiter: resilience.NewRateLimiter("mqtt "+r.rule.Topic, r.rule.Rate, r.rule.Burst, 0)}
+		r.buckets[device] = b
+	}
+	b.lastSeen = now
+	return b
+}
+
+// deviceLevel 返回主题过滤器中第一个 + 所在的层级，没有时返回 -1
+func deviceLevel(filter string) int {
+	for i, level := range strings.Split(filter, "/") {
+		if level == "+" {
+			return i
+		}
+	}
+	return -1
+}
+
+// topicMatches 判断主题是否匹配 MQTT 主题过滤器
+func topicMatches(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
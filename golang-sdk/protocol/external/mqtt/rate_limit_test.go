@@ -0,0 +1,119 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"devices/+/telemetry", "devices/d1/telemetry", true},
+		{"devices/+/telemetry", "devices/d1/status", false},
+		{"devices/+/telemetry", "devices/d1/telemetry/extra", false},
+		{"devices/#", "devices/d1/telemetry", true},
+		{"devices/#", "devices", true},
+		{"devices/d1", "devices/d2", false},
+	}
+	for _, tt := range tests {
+		if got := topicMatches(tt.filter, tt.topic); got != tt.want {
+			t.Errorf("topicMatches(%q, %q) = %v, want %v", tt.filter, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitRuleValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RateLimitRule
+		ok   bool
+	}{
+		{"按设备限流", RateLimitRule{Topic: "devices/+/telemetry", PerDevice: true, Rate: 1}, true},
+		{"缺少设备层级", RateLimitRule{Topic: "devices/telemetry", PerDevice: true, Rate: 1}, false},
+		{"速率为 0", RateLimitRule{Topic: "devices/#", Rate: 0}, false},
+		{"未知处理方式", RateLimitRule{Topic: "devices/#", Rate: 1, Action: "kick"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRateLimitsPerDevice(t *testing.T) {
+	limits := newRateLimits([]RateLimitRule{
+		{Topic: "devices/+/telemetry", PerDevice: true, Rate: 0.1, Burst: 2},
+	}, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if !limits.allow(ctx, "devices/d1/telemetry") {
+			t.Fatalf("message %d from d1 should be allowed", i)
+		}
+	}
+	if limits.allow(ctx, "devices/d1/telemetry") {
+		t.Error("Expected the third message from d1 to be dropped")
+	}
+	// 其他设备和不匹配的主题不受影响
+	if !limits.allow(ctx, "devices/d2/telemetry") {
+		t.Error("Expected d2 to have its own bucket")
+	}
+	if !limits.allow(ctx, "devices/d1/status") {
+		t.Error("Expected unmatched topic to be allowed")
+	}
+}
+
+func TestRateLimitsQueue(t *testing.T) {
+	limits := newRateLimits([]RateLimitRule{
+		{Topic: "sensors/#", Rate: 50, Burst: 1, Action: RateLimitQueue, MaxWait: time.Second},
+	}, nil)
+	ctx := context.Background()
+
+	if !limits.allow(ctx, "sensors/a") {
+		t.Fatal("first message should be allowed")
+	}
+	start := time.Now()
+	if !limits.allow(ctx, "sensors/b") {
+		t.Fatal("queued message should be allowed after waiting for a token")
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("Expected queued message to wait, waited %v", elapsed)
+	}
+
+	// 超过最长等待时间后丢弃
+	limits = newRateLimits([]RateLimitRule{
+		{Topic: "sensors/#", Rate: 0.1, Burst: 1, Action: RateLimitQueue, MaxWait: 20 * time.Millisecond},
+	}, nil)
+	limits.allow(ctx, "sensors/a")
+	if limits.allow(ctx, "sensors/a") {
+		t.Error("Expected message to be dropped after MaxWait")
+	}
+}
+
+func TestRateLimitsDisconnect(t *testing.T) {
+	var disconnected []string
+	limits := newRateLimits([]RateLimitRule{
+		{Topic: "devices/+/telemetry", PerDevice: true, Rate: 200, Burst: 1, Action: RateLimitDisconnect, BlockFor: time.Hour},
+	}, func(topic, device string) {
+		disconnected = append(disconnected, device)
+	})
+	ctx := context.Background()
+
+	limits.allow(ctx, "devices/d1/telemetry")
+	if limits.allow(ctx, "devices/d1/telemetry") {
+		t.Fatal("Expected the second message to exceed the limit")
+	}
+	// 令牌补充后设备仍在 BlockFor 内被丢弃
+	time.Sleep(10 * time.Millisecond)
+	if limits.allow(ctx, "devices/d1/telemetry") {
+		t.Error("Expected blocked device to be dropped")
+	}
+	if len(disconnected) != 1 || disconnected[0] != "d1" {
+		t.Errorf("OnDisconnect calls = %v, want [d1]", disconnected)
+	}
+}
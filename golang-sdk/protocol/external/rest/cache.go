@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy GET 请求调用的业务方法的缓存策略
+//
+// 匹配的响应带 Cache-Control 响应头，网关、CDN 和浏览器据此缓存；ETag 由响应体的哈希生成，
+// 客户端以 If-None-Match 重新验证时响应体未变则返回 304，不再传输响应体
+type CachePolicy struct {
+	// Method 业务方法名（<服务>.<方法>），<服务>.* 匹配服务的所有方法，* 匹配所有方法
+	Method string
+	// MaxAge 客户端缓存的时长，为 0 时每次使用前须以 ETag 重新验证（no-cache）
+	MaxAge time.Duration
+	// SharedMaxAge 网关和 CDN 等共享缓存的时长（s-maxage），为 0 时与 MaxAge 相同
+	SharedMaxAge time.Duration
+	// StaleWhileRevalidate 过期后仍可使用旧响应、同时在后台重新验证的时长
+	StaleWhileRevalidate time.Duration
+	// Private 响应只能由客户端缓存，不能由共享缓存缓存（如按用户返回的数据）
+	Private bool
+	// NoStore 响应不能缓存，也不生成 ETag
+	NoStore bool
+}
+
+// matches 判断策略是否适用于业务方法
+func (p *CachePolicy) matches(method string) bool {
+	return matchMethod(p.Method, method)
+}
+
+// matchMethod 判断业务方法是否匹配 <服务>.<方法>、<服务>.* 或 *
+func matchMethod(pattern, method string) bool {
+	if pattern == "*" || pattern == method {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, ".*")
+	return ok && strings.HasPrefix(method, prefix+".")
+}
+
+// cacheControl 返回 Cache-Control 响应头的值
+func (p *CachePolicy) cacheControl() string {
+	if p.NoStore {
+		return "no-store"
+	}
+	directives := []string{"public"}
+	if p.Private {
+		directives[0] = "private"
+	}
+	if p.MaxAge <= 0 {
+		directives = append(directives, "no-cache")
+	} else {
+		directives = append(directives, "max-age="+seconds(p.MaxAge))
+		if p.SharedMaxAge > 0 && !p.Private {
+			directives = append(directives, "s-maxage="+seconds(p.SharedMaxAge))
+		}
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(p.StaleWhileRevalidate))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// cachePolicy 返回适用于业务方法的第一个缓存策略，没有时返回 nil
+func (h *RestProtocolHandler) cachePolicy(method string) *CachePolicy {
+	for i := range h.config.CachePolicies {
+		if h.config.CachePolicies[i].matches(method) {
+			return &h.config.CachePolicies[i]
+		}
+	}
+	return nil
+}
+
+// entityTag 返回响应体的强 ETag
+func entityTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified 判断 If-None-Match 是否包含 etag，按弱比较忽略 W/ 前缀
+func notModified(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// seconds 将时长格式化为整秒数
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
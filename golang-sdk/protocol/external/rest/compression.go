@@ -0,0 +1,318 @@
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 内置的内容编码，br 需以 RegisterCodec 注册实现后才会使用
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingZstd    = "zstd"
+	EncodingBrotli  = "br"
+)
+
+// 压缩的默认参数
+const (
+	// DefaultCompressionMinSize 小于该字节数的响应不压缩
+	DefaultCompressionMinSize = 1024
+	// DefaultMaxDecodedSize 解压后请求体的默认上限，与 GoFrame 服务器默认的请求体上限相同
+	DefaultMaxDecodedSize = 8 << 20
+)
+
+// defaultEncodings 服务端默认的编码偏好顺序
+var defaultEncodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip, EncodingDeflate}
+
+// Codec 内容编码的实现
+type Codec struct {
+	// NewWriter 返回将压缩数据写入 w 的写入器，Close 时写入剩余数据；写入器实现 Flush() error 时流式结果也会压缩
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+	// NewReader 返回解压 r 的读取器
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		EncodingGzip: {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		EncodingDeflate: {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) },
+		},
+		EncodingZstd: {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				decoder, err := zstd.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				return decoder.IOReadCloser(), nil
+			},
+		},
+	}
+)
+
+// RegisterCodec 注册内容编码，同名的编码被替换；如以 github.com/andybalholm/brotli 注册 br：
+//
+//	rest.RegisterCodec(rest.EncodingBrotli, rest.Codec{
+//	    NewWriter: func(w io.Writer) (io.WriteCloser, error) { return brotli.NewWriter(w), nil },
+//	    NewReader: func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(brotli.NewReader(r)), nil },
+//	})
+func RegisterCodec(name string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[strings.ToLower(name)] = codec
+}
+
+// lookupCodec 返回已注册的内容编码
+func lookupCodec(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+// CompressionConfig 请求和响应压缩配置
+//
+// 响应按 Accept-Encoding 协商编码，客户端给出的 q 值相同时按 Encodings 的顺序选择；
+// 请求体按 Content-Encoding 解压，不支持的编码或无法解压时返回 BadRequest 错误
+type CompressionConfig struct {
+	// Encodings 服务端偏好顺序的响应编码，为空时为 zstd、br、gzip、deflate；未注册的编码被忽略
+	Encodings []string
+	// MinSize 小于该字节数的响应不压缩，为 0 时使用 DefaultCompressionMinSize
+	MinSize int
+	// MaxDecodedSize 解压后请求体的最大字节数，超过时返回 BadRequest 错误，为 0 时使用 DefaultMaxDecodedSize
+	MaxDecodedSize int64
+}
+
+// encodings 返回可用的响应编码
+func (c *CompressionConfig) encodings() []string {
+	configured := c.Encodings
+	if len(configured) == 0 {
+		configured = defaultEncodings
+	}
+	available := make([]string, 0, len(configured))
+	for _, name := range configured {
+		name = strings.ToLower(name)
+		if _, ok := lookupCodec(name); ok {
+			available = append(available, name)
+		}
+	}
+	return available
+}
+
+// minSize 返回压缩的大小阈值
+func (c *CompressionConfig) minSize() int {
+	if c.MinSize > 0 {
+		return c.MinSize
+	}
+	return DefaultCompressionMinSize
+}
+
+// maxDecodedSize 返回解压后请求体的上限
+func (c *CompressionConfig) maxDecodedSize() int64 {
+	if c.MaxDecodedSize > 0 {
+		return c.MaxDecodedSize
+	}
+	return DefaultMaxDecodedSize
+}
+
+// negotiateEncoding 按 Accept-Encoding 从 encodings 中选择 q 值最高的编码，q 值相同时取靠前的；
+// 没有可接受的编码时返回空字符串（不压缩）
+func negotiateEncoding(acceptEncoding string, encodings []string) string {
+	if strings.TrimSpace(acceptEncoding) == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		accepted[name] = q
+	}
+
+	selected, best := "", 0.0
+	for _, name := range encodings {
+		q, ok := accepted[name]
+		if !ok {
+			q = accepted["*"]
+		}
+		if q > best {
+			selected, best = name, q
+		}
+	}
+	return selected
+}
+
+// decodeRequestBody 按 Content-Encoding 解压请求体，之后的 GetBody 读取解压后的内容
+func (h *RestProtocolHandler) decodeRequestBody(r *ghttp.Request) error {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return nil
+	}
+	codec, ok := lookupCodec(encoding)
+	if !ok {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, fmt.Sprintf("unsupported content encoding: %s", encoding))
+	}
+
+	reader, err := codec.NewReader(r.Body)
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("invalid %s body", encoding))
+	}
+	defer reader.Close()
+	limit := h.config.Compression.maxDecodedSize()
+	body, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.BadRequest, fmt.Sprintf("invalid %s body", encoding))
+	}
+	if int64(len(body)) > limit {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, fmt.Sprintf("decoded request body exceeds %d bytes", limit))
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.Header.Del("Content-Encoding")
+	r.ContentLength = int64(len(body))
+	return nil
+}
+
+// compressResponse 按 Accept-Encoding 压缩缓冲的响应体，在处理请求结束后调用
+//
+// 响应体小于阈值、已设置 Content-Encoding（如流式结果已压缩或已发送）、状态为 204/304 或压缩后没有变小时不压缩；
+// 压缩后强 ETag 改为弱 ETag，因为压缩结果与未压缩的响应体逐字节不同
+func (h *RestProtocolHandler) compressResponse(r *ghttp.Request) {
+	header := r.Response.Header()
+	if header.Get("Content-Encoding") != "" {
+		return
+	}
+	header.Add("Vary", "Accept-Encoding")
+	status := r.Response.Status
+	size := r.Response.BufferLength()
+	if size == 0 || status == http.StatusNoContent || status == http.StatusNotModified {
+		return
+	}
+
+	config := h.config.Compression
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), config.encodings())
+	if encoding == "" {
+		return
+	}
+	if size < config.minSize() {
+		recordCompressionSkipped(encoding, skippedBelowMinSize)
+		return
+	}
+	codec, _ := lookupCodec(encoding)
+	var buf bytes.Buffer
+	writer, err := codec.NewWriter(&buf)
+	if err != nil {
+		return
+	}
+	if _, err := writer.Write(r.Response.Buffer()); err != nil {
+		return
+	}
+	if err := writer.Close(); err != nil {
+		return
+	}
+	if buf.Len() >= size {
+		recordCompressionSkipped(encoding, skippedNotSmaller)
+		return
+	}
+
+	recordCompression(encoding, size, buf.Len())
+	header.Set("Content-Encoding", encoding)
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+	r.Response.SetBuffer(buf.Bytes())
+}
+
+// streamWriter 压缩流式结果的写入器，每个元素写入后 Flush 使其立即发送
+type streamWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += n
+	return n, err
+}
+
+// streamEncoder 流式结果的压缩写入器，统计压缩前后的字节数
+type streamEncoder struct {
+	streamWriter
+	encoding string
+	original int
+	out      *countingWriter
+}
+
+// newStreamEncoder 在发送第一个元素前调用，设置 Content-Encoding 并返回写入响应的压缩写入器；
+// 未配置压缩、客户端不接受压缩或编码不支持 Flush 时返回 nil
+func (h *RestProtocolHandler) newStreamEncoder(r *ghttp.Request) *streamEncoder {
+	if h.config.Compression == nil {
+		return nil
+	}
+	header := r.Response.Header()
+	header.Add("Vary", "Accept-Encoding")
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), h.config.Compression.encodings())
+	if encoding == "" {
+		return nil
+	}
+	codec, _ := lookupCodec(encoding)
+	out := &countingWriter{w: r.Response.Writer}
+	writer, err := codec.NewWriter(out)
+	if err != nil {
+		return nil
+	}
+	flusher, ok := writer.(streamWriter)
+	if !ok {
+		return nil
+	}
+	header.Set("Content-Encoding", encoding)
+	return &streamEncoder{streamWriter: flusher, encoding: encoding, out: out}
+}
+
+// Write 压缩写入，记录压缩前的字节数
+func (e *streamEncoder) Write(p []byte) (int, error) {
+	n, err := e.streamWriter.Write(p)
+	e.original += n
+	return n, err
+}
+
+// Close 写入剩余的压缩数据并记录压缩前后的字节数
+func (e *streamEncoder) Close() error {
+	err := e.streamWriter.Close()
+	recordCompression(e.encoding, e.original, e.out.count)
+	return err
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultEnvelopeSuccessMessage 成功响应信封的默认 message
+const DefaultEnvelopeSuccessMessage = "success"
+
+// EnvelopeConfig 统一响应信封配置
+//
+// 启用后成功和错误响应都包装为 {"code", "message", "data", "trace_id"}，供要求统一响应结构的团队使用：
+// 成功时 code 为 SuccessCode、data 为业务方法的结果；错误时 code 为框架错误码、message 为错误消息、data 为 null，
+// HTTP 状态码与不包装时相同。流式结果不包装
+type EnvelopeConfig struct {
+	// SuccessCode 成功响应的 code，默认 0
+	SuccessCode int
+	// SuccessMessage 成功响应的 message，为空时使用 DefaultEnvelopeSuccessMessage
+	SuccessMessage string
+	// Raw 不包装响应、原样返回结果和 Problem Details 的业务方法（<服务>.<方法>），
+	// <服务>.* 匹配服务的所有方法，* 匹配所有方法，如文件下载和已有固定格式的回调
+	Raw []string
+}
+
+// enveloped 判断业务方法的响应是否包装为信封，method 为空表示尚未解析出业务方法
+func (h *RestProtocolHandler) enveloped(method string) bool {
+	envelope := h.config.Envelope
+	if envelope == nil {
+		return false
+	}
+	for _, pattern := range envelope.Raw {
+		if matchMethod(pattern, method) {
+			return false
+		}
+	}
+	return true
+}
+
+// successEnvelope 返回包装结果的成功响应信封
+//
+// 以 map 表示使 XML 序列化器按通用规则转换 data
+func (h *RestProtocolHandler) successEnvelope(ctx context.Context, result interface{}) map[string]interface{} {
+	message := h.config.Envelope.SuccessMessage
+	if message == "" {
+		message = DefaultEnvelopeSuccessMessage
+	}
+	envelope := map[string]interface{}{
+		"code":    h.config.Envelope.SuccessCode,
+		"message": message,
+		"data":    result,
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		envelope["trace_id"] = spanContext.TraceID().String()
+	}
+	return envelope
+}
+
+// sendErrorFor 发送业务方法的错误响应，方法的响应包装为信封时发送错误信封，否则发送 Problem Details
+func (h *RestProtocolHandler) sendErrorFor(ctx context.Context, r *ghttp.Request, method string, err error, xmlType string) {
+	if !h.enveloped(method) {
+		h.sendProblem(ctx, r, err, xmlType)
+		return
+	}
+
+	payload := adapter.NewErrorPayload(ctx, err)
+	status := frameworkerrors.ErrorCode(payload.Code).ToHTTPStatus()
+	if retryAfter, ok := frameworkerrors.RetryAfterFromFields(payload.Fields); ok {
+		r.Response.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	envelope := map[string]interface{}{
+		"code":    payload.Code,
+		"message": payload.Message,
+		"data":    nil,
+	}
+	if payload.TraceID != "" {
+		envelope["trace_id"] = payload.TraceID
+	}
+
+	if xmlType != "" {
+		if data, xmlErr := h.xml.Serialize(envelope); xmlErr == nil {
+			r.Response.Header().Set("Content-Type", xmlType+"; charset=utf-8")
+			r.Response.WriteStatus(status, data)
+			return
+		}
+	}
+	data, _ := json.Marshal(envelope)
+	r.Response.Header().Set("Content-Type", "application/json")
+	r.Response.WriteStatus(status, data)
+}
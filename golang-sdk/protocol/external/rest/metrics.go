@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	compressionMetricsOnce sync.Once
+	// 响应压缩前后字节数
+	compressionBytes *prometheus.CounterVec
+	// 压缩节省的字节数
+	compressionSaved *prometheus.CounterVec
+	// 客户端接受压缩但未压缩的响应数
+	compressionSkipped *prometheus.CounterVec
+)
+
+// 未压缩的原因
+const (
+	skippedBelowMinSize = "below_min_size"
+	skippedNotSmaller   = "not_smaller"
+)
+
+// initCompressionMetrics 初始化压缩指标
+func initCompressionMetrics() {
+	compressionMetricsOnce.Do(func() {
+		compressionBytes = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_rest_compression_bytes_total",
+				Help: "Total number of REST response bytes before and after compression by encoding",
+			},
+			[]string{"encoding", "stage"},
+		)
+		compressionSaved = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_rest_compression_saved_bytes_total",
+				Help: "Total number of REST response bytes saved by compression by encoding",
+			},
+			[]string{"encoding"},
+		)
+		compressionSkipped = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_rest_compression_skipped_total",
+				Help: "Total number of REST responses sent uncompressed although the client accepted compression, by reason (below_min_size, not_smaller)",
+			},
+			[]string{"encoding", "reason"},
+		)
+	})
+}
+
+// recordCompression 记录一次响应压缩
+func recordCompression(encoding string, original, compressed int) {
+	initCompressionMetrics()
+	compressionBytes.WithLabelValues(encoding, "original").Add(float64(original))
+	compressionBytes.WithLabelValues(encoding, "compressed").Add(float64(compressed))
+	if saved := original - compressed; saved > 0 {
+		compressionSaved.WithLabelValues(encoding).Add(float64(saved))
+	}
+}
+
+// recordCompressionSkipped 记录一次未压缩的响应
+func recordCompressionSkipped(encoding, reason string) {
+	initCompressionMetrics()
+	compressionSkipped.WithLabelValues(encoding, reason).Inc()
+}
@@ -4,17 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/serializer"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 )
 
 // RestProtocolHandler REST 协议处理器
 type RestProtocolHandler struct {
-	server *ghttp.Server
-	config *RestConfig
+	server          *ghttp.Server
+	config          *RestConfig
+	xml             *serializer.XmlSerializer
+	protocolAdapter *adapter.DefaultProtocolAdapter
+	// disabled 运行时停用，见 SetEnabled
+	disabled atomic.Bool
 }
 
 // RestConfig REST 配置
@@ -22,38 +35,99 @@ type RestConfig struct {
 	Host string
 	Port int
 	Path string
+	// XML 请求体和响应体的 XML 转换配置，为 nil 时使用 serializer.DefaultXmlConfig
+	XML *serializer.XmlConfig
+	// ProblemTypeBase 错误响应中 problem 类型 URI 的前缀，后接错误码，为空时使用 errors.DefaultProblemTypeBase
+	ProblemTypeBase string
+	// Server 与同端口的其他 HTTP 协议共用的服务器，不为 nil 时 Start 只注册路由，服务器由调用方启动和关闭
+	Server *ghttp.Server
+	// Listener 不为 nil 时在该监听器上提供服务并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器；
+	// 监听器的 Addr 须为 *net.TCPAddr
+	Listener net.Listener
+	// Dispatcher 本地业务方法分发器，不为 nil 时按 X-Service-Name/X-Method-Name 请求头
+	// 或请求体的 service/method 字段调用业务方法
+	Dispatcher adapter.Dispatcher
+	// CachePolicies GET 请求调用的业务方法的缓存策略，按顺序使用第一个匹配的策略；
+	// GET 响应总是带由响应体生成的 ETag，If-None-Match 匹配时返回 304
+	CachePolicies []CachePolicy
+	// Envelope 不为 nil 时成功和错误响应都包装为统一的 {code, message, data, trace_id} 信封，
+	// EnvelopeConfig.Raw 中的业务方法不包装；为 nil 时成功响应为结果本身，错误响应为 Problem Details
+	Envelope *EnvelopeConfig
+	// Compression 不为 nil 时按 Accept-Encoding 压缩响应（包括流式结果），按 Content-Encoding 解压请求体；
+	// 为 nil 时不压缩，请求体原样读取
+	Compression *CompressionConfig
 }
 
 // NewRestProtocolHandler 创建 REST 协议处理器
 func NewRestProtocolHandler(config *RestConfig) *RestProtocolHandler {
-	// 为每个handler创建独立的命名服务器实例
-	serverName := fmt.Sprintf("rest-%s-%d", config.Host, config.Port)
-	server := g.Server(serverName)
+	server := config.Server
+	if server == nil {
+		// 为每个handler创建独立的命名服务器实例
+		serverName := fmt.Sprintf("rest-%s-%d", config.Host, config.Port)
+		if config.Listener != nil {
+			serverName = fmt.Sprintf("rest-%s", config.Listener.Addr())
+		}
+		server = g.Server(serverName)
+	}
 	return &RestProtocolHandler{
-		server: server,
-		config: config,
+		server:          server,
+		config:          config,
+		protocolAdapter: adapter.NewDefaultProtocolAdapter(),
 	}
 }
 
 // Start 启动 REST 服务器
 func (h *RestProtocolHandler) Start() error {
-	// 配置服务器
-	h.server.SetAddr(h.config.Host + ":" + strconv.Itoa(h.config.Port))
-	
+	// 创建 XML 序列化器，供只能收发 XML 的客户端使用
+	xmlConfig := h.config.XML
+	if xmlConfig == nil {
+		xmlConfig = serializer.DefaultXmlConfig()
+	}
+	xmlSerializer, err := serializer.NewXmlSerializer(xmlConfig)
+	if err != nil {
+		return fmt.Errorf("invalid xml config: %w", err)
+	}
+	h.xml = xmlSerializer
+
 	// 注册路由
 	h.registerRoutes()
 	
-	// 启动服务器
-	go h.server.Run()
+	// 共用的服务器由调用方启动
+	if h.config.Server != nil {
+		return nil
+	}
 	
-	return nil
+	// 配置并启动服务器
+	if h.config.Listener != nil {
+		if err := h.server.SetListener(h.config.Listener); err != nil {
+			return err
+		}
+	} else {
+		h.server.SetAddr(h.config.Host + ":" + strconv.Itoa(h.config.Port))
+	}
+	// 监听成功后返回，端口被占用等错误返回给调用方；请求在服务器的协程中处理
+	return h.server.Start()
 }
 
-// Stop 停止 REST 服务器
+// Stop 停止 REST 服务器，共用的服务器由调用方关闭
 func (h *RestProtocolHandler) Stop(ctx context.Context) error {
+	if h.config.Server != nil {
+		return nil
+	}
 	return h.server.Shutdown()
 }
 
+// SetEnabled 在运行时启用或停用协议，停用后新请求返回 503 Problem Details 并关闭连接，
+// 处理中的请求继续完成；共用的服务器继续监听，同端口的其他协议不受影响
+func (h *RestProtocolHandler) SetEnabled(enabled bool) {
+	h.disabled.Store(!enabled)
+}
+
+// Enabled 返回协议是否启用
+func (h *RestProtocolHandler) Enabled() bool {
+	return !h.disabled.Load()
+}
+
 // registerRoutes 注册路由
 func (h *RestProtocolHandler) registerRoutes() {
 	group := h.server.Group(h.config.Path)
@@ -68,6 +142,11 @@ func (h *RestProtocolHandler) registerRoutes() {
 
 // handleRequest 处理 HTTP 请求
 func (h *RestProtocolHandler) handleRequest(r *ghttp.Request) {
+	if h.disabled.Load() {
+		r.Response.Header().Set("Connection", "close")
+		h.sendError(r.Context(), r, frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "REST protocol is disabled"), xmlResponseType(r.Header))
+		return
+	}
 	// 解析请求
 	request := &RestRequest{
 		Method:  r.Method,
@@ -90,10 +169,41 @@ func (h *RestProtocolHandler) handleRequest(r *ghttp.Request) {
 		}
 	}
 	
+	// 根据 Accept 和 Content-Type 确定是否以 XML 响应
+	xmlType := xmlResponseType(r.Header)
+	if h.config.Compression != nil {
+		defer h.compressResponse(r)
+	}
+
+	// 创建服务端 span，上游追踪上下文作为父 span
+	ctx := adapter.ExtractTraceContext(r.Context(), request.Headers)
+	ctx, span := adapter.StartServerSpan(ctx, adapter.ProtocolREST, "", request.Method+" "+request.Path)
+	defer span.End()
+	
 	// 读取请求体
 	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if h.config.Compression != nil {
+			if err := h.decodeRequestBody(r); err != nil {
+				h.sendError(ctx, r, err, xmlType)
+				return
+			}
+		}
 		body := r.GetBody()
-		if len(body) > 0 {
+		if len(body) > 0 && isXMLMediaType(mediaType(r.Header.Get("Content-Type"))) {
+			var bodyData interface{}
+			if err := h.xml.Deserialize(body, &bodyData); err != nil {
+				h.sendError(ctx, r, &adapter.FrameworkError{
+					Code:    adapter.ErrorBadRequest,
+					Message: fmt.Sprintf("invalid xml body: %v", err),
+					Cause:   err,
+				}, xmlType)
+				return
+			}
+			request.Body = bodyData
+		} else if len(body) > 0 && isRoutedByHeaders(request.Headers) && isJSONMediaType(mediaType(r.Header.Get("Content-Type"))) {
+			// 服务和方法由请求头指定时无需解析请求体，原样转发
+			request.Body = serializer.NewRawPayload(serializer.JSON, body)
+		} else if len(body) > 0 {
 			var bodyData interface{}
 			if err := json.Unmarshal(body, &bodyData); err == nil {
 				request.Body = bodyData
@@ -103,6 +213,11 @@ func (h *RestProtocolHandler) handleRequest(r *ghttp.Request) {
 		}
 	}
 	
+	if h.config.Dispatcher != nil {
+		h.dispatch(ctx, r, request, xmlType)
+		return
+	}
+	
 	// TODO: 调用协议适配器转换请求
 	// TODO: 调用消息路由器路由到目标服务
 	// TODO: 获取响应并转换回 REST 格式
@@ -118,18 +233,88 @@ func (h *RestProtocolHandler) handleRequest(r *ghttp.Request) {
 		},
 	}
 	
-	h.sendResponse(r, response)
+	h.sendResponse(r, response, xmlType)
+}
+
+// dispatch 经协议适配器解析服务和方法后调用本地业务方法
+//
+// 服务和方法由请求头指定时请求体即为参数，由请求体指定时参数为请求体的 params 字段
+func (h *RestProtocolHandler) dispatch(ctx context.Context, r *ghttp.Request, request *RestRequest, xmlType string) {
+	internal, err := h.protocolAdapter.TransformRequest(ctx, &adapter.ExternalRequest{
+		Protocol: adapter.ProtocolREST,
+		Headers:  request.Headers,
+		Body:     request.Body,
+		// 请求转换规则据此提取路径参数
+		Metadata: &adapter.RequestMetadata{Extra: map[string]string{adapter.MetadataPath: request.Path}},
+	})
+	if err != nil {
+		h.sendError(ctx, r, err, xmlType)
+		return
+	}
+	
+	if !isRoutedByHeaders(request.Headers) {
+		body, _ := request.Body.(map[string]interface{})
+		if internal.Payload, err = json.Marshal(body["params"]); err != nil {
+			h.sendError(ctx, r, &adapter.FrameworkError{
+				Code:    adapter.ErrorSerialization,
+				Message: "failed to serialize params",
+				Cause:   err,
+			}, xmlType)
+			return
+		}
+	}
+	
+	method := internal.Service + "." + internal.Method
+	result, err := h.config.Dispatcher(ctx, internal)
+	if err != nil {
+		h.sendErrorFor(ctx, r, method, err, xmlType)
+		return
+	}
+	
+	if stream, ok := result.(*adapter.Stream); ok {
+		h.sendStream(ctx, r, stream, xmlType)
+		return
+	}
+	
+	if r.Method == http.MethodGet && result != nil {
+		h.sendCacheable(ctx, r, method, result, xmlType)
+		return
+	}
+	
+	if h.enveloped(method) {
+		result = h.successEnvelope(ctx, result)
+	}
+	h.sendResponse(r, &RestResponse{
+		StatusCode: http.StatusOK,
+		Headers:    make(map[string]string),
+		Body:       result,
+	}, xmlType)
 }
 
-// sendResponse 发送响应
-func (h *RestProtocolHandler) sendResponse(r *ghttp.Request, response *RestResponse) {
+// sendResponse 发送响应，xmlType 不为空时以该媒体类型发送 XML 响应体
+func (h *RestProtocolHandler) sendResponse(r *ghttp.Request, response *RestResponse, xmlType string) {
 	// 设置响应头
 	for key, value := range response.Headers {
 		r.Response.Header().Set(key, value)
 	}
 	
-	// 设置状态码
-	r.Response.WriteStatus(response.StatusCode)
+	if xmlType != "" && response.Body != nil {
+		data, err := h.xml.Serialize(response.Body)
+		if err != nil {
+			h.sendError(r.Context(), r, &adapter.FrameworkError{
+				Code:    adapter.ErrorInternal,
+				Message: fmt.Sprintf("failed to serialize xml response: %v", err),
+				Cause:   err,
+			}, "")
+			return
+		}
+		r.Response.Header().Set("Content-Type", xmlType+"; charset=utf-8")
+		r.Response.WriteStatus(response.StatusCode, data)
+		return
+	}
+	
+	// 设置状态码，WriteStatus 在没有内容时会写入状态文本
+	r.Response.WriteHeader(response.StatusCode)
 	
 	// 发送响应体
 	if response.Body != nil {
@@ -137,6 +322,223 @@ func (h *RestProtocolHandler) sendResponse(r *ghttp.Request, response *RestRespo
 	}
 }
 
+// sendCacheable 发送 GET 请求的响应：ETag 为序列化后响应体的哈希，If-None-Match 匹配时返回 304 而不发送响应体；
+// 匹配缓存策略时设置 Cache-Control，策略为 NoStore 时不生成 ETag。
+// 响应包装为信封时 ETag 由结果生成，不受每次请求不同的 trace_id 影响
+func (h *RestProtocolHandler) sendCacheable(ctx context.Context, r *ghttp.Request, method string, result interface{}, xmlType string) {
+	header := r.Response.Header()
+	body := result
+	if h.enveloped(method) {
+		body = h.successEnvelope(ctx, result)
+	}
+	policy := h.cachePolicy(method)
+	if policy != nil {
+		header.Set("Cache-Control", policy.cacheControl())
+		if policy.NoStore {
+			h.sendResponse(r, &RestResponse{StatusCode: http.StatusOK, Headers: make(map[string]string), Body: body}, xmlType)
+			return
+		}
+	}
+	
+	serialize := json.Marshal
+	contentType := "application/json"
+	if xmlType != "" {
+		serialize = h.xml.Serialize
+		contentType = xmlType + "; charset=utf-8"
+	}
+	data, err := serialize(result)
+	if err == nil && h.enveloped(method) {
+		etag := entityTag(data)
+		if data, err = serialize(body); err == nil {
+			header.Set("ETag", etag)
+		}
+	}
+	if err != nil {
+		header.Del("Cache-Control")
+		h.sendError(ctx, r, &adapter.FrameworkError{
+			Code:    adapter.ErrorSerialization,
+			Message: fmt.Sprintf("failed to serialize response: %v", err),
+			Cause:   err,
+		}, "")
+		return
+	}
+	
+	// JSON 和 XML 响应体不同，共享缓存须按 Accept 区分
+	etag := header.Get("ETag")
+	if etag == "" {
+		etag = entityTag(data)
+		header.Set("ETag", etag)
+	}
+	header.Add("Vary", "Accept")
+	if notModified(r.Header.Get("If-None-Match"), etag) {
+		r.Response.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", contentType)
+	r.Response.WriteStatus(http.StatusOK, data)
+}
+
+// sendStream 边产生边发送流式结果，每个元素发送后立即刷新
+//
+// Accept 为 application/x-ndjson 时每行一个元素，中途出错时最后一行为 {"error": 跨语言错误格式}；
+// 否则以分块传输发送 JSON 数组，中途出错时数组不闭合，客户端解析失败而不会把部分结果当作完整结果。
+// 发送第一个元素前出错时返回普通的错误响应；XML 响应收集所有元素后发送。
+// 配置了 Compression 且客户端接受压缩时以协商的编码压缩，每个元素发送前刷新压缩器
+func (h *RestProtocolHandler) sendStream(ctx context.Context, r *ghttp.Request, stream *adapter.Stream, xmlType string) {
+	ndjson := adapter.AcceptsNDJSON(r.Header.Get("Accept"))
+	if xmlType != "" && !ndjson {
+		items, err := stream.Collect(ctx)
+		if err != nil {
+			h.sendError(ctx, r, err, xmlType)
+			return
+		}
+		h.sendResponse(r, &RestResponse{
+			StatusCode: http.StatusOK,
+			Headers:    make(map[string]string),
+			Body:       items,
+		}, xmlType)
+		return
+	}
+	
+	contentType := "application/json"
+	if ndjson {
+		contentType = adapter.NDJSONContentType
+	}
+	var out io.Writer = r.Response.Writer
+	var encoder *streamEncoder
+	count := 0
+	err := stream.Each(ctx, func(item interface{}) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return &adapter.FrameworkError{
+				Code:    adapter.ErrorSerialization,
+				Message: "failed to serialize stream item",
+				Cause:   err,
+			}
+		}
+		switch {
+		case count == 0:
+			r.Response.Header().Set("Content-Type", contentType)
+			if encoder = h.newStreamEncoder(r); encoder != nil {
+				out = encoder
+			}
+			if !ndjson {
+				io.WriteString(out, "[")
+			}
+		case !ndjson:
+			io.WriteString(out, ",")
+		}
+		out.Write(data)
+		if ndjson {
+			io.WriteString(out, "\n")
+		}
+		if encoder != nil {
+			encoder.Flush()
+		}
+		r.Response.Flush()
+		count++
+		return nil
+	})
+	
+	switch {
+	case err != nil && count == 0:
+		h.sendError(ctx, r, err, "")
+		return
+	case err != nil:
+		if ndjson {
+			line, _ := json.Marshal(map[string]interface{}{"error": adapter.NewErrorPayload(ctx, err)})
+			out.Write(append(line, '\n'))
+		}
+	case count == 0:
+		r.Response.Header().Set("Content-Type", contentType)
+		if !ndjson {
+			r.Response.Write("[]")
+		}
+		return
+	case !ndjson:
+		io.WriteString(out, "]")
+	}
+	// 已发送元素时写完剩余数据，缓冲区清空后处理结束时不会再压缩
+	if encoder != nil {
+		encoder.Close()
+	}
+	r.Response.Flush()
+}
+
+// sendError 发送尚未解析出业务方法时的错误响应，配置了 Envelope 时发送错误信封，否则发送 Problem Details
+func (h *RestProtocolHandler) sendError(ctx context.Context, r *ghttp.Request, err error, xmlType string) {
+	h.sendErrorFor(ctx, r, "", err, xmlType)
+}
+
+// sendProblem 以 RFC 7807 Problem Details 格式发送错误响应，instance 为追踪 ID
+//
+// xmlType 不为空时以 application/problem+xml 返回，否则为 application/problem+json
+func (h *RestProtocolHandler) sendProblem(ctx context.Context, r *ghttp.Request, err error, xmlType string) {
+	problem := adapter.NewProblemDetails(ctx, err, h.config.ProblemTypeBase)
+	if retryAfter, ok := frameworkerrors.RetryAfterFromFields(problem.Fields); ok {
+		r.Response.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	
+	if xmlType != "" {
+		if data, xmlErr := h.xml.Serialize(problem); xmlErr == nil {
+			r.Response.Header().Set("Content-Type", frameworkerrors.ProblemXMLContentType+"; charset=utf-8")
+			r.Response.WriteStatus(problem.Status, data)
+			return
+		}
+	}
+	
+	data, _ := json.Marshal(problem)
+	r.Response.Header().Set("Content-Type", frameworkerrors.ProblemJSONContentType)
+	r.Response.WriteStatus(problem.Status, data)
+}
+
+// xmlResponseType 返回响应应使用的 XML 媒体类型，不需要 XML 响应时返回空字符串
+//
+// Accept 中先出现的 XML 或 JSON 类型优先，未指定时与请求体的 Content-Type 一致
+func xmlResponseType(headers http.Header) string {
+	for _, part := range strings.Split(headers.Get("Accept"), ",") {
+		accepted := mediaType(part)
+		if isXMLMediaType(accepted) {
+			return accepted
+		}
+		if accepted == "application/json" {
+			return ""
+		}
+	}
+	
+	if contentType := mediaType(headers.Get("Content-Type")); isXMLMediaType(contentType) {
+		return contentType
+	}
+	return ""
+}
+
+// mediaType 解析不带参数的媒体类型，解析失败时返回空字符串
+func mediaType(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	parsed, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return ""
+	}
+	return parsed
+}
+
+// isRoutedByHeaders 检查是否通过请求头指定了服务和方法
+func isRoutedByHeaders(headers map[string]string) bool {
+	return headers["X-Service-Name"] != "" && headers["X-Method-Name"] != ""
+}
+
+// isJSONMediaType 检查是否为 JSON 媒体类型，包括 application/json 和 *+json
+func isJSONMediaType(value string) bool {
+	return value == "application/json" || strings.HasSuffix(value, "+json")
+}
+
+// isXMLMediaType 检查是否为 XML 媒体类型，包括 text/xml、application/xml 和 application/soap+xml 等
+func isXMLMediaType(value string) bool {
+	return value == "text/xml" || value == "application/xml" || strings.HasSuffix(value, "+xml")
+}
+
 // RestRequest REST 请求
 type RestRequest struct {
 	Method  string
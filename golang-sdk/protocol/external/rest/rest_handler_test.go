@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/transport/memory"
+	"github.com/framework/golang-sdk/serializer"
 )
 
 // TestRestHandlerCreation 测试 REST 处理器创建
@@ -25,10 +32,10 @@ func TestRestHandlerCreation(t *testing.T) {
 
 // TestRestHandlerStartStop 测试 REST 处理器启动和停止
 func TestRestHandlerStartStop(t *testing.T) {
+	listener := memory.Listen()
 	config := &RestConfig{
-		Host: "127.0.0.1",
-		Port: 8082,
-		Path: "/api",
+		Listener: listener,
+		Path:     "/api",
 	}
 	
 	handler := NewRestProtocolHandler(config)
@@ -39,9 +46,6 @@ func TestRestHandlerStartStop(t *testing.T) {
 		t.Fatalf("Failed to start REST handler: %v", err)
 	}
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 停止处理器
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -54,10 +58,10 @@ func TestRestHandlerStartStop(t *testing.T) {
 
 // TestRestHandlerGET 测试 GET 请求
 func TestRestHandlerGET(t *testing.T) {
+	listener := memory.Listen()
 	config := &RestConfig{
-		Host: "127.0.0.1",
-		Port: 8083,
-		Path: "/api",
+		Listener: listener,
+		Path:     "/api",
 	}
 	
 	handler := NewRestProtocolHandler(config)
@@ -67,11 +71,8 @@ func TestRestHandlerGET(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 发送 GET 请求
-	resp, err := http.Get("http://127.0.0.1:8083/api/test")
+	resp, err := listener.HTTPClient().Get("http://" + listener.Address() + "/api/test")
 	if err != nil {
 		t.Fatalf("Failed to send GET request: %v", err)
 	}
@@ -84,10 +85,10 @@ func TestRestHandlerGET(t *testing.T) {
 
 // TestRestHandlerPOST 测试 POST 请求
 func TestRestHandlerPOST(t *testing.T) {
+	listener := memory.Listen()
 	config := &RestConfig{
-		Host: "127.0.0.1",
-		Port: 8084,
-		Path: "/api",
+		Listener: listener,
+		Path:     "/api",
 	}
 	
 	handler := NewRestProtocolHandler(config)
@@ -97,9 +98,6 @@ func TestRestHandlerPOST(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 准备请求体
 	requestBody := map[string]interface{}{
 		"name": "test",
@@ -108,7 +106,7 @@ func TestRestHandlerPOST(t *testing.T) {
 	bodyBytes, _ := json.Marshal(requestBody)
 	
 	// 发送 POST 请求
-	resp, err := http.Post("http://127.0.0.1:8084/api/test", "application/json", bytes.NewBuffer(bodyBytes))
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/api/test", "application/json", bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		t.Fatalf("Failed to send POST request: %v", err)
 	}
@@ -121,10 +119,10 @@ func TestRestHandlerPOST(t *testing.T) {
 
 // TestRestHandlerAllMethods 测试所有 HTTP 方法
 func TestRestHandlerAllMethods(t *testing.T) {
+	listener := memory.Listen()
 	config := &RestConfig{
-		Host: "127.0.0.1",
-		Port: 8085,
-		Path: "/api",
+		Listener: listener,
+		Path:     "/api",
 	}
 	
 	handler := NewRestProtocolHandler(config)
@@ -134,9 +132,6 @@ func TestRestHandlerAllMethods(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	methods := []string{
 		http.MethodGet,
 		http.MethodPost,
@@ -145,10 +140,10 @@ func TestRestHandlerAllMethods(t *testing.T) {
 		http.MethodPatch,
 	}
 	
-	client := &http.Client{}
+	client := listener.HTTPClient()
 	
 	for _, method := range methods {
-		req, err := http.NewRequest(method, "http://127.0.0.1:8085/api/test", nil)
+		req, err := http.NewRequest(method, "http://"+listener.Address()+"/api/test", nil)
 		if err != nil {
 			t.Fatalf("Failed to create %s request: %v", method, err)
 		}
@@ -165,3 +160,520 @@ func TestRestHandlerAllMethods(t *testing.T) {
 		resp.Body.Close()
 	}
 }
+
+// TestRestHandlerXML 测试 XML 请求和响应
+func TestRestHandlerXML(t *testing.T) {
+	listener := memory.Listen()
+	config := &RestConfig{
+		Listener: listener,
+		Path:     "/api",
+		XML: &serializer.XmlConfig{
+			RootElement:  "Response",
+			FieldMapping: map[string]string{"CustNo": "customerId"},
+		},
+	}
+	
+	handler := NewRestProtocolHandler(config)
+	err := handler.Start()
+	if err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	// 发送 XML 请求，响应同样为 XML
+	body := `<Order><CustNo>1001</CustNo></Order>`
+	resp, err := listener.HTTPClient().Post("http://"+listener.Address()+"/api/orders", "text/xml; charset=utf-8", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to send XML request: %v", err)
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/xml") {
+		t.Errorf("Expected text/xml response, got %s", contentType)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(data), "<Response>") {
+		t.Errorf("Expected XML response body, got %s", data)
+	}
+	
+	// 格式错误的 XML 返回 400
+	resp, err = listener.HTTPClient().Post("http://"+listener.Address()+"/api/orders", "application/xml", strings.NewReader("<Order>"))
+	if err != nil {
+		t.Fatalf("Failed to send XML request: %v", err)
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, frameworkerrors.ProblemXMLContentType) {
+		t.Errorf("Expected problem+xml response, got %s", contentType)
+	}
+	data, _ = io.ReadAll(resp.Body)
+	if !strings.Contains(string(data), "<status>400</status>") {
+		t.Errorf("Expected problem details body, got %s", data)
+	}
+}
+
+// TestRestHandlerProblemDetails 测试错误响应为 RFC 7807 Problem Details
+func TestRestHandlerProblemDetails(t *testing.T) {
+	listener := memory.Listen()
+	config := &RestConfig{
+		Listener:        listener,
+		Path:            "/api",
+		ProblemTypeBase: "https://errors.example.com/",
+	}
+	
+	handler := NewRestProtocolHandler(config)
+	err := handler.Start()
+	if err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	// 格式错误的 XML 请求体，客户端接受 JSON
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Address()+"/api/orders", strings.NewReader("<Order>"))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	
+	resp, err := listener.HTTPClient().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != frameworkerrors.ProblemJSONContentType {
+		t.Errorf("Expected %s, got %s", frameworkerrors.ProblemJSONContentType, contentType)
+	}
+	
+	var problem frameworkerrors.ProblemDetails
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem details: %v", err)
+	}
+	if problem.Type != "https://errors.example.com/400" {
+		t.Errorf("Expected type https://errors.example.com/400, got %s", problem.Type)
+	}
+	if problem.Title != "Bad Request" || problem.Status != http.StatusBadRequest {
+		t.Errorf("Unexpected title/status: %s/%d", problem.Title, problem.Status)
+	}
+	if !strings.HasPrefix(problem.Detail, "invalid xml body") {
+		t.Errorf("Unexpected detail: %s", problem.Detail)
+	}
+	if problem.Instance != traceID {
+		t.Errorf("Expected instance %s, got %s", traceID, problem.Instance)
+	}
+}
+
+// TestXMLResponseType 测试响应格式的选择
+func TestXMLResponseType(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		expected    string
+	}{
+		{"json by default", "", "", ""},
+		{"xml request", "", "text/xml; charset=utf-8", "text/xml"},
+		{"soap request", "*/*", "application/soap+xml", "application/soap+xml"},
+		{"accept xml", "application/xml", "application/json", "application/xml"},
+		{"accept json first", "application/json, application/xml", "text/xml", ""},
+		{"accept xml with quality", "application/xml;q=0.9", "", "application/xml"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.accept != "" {
+				headers.Set("Accept", tt.accept)
+			}
+			if tt.contentType != "" {
+				headers.Set("Content-Type", tt.contentType)
+			}
+			if got := xmlResponseType(headers); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestRestHandlerStream 测试流式结果的 NDJSON 和分块 JSON 数组响应
+func TestRestHandlerStream(t *testing.T) {
+	listener := memory.Listen()
+	config := &RestConfig{
+		Listener: listener,
+		Path:     "/api",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			var params struct {
+				Count  int `json:"count"`
+				FailAt int `json:"failAt"`
+			}
+			json.Unmarshal(request.Payload, &params)
+			return adapter.NewStream(func(ctx context.Context, send func(item interface{}) error) error {
+				for i := 0; i < params.Count; i++ {
+					if i+1 == params.FailAt {
+						return frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "backend unavailable")
+					}
+					if err := send(map[string]int{"n": i}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}), nil
+		},
+	}
+	
+	handler := NewRestProtocolHandler(config)
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	call := func(accept, params string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Address()+"/api/items", strings.NewReader(params))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Service-Name", "item")
+		req.Header.Set("X-Method-Name", "list")
+		req.Header.Set("Accept", accept)
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+	
+	t.Run("NDJSON", func(t *testing.T) {
+		resp, body := call(adapter.NDJSONContentType, `{"count":3}`)
+		if resp.Header.Get("Content-Type") != adapter.NDJSONContentType {
+			t.Errorf("Expected %s, got %s", adapter.NDJSONContentType, resp.Header.Get("Content-Type"))
+		}
+		if body != "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n" {
+			t.Errorf("Unexpected body: %q", body)
+		}
+	})
+	
+	t.Run("JSON 数组", func(t *testing.T) {
+		resp, body := call("application/json", `{"count":3}`)
+		if resp.StatusCode != http.StatusOK || body != `[{"n":0},{"n":1},{"n":2}]` {
+			t.Errorf("Unexpected response: %d %s", resp.StatusCode, body)
+		}
+		if _, body := call("application/json", `{"count":0}`); body != "[]" {
+			t.Errorf("Expected empty array, got %s", body)
+		}
+	})
+	
+	t.Run("发送前出错", func(t *testing.T) {
+		resp, _ := call(adapter.NDJSONContentType, `{"count":3,"failAt":1}`)
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+		}
+	})
+	
+	t.Run("中途出错", func(t *testing.T) {
+		_, body := call(adapter.NDJSONContentType, `{"count":3,"failAt":2}`)
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		if len(lines) != 2 || lines[0] != `{"n":0}` {
+			t.Fatalf("Unexpected body: %q", body)
+		}
+		var last struct {
+			Error frameworkerrors.ErrorPayload `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(lines[1]), &last); err != nil || last.Error.Code != int(frameworkerrors.ServiceUnavailable) {
+			t.Errorf("Expected error line, got %s", lines[1])
+		}
+		
+		// JSON 数组不闭合
+		_, body = call("application/json", `{"count":3,"failAt":2}`)
+		if body != `[{"n":0}` {
+			t.Errorf("Expected unterminated array, got %s", body)
+		}
+	})
+}
+
+// TestRestHandlerCache 测试 GET 响应的 ETag、条件请求和缓存策略
+func TestRestHandlerCache(t *testing.T) {
+	listener := memory.Listen()
+	version := 1
+	config := &RestConfig{
+		Listener: listener,
+		Path:     "/api",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			return map[string]interface{}{"method": request.Method, "version": version}, nil
+		},
+		CachePolicies: []CachePolicy{
+			{Method: "catalog.getItem", MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute, StaleWhileRevalidate: 30 * time.Second},
+			{Method: "user.*", MaxAge: 30 * time.Second, Private: true},
+			{Method: "session.*", NoStore: true},
+		},
+	}
+	
+	handler := NewRestProtocolHandler(config)
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	get := func(service, method, ifNoneMatch string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, "http://"+listener.Address()+"/api/items", nil)
+		req.Header.Set("X-Service-Name", service)
+		req.Header.Set("X-Method-Name", method)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+	
+	resp, body := get("catalog", "getItem", "")
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || body != `{"method":"getItem","version":1}` {
+		t.Fatalf("Unexpected response: %d %q %s", resp.StatusCode, etag, body)
+	}
+	if got := resp.Header.Get("Cache-Control"); got != "public, max-age=60, s-maxage=300, stale-while-revalidate=30" {
+		t.Errorf("Unexpected Cache-Control: %s", got)
+	}
+	
+	// 响应体未变时返回 304，弱比较忽略 W/ 前缀
+	resp, body = get("catalog", "getItem", `"other", W/`+etag)
+	if resp.StatusCode != http.StatusNotModified || body != "" || resp.Header.Get("ETag") != etag {
+		t.Errorf("Expected 304 with ETag, got %d %q", resp.StatusCode, body)
+	}
+	
+	// 响应体变化后 ETag 不再匹配
+	version = 2
+	if resp, _ = get("catalog", "getItem", etag); resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("Expected new ETag after change, got %d %s", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+	
+	if resp, _ = get("user", "getProfile", ""); resp.Header.Get("Cache-Control") != "private, max-age=30" {
+		t.Errorf("Unexpected private Cache-Control: %s", resp.Header.Get("Cache-Control"))
+	}
+	if resp, _ = get("session", "current", ""); resp.Header.Get("Cache-Control") != "no-store" || resp.Header.Get("ETag") != "" {
+		t.Errorf("Expected no-store without ETag, got %q %q", resp.Header.Get("Cache-Control"), resp.Header.Get("ETag"))
+	}
+	// 没有匹配的策略时只生成 ETag
+	if resp, _ = get("order", "list", ""); resp.Header.Get("Cache-Control") != "" || resp.Header.Get("ETag") == "" {
+		t.Errorf("Expected ETag only, got %q %q", resp.Header.Get("Cache-Control"), resp.Header.Get("ETag"))
+	}
+}
+
+// TestRestHandlerEnvelope 测试统一响应信封：成功和错误响应都包装，Raw 中的方法原样返回
+func TestRestHandlerEnvelope(t *testing.T) {
+	listener := memory.Listen()
+	config := &RestConfig{
+		Listener: listener,
+		Path:     "/api",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			if request.Method == "missing" {
+				return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "order not found")
+			}
+			return map[string]interface{}{"id": 1}, nil
+		},
+		Envelope: &EnvelopeConfig{Raw: []string{"files.*"}},
+	}
+	
+	handler := NewRestProtocolHandler(config)
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	call := func(httpMethod, service, method, ifNoneMatch string) (*http.Response, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(httpMethod, "http://"+listener.Address()+"/api/orders", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Service-Name", service)
+		req.Header.Set("X-Method-Name", method)
+		req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp, body
+	}
+	
+	resp, body := call(http.MethodPost, "order", "get", "")
+	data, _ := body["data"].(map[string]interface{})
+	if resp.StatusCode != http.StatusOK || body["code"] != float64(0) || body["message"] != DefaultEnvelopeSuccessMessage ||
+		data["id"] != float64(1) || body["trace_id"] != traceID {
+		t.Errorf("Unexpected success envelope: %d %v", resp.StatusCode, body)
+	}
+	
+	resp, body = call(http.MethodPost, "order", "missing", "")
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != "application/json" ||
+		body["code"] != float64(frameworkerrors.NotFound) || body["message"] != "order not found" || body["data"] != nil || body["trace_id"] != traceID {
+		t.Errorf("Unexpected error envelope: %d %v", resp.StatusCode, body)
+	}
+	
+	// GET 响应的 ETag 不受 trace_id 影响
+	resp, _ = call(http.MethodGet, "order", "get", "")
+	etag := resp.Header.Get("ETag")
+	if resp, _ = call(http.MethodGet, "order", "get", etag); etag == "" || resp.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304 for enveloped GET, got %d %q", resp.StatusCode, etag)
+	}
+	
+	// Raw 中的方法原样返回结果和 Problem Details
+	if resp, body = call(http.MethodPost, "files", "get", ""); resp.StatusCode != http.StatusOK || body["id"] != float64(1) {
+		t.Errorf("Expected raw result, got %d %v", resp.StatusCode, body)
+	}
+	if resp, _ = call(http.MethodPost, "files", "missing", ""); resp.Header.Get("Content-Type") != frameworkerrors.ProblemJSONContentType {
+		t.Errorf("Expected problem details for raw method, got %s", resp.Header.Get("Content-Type"))
+	}
+}
+
+// TestNegotiateEncoding 测试按 Accept-Encoding 和服务端偏好选择编码
+func TestNegotiateEncoding(t *testing.T) {
+	encodings := []string{EncodingZstd, EncodingGzip, EncodingDeflate}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, deflate, br, zstd", EncodingZstd},
+		{"gzip;q=1.0, zstd;q=0.5", EncodingGzip},
+		{"zstd;q=0, *", EncodingGzip},
+		{"identity", ""},
+		{"br", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.accept, encodings); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// TestRestHandlerCompression 测试响应压缩、请求体解压和流式结果压缩
+func TestRestHandlerCompression(t *testing.T) {
+	listener := memory.Listen()
+	config := &RestConfig{
+		Listener: listener,
+		Path:     "/api",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			var params struct {
+				Text   string `json:"text"`
+				Stream int    `json:"stream"`
+			}
+			json.Unmarshal(request.Payload, &params)
+			if params.Stream > 0 {
+				return adapter.NewStream(func(ctx context.Context, send func(item interface{}) error) error {
+					for i := 0; i < params.Stream; i++ {
+						if err := send(map[string]int{"n": i}); err != nil {
+							return err
+						}
+					}
+					return nil
+				}), nil
+			}
+			return map[string]string{"echo": params.Text}, nil
+		},
+		Compression: &CompressionConfig{MinSize: 256},
+	}
+	handler := NewRestProtocolHandler(config)
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start REST handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	call := func(body []byte, headers map[string]string) (*http.Response, []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, "http://"+listener.Address()+"/api/echo", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Service-Name", "text")
+		req.Header.Set("X-Method-Name", "echo")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := listener.HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, data
+	}
+	decode := func(encoding string, data []byte) string {
+		t.Helper()
+		codec, _ := lookupCodec(encoding)
+		reader, err := codec.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to create %s reader: %v", encoding, err)
+		}
+		defer reader.Close()
+		plain, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Failed to decode %s body: %v", encoding, err)
+		}
+		return string(plain)
+	}
+	text := strings.Repeat("compressible ", 100)
+	params, _ := json.Marshal(map[string]string{"text": text})
+
+	for _, encoding := range []string{EncodingGzip, EncodingZstd, EncodingDeflate} {
+		resp, data := call(params, map[string]string{"Accept-Encoding": encoding})
+		if resp.Header.Get("Content-Encoding") != encoding {
+			t.Fatalf("Expected Content-Encoding %s, got %q", encoding, resp.Header.Get("Content-Encoding"))
+		}
+		if len(data) >= len(params) {
+			t.Errorf("%s body not smaller: %d >= %d", encoding, len(data), len(params))
+		}
+		var result map[string]string
+		if err := json.Unmarshal([]byte(decode(encoding, data)), &result); err != nil || result["echo"] != text {
+			t.Errorf("Unexpected %s result: %v %v", encoding, result, err)
+		}
+	}
+
+	// 小于阈值和客户端不接受压缩时原样返回
+	if resp, data := call([]byte(`{"text":"short"}`), map[string]string{"Accept-Encoding": "gzip"}); resp.Header.Get("Content-Encoding") != "" || string(data) != `{"echo":"short"}` {
+		t.Errorf("Expected uncompressed small response, got %q %s", resp.Header.Get("Content-Encoding"), data)
+	}
+	if resp, _ := call(params, map[string]string{"Accept-Encoding": "identity"}); resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("Expected uncompressed response, got %q", resp.Header.Get("Content-Encoding"))
+	}
+
+	// 压缩的请求体
+	var compressed bytes.Buffer
+	codec, _ := lookupCodec(EncodingGzip)
+	writer, _ := codec.NewWriter(&compressed)
+	writer.Write(params)
+	writer.Close()
+	resp, data := call(compressed.Bytes(), map[string]string{"Content-Encoding": "gzip", "Accept-Encoding": "identity"})
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), "compressible") {
+		t.Errorf("Unexpected response to gzip body: %d %s", resp.StatusCode, data)
+	}
+	if resp, _ := call(params, map[string]string{"Content-Encoding": "compress"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unsupported encoding, got %d", resp.StatusCode)
+	}
+
+	// 流式结果逐个元素压缩
+	resp, data = call([]byte(`{"stream":3}`), map[string]string{"Accept-Encoding": "gzip", "Accept": adapter.NDJSONContentType})
+	if resp.Header.Get("Content-Encoding") != EncodingGzip {
+		t.Fatalf("Expected gzip stream, got %q", resp.Header.Get("Content-Encoding"))
+	}
+	if body := decode(EncodingGzip, data); body != "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n" {
+		t.Errorf("Unexpected stream body: %q", body)
+	}
+}
@@ -0,0 +1,103 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// connectionSendBuffer 每个连接待发送消息的缓冲数，广播消息超出时视为慢连接并关闭
+const connectionSendBuffer = 64
+
+// textMessage WebSocket 文本消息类型
+const textMessage = 1
+
+// closeMessage WebSocket 关闭帧类型
+const closeMessage = 8
+
+// closeTryAgainLater 关闭帧状态码：服务暂时不可用，客户端稍后重连
+const closeTryAgainLater = 1013
+
+// outbound 待发送的消息
+type outbound struct {
+	msgType int
+	data    []byte
+}
+
+// connection WebSocket 连接，响应和广播消息由单独的协程按顺序写入，作为 Hub 的订阅方
+type connection struct {
+	ws        *ghttp.WebSocket
+	send      chan outbound
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newConnection 创建连接并启动写协程
+func newConnection(ws *ghttp.WebSocket) *connection {
+	s := &connection{
+		ws:   ws,
+		send: make(chan outbound, connectionSendBuffer),
+		done: make(chan struct{}),
+	}
+	go s.writeLoop()
+	return s
+}
+
+// Send 投递广播消息，缓冲已满时关闭连接，避免慢连接阻塞广播
+func (s *connection) Send(message []byte) bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+	select {
+	case s.send <- outbound{msgType: textMessage, data: message}:
+		return true
+	default:
+		s.close()
+		return false
+	}
+}
+
+// reply 投递请求的响应，缓冲已满时等待，连接关闭后返回 false
+func (s *connection) reply(msgType int, data []byte) bool {
+	select {
+	case s.send <- outbound{msgType: msgType, data: data}:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// writeLoop 依次写入待发送的消息，写入失败时关闭连接
+func (s *connection) writeLoop() {
+	for {
+		select {
+		case message := <-s.send:
+			if err := s.ws.WriteMessage(message.msgType, message.data); err != nil {
+				s.close()
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// close 关闭连接，阻塞中的读取随之返回
+func (s *connection) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.ws.Close()
+	})
+}
+
+// shutdown 发送带状态码的关闭帧后关闭连接
+func (s *connection) shutdown(code uint16, reason string) {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	payload = append(payload, reason...)
+	s.ws.WriteControl(closeMessage, payload, time.Now().Add(time.Second))
+	s.close()
+}
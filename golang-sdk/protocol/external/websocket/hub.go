@@ -0,0 +1,214 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/framework/golang-sdk/messaging"
+)
+
+// DefaultTopicPrefix Hub 经消息中间件广播时主题名的默认前缀
+const DefaultTopicPrefix = "ws."
+
+// ErrHubClosed Hub 已关闭
+var ErrHubClosed = errors.New("websocket hub closed")
+
+// Subscriber 主题的订阅方，通常为 WebSocket 连接
+type Subscriber interface {
+	// Send 投递一条文本消息，不能阻塞；返回 false 表示订阅方已无法接收，Hub 将其从所有主题中移除
+	Send(message []byte) bool
+}
+
+// HubOptions Hub 选项
+type HubOptions struct {
+	// Broker 多实例间转发广播的消息中间件（如 messaging.RedisPubSubBroker），为 nil 时只广播到本实例的连接；
+	// Hub 不关闭 Broker
+	Broker messaging.Broker
+	// TopicPrefix 经 Broker 发布时主题名的前缀，为空时使用 DefaultTopicPrefix
+	TopicPrefix string
+}
+
+// Hub WebSocket 主题订阅与广播
+//
+// 连接订阅主题后，Broadcast 发往该主题的消息以 {"topic", "data"} 推送给所有订阅的连接。
+// 配置 Broker 时广播先发布到消息中间件，各实例只为本地有订阅的主题订阅中间件，再推送给本地连接，
+// 因此任一实例调用 Broadcast 都能送达所有实例上的订阅方
+type Hub struct {
+	broker messaging.Broker
+	prefix string
+
+	mu     sync.Mutex
+	topics map[string]*hubTopic
+	closed bool
+}
+
+// hubTopic 本实例上一个主题的订阅方，sub 为对应的消息中间件订阅
+type hubTopic struct {
+	subscribers map[Subscriber]struct{}
+	sub         messaging.Subscription
+}
+
+// hubMessage 推送给订阅方的广播消息
+type hubMessage struct {
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// NewHub 创建 Hub，options 为 nil 时只在本实例内广播
+func NewHub(options *HubOptions) *Hub {
+	h := &Hub{
+		prefix: DefaultTopicPrefix,
+		topics: make(map[string]*hubTopic),
+	}
+	if options != nil {
+		h.broker = options.Broker
+		if options.TopicPrefix != "" {
+			h.prefix = options.TopicPrefix
+		}
+	}
+	return h
+}
+
+// Subscribe 将订阅方加入主题，本实例上第一个订阅方加入时订阅消息中间件
+func (h *Hub) Subscribe(topic string, subscriber Subscriber) error {
+	if topic == "" {
+		return errors.New("topic is required")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrHubClosed
+	}
+	t, ok := h.topics[topic]
+	if !ok {
+		t = &hubTopic{subscribers: make(map[Subscriber]struct{})}
+		if h.broker != nil {
+			sub, err := h.broker.Subscribe(h.prefix+topic, "", func(ctx context.Context, msg *messaging.Message) error {
+				h.deliver(topic, msg.Payload)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to subscribe to topic %s: %w", topic, err)
+			}
+			t.sub = sub
+		}
+		h.topics[topic] = t
+	}
+	t.subscribers[subscriber] = struct{}{}
+	return nil
+}
+
+// Unsubscribe 将订阅方移出主题，本实例上最后一个订阅方移出时取消消息中间件的订阅
+func (h *Hub) Unsubscribe(topic string, subscriber Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(topic, subscriber)
+}
+
+// UnsubscribeAll 将订阅方移出所有主题，连接关闭时调用
+func (h *Hub) UnsubscribeAll(subscriber Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for topic := range h.topics {
+		h.remove(topic, subscriber)
+	}
+}
+
+// remove 将订阅方移出主题，调用方持有 h.mu
+func (h *Hub) remove(topic string, subscriber Subscriber) {
+	t, ok := h.topics[topic]
+	if !ok {
+		return
+	}
+	delete(t.subscribers, subscriber)
+	if len(t.subscribers) > 0 {
+		return
+	}
+	delete(h.topics, topic)
+	if t.sub != nil {
+		t.sub.Unsubscribe()
+	}
+}
+
+// Broadcast 向主题的所有订阅方推送 payload 的 JSON 编码，json.RawMessage 原样推送；
+// 配置 Broker 时经消息中间件送达所有实例
+func (h *Hub) Broadcast(ctx context.Context, topic string, payload interface{}) error {
+	if topic == "" {
+		return errors.New("topic is required")
+	}
+	data, ok := payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to encode broadcast payload: %w", err)
+		}
+	}
+	message, err := json.Marshal(hubMessage{Topic: topic, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode broadcast payload: %w", err)
+	}
+
+	h.mu.Lock()
+	closed := h.closed
+	h.mu.Unlock()
+	if closed {
+		return ErrHubClosed
+	}
+	if h.broker != nil {
+		return h.broker.Publish(ctx, &messaging.Message{Topic: h.prefix + topic, Payload: message})
+	}
+	h.deliver(topic, message)
+	return nil
+}
+
+// Subscribers 返回本实例上主题的订阅方数量
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if t, ok := h.topics[topic]; ok {
+		return len(t.subscribers)
+	}
+	return 0
+}
+
+// Close 取消所有订阅，之后 Subscribe 和 Broadcast 返回 ErrHubClosed
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	for topic, t := range h.topics {
+		if t.sub != nil {
+			t.sub.Unsubscribe()
+		}
+		delete(h.topics, topic)
+	}
+	return nil
+}
+
+// deliver 将消息推送给本实例上主题的订阅方，无法接收的订阅方移出所有主题
+func (h *Hub) deliver(topic string, message []byte) {
+	h.mu.Lock()
+	t, ok := h.topics[topic]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	subscribers := make([]Subscriber, 0, len(t.subscribers))
+	for subscriber := range t.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	h.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		if !subscriber.Send(message) {
+			h.UnsubscribeAll(subscriber)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/framework/golang-sdk/messaging"
+)
+
+// recordingSubscriber 记录收到的消息，full 为 true 时模拟无法接收的连接
+type recordingSubscriber struct {
+	mu       sync.Mutex
+	messages []string
+	full     bool
+}
+
+func (s *recordingSubscriber) Send(message []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full {
+		return false
+	}
+	s.messages = append(s.messages, string(message))
+	return true
+}
+
+func (s *recordingSubscriber) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func TestHubBroadcast(t *testing.T) {
+	hub := NewHub(nil)
+	defer hub.Close()
+
+	orders, users := &recordingSubscriber{}, &recordingSubscriber{}
+	if err := hub.Subscribe("orders", orders); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	hub.Subscribe("users", users)
+
+	if err := hub.Broadcast(context.Background(), "orders", map[string]int{"id": 1}); err != nil {
+		t.Fatalf("Broadcast failed: %v", err)
+	}
+	hub.Broadcast(context.Background(), "users", json.RawMessage(`"raw"`))
+
+	if got := orders.received(); len(got) != 1 || got[0] != `{"topic":"orders","data":{"id":1}}` {
+		t.Errorf("orders received %v", got)
+	}
+	if got := users.received(); len(got) != 1 || got[0] != `{"topic":"users","data":"raw"}` {
+		t.Errorf("users received %v", got)
+	}
+
+	hub.Unsubscribe("orders", orders)
+	hub.Broadcast(context.Background(), "orders", 2)
+	if got := orders.received(); len(got) != 1 {
+		t.Errorf("expected no messages after unsubscribe, got %v", got)
+	}
+	if hub.Subscribers("orders") != 0 {
+		t.Errorf("expected topic to be removed, got %d subscribers", hub.Subscribers("orders"))
+	}
+}
+
+func TestHubRemovesFailedSubscriber(t *testing.T) {
+	hub := NewHub(nil)
+	defer hub.Close()
+
+	slow := &recordingSubscriber{full: true}
+	hub.Subscribe("orders", slow)
+	hub.Subscribe("users", slow)
+
+	hub.Broadcast(context.Background(), "orders", 1)
+	if hub.Subscribers("orders") != 0 || hub.Subscribers("users") != 0 {
+		t.Error("expected failed subscriber to be removed from all topics")
+	}
+}
+
+func TestHubBroker(t *testing.T) {
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	// 两个实例共用消息中间件
+	first := NewHub(&HubOptions{Broker: broker})
+	second := NewHub(&HubOptions{Broker: broker})
+	defer first.Close()
+	defer second.Close()
+
+	local, remote := &recordingSubscriber{}, &recordingSubscriber{}
+	first.Subscribe("orders", local)
+	second.Subscribe("orders", remote)
+
+	if err := first.Broadcast(context.Background(), "orders", "created"); err != nil {
+		t.Fatalf("Broadcast failed: %v", err)
+	}
+	want := `{"topic":"orders","data":"created"}`
+	for name, subscriber := range map[string]*recordingSubscriber{"local": local, "remote": remote} {
+		if got := subscriber.received(); len(got) != 1 || got[0] != want {
+			t.Errorf("%s received %v", name, got)
+		}
+	}
+
+	second.UnsubscribeAll(remote)
+	first.Broadcast(context.Background(), "orders", "updated")
+	if got := remote.received(); len(got) != 1 {
+		t.Errorf("expected no messages after unsubscribe, got %v", got)
+	}
+}
+
+func TestHubClosed(t *testing.T) {
+	hub := NewHub(nil)
+	hub.Close()
+
+	if err := hub.Subscribe("orders", &recordingSubscriber{}); err != ErrHubClosed {
+		t.Errorf("Subscribe error = %v, want ErrHubClosed", err)
+	}
+	if err := hub.Broadcast(context.Background(), "orders", 1); err != ErrHubClosed {
+		t.Errorf("Broadcast error = %v, want ErrHubClosed", err)
+	}
+	if err := hub.Subscribe("", &recordingSubscriber{}); err == nil {
+		t.Error("expected error for empty topic")
+	}
+}
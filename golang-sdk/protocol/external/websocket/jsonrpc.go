@@ -0,0 +1,330 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/external/jsonrpc"
+)
+
+// DefaultMaxConcurrentCalls 每个连接默认并发处理的 JSON-RPC 请求数上限
+const DefaultMaxConcurrentCalls = 64
+
+// rpcMessage 收到的 JSON-RPC 2.0 消息：有 method 时为请求（没有 id 时为通知），有 result 或 error 时为客户端对服务端调用的响应
+type rpcMessage struct {
+	Jsonrpc string                `json:"jsonrpc"`
+	Method  string                `json:"method"`
+	Params  json.RawMessage       `json:"params"`
+	Id      json.RawMessage       `json:"id"`
+	Result  json.RawMessage       `json:"result"`
+	Error   *jsonrpc.JsonRpcError `json:"error"`
+}
+
+// isRPC 判断文本消息是否为 JSON-RPC 2.0 消息：批量请求（数组）或带 jsonrpc 字段的对象
+func isRPC(message []byte) bool {
+	trimmed := bytes.TrimSpace(message)
+	if len(trimmed) == 0 {
+		return false
+	}
+	if trimmed[0] == '[' {
+		return true
+	}
+	var probe struct {
+		Jsonrpc string `json:"jsonrpc"`
+	}
+	return json.Unmarshal(trimmed, &probe) == nil && probe.Jsonrpc != ""
+}
+
+// Peer 连接的 JSON-RPC 对端，业务方法经 PeerFromContext 获取后可以调用客户端方法或向客户端发送通知，
+// 也可以保存 Peer 在连接关闭（Done）前随时推送
+type Peer struct {
+	conn  *connection
+	calls chan struct{} // 并发处理请求的信号量
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *rpcMessage // 服务端调用的 ID -> 等待响应的通道
+}
+
+// newPeer 创建连接的 JSON-RPC 对端
+func newPeer(conn *connection, maxCalls int) *Peer {
+	if maxCalls <= 0 {
+		maxCalls = DefaultMaxConcurrentCalls
+	}
+	return &Peer{
+		conn:    conn,
+		calls:   make(chan struct{}, maxCalls),
+		pending: make(map[string]chan *rpcMessage),
+	}
+}
+
+type peerKey struct{}
+
+// withPeer 将对端写入 context
+func withPeer(ctx context.Context, peer *Peer) context.Context {
+	return context.WithValue(ctx, peerKey{}, peer)
+}
+
+// PeerFromContext 获取发起 JSON-RPC 请求的 WebSocket 对端，不是经 WebSocket 的 JSON-RPC 请求时返回 nil
+func PeerFromContext(ctx context.Context) *Peer {
+	peer, _ := ctx.Value(peerKey{}).(*Peer)
+	return peer
+}
+
+// Call 调用客户端方法并等待响应，result 不为 nil 时将结果解码到 result；客户端返回的错误按 JSON-RPC 错误码还原为框架错误
+func (p *Peer) Call(ctx context.Context, method string, params, result interface{}) error {
+	p.mu.Lock()
+	p.nextID++
+	id := p.nextID
+	key := strconv.FormatInt(id, 10)
+	response := make(chan *rpcMessage, 1)
+	p.pending[key] = response
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, key)
+		p.mu.Unlock()
+	}()
+
+	if err := p.send(&jsonrpc.JsonRpcRequest{Jsonrpc: "2.0", Method: method, Params: params, Id: id}); err != nil {
+		return err
+	}
+	select {
+	case message := <-response:
+		if message.Error != nil {
+			return frameworkerrors.NewFrameworkErrorFromJSONRPCCode(message.Error.Code, message.Error.Message)
+		}
+		if result != nil && len(message.Result) > 0 {
+			if err := json.Unmarshal(message.Result, result); err != nil {
+				return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to decode call result")
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.conn.done:
+		return connectionClosed()
+	}
+}
+
+// Notify 向客户端发送通知，不等待响应
+func (p *Peer) Notify(method string, params interface{}) error {
+	return p.send(&jsonrpc.JsonRpcNotification{Jsonrpc: "2.0", Method: method, Params: params})
+}
+
+// Done 返回连接关闭时关闭的通道
+func (p *Peer) Done() <-chan struct{} {
+	return p.conn.done
+}
+
+// send 编码并投递消息，连接关闭后返回错误
+func (p *Peer) send(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.SerializationError, "failed to serialize JSON-RPC message")
+	}
+	if !p.conn.reply(textMessage, data) {
+		return connectionClosed()
+	}
+	return nil
+}
+
+// resolve 将客户端的响应交给等待中的服务端调用，没有对应的调用时丢弃
+func (p *Peer) resolve(message *rpcMessage) {
+	p.mu.Lock()
+	response, ok := p.pending[string(message.Id)]
+	p.mu.Unlock()
+	if ok {
+		select {
+		case response <- message:
+		default:
+		}
+	}
+}
+
+// acquire 占用一个并发请求名额，已达上限时返回 false
+func (p *Peer) acquire() bool {
+	select {
+	case p.calls <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release 释放并发请求名额
+func (p *Peer) release() {
+	<-p.calls
+}
+
+// connectionClosed 返回连接已关闭的错误
+func connectionClosed() error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.ConnectionError, "websocket connection closed")
+}
+
+// handleRPC 处理 JSON-RPC 2.0 消息
+//
+// 请求和批量请求在单独的协程中分发，读取循环不被阻塞，业务方法可以经 Peer 调用客户端并收到响应；
+// 并发处理的请求数超过上限时响应 ServiceUnavailable
+func (h *WebSocketProtocolHandler) handleRPC(ctx context.Context, peer *Peer, headers map[string]string, message []byte) {
+	trimmed := bytes.TrimSpace(message)
+	if trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			peer.send(rpcErrorResponse(nil, -32700, "Parse error", err.Error()))
+			return
+		}
+		if len(batch) == 0 {
+			peer.send(rpcErrorResponse(nil, -32600, "Invalid Request", "empty batch"))
+			return
+		}
+		if !peer.acquire() {
+			peer.send(overloaded(ctx, nil))
+			return
+		}
+		go func() {
+			defer peer.release()
+			h.handleBatch(ctx, peer, headers, batch)
+		}()
+		return
+	}
+
+	var request rpcMessage
+	if err := json.Unmarshal(trimmed, &request); err != nil {
+		peer.send(rpcErrorResponse(nil, -32700, "Parse error", err.Error()))
+		return
+	}
+	if request.Method == "" && (request.Result != nil || request.Error != nil) {
+		peer.resolve(&request)
+		return
+	}
+	if !peer.acquire() {
+		if request.Id != nil {
+			peer.send(overloaded(ctx, request.Id))
+		}
+		return
+	}
+	go func() {
+		defer peer.release()
+		if response := h.call(ctx, peer, headers, &request); response != nil {
+			peer.send(response)
+		}
+	}()
+}
+
+// handleBatch 处理批量请求中的各个消息，所有请求完成后以数组发送响应；全部为通知时不发送响应
+//
+// 各消息占用连接的并发请求名额并发处理，名额用尽时在批量请求自身占用的名额内依次处理
+func (h *WebSocketProtocolHandler) handleBatch(ctx context.Context, peer *Peer, headers map[string]string, batch []json.RawMessage) {
+	responses := make([]*jsonrpc.JsonRpcResponse, len(batch))
+	var wg sync.WaitGroup
+	for i, raw := range batch {
+		var request rpcMessage
+		if err := json.Unmarshal(raw, &request); err != nil {
+			responses[i] = rpcErrorResponse(nil, -32600, "Invalid Request", err.Error())
+			continue
+		}
+		if !peer.acquire() {
+			responses[i] = h.call(ctx, peer, headers, &request)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, request *rpcMessage) {
+			defer wg.Done()
+			defer peer.release()
+			responses[i] = h.call(ctx, peer, headers, request)
+		}(i, &request)
+	}
+	wg.Wait()
+
+	sent := make([]*jsonrpc.JsonRpcResponse, 0, len(responses))
+	for _, response := range responses {
+		if response != nil {
+			sent = append(sent, response)
+		}
+	}
+	if len(sent) > 0 {
+		peer.send(sent)
+	}
+}
+
+// call 以与 HTTP JSON-RPC 相同的方法名（service.method）分发请求，返回要发送的响应；
+// 通知和客户端对服务端调用的响应返回 nil
+func (h *WebSocketProtocolHandler) call(ctx context.Context, peer *Peer, headers map[string]string, request *rpcMessage) *jsonrpc.JsonRpcResponse {
+	if request.Method == "" && (request.Result != nil || request.Error != nil) {
+		peer.resolve(request)
+		return nil
+	}
+	if request.Jsonrpc != "2.0" {
+		return rpcErrorResponse(request.Id, -32600, "Invalid Request", "jsonrpc must be 2.0")
+	}
+	if request.Method == "" {
+		return rpcErrorResponse(request.Id, -32600, "Invalid Request", "method is required")
+	}
+
+	ctx = adapter.ExtractTraceContext(ctx, headers)
+	ctx, span := adapter.StartServerSpan(ctx, adapter.ProtocolWebSocket, "", request.Method)
+	result, err := h.dispatchRPC(withPeer(ctx, peer), headers, request)
+	if stream, ok := result.(*adapter.Stream); ok && err == nil {
+		result, err = sendStream(ctx, peer, request.Id, stream)
+	}
+	adapter.EndSpan(span, err)
+
+	if request.Id == nil {
+		return nil
+	}
+	if err != nil {
+		return &jsonrpc.JsonRpcResponse{Jsonrpc: "2.0", Id: request.Id, Error: jsonrpc.MethodError(ctx, err)}
+	}
+	return &jsonrpc.JsonRpcResponse{Jsonrpc: "2.0", Id: request.Id, Result: result}
+}
+
+// dispatchRPC 经协议适配器解析方法名中的服务和方法后调用本地业务方法
+func (h *WebSocketProtocolHandler) dispatchRPC(ctx context.Context, headers map[string]string, request *rpcMessage) (interface{}, error) {
+	internal, err := h.protocolAdapter.TransformRequest(ctx, &adapter.ExternalRequest{
+		Protocol: adapter.ProtocolJSONRPC,
+		Headers:  headers,
+		Body:     map[string]interface{}{"jsonrpc": request.Jsonrpc, "method": request.Method},
+	})
+	if err != nil {
+		return nil, err
+	}
+	internal.Payload = []byte(request.Params)
+	return h.config.Dispatcher(ctx, internal)
+}
+
+// sendStream 以 jsonrpc.ProgressMethod 通知逐项发送流式结果，返回发送完毕后响应的 jsonrpc.StreamResult
+func sendStream(ctx context.Context, peer *Peer, id json.RawMessage, stream *adapter.Stream) (interface{}, error) {
+	count := 0
+	err := stream.Each(ctx, func(item interface{}) error {
+		if err := peer.Notify(jsonrpc.ProgressMethod, jsonrpc.ProgressParams{Id: id, Value: item}); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &jsonrpc.StreamResult{Count: count}, nil
+}
+
+// rpcErrorResponse 构造 JSON-RPC 错误响应，id 为 nil 时响应的 id 为 null
+func rpcErrorResponse(id json.RawMessage, code int, message string, data interface{}) *jsonrpc.JsonRpcResponse {
+	return &jsonrpc.JsonRpcResponse{
+		Jsonrpc: "2.0",
+		Id:      id,
+		Error:   &jsonrpc.JsonRpcError{Code: code, Message: message, Data: data},
+	}
+}
+
+// overloaded 构造并发请求数超过上限时的错误响应
+func overloaded(ctx context.Context, id json.RawMessage) *jsonrpc.JsonRpcResponse {
+	err := frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "too many concurrent requests on this connection")
+	return &jsonrpc.JsonRpcResponse{Jsonrpc: "2.0", Id: id, Error: jsonrpc.MethodError(ctx, err)}
+}
@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/transport/memory"
+	"github.com/gogf/gf/v2/net/gclient"
+)
+
+// TestWebSocketJsonRpc 测试 JSON-RPC 2.0 请求、通知、批量请求和服务端发起的调用
+func TestWebSocketJsonRpc(t *testing.T) {
+	var notified atomic.Int32
+	listener := memory.Listen()
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Listener: listener,
+		Path:     "/ws",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			var params map[string]interface{}
+			json.Unmarshal(request.Payload, &params)
+			switch request.Service + "." + request.Method {
+			case "math.add":
+				return params["a"].(float64) + params["b"].(float64), nil
+			case "log.write":
+				notified.Add(1)
+				return nil, nil
+			case "order.confirm":
+				// 处理请求时回调客户端，读取循环不被阻塞
+				var answer string
+				if err := PeerFromContext(ctx).Call(ctx, "ui.confirm", params, &answer); err != nil {
+					return nil, err
+				}
+				return "user said " + answer, nil
+			}
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "method not found")
+		},
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start WebSocket handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	client := gclient.NewWebSocket()
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer conn.Close()
+
+	read := func(v interface{}) {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		if err := json.Unmarshal(message, v); err != nil {
+			t.Fatalf("invalid message: %s", message)
+		}
+	}
+
+	// 通知不响应，紧随其后的请求的响应是下一条消息；请求并发分发，通知的处理可能晚于后续请求完成
+	conn.WriteMessage(1, []byte(`{"jsonrpc":"2.0","method":"log.write","params":{"line":"x"}}`))
+	conn.WriteMessage(1, []byte(`{"jsonrpc":"2.0","method":"math.add","params":{"a":1,"b":2},"id":1}`))
+	var response map[string]interface{}
+	read(&response)
+	if response["id"] != float64(1) || response["result"] != float64(3) {
+		t.Fatalf("unexpected response: %v", response)
+	}
+	conn.WriteMessage(1, []byte(`{"jsonrpc":"2.0","method":"math.sub","id":"a"}`))
+	response = nil
+	read(&response)
+	rpcErr, _ := response["error"].(map[string]interface{})
+	if response["id"] != "a" || rpcErr["code"] != float64(frameworkerrors.NotFound.ToJSONRPCCode()) {
+		t.Errorf("expected method not found error, got %v", response)
+	}
+
+	// 服务端发起的调用：客户端收到请求后响应，服务端再响应客户端的原始请求
+	conn.WriteMessage(1, []byte(`{"jsonrpc":"2.0","method":"order.confirm","params":{"orderId":7},"id":2}`))
+	var call map[string]interface{}
+	read(&call)
+	if call["method"] != "ui.confirm" || call["id"] == nil {
+		t.Fatalf("expected server-initiated call, got %v", call)
+	}
+	reply, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": call["id"], "result": "yes"})
+	conn.WriteMessage(1, reply)
+	response = nil
+	read(&response)
+	if response["id"] != float64(2) || response["result"] != "user said yes" {
+		t.Errorf("unexpected response: %v", response)
+	}
+
+	// 批量请求的响应按请求顺序排列，不包括通知
+	conn.WriteMessage(1, []byte(`[
+		{"jsonrpc":"2.0","method":"math.add","params":{"a":1,"b":1},"id":10},
+		{"jsonrpc":"2.0","method":"log.write"},
+		{"jsonrpc":"1.0","method":"math.add","id":11}
+	]`))
+	var batch []map[string]interface{}
+	read(&batch)
+	if len(batch) != 2 || batch[0]["result"] != float64(2) || batch[1]["error"] == nil {
+		t.Errorf("unexpected batch response: %v", batch)
+	}
+
+	// 没有 jsonrpc 字段的消息仍按原有格式分发
+	conn.WriteMessage(1, []byte(`{"id":3,"service":"math","method":"add","params":{"a":2,"b":2}}`))
+	response = nil
+	read(&response)
+	if response["id"] != float64(3) || response["result"] != float64(4) || response["jsonrpc"] != nil {
+		t.Errorf("unexpected legacy response: %v", response)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for notified.Load() != 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if notified.Load() != 2 {
+		t.Errorf("expected 2 notifications to be dispatched, got %d", notified.Load())
+	}
+}
+
+// TestPeerCallConnectionClosed 测试连接关闭后服务端发起的调用立即失败
+func TestPeerCallConnectionClosed(t *testing.T) {
+	peer := &Peer{
+		conn:    &connection{send: make(chan outbound, 1), done: make(chan struct{})},
+		pending: make(map[string]chan *rpcMessage),
+	}
+	close(peer.conn.done)
+	err := peer.Call(context.Background(), "ui.confirm", nil, nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ConnectionError {
+		t.Errorf("expected connection error, got %v", err)
+	}
+}
+
+// TestWebSocketJsonRpcBatchConcurrency 测试批量请求中的请求不超过连接的并发请求数上限
+func TestWebSocketJsonRpcBatchConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	listener := memory.Listen()
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Listener:           listener,
+		Path:               "/ws",
+		MaxConcurrentCalls: 2,
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return "ok", nil
+		},
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start WebSocket handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	client := gclient.NewWebSocket()
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer conn.Close()
+
+	const items = 10
+	batch := make([]map[string]interface{}, items)
+	for i := range batch {
+		batch[i] = map[string]interface{}{"jsonrpc": "2.0", "method": "task.run", "id": i}
+	}
+	data, _ := json.Marshal(batch)
+	conn.WriteMessage(1, data)
+
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	var responses []map[string]interface{}
+	if err := json.Unmarshal(message, &responses); err != nil || len(responses) != items {
+		t.Fatalf("unexpected batch response: %s", message)
+	}
+	for i, response := range responses {
+		if response["id"] != float64(i) || response["result"] != "ok" {
+			t.Errorf("unexpected response %d: %v", i, response)
+		}
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Peak concurrency = %d, want at most 2", got)
+	}
+}
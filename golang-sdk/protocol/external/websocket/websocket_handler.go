@@ -4,7 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
 
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/dedup"
+	"github.com/framework/golang-sdk/session"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/glog"
@@ -12,8 +19,14 @@ import (
 
 // WebSocketProtocolHandler WebSocket 协议处理器
 type WebSocketProtocolHandler struct {
-	server *ghttp.Server
-	config *WebSocketConfig
+	server          *ghttp.Server
+	config          *WebSocketConfig
+	protocolAdapter *adapter.DefaultProtocolAdapter
+	// disabled 运行时停用，见 SetEnabled
+	disabled atomic.Bool
+	// conns 已建立的连接，停用时关闭
+	mu    sync.Mutex
+	conns map[*connection]struct{}
 }
 
 // WebSocketConfig WebSocket 配置
@@ -21,50 +34,179 @@ type WebSocketConfig struct {
 	Host string
 	Port int
 	Path string
+	// Server 与同端口的其他 HTTP 协议共用的服务器，不为 nil 时 Start 只注册路由，服务器由调用方启动和关闭
+	Server *ghttp.Server
+	// Listener 不为 nil 时在该监听器上提供服务并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器；
+	// 监听器的 Addr 须为 *net.TCPAddr
+	Listener net.Listener
+	// Dispatcher 本地业务方法分发器，不为 nil 时文本消息按 {"id", "service", "method", "params"} 调用业务方法，
+	// 响应为 {"id", "result"} 或 {"id", "error"}。
+	// 带 jsonrpc 字段的文本消息和批量请求按 JSON-RPC 2.0 处理：方法名与 HTTP JSON-RPC 相同（service.method），
+	// 请求并发分发，没有 id 的通知不响应，业务方法可以经 PeerFromContext 向客户端发起调用或发送通知
+	Dispatcher adapter.Dispatcher
+	// MaxConcurrentCalls 每个连接并发处理的 JSON-RPC 请求数上限（批量请求计为一个），超出时响应 ServiceUnavailable；
+	// 为 0 时使用 DefaultMaxConcurrentCalls
+	MaxConcurrentCalls int
+	// Dedup 已处理幂等键的存储，不为 nil 时带 idempotencyKey 字段的消息只分发一次，重复的消息响应 {"id", "duplicate": true}；
+	// 分发失败的消息不记录，客户端可以用相同的幂等键重试
+	Dedup dedup.Store
+	// Hub 主题订阅与广播，不为 nil 时文本消息 {"id", "action": "subscribe" | "unsubscribe", "topic"} 订阅或退订主题，
+	// 响应为 {"id", "result": {"topic", "subscribed"}}，广播消息以 {"topic", "data"} 推送；连接关闭时退订所有主题
+	Hub *Hub
+	// AuthorizeSubscribe 订阅主题前的授权检查，headers 为握手请求头，返回错误时拒绝订阅；为 nil 时允许订阅任意主题
+	AuthorizeSubscribe func(ctx context.Context, headers map[string]string, topic string) error
+	// Sessions 会话状态存储，不为 nil 时每个连接绑定一个会话，分发业务方法时以 session.WithSession 写入 context；
+	// 握手请求头 X-Session-Id 为有效的会话 ID 时沿用该会话（客户端重连到任一实例都能恢复状态），否则创建新会话。
+	// 文本消息 {"id", "action": "session"} 返回 {"id", "result": {"sessionId"}}
+	Sessions session.Store
 }
 
+// HeaderSessionID 握手请求中恢复会话的请求头
+const HeaderSessionID = "X-Session-Id"
+
 // NewWebSocketProtocolHandler 创建 WebSocket 协议处理器
 func NewWebSocketProtocolHandler(config *WebSocketConfig) *WebSocketProtocolHandler {
-	// 为每个handler创建独立的命名服务器实例
-	serverName := fmt.Sprintf("websocket-%s-%d", config.Host, config.Port)
-	server := g.Server(serverName)
+	server := config.Server
+	if server == nil {
+		// 为每个handler创建独立的命名服务器实例
+		serverName := fmt.Sprintf("websocket-%s-%d", config.Host, config.Port)
+		if config.Listener != nil {
+			serverName = fmt.Sprintf("websocket-%s", config.Listener.Addr())
+		}
+		server = g.Server(serverName)
+	}
 	return &WebSocketProtocolHandler{
-		server: server,
-		config: config,
+		server:          server,
+		config:          config,
+		protocolAdapter: adapter.NewDefaultProtocolAdapter(),
+		conns:           make(map[*connection]struct{}),
 	}
 }
 
 // Start 启动 WebSocket 服务器
 func (h *WebSocketProtocolHandler) Start() error {
-	// 配置服务器
-	h.server.SetAddr(fmt.Sprintf("%s:%d", h.config.Host, h.config.Port))
-	
 	// 注册 WebSocket 路由
 	h.server.BindHandler(h.config.Path, h.handleWebSocket)
 	
-	// 启动服务器
-	go h.server.Run()
+	// 共用的服务器由调用方启动
+	if h.config.Server != nil {
+		return nil
+	}
 	
-	return nil
+	// 配置并启动服务器
+	if h.config.Listener != nil {
+		if err := h.server.SetListener(h.config.Listener); err != nil {
+			return err
+		}
+	} else {
+		h.server.SetAddr(fmt.Sprintf("%s:%d", h.config.Host, h.config.Port))
+	}
+	// 监听成功后返回，端口被占用等错误返回给调用方；请求在服务器的协程中处理
+	return h.server.Start()
 }
 
-// Stop 停止 WebSocket 服务器
+// Stop 停止 WebSocket 服务器，共用的服务器由调用方关闭
 func (h *WebSocketProtocolHandler) Stop(ctx context.Context) error {
+	if h.config.Server != nil {
+		return nil
+	}
 	return h.server.Shutdown()
 }
 
+// SetEnabled 在运行时启用或停用协议，停用后握手请求返回 503，已建立的连接以状态码 1013（稍后重试）关闭；
+// 共用的服务器继续监听，同端口的其他协议不受影响
+func (h *WebSocketProtocolHandler) SetEnabled(enabled bool) {
+	h.disabled.Store(!enabled)
+	if enabled {
+		return
+	}
+	h.mu.Lock()
+	conns := make([]*connection, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+	// 关闭帧最多等待一秒写入，各连接并发关闭，慢连接不阻塞调用方
+	for _, conn := range conns {
+		go conn.shutdown(closeTryAgainLater, "protocol disabled")
+	}
+}
+
+// Enabled 返回协议是否启用
+func (h *WebSocketProtocolHandler) Enabled() bool {
+	return !h.disabled.Load()
+}
+
+// Connections 返回已建立的连接数
+func (h *WebSocketProtocolHandler) Connections() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// track 记录已建立的连接，返回的函数在连接关闭时移除；停用后建立的连接立即关闭
+func (h *WebSocketProtocolHandler) track(conn *connection) func() {
+	h.mu.Lock()
+	h.conns[conn] = struct{}{}
+	h.mu.Unlock()
+	// 与 SetEnabled 并发时，记录后再检查一次，避免停用时遗漏该连接
+	if h.disabled.Load() {
+		go conn.shutdown(closeTryAgainLater, "protocol disabled")
+	}
+	return func() {
+		h.mu.Lock()
+		delete(h.conns, conn)
+		h.mu.Unlock()
+	}
+}
+
 // handleWebSocket 处理 WebSocket 连接
 func (h *WebSocketProtocolHandler) handleWebSocket(r *ghttp.Request) {
+	if h.disabled.Load() {
+		r.Response.Header().Set("Connection", "close")
+		r.Response.WriteStatus(http.StatusServiceUnavailable, "WebSocket protocol is disabled")
+		return
+	}
 	ws, err := r.WebSocket()
 	if err != nil {
 		glog.Error(r.Context(), "WebSocket upgrade failed:", err)
 		r.Response.WriteStatus(500)
 		return
 	}
-	defer ws.Close()
+	// 响应和广播消息由连接的写协程发送
+	conn := newConnection(ws)
+	defer conn.close()
+	defer h.track(conn)()
+	if h.config.Hub != nil {
+		defer h.config.Hub.UnsubscribeAll(conn)
+	}
 	
 	glog.Info(r.Context(), "WebSocket connection established")
 	
+	// 握手请求头，分发业务方法时用于认证
+	headers := make(map[string]string)
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+	
+	// 连接关闭时取消处理中的 JSON-RPC 请求
+	connCtx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if h.config.Sessions != nil {
+		id := r.Header.Get(HeaderSessionID)
+		if !session.ValidID(id) {
+			id = session.NewID()
+		}
+		connCtx = session.WithSession(connCtx, session.New(h.config.Sessions, id))
+	}
+	
+	var peer *Peer
+	if h.config.Dispatcher != nil {
+		peer = newPeer(conn, h.config.MaxConcurrentCalls)
+	}
+	
 	// 持续读取消息
 	for {
 		// 读取消息（支持文本和二进制）
@@ -74,12 +216,27 @@ func (h *WebSocketProtocolHandler) handleWebSocket(r *ghttp.Request) {
 			break
 		}
 		
+		// JSON-RPC 消息异步处理，响应由处理协程发送
+		if peer != nil && msgType == textMessage && isRPC(message) {
+			h.handleRPC(connCtx, peer, headers, message)
+			continue
+		}
+		
 		// 处理消息
-		response := h.handleMessage(msgType, message)
+		ctx, span := adapter.StartServerSpan(connCtx, adapter.ProtocolWebSocket, "", r.URL.Path)
+		var response []byte
+		if control, ok := h.control(msgType, message); ok {
+			response = h.handleControl(ctx, conn, headers, control)
+		} else if h.config.Dispatcher != nil && msgType == 1 {
+			response = h.dispatch(ctx, headers, message)
+		} else {
+			response = h.handleMessage(msgType, message)
+		}
+		span.End()
 		
 		// 发送响应
-		if err := ws.WriteMessage(msgType, response); err != nil {
-			glog.Error(r.Context(), "WebSocket write error:", err)
+		if !conn.reply(msgType, response) {
+			glog.Error(r.Context(), "WebSocket write error: connection closed")
 			break
 		}
 	}
@@ -87,6 +244,128 @@ func (h *WebSocketProtocolHandler) handleWebSocket(r *ghttp.Request) {
 	glog.Info(r.Context(), "WebSocket connection closed")
 }
 
+// controlMessage 订阅、退订主题或查询会话的控制消息
+type controlMessage struct {
+	ID     interface{} `json:"id"`
+	Action string      `json:"action"`
+	Topic  string      `json:"topic"`
+}
+
+// control 解析已配置功能的控制消息：配置了 Hub 时为 subscribe 和 unsubscribe，配置了 Sessions 时为 session；
+// 不是控制消息时返回 false
+func (h *WebSocketProtocolHandler) control(msgType int, message []byte) (*controlMessage, bool) {
+	if msgType != 1 || (h.config.Hub == nil && h.config.Sessions == nil) {
+		return nil, false
+	}
+	var control controlMessage
+	if err := json.Unmarshal(message, &control); err != nil {
+		return nil, false
+	}
+	switch control.Action {
+	case "subscribe", "unsubscribe":
+		return &control, h.config.Hub != nil
+	case "session":
+		return &control, h.config.Sessions != nil
+	}
+	return nil, false
+}
+
+// handleControl 处理控制消息
+func (h *WebSocketProtocolHandler) handleControl(ctx context.Context, conn *connection, headers map[string]string, control *controlMessage) []byte {
+	var result interface{}
+	if control.Action == "session" {
+		sess, _ := session.FromContext(ctx)
+		result = map[string]interface{}{"sessionId": sess.ID()}
+	} else {
+		var err error
+		if result, err = h.subscribe(ctx, conn, headers, control); err != nil {
+			return h.errorMessage(ctx, control.ID, err)
+		}
+	}
+	response, _ := json.Marshal(map[string]interface{}{"id": control.ID, "result": result})
+	return response
+}
+
+// subscribe 订阅或退订主题，订阅前按 AuthorizeSubscribe 授权
+func (h *WebSocketProtocolHandler) subscribe(ctx context.Context, conn *connection, headers map[string]string, control *controlMessage) (interface{}, error) {
+	if control.Topic == "" {
+		return nil, &adapter.FrameworkError{
+			Code:    adapter.ErrorBadRequest,
+			Message: "topic is required",
+		}
+	}
+	
+	subscribed := control.Action == "subscribe"
+	if subscribed {
+		if h.config.AuthorizeSubscribe != nil {
+			if err := h.config.AuthorizeSubscribe(ctx, headers, control.Topic); err != nil {
+				return nil, err
+			}
+		}
+		if err := h.config.Hub.Subscribe(control.Topic, conn); err != nil {
+			return nil, err
+		}
+	} else {
+		h.config.Hub.Unsubscribe(control.Topic, conn)
+	}
+	return map[string]interface{}{"topic": control.Topic, "subscribed": subscribed}, nil
+}
+
+// dispatch 经协议适配器解析消息中的服务和方法后调用本地业务方法
+func (h *WebSocketProtocolHandler) dispatch(ctx context.Context, headers map[string]string, message []byte) []byte {
+	var body map[string]interface{}
+	if err := json.Unmarshal(message, &body); err != nil {
+		return h.errorMessage(ctx, nil, &adapter.FrameworkError{
+			Code:    adapter.ErrorBadRequest,
+			Message: fmt.Sprintf("invalid message: %v", err),
+			Cause:   err,
+		})
+	}
+	id := body["id"]
+	
+	internal, err := h.protocolAdapter.TransformRequest(ctx, &adapter.ExternalRequest{
+		Protocol: adapter.ProtocolWebSocket,
+		Headers:  headers,
+		Body:     body,
+	})
+	if err != nil {
+		return h.errorMessage(ctx, id, err)
+	}
+	if internal.Payload, err = json.Marshal(body["params"]); err != nil {
+		return h.errorMessage(ctx, id, err)
+	}
+	
+	key, _ := body[dedup.FieldIdempotencyKey].(string)
+	var result interface{}
+	duplicate, err := dedup.Do(h.config.Dedup, key, func() error {
+		var err error
+		result, err = h.config.Dispatcher(ctx, internal)
+		return err
+	})
+	if err != nil {
+		return h.errorMessage(ctx, id, err)
+	}
+	if duplicate {
+		response, _ := json.Marshal(map[string]interface{}{"id": id, "duplicate": true})
+		return response
+	}
+	
+	response, err := json.Marshal(map[string]interface{}{"id": id, "result": result})
+	if err != nil {
+		return h.errorMessage(ctx, id, err)
+	}
+	return response
+}
+
+// errorMessage 构造错误响应消息，error 为跨语言传输格式的结构化错误
+func (h *WebSocketProtocolHandler) errorMessage(ctx context.Context, id interface{}, err error) []byte {
+	response, _ := json.Marshal(map[string]interface{}{
+		"id":    id,
+		"error": adapter.NewErrorPayload(ctx, err),
+	})
+	return response
+}
+
 // handleMessage 处理 WebSocket 消息
 func (h *WebSocketProtocolHandler) handleMessage(msgType int, message []byte) []byte {
 	// 创建请求对象
@@ -2,10 +2,17 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/transport/memory"
+	"github.com/framework/golang-sdk/protocol/dedup"
+	"github.com/framework/golang-sdk/session"
 	"github.com/gogf/gf/v2/net/gclient"
+	"github.com/gorilla/websocket"
 )
 
 // TestWebSocketHandlerCreation 测试 WebSocket 处理器创建
@@ -24,10 +31,10 @@ func TestWebSocketHandlerCreation(t *testing.T) {
 
 // TestWebSocketHandlerStartStop 测试 WebSocket 处理器启动和停止
 func TestWebSocketHandlerStartStop(t *testing.T) {
+	listener := memory.Listen()
 	config := &WebSocketConfig{
-		Host: "127.0.0.1",
-		Port: 8092,
-		Path: "/ws",
+		Listener: listener,
+		Path:     "/ws",
 	}
 	
 	handler := NewWebSocketProtocolHandler(config)
@@ -38,9 +45,6 @@ func TestWebSocketHandlerStartStop(t *testing.T) {
 		t.Fatalf("Failed to start WebSocket handler: %v", err)
 	}
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 停止处理器
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -53,10 +57,10 @@ func TestWebSocketHandlerStartStop(t *testing.T) {
 
 // TestWebSocketTextMessage 测试文本消息
 func TestWebSocketTextMessage(t *testing.T) {
+	listener := memory.Listen()
 	config := &WebSocketConfig{
-		Host: "127.0.0.1",
-		Port: 8093,
-		Path: "/ws",
+		Listener: listener,
+		Path:     "/ws",
 	}
 	
 	handler := NewWebSocketProtocolHandler(config)
@@ -66,12 +70,10 @@ func TestWebSocketTextMessage(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 创建 WebSocket 客户端
 	client := gclient.NewWebSocket()
-	conn, _, err := client.Dial("ws://127.0.0.1:8093/ws", nil)
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
@@ -101,10 +103,10 @@ func TestWebSocketTextMessage(t *testing.T) {
 
 // TestWebSocketBinaryMessage 测试二进制消息
 func TestWebSocketBinaryMessage(t *testing.T) {
+	listener := memory.Listen()
 	config := &WebSocketConfig{
-		Host: "127.0.0.1",
-		Port: 8094,
-		Path: "/ws",
+		Listener: listener,
+		Path:     "/ws",
 	}
 	
 	handler := NewWebSocketProtocolHandler(config)
@@ -114,12 +116,10 @@ func TestWebSocketBinaryMessage(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 创建 WebSocket 客户端
 	client := gclient.NewWebSocket()
-	conn, _, err := client.Dial("ws://127.0.0.1:8094/ws", nil)
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
@@ -149,10 +149,10 @@ func TestWebSocketBinaryMessage(t *testing.T) {
 
 // TestWebSocketMultipleMessages 测试多条消息
 func TestWebSocketMultipleMessages(t *testing.T) {
+	listener := memory.Listen()
 	config := &WebSocketConfig{
-		Host: "127.0.0.1",
-		Port: 8095,
-		Path: "/ws",
+		Listener: listener,
+		Path:     "/ws",
 	}
 	
 	handler := NewWebSocketProtocolHandler(config)
@@ -162,12 +162,10 @@ func TestWebSocketMultipleMessages(t *testing.T) {
 	}
 	defer handler.Stop(context.Background())
 	
-	// 等待服务器启动
-	time.Sleep(500 * time.Millisecond)
-	
 	// 创建 WebSocket 客户端
 	client := gclient.NewWebSocket()
-	conn, _, err := client.Dial("ws://127.0.0.1:8095/ws", nil)
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
 	if err != nil {
 		t.Fatalf("Failed to connect to WebSocket: %v", err)
 	}
@@ -192,3 +190,234 @@ func TestWebSocketMultipleMessages(t *testing.T) {
 		}
 	}
 }
+
+// TestWebSocketDispatchDedup 测试带幂等键的消息只分发一次
+func TestWebSocketDispatchDedup(t *testing.T) {
+	calls := 0
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Host: "127.0.0.1",
+		Port: 8096,
+		Path: "/ws",
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			calls++
+			return calls, nil
+		},
+		Dedup: dedup.NewCache(10, time.Minute),
+	})
+	
+	tests := []struct {
+		name      string
+		message   string
+		duplicate bool
+		calls     int
+	}{
+		{"首次投递", `{"id":1,"service":"order","method":"create","idempotencyKey":"k-1"}`, false, 1},
+		{"重复投递", `{"id":2,"service":"order","method":"create","idempotencyKey":"k-1"}`, true, 1},
+		{"不同幂等键", `{"id":3,"service":"order","method":"create","idempotencyKey":"k-2"}`, false, 2},
+		{"没有幂等键", `{"id":4,"service":"order","method":"create"}`, false, 3},
+		{"没有幂等键再次投递", `{"id":5,"service":"order","method":"create"}`, false, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response map[string]interface{}
+			if err := json.Unmarshal(handler.dispatch(context.Background(), nil, []byte(tt.message)), &response); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if response["error"] != nil {
+				t.Fatalf("unexpected error: %v", response["error"])
+			}
+			if duplicate, _ := response["duplicate"].(bool); duplicate != tt.duplicate {
+				t.Errorf("expected duplicate=%v, got %v", tt.duplicate, response)
+			}
+			if calls != tt.calls {
+				t.Errorf("expected %d dispatcher calls, got %d", tt.calls, calls)
+			}
+		})
+	}
+}
+
+// TestWebSocketSubscription 测试订阅主题后收到广播消息
+func TestWebSocketSubscription(t *testing.T) {
+	hub := NewHub(nil)
+	defer hub.Close()
+	listener := memory.Listen()
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Listener: listener,
+		Path:     "/ws",
+		Hub:      hub,
+		AuthorizeSubscribe: func(ctx context.Context, headers map[string]string, topic string) error {
+			if topic == "admin" {
+				return &adapter.FrameworkError{Code: adapter.ErrorForbidden, Message: "access denied"}
+			}
+			return nil
+		},
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start WebSocket handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	client := gclient.NewWebSocket()
+	client.NetDialContext = memory.DialContext
+	conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer conn.Close()
+	
+	read := func() map[string]interface{} {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(message, &body); err != nil {
+			t.Fatalf("invalid message: %s", message)
+		}
+		return body
+	}
+	
+	conn.WriteMessage(1, []byte(`{"id":1,"action":"subscribe","topic":"admin"}`))
+	if response := read(); response["error"] == nil {
+		t.Errorf("expected unauthorized subscription to fail, got %v", response)
+	}
+	
+	conn.WriteMessage(1, []byte(`{"id":2,"action":"subscribe","topic":"orders"}`))
+	if response := read(); response["error"] != nil {
+		t.Fatalf("subscribe failed: %v", response["error"])
+	}
+	if hub.Subscribers("orders") != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", hub.Subscribers("orders"))
+	}
+	
+	if err := hub.Broadcast(context.Background(), "orders", map[string]int{"id": 7}); err != nil {
+		t.Fatalf("Broadcast failed: %v", err)
+	}
+	message := read()
+	if message["topic"] != "orders" {
+		t.Errorf("unexpected broadcast message: %v", message)
+	}
+	if data, _ := message["data"].(map[string]interface{}); data["id"] != float64(7) {
+		t.Errorf("unexpected broadcast data: %v", message["data"])
+	}
+	
+	conn.WriteMessage(1, []byte(`{"id":3,"action":"unsubscribe","topic":"orders"}`))
+	if response := read(); response["error"] != nil {
+		t.Fatalf("unsubscribe failed: %v", response["error"])
+	}
+	if hub.Subscribers("orders") != 0 {
+		t.Errorf("expected no subscribers, got %d", hub.Subscribers("orders"))
+	}
+}
+
+// TestWebSocketSession 测试重连时以 X-Session-Id 恢复会话状态
+func TestWebSocketSession(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Listener: listener,
+		Path:     "/ws",
+		Sessions: session.NewMemoryStore(time.Minute),
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			sess, ok := session.FromContext(ctx)
+			if !ok {
+				return nil, &adapter.FrameworkError{Code: adapter.ErrorInternal, Message: "no session"}
+			}
+			var count int
+			sess.GetJSON(ctx, "count", &count)
+			count++
+			return count, sess.SetJSON(ctx, "count", count)
+		},
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start WebSocket handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	call := func(header http.Header, messages ...string) []map[string]interface{} {
+		client := gclient.NewWebSocket()
+		client.NetDialContext = memory.DialContext
+		conn, _, err := client.Dial("ws://"+listener.Address()+"/ws", header)
+		if err != nil {
+			t.Fatalf("Failed to connect to WebSocket: %v", err)
+		}
+		defer conn.Close()
+		var responses []map[string]interface{}
+		for _, message := range messages {
+			conn.WriteMessage(1, []byte(message))
+			conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+			var response map[string]interface{}
+			json.Unmarshal(data, &response)
+			responses = append(responses, response)
+		}
+		return responses
+	}
+	
+	increment := `{"id":1,"service":"counter","method":"increment"}`
+	first := call(nil, increment, increment, `{"id":2,"action":"session"}`)
+	if first[1]["result"] != float64(2) {
+		t.Fatalf("expected count 2, got %v", first[1])
+	}
+	result, _ := first[2]["result"].(map[string]interface{})
+	sessionID, _ := result["sessionId"].(string)
+	if !session.ValidID(sessionID) {
+		t.Fatalf("unexpected session response: %v", first[2])
+	}
+	
+	// 带会话 ID 重连时沿用会话，不带时创建新会话
+	resumed := call(http.Header{HeaderSessionID: []string{sessionID}}, increment)
+	if resumed[0]["result"] != float64(3) {
+		t.Errorf("expected resumed count 3, got %v", resumed[0])
+	}
+	fresh := call(nil, increment)
+	if fresh[0]["result"] != float64(1) {
+		t.Errorf("expected new session count 1, got %v", fresh[0])
+	}
+}
+
+// TestWebSocketSetEnabled 测试运行时停用和启用协议
+func TestWebSocketSetEnabled(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewWebSocketProtocolHandler(&WebSocketConfig{
+		Listener: listener,
+		Path:     "/ws",
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start WebSocket handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	client := gclient.NewWebSocket()
+	client.NetDialContext = memory.DialContext
+	url := "ws://" + listener.Address() + "/ws"
+	conn, _, err := client.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(time.Second)
+	for handler.Connections() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 停用后已建立的连接以 1013 关闭，新的握手返回 503
+	handler.SetEnabled(false)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, 1013) {
+		t.Errorf("Expected close 1013 after disabling, got %v", err)
+	}
+	if _, resp, err := client.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 handshake while disabled, got %v", err)
+	}
+
+	handler.SetEnabled(true)
+	conn, _, err = client.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect after enabling: %v", err)
+	}
+	conn.Close()
+}
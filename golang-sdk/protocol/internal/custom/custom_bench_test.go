@@ -0,0 +1,89 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+)
+
+// benchmarkFrame 返回携带 size 字节帧体的 DATA 帧
+func benchmarkFrame(size int) *CustomFrame {
+	frame := dataFrame(ProtocolVersion, string(bytes.Repeat([]byte("x"), size)))
+	frame.Header.Sequence = 1
+	return frame
+}
+
+// readerConn 从内存缓冲区读取的连接，用于在不经过网络的情况下测试帧解码
+type readerConn struct {
+	net.Conn
+	reader *bytes.Reader
+}
+
+func (c *readerConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// BenchmarkEncodeFrame 帧编码的基准测试
+func BenchmarkEncodeFrame(b *testing.B) {
+	frame := benchmarkFrame(1024)
+	b.SetBytes(int64(frameHeaderSize + len(frame.Body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encodeFrame(frame)
+	}
+}
+
+// BenchmarkReadFrame 帧解码的基准测试
+func BenchmarkReadFrame(b *testing.B) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+	data := encodeFrame(benchmarkFrame(1024))
+	conn := &readerConn{reader: bytes.NewReader(data)}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn.reader.Reset(data)
+		if _, err := h.readFrame(conn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEnvelopeRoundTrip 调用信封编解码的基准测试
+func BenchmarkEnvelopeRoundTrip(b *testing.B) {
+	envelope := &Envelope{
+		Service:  "hello",
+		Method:   "sayHello",
+		Metadata: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "X-User-Id": "u1"},
+		Payload:  []byte(`{"name":"Go"}`),
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalEnvelope(envelope.Marshal()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCall 经 net.Pipe 完成一次调用的基准测试，包括帧编解码、信封编解码和分发
+func BenchmarkCall(b *testing.B) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{Dispatcher: echoDispatcher})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+	if _, err := client.Handshake(); err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	params := map[string]string{"name": "Go"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result map[string]interface{}
+		if err := client.Call(ctx, "hello", "sayHello", params, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
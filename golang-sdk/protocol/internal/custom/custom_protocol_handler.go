@@ -2,12 +2,20 @@ package custom
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/protocol/adapter"
 	"github.com/gogf/gf/v2/os/glog"
 )
 
@@ -24,6 +32,32 @@ type CustomProtocolHandler struct {
 type CustomProtocolConfig struct {
 	Host string
 	Port int
+	// Listener 不为 nil 时服务端在该监听器上接受连接并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器
+	Listener net.Listener
+	// Dialer 不为 nil 时客户端以它建立连接（如 memory.DialContext），为 nil 时使用 net.Dial
+	Dialer func(ctx context.Context, network, address string) (net.Conn, error)
+	// Features 声明支持的特性（如 FeatureCompression），握手后启用双方共同支持的特性
+	Features []string
+	// HandshakeTimeout 客户端等待 SETTINGS 确认的时间，为 0 时使用 DefaultHandshakeTimeout
+	HandshakeTimeout time.Duration
+	// KeepAliveInterval 连接空闲该时间后发送 PING，为 0 时不发送；只对协商到 KeepAliveVersion 及以上的连接生效，
+	// 客户端在 Handshake 成功后启动心跳
+	KeepAliveInterval time.Duration
+	// KeepAliveTimeout 发送 PING 后等待对端响应的时间，超时未收到任何帧时判定对端失效并关闭连接，
+	// 为 0 时使用 DefaultKeepAliveTimeout
+	KeepAliveTimeout time.Duration
+	// OnPeerDead 客户端判定对端失效并关闭连接后调用，可用于通知连接管理器重连
+	OnPeerDead func(err error)
+	// Dispatcher 本地业务方法分发器，不为 nil 时带 FlagEnvelope 的 DATA 帧按信封中的服务和方法调用业务方法，
+	// 不再交给 FrameTypeData 的处理器
+	Dispatcher adapter.Dispatcher
+	// TLSConfig 不为 nil 时服务端监听器和客户端连接使用 TLS；服务端传入服务端配置，客户端传入客户端配置
+	TLSConfig *tls.Config
+	// IdleTimeout 服务端关闭超过该时间没有收到请求帧（PING、PONG 不计）且没有打开的流的连接，为 0 时不限制
+	IdleTimeout time.Duration
+	// StreamIdleTimeout 服务端结束两个方向超过该时间没有帧的流，取消其 context 并以 Timeout 错误帧通知对端，为 0 时不限制；
+	// 只对协商了 FeatureStreaming 的连接生效
+	StreamIdleTimeout time.Duration
 }
 
 // MessageHandler 消息处理器
@@ -42,9 +76,18 @@ func NewCustomProtocolHandler(config *CustomProtocolConfig) *CustomProtocolHandl
 func (h *CustomProtocolHandler) Start() error {
 	address := fmt.Sprintf("%s:%d", h.config.Host, h.config.Port)
 	
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	listener := h.config.Listener
+	if listener != nil {
+		address = listener.Addr().String()
+	} else {
+		var err error
+		listener, err = lifecycle.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %v", address, err)
+		}
+	}
+	if h.config.TLSConfig != nil {
+		listener = tls.NewListener(listener, h.config.TLSConfig)
 	}
 	
 	h.listener = listener
@@ -101,46 +144,274 @@ func (h *CustomProtocolHandler) acceptConnections() {
 }
 
 // handleConnection 处理连接
+//
+// 第一个帧为 SETTINGS 时协商协议版本和特性，之后的帧版本不能高于协商的版本；
+// 第一个帧不是 SETTINGS 时视为不支持协商的对端，接受所有支持的版本但不启用任何特性。
+// 响应帧的版本不高于请求帧的版本，版本不受支持或没有共同版本时返回 ProtocolError 并关闭连接。
+// PING 帧直接以 PONG 帧响应；配置了 KeepAliveInterval 时，协商到 KeepAliveVersion 及以上的连接空闲后发送 PING，
+// KeepAliveTimeout 内没有收到任何帧时关闭连接。
+// 带截止时间的帧已过截止时间时不再处理，DATA 帧以 Timeout 错误帧响应；未过期的帧交给处理器的 context 带该截止时间。
+// 配置了 IdleTimeout 时关闭空闲的连接；协商了 FeatureStreaming 的连接按流跟踪 DATA 帧，流结束或空闲超过
+// StreamIdleTimeout 时取消交给处理器的 context，连接关闭时取消所有 context。
+// 处理器可通过 FrameWriterFromContext 在其他协程中向该连接并发发送帧
 func (h *CustomProtocolHandler) handleConnection(conn net.Conn) {
 	defer conn.Close()
 	
-	ctx := context.Background()
+	writer := NewFrameWriter(conn)
+	connCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := withFrameWriter(connCtx, writer)
+	writerCtx := ctx
+	streams := newStreamTracker(h.config.StreamIdleTimeout, func(streamId, version uint32) {
+		h.expireStream(writerCtx, writer, streamId, version)
+	})
+	defer streams.close()
+	writer.observe = streams.observe
+	var negotiated *Negotiated
+	maxVersion := ProtocolVersion
+	reader := &countingConn{Conn: conn}
+	pingSent := false
+	// pingDue 发送 PING 或判定对端失效的时间，收到帧或发送 PING 后重新计算
+	var pingDue time.Time
+	lastRequest := time.Now()
 	
 	for {
+		keepAlive := h.config.KeepAliveInterval > 0 && negotiated != nil && negotiated.Version >= KeepAliveVersion
+		var idleDue time.Time
+		if keepAlive && pingDue.IsZero() {
+			wait := h.config.KeepAliveInterval
+			if pingSent {
+				wait = keepAliveTimeout(h.config)
+			}
+			pingDue = time.Now().Add(wait)
+		}
+		if h.config.IdleTimeout > 0 {
+			idleDue = h.idleDue(streams, lastRequest)
+		}
+		if keepAlive || h.config.IdleTimeout > 0 {
+			conn.SetReadDeadline(earliest(pingDue, idleDue))
+		}
+		
 		// 读取帧
-		frame, err := h.readFrame(conn)
+		read := reader.n
+		frame, err := h.readFrame(reader)
 		if err != nil {
+			// 没有读到任何字节的超时为连接空闲，读取帧的中途超时无法恢复帧边界，直接关闭连接
+			if isTimeout(err) && reader.n == read {
+				now := time.Now()
+				if h.config.IdleTimeout > 0 && !now.Before(h.idleDue(streams, lastRequest)) {
+					glog.Infof(ctx, "Connection idle for more than %v, closing connection", h.config.IdleTimeout)
+					return
+				}
+				if keepAlive && !now.Before(pingDue) {
+					if pingSent {
+						glog.Errorf(ctx, "Peer did not respond to ping within %v, closing connection", keepAliveTimeout(h.config))
+						return
+					}
+					if err := writer.SendFrame(NewPingFrame(negotiated.Version)); err != nil {
+						glog.Errorf(ctx, "Failed to write ping: %v", err)
+						return
+					}
+					pingSent = true
+					pingDue = time.Time{}
+				}
+				continue
+			}
 			if err != io.EOF {
 				glog.Errorf(ctx, "Failed to read frame: %v", err)
 			}
 			return
 		}
+		pingSent = false
+		pingDue = time.Time{}
 		
-		// 查找处理器
-		h.mu.RLock()
-		handler, exists := h.handlers[frame.Header.Type.String()]
-		h.mu.RUnlock()
+		if negotiated == nil {
+			negotiated = legacyNegotiated
+			if frame.Header.Type == FrameTypeSettings && frame.Header.Flags&FlagAck == 0 {
+				if negotiated, err = h.negotiate(frame); err != nil {
+					h.reject(ctx, writer, frame.Header.StreamId, MinProtocolVersion, err)
+					return
+				}
+				maxVersion = negotiated.Version
+				ctx = withNegotiated(ctx, negotiated)
+				ack, err := NewSettingsFrame(&Settings{Versions: []uint32{negotiated.Version}, Features: negotiated.Features}, FlagAck)
+				if err == nil {
+					err = writer.SendFrame(ack)
+				}
+				if err != nil {
+					glog.Errorf(ctx, "Failed to write settings: %v", err)
+					return
+				}
+				continue
+			}
+			ctx = withNegotiated(ctx, negotiated)
+		}
+		if err := checkVersion(frame, maxVersion); err != nil {
+			h.reject(ctx, writer, frame.Header.StreamId, MinProtocolVersion, err)
+			return
+		}
 		
-		if !exists {
-			// 没有找到对应的处理器，跳过该帧
+		switch frame.Header.Type {
+		case FrameTypePing:
+			if err := writer.SendFrame(newPongFrame(frame)); err != nil {
+				glog.Errorf(ctx, "Failed to write pong: %v", err)
+				return
+			}
+			continue
+		case FrameTypePong:
 			continue
 		}
+		lastRequest = time.Now()
 		
-		// 调用处理器
-		response, err := handler(ctx, frame)
-		if err != nil {
-			glog.Errorf(ctx, "Handler error: %v", err)
+		// 已过截止时间的帧不再处理，等待响应的 DATA 帧以 Timeout 错误帧通知对端
+		deadline, hasDeadline := FrameDeadline(frame)
+		if hasDeadline && !time.Now().Before(deadline) {
+			if frame.Header.Type == FrameTypeData {
+				h.sendError(ctx, writer, frame, expiredError(frame, deadline))
+			}
 			continue
 		}
 		
-		// 发送响应
-		if response != nil {
-			if err := h.writeFrame(conn, response); err != nil {
-				glog.Errorf(ctx, "Failed to write response: %v", err)
-				return
+		// METADATA 帧携带的安全上下文和追踪上下文作用于该连接后续的所有帧
+		if frame.Header.Type == FrameTypeMetadata {
+			ctx = applyMetadata(ctx, frame.Body)
+		}
+		
+		frameCtx := ctx
+		if frame.Header.StreamId != 0 && negotiated.Supports(FeatureStreaming) {
+			switch {
+			case frame.Header.Type == FrameTypeData && !isEnvelopeFrame(frame):
+				frameCtx = streamContext{Context: streams.open(ctx, frame.Header.StreamId, frame.Header.Version), values: ctx}
+			case frame.Header.Type == FrameTypeClose || frame.Header.Type == FrameTypeError:
+				streams.end(frame.Header.StreamId)
+			}
+		}
+		cancelDeadline := func() {}
+		if hasDeadline {
+			frameCtx, cancelDeadline = context.WithDeadline(frameCtx, deadline)
+		}
+		ok := h.processFrame(ctx, frameCtx, writer, frame)
+		cancelDeadline()
+		if !ok {
+			return
+		}
+	}
+}
+
+// processFrame 以信封分发或交给帧类型的处理器，frameCtx 为交给处理器的 context；写入连接失败时返回 false
+func (h *CustomProtocolHandler) processFrame(ctx, frameCtx context.Context, writer *FrameWriter, frame *CustomFrame) bool {
+	if h.config.Dispatcher != nil && isEnvelopeFrame(frame) {
+		spanCtx, response, err := h.dispatchEnvelope(frameCtx, frame)
+		if err != nil {
+			glog.Errorf(ctx, "Dispatch error: %v", err)
+			if response, err = NewErrorFrame(spanCtx, frame.Header.StreamId, err); err != nil {
+				glog.Errorf(ctx, "Failed to create error frame: %v", err)
+				return true
 			}
+			response.Header.Version = frame.Header.Version
+		}
+		if err := writer.SendFrame(response); err != nil {
+			glog.Errorf(ctx, "Failed to write response: %v", err)
+			return false
+		}
+		return true
+	}
+	
+	// 查找处理器
+	h.mu.RLock()
+	handler, exists := h.handlers[frame.Header.Type.String()]
+	h.mu.RUnlock()
+	
+	if !exists {
+		// 没有找到对应的处理器，跳过该帧
+		return true
+	}
+	
+	// 调用处理器
+	spanCtx, span := adapter.StartServerSpan(frameCtx, adapter.ProtocolCustomBinary, "", frame.Header.Type.String())
+	response, err := handler(spanCtx, frame)
+	adapter.EndSpan(span, err)
+	if err != nil {
+		glog.Errorf(ctx, "Handler error: %v", err)
+		
+		// 以 ERROR 帧返回结构化错误
+		return h.sendError(spanCtx, writer, frame, err)
+	}
+	
+	// 发送响应
+	if response != nil {
+		if response.Header != nil && response.Header.Version > frame.Header.Version {
+			response.Header.Version = frame.Header.Version
+		}
+		if err := writer.SendFrame(response); err != nil {
+			glog.Errorf(ctx, "Failed to write response: %v", err)
+			return false
 		}
 	}
+	return true
+}
+
+// sendError 以与 frame 相同的流 ID 和版本发送 ERROR 帧，写入失败时返回 false
+func (h *CustomProtocolHandler) sendError(ctx context.Context, writer *FrameWriter, frame *CustomFrame, err error) bool {
+	errorFrame, frameErr := NewErrorFrame(ctx, frame.Header.StreamId, err)
+	if frameErr != nil {
+		glog.Errorf(ctx, "Failed to create error frame: %v", frameErr)
+		return true
+	}
+	errorFrame.Header.Version = frame.Header.Version
+	if err := writer.SendFrame(errorFrame); err != nil {
+		glog.Errorf(ctx, "Failed to write error frame: %v", err)
+		return false
+	}
+	return true
+}
+
+// expireStream 以 Timeout 错误帧通知对端流因空闲被结束，version 为流上第一个帧的版本
+func (h *CustomProtocolHandler) expireStream(ctx context.Context, writer *FrameWriter, streamId, version uint32) {
+	err := frameworkerrors.NewFrameworkError(frameworkerrors.Timeout,
+		fmt.Sprintf("stream %d idle for more than %v", streamId, h.config.StreamIdleTimeout))
+	h.sendError(ctx, writer, &CustomFrame{Header: &FrameHeader{StreamId: streamId, Version: version}}, err)
+}
+
+// idleDue 返回连接空闲超时的时间：最后一个请求帧或最后一个流结束之后 IdleTimeout；
+// 有打开的流时连接不算空闲，返回 IdleTimeout 之后再次检查的时间
+func (h *CustomProtocolHandler) idleDue(streams *streamTracker, lastRequest time.Time) time.Time {
+	since, active := streams.idleSince()
+	if active {
+		return time.Now().Add(h.config.IdleTimeout)
+	}
+	if since.Before(lastRequest) {
+		since = lastRequest
+	}
+	return since.Add(h.config.IdleTimeout)
+}
+
+// earliest 返回较早的非零时间，都为零时返回零值
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// negotiate 按本端支持的版本和特性响应客户端的 SETTINGS 帧
+func (h *CustomProtocolHandler) negotiate(frame *CustomFrame) (*Negotiated, error) {
+	settings, err := ParseSettingsFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+	return Negotiate(&Settings{Versions: SupportedVersions(), Features: h.config.Features}, settings)
+}
+
+// reject 以 ERROR 帧返回协议错误，调用方随后关闭连接
+func (h *CustomProtocolHandler) reject(ctx context.Context, writer *FrameWriter, streamId, version uint32, err error) {
+	glog.Errorf(ctx, "Protocol error: %v", err)
+	errorFrame, frameErr := NewErrorFrame(ctx, streamId, err)
+	if frameErr != nil {
+		return
+	}
+	errorFrame.Header.Version = version
+	writer.SendFrame(errorFrame)
 }
 
 // readFrame 读取帧
@@ -201,53 +472,20 @@ func (h *CustomProtocolHandler) readFrame(conn net.Conn) (*CustomFrame, error) {
 		return nil, err
 	}
 	
-	return &CustomFrame{
+	frame := &CustomFrame{
 		Header: header,
 		Body:   body,
-	}, nil
+	}
+	if err := splitDeadline(frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
 }
 
-// writeFrame 写入帧
+// writeFrame 写入帧，帧头和帧体编码后一次写入；同一连接的并发写入须通过 FrameWriter 串行化
 func (h *CustomProtocolHandler) writeFrame(conn net.Conn, frame *CustomFrame) error {
-	// 写入帧头
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.Magic); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.Version); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, uint32(frame.Header.Type)); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.Flags); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.StreamId); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.BodyLength); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.Sequence); err != nil {
-		return err
-	}
-	
-	if err := binary.Write(conn, binary.BigEndian, frame.Header.Timestamp); err != nil {
-		return err
-	}
-	
-	// 写入帧体
-	if _, err := conn.Write(frame.Body); err != nil {
-		return err
-	}
-	
-	return nil
+	_, err := conn.Write(encodeFrame(frame))
+	return err
 }
 
 // CustomFrame 自定义协议帧
@@ -266,6 +504,9 @@ type FrameHeader struct {
 	BodyLength uint32    // 帧体长度
 	Sequence   uint64    // 序列号
 	Timestamp  int64     // 时间戳
+	// Timeout 带 FlagDeadline 时的超时毫秒数，截止时间为 Timestamp 加 Timeout（见 SetFrameDeadline）；
+	// 编码在帧体前 4 字节，不计入 Body 和 BodyLength
+	Timeout uint32
 }
 
 // FrameType 帧类型
@@ -308,10 +549,100 @@ func (t FrameType) String() string {
 // MagicNumber 魔数
 const MagicNumber uint32 = 0x46524D57 // "FRMW"
 
+// ProtocolVersion 当前协议版本，版本 2 起支持以 SETTINGS 帧协商版本和特性
+const ProtocolVersion uint32 = 2
+
+// NewMetadataFrame 创建 METADATA 帧，帧体为 JSON 编码的键值对
+func NewMetadataFrame(streamId uint32, metadata map[string]string) (*CustomFrame, error) {
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %v", err)
+	}
+	
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ProtocolVersion,
+			Type:       FrameTypeMetadata,
+			StreamId:   streamId,
+			BodyLength: uint32(len(body)),
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: body,
+	}, nil
+}
+
+// NewErrorFrame 创建 ERROR 帧，帧体为 JSON 编码的跨语言错误传输格式
+func NewErrorFrame(ctx context.Context, streamId uint32, err error) (*CustomFrame, error) {
+	if err == nil {
+		return nil, fmt.Errorf("error cannot be nil")
+	}
+	
+	body, marshalErr := json.Marshal(adapter.NewErrorPayload(ctx, err))
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal error: %v", marshalErr)
+	}
+	
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ProtocolVersion,
+			Type:       FrameTypeError,
+			StreamId:   streamId,
+			BodyLength: uint32(len(body)),
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: body,
+	}, nil
+}
+
+// ParseErrorFrame 从 ERROR 帧还原框架错误
+func ParseErrorFrame(frame *CustomFrame) (*frameworkerrors.FrameworkError, error) {
+	if frame == nil || frame.Header == nil || frame.Header.Type != FrameTypeError {
+		return nil, fmt.Errorf("not an error frame")
+	}
+	return frameworkerrors.UnmarshalError(frame.Body)
+}
+
+// applyMetadata 将 METADATA 帧中的安全上下文和追踪上下文写入 context
+func applyMetadata(ctx context.Context, body []byte) context.Context {
+	var metadata map[string]string
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return ctx
+	}
+	return applyHeaders(ctx, metadata)
+}
+
+// applyHeaders 将请求头中的安全上下文和追踪上下文写入 context
+func applyHeaders(ctx context.Context, metadata map[string]string) context.Context {
+	if len(metadata) == 0 {
+		return ctx
+	}
+	if sc := adapter.SecurityContextFromHeaders(metadata); sc != nil {
+		ctx = adapter.WithSecurityContext(ctx, sc)
+	}
+	return adapter.ExtractTraceContext(ctx, metadata)
+}
+
 // CustomProtocolClient 自定义协议客户端
 type CustomProtocolClient struct {
-	conn   net.Conn
-	config *CustomProtocolConfig
+	conn       net.Conn
+	config     *CustomProtocolConfig
+	negotiated *Negotiated
+	writeMu    sync.Mutex
+	closeOnce  sync.Once
+	closed     atomic.Bool
+	
+	// Call 依次进行，每次调用使用新的流 ID
+	callMu       sync.Mutex
+	nextStreamId atomic.Uint32
+	
+	// 心跳启动后由读取协程接收帧
+	frames       chan *CustomFrame
+	readErr      error
+	done         chan struct{}
+	lastReceived atomic.Int64
+	dead         atomic.Bool
 }
 
 // NewCustomProtocolClient 创建自定义协议客户端
@@ -323,9 +654,21 @@ func NewCustomProtocolClient(config *CustomProtocolConfig) *CustomProtocolClient
 
 // Connect 连接到服务器
 func (c *CustomProtocolClient) Connect() error {
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
-	
-	conn, err := net.Dial("tcp", address)
+	address := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	
+	var conn net.Conn
+	var err error
+	switch {
+	case c.config.Dialer != nil:
+		conn, err = c.config.Dialer(context.Background(), "tcp", address)
+		if err == nil && c.config.TLSConfig != nil {
+			conn = tls.Client(conn, clientTLSConfig(c.config.TLSConfig, c.config.Host))
+		}
+	case c.config.TLSConfig != nil:
+		conn, err = tls.Dial("tcp", address, c.config.TLSConfig)
+	default:
+		conn, err = net.Dial("tcp", address)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to %s: %v", address, err)
 	}
@@ -334,30 +677,172 @@ func (c *CustomProtocolClient) Connect() error {
 	return nil
 }
 
+// Handshake 发送 SETTINGS 帧协商协议版本和特性，须在连接后、发送其他帧之前调用
+//
+// 服务端在 HandshakeTimeout 内没有确认时视为不支持协商的版本 1 服务端，降级为版本 1 且不启用任何特性；
+// 没有共同支持的版本时返回服务端的 ProtocolError。配置了 KeepAliveInterval 且协商到 KeepAliveVersion 及以上时启动心跳
+func (c *CustomProtocolClient) Handshake() (*Negotiated, error) {
+	if c.conn == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+	
+	frame, err := NewSettingsFrame(&Settings{Versions: SupportedVersions(), Features: c.config.Features}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SendFrame(frame); err != nil {
+		return nil, err
+	}
+	
+	timeout := c.config.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = DefaultHandshakeTimeout
+	}
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	reply, err := c.ReceiveFrame()
+	c.conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		if isTimeout(err) {
+			c.negotiated = legacyNegotiated
+			return c.negotiated, nil
+		}
+		return nil, err
+	}
+	
+	switch {
+	case reply.Header.Type == FrameTypeError:
+		fe, err := ParseErrorFrame(reply)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fe
+	case reply.Header.Type == FrameTypeSettings && reply.Header.Flags&FlagAck != 0:
+		settings, err := ParseSettingsFrame(reply)
+		if err != nil {
+			return nil, err
+		}
+		if len(settings.Versions) != 1 || !containsVersion(SupportedVersions(), settings.Versions[0]) {
+			return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError,
+				fmt.Sprintf("server selected unsupported protocol versions %v", settings.Versions))
+		}
+		c.negotiated = &Negotiated{Version: settings.Versions[0], Features: settings.Features}
+		if c.config.KeepAliveInterval > 0 && c.negotiated.Version >= KeepAliveVersion {
+			c.startKeepAlive()
+		}
+		return c.negotiated, nil
+	default:
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError,
+			fmt.Sprintf("unexpected %s frame during handshake", reply.Header.Type))
+	}
+}
+
+// Negotiated 返回 Handshake 的协商结果，未握手时为 nil
+func (c *CustomProtocolClient) Negotiated() *Negotiated {
+	return c.negotiated
+}
+
 // Close 关闭连接
 func (c *CustomProtocolClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+	if c.conn == nil {
+		return nil
 	}
-	return nil
+	var err error
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		if c.done != nil {
+			close(c.done)
+		}
+		err = c.conn.Close()
+	})
+	return err
 }
 
-// SendFrame 发送帧
+// SendFrame 发送帧，握手后帧版本高于协商的版本时降为协商的版本，未协商 FeatureDeadline 时去掉帧的截止时间，
+// 可在多个协程中并发调用
 func (c *CustomProtocolClient) SendFrame(frame *CustomFrame) error {
 	if c.conn == nil {
 		return fmt.Errorf("client not connected")
 	}
+	if c.negotiated != nil && frame.Header.Version > c.negotiated.Version {
+		frame.Header.Version = c.negotiated.Version
+	}
+	if frame.Header.Flags&FlagDeadline != 0 && !c.negotiated.Supports(FeatureDeadline) {
+		SetFrameDeadline(frame, time.Time{})
+	}
 	
-	handler := &CustomProtocolHandler{}
-	return handler.writeFrame(c.conn, frame)
+	data := encodeFrame(frame)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(data)
+	return err
+}
+
+// SendSecurityContext 通过 METADATA 帧转发 context 中的安全上下文
+func (c *CustomProtocolClient) SendSecurityContext(ctx context.Context) error {
+	sc := adapter.SecurityContextFromContext(ctx)
+	if sc == nil {
+		return nil
+	}
+	
+	frame, err := NewMetadataFrame(0, sc.ToHeaders())
+	if err != nil {
+		return err
+	}
+	return c.SendFrame(frame)
+}
+
+// SendTraceContext 通过 METADATA 帧转发 context 中的 W3C 追踪上下文
+func (c *CustomProtocolClient) SendTraceContext(ctx context.Context) error {
+	metadata := make(map[string]string)
+	adapter.InjectTraceContext(ctx, metadata)
+	if len(metadata) == 0 {
+		return nil
+	}
+	
+	frame, err := NewMetadataFrame(0, metadata)
+	if err != nil {
+		return err
+	}
+	return c.SendFrame(frame)
 }
 
-// ReceiveFrame 接收帧
+// ReceiveFrame 接收帧，自动响应服务端的 PING 并跳过 PONG；心跳判定对端失效后返回 ErrPeerDead
 func (c *CustomProtocolClient) ReceiveFrame() (*CustomFrame, error) {
 	if c.conn == nil {
 		return nil, fmt.Errorf("client not connected")
 	}
+	if c.frames != nil {
+		frame, ok := <-c.frames
+		if !ok {
+			return nil, c.readErr
+		}
+		return frame, nil
+	}
 	
 	handler := &CustomProtocolHandler{}
-	return handler.readFrame(c.conn)
+	for {
+		frame, err := handler.readFrame(c.conn)
+		if err != nil {
+			return nil, err
+		}
+		switch frame.Header.Type {
+		case FrameTypePing:
+			if err := c.SendFrame(newPongFrame(frame)); err != nil {
+				return nil, err
+			}
+		case FrameTypePong:
+		default:
+			return frame, nil
+		}
+	}
+}
+
+// clientTLSConfig 返回在 Dialer 建立的连接上握手使用的 TLS 配置，未设置 ServerName 时以 host 校验证书，与 tls.Dial 相同
+func clientTLSConfig(config *tls.Config, host string) *tls.Config {
+	if config.ServerName != "" {
+		return config
+	}
+	config = config.Clone()
+	config.ServerName = host
+	return config
 }
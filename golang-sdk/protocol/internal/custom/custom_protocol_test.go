@@ -4,6 +4,10 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TestCustomProtocolHandlerCreation 测试自定义协议处理器创建
@@ -214,3 +218,161 @@ func TestCustomProtocolClientServerCommunication(t *testing.T) {
 		t.Errorf("Expected frame type DATA, got %s", recvFrame.Header.Type)
 	}
 }
+
+// TestCustomProtocolSecurityContextPropagation 测试通过 METADATA 帧传递安全上下文
+func TestCustomProtocolSecurityContextPropagation(t *testing.T) {
+	config := &CustomProtocolConfig{
+		Host: "127.0.0.1",
+		Port: 11006,
+	}
+	
+	handler := NewCustomProtocolHandler(config)
+	
+	// 回显调用方用户 ID
+	handler.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		userID := ""
+		if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+			userID = sc.UserID
+		}
+		frame.Body = []byte(userID)
+		frame.Header.BodyLength = uint32(len(frame.Body))
+		return frame, nil
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	client := NewCustomProtocolClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	ctx := adapter.WithSecurityContext(context.Background(), &adapter.SecurityContext{UserID: "user-1"})
+	if err := client.SendSecurityContext(ctx); err != nil {
+		t.Fatalf("Failed to send security context: %v", err)
+	}
+	
+	err := client.SendFrame(&CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ProtocolVersion,
+			Type:       FrameTypeData,
+			StreamId:   1,
+			BodyLength: 4,
+			Sequence:   1,
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: []byte("ping"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to send frame: %v", err)
+	}
+	
+	recvFrame, err := client.ReceiveFrame()
+	if err != nil {
+		t.Fatalf("Failed to receive frame: %v", err)
+	}
+	
+	if string(recvFrame.Body) != "user-1" {
+		t.Errorf("Expected 'user-1', got '%s'", recvFrame.Body)
+	}
+}
+
+// TestApplyMetadataTraceContext 测试 METADATA 帧中的追踪上下文解析
+func TestApplyMetadataTraceContext(t *testing.T) {
+	frame, err := NewMetadataFrame(0, map[string]string{
+		adapter.HeaderTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		adapter.HeaderAuthUserID:  "user-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create metadata frame: %v", err)
+	}
+	
+	ctx := applyMetadata(context.Background(), frame.Body)
+	
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Unexpected trace ID: %s", sc.TraceID())
+	}
+	
+	if security := adapter.SecurityContextFromContext(ctx); security == nil || security.UserID != "user-1" {
+		t.Errorf("Unexpected security context: %+v", security)
+	}
+}
+
+// TestHandlerErrorFrame 测试处理器返回错误时客户端收到结构化的 ERROR 帧
+func TestHandlerErrorFrame(t *testing.T) {
+	config := &CustomProtocolConfig{
+		Host: "127.0.0.1",
+		Port: 11007,
+	}
+	
+	handler := NewCustomProtocolHandler(config)
+	handler.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "user not found").WithDetail("userId", "42")
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	client := NewCustomProtocolClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	err := client.SendFrame(&CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ProtocolVersion,
+			Type:       FrameTypeData,
+			StreamId:   3,
+			BodyLength: 4,
+			Sequence:   1,
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: []byte("ping"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to send frame: %v", err)
+	}
+	
+	recvFrame, err := client.ReceiveFrame()
+	if err != nil {
+		t.Fatalf("Failed to receive frame: %v", err)
+	}
+	if recvFrame.Header.Type != FrameTypeError || recvFrame.Header.StreamId != 3 {
+		t.Fatalf("Expected ERROR frame on stream 3, got %s on stream %d", recvFrame.Header.Type, recvFrame.Header.StreamId)
+	}
+	
+	fe, err := ParseErrorFrame(recvFrame)
+	if err != nil {
+		t.Fatalf("Failed to parse error frame: %v", err)
+	}
+	if fe.Code != frameworkerrors.NotFound || fe.Message != "user not found" || fe.Fields["userId"] != "42" {
+		t.Errorf("Unexpected error: %+v", fe)
+	}
+}
+
+// TestParseErrorFrameInvalid 测试非 ERROR 帧的解析
+func TestParseErrorFrameInvalid(t *testing.T) {
+	frame, err := NewMetadataFrame(0, map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("Failed to create metadata frame: %v", err)
+	}
+	if _, err := ParseErrorFrame(frame); err == nil {
+		t.Error("Expected error for non-error frame")
+	}
+	if _, err := NewErrorFrame(context.Background(), 1, nil); err == nil {
+		t.Error("Expected error for nil error")
+	}
+}
@@ -0,0 +1,214 @@
+package custom
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// FlagDeadline 帧带截止时间的标志，帧体前 4 字节为大端编码的超时毫秒数，截止时间为帧头 Timestamp 加该超时；
+// 只能发给协商了 FeatureDeadline 的对端
+const FlagDeadline uint32 = 0x4
+
+// deadlineSize 帧体中超时字段的长度
+const deadlineSize = 4
+
+// SetFrameDeadline 为帧设置截止时间，deadline 为零值时清除；帧头 Timestamp 为 0 时设为当前时间
+//
+// 截止时间以发送方的时钟计算，接收方按自身时钟判断是否已过，双方时钟须同步（如 NTP）
+func SetFrameDeadline(frame *CustomFrame, deadline time.Time) {
+	if deadline.IsZero() {
+		frame.Header.Flags &^= FlagDeadline
+		frame.Header.Timeout = 0
+		return
+	}
+	if frame.Header.Timestamp == 0 {
+		frame.Header.Timestamp = time.Now().UnixMilli()
+	}
+	timeout := deadline.UnixMilli() - frame.Header.Timestamp
+	if timeout < 1 {
+		timeout = 1 // 0 表示没有截止时间，已过期的截止时间编码为 1 毫秒
+	}
+	if timeout > int64(^uint32(0)) {
+		timeout = int64(^uint32(0))
+	}
+	frame.Header.Flags |= FlagDeadline
+	frame.Header.Timeout = uint32(timeout)
+}
+
+// FrameDeadline 返回帧的截止时间，帧没有截止时间时 ok 为 false
+func FrameDeadline(frame *CustomFrame) (deadline time.Time, ok bool) {
+	if frame.Header.Flags&FlagDeadline == 0 || frame.Header.Timeout == 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(frame.Header.Timestamp).Add(time.Duration(frame.Header.Timeout) * time.Millisecond), true
+}
+
+// splitDeadline 从带 FlagDeadline 的帧体中取出超时字段，Body 和 BodyLength 只保留其余部分
+func splitDeadline(frame *CustomFrame) error {
+	if frame.Header.Flags&FlagDeadline == 0 {
+		return nil
+	}
+	if len(frame.Body) < deadlineSize {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError,
+			fmt.Sprintf("%s frame with deadline flag has %d-byte body", frame.Header.Type, len(frame.Body)))
+	}
+	frame.Header.Timeout = binary.BigEndian.Uint32(frame.Body)
+	frame.Body = frame.Body[deadlineSize:]
+	frame.Header.BodyLength = uint32(len(frame.Body))
+	return nil
+}
+
+// expiredError 返回截止时间已过的请求的错误
+func expiredError(frame *CustomFrame, deadline time.Time) error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.Timeout,
+		fmt.Sprintf("%s frame on stream %d expired %v ago", frame.Header.Type, frame.Header.StreamId, time.Since(deadline).Round(time.Millisecond)))
+}
+
+// streamTracker 跟踪连接上流式传输的流，流结束或空闲超时时取消流的 context，释放处理器为该流占用的资源
+//
+// 流在收到第一个帧时打开，收到或发送 CLOSE、ERROR 帧时结束；idle 大于 0 时，两个方向都没有帧的时间超过 idle 的流
+// 被结束并以 Timeout 错误帧通知对端
+type streamTracker struct {
+	idle     time.Duration
+	onExpire func(streamId, version uint32)
+
+	mu      sync.Mutex
+	streams map[uint32]*trackedStream
+	// lastEnded 最近一个流结束的时间，连接空闲时间从所有流结束后开始计算
+	lastEnded time.Time
+	closed    bool
+}
+
+// trackedStream 一个打开的流
+type trackedStream struct {
+	version    uint32
+	ctx        context.Context
+	cancel     context.CancelFunc
+	lastActive time.Time
+	timer      *time.Timer
+}
+
+// newStreamTracker 创建流跟踪器，onExpire 在流空闲超时后以流 ID 和流上第一个帧的版本调用
+func newStreamTracker(idle time.Duration, onExpire func(streamId, version uint32)) *streamTracker {
+	return &streamTracker{
+		idle:     idle,
+		onExpire: onExpire,
+		streams:  make(map[uint32]*trackedStream),
+	}
+}
+
+// open 返回流的 context，流未打开时以 parent 打开；连接已关闭时返回已取消的 context
+func (t *streamTracker) open(parent context.Context, streamId, version uint32) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if stream, ok := t.streams[streamId]; ok {
+		stream.lastActive = time.Now()
+		return stream.ctx
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	if t.closed {
+		cancel()
+		return ctx
+	}
+	stream := &trackedStream{version: version, ctx: ctx, cancel: cancel, lastActive: time.Now()}
+	if t.idle > 0 {
+		stream.timer = time.AfterFunc(t.idle, func() { t.checkIdle(streamId, stream) })
+	}
+	t.streams[streamId] = stream
+	return ctx
+}
+
+// observe 记录流上收发的帧：CLOSE 和 ERROR 帧结束流，其他帧刷新流的空闲时间
+func (t *streamTracker) observe(frame *CustomFrame) {
+	switch frame.Header.Type {
+	case FrameTypeClose, FrameTypeError:
+		t.end(frame.Header.StreamId)
+	default:
+		t.mu.Lock()
+		if stream, ok := t.streams[frame.Header.StreamId]; ok {
+			stream.lastActive = time.Now()
+		}
+		t.mu.Unlock()
+	}
+}
+
+// end 结束流并取消其 context
+func (t *streamTracker) end(streamId uint32) {
+	t.mu.Lock()
+	stream, ok := t.streams[streamId]
+	if ok {
+		delete(t.streams, streamId)
+		t.lastEnded = time.Now()
+	}
+	t.mu.Unlock()
+	if ok {
+		stream.stop()
+	}
+}
+
+// checkIdle 流的空闲计时到期：期间有帧时按剩余时间重新计时，否则结束流并通知对端
+func (t *streamTracker) checkIdle(streamId uint32, stream *trackedStream) {
+	t.mu.Lock()
+	if t.streams[streamId] != stream {
+		t.mu.Unlock()
+		return
+	}
+	if idle := time.Since(stream.lastActive); idle < t.idle {
+		stream.timer.Reset(t.idle - idle)
+		t.mu.Unlock()
+		return
+	}
+	delete(t.streams, streamId)
+	t.lastEnded = time.Now()
+	t.mu.Unlock()
+
+	stream.stop()
+	if t.onExpire != nil {
+		t.onExpire(streamId, stream.version)
+	}
+}
+
+// idleSince 返回连接上没有打开的流的起始时间，有打开的流时 active 为 true
+func (t *streamTracker) idleSince() (since time.Time, active bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastEnded, len(t.streams) > 0
+}
+
+// close 连接关闭时结束所有流
+func (t *streamTracker) close() {
+	t.mu.Lock()
+	streams := t.streams
+	t.streams = make(map[uint32]*trackedStream)
+	t.closed = true
+	t.mu.Unlock()
+	for _, stream := range streams {
+		stream.stop()
+	}
+}
+
+// stop 停止计时并取消流的 context
+func (s *trackedStream) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.cancel()
+}
+
+// streamContext 流上的帧交给处理器的 context：取消和截止时间来自流，值（安全上下文、追踪上下文等）来自连接，
+// 流打开后收到的 METADATA 帧同样作用于该流后续的帧
+type streamContext struct {
+	context.Context
+	values context.Context
+}
+
+// Value 从连接的 context 取值
+func (c streamContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
@@ -0,0 +1,141 @@
+package custom
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+func TestFrameDeadlineRoundTrip(t *testing.T) {
+	frame := dataFrame(ProtocolVersion, "hi")
+	frame.Header.Timestamp = 1_000
+	SetFrameDeadline(frame, time.UnixMilli(1_250))
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	go clientConn.Write(encodeFrame(frame))
+
+	decoded, err := (&CustomProtocolHandler{}).readFrame(serverConn)
+	if err != nil {
+		t.Fatalf("readFrame failed: %v", err)
+	}
+	if string(decoded.Body) != "hi" || decoded.Header.BodyLength != 2 || decoded.Header.Timeout != 250 {
+		t.Fatalf("decoded frame = %+v %q", decoded.Header, decoded.Body)
+	}
+	if deadline, ok := FrameDeadline(decoded); !ok || !deadline.Equal(time.UnixMilli(1_250)) {
+		t.Errorf("FrameDeadline = %v, %v", deadline, ok)
+	}
+
+	SetFrameDeadline(frame, time.Time{})
+	if _, ok := FrameDeadline(frame); ok || frame.Header.Flags&FlagDeadline != 0 {
+		t.Errorf("expected deadline to be cleared, got %+v", frame.Header)
+	}
+}
+
+func TestExpiredFrameDropped(t *testing.T) {
+	calls := 0
+	var seen time.Time
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{
+		Features: []string{FeatureDeadline},
+		Dispatcher: func(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+			calls++
+			seen, _ = ctx.Deadline()
+			return "ok", nil
+		},
+	})
+	client := pipeClient(h, &CustomProtocolConfig{Features: []string{FeatureDeadline}})
+	defer client.Close()
+	if negotiated, err := client.Handshake(); err != nil || !negotiated.Supports(FeatureDeadline) {
+		t.Fatalf("Handshake = %v, %v", negotiated, err)
+	}
+
+	// 截止时间已过的请求不调用业务方法，以 Timeout 错误帧响应
+	expired := NewEnvelopeFrame(9, &Envelope{Service: "hello", Method: "sayHello"})
+	expired.Header.Timestamp = time.Now().Add(-time.Second).UnixMilli()
+	SetFrameDeadline(expired, time.Now().Add(-500*time.Millisecond))
+	client.SendFrame(expired)
+	response, err := client.ReceiveFrame()
+	if err != nil || response.Header.Type != FrameTypeError || response.Header.StreamId != 9 {
+		t.Fatalf("expected error frame, got %v %v", response, err)
+	}
+	if fe, _ := ParseErrorFrame(response); fe == nil || fe.Code != frameworkerrors.Timeout {
+		t.Errorf("expected Timeout, got %v", fe)
+	}
+	if calls != 0 {
+		t.Errorf("expected expired request not to be dispatched, got %d calls", calls)
+	}
+
+	// 未过期的请求带截止时间调用业务方法
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	if err := client.Call(ctx, "hello", "sayHello", nil, nil); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if calls != 1 || seen.IsZero() || seen.Sub(want).Abs() > time.Millisecond {
+		t.Errorf("dispatcher deadline = %v, want %v", seen, want)
+	}
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	streamDone := make(chan error, 2)
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{
+		Features:          []string{FeatureStreaming},
+		StreamIdleTimeout: 50 * time.Millisecond,
+	})
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		// 模拟为流推送数据的协程，流结束后退出
+		go func() {
+			<-ctx.Done()
+			streamDone <- ctx.Err()
+		}()
+		return nil, nil
+	})
+	client := pipeClient(h, &CustomProtocolConfig{Features: []string{FeatureStreaming}})
+	defer client.Close()
+	if _, err := client.Handshake(); err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+
+	// 空闲的流被结束并以 Timeout 错误帧通知
+	client.SendFrame(dataFrame(ProtocolVersion, "open"))
+	response, err := client.ReceiveFrame()
+	if err != nil || response.Header.Type != FrameTypeError || response.Header.StreamId != 1 {
+		t.Fatalf("expected error frame, got %v %v", response, err)
+	}
+	if fe, _ := ParseErrorFrame(response); fe == nil || fe.Code != frameworkerrors.Timeout {
+		t.Errorf("expected Timeout, got %v", fe)
+	}
+	select {
+	case <-streamDone:
+	case <-time.After(time.Second):
+		t.Fatal("expected stream context to be cancelled after idle timeout")
+	}
+
+	// 对端发送 CLOSE 帧时结束流
+	frame := dataFrame(ProtocolVersion, "open")
+	frame.Header.StreamId = 3
+	client.SendFrame(frame)
+	client.SendFrame(&CustomFrame{Header: &FrameHeader{Magic: MagicNumber, Version: ProtocolVersion, Type: FrameTypeClose, StreamId: 3}})
+	select {
+	case <-streamDone:
+	case <-time.After(40 * time.Millisecond):
+		t.Fatal("expected stream context to be cancelled by CLOSE frame")
+	}
+}
+
+func TestIdleConnectionClosed(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{IdleTimeout: 30 * time.Millisecond})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := h.readFrame(client.conn); err == nil || isTimeout(err) {
+		t.Errorf("expected idle connection to be closed, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// FlagEnvelope DATA 帧的信封标志，帧体为 protobuf 编码的 Envelope，按其中的服务和方法分发
+const FlagEnvelope uint32 = 0x2
+
+// Envelope DATA 帧携带的请求或响应信封
+//
+// 按以下 protobuf 消息编码，其他语言可由该定义生成代码：
+//
+//	message Envelope {
+//	  string service = 1;
+//	  string method = 2;
+//	  map<string, string> metadata = 3;
+//	  bytes payload = 4;
+//	}
+//
+// 请求的 Payload 为 JSON 编码的参数，Metadata 携带认证信息、追踪上下文等请求头；
+// 响应的 Service 和 Method 与请求相同，Payload 为 JSON 编码的结果
+type Envelope struct {
+	Service  string
+	Method   string
+	Metadata map[string]string
+	Payload  []byte
+}
+
+// Marshal 按 protobuf 编码信封，Metadata 按键排序以保证编码结果稳定
+func (e *Envelope) Marshal() []byte {
+	var b []byte
+	if e.Service != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, e.Service)
+	}
+	if e.Method != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, e.Method)
+	}
+
+	keys := make([]string, 0, len(e.Metadata))
+	for k := range e.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, e.Metadata[k])
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+
+	if len(e.Payload) > 0 {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Payload)
+	}
+	return b
+}
+
+// UnmarshalEnvelope 解码 protobuf 编码的信封，忽略未知字段
+func UnmarshalEnvelope(data []byte) (*Envelope, error) {
+	e := &Envelope{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, envelopeError(protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType || num < 1 || num > 4 {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, envelopeError(protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, envelopeError(protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch num {
+		case 1:
+			e.Service = string(value)
+		case 2:
+			e.Method = string(value)
+		case 3:
+			k, v, err := unmarshalMetadataEntry(value)
+			if err != nil {
+				return nil, err
+			}
+			if e.Metadata == nil {
+				e.Metadata = make(map[string]string)
+			}
+			e.Metadata[k] = v
+		case 4:
+			e.Payload = append([]byte(nil), value...)
+		}
+	}
+	return e, nil
+}
+
+// unmarshalMetadataEntry 解码 map<string, string> 的一个键值对
+func unmarshalMetadataEntry(data []byte) (string, string, error) {
+	var key, value string
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return "", "", envelopeError(protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType || (num != 1 && num != 2) {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return "", "", envelopeError(protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		s, n := protowire.ConsumeString(data)
+		if n < 0 {
+			return "", "", envelopeError(protowire.ParseError(n))
+		}
+		data = data[n:]
+		if num == 1 {
+			key = s
+		} else {
+			value = s
+		}
+	}
+	return key, value, nil
+}
+
+// envelopeError 将信封解码错误包装为 ProtocolError
+func envelopeError(err error) error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError, fmt.Sprintf("invalid envelope: %v", err))
+}
+
+// NewEnvelopeFrame 创建携带信封的 DATA 帧
+func NewEnvelopeFrame(streamId uint32, envelope *Envelope) *CustomFrame {
+	body := envelope.Marshal()
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ProtocolVersion,
+			Type:       FrameTypeData,
+			Flags:      FlagEnvelope,
+			StreamId:   streamId,
+			BodyLength: uint32(len(body)),
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: body,
+	}
+}
+
+// isEnvelopeFrame 判断是否为携带信封的 DATA 帧
+func isEnvelopeFrame(frame *CustomFrame) bool {
+	return frame.Header.Type == FrameTypeData && frame.Header.Flags&FlagEnvelope != 0
+}
+
+// dispatchEnvelope 解码 DATA 帧中的信封，经 Dispatcher 调用本地业务方法，返回携带结果信封的 DATA 帧
+//
+// 信封的 Metadata 作为请求头传给业务方法，其中的安全上下文和追踪上下文只作用于该请求
+func (h *CustomProtocolHandler) dispatchEnvelope(ctx context.Context, frame *CustomFrame) (context.Context, *CustomFrame, error) {
+	envelope, err := UnmarshalEnvelope(frame.Body)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if envelope.Service == "" && envelope.Method == "" {
+		return ctx, nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "envelope service and method are required")
+	}
+
+	ctx = applyHeaders(ctx, envelope.Metadata)
+	spanCtx, span := adapter.StartServerSpan(ctx, adapter.ProtocolCustomBinary, envelope.Service, envelope.Method)
+	result, err := h.config.Dispatcher(spanCtx, &adapter.InternalRequest{
+		Service: envelope.Service,
+		Method:  envelope.Method,
+		Payload: envelope.Payload,
+		Headers: envelope.Metadata,
+	})
+	adapter.EndSpan(span, err)
+	if err != nil {
+		return spanCtx, nil, err
+	}
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return spanCtx, nil, frameworkerrors.Wrap(err, frameworkerrors.InternalError, "failed to marshal result")
+	}
+	response := NewEnvelopeFrame(frame.Header.StreamId, &Envelope{
+		Service: envelope.Service,
+		Method:  envelope.Method,
+		Payload: payload,
+	})
+	response.Header.Version = frame.Header.Version
+	return spanCtx, response, nil
+}
+
+// Call 以信封 DATA 帧调用服务端的业务方法并等待同一流 ID 的响应，params 和结果以 JSON 编码，result 为 nil 时丢弃结果
+//
+// context 中的安全上下文和追踪上下文随信封的 Metadata 发送，协商了 FeatureDeadline 时 context 的截止时间随帧发送，
+// 服务端不再处理已过期的请求；业务错误以服务端的 ERROR 帧还原为框架错误返回。
+// 同一客户端上的调用依次进行
+func (c *CustomProtocolClient) Call(ctx context.Context, service, method string, params, result interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var payload []byte
+	if params != nil {
+		var err error
+		if payload, err = json.Marshal(params); err != nil {
+			return fmt.Errorf("failed to marshal params: %v", err)
+		}
+	}
+	metadata := make(map[string]string)
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		for k, v := range sc.ToHeaders() {
+			metadata[k] = v
+		}
+	}
+	adapter.InjectTraceContext(ctx, metadata)
+
+	c.callMu.Lock()
+	defer c.callMu.Unlock()
+
+	streamId := c.nextStreamId.Add(1)
+	request := NewEnvelopeFrame(streamId, &Envelope{
+		Service:  service,
+		Method:   method,
+		Metadata: metadata,
+		Payload:  payload,
+	})
+	if deadline, ok := ctx.Deadline(); ok {
+		SetFrameDeadline(request, deadline)
+	}
+	if err := c.SendFrame(request); err != nil {
+		return err
+	}
+
+	for {
+		frame, err := c.ReceiveFrame()
+		if err != nil {
+			return err
+		}
+		if frame.Header.StreamId != streamId {
+			continue
+		}
+
+		switch {
+		case frame.Header.Type == FrameTypeError:
+			fe, err := ParseErrorFrame(frame)
+			if err != nil {
+				return err
+			}
+			return fe
+		case isEnvelopeFrame(frame):
+			envelope, err := UnmarshalEnvelope(frame.Body)
+			if err != nil {
+				return err
+			}
+			if result == nil || len(envelope.Payload) == 0 {
+				return nil
+			}
+			return json.Unmarshal(envelope.Payload, result)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	envelope := &Envelope{
+		Service:  "hello",
+		Method:   "sayHello",
+		Metadata: map[string]string{"traceparent": "00-abc-def-01", "X-User-Id": "u1"},
+		Payload:  []byte(`{"name":"Go"}`),
+	}
+	data := envelope.Marshal()
+
+	// 未知字段被忽略，兼容新版本增加的字段
+	data = protowire.AppendTag(data, 9, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+
+	got, err := UnmarshalEnvelope(data)
+	if err != nil {
+		t.Fatalf("UnmarshalEnvelope failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, envelope) {
+		t.Errorf("UnmarshalEnvelope = %+v, want %+v", got, envelope)
+	}
+
+	if _, err := UnmarshalEnvelope([]byte{0x0a, 0x05, 'a'}); err == nil {
+		t.Error("Expected error for truncated envelope")
+	}
+}
+
+// echoDispatcher 返回请求的服务、方法、参数和认证用户
+func echoDispatcher(ctx context.Context, request *adapter.InternalRequest) (interface{}, error) {
+	if request.Method == "fail" {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "invalid request")
+	}
+	var params map[string]interface{}
+	json.Unmarshal(request.Payload, &params)
+	user := ""
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		user = sc.UserID
+	}
+	return map[string]interface{}{
+		"route":  request.Service + "." + request.Method,
+		"params": params,
+		"user":   user,
+	}, nil
+}
+
+func TestCallDispatchesEnvelope(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{Dispatcher: echoDispatcher})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+	if _, err := client.Handshake(); err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+
+	ctx := adapter.WithSecurityContext(context.Background(), &adapter.SecurityContext{UserID: "u1"})
+	var result map[string]interface{}
+	if err := client.Call(ctx, "hello", "sayHello", map[string]string{"name": "Go"}, &result); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"route":  "hello.sayHello",
+		"params": map[string]interface{}{"name": "Go"},
+		"user":   "u1",
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Call result = %v, want %v", result, want)
+	}
+
+	// 业务错误以 ERROR 帧还原为框架错误
+	err := client.Call(context.Background(), "hello", "fail", nil, nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Errorf("Expected BadRequest, got %v", err)
+	}
+}
+
+func TestPlainDataFrameUsesHandler(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{Dispatcher: echoDispatcher})
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		return frame, nil
+	})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	// 不带 FlagEnvelope 的 DATA 帧仍交给注册的处理器
+	client.SendFrame(dataFrame(1, "raw"))
+	response, err := client.ReceiveFrame()
+	if err != nil || string(response.Body) != "raw" {
+		t.Fatalf("unexpected response: %v %v", response, err)
+	}
+
+	// 无效的信封返回 ERROR 帧，连接保持可用
+	invalid := dataFrame(1, "\x0a\x05a")
+	invalid.Header.Flags = FlagEnvelope
+	client.SendFrame(invalid)
+	response, err = client.ReceiveFrame()
+	if err != nil || response.Header.Type != FrameTypeError {
+		t.Fatalf("expected error frame, got %v %v", response, err)
+	}
+	fe, err := ParseErrorFrame(response)
+	if err != nil || fe.Code != frameworkerrors.ProtocolError {
+		t.Errorf("Expected ProtocolError, got %v %v", fe, err)
+	}
+
+	if err := client.Call(context.Background(), "hello", "ping", nil, nil); err != nil {
+		t.Errorf("Call after invalid envelope failed: %v", err)
+	}
+}
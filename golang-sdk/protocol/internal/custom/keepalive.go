@@ -0,0 +1,152 @@
+package custom
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// KeepAliveVersion 自动回复 PING 的最低协议版本，只有协商到该版本及以上的连接才发送心跳，
+// 避免把不回复 PONG 的版本 1 对端误判为失效
+const KeepAliveVersion uint32 = 2
+
+// DefaultKeepAliveTimeout 发送 PING 后等待对端响应的默认时间
+const DefaultKeepAliveTimeout = 10 * time.Second
+
+// ErrPeerDead 对端在 KeepAliveTimeout 内没有响应 PING，连接已关闭
+var ErrPeerDead = errors.New("custom protocol peer did not respond to ping")
+
+// NewPingFrame 创建 PING 帧，对端以流 ID 和帧体相同的 PONG 帧响应
+func NewPingFrame(version uint32) *CustomFrame {
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:     MagicNumber,
+			Version:   version,
+			Type:      FrameTypePing,
+			Timestamp: time.Now().UnixMilli(),
+		},
+	}
+}
+
+// newPongFrame 创建响应 ping 的 PONG 帧，流 ID、版本和帧体与 PING 帧相同
+func newPongFrame(ping *CustomFrame) *CustomFrame {
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    ping.Header.Version,
+			Type:       FrameTypePong,
+			StreamId:   ping.Header.StreamId,
+			BodyLength: uint32(len(ping.Body)),
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: ping.Body,
+	}
+}
+
+// keepAliveTimeout 返回配置的心跳超时，为 0 时使用 DefaultKeepAliveTimeout
+func keepAliveTimeout(config *CustomProtocolConfig) time.Duration {
+	if config.KeepAliveTimeout > 0 {
+		return config.KeepAliveTimeout
+	}
+	return DefaultKeepAliveTimeout
+}
+
+// isTimeout 判断是否为读超时
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// countingConn 记录已读取的字节数，用于区分空闲超时和读取帧的中途超时
+type countingConn struct {
+	net.Conn
+	n int64
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// startKeepAlive 启动客户端的读取协程和心跳协程，之后 ReceiveFrame 从读取协程接收帧
+func (c *CustomProtocolClient) startKeepAlive() {
+	c.frames = make(chan *CustomFrame, 16)
+	c.done = make(chan struct{})
+	c.lastReceived.Store(time.Now().UnixNano())
+	go c.readLoop()
+	go c.keepAliveLoop(c.config.KeepAliveInterval, keepAliveTimeout(c.config))
+}
+
+// readLoop 持续读取帧，回复 PING，丢弃 PONG，其余帧交给 ReceiveFrame；读取失败时关闭 frames
+func (c *CustomProtocolClient) readLoop() {
+	defer close(c.frames)
+	handler := &CustomProtocolHandler{}
+	for {
+		frame, err := handler.readFrame(c.conn)
+		if err != nil {
+			if c.dead.Load() {
+				err = ErrPeerDead
+			}
+			c.readErr = err
+			return
+		}
+		c.lastReceived.Store(time.Now().UnixNano())
+
+		switch frame.Header.Type {
+		case FrameTypePing:
+			c.SendFrame(newPongFrame(frame))
+		case FrameTypePong:
+		default:
+			select {
+			case c.frames <- frame:
+			case <-c.done:
+				c.readErr = net.ErrClosed
+				return
+			}
+		}
+	}
+}
+
+// keepAliveLoop 连接空闲 interval 后发送 PING，timeout 内没有收到任何帧时判定对端失效并关闭连接
+func (c *CustomProtocolClient) keepAliveLoop(interval, timeout time.Duration) {
+	wait := interval
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(wait):
+		}
+
+		idle := time.Since(time.Unix(0, c.lastReceived.Load()))
+		if idle < interval {
+			wait = interval - idle
+			continue
+		}
+
+		sent := time.Now()
+		if err := c.SendFrame(NewPingFrame(c.negotiated.Version)); err != nil {
+			return
+		}
+		select {
+		case <-c.done:
+			return
+		case <-time.After(timeout):
+		}
+		if c.lastReceived.Load() < sent.UnixNano() {
+			c.dead.Store(true)
+			c.Close()
+			if c.config.OnPeerDead != nil {
+				c.config.OnPeerDead(ErrPeerDead)
+			}
+			return
+		}
+		wait = interval
+	}
+}
+
+// Healthy 判断连接是否可用：已连接、未关闭且心跳没有判定对端失效。
+// 连接池通过该方法回收失效的连接
+func (c *CustomProtocolClient) Healthy() bool {
+	return c.conn != nil && !c.closed.Load() && !c.dead.Load()
+}
@@ -0,0 +1,142 @@
+package custom
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServerRespondsToPing(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	ping := NewPingFrame(1)
+	ping.Header.StreamId = 7
+	client.SendFrame(ping)
+
+	// 直接读取连接，ReceiveFrame 会跳过 PONG
+	pong, err := h.readFrame(client.conn)
+	if err != nil {
+		t.Fatalf("readFrame failed: %v", err)
+	}
+	if pong.Header.Type != FrameTypePong || pong.Header.StreamId != 7 || pong.Header.Version != 1 {
+		t.Errorf("unexpected pong: %+v", pong.Header)
+	}
+}
+
+func TestServerDetectsDeadPeer(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{
+		KeepAliveInterval: 20 * time.Millisecond,
+		KeepAliveTimeout:  20 * time.Millisecond,
+	})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	settings, _ := NewSettingsFrame(&Settings{Versions: SupportedVersions()}, 0)
+	client.SendFrame(settings)
+	if ack, err := h.readFrame(client.conn); err != nil || ack.Header.Type != FrameTypeSettings {
+		t.Fatalf("expected settings ack, got %v %v", ack, err)
+	}
+
+	// 连接空闲后服务端发送 PING，不响应时关闭连接
+	ping, err := h.readFrame(client.conn)
+	if err != nil || ping.Header.Type != FrameTypePing {
+		t.Fatalf("expected ping, got %v %v", ping, err)
+	}
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := h.readFrame(client.conn); err == nil || isTimeout(err) {
+		t.Errorf("expected connection to be closed, got %v", err)
+	}
+}
+
+func TestServerLegacyConnectionNoPing(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{KeepAliveInterval: 10 * time.Millisecond})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	// 未协商的版本 1 连接不发送 PING
+	client.SendFrame(dataFrame(1, "hi"))
+	client.conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if frame, err := h.readFrame(client.conn); !isTimeout(err) {
+		t.Errorf("expected no frames on legacy connection, got %v %v", frame, err)
+	}
+}
+
+func TestClientKeepAlive(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		return frame, nil
+	})
+	client := pipeClient(h, &CustomProtocolConfig{
+		KeepAliveInterval: 10 * time.Millisecond,
+		KeepAliveTimeout:  50 * time.Millisecond,
+	})
+	defer client.Close()
+
+	if _, err := client.Handshake(); err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !client.Healthy() {
+		t.Fatal("expected connection to stay healthy while server answers pings")
+	}
+
+	// 心跳期间请求和响应不受影响
+	client.SendFrame(dataFrame(ProtocolVersion, "hi"))
+	response, err := client.ReceiveFrame()
+	if err != nil || string(response.Body) != "hi" {
+		t.Fatalf("unexpected response: %v %v", response, err)
+	}
+
+	client.Close()
+	if client.Healthy() {
+		t.Error("expected closed client to be unhealthy")
+	}
+}
+
+func TestClientDetectsDeadPeer(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	// 确认握手后不再响应任何帧的服务端
+	go func() {
+		h := &CustomProtocolHandler{}
+		if _, err := h.readFrame(serverConn); err != nil {
+			return
+		}
+		ack, _ := NewSettingsFrame(&Settings{Versions: []uint32{ProtocolVersion}}, FlagAck)
+		h.writeFrame(serverConn, ack)
+		for {
+			if _, err := h.readFrame(serverConn); err != nil {
+				return
+			}
+		}
+	}()
+
+	dead := make(chan error, 1)
+	client := &CustomProtocolClient{conn: clientConn, config: &CustomProtocolConfig{
+		KeepAliveInterval: 10 * time.Millisecond,
+		KeepAliveTimeout:  20 * time.Millisecond,
+		OnPeerDead:        func(err error) { dead <- err },
+	}}
+	defer client.Close()
+
+	if _, err := client.Handshake(); err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	select {
+	case err := <-dead:
+		if err != ErrPeerDead {
+			t.Errorf("OnPeerDead error = %v, want ErrPeerDead", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected dead peer to be detected")
+	}
+	if client.Healthy() {
+		t.Error("expected client to be unhealthy")
+	}
+	if _, err := client.ReceiveFrame(); err != ErrPeerDead {
+		t.Errorf("ReceiveFrame error = %v, want ErrPeerDead", err)
+	}
+}
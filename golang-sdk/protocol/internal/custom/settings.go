@@ -0,0 +1,167 @@
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// MinProtocolVersion 支持的最低协议版本，版本 1 的对端不发送 SETTINGS 帧
+const MinProtocolVersion uint32 = 1
+
+// FlagAck SETTINGS 帧的确认标志，服务端以带该标志的 SETTINGS 帧返回协商结果
+const FlagAck uint32 = 0x1
+
+// 可协商的特性，双方都声明支持时才可使用
+const (
+	// FeatureCompression 帧体压缩
+	FeatureCompression = "compression"
+	// FeatureStreaming 同一流上的多帧流式传输
+	FeatureStreaming = "streaming"
+	// FeatureDeadline 帧可带截止时间（FlagDeadline），服务端丢弃已过截止时间的帧
+	FeatureDeadline = "deadline"
+)
+
+// DefaultHandshakeTimeout 客户端等待 SETTINGS 确认的默认时间，超时视为不支持协商的版本 1 服务端
+const DefaultHandshakeTimeout = time.Second
+
+// Settings SETTINGS 帧体，JSON 编码
+//
+// 客户端在连接建立后的第一个帧中发送支持的版本和特性，服务端确认时 Versions 只包含协商出的版本，
+// Features 为双方共同支持的特性
+type Settings struct {
+	Versions []uint32 `json:"versions"`
+	Features []string `json:"features,omitempty"`
+}
+
+// Negotiated 连接的协商结果
+type Negotiated struct {
+	Version  uint32
+	Features []string
+}
+
+// Supports 判断特性是否已协商启用
+func (n *Negotiated) Supports(feature string) bool {
+	if n == nil {
+		return false
+	}
+	for _, f := range n.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyNegotiated 未发送 SETTINGS 帧的版本 1 对端，不启用任何特性
+var legacyNegotiated = &Negotiated{Version: MinProtocolVersion}
+
+// SupportedVersions 返回本实现支持的协议版本，从低到高
+func SupportedVersions() []uint32 {
+	versions := make([]uint32, 0, ProtocolVersion-MinProtocolVersion+1)
+	for v := MinProtocolVersion; v <= ProtocolVersion; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// Negotiate 选出双方都支持的最高版本和共同支持的特性，没有共同版本时返回 ProtocolError
+func Negotiate(local, remote *Settings) (*Negotiated, error) {
+	var version uint32
+	for _, v := range remote.Versions {
+		if v > version && containsVersion(local.Versions, v) {
+			version = v
+		}
+	}
+	if version == 0 {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError,
+			fmt.Sprintf("incompatible protocol versions: peer supports %v, local supports %v", remote.Versions, local.Versions))
+	}
+
+	negotiated := &Negotiated{Version: version}
+	for _, feature := range remote.Features {
+		if containsString(local.Features, feature) && !containsString(negotiated.Features, feature) {
+			negotiated.Features = append(negotiated.Features, feature)
+		}
+	}
+	return negotiated, nil
+}
+
+// NewSettingsFrame 创建 SETTINGS 帧，帧头版本固定为 MinProtocolVersion，任何版本的对端都能解析
+func NewSettingsFrame(settings *Settings, flags uint32) (*CustomFrame, error) {
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %v", err)
+	}
+
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    MinProtocolVersion,
+			Type:       FrameTypeSettings,
+			Flags:      flags,
+			BodyLength: uint32(len(body)),
+			Timestamp:  time.Now().UnixMilli(),
+		},
+		Body: body,
+	}, nil
+}
+
+// ParseSettingsFrame 解析 SETTINGS 帧体
+func ParseSettingsFrame(frame *CustomFrame) (*Settings, error) {
+	if frame == nil || frame.Header == nil || frame.Header.Type != FrameTypeSettings {
+		return nil, fmt.Errorf("not a settings frame")
+	}
+	var settings Settings
+	if err := json.Unmarshal(frame.Body, &settings); err != nil {
+		return nil, frameworkerrors.Wrap(err, frameworkerrors.ProtocolError, "invalid settings frame")
+	}
+	return &settings, nil
+}
+
+// checkVersion 检查帧版本在 MinProtocolVersion 和 max 之间，max 为协商的版本，未协商时为 ProtocolVersion
+func checkVersion(frame *CustomFrame, max uint32) error {
+	version := frame.Header.Version
+	if version < MinProtocolVersion || version > max {
+		return frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError,
+			fmt.Sprintf("unsupported protocol version %d, supported versions %d-%d", version, MinProtocolVersion, max))
+	}
+	return nil
+}
+
+// negotiatedKey 协商结果的上下文键
+type negotiatedKey struct{}
+
+// withNegotiated 将连接的协商结果写入 context
+func withNegotiated(ctx context.Context, negotiated *Negotiated) context.Context {
+	return context.WithValue(ctx, negotiatedKey{}, negotiated)
+}
+
+// NegotiatedFromContext 返回处理器所在连接的协商结果，处理器据此决定是否使用压缩、流式传输等特性
+func NegotiatedFromContext(ctx context.Context) *Negotiated {
+	negotiated, _ := ctx.Value(negotiatedKey{}).(*Negotiated)
+	return negotiated
+}
+
+// containsVersion 判断版本列表是否包含 v
+func containsVersion(versions []uint32, v uint32) bool {
+	for _, version := range versions {
+		if version == v {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString 判断字符串列表是否包含 s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
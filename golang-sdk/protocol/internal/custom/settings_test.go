@@ -0,0 +1,224 @@
+package custom
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+func TestNegotiate(t *testing.T) {
+	local := &Settings{Versions: []uint32{1, 2}, Features: []string{FeatureCompression, FeatureStreaming}}
+	tests := []struct {
+		name         string
+		remote       *Settings
+		wantVersion  uint32
+		wantFeatures []string
+		wantErr      bool
+	}{
+		{name: "选最高共同版本", remote: &Settings{Versions: []uint32{1, 2, 3}}, wantVersion: 2},
+		{name: "降级到版本 1", remote: &Settings{Versions: []uint32{1}, Features: []string{FeatureCompression}}, wantVersion: 1, wantFeatures: []string{FeatureCompression}},
+		{name: "只启用共同特性", remote: &Settings{Versions: []uint32{2}, Features: []string{FeatureStreaming, "encryption"}}, wantVersion: 2, wantFeatures: []string{FeatureStreaming}},
+		{name: "没有共同版本", remote: &Settings{Versions: []uint32{3, 4}}, wantErr: true},
+		{name: "未声明版本", remote: &Settings{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			negotiated, err := Negotiate(local, tt.remote)
+			if tt.wantErr {
+				if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ProtocolError {
+					t.Fatalf("expected ProtocolError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Negotiate failed: %v", err)
+			}
+			if negotiated.Version != tt.wantVersion || !reflect.DeepEqual(negotiated.Features, tt.wantFeatures) {
+				t.Errorf("Negotiate = %+v, want version %d features %v", negotiated, tt.wantVersion, tt.wantFeatures)
+			}
+		})
+	}
+}
+
+// pipeClient 经 net.Pipe 连接到处理器的客户端
+func pipeClient(h *CustomProtocolHandler, config *CustomProtocolConfig) *CustomProtocolClient {
+	serverConn, clientConn := net.Pipe()
+	go h.handleConnection(serverConn)
+	return &CustomProtocolClient{conn: clientConn, config: config}
+}
+
+// dataFrame 创建指定版本的 DATA 帧
+func dataFrame(version uint32, body string) *CustomFrame {
+	return &CustomFrame{
+		Header: &FrameHeader{
+			Magic:      MagicNumber,
+			Version:    version,
+			Type:       FrameTypeData,
+			StreamId:   1,
+			BodyLength: uint32(len(body)),
+		},
+		Body: []byte(body),
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{Features: []string{FeatureCompression}})
+	var seen *Negotiated
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		seen = NegotiatedFromContext(ctx)
+		return frame, nil
+	})
+
+	client := pipeClient(h, &CustomProtocolConfig{Features: []string{FeatureCompression, FeatureStreaming}})
+	defer client.Close()
+
+	negotiated, err := client.Handshake()
+	if err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	if negotiated.Version != ProtocolVersion || !negotiated.Supports(FeatureCompression) || negotiated.Supports(FeatureStreaming) {
+		t.Errorf("unexpected negotiation: %+v", negotiated)
+	}
+
+	if err := client.SendFrame(dataFrame(ProtocolVersion, "hi")); err != nil {
+		t.Fatalf("SendFrame failed: %v", err)
+	}
+	response, err := client.ReceiveFrame()
+	if err != nil {
+		t.Fatalf("ReceiveFrame failed: %v", err)
+	}
+	if response.Header.Type != FrameTypeData || string(response.Body) != "hi" {
+		t.Errorf("unexpected response: %s %s", response.Header.Type, response.Body)
+	}
+	if seen == nil || !seen.Supports(FeatureCompression) {
+		t.Errorf("handler should see negotiated features, got %+v", seen)
+	}
+}
+
+func TestHandshakeLegacyServer(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	// 版本 1 服务端没有 SETTINGS 处理器，读取并丢弃帧
+	go func() {
+		h := &CustomProtocolHandler{}
+		for {
+			if _, err := h.readFrame(serverConn); err != nil {
+				return
+			}
+		}
+	}()
+
+	client := &CustomProtocolClient{conn: clientConn, config: &CustomProtocolConfig{
+		Features:         []string{FeatureCompression},
+		HandshakeTimeout: 50 * time.Millisecond,
+	}}
+	defer client.Close()
+
+	negotiated, err := client.Handshake()
+	if err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	if negotiated.Version != 1 || negotiated.Supports(FeatureCompression) {
+		t.Errorf("expected downgrade to version 1 without features, got %+v", negotiated)
+	}
+
+	// 握手后发送的帧降为协商的版本
+	frame := dataFrame(ProtocolVersion, "hi")
+	client.SendFrame(frame)
+	if frame.Header.Version != 1 {
+		t.Errorf("expected frame version 1, got %d", frame.Header.Version)
+	}
+}
+
+func TestLegacyClient(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{Features: []string{FeatureCompression}})
+	var seen *Negotiated
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		seen = NegotiatedFromContext(ctx)
+		return dataFrame(ProtocolVersion, "ok"), nil
+	})
+
+	// 版本 1 客户端不握手，直接发送帧
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+	client.SendFrame(dataFrame(1, "hi"))
+
+	response, err := client.ReceiveFrame()
+	if err != nil {
+		t.Fatalf("ReceiveFrame failed: %v", err)
+	}
+	if response.Header.Version != 1 {
+		t.Errorf("response version should not exceed request version, got %d", response.Header.Version)
+	}
+	if seen == nil || seen.Version != 1 || seen.Supports(FeatureCompression) {
+		t.Errorf("expected version 1 without features, got %+v", seen)
+	}
+}
+
+func TestIncompatiblePeer(t *testing.T) {
+	tests := []struct {
+		name  string
+		first *CustomFrame
+	}{
+		{name: "没有共同版本", first: func() *CustomFrame {
+			frame, _ := NewSettingsFrame(&Settings{Versions: []uint32{ProtocolVersion + 1}}, 0)
+			return frame
+		}()},
+		{name: "帧版本过高", first: dataFrame(ProtocolVersion+1, "hi")},
+		{name: "帧版本为 0", first: dataFrame(0, "hi")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+			h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+				return frame, nil
+			})
+			client := pipeClient(h, &CustomProtocolConfig{})
+			defer client.Close()
+
+			client.SendFrame(tt.first)
+			response, err := client.ReceiveFrame()
+			if err != nil {
+				t.Fatalf("ReceiveFrame failed: %v", err)
+			}
+			fe, err := ParseErrorFrame(response)
+			if err != nil || fe.Code != frameworkerrors.ProtocolError {
+				t.Fatalf("expected ProtocolError frame, got %v %v", fe, err)
+			}
+			// 服务端随后关闭连接
+			if _, err := client.ReceiveFrame(); err == nil {
+				t.Error("expected connection to be closed")
+			}
+		})
+	}
+}
+
+func TestFrameAboveNegotiatedVersion(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		return frame, nil
+	})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	// 只声明版本 1，协商后发送版本 2 的帧
+	settings, _ := NewSettingsFrame(&Settings{Versions: []uint32{1}}, 0)
+	client.SendFrame(settings)
+	if ack, err := client.ReceiveFrame(); err != nil || ack.Header.Flags&FlagAck == 0 {
+		t.Fatalf("expected settings ack, got %v %v", ack, err)
+	}
+	client.SendFrame(dataFrame(2, "hi"))
+	response, err := client.ReceiveFrame()
+	if err != nil {
+		t.Fatalf("ReceiveFrame failed: %v", err)
+	}
+	if fe, err := ParseErrorFrame(response); err != nil || fe.Code != frameworkerrors.ProtocolError {
+		t.Errorf("expected ProtocolError frame, got %v %v", fe, err)
+	}
+}
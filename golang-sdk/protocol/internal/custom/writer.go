@@ -0,0 +1,72 @@
+package custom
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+)
+
+// frameHeaderSize 编码后的帧头长度
+const frameHeaderSize = 40
+
+// FrameWriter 串行化同一连接上的帧写入，每个帧编码后一次写入，可在多个协程中并发调用
+type FrameWriter struct {
+	conn net.Conn
+	mu   sync.Mutex
+	// observe 帧发送成功后调用，服务端以此跟踪流的状态
+	observe func(frame *CustomFrame)
+}
+
+// NewFrameWriter 创建连接的帧写入器，同一连接的所有写入须经过同一个 FrameWriter
+func NewFrameWriter(conn net.Conn) *FrameWriter {
+	return &FrameWriter{conn: conn}
+}
+
+// SendFrame 发送帧，并发调用时帧之间不会交错
+func (w *FrameWriter) SendFrame(frame *CustomFrame) error {
+	data := encodeFrame(frame)
+
+	w.mu.Lock()
+	_, err := w.conn.Write(data)
+	w.mu.Unlock()
+	if err == nil && w.observe != nil {
+		w.observe(frame)
+	}
+	return err
+}
+
+// encodeFrame 将帧头和帧体编码为一个缓冲区，带 FlagDeadline 时超时字段写在帧体之前并计入帧体长度
+func encodeFrame(frame *CustomFrame) []byte {
+	prefix := 0
+	if frame.Header.Flags&FlagDeadline != 0 {
+		prefix = deadlineSize
+	}
+	buf := make([]byte, frameHeaderSize+prefix+len(frame.Body))
+	binary.BigEndian.PutUint32(buf[0:], frame.Header.Magic)
+	binary.BigEndian.PutUint32(buf[4:], frame.Header.Version)
+	binary.BigEndian.PutUint32(buf[8:], uint32(frame.Header.Type))
+	binary.BigEndian.PutUint32(buf[12:], frame.Header.Flags)
+	binary.BigEndian.PutUint32(buf[16:], frame.Header.StreamId)
+	binary.BigEndian.PutUint32(buf[20:], frame.Header.BodyLength+uint32(prefix))
+	binary.BigEndian.PutUint64(buf[24:], frame.Header.Sequence)
+	binary.BigEndian.PutUint64(buf[32:], uint64(frame.Header.Timestamp))
+	if prefix > 0 {
+		binary.BigEndian.PutUint32(buf[frameHeaderSize:], frame.Header.Timeout)
+	}
+	copy(buf[frameHeaderSize+prefix:], frame.Body)
+	return buf
+}
+
+type frameWriterKey struct{}
+
+// withFrameWriter 将连接的帧写入器写入 context
+func withFrameWriter(ctx context.Context, writer *FrameWriter) context.Context {
+	return context.WithValue(ctx, frameWriterKey{}, writer)
+}
+
+// FrameWriterFromContext 获取处理器所在连接的帧写入器，用于在处理器之外的协程中向该连接推送帧
+func FrameWriterFromContext(ctx context.Context) *FrameWriter {
+	writer, _ := ctx.Value(frameWriterKey{}).(*FrameWriter)
+	return writer
+}
@@ -0,0 +1,108 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+)
+
+func TestEncodeFrameRoundTrip(t *testing.T) {
+	frame := dataFrame(ProtocolVersion, "hello")
+	frame.Header.Flags = FlagAck
+	frame.Header.Sequence = 42
+	frame.Header.Timestamp = 1700000000000
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	go NewFrameWriter(clientConn).SendFrame(frame)
+
+	h := &CustomProtocolHandler{}
+	got, err := h.readFrame(serverConn)
+	if err != nil {
+		t.Fatalf("readFrame failed: %v", err)
+	}
+	if *got.Header != *frame.Header || string(got.Body) != "hello" {
+		t.Errorf("readFrame = %+v %q, want %+v %q", got.Header, got.Body, frame.Header, frame.Body)
+	}
+}
+
+func TestClientConcurrentSendFrame(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	client := &CustomProtocolClient{conn: clientConn, config: &CustomProtocolConfig{}}
+	defer client.Close()
+
+	const senders, perSender = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perSender; j++ {
+				body := fmt.Sprintf("sender-%d-frame-%d", i, j)
+				frame := dataFrame(ProtocolVersion, body)
+				frame.Header.StreamId = uint32(i)
+				if err := client.SendFrame(frame); err != nil {
+					t.Errorf("SendFrame failed: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+
+	// 并发发送的帧不交错，每个帧都能完整解析
+	h := &CustomProtocolHandler{}
+	next := make(map[uint32]int)
+	for n := 0; n < senders*perSender; n++ {
+		frame, err := h.readFrame(serverConn)
+		if err != nil {
+			t.Fatalf("readFrame failed after %d frames: %v", n, err)
+		}
+		id := frame.Header.StreamId
+		want := fmt.Sprintf("sender-%d-frame-%d", id, next[id])
+		if string(frame.Body) != want {
+			t.Fatalf("frame body = %q, want %q", frame.Body, want)
+		}
+		next[id]++
+	}
+	wg.Wait()
+}
+
+func TestHandlerPushesFromContext(t *testing.T) {
+	h := NewCustomProtocolHandler(&CustomProtocolConfig{})
+	h.RegisterHandler(FrameTypeData, func(ctx context.Context, frame *CustomFrame) (*CustomFrame, error) {
+		writer := FrameWriterFromContext(ctx)
+		if writer == nil {
+			return nil, fmt.Errorf("no frame writer in context")
+		}
+		// 处理器返回响应的同时在其他协程中推送帧
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				writer.SendFrame(dataFrame(frame.Header.Version, "push"))
+			}()
+		}
+		wg.Wait()
+		return dataFrame(frame.Header.Version, "response"), nil
+	})
+	client := pipeClient(h, &CustomProtocolConfig{})
+	defer client.Close()
+
+	client.SendFrame(dataFrame(1, "hi"))
+	counts := make(map[string]int)
+	for i := 0; i < 5; i++ {
+		frame, err := client.ReceiveFrame()
+		if err != nil {
+			t.Fatalf("ReceiveFrame failed: %v", err)
+		}
+		counts[string(frame.Body)]++
+	}
+	if counts["push"] != 4 || counts["response"] != 1 {
+		t.Errorf("received frames = %v, want 4 push and 1 response", counts)
+	}
+}
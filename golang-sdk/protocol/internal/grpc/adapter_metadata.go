@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ExternalRequestFromContext 由 gRPC 入站调用构造协议适配器的外部请求
+//
+// 入站元数据按 adapter.HeadersFromGRPCMetadata 转换为请求头，完整方法名写入 adapter.HeaderGRPCMethod，
+// 适配器据此确定服务和方法
+func ExternalRequestFromContext(ctx context.Context, fullMethod string, body interface{}) *adapter.ExternalRequest {
+	var headers map[string]string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		headers = adapter.HeadersFromGRPCMetadata(md)
+	} else {
+		headers = make(map[string]string)
+	}
+	headers[adapter.HeaderGRPCMethod] = fullMethod
+
+	requestMetadata := &adapter.RequestMetadata{Timestamp: time.Now().UnixMilli()}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		requestMetadata.ClientAddr = p.Addr.String()
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(values) > 0 {
+		requestMetadata.RequestId = values[0]
+	}
+
+	return &adapter.ExternalRequest{
+		Protocol: adapter.ProtocolGRPC,
+		Headers:  headers,
+		Body:     body,
+		Metadata: requestMetadata,
+	}
+}
+
+// SendExternalResponse 将适配器转换后的响应写回 gRPC 调用，返回处理器应返回的错误
+//
+// 响应头按 adapter.GRPCMetadataFromHeaders 转换后作为 trailer 发送；响应包含错误时返回的 gRPC 状态
+// 由 StatusFromError 构造，完整的结构化错误（含 details）放在 status details 中，而不是拼接为消息字符串
+func SendExternalResponse(ctx context.Context, external *adapter.ExternalResponse) error {
+	if external == nil {
+		return nil
+	}
+	if md := adapter.GRPCMetadataFromHeaders(external.Headers); len(md) > 0 {
+		if err := grpc.SetTrailer(ctx, metadata.MD(md)); err != nil {
+			return StatusFromError(ctx, err).Err()
+		}
+	}
+	if external.Error != nil {
+		return StatusFromError(ctx, external.Error).Err()
+	}
+	return nil
+}
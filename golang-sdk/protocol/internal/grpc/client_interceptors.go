@@ -0,0 +1,354 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenSource 返回出站调用携带的访问令牌，返回空字符串时不携带
+type TokenSource func(ctx context.Context) (string, error)
+
+// ClientInterceptorConfig gRPC 客户端拦截器配置
+//
+// 错误还原、安全上下文、追踪和追踪上下文传递总是启用，其余功能在对应字段设置后启用
+type ClientInterceptorConfig struct {
+	// Metrics 按逻辑调用记录请求数、耗时和错误，重试只记一次，为 nil 时不记录
+	Metrics *observability.MetricsCollector
+	// RetryPolicy 可重试错误的重试策略，流式调用只重试建立流，为 nil 时不重试
+	RetryPolicy *resilience.RetryPolicy
+	// CircuitBreaker 为每个目标地址（ClientConn.Target）创建熔断器，为 nil 时不熔断
+	CircuitBreaker func(target string) *resilience.CircuitBreaker
+	// TokenSource 以 authorization: Bearer 元数据携带的访问令牌，为 nil 时不携带
+	TokenSource TokenSource
+}
+
+// ClientDialOptions 返回按配置串联客户端拦截器的拨号选项，config 为 nil 时只启用总是启用的拦截器
+//
+// 拦截器由外到内依次为：指标、熔断、重试、错误还原、安全上下文、访问令牌、追踪、追踪上下文。
+// 指标和熔断按逻辑调用统计，重试的每次尝试各有一个客户端 span，下游收到的父 span 为该 span
+func ClientDialOptions(config *ClientInterceptorConfig) []grpc.DialOption {
+	if config == nil {
+		config = &ClientInterceptorConfig{}
+	}
+
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	if config.Metrics != nil {
+		unary = append(unary, MetricsUnaryClientInterceptor(config.Metrics))
+		stream = append(stream, MetricsStreamClientInterceptor(config.Metrics))
+	}
+	if config.CircuitBreaker != nil {
+		// 一元调用和流式调用共用同一目标的熔断器
+		breakers := newBreakerSet(config.CircuitBreaker)
+		unary = append(unary, breakers.unaryInterceptor())
+		stream = append(stream, breakers.streamInterceptor())
+	}
+	if config.RetryPolicy != nil {
+		unary = append(unary, RetryUnaryClientInterceptor(config.RetryPolicy))
+		stream = append(stream, RetryStreamClientInterceptor(config.RetryPolicy))
+	}
+	unary = append(unary, ErrorUnaryClientInterceptor(), SecurityContextUnaryClientInterceptor())
+	stream = append(stream, ErrorStreamClientInterceptor(), SecurityContextStreamClientInterceptor())
+	if config.TokenSource != nil {
+		unary = append(unary, AuthTokenUnaryClientInterceptor(config.TokenSource))
+		stream = append(stream, AuthTokenStreamClientInterceptor(config.TokenSource))
+	}
+	unary = append(unary, TracingUnaryClientInterceptor(), TraceContextUnaryClientInterceptor())
+	stream = append(stream, TracingStreamClientInterceptor(), TraceContextStreamClientInterceptor())
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}
+}
+
+// MetricsUnaryClientInterceptor 记录出站调用的请求数、耗时和错误，服务和方法为 gRPC 的服务全名和方法名
+func MetricsUnaryClientInterceptor(metrics *observability.MetricsCollector) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordCall(metrics, method, err, time.Since(start))
+		return err
+	}
+}
+
+// MetricsStreamClientInterceptor 流式调用的指标拦截器，在流结束时记录
+func MetricsStreamClientInterceptor(metrics *observability.MetricsCollector) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			recordCall(metrics, method, err, time.Since(start))
+			return nil, err
+		}
+		return newObservedStream(cs, desc, func(err error) {
+			recordCall(metrics, method, err, time.Since(start))
+		}), nil
+	}
+}
+
+// recordCall 记录一次出站调用
+func recordCall(metrics *observability.MetricsCollector, fullMethod string, err error, duration time.Duration) {
+	service, method := splitFullMethod(fullMethod)
+	status := observability.RequestStatusSuccess
+	if err != nil {
+		status = adapter.ErrorCodeLabel(callError(err))
+		metrics.RecordError(service, method, status)
+	}
+	metrics.RecordRequest(service, method, string(adapter.ProtocolGRPC), status, duration)
+}
+
+// CircuitBreakerUnaryClientInterceptor 按目标地址熔断出站调用，熔断期间直接返回 ServiceUnavailable
+//
+// 只有服务端错误（5xx、超时、连接错误等）计入失败，客户端错误原样返回；newBreaker 为 nil 时使用 resilience.NewDefaultCircuitBreaker
+func CircuitBreakerUnaryClientInterceptor(newBreaker func(target string) *resilience.CircuitBreaker) grpc.UnaryClientInterceptor {
+	return newBreakerSet(newBreaker).unaryInterceptor()
+}
+
+// CircuitBreakerStreamClientInterceptor 流式调用的熔断拦截器，按建立流的结果计入成功或失败
+func CircuitBreakerStreamClientInterceptor(newBreaker func(target string) *resilience.CircuitBreaker) grpc.StreamClientInterceptor {
+	return newBreakerSet(newBreaker).streamInterceptor()
+}
+
+// breakerSet 按目标地址创建和缓存熔断器
+type breakerSet struct {
+	newBreaker func(target string) *resilience.CircuitBreaker
+	mu         sync.Mutex
+	breakers   map[string]*resilience.CircuitBreaker
+}
+
+func newBreakerSet(newBreaker func(target string) *resilience.CircuitBreaker) *breakerSet {
+	if newBreaker == nil {
+		newBreaker = resilience.NewDefaultCircuitBreaker
+	}
+	return &breakerSet{newBreaker: newBreaker, breakers: make(map[string]*resilience.CircuitBreaker)}
+}
+
+// get 返回目标地址的熔断器
+func (s *breakerSet) get(target string) *resilience.CircuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaker, ok := s.breakers[target]
+	if !ok {
+		breaker = s.newBreaker(target)
+		s.breakers[target] = breaker
+	}
+	return breaker
+}
+
+func (s *breakerSet) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var callErr error
+		if err := s.get(cc.Target()).Execute(func() error {
+			callErr = invoker(ctx, method, req, reply, cc, opts...)
+			if isServerFailure(callErr) {
+				return callErr
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return callErr
+	}
+}
+
+func (s *breakerSet) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		var cs grpc.ClientStream
+		var callErr error
+		if err := s.get(cc.Target()).Execute(func() error {
+			cs, callErr = streamer(ctx, desc, cc, method, opts...)
+			if isServerFailure(callErr) {
+				return callErr
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return cs, callErr
+	}
+}
+
+// RetryUnaryClientInterceptor 按重试策略重试可重试的错误，ctx 结束时停止重试并返回最后一次的错误
+func RetryUnaryClientInterceptor(policy *resilience.RetryPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= policy.MaxAttempts || !retryable(policy, err) {
+				return err
+			}
+			select {
+			case <-time.After(policy.CalculateDelay(attempt - 1)):
+			case <-ctx.Done():
+				return err
+			}
+		}
+	}
+}
+
+// RetryStreamClientInterceptor 流式调用的重试拦截器，只重试建立流，流建立后的错误不重试
+func RetryStreamClientInterceptor(policy *resilience.RetryPolicy) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		for attempt := 1; ; attempt++ {
+			cs, err := streamer(ctx, desc, cc, method, opts...)
+			if err == nil || attempt >= policy.MaxAttempts || !retryable(policy, err) {
+				return cs, err
+			}
+			select {
+			case <-time.After(policy.CalculateDelay(attempt - 1)):
+			case <-ctx.Done():
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryable 判断错误是否按重试策略重试，非框架错误和 gRPC 状态以外的错误不重试
+func retryable(policy *resilience.RetryPolicy, err error) bool {
+	fe, ok := callError(err).(*frameworkerrors.FrameworkError)
+	return ok && policy.IsRetryable(fe.Code)
+}
+
+// isServerFailure 判断错误是否计入熔断失败：服务端错误、超时和框架错误（如连接失败）
+func isServerFailure(err error) bool {
+	fe, ok := callError(err).(*frameworkerrors.FrameworkError)
+	if !ok {
+		return false
+	}
+	return fe.Code == frameworkerrors.Timeout || fe.Code.IsServerError() || fe.Code.IsFrameworkError()
+}
+
+// callError 将 gRPC 状态还原为框架错误，使拦截器不论位于错误还原拦截器内外都能按错误码判断
+func callError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if fe, ok := frameworkerrors.FromError(err); ok {
+		return fe
+	}
+	if st, ok := status.FromError(err); ok {
+		return ErrorFromStatus(st)
+	}
+	return err
+}
+
+// AuthTokenUnaryClientInterceptor 以 authorization: Bearer 出站元数据携带访问令牌，获取令牌失败时返回 Unauthorized
+func AuthTokenUnaryClientInterceptor(source TokenSource) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := withOutgoingToken(ctx, source)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// AuthTokenStreamClientInterceptor 流式调用的访问令牌客户端拦截器
+func AuthTokenStreamClientInterceptor(source TokenSource) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := withOutgoingToken(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// withOutgoingToken 追加访问令牌元数据
+func withOutgoingToken(ctx context.Context, source TokenSource) (context.Context, error) {
+	token, err := source(ctx)
+	if err != nil {
+		return ctx, frameworkerrors.Wrap(err, frameworkerrors.Unauthorized, "failed to get access token")
+	}
+	if token == "" {
+		return ctx, nil
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token), nil
+}
+
+// ErrorStreamClientInterceptor 将流中收发消息返回的 gRPC 状态还原为框架错误
+func ErrorStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, callError(err)
+		}
+		return &errorStream{ClientStream: cs}, nil
+	}
+}
+
+// errorStream 还原收发消息的错误，io.EOF 原样返回
+type errorStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorStream) SendMsg(m interface{}) error {
+	return streamError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorStream) RecvMsg(m interface{}) error {
+	return streamError(s.ClientStream.RecvMsg(m))
+}
+
+// streamError 还原流的错误，io.EOF 表示流正常结束，原样返回
+func streamError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+	return callError(err)
+}
+
+// TracingStreamClientInterceptor 为每个出站流创建客户端 span，流结束时结束
+//
+// 需位于 TraceContextStreamClientInterceptor 之前，使下游收到的父 span 为该客户端 span
+func TracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		service, name := splitFullMethod(method)
+		ctx, span := adapter.StartClientSpan(ctx, adapter.ProtocolGRPC, service, name, cc.Target())
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			adapter.EndSpan(span, err)
+			return nil, err
+		}
+		return newObservedStream(cs, desc, func(err error) {
+			adapter.EndSpan(span, err)
+		}), nil
+	}
+}
+
+// observedStream 在流结束时调用一次 finish：接收消息返回错误（io.EOF 视为成功），
+// 或服务端不以流返回时收到唯一的响应
+type observedStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	once          sync.Once
+	finish        func(err error)
+}
+
+func newObservedStream(cs grpc.ClientStream, desc *grpc.StreamDesc, finish func(err error)) *observedStream {
+	return &observedStream{ClientStream: cs, serverStreams: desc.ServerStreams, finish: finish}
+}
+
+func (s *observedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.once.Do(func() { s.finish(nil) })
+	case err != nil:
+		s.once.Do(func() { s.finish(err) })
+	case !s.serverStreams:
+		s.once.Do(func() { s.finish(nil) })
+	}
+	return err
+}
@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/observability"
+	"github.com/framework/golang-sdk/resilience"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeConn 创建不发起连接的 ClientConn，拦截器只读取其目标地址
+func fakeConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	cc, err := grpc.Dial("passthrough:///user-service:9000", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return cc
+}
+
+// failingInvoker 前 failures 次调用返回 code 状态，之后成功，calls 记录调用次数
+func failingInvoker(code codes.Code, failures int, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "backend failed")
+		}
+		return nil
+	}
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	cc := fakeConn(t)
+	interceptor := RetryUnaryClientInterceptor(resilience.NewRetryPolicy(3, time.Millisecond, time.Millisecond, 1))
+
+	// Unavailable 映射为 ServiceUnavailable，可重试
+	calls := 0
+	err := interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 2, &calls))
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success after 3 calls, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 5, &calls))
+	if err == nil || calls != 3 {
+		t.Errorf("Expected error after 3 calls, got %v after %d", err, calls)
+	}
+
+	// 客户端错误不重试
+	calls = 0
+	err = interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.InvalidArgument, 5, &calls))
+	if err == nil || calls != 1 {
+		t.Errorf("Expected error after 1 call, got %v after %d", err, calls)
+	}
+}
+
+func TestCircuitBreakerUnaryClientInterceptor(t *testing.T) {
+	cc := fakeConn(t)
+	var targets []string
+	interceptor := CircuitBreakerUnaryClientInterceptor(func(target string) *resilience.CircuitBreaker {
+		targets = append(targets, target)
+		return resilience.NewCircuitBreaker(target, 2, 1, time.Minute)
+	})
+
+	// 客户端错误不计入失败
+	calls := 0
+	for i := 0; i < 3; i++ {
+		interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.NotFound, 5, &calls))
+	}
+	if calls != 3 {
+		t.Fatalf("Expected 3 calls before breaker opens, got %d", calls)
+	}
+
+	calls = 0
+	for i := 0; i < 3; i++ {
+		interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 5, &calls))
+	}
+	if calls != 2 {
+		t.Errorf("Expected breaker to open after 2 failures, got %d calls", calls)
+	}
+	err := interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 0, &calls))
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ServiceUnavailable {
+		t.Errorf("Expected ServiceUnavailable from open breaker, got %v", err)
+	}
+	if len(targets) != 1 || targets[0] != cc.Target() {
+		t.Errorf("Expected one breaker for %s, got %v", cc.Target(), targets)
+	}
+}
+
+func TestAuthTokenUnaryClientInterceptor(t *testing.T) {
+	cc := fakeConn(t)
+	var got []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get("authorization")
+		return nil
+	}
+
+	interceptor := AuthTokenUnaryClientInterceptor(func(ctx context.Context) (string, error) {
+		return "token-1", nil
+	})
+	if err := interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, invoker); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if len(got) != 1 || got[0] != "Bearer token-1" {
+		t.Errorf("authorization = %v, want [Bearer token-1]", got)
+	}
+
+	interceptor = AuthTokenUnaryClientInterceptor(func(ctx context.Context) (string, error) {
+		return "", errors.New("token expired")
+	})
+	err := interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, invoker)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.Unauthorized {
+		t.Errorf("Expected Unauthorized, got %v", err)
+	}
+}
+
+func TestMetricsUnaryClientInterceptor(t *testing.T) {
+	cc := fakeConn(t)
+	registry := prometheus.NewRegistry()
+	metrics, err := observability.NewMetricsCollectorWithConfig("client", &observability.MetricsConfig{Registerer: registry})
+	if err != nil {
+		t.Fatalf("NewMetricsCollectorWithConfig failed: %v", err)
+	}
+	interceptor := MetricsUnaryClientInterceptor(metrics)
+
+	calls := 0
+	interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 1, &calls))
+	interceptor(context.Background(), "/user.UserService/Get", nil, nil, cc, failingInvoker(codes.Unavailable, 1, &calls))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	counts := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "framework_request_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["service"] != "user.UserService" || labels["method"] != "Get" || labels["protocol"] != "gRPC" {
+				t.Errorf("Unexpected labels: %v", labels)
+			}
+			counts[labels["status"]] = metric.GetCounter().GetValue()
+		}
+	}
+	want := map[string]float64{"503": 1, observability.RequestStatusSuccess: 1}
+	if len(counts) != 2 || counts["503"] != 1 || counts[observability.RequestStatusSuccess] != 1 {
+		t.Errorf("request counts = %v, want %v", counts, want)
+	}
+}
+
+// fakeStream 依次返回 errs 中的接收结果
+type fakeStream struct {
+	grpc.ClientStream
+	errs []error
+}
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func TestObservedStream(t *testing.T) {
+	var finished []error
+	finish := func(err error) { finished = append(finished, err) }
+
+	// 服务端流在 io.EOF 时成功结束，只调用一次
+	stream := newObservedStream(&fakeStream{errs: []error{nil, nil, io.EOF, io.EOF}}, &grpc.StreamDesc{ServerStreams: true}, finish)
+	for i := 0; i < 4; i++ {
+		stream.RecvMsg(nil)
+	}
+	if len(finished) != 1 || finished[0] != nil {
+		t.Errorf("finished = %v, want [nil]", finished)
+	}
+
+	// 服务端只返回一个响应时收到即结束
+	finished = nil
+	newObservedStream(&fakeStream{errs: []error{nil}}, &grpc.StreamDesc{ClientStreams: true}, finish).RecvMsg(nil)
+	if len(finished) != 1 || finished[0] != nil {
+		t.Errorf("finished = %v, want [nil]", finished)
+	}
+
+	// 错误经 errorStream 还原为框架错误
+	finished = nil
+	failed := &errorStream{ClientStream: &fakeStream{errs: []error{status.Error(codes.DeadlineExceeded, "slow")}}}
+	newObservedStream(failed, &grpc.StreamDesc{ServerStreams: true}, finish).RecvMsg(nil)
+	if len(finished) != 1 {
+		t.Fatalf("finished = %v, want one error", finished)
+	}
+	if fe, ok := frameworkerrors.FromError(finished[0]); !ok || fe.Code != frameworkerrors.Timeout {
+		t.Errorf("Expected Timeout, got %v", finished[0])
+	}
+}
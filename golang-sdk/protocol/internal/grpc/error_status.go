@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// StatusFromError 将错误转换为 gRPC 状态
+//
+// 状态码按框架错误码映射，完整的错误以跨语言传输格式放在 google.protobuf.Struct 类型的 status details 中，
+// Java、PHP 等其他语言的 SDK 从 details 还原结构化错误
+func StatusFromError(ctx context.Context, err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		if _, isFramework := frameworkerrors.FromError(err); !isFramework {
+			// 已经是 gRPC 状态的错误原样返回
+			return st
+		}
+	}
+
+	payload := adapter.NewErrorPayload(ctx, err)
+	st := status.New(codes.Code(frameworkerrors.ErrorCode(payload.Code).ToGRPCStatus()), payload.Message)
+
+	detail, convErr := payloadToStruct(payload)
+	if convErr != nil {
+		return st
+	}
+	withDetails, detailErr := st.WithDetails(detail)
+	if detailErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// ErrorFromStatus 从 gRPC 状态还原框架错误，st 为 nil 或 OK 时返回 nil
+//
+// status details 中没有框架错误时按 gRPC 状态码映射错误码
+func ErrorFromStatus(st *status.Status) *frameworkerrors.FrameworkError {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	for _, detail := range st.Details() {
+		s, ok := detail.(*structpb.Struct)
+		if !ok {
+			continue
+		}
+		if fe, err := structToError(s); err == nil {
+			return fe
+		}
+	}
+	return frameworkerrors.NewFrameworkErrorFromGRPCStatus(int(st.Code()), st.Message())
+}
+
+// ErrorUnaryServerInterceptor 将处理器返回的错误转换为携带结构化错误的 gRPC 状态
+func ErrorUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, StatusFromError(ctx, err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// ErrorStreamServerInterceptor 流式调用的错误拦截器，将处理器返回的错误转换为与 ErrorUnaryServerInterceptor 相同的 gRPC 状态
+func ErrorStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, stream); err != nil {
+			return StatusFromError(stream.Context(), err).Err()
+		}
+		return nil
+	}
+}
+
+// ErrorUnaryClientInterceptor 将下游返回的 gRPC 状态还原为框架错误
+func ErrorUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return err
+		}
+		return ErrorFromStatus(st)
+	}
+}
+
+// payloadToStruct 将错误传输格式转换为 google.protobuf.Struct
+func payloadToStruct(payload *frameworkerrors.ErrorPayload) (*structpb.Struct, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
+
+// structToError 从 google.protobuf.Struct 还原框架错误
+func structToError(s *structpb.Struct) (*frameworkerrors.FrameworkError, error) {
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	return frameworkerrors.UnmarshalError(data)
+}
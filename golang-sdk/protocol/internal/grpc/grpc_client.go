@@ -2,10 +2,12 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -23,6 +25,10 @@ type GrpcClientConfig struct {
 	// TLS 配置（可选）
 	UseTLS   bool
 	CertFile string
+	// TLSConfig 不为 nil 时使用该配置建立 TLS 连接（如 mTLS 引导提供的客户端配置），优先于 UseTLS
+	TLSConfig *tls.Config
+	// Interceptors 客户端拦截器配置，为 nil 时只启用错误还原、安全上下文和追踪（见 ClientDialOptions）
+	Interceptors *ClientInterceptorConfig
 }
 
 // NewGrpcClient 创建 gRPC 客户端
@@ -37,14 +43,17 @@ func (c *GrpcClient) Connect(ctx context.Context) error {
 	target := fmt.Sprintf("%s:%d", c.config.Address, c.config.Port)
 	
 	// 配置连接选项
-	opts := []grpc.DialOption{
-		grpc.WithBlock(),
-	}
+	opts := append([]grpc.DialOption{grpc.WithBlock()}, ClientDialOptions(c.config.Interceptors)...)
 	
 	// 配置 TLS
-	if c.config.UseTLS {
-		// TODO: 实现 TLS 配置
-		return fmt.Errorf("TLS not implemented yet")
+	if c.config.TLSConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(c.config.TLSConfig)))
+	} else if c.config.UseTLS {
+		creds, err := credentials.NewClientTLSFromFile(c.config.CertFile, "")
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %v", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
 	} else {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
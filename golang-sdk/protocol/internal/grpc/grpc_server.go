@@ -2,18 +2,32 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
+	"strconv"
 
+	"github.com/framework/golang-sdk/lifecycle"
 	"github.com/gogf/gf/v2/os/glog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 )
 
 // GrpcServer gRPC 服务器
 type GrpcServer struct {
-	server   *grpc.Server
-	config   *GrpcServerConfig
-	listener net.Listener
+	server    *grpc.Server
+	config    *GrpcServerConfig
+	listeners []net.Listener
+	// services Start 之前注册的服务，创建 gRPC 服务器后注册
+	services []serviceRegistration
+}
+
+// serviceRegistration 待注册的服务
+type serviceRegistration struct {
+	desc *grpc.ServiceDesc
+	impl interface{}
 }
 
 // GrpcServerConfig gRPC 服务器配置
@@ -24,6 +38,12 @@ type GrpcServerConfig struct {
 	UseTLS   bool
 	CertFile string
 	KeyFile  string
+	// TLSConfig 不为 nil 时使用该配置（如 mTLS 引导提供的服务端配置），优先于 UseTLS
+	TLSConfig *tls.Config
+	// ExtraAddresses 额外监听的地址（host:port），同一 gRPC 服务器在 Host:Port 和这些地址上提供相同的服务
+	ExtraAddresses []string
+	// Listener 不为 nil 时代替 Host:Port 上的监听器，如测试时使用 memory.Listen 返回的进程内监听器
+	Listener net.Listener
 }
 
 // NewGrpcServer 创建 gRPC 服务器
@@ -36,37 +56,67 @@ func NewGrpcServer(config *GrpcServerConfig) *GrpcServer {
 // Start 启动 gRPC 服务器
 func (s *GrpcServer) Start() error {
 	// 创建监听器
-	address := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	addresses := s.config.ExtraAddresses
+	if s.config.Listener != nil {
+		s.listeners = append(s.listeners, s.config.Listener)
+	} else {
+		address := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+		addresses = append([]string{address}, addresses...)
+	}
+	for _, address := range addresses {
+		listener, err := lifecycle.Listen("tcp", address)
+		if err != nil {
+			s.closeListeners()
+			return fmt.Errorf("failed to listen on %s: %v", address, err)
+		}
+		s.listeners = append(s.listeners, listener)
 	}
-	
-	s.listener = listener
 	
 	// 配置服务器选项
-	opts := []grpc.ServerOption{}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			SecurityContextUnaryServerInterceptor(),
+			TraceContextUnaryServerInterceptor(),
+			TracingUnaryServerInterceptor(),
+			ErrorUnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			SecurityContextStreamServerInterceptor(),
+			TraceContextStreamServerInterceptor(),
+			TracingStreamServerInterceptor(),
+			ErrorStreamServerInterceptor(),
+		),
+	}
 	
 	// 配置 TLS
-	if s.config.UseTLS {
-		// TODO: 实现 TLS 配置
-		return fmt.Errorf("TLS not implemented yet")
+	if s.config.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.config.TLSConfig)))
+	} else if s.config.UseTLS {
+		creds, err := credentials.NewServerTLSFromFile(s.config.CertFile, s.config.KeyFile)
+		if err != nil {
+			s.closeListeners()
+			return fmt.Errorf("failed to load TLS certificate: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
 	}
 	
 	// 创建 gRPC 服务器
 	s.server = grpc.NewServer(opts...)
 	
-	// TODO: 注册服务
-	// pb.RegisterFrameworkServiceServer(s.server, &frameworkServiceImpl{})
-	
-	glog.Infof(context.Background(), "gRPC server starting on %s", address)
+	// 注册 Start 之前登记的服务
+	for _, service := range s.services {
+		s.server.RegisterService(service.desc, service.impl)
+	}
 	
-	// 启动服务器（阻塞）
-	go func() {
-		if err := s.server.Serve(listener); err != nil {
-			glog.Errorf(context.Background(), "gRPC server error: %v", err)
-		}
-	}()
+	// 在每个监听器上启动服务器（阻塞）
+	for _, listener := range s.listeners {
+		glog.Infof(context.Background(), "gRPC server starting on %s", listener.Addr())
+		go func(listener net.Listener) {
+			if err := s.server.Serve(listener); err != nil {
+				glog.Errorf(context.Background(), "gRPC server error: %v", err)
+			}
+		}(listener)
+	}
 	
 	return nil
 }
@@ -92,21 +142,43 @@ func (s *GrpcServer) Stop(ctx context.Context) error {
 		}
 	}
 	
-	if s.listener != nil {
-		s.listener.Close()
-	}
+	s.closeListeners()
 	
 	return nil
 }
 
+// closeListeners 关闭所有监听器
+func (s *GrpcServer) closeListeners() {
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+	s.listeners = nil
+}
+
 // GetServer 获取底层 gRPC 服务器
 func (s *GrpcServer) GetServer() *grpc.Server {
 	return s.server
 }
 
-// RegisterService 注册服务
+// RegisterService 注册服务，须在 Start 之前调用；实现了 grpc.ServiceRegistrar，可直接传给生成的 RegisterXxxServer
 func (s *GrpcServer) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
 	if s.server != nil {
 		s.server.RegisterService(desc, impl)
+		return
+	}
+	s.services = append(s.services, serviceRegistration{desc: desc, impl: impl})
+}
+
+// ServeHTTP 处理 gRPC-Web 网关转换后的 HTTP/2 请求，与监听端口上的调用经过相同的拦截器
+//
+// 服务器未启动时返回 Unavailable 状态
+func (s *GrpcServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.server == nil {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", strconv.Itoa(int(codes.Unavailable)))
+		w.Header().Set("Grpc-Message", "grpc server not started")
+		w.WriteHeader(http.StatusOK)
+		return
 	}
+	s.server.ServeHTTP(w, r)
 }
@@ -2,8 +2,29 @@ package grpc
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
 	"testing"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/security"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // TestGrpcClientCreation 测试 gRPC 客户端创建
@@ -129,6 +150,61 @@ func TestGrpcClientConnectToServer(t *testing.T) {
 	}
 }
 
+// TestGrpcMutualTLS 测试双向 TLS：出示同一 CA 签发证书的客户端可以连接，没有证书的客户端被拒绝
+func TestGrpcMutualTLS(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	ca, _ := x509.ParseCertificate(der)
+	mtls, err := security.NewMTLSBootstrapper(context.Background(), &security.MTLSConfig{
+		Source: &security.LocalCASource{CA: ca, CAKey: key, CommonName: "grpc-test"},
+	})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer mtls.Close()
+
+	server := NewGrpcServer(&GrpcServerConfig{
+		Host:      "127.0.0.1",
+		Port:      9007,
+		TLSConfig: mtls.ServerTLSConfig(tls.RequireAndVerifyClientCert),
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start gRPC server: %v", err)
+	}
+	defer server.Stop(context.Background())
+
+	client := NewGrpcClient(&GrpcClientConfig{
+		Address:   "127.0.0.1",
+		Port:      9007,
+		Timeout:   5 * time.Second,
+		TLSConfig: mtls.ClientTLSConfig(),
+	})
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Failed to connect with certificate: %v", err)
+	}
+	client.Close()
+
+	plain := NewGrpcClient(&GrpcClientConfig{
+		Address:   "127.0.0.1",
+		Port:      9007,
+		Timeout:   500 * time.Millisecond,
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	})
+	if err := plain.Connect(context.Background()); err == nil {
+		plain.Close()
+		t.Error("Expected connection without client certificate to fail")
+	}
+}
+
 // TestGrpcClientClose 测试客户端关闭
 func TestGrpcClientClose(t *testing.T) {
 	// 启动服务器
@@ -187,3 +263,298 @@ func TestGrpcClientCallWithoutConnection(t *testing.T) {
 		t.Error("Expected error when calling without connection")
 	}
 }
+
+// TestSecurityContextPropagation 测试安全上下文通过 gRPC 元数据传递
+func TestSecurityContextPropagation(t *testing.T) {
+	ctx := adapter.WithSecurityContext(context.Background(), &adapter.SecurityContext{
+		UserID:     "user-1",
+		Roles:      []string{"admin"},
+		TenantID:   "tenant-a",
+		AuthMethod: adapter.AuthMethodJWT,
+	})
+
+	// 客户端写入出站元数据
+	outgoing := withOutgoingSecurityContext(ctx)
+	md, ok := metadata.FromOutgoingContext(outgoing)
+	if !ok {
+		t.Fatal("Expected outgoing metadata")
+	}
+	if got := md.Get("x-auth-user-id"); len(got) != 1 || got[0] != "user-1" {
+		t.Errorf("Expected x-auth-user-id user-1, got %v", got)
+	}
+
+	// 服务端从入站元数据恢复
+	incoming := withIncomingSecurityContext(metadata.NewIncomingContext(context.Background(), md))
+	sc := adapter.SecurityContextFromContext(incoming)
+	if sc == nil {
+		t.Fatal("Expected security context on server side")
+	}
+	if sc.UserID != "user-1" || sc.TenantID != "tenant-a" || len(sc.Roles) != 1 {
+		t.Errorf("Unexpected security context: %+v", sc)
+	}
+
+	// 流式调用的服务端拦截器同样恢复安全上下文
+	stream := &contextServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+	err := SecurityContextStreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		sc = adapter.SecurityContextFromContext(stream.Context())
+		return nil
+	})
+	if err != nil || sc == nil || sc.UserID != "user-1" {
+		t.Errorf("Expected security context on stream, got %+v (%v)", sc, err)
+	}
+}
+
+// TestTraceContextPropagation 测试 W3C 追踪上下文通过 gRPC 元数据传递
+func TestTraceContextPropagation(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	// 客户端写入出站元数据
+	outgoing := withOutgoingTraceContext(ctx)
+	md, ok := metadata.FromOutgoingContext(outgoing)
+	if !ok {
+		t.Fatal("Expected outgoing metadata")
+	}
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := md.Get("traceparent"); len(got) != 1 || got[0] != want {
+		t.Errorf("Expected traceparent %s, got %v", want, got)
+	}
+
+	// 服务端从入站元数据恢复
+	incoming := withIncomingTraceContext(metadata.NewIncomingContext(context.Background(), md))
+	sc := trace.SpanContextFromContext(incoming)
+	if sc.TraceID() != traceID || !sc.IsRemote() {
+		t.Errorf("Unexpected span context: %+v", sc)
+	}
+
+	// 流式调用的服务端拦截器同样恢复上游 span 上下文，W3C 和 B3 格式均可
+	b3 := metadata.Pairs("x-b3-traceid", "4bf92f3577b34da6a3ce929d0e0e4736", "x-b3-spanid", "00f067aa0ba902b7", "x-b3-sampled", "1")
+	for _, incomingMD := range []metadata.MD{md, b3} {
+		stream := &contextServerStream{ctx: metadata.NewIncomingContext(context.Background(), incomingMD)}
+		var streamSC trace.SpanContext
+		err := TraceContextStreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			streamSC = trace.SpanContextFromContext(stream.Context())
+			return nil
+		})
+		if err != nil || streamSC.TraceID() != traceID || streamSC.SpanID() != spanID || !streamSC.IsRemote() {
+			t.Errorf("Unexpected stream span context from %v: %+v (%v)", incomingMD, streamSC, err)
+		}
+	}
+}
+
+// TestTracingStreamServerInterceptor 测试流式调用的服务端 span 以上游 span 为父 span，在处理器返回时结束
+func TestTracingStreamServerInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	md := metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	stream := &contextServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+	info := &grpc.StreamServerInfo{FullMethod: "/chat.ChatService/Subscribe", IsServerStream: true}
+
+	var handlerSC trace.SpanContext
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		handlerSC = trace.SpanContextFromContext(stream.Context())
+		return frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "room not found")
+	}
+	err := TraceContextStreamServerInterceptor()(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return TracingStreamServerInterceptor()(srv, stream, info, handler)
+	})
+	if err == nil {
+		t.Fatal("Expected handler error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "chat.ChatService/Subscribe" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Unexpected span %s (%v)", span.Name(), span.SpanKind())
+	}
+	if span.Parent().SpanID().String() != "00f067aa0ba902b7" || span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected span to continue the upstream trace, got parent %v", span.Parent())
+	}
+	if span.Status().Code != otelcodes.Error {
+		t.Errorf("Expected error status, got %v", span.Status())
+	}
+	if handlerSC.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Expected handler context to carry the server span, got %v", handlerSC.SpanID())
+	}
+}
+
+// TestStatusFromError 测试框架错误与 gRPC 状态的相互转换
+func TestStatusFromError(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	err := frameworkerrors.NewFrameworkErrorWithDetails(frameworkerrors.Timeout, "调用超时", "3s").
+		WithServiceID("order-service").
+		WithDetail("attempt", 2)
+
+	st := StatusFromError(ctx, err)
+	if st.Code() != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", st.Code())
+	}
+	if st.Message() != "调用超时" {
+		t.Errorf("Expected message '调用超时', got %s", st.Message())
+	}
+
+	// 通过 status 错误往返后还原全部字段
+	roundTrip, _ := status.FromError(st.Err())
+	fe := ErrorFromStatus(roundTrip)
+	if fe.Code != frameworkerrors.Timeout || fe.Details != "3s" || fe.ServiceID != "order-service" {
+		t.Errorf("Unexpected error: %+v", fe)
+	}
+	if fe.TraceID != traceID.String() {
+		t.Errorf("Expected trace ID %s, got %s", traceID, fe.TraceID)
+	}
+	if fe.Fields["attempt"] != float64(2) {
+		t.Errorf("Unexpected fields: %v", fe.Fields)
+	}
+	if fe.Timestamp != err.Timestamp {
+		t.Errorf("Expected timestamp %d, got %d", err.Timestamp, fe.Timestamp)
+	}
+}
+
+// TestErrorStreamServerInterceptor 测试流式处理器返回的错误与一元调用同样转换为携带结构化错误的 gRPC 状态
+func TestErrorStreamServerInterceptor(t *testing.T) {
+	stream := &contextServerStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/chat.ChatService/Subscribe", IsServerStream: true}
+
+	err := ErrorStreamServerInterceptor()(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return frameworkerrors.NewFrameworkErrorWithDetails(frameworkerrors.NotFound, "room not found", "room-1")
+	})
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.NotFound || len(st.Details()) != 1 {
+		t.Fatalf("Expected NotFound status with details, got %v", err)
+	}
+	if fe := ErrorFromStatus(st); fe.Code != frameworkerrors.NotFound || fe.Details != "room-1" {
+		t.Errorf("Unexpected error: %+v", fe)
+	}
+
+	err = ErrorStreamServerInterceptor()(nil, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+// TestStatusFromErrorContext 测试调用方取消与超时使用不同的状态码
+func TestStatusFromErrorContext(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		want frameworkerrors.ErrorCode
+	}{
+		{"超时", context.DeadlineExceeded, codes.DeadlineExceeded, frameworkerrors.Timeout},
+		{"取消", context.Canceled, codes.Canceled, frameworkerrors.ClientClosedRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := StatusFromError(context.Background(), tt.err)
+			if st.Code() != tt.code {
+				t.Errorf("Expected %v, got %v", tt.code, st.Code())
+			}
+			if fe := ErrorFromStatus(st); fe.Code != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, fe.Code)
+			}
+			// 没有结构化详情时同样按状态码区分
+			if fe := ErrorFromStatus(status.New(tt.code, "")); fe.Code != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, fe.Code)
+			}
+		})
+	}
+}
+
+// TestStatusFromErrorPlain 测试非框架错误的转换
+func TestStatusFromErrorPlain(t *testing.T) {
+	if StatusFromError(context.Background(), nil) != nil {
+		t.Error("Expected nil status for nil error")
+	}
+
+	// 已经是 gRPC 状态的错误原样返回
+	grpcErr := status.Error(codes.PermissionDenied, "denied")
+	if st := StatusFromError(context.Background(), grpcErr); st.Code() != codes.PermissionDenied || len(st.Details()) != 0 {
+		t.Errorf("Unexpected status: %v", st)
+	}
+
+	// 普通错误作为 InternalError 传递
+	st := StatusFromError(context.Background(), errors.New("boom"))
+	if st.Code() != codes.Internal {
+		t.Errorf("Expected Internal, got %v", st.Code())
+	}
+
+	// 没有结构化详情时按状态码映射
+	fe := ErrorFromStatus(status.New(codes.NotFound, "missing"))
+	if fe.Code != frameworkerrors.NotFound || fe.Message != "missing" {
+		t.Errorf("Unexpected error: %+v", fe)
+	}
+	if ErrorFromStatus(status.New(codes.OK, "")) != nil {
+		t.Error("Expected nil error for OK status")
+	}
+
+	// adapter 错误同样携带结构化详情
+	adapterErr := &adapter.FrameworkError{Code: adapter.ErrorRouting, Message: "no endpoint"}
+	if fe := ErrorFromStatus(StatusFromError(context.Background(), adapterErr)); fe.Code != frameworkerrors.RoutingError {
+		t.Errorf("Expected RoutingError, got %v", fe.Code)
+	}
+}
+
+// TestExternalRequestFromContext 测试 gRPC 入站调用经协议适配器转换，错误以结构化 status details 返回
+func TestExternalRequestFromContext(t *testing.T) {
+	md := metadata.Pairs("x-tenant-id", "tenant-a", "x-request-id", "req-1", "x-token-bin", "\x00\x01")
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	external := ExternalRequestFromContext(ctx, "/order.OrderService/Create", map[string]interface{}{"sku": "A1"})
+	if external.Protocol != adapter.ProtocolGRPC || external.Metadata.RequestId != "req-1" {
+		t.Errorf("Unexpected external request: %+v", external)
+	}
+	if external.Headers["X-Tenant-Id"] != "tenant-a" || external.Headers["X-Token-Bin"] != "AAE=" {
+		t.Errorf("Expected metadata to be mapped to headers, got %v", external.Headers)
+	}
+
+	protocolAdapter := adapter.NewDefaultProtocolAdapter()
+	internal, err := protocolAdapter.TransformRequest(ctx, external)
+	if err != nil {
+		t.Fatalf("TransformRequest failed: %v", err)
+	}
+	if internal.Service != "order.OrderService" || internal.Method != "Create" {
+		t.Errorf("Expected order.OrderService.Create, got %s.%s", internal.Service, internal.Method)
+	}
+
+	response, err := protocolAdapter.TransformResponse(ctx, &adapter.InternalResponse{
+		Error: &adapter.FrameworkError{Code: adapter.ErrorForbidden, Message: "库存不足", Details: map[string]interface{}{"sku": "A1"}},
+	}, adapter.ProtocolGRPC)
+	if err != nil {
+		t.Fatalf("TransformResponse failed: %v", err)
+	}
+	st, _ := status.FromError(SendExternalResponse(ctx, response))
+	if st.Code() != codes.Code(response.StatusCode) {
+		t.Errorf("Expected status code %d, got %v", response.StatusCode, st.Code())
+	}
+	fe := ErrorFromStatus(st)
+	if fe == nil || fe.Code != frameworkerrors.Forbidden || fe.Message != "库存不足" {
+		t.Fatalf("Unexpected error: %+v", fe)
+	}
+	if details, ok := fe.Fields[adapter.DetailsField].(map[string]interface{}); !ok || details["sku"] != "A1" {
+		t.Errorf("Expected structured details, got %#v", fe.Fields)
+	}
+
+	if err := SendExternalResponse(ctx, &adapter.ExternalResponse{Protocol: adapter.ProtocolGRPC}); err != nil {
+		t.Errorf("Expected nil error for successful response, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// SecurityContextUnaryClientInterceptor 将 context 中的安全上下文写入 gRPC 出站元数据
+func SecurityContextUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingSecurityContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// SecurityContextStreamClientInterceptor 流式调用的安全上下文客户端拦截器
+func SecurityContextStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingSecurityContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// SecurityContextUnaryServerInterceptor 从 gRPC 入站元数据恢复安全上下文
+func SecurityContextUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withIncomingSecurityContext(ctx), req)
+	}
+}
+
+// SecurityContextStreamServerInterceptor 流式调用的安全上下文服务端拦截器，处理器经 stream.Context() 读取安全上下文
+func SecurityContextStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: withIncomingSecurityContext(stream.Context())})
+	}
+}
+
+// contextServerStream 替换 Context 的服务端流
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回替换后的 context
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// withOutgoingSecurityContext 追加安全上下文元数据
+func withOutgoingSecurityContext(ctx context.Context) context.Context {
+	sc := adapter.SecurityContextFromContext(ctx)
+	if sc == nil {
+		return ctx
+	}
+
+	pairs := make([]string, 0, 8)
+	for key, value := range sc.ToHeaders() {
+		pairs = append(pairs, strings.ToLower(key), value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// withIncomingSecurityContext 解析入站元数据中的安全上下文
+func withIncomingSecurityContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	headers := make(map[string]string)
+	for _, name := range adapter.SecurityHeaders() {
+		if values := md.Get(name); len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+
+	if sc := adapter.SecurityContextFromHeaders(headers); sc != nil {
+		return adapter.WithSecurityContext(ctx, sc)
+	}
+	return ctx
+}
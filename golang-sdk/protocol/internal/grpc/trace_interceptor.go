@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	frameworkmetadata "github.com/framework/golang-sdk/metadata"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// TraceContextUnaryClientInterceptor 将 context 中的 span 上下文以 W3C 格式写入 gRPC 出站元数据
+func TraceContextUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingTraceContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// TraceContextStreamClientInterceptor 流式调用的追踪上下文客户端拦截器
+func TraceContextStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingTraceContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// TraceContextUnaryServerInterceptor 从 gRPC 入站元数据恢复上游 span 上下文
+func TraceContextUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withIncomingTraceContext(ctx), req)
+	}
+}
+
+// TraceContextStreamServerInterceptor 流式调用的追踪上下文服务端拦截器，处理器经 stream.Context() 读取上游 span 上下文
+func TraceContextStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: withIncomingTraceContext(stream.Context())})
+	}
+}
+
+// TracingUnaryClientInterceptor 为每次出站调用创建客户端 span
+//
+// 需位于 TraceContextUnaryClientInterceptor 之前，使下游收到的父 span 为该客户端 span
+func TracingUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		service, name := splitFullMethod(method)
+		ctx, span := adapter.StartClientSpan(ctx, adapter.ProtocolGRPC, service, name, cc.Target())
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		adapter.EndSpan(span, err)
+		return err
+	}
+}
+
+// TracingUnaryServerInterceptor 为每次入站调用创建服务端 span
+//
+// 需位于 TraceContextUnaryServerInterceptor 之后，使服务端 span 成为上游 span 的子 span
+func TracingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		adapter.EndSpan(span, err)
+		return resp, err
+	}
+}
+
+// TracingStreamServerInterceptor 为每个入站流创建服务端 span，处理器返回时结束，处理器经 stream.Context() 取得该 span
+//
+// 需位于 TraceContextStreamServerInterceptor 之后，使服务端 span 成为上游 span 的子 span
+func TracingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(stream.Context(), info.FullMethod)
+		err := handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+		adapter.EndSpan(span, err)
+		return err
+	}
+}
+
+// startServerSpan 为入站调用创建服务端 span，记录调用方地址
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	service, name := splitFullMethod(fullMethod)
+	ctx, span := adapter.StartServerSpan(ctx, adapter.ProtocolGRPC, service, name)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			span.SetAttributes(attribute.String("client.address", host))
+		}
+	}
+	return ctx, span
+}
+
+// splitFullMethod 将 /package.Service/Method 拆分为服务名和方法名
+func splitFullMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+// withOutgoingTraceContext 追加追踪上下文元数据
+func withOutgoingTraceContext(ctx context.Context) context.Context {
+	headers := make(map[string]string)
+	adapter.InjectTraceContext(ctx, headers)
+	if len(headers) == 0 {
+		return ctx
+	}
+
+	pairs := make([]string, 0, len(headers)*2)
+	for key, value := range headers {
+		pairs = append(pairs, strings.ToLower(key), value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// withIncomingTraceContext 解析入站元数据中的追踪上下文和框架元数据
+func withIncomingTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	headers := make(map[string]string)
+	for _, name := range adapter.TraceHeaders() {
+		if values := md.Get(name); len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	// gRPC 元数据的键为小写，元数据请求头按前缀匹配
+	prefix := strings.ToLower(frameworkmetadata.HeaderPrefix)
+	for name, values := range md {
+		if strings.HasPrefix(name, prefix) && len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	return adapter.ExtractTraceContext(ctx, headers)
+}
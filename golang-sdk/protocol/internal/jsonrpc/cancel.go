@@ -0,0 +1,130 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// CancelMethod 取消通知的方法名，params 为 {"id": <请求 ID>}；调用方放弃等待时在同一连接上发送，
+// 服务端取消该请求的 context，处理器应在 ctx 结束后尽快返回。按 JSON-RPC 2.0 约定以 rpc. 开头的方法名为协议扩展
+//
+//	{"jsonrpc":"2.0","method":"rpc.cancel","params":{"id":42}}
+//
+// 通知不带 id，服务端不返回响应；带 id 时返回是否找到该请求。取消通知在请求开始处理前到达或请求已完成时被忽略，
+// 请求仍受截止时间限制
+const CancelMethod = "rpc.cancel"
+
+// cancelMarker 快速判断读取的数据是否可能为取消通知
+var cancelMarker = []byte(`"` + CancelMethod + `"`)
+
+// cancelParams 取消通知的参数
+type cancelParams struct {
+	Id interface{} `json:"id"`
+}
+
+// callTracker 连接上处理中的请求，按请求 ID 取消
+type callTracker struct {
+	mu    sync.Mutex
+	calls map[string]*trackedCall
+}
+
+// trackedCall 一个处理中的请求
+type trackedCall struct {
+	cancel context.CancelFunc
+}
+
+// newCallTracker 创建请求跟踪器
+func newCallTracker() *callTracker {
+	return &callTracker{calls: make(map[string]*trackedCall)}
+}
+
+// start 返回可按 id 取消的 context，请求处理完成后须调用 done；id 为 null 的通知不跟踪
+func (t *callTracker) start(ctx context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	key, ok := idKey(id)
+	if !ok {
+		return ctx, cancel
+	}
+	call := &trackedCall{cancel: cancel}
+	t.mu.Lock()
+	t.calls[key] = call
+	t.mu.Unlock()
+	return ctx, func() {
+		t.mu.Lock()
+		if t.calls[key] == call {
+			delete(t.calls, key)
+		}
+		t.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel 取消 id 对应的处理中的请求，返回是否找到该请求
+func (t *callTracker) cancel(id interface{}) bool {
+	key, ok := idKey(id)
+	if !ok {
+		return false
+	}
+	t.mu.Lock()
+	call := t.calls[key]
+	t.mu.Unlock()
+	if call == nil {
+		return false
+	}
+	call.cancel()
+	return true
+}
+
+// parseCancel 读取的数据为取消通知（或带 id 的取消请求）时返回该通知，批量请求不作为取消通知
+func parseCancel(data json.RawMessage) (*JsonRpcRequest, *cancelParams, bool) {
+	if len(data) == 0 || data[0] != '{' || !bytes.Contains(data, cancelMarker) {
+		return nil, nil, false
+	}
+	var request JsonRpcRequest
+	if err := json.Unmarshal(data, &request); err != nil || request.Method != CancelMethod {
+		return nil, nil, false
+	}
+	var params cancelParams
+	if raw, err := json.Marshal(request.Params); err == nil {
+		json.Unmarshal(raw, &params)
+	}
+	return &request, &params, true
+}
+
+// idKey 返回请求 ID 的规范 JSON 编码，数字 1 与字符串 "1" 不同；ID 为 null 时 ok 为 false
+func idKey(id interface{}) (string, bool) {
+	if id == nil {
+		return "", false
+	}
+	data, err := json.Marshal(id)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// contextErrorResponse 请求在处理前已过截止时间或被取消，不调用处理器，返回 Timeout 或 ClientClosedRequest 错误
+func contextErrorResponse(ctx context.Context, request *JsonRpcRequest) *JsonRpcResponse {
+	code, _ := frameworkerrors.ContextErrorCode(ctx.Err())
+	err := frameworkerrors.NewFrameworkError(code, fmt.Sprintf("method %s: %v before handling", request.Method, ctx.Err()))
+	payload := adapter.NewErrorPayload(ctx, err)
+	return newErrorResponse(request.Id, frameworkerrors.ErrorCode(payload.Code).ToJSONRPCCode(), payload.Message, payload)
+}
+
+// sendCancel 通知服务端取消 ids 对应的请求，调用方已放弃等待，发送失败时忽略
+func (c *InternalJsonRpcClient) sendCancel(ids []uint64) {
+	var data []byte
+	for _, id := range ids {
+		data = append(data, `{"jsonrpc":"2.0","method":"`+CancelMethod+`","params":{"id":`+strconv.FormatUint(id, 10)+`}}`+"\n"...)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.Write(data)
+}
@@ -0,0 +1,71 @@
+package jsonrpc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+// lingerTimeout 关闭连接前丢弃对端剩余数据的最长时间
+const lingerTimeout = time.Second
+
+// DefaultMaxMessageSize 单个请求（包括批量请求）默认的字节数上限
+const DefaultMaxMessageSize = 4 << 20
+
+// errMessageTooLarge 请求超过字节数上限
+var errMessageTooLarge = errors.New("message too large")
+
+// frameReader 限制连接上每个 JSON 值的字节数
+//
+// json.Decoder 会预读后续数据，因此按连接上的绝对偏移限制读取：当前值从 start 开始，读取不超过 start+max，
+// 解码器需要更多数据才能完成当前值时返回 errMessageTooLarge，而不是无限缓冲直到内存耗尽
+type frameReader struct {
+	r     io.Reader
+	max   int64
+	read  int64 // 已从连接读取的字节数
+	start int64 // 当前值在连接上的起始偏移
+}
+
+// newFrameReader 创建限制每个值不超过 max 字节的读取器
+func newFrameReader(r io.Reader, max int) *frameReader {
+	return &frameReader{r: r, max: int64(max)}
+}
+
+// Read 从连接读取，不超过当前值的字节数上限
+func (f *frameReader) Read(p []byte) (int, error) {
+	remaining := f.start + f.max - f.read
+	if remaining <= 0 {
+		return 0, errMessageTooLarge
+	}
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := f.r.Read(p)
+	f.read += int64(n)
+	return n, err
+}
+
+// next 开始读取下一个值，offset 为上一个值结束处的偏移（json.Decoder.InputOffset）
+func (f *frameReader) next(offset int64) {
+	f.start = offset
+}
+
+// maxMessageSize 返回配置的字节数上限，未配置时为 DefaultMaxMessageSize
+func maxMessageSize(config *InternalJsonRpcConfig) int {
+	if config.MaxMessageSize > 0 {
+		return config.MaxMessageSize
+	}
+	return DefaultMaxMessageSize
+}
+
+// lingerClose 关闭写方向后在 lingerTimeout 内丢弃对端仍在发送的数据
+//
+// 连接上有未读数据时直接关闭会发送 RST，对端可能在读到已写回的错误响应之前就收到连接重置
+func lingerClose(conn net.Conn, max int) {
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	}
+	conn.SetReadDeadline(time.Now().Add(lingerTimeout))
+	io.Copy(io.Discard, io.LimitReader(conn, int64(max)))
+}
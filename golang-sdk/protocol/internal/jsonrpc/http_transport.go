@@ -0,0 +1,79 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// 客户端传输方式
+const (
+	// TransportTCP 以 TCP 连接发送换行分隔的 JSON 请求，服务端为 InternalJsonRpcHandler
+	TransportTCP = "tcp"
+	// TransportHTTP 以 HTTP POST 发送请求，服务端为 PHP、Java 等 SDK 提供的 JSON-RPC 端点
+	TransportHTTP = "http"
+)
+
+// DefaultHTTPPath HTTP 传输默认的请求路径，与各语言 SDK 的服务端一致
+const DefaultHTTPPath = "/jsonrpc"
+
+// maxHTTPResponseSize HTTP 响应体的大小上限
+const maxHTTPResponseSize = 16 << 20
+
+// connectHTTP 准备 HTTP 传输的客户端和请求 URL
+func (c *InternalJsonRpcClient) connectHTTP(address string) {
+	scheme := "http"
+	if c.config.TLSConfig != nil {
+		scheme = "https"
+	}
+	path := c.config.Path
+	if path == "" {
+		path = DefaultHTTPPath
+	}
+	c.url = scheme + "://" + address + path
+
+	c.http = c.config.HTTPClient
+	if c.http == nil {
+		c.http = &http.Client{Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+			TLSClientConfig:     c.config.TLSConfig,
+			ForceAttemptHTTP2:   c.config.TLSConfig != nil,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		}}
+	}
+}
+
+// postHTTP 以 HTTP POST 发送请求并读取响应体
+//
+// 非 2xx 状态码的响应体为 JSON-RPC 错误响应时照常返回，由调用方解析错误；否则返回包含状态码的错误
+func (c *InternalJsonRpcClient) postHTTP(ctx context.Context, data []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	trimmed := bytes.TrimSpace(body)
+	if resp.StatusCode/100 != 2 && (len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[')) {
+		return nil, fmt.Errorf("JSON-RPC endpoint %s returned HTTP %d", c.url, resp.StatusCode)
+	}
+	return trimmed, nil
+}
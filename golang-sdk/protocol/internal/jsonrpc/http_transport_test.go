@@ -0,0 +1,132 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// jsonRpcEndpoint 模拟其他语言 SDK 的 JSON-RPC over HTTP 端点，记录请求的远端地址
+func jsonRpcEndpoint(t *testing.T, remotes *sync.Map) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(DefaultHTTPPath, func(w http.ResponseWriter, r *http.Request) {
+		remotes.Store(r.RemoteAddr, true)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		var request JsonRpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response := &JsonRpcResponse{Jsonrpc: "2.0", Id: request.Id}
+		if request.Method == "hello.fail" {
+			payload := adapter.NewErrorPayload(r.Context(), frameworkerrors.NewFrameworkError(frameworkerrors.NotFound, "user not found"))
+			response.Error = &JsonRpcError{Code: -32001, Message: payload.Message, Data: payload}
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			response.Result = map[string]interface{}{"method": request.Method, "params": request.Params}
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+	return mux
+}
+
+// httpClientConfig 返回指向测试服务器的 HTTP 传输配置
+func httpClientConfig(server *httptest.Server) *InternalJsonRpcConfig {
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return &InternalJsonRpcConfig{Host: host, Port: portNum, Transport: TransportHTTP}
+}
+
+func TestHTTPTransport(t *testing.T) {
+	var remotes sync.Map
+	server := httptest.NewServer(jsonRpcEndpoint(t, &remotes))
+	defer server.Close()
+
+	client := NewInternalJsonRpcClient(httpClientConfig(server))
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		result, err := client.Call(context.Background(), "hello.sayHello", map[string]interface{}{"name": "Go"}, i)
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		reply, _ := result.(map[string]interface{})
+		if reply["method"] != "hello.sayHello" {
+			t.Errorf("Unexpected result: %v", result)
+		}
+	}
+
+	// 依次发起的调用复用同一个 keep-alive 连接
+	count := 0
+	remotes.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Errorf("Expected calls to reuse 1 connection, got %d", count)
+	}
+
+	// 非 2xx 状态码携带的 JSON-RPC 错误还原为框架错误
+	_, err := client.Call(context.Background(), "hello.fail", nil, 4)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestHTTPTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := NewInternalJsonRpcClient(httpClientConfig(server))
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	// 响应不是 JSON-RPC 时返回包含状态码的错误
+	if _, err := client.Call(context.Background(), "hello.sayHello", nil, 1); err == nil {
+		t.Error("Expected error for HTTP 404")
+	}
+
+	invalid := NewInternalJsonRpcClient(&InternalJsonRpcConfig{Host: "127.0.0.1", Port: 1, Transport: "udp"})
+	if err := invalid.Connect(); err == nil {
+		t.Error("Expected error for unsupported transport")
+	}
+}
+
+func TestHTTPSTransport(t *testing.T) {
+	var remotes sync.Map
+	server := httptest.NewTLSServer(jsonRpcEndpoint(t, &remotes))
+	defer server.Close()
+
+	config := httpClientConfig(server)
+	config.TLSConfig = &tls.Config{}
+	config.HTTPClient = server.Client()
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.Call(context.Background(), "hello.ping", nil, 1)
+	if err != nil {
+		t.Fatalf("Call over HTTPS failed: %v", err)
+	}
+	if reply, _ := result.(map[string]interface{}); reply["method"] != "hello.ping" {
+		t.Errorf("Unexpected result: %v", result)
+	}
+}
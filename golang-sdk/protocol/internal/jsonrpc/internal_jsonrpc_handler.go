@@ -3,12 +3,22 @@ package jsonrpc
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/internal/rpcbind"
+	"github.com/framework/golang-sdk/lifecycle"
+	"github.com/framework/golang-sdk/protocol/adapter"
 	"github.com/gogf/gf/v2/os/glog"
 )
 
@@ -19,14 +29,41 @@ type InternalJsonRpcHandler struct {
 	handlers map[string]MethodHandler
 	mu       sync.RWMutex
 	stopChan chan struct{}
+	conns    map[net.Conn]struct{}
+	connsMu  sync.Mutex
+	connWg   sync.WaitGroup
 }
 
 // InternalJsonRpcConfig 内部 JSON-RPC 配置
 type InternalJsonRpcConfig struct {
 	Host string
 	Port int
+	// Listener 不为 nil 时服务端在该监听器上接受连接并忽略 Host 和 Port，如测试时使用 memory.Listen 返回的进程内监听器
+	Listener net.Listener
+	// Dialer 不为 nil 时 TCP 传输的客户端以它建立连接（如 memory.DialContext），为 nil 时使用 net.Dial
+	Dialer func(ctx context.Context, network, address string) (net.Conn, error)
+	// MaxConcurrentRequests 每个连接上同时处理的请求数上限，达到上限后暂停读取该连接的后续请求，
+	// 为 0 时使用 DefaultMaxConcurrentRequests；批量请求计为一个请求，其中的请求只使用空闲的名额并发执行
+	MaxConcurrentRequests int
+	// MaxMessageSize TCP 传输上单个请求（包括批量请求）的字节数上限，为 0 时使用 DefaultMaxMessageSize；
+	// 服务端对超过上限的请求返回 -32600 错误后关闭连接，客户端不发送超过上限的请求并返回 BadRequest
+	MaxMessageSize int
+	// Transport 客户端的传输方式，为空时使用 TransportTCP；TransportHTTP 以 HTTP POST 调用其他语言 SDK 的 JSON-RPC 端点
+	Transport string
+	// Path HTTP 传输的请求路径，为空时使用 DefaultHTTPPath
+	Path string
+	// TLSConfig 不为 nil 时服务端监听器和 TCP 传输使用 TLS，HTTP 传输使用 HTTPS；
+	// 服务端传入服务端配置，客户端传入客户端配置（如 mTLS 引导提供的配置）
+	TLSConfig *tls.Config
+	// HTTPClient HTTP 传输使用的客户端，为 nil 时创建复用 keep-alive 连接的客户端
+	HTTPClient *http.Client
+	// CallTimeout 客户端每次调用（包括批量调用）等待响应的时间，为 0 时只受调用方 ctx 限制
+	CallTimeout time.Duration
 }
 
+// DefaultMaxConcurrentRequests 每个连接默认的并发请求数上限
+const DefaultMaxConcurrentRequests = 64
+
 // MethodHandler 方法处理器
 type MethodHandler func(ctx context.Context, params interface{}) (interface{}, error)
 
@@ -36,6 +73,7 @@ func NewInternalJsonRpcHandler(config *InternalJsonRpcConfig) *InternalJsonRpcHa
 		config:   config,
 		handlers: make(map[string]MethodHandler),
 		stopChan: make(chan struct{}),
+		conns:    make(map[net.Conn]struct{}),
 	}
 }
 
@@ -43,9 +81,18 @@ func NewInternalJsonRpcHandler(config *InternalJsonRpcConfig) *InternalJsonRpcHa
 func (h *InternalJsonRpcHandler) Start() error {
 	address := fmt.Sprintf("%s:%d", h.config.Host, h.config.Port)
 	
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	listener := h.config.Listener
+	if listener != nil {
+		address = listener.Addr().String()
+	} else {
+		var err error
+		listener, err = lifecycle.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %v", address, err)
+		}
+	}
+	if h.config.TLSConfig != nil {
+		listener = tls.NewListener(listener, h.config.TLSConfig)
 	}
 	
 	h.listener = listener
@@ -58,6 +105,8 @@ func (h *InternalJsonRpcHandler) Start() error {
 }
 
 // Stop 停止内部 JSON-RPC 服务器
+//
+// 停止接受新连接和已有连接上的新请求，等待处理中的请求写回响应后关闭连接；ctx 结束时直接关闭剩余连接
 func (h *InternalJsonRpcHandler) Stop(ctx context.Context) error {
 	close(h.stopChan)
 	
@@ -65,6 +114,28 @@ func (h *InternalJsonRpcHandler) Stop(ctx context.Context) error {
 		h.listener.Close()
 	}
 	
+	// 读取超时使各连接的读取循环退出
+	h.connsMu.Lock()
+	for conn := range h.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	h.connsMu.Unlock()
+	
+	done := make(chan struct{})
+	go func() {
+		h.connWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		h.connsMu.Lock()
+		for conn := range h.conns {
+			conn.Close()
+		}
+		h.connsMu.Unlock()
+	}
+	
 	glog.Info(ctx, "Internal JSON-RPC server stopped")
 	return nil
 }
@@ -77,6 +148,21 @@ func (h *InternalJsonRpcHandler) RegisterMethod(method string, handler MethodHan
 	h.handlers[method] = handler
 }
 
+// RegisterService 注册服务对象，其导出方法以 <name>.<首字母小写的方法名> 注册为方法处理器
+//
+// 方法签名须为 func(ctx context.Context[, req T]) ([resp R, ]error)，其他导出方法被忽略；
+// 请求参数按 T 的类型解码，具名参数和位置参数的绑定规则与 framework.Server.Register 相同
+func (h *InternalJsonRpcHandler) RegisterService(name string, service interface{}) error {
+	handlers, err := rpcbind.Methods(name, service)
+	if err != nil {
+		return err
+	}
+	for method, handler := range handlers {
+		h.RegisterMethod(method, MethodHandler(handler))
+	}
+	return nil
+}
+
 // acceptConnections 接受连接
 func (h *InternalJsonRpcHandler) acceptConnections() {
 	for {
@@ -96,81 +182,252 @@ func (h *InternalJsonRpcHandler) acceptConnections() {
 			}
 			
 			// 处理连接
+			if !h.trackConn(conn) {
+				conn.Close()
+				return
+			}
 			go h.handleConnection(conn)
 		}
 	}
 }
 
+// trackConn 记录连接，服务器已停止时返回 false
+func (h *InternalJsonRpcHandler) trackConn(conn net.Conn) bool {
+	h.connsMu.Lock()
+	defer h.connsMu.Unlock()
+	
+	select {
+	case <-h.stopChan:
+		return false
+	default:
+	}
+	h.conns[conn] = struct{}{}
+	h.connWg.Add(1)
+	return true
+}
+
+// untrackConn 移除连接记录
+func (h *InternalJsonRpcHandler) untrackConn(conn net.Conn) {
+	h.connsMu.Lock()
+	delete(h.conns, conn)
+	h.connsMu.Unlock()
+	h.connWg.Done()
+}
+
 // handleConnection 处理连接
+//
+// 连接上的每个 JSON 值为一个请求（单个请求对象或批量请求数组），各请求在独立的协程中处理，
+// 响应经 responseWriter 逐个写回，之间以换行分隔；响应顺序与请求顺序无关，客户端按 id 匹配。
+// 同时处理的请求达到 MaxConcurrentRequests 时暂停读取，连接关闭、出现无法解析的数据或请求超过 MaxMessageSize 时
+// 等待处理中的请求完成后关闭连接
 func (h *InternalJsonRpcHandler) handleConnection(conn net.Conn) {
+	defer h.untrackConn(conn)
 	defer conn.Close()
 	
 	ctx := context.Background()
+	writer := &responseWriter{conn: conn}
+	calls := newCallTracker()
+	maxSize := maxMessageSize(h.config)
+	reader := newFrameReader(conn, maxSize)
+	decoder := json.NewDecoder(reader)
 	
-	// 读取请求
-	buffer := make([]byte, 4096)
-	n, err := conn.Read(buffer)
-	if err != nil {
-		if err != io.EOF {
-			glog.Errorf(ctx, "Failed to read request: %v", err)
+	limit := h.config.MaxConcurrentRequests
+	if limit <= 0 {
+		limit = DefaultMaxConcurrentRequests
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	
+	for {
+		var data json.RawMessage
+		if err := decoder.Decode(&data); err != nil {
+			var syntaxErr *json.SyntaxError
+			switch {
+			case errors.Is(err, errMessageTooLarge):
+				// 剩余数据无法与下一个请求区分，返回错误后关闭连接
+				writer.sendError(nil, -32600, "Invalid Request", fmt.Sprintf("request exceeds maximum message size of %d bytes", maxSize))
+				// 处理中的请求写回响应后再关闭写入端，未读的数据被丢弃，避免连接被重置时客户端丢失已发送的响应
+				wg.Wait()
+				lingerClose(conn, maxSize)
+			case errors.As(err, &syntaxErr):
+				// 无法确定下一个请求的起点，返回解析错误后关闭连接
+				writer.sendError(nil, -32700, "Parse error", err.Error())
+				wg.Wait()
+				lingerClose(conn, maxSize)
+			case err != io.EOF && !isClosedError(err):
+				select {
+				case <-h.stopChan:
+				default:
+					glog.Errorf(ctx, "Failed to read request: %v", err)
+				}
+			}
+			return
 		}
+		reader.next(decoder.InputOffset())
+		
+		// 取消通知不占用并发请求数，达到上限时仍可取消处理中的请求
+		if request, params, ok := parseCancel(data); ok {
+			cancelled := calls.cancel(params.Id)
+			if request.Id != nil {
+				writer.writeResponse(&JsonRpcResponse{Jsonrpc: "2.0", Id: request.Id, Result: cancelled})
+			}
+			continue
+		}
+		
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(data json.RawMessage) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			h.handleMessage(ctx, writer, calls, slots, data)
+		}(data)
+	}
+}
+
+// handleMessage 处理连接上读取的一个请求或批量请求，调用方已占用 slots 中的一个名额
+func (h *InternalJsonRpcHandler) handleMessage(ctx context.Context, writer *responseWriter, calls *callTracker, slots chan struct{}, data json.RawMessage) {
+	// 批量请求为请求数组
+	if len(data) > 0 && data[0] == '[' {
+		h.handleBatch(ctx, writer, calls, slots, data)
 		return
 	}
 	
 	// 解析 JSON-RPC 请求
 	var request JsonRpcRequest
-	if err := json.Unmarshal(buffer[:n], &request); err != nil {
-		h.sendError(conn, nil, -32700, "Parse error", err.Error())
+	if err := json.Unmarshal(data, &request); err != nil {
+		writer.sendError(nil, -32600, "Invalid Request", err.Error())
 		return
 	}
 	
+	writer.writeResponse(h.processRequest(ctx, calls, &request))
+}
+
+// handleBatch 处理批量请求，响应按请求顺序返回
+//
+// 各请求占用连接的并发名额（slots）并发执行；名额用尽时在批量请求自身占用的名额内依次执行，
+// 批量请求中的请求数不增加连接上同时处理的请求数，也不会因等待名额而互相阻塞
+func (h *InternalJsonRpcHandler) handleBatch(ctx context.Context, writer *responseWriter, calls *callTracker, slots chan struct{}, data []byte) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		writer.sendError(nil, -32600, "Invalid Request", err.Error())
+		return
+	}
+	if len(items) == 0 {
+		writer.sendError(nil, -32600, "Invalid Request", "batch is empty")
+		return
+	}
+	
+	responses := make([]*JsonRpcResponse, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		var request JsonRpcRequest
+		if err := json.Unmarshal(item, &request); err != nil {
+			responses[i] = newErrorResponse(nil, -32600, "Invalid Request", err.Error())
+			continue
+		}
+		
+		select {
+		case slots <- struct{}{}:
+			wg.Add(1)
+			go func(index int, request JsonRpcRequest) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				responses[index] = h.processRequest(ctx, calls, &request)
+			}(i, request)
+		default:
+			responses[i] = h.processRequest(ctx, calls, &request)
+		}
+	}
+	wg.Wait()
+	
+	writer.writeResponse(responses)
+}
+
+// processRequest 处理单个请求并构造响应
+//
+// 处理器的 ctx 在 meta 中的剩余超时（adapter.HeaderTimeout）到期或收到该请求的取消通知（CancelMethod）时结束；
+// 开始处理前 ctx 已结束时不调用处理器，直接返回 Timeout 或 ClientClosedRequest 错误
+func (h *InternalJsonRpcHandler) processRequest(ctx context.Context, calls *callTracker, request *JsonRpcRequest) *JsonRpcResponse {
 	// 验证请求
 	if request.Jsonrpc != "2.0" {
-		h.sendError(conn, request.Id, -32600, "Invalid Request", "jsonrpc must be 2.0")
-		return
+		return newErrorResponse(request.Id, -32600, "Invalid Request", "jsonrpc must be 2.0")
 	}
 	
 	if request.Method == "" {
-		h.sendError(conn, request.Id, -32600, "Invalid Request", "method is required")
-		return
+		return newErrorResponse(request.Id, -32600, "Invalid Request", "method is required")
 	}
 	
+	// 恢复调用方的安全上下文和追踪上下文
+	if sc := adapter.SecurityContextFromHeaders(request.Meta); sc != nil {
+		ctx = adapter.WithSecurityContext(ctx, sc)
+	}
+	ctx = adapter.ExtractTraceContext(ctx, request.Meta)
+	ctx, cancel := adapter.ExtractDeadline(ctx, request.Meta)
+	defer cancel()
+	ctx, done := calls.start(ctx, request.Id)
+	defer done()
+	
 	// 查找处理器
 	h.mu.RLock()
 	handler, exists := h.handlers[request.Method]
 	h.mu.RUnlock()
 	
 	if !exists {
-		h.sendError(conn, request.Id, -32601, "Method not found", fmt.Sprintf("method %s not found", request.Method))
-		return
+		return newErrorResponse(request.Id, -32601, "Method not found", fmt.Sprintf("method %s not found", request.Method))
+	}
+	
+	if ctx.Err() != nil {
+		return contextErrorResponse(ctx, request)
 	}
 	
 	// 调用处理器
+	ctx, span := adapter.StartServerSpan(ctx, adapter.ProtocolInternalRPC, "", request.Method)
 	result, err := handler(ctx, request.Params)
+	adapter.EndSpan(span, err)
 	if err != nil {
-		h.sendError(conn, request.Id, -32603, "Internal error", err.Error())
-		return
+		// data 为跨语言传输格式的结构化错误
+		payload := adapter.NewErrorPayload(ctx, err)
+		return newErrorResponse(request.Id, frameworkerrors.ErrorCode(payload.Code).ToJSONRPCCode(), payload.Message, payload)
 	}
 	
-	// 发送响应
-	h.sendResponse(conn, request.Id, result)
-}
-
-// sendResponse 发送响应
-func (h *InternalJsonRpcHandler) sendResponse(conn net.Conn, id interface{}, result interface{}) {
-	response := JsonRpcResponse{
+	return &JsonRpcResponse{
 		Jsonrpc: "2.0",
-		Id:      id,
+		Id:      request.Id,
 		Result:  result,
 	}
-	
+}
+
+// responseWriter 串行化同一连接上的响应写入，每个响应编码后一次写入并以换行结尾
+type responseWriter struct {
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+// writeResponse 发送响应或批量响应
+func (w *responseWriter) writeResponse(response interface{}) {
 	data, _ := json.Marshal(response)
-	conn.Write(data)
+	data = append(data, '\n')
+	
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.conn.Write(data)
 }
 
 // sendError 发送错误响应
-func (h *InternalJsonRpcHandler) sendError(conn net.Conn, id interface{}, code int, message string, data interface{}) {
-	response := JsonRpcResponse{
+func (w *responseWriter) sendError(id interface{}, code int, message string, data interface{}) {
+	w.writeResponse(newErrorResponse(id, code, message, data))
+}
+
+// isClosedError 判断是否为连接已关闭或读取超时（Stop 时设置）导致的错误
+func isClosedError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, net.ErrClosed) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// newErrorResponse 构造错误响应
+func newErrorResponse(id interface{}, code int, message string, data interface{}) *JsonRpcResponse {
+	return &JsonRpcResponse{
 		Jsonrpc: "2.0",
 		Id:      id,
 		Error: &JsonRpcError{
@@ -179,9 +436,6 @@ func (h *InternalJsonRpcHandler) sendError(conn net.Conn, id interface{}, code i
 			Data:    data,
 		},
 	}
-	
-	responseData, _ := json.Marshal(response)
-	conn.Write(responseData)
 }
 
 // JsonRpcRequest JSON-RPC 请求
@@ -190,6 +444,8 @@ type JsonRpcRequest struct {
 	Method  string      `json:"method"`
 	Params  interface{} `json:"params,omitempty"`
 	Id      interface{} `json:"id"`
+	// Meta 框架扩展字段，携带安全上下文、追踪上下文等跨语言元数据
+	Meta map[string]string `json:"meta,omitempty"`
 }
 
 // JsonRpcResponse JSON-RPC 响应
@@ -208,9 +464,19 @@ type JsonRpcError struct {
 }
 
 // InternalJsonRpcClient 内部 JSON-RPC 客户端
+//
+// 可在多个协程中并发调用：TCP 传输在同一连接上流水线发送请求，不等待前一个响应，响应按请求 ID 分发给各调用
 type InternalJsonRpcClient struct {
 	conn   net.Conn
+	http   *http.Client
+	url    string
 	config *InternalJsonRpcConfig
+	nextID atomic.Uint64
+	
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]*pendingCall
+	readErr error
 }
 
 // NewInternalJsonRpcClient 创建内部 JSON-RPC 客户端
@@ -220,21 +486,48 @@ func NewInternalJsonRpcClient(config *InternalJsonRpcConfig) *InternalJsonRpcCli
 	}
 }
 
-// Connect 连接到服务器
+// Connect 连接到服务器，HTTP 传输只准备客户端，连接在调用时建立并复用
 func (c *InternalJsonRpcClient) Connect() error {
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
+	address := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	
+	switch c.config.Transport {
+	case "", TransportTCP:
+	case TransportHTTP:
+		c.connectHTTP(address)
+		return nil
+	default:
+		return fmt.Errorf("unsupported transport %q", c.config.Transport)
+	}
 	
-	conn, err := net.Dial("tcp", address)
+	var conn net.Conn
+	var err error
+	switch {
+	case c.config.Dialer != nil:
+		conn, err = c.config.Dialer(context.Background(), "tcp", address)
+		if err == nil && c.config.TLSConfig != nil {
+			conn = tls.Client(conn, clientTLSConfig(c.config.TLSConfig, c.config.Host))
+		}
+	case c.config.TLSConfig != nil:
+		conn, err = tls.Dial("tcp", address, c.config.TLSConfig)
+	default:
+		conn, err = net.Dial("tcp", address)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to %s: %v", address, err)
 	}
 	
 	c.conn = conn
+	c.pending = make(map[string]*pendingCall)
+	go c.readResponses(json.NewDecoder(conn))
 	return nil
 }
 
 // Close 关闭连接
 func (c *InternalJsonRpcClient) Close() error {
+	if c.http != nil {
+		c.http.CloseIdleConnections()
+		return nil
+	}
 	if c.conn != nil {
 		return c.conn.Close()
 	}
@@ -242,47 +535,230 @@ func (c *InternalJsonRpcClient) Close() error {
 }
 
 // Call 调用远程方法
+//
+// 请求 ID 由客户端分配以保证同一连接上未完成的调用 ID 唯一，参数 id 仅为兼容保留；
+// 等待响应的时间受 ctx 和 CallTimeout 限制，超时返回 Timeout 错误。两者中较早的截止时间以剩余超时
+// （adapter.HeaderTimeout）写入请求的 meta，TCP 传输在放弃等待时向服务端发送取消通知（CancelMethod），
+// 服务端据此停止处理；HTTP 传输放弃等待时中断请求
 func (c *InternalJsonRpcClient) Call(ctx context.Context, method string, params interface{}, id interface{}) (interface{}, error) {
-	if c.conn == nil {
+	if !c.connected() {
 		return nil, fmt.Errorf("client not connected")
 	}
 	
+	ctx, span := adapter.StartClientSpan(ctx, adapter.ProtocolInternalRPC, "", method, c.endpoint())
+	result, err := c.call(ctx, method, params)
+	adapter.EndSpan(span, err)
+	return result, err
+}
+
+// call 发送请求并等待响应
+func (c *InternalJsonRpcClient) call(ctx context.Context, method string, params interface{}) (interface{}, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	
 	// 构造请求
+	wireID := c.nextID.Add(1)
 	request := JsonRpcRequest{
 		Jsonrpc: "2.0",
 		Method:  method,
 		Params:  params,
-		Id:      id,
-	}
-	
-	// 序列化请求
-	requestData, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+		Id:      wireID,
 	}
 	
-	// 发送请求
-	if _, err := c.conn.Write(requestData); err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
-	}
+	request.Meta = requestMeta(ctx)
 	
-	// 读取响应
-	buffer := make([]byte, 4096)
-	n, err := c.conn.Read(buffer)
+	// 发送请求并读取响应
+	data, err := c.roundTrip(ctx, request, []uint64{wireID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return nil, err
 	}
 	
 	// 解析响应
 	var response JsonRpcResponse
-	if err := json.NewDecoder(bytes.NewReader(buffer[:n])).Decode(&response); err != nil {
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
 	
 	// 检查错误
 	if response.Error != nil {
-		return nil, fmt.Errorf("JSON-RPC error %d: %s", response.Error.Code, response.Error.Message)
+		return nil, response.Error.toError()
 	}
 	
 	return response.Result, nil
 }
+
+// BatchCall 批量请求中的单次调用
+type BatchCall struct {
+	Method string
+	Params interface{}
+}
+
+// CallBatch 在一次往返中发送批量请求，结果按 calls 的顺序返回
+//
+// 部分调用失败时对应结果为 nil，并返回 *errors.MultiError，按调用在 calls 中的序号记录方法、端点和错误
+func (c *InternalJsonRpcClient) CallBatch(ctx context.Context, calls []BatchCall) ([]interface{}, error) {
+	if !c.connected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("batch is empty")
+	}
+	
+	ctx, span := adapter.StartClientSpan(ctx, adapter.ProtocolInternalRPC, "", "batch", c.endpoint())
+	results, err := c.callBatch(ctx, calls)
+	adapter.EndSpan(span, err)
+	return results, err
+}
+
+// callBatch 发送批量请求并等待响应，各调用的请求 ID 连续分配，减去首个 ID 即为调用的序号
+func (c *InternalJsonRpcClient) callBatch(ctx context.Context, calls []BatchCall) ([]interface{}, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	
+	meta := requestMeta(ctx)
+	base := c.nextID.Add(uint64(len(calls))) - uint64(len(calls)) + 1
+	requests := make([]JsonRpcRequest, len(calls))
+	ids := make([]uint64, len(calls))
+	for i, call := range calls {
+		ids[i] = base + uint64(i)
+		requests[i] = JsonRpcRequest{
+			Jsonrpc: "2.0",
+			Method:  call.Method,
+			Params:  call.Params,
+			Id:      ids[i],
+			Meta:    meta,
+		}
+	}
+	
+	data, err := c.roundTrip(ctx, requests, ids)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 整个批量请求无效时服务端返回单个错误响应
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var response JsonRpcResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+		if response.Error != nil {
+			return nil, response.Error.toError()
+		}
+		return nil, fmt.Errorf("unexpected response to batch request")
+	}
+	
+	var responses []JsonRpcResponse
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	
+	endpoint := c.endpoint()
+	results := make([]interface{}, len(calls))
+	received := make([]bool, len(calls))
+	multi := frameworkerrors.NewMultiError(len(calls))
+	for _, response := range responses {
+		id, ok := response.Id.(float64)
+		if !ok || id < float64(base) || id >= float64(base)+float64(len(calls)) {
+			continue
+		}
+		index := int(id - float64(base))
+		received[index] = true
+		if response.Error != nil {
+			multi.Add(frameworkerrors.TargetError{Index: index, Method: calls[index].Method, Endpoint: endpoint, Attempt: 1, Err: response.Error.toError()})
+			continue
+		}
+		results[index] = response.Result
+	}
+	for index, ok := range received {
+		if !ok {
+			err := frameworkerrors.NewFrameworkError(frameworkerrors.ProtocolError, "missing response in batch")
+			multi.Add(frameworkerrors.TargetError{Index: index, Method: calls[index].Method, Endpoint: endpoint, Attempt: 1, Err: err})
+		}
+	}
+	
+	return results, multi.ErrorOrNil()
+}
+
+// connected 判断是否已调用 Connect
+func (c *InternalJsonRpcClient) connected() bool {
+	return c.conn != nil || c.http != nil
+}
+
+// endpoint 返回服务端地址，HTTP 传输时为请求 URL
+func (c *InternalJsonRpcClient) endpoint() string {
+	if c.http != nil {
+		return c.url
+	}
+	return c.conn.RemoteAddr().String()
+}
+
+// roundTrip 发送请求并等待响应，ids 为请求（或批量请求中各请求）的 ID
+func (c *InternalJsonRpcClient) roundTrip(ctx context.Context, request interface{}, ids []uint64) ([]byte, error) {
+	// 序列化请求
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+	
+	if c.http == nil && len(requestData) > maxMessageSize(c.config) {
+		return nil, frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+			fmt.Sprintf("request of %d bytes exceeds maximum message size of %d bytes", len(requestData), maxMessageSize(c.config)))
+	}
+	
+	if c.http != nil {
+		data, err := c.postHTTP(ctx, requestData)
+		if err != nil && ctx.Err() != nil {
+			return nil, callContextError(ctx, c.url)
+		}
+		return data, err
+	}
+	return c.pipeline(ctx, append(requestData, '\n'), ids)
+}
+
+// callContext 返回受 CallTimeout 限制的调用 ctx
+func (c *InternalJsonRpcClient) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.CallTimeout > 0 {
+		return context.WithTimeout(ctx, c.config.CallTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// requestMeta 返回需要转发的安全上下文、追踪上下文和剩余超时，没有时返回 nil
+func requestMeta(ctx context.Context) map[string]string {
+	meta := make(map[string]string)
+	if sc := adapter.SecurityContextFromContext(ctx); sc != nil {
+		meta = sc.ToHeaders()
+	}
+	adapter.InjectTraceContext(ctx, meta)
+	adapter.InjectDeadline(ctx, meta)
+	if len(meta) == 0 {
+		return nil
+	}
+	return meta
+}
+
+// toError 将 JSON-RPC 错误转换为 Go 错误，data 为结构化错误时还原为框架错误
+func (e *JsonRpcError) toError() error {
+	if e.Data != nil {
+		if data, err := json.Marshal(e.Data); err == nil {
+			if fe, err := frameworkerrors.UnmarshalError(data); err == nil {
+				return fe
+			}
+		}
+	}
+	if detail, ok := e.Data.(string); ok && detail != "" {
+		return fmt.Errorf("JSON-RPC error %d: %s: %s", e.Code, e.Message, detail)
+	}
+	return fmt.Errorf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// clientTLSConfig 返回在 Dialer 建立的连接上握手使用的 TLS 配置，未设置 ServerName 时以 host 校验证书，与 tls.Dial 相同
+func clientTLSConfig(config *tls.Config, host string) *tls.Config {
+	if config.ServerName != "" {
+		return config
+	}
+	config = config.Clone()
+	config.ServerName = host
+	return config
+}
@@ -2,8 +2,21 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/transport/memory"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TestInternalJsonRpcHandlerCreation 测试内部 JSON-RPC 处理器创建
@@ -143,3 +156,654 @@ func TestInternalJsonRpcClientServerCommunication(t *testing.T) {
 		t.Error("Expected non-nil result")
 	}
 }
+
+// TestInternalJsonRpcMemoryTransport 测试客户端通过进程内监听器调用服务端，不绑定真实端口
+func TestInternalJsonRpcMemoryTransport(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewInternalJsonRpcHandler(&InternalJsonRpcConfig{Listener: listener})
+	handler.RegisterMethod("echo", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return params, nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	client := NewInternalJsonRpcClient(&InternalJsonRpcConfig{
+		Host:   "127.0.0.1",
+		Port:   listener.Port(),
+		Dialer: memory.DialContext,
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	result, err := client.Call(context.Background(), "echo", "hello", 1)
+	if err != nil {
+		t.Fatalf("Failed to call method: %v", err)
+	}
+	if result != "hello" {
+		t.Errorf("Expected echo result, got %v", result)
+	}
+}
+
+// TestInternalJsonRpcSecurityContextPropagation 测试安全上下文在调用中传递
+func TestInternalJsonRpcSecurityContextPropagation(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10006,
+	}
+	
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("whoami", func(ctx context.Context, params interface{}) (interface{}, error) {
+		sc := adapter.SecurityContextFromContext(ctx)
+		if sc == nil {
+			return "anonymous", nil
+		}
+		return sc.UserID + "@" + sc.TenantID, nil
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	ctx := adapter.WithSecurityContext(context.Background(), &adapter.SecurityContext{
+		UserID:   "user-1",
+		TenantID: "tenant-a",
+	})
+	result, err := client.Call(ctx, "whoami", nil, 1)
+	if err != nil {
+		t.Fatalf("Failed to call method: %v", err)
+	}
+	
+	if result != "user-1@tenant-a" {
+		t.Errorf("Expected 'user-1@tenant-a', got %v", result)
+	}
+}
+
+// TestInternalJsonRpcTraceContextPropagation 测试追踪上下文在调用中传递
+func TestInternalJsonRpcTraceContextPropagation(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10007,
+	}
+	
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("traceid", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return trace.SpanContextFromContext(ctx).TraceID().String(), nil
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	
+	result, err := client.Call(ctx, "traceid", nil, 1)
+	if err != nil {
+		t.Fatalf("Failed to call method: %v", err)
+	}
+	
+	if result != traceID.String() {
+		t.Errorf("Expected '%s', got %v", traceID, result)
+	}
+}
+
+// TestStructuredError 测试处理器错误以结构化格式返回给客户端
+func TestStructuredError(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10008,
+	}
+	
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("fail", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return nil, &adapter.FrameworkError{Code: adapter.ErrorServiceUnavailable, Message: "inventory unavailable", ServiceId: "inventory"}
+	})
+	handler.RegisterMethod("boom", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	tests := []struct {
+		method    string
+		code      frameworkerrors.ErrorCode
+		message   string
+		retryable bool
+	}{
+		{"fail", frameworkerrors.ServiceUnavailable, "inventory unavailable", true},
+		{"boom", frameworkerrors.InternalError, "boom", false},
+	}
+	
+	for _, tt := range tests {
+		client := NewInternalJsonRpcClient(config)
+		if err := client.Connect(); err != nil {
+			t.Fatalf("Failed to connect client: %v", err)
+		}
+		
+		_, err := client.Call(context.Background(), tt.method, nil, 1)
+		client.Close()
+		
+		fe, ok := frameworkerrors.FromError(err)
+		if !ok {
+			t.Fatalf("%s: expected framework error, got %v", tt.method, err)
+		}
+		if fe.Code != tt.code || fe.Message != tt.message || fe.Code.IsRetryable() != tt.retryable {
+			t.Errorf("%s: unexpected error %+v", tt.method, fe)
+		}
+	}
+}
+
+func TestBatchCall(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10009,
+	}
+	
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("echo", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return params, nil
+	})
+	handler.RegisterMethod("fail", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return nil, &adapter.FrameworkError{Code: adapter.ErrorServiceUnavailable, Message: "inventory unavailable"}
+	})
+	
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	time.Sleep(300 * time.Millisecond)
+	
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	results, err := client.CallBatch(context.Background(), []BatchCall{
+		{Method: "echo", Params: "a"},
+		{Method: "fail"},
+		{Method: "missing"},
+		{Method: "echo", Params: "d"},
+	})
+	
+	// 成功的调用按顺序返回结果
+	if len(results) != 4 || results[0] != "a" || results[1] != nil || results[3] != "d" {
+		t.Errorf("Unexpected results: %v", results)
+	}
+	
+	var multi *frameworkerrors.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("Expected *MultiError, got %v", err)
+	}
+	errs := multi.Errors()
+	if multi.Total != 4 || len(errs) != 2 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if errs[0].Index != 1 || errs[0].Method != "fail" || errs[0].Endpoint != "127.0.0.1:10009" || errs[0].Code() != frameworkerrors.ServiceUnavailable {
+		t.Errorf("Unexpected error for fail: %+v", errs[0])
+	}
+	if errs[1].Index != 2 || errs[1].Method != "missing" {
+		t.Errorf("Unexpected error for missing: %+v", errs[1])
+	}
+}
+
+type greetRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Times int    `json:"times" validate:"omitempty,max=3"`
+}
+
+type greetService struct{}
+
+func (s *greetService) SayHello(ctx context.Context, req *greetRequest) (map[string]interface{}, error) {
+	return map[string]interface{}{"message": "Hello, " + req.Name, "times": req.Times}, nil
+}
+
+func (s *greetService) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// TestRegisterService 测试从服务对象注册方法
+func TestRegisterService(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10010,
+	}
+
+	handler := NewInternalJsonRpcHandler(config)
+	if err := handler.RegisterService("greet", &greetService{}); err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+	if err := handler.RegisterService("empty", struct{}{}); err == nil {
+		t.Error("Expected error for service without methods")
+	}
+
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	time.Sleep(300 * time.Millisecond)
+
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	call := func(method string, params interface{}) (interface{}, error) {
+		return client.Call(context.Background(), method, params, 1)
+	}
+
+	// 具名参数和位置参数都按请求结构体解码
+	for _, params := range []interface{}{
+		map[string]interface{}{"name": "Go", "times": 2},
+		[]interface{}{"Go", 2},
+	} {
+		result, err := call("greet.sayHello", params)
+		if err != nil {
+			t.Fatalf("Call greet.sayHello failed: %v", err)
+		}
+		reply, _ := result.(map[string]interface{})
+		if reply["message"] != "Hello, Go" || reply["times"] != float64(2) {
+			t.Errorf("Unexpected result for %v: %v", params, result)
+		}
+	}
+
+	if result, err := call("greet.ping", nil); err != nil || result != "pong" {
+		t.Errorf("Call greet.ping = %v, %v", result, err)
+	}
+
+	// 参数类型错误返回 BadRequest
+	_, err := call("greet.sayHello", map[string]interface{}{"name": 1})
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Errorf("Expected BadRequest, got %v", err)
+	}
+
+	// 违反 validate 标签的参数返回 BadRequest，违规字段随错误详情传回
+	_, err = call("greet.sayHello", map[string]interface{}{"times": 5})
+	fe, ok := frameworkerrors.FromError(err)
+	if !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Fatalf("Expected BadRequest, got %v", err)
+	}
+	violations, _ := fe.Fields["violations"].([]interface{})
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", fe.Fields)
+	}
+	if first, _ := violations[0].(map[string]interface{}); first["field"] != "name" || first["rule"] != "required" {
+		t.Errorf("Unexpected violation: %v", violations[0])
+	}
+}
+
+// dialRaw 建立原始 TCP 连接，返回按行读取响应的解码器
+func dialRaw(t *testing.T, config *InternalJsonRpcConfig) (net.Conn, *json.Decoder) {
+	t.Helper()
+	conn, err := net.Dial("tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.Port)))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return conn, json.NewDecoder(conn)
+}
+
+// readResponse 读取一个响应
+func readResponse(t *testing.T, decoder *json.Decoder) *JsonRpcResponse {
+	t.Helper()
+	var response JsonRpcResponse
+	if err := decoder.Decode(&response); err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return &response
+}
+
+// TestConcurrentRequestsPerConnection 测试同一连接上的请求并发处理，响应按完成顺序返回
+func TestConcurrentRequestsPerConnection(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host: "127.0.0.1",
+		Port: 10011,
+	}
+
+	release := make(chan struct{})
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("slow", func(ctx context.Context, params interface{}) (interface{}, error) {
+		<-release
+		return "slow", nil
+	})
+	handler.RegisterMethod("fast", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return "fast", nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	time.Sleep(300 * time.Millisecond)
+
+	conn, decoder := dialRaw(t, config)
+	defer conn.Close()
+
+	// 两个请求在同一次写入中发送，慢请求不阻塞后续请求
+	conn.Write([]byte(`{"jsonrpc":"2.0","method":"slow","id":1}` + "\n" + `{"jsonrpc":"2.0","method":"fast","id":2}` + "\n"))
+
+	if response := readResponse(t, decoder); response.Id != float64(2) || response.Result != "fast" {
+		t.Fatalf("Expected fast response first, got %+v", response)
+	}
+	close(release)
+	if response := readResponse(t, decoder); response.Id != float64(1) || response.Result != "slow" {
+		t.Errorf("Expected slow response, got %+v", response)
+	}
+
+	// 无法解析的数据返回解析错误后关闭连接
+	conn.Write([]byte(`{"jsonrpc":}` + "\n"))
+	if response := readResponse(t, decoder); response.Error == nil || response.Error.Code != -32700 {
+		t.Errorf("Expected parse error, got %+v", response)
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected connection closed, got %v", err)
+	}
+}
+
+// TestMaxConcurrentRequests 测试每个连接的并发请求数上限
+func TestMaxConcurrentRequests(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host:                  "127.0.0.1",
+		Port:                  10012,
+		MaxConcurrentRequests: 2,
+	}
+
+	var running, peak atomic.Int32
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("work", func(ctx context.Context, params interface{}) (interface{}, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		return params, nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	conn, decoder := dialRaw(t, config)
+	defer conn.Close()
+
+	const requests = 6
+	for i := 0; i < requests; i++ {
+		fmt.Fprintf(conn, `{"jsonrpc":"2.0","method":"work","params":%d,"id":%d}`+"\n", i, i)
+	}
+
+	// Stop 等待处理中的请求写回响应
+	stopped := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		handler.Stop(context.Background())
+		close(stopped)
+	}()
+
+	seen := make(map[float64]bool)
+	for {
+		var response JsonRpcResponse
+		if err := decoder.Decode(&response); err != nil {
+			break
+		}
+		if response.Error != nil || response.Result != response.Id {
+			t.Errorf("Unexpected response: %+v", response)
+		}
+		seen[response.Id.(float64)] = true
+	}
+	<-stopped
+
+	if len(seen) == 0 {
+		t.Error("Expected in-flight requests to complete before stop")
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Peak concurrency = %d, want at most 2", got)
+	}
+}
+
+// TestMaxConcurrentRequestsBatch 测试批量请求中的请求不超过连接的并发请求数上限
+func TestMaxConcurrentRequestsBatch(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host:                  "127.0.0.1",
+		Port:                  10015,
+		MaxConcurrentRequests: 2,
+	}
+
+	var running, peak atomic.Int32
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("work", func(ctx context.Context, params interface{}) (interface{}, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return params, nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	time.Sleep(300 * time.Millisecond)
+
+	conn, decoder := dialRaw(t, config)
+	defer conn.Close()
+
+	const items = 10
+	batch := make([]string, items)
+	for i := range batch {
+		batch[i] = fmt.Sprintf(`{"jsonrpc":"2.0","method":"work","params":%d,"id":%d}`, i, i)
+	}
+	fmt.Fprintf(conn, "[%s]\n", strings.Join(batch, ","))
+	fmt.Fprintf(conn, "[%s]\n", strings.Join(batch, ","))
+
+	for n := 0; n < 2; n++ {
+		var responses []JsonRpcResponse
+		if err := decoder.Decode(&responses); err != nil {
+			t.Fatalf("Failed to read batch response: %v", err)
+		}
+		if len(responses) != items {
+			t.Fatalf("Expected %d responses, got %d", items, len(responses))
+		}
+		for i, response := range responses {
+			if response.Error != nil || response.Id != float64(i) || response.Result != float64(i) {
+				t.Errorf("Unexpected response %d: %+v", i, response)
+			}
+		}
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Peak concurrency = %d, want at most 2", got)
+	}
+}
+
+// TestMaxMessageSize 测试超过字节数上限的请求返回 -32600 错误，而不是解析错误
+func TestMaxMessageSize(t *testing.T) {
+	config := &InternalJsonRpcConfig{
+		Host:           "127.0.0.1",
+		Port:           10013,
+		MaxMessageSize: 1024,
+	}
+
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("echo", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return params, nil
+	})
+	release := make(chan struct{})
+	handler.RegisterMethod("slow", func(ctx context.Context, params interface{}) (interface{}, error) {
+		<-release
+		return "slow", nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+
+	time.Sleep(300 * time.Millisecond)
+
+	// 同一次写入中的多个小请求合计超过上限时照常处理
+	conn, decoder := dialRaw(t, config)
+	defer conn.Close()
+	small := fmt.Sprintf(`{"jsonrpc":"2.0","method":"echo","params":"%s","id":%%d}`+"\n", strings.Repeat("a", 600))
+	conn.Write([]byte(fmt.Sprintf(small, 1) + fmt.Sprintf(small, 2)))
+	for i := 0; i < 2; i++ {
+		if response := readResponse(t, decoder); response.Error != nil {
+			t.Fatalf("Expected small request to succeed, got %+v", response.Error)
+		}
+	}
+
+	large := fmt.Sprintf(`{"jsonrpc":"2.0","method":"echo","params":"%s","id":3}`+"\n", strings.Repeat("a", 2048))
+	conn.Write([]byte(large))
+	response := readResponse(t, decoder)
+	if response.Error == nil || response.Error.Code != -32600 || response.Id != nil {
+		t.Errorf("Expected invalid request error, got %+v", response)
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected connection closed, got %v", err)
+	}
+
+	// 处理中的请求写回响应后才关闭连接
+	pending, pendingDecoder := dialRaw(t, config)
+	defer pending.Close()
+	pending.Write([]byte(`{"jsonrpc":"2.0","method":"slow","id":4}` + "\n" + large))
+	if response := readResponse(t, pendingDecoder); response.Error == nil || response.Error.Code != -32600 {
+		t.Errorf("Expected invalid request error, got %+v", response)
+	}
+	close(release)
+	if response := readResponse(t, pendingDecoder); response.Id != float64(4) || response.Result != "slow" {
+		t.Errorf("Expected slow response before close, got %+v", response)
+	}
+	if _, err := pending.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected connection closed, got %v", err)
+	}
+
+	// 客户端不发送超过上限的请求，连接继续可用
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	_, err := client.Call(context.Background(), "echo", strings.Repeat("a", 2048), nil)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.BadRequest {
+		t.Errorf("Expected BadRequest, got %v", err)
+	}
+	if result, err := client.Call(context.Background(), "echo", "ok", nil); err != nil || result != "ok" {
+		t.Errorf("Expected connection to remain usable, got %v, %v", result, err)
+	}
+
+	// 客户端的上限大于服务端时，调用返回服务端的错误
+	unlimited := NewInternalJsonRpcClient(&InternalJsonRpcConfig{Host: config.Host, Port: config.Port})
+	if err := unlimited.Connect(); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer unlimited.Close()
+	_, err = unlimited.Call(context.Background(), "echo", strings.Repeat("a", 2048), nil)
+	if err == nil || !strings.Contains(err.Error(), "maximum message size") {
+		t.Errorf("Expected message size error, got %v", err)
+	}
+}
+
+// TestDeadlinePropagationAndCancel 测试调用方的截止时间随请求传递，调用方放弃等待后服务端的 ctx 被取消
+func TestDeadlinePropagationAndCancel(t *testing.T) {
+	listener := memory.Listen()
+	handler := NewInternalJsonRpcHandler(&InternalJsonRpcConfig{Listener: listener})
+	started := make(chan bool, 1)
+	stopped := make(chan error, 1)
+	handler.RegisterMethod("wait", func(ctx context.Context, params interface{}) (interface{}, error) {
+		_, hasDeadline := ctx.Deadline()
+		started <- hasDeadline
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	defer handler.Stop(context.Background())
+	
+	client := NewInternalJsonRpcClient(&InternalJsonRpcConfig{
+		Host:   "127.0.0.1",
+		Port:   listener.Port(),
+		Dialer: memory.DialContext,
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+	
+	// 服务端的 ctx 带调用方的截止时间
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := client.Call(ctx, "wait", nil, 1)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.Timeout {
+		t.Errorf("Expected Timeout error, got %v", err)
+	}
+	if hasDeadline := <-started; !hasDeadline {
+		t.Error("Expected handler ctx to have a deadline")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler ctx was not done after the deadline")
+	}
+	
+	// 没有截止时间的调用被取消时，服务端经取消通知结束处理
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = client.Call(ctx, "wait", nil, 2)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ClientClosedRequest {
+		t.Errorf("Expected ClientClosedRequest error, got %v", err)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Handler ctx error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler ctx was not cancelled by the cancel notification")
+	}
+}
@@ -0,0 +1,157 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// pendingCall 等待响应的调用，批量请求中的各请求 ID 指向同一个 pendingCall
+type pendingCall struct {
+	ids  []string
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// pipeline 在 TCP 连接上发送请求并等待响应，不等待同一连接上其他调用的响应
+func (c *InternalJsonRpcClient) pipeline(ctx context.Context, data []byte, ids []uint64) ([]byte, error) {
+	call := &pendingCall{ids: make([]string, len(ids)), done: make(chan struct{})}
+	for i, id := range ids {
+		call.ids[i] = strconv.FormatUint(id, 10)
+	}
+
+	c.mu.Lock()
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return nil, err
+	}
+	for _, id := range call.ids {
+		c.pending[id] = call
+	}
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	_, err := c.conn.Write(data)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.removePending(call)
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-ctx.Done():
+		// 之后到达的响应被丢弃，服务端收到取消通知后停止处理
+		c.removePending(call)
+		go c.sendCancel(ids)
+		return nil, callContextError(ctx, c.conn.RemoteAddr().String())
+	}
+}
+
+// removePending 移除调用的等待记录
+func (c *InternalJsonRpcClient) removePending(call *pendingCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range call.ids {
+		if c.pending[id] == call {
+			delete(c.pending, id)
+		}
+	}
+}
+
+// readResponses 持续读取连接上的响应并按 ID 分发，连接出错或关闭时以该错误结束所有等待中的调用
+func (c *InternalJsonRpcClient) readResponses(decoder *json.Decoder) {
+	// 服务端关闭连接前返回的 ID 为 null 的错误，如请求超过服务端的 MaxMessageSize
+	var rejected error
+	for {
+		var data json.RawMessage
+		if err := decoder.Decode(&data); err != nil {
+			if rejected != nil {
+				c.failPending(rejected)
+			} else {
+				c.failPending(fmt.Errorf("failed to read response: %v", err))
+			}
+			return
+		}
+
+		id, ok := responseID(data)
+		if !ok {
+			// ID 为 null 的错误响应无法对应到调用，服务端随后关闭连接，等待中的调用以该错误结束
+			var response JsonRpcResponse
+			if json.Unmarshal(data, &response) == nil && response.Error != nil {
+				rejected = response.Error.toError()
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		call := c.pending[id]
+		if call != nil {
+			for _, id := range call.ids {
+				delete(c.pending, id)
+			}
+		}
+		c.mu.Unlock()
+
+		if call != nil {
+			call.data = data
+			close(call.done)
+		}
+	}
+}
+
+// failPending 记录连接错误并结束所有等待中的调用，之后的调用直接返回该错误
+func (c *InternalJsonRpcClient) failPending(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readErr = err
+	for id, call := range c.pending {
+		delete(c.pending, id)
+		select {
+		case <-call.done:
+		default:
+			call.err = err
+			close(call.done)
+		}
+	}
+}
+
+// responseID 返回响应（或批量响应中第一个有 ID 的响应）的 ID
+func responseID(data json.RawMessage) (string, bool) {
+	type idOnly struct {
+		Id json.RawMessage `json:"id"`
+	}
+
+	var responses []idOnly
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return "", false
+		}
+	} else {
+		var response idOnly
+		if err := json.Unmarshal(data, &response); err != nil {
+			return "", false
+		}
+		responses = append(responses, response)
+	}
+
+	for _, response := range responses {
+		if len(response.Id) > 0 && string(response.Id) != "null" {
+			return string(response.Id), true
+		}
+	}
+	return "", false
+}
+
+// callContextError 将调用 ctx 的结束原因转换为框架错误
+func callContextError(ctx context.Context, endpoint string) error {
+	code, _ := frameworkerrors.ContextErrorCode(ctx.Err())
+	return frameworkerrors.NewFrameworkError(code, fmt.Sprintf("call to %s: %v", endpoint, ctx.Err()))
+}
@@ -0,0 +1,127 @@
+package jsonrpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// startPipelineServer 启动注册了 slow、echo 方法的服务器，slow 在 release 关闭前阻塞
+func startPipelineServer(t *testing.T, port int, release chan struct{}) (*InternalJsonRpcHandler, *InternalJsonRpcConfig) {
+	t.Helper()
+	config := &InternalJsonRpcConfig{Host: "127.0.0.1", Port: port}
+	handler := NewInternalJsonRpcHandler(config)
+	handler.RegisterMethod("slow", func(ctx context.Context, params interface{}) (interface{}, error) {
+		<-release
+		return "slow", nil
+	})
+	handler.RegisterMethod("echo", func(ctx context.Context, params interface{}) (interface{}, error) {
+		return params, nil
+	})
+	if err := handler.Start(); err != nil {
+		t.Fatalf("Failed to start handler: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	return handler, config
+}
+
+// TestPipelinedCalls 测试同一连接上的并发调用互不等待
+func TestPipelinedCalls(t *testing.T) {
+	release := make(chan struct{})
+	handler, config := startPipelineServer(t, 10013, release)
+	defer handler.Stop(context.Background())
+
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	defer client.Close()
+
+	slowDone := make(chan error, 1)
+	go func() {
+		result, err := client.Call(context.Background(), "slow", nil, 1)
+		if err == nil && result != "slow" {
+			t.Errorf("Unexpected slow result: %v", result)
+		}
+		slowDone <- err
+	}()
+
+	// slow 未完成时其他调用照常返回
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := client.Call(context.Background(), "echo", float64(i), 1)
+			if err != nil || result != float64(i) {
+				t.Errorf("echo %d = %v, %v", i, result, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	results, err := client.CallBatch(context.Background(), []BatchCall{
+		{Method: "echo", Params: "a"},
+		{Method: "echo", Params: "b"},
+	})
+	if err != nil || len(results) != 2 || results[0] != "a" || results[1] != "b" {
+		t.Errorf("CallBatch = %v, %v", results, err)
+	}
+
+	select {
+	case err := <-slowDone:
+		t.Fatalf("slow call returned early: %v", err)
+	default:
+	}
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Errorf("slow call failed: %v", err)
+	}
+}
+
+// TestCallTimeout 测试每次调用的超时，超时后的响应被丢弃，连接可继续使用
+func TestCallTimeout(t *testing.T) {
+	release := make(chan struct{})
+	handler, config := startPipelineServer(t, 10014, release)
+	defer handler.Stop(context.Background())
+	defer close(release)
+
+	config.CallTimeout = 100 * time.Millisecond
+	client := NewInternalJsonRpcClient(config)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+
+	_, err := client.Call(context.Background(), "slow", nil, 1)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.Timeout {
+		t.Fatalf("Expected Timeout, got %v", err)
+	}
+
+	// 调用方取消时返回 ClientClosedRequest
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Call(ctx, "slow", nil, 2)
+	if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.ClientClosedRequest {
+		t.Errorf("Expected ClientClosedRequest, got %v", err)
+	}
+
+	if result, err := client.Call(context.Background(), "echo", "ok", 3); err != nil || result != "ok" {
+		t.Errorf("Call after timeout = %v, %v", result, err)
+	}
+
+	// 连接关闭后等待中和之后的调用都返回错误
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.Close()
+	}()
+	config.CallTimeout = 0
+	if _, err := client.Call(context.Background(), "slow", nil, 4); err == nil {
+		t.Error("Expected error after connection closed")
+	}
+	if _, err := client.Call(context.Background(), "echo", "ok", 5); err == nil {
+		t.Error("Expected error on closed client")
+	}
+}
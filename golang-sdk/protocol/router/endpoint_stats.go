@@ -0,0 +1,96 @@
+package router
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyWeight 新的延迟样本在滑动平均中的权重
+const latencyWeight = 0.3
+
+// DefaultEndpointStats 进程内共享的端点负载统计，LeastLoadedLoadBalancer 未指定统计时使用
+var DefaultEndpointStats = NewEndpointStats()
+
+// EndpointLoad 端点的负载
+type EndpointLoad struct {
+	InFlight int64         // 进行中的请求数
+	Latency  time.Duration // 已完成请求延迟的指数加权滑动平均，没有完成的请求时为 0
+	Requests int64         // 已完成的请求数
+}
+
+// EndpointStats 按端点（地址:端口）统计进行中的请求数和延迟
+//
+// 连接管理器在获取连接时调用 Begin、释放连接时调用 End（见 connection.ConnectionConfig.EndpointStats），
+// LeastLoadedLoadBalancer 据此选择负载最低的端点
+type EndpointStats struct {
+	mu        sync.RWMutex
+	endpoints map[string]*EndpointLoad
+}
+
+// NewEndpointStats 创建端点负载统计
+func NewEndpointStats() *EndpointStats {
+	return &EndpointStats{
+		endpoints: make(map[string]*EndpointLoad),
+	}
+}
+
+// EndpointKey 返回端点的统计 key，与 connection.ServiceEndpoint.Key 一致
+func EndpointKey(address string, port int) string {
+	return address + ":" + strconv.Itoa(port)
+}
+
+// Key 返回端点的统计 key
+func (e *ServiceEndpoint) Key() string {
+	return EndpointKey(e.Address, e.Port)
+}
+
+// Begin 记录端点开始处理一个请求
+func (s *EndpointStats) Begin(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	load, ok := s.endpoints[key]
+	if !ok {
+		load = &EndpointLoad{}
+		s.endpoints[key] = load
+	}
+	load.InFlight++
+}
+
+// End 记录端点完成一个请求及其延迟，没有对应的 Begin 时忽略
+func (s *EndpointStats) End(key string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	load, ok := s.endpoints[key]
+	if !ok || load.InFlight == 0 {
+		return
+	}
+	load.InFlight--
+	load.Requests++
+	if load.Latency == 0 {
+		load.Latency = latency
+	} else {
+		load.Latency = time.Duration(float64(load.Latency)*(1-latencyWeight) + float64(latency)*latencyWeight)
+	}
+}
+
+// Load 返回端点的负载，没有统计时返回零值
+func (s *EndpointStats) Load(key string) EndpointLoad {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if load, ok := s.endpoints[key]; ok {
+		return *load
+	}
+	return EndpointLoad{}
+}
+
+// Remove 删除端点的统计，端点下线后调用
+func (s *EndpointStats) Remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.endpoints, key)
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -72,16 +73,34 @@ func (lb *RandomLoadBalancer) Select(endpoints []*ServiceEndpoint) (*ServiceEndp
 }
 
 // WeightedRoundRobinLoadBalancer 加权轮询负载均衡器
+//
+// 按端点元数据中的 weight 平滑加权轮询。调用方经 ResultReporter 报告调用结果时，
+// 错误率或延迟升高的端点按 WeightDecayConfig 逐步降权并随结果好转逐步恢复，比熔断或摘除端点更平滑
 type WeightedRoundRobinLoadBalancer struct {
-	mu              sync.Mutex
-	currentWeights  map[string]int
+	mu             sync.Mutex
+	currentWeights map[string]int
+	decay          *WeightDecayConfig         // 为 nil 时不降权
+	health         map[string]*endpointHealth // 端点ID -> 调用结果的滑动平均
+	now            func() time.Time
 }
 
-// NewWeightedRoundRobinLoadBalancer 创建加权轮询负载均衡器
+// NewWeightedRoundRobinLoadBalancer 创建加权轮询负载均衡器，按 DefaultWeightDecayConfig 降权
 func NewWeightedRoundRobinLoadBalancer() *WeightedRoundRobinLoadBalancer {
-	return &WeightedRoundRobinLoadBalancer{
+	return NewWeightedRoundRobinLoadBalancerWithDecay(DefaultWeightDecayConfig())
+}
+
+// NewWeightedRoundRobinLoadBalancerWithDecay 创建按调用结果降权的加权轮询负载均衡器，decay 为 nil 时不降权，
+// 未设置的字段使用 DefaultWeightDecayConfig 的值
+func NewWeightedRoundRobinLoadBalancerWithDecay(decay *WeightDecayConfig) *WeightedRoundRobinLoadBalancer {
+	lb := &WeightedRoundRobinLoadBalancer{
 		currentWeights: make(map[string]int),
+		health:         make(map[string]*endpointHealth),
+		now:            time.Now,
+	}
+	if decay != nil {
+		lb.decay = decay.normalize()
 	}
+	return lb
 }
 
 // Select 选择端点
@@ -96,26 +115,22 @@ func (lb *WeightedRoundRobinLoadBalancer) Select(endpoints []*ServiceEndpoint) (
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
 
-	// 计算总权重
+	// 计算有效权重和总权重
+	factors := lb.factors(endpoints)
+	weights := make([]int, len(endpoints))
 	totalWeight := 0
-	for _, endpoint := range endpoints {
-		weight := lb.getWeight(endpoint)
-		totalWeight += weight
-	}
-
-	if totalWeight == 0 {
-		// 如果所有权重都是0，使用简单轮询
-		return endpoints[0], nil
+	for i, endpoint := range endpoints {
+		weights[i] = max(1, int(float64(lb.getWeight(endpoint)*weightScale)*factors[i]))
+		totalWeight += weights[i]
 	}
 
 	// 更新当前权重并选择最大的
 	var selected *ServiceEndpoint
-	maxWeight := -1
+	maxWeight := math.MinInt
 
-	for _, endpoint := range endpoints {
-		weight := lb.getWeight(endpoint)
+	for i, endpoint := range endpoints {
 		currentWeight := lb.currentWeights[endpoint.ServiceId]
-		currentWeight += weight
+		currentWeight += weights[i]
 		lb.currentWeights[endpoint.ServiceId] = currentWeight
 
 		if currentWeight > maxWeight {
@@ -125,9 +140,7 @@ func (lb *WeightedRoundRobinLoadBalancer) Select(endpoints []*ServiceEndpoint) (
 	}
 
 	// 减少选中端点的当前权重
-	if selected != nil {
-		lb.currentWeights[selected.ServiceId] -= totalWeight
-	}
+	lb.currentWeights[selected.ServiceId] -= totalWeight
 
 	return selected, nil
 }
@@ -206,3 +219,70 @@ func (lb *LeastConnectionLoadBalancer) ReleaseConnection(endpointId string) {
 		lb.connections[endpointId]--
 	}
 }
+
+// LeastLoadedLoadBalancer 最低负载负载均衡器
+//
+// 按 EndpointStats 中的运行时负载选择端点：负载为 (进行中的请求数 + 1) × 平均延迟，
+// 同时考虑并发和处理速度；还没有完成请求的端点以其他端点的平均延迟计算，负载相同时轮流选择。
+// 统计由连接管理器在释放连接时更新，与 LeastConnectionLoadBalancer 只统计本负载均衡器的选择次数不同
+type LeastLoadedLoadBalancer struct {
+	stats   *EndpointStats
+	mu      sync.Mutex
+	counter int
+}
+
+// NewLeastLoadedLoadBalancer 创建最低负载负载均衡器，stats 为 nil 时使用 DefaultEndpointStats
+func NewLeastLoadedLoadBalancer(stats *EndpointStats) *LeastLoadedLoadBalancer {
+	if stats == nil {
+		stats = DefaultEndpointStats
+	}
+	return &LeastLoadedLoadBalancer{stats: stats}
+}
+
+// Select 选择端点
+func (lb *LeastLoadedLoadBalancer) Select(endpoints []*ServiceEndpoint) (*ServiceEndpoint, error) {
+	if len(endpoints) == 0 {
+		return nil, &adapter.FrameworkError{
+			Code:    adapter.ErrorNotFound,
+			Message: "no endpoints available",
+		}
+	}
+
+	loads := make([]EndpointLoad, len(endpoints))
+	var known int
+	var total time.Duration
+	for i, endpoint := range endpoints {
+		loads[i] = lb.stats.Load(endpoint.Key())
+		if loads[i].Requests > 0 {
+			known++
+			total += loads[i].Latency
+		}
+	}
+	// 没有完成请求的端点按平均延迟计算，都没有时只比较进行中的请求数
+	defaultLatency := time.Duration(1)
+	if known > 0 {
+		defaultLatency = max(total/time.Duration(known), 1)
+	}
+
+	lb.mu.Lock()
+	start := lb.counter % len(endpoints)
+	lb.counter++
+	lb.mu.Unlock()
+
+	var selected *ServiceEndpoint
+	var minCost float64
+	for i := range endpoints {
+		index := (start + i) % len(endpoints)
+		latency := defaultLatency
+		if loads[index].Requests > 0 {
+			latency = max(loads[index].Latency, 1)
+		}
+		cost := float64(loads[index].InFlight+1) * float64(latency)
+		if selected == nil || cost < minCost {
+			selected = endpoints[index]
+			minCost = cost
+		}
+	}
+
+	return selected, nil
+}
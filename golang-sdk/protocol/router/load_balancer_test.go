@@ -2,6 +2,7 @@ package router
 
 import (
 	"testing"
+	"time"
 )
 
 func TestRoundRobinLoadBalancer_Select(t *testing.T) {
@@ -141,6 +142,59 @@ func TestWeightedRoundRobinLoadBalancer_Select_NoWeight(t *testing.T) {
 	}
 }
 
+func TestWeightedRoundRobinLoadBalancer_Decay(t *testing.T) {
+	lb := NewWeightedRoundRobinLoadBalancer()
+	now := time.Now()
+	lb.now = func() time.Time { return now }
+
+	endpoints := []*ServiceEndpoint{
+		{ServiceId: "e1", Address: "localhost", Port: 8080},
+		{ServiceId: "e2", Address: "localhost", Port: 8081},
+		{ServiceId: "e3", Address: "localhost", Port: 8082},
+	}
+	count := func(n int) map[string]int {
+		results := make(map[string]int)
+		for i := 0; i < n; i++ {
+			endpoint, _ := lb.Select(endpoints)
+			results[endpoint.ServiceId]++
+		}
+		return results
+	}
+
+	// e2 错误率升高、e3 延迟升高时逐步降权，而不是完全摘除
+	for i := 0; i < 10; i++ {
+		lb.ReportResult(endpoints[0], 10*time.Millisecond, false)
+		lb.ReportResult(endpoints[1], 10*time.Millisecond, i%2 == 0)
+		lb.ReportResult(endpoints[2], 200*time.Millisecond, false)
+	}
+	weights := lb.EffectiveWeights(endpoints)
+	if weights["e1"] != 1 || weights["e2"] >= 0.8 || weights["e3"] >= 0.5 || weights["e3"] < 0.1 {
+		t.Fatalf("Unexpected effective weights: %v", weights)
+	}
+	results := count(100)
+	if results["e1"] <= results["e2"] || results["e2"] <= results["e3"] || results["e3"] == 0 {
+		t.Errorf("Expected e1 > e2 > e3 > 0, got %v", results)
+	}
+
+	// 没有新的调用结果时按半衰期逐步恢复
+	now = now.Add(10 * time.Second)
+	recovering := lb.EffectiveWeights(endpoints)
+	if recovering["e3"] <= weights["e3"] || recovering["e3"] >= 1 {
+		t.Errorf("Expected e3 partially recovered, got %v (was %v)", recovering["e3"], weights["e3"])
+	}
+	now = now.Add(5 * time.Minute)
+	if recovered := lb.EffectiveWeights(endpoints); recovered["e2"] < 0.99 || recovered["e3"] < 0.99 {
+		t.Errorf("Expected weights recovered, got %v", recovered)
+	}
+
+	// 未启用降权时忽略调用结果
+	plain := NewWeightedRoundRobinLoadBalancerWithDecay(nil)
+	ReportResult(plain, endpoints[0], time.Second, true)
+	if weights := plain.EffectiveWeights(endpoints); weights["e1"] != 1 {
+		t.Errorf("Expected no decay, got %v", weights)
+	}
+}
+
 func TestLeastConnectionLoadBalancer_Select(t *testing.T) {
 	lb := NewLeastConnectionLoadBalancer()
 
@@ -214,3 +268,91 @@ func TestLeastConnectionLoadBalancer_Select_EmptyEndpoints(t *testing.T) {
 		t.Error("Should return error for empty endpoints")
 	}
 }
+
+func TestLeastLoadedLoadBalancer_Select(t *testing.T) {
+	stats := NewEndpointStats()
+	lb := NewLeastLoadedLoadBalancer(stats)
+
+	endpoints := []*ServiceEndpoint{
+		{ServiceId: "e1", Address: "localhost", Port: 8080},
+		{ServiceId: "e2", Address: "localhost", Port: 8081},
+		{ServiceId: "e3", Address: "localhost", Port: 8082},
+	}
+
+	// 没有统计时轮流选择
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		endpoint, err := lb.Select(endpoints)
+		if err != nil {
+			t.Fatalf("Select failed: %v", err)
+		}
+		seen[endpoint.ServiceId] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected all endpoints to be selected, got %v", seen)
+	}
+
+	// e1 慢，e2 快但有 3 个进行中的请求，e3 还没有完成的请求按平均延迟计算
+	stats.Begin("localhost:8080")
+	stats.End("localhost:8080", 100*time.Millisecond)
+	stats.Begin("localhost:8081")
+	stats.End("localhost:8081", 10*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		stats.Begin("localhost:8081")
+	}
+	stats.Begin("localhost:8082")
+
+	// 负载：e1 = 1 × 100ms，e2 = 4 × 10ms，e3 = 2 × 55ms
+	for i := 0; i < 3; i++ {
+		endpoint, err := lb.Select(endpoints)
+		if err != nil {
+			t.Fatalf("Select failed: %v", err)
+		}
+		if endpoint.ServiceId != "e2" {
+			t.Errorf("Expected e2, got %s", endpoint.ServiceId)
+		}
+	}
+
+	// e2 的请求变多后选择 e1
+	for i := 0; i < 8; i++ {
+		stats.Begin("localhost:8081")
+	}
+	endpoint, _ := lb.Select(endpoints)
+	if endpoint.ServiceId != "e1" {
+		t.Errorf("Expected e1, got %s", endpoint.ServiceId)
+	}
+
+	if _, err := lb.Select([]*ServiceEndpoint{}); err == nil {
+		t.Error("Should return error for empty endpoints")
+	}
+}
+
+func TestEndpointStats(t *testing.T) {
+	stats := NewEndpointStats()
+
+	stats.Begin("localhost:8080")
+	stats.Begin("localhost:8080")
+	stats.End("localhost:8080", 100*time.Millisecond)
+	stats.End("localhost:8080", 200*time.Millisecond)
+
+	load := stats.Load("localhost:8080")
+	if load.InFlight != 0 || load.Requests != 2 {
+		t.Errorf("Expected 0 in flight and 2 requests, got %+v", load)
+	}
+	// 滑动平均：100ms × 0.7 + 200ms × 0.3
+	if load.Latency != 130*time.Millisecond {
+		t.Errorf("Expected latency 130ms, got %v", load.Latency)
+	}
+
+	// 没有对应 Begin 的 End 被忽略
+	stats.End("localhost:8080", time.Second)
+	stats.End("localhost:9090", time.Second)
+	if got := stats.Load("localhost:8080"); got != load {
+		t.Errorf("Unmatched End changed load to %+v", got)
+	}
+
+	stats.Remove("localhost:8080")
+	if got := stats.Load("localhost:8080"); got != (EndpointLoad{}) {
+		t.Errorf("Expected zero load after Remove, got %+v", got)
+	}
+}
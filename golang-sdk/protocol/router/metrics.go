@@ -0,0 +1,53 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	routerMetricsOnce sync.Once
+	// 路由计数器
+	routeTotal *prometheus.CounterVec
+	// 路由耗时直方图
+	routeDuration *prometheus.HistogramVec
+)
+
+// initRouterMetrics 初始化路由器指标
+func initRouterMetrics() {
+	routerMetricsOnce.Do(func() {
+		routeTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_router_route_total",
+				Help: "Total number of routing decisions",
+			},
+			[]string{"protocol", "service", "method", "error_code"},
+		)
+		routeDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_router_route_duration_seconds",
+				Help:    "Routing duration in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"protocol", "service", "method"},
+		)
+	})
+}
+
+// RecordRoute 记录一次路由结果，protocol 取选中端点的协议，路由失败时为空
+func RecordRoute(service, method string, endpoint *ServiceEndpoint, err error, duration time.Duration) {
+	initRouterMetrics()
+
+	var protocol string
+	if endpoint != nil {
+		protocol = string(endpoint.Protocol)
+	}
+
+	routeTotal.WithLabelValues(protocol, service, method, adapter.ErrorCodeLabel(err)).Inc()
+	routeDuration.WithLabelValues(protocol, service, method).Observe(duration.Seconds())
+}
@@ -3,9 +3,12 @@ package router
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/framework/golang-sdk/protocol/adapter"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ServiceEndpoint 服务端点
@@ -15,6 +18,8 @@ type ServiceEndpoint struct {
 	Port      int               // 端口
 	Protocol  adapter.ProtocolType // 协议类型
 	Metadata  map[string]string // 元数据
+	Serializations []string     // 支持的序列化格式，为空时视为只支持 json
+	Language  string            // 服务实例的实现语言（如 java、php），来自注册信息，为空时未知
 }
 
 // RoutingRule 路由规则
@@ -23,6 +28,22 @@ type RoutingRule struct {
 	Priority int                                         // 优先级（数字越大优先级越高）
 	Matcher  func(*adapter.InternalRequest) bool        // 匹配函数
 	Target   func(*adapter.InternalRequest) string      // 目标服务函数
+	Schedule *Schedule                                   // 生效窗口，为 nil 时始终生效
+}
+
+// Active 判断规则在 t 时刻是否生效
+func (rule *RoutingRule) Active(t time.Time) bool {
+	return rule.Schedule == nil || rule.Schedule.Active(t)
+}
+
+// ApplyRules 按 rules 的顺序返回第一条在 now 时刻生效且匹配请求的规则的目标服务，没有匹配时返回空字符串
+func ApplyRules(rules []*RoutingRule, request *adapter.InternalRequest, now time.Time) string {
+	for _, rule := range rules {
+		if rule.Active(now) && rule.Matcher(request) {
+			return rule.Target(request)
+		}
+	}
+	return ""
 }
 
 // MessageRouter 消息路由器接口
@@ -46,8 +67,13 @@ type DefaultMessageRouter struct {
 	routingTable   map[string][]*ServiceEndpoint // 服务名 -> 端点列表
 	rules          []*RoutingRule                 // 路由规则列表（按优先级排序）
 	loadBalancer   LoadBalancer                   // 负载均衡器
+	onRemoved      []EndpointRemovedListener      // 端点移除监听器
+	preferred      map[string]adapter.ProtocolType // 服务名 -> 首选协议
 }
 
+// EndpointRemovedListener 端点从路由表移除时的监听器
+type EndpointRemovedListener func(serviceName string, endpoint *ServiceEndpoint)
+
 // LoadBalancer 负载均衡器接口
 type LoadBalancer interface {
 	// Select 从端点列表中选择一个端点
@@ -64,11 +90,24 @@ func NewDefaultMessageRouter(loadBalancer LoadBalancer) *DefaultMessageRouter {
 		routingTable: make(map[string][]*ServiceEndpoint),
 		rules:        make([]*RoutingRule, 0),
 		loadBalancer: loadBalancer,
+		preferred:    make(map[string]adapter.ProtocolType),
 	}
 }
 
 // Route 路由消息到目标服务
-func (r *DefaultMessageRouter) Route(ctx context.Context, request *adapter.InternalRequest) (*ServiceEndpoint, error) {
+func (r *DefaultMessageRouter) Route(ctx context.Context, request *adapter.InternalRequest) (endpoint *ServiceEndpoint, err error) {
+	ctx, span := adapter.StartInternalSpan(ctx, "router.Route")
+	start := time.Now()
+	var targetService string
+	defer func() {
+		adapter.EndSpan(span, err)
+		var method string
+		if request != nil {
+			method = request.Method
+		}
+		RecordRoute(targetService, method, endpoint, err, time.Since(start))
+	}()
+
 	if request == nil {
 		return nil, &adapter.FrameworkError{
 			Code:    adapter.ErrorBadRequest,
@@ -77,10 +116,11 @@ func (r *DefaultMessageRouter) Route(ctx context.Context, request *adapter.Inter
 	}
 
 	// 应用路由规则
-	targetService := r.applyRoutingRules(request)
+	targetService = r.applyRoutingRules(request)
 	if targetService == "" {
 		targetService = request.Service
 	}
+	span.SetAttributes(adapter.AttrService.String(targetService), adapter.AttrMethod.String(request.Method))
 
 	// 查找服务端点
 	endpoints, err := r.GetServiceEndpoints(targetService)
@@ -95,8 +135,11 @@ func (r *DefaultMessageRouter) Route(ctx context.Context, request *adapter.Inter
 		}
 	}
 
+	// 存在首选协议的端点时只在其中选择
+	endpoints = r.filterPreferred(targetService, endpoints)
+
 	// 使用负载均衡器选择端点
-	endpoint, err := r.loadBalancer.Select(endpoints)
+	endpoint, err = SelectEndpoint(ctx, r.loadBalancer, endpoints)
 	if err != nil {
 		return nil, &adapter.FrameworkError{
 			Code:    adapter.ErrorRouting,
@@ -108,6 +151,22 @@ func (r *DefaultMessageRouter) Route(ctx context.Context, request *adapter.Inter
 	return endpoint, nil
 }
 
+// SelectEndpoint 通过负载均衡器选择端点，选择过程记录为独立的子 span
+func SelectEndpoint(ctx context.Context, loadBalancer LoadBalancer, endpoints []*ServiceEndpoint) (*ServiceEndpoint, error) {
+	_, span := adapter.StartInternalSpan(ctx, "router.SelectEndpoint",
+		attribute.Int("router.candidates", len(endpoints)))
+
+	endpoint, err := loadBalancer.Select(endpoints)
+	if err == nil {
+		span.SetAttributes(
+			adapter.AttrEndpoint.String(fmt.Sprintf("%s:%d", endpoint.Address, endpoint.Port)),
+			adapter.AttrProtocol.String(string(endpoint.Protocol)),
+		)
+	}
+	adapter.EndSpan(span, err)
+	return endpoint, err
+}
+
 // RegisterRule 注册路由规则
 func (r *DefaultMessageRouter) RegisterRule(rule *RoutingRule) error {
 	if rule == nil {
@@ -131,6 +190,16 @@ func (r *DefaultMessageRouter) RegisterRule(rule *RoutingRule) error {
 		}
 	}
 
+	if rule.Schedule != nil {
+		if err := rule.Schedule.Validate(); err != nil {
+			return &adapter.FrameworkError{
+				Code:    adapter.ErrorBadRequest,
+				Message: fmt.Sprintf("invalid schedule for rule %s", rule.Name),
+				Cause:   err,
+			}
+		}
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -187,15 +256,8 @@ func (r *DefaultMessageRouter) applyRoutingRules(request *adapter.InternalReques
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// 按优先级顺序应用规则
-	for _, rule := range r.rules {
-		if rule.Matcher(request) {
-			return rule.Target(request)
-		}
-	}
-
-	// 没有匹配的规则，返回空字符串
-	return ""
+	// 按优先级顺序应用当前生效的规则，没有匹配的规则时返回空字符串
+	return ApplyRules(r.rules, request, time.Now())
 }
 
 // sortRules 按优先级排序规则（优先级高的在前）
@@ -211,6 +273,63 @@ func (r *DefaultMessageRouter) sortRules() {
 	}
 }
 
+// SetPreferredProtocol 设置服务的首选协议，protocol 为空时清除
+func (r *DefaultMessageRouter) SetPreferredProtocol(serviceName string, protocol adapter.ProtocolType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if protocol == "" {
+		delete(r.preferred, serviceName)
+		return
+	}
+	r.preferred[serviceName] = protocol
+}
+
+// filterPreferred 返回使用首选协议的端点，没有首选协议或没有匹配端点时返回全部端点
+func (r *DefaultMessageRouter) filterPreferred(serviceName string, endpoints []*ServiceEndpoint) []*ServiceEndpoint {
+	r.mu.RLock()
+	protocol, ok := r.preferred[serviceName]
+	r.mu.RUnlock()
+	if !ok {
+		return endpoints
+	}
+
+	matched := make([]*ServiceEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint.Protocol == protocol {
+			matched = append(matched, endpoint)
+		}
+	}
+	if len(matched) == 0 {
+		return endpoints
+	}
+	return matched
+}
+
+// OnEndpointRemoved 注册端点移除监听器
+func (r *DefaultMessageRouter) OnEndpointRemoved(listener EndpointRemovedListener) {
+	if listener == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRemoved = append(r.onRemoved, listener)
+}
+
+// EventEndpointEjected 端点移出路由表的框架事件类型，与 observability.EventEndpointEjected 一致
+const EventEndpointEjected = "endpoint.ejected"
+
+// WatchEvents 将端点移出路由表以框架事件发布，实现 observability.EventSource，事件来源为服务名
+func (r *DefaultMessageRouter) WatchEvents(emit func(eventType, source string, attributes map[string]string)) {
+	r.OnEndpointRemoved(func(serviceName string, endpoint *ServiceEndpoint) {
+		emit(EventEndpointEjected, serviceName, map[string]string{
+			"endpoint_id": endpoint.ServiceId,
+			"address":     endpoint.Address + ":" + strconv.Itoa(endpoint.Port),
+		})
+	})
+}
+
 // AddServiceEndpoint 添加服务端点
 func (r *DefaultMessageRouter) AddServiceEndpoint(serviceName string, endpoint *ServiceEndpoint) error {
 	if serviceName == "" {
@@ -242,10 +361,10 @@ func (r *DefaultMessageRouter) AddServiceEndpoint(serviceName string, endpoint *
 // RemoveServiceEndpoint 移除服务端点
 func (r *DefaultMessageRouter) RemoveServiceEndpoint(serviceName string, endpointId string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	endpoints, exists := r.routingTable[serviceName]
 	if !exists {
+		r.mu.Unlock()
 		return &adapter.FrameworkError{
 			Code:    adapter.ErrorNotFound,
 			Message: fmt.Sprintf("service not found: %s", serviceName),
@@ -256,9 +375,16 @@ func (r *DefaultMessageRouter) RemoveServiceEndpoint(serviceName string, endpoin
 	for i, endpoint := range endpoints {
 		if endpoint.ServiceId == endpointId {
 			r.routingTable[serviceName] = append(endpoints[:i], endpoints[i+1:]...)
+			listeners := r.onRemoved
+			r.mu.Unlock()
+
+			for _, listener := range listeners {
+				listener(serviceName, endpoint)
+			}
 			return nil
 		}
 	}
+	r.mu.Unlock()
 
 	return &adapter.FrameworkError{
 		Code:    adapter.ErrorNotFound,
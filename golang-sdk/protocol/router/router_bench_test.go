@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// newBenchmarkRouter 创建包含 services 个服务、每个服务 instances 个实例的路由器
+func newBenchmarkRouter(b *testing.B, loadBalancer LoadBalancer, services, instances int) *DefaultMessageRouter {
+	r := NewDefaultMessageRouter(loadBalancer)
+	table := make(map[string][]*ServiceEndpoint, services)
+	for s := 0; s < services; s++ {
+		name := fmt.Sprintf("service-%d", s)
+		for n := 0; n < instances; n++ {
+			table[name] = append(table[name], &ServiceEndpoint{
+				ServiceId: fmt.Sprintf("%s-%d", name, n),
+				Address:   fmt.Sprintf("10.0.%d.%d", s, n),
+				Port:      8080,
+				Protocol:  adapter.ProtocolGRPC,
+			})
+		}
+	}
+	if err := r.UpdateRoutingTable(table); err != nil {
+		b.Fatal(err)
+	}
+	return r
+}
+
+// BenchmarkRoute 按服务名路由的基准测试
+func BenchmarkRoute(b *testing.B) {
+	r := newBenchmarkRouter(b, nil, 50, 8)
+	request := &adapter.InternalRequest{Service: "service-7", Method: "get"}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Route(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRouteWithRules 匹配路由规则后路由的基准测试
+func BenchmarkRouteWithRules(b *testing.B) {
+	r := newBenchmarkRouter(b, nil, 50, 8)
+	for n := 0; n < 10; n++ {
+		method := fmt.Sprintf("method-%d", n)
+		r.RegisterRule(&RoutingRule{
+			Name:     method,
+			Priority: n,
+			Matcher:  func(request *adapter.InternalRequest) bool { return request.Method == method },
+			Target:   func(*adapter.InternalRequest) string { return "service-1" },
+		})
+	}
+	request := &adapter.InternalRequest{Service: "service-7", Method: "method-0"}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Route(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRouteParallel 并发路由的基准测试，各负载均衡策略分别测试
+func BenchmarkRouteParallel(b *testing.B) {
+	balancers := []struct {
+		name string
+		new  func() LoadBalancer
+	}{
+		{"RoundRobin", func() LoadBalancer { return NewRoundRobinLoadBalancer() }},
+		{"Random", func() LoadBalancer { return NewRandomLoadBalancer() }},
+		{"WeightedRoundRobin", func() LoadBalancer { return NewWeightedRoundRobinLoadBalancer() }},
+		{"LeastConnection", func() LoadBalancer { return NewLeastConnectionLoadBalancer() }},
+	}
+	for _, balancer := range balancers {
+		b.Run(balancer.name, func(b *testing.B) {
+			r := newBenchmarkRouter(b, balancer.new(), 50, 8)
+			request := &adapter.InternalRequest{Service: "service-7", Method: "get"}
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := r.Route(ctx, request); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
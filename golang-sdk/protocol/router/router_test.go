@@ -270,3 +270,55 @@ func TestDefaultMessageRouter_Route_NilRequest(t *testing.T) {
 		t.Error("Should return error for nil request")
 	}
 }
+
+func TestDefaultMessageRouter_PreferredProtocol(t *testing.T) {
+	router := NewDefaultMessageRouter(nil)
+	ctx := context.Background()
+
+	router.AddServiceEndpoint("payment-service", &ServiceEndpoint{
+		ServiceId: "payment-rest",
+		Address:   "localhost",
+		Port:      8080,
+		Protocol:  adapter.ProtocolREST,
+	})
+	router.AddServiceEndpoint("payment-service", &ServiceEndpoint{
+		ServiceId: "payment-grpc",
+		Address:   "localhost",
+		Port:      9090,
+		Protocol:  adapter.ProtocolGRPC,
+	})
+
+	request := &adapter.InternalRequest{Service: "payment-service", Method: "pay"}
+
+	// 存在首选协议的端点时总是选择该端点
+	router.SetPreferredProtocol("payment-service", adapter.ProtocolGRPC)
+	for i := 0; i < 4; i++ {
+		result, err := router.Route(ctx, request)
+		if err != nil {
+			t.Fatalf("Route failed: %v", err)
+		}
+		if result.ServiceId != "payment-grpc" {
+			t.Errorf("Expected preferred endpoint 'payment-grpc', got '%s'", result.ServiceId)
+		}
+	}
+
+	// 没有首选协议的端点时回退到全部端点
+	router.SetPreferredProtocol("payment-service", adapter.ProtocolMQTT)
+	if _, err := router.Route(ctx, request); err != nil {
+		t.Fatalf("Route should fall back to all endpoints: %v", err)
+	}
+
+	// 清除首选协议后按负载均衡轮询
+	router.SetPreferredProtocol("payment-service", "")
+	seen := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		result, err := router.Route(ctx, request)
+		if err != nil {
+			t.Fatalf("Route failed: %v", err)
+		}
+		seen[result.ServiceId] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("Expected both endpoints after clearing preference, got %v", seen)
+	}
+}
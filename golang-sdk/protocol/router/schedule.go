@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/cron"
+)
+
+// MaxScheduleDuration cron 窗口持续时间的上限
+const MaxScheduleDuration = 31 * 24 * time.Hour
+
+// Schedule 路由规则的生效窗口，用于提前安排维护期间的重定向和区域切换，窗口结束后自动恢复
+//
+// Start 和 End 限定绝对时间范围（左闭右开），为零值时不限；Cron 不为空时规则只在每次触发后的 Duration 内生效，
+// 例如 Cron 为 "0 2 * * SUN"、Duration 为 2h 表示每周日 02:00-04:00。两者同时设置时需同时满足
+type Schedule struct {
+	Start    time.Time
+	End      time.Time
+	Cron     string
+	Duration time.Duration
+	// Location 计算 Cron 使用的时区，为 nil 时使用本地时区
+	Location *time.Location
+
+	once sync.Once
+	expr *cron.Expr
+	err  error
+
+	// 缓存最近一次计算的分钟及其之前的最近触发时间，同一分钟内的请求不再重复扫描
+	mu         sync.Mutex
+	lastMinute time.Time
+	lastFire   time.Time
+}
+
+// Validate 校验生效窗口
+func (s *Schedule) Validate() error {
+	if !s.Start.IsZero() && !s.End.IsZero() && !s.End.After(s.Start) {
+		return fmt.Errorf("schedule end %s must be after start %s", s.End.Format(time.RFC3339), s.Start.Format(time.RFC3339))
+	}
+	if s.Cron == "" {
+		if s.Duration != 0 {
+			return fmt.Errorf("schedule duration requires cron")
+		}
+		return nil
+	}
+	if s.Duration <= 0 || s.Duration > MaxScheduleDuration {
+		return fmt.Errorf("schedule duration must be positive and at most %s when cron is set, got %s", MaxScheduleDuration, s.Duration)
+	}
+	_, err := s.compile()
+	return err
+}
+
+// Active 判断规则在 t 时刻是否生效
+func (s *Schedule) Active(t time.Time) bool {
+	if !s.Start.IsZero() && t.Before(s.Start) {
+		return false
+	}
+	if !s.End.IsZero() && !t.Before(s.End) {
+		return false
+	}
+	if s.Cron == "" {
+		return true
+	}
+
+	expr, err := s.compile()
+	if err != nil {
+		return false
+	}
+	fire := s.prevFire(expr, t)
+	return !fire.IsZero() && t.Before(fire.Add(s.Duration))
+}
+
+// compile 解析 Cron，结果只计算一次
+func (s *Schedule) compile() (*cron.Expr, error) {
+	s.once.Do(func() {
+		s.expr, s.err = cron.Parse(s.Cron)
+	})
+	return s.expr, s.err
+}
+
+// prevFire 返回 t 之前 Duration 内最近一次触发时间，没有时返回零值
+func (s *Schedule) prevFire(expr *cron.Expr, t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	minute := t.Truncate(time.Minute)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !minute.Equal(s.lastMinute) {
+		// 更早的触发在 minute 时刻窗口已经结束，只需扫描 Duration 之内的整分钟
+		s.lastFire = expr.Prev(minute, s.Duration)
+		s.lastMinute = minute
+	}
+	return s.lastFire
+}
@@ -0,0 +1,120 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+func TestSchedule_Window(t *testing.T) {
+	start := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	s := &Schedule{Start: start, End: start.Add(2 * time.Hour)}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{start.Add(-time.Second), false},
+		{start, true},
+		{start.Add(119 * time.Minute), true},
+		{start.Add(2 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := s.Active(tt.t); got != tt.want {
+			t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Cron(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	// 每周日 02:00-04:00（东八区）
+	s := &Schedule{Cron: "0 2 * * SUN", Duration: 2 * time.Hour, Location: shanghai}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	sunday := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 3, hour, minute, 0, 0, shanghai).UTC()
+	}
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{sunday(1, 59), false},
+		{sunday(2, 0), true},
+		{sunday(3, 30), true},
+		{sunday(3, 59).Add(59 * time.Second), true},
+		{sunday(4, 0), false},
+		{sunday(2, 0).Add(24 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := s.Active(tt.t); got != tt.want {
+			t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		schedule *Schedule
+	}{
+		{name: "end before start", schedule: &Schedule{Start: now, End: now.Add(-time.Hour)}},
+		{name: "cron without duration", schedule: &Schedule{Cron: "0 2 * * *"}},
+		{name: "duration without cron", schedule: &Schedule{Duration: time.Hour}},
+		{name: "invalid cron", schedule: &Schedule{Cron: "0 25 * * *", Duration: time.Hour}},
+		{name: "duration too long", schedule: &Schedule{Cron: "@daily", Duration: MaxScheduleDuration + time.Hour}},
+	}
+	for _, tt := range tests {
+		if err := tt.schedule.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestDefaultMessageRouter_ScheduledRule(t *testing.T) {
+	r := NewDefaultMessageRouter(nil)
+	r.UpdateRoutingTable(map[string][]*ServiceEndpoint{
+		"orders":        {{ServiceId: "orders-1", Address: "localhost", Port: 8080}},
+		"orders-backup": {{ServiceId: "orders-backup-1", Address: "localhost", Port: 9090}},
+	})
+
+	redirect := func(schedule *Schedule) *RoutingRule {
+		return &RoutingRule{
+			Name:     "maintenance",
+			Priority: 10,
+			Matcher:  func(req *adapter.InternalRequest) bool { return req.Service == "orders" },
+			Target:   func(req *adapter.InternalRequest) string { return "orders-backup" },
+			Schedule: schedule,
+		}
+	}
+	if err := r.RegisterRule(redirect(&Schedule{Cron: "bad", Duration: time.Hour})); err == nil {
+		t.Fatal("Expected error for invalid schedule")
+	}
+
+	// 维护窗口已经结束，规则不再生效
+	past := time.Now().Add(-2 * time.Hour)
+	if err := r.RegisterRule(redirect(&Schedule{Start: past, End: past.Add(time.Hour)})); err != nil {
+		t.Fatalf("RegisterRule failed: %v", err)
+	}
+	endpoint, err := r.Route(context.Background(), &adapter.InternalRequest{Service: "orders"})
+	if err != nil || endpoint.ServiceId != "orders-1" {
+		t.Fatalf("Expected original service after window, got %+v, %v", endpoint, err)
+	}
+
+	// 进行中的窗口重定向到备用服务
+	if err := r.RegisterRule(redirect(&Schedule{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)})); err != nil {
+		t.Fatalf("RegisterRule failed: %v", err)
+	}
+	endpoint, err = r.Route(context.Background(), &adapter.InternalRequest{Service: "orders"})
+	if err != nil || endpoint.ServiceId != "orders-backup-1" {
+		t.Fatalf("Expected backup service during window, got %+v, %v", endpoint, err)
+	}
+}
@@ -0,0 +1,163 @@
+package router
+
+import (
+	"math"
+	"time"
+)
+
+// weightScale 有效权重相对配置权重的放大倍数，使降权后的权重仍可用整数表示
+const weightScale = 100
+
+// ResultReporter 接收调用结果的负载均衡器（如 WeightedRoundRobinLoadBalancer）
+//
+// 调用方在每次调用结束后报告端点的延迟和是否失败，failed 只应表示服务端故障（5xx、超时、连接错误），
+// 业务错误（4xx）不应计入
+type ResultReporter interface {
+	ReportResult(endpoint *ServiceEndpoint, latency time.Duration, failed bool)
+}
+
+// ReportResult 负载均衡器实现 ResultReporter 时报告调用结果，否则忽略
+func ReportResult(loadBalancer LoadBalancer, endpoint *ServiceEndpoint, latency time.Duration, failed bool) {
+	if reporter, ok := loadBalancer.(ResultReporter); ok && endpoint != nil {
+		reporter.ReportResult(endpoint, latency, failed)
+	}
+}
+
+// WeightDecayConfig 加权轮询按调用结果降权的配置
+//
+// 端点的错误率和延迟的指数加权滑动平均决定降权比例：有效权重 = 配置权重 × (1 - 错误率) × 延迟系数，
+// 延迟超过其他端点平均延迟的 LatencyThreshold 倍时延迟系数为 阈值延迟 / 端点延迟，否则为 1。
+// 降权幅度在没有新的调用结果时按 RecoveryHalfLife 减半，端点逐渐恢复而不是一次性恢复；
+// 有效权重不低于配置权重的 MinFactor，降权的端点仍分到少量请求，据此反映恢复情况
+type WeightDecayConfig struct {
+	// Alpha 新的调用结果在滑动平均中的权重，默认 0.3
+	Alpha float64
+	// LatencyThreshold 端点延迟超过其他端点平均延迟的倍数时开始降权，默认 2
+	LatencyThreshold float64
+	// MinFactor 有效权重相对配置权重的最低比例，默认 0.1
+	MinFactor float64
+	// RecoveryHalfLife 没有新的调用结果时降权幅度减半的时间，默认 10 秒
+	RecoveryHalfLife time.Duration
+}
+
+// DefaultWeightDecayConfig 返回默认的降权配置
+func DefaultWeightDecayConfig() *WeightDecayConfig {
+	return &WeightDecayConfig{
+		Alpha:            latencyWeight,
+		LatencyThreshold: 2,
+		MinFactor:        0.1,
+		RecoveryHalfLife: 10 * time.Second,
+	}
+}
+
+// endpointHealth 端点调用结果的滑动平均
+type endpointHealth struct {
+	errorRate float64
+	latency   time.Duration
+	updated   time.Time
+}
+
+// record 将一次调用结果计入滑动平均
+func (h *endpointHealth) record(alpha float64, latency time.Duration, failed bool, now time.Time) {
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+	if h.updated.IsZero() {
+		h.errorRate, h.latency = sample, latency
+	} else {
+		h.errorRate = h.errorRate*(1-alpha) + sample*alpha
+		h.latency = time.Duration(float64(h.latency)*(1-alpha) + float64(latency)*alpha)
+	}
+	h.updated = now
+}
+
+// normalize 补全未设置的降权配置
+func (c *WeightDecayConfig) normalize() *WeightDecayConfig {
+	defaults := DefaultWeightDecayConfig()
+	cfg := *c
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		cfg.Alpha = defaults.Alpha
+	}
+	if cfg.LatencyThreshold < 1 {
+		cfg.LatencyThreshold = defaults.LatencyThreshold
+	}
+	if cfg.MinFactor <= 0 || cfg.MinFactor > 1 {
+		cfg.MinFactor = defaults.MinFactor
+	}
+	if cfg.RecoveryHalfLife <= 0 {
+		cfg.RecoveryHalfLife = defaults.RecoveryHalfLife
+	}
+	return &cfg
+}
+
+// decayFactors 返回各端点有效权重相对配置权重的比例，没有调用结果的端点为 1；调用时须持有 lb.mu
+func (lb *WeightedRoundRobinLoadBalancer) decayFactors(endpoints []*ServiceEndpoint, now time.Time) []float64 {
+	factors := make([]float64, len(endpoints))
+	var total time.Duration
+	var known int
+	for _, endpoint := range endpoints {
+		if h, ok := lb.health[endpoint.ServiceId]; ok {
+			total += h.latency
+			known++
+		}
+	}
+	cfg := lb.decay
+	for i, endpoint := range endpoints {
+		factors[i] = 1
+		h, ok := lb.health[endpoint.ServiceId]
+		if !ok {
+			continue
+		}
+		// 与其他端点的平均延迟比较，避免慢端点自身拉高基准
+		latencyFactor := 1.0
+		if known > 1 && h.latency > 0 {
+			threshold := float64(total-h.latency) / float64(known-1) * cfg.LatencyThreshold
+			latencyFactor = math.Min(1, threshold/float64(h.latency))
+		}
+		penalty := 1 - (1-h.errorRate)*latencyFactor
+		// 没有新的调用结果时降权幅度按半衰期恢复
+		penalty *= math.Exp2(-float64(now.Sub(h.updated)) / float64(cfg.RecoveryHalfLife))
+		factors[i] = math.Max(cfg.MinFactor, 1-penalty)
+	}
+	return factors
+}
+
+// ReportResult 将调用结果计入端点的错误率和延迟，实现 ResultReporter；未启用降权时忽略
+func (lb *WeightedRoundRobinLoadBalancer) ReportResult(endpoint *ServiceEndpoint, latency time.Duration, failed bool) {
+	if lb.decay == nil {
+		return
+	}
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	h, ok := lb.health[endpoint.ServiceId]
+	if !ok {
+		h = &endpointHealth{}
+		lb.health[endpoint.ServiceId] = h
+	}
+	h.record(lb.decay.Alpha, latency, failed, time.Now())
+}
+
+// EffectiveWeights 返回各端点当前的有效权重（配置权重 × 降权比例），按 ServiceId 索引，用于观察降权情况
+func (lb *WeightedRoundRobinLoadBalancer) EffectiveWeights(endpoints []*ServiceEndpoint) map[string]float64 {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	weights := make(map[string]float64, len(endpoints))
+	factors := lb.factors(endpoints)
+	for i, endpoint := range endpoints {
+		weights[endpoint.ServiceId] = float64(lb.getWeight(endpoint)) * factors[i]
+	}
+	return weights
+}
+
+// factors 返回各端点的降权比例，未启用降权时均为 1；调用时须持有 lb.mu
+func (lb *WeightedRoundRobinLoadBalancer) factors(endpoints []*ServiceEndpoint) []float64 {
+	if lb.decay == nil || len(lb.health) == 0 {
+		factors := make([]float64, len(endpoints))
+		for i := range factors {
+			factors[i] = 1
+		}
+		return factors
+	}
+	return lb.decayFactors(endpoints, lb.now())
+}
@@ -0,0 +1,170 @@
+// Package memory 提供基于 net.Pipe 的进程内传输，在不绑定真实端口的情况下测试协议处理器、适配器和客户端
+//
+// Listener 实现 net.Listener，可设置到各协议处理器和内部 gRPC 服务器配置的 Listener 字段；客户端通过 Listener.DialContext、
+// Listener.HTTPClient、Listener.GrpcDialOption（可追加到 connection.ConnectionConfig.GrpcDialOptions）
+// 或按地址拨号的 DialContext（可设置到内部 JSON-RPC、自定义协议客户端配置的 Dialer 和 WebSocket 客户端的 NetDialContext）连接
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// ErrNoListener 拨号的地址上没有进程内监听器
+var ErrNoListener = errors.New("memory: no listener on address")
+
+var (
+	mu        sync.Mutex
+	listeners = make(map[string]*Listener) // 地址 -> 监听器
+	nextPort  = 0
+)
+
+// Listener 进程内监听器
+//
+// Addr 返回 127.0.0.1 和虚拟端口组成的 *net.TCPAddr，兼容只接受 TCP 监听器的库（如 gf 的 ghttp.Server.SetListener）；
+// 虚拟端口在进程内唯一，不占用真实端口
+type Listener struct {
+	addr  *net.TCPAddr
+	conns chan net.Conn
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Listen 创建进程内监听器
+func Listen() *Listener {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l := &Listener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+	// 虚拟端口回绕后跳过仍在使用的地址
+	for l.addr == nil || listeners[l.addr.String()] != nil {
+		l.addr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: allocatePort()}
+	}
+	listeners[l.addr.String()] = l
+	return l
+}
+
+// Accept 等待并返回下一个连接，监听器关闭后返回 net.ErrClosed
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close 关闭监听器，已建立的连接不受影响
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		mu.Lock()
+		delete(listeners, l.addr.String())
+		mu.Unlock()
+	})
+	return nil
+}
+
+// Addr 返回监听器的虚拟地址
+func (l *Listener) Addr() net.Addr {
+	return l.addr
+}
+
+// Address 返回监听器的虚拟地址（host:port），可用于 URL 和注册到注册中心
+func (l *Listener) Address() string {
+	return l.addr.String()
+}
+
+// Port 返回监听器的虚拟端口
+func (l *Listener) Port() int {
+	return l.addr.Port
+}
+
+// DialContext 建立到监听器的连接，等待服务端 Accept 直到 ctx 结束
+func (l *Listener) DialContext(ctx context.Context) (net.Conn, error) {
+	server, client := net.Pipe()
+	mu.Lock()
+	clientAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: allocatePort()}
+	mu.Unlock()
+
+	select {
+	case l.conns <- &conn{Conn: server, local: l.addr, remote: clientAddr}:
+		return &conn{Conn: client, local: clientAddr, remote: l.addr}, nil
+	case <-l.done:
+		server.Close()
+		client.Close()
+		return nil, &net.OpError{Op: "dial", Net: "memory", Addr: l.addr, Err: net.ErrClosed}
+	case <-ctx.Done():
+		server.Close()
+		client.Close()
+		return nil, &net.OpError{Op: "dial", Net: "memory", Addr: l.addr, Err: ctx.Err()}
+	}
+}
+
+// HTTPClient 返回所有请求都发往该监听器的 HTTP 客户端，URL 中的主机名不影响连接
+func (l *Listener) HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return l.DialContext(ctx)
+			},
+		},
+	}
+}
+
+// GrpcDialOption 返回连接到该监听器的 gRPC 拨号选项，拨号目标不影响连接
+func (l *Listener) GrpcDialOption() grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return l.DialContext(ctx)
+	})
+}
+
+// DialContext 按地址（Listener.Address）连接进程内监听器，签名与 net.Dialer.DialContext 相同，network 被忽略
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	mu.Lock()
+	l := listeners[address]
+	mu.Unlock()
+	if l == nil {
+		return nil, &net.OpError{Op: "dial", Net: "memory", Err: fmt.Errorf("%w %s", ErrNoListener, address)}
+	}
+	return l.DialContext(ctx)
+}
+
+// allocatePort 分配虚拟端口（需要持有锁）
+func allocatePort() int {
+	nextPort++
+	if nextPort > 65535 {
+		nextPort = 1
+	}
+	return nextPort
+}
+
+// conn 进程内连接，以虚拟地址代替 net.Pipe 的 pipe 地址
+type conn struct {
+	net.Conn
+	local  net.Addr
+	remote net.Addr
+}
+
+// LocalAddr 返回本端的虚拟地址
+func (c *conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr 返回对端的虚拟地址
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// 确保 Listener 实现 net.Listener
+var _ net.Listener = (*Listener)(nil)
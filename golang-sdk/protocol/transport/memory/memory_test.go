@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestListenerDial 测试拨号后两端通过管道收发数据，地址为虚拟 TCP 地址
+func TestListenerDial(t *testing.T) {
+	listener := Listen()
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			t.Errorf("Accept failed: %v", err)
+			return
+		}
+		accepted <- conn
+	}()
+
+	client, err := DialContext(context.Background(), "tcp", listener.Address())
+	if err != nil {
+		t.Fatalf("DialContext failed: %v", err)
+	}
+	defer client.Close()
+	server := <-accepted
+	defer server.Close()
+
+	if client.RemoteAddr().String() != listener.Address() {
+		t.Errorf("expected remote address %s, got %s", listener.Address(), client.RemoteAddr())
+	}
+	if server.RemoteAddr().String() != client.LocalAddr().String() {
+		t.Errorf("expected server to see client address %s, got %s", client.LocalAddr(), server.RemoteAddr())
+	}
+
+	go client.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(server, buf); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("expected ping, got %q", buf)
+	}
+}
+
+// TestListenerUniqueAddress 测试每个监听器的虚拟地址不同
+func TestListenerUniqueAddress(t *testing.T) {
+	a, b := Listen(), Listen()
+	defer a.Close()
+	defer b.Close()
+
+	if a.Address() == b.Address() {
+		t.Errorf("expected distinct addresses, both are %s", a.Address())
+	}
+	if _, ok := a.Addr().(*net.TCPAddr); !ok {
+		t.Errorf("expected *net.TCPAddr, got %T", a.Addr())
+	}
+}
+
+// TestListenerClose 测试关闭后 Accept 和拨号返回错误
+func TestListenerClose(t *testing.T) {
+	listener := Listen()
+	address := listener.Address()
+	listener.Close()
+
+	if _, err := listener.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected net.ErrClosed from Accept, got %v", err)
+	}
+	if _, err := listener.DialContext(context.Background()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected net.ErrClosed from DialContext, got %v", err)
+	}
+	if _, err := DialContext(context.Background(), "tcp", address); !errors.Is(err, ErrNoListener) {
+		t.Errorf("expected ErrNoListener, got %v", err)
+	}
+}
+
+// TestDialContextCanceled 测试服务端不 Accept 时拨号随 ctx 结束
+func TestDialContextCanceled(t *testing.T) {
+	listener := Listen()
+	defer listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := listener.DialContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestHTTPClient 测试 HTTP 服务器在进程内监听器上处理请求
+func TestHTTPClient(t *testing.T) {
+	listener := Listen()
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	resp, err := listener.HTTPClient().Get("http://" + listener.Address() + "/hello")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "/hello" {
+		t.Errorf("expected /hello, got %q", body)
+	}
+}
@@ -0,0 +1,60 @@
+// Package transport 导出内部协议的服务端类型和 gRPC 客户端拦截器，供 protocol 之外的包（如 framework）创建内部协议服务
+//
+// Go 只允许 protocol 下的包导入 protocol/internal，这里以类型别名转发，类型与 internal 包中的完全相同
+package transport
+
+import (
+	"github.com/framework/golang-sdk/protocol/internal/custom"
+	"github.com/framework/golang-sdk/protocol/internal/grpc"
+	"github.com/framework/golang-sdk/protocol/internal/jsonrpc"
+	grpcgo "google.golang.org/grpc"
+)
+
+// 内部 gRPC 服务器
+type (
+	GrpcServer       = grpc.GrpcServer
+	GrpcServerConfig = grpc.GrpcServerConfig
+)
+
+// NewGrpcServer 创建内部 gRPC 服务器
+func NewGrpcServer(config *GrpcServerConfig) *GrpcServer {
+	return grpc.NewGrpcServer(config)
+}
+
+// gRPC 客户端拦截器
+type (
+	GrpcClientInterceptorConfig = grpc.ClientInterceptorConfig
+	GrpcTokenSource             = grpc.TokenSource
+)
+
+// GrpcClientDialOptions 返回串联 gRPC 客户端拦截器（指标、熔断、重试、错误还原、安全上下文、访问令牌和追踪）的拨号选项，
+// 可设置到 connection.ConnectionConfig.GrpcDialOptions
+func GrpcClientDialOptions(config *GrpcClientInterceptorConfig) []grpcgo.DialOption {
+	return grpc.ClientDialOptions(config)
+}
+
+// 内部 JSON-RPC 处理器
+type (
+	InternalJsonRpcHandler = jsonrpc.InternalJsonRpcHandler
+	InternalJsonRpcConfig  = jsonrpc.InternalJsonRpcConfig
+	JsonRpcMethodHandler   = jsonrpc.MethodHandler
+)
+
+// NewInternalJsonRpcHandler 创建内部 JSON-RPC 处理器
+func NewInternalJsonRpcHandler(config *InternalJsonRpcConfig) *InternalJsonRpcHandler {
+	return jsonrpc.NewInternalJsonRpcHandler(config)
+}
+
+// 自定义二进制协议处理器
+type (
+	CustomProtocolHandler = custom.CustomProtocolHandler
+	CustomProtocolConfig  = custom.CustomProtocolConfig
+)
+
+// CustomFeatureDeadline 自定义协议的截止时间特性，服务端声明后客户端可在帧中携带截止时间
+const CustomFeatureDeadline = custom.FeatureDeadline
+
+// NewCustomProtocolHandler 创建自定义二进制协议处理器
+func NewCustomProtocolHandler(config *CustomProtocolConfig) *CustomProtocolHandler {
+	return custom.NewCustomProtocolHandler(config)
+}
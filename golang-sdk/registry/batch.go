@@ -0,0 +1,35 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// RegisterBatch 批量注册服务实例，单个实例失败不影响其他实例
+//
+// 有实例注册失败时返回 *errors.MultiError，按实例在 services 中的序号记录服务名、地址和错误
+func RegisterBatch(ctx context.Context, registry ServiceRegistry, services []*ServiceInfo) error {
+	if registry == nil {
+		return fmt.Errorf("registry is nil")
+	}
+
+	multi := frameworkerrors.NewMultiError(len(services))
+	for i, service := range services {
+		err := registry.Register(ctx, service)
+		if err == nil {
+			continue
+		}
+
+		target := frameworkerrors.TargetError{Index: i, Attempt: 1, Err: err}
+		if service != nil {
+			target.Service = service.Name
+			if service.Address != "" {
+				target.Endpoint = fmt.Sprintf("%s:%d", service.Address, service.Port)
+			}
+		}
+		multi.Add(target)
+	}
+	return multi.ErrorOrNil()
+}
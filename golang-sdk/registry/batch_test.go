@@ -0,0 +1,49 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+func TestRegisterBatch(t *testing.T) {
+	registry := NewMemoryRegistry(nil)
+	defer registry.Close()
+	ctx := context.Background()
+
+	services := []*ServiceInfo{
+		{ID: "user-1", Name: "user-service", Address: "10.0.0.1", Port: 8080},
+		{ID: "", Name: "order-service", Address: "10.0.0.2", Port: 8081},
+		nil,
+		{ID: "user-2", Name: "user-service", Address: "10.0.0.3", Port: 8080},
+	}
+
+	err := RegisterBatch(ctx, registry, services)
+	var multi *frameworkerrors.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("RegisterBatch() = %v, want *MultiError", err)
+	}
+
+	errs := multi.Errors()
+	if multi.Total != 4 || len(errs) != 2 {
+		t.Fatalf("Errors() = %v", errs)
+	}
+	if errs[0].Index != 1 || errs[0].Service != "order-service" || errs[0].Endpoint != "10.0.0.2:8081" || errs[0].Attempt != 1 {
+		t.Errorf("errs[0] = %+v", errs[0])
+	}
+	if errs[1].Index != 2 || errs[1].Service != "" {
+		t.Errorf("errs[1] = %+v", errs[1])
+	}
+
+	// 其余实例正常注册
+	instances, err := registry.Discover(ctx, "user-service")
+	if err != nil || len(instances) != 2 {
+		t.Errorf("Discover() = %v, %v", instances, err)
+	}
+
+	if err := RegisterBatch(ctx, registry, services[:1]); err != nil {
+		t.Errorf("RegisterBatch() = %v, want nil", err)
+	}
+}
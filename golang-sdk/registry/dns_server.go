@@ -0,0 +1,400 @@
+package registry
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNS 服务器的默认配置
+const (
+	DefaultDNSAddress = ":8053"
+	DefaultDNSDomain  = "framework.local"
+	DefaultDNSTTL     = 5 * time.Second
+)
+
+const (
+	// dnsUDPSize 不带 EDNS 的 UDP 响应上限，超过时只返回问题并设置 TC 标志，客户端改用 TCP 重新查询
+	dnsUDPSize = 512
+	// dnsLookupTimeout 每次查询注册中心的超时，应小于 DNS 客户端的超时（通常为 5 秒）
+	dnsLookupTimeout = 2 * time.Second
+	// dnsTCPIdleTimeout TCP 连接上两次查询之间的最长空闲时间
+	dnsTCPIdleTimeout = 10 * time.Second
+)
+
+// DNSConfig 内置 DNS 服务器配置
+type DNSConfig struct {
+	// Address 监听地址，同时监听 UDP 和 TCP 的同一端口，为空时为 DefaultDNSAddress
+	Address string
+	// Domain 服务域名的后缀，为空时为 DefaultDNSDomain
+	Domain string
+	// TTL 应答记录的 TTL，为 0 时为 DefaultDNSTTL；实例上下线后客户端最多在 TTL 内仍使用旧记录
+	TTL time.Duration
+}
+
+// DNSServer 以注册中心中的实例应答 DNS 查询，供只能通过 DNS 发现服务的旧工具使用
+//
+// 每次查询都从注册中心查询实例，支持以下名称（domain 为 DNSConfig.Domain）：
+//
+//	A/AAAA  <服务>.<domain>               服务所有实例的 IP 地址
+//	A/AAAA  <实例>.<服务>.<domain>        单个实例的 IP 地址，即 SRV 记录的目标
+//	SRV     <服务>.<domain>               所有实例及实例端口
+//	SRV     _<协议>._tcp.<服务>.<domain>  支持该协议的实例及元数据中该协议的端口（RFC 2782），如 _grpc._tcp.order.framework.local
+//
+// <实例> 为实例 ID 转换成的 DNS 标签（小写，字母、数字和连字符以外的字符替换为连字符）；SRV 记录的权重为实例元数据 weight。
+// 实例地址为主机名时不返回 A/AAAA 记录，SRV 记录的目标为该主机名。服务没有实例时返回 NXDOMAIN，
+// 不在 domain 下的名称返回 REFUSED，注册中心查询失败时返回 SERVFAIL
+type DNSServer struct {
+	registry ServiceRegistry
+	config   DNSConfig
+	// suffix 小写的 .<domain>.，用于匹配查询名称
+	suffix string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	packet   net.PacketConn
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDNSServer 创建 DNS 服务器，config 为 nil 时使用默认配置
+func NewDNSServer(registry ServiceRegistry, config *DNSConfig) *DNSServer {
+	if config == nil {
+		config = &DNSConfig{}
+	}
+	s := &DNSServer{registry: registry, config: *config, conns: make(map[net.Conn]struct{})}
+	if s.config.Address == "" {
+		s.config.Address = DefaultDNSAddress
+	}
+	s.config.Domain = strings.ToLower(strings.Trim(s.config.Domain, "."))
+	if s.config.Domain == "" {
+		s.config.Domain = DefaultDNSDomain
+	}
+	if s.config.TTL <= 0 {
+		s.config.TTL = DefaultDNSTTL
+	}
+	s.suffix = "." + s.config.Domain + "."
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// Start 监听 UDP 和 TCP 并开始应答查询
+func (s *DNSServer) Start() error {
+	packet, err := net.ListenPacket("udp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen DNS on udp %s: %w", s.config.Address, err)
+	}
+	// 端口为 0 时 TCP 使用 UDP 分配到的端口
+	listener, err := net.Listen("tcp", packet.LocalAddr().String())
+	if err != nil {
+		packet.Close()
+		return fmt.Errorf("failed to listen DNS on tcp %s: %w", packet.LocalAddr(), err)
+	}
+
+	s.mu.Lock()
+	s.packet = packet
+	s.listener = listener
+	s.mu.Unlock()
+
+	s.wg.Add(2)
+	go s.serveUDP(packet)
+	go s.serveTCP(listener)
+	return nil
+}
+
+// Addr 返回 UDP 监听地址，未启动时为 nil；TCP 监听同一端口
+func (s *DNSServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.packet == nil {
+		return nil
+	}
+	return s.packet.LocalAddr()
+}
+
+// Stop 停止监听并关闭 TCP 连接，等待正在处理的查询结束或 ctx 结束
+func (s *DNSServer) Stop(ctx context.Context) error {
+	s.cancel()
+	s.mu.Lock()
+	if s.packet != nil {
+		s.packet.Close()
+	}
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serveUDP 读取 UDP 查询，每个查询在单独的协程中应答，不因注册中心查询阻塞后续查询
+func (s *DNSServer) serveUDP(packet net.PacketConn) {
+	defer s.wg.Done()
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := packet.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		query := append([]byte(nil), buf[:n]...)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if response := s.handle(query, dnsUDPSize); response != nil {
+				packet.WriteTo(response, addr)
+			}
+		}()
+	}
+}
+
+// serveTCP 接受 TCP 连接，每个消息带两字节长度前缀（RFC 1035 4.2.2）
+func (s *DNSServer) serveTCP(listener net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+			}()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// serveConn 按顺序应答 TCP 连接上的查询，空闲超时或对端关闭时返回
+func (s *DNSServer) serveConn(conn net.Conn) {
+	var length [2]byte
+	for {
+		conn.SetDeadline(time.Now().Add(dnsTCPIdleTimeout))
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		response := s.handle(query, 65535)
+		if response == nil {
+			return
+		}
+		binary.BigEndian.PutUint16(length[:], uint16(len(response)))
+		if _, err := conn.Write(append(length[:], response...)); err != nil {
+			return
+		}
+	}
+}
+
+// handle 应答一个查询，无法解析消息头时返回 nil 不应答；响应超过 maxSize 时只返回问题并设置 TC 标志
+func (s *DNSServer) handle(query []byte, maxSize int) []byte {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil || header.Response {
+		return nil
+	}
+
+	response := dnsmessage.Message{Header: dnsmessage.Header{
+		ID:               header.ID,
+		Response:         true,
+		OpCode:           header.OpCode,
+		Authoritative:    true,
+		RecursionDesired: header.RecursionDesired,
+	}}
+	question, err := parser.Question()
+	switch {
+	case err != nil:
+		response.RCode = dnsmessage.RCodeFormatError
+	case header.OpCode != 0:
+		response.Questions = []dnsmessage.Question{question}
+		response.RCode = dnsmessage.RCodeNotImplemented
+	default:
+		response.Questions = []dnsmessage.Question{question}
+		response.Answers, response.Additionals, response.RCode = s.resolve(question)
+	}
+	if err == nil {
+		recordDNSQuery(strings.TrimPrefix(question.Type.String(), "Type"), strings.TrimPrefix(response.RCode.String(), "RCode"))
+	}
+
+	packed, err := response.Pack()
+	if err != nil {
+		return nil
+	}
+	if len(packed) > maxSize {
+		response.Truncated = true
+		response.Answers = nil
+		response.Additionals = nil
+		if packed, err = response.Pack(); err != nil {
+			return nil
+		}
+	}
+	return packed
+}
+
+// resolve 按查询名称从注册中心查询实例并生成应答记录
+func (s *DNSServer) resolve(question dnsmessage.Question) (answers, additionals []dnsmessage.Resource, rcode dnsmessage.RCode) {
+	if question.Class != dnsmessage.ClassINET {
+		return nil, nil, dnsmessage.RCodeRefused
+	}
+	name := strings.ToLower(question.Name.String())
+	rest, ok := strings.CutSuffix(name, s.suffix)
+	if !ok {
+		return nil, nil, dnsmessage.RCodeRefused
+	}
+
+	var service, instance, protocol string
+	labels := strings.Split(rest, ".")
+	switch {
+	case len(labels) == 1:
+		service = labels[0]
+	case len(labels) == 2 && !strings.HasPrefix(labels[0], "_"):
+		instance, service = labels[0], labels[1]
+	case len(labels) == 3 && strings.HasPrefix(labels[0], "_") && labels[1] == "_tcp":
+		protocol, service = labels[0][1:], labels[2]
+	default:
+		return nil, nil, dnsmessage.RCodeNameError
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, dnsLookupTimeout)
+	defer cancel()
+	services, err := s.registry.Discover(ctx, service)
+	if err != nil {
+		return nil, nil, dnsmessage.RCodeServerFailure
+	}
+
+	var endpoints []*router.ServiceEndpoint
+	if protocol != "" {
+		endpoints = ProtocolEndpoints(services, adapter.ProtocolType(protocol))
+	} else {
+		for _, info := range services {
+			if instance == "" || dnsLabel(info.ID) == instance {
+				endpoints = append(endpoints, newEndpoint(info, "", info.Port))
+			}
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, nil, dnsmessage.RCodeNameError
+	}
+
+	ttl := uint32(s.config.TTL / time.Second)
+	switch question.Type {
+	case dnsmessage.TypeA, dnsmessage.TypeAAAA:
+		if protocol != "" {
+			break
+		}
+		seen := make(map[string]bool)
+		for _, endpoint := range endpoints {
+			if record, ok := addressRecord(question.Name, question.Type, endpoint.Address, ttl); ok && !seen[endpoint.Address] {
+				seen[endpoint.Address] = true
+				answers = append(answers, record)
+			}
+		}
+	case dnsmessage.TypeSRV:
+		if instance != "" {
+			break
+		}
+		for _, endpoint := range endpoints {
+			isIP := net.ParseIP(endpoint.Address) != nil
+			target := endpoint.Address + "."
+			if isIP {
+				target = dnsLabel(endpoint.ServiceId) + "." + service + s.suffix
+			}
+			targetName, err := dnsmessage.NewName(target)
+			if err != nil {
+				continue
+			}
+			// IP 地址的目标附带 A/AAAA 记录，客户端无需再次查询
+			if isIP {
+				for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+					if record, ok := addressRecord(targetName, qtype, endpoint.Address, ttl); ok {
+						additionals = append(additionals, record)
+					}
+				}
+			}
+			answers = append(answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: question.Name, Type: dnsmessage.TypeSRV, Class: dnsmessage.ClassINET, TTL: ttl},
+				Body:   &dnsmessage.SRVResource{Weight: srvWeight(endpoint), Port: uint16(endpoint.Port), Target: targetName},
+			})
+		}
+	}
+	// 名称存在但没有该类型的记录时返回空应答（NODATA）
+	return answers, additionals, dnsmessage.RCodeSuccess
+}
+
+// addressRecord 返回 address 的 A 或 AAAA 记录，address 不是该类型的 IP 地址时返回 false
+func addressRecord(name dnsmessage.Name, qtype dnsmessage.Type, address string, ttl uint32) (dnsmessage.Resource, bool) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return dnsmessage.Resource{}, false
+	}
+	header := dnsmessage.ResourceHeader{Name: name, Type: qtype, Class: dnsmessage.ClassINET, TTL: ttl}
+	if ip4 := ip.To4(); ip4 != nil {
+		if qtype != dnsmessage.TypeA {
+			return dnsmessage.Resource{}, false
+		}
+		return dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: [4]byte(ip4)}}, true
+	}
+	if qtype != dnsmessage.TypeAAAA {
+		return dnsmessage.Resource{}, false
+	}
+	return dnsmessage.Resource{Header: header, Body: &dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())}}, true
+}
+
+// srvWeight 返回实例元数据 weight 对应的 SRV 权重，未设置或不合法时为 1
+func srvWeight(endpoint *router.ServiceEndpoint) uint16 {
+	weight, err := strconv.Atoi(endpoint.Metadata["weight"])
+	if err != nil || weight <= 0 {
+		return 1
+	}
+	return uint16(min(weight, 65535))
+}
+
+// dnsLabel 将实例 ID 转换为 DNS 标签：小写，字母、数字和连字符以外的字符替换为连字符，最长 63 个字符
+func dnsLabel(id string) string {
+	label := []byte(strings.ToLower(id))
+	for i, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			label[i] = '-'
+		}
+	}
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	return string(label)
+}
@@ -0,0 +1,161 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSServer 启动监听随机端口的 DNS 服务器，返回使用该服务器的解析器
+func startDNSServer(t *testing.T, reg ServiceRegistry, network string) (*DNSServer, *net.Resolver) {
+	t.Helper()
+	server := NewDNSServer(reg, &DNSConfig{Address: "127.0.0.1:0"})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start DNS server: %v", err)
+	}
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server.Addr().String())
+		},
+	}
+	return server, resolver
+}
+
+// registerGreeters 注册两个支持 gRPC 的实例和一个主机名地址的实例
+func registerGreeters(t *testing.T, reg ServiceRegistry) {
+	t.Helper()
+	ctx := context.Background()
+	services := []*ServiceInfo{
+		{ID: "greeter-1", Name: "greeter", Address: "10.0.0.1", Port: 8080, Protocols: []string{"jsonrpc", "grpc"},
+			Metadata: map[string]string{"port.grpc": "9090", "weight": "3"}},
+		{ID: "greeter-2", Name: "greeter", Address: "10.0.0.2", Port: 8080, Protocols: []string{"grpc"}},
+		{ID: "greeter-3", Name: "greeter", Address: "greeter-3.internal", Port: 8080, Protocols: []string{"jsonrpc"}},
+	}
+	for _, service := range services {
+		if err := reg.Register(ctx, service); err != nil {
+			t.Fatalf("Failed to register %s: %v", service.ID, err)
+		}
+	}
+}
+
+// TestDNSServerLookup 测试以 Go 解析器经 UDP 和 TCP 查询 A 和 SRV 记录
+func TestDNSServerLookup(t *testing.T) {
+	reg := NewMemoryRegistry(nil)
+	defer reg.Close()
+	registerGreeters(t, reg)
+
+	for _, network := range []string{"udp", "tcp"} {
+		t.Run(network, func(t *testing.T) {
+			_, resolver := startDNSServer(t, reg, network)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// 主机名地址的实例没有 A 记录
+			addrs, err := resolver.LookupHost(ctx, "greeter.framework.local")
+			if err != nil {
+				t.Fatalf("LookupHost failed: %v", err)
+			}
+			sort.Strings(addrs)
+			if fmt.Sprint(addrs) != "[10.0.0.1 10.0.0.2]" {
+				t.Errorf("LookupHost = %v, want [10.0.0.1 10.0.0.2]", addrs)
+			}
+
+			// 按协议查询时端口为该协议的端口，目标可以解析为实例地址
+			_, srvs, err := resolver.LookupSRV(ctx, "grpc", "tcp", "greeter.framework.local")
+			if err != nil {
+				t.Fatalf("LookupSRV failed: %v", err)
+			}
+			got := make(map[string]string)
+			for _, srv := range srvs {
+				got[srv.Target] = fmt.Sprintf("%d/%d", srv.Port, srv.Weight)
+			}
+			want := map[string]string{
+				"greeter-1.greeter.framework.local.": "9090/3",
+				"greeter-2.greeter.framework.local.": "8080/1",
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("LookupSRV = %v, want %v", got, want)
+			}
+			addrs, err = resolver.LookupHost(ctx, "greeter-2.greeter.framework.local")
+			if err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.2" {
+				t.Errorf("LookupHost(instance) = %v, %v, want [10.0.0.2]", addrs, err)
+			}
+
+			// 不限协议时包括主机名地址的实例
+			_, srvs, err = resolver.LookupSRV(ctx, "", "", "greeter.framework.local")
+			if err != nil || len(srvs) != 3 {
+				t.Fatalf("LookupSRV(all) = %v, %v, want 3 records", srvs, err)
+			}
+		})
+	}
+}
+
+// TestDNSServerResponseCodes 测试不存在的服务、其他域名和截断
+func TestDNSServerResponseCodes(t *testing.T) {
+	reg := NewMemoryRegistry(nil)
+	defer reg.Close()
+	registerGreeters(t, reg)
+	server := NewDNSServer(reg, &DNSConfig{Domain: "Svc.Local."})
+
+	query := func(name string, qtype dnsmessage.Type, maxSize int) dnsmessage.Message {
+		t.Helper()
+		packed, err := (&dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: 7, RecursionDesired: true},
+			Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(name), Type: qtype, Class: dnsmessage.ClassINET}},
+		}).Pack()
+		if err != nil {
+			t.Fatalf("Failed to pack query: %v", err)
+		}
+		var response dnsmessage.Message
+		if err := response.Unpack(server.handle(packed, maxSize)); err != nil {
+			t.Fatalf("Failed to unpack response for %s: %v", name, err)
+		}
+		if response.ID != 7 || !response.Response {
+			t.Errorf("Unexpected response header %+v", response.Header)
+		}
+		return response
+	}
+
+	// 域名不区分大小写
+	if resp := query("GREETER.svc.local.", dnsmessage.TypeA, 512); resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 2 {
+		t.Errorf("A = %v with %d answers, want 2", resp.RCode, len(resp.Answers))
+	}
+	if resp := query("unknown.svc.local.", dnsmessage.TypeA, 512); resp.RCode != dnsmessage.RCodeNameError {
+		t.Errorf("unknown service rcode = %v, want NXDOMAIN", resp.RCode)
+	}
+	if resp := query("_http._tcp.greeter.svc.local.", dnsmessage.TypeSRV, 512); resp.RCode != dnsmessage.RCodeNameError {
+		t.Errorf("unsupported protocol rcode = %v, want NXDOMAIN", resp.RCode)
+	}
+	if resp := query("example.com.", dnsmessage.TypeA, 512); resp.RCode != dnsmessage.RCodeRefused {
+		t.Errorf("other domain rcode = %v, want REFUSED", resp.RCode)
+	}
+	// 名称存在但没有该类型的记录
+	if resp := query("greeter.svc.local.", dnsmessage.TypeMX, 512); resp.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 0 {
+		t.Errorf("MX = %v with %d answers, want NODATA", resp.RCode, len(resp.Answers))
+	}
+	// 超过 UDP 上限时只返回问题并设置 TC 标志
+	if resp := query("greeter.svc.local.", dnsmessage.TypeSRV, 64); !resp.Truncated || len(resp.Answers) != 0 || len(resp.Questions) != 1 {
+		t.Errorf("Expected truncated response, got %+v", resp)
+	}
+}
+
+func TestDNSLabel(t *testing.T) {
+	tests := map[string]string{
+		"greeter-1":                   "greeter-1",
+		"Order_Service.10.0.0.1:8080": "order-service-10-0-0-1-8080",
+	}
+	for id, want := range tests {
+		if got := dnsLabel(id); got != want {
+			t.Errorf("dnsLabel(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 端点健康检查的默认值
+const (
+	defaultEndpointCheckInterval    = 5 * time.Second
+	defaultEndpointFailureThreshold = 3
+	// endpointScoreDecay 健康分数的衰减系数，每次检查的结果占 1-endpointScoreDecay 的权重
+	endpointScoreDecay = 0.7
+)
+
+// EtcdEndpointStatus etcd 端点的健康状态
+type EtcdEndpointStatus struct {
+	Endpoint string `json:"endpoint"`
+	// Healthy 连续失败次数未达到阈值，客户端只向健康的端点发送请求
+	Healthy bool `json:"healthy"`
+	// Score 检查成功率的指数移动平均（0-1），健康端点按分数从高到低、延迟从低到高排序
+	Score float64 `json:"score"`
+	// Latency 检查延迟的指数移动平均
+	Latency             time.Duration `json:"latency"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	LastError           string        `json:"lastError,omitempty"`
+	LastCheck           time.Time     `json:"lastCheck,omitempty"`
+}
+
+// endpointPool 记录各端点的检查结果，决定客户端使用的端点及其顺序
+type endpointPool struct {
+	mu        sync.Mutex
+	threshold int
+	states    []*EtcdEndpointStatus // 按配置顺序
+}
+
+// newEndpointPool 创建端点池，所有端点初始为健康
+func newEndpointPool(endpoints []string, threshold int) *endpointPool {
+	if threshold <= 0 {
+		threshold = defaultEndpointFailureThreshold
+	}
+	pool := &endpointPool{threshold: threshold}
+	for _, endpoint := range endpoints {
+		pool.states = append(pool.states, &EtcdEndpointStatus{Endpoint: endpoint, Healthy: true, Score: 1})
+	}
+	return pool
+}
+
+// record 记录一次检查结果；从未检查成功过的端点首次失败即视为不健康，启动时不会把请求发往不可达的端点
+func (p *endpointPool) record(endpoint string, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, state := range p.states {
+		if state.Endpoint != endpoint {
+			continue
+		}
+		first := state.LastCheck.IsZero()
+		state.LastCheck = time.Now()
+		if err != nil {
+			state.Score *= endpointScoreDecay
+			state.ConsecutiveFailures++
+			if first {
+				state.ConsecutiveFailures = p.threshold
+			}
+			state.LastError = err.Error()
+		} else {
+			state.Score = state.Score*endpointScoreDecay + 1 - endpointScoreDecay
+			state.ConsecutiveFailures = 0
+			state.LastError = ""
+			if state.Latency == 0 {
+				state.Latency = latency
+			} else {
+				state.Latency = time.Duration(float64(state.Latency)*endpointScoreDecay + float64(latency)*(1-endpointScoreDecay))
+			}
+		}
+		state.Healthy = state.ConsecutiveFailures < p.threshold
+		return
+	}
+}
+
+// ordered 返回按健康程度排序的端点：健康的在前，按分数从高到低、延迟从低到高，其余按配置顺序
+func (p *endpointPool) ordered() []*EtcdEndpointStatus {
+	p.mu.Lock()
+	states := make([]*EtcdEndpointStatus, len(p.states))
+	for i, state := range p.states {
+		copied := *state
+		states[i] = &copied
+	}
+	p.mu.Unlock()
+
+	sort.SliceStable(states, func(i, j int) bool {
+		a, b := states[i], states[j]
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Latency < b.Latency
+	})
+	return states
+}
+
+// active 返回客户端应使用的端点，没有健康端点时返回全部端点以便继续尝试
+func (p *endpointPool) active() []string {
+	states := p.ordered()
+	endpoints := make([]string, 0, len(states))
+	for _, state := range states {
+		if state.Healthy {
+			endpoints = append(endpoints, state.Endpoint)
+		}
+	}
+	if len(endpoints) > 0 {
+		return endpoints
+	}
+	for _, state := range states {
+		endpoints = append(endpoints, state.Endpoint)
+	}
+	return endpoints
+}
+
+// checkEndpoints 并发检查所有端点并记录结果，返回可达的端点数和最后一个错误
+func (r *EtcdRegistry) checkEndpoints(ctx context.Context) (int, error) {
+	states := r.endpoints.ordered()
+	var (
+		mu        sync.Mutex
+		reachable int
+		lastErr   error
+		wg        sync.WaitGroup
+	)
+	for _, state := range states {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			start := time.Now()
+			err := r.status(ctx, endpoint)
+			r.endpoints.record(endpoint, time.Since(start), err)
+			setEndpointHealthy(endpoint, err == nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+			} else {
+				reachable++
+			}
+		}(state.Endpoint)
+	}
+	wg.Wait()
+	return reachable, lastErr
+}
+
+// applyEndpoints 健康端点变化时更新客户端使用的端点；客户端在端点间轮询，只在成员变化时更新，避免分数波动导致频繁切换
+func (r *EtcdRegistry) applyEndpoints() {
+	active := r.endpoints.active()
+
+	r.mu.Lock()
+	previous := r.activeEndpoints
+	changed := !sameEndpoints(active, previous)
+	r.activeEndpoints = active
+	r.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, endpoint := range previous {
+		if !containsString(active, endpoint) {
+			recordEndpointFailover(endpoint)
+		}
+	}
+	r.setEndpoints(active...)
+}
+
+// monitorEndpoints 定期检查端点健康，操作失败时立即检查，将请求切换到健康的端点
+func (r *EtcdRegistry) monitorEndpoints() {
+	defer r.wg.Done()
+
+	interval := r.config.EndpointCheckInterval
+	if interval <= 0 {
+		interval = defaultEndpointCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.recheck:
+		}
+		ctx, cancel := context.WithTimeout(r.ctx, r.dialTimeout())
+		r.checkEndpoints(ctx)
+		cancel()
+		r.applyEndpoints()
+	}
+}
+
+// reportError 操作失败时触发一次端点检查，调用方取消的操作除外
+func (r *EtcdRegistry) reportError(err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	select {
+	case r.recheck <- struct{}{}:
+	default:
+	}
+}
+
+// EndpointStatus 返回各 etcd 端点的健康状态，按客户端使用的优先顺序排列
+func (r *EtcdRegistry) EndpointStatus() []EtcdEndpointStatus {
+	states := r.endpoints.ordered()
+	statuses := make([]EtcdEndpointStatus, len(states))
+	for i, state := range states {
+		statuses[i] = *state
+	}
+	return statuses
+}
+
+// dialTimeout 返回单次检查的超时
+func (r *EtcdRegistry) dialTimeout() time.Duration {
+	if r.config.DialTimeout > 0 {
+		return r.config.DialTimeout
+	}
+	return 5 * time.Second
+}
+
+// sameEndpoints 判断两组端点是否相同，不考虑顺序
+func sameEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, endpoint := range a {
+		if !containsString(b, endpoint) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString 判断切片中是否包含 s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpointPool(t *testing.T) {
+	pool := newEndpointPool([]string{"a:2379", "b:2379", "c:2379"}, 2)
+	down := errors.New("connection refused")
+
+	// 从未检查成功的端点首次失败即不健康
+	pool.record("a:2379", 0, down)
+	pool.record("b:2379", 30*time.Millisecond, nil)
+	pool.record("c:2379", 10*time.Millisecond, nil)
+	if got := pool.active(); !equalOrder(got, "c:2379", "b:2379") {
+		t.Fatalf("active = %v, want [c:2379 b:2379]", got)
+	}
+
+	// 达到阈值前仍然使用，分数降低后排在后面
+	pool.record("c:2379", 0, down)
+	if got := pool.active(); !equalOrder(got, "b:2379", "c:2379") {
+		t.Fatalf("active = %v, want [b:2379 c:2379]", got)
+	}
+	pool.record("c:2379", 0, down)
+	if got := pool.active(); !equalOrder(got, "b:2379") {
+		t.Fatalf("active = %v, want [b:2379]", got)
+	}
+
+	// 恢复后重新使用
+	pool.record("a:2379", 5*time.Millisecond, nil)
+	if got := pool.active(); !equalOrder(got, "b:2379", "a:2379") {
+		t.Fatalf("active = %v, want [b:2379 a:2379]", got)
+	}
+	states := pool.ordered()
+	if last := states[2]; last.Endpoint != "c:2379" || last.Healthy || last.ConsecutiveFailures != 2 || last.LastError != down.Error() {
+		t.Errorf("Unexpected state: %+v", last)
+	}
+
+	// 全部不健康时继续尝试全部端点
+	pool.record("a:2379", 0, down)
+	pool.record("a:2379", 0, down)
+	pool.record("b:2379", 0, down)
+	pool.record("b:2379", 0, down)
+	if got := pool.active(); len(got) != 3 {
+		t.Errorf("Expected all endpoints when none is healthy, got %v", got)
+	}
+}
+
+// TestEtcdEndpointFailover 测试端点不可达时切走请求，操作失败立即触发检查，恢复后重新使用
+func TestEtcdEndpointFailover(t *testing.T) {
+	var mu sync.Mutex
+	unreachable := map[string]bool{}
+	applied := make(chan []string, 8)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	config := DefaultEtcdRegistryConfig()
+	config.Endpoints = []string{"a:2379", "b:2379"}
+	config.EndpointCheckInterval = time.Hour
+	config.EndpointFailureThreshold = 1
+	r := &EtcdRegistry{
+		config:    config,
+		endpoints: newEndpointPool(config.Endpoints, config.EndpointFailureThreshold),
+		status: func(ctx context.Context, endpoint string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if unreachable[endpoint] {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+		setEndpoints: func(endpoints ...string) { applied <- endpoints },
+		recheck:      make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	defer func() {
+		cancel()
+		r.wg.Wait()
+	}()
+
+	setReachable := func(endpoint string, reachable bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		unreachable[endpoint] = !reachable
+	}
+	expectApplied := func(want ...string) {
+		t.Helper()
+		select {
+		case got := <-applied:
+			if !sameEndpoints(got, want) {
+				t.Fatalf("endpoints = %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected endpoints %v", want)
+		}
+	}
+
+	if reachable, _ := r.checkEndpoints(ctx); reachable != 2 {
+		t.Fatalf("Expected 2 reachable endpoints, got %d", reachable)
+	}
+	r.applyEndpoints()
+	expectApplied("a:2379", "b:2379")
+
+	r.wg.Add(1)
+	go r.monitorEndpoints()
+
+	setReachable("a:2379", false)
+	r.reportError(errors.New("context deadline exceeded"))
+	expectApplied("b:2379")
+	if status := r.EndpointStatus(); status[0].Endpoint != "b:2379" || status[1].Healthy {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if err := r.Ping(ctx); err != nil {
+		t.Errorf("Ping failed with a healthy endpoint: %v", err)
+	}
+
+	setReachable("a:2379", true)
+	r.reportError(errors.New("context deadline exceeded"))
+	expectApplied("a:2379", "b:2379")
+
+	// 调用方取消的操作不触发检查
+	r.reportError(context.Canceled)
+	if len(r.recheck) != 0 {
+		t.Error("Expected no recheck for canceled operation")
+	}
+}
+
+func equalOrder(got []string, want ...string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
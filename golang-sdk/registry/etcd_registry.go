@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +19,18 @@ type EtcdRegistryConfig struct {
 	TTL              int64         // 租约 TTL（秒）
 	HeartbeatInterval time.Duration // 心跳间隔
 	DialTimeout      time.Duration // 连接超时
+	// Username 和 Password 为 etcd 认证的用户名和密码，为空时不认证；配合 etcd 角色限制可访问的 key 前缀和读写权限
+	Username string
+	Password string
+	// EndpointCheckInterval 检查各端点健康的间隔，为 0 时使用 5s；操作失败时会立即检查
+	EndpointCheckInterval time.Duration
+	// EndpointFailureThreshold 端点连续检查失败多少次后不再向其发送请求，为 0 时使用 3；恢复后自动重新使用
+	EndpointFailureThreshold int
+	// WatchWorkers 并发执行监听回调的 worker 数，为 0 时使用 DefaultWatchWorkers
+	WatchWorkers int
+	// SlowWatchThreshold 监听回调执行超过该时间时记录警告并计入 framework_registry_watch_callback_slow_total，
+	// 为 0 时使用 DefaultSlowWatchThreshold
+	SlowWatchThreshold time.Duration
 }
 
 // DefaultEtcdRegistryConfig 默认配置
@@ -28,17 +41,52 @@ func DefaultEtcdRegistryConfig() *EtcdRegistryConfig {
 		TTL:              10,
 		HeartbeatInterval: 3 * time.Second,
 		DialTimeout:      5 * time.Second,
+		EndpointCheckInterval:    defaultEndpointCheckInterval,
+		EndpointFailureThreshold: defaultEndpointFailureThreshold,
+		WatchWorkers:             DefaultWatchWorkers,
+		SlowWatchThreshold:       DefaultSlowWatchThreshold,
 	}
 }
 
+// 监听中断后重新建立监听的退避时间
+const (
+	watchRetryInitial = 100 * time.Millisecond
+	watchRetryMax     = 5 * time.Second
+)
+
+// 重新建立监听的原因，作为 framework_registry_watch_restarts_total 的 reason 标签
+const (
+	watchRestartClosed    = "closed"    // 监听通道被关闭，如 leader 切换、连接中断
+	watchRestartCompacted = "compacted" // 上次处理的 revision 已被压缩，需要重新查询服务列表
+	watchRestartError     = "error"     // 监听返回错误或查询服务列表失败
+)
+
 // EtcdRegistry 基于 etcd 的服务注册中心
+//
+// 配置多个端点时定期检查各端点的健康，客户端只向健康的端点发送请求；端点恢复后自动重新使用，
+// 所有端点都不健康时继续尝试全部端点
 type EtcdRegistry struct {
 	client    *clientv3.Client
+	// kv 和 watcher 为 client，查询和监听经由接口以便测试时替换
+	kv        clientv3.KV
+	watcher   clientv3.Watcher
 	config    *EtcdRegistryConfig
 	leaseID   clientv3.LeaseID
 	mu        sync.RWMutex
 	services  map[string]*ServiceInfo // serviceID -> ServiceInfo
-	watchers  map[string][]func([]*ServiceInfo) // serviceName -> callbacks
+	watchers  map[string][]*serviceWatcher // serviceName -> callbacks
+	// dispatcher 执行监听回调，回调不在监听 goroutine 上执行，阻塞或 panic 的回调不影响监听的恢复
+	dispatcher *watchDispatcher
+	// deregisteredAt 本实例注销的 key -> 注销时间，删除事件没有值，据此计算传播延迟
+	deregisteredAt map[string]time.Time
+	// endpoints 各端点的健康状态，activeEndpoints 为客户端当前使用的端点
+	endpoints       *endpointPool
+	activeEndpoints []string
+	// status 和 setEndpoints 为 client 的方法，测试时替换
+	status       func(ctx context.Context, endpoint string) error
+	setEndpoints func(endpoints ...string)
+	// recheck 操作失败时触发立即检查端点
+	recheck   chan struct{}
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
@@ -53,57 +101,70 @@ func NewEtcdRegistry(config *EtcdRegistryConfig) (*EtcdRegistry, error) {
 	client, err := clientv3.New(clientv3.Config{
 		Endpoints:   config.Endpoints,
 		DialTimeout: config.DialTimeout,
+		Username:    config.Username,
+		Password:    config.Password,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create etcd client: %w", err)
 	}
 
-	// 立即做连通性检查，避免测试中懒连接导致的超时挂起
-	pingCtx, pingCancel := context.WithTimeout(context.Background(), config.DialTimeout)
-	defer pingCancel()
-	_, err = client.Status(pingCtx, config.Endpoints[0])
-	if err != nil {
-		_ = client.Close()
-		return nil, fmt.Errorf("etcd not reachable at %s: %w", config.Endpoints[0], err)
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	registry := &EtcdRegistry{
 		client:   client,
+		kv:       client,
+		watcher:  client,
 		config:   config,
 		services: make(map[string]*ServiceInfo),
-		watchers: make(map[string][]func([]*ServiceInfo)),
+		watchers: make(map[string][]*serviceWatcher),
+		dispatcher: newWatchDispatcher("etcd", config.WatchWorkers, config.SlowWatchThreshold),
+		deregisteredAt: make(map[string]time.Time),
+		endpoints: newEndpointPool(config.Endpoints, config.EndpointFailureThreshold),
+		status: func(ctx context.Context, endpoint string) error {
+			_, err := client.Status(ctx, endpoint)
+			return err
+		},
+		setEndpoints: client.SetEndpoints,
+		recheck:  make(chan struct{}, 1),
 		ctx:      ctx,
 		cancel:   cancel,
 	}
 
+	// 立即做连通性检查，避免测试中懒连接导致的超时挂起；任一端点可达即可启动，不可达的端点不再使用直到恢复
+	pingCtx, pingCancel := context.WithTimeout(context.Background(), registry.dialTimeout())
+	defer pingCancel()
+	if reachable, err := registry.checkEndpoints(pingCtx); reachable == 0 {
+		cancel()
+		registry.dispatcher.close()
+		_ = client.Close()
+		return nil, fmt.Errorf("etcd not reachable at %s: %w", strings.Join(config.Endpoints, ","), err)
+	}
+	registry.applyEndpoints()
+
+	registry.wg.Add(1)
+	go registry.monitorEndpoints()
+
 	return registry, nil
 }
 
-// Register 注册服务
+// Register 注册服务，服务实例信息经 ValidateServiceInfo 校验后写入 NormalizeServiceInfo 规范化的副本
 func (r *EtcdRegistry) Register(ctx context.Context, service *ServiceInfo) error {
-	if service == nil {
-		return fmt.Errorf("service is nil")
-	}
-
-	if service.ID == "" {
-		return fmt.Errorf("service ID is empty")
-	}
-
-	if service.Name == "" {
-		return fmt.Errorf("service name is empty")
+	service, err := prepareService(service)
+	if err != nil {
+		return err
 	}
 
 	// 创建租约
 	lease, err := r.client.Grant(ctx, r.config.TTL)
 	if err != nil {
+		r.reportError(err)
 		return fmt.Errorf("failed to create lease: %w", err)
 	}
 
 	r.leaseID = lease.ID
 
-	// 序列化服务信息
+	// 序列化服务信息，注册时间用于监听方计算传播延迟
+	service.RegisteredAt = time.Now()
 	data, err := json.Marshal(service)
 	if err != nil {
 		return fmt.Errorf("failed to marshal service info: %w", err)
@@ -113,6 +174,7 @@ func (r *EtcdRegistry) Register(ctx context.Context, service *ServiceInfo) error
 	key := r.getServiceKey(service.Name, service.ID)
 	_, err = r.client.Put(ctx, key, string(data), clientv3.WithLease(lease.ID))
 	if err != nil {
+		r.reportError(err)
 		return fmt.Errorf("failed to register service: %w", err)
 	}
 
@@ -138,15 +200,19 @@ func (r *EtcdRegistry) Deregister(ctx context.Context, serviceID string) error {
 	service, exists := r.services[serviceID]
 	if !exists {
 		r.mu.Unlock()
-		return fmt.Errorf("service not found: %s", serviceID)
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
 	}
 	delete(r.services, serviceID)
+	key := r.getServiceKey(service.Name, service.ID)
+	if len(r.watchers[service.Name]) > 0 {
+		r.deregisteredAt[key] = time.Now()
+	}
 	r.mu.Unlock()
 
 	// 从 etcd 删除服务
-	key := r.getServiceKey(service.Name, service.ID)
 	_, err := r.client.Delete(ctx, key)
 	if err != nil {
+		r.reportError(err)
 		return fmt.Errorf("failed to deregister service: %w", err)
 	}
 
@@ -154,6 +220,7 @@ func (r *EtcdRegistry) Deregister(ctx context.Context, serviceID string) error {
 	if r.leaseID != 0 {
 		_, err = r.client.Revoke(ctx, r.leaseID)
 		if err != nil {
+			r.reportError(err)
 			return fmt.Errorf("failed to revoke lease: %w", err)
 		}
 	}
@@ -167,11 +234,18 @@ func (r *EtcdRegistry) Discover(ctx context.Context, serviceName string) ([]*Ser
 		return nil, fmt.Errorf("service name is empty")
 	}
 
+	services, _, err := r.discover(ctx, serviceName)
+	return services, err
+}
+
+// discover 查询服务，同时返回查询时 etcd 的 revision，监听从该 revision 之后开始不会遗漏变化
+func (r *EtcdRegistry) discover(ctx context.Context, serviceName string) ([]*ServiceInfo, int64, error) {
 	// 查询服务前缀
 	prefix := r.getServicePrefix(serviceName)
-	resp, err := r.client.Get(ctx, prefix, clientv3.WithPrefix())
+	resp, err := r.kv.Get(ctx, prefix, clientv3.WithPrefix())
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover services: %w", err)
+		r.reportError(err)
+		return nil, 0, fmt.Errorf("failed to discover services: %w", err)
 	}
 
 	// 解析服务信息
@@ -184,7 +258,56 @@ func (r *EtcdRegistry) Discover(ctx context.Context, serviceName string) ([]*Ser
 		services = append(services, &service)
 	}
 
-	return services, nil
+	return services, resp.Header.Revision, nil
+}
+
+// DiscoverByPrefix 查询服务名以 prefix 开头的所有服务实例，按服务名分组
+func (r *EtcdRegistry) DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error) {
+	groups, _, err := r.discoverPrefix(ctx, prefix)
+	return groups, err
+}
+
+// discoverPrefix 以一次范围查询获取服务名以 prefix 开头的服务实例，同时返回查询时 etcd 的 revision
+func (r *EtcdRegistry) discoverPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, int64, error) {
+	resp, err := r.kv.Get(ctx, r.getNamePrefix(prefix), clientv3.WithPrefix())
+	if err != nil {
+		r.reportError(err)
+		return nil, 0, fmt.Errorf("failed to discover services: %w", err)
+	}
+
+	groups := make(map[string][]*ServiceInfo)
+	for _, kv := range resp.Kvs {
+		var service ServiceInfo
+		if err := json.Unmarshal(kv.Value, &service); err != nil || !strings.HasPrefix(service.Name, prefix) {
+			continue // 跳过无效的服务信息
+		}
+		groups[service.Name] = append(groups[service.Name], &service)
+	}
+	return groups, resp.Header.Revision, nil
+}
+
+// WatchPattern 监听服务名匹配 pattern 的所有服务，每次调用建立一个监听模式字面前缀的 etcd 监听，中断后的恢复方式与 Watch 相同
+func (r *EtcdRegistry) WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error {
+	if err := validatePattern(pattern, callback); err != nil {
+		return err
+	}
+
+	prefix := patternPrefix(pattern)
+	sub := &watchSubscriber{label: pattern}
+	r.wg.Add(1)
+	go r.watchLoop(watchTarget{
+		label:  pattern,
+		prefix: r.getNamePrefix(prefix),
+		sync: func(ctx context.Context) (func(), int64, error) {
+			groups, revision, err := r.discoverPrefix(ctx, prefix)
+			notify := func() {
+				matched := filterPattern(pattern, groups)
+				r.dispatcher.submit(sub, func() { callback(matched) })
+			}
+			return notify, revision, err
+		},
+	})
+	return nil
 }
 
 // HealthCheck 健康检查
@@ -194,13 +317,14 @@ func (r *EtcdRegistry) HealthCheck(ctx context.Context, serviceID string) (Healt
 	r.mu.RUnlock()
 
 	if !exists {
-		return HealthStatusUnknown, fmt.Errorf("service not found: %s", serviceID)
+		return HealthStatusUnknown, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
 	}
 
 	// 检查服务在 etcd 中是否存在
 	key := r.getServiceKey(service.Name, service.ID)
 	resp, err := r.client.Get(ctx, key)
 	if err != nil {
+		r.reportError(err)
 		return HealthStatusUnknown, fmt.Errorf("failed to check service health: %w", err)
 	}
 
@@ -212,6 +336,10 @@ func (r *EtcdRegistry) HealthCheck(ctx context.Context, serviceID string) (Healt
 }
 
 // Watch 监听服务变化
+//
+// 同一服务的回调共用一个 etcd 监听。监听通道被 etcd 关闭（leader 切换、连接中断等）后从上次处理的 revision 重新建立，
+// 不会遗漏中间的变化；该 revision 已被压缩时重新查询服务列表并通知回调，再从查询的 revision 继续监听。
+// 回调由 dispatcher 的 worker 执行，与 MemoryRegistry 相同：同一回调按顺序执行，执行期间的多次变化合并为最新的服务列表
 func (r *EtcdRegistry) Watch(ctx context.Context, serviceName string, callback func([]*ServiceInfo)) error {
 	if serviceName == "" {
 		return fmt.Errorf("service name is empty")
@@ -223,20 +351,44 @@ func (r *EtcdRegistry) Watch(ctx context.Context, serviceName string, callback f
 
 	// 注册回调
 	r.mu.Lock()
-	r.watchers[serviceName] = append(r.watchers[serviceName], callback)
+	first := len(r.watchers[serviceName]) == 0
+	r.watchers[serviceName] = append(r.watchers[serviceName], &serviceWatcher{
+		watchSubscriber: &watchSubscriber{label: serviceName},
+		callback:        callback,
+	})
 	r.mu.Unlock()
 
-	// 启动监听
-	r.wg.Add(1)
-	go r.watchService(serviceName)
+	// 第一个回调时启动监听
+	if first {
+		r.wg.Add(1)
+		go r.watchService(serviceName)
+	}
 
 	return nil
 }
 
-// Close 关闭注册中心连接
+// Ping 检查 etcd 连通性，按健康程度依次尝试各端点，任一端点可达即视为正常
+func (r *EtcdRegistry) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, state := range r.endpoints.ordered() {
+		start := time.Now()
+		err := r.status(ctx, state.Endpoint)
+		r.endpoints.record(state.Endpoint, time.Since(start), err)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	r.reportError(lastErr)
+	return fmt.Errorf("etcd not reachable: %w", lastErr)
+}
+
+// Close 关闭注册中心连接，尚未执行的监听通知被丢弃
 func (r *EtcdRegistry) Close() error {
 	r.cancel()
 	r.wg.Wait()
+	r.dispatcher.close()
 
 	if r.client != nil {
 		return r.client.Close()
@@ -264,6 +416,7 @@ func (r *EtcdRegistry) keepAlive(serviceID string) {
 			// 续约
 			_, err := r.client.KeepAliveOnce(r.ctx, r.leaseID)
 			if err != nil {
+				r.reportError(err)
 				// 续约失败，尝试重新注册
 				r.mu.RLock()
 				service, exists := r.services[serviceID]
@@ -277,35 +430,155 @@ func (r *EtcdRegistry) keepAlive(serviceID string) {
 	}
 }
 
-// watchService 监听服务变化
+// watchTarget 一个 etcd 监听：监听 prefix 下的 key，变化时以 sync 查询的最新状态通知回调
+type watchTarget struct {
+	// label 为 framework_registry_watch_restarts_total 的 service 标签
+	label  string
+	prefix string
+	// sync 查询最新状态，返回通知回调的函数和查询时 etcd 的 revision
+	sync func(ctx context.Context) (func(), int64, error)
+}
+
+// serviceWatch 返回监听单个服务的 watchTarget
+func (r *EtcdRegistry) serviceWatch(serviceName string) watchTarget {
+	return watchTarget{
+		label:  serviceName,
+		prefix: r.getServicePrefix(serviceName),
+		sync: func(ctx context.Context) (func(), int64, error) {
+			services, revision, err := r.discover(ctx, serviceName)
+			return func() { r.notify(serviceName, services) }, revision, err
+		},
+	}
+}
+
+// watchService 监听服务变化，监听中断后按退避时间重新建立，直到注册中心关闭
 func (r *EtcdRegistry) watchService(serviceName string) {
-	defer r.wg.Done()
+	r.watchLoop(r.serviceWatch(serviceName))
+}
 
-	prefix := r.getServicePrefix(serviceName)
-	watchChan := r.client.Watch(r.ctx, prefix, clientv3.WithPrefix())
+// watchLoop 监听 target 的变化，监听中断后按退避时间重新建立，直到注册中心关闭
+func (r *EtcdRegistry) watchLoop(target watchTarget) {
+	defer r.wg.Done()
 
+	// revision 为已反映到回调的最新 revision，为 0 时先查询；首次查询只用于确定起点，不通知回调
+	var revision int64
+	resync := false
+	delay := watchRetryInitial
 	for {
-		select {
-		case <-r.ctx.Done():
-			return
-		case watchResp := <-watchChan:
-			if watchResp.Err() != nil {
-				continue
-			}
-
-			// 查询最新的服务列表
-			services, err := r.Discover(r.ctx, serviceName)
+		if revision == 0 {
+			notify, rev, err := target.sync(r.ctx)
 			if err != nil {
+				if !r.waitRetry(&delay) {
+					return
+				}
 				continue
 			}
+			revision = rev
+			if resync {
+				notify()
+			}
+		}
 
-			// 通知所有回调
-			r.mu.RLock()
-			callbacks := r.watchers[serviceName]
-			r.mu.RUnlock()
+		reason := r.consumeWatch(target, &revision, &delay)
+		if r.ctx.Err() != nil {
+			return
+		}
+		recordWatchRestart(target.label, reason)
+		if reason != watchRestartClosed {
+			revision = 0
+		}
+		resync = true
+		if !r.waitRetry(&delay) {
+			return
+		}
+	}
+}
+
+// consumeWatch 从 revision 之后开始监听并通知回调，直到监听通道关闭，返回关闭的原因
+//
+// revision 随处理的事件和进度通知前进；要求 leader 存在，与集群失联的成员上的监听会被关闭而不是一直等待
+func (r *EtcdRegistry) consumeWatch(target watchTarget, revision *int64, delay *time.Duration) string {
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(r.ctx))
+	defer cancel()
+
+	watchChan := r.watcher.Watch(ctx, target.prefix, clientv3.WithPrefix(), clientv3.WithRev(*revision+1), clientv3.WithProgressNotify())
+	for watchResp := range watchChan {
+		if watchResp.CompactRevision != 0 {
+			return watchRestartCompacted
+		}
+		if watchResp.Err() != nil {
+			return watchRestartError
+		}
+		*delay = watchRetryInitial
+
+		// 进度通知表示该 revision 之前的事件都已送达
+		if watchResp.IsProgressNotify() {
+			*revision = max(*revision, watchResp.Header.Revision)
+			continue
+		}
+		if len(watchResp.Events) == 0 {
+			continue
+		}
+		*revision = watchResp.Events[len(watchResp.Events)-1].Kv.ModRevision
 
-			for _, callback := range callbacks {
-				callback(services)
+		// 查询最新的状态，失败时重新查询后通知，回调不会停留在旧的服务列表
+		notify, _, err := target.sync(r.ctx)
+		if err != nil {
+			return watchRestartError
+		}
+		r.observePropagation(watchResp.Events)
+		notify()
+	}
+	return watchRestartClosed
+}
+
+// notify 以服务列表通知服务的所有回调，回调由 dispatcher 执行，不阻塞监听
+func (r *EtcdRegistry) notify(serviceName string, services []*ServiceInfo) {
+	r.mu.RLock()
+	watchers := r.watchers[serviceName]
+	r.mu.RUnlock()
+
+	for _, watcher := range watchers {
+		callback := watcher.callback
+		r.dispatcher.submit(watcher.watchSubscriber, func() { callback(services) })
+	}
+}
+
+// waitRetry 等待 delay 后将其加倍（最多 watchRetryMax），注册中心关闭时返回 false
+func (r *EtcdRegistry) waitRetry(delay *time.Duration) bool {
+	timer := time.NewTimer(*delay)
+	defer timer.Stop()
+	*delay = min(*delay*2, watchRetryMax)
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// observePropagation 记录从注册、注销到通知监听者的传播延迟
+//
+// 注册事件以值中的 RegisteredAt 为起点（跨进程时依赖时钟同步）；删除事件没有值，只记录本实例注销的服务，
+// 租约过期导致的删除无法得知发生时间，不记录
+func (r *EtcdRegistry) observePropagation(events []*clientv3.Event) {
+	now := time.Now()
+	for _, event := range events {
+		switch event.Type {
+		case clientv3.EventTypePut:
+			var service ServiceInfo
+			if err := json.Unmarshal(event.Kv.Value, &service); err != nil || service.RegisteredAt.IsZero() {
+				continue
+			}
+			observeWatchPropagation("etcd", watchOpRegister, now.Sub(service.RegisteredAt))
+		case clientv3.EventTypeDelete:
+			key := string(event.Kv.Key)
+			r.mu.Lock()
+			deregisteredAt, ok := r.deregisteredAt[key]
+			delete(r.deregisteredAt, key)
+			r.mu.Unlock()
+			if ok {
+				observeWatchPropagation("etcd", watchOpDeregister, now.Sub(deregisteredAt))
 			}
 		}
 	}
@@ -316,6 +589,11 @@ func (r *EtcdRegistry) getServiceKey(serviceName, serviceID string) string {
 	return path.Join(r.config.Namespace, serviceName, serviceID)
 }
 
+// getNamePrefix 获取服务名以 prefix 开头的服务的 etcd 前缀
+func (r *EtcdRegistry) getNamePrefix(prefix string) string {
+	return strings.TrimSuffix(r.config.Namespace, "/") + "/" + prefix
+}
+
 // getServicePrefix 获取服务的 etcd 前缀
 func (r *EtcdRegistry) getServicePrefix(serviceName string) string {
 	return path.Join(r.config.Namespace, serviceName) + "/"
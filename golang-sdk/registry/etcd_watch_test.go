@@ -0,0 +1,229 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd 以内存中的服务列表实现查询，监听通道由测试逐个提供
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Watcher
+
+	mu       sync.Mutex
+	services []*ServiceInfo
+	revision int64
+	// watches 每次 Watch 的起始 revision
+	watches chan int64
+	// channels 依次作为 Watch 返回的通道
+	channels chan chan clientv3.WatchResponse
+}
+
+func (f *fakeEtcd) set(revision int64, services ...*ServiceInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revision = revision
+	f.services = services
+}
+
+func (f *fakeEtcd) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{Header: &etcdserverpb.ResponseHeader{Revision: f.revision}}
+	for _, service := range f.services {
+		value, _ := json.Marshal(service)
+		resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(key + service.ID), Value: value})
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	out := make(chan clientv3.WatchResponse)
+	f.watches <- clientv3.OpGet(key, opts...).Rev()
+	in := <-f.channels
+	// 与 etcd 客户端一样在 ctx 取消时关闭通道
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resp, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- resp:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// TestEtcdWatchResumes 测试监听通道关闭后从上次处理的 revision 之后重新监听，revision 被压缩时重新查询并通知回调
+func TestEtcdWatchResumes(t *testing.T) {
+	fake := &fakeEtcd{watches: make(chan int64, 1), channels: make(chan chan clientv3.WatchResponse, 1)}
+	fake.set(10, &ServiceInfo{ID: "greeter-1", Name: "greeter"})
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &EtcdRegistry{
+		kv:         fake,
+		watcher:    fake,
+		config:     DefaultEtcdRegistryConfig(),
+		watchers:   make(map[string][]*serviceWatcher),
+		dispatcher: newWatchDispatcher("etcd", 0, 0),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	defer r.Close()
+
+	notified := make(chan int, 4)
+	callback := func(services []*ServiceInfo) { notified <- len(services) }
+	if err := r.Watch(ctx, "greeter", callback); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	// 同一服务的回调共用一个监听
+	if err := r.Watch(ctx, "greeter", callback); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	expectWatch := func(want int64) chan clientv3.WatchResponse {
+		t.Helper()
+		select {
+		case rev := <-fake.watches:
+			if rev != want {
+				t.Fatalf("watch started at revision %d, want %d", rev, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected watch at revision %d", want)
+		}
+		ch := make(chan clientv3.WatchResponse, 1)
+		fake.channels <- ch
+		return ch
+	}
+	expectNotify := func(want int) {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			select {
+			case got := <-notified:
+				if got != want {
+					t.Fatalf("callback got %d services, want %d", got, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Expected callback")
+			}
+		}
+	}
+
+	// 首次查询的 revision 之后开始监听
+	ch := expectWatch(11)
+
+	// 处理事件后通知回调，进度通知推进 revision
+	fake.set(12, &ServiceInfo{ID: "greeter-1", Name: "greeter"}, &ServiceInfo{ID: "greeter-2", Name: "greeter"})
+	ch <- clientv3.WatchResponse{
+		Header: etcdserverpb.ResponseHeader{Revision: 12},
+		Events: []*clientv3.Event{{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/services/greeter/greeter-2"), ModRevision: 12}}},
+	}
+	expectNotify(2)
+	ch <- clientv3.WatchResponse{Header: etcdserverpb.ResponseHeader{Revision: 15}}
+
+	// 通道关闭后从 revision 之后重新监听，不通知回调
+	close(ch)
+	ch = expectWatch(16)
+
+	// revision 被压缩时重新查询，通知回调后从查询的 revision 之后监听
+	fake.set(20, &ServiceInfo{ID: "greeter-3", Name: "greeter"})
+	ch <- clientv3.WatchResponse{Header: etcdserverpb.ResponseHeader{Revision: 20}, CompactRevision: 18}
+	close(ch)
+	expectNotify(1)
+	expectWatch(21)
+
+	select {
+	case got := <-notified:
+		t.Errorf("Unexpected callback with %d services", got)
+	default:
+	}
+}
+
+// TestEtcdWatchCallbackIsolation 测试 panic 和阻塞的监听回调不影响其他回调和监听的恢复
+func TestEtcdWatchCallbackIsolation(t *testing.T) {
+	fake := &fakeEtcd{watches: make(chan int64, 1), channels: make(chan chan clientv3.WatchResponse, 1)}
+	fake.set(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &EtcdRegistry{
+		kv:         fake,
+		watcher:    fake,
+		config:     DefaultEtcdRegistryConfig(),
+		watchers:   make(map[string][]*serviceWatcher),
+		dispatcher: newWatchDispatcher("etcd", 2, 20*time.Millisecond),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	defer r.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	r.Watch(ctx, "etcd-isolation", func(services []*ServiceInfo) {
+		<-release
+	})
+	r.Watch(ctx, "etcd-isolation", func(services []*ServiceInfo) {
+		panic("bad watcher")
+	})
+	updates := make(chan int, 10)
+	r.Watch(ctx, "etcd-isolation", func(services []*ServiceInfo) {
+		updates <- len(services)
+	})
+
+	var ch chan clientv3.WatchResponse
+	expectWatch := func(want int64) {
+		t.Helper()
+		select {
+		case rev := <-fake.watches:
+			if rev != want {
+				t.Fatalf("watch started at revision %d, want %d", rev, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected watch at revision %d", want)
+		}
+		ch = make(chan clientv3.WatchResponse, 1)
+		fake.channels <- ch
+	}
+	expectWatch(11)
+
+	services := []*ServiceInfo{}
+	for i := 1; i <= 3; i++ {
+		services = append(services, &ServiceInfo{ID: fmt.Sprintf("etcd-isolation-%d", i), Name: "etcd-isolation"})
+		revision := int64(11 + i)
+		fake.set(revision, services...)
+		ch <- clientv3.WatchResponse{
+			Header: etcdserverpb.ResponseHeader{Revision: revision},
+			Events: []*clientv3.Event{{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/services/etcd-isolation/" + services[i-1].ID), ModRevision: revision}}},
+		}
+		select {
+		case count := <-updates:
+			if count != i {
+				t.Errorf("Expected %d instances, got %d", i, count)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for notification %d", i)
+		}
+	}
+
+	// 阻塞的回调不妨碍监听关闭后的恢复
+	close(ch)
+	expectWatch(15)
+
+	if count := watchCallbackCount(t, "framework_registry_watch_callback_panics_total", "etcd-isolation"); count == 0 {
+		t.Error("Expected recovered panics to be counted")
+	}
+}
@@ -3,6 +3,7 @@ package registry_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/framework/golang-sdk/protocol/adapter"
@@ -195,10 +196,10 @@ func Example_serviceWatch() {
 
 	ctx := context.Background()
 
-	// 设置监听
-	changeCount := 0
+	// 设置监听，回调在 dispatcher 的 worker 上执行
+	var changeCount atomic.Int32
 	err := reg.Watch(ctx, "notification-service", func(services []*registry.ServiceInfo) {
-		changeCount++
+		changeCount.Add(1)
 		fmt.Printf("Service changed, now %d instances\n", len(services))
 	})
 	if err != nil {
@@ -254,7 +255,7 @@ func Example_serviceWatch() {
 	// 等待监听触发
 	time.Sleep(100 * time.Millisecond)
 
-	fmt.Printf("Total changes detected: %d\n", changeCount)
+	fmt.Printf("Total changes detected: %d\n", changeCount.Load())
 
 	// Output:
 	// Service changed, now 1 instances
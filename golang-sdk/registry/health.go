@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+)
+
+// 注册中心的健康检查，实现 observability.HealthCheck 和 lifecycle.Dependency，
+// 可通过 HealthChecker.RegisterCheck 注册或作为启动依赖
+
+// healthProbeService 注册中心连通性探测使用的服务名
+const healthProbeService = "__health_probe__"
+
+// pinger 支持直接探测连通性的注册中心
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ConnectivityCheck 注册中心连通性检查
+type ConnectivityCheck struct {
+	registry ServiceRegistry
+}
+
+// NewConnectivityCheck 创建注册中心连通性检查
+//
+// 注册中心实现 Ping 方法（如 EtcdRegistry）时直接使用，否则通过一次服务查询探测
+func NewConnectivityCheck(registry ServiceRegistry) *ConnectivityCheck {
+	return &ConnectivityCheck{registry: registry}
+}
+
+// Name 返回检查名称 registry
+func (c *ConnectivityCheck) Name() string {
+	return "registry"
+}
+
+// Check 注册中心可以访问时返回 nil
+func (c *ConnectivityCheck) Check(ctx context.Context) error {
+	if p, ok := c.registry.(pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	if _, err := c.registry.Discover(ctx, healthProbeService); err != nil {
+		return fmt.Errorf("registry not reachable: %w", err)
+	}
+	return nil
+}
+
+// DiscoveryCheck 下游服务发现检查，注册中心中存在服务实例时通过
+type DiscoveryCheck struct {
+	registry    ServiceRegistry
+	serviceName string
+}
+
+// NewDiscoveryCheck 创建下游服务发现检查
+//
+// 主要作为启动依赖（见 lifecycle.WaitForDependencies），等待下游服务启动后再接收流量
+func NewDiscoveryCheck(registry ServiceRegistry, serviceName string) *DiscoveryCheck {
+	return &DiscoveryCheck{registry: registry, serviceName: serviceName}
+}
+
+// Name 返回检查名称 service:<服务名>
+func (c *DiscoveryCheck) Name() string {
+	return "service:" + c.serviceName
+}
+
+// Check 注册中心中存在服务实例时返回 nil
+func (c *DiscoveryCheck) Check(ctx context.Context) error {
+	services, err := c.registry.Discover(ctx, c.serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to discover %s: %w", c.serviceName, err)
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("no instances of %s registered", c.serviceName)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConnectivityCheck(t *testing.T) {
+	reg := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer reg.Close()
+
+	check := NewConnectivityCheck(reg)
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("registry check failed: %v", err)
+	}
+}
+
+func TestDiscoveryCheck(t *testing.T) {
+	reg := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer reg.Close()
+
+	check := NewDiscoveryCheck(reg, "order-service")
+	if check.Name() != "service:order-service" {
+		t.Errorf("Name = %s", check.Name())
+	}
+	if err := check.Check(context.Background()); err == nil {
+		t.Error("Expected error before order-service registers")
+	}
+
+	reg.Register(context.Background(), &ServiceInfo{ID: "order-1", Name: "order-service", Address: "127.0.0.1", Port: 8080})
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("service check failed: %v", err)
+	}
+}
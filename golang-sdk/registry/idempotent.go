@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// MetadataIdempotentMethods 声明幂等方法的元数据键，值为逗号分隔的完整方法名（如 user.getUser,user.listUsers）
+//
+// 各语言 SDK 注册服务时写入，调用方只自动重试这些方法，避免重复执行有副作用的调用
+const MetadataIdempotentMethods = "idempotent.methods"
+
+// SetIdempotentMethods 将幂等方法写入服务实例元数据，方法名排序去重，methods 为空时删除该键
+func SetIdempotentMethods(service *ServiceInfo, methods []string) {
+	set := make(map[string]bool, len(methods))
+	names := make([]string, 0, len(methods))
+	for _, method := range methods {
+		method = strings.TrimSpace(method)
+		if method == "" || set[method] {
+			continue
+		}
+		set[method] = true
+		names = append(names, method)
+	}
+	if len(names) == 0 {
+		delete(service.Metadata, MetadataIdempotentMethods)
+		return
+	}
+	sort.Strings(names)
+	if service.Metadata == nil {
+		service.Metadata = make(map[string]string)
+	}
+	service.Metadata[MetadataIdempotentMethods] = strings.Join(names, ",")
+}
+
+// IsIdempotent 判断实例元数据是否声明 method 为幂等方法
+func IsIdempotent(metadata map[string]string, method string) bool {
+	for _, declared := range strings.Split(metadata[MetadataIdempotentMethods], ",") {
+		if strings.TrimSpace(declared) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIdempotent 判断服务的所有可路由实例是否都声明 method 为幂等方法
+//
+// 滚动发布期间新旧实例的声明可能不同，只要有一个实例未声明就视为非幂等；没有可路由的实例时返回 false
+func (rr *RegistryRouter) IsIdempotent(ctx context.Context, request *adapter.InternalRequest) bool {
+	serviceName, err := rr.resolveServiceName(request)
+	if err != nil {
+		return false
+	}
+	snapshot, err := rr.endpoints(ctx, serviceName)
+	if err != nil || len(snapshot.endpoints) == 0 {
+		return false
+	}
+	for _, endpoint := range snapshot.endpoints {
+		if !IsIdempotent(endpoint.Metadata, request.Method) {
+			return false
+		}
+	}
+	return true
+}
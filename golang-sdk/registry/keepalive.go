@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// DefaultKeepAliveInterval KeepRegistered 默认的心跳间隔
+const DefaultKeepAliveInterval = 10 * time.Second
+
+// Heartbeater 需要服务实例定期发送心跳的注册中心，如 MemoryRegistry
+type Heartbeater interface {
+	Heartbeat(ctx context.Context, serviceID string) error
+}
+
+// RegistrationState 服务实例在注册中心中的状态
+type RegistrationState string
+
+const (
+	// RegistrationActive 心跳成功，实例可被发现
+	RegistrationActive RegistrationState = "active"
+	// RegistrationLost 注册中心中找不到实例（如注册中心重启或实例已过期），正在重新注册
+	RegistrationLost RegistrationState = "lost"
+	// RegistrationFailing 心跳或重新注册失败（如注册中心不可达），下个周期重试
+	RegistrationFailing RegistrationState = "failing"
+)
+
+// KeepRegisteredOptions KeepRegistered 选项
+type KeepRegisteredOptions struct {
+	// Interval 心跳间隔，为 0 时使用 DefaultKeepAliveInterval，应小于注册中心的 TTL
+	Interval time.Duration
+	// Timeout 每次心跳和重新注册的超时，为 0 时使用 Interval
+	Timeout time.Duration
+	// OnStateChange 状态变化时调用，err 为导致 RegistrationLost 或 RegistrationFailing 的错误
+	OnStateChange func(service *ServiceInfo, state RegistrationState, err error)
+}
+
+// KeepRegistered 定期为已注册的服务实例发送心跳，直到 ctx 结束，返回 ctx.Err()
+//
+// 注册中心实现 Heartbeater 时发送心跳，否则以 HealthCheck 探测实例；返回 ErrServiceNotFound
+// （或 NotFound 框架错误）时立即重新注册 service。其他错误视为注册中心暂时不可用，下个周期重试。
+// 状态变化经 OnStateChange 回调，并记录在 framework_registry_* 指标中
+func KeepRegistered(ctx context.Context, reg ServiceRegistry, service *ServiceInfo, options *KeepRegisteredOptions) error {
+	if options == nil {
+		options = &KeepRegisteredOptions{}
+	}
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = interval
+	}
+
+	keeper := &keeper{reg: reg, service: service, options: options, timeout: timeout, state: RegistrationActive}
+	setRegistrationActive(service.Name, true)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			keeper.beat(ctx)
+		}
+	}
+}
+
+// keeper KeepRegistered 的状态
+type keeper struct {
+	reg     ServiceRegistry
+	service *ServiceInfo
+	options *KeepRegisteredOptions
+	timeout time.Duration
+	state   RegistrationState
+}
+
+// beat 发送一次心跳，实例丢失时重新注册
+func (k *keeper) beat(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	err := k.probe(ctx)
+	switch {
+	case err == nil:
+		recordHeartbeat(k.service.Name, "ok")
+		k.transition(RegistrationActive, nil)
+		return
+	case !IsServiceNotFound(err):
+		recordHeartbeat(k.service.Name, "error")
+		k.transition(RegistrationFailing, err)
+		return
+	}
+
+	recordHeartbeat(k.service.Name, "not_found")
+	k.transition(RegistrationLost, err)
+	if err := k.reg.Register(ctx, k.service); err != nil {
+		recordReregistration(k.service.Name, "error")
+		k.transition(RegistrationFailing, err)
+		return
+	}
+	recordReregistration(k.service.Name, "ok")
+	k.transition(RegistrationActive, nil)
+}
+
+// probe 发送心跳，注册中心不需要心跳时检查实例是否存在
+func (k *keeper) probe(ctx context.Context) error {
+	if hb, ok := k.reg.(Heartbeater); ok {
+		return hb.Heartbeat(ctx, k.service.ID)
+	}
+	_, err := k.reg.HealthCheck(ctx, k.service.ID)
+	return err
+}
+
+// transition 切换状态，状态变化时更新指标并回调
+func (k *keeper) transition(state RegistrationState, err error) {
+	if state == k.state {
+		return
+	}
+	k.state = state
+	setRegistrationActive(k.service.Name, state == RegistrationActive)
+	if k.options.OnStateChange != nil {
+		k.options.OnStateChange(k.service, state, err)
+	}
+}
+
+// IsServiceNotFound 判断注册中心返回的错误是否表示实例不存在
+func IsServiceNotFound(err error) bool {
+	if errors.Is(err, ErrServiceNotFound) {
+		return true
+	}
+	fe, ok := frameworkerrors.FromError(err)
+	return ok && fe.Code == frameworkerrors.NotFound
+}
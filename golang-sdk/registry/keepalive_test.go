@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyRegistry 心跳可以注入错误的内存注册中心
+type flakyRegistry struct {
+	*MemoryRegistry
+	mu           sync.Mutex
+	heartbeatErr error
+}
+
+func (f *flakyRegistry) Heartbeat(ctx context.Context, serviceID string) error {
+	f.mu.Lock()
+	err := f.heartbeatErr
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return f.MemoryRegistry.Heartbeat(ctx, serviceID)
+}
+
+func (f *flakyRegistry) setHeartbeatErr(err error) {
+	f.mu.Lock()
+	f.heartbeatErr = err
+	f.mu.Unlock()
+}
+
+// TestKeepRegistered 测试实例丢失后自动重新注册，注册中心不可用时报告 failing
+func TestKeepRegistered(t *testing.T) {
+	reg := &flakyRegistry{MemoryRegistry: NewMemoryRegistry(DefaultMemoryRegistryConfig())}
+	defer reg.Close()
+
+	service := &ServiceInfo{ID: "order-1", Name: "order-service", Address: "127.0.0.1", Port: 8080}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := reg.Register(ctx, service); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+
+	var mu sync.Mutex
+	var states []RegistrationState
+	done := make(chan error, 1)
+	go func() {
+		done <- KeepRegistered(ctx, reg, service, &KeepRegisteredOptions{
+			Interval: 20 * time.Millisecond,
+			OnStateChange: func(service *ServiceInfo, state RegistrationState, err error) {
+				mu.Lock()
+				states = append(states, state)
+				mu.Unlock()
+			},
+		})
+	}()
+	waitForStates := func(n int) []RegistrationState {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := append([]RegistrationState(nil), states...)
+			mu.Unlock()
+			if len(got) >= n {
+				return got
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for %d state changes", n)
+		return nil
+	}
+
+	// 模拟注册中心重启丢失实例
+	reg.Deregister(context.Background(), service.ID)
+	got := waitForStates(2)
+	if got[0] != RegistrationLost || got[1] != RegistrationActive {
+		t.Errorf("Expected lost then active, got %v", got)
+	}
+	services, _ := reg.Discover(context.Background(), "order-service")
+	if len(services) != 1 || services[0].ID != service.ID {
+		t.Errorf("Expected instance to be re-registered, got %v", services)
+	}
+
+	// 注册中心不可达时不重新注册，恢复后回到 active
+	reg.setHeartbeatErr(errors.New("connection refused"))
+	got = waitForStates(3)
+	if got[2] != RegistrationFailing {
+		t.Errorf("Expected failing, got %v", got)
+	}
+	reg.setHeartbeatErr(nil)
+	got = waitForStates(4)
+	if got[3] != RegistrationActive {
+		t.Errorf("Expected active after recovery, got %v", got)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestIsServiceNotFound 测试实例不存在错误的识别
+func TestIsServiceNotFound(t *testing.T) {
+	reg := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer reg.Close()
+
+	if err := reg.Heartbeat(context.Background(), "missing"); !IsServiceNotFound(err) {
+		t.Errorf("Expected heartbeat of unknown instance to be not found, got %v", err)
+	}
+	if IsServiceNotFound(errors.New("connection refused")) {
+		t.Error("Expected other errors not to be not found")
+	}
+}
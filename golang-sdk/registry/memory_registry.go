@@ -3,6 +3,8 @@ package registry
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,14 +14,21 @@ type MemoryRegistryConfig struct {
 	TTL               time.Duration // 服务 TTL（生存时间）
 	HeartbeatInterval time.Duration // 心跳间隔
 	CleanupInterval   time.Duration // 清理过期服务的间隔
+	// WatchWorkers 并发执行监听回调的 worker 数，为 0 时使用 DefaultWatchWorkers
+	WatchWorkers int
+	// SlowWatchThreshold 监听回调执行超过该时间时记录警告并计入 framework_registry_watch_callback_slow_total，
+	// 为 0 时使用 DefaultSlowWatchThreshold
+	SlowWatchThreshold time.Duration
 }
 
 // DefaultMemoryRegistryConfig 默认配置
 func DefaultMemoryRegistryConfig() *MemoryRegistryConfig {
 	return &MemoryRegistryConfig{
-		TTL:               30 * time.Second,
-		HeartbeatInterval: 10 * time.Second,
-		CleanupInterval:   5 * time.Second,
+		TTL:                30 * time.Second,
+		HeartbeatInterval:  10 * time.Second,
+		CleanupInterval:    5 * time.Second,
+		WatchWorkers:       DefaultWatchWorkers,
+		SlowWatchThreshold: DefaultSlowWatchThreshold,
 	}
 }
 
@@ -36,10 +45,23 @@ type MemoryRegistry struct {
 	config    *MemoryRegistryConfig
 	mu        sync.RWMutex
 	services  map[string]map[string]*serviceEntry // serviceName -> serviceID -> entry
-	watchers  map[string][]func([]*ServiceInfo)   // serviceName -> callbacks
+	watchers  map[string][]*serviceWatcher        // serviceName -> callbacks
+	patternWatchers []patternWatcher              // 按模式监听的回调
+	onExpired []func(*ServiceInfo)                // 实例过期监听器
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
+
+	// notifyMu 串行化变更通知，最后一次通知总是反映最新的服务列表
+	notifyMu sync.Mutex
+	// dispatcher 执行监听回调和实例过期监听器
+	dispatcher *watchDispatcher
+}
+
+// serviceWatcher 监听单个服务的回调
+type serviceWatcher struct {
+	*watchSubscriber
+	callback func([]*ServiceInfo)
 }
 
 // NewMemoryRegistry 创建内存注册中心
@@ -51,11 +73,12 @@ func NewMemoryRegistry(config *MemoryRegistryConfig) *MemoryRegistry {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	registry := &MemoryRegistry{
-		config:   config,
-		services: make(map[string]map[string]*serviceEntry),
-		watchers: make(map[string][]func([]*ServiceInfo)),
-		ctx:      ctx,
-		cancel:   cancel,
+		config:     config,
+		services:   make(map[string]map[string]*serviceEntry),
+		watchers:   make(map[string][]*serviceWatcher),
+		ctx:        ctx,
+		cancel:     cancel,
+		dispatcher: newWatchDispatcher("memory", config.WatchWorkers, config.SlowWatchThreshold),
 	}
 
 	// 启动定期清理过期服务的 goroutine
@@ -65,18 +88,11 @@ func NewMemoryRegistry(config *MemoryRegistryConfig) *MemoryRegistry {
 	return registry
 }
 
-// Register 注册服务
+// Register 注册服务，服务实例信息经 ValidateServiceInfo 校验后保存 NormalizeServiceInfo 规范化的副本
 func (m *MemoryRegistry) Register(ctx context.Context, service *ServiceInfo) error {
-	if service == nil {
-		return fmt.Errorf("service is nil")
-	}
-
-	if service.ID == "" {
-		return fmt.Errorf("service ID is empty")
-	}
-
-	if service.Name == "" {
-		return fmt.Errorf("service name is empty")
+	service, err := prepareService(service)
+	if err != nil {
+		return err
 	}
 
 	m.mu.Lock()
@@ -88,15 +104,17 @@ func (m *MemoryRegistry) Register(ctx context.Context, service *ServiceInfo) err
 	}
 
 	// 创建或更新服务条目
+	now := time.Now()
+	service.RegisteredAt = now
 	entry := &serviceEntry{
 		info:      service,
-		expiresAt: time.Now().Add(m.config.TTL),
+		expiresAt: now.Add(m.config.TTL),
 	}
 
 	m.services[service.Name][service.ID] = entry
 
 	// 通知监听者
-	go m.notifyWatchers(service.Name)
+	go m.notifyWatchers(service.Name, watchOpRegister, now)
 
 	return nil
 }
@@ -129,11 +147,11 @@ func (m *MemoryRegistry) Deregister(ctx context.Context, serviceID string) error
 	}
 
 	if !found {
-		return fmt.Errorf("service not found: %s", serviceID)
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
 	}
 
 	// 通知监听者
-	go m.notifyWatchers(serviceName)
+	go m.notifyWatchers(serviceName, watchOpDeregister, time.Now())
 
 	return nil
 }
@@ -207,7 +225,7 @@ func (m *MemoryRegistry) HealthCheck(ctx context.Context, serviceID string) (Hea
 		}
 	}
 
-	return HealthStatusUnknown, fmt.Errorf("service not found: %s", serviceID)
+	return HealthStatusUnknown, fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
 }
 
 // Watch 监听服务变化
@@ -224,15 +242,66 @@ func (m *MemoryRegistry) Watch(ctx context.Context, serviceName string, callback
 	defer m.mu.Unlock()
 
 	// 注册回调
-	m.watchers[serviceName] = append(m.watchers[serviceName], callback)
+	m.watchers[serviceName] = append(m.watchers[serviceName], &serviceWatcher{
+		watchSubscriber: &watchSubscriber{label: serviceName},
+		callback:        callback,
+	})
 
 	return nil
 }
 
-// Close 关闭注册中心
+// patternWatcher 按模式监听的回调
+type patternWatcher struct {
+	*watchSubscriber
+	pattern  string
+	callback func(map[string][]*ServiceInfo)
+}
+
+// DiscoverByPrefix 查询服务名以 prefix 开头的所有服务实例，按服务名分组
+func (m *MemoryRegistry) DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error) {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.services))
+	for name := range m.services {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	m.mu.RUnlock()
+
+	result := make(map[string][]*ServiceInfo, len(names))
+	for _, name := range names {
+		services, err := m.Discover(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(services) > 0 {
+			result[name] = services
+		}
+	}
+	return result, nil
+}
+
+// WatchPattern 监听服务名匹配 pattern 的所有服务
+func (m *MemoryRegistry) WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error {
+	if err := validatePattern(pattern, callback); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.patternWatchers = append(m.patternWatchers, patternWatcher{
+		watchSubscriber: &watchSubscriber{label: pattern},
+		pattern:         pattern,
+		callback:        callback,
+	})
+	return nil
+}
+
+// Close 关闭注册中心，尚未执行的监听通知被丢弃
 func (m *MemoryRegistry) Close() error {
 	m.cancel()
 	m.wg.Wait()
+	m.dispatcher.close()
 	return nil
 }
 
@@ -255,7 +324,7 @@ func (m *MemoryRegistry) Heartbeat(ctx context.Context, serviceID string) error
 		}
 	}
 
-	return fmt.Errorf("service not found: %s", serviceID)
+	return fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
 }
 
 // cleanupExpiredServices 定期清理过期的服务
@@ -281,18 +350,23 @@ func (m *MemoryRegistry) performCleanup() {
 	defer m.mu.Unlock()
 
 	now := time.Now()
-	changedServices := make(map[string]bool)
+	// 服务名 -> 最早过期的时间，作为传播延迟的起点
+	changedServices := make(map[string]time.Time)
+	var expiredServices []*ServiceInfo
 
 	// 遍历所有服务，删除过期的实例
 	for serviceName, instances := range m.services {
 		for serviceID, entry := range instances {
 			entry.mu.RLock()
-			expired := entry.expiresAt.Before(now)
+			expiresAt := entry.expiresAt
 			entry.mu.RUnlock()
 
-			if expired {
+			if expiresAt.Before(now) {
 				delete(instances, serviceID)
-				changedServices[serviceName] = true
+				if first, ok := changedServices[serviceName]; !ok || expiresAt.Before(first) {
+					changedServices[serviceName] = expiresAt
+				}
+				expiredServices = append(expiredServices, entry.info)
 			}
 		}
 
@@ -303,30 +377,91 @@ func (m *MemoryRegistry) performCleanup() {
 	}
 
 	// 通知监听者
-	for serviceName := range changedServices {
-		go m.notifyWatchers(serviceName)
+	for serviceName, expiredAt := range changedServices {
+		go m.notifyWatchers(serviceName, watchOpExpire, expiredAt)
+	}
+
+	if len(expiredServices) > 0 && len(m.onExpired) > 0 {
+		go m.notifyExpired(m.onExpired, expiredServices)
+	}
+}
+
+// OnInstanceExpired 注册实例过期监听器，心跳超时被清理的实例会逐个通知
+func (m *MemoryRegistry) OnInstanceExpired(listener func(*ServiceInfo)) {
+	if listener == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onExpired = append(m.onExpired, listener)
+}
+
+// EventInstanceExpired 实例心跳超时被清理的框架事件类型，与 observability.EventInstanceExpired 一致
+const EventInstanceExpired = "registry.instance_expired"
+
+// WatchEvents 将实例过期以框架事件发布，实现 observability.EventSource，事件来源为服务名
+func (m *MemoryRegistry) WatchEvents(emit func(eventType, source string, attributes map[string]string)) {
+	m.OnInstanceExpired(func(service *ServiceInfo) {
+		emit(EventInstanceExpired, service.Name, map[string]string{
+			"instance_id": service.ID,
+			"address":     service.Address + ":" + strconv.Itoa(service.Port),
+		})
+	})
+}
+
+// notifyExpired 通知实例过期监听器，监听器的 panic 被恢复并记录
+func (m *MemoryRegistry) notifyExpired(listeners []func(*ServiceInfo), services []*ServiceInfo) {
+	for _, service := range services {
+		for _, listener := range listeners {
+			m.dispatcher.invoke(service.Name, func() { listener(service) })
+		}
 	}
 }
 
-// notifyWatchers 通知监听者服务变化
-func (m *MemoryRegistry) notifyWatchers(serviceName string) {
+// notifyWatchers 通知监听者服务变化，记录从 changedAt 到通知的传播延迟
+//
+// 回调由 dispatcher 的 worker 执行，不阻塞注册中心：同一回调按顺序执行，执行期间的多次变化合并为最新的服务列表
+func (m *MemoryRegistry) notifyWatchers(serviceName, op string, changedAt time.Time) {
 	m.mu.RLock()
 	callbacks := m.watchers[serviceName]
+	var patterns []patternWatcher
+	for _, watcher := range m.patternWatchers {
+		if MatchServiceName(watcher.pattern, serviceName) {
+			patterns = append(patterns, watcher)
+		}
+	}
 	m.mu.RUnlock()
 
-	if len(callbacks) == 0 {
+	if len(callbacks) == 0 && len(patterns) == 0 {
 		return
 	}
 
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+
 	// 获取最新的服务列表
 	services, err := m.Discover(context.Background(), serviceName)
 	if err != nil {
 		return
 	}
 
+	observeWatchPropagation("memory", op, time.Since(changedAt))
+
 	// 调用所有回调
-	for _, callback := range callbacks {
-		callback(services)
+	for _, watcher := range callbacks {
+		callback := watcher.callback
+		m.dispatcher.submit(watcher.watchSubscriber, func() { callback(services) })
+	}
+
+	// 按模式监听的回调收到所有匹配服务的实例
+	for _, watcher := range patterns {
+		groups, err := m.DiscoverByPrefix(context.Background(), patternPrefix(watcher.pattern))
+		if err != nil {
+			continue
+		}
+		callback, matched := watcher.callback, filterPattern(watcher.pattern, groups)
+		m.dispatcher.submit(watcher.watchSubscriber, func() { callback(matched) })
 	}
 }
 
@@ -3,6 +3,7 @@ package registry
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -391,3 +392,120 @@ func TestMemoryRegistryRouterTTLExpiration(t *testing.T) {
 		t.Error("Expected error after TTL expiration, got nil")
 	}
 }
+
+// countingRegistry 统计 Discover 调用次数的注册中心
+type countingRegistry struct {
+	ServiceRegistry
+	discovers atomic.Int32
+}
+
+func (c *countingRegistry) Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	c.discovers.Add(1)
+	return c.ServiceRegistry.Discover(ctx, serviceName)
+}
+
+// TestRegistryRouterSnapshot 测试路由使用自动监听维护的端点快照
+func TestRegistryRouterSnapshot(t *testing.T) {
+	registry := &countingRegistry{ServiceRegistry: NewMemoryRegistry(nil)}
+	registryRouter := NewRegistryRouter(registry, nil)
+	defer registryRouter.Close()
+
+	ctx := context.Background()
+	request := &adapter.InternalRequest{Service: "snapshot-service", Method: "test"}
+	register := func(id string, port int) {
+		if err := registry.Register(ctx, &ServiceInfo{ID: id, Name: "snapshot-service", Address: "localhost", Port: port}); err != nil {
+			t.Fatalf("Failed to register service: %v", err)
+		}
+	}
+	// waitForPorts 等待变更通知更新快照后路由结果只包含 ports
+	waitForPorts := func(ports ...int) {
+		t.Helper()
+		want := make(map[int]bool)
+		for _, port := range ports {
+			want[port] = true
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got := make(map[int]bool)
+			for i := 0; i < 2*len(ports)+2; i++ {
+				if endpoint, err := registryRouter.Route(ctx, request); err == nil {
+					got[endpoint.Port] = true
+				}
+			}
+			if fmt.Sprint(got) == fmt.Sprint(want) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected ports %v, got %v", want, got)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	register("snapshot-1", 9501)
+	for i := 0; i < 100; i++ {
+		if _, err := registryRouter.Route(ctx, request); err != nil {
+			t.Fatalf("Failed to route request: %v", err)
+		}
+	}
+	if got := registry.discovers.Load(); got != 1 {
+		t.Errorf("Expected 1 Discover call, got %d", got)
+	}
+
+	t.Run("注册和注销实例后更新快照", func(t *testing.T) {
+		register("snapshot-2", 9502)
+		waitForPorts(9501, 9502)
+
+		registry.Deregister(ctx, "snapshot-1")
+		waitForPorts(9502)
+
+		registry.Deregister(ctx, "snapshot-2")
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			_, err := registryRouter.Route(ctx, request)
+			if err != nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Expected routing to fail after all instances deregistered")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		register("snapshot-3", 9503)
+		waitForPorts(9503)
+
+		if got := registry.discovers.Load(); got != 1 {
+			t.Errorf("Expected snapshot to be updated by watch only, got %d Discover calls", got)
+		}
+	})
+
+	t.Run("停止监听后重新查询注册中心", func(t *testing.T) {
+		registryRouter.StopWatchService("snapshot-service")
+		waitForPorts(9503)
+		if got := registry.discovers.Load(); got != 2 {
+			t.Errorf("Expected 2 Discover calls, got %d", got)
+		}
+	})
+
+	t.Run("监听的 ctx 取消后丢弃快照", func(t *testing.T) {
+		watchCtx, cancel := context.WithCancel(ctx)
+		if err := registryRouter.WatchService(watchCtx, "snapshot-service"); err != nil {
+			t.Fatalf("Failed to watch service: %v", err)
+		}
+		cancel()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			registryRouter.mu.RLock()
+			_, watching := registryRouter.watchers["snapshot-service"]
+			registryRouter.mu.RUnlock()
+			if !watching {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Expected watch to be removed after ctx canceled")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		waitForPorts(9503)
+	})
+}
@@ -3,8 +3,11 @@ package registry
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // TestMemoryRegistryServiceRegistration 测试内存注册中心的服务注册
@@ -129,10 +132,10 @@ func TestMemoryRegistryServiceWatch(t *testing.T) {
 
 	ctx := context.Background()
 
-	// 设置监听
-	changeCount := 0
+	// 设置监听，回调在 dispatcher 的 worker 上执行
+	var changeCount atomic.Int32
 	err := registry.Watch(ctx, "watch-test-service", func(services []*ServiceInfo) {
-		changeCount++
+		changeCount.Add(1)
 	})
 	if err != nil {
 		t.Fatalf("Failed to watch service: %v", err)
@@ -158,7 +161,7 @@ func TestMemoryRegistryServiceWatch(t *testing.T) {
 	// 等待监听触发
 	time.Sleep(100 * time.Millisecond)
 
-	if changeCount == 0 {
+	if changeCount.Load() == 0 {
 		t.Error("Expected service change notification, but got none")
 	}
 
@@ -169,6 +172,138 @@ func TestMemoryRegistryServiceWatch(t *testing.T) {
 	}
 }
 
+// TestMemoryRegistryWatchPropagationMetric 测试注册和注销到通知监听者的传播延迟指标
+func TestMemoryRegistryWatchPropagationMetric(t *testing.T) {
+	registry := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer registry.Close()
+
+	ctx := context.Background()
+	notified := make(chan struct{}, 2)
+	if err := registry.Watch(ctx, "propagation-service", func(services []*ServiceInfo) {
+		notified <- struct{}{}
+	}); err != nil {
+		t.Fatalf("Failed to watch service: %v", err)
+	}
+
+	service := &ServiceInfo{
+		ID:        "propagation-1",
+		Name:      "propagation-service",
+		Version:   "1.0.0",
+		Language:  "golang",
+		Address:   "localhost",
+		Port:      9090,
+		Protocols: []string{"gRPC"},
+	}
+	if err := registry.Register(ctx, service); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+	<-notified
+	if err := registry.Deregister(ctx, service.ID); err != nil {
+		t.Fatalf("Failed to deregister service: %v", err)
+	}
+	<-notified
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != "framework_registry_watch_propagation_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			counts[labels["backend"]+"|"+labels["operation"]] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	for _, key := range []string{"memory|register", "memory|deregister"} {
+		if counts[key] == 0 {
+			t.Errorf("expected propagation observation with labels %s", key)
+		}
+	}
+}
+
+// TestMemoryRegistryWatchCallbackIsolation 测试阻塞和 panic 的监听回调不影响其他回调
+func TestMemoryRegistryWatchCallbackIsolation(t *testing.T) {
+	config := DefaultMemoryRegistryConfig()
+	config.WatchWorkers = 2
+	config.SlowWatchThreshold = 20 * time.Millisecond
+	registry := NewMemoryRegistry(config)
+	defer registry.Close()
+
+	ctx := context.Background()
+	release := make(chan struct{})
+	defer close(release)
+	blocked := make(chan int, 10)
+	registry.Watch(ctx, "isolation-service", func(services []*ServiceInfo) {
+		blocked <- len(services)
+		<-release
+	})
+	registry.Watch(ctx, "isolation-service", func(services []*ServiceInfo) {
+		panic("bad watcher")
+	})
+	updates := make(chan int, 10)
+	registry.Watch(ctx, "isolation-service", func(services []*ServiceInfo) {
+		updates <- len(services)
+	})
+
+	for i := 1; i <= 3; i++ {
+		registry.Register(ctx, &ServiceInfo{ID: fmt.Sprintf("isolation-%d", i), Name: "isolation-service", Address: "localhost", Port: 9000 + i})
+		select {
+		case count := <-updates:
+			if count != i {
+				t.Errorf("Expected %d instances, got %d", i, count)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for notification %d", i)
+		}
+	}
+	// 阻塞的回调只执行了第一次，之后的通知合并为一次等待执行
+	if len(blocked) != 1 {
+		t.Errorf("Expected blocked watcher to run once, ran %d times", len(blocked))
+	}
+
+	// 阻塞超过阈值的回调在返回前即被发现
+	deadline := time.Now().Add(time.Second)
+	for watchCallbackCount(t, "framework_registry_watch_callback_slow_total", "isolation-service") == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if count := watchCallbackCount(t, "framework_registry_watch_callback_slow_total", "isolation-service"); count != 1 {
+		t.Errorf("Expected 1 slow callback, got %v", count)
+	}
+	if count := watchCallbackCount(t, "framework_registry_watch_callback_panics_total", "isolation-service"); count == 0 {
+		t.Error("Expected recovered panics to be counted")
+	}
+}
+
+// watchCallbackCount 返回监听回调计数器中 watch 标签为 watch 的值
+func watchCallbackCount(t *testing.T, name, watch string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "watch" && label.GetValue() == watch {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
 // TestMemoryRegistryTTL 测试服务 TTL 过期
 func TestMemoryRegistryTTL(t *testing.T) {
 	// 使用较短的 TTL 和清理间隔进行测试
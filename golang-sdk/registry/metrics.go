@@ -0,0 +1,219 @@
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	registryMetricsOnce sync.Once
+	// 心跳计数器
+	heartbeatTotal *prometheus.CounterVec
+	// 重新注册计数器
+	reregistrationTotal *prometheus.CounterVec
+	// 实例是否处于 active 状态
+	registrationActive *prometheus.GaugeVec
+	// 从注册、注销到通知监听者的传播延迟
+	watchPropagationSeconds *prometheus.HistogramVec
+	// etcd 监听中断后重新建立的次数
+	watchRestartsTotal *prometheus.CounterVec
+	// 内置 DNS 服务器应答的查询数
+	dnsQueriesTotal *prometheus.CounterVec
+	// etcd 端点是否健康
+	endpointHealthy *prometheus.GaugeVec
+	// 请求从 etcd 端点切走的次数
+	endpointFailoversTotal *prometheus.CounterVec
+	// 多注册中心中各注册中心待修复的服务数
+	backendLagging *prometheus.GaugeVec
+	// 多注册中心的后台修复次数
+	backendRepairsTotal *prometheus.CounterVec
+	// 监听回调 panic 的次数
+	watchCallbackPanicsTotal *prometheus.CounterVec
+	// 执行超过阈值的监听回调次数
+	watchCallbackSlowTotal *prometheus.CounterVec
+)
+
+// 传播延迟指标的 operation 标签
+const (
+	watchOpRegister   = "register"
+	watchOpDeregister = "deregister"
+	watchOpExpire     = "expire"
+)
+
+// initRegistryMetrics 初始化注册保活指标
+func initRegistryMetrics() {
+	registryMetricsOnce.Do(func() {
+		heartbeatTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_heartbeats_total",
+				Help: "Total number of registry heartbeats by result (ok, error, not_found)",
+			},
+			[]string{"service", "result"},
+		)
+		reregistrationTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_reregistrations_total",
+				Help: "Total number of automatic re-registrations after the instance was lost",
+			},
+			[]string{"service", "result"},
+		)
+		registrationActive = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_registry_registration_active",
+				Help: "Whether the instance is registered and heartbeating (1) or lost/failing (0)",
+			},
+			[]string{"service"},
+		)
+		watchPropagationSeconds = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_registry_watch_propagation_seconds",
+				Help:    "Delay from Register/Deregister/expiry to watcher notification by backend and operation",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"backend", "operation"},
+		)
+		watchRestartsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_watch_restarts_total",
+				Help: "Total number of etcd watches re-established after the watch channel was closed, by reason (closed, compacted, error)",
+			},
+			[]string{"service", "reason"},
+		)
+		dnsQueriesTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_dns_queries_total",
+				Help: "Total number of DNS queries answered from registry data by query type and response code",
+			},
+			[]string{"type", "rcode"},
+		)
+		endpointHealthy = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_registry_endpoint_healthy",
+				Help: "Whether the last status check of the etcd endpoint succeeded (1) or failed (0)",
+			},
+			[]string{"endpoint"},
+		)
+		endpointFailoversTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_endpoint_failovers_total",
+				Help: "Total number of times requests were moved away from an etcd endpoint after failed status checks",
+			},
+			[]string{"endpoint"},
+		)
+		backendLagging = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_registry_backend_lagging",
+				Help: "Number of services waiting to be repaired on each backend of a multi-registry",
+			},
+			[]string{"backend"},
+		)
+		backendRepairsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_backend_repairs_total",
+				Help: "Total number of background repairs of lagging multi-registry backends by result (ok, error)",
+			},
+			[]string{"backend", "result"},
+		)
+		watchCallbackPanicsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_watch_callback_panics_total",
+				Help: "Total number of recovered panics in registry watch callbacks by backend and watched service or pattern",
+			},
+			[]string{"backend", "watch"},
+		)
+		watchCallbackSlowTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_registry_watch_callback_slow_total",
+				Help: "Total number of registry watch callbacks running longer than the slow threshold by backend and watched service or pattern",
+			},
+			[]string{"backend", "watch"},
+		)
+	})
+}
+
+// recordHeartbeat 记录一次心跳结果
+func recordHeartbeat(service, result string) {
+	initRegistryMetrics()
+	heartbeatTotal.WithLabelValues(service, result).Inc()
+}
+
+// recordReregistration 记录一次重新注册结果
+func recordReregistration(service, result string) {
+	initRegistryMetrics()
+	reregistrationTotal.WithLabelValues(service, result).Inc()
+}
+
+// setRegistrationActive 设置实例是否处于 active 状态
+func setRegistrationActive(service string, active bool) {
+	initRegistryMetrics()
+	value := 0.0
+	if active {
+		value = 1
+	}
+	registrationActive.WithLabelValues(service).Set(value)
+}
+
+// observeWatchPropagation 记录一次变更传播到监听者的延迟，时钟不同步导致的负值记为 0
+func observeWatchPropagation(backend, operation string, delay time.Duration) {
+	initRegistryMetrics()
+	if delay < 0 {
+		delay = 0
+	}
+	watchPropagationSeconds.WithLabelValues(backend, operation).Observe(delay.Seconds())
+}
+
+// recordWatchRestart 记录一次监听重新建立
+func recordWatchRestart(service, reason string) {
+	initRegistryMetrics()
+	watchRestartsTotal.WithLabelValues(service, reason).Inc()
+}
+
+// recordDNSQuery 记录一次 DNS 查询的类型（如 A、SRV）和响应码（如 Success、NameError）
+func recordDNSQuery(qtype, rcode string) {
+	initRegistryMetrics()
+	dnsQueriesTotal.WithLabelValues(qtype, rcode).Inc()
+}
+
+// setEndpointHealthy 记录 etcd 端点最近一次检查是否成功
+func setEndpointHealthy(endpoint string, healthy bool) {
+	initRegistryMetrics()
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	endpointHealthy.WithLabelValues(endpoint).Set(value)
+}
+
+// recordEndpointFailover 记录一次请求从 etcd 端点切走
+func recordEndpointFailover(endpoint string) {
+	initRegistryMetrics()
+	endpointFailoversTotal.WithLabelValues(endpoint).Inc()
+}
+
+// setBackendLagging 设置多注册中心中注册中心待修复的服务数
+func setBackendLagging(backend string, count int) {
+	initRegistryMetrics()
+	backendLagging.WithLabelValues(backend).Set(float64(count))
+}
+
+// recordBackendRepair 记录一次后台修复结果
+func recordBackendRepair(backend, result string) {
+	initRegistryMetrics()
+	backendRepairsTotal.WithLabelValues(backend, result).Inc()
+}
+
+// recordWatchCallbackPanic 记录一次监听回调 panic
+func recordWatchCallbackPanic(backend, watch string) {
+	initRegistryMetrics()
+	watchCallbackPanicsTotal.WithLabelValues(backend, watch).Inc()
+}
+
+// recordWatchCallbackSlow 记录一次执行过慢的监听回调
+func recordWatchCallbackSlow(backend, watch string) {
+	initRegistryMetrics()
+	watchCallbackSlowTotal.WithLabelValues(backend, watch).Inc()
+}
@@ -0,0 +1,509 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrQuorumNotReached 注册或注销成功的注册中心少于法定数时返回的错误，经 errors.Is 匹配
+var ErrQuorumNotReached = errors.New("registry quorum not reached")
+
+// 多注册中心的默认参数
+const (
+	DefaultRepairInterval = 10 * time.Second
+	DefaultRepairTimeout  = 5 * time.Second
+)
+
+// RegistryBackend 多注册中心中的一个注册中心
+type RegistryBackend struct {
+	// Name 注册中心名称，用于日志、指标和 Lagging，为空时为 registry-<序号>
+	Name     string
+	Registry ServiceRegistry
+}
+
+// MultiRegistryConfig 多注册中心配置
+type MultiRegistryConfig struct {
+	// Quorum 注册和注销需要成功的注册中心数，为 0 时为多数（n/2+1）
+	Quorum int
+	// RepairInterval 后台修复的周期，为 0 时使用 DefaultRepairInterval
+	RepairInterval time.Duration
+	// RepairTimeout 修复时每次注册、注销和检查的超时，为 0 时使用 DefaultRepairTimeout
+	RepairTimeout time.Duration
+}
+
+// MultiRegistry 同时写入多个注册中心的服务注册中心
+//
+// 关键服务可以同时注册到多个注册中心（如两个独立的 etcd 集群），单个注册中心故障时服务发现仍然可用。
+// Register 和 Deregister 并发写入所有注册中心，成功数达到 Quorum 即返回成功；失败的注册中心记为落后，
+// 由后台按 RepairInterval 重试，直到与本实例注册的服务一致。后台还会检查已注册的服务是否仍在各注册中心中
+// （如注册中心重启后丢失数据），缺失时重新注册。
+//
+// Discover 合并所有可用注册中心的结果（同一实例 ID 取靠前的注册中心），任一注册中心可用即成功；
+// Watch 和 WatchPattern 同样合并各注册中心的通知
+type MultiRegistry struct {
+	backends []RegistryBackend
+	quorum   int
+	interval time.Duration
+	timeout  time.Duration
+
+	// writeMu 串行化对注册中心的写入，修复与 Register、Deregister 不会交错
+	writeMu sync.Mutex
+
+	mu sync.Mutex
+	// services 本实例经 Register 注册、尚未注销的服务
+	services map[string]*ServiceInfo
+	// lagging 每个注册中心待修复的服务 ID，服务在 services 中时重新注册，否则注销
+	lagging []map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMultiRegistry 创建多注册中心，config 为 nil 时使用默认配置，后台修复随即开始，Close 时停止
+func NewMultiRegistry(backends []RegistryBackend, config *MultiRegistryConfig) (*MultiRegistry, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no registry backends")
+	}
+	if config == nil {
+		config = &MultiRegistryConfig{}
+	}
+	quorum := config.Quorum
+	if quorum == 0 {
+		quorum = len(backends)/2 + 1
+	}
+	if quorum < 1 || quorum > len(backends) {
+		return nil, fmt.Errorf("registry quorum %d out of range [1, %d]", quorum, len(backends))
+	}
+
+	named := make([]RegistryBackend, len(backends))
+	lagging := make([]map[string]bool, len(backends))
+	for i, backend := range backends {
+		if backend.Registry == nil {
+			return nil, fmt.Errorf("registry backend %d is nil", i)
+		}
+		if backend.Name == "" {
+			backend.Name = fmt.Sprintf("registry-%d", i)
+		}
+		named[i] = backend
+		lagging[i] = make(map[string]bool)
+		setBackendLagging(backend.Name, 0)
+	}
+
+	m := &MultiRegistry{
+		backends: named,
+		quorum:   quorum,
+		interval: config.RepairInterval,
+		timeout:  config.RepairTimeout,
+		services: make(map[string]*ServiceInfo),
+		lagging:  lagging,
+	}
+	if m.interval <= 0 {
+		m.interval = DefaultRepairInterval
+	}
+	if m.timeout <= 0 {
+		m.timeout = DefaultRepairTimeout
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.wg.Add(1)
+	go m.repairLoop()
+	return m, nil
+}
+
+// Quorum 返回写入需要成功的注册中心数
+func (m *MultiRegistry) Quorum() int {
+	return m.quorum
+}
+
+// Register 向所有注册中心注册服务，成功数少于法定数时返回 ErrQuorumNotReached；
+// 无论是否达到法定数，失败的注册中心都在后台继续重试，直到 Deregister
+func (m *MultiRegistry) Register(ctx context.Context, service *ServiceInfo) error {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	}
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	m.mu.Lock()
+	m.services[service.ID] = service
+	m.mu.Unlock()
+
+	errs := m.write(ctx, service.ID, func(ctx context.Context, reg ServiceRegistry) error {
+		return reg.Register(ctx, service)
+	})
+	return m.checkQuorum("register", service.ID, errs)
+}
+
+// Deregister 从所有注册中心注销服务，实例不存在的注册中心视为成功；成功数少于法定数时返回 ErrQuorumNotReached，
+// 失败的注册中心在后台继续重试
+func (m *MultiRegistry) Deregister(ctx context.Context, serviceID string) error {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	m.mu.Lock()
+	delete(m.services, serviceID)
+	m.mu.Unlock()
+
+	errs := m.write(ctx, serviceID, func(ctx context.Context, reg ServiceRegistry) error {
+		if err := reg.Deregister(ctx, serviceID); err != nil && !IsServiceNotFound(err) {
+			return err
+		}
+		return nil
+	})
+	return m.checkQuorum("deregister", serviceID, errs)
+}
+
+// write 并发写入所有注册中心，更新各注册中心是否落后，返回每个注册中心的错误
+func (m *MultiRegistry) write(ctx context.Context, serviceID string, op func(context.Context, ServiceRegistry) error) []error {
+	errs := make([]error, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		wg.Add(1)
+		go func(i int, reg ServiceRegistry) {
+			defer wg.Done()
+			errs[i] = op(ctx, reg)
+		}(i, backend.Registry)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, err := range errs {
+		m.markLagging(i, serviceID, err != nil)
+	}
+	return errs
+}
+
+// checkQuorum 成功数达到法定数时返回 nil，否则返回带各注册中心错误的 ErrQuorumNotReached
+func (m *MultiRegistry) checkQuorum(op, serviceID string, errs []error) error {
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", m.backends[i].Name, err))
+		}
+	}
+	if succeeded := len(errs) - len(failed); succeeded < m.quorum {
+		return fmt.Errorf("%w: %s %s succeeded on %d of %d registries (quorum %d): %w",
+			ErrQuorumNotReached, op, serviceID, succeeded, len(errs), m.quorum, errors.Join(failed...))
+	}
+	return nil
+}
+
+// markLagging 标记注册中心上的服务是否待修复，调用方须持有 mu
+func (m *MultiRegistry) markLagging(i int, serviceID string, lagging bool) {
+	if lagging {
+		m.lagging[i][serviceID] = true
+	} else {
+		delete(m.lagging[i], serviceID)
+	}
+	setBackendLagging(m.backends[i].Name, len(m.lagging[i]))
+}
+
+// Lagging 返回各注册中心待修复的服务 ID（已排序），没有待修复服务的注册中心不在结果中
+func (m *MultiRegistry) Lagging() map[string][]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string][]string)
+	for i, ids := range m.lagging {
+		if len(ids) == 0 {
+			continue
+		}
+		list := make([]string, 0, len(ids))
+		for id := range ids {
+			list = append(list, id)
+		}
+		sort.Strings(list)
+		result[m.backends[i].Name] = list
+	}
+	return result
+}
+
+// repairLoop 定期修复落后的注册中心，直到 Close
+func (m *MultiRegistry) repairLoop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.repair()
+		}
+	}
+}
+
+// repair 重试各注册中心待修复的写入，并检查已注册的服务是否仍在各注册中心中，缺失时重新注册
+func (m *MultiRegistry) repair() {
+	for i := range m.backends {
+		m.mu.Lock()
+		ids := make([]string, 0, len(m.services)+len(m.lagging[i]))
+		for id := range m.lagging[i] {
+			ids = append(ids, id)
+		}
+		for id := range m.services {
+			if !m.lagging[i][id] {
+				ids = append(ids, id)
+			}
+		}
+		m.mu.Unlock()
+
+		for _, id := range ids {
+			if m.ctx.Err() != nil {
+				return
+			}
+			m.repairService(i, id)
+		}
+	}
+}
+
+// repairService 使注册中心上的服务与本实例注册的状态一致
+func (m *MultiRegistry) repairService(i int, serviceID string) {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	m.mu.Lock()
+	service := m.services[serviceID]
+	lagging := m.lagging[i][serviceID]
+	m.mu.Unlock()
+	if service == nil && !lagging {
+		return // 检查期间已注销且各注册中心已同步
+	}
+
+	backend := m.backends[i]
+	ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case service == nil:
+		if err = backend.Registry.Deregister(ctx, serviceID); IsServiceNotFound(err) {
+			err = nil
+		}
+	case lagging:
+		err = backend.Registry.Register(ctx, service)
+	default:
+		if _, err = backend.Registry.HealthCheck(ctx, serviceID); !IsServiceNotFound(err) {
+			return // 服务仍在，或注册中心暂时不可用时等待下个周期
+		}
+		err = backend.Registry.Register(ctx, service)
+	}
+	if err != nil {
+		recordBackendRepair(backend.Name, "error")
+	} else {
+		recordBackendRepair(backend.Name, "ok")
+	}
+
+	m.mu.Lock()
+	m.markLagging(i, serviceID, err != nil)
+	m.mu.Unlock()
+}
+
+// Discover 查询所有注册中心并合并结果，任一注册中心成功即返回；全部失败时返回第一个注册中心的错误
+func (m *MultiRegistry) Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	results := make([][]*ServiceInfo, len(m.backends))
+	errs := make([]error, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		wg.Add(1)
+		go func(i int, reg ServiceRegistry) {
+			defer wg.Done()
+			results[i], errs[i] = reg.Discover(ctx, serviceName)
+		}(i, backend.Registry)
+	}
+	wg.Wait()
+
+	if err := allFailed(errs); err != nil {
+		return nil, err
+	}
+	return mergeServices(results), nil
+}
+
+// HealthCheck 按顺序检查各注册中心，返回第一个成功的结果；全部失败时返回第一个注册中心的错误
+func (m *MultiRegistry) HealthCheck(ctx context.Context, serviceID string) (HealthStatus, error) {
+	var first error
+	for _, backend := range m.backends {
+		status, err := backend.Registry.HealthCheck(ctx, serviceID)
+		if err == nil {
+			return status, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return HealthStatusUnknown, first
+}
+
+// Heartbeat 向需要心跳的注册中心发送心跳，实例丢失的注册中心立即以 Register 的服务重新注册；
+// 成功数少于法定数时返回错误，所有注册中心都找不到本实例未注册的服务时返回 ErrServiceNotFound
+func (m *MultiRegistry) Heartbeat(ctx context.Context, serviceID string) error {
+	m.mu.Lock()
+	service := m.services[serviceID]
+	m.mu.Unlock()
+
+	errs := make([]error, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		hb, ok := backend.Registry.(Heartbeater)
+		if !ok {
+			continue // 自行续约的注册中心（如 EtcdRegistry）由后台修复检查
+		}
+		wg.Add(1)
+		go func(i int, hb Heartbeater) {
+			defer wg.Done()
+			errs[i] = hb.Heartbeat(ctx, serviceID)
+		}(i, hb)
+	}
+	wg.Wait()
+
+	notFound := 0
+	for i, err := range errs {
+		if !IsServiceNotFound(err) {
+			continue
+		}
+		notFound++
+		if service != nil {
+			m.writeMu.Lock()
+			errs[i] = m.backends[i].Registry.Register(ctx, service)
+			m.mu.Lock()
+			m.markLagging(i, serviceID, errs[i] != nil)
+			m.mu.Unlock()
+			m.writeMu.Unlock()
+		}
+	}
+	if service == nil && notFound == len(m.backends) {
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, serviceID)
+	}
+	return m.checkQuorum("heartbeat", serviceID, errs)
+}
+
+// Watch 监听所有注册中心的服务变化，任一注册中心通知时以合并后的实例列表调用回调；
+// 至少一个注册中心监听成功即返回 nil
+func (m *MultiRegistry) Watch(ctx context.Context, serviceName string, callback func([]*ServiceInfo)) error {
+	if callback == nil {
+		return fmt.Errorf("callback is nil")
+	}
+	var mu sync.Mutex
+	latest := make([][]*ServiceInfo, len(m.backends))
+	errs := make([]error, len(m.backends))
+	for i, backend := range m.backends {
+		i := i
+		if services, err := backend.Registry.Discover(ctx, serviceName); err == nil {
+			latest[i] = services
+		}
+		errs[i] = backend.Registry.Watch(ctx, serviceName, func(services []*ServiceInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			latest[i] = services
+			callback(mergeServices(latest))
+		})
+	}
+	return allFailed(errs)
+}
+
+// DiscoverByPrefix 按服务名前缀查询所有注册中心并合并结果，注册中心需实现 PatternDiscoverer
+func (m *MultiRegistry) DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error) {
+	results := make([]map[string][]*ServiceInfo, len(m.backends))
+	errs := make([]error, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		wg.Add(1)
+		go func(i int, reg ServiceRegistry) {
+			defer wg.Done()
+			results[i], errs[i] = DiscoverByPrefix(ctx, reg, prefix)
+		}(i, backend.Registry)
+	}
+	wg.Wait()
+
+	if err := allFailed(errs); err != nil {
+		return nil, err
+	}
+	return mergeGroups(results), nil
+}
+
+// WatchPattern 按模式监听所有注册中心并合并通知，注册中心需实现 PatternDiscoverer
+func (m *MultiRegistry) WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error {
+	if err := validatePattern(pattern, callback); err != nil {
+		return err
+	}
+	var mu sync.Mutex
+	latest := make([]map[string][]*ServiceInfo, len(m.backends))
+	errs := make([]error, len(m.backends))
+	for i, backend := range m.backends {
+		i := i
+		discoverer, ok := backend.Registry.(PatternDiscoverer)
+		if !ok {
+			errs[i] = fmt.Errorf("%w: %T", ErrPatternNotSupported, backend.Registry)
+			continue
+		}
+		if groups, err := discoverer.DiscoverByPrefix(ctx, patternPrefix(pattern)); err == nil {
+			latest[i] = filterPattern(pattern, groups)
+		}
+		errs[i] = discoverer.WatchPattern(ctx, pattern, func(groups map[string][]*ServiceInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			latest[i] = groups
+			callback(mergeGroups(latest))
+		})
+	}
+	return allFailed(errs)
+}
+
+// Close 停止后台修复并关闭所有注册中心
+func (m *MultiRegistry) Close() error {
+	m.cancel()
+	m.wg.Wait()
+	errs := make([]error, 0, len(m.backends))
+	for _, backend := range m.backends {
+		if err := backend.Registry.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// allFailed 所有注册中心都失败时返回第一个错误，否则返回 nil
+func allFailed(errs []error) error {
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errs[0]
+}
+
+// mergeServices 合并各注册中心的实例列表，同一实例 ID 取靠前的注册中心
+func mergeServices(lists [][]*ServiceInfo) []*ServiceInfo {
+	seen := make(map[string]bool)
+	var merged []*ServiceInfo
+	for _, list := range lists {
+		for _, service := range list {
+			if !seen[service.ID] {
+				seen[service.ID] = true
+				merged = append(merged, service)
+			}
+		}
+	}
+	return merged
+}
+
+// mergeGroups 按服务名合并各注册中心的分组
+func mergeGroups(results []map[string][]*ServiceInfo) map[string][]*ServiceInfo {
+	lists := make(map[string][][]*ServiceInfo)
+	for _, groups := range results {
+		for name, services := range groups {
+			lists[name] = append(lists[name], services)
+		}
+	}
+	merged := make(map[string][]*ServiceInfo, len(lists))
+	for name, list := range lists {
+		if services := mergeServices(list); len(services) > 0 {
+			merged[name] = services
+		}
+	}
+	return merged
+}
@@ -0,0 +1,153 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// outageRegistry 可模拟整体不可用的内存注册中心
+type outageRegistry struct {
+	*MemoryRegistry
+	down atomic.Bool
+}
+
+var errRegistryDown = errors.New("registry unavailable")
+
+func (f *outageRegistry) Register(ctx context.Context, service *ServiceInfo) error {
+	if f.down.Load() {
+		return errRegistryDown
+	}
+	return f.MemoryRegistry.Register(ctx, service)
+}
+
+func (f *outageRegistry) Deregister(ctx context.Context, serviceID string) error {
+	if f.down.Load() {
+		return errRegistryDown
+	}
+	return f.MemoryRegistry.Deregister(ctx, serviceID)
+}
+
+func (f *outageRegistry) Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	if f.down.Load() {
+		return nil, errRegistryDown
+	}
+	return f.MemoryRegistry.Discover(ctx, serviceName)
+}
+
+func (f *outageRegistry) HealthCheck(ctx context.Context, serviceID string) (HealthStatus, error) {
+	if f.down.Load() {
+		return HealthStatusUnknown, errRegistryDown
+	}
+	return f.MemoryRegistry.HealthCheck(ctx, serviceID)
+}
+
+func TestMultiRegistryQuorumAndRepair(t *testing.T) {
+	backends := make([]*outageRegistry, 3)
+	list := make([]RegistryBackend, 3)
+	for i := range backends {
+		backends[i] = &outageRegistry{MemoryRegistry: NewMemoryRegistry(nil)}
+		list[i] = RegistryBackend{Registry: backends[i]}
+	}
+	multi, err := NewMultiRegistry(list, &MultiRegistryConfig{RepairInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewMultiRegistry failed: %v", err)
+	}
+	defer multi.Close()
+	if multi.Quorum() != 2 {
+		t.Fatalf("Quorum = %d, want 2", multi.Quorum())
+	}
+
+	ctx := context.Background()
+	service := &ServiceInfo{ID: "order-1", Name: "order-service", Address: "10.0.0.1", Port: 8080}
+
+	// 一个注册中心不可用时仍达到法定数，发现不受影响
+	backends[2].down.Store(true)
+	if err := multi.Register(ctx, service); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if lagging := multi.Lagging(); len(lagging["registry-2"]) != 1 {
+		t.Errorf("Lagging = %v", lagging)
+	}
+	backends[0].down.Store(true)
+	if services, err := multi.Discover(ctx, "order-service"); err != nil || len(services) != 1 {
+		t.Fatalf("Discover = %v, %v", services, err)
+	}
+
+	// 两个不可用时未达到法定数
+	other := &ServiceInfo{ID: "order-2", Name: "order-service", Address: "10.0.0.2", Port: 8080}
+	if err := multi.Register(ctx, other); !errors.Is(err, ErrQuorumNotReached) {
+		t.Fatalf("Expected ErrQuorumNotReached, got %v", err)
+	}
+
+	// 恢复后后台修复落后的注册中心
+	backends[0].down.Store(false)
+	backends[2].down.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(multi.Lagging()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if lagging := multi.Lagging(); len(lagging) != 0 {
+		t.Fatalf("Expected backends repaired, still lagging: %v", lagging)
+	}
+	for i, backend := range backends {
+		if services, _ := backend.MemoryRegistry.Discover(ctx, "order-service"); len(services) != 2 {
+			t.Errorf("Backend %d has %d instances, want 2", i, len(services))
+		}
+	}
+
+	// 注册中心丢失数据后重新注册
+	backends[1].MemoryRegistry.Deregister(ctx, "order-1")
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := backends[1].MemoryRegistry.HealthCheck(ctx, "order-1"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := backends[1].MemoryRegistry.HealthCheck(ctx, "order-1"); err != nil {
+		t.Errorf("Expected order-1 re-registered on backend 1: %v", err)
+	}
+
+	// 注销失败的注册中心同样在后台修复
+	backends[2].down.Store(true)
+	if err := multi.Deregister(ctx, "order-1"); err != nil {
+		t.Fatalf("Deregister failed: %v", err)
+	}
+	backends[2].down.Store(false)
+	deadline = time.Now().Add(2 * time.Second)
+	for len(multi.Lagging()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := backends[2].MemoryRegistry.HealthCheck(ctx, "order-1"); !IsServiceNotFound(err) {
+		t.Errorf("Expected order-1 deregistered on backend 2, got %v", err)
+	}
+}
+
+func TestMultiRegistryWatchMerges(t *testing.T) {
+	a, b := NewMemoryRegistry(nil), NewMemoryRegistry(nil)
+	multi, err := NewMultiRegistry([]RegistryBackend{{Name: "a", Registry: a}, {Name: "b", Registry: b}}, &MultiRegistryConfig{Quorum: 1})
+	if err != nil {
+		t.Fatalf("NewMultiRegistry failed: %v", err)
+	}
+	defer multi.Close()
+
+	ctx := context.Background()
+	updates := make(chan []*ServiceInfo, 10)
+	if err := multi.Watch(ctx, "user-service", func(services []*ServiceInfo) { updates <- services }); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	a.Register(ctx, &ServiceInfo{ID: "user-1", Name: "user-service", Address: "10.0.0.1", Port: 8080})
+	<-updates
+	b.Register(ctx, &ServiceInfo{ID: "user-2", Name: "user-service", Address: "10.0.0.2", Port: 8080})
+	select {
+	case services := <-updates:
+		if len(services) != 2 {
+			t.Errorf("Expected merged instances from both registries, got %d", len(services))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for watch notification")
+	}
+}
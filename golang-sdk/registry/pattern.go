@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrPatternNotSupported 注册中心不支持按前缀查询或按模式监听时返回的错误
+var ErrPatternNotSupported = errors.New("registry does not support prefix discovery")
+
+// PatternDiscoverer 支持按服务名前缀查询和按模式监听的注册中心，用于仪表盘和网关在不知道确切服务名时列出一类服务；
+// MemoryRegistry、EtcdRegistry、TenantRegistry 和 ReadOnlyRegistry 实现
+type PatternDiscoverer interface {
+	// DiscoverByPrefix 查询服务名以 prefix 开头的所有服务实例，按服务名分组，prefix 为空时返回所有服务
+	DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error)
+
+	// WatchPattern 监听服务名匹配 pattern 的所有服务，任一匹配的服务变化时以全部匹配服务的实例（按服务名分组）调用回调；
+	// pattern 为 path.Match 语法的通配符，如 payment-*、order-v[12]
+	WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error
+}
+
+// DiscoverByPrefix 以注册中心的 PatternDiscoverer 实现按前缀查询服务，不支持时返回 ErrPatternNotSupported
+func DiscoverByPrefix(ctx context.Context, registry ServiceRegistry, prefix string) (map[string][]*ServiceInfo, error) {
+	discoverer, ok := registry.(PatternDiscoverer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrPatternNotSupported, registry)
+	}
+	return discoverer.DiscoverByPrefix(ctx, prefix)
+}
+
+// MatchServiceName 判断服务名是否匹配 path.Match 语法的模式，* 不匹配 /（租户隔离的服务名以 / 分隔租户）
+func MatchServiceName(pattern, serviceName string) bool {
+	matched, err := path.Match(pattern, serviceName)
+	return err == nil && matched
+}
+
+// validatePattern 校验 WatchPattern 的参数
+func validatePattern(pattern string, callback func(map[string][]*ServiceInfo)) error {
+	if pattern == "" {
+		return fmt.Errorf("pattern is empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	if callback == nil {
+		return fmt.Errorf("callback is nil")
+	}
+	return nil
+}
+
+// patternPrefix 返回模式中第一个通配符之前的字面前缀，只需查询和监听以其开头的服务
+func patternPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// escapePattern 转义字符串中的通配符，使其在模式中按字面匹配
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[\`, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// filterPattern 返回服务名匹配模式的分组
+func filterPattern(pattern string, groups map[string][]*ServiceInfo) map[string][]*ServiceInfo {
+	matched := make(map[string][]*ServiceInfo, len(groups))
+	for name, services := range groups {
+		if MatchServiceName(pattern, name) {
+			matched[name] = services
+		}
+	}
+	return matched
+}
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestMemoryRegistry_DiscoverByPrefix(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+	ctx := context.Background()
+
+	for _, s := range []*ServiceInfo{
+		{ID: "pay-a-1", Name: "payment-alipay", Address: "localhost", Port: 8080},
+		{ID: "pay-a-2", Name: "payment-alipay", Address: "localhost", Port: 8081},
+		{ID: "pay-w-1", Name: "payment-wechat", Address: "localhost", Port: 8082},
+		{ID: "order-1", Name: "order-service", Address: "localhost", Port: 8083},
+	} {
+		if err := memory.Register(ctx, s); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+
+	groups, err := DiscoverByPrefix(ctx, memory, "payment-")
+	if err != nil {
+		t.Fatalf("DiscoverByPrefix failed: %v", err)
+	}
+	if len(groups) != 2 || len(groups["payment-alipay"]) != 2 || len(groups["payment-wechat"]) != 1 {
+		t.Errorf("Unexpected groups: %v", groups)
+	}
+	if all, _ := memory.DiscoverByPrefix(ctx, ""); len(all) != 3 {
+		t.Errorf("Expected all 3 services for empty prefix, got %d", len(all))
+	}
+
+	// 只读和租户隔离的注册中心同样支持
+	readOnly, _ := NewReadOnlyRegistry(memory)
+	if groups, err := readOnly.DiscoverByPrefix(ctx, "order"); err != nil || len(groups) != 1 {
+		t.Errorf("Unexpected read-only groups: %v, %v", groups, err)
+	}
+	tenant, _ := NewTenantRegistry(memory, "tenant-a")
+	tenant.Register(ctx, &ServiceInfo{ID: "t-pay-1", Name: "payment-card", Address: "localhost", Port: 9000})
+	groups, err = tenant.DiscoverByPrefix(ctx, "payment-")
+	if err != nil || len(groups) != 1 || groups["payment-card"][0].Name != "payment-card" {
+		t.Errorf("Unexpected tenant groups: %v, %v", groups, err)
+	}
+
+	// 不支持的注册中心
+	if _, err := DiscoverByPrefix(ctx, struct{ ServiceRegistry }{memory}, "payment-"); !errors.Is(err, ErrPatternNotSupported) {
+		t.Errorf("Expected ErrPatternNotSupported, got %v", err)
+	}
+}
+
+func TestMemoryRegistry_WatchPattern(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+	ctx := context.Background()
+
+	if err := memory.WatchPattern(ctx, "payment-[", func(map[string][]*ServiceInfo) {}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+
+	notified := make(chan map[string][]*ServiceInfo, 8)
+	if err := memory.WatchPattern(ctx, "payment-*", func(groups map[string][]*ServiceInfo) { notified <- groups }); err != nil {
+		t.Fatalf("WatchPattern failed: %v", err)
+	}
+	tenant, _ := NewTenantRegistry(memory, "tenant-a")
+	tenantNotified := make(chan map[string][]*ServiceInfo, 8)
+	if err := tenant.WatchPattern(ctx, "payment-*", func(groups map[string][]*ServiceInfo) { tenantNotified <- groups }); err != nil {
+		t.Fatalf("WatchPattern failed: %v", err)
+	}
+
+	expect := func(ch chan map[string][]*ServiceInfo, want ...string) {
+		t.Helper()
+		select {
+		case groups := <-ch:
+			if len(groups) != len(want) {
+				t.Fatalf("Expected services %v, got %v", want, groups)
+			}
+			for _, name := range want {
+				if len(groups[name]) == 0 {
+					t.Fatalf("Expected services %v, got %v", want, groups)
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected notification with %v", want)
+		}
+	}
+
+	memory.Register(ctx, &ServiceInfo{ID: "pay-a-1", Name: "payment-alipay", Address: "localhost", Port: 8080})
+	expect(notified, "payment-alipay")
+	memory.Register(ctx, &ServiceInfo{ID: "pay-w-1", Name: "payment-wechat", Address: "localhost", Port: 8081})
+	expect(notified, "payment-alipay", "payment-wechat")
+
+	// 不匹配的服务不触发通知；租户的服务只通知租户的监听
+	memory.Register(ctx, &ServiceInfo{ID: "order-1", Name: "order-service", Address: "localhost", Port: 8082})
+	tenant.Register(ctx, &ServiceInfo{ID: "t-pay-1", Name: "payment-card", Address: "localhost", Port: 9000})
+	expect(tenantNotified, "payment-card")
+	memory.Deregister(ctx, "pay-a-1")
+	expect(notified, "payment-wechat")
+}
+
+// TestEtcdWatchPattern 测试 etcd 按模式监听：监听模式的字面前缀，变化时以所有匹配服务通知回调
+func TestEtcdWatchPattern(t *testing.T) {
+	fake := &fakeEtcd{watches: make(chan int64, 1), channels: make(chan chan clientv3.WatchResponse, 1)}
+	fake.set(10, &ServiceInfo{ID: "pay-a-1", Name: "payment-alipay"}, &ServiceInfo{ID: "order-1", Name: "order-service"})
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &EtcdRegistry{
+		kv:         fake,
+		watcher:    fake,
+		config:     DefaultEtcdRegistryConfig(),
+		watchers:   make(map[string][]*serviceWatcher),
+		dispatcher: newWatchDispatcher("etcd", 0, 0),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	defer r.Close()
+
+	groups, err := r.DiscoverByPrefix(ctx, "payment-")
+	if err != nil || len(groups) != 1 || len(groups["payment-alipay"]) != 1 {
+		t.Fatalf("Unexpected groups: %v, %v", groups, err)
+	}
+
+	notified := make(chan map[string][]*ServiceInfo, 4)
+	if err := r.WatchPattern(ctx, "payment-*", func(groups map[string][]*ServiceInfo) { notified <- groups }); err != nil {
+		t.Fatalf("WatchPattern failed: %v", err)
+	}
+	select {
+	case rev := <-fake.watches:
+		if rev != 11 {
+			t.Fatalf("watch started at revision %d, want 11", rev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected watch")
+	}
+	ch := make(chan clientv3.WatchResponse, 1)
+	fake.channels <- ch
+
+	fake.set(12, &ServiceInfo{ID: "pay-a-1", Name: "payment-alipay"}, &ServiceInfo{ID: "pay-w-1", Name: "payment-wechat"},
+		&ServiceInfo{ID: "order-1", Name: "order-service"})
+	ch <- clientv3.WatchResponse{
+		Header: etcdserverpb.ResponseHeader{Revision: 12},
+		Events: []*clientv3.Event{{Type: clientv3.EventTypePut, Kv: &mvccpb.KeyValue{Key: []byte("/services/payment-wechat/pay-w-1"), ModRevision: 12}}},
+	}
+	select {
+	case groups := <-notified:
+		if len(groups) != 2 || groups["order-service"] != nil {
+			t.Errorf("Expected the two payment services, got %v", groups)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected notification")
+	}
+}
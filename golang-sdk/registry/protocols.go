@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/protocol/router"
+)
+
+// MetadataPortPrefix 服务实例元数据中各协议监听端口的键前缀，如 port.grpc=9001；注册时键中的协议名称与 Protocols 一同规范化
+const MetadataPortPrefix = "port."
+
+// negotiateProtocol 返回 callerProtocols 中第一个实例也支持的协议的序号及实例声明的协议名称，没有兼容协议时序号为 -1
+//
+// 协议名称比较时忽略大小写和连字符，如 jsonrpc 与 JSON-RPC 相同；实例未声明协议时视为支持所有协议
+func negotiateProtocol(callerProtocols []adapter.ProtocolType, instanceProtocols []string) (int, string) {
+	if len(instanceProtocols) == 0 {
+		return 0, ""
+	}
+	for rank, protocol := range callerProtocols {
+		for _, declared := range instanceProtocols {
+			if protocolKey(declared) == protocolKey(string(protocol)) {
+				return rank, declared
+			}
+		}
+	}
+	return -1, ""
+}
+
+// ProtocolEndpoints 返回支持 protocol 协议的实例的端点，Port 为实例元数据中该协议的端口
+//
+// 未声明协议的实例视为支持所有协议；用于不经过 RegistryRouter 直接连接实例的客户端，如 grpcclient 的名称解析
+func ProtocolEndpoints(services []*ServiceInfo, protocol adapter.ProtocolType) []*router.ServiceEndpoint {
+	endpoints := make([]*router.ServiceEndpoint, 0, len(services))
+	for _, service := range services {
+		rank, declared := negotiateProtocol([]adapter.ProtocolType{protocol}, service.Protocols)
+		if rank < 0 {
+			continue
+		}
+		endpoints = append(endpoints, newEndpoint(service, protocol, protocolPort(service, declared)))
+	}
+	return endpoints
+}
+
+// protocolPort 返回实例中 protocol 协议的端口，元数据中没有该协议的端口时返回实例端口
+func protocolPort(service *ServiceInfo, protocol string) int {
+	if protocol == "" {
+		return service.Port
+	}
+	if port, err := strconv.Atoi(service.Metadata[MetadataPortPrefix+protocol]); err == nil && port > 0 {
+		return port
+	}
+	// 其他 SDK 直接写入注册中心的实例未经规范化
+	for key, value := range service.Metadata {
+		if name, ok := strings.CutPrefix(key, MetadataPortPrefix); ok && protocolKey(name) == protocolKey(protocol) {
+			if port, err := strconv.Atoi(value); err == nil && port > 0 {
+				return port
+			}
+		}
+	}
+	return service.Port
+}
+
+// protocolKey 返回比较协议名称使用的键
+func protocolKey(protocol string) string {
+	return strings.ToLower(strings.ReplaceAll(protocol, "-", ""))
+}
+
+// appendMissing 追加 values 中 list 还没有的值
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
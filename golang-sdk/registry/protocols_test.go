@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// TestRegistryRouterProtocols 测试按调用方支持的协议选择端点和协议
+func TestRegistryRouterProtocols(t *testing.T) {
+	ctx := context.Background()
+	route := func(t *testing.T, services []*ServiceInfo, protocols ...adapter.ProtocolType) (string, adapter.ProtocolType, int, error) {
+		t.Helper()
+		registry := NewMemoryRegistry(nil)
+		registryRouter := NewRegistryRouter(registry, nil)
+		defer registryRouter.Close()
+		registryRouter.SetProtocols(protocols...)
+		for _, service := range services {
+			service.Name = "protocol-service"
+			if err := registry.Register(ctx, service); err != nil {
+				t.Fatalf("Failed to register service: %v", err)
+			}
+		}
+		endpoint, err := registryRouter.Route(ctx, &adapter.InternalRequest{Service: "protocol-service", Method: "test"})
+		if err != nil {
+			return "", "", 0, err
+		}
+		return endpoint.ServiceId, endpoint.Protocol, endpoint.Port, nil
+	}
+
+	t.Run("优先 gRPC 并使用该协议的端口", func(t *testing.T) {
+		id, protocol, port, err := route(t, []*ServiceInfo{
+			{ID: "json-only", Address: "localhost", Port: 9001, Protocols: []string{"JSON-RPC"}},
+			{ID: "both", Address: "localhost", Port: 9002, Protocols: []string{"JSON-RPC", "gRPC"},
+				Metadata: map[string]string{MetadataPortPrefix + "gRPC": "9102"}},
+		}, adapter.ProtocolGRPC, adapter.ProtocolJSONRPC)
+		if err != nil {
+			t.Fatalf("Route failed: %v", err)
+		}
+		if id != "both" || protocol != adapter.ProtocolGRPC || port != 9102 {
+			t.Errorf("Expected both/gRPC/9102, got %s/%s/%d", id, protocol, port)
+		}
+	})
+
+	t.Run("回退到 JSON-RPC", func(t *testing.T) {
+		id, protocol, port, err := route(t, []*ServiceInfo{
+			{ID: "rest-only", Address: "localhost", Port: 9001, Protocols: []string{"REST"}},
+			{ID: "json", Address: "localhost", Port: 9002, Protocols: []string{"REST", "jsonrpc"}},
+		}, adapter.ProtocolGRPC, adapter.ProtocolJSONRPC)
+		if err != nil {
+			t.Fatalf("Route failed: %v", err)
+		}
+		if id != "json" || protocol != adapter.ProtocolJSONRPC || port != 9002 {
+			t.Errorf("Expected json/JSON-RPC/9002, got %s/%s/%d", id, protocol, port)
+		}
+	})
+
+	t.Run("未声明协议的实例视为兼容", func(t *testing.T) {
+		_, protocol, _, err := route(t, []*ServiceInfo{
+			{ID: "legacy", Address: "localhost", Port: 9001},
+		}, adapter.ProtocolJSONRPC)
+		if err != nil || protocol != adapter.ProtocolJSONRPC {
+			t.Errorf("Expected JSON-RPC endpoint, got %s, %v", protocol, err)
+		}
+	})
+
+	t.Run("没有兼容协议时返回协议错误", func(t *testing.T) {
+		_, _, _, err := route(t, []*ServiceInfo{
+			{ID: "grpc-only", Address: "localhost", Port: 9001, Protocols: []string{"gRPC"}},
+		}, adapter.ProtocolJSONRPC)
+		if fe, ok := errors.FromError(err); !ok || fe.Code != errors.ProtocolError {
+			t.Errorf("Expected ProtocolError, got %v", err)
+		}
+	})
+
+	t.Run("未设置协议时不限制", func(t *testing.T) {
+		_, protocol, port, err := route(t, []*ServiceInfo{
+			{ID: "rest-grpc", Address: "localhost", Port: 9001, Protocols: []string{"REST", "gRPC"}},
+		})
+		if err != nil || protocol != adapter.ProtocolGRPC || port != 9001 {
+			t.Errorf("Expected gRPC/9001, got %s/%d, %v", protocol, port, err)
+		}
+	})
+}
+
+// TestProtocolEndpoints 测试按协议筛选实例并使用该协议的端口
+func TestProtocolEndpoints(t *testing.T) {
+	services := []*ServiceInfo{
+		{ID: "a", Address: "10.0.0.1", Port: 8080, Protocols: []string{"jsonrpc", "grpc"}, Metadata: map[string]string{"port.grpc": "9090"}},
+		{ID: "b", Address: "10.0.0.2", Port: 8080, Protocols: []string{"jsonrpc"}},
+		{ID: "c", Address: "10.0.0.3", Port: 9091},
+	}
+	endpoints := ProtocolEndpoints(services, adapter.ProtocolGRPC)
+	if len(endpoints) != 2 {
+		t.Fatalf("Expected 2 gRPC endpoints, got %d", len(endpoints))
+	}
+	if endpoints[0].ServiceId != "a" || endpoints[0].Port != 9090 || endpoints[0].Protocol != adapter.ProtocolGRPC {
+		t.Errorf("endpoint a = %+v, want gRPC port 9090", endpoints[0])
+	}
+	if endpoints[1].ServiceId != "c" || endpoints[1].Port != 9091 {
+		t.Errorf("endpoint c = %+v, want instance port 9091", endpoints[1])
+	}
+}
@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly 只读注册中心拒绝注册和注销时返回的错误，经 errors.Is 匹配
+var ErrReadOnly = errors.New("registry is read-only")
+
+// ReadOnlyRegistry 只读的服务注册中心
+//
+// 包装任意 ServiceRegistry，只允许查询和监听，拒绝注册和注销，用于只需消费服务拓扑的边缘网关等不可信客户端。
+// 包装只能防止本进程误写；要阻止被攻破的客户端篡改拓扑，还需在注册中心侧授予只读权限，
+// 如为 etcd 用户分配只对命名空间前缀有 read 权限的角色（见 EtcdRegistryConfig.Username）
+type ReadOnlyRegistry struct {
+	registry ServiceRegistry
+}
+
+// NewReadOnlyRegistry 创建只读的服务注册中心
+func NewReadOnlyRegistry(registry ServiceRegistry) (*ReadOnlyRegistry, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+	return &ReadOnlyRegistry{registry: registry}, nil
+}
+
+// IsReadOnly 判断注册中心是否只读，只读时调用方（如 framework.Server）不注册自身实例
+func IsReadOnly(registry ServiceRegistry) bool {
+	readOnly, ok := registry.(interface{ ReadOnly() bool })
+	return ok && readOnly.ReadOnly()
+}
+
+// ReadOnly 返回 true
+func (r *ReadOnlyRegistry) ReadOnly() bool {
+	return true
+}
+
+// Register 拒绝注册，返回 ErrReadOnly
+func (r *ReadOnlyRegistry) Register(ctx context.Context, service *ServiceInfo) error {
+	if service == nil {
+		return fmt.Errorf("%w: cannot register service", ErrReadOnly)
+	}
+	return fmt.Errorf("%w: cannot register service %s", ErrReadOnly, service.ID)
+}
+
+// Deregister 拒绝注销，返回 ErrReadOnly
+func (r *ReadOnlyRegistry) Deregister(ctx context.Context, serviceID string) error {
+	return fmt.Errorf("%w: cannot deregister service %s", ErrReadOnly, serviceID)
+}
+
+// Discover 查询服务
+func (r *ReadOnlyRegistry) Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	return r.registry.Discover(ctx, serviceName)
+}
+
+// HealthCheck 健康检查
+func (r *ReadOnlyRegistry) HealthCheck(ctx context.Context, serviceID string) (HealthStatus, error) {
+	return r.registry.HealthCheck(ctx, serviceID)
+}
+
+// Watch 监听服务变化
+func (r *ReadOnlyRegistry) Watch(ctx context.Context, serviceName string, callback func([]*ServiceInfo)) error {
+	return r.registry.Watch(ctx, serviceName, callback)
+}
+
+// DiscoverByPrefix 按服务名前缀查询服务，底层注册中心需实现 PatternDiscoverer
+func (r *ReadOnlyRegistry) DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error) {
+	return DiscoverByPrefix(ctx, r.registry, prefix)
+}
+
+// WatchPattern 按模式监听服务，底层注册中心需实现 PatternDiscoverer
+func (r *ReadOnlyRegistry) WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error {
+	discoverer, ok := r.registry.(PatternDiscoverer)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrPatternNotSupported, r.registry)
+	}
+	return discoverer.WatchPattern(ctx, pattern, callback)
+}
+
+// Close 关闭底层注册中心
+func (r *ReadOnlyRegistry) Close() error {
+	return r.registry.Close()
+}
@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestReadOnlyRegistry 测试只读注册中心允许查询和监听、拒绝注册和注销
+func TestReadOnlyRegistry(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+	ctx := context.Background()
+
+	if _, err := NewReadOnlyRegistry(nil); err == nil {
+		t.Error("Expected error for nil registry")
+	}
+	readOnly, err := NewReadOnlyRegistry(memory)
+	if err != nil {
+		t.Fatalf("Failed to create read-only registry: %v", err)
+	}
+	if !IsReadOnly(readOnly) || IsReadOnly(memory) {
+		t.Error("Expected only the wrapper to be read-only")
+	}
+
+	service := &ServiceInfo{ID: "order-1", Name: "order-service", Address: "localhost", Port: 8080}
+	if err := readOnly.Register(ctx, service); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly from Register, got %v", err)
+	}
+	if err := memory.Register(ctx, service); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+	if err := readOnly.Deregister(ctx, service.ID); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly from Deregister, got %v", err)
+	}
+
+	services, err := readOnly.Discover(ctx, "order-service")
+	if err != nil || len(services) != 1 {
+		t.Fatalf("Expected the registered service, got %v, %v", services, err)
+	}
+	if status, err := readOnly.HealthCheck(ctx, service.ID); err != nil || status != HealthStatusHealthy {
+		t.Errorf("Expected healthy, got %s, %v", status, err)
+	}
+
+	// 其他进程的变更通过只读注册中心送达监听者
+	notified := make(chan int, 4)
+	if err := readOnly.Watch(ctx, "order-service", func(services []*ServiceInfo) { notified <- len(services) }); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	memory.Register(ctx, &ServiceInfo{ID: "order-2", Name: "order-service", Address: "localhost", Port: 8081})
+	for {
+		select {
+		case n := <-notified:
+			if n == 2 {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected watch notification")
+		}
+	}
+}
@@ -2,20 +2,25 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrServiceNotFound 服务实例未注册或已过期，注销、健康检查和心跳返回的错误经 errors.Is 匹配
+var ErrServiceNotFound = errors.New("service not found")
+
 // ServiceInfo 服务信息
 type ServiceInfo struct {
-	ID           string            // 服务实例 ID
-	Name         string            // 服务名称
-	Version      string            // 服务版本
-	Language     string            // 编程语言
-	Address      string            // 服务地址
-	Port         int               // 服务端口
-	Protocols    []string          // 支持的协议
-	Metadata     map[string]string // 元数据
-	RegisteredAt time.Time         // 注册时间
+	ID             string            // 服务实例 ID
+	Name           string            // 服务名称
+	Version        string            // 服务版本
+	Language       string            // 编程语言
+	Address        string            // 服务地址
+	Port           int               // 服务端口
+	Protocols      []string          // 支持的协议
+	Serializations []string          // 支持的序列化格式，为空时视为只支持 json
+	Metadata       map[string]string // 元数据
+	RegisteredAt   time.Time         // 注册时间
 }
 
 // HealthStatus 健康状态
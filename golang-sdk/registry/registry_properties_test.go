@@ -83,7 +83,7 @@ func TestServiceRegistrationDiscoverability(t *testing.T) {
 		},
 		gen.Identifier(),
 		gen.Identifier(),
-		gen.RegexMatch("v[0-9]+\\.[0-9]+\\.[0-9]+"),
+		gen.RegexMatch("v(0|[1-9][0-9]{0,2})\\.(0|[1-9][0-9]{0,2})\\.(0|[1-9][0-9]{0,2})"),
 		gen.RegexMatch("[0-9]{1,3}\\.[0-9]{1,3}\\.[0-9]{1,3}\\.[0-9]{1,3}"),
 		gen.UInt16Range(1024, 65535),
 	))
@@ -313,7 +313,7 @@ func TestServiceRegistrationDiscoverability(t *testing.T) {
 		},
 		gen.Identifier(),
 		gen.Identifier(),
-		gen.RegexMatch("v[0-9]+\\.[0-9]+\\.[0-9]+"),
+		gen.RegexMatch("v(0|[1-9][0-9]{0,2})\\.(0|[1-9][0-9]{0,2})\\.(0|[1-9][0-9]{0,2})"),
 	))
 
 	properties.Property("Healthy service returns healthy status", prop.ForAll(
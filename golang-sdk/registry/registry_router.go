@@ -4,18 +4,40 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/framework/golang-sdk/protocol/adapter"
 	"github.com/framework/golang-sdk/protocol/router"
 )
 
 // RegistryRouter 集成服务注册的路由器
+//
+// 首次路由到某个服务时自动监听该服务的变化，并在内存中维护端点快照，之后的路由直接使用快照而不再查询注册中心。
+// 通过 SetProtocols 设置调用方支持的协议后，只路由到支持其中某个协议的实例，并优先使用排在前面的协议
 type RegistryRouter struct {
 	registry     ServiceRegistry
 	router       router.MessageRouter
 	loadBalancer router.LoadBalancer
 	mu           sync.RWMutex
-	watchers     map[string]context.CancelFunc // serviceName -> cancel function
+	watchers     map[string]*serviceWatch     // serviceName -> watch
+	snapshots    map[string]*endpointSnapshot // serviceName -> 端点快照
+	// tenantIsolation 启用后按请求元数据中的租户ID在租户命名空间内发现服务
+	tenantIsolation bool
+	// protocols 调用方支持的协议，按优先级排列；为空时不限制协议
+	protocols []adapter.ProtocolType
+}
+
+// endpointSnapshot 服务的端点快照
+type endpointSnapshot struct {
+	// endpoints 可路由的端点，设置了调用方协议时只包含使用最优兼容协议的端点
+	endpoints []*router.ServiceEndpoint
+	// incompatible 没有兼容协议的实例支持的协议，用于说明路由失败的原因
+	incompatible []string
+}
+
+// serviceWatch 对一个服务的监听，取消或被替换后忽略其回调
+type serviceWatch struct {
+	cancel context.CancelFunc
 }
 
 // NewRegistryRouter 创建集成服务注册的路由器
@@ -28,12 +50,25 @@ func NewRegistryRouter(registry ServiceRegistry, loadBalancer router.LoadBalance
 		registry:     registry,
 		router:       router.NewDefaultMessageRouter(loadBalancer),
 		loadBalancer: loadBalancer,
-		watchers:     make(map[string]context.CancelFunc),
+		watchers:     make(map[string]*serviceWatch),
+		snapshots:    make(map[string]*endpointSnapshot),
 	}
 }
 
 // Route 路由消息到目标服务
-func (rr *RegistryRouter) Route(ctx context.Context, request *adapter.InternalRequest) (*router.ServiceEndpoint, error) {
+func (rr *RegistryRouter) Route(ctx context.Context, request *adapter.InternalRequest) (endpoint *router.ServiceEndpoint, err error) {
+	ctx, span := adapter.StartInternalSpan(ctx, "router.Route")
+	start := time.Now()
+	var serviceName string
+	defer func() {
+		adapter.EndSpan(span, err)
+		var method string
+		if request != nil {
+			method = request.Method
+		}
+		router.RecordRoute(serviceName, method, endpoint, err, time.Since(start))
+	}()
+
 	if request == nil {
 		return nil, &adapter.FrameworkError{
 			Code:    adapter.ErrorBadRequest,
@@ -41,8 +76,17 @@ func (rr *RegistryRouter) Route(ctx context.Context, request *adapter.InternalRe
 		}
 	}
 
-	// 从注册中心查询服务
-	services, err := rr.registry.Discover(ctx, request.Service)
+	// 从端点快照或注册中心查询服务
+	serviceName, err = rr.resolveServiceName(request)
+	if err != nil {
+		return nil, &adapter.FrameworkError{
+			Code:    adapter.ErrorBadRequest,
+			Message: err.Error(),
+			Cause:   err,
+		}
+	}
+	span.SetAttributes(adapter.AttrService.String(serviceName), adapter.AttrMethod.String(request.Method))
+	snapshot, err := rr.endpoints(ctx, serviceName)
 	if err != nil {
 		return nil, &adapter.FrameworkError{
 			Code:    adapter.ErrorNotFound,
@@ -51,28 +95,23 @@ func (rr *RegistryRouter) Route(ctx context.Context, request *adapter.InternalRe
 		}
 	}
 
-	if len(services) == 0 {
+	endpoints := snapshot.endpoints
+	if len(endpoints) == 0 && len(snapshot.incompatible) > 0 {
 		return nil, &adapter.FrameworkError{
-			Code:    adapter.ErrorNotFound,
-			Message: fmt.Sprintf("no available instances for service: %s", request.Service),
+			Code: adapter.ErrorProtocol,
+			Message: fmt.Sprintf("no instance of service %s supports protocols %v, available protocols: %v",
+				request.Service, rr.callerProtocols(), snapshot.incompatible),
 		}
 	}
-
-	// 转换为 ServiceEndpoint
-	endpoints := make([]*router.ServiceEndpoint, 0, len(services))
-	for _, service := range services {
-		endpoint := &router.ServiceEndpoint{
-			ServiceId: service.ID,
-			Address:   service.Address,
-			Port:      service.Port,
-			Protocol:  rr.selectProtocol(service.Protocols),
-			Metadata:  service.Metadata,
+	if len(endpoints) == 0 {
+		return nil, &adapter.FrameworkError{
+			Code:    adapter.ErrorNotFound,
+			Message: fmt.Sprintf("no available instances for service: %s", request.Service),
 		}
-		endpoints = append(endpoints, endpoint)
 	}
 
 	// 使用负载均衡器选择端点
-	endpoint, err := rr.loadBalancer.Select(endpoints)
+	endpoint, err = router.SelectEndpoint(ctx, rr.loadBalancer, endpoints)
 	if err != nil {
 		return nil, &adapter.FrameworkError{
 			Code:    adapter.ErrorRouting,
@@ -84,96 +123,262 @@ func (rr *RegistryRouter) Route(ctx context.Context, request *adapter.InternalRe
 	return endpoint, nil
 }
 
-// RegisterService 注册服务
-func (rr *RegistryRouter) RegisterService(ctx context.Context, service *ServiceInfo) error {
-	return rr.registry.Register(ctx, service)
+// ReportResult 向负载均衡器报告对 Route 选出的端点的调用结果，负载均衡器实现 router.ResultReporter 时
+// （如 WeightedRoundRobinLoadBalancer）据此调整端点的有效权重
+func (rr *RegistryRouter) ReportResult(endpoint *router.ServiceEndpoint, latency time.Duration, failed bool) {
+	router.ReportResult(rr.loadBalancer, endpoint, latency, failed)
 }
 
-// DeregisterService 注销服务
-func (rr *RegistryRouter) DeregisterService(ctx context.Context, serviceID string) error {
-	return rr.registry.Deregister(ctx, serviceID)
+// SetTenantIsolation 设置是否启用租户隔离的服务发现
+func (rr *RegistryRouter) SetTenantIsolation(enabled bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.tenantIsolation = enabled
 }
 
-// WatchService 监听服务变化
-func (rr *RegistryRouter) WatchService(ctx context.Context, serviceName string) error {
+// SetProtocols 设置调用方支持的协议，按优先级排列，如 gRPC 优先、JSON-RPC 其次
+//
+// 设置后只路由到支持其中某个协议的实例，端点的 Protocol 为协商出的协议，Port 为实例元数据中该协议的端口；
+// 有实例但都不支持这些协议时 Route 返回 ProtocolError。未声明协议的实例视为支持所有协议。不调用或 protocols 为空时不限制协议
+func (rr *RegistryRouter) SetProtocols(protocols ...adapter.ProtocolType) {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
+	rr.protocols = append([]adapter.ProtocolType(nil), protocols...)
+	// 快照按调用方协议建立，丢弃后在下次路由时重新查询
+	rr.snapshots = make(map[string]*endpointSnapshot)
+}
 
-	// 如果已经在监听，先取消
-	if cancel, exists := rr.watchers[serviceName]; exists {
-		cancel()
+// callerProtocols 返回调用方支持的协议
+func (rr *RegistryRouter) callerProtocols() []adapter.ProtocolType {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.protocols
+}
+
+// resolveServiceName 解析实际用于发现的服务名称
+//
+// 租户标识由适配器从认证后的安全上下文写入元数据，不合法的租户标识返回错误，避免拼出其他租户的服务名
+func (rr *RegistryRouter) resolveServiceName(request *adapter.InternalRequest) (string, error) {
+	rr.mu.RLock()
+	tenantIsolation := rr.tenantIsolation
+	rr.mu.RUnlock()
+
+	if !tenantIsolation || request.Metadata == nil {
+		return request.Service, nil
 	}
 
-	// 创建新的监听上下文
-	watchCtx, cancel := context.WithCancel(ctx)
-	rr.watchers[serviceName] = cancel
+	tenantID := request.Metadata[adapter.MetadataTenantID]
+	if tenantID == "" {
+		return request.Service, nil
+	}
+	if err := ValidateTenantID(tenantID); err != nil {
+		return "", err
+	}
+	return TenantServiceName(tenantID, request.Service), nil
+}
 
-	// 启动监听
-	return rr.registry.Watch(watchCtx, serviceName, func(services []*ServiceInfo) {
-		// 更新路由表
-		endpoints := make(map[string][]*router.ServiceEndpoint)
-		serviceEndpoints := make([]*router.ServiceEndpoint, 0, len(services))
+// RegisterService 注册服务
+func (rr *RegistryRouter) RegisterService(ctx context.Context, service *ServiceInfo) error {
+	return rr.registry.Register(ctx, service)
+}
 
-		for _, service := range services {
-			endpoint := &router.ServiceEndpoint{
-				ServiceId: service.ID,
-				Address:   service.Address,
-				Port:      service.Port,
-				Protocol:  rr.selectProtocol(service.Protocols),
-				Metadata:  service.Metadata,
-			}
-			serviceEndpoints = append(serviceEndpoints, endpoint)
-		}
-
-		endpoints[serviceName] = serviceEndpoints
-		_ = rr.router.UpdateRoutingTable(endpoints)
-	})
+// DeregisterService 注销服务
+func (rr *RegistryRouter) DeregisterService(ctx context.Context, serviceID string) error {
+	return rr.registry.Deregister(ctx, serviceID)
 }
 
-// StopWatchService 停止监听服务变化
+// WatchService 监听服务变化，已在监听时重新监听
+//
+// 路由时会自动监听，通常无需调用；ctx 取消后停止监听并丢弃端点快照
+func (rr *RegistryRouter) WatchService(ctx context.Context, serviceName string) error {
+	return rr.watch(ctx, serviceName)
+}
+
+// StopWatchService 停止监听服务变化并丢弃端点快照，之后路由到该服务时重新查询注册中心并监听
 func (rr *RegistryRouter) StopWatchService(serviceName string) {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
 
-	if cancel, exists := rr.watchers[serviceName]; exists {
-		cancel()
+	if w, exists := rr.watchers[serviceName]; exists {
+		w.cancel()
 		delete(rr.watchers, serviceName)
 	}
+	delete(rr.snapshots, serviceName)
 }
 
 // Close 关闭路由器
 func (rr *RegistryRouter) Close() error {
 	// 停止所有监听
 	rr.mu.Lock()
-	for _, cancel := range rr.watchers {
-		cancel()
+	for _, w := range rr.watchers {
+		w.cancel()
 	}
-	rr.watchers = make(map[string]context.CancelFunc)
+	rr.watchers = make(map[string]*serviceWatch)
+	rr.snapshots = make(map[string]*endpointSnapshot)
 	rr.mu.Unlock()
 
 	// 关闭注册中心连接
 	return rr.registry.Close()
 }
 
-// selectProtocol 选择协议
+// endpoints 返回服务的端点，优先使用端点快照
+//
+// 没有快照时开始监听服务（已在监听则跳过），再查询注册中心建立快照；监听失败时不建立快照，每次路由都查询注册中心
+func (rr *RegistryRouter) endpoints(ctx context.Context, serviceName string) (*endpointSnapshot, error) {
+	rr.mu.RLock()
+	snapshot, cached := rr.snapshots[serviceName]
+	_, watching := rr.watchers[serviceName]
+	rr.mu.RUnlock()
+	if cached {
+		return snapshot, nil
+	}
+
+	// 先监听再查询，查询期间的变化不会丢失
+	if !watching {
+		watching = rr.watch(context.Background(), serviceName) == nil
+	}
+
+	services, err := rr.registry.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	snapshot = rr.newSnapshot(services)
+	if !watching {
+		return snapshot, nil
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if _, exists := rr.watchers[serviceName]; !exists {
+		return snapshot, nil
+	}
+	// 查询期间监听回调已更新快照时以回调的结果为准
+	if existing, exists := rr.snapshots[serviceName]; exists {
+		return existing, nil
+	}
+	rr.snapshots[serviceName] = snapshot
+	return snapshot, nil
+}
+
+// watch 监听服务变化，变化时更新端点快照和路由表
+func (rr *RegistryRouter) watch(ctx context.Context, serviceName string) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	w := &serviceWatch{cancel: cancel}
+
+	rr.mu.Lock()
+	// 如果已经在监听，先取消
+	if previous, exists := rr.watchers[serviceName]; exists {
+		previous.cancel()
+	}
+	rr.watchers[serviceName] = w
+	rr.mu.Unlock()
+	// ctx 取消后快照不再更新，丢弃快照，之后路由时重新监听
+	context.AfterFunc(watchCtx, func() { rr.forget(serviceName, w) })
+
+	// 注册中心可能不会因 ctx 取消而移除回调，回调中按 watch 是否仍有效决定是否更新
+	err := rr.registry.Watch(watchCtx, serviceName, func(services []*ServiceInfo) {
+		if watchCtx.Err() != nil {
+			return
+		}
+		snapshot := rr.newSnapshot(services)
+
+		rr.mu.Lock()
+		if rr.watchers[serviceName] != w {
+			rr.mu.Unlock()
+			return
+		}
+		rr.snapshots[serviceName] = snapshot
+		rr.mu.Unlock()
+
+		// 更新路由表
+		_ = rr.router.UpdateRoutingTable(map[string][]*router.ServiceEndpoint{serviceName: snapshot.endpoints})
+	})
+	if err != nil {
+		cancel()
+	}
+	return err
+}
+
+// forget 监听 w 仍有效时移除监听和端点快照
+func (rr *RegistryRouter) forget(serviceName string, w *serviceWatch) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.watchers[serviceName] == w {
+		delete(rr.watchers, serviceName)
+		delete(rr.snapshots, serviceName)
+	}
+}
+
+// newSnapshot 按调用方支持的协议将服务实例转换为端点快照
+func (rr *RegistryRouter) newSnapshot(services []*ServiceInfo) *endpointSnapshot {
+	protocols := rr.callerProtocols()
+	snapshot := &endpointSnapshot{endpoints: make([]*router.ServiceEndpoint, 0, len(services))}
+	if len(protocols) == 0 {
+		for _, service := range services {
+			snapshot.endpoints = append(snapshot.endpoints, newEndpoint(service, rr.selectProtocol(service.Protocols), service.Port))
+		}
+		return snapshot
+	}
+
+	// 只保留使用最优兼容协议的实例，如部分实例支持 gRPC 时不路由到只支持 JSON-RPC 的实例
+	best := len(protocols)
+	for _, service := range services {
+		rank, declared := negotiateProtocol(protocols, service.Protocols)
+		if rank < 0 {
+			snapshot.incompatible = appendMissing(snapshot.incompatible, service.Protocols...)
+			continue
+		}
+		if rank > best {
+			continue
+		}
+		if rank < best {
+			best = rank
+			snapshot.endpoints = snapshot.endpoints[:0]
+		}
+		snapshot.endpoints = append(snapshot.endpoints, newEndpoint(service, protocols[rank], protocolPort(service, declared)))
+	}
+	return snapshot
+}
+
+// newEndpoint 创建使用 protocol 协议的端点
+func newEndpoint(service *ServiceInfo, protocol adapter.ProtocolType, port int) *router.ServiceEndpoint {
+	return &router.ServiceEndpoint{
+		ServiceId:      service.ID,
+		Address:        service.Address,
+		Port:           port,
+		Protocol:       protocol,
+		Metadata:       service.Metadata,
+		Serializations: service.Serializations,
+		Language:       service.Language,
+	}
+}
+
+// selectProtocol 选择协议，协议名称比较时忽略大小写和连字符
 func (rr *RegistryRouter) selectProtocol(protocols []string) adapter.ProtocolType {
 	// 优先选择 gRPC
 	for _, p := range protocols {
-		if p == string(adapter.ProtocolGRPC) {
+		if protocolKey(p) == protocolKey(string(adapter.ProtocolGRPC)) {
 			return adapter.ProtocolGRPC
 		}
 	}
 
-	// 其次选择内部 RPC
+	// 其次选择 JSON-RPC
+	for _, p := range protocols {
+		if protocolKey(p) == protocolKey(string(adapter.ProtocolJSONRPC)) {
+			return adapter.ProtocolJSONRPC
+		}
+	}
+
+	// 再次选择内部 RPC
 	for _, p := range protocols {
-		if p == string(adapter.ProtocolInternalRPC) {
+		if protocolKey(p) == protocolKey(string(adapter.ProtocolInternalRPC)) {
 			return adapter.ProtocolInternalRPC
 		}
 	}
 
 	// 默认使用自定义二进制协议
 	if len(protocols) > 0 {
-		return adapter.ProtocolType(protocols[0])
+		return protocolType(protocols[0])
 	}
 
 	return adapter.ProtocolGRPC
@@ -0,0 +1,198 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TenantMetadataKey 服务元数据中的租户标识键
+const TenantMetadataKey = "tenant_id"
+
+// TenantServiceName 返回租户隔离后的服务名称
+//
+// 服务名以租户ID为前缀，etcd 中对应 namespace/tenant/service/id，实现命名空间级隔离
+func TenantServiceName(tenantID, serviceName string) string {
+	if tenantID == "" {
+		return serviceName
+	}
+	return tenantID + "/" + serviceName
+}
+
+// ValidateTenantID 校验租户ID，租户ID作为服务名的前缀，不能为空，不能含 / 或空白字符，
+// 否则可以借此构造出其他租户命名空间下的服务名
+func ValidateTenantID(tenantID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID is empty")
+	}
+	if strings.ContainsAny(tenantID, "/ \t\r\n") {
+		return fmt.Errorf("tenant ID cannot contain '/' or whitespace: %q", tenantID)
+	}
+	return nil
+}
+
+// TenantRegistry 租户隔离的服务注册中心
+//
+// 包装任意 ServiceRegistry，所有注册和发现都限定在单个租户的命名空间内；
+// 服务 ID 不带租户信息，Deregister 和 HealthCheck 先在底层注册中心的租户命名空间内查找实例并核对租户元数据，
+// 只作用于本租户的实例（包括重启前或同一租户的其他副本注册的实例），底层注册中心需实现 PatternDiscoverer
+type TenantRegistry struct {
+	registry ServiceRegistry
+	tenantID string
+}
+
+// NewTenantRegistry 创建租户隔离的服务注册中心
+func NewTenantRegistry(registry ServiceRegistry, tenantID string) (*TenantRegistry, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+
+	if err := ValidateTenantID(tenantID); err != nil {
+		return nil, err
+	}
+
+	return &TenantRegistry{
+		registry: registry,
+		tenantID: tenantID,
+	}, nil
+}
+
+// TenantID 返回租户ID
+func (t *TenantRegistry) TenantID() string {
+	return t.tenantID
+}
+
+// Register 在租户命名空间内注册服务
+func (t *TenantRegistry) Register(ctx context.Context, service *ServiceInfo) error {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	}
+
+	if service.Name == "" {
+		return fmt.Errorf("service name is empty")
+	}
+
+	scoped := *service
+	scoped.Name = TenantServiceName(t.tenantID, service.Name)
+	scoped.Metadata = make(map[string]string, len(service.Metadata)+1)
+	for k, v := range service.Metadata {
+		scoped.Metadata[k] = v
+	}
+	scoped.Metadata[TenantMetadataKey] = t.tenantID
+
+	return t.registry.Register(ctx, &scoped)
+}
+
+// Deregister 注销本租户的服务实例，其他租户的实例返回错误
+func (t *TenantRegistry) Deregister(ctx context.Context, serviceID string) error {
+	if err := t.owns(ctx, serviceID); err != nil {
+		return err
+	}
+	return t.registry.Deregister(ctx, serviceID)
+}
+
+// Discover 在租户命名空间内查询服务
+func (t *TenantRegistry) Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error) {
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name is empty")
+	}
+
+	services, err := t.registry.Discover(ctx, TenantServiceName(t.tenantID, serviceName))
+	if err != nil {
+		return nil, err
+	}
+
+	return t.unscope(services), nil
+}
+
+// HealthCheck 检查本租户的服务实例的健康状态，其他租户的实例返回错误
+func (t *TenantRegistry) HealthCheck(ctx context.Context, serviceID string) (HealthStatus, error) {
+	if err := t.owns(ctx, serviceID); err != nil {
+		return HealthStatusUnknown, err
+	}
+	return t.registry.HealthCheck(ctx, serviceID)
+}
+
+// owns 检查服务实例是否属于本租户：实例位于底层注册中心的租户命名空间内且租户元数据为本租户，
+// 不依赖本进程的注册记录；其他租户的实例与不存在的实例返回相同的错误
+func (t *TenantRegistry) owns(ctx context.Context, serviceID string) error {
+	groups, err := DiscoverByPrefix(ctx, t.registry, TenantServiceName(t.tenantID, ""))
+	if err != nil {
+		return err
+	}
+	for _, services := range groups {
+		for _, service := range services {
+			if service.ID == serviceID && service.Metadata[TenantMetadataKey] == t.tenantID {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s in tenant %s", ErrServiceNotFound, serviceID, t.tenantID)
+}
+
+// Watch 监听租户命名空间内的服务变化
+func (t *TenantRegistry) Watch(ctx context.Context, serviceName string, callback func([]*ServiceInfo)) error {
+	if serviceName == "" {
+		return fmt.Errorf("service name is empty")
+	}
+
+	if callback == nil {
+		return fmt.Errorf("callback is nil")
+	}
+
+	return t.registry.Watch(ctx, TenantServiceName(t.tenantID, serviceName), func(services []*ServiceInfo) {
+		callback(t.unscope(services))
+	})
+}
+
+// DiscoverByPrefix 在租户命名空间内按服务名前缀查询服务，底层注册中心需实现 PatternDiscoverer
+func (t *TenantRegistry) DiscoverByPrefix(ctx context.Context, prefix string) (map[string][]*ServiceInfo, error) {
+	groups, err := DiscoverByPrefix(ctx, t.registry, TenantServiceName(t.tenantID, prefix))
+	if err != nil {
+		return nil, err
+	}
+	return t.unscopeGroups(groups), nil
+}
+
+// WatchPattern 在租户命名空间内按模式监听服务，底层注册中心需实现 PatternDiscoverer
+func (t *TenantRegistry) WatchPattern(ctx context.Context, pattern string, callback func(map[string][]*ServiceInfo)) error {
+	if err := validatePattern(pattern, callback); err != nil {
+		return err
+	}
+	discoverer, ok := t.registry.(PatternDiscoverer)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrPatternNotSupported, t.registry)
+	}
+
+	// 租户 ID 不含 /，需转义通配符后作为模式的字面前缀
+	scoped := TenantServiceName(escapePattern(t.tenantID), pattern)
+	return discoverer.WatchPattern(ctx, scoped, func(groups map[string][]*ServiceInfo) {
+		callback(t.unscopeGroups(groups))
+	})
+}
+
+// Close 关闭底层注册中心
+func (t *TenantRegistry) Close() error {
+	return t.registry.Close()
+}
+
+// unscope 去除服务名称中的租户前缀
+func (t *TenantRegistry) unscope(services []*ServiceInfo) []*ServiceInfo {
+	prefix := t.tenantID + "/"
+	result := make([]*ServiceInfo, 0, len(services))
+	for _, service := range services {
+		unscoped := *service
+		unscoped.Name = strings.TrimPrefix(service.Name, prefix)
+		result = append(result, &unscoped)
+	}
+	return result
+}
+
+// unscopeGroups 去除按服务名分组的结果中的租户前缀
+func (t *TenantRegistry) unscopeGroups(groups map[string][]*ServiceInfo) map[string][]*ServiceInfo {
+	result := make(map[string][]*ServiceInfo, len(groups))
+	for name, services := range groups {
+		result[strings.TrimPrefix(name, t.tenantID+"/")] = t.unscope(services)
+	}
+	return result
+}
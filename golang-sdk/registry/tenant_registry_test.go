@@ -0,0 +1,179 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// TestTenantRegistryIsolation 测试租户之间的服务隔离
+func TestTenantRegistryIsolation(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+
+	ctx := context.Background()
+
+	tenantA, err := NewTenantRegistry(memory, "tenant-a")
+	if err != nil {
+		t.Fatalf("Failed to create tenant registry: %v", err)
+	}
+	tenantB, err := NewTenantRegistry(memory, "tenant-b")
+	if err != nil {
+		t.Fatalf("Failed to create tenant registry: %v", err)
+	}
+
+	service := &ServiceInfo{
+		ID:      "order-a-1",
+		Name:    "order-service",
+		Address: "localhost",
+		Port:    8080,
+	}
+	if err := tenantA.Register(ctx, service); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+
+	// 租户 A 可以发现服务，且服务名不带租户前缀
+	services, err := tenantA.Discover(ctx, "order-service")
+	if err != nil {
+		t.Fatalf("Failed to discover service: %v", err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+	if services[0].Name != "order-service" {
+		t.Errorf("Expected service name order-service, got %s", services[0].Name)
+	}
+	if services[0].Metadata[TenantMetadataKey] != "tenant-a" {
+		t.Errorf("Expected tenant metadata tenant-a, got %s", services[0].Metadata[TenantMetadataKey])
+	}
+
+	// 租户 B 看不到租户 A 的服务
+	services, err = tenantB.Discover(ctx, "order-service")
+	if err != nil {
+		t.Fatalf("Failed to discover service: %v", err)
+	}
+	if len(services) != 0 {
+		t.Errorf("Expected 0 services for tenant-b, got %d", len(services))
+	}
+
+	// 原始服务信息不应被修改
+	if service.Name != "order-service" || service.Metadata != nil {
+		t.Error("Register should not modify the caller's ServiceInfo")
+	}
+}
+
+// TestNewTenantRegistryValidation 测试租户注册中心参数校验
+func TestNewTenantRegistryValidation(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+
+	if _, err := NewTenantRegistry(nil, "tenant-a"); err == nil {
+		t.Error("Expected error for nil registry")
+	}
+	if _, err := NewTenantRegistry(memory, ""); err == nil {
+		t.Error("Expected error for empty tenant ID")
+	}
+	if _, err := NewTenantRegistry(memory, "a/b"); err == nil {
+		t.Error("Expected error for tenant ID containing '/'")
+	}
+	if _, err := NewTenantRegistry(memory, "a b"); err == nil {
+		t.Error("Expected error for tenant ID containing whitespace")
+	}
+}
+
+// TestTenantRegistryScopedDeregister 测试注销和健康检查限定在本租户注册的实例
+func TestTenantRegistryScopedDeregister(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	defer memory.Close()
+
+	ctx := context.Background()
+	tenantA, _ := NewTenantRegistry(memory, "tenant-a")
+	tenantB, _ := NewTenantRegistry(memory, "tenant-b")
+
+	if err := tenantA.Register(ctx, &ServiceInfo{ID: "order-a-1", Name: "order-service", Address: "localhost", Port: 8080}); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+
+	// 租户 B 不能检查或注销租户 A 的实例
+	if _, err := tenantB.HealthCheck(ctx, "order-a-1"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Expected ErrServiceNotFound for other tenant's health check, got %v", err)
+	}
+	if err := tenantB.Deregister(ctx, "order-a-1"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Expected ErrServiceNotFound for other tenant's deregister, got %v", err)
+	}
+	if services, _ := tenantA.Discover(ctx, "order-service"); len(services) != 1 {
+		t.Fatalf("Expected tenant-a instance to survive, got %d", len(services))
+	}
+
+	if _, err := tenantA.HealthCheck(ctx, "order-a-1"); err != nil {
+		t.Errorf("HealthCheck failed: %v", err)
+	}
+	// 同一租户的其他副本（或重启后的进程）同样可以检查和注销该实例，所属租户取自底层注册中心
+	replica, _ := NewTenantRegistry(memory, "tenant-a")
+	if status, err := replica.HealthCheck(ctx, "order-a-1"); err != nil || status != HealthStatusHealthy {
+		t.Errorf("Expected replica health check to succeed, got %v, %v", status, err)
+	}
+
+	// 租户命名空间内缺少租户元数据的实例不属于该租户
+	memory.Register(ctx, &ServiceInfo{ID: "order-raw-1", Name: TenantServiceName("tenant-a", "order-service"), Address: "localhost", Port: 8081})
+	if err := tenantA.Deregister(ctx, "order-raw-1"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Expected ErrServiceNotFound for instance without tenant metadata, got %v", err)
+	}
+	memory.Deregister(ctx, "order-raw-1")
+
+	if err := replica.Deregister(ctx, "order-a-1"); err != nil {
+		t.Fatalf("Deregister failed: %v", err)
+	}
+	if services, _ := tenantA.Discover(ctx, "order-service"); len(services) != 0 {
+		t.Errorf("Expected 0 services after deregister, got %d", len(services))
+	}
+}
+
+// TestRegistryRouterTenantIsolation 测试路由器按租户发现服务
+func TestRegistryRouterTenantIsolation(t *testing.T) {
+	memory := NewMemoryRegistry(DefaultMemoryRegistryConfig())
+	registryRouter := NewRegistryRouter(memory, nil)
+	defer registryRouter.Close()
+
+	ctx := context.Background()
+
+	tenantA, _ := NewTenantRegistry(memory, "tenant-a")
+	_ = tenantA.Register(ctx, &ServiceInfo{
+		ID:        "order-a-1",
+		Name:      "order-service",
+		Address:   "10.0.0.1",
+		Port:      8080,
+		Protocols: []string{"gRPC"},
+	})
+
+	registryRouter.SetTenantIsolation(true)
+
+	request := &adapter.InternalRequest{
+		Service:  "order-service",
+		Method:   "getOrder",
+		Metadata: map[string]string{adapter.MetadataTenantID: "tenant-a"},
+	}
+	endpoint, err := registryRouter.Route(ctx, request)
+	if err != nil {
+		t.Fatalf("Failed to route: %v", err)
+	}
+	if endpoint.ServiceId != "order-a-1" {
+		t.Errorf("Expected endpoint order-a-1, got %s", endpoint.ServiceId)
+	}
+
+	// 其他租户无法路由到该服务
+	request.Metadata[adapter.MetadataTenantID] = "tenant-b"
+	if _, err := registryRouter.Route(ctx, request); err == nil {
+		t.Error("Expected routing error for other tenant")
+	}
+
+	// 不合法的租户标识不能拼出其他租户的服务名
+	request.Metadata[adapter.MetadataTenantID] = "tenant-b/../tenant-a"
+	_, err = registryRouter.Route(ctx, request)
+	var fe *adapter.FrameworkError
+	if !errors.As(err, &fe) || fe.Code != adapter.ErrorBadRequest {
+		t.Errorf("Expected bad request for invalid tenant, got %v", err)
+	}
+}
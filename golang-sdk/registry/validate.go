@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// ErrInvalidServiceInfo 服务实例信息不合法，Register 返回的错误经 errors.Is 匹配
+var ErrInvalidServiceInfo = errors.New("invalid service info")
+
+// knownProtocols 各语言 SDK 可以声明的协议，键为规范化的协议名称
+var knownProtocols = map[string]adapter.ProtocolType{
+	protocolKey(string(adapter.ProtocolREST)):         adapter.ProtocolREST,
+	protocolKey(string(adapter.ProtocolWebSocket)):    adapter.ProtocolWebSocket,
+	protocolKey(string(adapter.ProtocolJSONRPC)):      adapter.ProtocolJSONRPC,
+	protocolKey(string(adapter.ProtocolMQTT)):         adapter.ProtocolMQTT,
+	protocolKey(string(adapter.ProtocolKafka)):        adapter.ProtocolKafka,
+	protocolKey(string(adapter.ProtocolGRPC)):         adapter.ProtocolGRPC,
+	protocolKey(string(adapter.ProtocolInternalRPC)):  adapter.ProtocolInternalRPC,
+	protocolKey(string(adapter.ProtocolCustomBinary)): adapter.ProtocolCustomBinary,
+	// PHP SDK 的客户端以 http 注册 JSON-RPC over HTTP 端点
+	"http": "HTTP",
+	// framework.Server 的外部协议 MQ（通用消息队列）和 gRPC-Web
+	"mq":      "MQ",
+	"grpcweb": "gRPC-Web",
+}
+
+// semverPattern 语义化版本，允许 v 前缀、预发布和构建标识，如 1.2.3、v2.0.0-rc.1+build.5
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// hostnamePattern 主机名的一个标签（RFC 1123）
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateServiceInfo 校验服务实例信息，返回包装 ErrInvalidServiceInfo 的错误并列出所有问题
+//
+// ID 和名称不能为空；地址为 IP 或主机名（不含端口和协议前缀）；端口在 1-65535 之间；
+// 协议名称忽略大小写和连字符后属于已知协议；版本为空或为语义化版本
+func ValidateServiceInfo(service *ServiceInfo) error {
+	if service == nil {
+		return fmt.Errorf("%w: service is nil", ErrInvalidServiceInfo)
+	}
+
+	var problems []string
+	if service.ID == "" {
+		problems = append(problems, "service ID is empty")
+	}
+	if service.Name == "" {
+		problems = append(problems, "service name is empty")
+	}
+	if !validAddress(service.Address) {
+		problems = append(problems, fmt.Sprintf("address %q is not an IP address or hostname", service.Address))
+	}
+	if service.Port < 1 || service.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("port %d out of range 1-65535", service.Port))
+	}
+	for _, protocol := range service.Protocols {
+		if _, ok := knownProtocols[protocolKey(protocol)]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown protocol %q", protocol))
+		}
+	}
+	if service.Version != "" && !semverPattern.MatchString(service.Version) {
+		problems = append(problems, fmt.Sprintf("version %q is not a semantic version", service.Version))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidServiceInfo, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// NormalizeServiceInfo 返回规范化的服务实例信息副本，不修改 service
+//
+// 协议名称转为小写并去掉连字符（如 JSON-RPC 为 jsonrpc，gRPC 为 grpc），port.<协议> 元数据的键随之转换，
+// 序列化格式转为小写，使各语言 SDK 注册的实例可以直接比较
+func NormalizeServiceInfo(service *ServiceInfo) *ServiceInfo {
+	normalized := *service
+	if service.Protocols != nil {
+		normalized.Protocols = make([]string, len(service.Protocols))
+		for i, protocol := range service.Protocols {
+			normalized.Protocols[i] = protocolKey(protocol)
+		}
+	}
+	if service.Serializations != nil {
+		normalized.Serializations = make([]string, len(service.Serializations))
+		for i, format := range service.Serializations {
+			normalized.Serializations[i] = strings.ToLower(format)
+		}
+	}
+	if service.Metadata != nil {
+		normalized.Metadata = make(map[string]string, len(service.Metadata))
+		for key, value := range service.Metadata {
+			if protocol, ok := strings.CutPrefix(key, MetadataPortPrefix); ok {
+				key = MetadataPortPrefix + protocolKey(protocol)
+			}
+			normalized.Metadata[key] = value
+		}
+	}
+	return &normalized
+}
+
+// prepareService 校验并规范化 Register 传入的服务实例信息
+func prepareService(service *ServiceInfo) (*ServiceInfo, error) {
+	if err := ValidateServiceInfo(service); err != nil {
+		return nil, err
+	}
+	return NormalizeServiceInfo(service), nil
+}
+
+// validAddress 判断地址是否为 IP 或主机名
+func validAddress(address string) bool {
+	if address == "" || len(address) > 253 {
+		return false
+	}
+	if net.ParseIP(address) != nil {
+		return true
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(address, "."), ".") {
+		if !hostnamePattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// protocolType 返回规范化协议名称对应的协议类型，未知协议原样返回
+func protocolType(protocol string) adapter.ProtocolType {
+	if t, ok := knownProtocols[protocolKey(protocol)]; ok {
+		return t
+	}
+	return adapter.ProtocolType(protocol)
+}
@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateServiceInfo 测试服务实例信息校验
+func TestValidateServiceInfo(t *testing.T) {
+	valid := func() *ServiceInfo {
+		return &ServiceInfo{ID: "order-1", Name: "order-service", Version: "1.2.0", Address: "10.0.0.1", Port: 8080, Protocols: []string{"gRPC", "jsonrpc"}}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(s *ServiceInfo)
+		wantErr string
+	}{
+		{name: "合法", modify: func(s *ServiceInfo) {}},
+		{name: "主机名和带前缀的版本", modify: func(s *ServiceInfo) { s.Address = "order.default.svc.cluster.local"; s.Version = "v2.0.0-rc.1+build.5" }},
+		{name: "IPv6 地址", modify: func(s *ServiceInfo) { s.Address = "fd00::1" }},
+		{name: "未声明版本和协议", modify: func(s *ServiceInfo) { s.Version = ""; s.Protocols = nil }},
+		{name: "端口越界", modify: func(s *ServiceInfo) { s.Port = 70000 }, wantErr: "port 70000 out of range"},
+		{name: "端口为 0", modify: func(s *ServiceInfo) { s.Port = 0 }, wantErr: "port 0 out of range"},
+		{name: "地址包含端口", modify: func(s *ServiceInfo) { s.Address = "10.0.0.1:8080" }, wantErr: "is not an IP address or hostname"},
+		{name: "地址包含协议前缀", modify: func(s *ServiceInfo) { s.Address = "http://order" }, wantErr: "is not an IP address or hostname"},
+		{name: "地址为空", modify: func(s *ServiceInfo) { s.Address = "" }, wantErr: "is not an IP address or hostname"},
+		{name: "未知协议", modify: func(s *ServiceInfo) { s.Protocols = []string{"gRPC", "smtp"} }, wantErr: `unknown protocol "smtp"`},
+		{name: "版本不是语义化版本", modify: func(s *ServiceInfo) { s.Version = "latest" }, wantErr: `version "latest"`},
+		{name: "ID 为空", modify: func(s *ServiceInfo) { s.ID = "" }, wantErr: "service ID is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := valid()
+			tt.modify(service)
+			err := ValidateServiceInfo(service)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid service info, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidServiceInfo) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// 所有问题在同一个错误中列出
+	err := ValidateServiceInfo(&ServiceInfo{ID: "order-1", Name: "order-service", Address: "bad address", Port: -1})
+	if err == nil || !strings.Contains(err.Error(), "address") || !strings.Contains(err.Error(), "port -1") {
+		t.Errorf("Expected all problems to be reported, got %v", err)
+	}
+}
+
+// TestRegisterNormalizesServiceInfo 测试注册时规范化协议名称，并拒绝不合法的实例
+func TestRegisterNormalizesServiceInfo(t *testing.T) {
+	reg := NewMemoryRegistry(nil)
+	defer reg.Close()
+	ctx := context.Background()
+
+	service := &ServiceInfo{
+		ID:             "order-1",
+		Name:           "order-service",
+		Address:        "10.0.0.1",
+		Port:           8080,
+		Protocols:      []string{"gRPC", "JSON-RPC"},
+		Serializations: []string{"MsgPack"},
+		Metadata:       map[string]string{MetadataPortPrefix + "gRPC": "9090", "region": "us-west"},
+	}
+	if err := reg.Register(ctx, service); err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+	if service.Protocols[0] != "gRPC" {
+		t.Error("Expected caller's service info to be left unchanged")
+	}
+
+	services, _ := reg.Discover(ctx, "order-service")
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 instance, got %d", len(services))
+	}
+	got := services[0]
+	if strings.Join(got.Protocols, ",") != "grpc,jsonrpc" || got.Serializations[0] != "msgpack" {
+		t.Errorf("Expected normalized protocols and serializations, got %v %v", got.Protocols, got.Serializations)
+	}
+	if got.Metadata[MetadataPortPrefix+"grpc"] != "9090" || got.Metadata["region"] != "us-west" {
+		t.Errorf("Expected normalized port metadata, got %v", got.Metadata)
+	}
+
+	err := reg.Register(ctx, &ServiceInfo{ID: "order-2", Name: "order-service", Address: "10.0.0.2", Port: 8080, Protocols: []string{"carrier-pigeon"}})
+	if !errors.Is(err, ErrInvalidServiceInfo) {
+		t.Errorf("Expected invalid service info error, got %v", err)
+	}
+	if services, _ := reg.Discover(ctx, "order-service"); len(services) != 1 {
+		t.Errorf("Expected invalid instance not to be registered, got %d instances", len(services))
+	}
+}
@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/os/glog"
+)
+
+// 监听回调执行的默认参数
+const (
+	// DefaultWatchWorkers 并发执行监听回调的 worker 数
+	DefaultWatchWorkers = 4
+	// DefaultSlowWatchThreshold 监听回调执行超过该时间时记录警告
+	DefaultSlowWatchThreshold = time.Second
+)
+
+// watchSubscriber 一个监听回调，同一回调的通知按顺序执行且不并发；
+// 回调执行期间到达的多次通知合并为最后一次，回调总是收到最新的服务列表
+type watchSubscriber struct {
+	// label 日志和指标中的监听目标，为服务名或模式
+	label string
+
+	mu      sync.Mutex
+	pending func() // 待执行的通知，为 nil 时没有
+	queued  bool   // 已在队列中或正在执行
+}
+
+// watchDispatcher 以固定数量的 worker 执行监听回调，恢复回调的 panic 并检测执行过慢的回调，
+// 一个阻塞或 panic 的回调最多占用一个 worker，不影响注册中心和其他回调
+type watchDispatcher struct {
+	backend   string
+	threshold time.Duration
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []*watchSubscriber
+	closed bool
+}
+
+// newWatchDispatcher 创建并启动 workers 个 worker，workers 为 0 时使用 DefaultWatchWorkers，threshold 为 0 时使用 DefaultSlowWatchThreshold
+func newWatchDispatcher(backend string, workers int, threshold time.Duration) *watchDispatcher {
+	if workers <= 0 {
+		workers = DefaultWatchWorkers
+	}
+	if threshold <= 0 {
+		threshold = DefaultSlowWatchThreshold
+	}
+	d := &watchDispatcher{backend: backend, threshold: threshold}
+	d.cond = sync.NewCond(&d.mu)
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+	return d
+}
+
+// submit 安排 sub 执行 notify，替换尚未执行的通知；不阻塞
+func (d *watchDispatcher) submit(sub *watchSubscriber, notify func()) {
+	sub.mu.Lock()
+	sub.pending = notify
+	if sub.queued {
+		sub.mu.Unlock()
+		return
+	}
+	sub.queued = true
+	sub.mu.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	d.queue = append(d.queue, sub)
+	d.cond.Signal()
+}
+
+// work 依次取出有待执行通知的回调并执行，直到关闭
+func (d *watchDispatcher) work() {
+	for {
+		d.mu.Lock()
+		for len(d.queue) == 0 && !d.closed {
+			d.cond.Wait()
+		}
+		if d.closed {
+			d.mu.Unlock()
+			return
+		}
+		sub := d.queue[0]
+		d.queue[0] = nil
+		d.queue = d.queue[1:]
+		d.mu.Unlock()
+
+		d.drain(sub)
+	}
+}
+
+// drain 执行 sub 的通知，执行期间到达的新通知在同一 worker 上接着执行
+func (d *watchDispatcher) drain(sub *watchSubscriber) {
+	for {
+		sub.mu.Lock()
+		notify := sub.pending
+		sub.pending = nil
+		if notify == nil {
+			sub.queued = false
+			sub.mu.Unlock()
+			return
+		}
+		sub.mu.Unlock()
+
+		d.invoke(sub.label, notify)
+	}
+}
+
+// invoke 执行一次回调，恢复 panic；执行超过阈值时记录警告（回调仍未返回时即记录，可发现阻塞的回调）
+func (d *watchDispatcher) invoke(label string, notify func()) {
+	ctx := context.Background()
+	start := time.Now()
+	slow := time.AfterFunc(d.threshold, func() {
+		recordWatchCallbackSlow(d.backend, label)
+		glog.Warningf(ctx, "Registry watch callback for %s has been running for more than %s", label, d.threshold)
+	})
+	defer func() {
+		if !slow.Stop() {
+			glog.Warningf(ctx, "Slow registry watch callback for %s took %s", label, time.Since(start).Round(time.Millisecond))
+		}
+		if r := recover(); r != nil {
+			recordWatchCallbackPanic(d.backend, label)
+			glog.Errorf(ctx, "Registry watch callback panic for %s: %v", label, r)
+		}
+	}()
+	notify()
+}
+
+// close 停止 worker，尚未执行的通知被丢弃；不等待执行中的回调，阻塞的回调不会阻塞关闭
+func (d *watchDispatcher) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	d.queue = nil
+	d.cond.Broadcast()
+}
@@ -0,0 +1,174 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+)
+
+// 接收队列的默认配置
+const (
+	DefaultAcceptQueueConcurrency = 100
+	DefaultAcceptQueueSize        = 100
+)
+
+// AcceptQueueConfig 接收队列配置
+type AcceptQueueConfig struct {
+	// MaxConcurrent 同时处理的请求数上限，为 0 时使用 DefaultAcceptQueueConcurrency
+	MaxConcurrent int
+	// QueueSize 等待处理的请求数上限，为 0 时使用 DefaultAcceptQueueSize，小于 0 时不排队
+	QueueSize int
+	// MaxWait 请求在队列中等待的最长时间，为 0 时只受请求自身的截止时间限制
+	MaxWait time.Duration
+}
+
+// AcceptQueueStats 接收队列的状态
+type AcceptQueueStats struct {
+	Active int `json:"active"`
+	Queued int `json:"queued"`
+}
+
+// AcceptQueue 协议处理器的有界接收队列
+//
+// 处理中的请求达到 MaxConcurrent 时，新请求按到达顺序排队，队列已满时立即返回 ServiceUnavailable。
+// 排队期间截止时间已过（或超过 MaxWait）的请求不再处理，返回 Timeout，避免在调用方已放弃的请求上浪费资源；
+// 调用方取消的请求返回 ClientClosedRequest。等待时间按 outcome 记录到 framework_accept_queue_wait_seconds
+type AcceptQueue struct {
+	name   string
+	config AcceptQueueConfig
+
+	mu      sync.Mutex
+	active  int
+	waiters []chan struct{}
+}
+
+// NewAcceptQueue 创建接收队列，name 用作指标的 queue 标签
+func NewAcceptQueue(name string, config *AcceptQueueConfig) *AcceptQueue {
+	q := &AcceptQueue{name: name, config: *config}
+	if q.config.MaxConcurrent < 1 {
+		q.config.MaxConcurrent = DefaultAcceptQueueConcurrency
+	}
+	if q.config.QueueSize == 0 {
+		q.config.QueueSize = DefaultAcceptQueueSize
+	}
+	if q.config.QueueSize < 0 {
+		q.config.QueueSize = 0
+	}
+	if q.config.MaxWait < 0 {
+		q.config.MaxWait = 0
+	}
+	return q
+}
+
+// Acquire 占用一个处理名额，返回释放函数；队列已满、截止时间已过或调用方取消时返回错误，不占用名额
+func (q *AcceptQueue) Acquire(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, q.dropped(ctx, 0)
+	}
+
+	q.mu.Lock()
+	if q.active < q.config.MaxConcurrent && len(q.waiters) == 0 {
+		q.active++
+		q.mu.Unlock()
+		observeAcceptWait(q.name, acceptOutcomeAdmitted, 0)
+		return q.releaseFunc(), nil
+	}
+	if len(q.waiters) >= q.config.QueueSize {
+		q.mu.Unlock()
+		recordAcceptQueueFull(q.name)
+		return nil, errors.NewFrameworkError(
+			errors.ServiceUnavailable,
+			fmt.Sprintf("接收队列 [%s] 已满，请求被拒绝", q.name),
+		)
+	}
+
+	// 排队等待释放的名额，release 直接将名额转交给队首
+	ready := make(chan struct{})
+	q.waiters = append(q.waiters, ready)
+	setAcceptQueueDepth(q.name, len(q.waiters))
+	q.mu.Unlock()
+
+	start := time.Now()
+	waitCtx := ctx
+	if q.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, q.config.MaxWait)
+		defer cancel()
+	}
+
+	select {
+	case <-ready:
+		// 名额转交与截止时间同时到达时，不处理已过期的请求
+		if waitCtx.Err() == nil {
+			observeAcceptWait(q.name, acceptOutcomeAdmitted, time.Since(start))
+			return q.releaseFunc(), nil
+		}
+		q.mu.Lock()
+		q.releaseLocked()
+		q.mu.Unlock()
+	case <-waitCtx.Done():
+		q.mu.Lock()
+		removed := false
+		for i, waiter := range q.waiters {
+			if waiter == ready {
+				q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			// 过期的同时已被转交名额，归还给下一个等待者
+			q.releaseLocked()
+		}
+		setAcceptQueueDepth(q.name, len(q.waiters))
+		q.mu.Unlock()
+	}
+	return nil, q.dropped(waitCtx, time.Since(start))
+}
+
+// Stats 返回处理中和排队中的请求数
+func (q *AcceptQueue) Stats() AcceptQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return AcceptQueueStats{Active: q.active, Queued: len(q.waiters)}
+}
+
+// dropped 记录未处理的请求并返回错误，调用方取消为 ClientClosedRequest，其他为 Timeout
+func (q *AcceptQueue) dropped(ctx context.Context, wait time.Duration) error {
+	if code, _ := errors.ContextErrorCode(ctx.Err()); code == errors.ClientClosedRequest {
+		observeAcceptWait(q.name, acceptOutcomeCanceled, wait)
+		return errors.NewFrameworkError(code, fmt.Sprintf("请求在接收队列 [%s] 中等待时被调用方取消", q.name))
+	}
+	observeAcceptWait(q.name, acceptOutcomeExpired, wait)
+	return errors.NewFrameworkError(
+		errors.Timeout,
+		fmt.Sprintf("请求在接收队列 [%s] 中等待 %s 后已超过截止时间，未处理", q.name, wait.Round(time.Millisecond)),
+	)
+}
+
+// releaseFunc 返回只生效一次的释放函数
+func (q *AcceptQueue) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			q.releaseLocked()
+		})
+	}
+}
+
+// releaseLocked 释放一个名额，有请求排队时直接转交给队首（需要持有锁）
+func (q *AcceptQueue) releaseLocked() {
+	if len(q.waiters) > 0 {
+		ready := q.waiters[0]
+		q.waiters = q.waiters[1:]
+		close(ready)
+		setAcceptQueueDepth(q.name, len(q.waiters))
+		return
+	}
+	q.active--
+}
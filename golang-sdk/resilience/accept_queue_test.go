@@ -0,0 +1,120 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestAcceptQueueAdmitsInOrder(t *testing.T) {
+	q := NewAcceptQueue("test-order", &AcceptQueueConfig{MaxConcurrent: 1, QueueSize: 1})
+
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := q.Acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	waitQueued(t, q, 1)
+
+	// 队列已满时立即拒绝
+	if _, err := q.Acquire(context.Background()); errorCode(err) != errors.ServiceUnavailable {
+		t.Fatalf("Expected ServiceUnavailable when the queue is full, got %v", err)
+	}
+
+	release()
+	release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("Queued request failed: %v", err)
+	}
+	if stats := q.Stats(); stats.Active != 0 || stats.Queued != 0 {
+		t.Errorf("Stats = %+v, want empty", stats)
+	}
+}
+
+func TestAcceptQueueDropsExpiredRequests(t *testing.T) {
+	q := NewAcceptQueue("test-expired", &AcceptQueueConfig{MaxConcurrent: 1})
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	// 排队期间截止时间已过
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Acquire(ctx); errorCode(err) != errors.Timeout {
+		t.Fatalf("Expected Timeout for expired request, got %v", err)
+	}
+
+	// 到达时截止时间已过的请求不排队
+	if _, err := q.Acquire(ctx); errorCode(err) != errors.Timeout {
+		t.Fatalf("Expected Timeout for already expired request, got %v", err)
+	}
+
+	// 调用方取消
+	canceled, cancelNow := context.WithCancel(context.Background())
+	go func() {
+		waitQueued(t, q, 1)
+		cancelNow()
+	}()
+	if _, err := q.Acquire(canceled); errorCode(err) != errors.ClientClosedRequest {
+		t.Fatalf("Expected ClientClosedRequest for canceled request, got %v", err)
+	}
+
+	if stats := q.Stats(); stats.Active != 1 || stats.Queued != 0 {
+		t.Errorf("Stats = %+v, want 1 active", stats)
+	}
+	for reason, want := range map[string]float64{acceptOutcomeExpired: 2, acceptOutcomeCanceled: 1} {
+		var metric dto.Metric
+		if err := acceptQueueDroppedTotal.WithLabelValues("test-expired", reason).Write(&metric); err != nil {
+			t.Fatalf("Failed to read metric: %v", err)
+		}
+		if got := metric.GetCounter().GetValue(); got != want {
+			t.Errorf("%s drops = %v, want %v", reason, got, want)
+		}
+	}
+}
+
+func TestAcceptQueueMaxWait(t *testing.T) {
+	q := NewAcceptQueue("test-max-wait", &AcceptQueueConfig{MaxConcurrent: 1, MaxWait: 20 * time.Millisecond})
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	// 没有截止时间的请求最多等待 MaxWait
+	if _, err := q.Acquire(context.Background()); errorCode(err) != errors.Timeout {
+		t.Fatalf("Expected Timeout after MaxWait, got %v", err)
+	}
+
+	// 等待中的请求过期后，释放的名额不会丢失
+	release()
+	release, err = q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire after release failed: %v", err)
+	}
+	release()
+}
+
+// waitQueued 等待队列中有 n 个请求
+func waitQueued(t *testing.T, q *AcceptQueue, n int) {
+	deadline := time.Now().Add(time.Second)
+	for q.Stats().Queued != n {
+		if time.Now().After(deadline) {
+			t.Errorf("Timed out waiting for %d queued requests", n)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,150 @@
+package resilience
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 自适应超时的默认配置
+const (
+	DefaultAdaptivePercentile = 0.99
+	DefaultAdaptiveMultiplier = 1.5
+	DefaultAdaptiveFloor      = 100 * time.Millisecond
+	DefaultAdaptiveCeiling    = 30 * time.Second
+	DefaultAdaptiveMinSamples = 20
+	DefaultAdaptiveWindow     = 200
+)
+
+// AdaptiveTimeoutConfig 自适应超时配置，零值字段使用默认值
+type AdaptiveTimeoutConfig struct {
+	// Percentile 取历史延迟的分位数（0~1），默认 0.99
+	Percentile float64
+	// Multiplier 超时为分位数延迟的倍数，默认 1.5
+	Multiplier float64
+	// Floor/Ceiling 超时的下限和上限，默认 100 毫秒和 30 秒
+	Floor   time.Duration
+	Ceiling time.Duration
+	// MinSamples 样本数达到该值前使用 Ceiling，默认 20
+	MinSamples int
+	// Window 每个服务方法保留的最近样本数，默认 200
+	Window int
+}
+
+// AdaptiveTimeout 按服务方法的历史延迟计算每次调用的超时
+//
+// 超时为最近 Window 次调用延迟的 Percentile 分位数乘以 Multiplier，限制在 [Floor, Ceiling] 内。
+// 超时的调用以实际耗时（约等于超时）计入样本，延迟整体上升时超时随之放宽
+type AdaptiveTimeout struct {
+	config AdaptiveTimeoutConfig
+
+	mu      sync.Mutex
+	methods map[string]*latencyWindow
+}
+
+// latencyWindow 单个服务方法最近的延迟样本
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	// timeout 按当前样本计算的超时，新增样本后重新计算
+	timeout time.Duration
+	dirty   bool
+}
+
+// NewAdaptiveTimeout 创建自适应超时
+func NewAdaptiveTimeout(config *AdaptiveTimeoutConfig) *AdaptiveTimeout {
+	cfg := AdaptiveTimeoutConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile > 1 {
+		cfg.Percentile = DefaultAdaptivePercentile
+	}
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = DefaultAdaptiveMultiplier
+	}
+	if cfg.Floor <= 0 {
+		cfg.Floor = DefaultAdaptiveFloor
+	}
+	if cfg.Ceiling <= 0 {
+		cfg.Ceiling = DefaultAdaptiveCeiling
+	}
+	if cfg.Ceiling < cfg.Floor {
+		cfg.Ceiling = cfg.Floor
+	}
+	if cfg.MinSamples < 1 {
+		cfg.MinSamples = DefaultAdaptiveMinSamples
+	}
+	if cfg.Window < cfg.MinSamples {
+		cfg.Window = DefaultAdaptiveWindow
+		if cfg.Window < cfg.MinSamples {
+			cfg.Window = cfg.MinSamples
+		}
+	}
+
+	return &AdaptiveTimeout{
+		config:  cfg,
+		methods: make(map[string]*latencyWindow),
+	}
+}
+
+// Timeout 返回调用 service 服务 method 方法的超时，样本不足时返回 Ceiling
+func (a *AdaptiveTimeout) Timeout(service, method string) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w := a.methods[methodKey(service, method)]
+	if w == nil || len(w.samples) < a.config.MinSamples {
+		return a.config.Ceiling
+	}
+	if w.dirty {
+		w.timeout = a.compute(w.samples)
+		w.dirty = false
+	}
+	return w.timeout
+}
+
+// Observe 记录一次调用的延迟
+func (a *AdaptiveTimeout) Observe(service, method string, latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := methodKey(service, method)
+	w := a.methods[key]
+	if w == nil {
+		w = &latencyWindow{samples: make([]time.Duration, 0, a.config.Window)}
+		a.methods[key] = w
+	}
+	if len(w.samples) < a.config.Window {
+		w.samples = append(w.samples, latency)
+	} else {
+		w.samples[w.next] = latency
+		w.next = (w.next + 1) % a.config.Window
+	}
+	w.dirty = true
+}
+
+// compute 按样本计算超时
+func (a *AdaptiveTimeout) compute(samples []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(a.config.Percentile*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	timeout := time.Duration(float64(sorted[index]) * a.config.Multiplier)
+	if timeout < a.config.Floor {
+		return a.config.Floor
+	}
+	if timeout > a.config.Ceiling {
+		return a.config.Ceiling
+	}
+	return timeout
+}
+
+// methodKey 返回服务方法的键
+func methodKey(service, method string) string {
+	return service + "/" + method
+}
@@ -0,0 +1,68 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveTimeout(t *testing.T) {
+	a := NewAdaptiveTimeout(&AdaptiveTimeoutConfig{
+		Percentile: 0.9,
+		Multiplier: 2,
+		Floor:      10 * time.Millisecond,
+		Ceiling:    time.Second,
+		MinSamples: 10,
+		Window:     10,
+	})
+
+	t.Run("样本不足时使用上限", func(t *testing.T) {
+		for i := 0; i < 9; i++ {
+			a.Observe("svc", "m", 20*time.Millisecond)
+		}
+		if got := a.Timeout("svc", "m"); got != time.Second {
+			t.Errorf("Timeout = %v, want 1s", got)
+		}
+		if got := a.Timeout("svc", "other"); got != time.Second {
+			t.Errorf("Timeout of unknown method = %v, want 1s", got)
+		}
+	})
+
+	t.Run("分位数乘以倍数", func(t *testing.T) {
+		a.Observe("svc", "m", 100*time.Millisecond)
+		// 10 个样本的 P90 为第 9 个：20ms
+		if got := a.Timeout("svc", "m"); got != 40*time.Millisecond {
+			t.Errorf("Timeout = %v, want 40ms", got)
+		}
+		a.Observe("svc", "m", 100*time.Millisecond)
+		if got := a.Timeout("svc", "m"); got != 200*time.Millisecond {
+			t.Errorf("Timeout = %v, want 200ms", got)
+		}
+	})
+
+	t.Run("只保留最近的样本", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			a.Observe("svc", "m", time.Millisecond)
+		}
+		if got := a.Timeout("svc", "m"); got != 10*time.Millisecond {
+			t.Errorf("Timeout = %v, want floor 10ms", got)
+		}
+	})
+
+	t.Run("不超过上限", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			a.Observe("svc", "m", 2*time.Second)
+		}
+		if got := a.Timeout("svc", "m"); got != time.Second {
+			t.Errorf("Timeout = %v, want ceiling 1s", got)
+		}
+	})
+}
+
+func TestAdaptiveTimeoutDefaults(t *testing.T) {
+	a := NewAdaptiveTimeout(nil)
+	if a.config.Percentile != DefaultAdaptivePercentile || a.config.Multiplier != DefaultAdaptiveMultiplier ||
+		a.config.Floor != DefaultAdaptiveFloor || a.config.Ceiling != DefaultAdaptiveCeiling ||
+		a.config.MinSamples != DefaultAdaptiveMinSamples || a.config.Window != DefaultAdaptiveWindow {
+		t.Errorf("unexpected defaults: %+v", a.config)
+	}
+}
@@ -0,0 +1,161 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+// Bulkhead 舱壁，限制同时执行的请求数
+//
+// 请求的优先级由 metadata.PriorityFromContext 确定：批处理请求最多占用 batchRatio 比例的并发数，
+// 达到上限或舱壁已满时立即拒绝；交互请求可使用全部并发数，已满时最多排队等待 maxWait，
+// 释放的并发数优先交给排队的交互请求
+type Bulkhead struct {
+	name          string
+	maxConcurrent int
+	batchLimit    int
+	maxWait       time.Duration
+
+	mu          sync.Mutex
+	active      int
+	activeBatch int
+	waiters     []chan struct{}
+}
+
+// NewBulkhead 创建舱壁
+//
+// batchRatio 为批处理请求可占用的并发数比例（0~1），maxWait 为交互请求排队等待的最长时间，为 0 时不排队
+func NewBulkhead(name string, maxConcurrent int, batchRatio float64, maxWait time.Duration) *Bulkhead {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if batchRatio < 0 {
+		batchRatio = 0
+	}
+	if batchRatio > 1 {
+		batchRatio = 1
+	}
+	if maxWait < 0 {
+		maxWait = 0
+	}
+
+	return &Bulkhead{
+		name:          name,
+		maxConcurrent: maxConcurrent,
+		batchLimit:    int(math.Floor(float64(maxConcurrent) * batchRatio)),
+		maxWait:       maxWait,
+	}
+}
+
+// Acquire 按 context 中的优先级占用一个并发数，返回释放函数；被拒绝时返回 ServiceUnavailable 错误
+func (b *Bulkhead) Acquire(ctx context.Context) (func(), error) {
+	priority := metadata.PriorityFromContext(ctx)
+
+	b.mu.Lock()
+	if priority == metadata.PriorityBatch {
+		if b.active >= b.maxConcurrent || b.activeBatch >= b.batchLimit || len(b.waiters) > 0 {
+			b.mu.Unlock()
+			return nil, b.rejected(priority)
+		}
+		b.active++
+		b.activeBatch++
+		b.mu.Unlock()
+		return b.releaseFunc(priority), nil
+	}
+
+	if b.active < b.maxConcurrent {
+		b.active++
+		b.mu.Unlock()
+		return b.releaseFunc(priority), nil
+	}
+	if b.maxWait == 0 {
+		b.mu.Unlock()
+		return nil, b.rejected(priority)
+	}
+
+	// 排队等待释放的并发数，release 直接将并发数转交给队首
+	ready := make(chan struct{})
+	b.waiters = append(b.waiters, ready)
+	b.mu.Unlock()
+
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-ready:
+		return b.releaseFunc(priority), nil
+	case <-timer.C:
+		err = b.rejected(priority)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, waiter := range b.waiters {
+		if waiter == ready {
+			b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+			return nil, err
+		}
+	}
+	// 超时的同时已被转交并发数，归还给下一个等待者
+	b.releaseLocked(metadata.PriorityInteractive)
+	return nil, err
+}
+
+// Execute 在舱壁内执行操作，舱壁已满时不执行并返回 ServiceUnavailable 错误
+func (b *Bulkhead) Execute(ctx context.Context, operation func() error) error {
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return operation()
+}
+
+// Active 返回正在执行的请求数和其中的批处理请求数
+func (b *Bulkhead) Active() (total, batch int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.active, b.activeBatch
+}
+
+// releaseFunc 返回只生效一次的释放函数
+func (b *Bulkhead) releaseFunc(priority metadata.Priority) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.releaseLocked(priority)
+		})
+	}
+}
+
+// releaseLocked 释放一个并发数，有交互请求排队时直接转交给队首（需要持有锁）
+func (b *Bulkhead) releaseLocked(priority metadata.Priority) {
+	if priority == metadata.PriorityBatch {
+		b.activeBatch--
+	}
+	if len(b.waiters) > 0 {
+		ready := b.waiters[0]
+		b.waiters = b.waiters[1:]
+		close(ready)
+		return
+	}
+	b.active--
+}
+
+// rejected 返回请求被拒绝的错误
+func (b *Bulkhead) rejected(priority metadata.Priority) error {
+	return errors.NewFrameworkError(
+		errors.ServiceUnavailable,
+		fmt.Sprintf("舱壁 [%s] 已满，%s 请求被拒绝", b.name, priority),
+	)
+}
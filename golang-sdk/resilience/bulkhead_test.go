@@ -0,0 +1,113 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+func TestBulkheadShedsBatchFirst(t *testing.T) {
+	b := NewBulkhead("test", 4, 0.5, 0)
+	interactive := context.Background()
+	batch := metadata.WithPriority(context.Background(), metadata.PriorityBatch)
+
+	// 批处理请求最多占用一半并发数
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := b.Acquire(batch)
+		if err != nil {
+			t.Fatalf("batch acquire %d failed: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	_, err := b.Acquire(batch)
+	if errorCode(err) != errors.ServiceUnavailable {
+		t.Fatalf("Expected ServiceUnavailable for batch, got %v", err)
+	}
+
+	// 剩余的并发数留给交互请求
+	for i := 0; i < 2; i++ {
+		release, err := b.Acquire(interactive)
+		if err != nil {
+			t.Fatalf("interactive acquire %d failed: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	if _, err := b.Acquire(interactive); err == nil {
+		t.Fatal("Expected interactive request to be rejected when full")
+	}
+
+	// 释放函数只生效一次
+	releases[0]()
+	releases[0]()
+	if total, batchActive := b.Active(); total != 3 || batchActive != 1 {
+		t.Errorf("Active = %d/%d, want 3/1", total, batchActive)
+	}
+}
+
+func TestBulkheadInteractiveWaits(t *testing.T) {
+	b := NewBulkhead("test", 1, 1, time.Second)
+	batch := metadata.WithPriority(context.Background(), metadata.PriorityBatch)
+
+	release, err := b.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := b.Acquire(context.Background())
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+
+	// 交互请求排队时批处理请求被拒绝
+	deadline := time.Now().Add(time.Second)
+	for {
+		b.mu.Lock()
+		waiting := len(b.waiters)
+		b.mu.Unlock()
+		if waiting > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := b.Acquire(batch); err == nil {
+		t.Error("Expected batch request to be rejected while interactive requests wait")
+	}
+
+	release()
+	if err := <-acquired; err != nil {
+		t.Errorf("Expected waiting interactive request to acquire, got %v", err)
+	}
+	if total, _ := b.Active(); total != 0 {
+		t.Errorf("Active = %d, want 0", total)
+	}
+}
+
+func TestBulkheadWaitTimeout(t *testing.T) {
+	b := NewBulkhead("test", 1, 1, 20*time.Millisecond)
+	release, _ := b.Acquire(context.Background())
+	defer release()
+
+	err := b.Execute(context.Background(), func() error {
+		t.Error("operation should not run")
+		return nil
+	})
+	if errorCode(err) != errors.ServiceUnavailable {
+		t.Errorf("Expected ServiceUnavailable, got %v", err)
+	}
+}
+
+// errorCode 返回框架错误的错误码，不是框架错误时返回 0
+func errorCode(err error) errors.ErrorCode {
+	if fe, ok := errors.FromError(err); ok {
+		return fe.Code
+	}
+	return 0
+}
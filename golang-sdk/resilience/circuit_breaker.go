@@ -35,6 +35,9 @@ func (s State) String() string {
 	}
 }
 
+// StateChangeListener 熔断器状态变化监听器
+type StateChangeListener func(name string, from, to State)
+
 // CircuitBreaker 熔断器实现
 type CircuitBreaker struct {
 	name             string
@@ -48,6 +51,9 @@ type CircuitBreaker struct {
 	lastFailureTime atomic.Int64
 
 	mu sync.RWMutex
+
+	listenersMu sync.RWMutex
+	listeners   []StateChangeListener
 }
 
 // NewCircuitBreaker 创建新的熔断器
@@ -128,12 +134,16 @@ func (cb *CircuitBreaker) AllowRequest() bool {
 		if time.Since(lastFailure) >= cb.timeout {
 			cb.mu.Lock()
 			// 双重检查
-			if cb.GetState() == StateOpen {
+			changed := cb.GetState() == StateOpen
+			if changed {
 				cb.state.Store(StateHalfOpen)
 				cb.successCount.Store(0)
 				fmt.Printf("熔断器 [%s] 从 OPEN 转为 HALF_OPEN\n", cb.name)
 			}
 			cb.mu.Unlock()
+			if changed {
+				cb.notifyStateChange(StateOpen, StateHalfOpen)
+			}
 			return true
 		}
 		return false
@@ -153,13 +163,17 @@ func (cb *CircuitBreaker) RecordSuccess() {
 		if successes >= int32(cb.successThreshold) {
 			cb.mu.Lock()
 			// 双重检查
-			if cb.GetState() == StateHalfOpen {
+			changed := cb.GetState() == StateHalfOpen
+			if changed {
 				cb.state.Store(StateClosed)
 				cb.failureCount.Store(0)
 				cb.successCount.Store(0)
 				fmt.Printf("熔断器 [%s] 从 HALF_OPEN 转为 CLOSED\n", cb.name)
 			}
 			cb.mu.Unlock()
+			if changed {
+				cb.notifyStateChange(StateHalfOpen, StateClosed)
+			}
 		}
 	} else if currentState == StateClosed {
 		// 成功时重置失败计数
@@ -175,22 +189,30 @@ func (cb *CircuitBreaker) RecordFailure() {
 	if currentState == StateHalfOpen {
 		// 半开状态下失败，立即转回 Open
 		cb.mu.Lock()
-		if cb.GetState() == StateHalfOpen {
+		changed := cb.GetState() == StateHalfOpen
+		if changed {
 			cb.state.Store(StateOpen)
 			cb.successCount.Store(0)
 			fmt.Printf("熔断器 [%s] 从 HALF_OPEN 转回 OPEN\n", cb.name)
 		}
 		cb.mu.Unlock()
+		if changed {
+			cb.notifyStateChange(StateHalfOpen, StateOpen)
+		}
 	} else if currentState == StateClosed {
 		failures := cb.failureCount.Add(1)
 		if failures >= int32(cb.failureThreshold) {
 			cb.mu.Lock()
 			// 双重检查
-			if cb.GetState() == StateClosed {
+			changed := cb.GetState() == StateClosed
+			if changed {
 				cb.state.Store(StateOpen)
 				fmt.Printf("熔断器 [%s] 从 CLOSED 转为 OPEN，连续失败 %d 次\n", cb.name, failures)
 			}
 			cb.mu.Unlock()
+			if changed {
+				cb.notifyStateChange(StateClosed, StateOpen)
+			}
 		}
 	}
 }
@@ -198,13 +220,60 @@ func (cb *CircuitBreaker) RecordFailure() {
 // Reset 重置熔断器到初始状态
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
+	previous := cb.GetState()
 	cb.state.Store(StateClosed)
 	cb.failureCount.Store(0)
 	cb.successCount.Store(0)
 	cb.lastFailureTime.Store(0)
 	fmt.Printf("熔断器 [%s] 已重置\n", cb.name)
+	cb.mu.Unlock()
+
+	if previous != StateClosed {
+		cb.notifyStateChange(previous, StateClosed)
+	}
+}
+
+// OnStateChange 注册状态变化监听器，监听器在状态切换后同步调用
+func (cb *CircuitBreaker) OnStateChange(listener StateChangeListener) {
+	if listener == nil {
+		return
+	}
+
+	cb.listenersMu.Lock()
+	defer cb.listenersMu.Unlock()
+	cb.listeners = append(cb.listeners, listener)
+}
+
+// 熔断器发布的框架事件类型，与 observability.EventCircuitBreaker* 一致
+const (
+	EventCircuitBreakerOpened   = "circuit_breaker.opened"
+	EventCircuitBreakerHalfOpen = "circuit_breaker.half_open"
+	EventCircuitBreakerClosed   = "circuit_breaker.closed"
+)
+
+// WatchEvents 将状态变化以框架事件发布，实现 observability.EventSource，事件来源为熔断器名
+func (cb *CircuitBreaker) WatchEvents(emit func(eventType, source string, attributes map[string]string)) {
+	cb.OnStateChange(func(name string, from, to State) {
+		eventType := EventCircuitBreakerClosed
+		switch to {
+		case StateOpen:
+			eventType = EventCircuitBreakerOpened
+		case StateHalfOpen:
+			eventType = EventCircuitBreakerHalfOpen
+		}
+		emit(eventType, name, map[string]string{"from": from.String(), "to": to.String()})
+	})
+}
+
+// notifyStateChange 通知所有监听器
+func (cb *CircuitBreaker) notifyStateChange(from, to State) {
+	cb.listenersMu.RLock()
+	listeners := cb.listeners
+	cb.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(cb.name, from, to)
+	}
 }
 
 // GetState 获取当前状态
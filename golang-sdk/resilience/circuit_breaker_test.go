@@ -283,3 +283,26 @@ func TestCircuitBreaker_BoundaryValues(t *testing.T) {
 		t.Errorf("Timeout = %v, should be >= 1ms", cb.GetTimeout())
 	}
 }
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	cb := NewCircuitBreaker("listener-test", 2, 1, 10*time.Millisecond)
+
+	var transitions []string
+	cb.OnStateChange(func(name string, from, to State) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	})
+
+	cb.RecordFailure()
+	cb.RecordFailure()
+	cb.Reset()
+
+	want := []string{"CLOSED->OPEN", "OPEN->CLOSED"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transition[%d] = %s, want %s", i, transitions[i], want[i])
+		}
+	}
+}
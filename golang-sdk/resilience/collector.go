@@ -0,0 +1,39 @@
+package resilience
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var circuitBreakerStateDesc = prometheus.NewDesc(
+	"framework_circuit_breaker_state",
+	"Circuit breaker state, 0 closed, 1 open, 2 half-open",
+	[]string{"service", "name"}, nil,
+)
+
+// circuitBreakerCollector 熔断器状态采集器
+type circuitBreakerCollector struct {
+	serviceName string
+	breakers    []*CircuitBreaker
+}
+
+// NewCircuitBreakerCollector 创建熔断器状态采集器，在采集时读取熔断器的当前状态，导出 framework_circuit_breaker_state，
+// 通过 prometheus.MustRegister 注册；observability 生成的仪表盘熔断器面板使用该指标
+func NewCircuitBreakerCollector(serviceName string, breakers ...*CircuitBreaker) prometheus.Collector {
+	return &circuitBreakerCollector{
+		serviceName: serviceName,
+		breakers:    breakers,
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *circuitBreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- circuitBreakerStateDesc
+}
+
+// Collect 实现 prometheus.Collector
+func (c *circuitBreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, cb := range c.breakers {
+		ch <- prometheus.MustNewConstMetric(circuitBreakerStateDesc, prometheus.GaugeValue,
+			float64(cb.GetState()), c.serviceName, cb.GetName())
+	}
+}
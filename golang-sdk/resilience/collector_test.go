@@ -0,0 +1,27 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCircuitBreakerCollector(t *testing.T) {
+	tripped := NewCircuitBreaker("payments", 1, 1, time.Minute)
+	tripped.RecordFailure()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCircuitBreakerCollector("order-service", tripped))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	if len(families) != 1 || families[0].GetName() != "framework_circuit_breaker_state" {
+		t.Fatalf("Unexpected metric families: %v", families)
+	}
+	if got := families[0].GetMetric()[0].GetGauge().GetValue(); got != float64(StateOpen) {
+		t.Errorf("Expected breaker state open, got %v", got)
+	}
+}
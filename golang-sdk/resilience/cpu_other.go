@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package resilience
+
+import "time"
+
+// processCPUTime 当前平台不支持读取进程 CPU 时间，CPU 使用率不参与过载判断
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,37 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+)
+
+// CircuitBreakerCheck 熔断器检查，实现 observability.HealthCheck，可通过 HealthChecker.RegisterCheck 注册
+type CircuitBreakerCheck struct {
+	maxOpen  int
+	breakers []*CircuitBreaker
+}
+
+// NewCircuitBreakerCheck 创建熔断器检查，打开的熔断器数量超过 maxOpen 时检查失败
+func NewCircuitBreakerCheck(maxOpen int, breakers ...*CircuitBreaker) *CircuitBreakerCheck {
+	return &CircuitBreakerCheck{maxOpen: maxOpen, breakers: breakers}
+}
+
+// Name 返回检查名称 circuit-breakers
+func (c *CircuitBreakerCheck) Name() string {
+	return "circuit-breakers"
+}
+
+// Check 打开的熔断器数量不超过 maxOpen 时返回 nil
+func (c *CircuitBreakerCheck) Check(ctx context.Context) error {
+	var open []string
+	for _, cb := range c.breakers {
+		if cb.GetState() == StateOpen {
+			open = append(open, cb.GetName())
+		}
+	}
+
+	if len(open) > c.maxOpen {
+		return fmt.Errorf("%d circuit breakers open: %v", len(open), open)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerCheck(t *testing.T) {
+	healthy := NewCircuitBreaker("healthy", 1, 1, time.Minute)
+	tripped := NewCircuitBreaker("tripped", 1, 1, time.Minute)
+	tripped.RecordFailure()
+
+	// 允许一个熔断器打开
+	if err := NewCircuitBreakerCheck(1, healthy, tripped).Check(context.Background()); err != nil {
+		t.Errorf("expected check to pass with one open breaker allowed: %v", err)
+	}
+
+	// 不允许熔断器打开
+	if err := NewCircuitBreakerCheck(0, healthy, tripped).Check(context.Background()); err == nil {
+		t.Error("expected check to fail with open breaker")
+	}
+}
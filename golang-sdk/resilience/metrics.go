@@ -0,0 +1,77 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	resilienceMetricsOnce sync.Once
+	// 请求在接收队列中的等待时间
+	acceptQueueWaitSeconds *prometheus.HistogramVec
+	// 接收队列丢弃的请求数
+	acceptQueueDroppedTotal *prometheus.CounterVec
+	// 接收队列中等待的请求数
+	acceptQueueDepth *prometheus.GaugeVec
+)
+
+// 接收队列指标的 outcome 和 reason 标签
+const (
+	acceptOutcomeAdmitted = "admitted"
+	acceptOutcomeExpired  = "expired"
+	acceptOutcomeCanceled = "canceled"
+	acceptReasonFull      = "full"
+)
+
+// initResilienceMetrics 初始化容错组件指标
+func initResilienceMetrics() {
+	resilienceMetricsOnce.Do(func() {
+		acceptQueueWaitSeconds = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_accept_queue_wait_seconds",
+				Help:    "Time requests spent in the accept queue by outcome (admitted, expired, canceled)",
+				Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"queue", "outcome"},
+		)
+		acceptQueueDroppedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_accept_queue_dropped_total",
+				Help: "Total number of requests dropped by the accept queue by reason (full, expired, canceled)",
+			},
+			[]string{"queue", "reason"},
+		)
+		acceptQueueDepth = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_accept_queue_depth",
+				Help: "Number of requests waiting in the accept queue",
+			},
+			[]string{"queue"},
+		)
+	})
+}
+
+// observeAcceptWait 记录请求的等待时间，未被处理的请求同时计入丢弃数
+func observeAcceptWait(queue, outcome string, wait time.Duration) {
+	initResilienceMetrics()
+	acceptQueueWaitSeconds.WithLabelValues(queue, outcome).Observe(wait.Seconds())
+	if outcome != acceptOutcomeAdmitted {
+		acceptQueueDroppedTotal.WithLabelValues(queue, outcome).Inc()
+	}
+}
+
+// recordAcceptQueueFull 记录队列已满被拒绝的请求
+func recordAcceptQueueFull(queue string) {
+	initResilienceMetrics()
+	acceptQueueDroppedTotal.WithLabelValues(queue, acceptReasonFull).Inc()
+}
+
+// setAcceptQueueDepth 设置队列中等待的请求数
+func setAcceptQueueDepth(queue string, depth int) {
+	initResilienceMetrics()
+	acceptQueueDepth.WithLabelValues(queue).Set(float64(depth))
+}
@@ -0,0 +1,239 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+// OverloadLevel 过载级别
+type OverloadLevel int32
+
+const (
+	// OverloadNone 未过载，允许所有请求
+	OverloadNone OverloadLevel = iota
+	// OverloadShedBatch 压力超过阈值，拒绝批处理请求
+	OverloadShedBatch
+	// OverloadShedAll 压力超过阈值的 CriticalRatio 倍，交互请求也被拒绝
+	OverloadShedAll
+)
+
+// String 返回过载级别的字符串表示
+func (l OverloadLevel) String() string {
+	switch l {
+	case OverloadNone:
+		return "NONE"
+	case OverloadShedBatch:
+		return "SHED_BATCH"
+	case OverloadShedAll:
+		return "SHED_ALL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// 过载控制的默认配置
+const (
+	DefaultOverloadInterval      = time.Second
+	DefaultOverloadRetryAfter    = time.Second
+	DefaultOverloadCriticalRatio = 1.5
+)
+
+// overloadRecoveryRatio 压力降到进入当前级别的阈值的该比例以下时才降低级别，避免在阈值附近反复切换
+const overloadRecoveryRatio = 0.9
+
+// OverloadConfig 过载控制配置，上限为 0 的指标不参与判断
+type OverloadConfig struct {
+	// MaxCPU 进程 CPU 使用率上限（0~1，相对于全部 CPU 核）
+	MaxCPU float64
+	// MaxGoroutines goroutine 数上限
+	MaxGoroutines int
+	// MaxQueueDepth QueueDepths 中任一队列深度的上限
+	MaxQueueDepth int
+	// QueueDepths 按名称返回队列深度的函数，如处理中的请求数、消息中间件待处理的消息数
+	QueueDepths map[string]func() int
+	// CriticalRatio 压力达到上限的多少倍时交互请求也被拒绝，为 0 时使用 DefaultOverloadCriticalRatio
+	CriticalRatio float64
+	// Interval 采样周期，为 0 时使用 DefaultOverloadInterval
+	Interval time.Duration
+	// RetryAfter 拒绝请求时建议调用方等待后重试的时间，为 0 时使用 DefaultOverloadRetryAfter
+	RetryAfter time.Duration
+	// OnChange 过载级别变化时调用
+	OnChange func(from, to OverloadLevel, pressure Pressure)
+}
+
+// Pressure 一次采样的系统压力
+type Pressure struct {
+	// CPU 两次采样之间进程的 CPU 使用率（0~1），当前平台不支持时为 0
+	CPU float64
+	// Goroutines goroutine 数
+	Goroutines int
+	// QueueDepths 各队列的深度
+	QueueDepths map[string]int
+	// Ratio 各指标与上限之比的最大值，达到 1 时过载
+	Ratio float64
+}
+
+// OverloadController 过载控制器
+//
+// 定期采样进程 CPU 使用率、goroutine 数和队列深度，任一指标超过上限时先拒绝批处理请求，
+// 超过上限的 CriticalRatio 倍时拒绝所有请求，在进程被压垮之前主动减载。
+// 被拒绝的请求返回 ServiceUnavailable 错误，并以 errors.FieldRetryAfter 建议调用方稍后重试
+type OverloadController struct {
+	name   string
+	config OverloadConfig
+
+	level    atomic.Int32
+	pressure atomic.Value // Pressure
+
+	mu         sync.Mutex
+	lastCPU    time.Duration
+	lastSample time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOverloadController 创建过载控制器并开始定期采样，Close 停止采样
+func NewOverloadController(name string, config *OverloadConfig) *OverloadController {
+	c := &OverloadController{
+		name:   name,
+		config: *config,
+		done:   make(chan struct{}),
+	}
+	if c.config.CriticalRatio <= 1 {
+		c.config.CriticalRatio = DefaultOverloadCriticalRatio
+	}
+	if c.config.Interval <= 0 {
+		c.config.Interval = DefaultOverloadInterval
+	}
+	if c.config.RetryAfter <= 0 {
+		c.config.RetryAfter = DefaultOverloadRetryAfter
+	}
+	c.pressure.Store(Pressure{})
+	c.lastCPU, _ = processCPUTime()
+	c.lastSample = time.Now()
+
+	go c.loop()
+	return c
+}
+
+// Allow 按当前过载级别和 context 中的优先级检查是否接受请求，拒绝时返回带重试等待时间的 ServiceUnavailable 错误
+func (c *OverloadController) Allow(ctx context.Context) error {
+	level := c.Level()
+	if level == OverloadNone {
+		return nil
+	}
+	priority := metadata.PriorityFromContext(ctx)
+	if level == OverloadShedBatch && priority != metadata.PriorityBatch {
+		return nil
+	}
+	return errors.NewFrameworkError(
+		errors.ServiceUnavailable,
+		fmt.Sprintf("服务 [%s] 过载，%s 请求被拒绝", c.name, priority),
+	).WithRetryAfter(c.config.RetryAfter)
+}
+
+// Level 返回当前的过载级别
+func (c *OverloadController) Level() OverloadLevel {
+	return OverloadLevel(c.level.Load())
+}
+
+// Pressure 返回最近一次采样的系统压力
+func (c *OverloadController) Pressure() Pressure {
+	return c.pressure.Load().(Pressure)
+}
+
+// Sample 立即采样并更新过载级别，返回采样结果
+func (c *OverloadController) Sample() Pressure {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pressure := Pressure{Goroutines: runtime.NumGoroutine()}
+	now := time.Now()
+	if cpu, ok := processCPUTime(); ok {
+		if elapsed := now.Sub(c.lastSample); elapsed > 0 {
+			pressure.CPU = float64(cpu-c.lastCPU) / float64(elapsed) / float64(runtime.NumCPU())
+		}
+		c.lastCPU = cpu
+	}
+	c.lastSample = now
+
+	if c.config.MaxCPU > 0 {
+		pressure.Ratio = math.Max(pressure.Ratio, pressure.CPU/c.config.MaxCPU)
+	}
+	if c.config.MaxGoroutines > 0 {
+		pressure.Ratio = math.Max(pressure.Ratio, float64(pressure.Goroutines)/float64(c.config.MaxGoroutines))
+	}
+	if len(c.config.QueueDepths) > 0 {
+		pressure.QueueDepths = make(map[string]int, len(c.config.QueueDepths))
+		for name, depth := range c.config.QueueDepths {
+			pressure.QueueDepths[name] = depth()
+			if c.config.MaxQueueDepth > 0 {
+				pressure.Ratio = math.Max(pressure.Ratio, float64(pressure.QueueDepths[name])/float64(c.config.MaxQueueDepth))
+			}
+		}
+	}
+	c.pressure.Store(pressure)
+
+	from := c.Level()
+	to := c.levelFor(from, pressure.Ratio)
+	if to != from {
+		c.level.Store(int32(to))
+		if c.config.OnChange != nil {
+			c.config.OnChange(from, to, pressure)
+		}
+	}
+	return pressure
+}
+
+// Close 停止采样，已过载时保持最后的过载级别
+func (c *OverloadController) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// levelFor 按压力比例计算过载级别，降低级别时要求压力低于进入当前级别的阈值的 overloadRecoveryRatio
+func (c *OverloadController) levelFor(current OverloadLevel, ratio float64) OverloadLevel {
+	level := OverloadNone
+	switch {
+	case ratio >= c.config.CriticalRatio:
+		level = OverloadShedAll
+	case ratio >= 1:
+		level = OverloadShedBatch
+	}
+	if level >= current {
+		return level
+	}
+
+	threshold := 1.0
+	if current == OverloadShedAll {
+		threshold = c.config.CriticalRatio
+	}
+	if ratio >= threshold*overloadRecoveryRatio {
+		return current
+	}
+	return level
+}
+
+// loop 按采样周期采样，直到 Close
+func (c *OverloadController) loop() {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.Sample()
+		case <-c.done:
+			return
+		}
+	}
+}
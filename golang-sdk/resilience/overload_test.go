@@ -0,0 +1,97 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+func TestOverloadControllerShedsBatchFirst(t *testing.T) {
+	depth := 0
+	var changes []OverloadLevel
+	c := NewOverloadController("test", &OverloadConfig{
+		MaxQueueDepth: 10,
+		QueueDepths:   map[string]func() int{"in-flight": func() int { return depth }},
+		Interval:      time.Hour,
+		RetryAfter:    2 * time.Second,
+		OnChange: func(from, to OverloadLevel, pressure Pressure) {
+			changes = append(changes, to)
+		},
+	})
+	defer c.Close()
+	interactive := context.Background()
+	batch := metadata.WithPriority(context.Background(), metadata.PriorityBatch)
+
+	c.Sample()
+	if c.Allow(interactive) != nil || c.Allow(batch) != nil {
+		t.Fatal("Expected all requests to be allowed without pressure")
+	}
+
+	// 超过上限时只拒绝批处理请求
+	depth = 12
+	if pressure := c.Sample(); pressure.QueueDepths["in-flight"] != 12 || pressure.Ratio != 1.2 {
+		t.Errorf("unexpected pressure: %+v", pressure)
+	}
+	if c.Level() != OverloadShedBatch {
+		t.Fatalf("Level = %v, want SHED_BATCH", c.Level())
+	}
+	if err := c.Allow(interactive); err != nil {
+		t.Errorf("Expected interactive request to be allowed, got %v", err)
+	}
+	err := c.Allow(batch)
+	if errorCode(err) != errors.ServiceUnavailable {
+		t.Fatalf("Expected ServiceUnavailable, got %v", err)
+	}
+	if retryAfter, ok := errors.RetryAfter(err); !ok || retryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, %v, want 2s", retryAfter, ok)
+	}
+
+	// 超过上限的 CriticalRatio 倍时拒绝所有请求
+	depth = 20
+	c.Sample()
+	if err := c.Allow(interactive); errorCode(err) != errors.ServiceUnavailable {
+		t.Errorf("Expected interactive request to be rejected, got %v", err)
+	}
+
+	// 压力略低于阈值时保持级别，降到阈值的 90% 以下才恢复
+	depth = 14
+	c.Sample()
+	if c.Level() != OverloadShedAll {
+		t.Errorf("Level = %v, want SHED_ALL", c.Level())
+	}
+	depth = 5
+	c.Sample()
+	if c.Level() != OverloadNone {
+		t.Errorf("Level = %v, want NONE", c.Level())
+	}
+
+	want := []OverloadLevel{OverloadShedBatch, OverloadShedAll, OverloadNone}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestOverloadControllerGoroutines(t *testing.T) {
+	c := NewOverloadController("test", &OverloadConfig{MaxGoroutines: 1, Interval: 10 * time.Millisecond})
+	defer c.Close()
+
+	// 后台采样发现 goroutine 数超过上限
+	deadline := time.Now().Add(time.Second)
+	for c.Level() == OverloadNone && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c.Level() != OverloadShedAll {
+		t.Errorf("Level = %v, want SHED_ALL", c.Level())
+	}
+	if c.Pressure().Goroutines < 2 {
+		t.Errorf("unexpected pressure: %+v", c.Pressure())
+	}
+}
@@ -0,0 +1,102 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+// Limiter 限流器，RateLimiter 在进程内限流，RedisRateLimiter 在多个实例间共享限额
+type Limiter interface {
+	// Allow 按 context 中的优先级取得一个令牌，取不到时返回 false
+	Allow(ctx context.Context) bool
+}
+
+// RateLimiter 令牌桶限流器
+//
+// 令牌以 rate 个/秒的速度补充，最多积累 burst 个，每个请求消耗一个令牌。
+// 请求的优先级由 metadata.PriorityFromContext 确定：桶中令牌不多于 burst*batchReserve 时
+// 只有交互请求能取得令牌，批处理请求先被限流
+type RateLimiter struct {
+	name    string
+	rate    float64
+	burst   float64
+	reserve float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter 创建限流器，初始时令牌桶是满的
+//
+// batchReserve 为保留给交互请求的令牌比例（0~1），为 0 时不区分优先级
+func NewRateLimiter(name string, rate float64, burst int, batchReserve float64) *RateLimiter {
+	if rate <= 0 {
+		rate = 1
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	if batchReserve < 0 {
+		batchReserve = 0
+	}
+	if batchReserve > 1 {
+		batchReserve = 1
+	}
+
+	l := &RateLimiter{
+		name:    name,
+		rate:    rate,
+		burst:   float64(burst),
+		reserve: math.Ceil(float64(burst) * batchReserve),
+		now:     time.Now,
+	}
+	l.tokens = l.burst
+	l.last = l.now()
+	return l
+}
+
+// Allow 按 context 中的优先级取得一个令牌，取不到时返回 false
+func (l *RateLimiter) Allow(ctx context.Context) bool {
+	priority := metadata.PriorityFromContext(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	required := 1.0
+	if priority == metadata.PriorityBatch {
+		required += l.reserve
+	}
+	if l.tokens < required {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Execute 取得令牌后执行操作，被限流时不执行并返回 ServiceUnavailable 错误
+func (l *RateLimiter) Execute(ctx context.Context, operation func() error) error {
+	if !l.Allow(ctx) {
+		return rateLimited(ctx, l.name)
+	}
+	return operation()
+}
+
+// rateLimited 返回请求被限流的错误
+func rateLimited(ctx context.Context, name string) error {
+	return errors.NewFrameworkError(
+		errors.ServiceUnavailable,
+		fmt.Sprintf("限流器 [%s] 令牌不足，%s 请求被拒绝", name, metadata.PriorityFromContext(ctx)),
+	)
+}
@@ -0,0 +1,48 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+func TestRateLimiterShedsBatchFirst(t *testing.T) {
+	l := NewRateLimiter("test", 10, 10, 0.3)
+	now := l.last
+	l.now = func() time.Time { return now }
+	batch := metadata.WithPriority(context.Background(), metadata.PriorityBatch)
+
+	// 保留 3 个令牌给交互请求
+	allowed := 0
+	for l.Allow(batch) {
+		allowed++
+	}
+	if allowed != 7 {
+		t.Errorf("batch allowed = %d, want 7", allowed)
+	}
+	for i := 0; i < 3; i++ {
+		if !l.Allow(context.Background()) {
+			t.Fatalf("interactive request %d should be allowed", i)
+		}
+	}
+	if l.Allow(context.Background()) {
+		t.Error("Expected interactive request to be limited when bucket is empty")
+	}
+
+	err := l.Execute(context.Background(), func() error { return nil })
+	if errorCode(err) != errors.ServiceUnavailable {
+		t.Errorf("Expected ServiceUnavailable, got %v", err)
+	}
+
+	// 补充 0.2 秒的令牌后交互请求恢复，批处理请求仍需等待令牌超过保留量
+	now = now.Add(200 * time.Millisecond)
+	if l.Allow(batch) {
+		t.Error("Expected batch request to be limited below the reserve")
+	}
+	if !l.Allow(context.Background()) {
+		t.Error("Expected interactive request to be allowed after refill")
+	}
+}
@@ -0,0 +1,357 @@
+package resilience
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/resp"
+	"github.com/framework/golang-sdk/metadata"
+)
+
+// DefaultRateLimitKeyPrefix RedisRateLimiter 限流键的默认前缀
+const DefaultRateLimitKeyPrefix = "ratelimit:"
+
+// maxIdleRateLimitConns RedisRateLimiter 保留的空闲连接数上限，并发调用超出时临时建立的连接用完即关闭
+const maxIdleRateLimitConns = 16
+
+// tokenBucketScript 令牌桶：按 Redis 服务器时间补充令牌，令牌不少于 required 时消耗一个
+//
+// KEYS[1] 限流键；ARGV 为补充速率（个/秒）、容量、所需令牌数、键的过期时间（毫秒）
+const tokenBucketScript = `
+redis.replicate_commands()
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local required = tonumber(ARGV[3])
+local now = redis.call('TIME')
+local ms = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = ms
+end
+tokens = math.min(burst, tokens + math.max(0, ms - ts) * rate / 1000)
+local allowed = 0
+if tokens >= required then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ms))
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return allowed
+`
+
+// slidingWindowScript 滑动窗口：有序集合记录窗口内每个请求的时间，窗口内请求数加保留数小于上限时记录本次请求
+//
+// KEYS[1] 限流键；ARGV 为窗口（毫秒）、上限、为交互请求保留的数量、本次请求的唯一成员
+const slidingWindowScript = `
+redis.replicate_commands()
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local reserve = tonumber(ARGV[3])
+local now = redis.call('TIME')
+local ms = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ms - window)
+if redis.call('ZCARD', KEYS[1]) + reserve >= limit then
+  return 0
+end
+redis.call('ZADD', KEYS[1], ms, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return 1
+`
+
+// RedisRateLimiterConfig Redis 限流器配置
+type RedisRateLimiterConfig struct {
+	// Address 服务器地址，默认为 127.0.0.1:6379
+	Address string
+	// Username/Password 认证信息，Username 为空时使用旧版 AUTH password
+	Username string
+	Password string
+	// DB 数据库编号
+	DB int
+	// KeyPrefix 限流键的前缀，默认为 DefaultRateLimitKeyPrefix，键为 <KeyPrefix><限流器名称>
+	KeyPrefix string
+	// Timeout 连接和命令超时，默认为 100 毫秒；限流在请求路径上，超时后使用本地限流
+	Timeout time.Duration
+
+	// Rate 所有实例合计每秒允许的请求数
+	Rate float64
+	// Burst 令牌桶容量，Window 为 0 时有效
+	Burst int
+	// Window 不为 0 时使用滑动窗口：任意 Window 时长内最多 Rate*Window 个请求；为 0 时使用令牌桶
+	Window time.Duration
+	// BatchReserve 保留给交互请求的比例（0~1），见 RateLimiter
+	BatchReserve float64
+
+	// FallbackRate/FallbackBurst Redis 不可用时本实例的本地限流速率和容量，默认与 Rate/Burst 相同；
+	// 多实例部署时通常设为 Rate 除以实例数
+	FallbackRate  float64
+	FallbackBurst int
+	// RetryInterval Redis 出错后使用本地限流的时间，到期后再次尝试 Redis，默认为 1 秒
+	RetryInterval time.Duration
+	// OnFallback Redis 出错、改用本地限流时调用
+	OnFallback func(err error)
+}
+
+// RedisRateLimiter 基于 Redis 的分布式限流器，多个实例共享同一限额
+//
+// 限流逻辑以 Lua 脚本在 Redis 中原子执行，使用 Redis 服务器时间，不受各实例时钟偏差影响（需要 Redis 3.2 及以上版本）。
+// Redis 不可用时在 RetryInterval 内改用本地的 RateLimiter，请求不会因限流器故障而全部失败。
+// 每次调用从空闲连接中取出一个连接执行脚本，并发调用各自使用独立的连接，不会排队等待其他调用的网络往返
+type RedisRateLimiter struct {
+	name    string
+	config  RedisRateLimiterConfig
+	key     string
+	script  string
+	sha     string
+	local   *RateLimiter
+	limit   int
+	reserve int
+	id      string
+	seq     atomic.Uint64
+
+	mu        sync.Mutex
+	idle      []*resp.Conn
+	downUntil time.Time
+	closed    bool
+}
+
+// NewRedisRateLimiter 创建 Redis 限流器，首次调用 Allow 时连接 Redis
+func NewRedisRateLimiter(name string, config *RedisRateLimiterConfig) *RedisRateLimiter {
+	cfg := RedisRateLimiterConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:6379"
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = DefaultRateLimitKeyPrefix
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 100 * time.Millisecond
+	}
+	if cfg.Rate <= 0 {
+		cfg.Rate = 1
+	}
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	if cfg.BatchReserve < 0 {
+		cfg.BatchReserve = 0
+	}
+	if cfg.BatchReserve > 1 {
+		cfg.BatchReserve = 1
+	}
+	if cfg.FallbackRate <= 0 {
+		cfg.FallbackRate = cfg.Rate
+	}
+	if cfg.FallbackBurst < 1 {
+		cfg.FallbackBurst = cfg.Burst
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = time.Second
+	}
+
+	l := &RedisRateLimiter{
+		name:   name,
+		config: cfg,
+		key:    cfg.KeyPrefix + name,
+		script: tokenBucketScript,
+		local:  NewRateLimiter(name, cfg.FallbackRate, cfg.FallbackBurst, cfg.BatchReserve),
+		id:     newLimiterID(),
+	}
+	if cfg.Window > 0 {
+		l.script = slidingWindowScript
+		l.limit = int(math.Max(1, math.Floor(cfg.Rate*cfg.Window.Seconds())))
+		l.reserve = int(math.Ceil(float64(l.limit) * cfg.BatchReserve))
+	} else {
+		l.reserve = int(math.Ceil(float64(cfg.Burst) * cfg.BatchReserve))
+	}
+	sum := sha1.Sum([]byte(l.script))
+	l.sha = hex.EncodeToString(sum[:])
+	return l
+}
+
+// Allow 按 context 中的优先级在 Redis 中取得一个令牌，Redis 不可用时使用本地限流
+func (l *RedisRateLimiter) Allow(ctx context.Context) bool {
+	batch := metadata.PriorityFromContext(ctx) == metadata.PriorityBatch
+	allowed, err := l.allowRemote(batch)
+	if err != nil {
+		return l.local.Allow(ctx)
+	}
+	return allowed
+}
+
+// Execute 取得令牌后执行操作，被限流时不执行并返回 ServiceUnavailable 错误
+func (l *RedisRateLimiter) Execute(ctx context.Context, operation func() error) error {
+	if !l.Allow(ctx) {
+		return rateLimited(ctx, l.name)
+	}
+	return operation()
+}
+
+// Close 关闭空闲的 Redis 连接，之后只使用本地限流；使用中的连接在调用结束时关闭
+func (l *RedisRateLimiter) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	idle := l.idle
+	l.idle = nil
+	l.mu.Unlock()
+
+	var err error
+	for _, conn := range idle {
+		if closeErr := conn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// allowRemote 在 Redis 中执行限流脚本，Redis 不可用或处于重试间隔内时返回错误
+//
+// 锁只用于取出和归还连接，连接、执行脚本等网络操作在锁外进行
+func (l *RedisRateLimiter) allowRemote(batch bool) (bool, error) {
+	conn, err := l.acquire()
+	if err != nil {
+		return false, err
+	}
+	if conn == nil {
+		if conn, err = l.dial(); err != nil {
+			l.fail(err)
+			return false, err
+		}
+	}
+
+	reply, err := l.eval(conn, l.args(batch)...)
+	if err != nil && resp.ErrorOf(err) == "" {
+		conn.Close()
+	} else {
+		l.release(conn)
+	}
+	if err != nil {
+		l.fail(err)
+		return false, err
+	}
+	allowed, _ := reply.(int64)
+	return allowed == 1, nil
+}
+
+// acquire 取出一个空闲连接，没有空闲连接时返回 nil；已关闭或处于重试间隔内时返回错误
+func (l *RedisRateLimiter) acquire() (*resp.Conn, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, errors.New("rate limiter closed")
+	}
+	if time.Now().Before(l.downUntil) {
+		return nil, errors.New("redis unavailable")
+	}
+	n := len(l.idle)
+	if n == 0 {
+		return nil, nil
+	}
+	conn := l.idle[n-1]
+	l.idle = l.idle[:n-1]
+	return conn, nil
+}
+
+// release 归还连接，已关闭或空闲连接已满时关闭连接
+func (l *RedisRateLimiter) release(conn *resp.Conn) {
+	l.mu.Lock()
+	if !l.closed && len(l.idle) < maxIdleRateLimitConns {
+		l.idle = append(l.idle, conn)
+		conn = nil
+	}
+	l.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// fail 记录 Redis 出错，RetryInterval 内使用本地限流；连接出错时关闭空闲连接，重试时重新连接
+//
+// 并发调用同时出错时只在首次进入重试间隔时调用 OnFallback
+func (l *RedisRateLimiter) fail(err error) {
+	now := time.Now()
+	l.mu.Lock()
+	down := now.Before(l.downUntil)
+	l.downUntil = now.Add(l.config.RetryInterval)
+	var idle []*resp.Conn
+	if resp.ErrorOf(err) == "" {
+		idle = l.idle
+		l.idle = nil
+	}
+	l.mu.Unlock()
+
+	for _, conn := range idle {
+		conn.Close()
+	}
+	if !down && l.config.OnFallback != nil {
+		l.config.OnFallback(fmt.Errorf("rate limiter %s: %w", l.name, err))
+	}
+}
+
+// args 返回限流脚本的键和参数
+func (l *RedisRateLimiter) args(batch bool) []interface{} {
+	if l.config.Window > 0 {
+		reserve := 0
+		if batch {
+			reserve = l.reserve
+		}
+		member := l.id + ":" + strconv.FormatUint(l.seq.Add(1), 10)
+		return []interface{}{l.key, int64(l.config.Window / time.Millisecond), l.limit, reserve, member}
+	}
+
+	required := 1
+	if batch {
+		required += l.reserve
+	}
+	// 令牌补满所需时间之后桶的状态与不存在时相同，键随之过期
+	ttl := int64(math.Ceil(float64(l.config.Burst)/l.config.Rate*1000)) + 1000
+	return []interface{}{l.key, strconv.FormatFloat(l.config.Rate, 'f', -1, 64), l.config.Burst, required, ttl}
+}
+
+// dial 按配置连接 Redis
+func (l *RedisRateLimiter) dial() (*resp.Conn, error) {
+	return resp.Dial(&resp.Config{
+		Address:  l.config.Address,
+		Username: l.config.Username,
+		Password: l.config.Password,
+		DB:       l.config.DB,
+		Timeout:  l.config.Timeout,
+	})
+}
+
+// eval 在 conn 上以 EVALSHA 执行脚本，Redis 中没有缓存脚本时以 EVAL 执行并缓存
+func (l *RedisRateLimiter) eval(conn *resp.Conn, args ...interface{}) (interface{}, error) {
+	command := append([]interface{}{"EVALSHA", l.sha, 1}, args...)
+	reply, err := conn.Do(0, command...)
+	if strings.HasPrefix(string(resp.ErrorOf(err)), "NOSCRIPT") {
+		command[0], command[1] = "EVAL", l.script
+		reply, err = conn.Do(0, command...)
+	}
+	return reply, err
+}
+
+// newLimiterID 返回区分各实例滑动窗口成员的随机 ID
+func newLimiterID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
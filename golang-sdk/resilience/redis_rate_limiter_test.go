@@ -0,0 +1,262 @@
+package resilience
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/metadata"
+)
+
+// fakeRedis 按顺序返回预设回复的 Redis 服务器，记录收到的命令
+type fakeRedis struct {
+	listener net.Listener
+	replies  []string
+
+	mu       sync.Mutex
+	commands [][]string
+}
+
+func newFakeRedis(t *testing.T, replies ...string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	f := &fakeRedis{listener: listener, replies: replies}
+	go f.serve()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		command, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, command)
+		reply := ":1"
+		if len(f.replies) > 0 {
+			reply, f.replies = f.replies[0], f.replies[1:]
+		}
+		f.mu.Unlock()
+		fmt.Fprintf(conn, "%s\r\n", reply)
+	}
+}
+
+// received 返回收到的命令
+func (f *fakeRedis) received() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.commands...)
+}
+
+// readCommand 读取 RESP 数组编码的命令
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestRedisRateLimiterScript(t *testing.T) {
+	redis := newFakeRedis(t, "-NOSCRIPT No matching script. Please use EVAL.", ":1", ":0")
+	l := NewRedisRateLimiter("orders", &RedisRateLimiterConfig{Address: redis.listener.Addr().String(), Rate: 10, Burst: 20, BatchReserve: 0.25})
+	defer l.Close()
+
+	// 脚本未缓存时以 EVAL 执行
+	if !l.Allow(context.Background()) {
+		t.Error("Expected first request to be allowed")
+	}
+	if l.Allow(metadata.WithPriority(context.Background(), metadata.PriorityBatch)) {
+		t.Error("Expected request to be limited by Redis")
+	}
+
+	commands := redis.received()
+	if len(commands) != 3 {
+		t.Fatalf("commands = %v, want 3", commands)
+	}
+	if commands[0][0] != "EVALSHA" || commands[1][0] != "EVAL" || commands[2][0] != "EVALSHA" {
+		t.Errorf("unexpected commands: %v %v %v", commands[0][0], commands[1][0], commands[2][0])
+	}
+	if commands[1][1] != tokenBucketScript || commands[1][3] != "ratelimit:orders" {
+		t.Errorf("unexpected EVAL arguments: %v", commands[1][2:])
+	}
+	// 交互请求需要 1 个令牌，批处理请求还需保留 5 个
+	if commands[1][6] != "1" || commands[2][6] != "6" {
+		t.Errorf("required tokens = %s, %s, want 1, 6", commands[1][6], commands[2][6])
+	}
+}
+
+func TestRedisRateLimiterSlidingWindow(t *testing.T) {
+	redis := newFakeRedis(t, ":1", ":1")
+	l := NewRedisRateLimiter("orders", &RedisRateLimiterConfig{Address: redis.listener.Addr().String(), Rate: 100, Window: 2 * time.Second, BatchReserve: 0.1})
+	defer l.Close()
+
+	l.Allow(context.Background())
+	l.Allow(context.Background())
+	commands := redis.received()
+	if len(commands) != 2 {
+		t.Fatalf("commands = %v, want 2", commands)
+	}
+	// 窗口 2000 毫秒，上限 200，交互请求不保留
+	if args := commands[0][4:8]; args[0] != "2000" || args[1] != "200" || args[2] != "0" {
+		t.Errorf("unexpected arguments: %v", args)
+	}
+	if commands[0][7] == commands[1][7] {
+		t.Errorf("Expected unique members, got %s twice", commands[0][7])
+	}
+}
+
+// TestRedisRateLimiterConcurrent 测试并发调用使用各自的连接，不等待其他调用的网络往返
+func TestRedisRateLimiterConcurrent(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	// 收到的命令在 release 关闭后才回复
+	arrived := make(chan struct{}, 2)
+	release := make(chan struct{})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					if _, err := readCommand(reader); err != nil {
+						return
+					}
+					arrived <- struct{}{}
+					<-release
+					fmt.Fprint(conn, ":1\r\n")
+				}
+			}()
+		}
+	}()
+
+	l := NewRedisRateLimiter("orders", &RedisRateLimiterConfig{Address: listener.Addr().String(), Rate: 10, Burst: 20, Timeout: 5 * time.Second})
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	results := make(chan bool, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- l.Allow(context.Background())
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-arrived:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected concurrent calls to reach Redis without waiting for each other")
+		}
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+	for allowed := range results {
+		if !allowed {
+			t.Error("Expected request to be allowed")
+		}
+	}
+
+	// 用完的连接保留为空闲连接
+	l.mu.Lock()
+	idle := len(l.idle)
+	l.mu.Unlock()
+	if idle != 2 {
+		t.Errorf("idle connections = %d, want 2", idle)
+	}
+}
+
+func TestRedisRateLimiterFallback(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	listener.Close()
+
+	var fallbacks int
+	l := NewRedisRateLimiter("orders", &RedisRateLimiterConfig{
+		Address:       address,
+		Rate:          100,
+		Burst:         100,
+		FallbackRate:  1,
+		FallbackBurst: 2,
+		RetryInterval: time.Hour,
+		OnFallback:    func(err error) { fallbacks++ },
+	})
+	defer l.Close()
+
+	// Redis 不可用时使用本地限流，重试间隔内不再连接
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if l.Allow(context.Background()) {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed = %d, want 2", allowed)
+	}
+	if fallbacks != 1 {
+		t.Errorf("fallbacks = %d, want 1", fallbacks)
+	}
+}
+
+func TestRedisRateLimiter(t *testing.T) {
+	var unavailable error
+	l := NewRedisRateLimiter(fmt.Sprintf("framework-test-%d", time.Now().UnixNano()), &RedisRateLimiterConfig{
+		Rate:       1,
+		Burst:      3,
+		OnFallback: func(err error) { unavailable = err },
+	})
+	defer l.Close()
+
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if l.Allow(context.Background()) {
+			allowed++
+		}
+	}
+	if unavailable != nil {
+		t.Skipf("Skipping test: redis not available: %v", unavailable)
+	}
+	if allowed != 3 {
+		t.Errorf("allowed = %d, want 3", allowed)
+	}
+}
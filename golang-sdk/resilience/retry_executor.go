@@ -23,6 +23,19 @@ func NewRetryExecutor(policy *RetryPolicy) *RetryExecutor {
 	}
 }
 
+// ForMethod 返回按方法幂等性重试的执行器
+//
+// 幂等方法按策略重试；非幂等方法只执行一次，避免失败的请求实际已在服务端执行时重复产生副作用，
+// 策略设置 RetryNonIdempotent 时同样重试。方法是否幂等见 registry.MetadataIdempotentMethods
+func (r *RetryExecutor) ForMethod(idempotent bool) *RetryExecutor {
+	if idempotent || r.policy.RetryNonIdempotent || r.policy.MaxAttempts <= 1 {
+		return r
+	}
+	policy := *r.policy
+	policy.MaxAttempts = 1
+	return &RetryExecutor{policy: &policy}
+}
+
 // Execute 同步执行带重试的操作
 func (r *RetryExecutor) Execute(operation func() error) error {
 	var lastErr error
@@ -36,7 +49,7 @@ func (r *RetryExecutor) Execute(operation func() error) error {
 		lastErr = err
 
 		// 检查是否为可重试的错误
-		if fe, ok := err.(*errors.FrameworkError); ok {
+		if fe, ok := errors.FromError(err); ok {
 			if !r.policy.IsRetryable(fe.Code) || attempt >= r.policy.MaxAttempts-1 {
 				return err
 			}
@@ -66,7 +79,7 @@ func (r *RetryExecutor) ExecuteWithResult(operation func() (interface{}, error))
 		lastErr = err
 
 		// 检查是否为可重试的错误
-		if fe, ok := err.(*errors.FrameworkError); ok {
+		if fe, ok := errors.FromError(err); ok {
 			if !r.policy.IsRetryable(fe.Code) || attempt >= r.policy.MaxAttempts-1 {
 				return nil, err
 			}
@@ -128,7 +141,7 @@ func (r *RetryExecutor) executeAsyncInternal(ctx context.Context, operation func
 	}
 
 	// 检查是否为可重试的错误
-	if fe, ok := err.(*errors.FrameworkError); ok {
+	if fe, ok := errors.FromError(err); ok {
 		if r.policy.IsRetryable(fe.Code) && attempt < r.policy.MaxAttempts-1 {
 			delay := r.policy.CalculateDelay(attempt)
 			fmt.Printf("异步操作失败，第 %d 次重试，延迟 %v，错误: %s\n", attempt+1, delay, err.Error())
@@ -162,7 +175,7 @@ func (r *RetryExecutor) executeAsyncInternalWithResult(ctx context.Context, oper
 	}
 
 	// 检查是否为可重试的错误
-	if fe, ok := err.(*errors.FrameworkError); ok {
+	if fe, ok := errors.FromError(err); ok {
 		if r.policy.IsRetryable(fe.Code) && attempt < r.policy.MaxAttempts-1 {
 			delay := r.policy.CalculateDelay(attempt)
 			fmt.Printf("异步操作失败，第 %d 次重试，延迟 %v，错误: %s\n", attempt+1, delay, err.Error())
@@ -2,6 +2,7 @@ package resilience
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -49,6 +50,31 @@ func TestRetryExecutor_Execute_RetryableError(t *testing.T) {
 	}
 }
 
+func TestRetryExecutor_Execute_WrappedRetryableError(t *testing.T) {
+	policy := NewRetryPolicyBuilder().
+		MaxAttempts(3).
+		InitialDelay(10 * time.Millisecond).
+		Build()
+	executor := NewRetryExecutor(policy)
+
+	// 经 fmt.Errorf 包装的框架错误同样按错误码重试
+	callCount := 0
+	err := executor.Execute(func() error {
+		callCount++
+		if callCount < 3 {
+			return fmt.Errorf("call user service: %w", errors.NewFrameworkError(errors.ConnectionError, "连接失败"))
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Execute() error = %v, want nil", err)
+	}
+	if callCount != 3 {
+		t.Errorf("callCount = %v, want 3", callCount)
+	}
+}
+
 func TestRetryExecutor_Execute_NonRetryableError(t *testing.T) {
 	executor := NewRetryExecutor(DefaultRetryPolicy())
 
@@ -242,3 +268,45 @@ func TestRetryExecutor_NilPolicy(t *testing.T) {
 		t.Errorf("Execute() with nil policy error = %v, want nil", err)
 	}
 }
+
+func TestRetryExecutor_ForMethod(t *testing.T) {
+	policy := NewRetryPolicyBuilder().
+		MaxAttempts(3).
+		InitialDelay(time.Millisecond).
+		Build()
+
+	tests := []struct {
+		name               string
+		idempotent         bool
+		retryNonIdempotent bool
+		wantCalls          int
+	}{
+		{"幂等方法按策略重试", true, false, 3},
+		{"非幂等方法不重试", false, false, 1},
+		{"显式允许重试非幂等方法", false, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := *policy
+			p.RetryNonIdempotent = tt.retryNonIdempotent
+
+			callCount := 0
+			err := NewRetryExecutor(&p).ForMethod(tt.idempotent).Execute(func() error {
+				callCount++
+				return errors.NewFrameworkError(errors.ConnectionError, "连接失败")
+			})
+
+			if err == nil {
+				t.Error("Execute() error = nil, want error")
+			}
+			if callCount != tt.wantCalls {
+				t.Errorf("callCount = %v, want %v", callCount, tt.wantCalls)
+			}
+		})
+	}
+
+	if policy.MaxAttempts != 3 {
+		t.Errorf("ForMethod() modified policy MaxAttempts = %v", policy.MaxAttempts)
+	}
+}
@@ -14,6 +14,9 @@ type RetryPolicy struct {
 	MaxDelay       time.Duration
 	Multiplier     float64
 	RetryableErrors map[errors.ErrorCode]bool
+	// RetryNonIdempotent 为 true 时 RetryExecutor.ForMethod 同样重试未声明幂等的方法，
+	// 只应在方法的副作用可以安全重复（如服务端按请求 ID 去重）时设置
+	RetryNonIdempotent bool
 }
 
 // NewRetryPolicy 创建新的重试策略
@@ -42,8 +45,14 @@ func NoRetryPolicy() *RetryPolicy {
 }
 
 // IsRetryable 判断给定的错误码是否可重试
+//
+// 策略中列出的错误码以策略为准；未列出的自定义错误码按 errors.RegisterCode 注册时声明的可重试性判断，
+// 需要禁止重试某个可重试的自定义错误码时将其设为 false
 func (p *RetryPolicy) IsRetryable(code errors.ErrorCode) bool {
-	return p.RetryableErrors[code]
+	if retryable, ok := p.RetryableErrors[code]; ok {
+		return retryable
+	}
+	return errors.IsCustomCode(code) && code.IsRetryable()
 }
 
 // CalculateDelay 计算第 attempt 次重试的延迟时间（指数退避）
@@ -133,6 +142,12 @@ func (b *RetryPolicyBuilder) AddRetryableError(code errors.ErrorCode) *RetryPoli
 	return b
 }
 
+// RetryNonIdempotent 设置是否重试未声明幂等的方法
+func (b *RetryPolicyBuilder) RetryNonIdempotent(retry bool) *RetryPolicyBuilder {
+	b.policy.RetryNonIdempotent = retry
+	return b
+}
+
 // Build 构建重试策略
 func (b *RetryPolicyBuilder) Build() *RetryPolicy {
 	return b.policy
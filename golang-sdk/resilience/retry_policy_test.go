@@ -165,3 +165,34 @@ func TestRetryPolicyBuilder_BoundaryValues(t *testing.T) {
 		t.Errorf("Multiplier = %v, should be >= 1.0", policy.Multiplier)
 	}
 }
+
+func TestRetryPolicy_IsRetryable_CustomCode(t *testing.T) {
+	const rateLimited errors.ErrorCode = 1101
+	const invalidCoupon errors.ErrorCode = 1102
+	if err := errors.RegisterCode(errors.CodeDefinition{Code: rateLimited, Name: "Rate Limited", Retryable: true, HTTPStatus: 429}); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+	if err := errors.RegisterCode(errors.CodeDefinition{Code: invalidCoupon, Name: "Invalid Coupon", HTTPStatus: 400}); err != nil {
+		t.Fatalf("RegisterCode failed: %v", err)
+	}
+
+	policy := DefaultRetryPolicy()
+	if !policy.IsRetryable(rateLimited) {
+		t.Error("registered retryable code should be retryable")
+	}
+	if policy.IsRetryable(invalidCoupon) {
+		t.Error("registered non-retryable code should not be retryable")
+	}
+
+	// 策略中显式设置的值优先
+	policy.RetryableErrors[rateLimited] = false
+	if policy.IsRetryable(rateLimited) {
+		t.Error("policy override should disable retry")
+	}
+
+	// 策略未列出的内置错误码不重试
+	policy = NewRetryPolicyBuilder().RetryableErrors(errors.Timeout).Build()
+	if policy.IsRetryable(errors.ServiceUnavailable) {
+		t.Error("ServiceUnavailable should not be retryable when not listed")
+	}
+}
@@ -0,0 +1,340 @@
+package saga
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Saga span 属性
+const (
+	AttrSagaName = attribute.Key("saga.name")
+	AttrSagaID   = attribute.Key("saga.id")
+	AttrSagaStep = attribute.Key("saga.step")
+)
+
+// Error Saga 执行失败：某个步骤失败，已完成的步骤已补偿或补偿失败
+type Error struct {
+	Saga string
+	ID   string
+	// Step 失败的步骤名称
+	Step string
+	// Err 步骤失败的原因
+	Err error
+	// CompensationErr 补偿失败的原因，为 nil 时已完成的步骤均已补偿
+	CompensationErr error
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.CompensationErr != nil {
+		return fmt.Sprintf("saga %s (%s) step %s failed: %v; compensation failed: %v", e.Saga, e.ID, e.Step, e.Err, e.CompensationErr)
+	}
+	return fmt.Sprintf("saga %s (%s) step %s failed: %v", e.Saga, e.ID, e.Step, e.Err)
+}
+
+// Unwrap 返回步骤失败的原因
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Options 编排器选项
+type Options struct {
+	// Store 实例状态存储，为 nil 时使用 MemoryStore
+	Store Store
+	// RetryPolicy 步骤和补偿失败时的重试策略，为 nil 时使用 resilience.DefaultRetryPolicy；非框架错误不重试
+	RetryPolicy *resilience.RetryPolicy
+}
+
+// Orchestrator Saga 编排器
+//
+// 按顺序执行步骤，每个步骤完成后保存实例状态；步骤失败时按相反顺序补偿已完成的步骤。
+// 进程崩溃后调用 ResumePending 从 Store 中恢复未结束的实例：执行中的实例从未确认完成的步骤继续，
+// 补偿中的实例继续补偿
+type Orchestrator struct {
+	store       Store
+	retryPolicy *resilience.RetryPolicy
+
+	mu      sync.Mutex
+	sagas   map[string]*Definition
+	running map[string]bool
+}
+
+// NewOrchestrator 创建 Saga 编排器
+func NewOrchestrator(options *Options) *Orchestrator {
+	if options == nil {
+		options = &Options{}
+	}
+	o := &Orchestrator{
+		store:       options.Store,
+		retryPolicy: options.RetryPolicy,
+		sagas:       make(map[string]*Definition),
+		running:     make(map[string]bool),
+	}
+	if o.store == nil {
+		o.store = NewMemoryStore()
+	}
+	if o.retryPolicy == nil {
+		o.retryPolicy = resilience.DefaultRetryPolicy()
+	}
+	return o
+}
+
+// Register 注册 Saga 定义，恢复实例前须注册实例对应的定义
+func (o *Orchestrator) Register(definition *Definition) error {
+	if err := definition.validate(); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.sagas[definition.Name]; ok {
+		return fmt.Errorf("saga %s already registered", definition.Name)
+	}
+	o.sagas[definition.Name] = definition
+	return nil
+}
+
+// Start 创建 name Saga 的实例并执行，返回实例的最终状态
+//
+// 步骤失败并完成补偿时返回 *Error；ctx 取消时实例保持未结束状态，可通过 Resume 继续
+func (o *Orchestrator) Start(ctx context.Context, name string, data Data) (*Instance, error) {
+	definition, err := o.definition(name)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = make(Data)
+	}
+	now := time.Now()
+	instance := &Instance{
+		ID:        newInstanceID(),
+		Saga:      name,
+		Status:    StatusRunning,
+		Data:      data,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := o.acquire(instance.ID); err != nil {
+		return nil, err
+	}
+	defer o.release(instance.ID)
+
+	if err := o.store.Save(ctx, instance); err != nil {
+		return nil, fmt.Errorf("failed to save saga instance: %w", err)
+	}
+	return instance, o.run(ctx, definition, instance)
+}
+
+// Resume 从 Store 读取实例并继续执行，已结束的实例直接返回
+func (o *Orchestrator) Resume(ctx context.Context, id string) (*Instance, error) {
+	if err := o.acquire(id); err != nil {
+		return nil, err
+	}
+	defer o.release(id)
+
+	instance, err := o.store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Status.Finished() {
+		return instance, nil
+	}
+	definition, err := o.definition(instance.Saga)
+	if err != nil {
+		return instance, err
+	}
+	if instance.Completed > len(definition.Steps) {
+		return instance, fmt.Errorf("saga %s instance %s has %d completed steps, definition has %d",
+			instance.Saga, id, instance.Completed, len(definition.Steps))
+	}
+	if instance.Data == nil {
+		instance.Data = make(Data)
+	}
+	return instance, o.run(ctx, definition, instance)
+}
+
+// ResumePending 恢复 Store 中全部未结束的实例，通常在服务启动时调用，各实例的错误合并返回
+//
+// 同一 Store 的实例应只由一个进程恢复
+func (o *Orchestrator) ResumePending(ctx context.Context) error {
+	instances, err := o.store.ListUnfinished(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list unfinished saga instances: %w", err)
+	}
+	var errs []error
+	for _, instance := range instances {
+		if _, err := o.Resume(ctx, instance.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Instance 读取实例状态
+func (o *Orchestrator) Instance(ctx context.Context, id string) (*Instance, error) {
+	return o.store.Load(ctx, id)
+}
+
+// definition 返回已注册的 Saga 定义
+func (o *Orchestrator) definition(name string) (*Definition, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	definition, ok := o.sagas[name]
+	if !ok {
+		return nil, fmt.Errorf("saga %s not registered", name)
+	}
+	return definition, nil
+}
+
+// acquire 标记实例正在本进程中执行，防止同一实例被并发恢复
+func (o *Orchestrator) acquire(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.running[id] {
+		return fmt.Errorf("saga instance %s is already running", id)
+	}
+	o.running[id] = true
+	return nil
+}
+
+// release 清除实例的执行标记
+func (o *Orchestrator) release(id string) {
+	o.mu.Lock()
+	delete(o.running, id)
+	o.mu.Unlock()
+}
+
+// run 在 saga span 中执行实例
+func (o *Orchestrator) run(ctx context.Context, definition *Definition, instance *Instance) error {
+	ctx, span := adapter.StartInternalSpan(ctx, "saga "+definition.Name,
+		AttrSagaName.String(definition.Name),
+		AttrSagaID.String(instance.ID),
+	)
+	err := o.execute(ctx, definition, instance)
+	adapter.EndSpan(span, err)
+	return err
+}
+
+// execute 执行剩余的正向步骤，失败时补偿已完成的步骤
+func (o *Orchestrator) execute(ctx context.Context, definition *Definition, instance *Instance) error {
+	var stepErr error
+	for instance.Status == StatusRunning {
+		if instance.Completed == len(definition.Steps) {
+			instance.Status = StatusCompleted
+			return o.save(ctx, instance)
+		}
+		step := definition.Steps[instance.Completed]
+		if err := o.runStep(ctx, "saga.step", instance, step.Name, step.Action); err != nil {
+			if ctx.Err() != nil {
+				return err // 调用方取消，保持执行中状态等待恢复
+			}
+			stepErr = err
+			instance.Status = StatusCompensating
+			instance.FailedStep = step.Name
+			instance.Error = err.Error()
+		} else {
+			instance.Completed++
+		}
+		if err := o.save(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	for instance.Completed > 0 {
+		step := definition.Steps[instance.Completed-1]
+		if step.Compensate != nil {
+			if err := o.runStep(ctx, "saga.compensate", instance, step.Name, step.Compensate); err != nil {
+				if ctx.Err() != nil {
+					return err // 调用方取消，保持补偿中状态等待恢复
+				}
+				instance.Status = StatusFailed
+				instance.CompensationError = err.Error()
+				if saveErr := o.save(ctx, instance); saveErr != nil {
+					return saveErr
+				}
+				return o.failure(instance, stepErr, err)
+			}
+		}
+		instance.Completed--
+		if err := o.save(ctx, instance); err != nil {
+			return err
+		}
+	}
+	instance.Status = StatusCompensated
+	if err := o.save(ctx, instance); err != nil {
+		return err
+	}
+	return o.failure(instance, stepErr, nil)
+}
+
+// runStep 在子 span 中按重试策略执行步骤或补偿
+func (o *Orchestrator) runStep(ctx context.Context, kind string, instance *Instance, name string, fn StepFunc) error {
+	ctx, span := adapter.StartInternalSpan(ctx, kind+" "+name,
+		AttrSagaName.String(instance.Saga),
+		AttrSagaID.String(instance.ID),
+		AttrSagaStep.String(name),
+	)
+	err := o.retry(ctx, func() error { return fn(ctx, instance.Data) })
+	adapter.EndSpan(span, err)
+	return err
+}
+
+// retry 按重试策略执行 fn，返回最后一次的错误
+func (o *Orchestrator) retry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= o.retryPolicy.MaxAttempts || !o.retryable(err) {
+			return err
+		}
+		select {
+		case <-time.After(o.retryPolicy.CalculateDelay(attempt - 1)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// retryable 判断错误是否按重试策略重试，非框架错误不重试
+func (o *Orchestrator) retryable(err error) bool {
+	fe, ok := frameworkerrors.FromError(err)
+	return ok && o.retryPolicy.IsRetryable(fe.Code)
+}
+
+// save 更新时间并保存实例状态
+func (o *Orchestrator) save(ctx context.Context, instance *Instance) error {
+	instance.UpdatedAt = time.Now()
+	if err := o.store.Save(ctx, instance); err != nil {
+		return fmt.Errorf("failed to save saga instance %s: %w", instance.ID, err)
+	}
+	return nil
+}
+
+// failure 构造实例失败的错误，恢复的实例 stepErr 为 nil，只保留了失败原因的文本
+func (o *Orchestrator) failure(instance *Instance, stepErr, compensationErr error) error {
+	if stepErr == nil {
+		stepErr = errors.New(instance.Error)
+	}
+	return &Error{
+		Saga:            instance.Saga,
+		ID:              instance.ID,
+		Step:            instance.FailedStep,
+		Err:             stepErr,
+		CompensationErr: compensationErr,
+	}
+}
+
+// newInstanceID 生成 128 位随机实例 ID
+func newInstanceID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
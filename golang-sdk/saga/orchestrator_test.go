@@ -0,0 +1,322 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/resilience"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// journal 记录步骤和补偿的执行顺序
+type journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (j *journal) add(entry string) {
+	j.mu.Lock()
+	j.entries = append(j.entries, entry)
+	j.mu.Unlock()
+}
+
+func (j *journal) list() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]string(nil), j.entries...)
+}
+
+// orderSaga 下单 Saga：预留库存、扣款、发货，failures 指定失败的步骤或补偿及其错误
+func orderSaga(j *journal, failures map[string]error) *Definition {
+	step := func(name string) Step {
+		return Step{
+			Name: name,
+			Action: func(ctx context.Context, data Data) error {
+				j.add(name)
+				if err := failures[name]; err != nil {
+					return err
+				}
+				return data.Set(name, "done")
+			},
+			Compensate: func(ctx context.Context, data Data) error {
+				j.add("undo " + name)
+				return failures["undo "+name]
+			},
+		}
+	}
+	return &Definition{
+		Name:  "order",
+		Steps: []Step{step("reserve"), step("charge"), step("ship")},
+	}
+}
+
+// newTestOrchestrator 创建不等待重试延迟的编排器
+func newTestOrchestrator(t *testing.T, store Store, definition *Definition) *Orchestrator {
+	o := NewOrchestrator(&Options{
+		Store:       store,
+		RetryPolicy: resilience.NewRetryPolicy(3, time.Millisecond, time.Millisecond, 1.0),
+	})
+	if err := o.Register(definition); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return o
+}
+
+func TestOrchestratorCompletes(t *testing.T) {
+	j := &journal{}
+	store := NewMemoryStore()
+	o := newTestOrchestrator(t, store, orderSaga(j, nil))
+
+	data := make(Data)
+	if err := data.Set("orderId", "o-1"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	instance, err := o.Start(context.Background(), "order", data)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if want := []string{"reserve", "charge", "ship"}; !reflect.DeepEqual(j.list(), want) {
+		t.Errorf("journal = %v, want %v", j.list(), want)
+	}
+	stored, err := store.Load(context.Background(), instance.ID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stored.Status != StatusCompleted || stored.Completed != 3 {
+		t.Errorf("status = %s, completed = %d", stored.Status, stored.Completed)
+	}
+	var orderID, shipped string
+	stored.Data.Get("orderId", &orderID)
+	stored.Data.Get("ship", &shipped)
+	if orderID != "o-1" || shipped != "done" {
+		t.Errorf("unexpected data: %v", stored.Data)
+	}
+}
+
+func TestOrchestratorCompensates(t *testing.T) {
+	declined := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "card declined")
+	tests := []struct {
+		name        string
+		failures    map[string]error
+		wantJournal []string
+		wantStatus  Status
+		wantStep    string
+	}{
+		{
+			name:        "按相反顺序补偿已完成的步骤",
+			failures:    map[string]error{"ship": declined},
+			wantJournal: []string{"reserve", "charge", "ship", "undo charge", "undo reserve"},
+			wantStatus:  StatusCompensated,
+			wantStep:    "ship",
+		},
+		{
+			name:        "第一步失败无需补偿",
+			failures:    map[string]error{"reserve": declined},
+			wantJournal: []string{"reserve"},
+			wantStatus:  StatusCompensated,
+			wantStep:    "reserve",
+		},
+		{
+			name:        "补偿失败停止补偿",
+			failures:    map[string]error{"ship": declined, "undo charge": errors.New("refund failed")},
+			wantJournal: []string{"reserve", "charge", "ship", "undo charge"},
+			wantStatus:  StatusFailed,
+			wantStep:    "ship",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &journal{}
+			o := newTestOrchestrator(t, NewMemoryStore(), orderSaga(j, tt.failures))
+
+			instance, err := o.Start(context.Background(), "order", nil)
+			var sagaErr *Error
+			if !errors.As(err, &sagaErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if sagaErr.Step != tt.wantStep {
+				t.Errorf("failed step = %s, want %s", sagaErr.Step, tt.wantStep)
+			}
+			if fe, ok := frameworkerrors.FromError(sagaErr.Err); !ok || fe.Code != frameworkerrors.BadRequest {
+				t.Errorf("step error = %v, want BadRequest", sagaErr.Err)
+			}
+			if (sagaErr.CompensationErr != nil) != (tt.wantStatus == StatusFailed) {
+				t.Errorf("compensation error = %v", sagaErr.CompensationErr)
+			}
+			if !reflect.DeepEqual(j.list(), tt.wantJournal) {
+				t.Errorf("journal = %v, want %v", j.list(), tt.wantJournal)
+			}
+
+			stored, _ := o.Instance(context.Background(), instance.ID)
+			if stored.Status != tt.wantStatus || stored.FailedStep != tt.wantStep || stored.Error == "" {
+				t.Errorf("unexpected instance: %+v", stored)
+			}
+		})
+	}
+}
+
+func TestOrchestratorRetriesStep(t *testing.T) {
+	j := &journal{}
+	attempts := 0
+	definition := orderSaga(j, nil)
+	definition.Steps[1].Action = func(ctx context.Context, data Data) error {
+		attempts++
+		if attempts < 3 {
+			return frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "unavailable")
+		}
+		return nil
+	}
+	o := newTestOrchestrator(t, NewMemoryStore(), definition)
+
+	if _, err := o.Start(context.Background(), "order", nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestOrchestratorResumePending(t *testing.T) {
+	tests := []struct {
+		name        string
+		instance    *Instance
+		wantJournal []string
+		wantStatus  Status
+	}{
+		{
+			name:        "执行中的实例从未完成的步骤继续",
+			instance:    &Instance{ID: "s-1", Saga: "order", Status: StatusRunning, Completed: 1},
+			wantJournal: []string{"charge", "ship"},
+			wantStatus:  StatusCompleted,
+		},
+		{
+			name: "补偿中的实例继续补偿",
+			instance: &Instance{ID: "s-1", Saga: "order", Status: StatusCompensating, Completed: 2,
+				FailedStep: "ship", Error: "card declined"},
+			wantJournal: []string{"undo charge", "undo reserve"},
+			wantStatus:  StatusCompensated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewMemoryStore()
+			store.Save(ctx, tt.instance)
+
+			// 模拟崩溃后重启的进程：新的编排器使用同一个存储
+			j := &journal{}
+			o := newTestOrchestrator(t, store, orderSaga(j, nil))
+			err := o.ResumePending(ctx)
+			if tt.wantStatus == StatusCompleted && err != nil {
+				t.Fatalf("ResumePending failed: %v", err)
+			}
+			if tt.wantStatus == StatusCompensated {
+				var sagaErr *Error
+				if !errors.As(err, &sagaErr) || sagaErr.Err.Error() != "card declined" {
+					t.Errorf("expected *Error with original failure, got %v", err)
+				}
+			}
+
+			if !reflect.DeepEqual(j.list(), tt.wantJournal) {
+				t.Errorf("journal = %v, want %v", j.list(), tt.wantJournal)
+			}
+			stored, _ := store.Load(ctx, "s-1")
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if pending, _ := store.ListUnfinished(ctx); len(pending) != 0 {
+				t.Errorf("unfinished instances = %d, want 0", len(pending))
+			}
+		})
+	}
+}
+
+func TestOrchestratorCancelKeepsInstanceResumable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &journal{}
+	definition := orderSaga(j, nil)
+	definition.Steps[1].Action = func(ctx context.Context, data Data) error {
+		cancel()
+		return ctx.Err()
+	}
+	store := NewMemoryStore()
+	o := newTestOrchestrator(t, store, definition)
+
+	instance, err := o.Start(ctx, "order", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	stored, _ := store.Load(context.Background(), instance.ID)
+	if stored.Status != StatusRunning || stored.Completed != 1 {
+		t.Errorf("status = %s, completed = %d, want running with 1 completed", stored.Status, stored.Completed)
+	}
+}
+
+func TestOrchestratorRegister(t *testing.T) {
+	noop := func(ctx context.Context, data Data) error { return nil }
+	tests := []struct {
+		name       string
+		definition *Definition
+	}{
+		{name: "缺少名称", definition: &Definition{Steps: []Step{{Name: "a", Action: noop}}}},
+		{name: "没有步骤", definition: &Definition{Name: "order"}},
+		{name: "步骤缺少名称", definition: &Definition{Name: "order", Steps: []Step{{Action: noop}}}},
+		{name: "步骤名称重复", definition: &Definition{Name: "order", Steps: []Step{{Name: "a", Action: noop}, {Name: "a", Action: noop}}}},
+		{name: "步骤缺少操作", definition: &Definition{Name: "order", Steps: []Step{{Name: "a"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewOrchestrator(nil).Register(tt.definition); err == nil {
+				t.Error("expected Register to fail")
+			}
+		})
+	}
+
+	o := newTestOrchestrator(t, nil, orderSaga(&journal{}, nil))
+	if err := o.Register(orderSaga(&journal{}, nil)); err == nil {
+		t.Error("expected duplicate Register to fail")
+	}
+	if _, err := o.Start(context.Background(), "missing", nil); err == nil {
+		t.Error("expected Start of unregistered saga to fail")
+	}
+}
+
+func TestOrchestratorTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	declined := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "card declined")
+	o := newTestOrchestrator(t, nil, orderSaga(&journal{}, map[string]error{"charge": declined}))
+	o.Start(context.Background(), "order", nil)
+
+	var names []string
+	var root string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		if span.Name() == "saga order" {
+			root = span.SpanContext().SpanID().String()
+		}
+	}
+	want := []string{"saga.step reserve", "saga.step charge", "saga.compensate reserve", "saga order"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("spans = %v, want %v", names, want)
+	}
+	for _, span := range recorder.Ended()[:3] {
+		if span.Parent().SpanID().String() != root {
+			t.Errorf("span %s is not a child of the saga span", span.Name())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Status Saga 实例状态
+type Status string
+
+const (
+	// StatusRunning 正在执行正向步骤
+	StatusRunning Status = "running"
+	// StatusCompensating 步骤失败，正在按相反顺序执行已完成步骤的补偿
+	StatusCompensating Status = "compensating"
+	// StatusCompleted 全部步骤执行成功
+	StatusCompleted Status = "completed"
+	// StatusCompensated 步骤失败，已完成步骤均已补偿
+	StatusCompensated Status = "compensated"
+	// StatusFailed 补偿失败，需要人工处理
+	StatusFailed Status = "failed"
+)
+
+// Finished 判断实例是否已结束，未结束的实例可以恢复执行
+func (s Status) Finished() bool {
+	return s == StatusCompleted || s == StatusCompensated || s == StatusFailed
+}
+
+// StepFunc 步骤的执行或补偿函数，可读写 Saga 数据
+type StepFunc func(ctx context.Context, data Data) error
+
+// Step Saga 中的一个步骤
+//
+// 进程崩溃后恢复时，未确认完成的步骤会再次执行，Action 和 Compensate 都应当是幂等的
+type Step struct {
+	// Name 步骤名称，在同一 Saga 中唯一
+	Name string
+	// Action 正向操作，通常调用一个服务
+	Action StepFunc
+	// Compensate 撤销 Action 的补偿操作，为 nil 时步骤无需补偿
+	Compensate StepFunc
+}
+
+// Definition Saga 定义，步骤按顺序执行
+type Definition struct {
+	Name  string
+	Steps []Step
+}
+
+// validate 校验定义
+func (d *Definition) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("saga name is required")
+	}
+	if len(d.Steps) == 0 {
+		return fmt.Errorf("saga %s has no steps", d.Name)
+	}
+	names := make(map[string]bool, len(d.Steps))
+	for i, step := range d.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("saga %s step %d has no name", d.Name, i)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("saga %s has duplicate step %s", d.Name, step.Name)
+		}
+		names[step.Name] = true
+		if step.Action == nil {
+			return fmt.Errorf("saga %s step %s has no action", d.Name, step.Name)
+		}
+	}
+	return nil
+}
+
+// Data Saga 数据，值以 JSON 保存，随实例状态持久化
+//
+// 步骤通过 Data 向后续步骤和补偿操作传递结果，如下单步骤写入订单号、取消订单的补偿读取订单号
+type Data map[string]json.RawMessage
+
+// Get 将 key 的值解码到 v，key 不存在时返回 false
+func (d Data) Get(key string, v interface{}) (bool, error) {
+	raw, ok := d[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to decode saga data %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set 将 v 以 JSON 编码保存到 key
+func (d Data) Set(key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode saga data %s: %w", key, err)
+	}
+	d[key] = raw
+	return nil
+}
+
+// Instance Saga 实例状态，每个步骤开始和结束时保存到 Store
+type Instance struct {
+	ID     string `json:"id"`
+	Saga   string `json:"saga"`
+	Status Status `json:"status"`
+	// Completed 已完成的正向步骤数；补偿时为尚未补偿的步骤数
+	Completed int `json:"completed"`
+	// FailedStep 失败的步骤名称
+	FailedStep string `json:"failedStep,omitempty"`
+	// Error 步骤失败的原因
+	Error string `json:"error,omitempty"`
+	// CompensationError 补偿失败的原因
+	CompensationError string    `json:"compensationError,omitempty"`
+	Data              Data      `json:"data"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// clone 深拷贝实例
+func (i *Instance) clone() *Instance {
+	copied := *i
+	copied.Data = make(Data, len(i.Data))
+	for k, v := range i.Data {
+		copied.Data[k] = append(json.RawMessage(nil), v...)
+	}
+	return &copied
+}
@@ -0,0 +1,69 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrInstanceNotFound Saga 实例不存在
+var ErrInstanceNotFound = errors.New("saga instance not found")
+
+// Store Saga 实例状态存储
+//
+// 进程崩溃后从 Store 中读取未结束的实例恢复执行，多进程部署时应使用共享的持久化存储
+type Store interface {
+	// Save 保存实例状态，已存在时覆盖
+	Save(ctx context.Context, instance *Instance) error
+	// Load 读取实例状态，不存在时返回 ErrInstanceNotFound
+	Load(ctx context.Context, id string) (*Instance, error)
+	// ListUnfinished 列出未结束（执行中或补偿中）的实例
+	ListUnfinished(ctx context.Context) ([]*Instance, error)
+}
+
+// MemoryStore 进程内实例存储，用于测试和单进程部署，进程退出后状态丢失
+type MemoryStore struct {
+	mu        sync.RWMutex
+	instances map[string]*Instance
+}
+
+// NewMemoryStore 创建进程内实例存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{instances: make(map[string]*Instance)}
+}
+
+// Save 保存实例状态的副本
+func (s *MemoryStore) Save(ctx context.Context, instance *Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances[instance.ID] = instance.clone()
+	return nil
+}
+
+// Load 读取实例状态的副本
+func (s *MemoryStore) Load(ctx context.Context, id string) (*Instance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	instance, ok := s.instances[id]
+	if !ok {
+		return nil, ErrInstanceNotFound
+	}
+	return instance.clone(), nil
+}
+
+// ListUnfinished 按创建时间列出未结束的实例
+func (s *MemoryStore) ListUnfinished(ctx context.Context) ([]*Instance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var instances []*Instance
+	for _, instance := range s.instances {
+		if !instance.Status.Finished() {
+			instances = append(instances, instance.clone())
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
+	})
+	return instances, nil
+}
@@ -0,0 +1,74 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if _, err := store.Load(ctx, "missing"); !errors.Is(err, ErrInstanceNotFound) {
+		t.Errorf("Load missing = %v, want ErrInstanceNotFound", err)
+	}
+
+	now := time.Now()
+	instances := []*Instance{
+		{ID: "c", Status: StatusCompensating, CreatedAt: now.Add(2 * time.Second)},
+		{ID: "a", Status: StatusRunning, CreatedAt: now},
+		{ID: "b", Status: StatusCompleted, CreatedAt: now.Add(time.Second)},
+		{ID: "d", Status: StatusFailed, CreatedAt: now.Add(3 * time.Second)},
+	}
+	for _, instance := range instances {
+		if err := store.Save(ctx, instance); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	unfinished, err := store.ListUnfinished(ctx)
+	if err != nil {
+		t.Fatalf("ListUnfinished failed: %v", err)
+	}
+	if len(unfinished) != 2 || unfinished[0].ID != "a" || unfinished[1].ID != "c" {
+		t.Errorf("unexpected unfinished instances: %+v", unfinished)
+	}
+
+	// 保存和读取的都是副本，修改不影响存储中的状态
+	instance := &Instance{ID: "e", Status: StatusRunning, Data: Data{}}
+	instance.Data.Set("orderId", "o-1")
+	store.Save(ctx, instance)
+	instance.Data.Set("orderId", "o-2")
+	loaded, _ := store.Load(ctx, "e")
+	loaded.Status = StatusCompleted
+
+	var orderID string
+	loaded.Data.Get("orderId", &orderID)
+	if orderID != "o-1" {
+		t.Errorf("orderId = %s, want o-1", orderID)
+	}
+	if reloaded, _ := store.Load(ctx, "e"); reloaded.Status != StatusRunning {
+		t.Errorf("status = %s, want running", reloaded.Status)
+	}
+}
+
+func TestData(t *testing.T) {
+	data := make(Data)
+	if err := data.Set("amount", 42); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	var amount int
+	if ok, err := data.Get("amount", &amount); !ok || err != nil || amount != 42 {
+		t.Errorf("Get = %v, %v, %d", ok, err, amount)
+	}
+	if ok, _ := data.Get("missing", &amount); ok {
+		t.Error("missing key should not be found")
+	}
+	var name string
+	if _, err := data.Get("amount", &name); err == nil {
+		t.Error("expected decode error")
+	}
+}
@@ -1,11 +1,13 @@
 package security
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
+	"path"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/framework/golang-sdk/security/credentials"
 )
 
 // APIKeyConfig API密钥配置
@@ -17,16 +19,20 @@ type APIKeyConfig struct {
 type APIKey struct {
 	Key       string    `json:"key"`
 	UserID    string    `json:"userId"`
+	TenantID  string    `json:"tenantId,omitempty"`
 	Roles     []string  `json:"roles"`
+	Scopes    []string  `json:"scopes,omitempty"` // 允许调用的操作(service:method，支持通配符)，为空时不限制
 	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
 	Active    bool      `json:"active"`
 }
 
 // APIKeyAuthenticator API密钥认证器
+//
+// 只保存密钥的 SHA-256 摘要，明文密钥仅在生成时返回一次
 type APIKeyAuthenticator struct {
 	config  *APIKeyConfig
-	keys    map[string]*APIKey
+	keys    map[string]*APIKey // 密钥摘要 -> 密钥信息
 	keysMux sync.RWMutex
 }
 
@@ -44,28 +50,51 @@ func NewAPIKeyAuthenticator(config *APIKeyConfig) (*APIKeyAuthenticator, error)
 
 // GenerateAPIKey 生成API密钥
 func (a *APIKeyAuthenticator) GenerateAPIKey(userID string, roles []string, expiresAt time.Time) (*APIKey, error) {
+	return a.GenerateAPIKeyForTenant(userID, "", roles, expiresAt)
+}
+
+// GenerateAPIKeyForTenant 生成归属于指定租户的API密钥
+func (a *APIKeyAuthenticator) GenerateAPIKeyForTenant(userID, tenantID string, roles []string, expiresAt time.Time) (*APIKey, error) {
+	return a.GenerateScopedAPIKey(userID, tenantID, roles, nil, expiresAt)
+}
+
+// GenerateScopedAPIKey 生成限定操作范围的API密钥
+//
+// scopes 中的每一项格式为 service:method，例如 "user-service:GetUser"、"order-service:*"
+func (a *APIKeyAuthenticator) GenerateScopedAPIKey(userID, tenantID string, roles, scopes []string, expiresAt time.Time) (*APIKey, error) {
 	if !a.config.Enabled {
 		return nil, fmt.Errorf("API key authentication is not enabled")
 	}
 
+	for _, scope := range scopes {
+		if err := validateScope(scope); err != nil {
+			return nil, err
+		}
+	}
+
 	// 生成随机密钥
-	keyBytes := make([]byte, 32)
-	if _, err := rand.Read(keyBytes); err != nil {
-		return nil, fmt.Errorf("failed to generate random key: %w", err)
+	key, err := credentials.GenerateToken(credentials.DefaultTokenLength)
+	if err != nil {
+		return nil, err
 	}
-	key := hex.EncodeToString(keyBytes)
 
 	apiKey := &APIKey{
 		Key:       key,
 		UserID:    userID,
+		TenantID:  tenantID,
 		Roles:     roles,
+		Scopes:    scopes,
 		CreatedAt: time.Now(),
 		ExpiresAt: expiresAt,
 		Active:    true,
 	}
 
+	// 存储的记录不包含明文密钥
+	stored := *apiKey
+	stored.Key = ""
+
 	a.keysMux.Lock()
-	a.keys[key] = apiKey
+	a.keys[credentials.HashToken(key)] = &stored
 	a.keysMux.Unlock()
 
 	return apiKey, nil
@@ -78,7 +107,7 @@ func (a *APIKeyAuthenticator) ValidateAPIKey(key string) (*APIKey, error) {
 	}
 
 	a.keysMux.RLock()
-	apiKey, exists := a.keys[key]
+	apiKey, exists := a.keys[credentials.HashToken(key)]
 	a.keysMux.RUnlock()
 
 	if !exists {
@@ -105,7 +134,7 @@ func (a *APIKeyAuthenticator) RevokeAPIKey(key string) error {
 	a.keysMux.Lock()
 	defer a.keysMux.Unlock()
 
-	apiKey, exists := a.keys[key]
+	apiKey, exists := a.keys[credentials.HashToken(key)]
 	if !exists {
 		return fmt.Errorf("API key not found")
 	}
@@ -118,3 +147,56 @@ func (a *APIKeyAuthenticator) RevokeAPIKey(key string) error {
 func (a *APIKeyAuthenticator) IsEnabled() bool {
 	return a.config.Enabled
 }
+
+// HasScope 检查API密钥是否允许调用指定服务方法
+func (k *APIKey) HasScope(service, method string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range k.Scopes {
+		if matchScope(scope, service, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckScope 检查API密钥的操作范围，不允许时返回错误
+func (k *APIKey) CheckScope(service, method string) error {
+	if !k.HasScope(service, method) {
+		return fmt.Errorf("API key scope denied: service=%s, method=%s", service, method)
+	}
+	return nil
+}
+
+// validateScope 校验操作范围格式
+func validateScope(scope string) error {
+	parts := strings.Split(scope, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid scope %q: expected service:method", scope)
+	}
+
+	for _, part := range parts {
+		if _, err := path.Match(part, ""); err != nil {
+			return fmt.Errorf("invalid scope %q: %w", scope, err)
+		}
+	}
+	return nil
+}
+
+// matchScope 匹配操作范围
+func matchScope(scope, service, method string) bool {
+	parts := strings.Split(scope, ":")
+	if len(parts) != 2 {
+		return false
+	}
+
+	serviceMatched, err := path.Match(parts[0], service)
+	if err != nil || !serviceMatched {
+		return false
+	}
+
+	methodMatched, err := path.Match(parts[1], method)
+	return err == nil && methodMatched
+}
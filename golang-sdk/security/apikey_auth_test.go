@@ -226,3 +226,60 @@ func TestAPIKeyAuthenticator_ValidateAPIKey_Disabled(t *testing.T) {
 		t.Error("ValidateAPIKey() should return error when API key auth is disabled")
 	}
 }
+
+func TestAPIKeyAuthenticator_GenerateScopedAPIKey(t *testing.T) {
+	auth, _ := NewAPIKeyAuthenticator(&APIKeyConfig{Enabled: true})
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{name: "no scopes", scopes: nil, wantErr: false},
+		{name: "valid scopes", scopes: []string{"user-service:GetUser", "order-service:*"}, wantErr: false},
+		{name: "missing method", scopes: []string{"user-service"}, wantErr: true},
+		{name: "empty service", scopes: []string{":GetUser"}, wantErr: true},
+		{name: "too many parts", scopes: []string{"a:b:c"}, wantErr: true},
+		{name: "malformed pattern", scopes: []string{"user-service:[Get"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.GenerateScopedAPIKey("svc-bot", "", []string{"service"}, tt.scopes, expiresAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateScopedAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	apiKey := &APIKey{
+		Scopes: []string{"user-service:GetUser", "order-service:*", "*:Health"},
+	}
+
+	tests := []struct {
+		service string
+		method  string
+		want    bool
+	}{
+		{"user-service", "GetUser", true},
+		{"user-service", "DeleteUser", false},
+		{"order-service", "CreateOrder", true},
+		{"payment-service", "Health", true},
+		{"payment-service", "Refund", false},
+	}
+
+	for _, tt := range tests {
+		if got := apiKey.HasScope(tt.service, tt.method); got != tt.want {
+			t.Errorf("HasScope(%s, %s) = %v, want %v", tt.service, tt.method, got, tt.want)
+		}
+	}
+
+	// 未设置操作范围的密钥不受限制
+	unscoped := &APIKey{}
+	if !unscoped.HasScope("any-service", "AnyMethod") {
+		t.Error("API key without scopes should allow all operations")
+	}
+}
@@ -0,0 +1,238 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultIssuedCertificateTTL 本地 CA 签发证书的默认有效期
+const DefaultIssuedCertificateTTL = 24 * time.Hour
+
+// FileCertificateSource 从文件读取证书，每次续期时重新读取
+//
+// 适用于由外部代理维护的证书：SPIRE（spiffe-helper 将 SVID 写入文件）、cert-manager 挂载的 Secret 等
+type FileCertificateSource struct {
+	CertFile string
+	KeyFile  string
+	// CAFile 信任的 CA 证书（PEM，可包含多个）
+	CAFile string
+}
+
+// Fetch 读取证书、私钥和 CA 文件
+func (s *FileCertificateSource) Fetch(ctx context.Context) (*CertificateBundle, error) {
+	cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate and key: %w", err)
+	}
+	caPEM, err := os.ReadFile(s.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to parse CA certificate")
+	}
+	return &CertificateBundle{Certificate: cert, Roots: roots}, nil
+}
+
+// LocalCASource 用本地 CA 签发短期证书，适用于开发、测试和没有外部 PKI 的内网部署
+type LocalCASource struct {
+	// CA 签发证书的 CA 证书和私钥
+	CA    *x509.Certificate
+	CAKey crypto.Signer
+	// CommonName 证书的通用名，通常为服务名
+	CommonName string
+	// DNSNames、IPAddresses、URIs 证书的 SAN，URIs 可用于 SPIFFE ID
+	DNSNames    []string
+	IPAddresses []net.IP
+	URIs        []*url.URL
+	// TTL 证书有效期，默认 DefaultIssuedCertificateTTL
+	TTL time.Duration
+}
+
+// NewLocalCASourceFromFiles 从 PEM 文件加载 CA 证书和私钥（PKCS#8、PKCS#1 或 EC 格式）
+func NewLocalCASourceFromFiles(certFile, keyFile, commonName string) (*LocalCASource, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA certificate and key: %w", err)
+	}
+	ca, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CA private key cannot sign")
+	}
+	return &LocalCASource{CA: ca, CAKey: signer, CommonName: commonName}, nil
+}
+
+// Fetch 生成新的 ECDSA P-256 私钥并签发同时用于服务端和客户端认证的证书
+func (s *LocalCASource) Fetch(ctx context.Context) (*CertificateBundle, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultIssuedCertificateTTL
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: s.CommonName},
+		DNSNames:     s.DNSNames,
+		IPAddresses:  s.IPAddresses,
+		URIs:         s.URIs,
+		// 容忍主机间的时钟偏差
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(ttl),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if template.NotAfter.After(s.CA.NotAfter) {
+		template.NotAfter = s.CA.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, s.CA, &key.PublicKey, s.CAKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(s.CA)
+	return &CertificateBundle{
+		Certificate: tls.Certificate{Certificate: [][]byte{der, s.CA.Raw}, PrivateKey: key, Leaf: leaf},
+		Roots:       roots,
+	}, nil
+}
+
+// VaultPKISource 通过 Vault PKI 引擎的 issue 接口签发证书
+type VaultPKISource struct {
+	// Address Vault 地址，如 https://vault.example.com:8200
+	Address string
+	// Token Vault 令牌，为空时读取 VAULT_TOKEN 环境变量
+	Token string
+	// Mount PKI 引擎的挂载路径，默认 pki
+	Mount string
+	// Role 签发证书使用的角色
+	Role string
+	// CommonName、AltNames、IPSANs、URISANs 请求的证书名称
+	CommonName string
+	AltNames   []string
+	IPSANs     []string
+	URISANs    []string
+	// TTL 请求的有效期，为 0 时使用角色的默认值
+	TTL time.Duration
+	// HTTPClient 访问 Vault 的客户端，为 nil 时使用 http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// vaultIssueResponse Vault PKI issue 接口的响应
+type vaultIssueResponse struct {
+	Data struct {
+		Certificate string   `json:"certificate"`
+		PrivateKey  string   `json:"private_key"`
+		IssuingCA   string   `json:"issuing_ca"`
+		CAChain     []string `json:"ca_chain"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// Fetch 调用 POST /v1/<Mount>/issue/<Role> 签发证书
+func (s *VaultPKISource) Fetch(ctx context.Context) (*CertificateBundle, error) {
+	mount := strings.Trim(s.Mount, "/")
+	if mount == "" {
+		mount = "pki"
+	}
+	token := s.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	request := map[string]string{"common_name": s.CommonName}
+	if len(s.AltNames) > 0 {
+		request["alt_names"] = strings.Join(s.AltNames, ",")
+	}
+	if len(s.IPSANs) > 0 {
+		request["ip_sans"] = strings.Join(s.IPSANs, ",")
+	}
+	if len(s.URISANs) > 0 {
+		request["uri_sans"] = strings.Join(s.URISANs, ",")
+	}
+	if s.TTL > 0 {
+		request["ttl"] = s.TTL.String()
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(s.Address, "/") + "/v1/" + mount + "/issue/" + url.PathEscape(s.Role)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", token)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result vaultIssueResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode Vault response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Vault returned status %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+	}
+
+	// 证书链为签发的证书加 ca_chain 中的 CA，签发证书的 CA 及其上级均作为信任的 CA
+	chainPEM := result.Data.Certificate
+	for _, ca := range result.Data.CAChain {
+		chainPEM += "\n" + ca
+	}
+	cert, err := tls.X509KeyPair([]byte(chainPEM), []byte(result.Data.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	caPEM := result.Data.IssuingCA + "\n" + strings.Join(result.Data.CAChain, "\n")
+	if !roots.AppendCertsFromPEM([]byte(caPEM)) {
+		return nil, fmt.Errorf("Vault returned no CA certificate")
+	}
+	return &CertificateBundle{Certificate: cert, Roots: roots}, nil
+}
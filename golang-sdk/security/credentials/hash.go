@@ -0,0 +1,170 @@
+package credentials
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 哈希算法
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// Argon2Params argon2id 参数
+type Argon2Params struct {
+	Memory      uint32 // 内存开销(KiB)
+	Iterations  uint32 // 迭代次数
+	Parallelism uint8  // 并行度
+	SaltLength  uint32 // 盐长度(字节)
+	KeyLength   uint32 // 输出长度(字节)
+}
+
+// DefaultArgon2Params 默认 argon2id 参数（OWASP 推荐的最低配置）
+var DefaultArgon2Params = &Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// MaxArgon2Memory argon2id 内存开销的上限(KiB)，即 1 GiB；哈希和校验时超过该值的参数被拒绝，
+// 避免构造的哈希字符串使校验占用过多内存
+const MaxArgon2Memory = 1024 * 1024
+
+// Validate 校验 argon2id 参数：迭代次数和并行度至少为 1，内存开销不小于 8*并行度（argon2 的下限）
+// 且不超过 MaxArgon2Memory，盐至少 8 字节，输出至少 4 字节
+func (p *Argon2Params) Validate() error {
+	if p.Iterations < 1 {
+		return fmt.Errorf("argon2id iterations must be at least 1")
+	}
+	if p.Parallelism < 1 {
+		return fmt.Errorf("argon2id parallelism must be at least 1")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return fmt.Errorf("argon2id memory must be at least %d KiB for parallelism %d", 8*uint32(p.Parallelism), p.Parallelism)
+	}
+	if p.Memory > MaxArgon2Memory {
+		return fmt.Errorf("argon2id memory %d KiB exceeds the limit of %d KiB", p.Memory, MaxArgon2Memory)
+	}
+	if p.SaltLength < 8 {
+		return fmt.Errorf("argon2id salt must be at least 8 bytes")
+	}
+	if p.KeyLength < 4 {
+		return fmt.Errorf("argon2id key must be at least 4 bytes")
+	}
+	return nil
+}
+
+// HashPassword 使用默认 argon2id 参数计算密码哈希
+func HashPassword(password string) (string, error) {
+	return HashArgon2id(password, DefaultArgon2Params)
+}
+
+// HashArgon2id 使用 argon2id 计算密码哈希
+//
+// 返回 PHC 字符串格式: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+func HashArgon2id(password string, params *Argon2Params) (string, error) {
+	if params == nil {
+		params = DefaultArgon2Params
+	}
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	hash := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id,
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// HashBcrypt 使用 bcrypt 计算密码哈希，cost 为 0 时使用默认值
+func HashBcrypt(password string, cost int) (string, error) {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword 校验密码与哈希是否匹配，根据哈希前缀自动识别算法
+func VerifyPassword(password, encoded string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$"+AlgorithmArgon2id+"$"):
+		return verifyArgon2id(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("invalid bcrypt hash: %w", err)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported hash format")
+	}
+}
+
+// verifyArgon2id 校验 argon2id 哈希
+func verifyArgon2id(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return false, fmt.Errorf("unsupported argon2id version: %d", version)
+	}
+
+	params := &Argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return false, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, fmt.Errorf("invalid argon2id hash: %w", err)
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(hash))
+	if err := params.Validate(); err != nil {
+		return false, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(hash)))
+	return subtle.ConstantTimeCompare(hash, computed) == 1, nil
+}
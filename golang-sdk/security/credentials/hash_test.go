@@ -0,0 +1,89 @@
+package credentials
+
+import (
+	"strings"
+	"testing"
+)
+
+// testArgon2Params 测试用的低开销参数
+var testArgon2Params = &Argon2Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHashArgon2id(t *testing.T) {
+	hash, err := HashArgon2id("s3cret", testArgon2Params)
+	if err != nil {
+		t.Fatalf("HashArgon2id() error = %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected hash format: %s", hash)
+	}
+
+	// 相同密码两次哈希结果应不同（随机盐）
+	other, _ := HashArgon2id("s3cret", testArgon2Params)
+	if hash == other {
+		t.Error("hashes of the same password should differ")
+	}
+
+	// 不合法的参数返回错误
+	for _, params := range []*Argon2Params{
+		{Memory: 1024, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		{Memory: 1024, Iterations: 1, Parallelism: 0, SaltLength: 16, KeyLength: 32},
+		{Memory: 4, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		{Memory: MaxArgon2Memory + 1, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	} {
+		if _, err := HashArgon2id("s3cret", params); err == nil {
+			t.Errorf("HashArgon2id(%+v) expected error", params)
+		}
+	}
+}
+
+func TestHashBcrypt(t *testing.T) {
+	hash, err := HashBcrypt("s3cret", 4)
+	if err != nil {
+		t.Fatalf("HashBcrypt() error = %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "$2a$04$") {
+		t.Errorf("unexpected hash format: %s", hash)
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	argonHash, _ := HashArgon2id("s3cret", testArgon2Params)
+	bcryptHash, _ := HashBcrypt("s3cret", 4)
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "argon2id match", password: "s3cret", encoded: argonHash, want: true},
+		{name: "argon2id mismatch", password: "wrong", encoded: argonHash, want: false},
+		{name: "bcrypt match", password: "s3cret", encoded: bcryptHash, want: true},
+		{name: "bcrypt mismatch", password: "wrong", encoded: bcryptHash, want: false},
+		{name: "unsupported format", password: "s3cret", encoded: "plaintext", wantErr: true},
+		{name: "malformed argon2id", password: "s3cret", encoded: "$argon2id$v=19$broken", wantErr: true},
+		{name: "argon2id zero parallelism", password: "s3cret", encoded: strings.Replace(argonHash, ",p=1$", ",p=0$", 1), wantErr: true},
+		{name: "argon2id excessive memory", password: "s3cret", encoded: strings.Replace(argonHash, "m=1024,", "m=4294967295,", 1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPassword(tt.password, tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
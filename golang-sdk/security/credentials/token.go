@@ -0,0 +1,38 @@
+package credentials
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultTokenLength 默认随机令牌长度(字节)
+const DefaultTokenLength = 32
+
+// GenerateToken 生成十六进制编码的随机令牌，length 为随机字节数
+func GenerateToken(length int) (string, error) {
+	if length <= 0 {
+		length = DefaultTokenLength
+	}
+
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken 计算随机令牌的 SHA-256 摘要
+//
+// 适用于 API 密钥等高熵令牌的存储；用户设置的密码应使用 HashPassword
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConstantTimeEqual 常量时间比较两个字符串，防止时序攻击
+func ConstantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,47 @@
+package credentials
+
+import (
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken(16)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if len(token) != 32 {
+		t.Errorf("token length = %d, want 32", len(token))
+	}
+
+	// 非正数长度使用默认值
+	token, _ = GenerateToken(0)
+	if len(token) != DefaultTokenLength*2 {
+		t.Errorf("token length = %d, want %d", len(token), DefaultTokenLength*2)
+	}
+
+	other, _ := GenerateToken(0)
+	if token == other {
+		t.Error("GenerateToken() should return different tokens")
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	// SHA-256("abc")
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashToken("abc"); got != want {
+		t.Errorf("HashToken() = %s, want %s", got, want)
+	}
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	if !ConstantTimeEqual("token", "token") {
+		t.Error("ConstantTimeEqual() should return true for equal strings")
+	}
+	if ConstantTimeEqual("token", "tokem") {
+		t.Error("ConstantTimeEqual() should return false for different strings")
+	}
+	if ConstantTimeEqual("token", "token-longer") {
+		t.Error("ConstantTimeEqual() should return false for different lengths")
+	}
+}
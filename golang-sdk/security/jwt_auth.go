@@ -17,8 +17,9 @@ type JWTConfig struct {
 
 // Claims JWT声明
 type Claims struct {
-	UserID string   `json:"userId"`
-	Roles  []string `json:"roles"`
+	UserID   string   `json:"userId"`
+	TenantID string   `json:"tenantId,omitempty"`
+	Roles    []string `json:"roles"`
 	jwt.RegisteredClaims
 }
 
@@ -44,14 +45,20 @@ func NewJWTAuthenticator(config *JWTConfig) (*JWTAuthenticator, error) {
 
 // GenerateToken 生成JWT令牌
 func (a *JWTAuthenticator) GenerateToken(userID string, roles []string) (string, error) {
+	return a.GenerateTokenForTenant(userID, "", roles)
+}
+
+// GenerateTokenForTenant 生成携带租户ID的JWT令牌
+func (a *JWTAuthenticator) GenerateTokenForTenant(userID, tenantID string, roles []string) (string, error) {
 	if !a.config.Enabled {
 		return "", fmt.Errorf("JWT authentication is not enabled")
 	}
 
 	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
-		Roles:  roles,
+		UserID:   userID,
+		TenantID: tenantID,
+		Roles:    roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    a.config.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -103,6 +103,31 @@ func TestJWTAuthenticator_GenerateAndValidateToken(t *testing.T) {
 	}
 }
 
+func TestJWTAuthenticator_GenerateTokenForTenant(t *testing.T) {
+	auth, err := NewJWTAuthenticator(&JWTConfig{
+		Enabled:    true,
+		Secret:     "test-secret-key",
+		Expiration: 1 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator() error = %v", err)
+	}
+
+	token, err := auth.GenerateTokenForTenant("user123", "tenant-a", []string{"user"})
+	if err != nil {
+		t.Fatalf("GenerateTokenForTenant() error = %v", err)
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+
+	if claims.TenantID != "tenant-a" {
+		t.Errorf("TenantID = %v, want %v", claims.TenantID, "tenant-a")
+	}
+}
+
 func TestJWTAuthenticator_ValidateToken_InvalidToken(t *testing.T) {
 	config := &JWTConfig{
 		Enabled:    true,
@@ -0,0 +1,34 @@
+package security
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	securityMetricsOnce sync.Once
+	// 证书剩余有效天数
+	certificateExpiryDays *prometheus.GaugeVec
+)
+
+// initSecurityMetrics 初始化安全相关指标
+func initSecurityMetrics() {
+	securityMetricsOnce.Do(func() {
+		certificateExpiryDays = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "framework_tls_certificate_expiry_days",
+				Help: "Days until the TLS certificate expires",
+			},
+			[]string{"cert_file"},
+		)
+	})
+}
+
+// recordCertificateExpiry 记录证书剩余有效天数
+func recordCertificateExpiry(certFile string, days int) {
+	initSecurityMetrics()
+	certificateExpiryDays.WithLabelValues(certFile).Set(float64(days))
+}
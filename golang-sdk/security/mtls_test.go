@@ -0,0 +1,225 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestCA 创建自签名的测试 CA
+func newTestCA(t *testing.T, name string) *LocalCASource {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+	return &LocalCASource{CA: ca, CAKey: key, CommonName: "order-service"}
+}
+
+// handshake 在本地 TCP 连接上完成 TLS 握手，返回客户端和服务端的错误
+func handshake(t *testing.T, client, server *tls.Config) (error, error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		tlsConn := tls.Server(conn, server)
+		err = tlsConn.Handshake()
+		if err == nil {
+			_, err = tlsConn.Write([]byte("ok"))
+		}
+		serverErr <- err
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	tlsConn := tls.Client(conn, client)
+	clientErr := tlsConn.Handshake()
+	if clientErr == nil {
+		// TLS 1.3 中服务端拒绝客户端证书的告警在客户端读取时才返回
+		_, clientErr = tlsConn.Read(make([]byte, 2))
+	}
+	return clientErr, <-serverErr
+}
+
+func TestMTLSBootstrapper(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	server, err := NewMTLSBootstrapper(context.Background(), &MTLSConfig{Source: ca})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer server.Close()
+	client, err := NewMTLSBootstrapper(context.Background(), &MTLSConfig{Source: ca})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer client.Close()
+
+	// 同一 CA 签发的证书互相信任
+	if clientErr, serverErr := handshake(t, client.ClientTLSConfig(), server.ServerTLSConfig(tls.RequireAndVerifyClientCert)); clientErr != nil || serverErr != nil {
+		t.Fatalf("handshake failed: client=%v server=%v", clientErr, serverErr)
+	}
+
+	// 其他 CA 签发的证书被拒绝
+	other, err := NewMTLSBootstrapper(context.Background(), &MTLSConfig{Source: newTestCA(t, "other-ca")})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer other.Close()
+	if _, serverErr := handshake(t, other.ClientTLSConfig(), server.ServerTLSConfig(tls.RequireAndVerifyClientCert)); serverErr == nil {
+		t.Error("Expected server to reject a client certificate from another CA")
+	}
+	if clientErr, _ := handshake(t, client.ClientTLSConfig(), other.ServerTLSConfig(tls.RequireAndVerifyClientCert)); clientErr == nil {
+		t.Error("Expected client to reject a server certificate from another CA")
+	}
+
+	// 没有客户端证书时只有 VerifyClientCertIfGiven 接受
+	plain := &tls.Config{InsecureSkipVerify: true}
+	if _, serverErr := handshake(t, plain, server.ServerTLSConfig(tls.RequireAndVerifyClientCert)); serverErr == nil {
+		t.Error("Expected server to require a client certificate")
+	}
+	if clientErr, serverErr := handshake(t, plain, server.ServerTLSConfig(tls.VerifyClientCertIfGiven)); clientErr != nil || serverErr != nil {
+		t.Errorf("handshake without client certificate failed: client=%v server=%v", clientErr, serverErr)
+	}
+}
+
+func TestMTLSBootstrapperAuthorizePeer(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	rejected := errors.New("not allowed")
+	server, err := NewMTLSBootstrapper(context.Background(), &MTLSConfig{
+		Source: ca,
+		AuthorizePeer: func(cert *x509.Certificate) error {
+			if cert.Subject.CommonName != "gateway" {
+				return rejected
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer server.Close()
+	client, _ := NewMTLSBootstrapper(context.Background(), &MTLSConfig{Source: ca})
+	defer client.Close()
+
+	if _, serverErr := handshake(t, client.ClientTLSConfig(), server.ServerTLSConfig(tls.RequireAndVerifyClientCert)); !errors.Is(serverErr, rejected) {
+		t.Errorf("Expected peer to be rejected, got %v", serverErr)
+	}
+}
+
+func TestMTLSBootstrapperRotation(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	ca.TTL = time.Minute + 500*time.Millisecond
+	rotated := make(chan *CertificateBundle, 1)
+	b, err := NewMTLSBootstrapper(context.Background(), &MTLSConfig{
+		Source: ca,
+		// 证书有效期从 1 分钟前开始，剩余不足 99% 时立即续期
+		RenewFraction: 0.99,
+		OnRotate: func(bundle *CertificateBundle) {
+			select {
+			case rotated <- bundle:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewMTLSBootstrapper failed: %v", err)
+	}
+	defer b.Close()
+	first := b.Certificate().Leaf.SerialNumber
+
+	select {
+	case bundle := <-rotated:
+		if bundle.Certificate.Leaf.SerialNumber.Cmp(first) == 0 {
+			t.Error("Expected a new certificate after rotation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for rotation")
+	}
+}
+
+func TestVaultPKISource(t *testing.T) {
+	ca := newTestCA(t, "vault-ca")
+	issued, err := ca.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(issued.Certificate.PrivateKey)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.CA.Raw}))
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/pki_int/issue/service" || r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		if request["common_name"] != "order-service" || request["ttl"] != "1h0m0s" {
+			t.Errorf("Unexpected request: %v", request)
+		}
+		var response vaultIssueResponse
+		response.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issued.Certificate.Certificate[0]}))
+		response.Data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+		response.Data.IssuingCA = caPEM
+		response.Data.CAChain = []string{caPEM}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer vault.Close()
+
+	source := &VaultPKISource{Address: vault.URL, Token: "s.token", Mount: "pki_int", Role: "service", CommonName: "order-service", TTL: time.Hour}
+	bundle, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if _, err := bundle.Certificate.Leaf.Verify(x509.VerifyOptions{Roots: bundle.Roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		t.Errorf("Issued certificate does not verify against returned CA: %v", err)
+	}
+
+	source.Token = "wrong"
+	if _, err := source.Fetch(context.Background()); err == nil {
+		t.Error("Expected error for rejected token")
+	}
+}
@@ -20,6 +20,8 @@ type Permission struct {
 type Role struct {
 	Name        string       `json:"name"`
 	Permissions []Permission `json:"permissions"`
+	// TenantID 角色所属租户，为空表示全局角色，对所有租户生效
+	TenantID string `json:"tenantId,omitempty"`
 }
 
 // RBACAuthorizer RBAC授权器
@@ -148,6 +150,46 @@ func (a *RBACAuthorizer) CheckPermission(roles []string, resource, action string
 	return fmt.Errorf("permission denied: resource=%s, action=%s", resource, action)
 }
 
+// CheckTenantPermission 在租户范围内检查权限
+//
+// 只有全局角色和属于该租户的角色参与匹配，其他租户的角色不会授予任何权限
+func (a *RBACAuthorizer) CheckTenantPermission(tenantID string, roles []string, resource, action string) error {
+	if !a.config.Enabled {
+		return nil // RBAC未启用，允许所有访问
+	}
+
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID is required")
+	}
+
+	if len(roles) == 0 {
+		return fmt.Errorf("no roles provided")
+	}
+
+	a.rolesMux.RLock()
+	defer a.rolesMux.RUnlock()
+
+	for _, roleName := range roles {
+		role, exists := a.roles[roleName]
+		if !exists {
+			continue
+		}
+
+		// 跳过其他租户的角色
+		if role.TenantID != "" && role.TenantID != tenantID {
+			continue
+		}
+
+		for _, perm := range role.Permissions {
+			if a.matchPermission(perm, resource, action) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("permission denied: tenant=%s, resource=%s, action=%s", tenantID, resource, action)
+}
+
 // matchPermission 匹配权限
 func (a *RBACAuthorizer) matchPermission(perm Permission, resource, action string) bool {
 	// 通配符匹配
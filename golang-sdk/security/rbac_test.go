@@ -306,3 +306,44 @@ func TestRBACAuthorizer_IsEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestRBACAuthorizer_CheckTenantPermission(t *testing.T) {
+	authorizer, err := NewRBACAuthorizer(&RBACConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("NewRBACAuthorizer() error = %v", err)
+	}
+
+	// 租户 A 专属角色
+	if err := authorizer.AddRole(&Role{
+		Name:     "tenant-a-operator",
+		TenantID: "tenant-a",
+		Permissions: []Permission{
+			{Resource: "orders", Action: "write"},
+		},
+	}); err != nil {
+		t.Fatalf("AddRole() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		roles    []string
+		resource string
+		action   string
+		wantErr  bool
+	}{
+		{"tenant role in own tenant", "tenant-a", []string{"tenant-a-operator"}, "orders", "write", false},
+		{"tenant role in other tenant", "tenant-b", []string{"tenant-a-operator"}, "orders", "write", true},
+		{"global role in any tenant", "tenant-b", []string{"guest"}, "service", "read", false},
+		{"missing tenant", "", []string{"admin"}, "service", "read", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.CheckTenantPermission(tt.tenantID, tt.roles, tt.resource, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckTenantPermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
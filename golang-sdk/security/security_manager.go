@@ -117,7 +117,46 @@ func (m *SecurityManager) Authorize(roles []string, resource, action string) err
 	return m.rbacAuthorizer.CheckPermission(roles, resource, action)
 }
 
+// AuthorizeAPIKey 对API密钥调用指定服务方法进行授权
+//
+// 先检查密钥的操作范围，再按密钥角色进行RBAC检查（service 作为资源，method 作为操作）
+func (m *SecurityManager) AuthorizeAPIKey(apiKey *APIKey, service, method string) error {
+	if apiKey == nil {
+		return fmt.Errorf("API key cannot be nil")
+	}
+
+	if err := apiKey.CheckScope(service, method); err != nil {
+		return err
+	}
+
+	return m.Authorize(apiKey.Roles, service, method)
+}
+
+// AuthorizeTenant 租户范围内的授权检查
+//
+// principalTenant 为认证主体（JWT/API密钥）所属租户，resourceTenant 为请求访问的租户，
+// 两者不一致时直接拒绝，防止跨租户访问
+func (m *SecurityManager) AuthorizeTenant(principalTenant, resourceTenant string, roles []string, resource, action string) error {
+	if principalTenant != resourceTenant {
+		return fmt.Errorf("cross-tenant access denied: principal tenant %q, resource tenant %q", principalTenant, resourceTenant)
+	}
+
+	if m.rbacAuthorizer == nil || !m.rbacAuthorizer.IsEnabled() {
+		return nil // RBAC未配置或未启用，允许租户内所有访问
+	}
+
+	return m.rbacAuthorizer.CheckTenantPermission(resourceTenant, roles, resource, action)
+}
+
 // IsTLSEnabled 检查TLS是否启用
 func (m *SecurityManager) IsTLSEnabled() bool {
 	return m.tlsManager != nil && m.tlsManager.IsEnabled()
 }
+
+// Close 释放安全管理器持有的资源
+func (m *SecurityManager) Close() error {
+	if m.tlsManager != nil {
+		return m.tlsManager.Close()
+	}
+	return nil
+}
@@ -227,6 +227,66 @@ func TestSecurityManager_Authorize(t *testing.T) {
 	}
 }
 
+func TestSecurityManager_AuthorizeTenant(t *testing.T) {
+	manager, err := NewSecurityManager(&SecurityConfig{
+		RBAC: &RBACConfig{
+			Enabled: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSecurityManager() error = %v", err)
+	}
+
+	// 同租户授权
+	if err := manager.AuthorizeTenant("tenant-a", "tenant-a", []string{"user"}, "service", "write"); err != nil {
+		t.Errorf("AuthorizeTenant() error = %v", err)
+	}
+
+	// 跨租户访问被拒绝，即使是管理员
+	if err := manager.AuthorizeTenant("tenant-a", "tenant-b", []string{"admin"}, "service", "read"); err == nil {
+		t.Error("AuthorizeTenant() should deny cross-tenant access")
+	}
+}
+
+func TestSecurityManager_AuthorizeAPIKey(t *testing.T) {
+	manager, err := NewSecurityManager(&SecurityConfig{
+		RBAC: &RBACConfig{
+			Enabled: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSecurityManager() error = %v", err)
+	}
+
+	apiKey := &APIKey{
+		Roles:  []string{"admin"},
+		Scopes: []string{"user-service:Get*"},
+	}
+
+	// 角色和操作范围均允许
+	if err := manager.AuthorizeAPIKey(apiKey, "user-service", "GetUser"); err != nil {
+		t.Errorf("AuthorizeAPIKey() error = %v", err)
+	}
+
+	// 管理员角色但超出操作范围
+	if err := manager.AuthorizeAPIKey(apiKey, "user-service", "DeleteUser"); err == nil {
+		t.Error("AuthorizeAPIKey() should deny operation outside key scopes")
+	}
+
+	// 操作范围允许但角色权限不足
+	guestKey := &APIKey{
+		Roles:  []string{"guest"},
+		Scopes: []string{"*:*"},
+	}
+	if err := manager.AuthorizeAPIKey(guestKey, "service", "write"); err == nil {
+		t.Error("AuthorizeAPIKey() should deny operation not granted by roles")
+	}
+
+	if err := manager.AuthorizeAPIKey(nil, "service", "read"); err == nil {
+		t.Error("AuthorizeAPIKey() should return error for nil API key")
+	}
+}
+
 func TestSecurityManager_Authorize_NotConfigured(t *testing.T) {
 	config := &SecurityConfig{
 		// RBAC未配置
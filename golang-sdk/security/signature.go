@@ -0,0 +1,218 @@
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 签名相关 HTTP 头
+//
+// 签名格式: X-Framework-Signature: t=<unix 秒>,v1=<hex(HMAC-SHA256(secret, "<t>.<body>"))>
+// 其他语言的接收方按同样规则计算即可完成校验
+const (
+	SignatureHeader  = "X-Framework-Signature"
+	SignatureVersion = "v1"
+)
+
+// DefaultSignatureTolerance 默认签名时间容忍窗口
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// SignatureConfig 签名配置
+type SignatureConfig struct {
+	Secret string `json:"secret" yaml:"secret"`
+	// Tolerance 时间戳容忍窗口，超出窗口的签名视为重放
+	Tolerance time.Duration `json:"tolerance" yaml:"tolerance"`
+	// RejectDuplicates 是否拒绝窗口内重复出现的签名
+	RejectDuplicates bool `json:"rejectDuplicates" yaml:"rejectDuplicates"`
+}
+
+// PayloadSigner HMAC 负载签名器
+//
+// 用于注册中心 webhook 及各类出站回调的签名和校验
+type PayloadSigner struct {
+	config  *SignatureConfig
+	seen    map[string]time.Time // 签名 -> 时间戳
+	seenMux sync.Mutex
+	now     func() time.Time
+}
+
+// NewPayloadSigner 创建负载签名器
+func NewPayloadSigner(config *SignatureConfig) (*PayloadSigner, error) {
+	if config == nil {
+		return nil, fmt.Errorf("signature config cannot be nil")
+	}
+
+	if config.Secret == "" {
+		return nil, fmt.Errorf("signature secret cannot be empty")
+	}
+
+	// 复制配置后再填充默认值，避免修改调用方共享的配置
+	cfg := *config
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = DefaultSignatureTolerance
+	}
+
+	return &PayloadSigner{
+		config: &cfg,
+		seen:   make(map[string]time.Time),
+		now:    time.Now,
+	}, nil
+}
+
+// Sign 对负载签名，返回签名头的值
+func (s *PayloadSigner) Sign(payload []byte) string {
+	return s.SignAt(payload, s.now())
+}
+
+// SignAt 使用指定时间戳对负载签名
+func (s *PayloadSigner) SignAt(payload []byte, timestamp time.Time) string {
+	ts := timestamp.Unix()
+	return fmt.Sprintf("t=%d,%s=%s", ts, SignatureVersion, s.computeSignature(ts, payload))
+}
+
+// Verify 校验签名头
+func (s *PayloadSigner) Verify(payload []byte, header string) error {
+	ts, signatures, err := parseSignatureHeader(header)
+	if err != nil {
+		return err
+	}
+
+	// 检查时间窗口
+	now := s.now()
+	signedAt := time.Unix(ts, 0)
+	if now.Sub(signedAt) > s.config.Tolerance || signedAt.Sub(now) > s.config.Tolerance {
+		return fmt.Errorf("signature timestamp outside tolerance window")
+	}
+
+	expected := s.computeSignature(ts, payload)
+	matched := ""
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			matched = sig
+			break
+		}
+	}
+	if matched == "" {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	if s.config.RejectDuplicates {
+		if err := s.markSeen(matched, signedAt, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SignRequest 对 HTTP 请求签名，body 为请求体
+func (s *PayloadSigner) SignRequest(req *http.Request, body []byte) {
+	req.Header.Set(SignatureHeader, s.Sign(body))
+}
+
+// VerifyRequest 校验 HTTP 请求签名，返回请求体
+//
+// 请求体读取后会被重置，后续处理器仍可读取
+func (s *PayloadSigner) VerifyRequest(req *http.Request) ([]byte, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		body = data
+	}
+
+	header := req.Header.Get(SignatureHeader)
+	if header == "" {
+		return nil, fmt.Errorf("missing %s header", SignatureHeader)
+	}
+
+	if err := s.Verify(body, header); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// computeSignature 计算 HMAC-SHA256 签名
+func (s *PayloadSigner) computeSignature(timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// markSeen 记录已使用的签名，拒绝窗口内的重复签名
+func (s *PayloadSigner) markSeen(signature string, signedAt, now time.Time) error {
+	s.seenMux.Lock()
+	defer s.seenMux.Unlock()
+
+	// 清理已超出窗口的记录
+	for sig, ts := range s.seen {
+		if now.Sub(ts) > s.config.Tolerance {
+			delete(s.seen, sig)
+		}
+	}
+
+	if _, exists := s.seen[signature]; exists {
+		return fmt.Errorf("signature has already been used")
+	}
+
+	s.seen[signature] = signedAt
+	return nil
+}
+
+// parseSignatureHeader 解析签名头
+func parseSignatureHeader(header string) (int64, []string, error) {
+	if header == "" {
+		return 0, nil, fmt.Errorf("signature header is empty")
+	}
+
+	var (
+		timestamp  int64
+		hasTs      bool
+		signatures []string
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			ts, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("invalid signature timestamp: %w", err)
+			}
+			timestamp = ts
+			hasTs = true
+		case SignatureVersion:
+			signatures = append(signatures, kv[1])
+		}
+	}
+
+	if !hasTs {
+		return 0, nil, fmt.Errorf("signature timestamp is missing")
+	}
+
+	if len(signatures) == 0 {
+		return 0, nil, fmt.Errorf("no %s signature found", SignatureVersion)
+	}
+
+	return timestamp, signatures, nil
+}
@@ -0,0 +1,182 @@
+package security
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewPayloadSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SignatureConfig
+		wantErr bool
+	}{
+		{
+			name:    "nil config",
+			config:  nil,
+			wantErr: true,
+		},
+		{
+			name:    "empty secret",
+			config:  &SignatureConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "valid config",
+			config:  &SignatureConfig{Secret: "webhook-secret"},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewPayloadSigner(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPayloadSigner() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && signer.config.Tolerance != DefaultSignatureTolerance {
+				t.Errorf("Tolerance = %v, want %v", signer.config.Tolerance, DefaultSignatureTolerance)
+			}
+		})
+	}
+}
+
+func TestNewPayloadSigner_DoesNotMutateConfig(t *testing.T) {
+	config := &SignatureConfig{Secret: "webhook-secret"}
+	signer, err := NewPayloadSigner(config)
+	if err != nil {
+		t.Fatalf("NewPayloadSigner() error = %v", err)
+	}
+	if config.Tolerance != 0 {
+		t.Errorf("caller config Tolerance = %v, want unchanged 0", config.Tolerance)
+	}
+	if signer.config.Tolerance != DefaultSignatureTolerance {
+		t.Errorf("signer Tolerance = %v, want %v", signer.config.Tolerance, DefaultSignatureTolerance)
+	}
+}
+
+func TestPayloadSigner_SignAndVerify(t *testing.T) {
+	signer, err := NewPayloadSigner(&SignatureConfig{Secret: "webhook-secret"})
+	if err != nil {
+		t.Fatalf("NewPayloadSigner() error = %v", err)
+	}
+
+	payload := []byte(`{"event":"service.registered","service":"user-service"}`)
+	header := signer.Sign(payload)
+
+	if err := signer.Verify(payload, header); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	// 篡改负载
+	if err := signer.Verify([]byte(`{"event":"service.deregistered"}`), header); err == nil {
+		t.Error("Verify() should fail for tampered payload")
+	}
+
+	// 错误的密钥
+	other, _ := NewPayloadSigner(&SignatureConfig{Secret: "other-secret"})
+	if err := other.Verify(payload, header); err == nil {
+		t.Error("Verify() should fail with different secret")
+	}
+}
+
+func TestPayloadSigner_KnownVector(t *testing.T) {
+	signer, _ := NewPayloadSigner(&SignatureConfig{Secret: "secret"})
+
+	// 固定向量，供其他语言实现对照
+	got := signer.SignAt([]byte("hello"), time.Unix(1700000000, 0))
+	want := "t=1700000000,v1=47b1df0ab12338b2685470b0d2b37033add7c3b2bc8172f313e77413f1bb78c8"
+	if got != want {
+		t.Errorf("SignAt() = %s, want %s", got, want)
+	}
+}
+
+func TestPayloadSigner_ReplayWindow(t *testing.T) {
+	signer, _ := NewPayloadSigner(&SignatureConfig{
+		Secret:    "webhook-secret",
+		Tolerance: time.Minute,
+	})
+
+	payload := []byte("payload")
+
+	// 过期的签名
+	old := signer.SignAt(payload, time.Now().Add(-2*time.Minute))
+	if err := signer.Verify(payload, old); err == nil {
+		t.Error("Verify() should reject signature outside tolerance window")
+	}
+
+	// 来自未来的签名
+	future := signer.SignAt(payload, time.Now().Add(2*time.Minute))
+	if err := signer.Verify(payload, future); err == nil {
+		t.Error("Verify() should reject signature from the future")
+	}
+}
+
+func TestPayloadSigner_RejectDuplicates(t *testing.T) {
+	signer, _ := NewPayloadSigner(&SignatureConfig{
+		Secret:           "webhook-secret",
+		RejectDuplicates: true,
+	})
+
+	payload := []byte("payload")
+	header := signer.Sign(payload)
+
+	if err := signer.Verify(payload, header); err != nil {
+		t.Fatalf("first Verify() error = %v", err)
+	}
+	if err := signer.Verify(payload, header); err == nil {
+		t.Error("second Verify() should reject replayed signature")
+	}
+}
+
+func TestPayloadSigner_InvalidHeader(t *testing.T) {
+	signer, _ := NewPayloadSigner(&SignatureConfig{Secret: "webhook-secret"})
+
+	headers := []string{
+		"",
+		"v1=abc",
+		"t=notanumber,v1=abc",
+		"t=1700000000",
+	}
+
+	for _, header := range headers {
+		if err := signer.Verify([]byte("payload"), header); err == nil {
+			t.Errorf("Verify(%q) should fail", header)
+		}
+	}
+}
+
+func TestPayloadSigner_HTTPRequest(t *testing.T) {
+	signer, _ := NewPayloadSigner(&SignatureConfig{Secret: "webhook-secret"})
+
+	body := []byte(`{"event":"ping"}`)
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/callback", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	signer.SignRequest(req, body)
+
+	got, err := signer.VerifyRequest(req)
+	if err != nil {
+		t.Fatalf("VerifyRequest() error = %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("VerifyRequest() body = %s, want %s", got, body)
+	}
+
+	// 请求体应可再次读取
+	again, _ := io.ReadAll(req.Body)
+	if !bytes.Equal(again, body) {
+		t.Errorf("request body after verify = %s, want %s", again, body)
+	}
+
+	// 缺少签名头
+	req.Header.Del(SignatureHeader)
+	if _, err := signer.VerifyRequest(req); err == nil {
+		t.Error("VerifyRequest() should fail without signature header")
+	}
+}
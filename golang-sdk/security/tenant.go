@@ -0,0 +1,36 @@
+package security
+
+import "context"
+
+// 租户标识在请求头和元数据中的键名，与 adapter 包保持一致
+const (
+	TenantHeader      = "X-Tenant-Id"
+	TenantMetadataKey = "tenant_id"
+)
+
+// tenantContextKey 租户ID的上下文键
+type tenantContextKey struct{}
+
+// WithTenantID 将租户ID写入上下文
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantIDFromContext 从上下文读取租户ID
+func TenantIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenantID
+	}
+	return ""
+}
+
+// TenantIDFromMetadata 从请求元数据读取租户ID
+func TenantIDFromMetadata(metadata map[string]string) string {
+	if metadata == nil {
+		return ""
+	}
+	return metadata[TenantMetadataKey]
+}
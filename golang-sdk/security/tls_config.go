@@ -1,10 +1,16 @@
 package security
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"math"
 	"os"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/os/glog"
 )
 
 // TLSConfig TLS配置
@@ -13,12 +19,31 @@ type TLSConfig struct {
 	CertFile string `json:"certFile" yaml:"certFile"`
 	KeyFile  string `json:"keyFile" yaml:"keyFile"`
 	CAFile   string `json:"caFile" yaml:"caFile"`
+	// ReloadInterval 证书文件变更检查间隔，0 表示不自动重载
+	ReloadInterval time.Duration `json:"reloadInterval" yaml:"reloadInterval"`
+	// ExpiryWarningDays 证书剩余有效天数低于该值时健康检查报告异常，默认 30 天
+	ExpiryWarningDays int `json:"expiryWarningDays" yaml:"expiryWarningDays"`
 }
 
+// DefaultExpiryWarningDays 默认证书过期预警天数
+const DefaultExpiryWarningDays = 30
+
 // TLSManager TLS管理器
 type TLSManager struct {
 	config    *TLSConfig
 	tlsConfig *tls.Config
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	notAfter    time.Time
+	certModTime time.Time
+	keyModTime  time.Time
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	reloadListeners []func(certFile string)
 }
 
 // NewTLSManager 创建TLS管理器
@@ -28,7 +53,8 @@ func NewTLSManager(config *TLSConfig) (*TLSManager, error) {
 	}
 
 	manager := &TLSManager{
-		config: config,
+		config:   config,
+		stopChan: make(chan struct{}),
 	}
 
 	if config.Enabled {
@@ -37,6 +63,12 @@ func NewTLSManager(config *TLSConfig) (*TLSManager, error) {
 			return nil, fmt.Errorf("failed to build TLS config: %w", err)
 		}
 		manager.tlsConfig = tlsConfig
+
+		// 启动证书文件监听
+		if config.ReloadInterval > 0 {
+			manager.wg.Add(1)
+			go manager.watchCertificate()
+		}
 	}
 
 	return manager, nil
@@ -45,14 +77,19 @@ func NewTLSManager(config *TLSConfig) (*TLSManager, error) {
 // buildTLSConfig 构建TLS配置
 func (m *TLSManager) buildTLSConfig() (*tls.Config, error) {
 	// 加载证书和密钥
-	cert, err := tls.LoadX509KeyPair(m.config.CertFile, m.config.KeyFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load certificate and key: %w", err)
+	if err := m.loadCertificate(); err != nil {
+		return nil, err
 	}
 
+	// 通过回调返回当前证书，证书重载后新握手立即生效，无需重启监听器
 	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		MinVersion:   tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return m.currentCertificate(), nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return m.currentCertificate(), nil
+		},
+		MinVersion: tls.VersionTLS12,
 	}
 
 	// 如果提供了CA文件，加载CA证书池
@@ -75,6 +112,165 @@ func (m *TLSManager) buildTLSConfig() (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// loadCertificate 从文件加载证书和密钥
+func (m *TLSManager) loadCertificate() error {
+	cert, err := tls.LoadX509KeyPair(m.config.CertFile, m.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate and key: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	cert.Leaf = leaf
+
+	certModTime, keyModTime := m.fileModTimes()
+
+	m.mu.Lock()
+	m.certificate = &cert
+	m.notAfter = leaf.NotAfter
+	m.certModTime = certModTime
+	m.keyModTime = keyModTime
+	m.mu.Unlock()
+
+	recordCertificateExpiry(m.config.CertFile, m.DaysUntilExpiry())
+	return nil
+}
+
+// currentCertificate 获取当前使用的证书
+func (m *TLSManager) currentCertificate() *tls.Certificate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.certificate
+}
+
+// fileModTimes 获取证书和密钥文件的修改时间
+func (m *TLSManager) fileModTimes() (time.Time, time.Time) {
+	var certModTime, keyModTime time.Time
+	if info, err := os.Stat(m.config.CertFile); err == nil {
+		certModTime = info.ModTime()
+	}
+	if info, err := os.Stat(m.config.KeyFile); err == nil {
+		keyModTime = info.ModTime()
+	}
+	return certModTime, keyModTime
+}
+
+// watchCertificate 定期检查证书文件变化并重载
+func (m *TLSManager) watchCertificate() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			if m.certificateChanged() {
+				// 重载失败时保留旧证书，等待下次检查
+				if err := m.Reload(); err != nil {
+					glog.Warningf(context.Background(), "TLS certificate reload failed for %s, keeping the current certificate: %v", m.config.CertFile, err)
+				}
+				continue
+			}
+			recordCertificateExpiry(m.config.CertFile, m.DaysUntilExpiry())
+		}
+	}
+}
+
+// certificateChanged 检查证书或密钥文件是否被修改
+func (m *TLSManager) certificateChanged() bool {
+	certModTime, keyModTime := m.fileModTimes()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !certModTime.Equal(m.certModTime) || !keyModTime.Equal(m.keyModTime)
+}
+
+// Reload 重新加载证书和密钥
+func (m *TLSManager) Reload() error {
+	if !m.config.Enabled {
+		return fmt.Errorf("TLS is not enabled")
+	}
+	if err := m.loadCertificate(); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	listeners := m.reloadListeners
+	m.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(m.config.CertFile)
+	}
+	return nil
+}
+
+// OnReload 注册证书重载监听器，证书成功重载后调用
+func (m *TLSManager) OnReload(listener func(certFile string)) {
+	if listener == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reloadListeners = append(m.reloadListeners, listener)
+}
+
+// EventConfigReloaded 证书重载的框架事件类型，与 observability.EventConfigReloaded 一致
+const EventConfigReloaded = "config.reloaded"
+
+// WatchEvents 将证书重载以框架事件发布，实现 observability.EventSource，事件来源为 tls
+func (m *TLSManager) WatchEvents(emit func(eventType, source string, attributes map[string]string)) {
+	m.OnReload(func(certFile string) {
+		emit(EventConfigReloaded, "tls", map[string]string{"cert_file": certFile})
+	})
+}
+
+// NotAfter 返回当前证书的过期时间
+func (m *TLSManager) NotAfter() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.notAfter
+}
+
+// DaysUntilExpiry 返回当前证书剩余有效天数（已过期时为负数）
+func (m *TLSManager) DaysUntilExpiry() int {
+	notAfter := m.NotAfter()
+	if notAfter.IsZero() {
+		return 0
+	}
+	return int(math.Floor(time.Until(notAfter).Hours() / 24))
+}
+
+// CheckExpiry 检查证书是否即将过期
+func (m *TLSManager) CheckExpiry(ctx context.Context) error {
+	if !m.config.Enabled {
+		return nil
+	}
+
+	warningDays := m.config.ExpiryWarningDays
+	if warningDays <= 0 {
+		warningDays = DefaultExpiryWarningDays
+	}
+
+	days := m.DaysUntilExpiry()
+	if days < 0 {
+		return fmt.Errorf("certificate %s expired at %s", m.config.CertFile, m.NotAfter().Format(time.RFC3339))
+	}
+	if days < warningDays {
+		return fmt.Errorf("certificate %s expires in %d days", m.config.CertFile, days)
+	}
+	return nil
+}
+
+// ExpiryHealthCheck 返回证书过期健康检查，可注册到 observability.HealthChecker
+func (m *TLSManager) ExpiryHealthCheck() *CertificateExpiryCheck {
+	return &CertificateExpiryCheck{manager: m}
+}
+
 // GetTLSConfig 获取TLS配置
 func (m *TLSManager) GetTLSConfig() *tls.Config {
 	return m.tlsConfig
@@ -84,3 +280,27 @@ func (m *TLSManager) GetTLSConfig() *tls.Config {
 func (m *TLSManager) IsEnabled() bool {
 	return m.config.Enabled
 }
+
+// Close 停止证书文件监听
+func (m *TLSManager) Close() error {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+	m.wg.Wait()
+	return nil
+}
+
+// CertificateExpiryCheck 证书过期健康检查
+type CertificateExpiryCheck struct {
+	manager *TLSManager
+}
+
+// Name 返回检查名称
+func (c *CertificateExpiryCheck) Name() string {
+	return "tls_certificate_expiry"
+}
+
+// Check 执行检查
+func (c *CertificateExpiryCheck) Check(ctx context.Context) error {
+	return c.manager.CheckExpiry(ctx)
+}
@@ -1,8 +1,19 @@
 package security
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewTLSManager(t *testing.T) {
@@ -113,3 +124,160 @@ func TestTLSConfig_MinVersion(t *testing.T) {
 		}
 	}
 }
+
+// writeTestCertificate 生成自签名证书并写入临时目录
+func writeTestCertificate(t *testing.T, dir string, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return certFile, keyFile
+}
+
+func TestTLSManager_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, time.Now().Add(10*24*time.Hour))
+
+	manager, err := NewTLSManager(&TLSConfig{
+		Enabled:  true,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	})
+	if err != nil {
+		t.Fatalf("NewTLSManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	first, err := manager.GetTLSConfig().GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetCertificate() error = %v", err)
+	}
+
+	// 替换证书文件后重载
+	writeTestCertificate(t, dir, time.Now().Add(90*24*time.Hour))
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	second, err := manager.GetTLSConfig().GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetCertificate() error = %v", err)
+	}
+
+	if first.Leaf.SerialNumber.Cmp(second.Leaf.SerialNumber) == 0 {
+		t.Error("GetCertificate() should return the reloaded certificate")
+	}
+	if days := manager.DaysUntilExpiry(); days < 88 || days > 90 {
+		t.Errorf("DaysUntilExpiry() = %d, want about 89", days)
+	}
+}
+
+func TestTLSManager_WatchCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, time.Now().Add(10*24*time.Hour))
+
+	manager, err := NewTLSManager(&TLSConfig{
+		Enabled:        true,
+		CertFile:       certFile,
+		KeyFile:        keyFile,
+		ReloadInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewTLSManager() error = %v", err)
+	}
+	defer manager.Close()
+
+	before := manager.NotAfter()
+
+	// 确保修改时间发生变化
+	time.Sleep(20 * time.Millisecond)
+	writeTestCertificate(t, dir, time.Now().Add(90*24*time.Hour))
+	future := time.Now().Add(time.Second)
+	_ = os.Chtimes(certFile, future, future)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if !manager.NotAfter().Equal(before) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("certificate was not reloaded after file change")
+}
+
+func TestTLSManager_CheckExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "valid certificate",
+			notAfter: time.Now().Add(365 * 24 * time.Hour),
+			wantErr:  false,
+		},
+		{
+			name:     "expiring soon",
+			notAfter: time.Now().Add(5 * 24 * time.Hour),
+			wantErr:  true,
+		},
+		{
+			name:     "expired",
+			notAfter: time.Now().Add(-time.Hour),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certFile, keyFile := writeTestCertificate(t, t.TempDir(), tt.notAfter)
+			manager, err := NewTLSManager(&TLSConfig{
+				Enabled:  true,
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			})
+			if err != nil {
+				t.Fatalf("NewTLSManager() error = %v", err)
+			}
+			defer manager.Close()
+
+			check := manager.ExpiryHealthCheck()
+			if check.Name() != "tls_certificate_expiry" {
+				t.Errorf("Name() = %s, want tls_certificate_expiry", check.Name())
+			}
+			if err := check.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package compat 检查消息结构（protobuf 描述符集或 JSON Schema）两个版本之间的兼容性，
+// 用于在服务部署前的 CI 中拦截不兼容的负载变更。
+//
+// 兼容方向：
+//
+//   - 向后兼容（Backward）：新版本的读取方能够读取旧版本写入的数据，服务升级后仍能处理存量数据和旧客户端的请求
+//   - 向前兼容（Forward）：旧版本的读取方能够读取新版本写入的数据，滚动升级期间旧实例仍能处理新实例发出的消息
+//   - 完全兼容（Full）：同时满足以上两者
+package compat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Mode 兼容性检查方向
+type Mode int
+
+const (
+	// Backward 向后兼容：新读取方读取旧数据
+	Backward Mode = 1 << iota
+	// Forward 向前兼容：旧读取方读取新数据
+	Forward
+	// Full 完全兼容
+	Full = Backward | Forward
+)
+
+// String 返回方向名称
+func (m Mode) String() string {
+	switch m {
+	case Backward:
+		return "BACKWARD"
+	case Forward:
+		return "FORWARD"
+	case Full:
+		return "FULL"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ParseMode 解析兼容性检查方向：backward、forward 或 full，不区分大小写
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "backward":
+		return Backward, nil
+	case "forward":
+		return Forward, nil
+	case "full":
+		return Full, nil
+	default:
+		return 0, fmt.Errorf("invalid compatibility mode %q, expected backward, forward or full", s)
+	}
+}
+
+// Violation 兼容性违规
+type Violation struct {
+	// Breaks 被破坏的兼容方向
+	Breaks Mode
+	// Rule 违规规则，如 FIELD_TYPE_CHANGED
+	Rule string
+	// Path 违规位置，如 framework.common.Message.timestamp 或 #/properties/id
+	Path string
+	// Message 违规描述
+	Message string
+}
+
+// String 返回单行描述
+func (v Violation) String() string {
+	return fmt.Sprintf("%-8s %s %s: %s", v.Breaks, v.Rule, v.Path, v.Message)
+}
+
+// Report 兼容性检查结果
+type Report struct {
+	// Mode 检查的兼容方向
+	Mode Mode
+	// Violations 违规列表，按位置和规则排序
+	Violations []Violation
+}
+
+// Compatible 是否兼容
+func (r *Report) Compatible() bool {
+	return len(r.Violations) == 0
+}
+
+// String 逐行列出违规项
+func (r *Report) String() string {
+	if r.Compatible() {
+		return fmt.Sprintf("compatible (%s)", r.Mode)
+	}
+
+	lines := make([]string, 0, len(r.Violations)+1)
+	lines = append(lines, fmt.Sprintf("%d compatibility violations (%s):", len(r.Violations), r.Mode))
+	for _, v := range r.Violations {
+		lines = append(lines, "  - "+v.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reporter 收集违规项，只保留与检查方向相关的违规
+type reporter struct {
+	mode       Mode
+	violations []Violation
+	seen       map[Violation]bool
+}
+
+// newReporter 创建违规收集器
+func newReporter(mode Mode) *reporter {
+	return &reporter{mode: mode, seen: make(map[Violation]bool)}
+}
+
+// add 记录违规
+func (r *reporter) add(breaks Mode, rule, path, format string, args ...interface{}) {
+	if breaks&r.mode == 0 {
+		return
+	}
+
+	v := Violation{Breaks: breaks, Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)}
+	if r.seen[v] {
+		return
+	}
+	r.seen[v] = true
+	r.violations = append(r.violations, v)
+}
+
+// report 生成排序后的检查结果
+func (r *reporter) report() *Report {
+	sort.SliceStable(r.violations, func(i, j int) bool {
+		if r.violations[i].Path != r.violations[j].Path {
+			return r.violations[i].Path < r.violations[j].Path
+		}
+		return r.violations[i].Rule < r.violations[j].Rule
+	})
+	return &Report{Mode: r.mode, Violations: r.violations}
+}
+
+// CheckFiles 比较两个版本的结构文件，按扩展名识别格式：
+// .json 为 JSON Schema，.pb、.bin、.binpb、.desc 为 protoc --descriptor_set_out 生成的描述符集
+func CheckFiles(oldPath, newPath string, mode Mode) (*Report, error) {
+	oldKind, err := fileKind(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newKind, err := fileKind(newPath)
+	if err != nil {
+		return nil, err
+	}
+	if oldKind != newKind {
+		return nil, fmt.Errorf("cannot compare %s with %s", oldPath, newPath)
+	}
+
+	oldData, err := os.ReadFile(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	newData, err := os.ReadFile(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	if oldKind == kindJSONSchema {
+		return CheckJSONSchema(oldData, newData, mode)
+	}
+
+	oldSet, err := ParseDescriptorSet(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", oldPath, err)
+	}
+	newSet, err := ParseDescriptorSet(newData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", newPath, err)
+	}
+	return CheckProto(oldSet, newSet, mode), nil
+}
+
+const (
+	kindJSONSchema = "jsonschema"
+	kindProto      = "proto"
+)
+
+// fileKind 根据扩展名识别结构文件格式
+func fileKind(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return kindJSONSchema, nil
+	case ".pb", ".bin", ".binpb", ".desc":
+		return kindProto, nil
+	case ".proto":
+		return "", fmt.Errorf("%s: .proto sources are not supported, compile them with protoc --include_imports --descriptor_set_out", path)
+	default:
+		return "", fmt.Errorf("%s: unsupported schema file type", path)
+	}
+}
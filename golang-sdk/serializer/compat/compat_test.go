@@ -0,0 +1,79 @@
+package compat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Mode
+		wantErr bool
+	}{
+		{input: "backward", want: Backward},
+		{input: "FORWARD", want: Forward},
+		{input: " full ", want: Full},
+		{input: "both", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMode(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMode(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestReporter_FiltersByMode(t *testing.T) {
+	r := newReporter(Backward)
+	r.add(Backward, "A", "x", "backward only")
+	r.add(Forward, "B", "x", "forward only")
+	r.add(Full, "C", "x", "both")
+	r.add(Full, "C", "x", "both")
+
+	report := r.report()
+	if len(report.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d:\n%s", len(report.Violations), report)
+	}
+	if !strings.HasPrefix(report.String(), "2 compatibility violations (BACKWARD):") {
+		t.Errorf("Unexpected report header:\n%s", report)
+	}
+
+	if s := newReporter(Full).report().String(); s != "compatible (FULL)" {
+		t.Errorf("Unexpected compatible report: %s", s)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "user.v1.json")
+	newPath := filepath.Join(dir, "user.v2.json")
+	if err := os.WriteFile(oldPath, []byte(baseUserSchema), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	newSchema := strings.Replace(baseUserSchema, `["id", "name"]`, `["id", "name", "status"]`, 1)
+	if err := os.WriteFile(newPath, []byte(newSchema), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	report, err := CheckFiles(oldPath, newPath, Backward)
+	if err != nil {
+		t.Fatalf("CheckFiles failed: %v", err)
+	}
+	assertRules(t, report, []string{RuleRequiredPropertyAdded})
+
+	if _, err := CheckFiles(oldPath, filepath.Join(dir, "user.pb"), Backward); err == nil {
+		t.Error("Expected error when comparing different schema formats")
+	}
+	if _, err := CheckFiles(filepath.Join(dir, "user.proto"), filepath.Join(dir, "user.proto"), Backward); err == nil ||
+		!strings.Contains(err.Error(), "descriptor_set_out") {
+		t.Errorf("Expected hint to compile .proto sources, got %v", err)
+	}
+}
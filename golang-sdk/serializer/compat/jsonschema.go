@@ -0,0 +1,345 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSON Schema 规则
+const (
+	RuleTypeNarrowed          = "TYPE_NARROWED"
+	RuleTypeWidened           = "TYPE_WIDENED"
+	RuleEnumNarrowed          = "ENUM_NARROWED"
+	RuleEnumWidened           = "ENUM_WIDENED"
+	RuleRequiredPropertyAdded = "REQUIRED_PROPERTY_ADDED"
+	RuleRequiredPropRemoved   = "REQUIRED_PROPERTY_REMOVED"
+	RulePropertyRemoved       = "PROPERTY_REMOVED"
+	RulePropertyAdded         = "PROPERTY_ADDED"
+	RuleAdditionalClosed      = "ADDITIONAL_PROPERTIES_CLOSED"
+	RuleAdditionalOpened      = "ADDITIONAL_PROPERTIES_OPENED"
+	RuleRangeNarrowed         = "RANGE_NARROWED"
+	RuleRangeWidened          = "RANGE_WIDENED"
+)
+
+// CheckJSONSchema 比较两个版本的 JSON Schema
+//
+// 支持 type、enum、const、properties、required、additionalProperties、items、数值和长度范围，
+// 以及文档内的 $ref（如 #/definitions/User 或 #/$defs/User）。
+// 向后兼容要求新 Schema 接受旧 Schema 允许的全部数据，向前兼容反之。
+func CheckJSONSchema(oldData, newData []byte, mode Mode) (*Report, error) {
+	var oldRoot, newRoot interface{}
+	if err := json.Unmarshal(oldData, &oldRoot); err != nil {
+		return nil, fmt.Errorf("invalid old JSON schema: %w", err)
+	}
+	if err := json.Unmarshal(newData, &newRoot); err != nil {
+		return nil, fmt.Errorf("invalid new JSON schema: %w", err)
+	}
+
+	c := &schemaComparer{
+		r:        newReporter(mode),
+		oldRoot:  oldRoot,
+		newRoot:  newRoot,
+		visiting: make(map[string]bool),
+	}
+	c.compare("#", oldRoot, newRoot)
+	return c.r.report(), nil
+}
+
+// schemaComparer JSON Schema 比较器
+type schemaComparer struct {
+	r        *reporter
+	oldRoot  interface{}
+	newRoot  interface{}
+	visiting map[string]bool // 正在比较的 $ref 组合，避免递归定义无限展开
+}
+
+// compare 比较同一位置的新旧 Schema
+func (c *schemaComparer) compare(path string, oldNode, newNode interface{}) {
+	oldSchema, oldRef := c.resolve(c.oldRoot, oldNode)
+	newSchema, newRef := c.resolve(c.newRoot, newNode)
+	if oldRef != "" || newRef != "" {
+		key := oldRef + "|" + newRef
+		if c.visiting[key] {
+			return
+		}
+		c.visiting[key] = true
+		defer delete(c.visiting, key)
+	}
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	c.compareTypes(path, oldSchema, newSchema)
+	c.compareEnums(path, oldSchema, newSchema)
+	c.compareRequired(path, oldSchema, newSchema)
+	c.compareProperties(path, oldSchema, newSchema)
+	c.compareBounds(path, oldSchema, newSchema)
+
+	if oldItems, ok := oldSchema["items"]; ok {
+		if newItems, ok := newSchema["items"]; ok {
+			c.compare(path+"/items", oldItems, newItems)
+		}
+	}
+}
+
+// resolve 展开文档内的 $ref，返回 Schema 对象和引用路径；布尔 Schema true 视为空对象，false 返回 nil
+func (c *schemaComparer) resolve(root, node interface{}) (map[string]interface{}, string) {
+	var ref string
+	for depth := 0; depth < 32; depth++ {
+		switch v := node.(type) {
+		case bool:
+			if v {
+				return map[string]interface{}{}, ref
+			}
+			return nil, ref
+		case map[string]interface{}:
+			target, ok := v["$ref"].(string)
+			if !ok {
+				return v, ref
+			}
+			ref = target
+			node = lookupPointer(root, target)
+		default:
+			return nil, ref
+		}
+	}
+	return nil, ref
+}
+
+// lookupPointer 按 JSON Pointer 查找文档内的节点，只支持以 # 开头的本地引用
+func lookupPointer(root interface{}, ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+
+	node := root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = m[token]
+	}
+	return node
+}
+
+// compareTypes 比较 type，integer 视为 number 的子类型
+func (c *schemaComparer) compareTypes(path string, oldSchema, newSchema map[string]interface{}) {
+	oldTypes, newTypes := schemaTypes(oldSchema), schemaTypes(newSchema)
+
+	if missing := uncoveredTypes(oldTypes, newTypes); len(missing) > 0 {
+		c.r.add(Backward, RuleTypeNarrowed, path, "no longer accepts %s", strings.Join(missing, ", "))
+	}
+	if extra := uncoveredTypes(newTypes, oldTypes); len(extra) > 0 {
+		c.r.add(Forward, RuleTypeWidened, path, "now accepts %s", strings.Join(extra, ", "))
+	}
+}
+
+// compareEnums 比较 enum 和 const
+func (c *schemaComparer) compareEnums(path string, oldSchema, newSchema map[string]interface{}) {
+	oldValues, oldOK := enumValues(oldSchema)
+	newValues, newOK := enumValues(newSchema)
+
+	switch {
+	case oldOK && newOK:
+		if removed := difference(oldValues, newValues); len(removed) > 0 {
+			c.r.add(Backward, RuleEnumNarrowed, path, "values removed: %s", strings.Join(removed, ", "))
+		}
+		if added := difference(newValues, oldValues); len(added) > 0 {
+			c.r.add(Forward, RuleEnumWidened, path, "values added: %s", strings.Join(added, ", "))
+		}
+	case newOK:
+		c.r.add(Backward, RuleEnumNarrowed, path, "enum constraint added")
+	case oldOK:
+		c.r.add(Forward, RuleEnumWidened, path, "enum constraint removed")
+	}
+}
+
+// compareRequired 比较 required
+func (c *schemaComparer) compareRequired(path string, oldSchema, newSchema map[string]interface{}) {
+	oldRequired, newRequired := stringSet(oldSchema["required"]), stringSet(newSchema["required"])
+
+	for _, name := range difference(newRequired, oldRequired) {
+		c.r.add(Backward, RuleRequiredPropertyAdded, path+"/properties/"+name, "property became required")
+	}
+	for _, name := range difference(oldRequired, newRequired) {
+		c.r.add(Forward, RuleRequiredPropRemoved, path+"/properties/"+name, "property is no longer required")
+	}
+}
+
+// compareProperties 比较 properties 和 additionalProperties
+func (c *schemaComparer) compareProperties(path string, oldSchema, newSchema map[string]interface{}) {
+	oldProps, _ := oldSchema["properties"].(map[string]interface{})
+	newProps, _ := newSchema["properties"].(map[string]interface{})
+	oldClosed, newClosed := isClosed(oldSchema), isClosed(newSchema)
+
+	for _, name := range sortedKeys(oldProps) {
+		propPath := path + "/properties/" + name
+		newProp, ok := newProps[name]
+		if !ok {
+			if newClosed {
+				c.r.add(Backward, RulePropertyRemoved, propPath, "property removed and additional properties are not allowed")
+			}
+			continue
+		}
+		c.compare(propPath, oldProps[name], newProp)
+	}
+
+	for _, name := range sortedKeys(newProps) {
+		if _, ok := oldProps[name]; !ok && oldClosed {
+			c.r.add(Forward, RulePropertyAdded, path+"/properties/"+name, "property added but older schema does not allow additional properties")
+		}
+	}
+
+	if newClosed && !oldClosed {
+		c.r.add(Backward, RuleAdditionalClosed, path, "additional properties are no longer allowed")
+	}
+	if oldClosed && !newClosed {
+		c.r.add(Forward, RuleAdditionalOpened, path, "additional properties are now allowed")
+	}
+}
+
+// compareBounds 比较数值、长度和元素个数范围
+func (c *schemaComparer) compareBounds(path string, oldSchema, newSchema map[string]interface{}) {
+	for _, key := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"} {
+		oldValue, oldOK := oldSchema[key].(float64)
+		newValue, newOK := newSchema[key].(float64)
+		switch {
+		case newOK && (!oldOK || newValue > oldValue):
+			c.r.add(Backward, RuleRangeNarrowed, path, "%s raised to %v", key, newValue)
+		case oldOK && (!newOK || newValue < oldValue):
+			c.r.add(Forward, RuleRangeWidened, path, "%s lowered from %v", key, oldValue)
+		}
+	}
+
+	for _, key := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"} {
+		oldValue, oldOK := oldSchema[key].(float64)
+		newValue, newOK := newSchema[key].(float64)
+		switch {
+		case newOK && (!oldOK || newValue < oldValue):
+			c.r.add(Backward, RuleRangeNarrowed, path, "%s lowered to %v", key, newValue)
+		case oldOK && (!newOK || newValue > oldValue):
+			c.r.add(Forward, RuleRangeWidened, path, "%s raised from %v", key, oldValue)
+		}
+	}
+}
+
+// schemaTypes 返回 Schema 允许的类型，未声明 type 时返回 nil 表示任意类型
+func schemaTypes(schema map[string]interface{}) []string {
+	switch v := schema["type"].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+// uncoveredTypes 返回 from 中不被 to 接受的类型
+func uncoveredTypes(from, to []string) []string {
+	if to == nil {
+		return nil
+	}
+	if from == nil {
+		return []string{"any type"}
+	}
+
+	accepted := make(map[string]bool, len(to))
+	for _, t := range to {
+		accepted[t] = true
+	}
+
+	var missing []string
+	for _, t := range from {
+		if accepted[t] || (t == "integer" && accepted["number"]) {
+			continue
+		}
+		missing = append(missing, t)
+	}
+	return missing
+}
+
+// enumValues 返回 enum 或 const 允许的取值（JSON 编码），未声明时返回 false
+func enumValues(schema map[string]interface{}) ([]string, bool) {
+	if value, ok := schema["const"]; ok {
+		return []string{encodeJSON(value)}, true
+	}
+
+	values, ok := schema["enum"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	encoded := make([]string, 0, len(values))
+	for _, value := range values {
+		encoded = append(encoded, encodeJSON(value))
+	}
+	return encoded, true
+}
+
+// isClosed 检查是否禁止额外属性
+func isClosed(schema map[string]interface{}) bool {
+	allowed, ok := schema["additionalProperties"].(bool)
+	return ok && !allowed
+}
+
+// stringSet 将字符串数组转换为列表
+func stringSet(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// difference 返回 a 中不在 b 中的元素，按字典序排序
+func difference(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, s := range b {
+		set[s] = true
+	}
+
+	var result []string
+	for _, s := range a {
+		if !set[s] {
+			result = append(result, s)
+			set[s] = true
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// encodeJSON 返回值的 JSON 编码
+func encodeJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,169 @@
+package compat
+
+import (
+	"strings"
+	"testing"
+)
+
+const baseUserSchema = `{
+  "type": "object",
+  "required": ["id", "name"],
+  "properties": {
+    "id": {"type": "integer", "minimum": 1},
+    "name": {"type": "string", "maxLength": 64},
+    "status": {"enum": ["active", "disabled"]},
+    "address": {"$ref": "#/definitions/Address"},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  },
+  "definitions": {
+    "Address": {
+      "type": "object",
+      "properties": {
+        "city": {"type": "string"},
+        "parent": {"$ref": "#/definitions/Address"}
+      }
+    }
+  }
+}`
+
+func TestCheckJSONSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		newSchema string
+		mode      Mode
+		wantRules []string
+	}{
+		{
+			name:      "identical",
+			newSchema: baseUserSchema,
+			mode:      Full,
+		},
+		{
+			name:      "optional property added",
+			newSchema: strings.Replace(baseUserSchema, `"tags":`, `"email": {"type": "string"}, "tags":`, 1),
+			mode:      Full,
+		},
+		{
+			name:      "required property added",
+			newSchema: strings.Replace(baseUserSchema, `["id", "name"]`, `["id", "name", "status"]`, 1),
+			mode:      Backward,
+			wantRules: []string{RuleRequiredPropertyAdded},
+		},
+		{
+			name:      "required property added is forward compatible",
+			newSchema: strings.Replace(baseUserSchema, `["id", "name"]`, `["id", "name", "status"]`, 1),
+			mode:      Forward,
+		},
+		{
+			name:      "required property removed",
+			newSchema: strings.Replace(baseUserSchema, `["id", "name"]`, `["id"]`, 1),
+			mode:      Full,
+			wantRules: []string{RuleRequiredPropRemoved},
+		},
+		{
+			name:      "type narrowed",
+			newSchema: strings.Replace(baseUserSchema, `"id": {"type": "integer"`, `"id": {"type": "string"`, 1),
+			mode:      Full,
+			wantRules: []string{RuleTypeNarrowed, RuleTypeWidened},
+		},
+		{
+			name:      "integer widened to number",
+			newSchema: strings.Replace(baseUserSchema, `"id": {"type": "integer"`, `"id": {"type": "number"`, 1),
+			mode:      Backward,
+		},
+		{
+			name:      "enum value added",
+			newSchema: strings.Replace(baseUserSchema, `["active", "disabled"]`, `["active", "disabled", "deleted"]`, 1),
+			mode:      Full,
+			wantRules: []string{RuleEnumWidened},
+		},
+		{
+			name:      "enum value removed",
+			newSchema: strings.Replace(baseUserSchema, `["active", "disabled"]`, `["active"]`, 1),
+			mode:      Backward,
+			wantRules: []string{RuleEnumNarrowed},
+		},
+		{
+			name:      "range narrowed",
+			newSchema: strings.Replace(baseUserSchema, `"maxLength": 64`, `"maxLength": 32`, 1),
+			mode:      Full,
+			wantRules: []string{RuleRangeNarrowed},
+		},
+		{
+			name:      "additional properties closed",
+			newSchema: strings.Replace(baseUserSchema, `"type": "object",`, `"type": "object", "additionalProperties": false,`, 1),
+			mode:      Backward,
+			wantRules: []string{RuleAdditionalClosed},
+		},
+		{
+			name:      "nested ref type changed",
+			newSchema: strings.Replace(baseUserSchema, `"city": {"type": "string"}`, `"city": {"type": "object"}`, 1),
+			mode:      Backward,
+			wantRules: []string{RuleTypeNarrowed},
+		},
+		{
+			name:      "array item type changed",
+			newSchema: strings.Replace(baseUserSchema, `"items": {"type": "string"}`, `"items": {"type": "integer"}`, 1),
+			mode:      Backward,
+			wantRules: []string{RuleTypeNarrowed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := CheckJSONSchema([]byte(baseUserSchema), []byte(tt.newSchema), tt.mode)
+			if err != nil {
+				t.Fatalf("CheckJSONSchema failed: %v", err)
+			}
+			assertRules(t, report, tt.wantRules)
+		})
+	}
+}
+
+func TestCheckJSONSchema_Paths(t *testing.T) {
+	newSchema := strings.Replace(baseUserSchema, `"city": {"type": "string"}`, `"city": {"type": "integer"}`, 1)
+	report, err := CheckJSONSchema([]byte(baseUserSchema), []byte(newSchema), Backward)
+	if err != nil {
+		t.Fatalf("CheckJSONSchema failed: %v", err)
+	}
+	if len(report.Violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %s", report)
+	}
+	if path := report.Violations[0].Path; path != "#/properties/address/properties/city" {
+		t.Errorf("Unexpected violation path %s", path)
+	}
+}
+
+func TestCheckJSONSchema_InvalidInput(t *testing.T) {
+	if _, err := CheckJSONSchema([]byte("{"), []byte(baseUserSchema), Full); err == nil {
+		t.Error("Expected error for invalid old schema")
+	}
+	if _, err := CheckJSONSchema([]byte(baseUserSchema), []byte("not json"), Full); err == nil {
+		t.Error("Expected error for invalid new schema")
+	}
+}
+
+// assertRules 检查报告中的违规规则与期望一致（不考虑顺序）
+func assertRules(t *testing.T, report *Report, want []string) {
+	t.Helper()
+
+	got := make(map[string]bool)
+	for _, v := range report.Violations {
+		got[v.Rule] = true
+	}
+	wantSet := make(map[string]bool)
+	for _, rule := range want {
+		wantSet[rule] = true
+		if !got[rule] {
+			t.Errorf("Expected rule %s, got:\n%s", rule, report)
+		}
+	}
+	for rule := range got {
+		if !wantSet[rule] {
+			t.Errorf("Unexpected rule %s:\n%s", rule, report)
+		}
+	}
+	if report.Compatible() != (len(want) == 0) {
+		t.Errorf("Compatible() = %v, want %v", report.Compatible(), len(want) == 0)
+	}
+}
@@ -0,0 +1,401 @@
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// protobuf 规则
+const (
+	RuleMessageRemoved        = "MESSAGE_REMOVED"
+	RuleFieldNumberReused     = "FIELD_NUMBER_NOT_RESERVED"
+	RuleFieldTypeChanged      = "FIELD_TYPE_CHANGED"
+	RuleFieldNameChanged      = "FIELD_NAME_CHANGED"
+	RuleFieldCardinality      = "FIELD_CARDINALITY_CHANGED"
+	RuleFieldOneofChanged     = "FIELD_ONEOF_CHANGED"
+	RuleRequiredFieldAdded    = "REQUIRED_FIELD_ADDED"
+	RuleRequiredFieldRemoved  = "REQUIRED_FIELD_REMOVED"
+	RuleEnumRemoved           = "ENUM_REMOVED"
+	RuleEnumValueRemoved      = "ENUM_VALUE_REMOVED"
+	RuleEnumValueAdded        = "ENUM_VALUE_ADDED"
+	RuleEnumValueRenamed      = "ENUM_VALUE_RENAMED"
+	RuleServiceRemoved        = "SERVICE_REMOVED"
+	RuleMethodRemoved         = "METHOD_REMOVED"
+	RuleMethodSignatureChange = "METHOD_SIGNATURE_CHANGED"
+)
+
+// ParseDescriptorSet 解析 protoc --descriptor_set_out 生成的二进制描述符集
+func ParseDescriptorSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	return set, nil
+}
+
+// CheckProto 比较两个版本的 protobuf 描述符集
+//
+// 除 protobuf 二进制编码的兼容性外，框架默认使用 JSON 序列化，因此字段和枚举值改名同样视为不兼容。
+// 服务定义按调用方判断：删除方法会使旧客户端无法调用新服务，视为破坏向后兼容。
+func CheckProto(oldSet, newSet *descriptorpb.FileDescriptorSet, mode Mode) *Report {
+	r := newReporter(mode)
+	oldIndex := indexDescriptors(oldSet)
+	newIndex := indexDescriptors(newSet)
+
+	for _, name := range oldIndex.messageNames {
+		oldMsg := oldIndex.messages[name]
+		newMsg, ok := newIndex.messages[name]
+		if !ok {
+			// map 字段的删除已在所属消息中报告
+			if !oldIndex.mapEntries[name] {
+				r.add(Backward, RuleMessageRemoved, name, "message removed")
+			}
+			continue
+		}
+		checkMessage(r, name, oldMsg, newMsg)
+	}
+
+	for _, name := range oldIndex.enumNames {
+		newEnum, ok := newIndex.enums[name]
+		if !ok {
+			r.add(Backward, RuleEnumRemoved, name, "enum removed")
+			continue
+		}
+		checkEnum(r, name, oldIndex.enums[name], newEnum)
+	}
+
+	for _, name := range oldIndex.serviceNames {
+		newService, ok := newIndex.services[name]
+		if !ok {
+			r.add(Backward, RuleServiceRemoved, name, "service removed")
+			continue
+		}
+		checkService(r, name, oldIndex.services[name], newService)
+	}
+
+	return r.report()
+}
+
+// descriptorIndex 按全名索引的描述符
+type descriptorIndex struct {
+	messages     map[string]*descriptorpb.DescriptorProto
+	enums        map[string]*descriptorpb.EnumDescriptorProto
+	services     map[string]*descriptorpb.ServiceDescriptorProto
+	mapEntries   map[string]bool
+	messageNames []string
+	enumNames    []string
+	serviceNames []string
+}
+
+// indexDescriptors 索引描述符集中的消息、枚举和服务，包括嵌套类型
+func indexDescriptors(set *descriptorpb.FileDescriptorSet) *descriptorIndex {
+	index := &descriptorIndex{
+		messages:   make(map[string]*descriptorpb.DescriptorProto),
+		enums:      make(map[string]*descriptorpb.EnumDescriptorProto),
+		services:   make(map[string]*descriptorpb.ServiceDescriptorProto),
+		mapEntries: make(map[string]bool),
+	}
+	if set == nil {
+		return index
+	}
+
+	for _, file := range set.GetFile() {
+		prefix := file.GetPackage()
+		for _, msg := range file.GetMessageType() {
+			index.addMessage(prefix, msg)
+		}
+		for _, enum := range file.GetEnumType() {
+			index.addEnum(prefix, enum)
+		}
+		for _, service := range file.GetService() {
+			name := qualify(prefix, service.GetName())
+			index.services[name] = service
+			index.serviceNames = append(index.serviceNames, name)
+		}
+	}
+	return index
+}
+
+// addMessage 索引消息及其嵌套类型，map 字段生成的 Entry 消息用于比较键值类型
+func (i *descriptorIndex) addMessage(prefix string, msg *descriptorpb.DescriptorProto) {
+	name := qualify(prefix, msg.GetName())
+	i.messages[name] = msg
+	i.messageNames = append(i.messageNames, name)
+	if msg.GetOptions().GetMapEntry() {
+		i.mapEntries[name] = true
+	}
+
+	for _, nested := range msg.GetNestedType() {
+		i.addMessage(name, nested)
+	}
+	for _, enum := range msg.GetEnumType() {
+		i.addEnum(name, enum)
+	}
+}
+
+// addEnum 索引枚举
+func (i *descriptorIndex) addEnum(prefix string, enum *descriptorpb.EnumDescriptorProto) {
+	name := qualify(prefix, enum.GetName())
+	i.enums[name] = enum
+	i.enumNames = append(i.enumNames, name)
+}
+
+// checkMessage 比较同名消息的字段
+func checkMessage(r *reporter, name string, oldMsg, newMsg *descriptorpb.DescriptorProto) {
+	newFields := make(map[int32]*descriptorpb.FieldDescriptorProto, len(newMsg.GetField()))
+	for _, field := range newMsg.GetField() {
+		newFields[field.GetNumber()] = field
+	}
+	oldFields := make(map[int32]*descriptorpb.FieldDescriptorProto, len(oldMsg.GetField()))
+	for _, field := range oldMsg.GetField() {
+		oldFields[field.GetNumber()] = field
+	}
+
+	for _, oldField := range oldMsg.GetField() {
+		path := fieldPath(name, oldField)
+		newField, ok := newFields[oldField.GetNumber()]
+		if !ok {
+			if !isReserved(newMsg, oldField) {
+				r.add(Full, RuleFieldNumberReused, path,
+					"field removed without reserving number %d and name %q", oldField.GetNumber(), oldField.GetName())
+			}
+			if oldField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+				r.add(Forward, RuleRequiredFieldRemoved, path, "required field removed")
+			}
+			continue
+		}
+		checkField(r, path, oldMsg, newMsg, oldField, newField)
+	}
+
+	for _, newField := range newMsg.GetField() {
+		if _, ok := oldFields[newField.GetNumber()]; !ok && newField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+			r.add(Backward, RuleRequiredFieldAdded, fieldPath(name, newField), "required field added")
+		}
+	}
+}
+
+// checkField 比较编号相同的字段
+func checkField(r *reporter, path string, oldMsg, newMsg *descriptorpb.DescriptorProto, oldField, newField *descriptorpb.FieldDescriptorProto) {
+	if oldField.GetName() != newField.GetName() {
+		r.add(Full, RuleFieldNameChanged, path, "field renamed to %q, JSON payloads use field names", newField.GetName())
+	}
+
+	if !wireCompatible(oldField, newField) {
+		r.add(Full, RuleFieldTypeChanged, path, "type changed from %s to %s", fieldType(oldField), fieldType(newField))
+	}
+
+	oldRepeated := oldField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	newRepeated := newField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	if oldRepeated != newRepeated {
+		r.add(Full, RuleFieldCardinality, path, "changed from %s to %s", cardinality(oldRepeated), cardinality(newRepeated))
+	}
+
+	oldRequired := oldField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+	newRequired := newField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+	if newRequired && !oldRequired {
+		r.add(Backward, RuleRequiredFieldAdded, path, "field became required")
+	}
+	if oldRequired && !newRequired {
+		r.add(Forward, RuleRequiredFieldRemoved, path, "field is no longer required")
+	}
+
+	if oldOneof, newOneof := oneofName(oldMsg, oldField), oneofName(newMsg, newField); oldOneof != newOneof {
+		r.add(Full, RuleFieldOneofChanged, path, "oneof changed from %q to %q", oldOneof, newOneof)
+	}
+}
+
+// checkEnum 比较同名枚举的取值
+func checkEnum(r *reporter, name string, oldEnum, newEnum *descriptorpb.EnumDescriptorProto) {
+	newValues := make(map[int32]string, len(newEnum.GetValue()))
+	for _, value := range newEnum.GetValue() {
+		if _, ok := newValues[value.GetNumber()]; !ok {
+			newValues[value.GetNumber()] = value.GetName()
+		}
+	}
+	oldValues := make(map[int32]string, len(oldEnum.GetValue()))
+	for _, value := range oldEnum.GetValue() {
+		if _, ok := oldValues[value.GetNumber()]; !ok {
+			oldValues[value.GetNumber()] = value.GetName()
+		}
+	}
+
+	for _, value := range oldEnum.GetValue() {
+		path := fmt.Sprintf("%s.%s", name, value.GetName())
+		newName, ok := newValues[value.GetNumber()]
+		switch {
+		case !ok:
+			r.add(Backward, RuleEnumValueRemoved, path, "value %d removed", value.GetNumber())
+		case newName != oldValues[value.GetNumber()]:
+			r.add(Full, RuleEnumValueRenamed, path, "value %d renamed to %s, JSON payloads use value names", value.GetNumber(), newName)
+		}
+	}
+
+	for _, value := range newEnum.GetValue() {
+		if _, ok := oldValues[value.GetNumber()]; !ok {
+			r.add(Forward, RuleEnumValueAdded, fmt.Sprintf("%s.%s", name, value.GetName()),
+				"value %d added, older readers cannot decode it from JSON", value.GetNumber())
+		}
+	}
+}
+
+// checkService 比较同名服务的方法
+func checkService(r *reporter, name string, oldService, newService *descriptorpb.ServiceDescriptorProto) {
+	newMethods := make(map[string]*descriptorpb.MethodDescriptorProto, len(newService.GetMethod()))
+	for _, method := range newService.GetMethod() {
+		newMethods[method.GetName()] = method
+	}
+
+	for _, oldMethod := range oldService.GetMethod() {
+		path := name + "." + oldMethod.GetName()
+		newMethod, ok := newMethods[oldMethod.GetName()]
+		if !ok {
+			r.add(Backward, RuleMethodRemoved, path, "method removed")
+			continue
+		}
+		if oldMethod.GetInputType() != newMethod.GetInputType() {
+			r.add(Full, RuleMethodSignatureChange, path, "request type changed from %s to %s",
+				trimDot(oldMethod.GetInputType()), trimDot(newMethod.GetInputType()))
+		}
+		if oldMethod.GetOutputType() != newMethod.GetOutputType() {
+			r.add(Full, RuleMethodSignatureChange, path, "response type changed from %s to %s",
+				trimDot(oldMethod.GetOutputType()), trimDot(newMethod.GetOutputType()))
+		}
+		if oldMethod.GetClientStreaming() != newMethod.GetClientStreaming() || oldMethod.GetServerStreaming() != newMethod.GetServerStreaming() {
+			r.add(Full, RuleMethodSignatureChange, path, "streaming mode changed from %s to %s",
+				streamingMode(oldMethod), streamingMode(newMethod))
+		}
+	}
+}
+
+// wireGroups 编码兼容的标量类型分组，同组类型可以互相替换
+var wireGroups = map[descriptorpb.FieldDescriptorProto_Type]int{
+	descriptorpb.FieldDescriptorProto_TYPE_INT32:    1,
+	descriptorpb.FieldDescriptorProto_TYPE_UINT32:   1,
+	descriptorpb.FieldDescriptorProto_TYPE_INT64:    1,
+	descriptorpb.FieldDescriptorProto_TYPE_UINT64:   1,
+	descriptorpb.FieldDescriptorProto_TYPE_BOOL:     1,
+	descriptorpb.FieldDescriptorProto_TYPE_SINT32:   2,
+	descriptorpb.FieldDescriptorProto_TYPE_SINT64:   2,
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED32:  3,
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED32: 3,
+	descriptorpb.FieldDescriptorProto_TYPE_FIXED64:  4,
+	descriptorpb.FieldDescriptorProto_TYPE_SFIXED64: 4,
+}
+
+// wireCompatible 检查字段类型在二进制和 JSON 编码下是否都兼容
+//
+// JSON 中 64 位整数编码为字符串、32 位整数编码为数字、bytes 编码为 base64，
+// 因此整数类型只在位宽相同时兼容，bool 与整数、string 与 bytes 不兼容
+func wireCompatible(oldField, newField *descriptorpb.FieldDescriptorProto) bool {
+	oldType, newType := oldField.GetType(), newField.GetType()
+	if oldType == newType {
+		switch oldType {
+		case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP,
+			descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+			return oldField.GetTypeName() == newField.GetTypeName()
+		}
+		return true
+	}
+
+	oldGroup, ok := wireGroups[oldType]
+	if !ok || oldGroup != wireGroups[newType] {
+		return false
+	}
+	return is64Bit(oldType) == is64Bit(newType) && (oldType == descriptorpb.FieldDescriptorProto_TYPE_BOOL) == (newType == descriptorpb.FieldDescriptorProto_TYPE_BOOL)
+}
+
+// is64Bit 检查是否为 64 位整数类型
+func is64Bit(t descriptorpb.FieldDescriptorProto_Type) bool {
+	switch t {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return true
+	}
+	return false
+}
+
+// isReserved 检查字段编号和名称是否已在消息中保留
+func isReserved(msg *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto) bool {
+	numberReserved := false
+	for _, reserved := range msg.GetReservedRange() {
+		// ReservedRange 的 End 不包含在内
+		if field.GetNumber() >= reserved.GetStart() && field.GetNumber() < reserved.GetEnd() {
+			numberReserved = true
+			break
+		}
+	}
+	if !numberReserved {
+		return false
+	}
+
+	for _, name := range msg.GetReservedName() {
+		if name == field.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// oneofName 返回字段所属 oneof 的名称，proto3 optional 生成的合成 oneof 视为不属于 oneof
+func oneofName(msg *descriptorpb.DescriptorProto, field *descriptorpb.FieldDescriptorProto) string {
+	if field.OneofIndex == nil || field.GetProto3Optional() {
+		return ""
+	}
+	index := int(field.GetOneofIndex())
+	if index < 0 || index >= len(msg.GetOneofDecl()) {
+		return ""
+	}
+	return msg.GetOneofDecl()[index].GetName()
+}
+
+// fieldType 返回字段类型描述
+func fieldType(field *descriptorpb.FieldDescriptorProto) string {
+	if field.GetTypeName() != "" {
+		return trimDot(field.GetTypeName())
+	}
+	return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+}
+
+// fieldPath 返回字段位置，如 framework.common.Message.timestamp(3)
+func fieldPath(message string, field *descriptorpb.FieldDescriptorProto) string {
+	return fmt.Sprintf("%s.%s(%d)", message, field.GetName(), field.GetNumber())
+}
+
+// cardinality 返回字段基数描述
+func cardinality(repeated bool) string {
+	if repeated {
+		return "repeated"
+	}
+	return "singular"
+}
+
+// streamingMode 返回方法的流式模式描述
+func streamingMode(method *descriptorpb.MethodDescriptorProto) string {
+	switch {
+	case method.GetClientStreaming() && method.GetServerStreaming():
+		return "bidi-streaming"
+	case method.GetClientStreaming():
+		return "client-streaming"
+	case method.GetServerStreaming():
+		return "server-streaming"
+	default:
+		return "unary"
+	}
+}
+
+// qualify 拼接全名
+func qualify(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// trimDot 去掉类型引用开头的点
+func trimDot(name string) string {
+	return strings.TrimPrefix(name, ".")
+}
@@ -0,0 +1,234 @@
+package compat
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// field 创建字段描述符
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   typ.Enum(),
+		Label:  label.Enum(),
+	}
+}
+
+const (
+	optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	required = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+)
+
+// userDescriptorSet 创建测试用的描述符集，modify 修改 User 消息、Status 枚举和 UserService 服务
+func userDescriptorSet(modify func(user *descriptorpb.DescriptorProto, status *descriptorpb.EnumDescriptorProto, service *descriptorpb.ServiceDescriptorProto)) *descriptorpb.FileDescriptorSet {
+	user := &descriptorpb.DescriptorProto{
+		Name: proto.String("User"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional),
+			field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+			field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated),
+			field("age", 4, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional),
+		},
+	}
+	status := &descriptorpb.EnumDescriptorProto{
+		Name: proto.String("Status"),
+		Value: []*descriptorpb.EnumValueDescriptorProto{
+			{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+			{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+		},
+	}
+	service := &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String("UserService"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			{Name: proto.String("GetUser"), InputType: proto.String(".demo.User"), OutputType: proto.String(".demo.User")},
+			{Name: proto.String("DeleteUser"), InputType: proto.String(".demo.User"), OutputType: proto.String(".demo.User")},
+		},
+	}
+	if modify != nil {
+		modify(user, status, service)
+	}
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:        proto.String("demo.proto"),
+			Package:     proto.String("demo"),
+			Syntax:      proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{user},
+			EnumType:    []*descriptorpb.EnumDescriptorProto{status},
+			Service:     []*descriptorpb.ServiceDescriptorProto{service},
+		}},
+	}
+}
+
+func TestCheckProto(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(user *descriptorpb.DescriptorProto, status *descriptorpb.EnumDescriptorProto, service *descriptorpb.ServiceDescriptorProto)
+		mode      Mode
+		wantRules []string
+	}{
+		{
+			name: "identical",
+			mode: Full,
+		},
+		{
+			name: "optional field added",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field = append(user.Field, field("email", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional))
+			},
+			mode: Full,
+		},
+		{
+			name: "field removed without reservation",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field = user.Field[:3]
+			},
+			mode:      Full,
+			wantRules: []string{RuleFieldNumberReused},
+		},
+		{
+			name: "field removed and reserved",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field = user.Field[:3]
+				user.ReservedRange = []*descriptorpb.DescriptorProto_ReservedRange{{Start: proto.Int32(4), End: proto.Int32(5)}}
+				user.ReservedName = []string{"age"}
+			},
+			mode: Full,
+		},
+		{
+			name: "field type changed",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+			},
+			mode:      Backward,
+			wantRules: []string{RuleFieldTypeChanged},
+		},
+		{
+			name: "compatible integer type change",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum()
+			},
+			mode: Full,
+		},
+		{
+			name: "integer width changed",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field[3].Type = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+			},
+			mode:      Full,
+			wantRules: []string{RuleFieldTypeChanged},
+		},
+		{
+			name: "field renamed",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field[1].Name = proto.String("full_name")
+			},
+			mode:      Full,
+			wantRules: []string{RuleFieldNameChanged},
+		},
+		{
+			name: "repeated became singular",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field[2].Label = optional.Enum()
+			},
+			mode:      Forward,
+			wantRules: []string{RuleFieldCardinality},
+		},
+		{
+			name: "required field added breaks backward",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field = append(user.Field, field("email", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, required))
+			},
+			mode:      Backward,
+			wantRules: []string{RuleRequiredFieldAdded},
+		},
+		{
+			name: "required field added keeps forward",
+			modify: func(user *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				user.Field = append(user.Field, field("email", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, required))
+			},
+			mode: Forward,
+		},
+		{
+			name: "enum value added breaks forward",
+			modify: func(_ *descriptorpb.DescriptorProto, status *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				status.Value = append(status.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String("DISABLED"), Number: proto.Int32(2)})
+			},
+			mode:      Full,
+			wantRules: []string{RuleEnumValueAdded},
+		},
+		{
+			name: "enum value added keeps backward",
+			modify: func(_ *descriptorpb.DescriptorProto, status *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				status.Value = append(status.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String("DISABLED"), Number: proto.Int32(2)})
+			},
+			mode: Backward,
+		},
+		{
+			name: "enum value removed",
+			modify: func(_ *descriptorpb.DescriptorProto, status *descriptorpb.EnumDescriptorProto, _ *descriptorpb.ServiceDescriptorProto) {
+				status.Value = status.Value[:1]
+			},
+			mode:      Backward,
+			wantRules: []string{RuleEnumValueRemoved},
+		},
+		{
+			name: "method removed",
+			modify: func(_ *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, service *descriptorpb.ServiceDescriptorProto) {
+				service.Method = service.Method[:1]
+			},
+			mode:      Backward,
+			wantRules: []string{RuleMethodRemoved},
+		},
+		{
+			name: "method became streaming",
+			modify: func(_ *descriptorpb.DescriptorProto, _ *descriptorpb.EnumDescriptorProto, service *descriptorpb.ServiceDescriptorProto) {
+				service.Method[0].ServerStreaming = proto.Bool(true)
+			},
+			mode:      Full,
+			wantRules: []string{RuleMethodSignatureChange},
+		},
+	}
+
+	oldSet := userDescriptorSet(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckProto(oldSet, userDescriptorSet(tt.modify), tt.mode)
+			assertRules(t, report, tt.wantRules)
+		})
+	}
+}
+
+func TestCheckProto_MessageRemoved(t *testing.T) {
+	newSet := userDescriptorSet(nil)
+	newSet.File[0].MessageType = nil
+
+	report := CheckProto(userDescriptorSet(nil), newSet, Backward)
+	assertRules(t, report, []string{RuleMessageRemoved})
+	if report.Violations[0].Path != "demo.User" {
+		t.Errorf("Unexpected path %s", report.Violations[0].Path)
+	}
+}
+
+func TestParseDescriptorSet(t *testing.T) {
+	data, err := proto.Marshal(userDescriptorSet(nil))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	set, err := ParseDescriptorSet(data)
+	if err != nil {
+		t.Fatalf("ParseDescriptorSet failed: %v", err)
+	}
+	if !CheckProto(userDescriptorSet(nil), set, Full).Compatible() {
+		t.Error("Parsed descriptor set should match the original")
+	}
+
+	if _, err := ParseDescriptorSet([]byte{0xff, 0xff}); err == nil {
+		t.Error("Expected error for invalid descriptor set")
+	}
+}
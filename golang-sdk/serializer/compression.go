@@ -0,0 +1,308 @@
+package serializer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionType 压缩算法
+type CompressionType string
+
+const (
+	Gzip   CompressionType = "gzip"
+	Zstd   CompressionType = "zstd"
+	Snappy CompressionType = "snappy"
+)
+
+// 各压缩格式的魔数，反序列化时据此识别压缩算法
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	zstdMagic   = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY") // snappy 流格式的 stream identifier
+)
+
+// Compressor 压缩器接口
+type Compressor interface {
+	// Compress 压缩数据，输出以该算法的魔数开头
+	Compress(data []byte) ([]byte, error)
+
+	// Decompress 解压数据
+	Decompress(data []byte) ([]byte, error)
+
+	// GetType 获取压缩算法
+	GetType() CompressionType
+}
+
+// CompressionConfig 压缩配置
+type CompressionConfig struct {
+	// Type 压缩算法
+	Type CompressionType
+	// Level 压缩级别，0 表示使用算法默认级别，snappy 忽略该值
+	Level int
+	// MinSize 小于该字节数的数据不压缩，原样输出
+	MinSize int
+}
+
+// DefaultCompressionConfig 返回默认压缩配置：gzip，默认级别，1KB 以下不压缩
+func DefaultCompressionConfig() *CompressionConfig {
+	return &CompressionConfig{
+		Type:    Gzip,
+		MinSize: 1024,
+	}
+}
+
+// NewCompressor 创建指定算法的压缩器
+func NewCompressor(compression CompressionType, level int) (Compressor, error) {
+	switch compression {
+	case Gzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid gzip level: %d", level)
+		}
+		return &gzipCompressor{level: level}, nil
+	case Zstd:
+		encoderLevel := zstd.SpeedDefault
+		if level != 0 {
+			encoderLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		return newZstdCompressor(encoderLevel)
+	case Snappy:
+		return &snappyCompressor{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression type: %s", compression)
+	}
+}
+
+// DetectCompression 根据魔数识别数据的压缩算法，未压缩时返回空字符串
+func DetectCompression(data []byte) CompressionType {
+	switch {
+	case bytes.HasPrefix(data, zstdMagic):
+		return Zstd
+	case bytes.HasPrefix(data, snappyMagic):
+		return Snappy
+	case bytes.HasPrefix(data, gzipMagic):
+		return Gzip
+	default:
+		return ""
+	}
+}
+
+// Decompress 使用指定算法解压数据，解压不依赖压缩级别
+func Decompress(compression CompressionType, data []byte) ([]byte, error) {
+	switch compression {
+	case Gzip:
+		return (&gzipCompressor{}).Decompress(data)
+	case Zstd:
+		decoder, err := sharedZstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(data, nil)
+	case Snappy:
+		return (&snappyCompressor{}).Decompress(data)
+	default:
+		return nil, fmt.Errorf("unsupported compression type: %s", compression)
+	}
+}
+
+// CompressedSerializer 压缩序列化器，包装任意序列化器，序列化后压缩、反序列化前解压
+//
+// 反序列化时按魔数识别压缩算法，因此可以读取任意受支持算法压缩的数据以及未压缩的数据
+type CompressedSerializer struct {
+	inner      Serializer
+	compressor Compressor
+	minSize    int
+}
+
+// NewCompressedSerializer 创建压缩序列化器
+func NewCompressedSerializer(inner Serializer, config *CompressionConfig) (*CompressedSerializer, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("inner serializer cannot be nil")
+	}
+	if config == nil {
+		return nil, fmt.Errorf("compression config cannot be nil")
+	}
+
+	compressor, err := NewCompressor(config.Type, config.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompressedSerializer{
+		inner:      inner,
+		compressor: compressor,
+		minSize:    config.MinSize,
+	}, nil
+}
+
+// Serialize 序列化并压缩数据，格式一致的 RawPayload 跳过序列化直接压缩
+func (s *CompressedSerializer) Serialize(data interface{}) ([]byte, error) {
+	raw, err := s.encode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < s.minSize {
+		recordCompressionSkipped(s.inner.GetFormat(), s.compressor.GetType())
+		return raw, nil
+	}
+
+	compressed, err := s.compressor.Compress(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress %s data: %w", s.compressor.GetType(), err)
+	}
+	recordCompression(s.inner.GetFormat(), s.compressor.GetType(), len(raw), len(compressed))
+	return compressed, nil
+}
+
+// encode 使用被包装的序列化器序列化数据
+func (s *CompressedSerializer) encode(data interface{}) ([]byte, error) {
+	if payload, ok := AsRawPayload(data); ok && payload.Format == s.inner.GetFormat() {
+		recordRawPayload(payload.Format, payload.Format, rawPassthrough)
+		return payload.Data, nil
+	}
+	return s.inner.Serialize(data)
+}
+
+// Deserialize 按魔数解压后反序列化数据
+func (s *CompressedSerializer) Deserialize(data []byte, target interface{}) error {
+	if compression := DetectCompression(data); compression != "" {
+		raw, err := Decompress(compression, data)
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s data: %w", compression, err)
+		}
+		data = raw
+	}
+	return s.inner.Deserialize(data, target)
+}
+
+// GetFormat 获取被包装序列化器的格式
+func (s *CompressedSerializer) GetFormat() SerializationFormat {
+	return s.inner.GetFormat()
+}
+
+// GetCompression 获取压缩算法
+func (s *CompressedSerializer) GetCompression() CompressionType {
+	return s.compressor.GetType()
+}
+
+// Unwrap 获取被包装的序列化器
+func (s *CompressedSerializer) Unwrap() Serializer {
+	return s.inner
+}
+
+// gzipCompressor gzip 压缩器
+type gzipCompressor struct {
+	level int
+}
+
+// Compress 压缩数据
+func (c *gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress 解压数据
+func (c *gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// GetType 获取压缩算法
+func (c *gzipCompressor) GetType() CompressionType {
+	return Gzip
+}
+
+var (
+	// zstd 解码器与压缩级别无关，所有压缩器共享同一个
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+)
+
+// sharedZstdDecoder 获取共享的 zstd 解码器
+func sharedZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil)
+		if zstdDecoderErr != nil {
+			zstdDecoderErr = fmt.Errorf("failed to create zstd decoder: %w", zstdDecoderErr)
+		}
+	})
+	return zstdDecoder, zstdDecoderErr
+}
+
+// zstdCompressor zstd 压缩器，EncodeAll 和 DecodeAll 支持并发调用
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+}
+
+// newZstdCompressor 创建 zstd 压缩器
+func newZstdCompressor(level zstd.EncoderLevel) (*zstdCompressor, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	return &zstdCompressor{encoder: encoder}, nil
+}
+
+// Compress 压缩数据
+func (c *zstdCompressor) Compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+// Decompress 解压数据
+func (c *zstdCompressor) Decompress(data []byte) ([]byte, error) {
+	return Decompress(Zstd, data)
+}
+
+// GetType 获取压缩算法
+func (c *zstdCompressor) GetType() CompressionType {
+	return Zstd
+}
+
+// snappyCompressor snappy 压缩器，使用带 stream identifier 的流格式以便识别
+type snappyCompressor struct{}
+
+// Compress 压缩数据
+func (c *snappyCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := snappy.NewBufferedWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress 解压数据
+func (c *snappyCompressor) Decompress(data []byte) ([]byte, error) {
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
+
+// GetType 获取压缩算法
+func (c *snappyCompressor) GetType() CompressionType {
+	return Snappy
+}
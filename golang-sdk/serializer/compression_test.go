@@ -0,0 +1,183 @@
+package serializer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type compressionPayload struct {
+	ID    int               `json:"id"`
+	Name  string            `json:"name"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+func newCompressionPayload() compressionPayload {
+	return compressionPayload{
+		ID:    42,
+		Name:  strings.Repeat("order-service ", 100),
+		Tags:  []string{"a", "b", "c"},
+		Attrs: map[string]string{"region": "eu", "tier": "gold"},
+	}
+}
+
+func TestCompressedSerializer_RoundTrip(t *testing.T) {
+	for _, compression := range []CompressionType{Gzip, Zstd, Snappy} {
+		t.Run(string(compression), func(t *testing.T) {
+			s, err := NewCompressedSerializer(NewJsonSerializer(), &CompressionConfig{Type: compression})
+			if err != nil {
+				t.Fatalf("NewCompressedSerializer failed: %v", err)
+			}
+
+			payload := newCompressionPayload()
+			data, err := s.Serialize(payload)
+			if err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			if got := DetectCompression(data); got != compression {
+				t.Errorf("Expected magic bytes for %s, detected %q", compression, got)
+			}
+			raw, _ := NewJsonSerializer().Serialize(payload)
+			if len(data) >= len(raw) {
+				t.Errorf("Expected compressed size < %d, got %d", len(raw), len(data))
+			}
+
+			var result compressionPayload
+			if err := s.Deserialize(data, &result); err != nil {
+				t.Fatalf("Deserialize failed: %v", err)
+			}
+			if !reflect.DeepEqual(result, payload) {
+				t.Errorf("Round trip mismatch: %+v", result)
+			}
+		})
+	}
+}
+
+func TestCompressedSerializer_MinSize(t *testing.T) {
+	s, err := NewCompressedSerializer(NewJsonSerializer(), &CompressionConfig{Type: Gzip, MinSize: 1024})
+	if err != nil {
+		t.Fatalf("NewCompressedSerializer failed: %v", err)
+	}
+
+	// 小于阈值的数据原样输出
+	data, err := s.Serialize(map[string]int{"id": 1})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if string(data) != `{"id":1}` {
+		t.Errorf("Expected uncompressed JSON, got %q", data)
+	}
+
+	var result map[string]int
+	if err := s.Deserialize(data, &result); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if result["id"] != 1 {
+		t.Errorf("Expected id 1, got %v", result)
+	}
+}
+
+func TestCompressedSerializer_DetectsAnyAlgorithm(t *testing.T) {
+	// 反序列化按魔数识别，与自身配置的压缩算法无关
+	reader, err := NewCompressedSerializer(NewJsonSerializer(), &CompressionConfig{Type: Gzip})
+	if err != nil {
+		t.Fatalf("NewCompressedSerializer failed: %v", err)
+	}
+
+	payload := newCompressionPayload()
+	for _, compression := range []CompressionType{Zstd, Snappy} {
+		writer, err := NewCompressedSerializer(NewJsonSerializer(), &CompressionConfig{Type: compression})
+		if err != nil {
+			t.Fatalf("NewCompressedSerializer failed: %v", err)
+		}
+		data, err := writer.Serialize(payload)
+		if err != nil {
+			t.Fatalf("Serialize failed: %v", err)
+		}
+
+		var result compressionPayload
+		if err := reader.Deserialize(data, &result); err != nil {
+			t.Fatalf("Deserialize %s data failed: %v", compression, err)
+		}
+		if result.ID != payload.ID {
+			t.Errorf("Expected id %d, got %d", payload.ID, result.ID)
+		}
+	}
+}
+
+func TestCompressedSerializer_CorruptData(t *testing.T) {
+	s, err := NewCompressedSerializer(NewJsonSerializer(), DefaultCompressionConfig())
+	if err != nil {
+		t.Fatalf("NewCompressedSerializer failed: %v", err)
+	}
+
+	corrupt := append(append([]byte{}, gzipMagic...), 0x00, 0x01, 0x02)
+	var result compressionPayload
+	err = s.Deserialize(corrupt, &result)
+	if err == nil || !strings.Contains(err.Error(), "failed to decompress gzip data") {
+		t.Errorf("Expected decompress error, got %v", err)
+	}
+}
+
+func TestNewCompressedSerializer_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		inner  Serializer
+		config *CompressionConfig
+	}{
+		{name: "nil inner", inner: nil, config: DefaultCompressionConfig()},
+		{name: "nil config", inner: NewJsonSerializer(), config: nil},
+		{name: "unknown type", inner: NewJsonSerializer(), config: &CompressionConfig{Type: "lz4"}},
+		{name: "invalid gzip level", inner: NewJsonSerializer(), config: &CompressionConfig{Type: Gzip, Level: 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCompressedSerializer(tt.inner, tt.config); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestSerializerRegistry_EnableCompression(t *testing.T) {
+	registry := NewSerializerRegistry()
+
+	if err := registry.EnableCompression(PROTOBUF, DefaultCompressionConfig()); err == nil {
+		t.Error("Expected error for unregistered format")
+	}
+
+	if err := registry.EnableCompression(JSON, &CompressionConfig{Type: Gzip}); err != nil {
+		t.Fatalf("EnableCompression failed: %v", err)
+	}
+	// 重复启用时替换压缩配置而不是嵌套包装
+	if err := registry.EnableCompression(JSON, &CompressionConfig{Type: Zstd}); err != nil {
+		t.Fatalf("EnableCompression failed: %v", err)
+	}
+
+	s, err := registry.Get(JSON)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	compressed, ok := s.(*CompressedSerializer)
+	if !ok {
+		t.Fatalf("Expected *CompressedSerializer, got %T", s)
+	}
+	if compressed.GetCompression() != Zstd {
+		t.Errorf("Expected zstd, got %s", compressed.GetCompression())
+	}
+	if _, nested := compressed.Unwrap().(*CompressedSerializer); nested {
+		t.Error("Compression should not be nested")
+	}
+
+	data, err := s.Serialize(newCompressionPayload())
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, zstdMagic) {
+		t.Error("Expected zstd output")
+	}
+}
@@ -0,0 +1,51 @@
+package serializer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// roundTripSample 往返检查默认的样例数据，同时可按 JSON 和 XML 编解码
+type roundTripSample struct {
+	Name  string   `json:"name" xml:"name"`
+	Count int      `json:"count" xml:"count"`
+	Tags  []string `json:"tags" xml:"tag"`
+}
+
+// RoundTripCheck 序列化往返检查，实现 observability.HealthCheck，主要用于启动自检（observability.SelfTest）
+type RoundTripCheck struct {
+	serializer Serializer
+	sample     interface{}
+}
+
+// NewRoundTripCheck 创建序列化往返检查，sample 序列化后反序列化的结果与之相等时通过
+//
+// sample 为 nil 时使用内置的结构体样例；sample 须为非指针值，反序列化到同类型的新值后以 reflect.DeepEqual 比较
+func NewRoundTripCheck(s Serializer, sample interface{}) *RoundTripCheck {
+	if sample == nil {
+		sample = roundTripSample{Name: "selftest", Count: 42, Tags: []string{"a", "b"}}
+	}
+	return &RoundTripCheck{serializer: s, sample: sample}
+}
+
+// Name 返回检查名称 serializer:<格式>
+func (c *RoundTripCheck) Name() string {
+	return "serializer:" + string(c.serializer.GetFormat())
+}
+
+// Check 样例经序列化和反序列化后不变时返回 nil
+func (c *RoundTripCheck) Check(ctx context.Context) error {
+	data, err := c.serializer.Serialize(c.sample)
+	if err != nil {
+		return fmt.Errorf("failed to serialize sample: %w", err)
+	}
+	decoded := reflect.New(reflect.TypeOf(c.sample))
+	if err := c.serializer.Deserialize(data, decoded.Interface()); err != nil {
+		return fmt.Errorf("failed to deserialize sample: %w", err)
+	}
+	if !reflect.DeepEqual(decoded.Elem().Interface(), c.sample) {
+		return fmt.Errorf("round trip mismatch: got %+v, want %+v", decoded.Elem().Interface(), c.sample)
+	}
+	return nil
+}
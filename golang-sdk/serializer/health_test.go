@@ -0,0 +1,29 @@
+package serializer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRoundTripCheck(t *testing.T) {
+	ctx := context.Background()
+	if err := NewRoundTripCheck(NewJsonSerializer(), nil).Check(ctx); err != nil {
+		t.Errorf("JSON round trip failed: %v", err)
+	}
+	xmlSerializer, err := NewXmlSerializer(DefaultXmlConfig())
+	if err != nil {
+		t.Fatalf("NewXmlSerializer failed: %v", err)
+	}
+	check := NewRoundTripCheck(xmlSerializer, nil)
+	if check.Name() != "serializer:xml" {
+		t.Errorf("Name = %s", check.Name())
+	}
+	if err := check.Check(ctx); err != nil {
+		t.Errorf("XML round trip failed: %v", err)
+	}
+
+	// 通用数据经 XML 往返后数字变为字符串
+	if err := NewRoundTripCheck(xmlSerializer, map[string]interface{}{"count": 1.0}).Check(ctx); err == nil {
+		t.Error("Expected mismatch for generic XML data")
+	}
+}
@@ -0,0 +1,96 @@
+package serializer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 用于防止重复注册的锁
+	compressionMetricsOnce sync.Once
+	// 压缩比直方图（压缩后大小 / 原始大小）
+	compressionRatio *prometheus.HistogramVec
+	// 压缩前后字节数
+	compressionBytes *prometheus.CounterVec
+	// 小于阈值未压缩的次数
+	compressionSkipped *prometheus.CounterVec
+)
+
+// initCompressionMetrics 初始化压缩指标
+func initCompressionMetrics() {
+	compressionMetricsOnce.Do(func() {
+		compressionRatio = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "framework_serializer_compression_ratio",
+				Help:    "Compressed size divided by original size",
+				Buckets: []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.2},
+			},
+			[]string{"format", "algorithm"},
+		)
+		compressionBytes = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_serializer_compression_bytes_total",
+				Help: "Total number of bytes before and after compression",
+			},
+			[]string{"format", "algorithm", "stage"},
+		)
+		compressionSkipped = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_serializer_compression_skipped_total",
+				Help: "Total number of payloads sent uncompressed because they were below the size threshold",
+			},
+			[]string{"format", "algorithm"},
+		)
+	})
+}
+
+// recordCompression 记录一次压缩
+func recordCompression(format SerializationFormat, algorithm CompressionType, original, compressed int) {
+	initCompressionMetrics()
+
+	if original > 0 {
+		compressionRatio.WithLabelValues(string(format), string(algorithm)).Observe(float64(compressed) / float64(original))
+	}
+	compressionBytes.WithLabelValues(string(format), string(algorithm), "original").Add(float64(original))
+	compressionBytes.WithLabelValues(string(format), string(algorithm), "compressed").Add(float64(compressed))
+}
+
+// recordCompressionSkipped 记录一次因小于阈值而跳过的压缩
+func recordCompressionSkipped(format SerializationFormat, algorithm CompressionType) {
+	initCompressionMetrics()
+	compressionSkipped.WithLabelValues(string(format), string(algorithm)).Inc()
+}
+
+// RawPayload 的处理结果
+const (
+	rawPassthrough = "passthrough"
+	rawTranscoded  = "transcoded"
+)
+
+var (
+	// 用于防止重复注册的锁
+	rawPayloadMetricsOnce sync.Once
+	// RawPayload 原样输出和转码的次数
+	rawPayloadTotal *prometheus.CounterVec
+)
+
+// initRawPayloadMetrics 初始化 RawPayload 指标
+func initRawPayloadMetrics() {
+	rawPayloadMetricsOnce.Do(func() {
+		rawPayloadTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "framework_serializer_raw_payload_total",
+				Help: "Total number of raw payloads passed through unchanged or transcoded to another format",
+			},
+			[]string{"source", "target", "result"},
+		)
+	})
+}
+
+// recordRawPayload 记录一次 RawPayload 的处理
+func recordRawPayload(source, target SerializationFormat, result string) {
+	initRawPayloadMetrics()
+	rawPayloadTotal.WithLabelValues(string(source), string(target), result).Inc()
+}
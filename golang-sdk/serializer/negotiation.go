@@ -0,0 +1,68 @@
+package serializer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatPreference 协商序列化格式时的优先级，靠前的格式优先
+//
+// CUSTOM 格式由双方约定，不参与自动协商
+var FormatPreference = []SerializationFormat{PROTOBUF, MSGPACK, JSON}
+
+// ParseFormats 解析服务注册信息中声明的序列化格式，忽略大小写和空白
+func ParseFormats(formats []string) []SerializationFormat {
+	parsed := make([]SerializationFormat, 0, len(formats))
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format != "" {
+			parsed = append(parsed, SerializationFormat(format))
+		}
+	}
+	return parsed
+}
+
+// Candidates 返回双方都支持的序列化格式，按 FormatPreference 排序
+//
+// remote 为空表示对方未声明序列化格式，视为只支持 JSON
+func Candidates(local, remote []SerializationFormat) []SerializationFormat {
+	if len(remote) == 0 {
+		remote = []SerializationFormat{JSON}
+	}
+
+	var candidates []SerializationFormat
+	for _, format := range FormatPreference {
+		if containsFormat(local, format) && containsFormat(remote, format) {
+			candidates = append(candidates, format)
+		}
+	}
+	return candidates
+}
+
+// Negotiate 选择双方都支持的最优序列化格式
+func Negotiate(local, remote []SerializationFormat) (SerializationFormat, error) {
+	candidates := Candidates(local, remote)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no mutually supported serialization format: local %v, remote %v", local, remote)
+	}
+	return candidates[0], nil
+}
+
+// Negotiate 从已注册的序列化器中选择对方支持的最优序列化器
+func (r *SerializerRegistry) Negotiate(remote []SerializationFormat) (Serializer, error) {
+	format, err := Negotiate(r.GetSupportedFormats(), remote)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(format)
+}
+
+// containsFormat 检查格式列表是否包含指定格式
+func containsFormat(formats []SerializationFormat, format SerializationFormat) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package serializer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stubSerializer 测试用的序列化器，只声明格式
+type stubSerializer struct {
+	JsonSerializer
+	format SerializationFormat
+}
+
+func (s *stubSerializer) GetFormat() SerializationFormat {
+	return s.format
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		local    []SerializationFormat
+		remote   []SerializationFormat
+		expected SerializationFormat
+		wantErr  bool
+	}{
+		{
+			name:     "prefer protobuf",
+			local:    []SerializationFormat{JSON, MSGPACK, PROTOBUF},
+			remote:   []SerializationFormat{JSON, PROTOBUF},
+			expected: PROTOBUF,
+		},
+		{
+			name:     "msgpack over json",
+			local:    []SerializationFormat{JSON, MSGPACK},
+			remote:   []SerializationFormat{MSGPACK, JSON, PROTOBUF},
+			expected: MSGPACK,
+		},
+		{
+			name:     "remote without declaration uses json",
+			local:    []SerializationFormat{PROTOBUF, JSON},
+			expected: JSON,
+		},
+		{
+			name:    "custom is not negotiated",
+			local:   []SerializationFormat{CUSTOM, JSON},
+			remote:  []SerializationFormat{CUSTOM},
+			wantErr: true,
+		},
+		{
+			name:    "no common format",
+			local:   []SerializationFormat{JSON},
+			remote:  []SerializationFormat{PROTOBUF},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := Negotiate(tt.local, tt.remote)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %s", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Negotiate failed: %v", err)
+			}
+			if format != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, format)
+			}
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	local := []SerializationFormat{JSON, PROTOBUF, MSGPACK}
+	remote := ParseFormats([]string{" JSON", "msgpack ", "Protobuf", ""})
+
+	expected := []SerializationFormat{PROTOBUF, MSGPACK, JSON}
+	if got := Candidates(local, remote); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestSerializerRegistry_Negotiate(t *testing.T) {
+	registry := NewSerializerRegistry()
+	registry.Register(&stubSerializer{format: MSGPACK})
+
+	s, err := registry.Negotiate([]SerializationFormat{JSON, MSGPACK})
+	if err != nil {
+		t.Fatalf("Negotiate failed: %v", err)
+	}
+	if s.GetFormat() != MSGPACK {
+		t.Errorf("Expected msgpack, got %s", s.GetFormat())
+	}
+
+	if _, err := registry.Negotiate([]SerializationFormat{PROTOBUF}); err == nil {
+		t.Error("Expected error when remote only supports unregistered formats")
+	}
+}
@@ -0,0 +1,98 @@
+package serializer
+
+import (
+	"fmt"
+)
+
+// RawPayload 已按 Format 编码的负载，用于代理转发时跳过解码再编码
+//
+// 目标格式与 Format 一致时原样输出 Data，不一致时先按 Format 解码再按目标格式编码。
+// Data 不做校验，调用方需保证其为合法的 Format 编码数据
+type RawPayload struct {
+	Format SerializationFormat
+	Data   []byte
+}
+
+// NewRawPayload 创建已编码的负载
+func NewRawPayload(format SerializationFormat, data []byte) *RawPayload {
+	return &RawPayload{Format: format, Data: data}
+}
+
+// MarshalJSON JSON 格式的负载原样输出，使其可以嵌入其他结构中一起编码
+func (p RawPayload) MarshalJSON() ([]byte, error) {
+	if p.Format != JSON {
+		return nil, fmt.Errorf("cannot embed %s payload in JSON", p.Format)
+	}
+	if len(p.Data) == 0 {
+		return []byte("null"), nil
+	}
+	return p.Data, nil
+}
+
+// AsRawPayload 检查数据是否为 RawPayload 或 *RawPayload
+func AsRawPayload(data interface{}) (*RawPayload, bool) {
+	switch p := data.(type) {
+	case *RawPayload:
+		return p, p != nil
+	case RawPayload:
+		return &p, true
+	default:
+		return nil, false
+	}
+}
+
+// Serialize 按指定格式序列化数据，RawPayload 格式一致时原样返回（已启用压缩时仍会压缩），不一致时转码
+func (r *SerializerRegistry) Serialize(format SerializationFormat, data interface{}) ([]byte, error) {
+	raw, isRaw := AsRawPayload(data)
+	if isRaw && raw.Format != format {
+		return r.Transcode(raw, format)
+	}
+
+	serializer, err := r.Get(format)
+	if err != nil {
+		if isRaw {
+			// 未注册该格式的序列化器时同样可以原样转发
+			recordRawPayload(raw.Format, format, rawPassthrough)
+			return raw.Data, nil
+		}
+		return nil, err
+	}
+	if isRaw {
+		if compressed, ok := serializer.(*CompressedSerializer); ok {
+			return compressed.Serialize(raw)
+		}
+		recordRawPayload(raw.Format, format, rawPassthrough)
+		return raw.Data, nil
+	}
+	return serializer.Serialize(data)
+}
+
+// Transcode 将已编码的负载转换为目标格式
+func (r *SerializerRegistry) Transcode(raw *RawPayload, format SerializationFormat) ([]byte, error) {
+	if raw.Format == format {
+		recordRawPayload(raw.Format, format, rawPassthrough)
+		return raw.Data, nil
+	}
+
+	source, err := r.Get(raw.Format)
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.Get(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if len(raw.Data) > 0 {
+		if err := source.Deserialize(raw.Data, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", raw.Format, err)
+		}
+	}
+	data, err := target.Serialize(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload as %s: %w", format, err)
+	}
+	recordRawPayload(raw.Format, format, rawTranscoded)
+	return data, nil
+}
@@ -0,0 +1,112 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJsonSerializer_RawPayloadPassthrough(t *testing.T) {
+	data := []byte(`{"id":9007199254740993}`)
+
+	for _, payload := range []interface{}{NewRawPayload(JSON, data), *NewRawPayload(JSON, data)} {
+		encoded, err := NewJsonSerializer().Serialize(payload)
+		if err != nil {
+			t.Fatalf("Serialize failed: %v", err)
+		}
+		// 原样返回同一块内存，不做拷贝
+		if &encoded[0] != &data[0] {
+			t.Error("Expected raw payload to be returned without copying")
+		}
+	}
+}
+
+func TestRawPayload_EmbeddedInJSON(t *testing.T) {
+	envelope := map[string]interface{}{
+		"result": NewRawPayload(JSON, []byte(`{"id":9007199254740993}`)),
+		"empty":  NewRawPayload(JSON, nil),
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"empty":null,"result":{"id":9007199254740993}}` {
+		t.Errorf("Unexpected result: %s", data)
+	}
+
+	if _, err := json.Marshal(NewRawPayload(PROTOBUF, []byte{0x08, 0x01})); err == nil {
+		t.Error("Expected error when embedding non-JSON payload in JSON")
+	}
+}
+
+func TestSerializerRegistry_SerializeRawPayload(t *testing.T) {
+	registry := NewSerializerRegistry()
+	xmlSerializer, err := NewXmlSerializer(DefaultXmlConfig())
+	if err != nil {
+		t.Fatalf("NewXmlSerializer failed: %v", err)
+	}
+	registry.Register(xmlSerializer)
+
+	raw := NewRawPayload(JSON, []byte(`{"name":"alice"}`))
+
+	// 格式一致时原样返回
+	data, err := registry.Serialize(JSON, raw)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if !bytes.Equal(data, raw.Data) {
+		t.Errorf("Expected passthrough, got %s", data)
+	}
+
+	// 格式不一致时转码
+	data, err = registry.Serialize(XML, raw)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if !strings.Contains(string(data), "<response><name>alice</name></response>") {
+		t.Errorf("Expected transcoded XML, got %s", data)
+	}
+
+	// 未注册的格式同样可以原样转发，但无法转码
+	protobuf := NewRawPayload(PROTOBUF, []byte{0x08, 0x01})
+	data, err = registry.Serialize(PROTOBUF, protobuf)
+	if err != nil || !bytes.Equal(data, protobuf.Data) {
+		t.Errorf("Expected passthrough of unregistered format, got %v, %v", data, err)
+	}
+	if _, err := registry.Serialize(JSON, protobuf); err == nil {
+		t.Error("Expected error when transcoding from unregistered format")
+	}
+
+	// 普通数据按格式序列化
+	data, err = registry.Serialize(JSON, map[string]string{"name": "bob"})
+	if err != nil || string(data) != `{"name":"bob"}` {
+		t.Errorf("Unexpected result: %s, %v", data, err)
+	}
+}
+
+func TestSerializerRegistry_SerializeRawPayloadCompressed(t *testing.T) {
+	registry := NewSerializerRegistry()
+	if err := registry.EnableCompression(JSON, &CompressionConfig{Type: Gzip}); err != nil {
+		t.Fatalf("EnableCompression failed: %v", err)
+	}
+
+	raw := NewRawPayload(JSON, []byte(`{"name":"`+strings.Repeat("a", 2048)+`"}`))
+	data, err := registry.Serialize(JSON, raw)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if DetectCompression(data) != Gzip {
+		t.Error("Expected raw payload to be compressed")
+	}
+
+	var decoded map[string]string
+	s, _ := registry.Get(JSON)
+	if err := s.Deserialize(data, &decoded); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if len(decoded["name"]) != 2048 {
+		t.Errorf("Unexpected decoded length: %d", len(decoded["name"]))
+	}
+}
@@ -13,6 +13,7 @@ const (
 	PROTOBUF SerializationFormat = "protobuf"
 	MSGPACK  SerializationFormat = "msgpack"
 	CUSTOM   SerializationFormat = "custom"
+	XML      SerializationFormat = "xml"
 )
 
 // Serializer 序列化器接口
@@ -58,6 +59,24 @@ func (r *SerializerRegistry) Get(format SerializationFormat) (Serializer, error)
 	return serializer, nil
 }
 
+// EnableCompression 用压缩序列化器替换已注册的序列化器，已压缩时按新配置重新包装
+func (r *SerializerRegistry) EnableCompression(format SerializationFormat, config *CompressionConfig) error {
+	serializer, err := r.Get(format)
+	if err != nil {
+		return err
+	}
+	if compressed, ok := serializer.(*CompressedSerializer); ok {
+		serializer = compressed.Unwrap()
+	}
+
+	compressed, err := NewCompressedSerializer(serializer, config)
+	if err != nil {
+		return err
+	}
+	r.Register(compressed)
+	return nil
+}
+
 // GetSupportedFormats 获取支持的格式
 func (r *SerializerRegistry) GetSupportedFormats() []SerializationFormat {
 	formats := make([]SerializationFormat, 0, len(r.serializers))
@@ -75,8 +94,12 @@ func NewJsonSerializer() *JsonSerializer {
 	return &JsonSerializer{}
 }
 
-// Serialize 序列化数据
+// Serialize 序列化数据，JSON 格式的 RawPayload 原样返回
 func (s *JsonSerializer) Serialize(data interface{}) ([]byte, error) {
+	if raw, ok := AsRawPayload(data); ok && raw.Format == JSON {
+		recordRawPayload(JSON, JSON, rawPassthrough)
+		return raw.Data, nil
+	}
 	return json.Marshal(data)
 }
 
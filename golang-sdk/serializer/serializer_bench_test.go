@@ -1,6 +1,9 @@
 package serializer
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 // BenchmarkJsonSerialize 序列化性能基准测试
 func BenchmarkJsonSerialize(b *testing.B) {
@@ -41,3 +44,65 @@ func BenchmarkJsonRoundTrip(b *testing.B) {
 		_ = s.Deserialize(raw, &result)
 	}
 }
+
+// benchmarkPayload 返回约 n 个元素的负载，用于压缩基准测试
+func benchmarkPayload(n int) map[string]interface{} {
+	items := make([]map[string]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"id": i, "sku": fmt.Sprintf("SKU-%05d", i), "qty": i % 7, "note": "standard delivery",
+		}
+	}
+	return map[string]interface{}{"orderId": "A-1", "items": items}
+}
+
+// BenchmarkXmlRoundTrip XML 序列化往返性能基准测试
+func BenchmarkXmlRoundTrip(b *testing.B) {
+	s, err := NewXmlSerializer(&XmlConfig{RootElement: "order", FieldMapping: map[string]string{"CustNo": "customerId"}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"customerId": "1001", "note": "rush", "items": []interface{}{"X", "Y"},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		raw, err := s.Serialize(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var result map[string]interface{}
+		if err := s.Deserialize(raw, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCompressedRoundTrip 各压缩算法的序列化往返性能基准测试
+func BenchmarkCompressedRoundTrip(b *testing.B) {
+	data := benchmarkPayload(200)
+	for _, compression := range []CompressionType{Gzip, Zstd, Snappy} {
+		b.Run(string(compression), func(b *testing.B) {
+			s, err := NewCompressedSerializer(NewJsonSerializer(), &CompressionConfig{Type: compression})
+			if err != nil {
+				b.Fatal(err)
+			}
+			// 吞吐量按未压缩的字节数计算
+			raw, _ := NewJsonSerializer().Serialize(data)
+			b.SetBytes(int64(len(raw)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				raw, err := s.Serialize(data)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var result map[string]interface{}
+				if err := s.Deserialize(raw, &result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
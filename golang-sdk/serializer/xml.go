@@ -0,0 +1,429 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// XmlConfig XML 序列化配置
+type XmlConfig struct {
+	// RootElement 序列化通用数据时的根元素名称
+	RootElement string
+	// FieldMapping 元素名到字段名的映射，键为元素名或从根元素下一级开始的路径（如 Order/CustNo），
+	// 路径优先于元素名，属性以 @ 开头（如 @id 或 Order/@id）
+	FieldMapping map[string]string
+}
+
+// DefaultXmlConfig 返回默认 XML 配置：根元素为 response，不做字段映射
+func DefaultXmlConfig() *XmlConfig {
+	return &XmlConfig{
+		RootElement: "response",
+	}
+}
+
+// XmlSerializer XML 序列化器，用于只能收发 XML 的遗留客户端
+//
+// 通用数据（map、切片、标量）与 XML 的转换规则：
+//
+//   - 根元素不出现在数据中，SOAP 请求的 Envelope/Body 被去掉，以其中的操作元素为根
+//   - 子元素转换为字段，同名子元素出现多次时转换为数组，叶子元素的值为字符串
+//   - 属性转换为 @ 开头的字段，同时包含子元素或属性和文本的元素，文本放在 #text 字段
+//   - 命名空间前缀被忽略，只使用元素的本地名称
+//
+// 结构体直接使用 encoding/xml 编解码，字段映射不作用于结构体，通过 xml 标签指定元素名
+type XmlSerializer struct {
+	rootElement string
+	// fields 元素名或路径到字段名的映射
+	fields map[string]string
+	// elements 字段名或父路径加字段名到元素名的映射，用于序列化
+	elements map[string]string
+}
+
+// NewXmlSerializer 创建 XML 序列化器
+func NewXmlSerializer(config *XmlConfig) (*XmlSerializer, error) {
+	if config == nil {
+		return nil, fmt.Errorf("xml config cannot be nil")
+	}
+
+	root := config.RootElement
+	if root == "" {
+		root = DefaultXmlConfig().RootElement
+	}
+	if !isXMLName(root) {
+		return nil, fmt.Errorf("invalid xml root element: %q", root)
+	}
+
+	s := &XmlSerializer{
+		rootElement: root,
+		fields:      make(map[string]string, len(config.FieldMapping)),
+		elements:    make(map[string]string, len(config.FieldMapping)),
+	}
+	for key, field := range config.FieldMapping {
+		if key == "" || field == "" {
+			return nil, fmt.Errorf("xml field mapping cannot contain empty names: %q -> %q", key, field)
+		}
+		s.fields[key] = field
+
+		// 按路径映射时，反向映射的键为父路径加字段名
+		elementKey := field
+		parent, name := "", key
+		if i := strings.LastIndex(key, "/"); i >= 0 {
+			parent, name = key[:i], key[i+1:]
+			elementKey = parent + "/" + field
+		}
+		if existing, ok := s.elements[elementKey]; ok && existing != name {
+			return nil, fmt.Errorf("xml field %q is mapped from both %q and %q", field, existing, name)
+		}
+		s.elements[elementKey] = name
+	}
+	return s, nil
+}
+
+// Serialize 序列化数据，结构体使用 encoding/xml，其余数据按通用规则编码
+func (s *XmlSerializer) Serialize(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	if isStruct(data) {
+		if err := xml.NewEncoder(&buf).Encode(data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	// 先按 JSON 规则转换为通用结构，使嵌套的结构体和各种 map 类型遵循 json 标签
+	value, err := toGeneric(data)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := xml.NewEncoder(&buf)
+	if err := s.encodeElement(encoder, s.rootElement, "", value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize 反序列化数据，目标为 *interface{} 或 *map[string]interface{} 时按通用规则解码
+func (s *XmlSerializer) Deserialize(data []byte, target interface{}) error {
+	switch t := target.(type) {
+	case *interface{}:
+		value, err := s.decode(data)
+		if err != nil {
+			return err
+		}
+		*t = value
+		return nil
+	case *map[string]interface{}:
+		value, err := s.decode(data)
+		if err != nil {
+			return err
+		}
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+		}
+		*t = m
+		return nil
+	default:
+		return xml.Unmarshal(data, target)
+	}
+}
+
+// GetFormat 获取序列化格式
+func (s *XmlSerializer) GetFormat() SerializationFormat {
+	return XML
+}
+
+// xmlNode 解析中的 XML 元素
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     strings.Builder
+}
+
+// decode 将 XML 文档解码为通用结构
+func (s *XmlSerializer) decode(data []byte) (interface{}, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.convert(unwrapEnvelope(root), ""), nil
+}
+
+// parseXML 解析 XML 文档，返回根元素
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid xml: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				// 忽略命名空间声明
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.attrs = append(node.attrs, attr)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root != nil {
+				return nil, fmt.Errorf("invalid xml: multiple root elements")
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("invalid xml: no root element")
+	}
+	return root, nil
+}
+
+// unwrapEnvelope 去掉 SOAP 的 Envelope/Body，返回其中的操作元素
+func unwrapEnvelope(root *xmlNode) *xmlNode {
+	if root.name != "Envelope" {
+		return root
+	}
+	for _, child := range root.children {
+		if child.name == "Body" && len(child.children) > 0 {
+			return child.children[0]
+		}
+	}
+	return root
+}
+
+// convert 将元素转换为通用结构，path 为元素相对根元素的路径
+func (s *XmlSerializer) convert(node *xmlNode, path string) interface{} {
+	text := strings.TrimSpace(node.text.String())
+	if len(node.children) == 0 && len(node.attrs) == 0 {
+		return text
+	}
+
+	result := make(map[string]interface{}, len(node.children)+len(node.attrs))
+	for _, attr := range node.attrs {
+		name := "@" + attr.Name.Local
+		result[s.fieldName(path, name)] = attr.Value
+	}
+
+	for _, child := range node.children {
+		childPath := joinPath(path, child.name)
+		field := s.fieldName(path, child.name)
+		value := s.convert(child, childPath)
+
+		switch existing := result[field].(type) {
+		case nil:
+			result[field] = value
+		case []interface{}:
+			result[field] = append(existing, value)
+		default:
+			result[field] = []interface{}{existing, value}
+		}
+	}
+
+	if text != "" {
+		result["#text"] = text
+	}
+	return result
+}
+
+// fieldName 查找元素或属性对应的字段名，路径映射优先
+func (s *XmlSerializer) fieldName(parent, name string) string {
+	if field, ok := s.fields[joinPath(parent, name)]; ok {
+		return field
+	}
+	if field, ok := s.fields[name]; ok {
+		return field
+	}
+	return name
+}
+
+// elementName 查找字段对应的元素名或属性名（含 @），路径映射优先
+func (s *XmlSerializer) elementName(parent, field string) string {
+	if name, ok := s.elements[joinPath(parent, field)]; ok {
+		return name
+	}
+	if name, ok := s.elements[field]; ok {
+		return name
+	}
+	return field
+}
+
+// encodeElement 将通用结构编码为元素，path 为元素相对根元素的路径
+func (s *XmlSerializer) encodeElement(encoder *xml.Encoder, name, path string, value interface{}) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("invalid xml element name: %q", name)
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var children []string
+		for _, key := range keys {
+			if key == "#text" {
+				continue
+			}
+			element := s.elementName(path, key)
+			if strings.HasPrefix(element, "@") {
+				attrName := strings.TrimPrefix(element, "@")
+				if !isXMLName(attrName) {
+					return fmt.Errorf("invalid xml attribute name: %q", attrName)
+				}
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attrName}, Value: formatScalar(v[key])})
+				continue
+			}
+			children = append(children, key)
+		}
+
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if text, ok := v["#text"]; ok {
+			if err := encoder.EncodeToken(xml.CharData(formatScalar(text))); err != nil {
+				return err
+			}
+		}
+		for _, key := range children {
+			element := s.elementName(path, key)
+			childPath := joinPath(path, element)
+			if items, ok := v[key].([]interface{}); ok {
+				for _, item := range items {
+					if err := s.encodeElement(encoder, element, childPath, item); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := s.encodeElement(encoder, element, childPath, v[key]); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	case []interface{}:
+		// 顶层或嵌套的数组，元素命名为 item
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := s.encodeElement(encoder, "item", joinPath(path, "item"), item); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	default:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if value != nil {
+			if err := encoder.EncodeToken(xml.CharData(formatScalar(value))); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	}
+}
+
+// toGeneric 按 JSON 规则将数据转换为 map、切片和标量组成的通用结构
+func toGeneric(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// formatScalar 将标量格式化为 XML 文本
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// isStruct 检查数据是否为结构体或结构体指针
+func isStruct(data interface{}) bool {
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct
+}
+
+// isXMLName 检查是否为不含命名空间前缀的合法 XML 名称
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// joinPath 拼接元素路径
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
@@ -0,0 +1,178 @@
+package serializer
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestXmlSerializer(t *testing.T, mapping map[string]string) *XmlSerializer {
+	t.Helper()
+	s, err := NewXmlSerializer(&XmlConfig{RootElement: "response", FieldMapping: mapping})
+	if err != nil {
+		t.Fatalf("NewXmlSerializer failed: %v", err)
+	}
+	return s
+}
+
+func TestXmlSerializer_DeserializeGeneric(t *testing.T) {
+	s := newTestXmlSerializer(t, map[string]string{
+		"CustNo":      "customerId",
+		"Items/Item":  "items",
+		"@id":         "orderId",
+		"Items/@type": "kind",
+	})
+
+	data := []byte(`<?xml version="1.0"?>
+<Order id="A-1">
+  <CustNo>1001</CustNo>
+  <Note>rush</Note>
+  <Items type="physical">
+    <Item><Sku>X</Sku><Qty>2</Qty></Item>
+    <Item><Sku>Y</Sku><Qty>1</Qty></Item>
+  </Items>
+  <Price currency="EUR">9.99</Price>
+</Order>`)
+
+	var body map[string]interface{}
+	if err := s.Deserialize(data, &body); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"orderId":    "A-1",
+		"customerId": "1001",
+		"Note":       "rush",
+		"Items": map[string]interface{}{
+			"kind": "physical",
+			"items": []interface{}{
+				map[string]interface{}{"Sku": "X", "Qty": "2"},
+				map[string]interface{}{"Sku": "Y", "Qty": "1"},
+			},
+		},
+		"Price": map[string]interface{}{"@currency": "EUR", "#text": "9.99"},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("Unexpected result:\n got: %v\nwant: %v", body, expected)
+	}
+}
+
+func TestXmlSerializer_DeserializeSOAP(t *testing.T) {
+	s := newTestXmlSerializer(t, nil)
+
+	data := []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Header><Token>abc</Token></soap:Header>
+  <soap:Body>
+    <m:GetUser xmlns:m="http://example.com/users"><m:UserId>42</m:UserId></m:GetUser>
+  </soap:Body>
+</soap:Envelope>`)
+
+	var body interface{}
+	if err := s.Deserialize(data, &body); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+
+	expected := map[string]interface{}{"UserId": "42"}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("Expected %v, got %v", expected, body)
+	}
+}
+
+func TestXmlSerializer_SerializeGeneric(t *testing.T) {
+	s := newTestXmlSerializer(t, map[string]string{
+		"CustNo":     "customerId",
+		"@id":        "orderId",
+		"Items/Item": "items",
+	})
+
+	data, err := s.Serialize(map[string]interface{}{
+		"orderId":    "A-1",
+		"customerId": 1001,
+		"paid":       true,
+		"empty":      nil,
+		"Items": map[string]interface{}{
+			"items": []interface{}{"X", "Y"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	// 子元素按字段名排序
+	expected := xml.Header + `<response id="A-1"><Items><Item>X</Item><Item>Y</Item></Items><CustNo>1001</CustNo><empty></empty><paid>true</paid></response>`
+	if string(data) != expected {
+		t.Errorf("Unexpected xml:\n got: %s\nwant: %s", data, expected)
+	}
+}
+
+func TestXmlSerializer_RoundTrip(t *testing.T) {
+	s := newTestXmlSerializer(t, map[string]string{"Name": "name", "@code": "code"})
+
+	original := map[string]interface{}{
+		"code": "C1",
+		"name": "Alice & Bob",
+		"tags": []interface{}{"a", "b"},
+	}
+	data, err := s.Serialize(original)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := s.Deserialize(data, &decoded); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("Round trip mismatch:\n got: %v\nwant: %v", decoded, original)
+	}
+}
+
+func TestXmlSerializer_Struct(t *testing.T) {
+	type user struct {
+		XMLName xml.Name `xml:"User"`
+		ID      int      `xml:"id,attr"`
+		Name    string   `xml:"Name"`
+	}
+	s := newTestXmlSerializer(t, nil)
+
+	data, err := s.Serialize(&user{ID: 7, Name: "alice"})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if !strings.Contains(string(data), `<User id="7"><Name>alice</Name></User>`) {
+		t.Errorf("Unexpected xml: %s", data)
+	}
+
+	var decoded user
+	if err := s.Deserialize(data, &decoded); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if decoded.ID != 7 || decoded.Name != "alice" {
+		t.Errorf("Unexpected result: %+v", decoded)
+	}
+}
+
+func TestXmlSerializer_Errors(t *testing.T) {
+	if _, err := NewXmlSerializer(nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+	if _, err := NewXmlSerializer(&XmlConfig{RootElement: "1root"}); err == nil {
+		t.Error("Expected error for invalid root element")
+	}
+	if _, err := NewXmlSerializer(&XmlConfig{FieldMapping: map[string]string{"A": "x", "B": "x"}}); err == nil {
+		t.Error("Expected error for ambiguous mapping")
+	}
+
+	s := newTestXmlSerializer(t, nil)
+	if _, err := s.Serialize(map[string]interface{}{"bad key": 1}); err == nil {
+		t.Error("Expected error for invalid element name")
+	}
+
+	var body interface{}
+	for _, data := range []string{`<a><b></a>`, ``, `<a/><b/>`} {
+		if err := s.Deserialize([]byte(data), &body); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore 进程内会话存储，用于测试和单实例部署，进程退出后状态丢失
+//
+// 过期的会话在访问时删除，写入时按过期时间的间隔清理其余过期会话
+type MemoryStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	sessions  map[string]*memorySession
+	lastSweep time.Time
+}
+
+// memorySession 进程内的会话
+type memorySession struct {
+	values map[string][]byte
+	expiry time.Time
+}
+
+// NewMemoryStore 创建进程内会话存储，ttl 不大于 0 时使用 DefaultTTL
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryStore{
+		ttl:       ttl,
+		sessions:  make(map[string]*memorySession),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get 读取键的副本
+func (s *MemoryStore) Get(ctx context.Context, id, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.session(id)
+	if sess == nil {
+		return nil, ErrNotFound
+	}
+	value, ok := sess.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Set 写入键的副本并刷新会话的过期时间
+func (s *MemoryStore) Set(ctx context.Context, id, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= s.ttl {
+		s.sweep(now)
+	}
+	sess := s.session(id)
+	if sess == nil {
+		sess = &memorySession{values: make(map[string][]byte)}
+		s.sessions[id] = sess
+	}
+	sess.values[key] = append([]byte(nil), value...)
+	sess.expiry = now.Add(s.ttl)
+	return nil
+}
+
+// Delete 删除键
+func (s *MemoryStore) Delete(ctx context.Context, id, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess := s.session(id); sess != nil {
+		delete(sess.values, key)
+	}
+	return nil
+}
+
+// Touch 刷新会话的过期时间
+func (s *MemoryStore) Touch(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess := s.session(id); sess != nil {
+		sess.expiry = time.Now().Add(s.ttl)
+	}
+	return nil
+}
+
+// TTL 返回会话的剩余过期时间
+func (s *MemoryStore) TTL(ctx context.Context, id string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.session(id)
+	if sess == nil {
+		return 0, ErrNotFound
+	}
+	return time.Until(sess.expiry), nil
+}
+
+// Destroy 删除会话
+func (s *MemoryStore) Destroy(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// session 返回未过期的会话，已过期时删除并返回 nil，调用方持有 s.mu
+func (s *MemoryStore) session(id string) *memorySession {
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+	if !time.Now().Before(sess.expiry) {
+		delete(s.sessions, id)
+		return nil
+	}
+	return sess
+}
+
+// sweep 删除所有过期的会话，调用方持有 s.mu
+func (s *MemoryStore) sweep(now time.Time) {
+	for id, sess := range s.sessions {
+		if !now.Before(sess.expiry) {
+			delete(s.sessions, id)
+		}
+	}
+	s.lastSweep = now
+}
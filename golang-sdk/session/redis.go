@@ -0,0 +1,182 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/framework/golang-sdk/internal/resp"
+)
+
+// DefaultKeyPrefix RedisStore 会话键的默认前缀
+const DefaultKeyPrefix = "session:"
+
+// RedisConfig Redis 会话存储配置
+type RedisConfig struct {
+	// Address 服务器地址，默认为 127.0.0.1:6379
+	Address string
+	// Username/Password 认证信息，Username 为空时使用旧版 AUTH password
+	Username string
+	Password string
+	// DB 数据库编号
+	DB int
+	// KeyPrefix 会话键的前缀，默认为 DefaultKeyPrefix
+	KeyPrefix string
+	// TTL 会话的过期时间，默认为 DefaultTTL
+	TTL time.Duration
+	// Timeout 连接和命令超时，默认为 5 秒
+	Timeout time.Duration
+}
+
+// RedisStore 基于 Redis 的会话存储，多个实例共用
+//
+// 每个会话对应一个哈希（<KeyPrefix><会话 ID>），键为哈希字段，过期时间由 PEXPIRE 设置，过期后由 Redis 删除
+type RedisStore struct {
+	config RedisConfig
+
+	mu     sync.Mutex
+	conn   *resp.Conn
+	closed bool
+}
+
+// NewRedisStore 连接 Redis
+func NewRedisStore(config *RedisConfig) (*RedisStore, error) {
+	cfg := RedisConfig{}
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Address == "" {
+		cfg.Address = "127.0.0.1:6379"
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = DefaultKeyPrefix
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	s := &RedisStore{config: cfg}
+	conn, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// Get 以 HGET 读取键
+func (s *RedisStore) Get(ctx context.Context, id, key string) ([]byte, error) {
+	reply, err := s.do("HGET", s.key(id), key)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// Set 以 HSET 写入键，再以 PEXPIRE 刷新会话的过期时间
+func (s *RedisStore) Set(ctx context.Context, id, key string, value []byte) error {
+	if _, err := s.do("HSET", s.key(id), key, value); err != nil {
+		return err
+	}
+	return s.Touch(ctx, id)
+}
+
+// Delete 以 HDEL 删除键
+func (s *RedisStore) Delete(ctx context.Context, id, key string) error {
+	_, err := s.do("HDEL", s.key(id), key)
+	return err
+}
+
+// Touch 以 PEXPIRE 刷新会话的过期时间
+func (s *RedisStore) Touch(ctx context.Context, id string) error {
+	_, err := s.do("PEXPIRE", s.key(id), int64(s.config.TTL/time.Millisecond))
+	return err
+}
+
+// TTL 以 PTTL 返回会话的剩余过期时间，会话没有过期时间时返回 0
+func (s *RedisStore) TTL(ctx context.Context, id string) (time.Duration, error) {
+	reply, err := s.do("PTTL", s.key(id))
+	if err != nil {
+		return 0, err
+	}
+	millis, _ := reply.(int64)
+	switch {
+	case millis == -2:
+		return 0, ErrNotFound
+	case millis < 0:
+		return 0, nil
+	}
+	return time.Duration(millis) * time.Millisecond, nil
+}
+
+// Destroy 以 DEL 删除会话
+func (s *RedisStore) Destroy(ctx context.Context, id string) error {
+	_, err := s.do("DEL", s.key(id))
+	return err
+}
+
+// Close 关闭连接
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// key 返回会话的 Redis 键
+func (s *RedisStore) key(id string) string {
+	return s.config.KeyPrefix + id
+}
+
+// dial 按配置连接 Redis
+func (s *RedisStore) dial() (*resp.Conn, error) {
+	return resp.Dial(&resp.Config{
+		Address:  s.config.Address,
+		Username: s.config.Username,
+		Password: s.config.Password,
+		DB:       s.config.DB,
+		Timeout:  s.config.Timeout,
+	})
+}
+
+// do 发送命令，连接断开时重新连接
+func (s *RedisStore) do(args ...interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.New("session store closed")
+	}
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	reply, err := s.conn.Do(0, args...)
+	if err != nil {
+		if resp.ErrorOf(err) == "" {
+			s.conn.Close()
+			s.conn = nil
+		}
+		return nil, fmt.Errorf("session store: %w", err)
+	}
+	return reply, nil
+}
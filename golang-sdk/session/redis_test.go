@@ -0,0 +1,16 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedisStore(t *testing.T) {
+	store, err := NewRedisStore(&RedisConfig{TTL: time.Minute, KeyPrefix: "framework-test:session:"})
+	if err != nil {
+		t.Skipf("Skipping test: redis not available: %v", err)
+	}
+	defer store.Close()
+
+	testStore(t, store, NewID())
+}
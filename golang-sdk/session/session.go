@@ -0,0 +1,157 @@
+// Package session 提供会话状态存储，WebSocket、自定义二进制协议等有状态协议按连接或认证身份保存状态，
+// 多个网关实例使用共享的 Store（如 RedisStore）时，客户端重连到任一实例都能读取之前的状态。
+//
+// 会话是一组键值，整个会话有统一的过期时间，Set 和 Touch 刷新过期时间：
+//
+//	sess, ok := session.FromContext(ctx)
+//	if ok {
+//	    err := sess.SetJSON(ctx, "cart", cart)
+//	}
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// DefaultTTL 会话默认的过期时间
+const DefaultTTL = 30 * time.Minute
+
+// ErrNotFound 会话或键不存在，或会话已过期
+var ErrNotFound = errors.New("session not found")
+
+// Store 会话状态存储，须支持并发调用
+//
+// 多实例部署时应使用共享存储，任一实例都能读写同一会话
+type Store interface {
+	// Get 读取会话中的键，会话或键不存在时返回 ErrNotFound
+	Get(ctx context.Context, id, key string) ([]byte, error)
+	// Set 写入会话中的键并刷新会话的过期时间，会话不存在时创建
+	Set(ctx context.Context, id, key string, value []byte) error
+	// Delete 删除会话中的键，不存在时返回 nil
+	Delete(ctx context.Context, id, key string) error
+	// Touch 刷新会话的过期时间，会话不存在时返回 nil
+	Touch(ctx context.Context, id string) error
+	// TTL 返回会话的剩余过期时间，会话不存在时返回 ErrNotFound
+	TTL(ctx context.Context, id string) (time.Duration, error)
+	// Destroy 删除整个会话，不存在时返回 nil
+	Destroy(ctx context.Context, id string) error
+}
+
+// Session 绑定到会话 ID 的状态读写
+type Session struct {
+	id    string
+	store Store
+}
+
+// New 创建会话 ID 为 id 的会话，会话在第一次 Set 时写入存储
+func New(store Store, id string) *Session {
+	return &Session{id: id, store: store}
+}
+
+// ID 返回会话 ID
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get 读取键，不存在时返回 ErrNotFound
+func (s *Session) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.store.Get(ctx, s.id, key)
+}
+
+// Set 写入键并刷新会话的过期时间
+func (s *Session) Set(ctx context.Context, key string, value []byte) error {
+	return s.store.Set(ctx, s.id, key, value)
+}
+
+// GetJSON 读取键并以 JSON 解码到 v，不存在时返回 ErrNotFound
+func (s *Session) GetJSON(ctx context.Context, key string, v interface{}) error {
+	data, err := s.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// SetJSON 以 JSON 编码 v 后写入键
+func (s *Session) SetJSON(ctx context.Context, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Set(ctx, key, data)
+}
+
+// Delete 删除键
+func (s *Session) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, s.id, key)
+}
+
+// Touch 刷新会话的过期时间
+func (s *Session) Touch(ctx context.Context) error {
+	return s.store.Touch(ctx, s.id)
+}
+
+// TTL 返回会话的剩余过期时间，会话不存在时返回 ErrNotFound
+func (s *Session) TTL(ctx context.Context) (time.Duration, error) {
+	return s.store.TTL(ctx, s.id)
+}
+
+// Destroy 删除整个会话
+func (s *Session) Destroy(ctx context.Context) error {
+	return s.store.Destroy(ctx, s.id)
+}
+
+// NewID 生成 128 位随机会话 ID，用于绑定到连接的会话
+func NewID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// ValidID 判断 id 是否为 NewID 生成的格式，客户端传入的会话 ID 须先检查
+func ValidID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// IdentityID 返回绑定到认证身份的会话 ID（user:<租户>/<用户>），同一用户的所有连接共用会话；
+// 未认证或没有用户 ID 时返回空
+func IdentityID(sc *adapter.SecurityContext) string {
+	if sc == nil || sc.UserID == "" {
+		return ""
+	}
+	return "user:" + sc.TenantID + "/" + sc.UserID
+}
+
+// ForIdentity 返回 context 中认证身份的会话，未认证时返回 false
+func ForIdentity(ctx context.Context, store Store) (*Session, bool) {
+	id := IdentityID(adapter.SecurityContextFromContext(ctx))
+	if id == "" {
+		return nil, false
+	}
+	return New(store, id), true
+}
+
+// sessionKey 会话的上下文键
+type sessionKey struct{}
+
+// WithSession 将会话写入 context，协议处理器为每个请求写入所属连接的会话
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// FromContext 读取协议处理器写入的会话，没有时返回 false
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*Session)
+	return s, ok && s != nil
+}
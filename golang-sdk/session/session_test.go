@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// testStore 按 Store 接口约定测试会话存储
+func testStore(t *testing.T, store Store, id string) {
+	ctx := context.Background()
+	defer store.Destroy(ctx, id)
+
+	if _, err := store.Get(ctx, id, "cart"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get on missing session: err = %v, want ErrNotFound", err)
+	}
+	if _, err := store.TTL(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TTL on missing session: err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Set(ctx, id, "cart", []byte(`["a"]`)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, err := store.Get(ctx, id, "cart"); err != nil || string(value) != `["a"]` {
+		t.Errorf("Get = %s, %v", value, err)
+	}
+	if _, err := store.Get(ctx, id, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get on missing key: err = %v, want ErrNotFound", err)
+	}
+	if ttl, err := store.TTL(ctx, id); err != nil || ttl <= 0 {
+		t.Errorf("TTL = %v, %v", ttl, err)
+	}
+
+	if err := store.Delete(ctx, id, "cart"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(ctx, id, "cart"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+
+	store.Set(ctx, id, "step", []byte("2"))
+	if err := store.Destroy(ctx, id); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if _, err := store.Get(ctx, id, "step"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Destroy: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(time.Minute), NewID())
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(50 * time.Millisecond)
+	store.Set(ctx, "a", "k", []byte("v"))
+	store.Set(ctx, "b", "k", []byte("v"))
+
+	time.Sleep(30 * time.Millisecond)
+	store.Touch(ctx, "a")
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "a", "k"); err != nil {
+		t.Errorf("expected touched session to be kept: %v", err)
+	}
+	if _, err := store.Get(ctx, "b", "k"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected expired session to be removed, err = %v", err)
+	}
+}
+
+func TestSessionJSON(t *testing.T) {
+	ctx := context.Background()
+	sess := New(NewMemoryStore(0), "s-1")
+
+	if err := sess.SetJSON(ctx, "profile", map[string]int{"level": 3}); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	var profile map[string]int
+	if err := sess.GetJSON(ctx, "profile", &profile); err != nil || profile["level"] != 3 {
+		t.Errorf("GetJSON = %v, %v", profile, err)
+	}
+	if err := sess.GetJSON(ctx, "missing", &profile); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetJSON on missing key: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	if id := NewID(); !ValidID(id) {
+		t.Errorf("NewID generated invalid id %q", id)
+	}
+	for _, id := range []string{"", "abc", "user:t/u", "zz" + NewID()[2:]} {
+		if ValidID(id) {
+			t.Errorf("ValidID(%q) = true", id)
+		}
+	}
+
+	tests := []struct {
+		name string
+		sc   *adapter.SecurityContext
+		want string
+	}{
+		{name: "未认证", sc: nil, want: ""},
+		{name: "没有用户", sc: &adapter.SecurityContext{TenantID: "t1"}, want: ""},
+		{name: "租户用户", sc: &adapter.SecurityContext{UserID: "u1", TenantID: "t1"}, want: "user:t1/u1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IdentityID(tt.sc); got != tt.want {
+				t.Errorf("IdentityID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	store := NewMemoryStore(0)
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("expected no session in empty context")
+	}
+	ctx := WithSession(context.Background(), New(store, "s-1"))
+	if sess, ok := FromContext(ctx); !ok || sess.ID() != "s-1" {
+		t.Errorf("FromContext = %v, %v", sess, ok)
+	}
+
+	if _, ok := ForIdentity(context.Background(), store); ok {
+		t.Error("expected no identity session without security context")
+	}
+	ctx = adapter.WithSecurityContext(ctx, &adapter.SecurityContext{UserID: "u1"})
+	if sess, ok := ForIdentity(ctx, store); !ok || sess.ID() != "user:/u1" {
+		t.Errorf("ForIdentity = %v, %v", sess, ok)
+	}
+}
@@ -0,0 +1,163 @@
+package tcc
+
+import (
+	"context"
+	"fmt"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+)
+
+// Resource 参与方资源的三个阶段，params 为 JSON 解码后的请求参数，三个阶段收到相同的参数
+type Resource struct {
+	// Try 检查并预留资源
+	Try func(ctx context.Context, params interface{}) (interface{}, error)
+	// Confirm 使用预留的资源，须幂等
+	Confirm func(ctx context.Context, params interface{}) error
+	// Cancel 释放预留的资源，须幂等；try 失败或超时后也会被调用，须容忍部分预留或未预留的情况
+	Cancel func(ctx context.Context, params interface{}) error
+}
+
+// Participant TCC 参与方
+//
+// 按 BranchStore 中的事务状态处理重复调用、空回滚和悬挂：
+//   - 重复的 try、confirm 和 cancel 不再执行
+//   - 未执行 try 时收到 cancel（try 请求丢失或晚到）直接返回成功，不调用 Resource.Cancel
+//   - 已取消的事务拒绝之后到达的 try，避免预留的资源无人释放
+type Participant struct {
+	resource string
+	handlers Resource
+	store    BranchStore
+}
+
+// NewParticipant 创建资源 resource 的参与方，store 为 nil 时使用保留 24 小时的 MemoryBranchStore
+func NewParticipant(resource string, handlers Resource, store BranchStore) *Participant {
+	if store == nil {
+		store = NewMemoryBranchStore(defaultBranchTTL)
+	}
+	return &Participant{
+		resource: resource,
+		handlers: handlers,
+		store:    store,
+	}
+}
+
+// Handlers 返回三个阶段的方法处理器，键为方法名，可逐个注册到 framework.Server：
+//
+//	for method, handler := range participant.Handlers() {
+//	    server.Handle(method, handler)
+//	}
+func (p *Participant) Handlers() map[string]func(ctx context.Context, params interface{}) (interface{}, error) {
+	return map[string]func(ctx context.Context, params interface{}) (interface{}, error){
+		MethodName(p.resource, PhaseTry): p.Try,
+		MethodName(p.resource, PhaseConfirm): func(ctx context.Context, params interface{}) (interface{}, error) {
+			return nil, p.Confirm(ctx, params)
+		},
+		MethodName(p.resource, PhaseCancel): func(ctx context.Context, params interface{}) (interface{}, error) {
+			return nil, p.Cancel(ctx, params)
+		},
+	}
+}
+
+// Try 执行 try 阶段，重复的 try 返回 nil 结果
+func (p *Participant) Try(ctx context.Context, params interface{}) (interface{}, error) {
+	txID, err := transactionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := p.store.CompareAndSet(ctx, txID, p.resource, StatusNone, StatusTried)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		status, err := p.store.Get(ctx, txID, p.resource)
+		if err != nil {
+			return nil, err
+		}
+		if status == StatusCancelled {
+			return nil, p.conflict(txID, "try after cancel")
+		}
+		return nil, nil
+	}
+	if p.handlers.Try == nil {
+		return nil, nil
+	}
+	return p.handlers.Try(ctx, params)
+}
+
+// Confirm 执行 confirm 阶段，已确认时直接返回
+func (p *Participant) Confirm(ctx context.Context, params interface{}) error {
+	txID, err := transactionID(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := p.store.Get(ctx, txID, p.resource)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case StatusConfirmed:
+		return nil
+	case StatusTried:
+	default:
+		return p.conflict(txID, fmt.Sprintf("confirm in status %q", status))
+	}
+	if p.handlers.Confirm != nil {
+		if err := p.handlers.Confirm(ctx, params); err != nil {
+			return err
+		}
+	}
+	_, err = p.store.CompareAndSet(ctx, txID, p.resource, StatusTried, StatusConfirmed)
+	return err
+}
+
+// Cancel 执行 cancel 阶段，已取消时直接返回，未执行 try 时记录取消并返回（空回滚）
+func (p *Participant) Cancel(ctx context.Context, params interface{}) error {
+	txID, err := transactionID(ctx)
+	if err != nil {
+		return err
+	}
+	ok, err := p.store.CompareAndSet(ctx, txID, p.resource, StatusNone, StatusCancelled)
+	if err != nil || ok {
+		return err
+	}
+	status, err := p.store.Get(ctx, txID, p.resource)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case StatusCancelled:
+		return nil
+	case StatusTried:
+	default:
+		return p.conflict(txID, fmt.Sprintf("cancel in status %q", status))
+	}
+	if p.handlers.Cancel != nil {
+		if err := p.handlers.Cancel(ctx, params); err != nil {
+			return err
+		}
+	}
+	_, err = p.store.CompareAndSet(ctx, txID, p.resource, StatusTried, StatusCancelled)
+	return err
+}
+
+// conflict 构造事务状态不允许该阶段的错误
+func (p *Participant) conflict(txID, reason string) error {
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+		fmt.Sprintf("transaction %s resource %s: %s", txID, p.resource, reason))
+}
+
+// TransactionID 返回 context 中的事务 ID，参与方的业务代码可据此关联预留记录
+func TransactionID(ctx context.Context) string {
+	return adapter.TransactionIDFromContext(ctx)
+}
+
+// transactionID 返回 context 中的事务 ID，缺失时返回 BadRequest
+func transactionID(ctx context.Context) (string, error) {
+	txID := adapter.TransactionIDFromContext(ctx)
+	if txID == "" {
+		return "", frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest,
+			fmt.Sprintf("missing %s header", adapter.HeaderTransactionID))
+	}
+	return txID, nil
+}
@@ -0,0 +1,93 @@
+package tcc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultBranchTTL NewParticipant 默认使用的进程内存储的记录保留时间
+const defaultBranchTTL = 24 * time.Hour
+
+// BranchStatus 参与方在某个事务中的状态
+type BranchStatus string
+
+const (
+	// StatusNone 未收到该事务的任何调用
+	StatusNone BranchStatus = ""
+	// StatusTried 已执行 try
+	StatusTried BranchStatus = "tried"
+	// StatusConfirmed 已确认
+	StatusConfirmed BranchStatus = "confirmed"
+	// StatusCancelled 已取消；未执行 try 时也记录为已取消，拒绝之后到达的 try
+	StatusCancelled BranchStatus = "cancelled"
+)
+
+// BranchStore 参与方的事务状态存储，多实例部署时应使用共享的持久化存储
+type BranchStore interface {
+	// Get 返回 (txID, resource) 的状态，不存在时返回 StatusNone
+	Get(ctx context.Context, txID, resource string) (BranchStatus, error)
+	// CompareAndSet 当前状态为 from 时更新为 to 并返回 true，否则不修改并返回 false
+	CompareAndSet(ctx context.Context, txID, resource string, from, to BranchStatus) (bool, error)
+}
+
+// MemoryBranchStore 进程内事务状态存储，记录在 ttl 后过期
+type MemoryBranchStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	branches  map[string]memoryBranch
+	lastPurge time.Time
+}
+
+// memoryBranch 事务状态及其过期时间
+type memoryBranch struct {
+	status BranchStatus
+	expiry time.Time
+}
+
+// NewMemoryBranchStore 创建进程内事务状态存储，ttl 应大于事务的最长持续时间
+func NewMemoryBranchStore(ttl time.Duration) *MemoryBranchStore {
+	return &MemoryBranchStore{
+		ttl:       ttl,
+		branches:  make(map[string]memoryBranch),
+		lastPurge: time.Now(),
+	}
+}
+
+// Get 返回未过期的状态
+func (s *MemoryBranchStore) Get(ctx context.Context, txID, resource string) (BranchStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(txID+"/"+resource, time.Now()), nil
+}
+
+// CompareAndSet 原子地比较并更新状态，每经过一个 ttl 清理一次过期的记录
+func (s *MemoryBranchStore) CompareAndSet(ctx context.Context, txID, resource string, from, to BranchStatus) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := txID + "/" + resource
+	now := time.Now()
+	if s.get(key, now) != from {
+		return false, nil
+	}
+	s.branches[key] = memoryBranch{status: to, expiry: now.Add(s.ttl)}
+
+	if now.Sub(s.lastPurge) >= s.ttl {
+		for k, b := range s.branches {
+			if !now.Before(b.expiry) {
+				delete(s.branches, k)
+			}
+		}
+		s.lastPurge = now
+	}
+	return true, nil
+}
+
+// get 返回 key 的状态，已过期时视为 StatusNone
+func (s *MemoryBranchStore) get(key string, now time.Time) BranchStatus {
+	b, ok := s.branches[key]
+	if !ok || !now.Before(b.expiry) {
+		return StatusNone
+	}
+	return b.status
+}
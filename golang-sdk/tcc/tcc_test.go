@@ -0,0 +1,274 @@
+package tcc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// fakeCaller 进程内调用参与方，事务 ID 经请求头传递，与跨进程调用相同
+type fakeCaller struct {
+	mu       sync.Mutex
+	handlers map[string]func(ctx context.Context, params interface{}) (interface{}, error)
+	calls    []string
+	failures map[string][]error // 方法 -> 依次返回的错误
+}
+
+func newFakeCaller(participants map[string]*Participant) *fakeCaller {
+	c := &fakeCaller{
+		handlers: make(map[string]func(ctx context.Context, params interface{}) (interface{}, error)),
+		failures: make(map[string][]error),
+	}
+	for service, participant := range participants {
+		for method, handler := range participant.Handlers() {
+			c.handlers[service+"/"+method] = handler
+		}
+	}
+	return c
+}
+
+func (c *fakeCaller) Call(ctx context.Context, service, method string, request interface{}, response interface{}) error {
+	key := service + "/" + method
+	c.mu.Lock()
+	c.calls = append(c.calls, key)
+	handler := c.handlers[key]
+	var err error
+	if failures := c.failures[key]; len(failures) > 0 {
+		err, c.failures[key] = failures[0], failures[1:]
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string)
+	adapter.InjectTraceContext(ctx, headers)
+	_, err = handler(adapter.ExtractTraceContext(context.Background(), headers), request)
+	return err
+}
+
+func (c *fakeCaller) list() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+// recorder 记录资源各阶段的业务调用
+type recorder struct {
+	mu      sync.Mutex
+	entries []string
+	txIDs   map[string]bool
+}
+
+func (r *recorder) resource(name string) Resource {
+	record := func(ctx context.Context, phase string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.entries = append(r.entries, name+"."+phase)
+		if r.txIDs == nil {
+			r.txIDs = make(map[string]bool)
+		}
+		r.txIDs[TransactionID(ctx)] = true
+	}
+	return Resource{
+		Try: func(ctx context.Context, params interface{}) (interface{}, error) {
+			record(ctx, PhaseTry)
+			return params, nil
+		},
+		Confirm: func(ctx context.Context, params interface{}) error {
+			record(ctx, PhaseConfirm)
+			return nil
+		},
+		Cancel: func(ctx context.Context, params interface{}) error {
+			record(ctx, PhaseCancel)
+			return nil
+		},
+	}
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.entries...)
+}
+
+// fastOptions 不等待重试延迟的事务选项
+var fastOptions = &Options{RetryPolicy: resilience.NewRetryPolicy(3, time.Millisecond, time.Millisecond, 1.0)}
+
+func TestTransactionConfirm(t *testing.T) {
+	r := &recorder{}
+	caller := newFakeCaller(map[string]*Participant{
+		"inventory-service": NewParticipant("inventory", r.resource("inventory"), nil),
+		"payment-service":   NewParticipant("payment", r.resource("payment"), nil),
+	})
+
+	var txID string
+	err := Execute(context.Background(), caller, fastOptions, func(ctx context.Context, tx *Transaction) error {
+		txID = tx.ID()
+		if TransactionID(ctx) != txID {
+			t.Errorf("context transaction id = %q, want %q", TransactionID(ctx), txID)
+		}
+		if err := tx.Try(ctx, "inventory-service", "inventory", map[string]int{"sku": 1}, nil); err != nil {
+			return err
+		}
+		return tx.Try(ctx, "payment-service", "payment", map[string]int{"amount": 42}, nil)
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := []string{"inventory.try", "payment.try", "inventory.confirm", "payment.confirm"}
+	if !reflect.DeepEqual(r.list(), want) {
+		t.Errorf("calls = %v, want %v", r.list(), want)
+	}
+	if len(r.txIDs) != 1 || !r.txIDs[txID] {
+		t.Errorf("participants saw transaction ids %v, want only %s", r.txIDs, txID)
+	}
+}
+
+func TestTransactionCancel(t *testing.T) {
+	r := &recorder{}
+	caller := newFakeCaller(map[string]*Participant{
+		"inventory-service": NewParticipant("inventory", r.resource("inventory"), nil),
+		"payment-service":   NewParticipant("payment", r.resource("payment"), nil),
+	})
+	declined := frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "insufficient balance")
+	caller.failures["payment-service/payment.try"] = []error{declined}
+
+	err := Execute(context.Background(), caller, fastOptions, func(ctx context.Context, tx *Transaction) error {
+		if err := tx.Try(ctx, "inventory-service", "inventory", nil, nil); err != nil {
+			return err
+		}
+		return tx.Try(ctx, "payment-service", "payment", nil, nil)
+	})
+	if !errors.Is(err, declined) {
+		t.Fatalf("expected try error, got %v", err)
+	}
+
+	// 失败的 payment.try 未到达参与方，其 cancel 为空回滚，不调用业务 Cancel
+	wantCalls := []string{
+		"inventory-service/inventory.try", "payment-service/payment.try",
+		"payment-service/payment.cancel", "inventory-service/inventory.cancel",
+	}
+	if !reflect.DeepEqual(caller.list(), wantCalls) {
+		t.Errorf("calls = %v, want %v", caller.list(), wantCalls)
+	}
+	if want := []string{"inventory.try", "inventory.cancel"}; !reflect.DeepEqual(r.list(), want) {
+		t.Errorf("business calls = %v, want %v", r.list(), want)
+	}
+}
+
+func TestTransactionRetriesConfirm(t *testing.T) {
+	r := &recorder{}
+	caller := newFakeCaller(map[string]*Participant{
+		"inventory-service": NewParticipant("inventory", r.resource("inventory"), nil),
+	})
+	unavailable := frameworkerrors.NewFrameworkError(frameworkerrors.ServiceUnavailable, "unavailable")
+	caller.failures["inventory-service/inventory.confirm"] = []error{unavailable, unavailable}
+
+	ctx, tx := Begin(context.Background(), caller, fastOptions)
+	if err := tx.Try(ctx, "inventory-service", "inventory", nil, nil); err != nil {
+		t.Fatalf("Try failed: %v", err)
+	}
+	if err := tx.Confirm(ctx); err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	if err := tx.Cancel(ctx); err == nil {
+		t.Error("expected Cancel after Confirm to fail")
+	}
+	if err := tx.Try(ctx, "inventory-service", "inventory", nil, nil); err == nil {
+		t.Error("expected Try after Confirm to fail")
+	}
+	if want := []string{"inventory.try", "inventory.confirm"}; !reflect.DeepEqual(r.list(), want) {
+		t.Errorf("business calls = %v, want %v", r.list(), want)
+	}
+}
+
+func TestParticipant(t *testing.T) {
+	badRequest := func(err error) bool {
+		fe, ok := frameworkerrors.FromError(err)
+		return ok && fe.Code == frameworkerrors.BadRequest
+	}
+
+	t.Run("重复调用只执行一次", func(t *testing.T) {
+		r := &recorder{}
+		p := NewParticipant("inventory", r.resource("inventory"), nil)
+		ctx := adapter.WithTransactionID(context.Background(), "tx-1")
+		for i := 0; i < 2; i++ {
+			if _, err := p.Try(ctx, nil); err != nil {
+				t.Fatalf("Try failed: %v", err)
+			}
+		}
+		for i := 0; i < 2; i++ {
+			if err := p.Confirm(ctx, nil); err != nil {
+				t.Fatalf("Confirm failed: %v", err)
+			}
+		}
+		if want := []string{"inventory.try", "inventory.confirm"}; !reflect.DeepEqual(r.list(), want) {
+			t.Errorf("business calls = %v, want %v", r.list(), want)
+		}
+		if err := p.Cancel(ctx, nil); !badRequest(err) {
+			t.Errorf("Cancel after Confirm = %v, want BadRequest", err)
+		}
+	})
+
+	t.Run("空回滚并拒绝悬挂的 try", func(t *testing.T) {
+		r := &recorder{}
+		p := NewParticipant("inventory", r.resource("inventory"), nil)
+		ctx := adapter.WithTransactionID(context.Background(), "tx-1")
+		if err := p.Cancel(ctx, nil); err != nil {
+			t.Fatalf("Cancel failed: %v", err)
+		}
+		if _, err := p.Try(ctx, nil); !badRequest(err) {
+			t.Errorf("Try after Cancel = %v, want BadRequest", err)
+		}
+		if len(r.list()) != 0 {
+			t.Errorf("business calls = %v, want none", r.list())
+		}
+	})
+
+	t.Run("未执行 try 时拒绝 confirm", func(t *testing.T) {
+		p := NewParticipant("inventory", Resource{}, nil)
+		ctx := adapter.WithTransactionID(context.Background(), "tx-1")
+		if err := p.Confirm(ctx, nil); !badRequest(err) {
+			t.Errorf("Confirm = %v, want BadRequest", err)
+		}
+	})
+
+	t.Run("缺少事务 ID", func(t *testing.T) {
+		p := NewParticipant("inventory", Resource{}, nil)
+		if _, err := p.Try(context.Background(), nil); !badRequest(err) {
+			t.Errorf("Try = %v, want BadRequest", err)
+		}
+	})
+}
+
+func TestMemoryBranchStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryBranchStore(20 * time.Millisecond)
+
+	if ok, _ := store.CompareAndSet(ctx, "tx-1", "inventory", StatusNone, StatusTried); !ok {
+		t.Fatal("expected first CompareAndSet to succeed")
+	}
+	if ok, _ := store.CompareAndSet(ctx, "tx-1", "inventory", StatusNone, StatusCancelled); ok {
+		t.Error("expected CompareAndSet with stale status to fail")
+	}
+	if status, _ := store.Get(ctx, "tx-1", "inventory"); status != StatusTried {
+		t.Errorf("status = %q, want tried", status)
+	}
+	if status, _ := store.Get(ctx, "tx-1", "payment"); status != StatusNone {
+		t.Errorf("status = %q, want none", status)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if status, _ := store.Get(ctx, "tx-1", "inventory"); status != StatusNone {
+		t.Errorf("expired status = %q, want none", status)
+	}
+}
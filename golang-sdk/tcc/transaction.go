@@ -0,0 +1,177 @@
+package tcc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+	"github.com/framework/golang-sdk/protocol/adapter"
+	"github.com/framework/golang-sdk/resilience"
+)
+
+// 参与方各阶段的方法名后缀，资源 inventory 的三个方法为 inventory.try、inventory.confirm 和 inventory.cancel
+const (
+	PhaseTry     = "try"
+	PhaseConfirm = "confirm"
+	PhaseCancel  = "cancel"
+)
+
+// MethodName 返回资源在某一阶段的方法名
+func MethodName(resource, phase string) string {
+	return resource + "." + phase
+}
+
+// Caller 调用参与方服务的客户端，client.FrameworkClient 实现了该接口
+type Caller interface {
+	Call(ctx context.Context, service, method string, request interface{}, response interface{}) error
+}
+
+// Options 事务选项
+type Options struct {
+	// RetryPolicy Confirm 和 Cancel 失败时的重试策略，为 nil 时使用 resilience.DefaultRetryPolicy；非框架错误不重试
+	RetryPolicy *resilience.RetryPolicy
+}
+
+// branch 事务中的一个参与方
+type branch struct {
+	service  string
+	resource string
+	request  interface{}
+}
+
+// Transaction TCC 事务协调者
+//
+// Try 调用参与方的 try 方法预留资源，全部成功后 Confirm 确认，任一失败时 Cancel 释放已预留的资源。
+// 事务 ID 写入 context，随请求头 X-Transaction-Id 传递给参与方
+type Transaction struct {
+	id          string
+	caller      Caller
+	retryPolicy *resilience.RetryPolicy
+
+	mu       sync.Mutex
+	branches []*branch
+	finished bool
+}
+
+// Begin 开始事务，返回携带事务 ID 的 context，事务内的调用均应使用该 context
+func Begin(ctx context.Context, caller Caller, options *Options) (context.Context, *Transaction) {
+	if options == nil {
+		options = &Options{}
+	}
+	tx := &Transaction{
+		id:          newTransactionID(),
+		caller:      caller,
+		retryPolicy: options.RetryPolicy,
+	}
+	if tx.retryPolicy == nil {
+		tx.retryPolicy = resilience.DefaultRetryPolicy()
+	}
+	return adapter.WithTransactionID(ctx, tx.id), tx
+}
+
+// Execute 在事务中执行 fn：fn 返回 nil 时 Confirm，否则 Cancel 并返回 fn 的错误
+func Execute(ctx context.Context, caller Caller, options *Options, fn func(ctx context.Context, tx *Transaction) error) error {
+	ctx, tx := Begin(ctx, caller, options)
+	if err := fn(ctx, tx); err != nil {
+		if cancelErr := tx.Cancel(ctx); cancelErr != nil {
+			return errors.Join(err, cancelErr)
+		}
+		return err
+	}
+	return tx.Confirm(ctx)
+}
+
+// ID 返回事务 ID
+func (t *Transaction) ID() string {
+	return t.id
+}
+
+// Try 调用 service 服务中 resource 资源的 try 方法
+//
+// 调用前即登记参与方：try 超时或失败时参与方可能已预留资源，Cancel 时仍会调用其 cancel 方法
+func (t *Transaction) Try(ctx context.Context, service, resource string, request interface{}, response interface{}) error {
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return fmt.Errorf("transaction %s already finished", t.id)
+	}
+	t.branches = append(t.branches, &branch{service: service, resource: resource, request: request})
+	t.mu.Unlock()
+
+	ctx = adapter.WithTransactionID(ctx, t.id)
+	return t.caller.Call(ctx, service, MethodName(resource, PhaseTry), request, response)
+}
+
+// Confirm 按登记顺序调用各参与方的 confirm 方法，失败的参与方按重试策略重试，错误合并返回
+func (t *Transaction) Confirm(ctx context.Context) error {
+	return t.finish(ctx, PhaseConfirm)
+}
+
+// Cancel 按登记的相反顺序调用各参与方的 cancel 方法，失败的参与方按重试策略重试，错误合并返回
+func (t *Transaction) Cancel(ctx context.Context) error {
+	return t.finish(ctx, PhaseCancel)
+}
+
+// finish 结束事务，对全部参与方执行 phase 阶段
+func (t *Transaction) finish(ctx context.Context, phase string) error {
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return fmt.Errorf("transaction %s already finished", t.id)
+	}
+	t.finished = true
+	branches := append([]*branch(nil), t.branches...)
+	t.mu.Unlock()
+
+	if phase == PhaseCancel {
+		for i, j := 0, len(branches)-1; i < j; i, j = i+1, j-1 {
+			branches[i], branches[j] = branches[j], branches[i]
+		}
+	}
+
+	ctx = adapter.WithTransactionID(ctx, t.id)
+	ctx, span := adapter.StartInternalSpan(ctx, "tcc "+phase)
+	var errs []error
+	for _, b := range branches {
+		if err := t.call(ctx, b, phase); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s.%s: %w", phase, b.service, b.resource, err))
+		}
+	}
+	err := errors.Join(errs...)
+	adapter.EndSpan(span, err)
+	return err
+}
+
+// call 按重试策略调用参与方的 confirm 或 cancel 方法
+func (t *Transaction) call(ctx context.Context, b *branch, phase string) error {
+	method := MethodName(b.resource, phase)
+	for attempt := 1; ; attempt++ {
+		err := t.caller.Call(ctx, b.service, method, b.request, nil)
+		if err == nil || attempt >= t.retryPolicy.MaxAttempts || !t.retryable(err) {
+			return err
+		}
+		select {
+		case <-time.After(t.retryPolicy.CalculateDelay(attempt - 1)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// retryable 判断错误是否按重试策略重试，非框架错误不重试
+func (t *Transaction) retryable(err error) bool {
+	fe, ok := frameworkerrors.FromError(err)
+	return ok && t.retryPolicy.IsRetryable(fe.Code)
+}
+
+// newTransactionID 生成 128 位随机事务 ID
+func newTransactionID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,338 @@
+// Package validate 按结构体字段的 validate 标签校验请求参数
+//
+// 标签由逗号分隔的规则组成，如 `validate:"required,min=1,max=64"`；嵌套的结构体、切片、数组和 map 的元素递归校验。
+// 违反规则时返回 BadRequest 框架错误，各字段的违规以 ViolationsField 详情随错误跨语言传输，
+// 字段路径使用 JSON 字段名，如 items[0].name。
+//
+// 支持的规则：
+//
+//	required      不能为零值；指针和接口不能为 nil，字符串、切片和 map 不能为空
+//	omitempty     值为零值时跳过其余规则
+//	min=N, max=N  数值的大小，字符串（按字符）、切片、数组和 map 的长度
+//	len=N         字符串、切片、数组和 map 的长度
+//	oneof=a b c   字符串或数值为列出的值之一
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+// ViolationsField 校验失败的错误详情中记录违规列表的键
+const ViolationsField = "violations"
+
+// Violation 一个字段违反的规则
+type Violation struct {
+	// Field 字段路径，使用 JSON 字段名，如 items[0].name
+	Field string `json:"field"`
+	// Rule 违反的规则，如 required、min=1
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// rule 解析后的一条规则
+type rule struct {
+	name  string
+	param string
+	// limit min、max、len 的参数
+	limit float64
+}
+
+// fieldSpec 结构体字段的名称和规则
+type fieldSpec struct {
+	index    int
+	name     string
+	rules    []rule
+	embedded bool
+}
+
+var specs sync.Map // reflect.Type -> []fieldSpec 或 error
+
+// Struct 校验 v 及其嵌套字段，v 为 nil 或不是结构体（及其指针、切片等容器）时返回 nil
+//
+// 存在违规时返回 BadRequest 错误，Fields[ViolationsField] 为 []Violation；标签无法解析时返回 InternalError
+func Struct(v interface{}) error {
+	var violations []Violation
+	if err := collect(reflect.ValueOf(v), "", &violations); err != nil {
+		return frameworkerrors.Wrap(err, frameworkerrors.InternalError, "invalid validate tag")
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.Field + " " + violation.Message
+	}
+	return frameworkerrors.NewFrameworkError(frameworkerrors.BadRequest, "validation failed: "+strings.Join(messages, "; ")).
+		WithDetail(ViolationsField, violations)
+}
+
+// collect 递归收集 v 的违规，path 为 v 的字段路径
+func collect(v reflect.Value, path string, violations *[]Violation) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields, err := structSpec(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			value := v.Field(field.index)
+			fieldPath := path
+			if !field.embedded {
+				fieldPath = joinPath(path, field.name)
+				for _, r := range field.rules {
+					if r.name == "omitempty" {
+						if value.IsZero() {
+							break
+						}
+						continue
+					}
+					if message, ok := check(r, value); !ok {
+						*violations = append(*violations, Violation{Field: fieldPath, Rule: r.String(), Message: message})
+						break
+					}
+				}
+			}
+			if err := collect(value, fieldPath, violations); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !containsStruct(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := collect(v.Index(i), fmt.Sprintf("%s[%d]", path, i), violations); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !containsStruct(v.Type().Elem()) {
+			return nil
+		}
+		// 按键排序，使违规顺序稳定
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return format(keys[i]) < format(keys[j]) })
+		for _, key := range keys {
+			if err := collect(v.MapIndex(key), fmt.Sprintf("%s[%s]", path, format(key)), violations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check 检查值是否满足规则，不满足时返回违规说明
+func check(r rule, v reflect.Value) (string, bool) {
+	if r.name == "required" {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			return "is required", !v.IsNil()
+		case reflect.String, reflect.Slice, reflect.Map:
+			return "is required", v.Len() > 0
+		default:
+			return "is required", !v.IsZero()
+		}
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", true
+		}
+		v = v.Elem()
+	}
+
+	switch r.name {
+	case "min", "max", "len":
+		size, isLength, ok := measure(v)
+		if !ok {
+			return "", true
+		}
+		subject := "must be"
+		if isLength {
+			subject = "length must be"
+		}
+		switch {
+		case r.name == "min" && size < r.limit:
+			return fmt.Sprintf("%s at least %s", subject, r.param), false
+		case r.name == "max" && size > r.limit:
+			return fmt.Sprintf("%s at most %s", subject, r.param), false
+		case r.name == "len" && size != r.limit:
+			return fmt.Sprintf("length must be %s", r.param), false
+		}
+	case "oneof":
+		value := format(v)
+		for _, option := range strings.Fields(r.param) {
+			if value == option {
+				return "", true
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", r.param), false
+	}
+	return "", true
+}
+
+// measure 返回数值的大小或字符串、容器的长度，isLength 表示返回的是长度
+func measure(v reflect.Value) (size float64, isLength bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true, true
+	default:
+		return 0, false, false
+	}
+}
+
+// structSpec 返回结构体字段的规则，按类型缓存
+func structSpec(typ reflect.Type) ([]fieldSpec, error) {
+	if cached, ok := specs.Load(typ); ok {
+		if err, isErr := cached.(error); isErr {
+			return nil, err
+		}
+		return cached.([]fieldSpec), nil
+	}
+
+	fields, err := parseStruct(typ)
+	if err != nil {
+		specs.Store(typ, err)
+		return nil, err
+	}
+	specs.Store(typ, fields)
+	return fields, nil
+}
+
+// parseStruct 解析结构体导出字段的 JSON 名称和 validate 标签
+func parseStruct(typ reflect.Type) ([]fieldSpec, error) {
+	var fields []fieldSpec
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name := field.Name
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if jsonName != "" {
+			name = jsonName
+		}
+
+		rules, err := parseRules(field.Tag.Get("validate"))
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typ.Name(), field.Name, err)
+		}
+		// 未命名的嵌入结构体的字段提升到外层，与 JSON 编码一致
+		embedded := field.Anonymous && jsonName == "" && indirect(field.Type).Kind() == reflect.Struct
+		if !field.IsExported() && !embedded {
+			continue
+		}
+		fields = append(fields, fieldSpec{index: i, name: name, rules: rules, embedded: embedded})
+	}
+	return fields, nil
+}
+
+// parseRules 解析 validate 标签
+func parseRules(tag string) ([]rule, error) {
+	if tag == "" || tag == "-" {
+		return nil, nil
+	}
+
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		r := rule{name: name, param: param}
+		switch name {
+		case "required", "omitempty":
+		case "min", "max", "len":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s requires a numeric parameter, got %q", name, param)
+			}
+			r.limit = limit
+		case "oneof":
+			if strings.TrimSpace(param) == "" {
+				return nil, fmt.Errorf("rule oneof requires at least one value")
+			}
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// String 返回规则的标签形式，如 min=1
+func (r rule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// containsStruct 判断类型（去掉指针后）是否可能包含需要校验的结构体
+func containsStruct(typ reflect.Type) bool {
+	switch indirect(typ).Kind() {
+	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// indirect 去掉类型的指针
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// format 格式化字符串、数值和布尔值，不经 Interface，未导出的嵌入结构体中的字段也可读取
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return v.Type().String()
+	}
+}
@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	frameworkerrors "github.com/framework/golang-sdk/errors"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"omitempty,len=5"`
+}
+
+type Audit struct {
+	Operator string `json:"operator" validate:"required"`
+}
+
+type orderItem struct {
+	Name     string `json:"name" validate:"required,max=8"`
+	Quantity int    `json:"quantity" validate:"min=1,max=99"`
+}
+
+type orderRequest struct {
+	Audit
+	ID       string               `json:"id" validate:"required"`
+	Status   string               `json:"status" validate:"oneof=new paid"`
+	Items    []orderItem          `json:"items" validate:"required,min=1"`
+	Address  *address             `json:"address" validate:"required"`
+	Priority *int                 `json:"priority" validate:"omitempty,max=3"`
+	Extra    map[string]orderItem `json:"extra"`
+	Tags     []string             `json:"tags" validate:"max=2"`
+	Ignored  string               `json:"-" validate:"required"`
+	internal string
+}
+
+func TestStruct(t *testing.T) {
+	priority := 5
+	tests := []struct {
+		name string
+		req  interface{}
+		want []Violation
+	}{
+		{
+			name: "有效请求",
+			req: &orderRequest{
+				Audit:   Audit{Operator: "u1"},
+				ID:      "o1",
+				Status:  "new",
+				Items:   []orderItem{{Name: "apple", Quantity: 2}},
+				Address: &address{City: "Beijing"},
+			},
+		},
+		{
+			name: "嵌套字段违规使用 JSON 字段路径",
+			req: orderRequest{
+				Status:   "shipped",
+				Items:    []orderItem{{Name: "apple", Quantity: 1}, {Name: "watermelon", Quantity: 0}},
+				Address:  &address{Zip: "123"},
+				Priority: &priority,
+				Extra:    map[string]orderItem{"gift": {Quantity: 1}},
+				Tags:     []string{"a", "b", "c"},
+			},
+			want: []Violation{
+				{Field: "operator", Rule: "required", Message: "is required"},
+				{Field: "id", Rule: "required", Message: "is required"},
+				{Field: "status", Rule: "oneof=new paid", Message: "must be one of [new paid]"},
+				{Field: "items[1].name", Rule: "max=8", Message: "length must be at most 8"},
+				{Field: "items[1].quantity", Rule: "min=1", Message: "must be at least 1"},
+				{Field: "address.city", Rule: "required", Message: "is required"},
+				{Field: "address.zip", Rule: "len=5", Message: "length must be 5"},
+				{Field: "priority", Rule: "max=3", Message: "must be at most 3"},
+				{Field: "extra[gift].name", Rule: "required", Message: "is required"},
+				{Field: "tags", Rule: "max=2", Message: "length must be at most 2"},
+			},
+		},
+		{
+			name: "空切片和 nil 指针",
+			req:  &orderRequest{Audit: Audit{Operator: "u1"}, ID: "o1", Status: "paid", Items: []orderItem{}},
+			want: []Violation{
+				{Field: "items", Rule: "required", Message: "is required"},
+				{Field: "address", Rule: "required", Message: "is required"},
+			},
+		},
+		{name: "nil", req: nil},
+		{name: "非结构体", req: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.req)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			fe, ok := frameworkerrors.FromError(err)
+			if !ok || fe.Code != frameworkerrors.BadRequest {
+				t.Fatalf("Expected BadRequest, got %v", err)
+			}
+			if got := fe.Fields[ViolationsField]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructInvalidTag(t *testing.T) {
+	type badMin struct {
+		Count int `validate:"min=one"`
+	}
+	type unknownRule struct {
+		Email string `validate:"email"`
+	}
+
+	for _, req := range []interface{}{badMin{}, &unknownRule{}} {
+		err := Struct(req)
+		if fe, ok := frameworkerrors.FromError(err); !ok || fe.Code != frameworkerrors.InternalError {
+			t.Errorf("Expected InternalError for %T, got %v", req, err)
+		}
+	}
+}